@@ -0,0 +1,440 @@
+// Package archive writes and verifies report archives (.otar): a report and
+// the logs written while collecting it, with a manifest of their SHA-256
+// digests that is optionally signed with Ed25519 and encrypted to an X25519
+// recipient. Archives are tamper-evident evidence for audits that need a
+// chain of custody.
+//
+// Recipients and identities use the age key format, so keys made with
+// age-keygen work, but the archive itself is not an age file: it is a gzip
+// tar sealed with AES-256-GCM under a key derived with HKDF-SHA256 from an
+// ephemeral X25519 exchange.
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+// Extension is the conventional file extension of report archives
+const Extension = ".otar"
+
+// Headers that start an archive, before the gzip tar or its ciphertext
+const (
+	headerPlain     = "omnitrust-archive/1\n"
+	headerEncrypted = "omnitrust-archive/1 x25519\n"
+)
+
+// Names of the archive members that describe the others
+const (
+	ManifestName  = "manifest.json"
+	SignatureName = "manifest.sig"
+)
+
+// hkdfInfo binds derived keys to this archive format
+const hkdfInfo = "omnitrust-archive/1"
+
+// maxArchiveSize bounds the decompressed size of an archive
+const maxArchiveSize = 256 << 20
+
+// File is a member of an archive
+type File struct {
+	Name string
+	Data []byte
+}
+
+// Digest records a member's size and SHA-256 digest in the manifest
+type Digest struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest describes an archive's origin and contents. Its signature
+// covers the digests of every other member, so signing it signs them all.
+type Manifest struct {
+	CreatedAt   time.Time `json:"created_at"`
+	Hostname    string    `json:"hostname,omitempty"`
+	MachineID   string    `json:"machine_id,omitempty"`
+	ToolVersion string    `json:"tool_version,omitempty"`
+	Files       []Digest  `json:"files"`
+	// Signer is the signing key's ID (see SignerID), empty if unsigned
+	Signer string `json:"signer,omitempty"`
+}
+
+// Options control how an archive is sealed
+type Options struct {
+	// Recipient encrypts the archive so only its identity can read it
+	Recipient *ecdh.PublicKey
+	// SigningKey signs the manifest
+	SigningKey ed25519.PrivateKey
+}
+
+// Write writes files as an archive described by m. It fills in the
+// manifest's digests and signer.
+func Write(w io.Writer, m Manifest, files []File, opts Options) error {
+	m.Files = make([]Digest, 0, len(files))
+	for _, f := range files {
+		if f.Name == ManifestName || f.Name == SignatureName {
+			return fmt.Errorf("archive member name %s is reserved", f.Name)
+		}
+		sum := sha256.Sum256(f.Data)
+		m.Files = append(m.Files, Digest{Name: f.Name, Size: int64(len(f.Data)), SHA256: hex.EncodeToString(sum[:])})
+	}
+	if opts.SigningKey != nil {
+		m.Signer = SignerID(opts.SigningKey.Public().(ed25519.PublicKey))
+	}
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	members := append([]File{{Name: ManifestName, Data: manifest}}, files...)
+	if opts.SigningKey != nil {
+		sig := ed25519.Sign(opts.SigningKey, manifest)
+		members = slices.Insert(members, 1, File{Name: SignatureName, Data: []byte(base64.StdEncoding.EncodeToString(sig) + "\n")})
+	}
+
+	payload, err := pack(members, m.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if opts.Recipient == nil {
+		_, err = io.WriteString(w, headerPlain)
+		if err == nil {
+			_, err = w.Write(payload)
+		}
+		return err
+	}
+	sealed, err := seal(payload, opts.Recipient)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(sealed)
+	return err
+}
+
+// pack writes members to a gzip tar
+func pack(members []File, modTime time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, f := range members {
+		hdr := &tar.Header{Name: f.Name, Mode: 0o600, Size: int64(len(f.Data)), ModTime: modTime, Format: tar.FormatPAX}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(f.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unpack reads the members of a gzip tar, in order
+func unpack(payload []byte) ([]File, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	tr := tar.NewReader(io.LimitReader(gz, maxArchiveSize))
+	var members []File
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return members, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("invalid archive: %s is not a regular file", hdr.Name)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive: %w", err)
+		}
+		members = append(members, File{Name: hdr.Name, Data: data})
+	}
+}
+
+// seal encrypts a payload to recipient. The output is the header, the
+// ephemeral public key, the nonce, and the AES-256-GCM ciphertext, with the
+// header and ephemeral key authenticated as additional data.
+func seal(payload []byte, recipient *ecdh.PublicKey) ([]byte, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := archiveAEAD(shared, ephemeral.PublicKey(), recipient)
+	if err != nil {
+		return nil, err
+	}
+	out := append([]byte(headerEncrypted), ephemeral.PublicKey().Bytes()...)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	ad := out
+	out = append(slices.Clip(out), nonce...)
+	return aead.Seal(out, nonce, payload, ad), nil
+}
+
+// open decrypts a sealed payload (after its header) with identity
+func open(sealed []byte, identity *ecdh.PrivateKey) ([]byte, error) {
+	if identity == nil {
+		return nil, errors.New("archive is encrypted: an identity is required to read it")
+	}
+	if len(sealed) < 32 {
+		return nil, errors.New("invalid archive: truncated")
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(sealed[:32])
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	shared, err := identity.ECDH(ephemeral)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	aead, err := archiveAEAD(shared, ephemeral, identity.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	rest := sealed[32:]
+	if len(rest) < aead.NonceSize() {
+		return nil, errors.New("invalid archive: truncated")
+	}
+	ad := append([]byte(headerEncrypted), sealed[:32]...)
+	payload, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], ad)
+	if err != nil {
+		return nil, errors.New("cannot decrypt archive: wrong identity, or the archive was modified")
+	}
+	return payload, nil
+}
+
+// archiveAEAD derives the AES-256-GCM cipher from an X25519 shared secret.
+// The salt binds the key to both public keys.
+func archiveAEAD(shared []byte, ephemeral, recipient *ecdh.PublicKey) (cipher.AEAD, error) {
+	salt := append(slices.Clone(ephemeral.Bytes()), recipient.Bytes()...)
+	key, err := hkdf.Key(sha256.New, shared, salt, hkdfInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Read decrypts an archive, if it is encrypted, and returns its members
+// and whether it was encrypted. It does not verify them (see Verify).
+func Read(data []byte, identity *ecdh.PrivateKey) (members []File, encrypted bool, err error) {
+	header, rest, ok := bytes.Cut(data, []byte("\n"))
+	if !ok {
+		return nil, false, errors.New("not a report archive")
+	}
+	switch string(header) + "\n" {
+	case headerPlain:
+		members, err = unpack(rest)
+	case headerEncrypted:
+		encrypted = true
+		var payload []byte
+		if payload, err = open(rest, identity); err == nil {
+			members, err = unpack(payload)
+		}
+	default:
+		return nil, false, errors.New("not a report archive")
+	}
+	return members, encrypted, err
+}
+
+// Verification is the outcome of verifying an archive
+type Verification struct {
+	// Valid is true if every listed member is present and unmodified, no
+	// unlisted member was added, and the signature, if any, is good
+	Valid     bool      `json:"valid"`
+	Encrypted bool      `json:"encrypted"`
+	Signed    bool      `json:"signed"`
+	Signer    string    `json:"signer,omitempty"`
+	Manifest  *Manifest `json:"manifest,omitempty"`
+	// Problems lists why the archive is not valid
+	Problems []string `json:"problems,omitempty"`
+}
+
+// VerifyOptions control verification
+type VerifyOptions struct {
+	// Identity decrypts encrypted archives
+	Identity *ecdh.PrivateKey
+	// Signer, if set, is the signer ID the archive must be signed by
+	Signer string
+}
+
+// Verify checks an archive's digests and signature. It returns an error
+// only if the archive cannot be read at all; tampering is reported in the
+// Verification.
+func Verify(data []byte, opts VerifyOptions) (*Verification, error) {
+	members, encrypted, err := Read(data, opts.Identity)
+	if err != nil {
+		return nil, err
+	}
+	v := &Verification{Encrypted: encrypted}
+	byName := make(map[string][]byte, len(members))
+	for _, f := range members {
+		if _, dup := byName[f.Name]; dup {
+			v.Problems = append(v.Problems, fmt.Sprintf("%s appears more than once", f.Name))
+		}
+		byName[f.Name] = f.Data
+	}
+
+	manifest, ok := byName[ManifestName]
+	if !ok {
+		return nil, errors.New("invalid archive: no manifest")
+	}
+	var m Manifest
+	if err := json.Unmarshal(manifest, &m); err != nil {
+		return nil, fmt.Errorf("invalid archive manifest: %w", err)
+	}
+	v.Manifest = &m
+
+	listed := map[string]bool{ManifestName: true, SignatureName: true}
+	for _, d := range m.Files {
+		listed[d.Name] = true
+		data, ok := byName[d.Name]
+		if !ok {
+			v.Problems = append(v.Problems, fmt.Sprintf("%s is missing", d.Name))
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != d.SHA256 || int64(len(data)) != d.Size {
+			v.Problems = append(v.Problems, fmt.Sprintf("%s was modified", d.Name))
+		}
+	}
+	for _, f := range members {
+		if !listed[f.Name] {
+			v.Problems = append(v.Problems, fmt.Sprintf("%s is not in the manifest", f.Name))
+		}
+	}
+
+	sig, signed := byName[SignatureName]
+	v.Signed = signed
+	switch {
+	case signed:
+		v.Signer = m.Signer
+		if problem := checkSignature(manifest, sig, m.Signer); problem != "" {
+			v.Problems = append(v.Problems, problem)
+		}
+	case m.Signer != "":
+		v.Problems = append(v.Problems, "the manifest names a signer but the signature is missing")
+	}
+	if opts.Signer != "" {
+		if _, err := parseSignerID(opts.Signer); err != nil {
+			return nil, err
+		}
+		if !signed || m.Signer != opts.Signer {
+			v.Problems = append(v.Problems, "not signed by the expected signer")
+		}
+	}
+	v.Valid = len(v.Problems) == 0
+	return v, nil
+}
+
+// checkSignature verifies the manifest's signature, returning the problem
+// if it does not verify
+func checkSignature(manifest, sig []byte, signer string) string {
+	pub, err := parseSignerID(signer)
+	if err != nil {
+		return "the manifest's signer is invalid"
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || !ed25519.Verify(pub, manifest, raw) {
+		return "the manifest signature does not verify"
+	}
+	return ""
+}
+
+// FormatVerificationTable formats a verification as a colored report
+func FormatVerificationTable(v *Verification) string {
+	return FormatVerificationTableWith(inspector.CurrentStyler(), v)
+}
+
+// FormatVerificationTableWith is like FormatVerificationTable but styles the
+// table with st
+func FormatVerificationTableWith(st inspector.Styler, v *Verification) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(inspector.IconShield + " Report Archive"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	if v.Valid {
+		sb.WriteString(st.Success(inspector.IconCheck + " Archive is intact"))
+	} else {
+		sb.WriteString(st.Danger(inspector.IconCross + " Archive failed verification"))
+	}
+	sb.WriteString("\n\n")
+
+	m := v.Manifest
+	sb.WriteString(st.BoldText("Created: "))
+	sb.WriteString(m.CreatedAt.Local().Format(time.RFC3339))
+	if m.Hostname != "" {
+		sb.WriteString(st.Muted(" on " + m.Hostname))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(st.BoldText("Encrypted: "))
+	sb.WriteString(fmt.Sprintf("%t", v.Encrypted))
+	sb.WriteString("\n")
+	sb.WriteString(st.BoldText("Signer: "))
+	if v.Signed {
+		sb.WriteString(v.Signer)
+	} else {
+		sb.WriteString(st.Warning("unsigned"))
+	}
+	sb.WriteString("\n\n")
+
+	for _, d := range m.Files {
+		sb.WriteString(fmt.Sprintf("  %-20s %8d  %s\n", d.Name, d.Size, st.Muted(d.SHA256)))
+	}
+	if len(v.Problems) > 0 {
+		sb.WriteString("\n")
+		for _, p := range v.Problems {
+			sb.WriteString("  " + st.Danger(inspector.IconCross+" "+p) + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// FormatVerification formats a verification in the specified format
+func FormatVerification(v *Verification, format string) string {
+	return inspector.FormatOutputWith(v, func(st inspector.Styler) string {
+		return FormatVerificationTableWith(st, v)
+	}, format)
+}
@@ -0,0 +1,207 @@
+package archive
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFiles() []File {
+	return []File{
+		{Name: "summary.json", Data: []byte(`{"overall_score": 75}`)},
+		{Name: "collection.log", Data: []byte("level=DEBUG msg=exec cmd=fdesetup\n")},
+	}
+}
+
+func writeArchive(t *testing.T, opts Options) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	m := Manifest{CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), Hostname: "web-01"}
+	if err := Write(&buf, m, testFiles(), opts); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestWriteVerify_Plain(t *testing.T) {
+	data := writeArchive(t, Options{})
+	v, err := Verify(data, VerifyOptions{})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !v.Valid || v.Encrypted || v.Signed || len(v.Manifest.Files) != 2 || v.Manifest.Hostname != "web-01" {
+		t.Errorf("verification = %+v", v)
+	}
+
+	members, _, err := Read(data, nil)
+	if err != nil || len(members) != 3 || members[0].Name != ManifestName || string(members[1].Data) != `{"overall_score": 75}` {
+		t.Errorf("Read = %+v, %v", members, err)
+	}
+}
+
+func TestWriteVerify_SignedEncrypted(t *testing.T) {
+	identity, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	data := writeArchive(t, Options{Recipient: identity.PublicKey(), SigningKey: key})
+	if bytes.Contains(data, []byte("overall_score")) {
+		t.Fatal("encrypted archive contains plaintext")
+	}
+
+	signer := SignerID(key.Public().(ed25519.PublicKey))
+	v, err := Verify(data, VerifyOptions{Identity: identity, Signer: signer})
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !v.Valid || !v.Encrypted || !v.Signed || v.Signer != signer {
+		t.Errorf("verification = %+v", v)
+	}
+
+	// Pinning another signer fails verification
+	_, other, _ := ed25519.GenerateKey(rand.Reader)
+	v, err = Verify(data, VerifyOptions{Identity: identity, Signer: SignerID(other.Public().(ed25519.PublicKey))})
+	if err != nil || v.Valid {
+		t.Errorf("Verify with another signer = %+v, %v", v, err)
+	}
+
+	if _, err := Verify(data, VerifyOptions{}); err == nil || !strings.Contains(err.Error(), "identity is required") {
+		t.Errorf("Verify without identity error = %v", err)
+	}
+	wrong, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	if _, err := Verify(data, VerifyOptions{Identity: wrong}); err == nil || !strings.Contains(err.Error(), "cannot decrypt") {
+		t.Errorf("Verify with wrong identity error = %v", err)
+	}
+
+	// Flipping a ciphertext bit is caught by the authenticated encryption
+	data[len(data)-1] ^= 1
+	if _, err := Verify(data, VerifyOptions{Identity: identity}); err == nil {
+		t.Error("expected error for a modified ciphertext")
+	}
+}
+
+func TestVerify_Tampered(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	members, _, err := Read(writeArchive(t, Options{SigningKey: key}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repack := func(members []File) []byte {
+		payload, err := pack(members, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return append([]byte(headerPlain), payload...)
+	}
+
+	tests := []struct {
+		name   string
+		modify func([]File) []File
+		want   string
+	}{
+		{"modified member", func(m []File) []File {
+			m[2].Data = []byte(`{"overall_score": 100}`)
+			return m
+		}, "summary.json was modified"},
+		{"removed member", func(m []File) []File {
+			return m[:3]
+		}, "collection.log is missing"},
+		{"added member", func(m []File) []File {
+			return append(m, File{Name: "extra.json", Data: []byte("{}")})
+		}, "extra.json is not in the manifest"},
+		{"modified manifest", func(m []File) []File {
+			m[0].Data = bytes.Replace(m[0].Data, []byte("web-01"), []byte("web-02"), 1)
+			return m
+		}, "signature does not verify"},
+		{"stripped signature", func(m []File) []File {
+			return append(m[:1], m[2:]...)
+		}, "signature is missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copied := make([]File, len(members))
+			copy(copied, members)
+			v, err := Verify(repack(tt.modify(copied)), VerifyOptions{})
+			if err != nil {
+				t.Fatalf("Verify failed: %v", err)
+			}
+			if v.Valid || !strings.Contains(strings.Join(v.Problems, "; "), tt.want) {
+				t.Errorf("problems = %v, want %q", v.Problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestRead_NotArchive(t *testing.T) {
+	if _, _, err := Read([]byte(`{"overall_score": 75}`), nil); err == nil {
+		t.Error("expected error for a non-archive")
+	}
+	if _, err := Verify([]byte("omnitrust-archive/1\nnot gzip"), VerifyOptions{}); err == nil {
+		t.Error("expected error for a corrupt archive")
+	}
+}
+
+func TestFormatVerification(t *testing.T) {
+	v, err := Verify(writeArchive(t, Options{}), VerifyOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := FormatVerification(v, "table")
+	for _, want := range []string{"Archive is intact", "unsigned", "collection.log"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestKeys(t *testing.T) {
+	identity, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	recipient := FormatRecipient(identity.PublicKey())
+	if !strings.HasPrefix(recipient, "age1") {
+		t.Errorf("recipient = %q", recipient)
+	}
+	pub, err := ParseRecipient(recipient)
+	if err != nil || !pub.Equal(identity.PublicKey()) {
+		t.Errorf("ParseRecipient = %v, %v", pub, err)
+	}
+	if _, err := ParseRecipient(recipient[:len(recipient)-1] + "q"); err == nil {
+		t.Error("expected error for a bad checksum")
+	}
+
+	secret := strings.ToUpper(bech32Encode(identityHRP, identity.Bytes()))
+	if !strings.HasPrefix(secret, "AGE-SECRET-KEY-1") {
+		t.Errorf("identity = %q", secret)
+	}
+	parsed, err := ParseIdentity(secret)
+	if err != nil || !parsed.Equal(identity) {
+		t.Errorf("ParseIdentity = %v, %v", parsed, err)
+	}
+	// A key in the format age-keygen writes
+	if _, err := ParseIdentity("AGE-SECRET-KEY-1GFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPQ4EGAEX"); err != nil {
+		t.Errorf("ParseIdentity(age key) failed: %v", err)
+	}
+	if _, err := ParseIdentity(recipient); err == nil {
+		t.Error("expected error for a recipient passed as an identity")
+	}
+}
+
+func TestLoadSigningKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "omnitrust", "signing.key")
+	key, created, err := LoadSigningKey(path)
+	if err != nil || !created {
+		t.Fatalf("LoadSigningKey = %v, %v", created, err)
+	}
+	again, created, err := LoadSigningKey(path)
+	if err != nil || created || !again.Equal(key) {
+		t.Errorf("reloaded key = %v, %v; want the stored key", created, err)
+	}
+	if _, err := parseSignerID(SignerID(key.Public().(ed25519.PublicKey))); err != nil {
+		t.Errorf("signer ID did not round-trip: %v", err)
+	}
+}
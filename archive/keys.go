@@ -0,0 +1,258 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// SigningKeyEnv names the Ed25519 key that signs archives, instead of the
+// default location (see SigningKeyPath)
+const SigningKeyEnv = "OMNITRUST_SIGNING_KEY"
+
+// Prefixes of age X25519 recipients and identities, which archives use as
+// their key format so keys made with age-keygen work as is
+const (
+	recipientHRP = "age"
+	identityHRP  = "age-secret-key-"
+)
+
+// ParseRecipient parses an age X25519 recipient ("age1…")
+func ParseRecipient(s string) (*ecdh.PublicKey, error) {
+	hrp, key, err := bech32Decode(strings.TrimSpace(s))
+	if err != nil || hrp != recipientHRP || len(key) != 32 {
+		return nil, fmt.Errorf("invalid recipient %q: want an age X25519 public key (age1…)", s)
+	}
+	return ecdh.X25519().NewPublicKey(key)
+}
+
+// FormatRecipient encodes an X25519 public key as an age recipient
+func FormatRecipient(pub *ecdh.PublicKey) string {
+	return bech32Encode(recipientHRP, pub.Bytes())
+}
+
+// ParseIdentity parses an age X25519 identity ("AGE-SECRET-KEY-1…")
+func ParseIdentity(s string) (*ecdh.PrivateKey, error) {
+	hrp, key, err := bech32Decode(strings.TrimSpace(s))
+	if err != nil || hrp != identityHRP || len(key) != 32 {
+		return nil, errors.New("invalid identity: want an age X25519 secret key (AGE-SECRET-KEY-1…)")
+	}
+	return ecdh.X25519().NewPrivateKey(key)
+}
+
+// LoadIdentity reads the first identity from an age key file, skipping
+// comments
+func LoadIdentity(path string) (*ecdh.PrivateKey, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- user-supplied key file
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return ParseIdentity(line)
+	}
+	return nil, fmt.Errorf("no identity in %s", path)
+}
+
+// SigningKeyPath returns the signing key file: OMNITRUST_SIGNING_KEY, or
+// else signing.key next to the per-user config file
+func SigningKeyPath() string {
+	if path := os.Getenv(SigningKeyEnv); path != "" {
+		return path
+	}
+	if runtime.GOOS == "windows" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return ""
+		}
+		return filepath.Join(dir, "omnitrust", "signing.key")
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "omnitrust", "signing.key")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "omnitrust", "signing.key")
+}
+
+// LoadSigningKey reads the PEM-encoded Ed25519 key at path, generating and
+// storing a new one if the file does not exist. created reports whether
+// the key is new.
+func LoadSigningKey(path string) (key ed25519.PrivateKey, created bool, err error) {
+	if path == "" {
+		return nil, false, errors.New("no signing key path: set " + SigningKeyEnv)
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- configured signing key path
+	if errors.Is(err, os.ErrNotExist) {
+		key, err = generateSigningKey(path)
+		return key, err == nil, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, false, fmt.Errorf("signing key %s is not a PEM private key", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, false, fmt.Errorf("signing key %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, false, fmt.Errorf("signing key %s is not an Ed25519 key", path)
+	}
+	return key, false, nil
+}
+
+// generateSigningKey creates an Ed25519 key and writes it to path
+func generateSigningKey(path string) (ed25519.PrivateKey, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// SignerID identifies a signing key by its base64-encoded public key
+func SignerID(pub ed25519.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(pub)
+}
+
+// parseSignerID decodes a signer ID back into a public key
+func parseSignerID(id string) (ed25519.PublicKey, error) {
+	pub, err := base64.StdEncoding.DecodeString(id)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid signer %q: want a base64 Ed25519 public key", id)
+	}
+	return ed25519.PublicKey(pub), nil
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32Polymod computes the BIP 173 checksum polynomial
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := range 5 {
+			if (top>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HRPExpand expands the human-readable part for the checksum
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := range len(hrp) {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := range len(hrp) {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// convertBits regroups a byte slice from one bit width to another
+func convertBits(data []byte, from, to uint, pad bool) ([]byte, error) {
+	var acc, bits uint
+	var out []byte
+	maxv := uint(1)<<to - 1
+	for _, b := range data {
+		if uint(b)>>from != 0 {
+			return nil, errors.New("invalid data range")
+		}
+		acc = acc<<from | uint(b)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(to-bits)&maxv))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxv != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return out, nil
+}
+
+// bech32Encode encodes data with a lowercase human-readable part. Age
+// keys exceed the 90-character limit of BIP 173, so none is applied.
+func bech32Encode(hrp string, data []byte) string {
+	values, _ := convertBits(data, 8, 5, true)
+	checksum := bech32Polymod(append(append(bech32HRPExpand(hrp), values...), 0, 0, 0, 0, 0, 0)) ^ 1
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range values {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for i := range 6 {
+		sb.WriteByte(bech32Charset[(checksum>>(5*(5-i)))&31])
+	}
+	return sb.String()
+}
+
+// bech32Decode decodes a bech32 string, returning the lowercased
+// human-readable part and the data
+func bech32Decode(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, errors.New("mixed case")
+	}
+	s = strings.ToLower(s)
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, errors.New("invalid separator position")
+	}
+	hrp := s[:pos]
+	values := make([]byte, 0, len(s)-pos-1)
+	for i := pos + 1; i < len(s); i++ {
+		v := strings.IndexByte(bech32Charset, s[i])
+		if v < 0 {
+			return "", nil, errors.New("invalid character")
+		}
+		values = append(values, byte(v))
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), values...)) != 1 {
+		return "", nil, errors.New("invalid checksum")
+	}
+	data, err := convertBits(values[:len(values)-6], 5, 8, false)
+	return hrp, data, err
+}
@@ -1,13 +1,36 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
+	"github.com/agentplexus/posture/config"
+	"github.com/agentplexus/posture/inspector"
 	"github.com/agentplexus/posture/server"
 )
 
 func main() {
+	configPath := flag.String("config", "", "Config file (default ~/.config/omnitrust/config.yaml)")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+		os.Exit(1)
+	}
+	cfg.ApplyEnv()
+	logger, err := inspector.LoggerFromEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+		os.Exit(1)
+	}
+	inspector.SetLogger(logger)
+	if err := inspector.SetLocale(inspector.LocaleFromEnv()); err != nil {
+		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
+		os.Exit(1)
+	}
+
 	if err := server.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/agentplexus/posture/report"
+	"github.com/spf13/cobra"
+)
+
+var baselineFrom string
+
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Record a security baseline that summaries are compared against",
+	Long: `Record the current security posture as a baseline. Every later
+summary then includes delta_from_baseline: the change in score and, per
+check, whether it improved or regressed since the baseline was taken.
+
+The baseline is stored in OMNITRUST_BASELINE (config: baseline), or else in
+baseline.json next to the per-user config file. Export a baseline from a
+reference machine and import it elsewhere to compare a fleet against one
+golden configuration.`,
+}
+
+var baselineSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Record the current security summary as the baseline",
+	Long: `Run the security summary and store its score and check outcomes as the
+baseline. With --from, take them from an exported summary instead:
+  posture summary -f json > golden.json
+  posture baseline set --from golden.json`,
+	Annotations: map[string]string{checksAnnotation: "all"},
+	Run: func(cmd *cobra.Command, args []string) {
+		var summary *inspector.SecuritySummary
+		var err error
+		if baselineFrom != "" {
+			summary, err = report.LoadBundle(baselineFrom)
+		} else {
+			summary, err = inspector.GetSecuritySummaryContext(context.Background())
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+		saveBaseline(inspector.NewBaseline(summary))
+	},
+}
+
+var baselineShowCmd = &cobra.Command{
+	Use:     "show",
+	Aliases: []string{"export"},
+	Short:   "Print the stored baseline",
+	Long: `Print the stored baseline. The JSON output can be imported on other
+machines with 'posture baseline import':
+  posture baseline export -f json > baseline.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		baseline := loadBaseline()
+		fmt.Println(formatted(baseline, inspector.FormatBaseline))
+	},
+}
+
+var baselineImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Store an exported baseline",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := os.ReadFile(args[0]) // #nosec G304 -- user-supplied baseline path
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		baseline, err := inspector.ParseBaseline(data)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		saveBaseline(baseline)
+	},
+}
+
+var baselineClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the stored baseline",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := inspector.BaselinePath()
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Removed the baseline %s\n", path)
+	},
+}
+
+// loadBaseline reads the stored baseline, exiting if there is none
+func loadBaseline() *inspector.Baseline {
+	path := inspector.BaselinePath()
+	baseline, err := inspector.LoadBaseline(path)
+	if err == nil && baseline == nil {
+		err = fmt.Errorf("no baseline at %s: run 'posture baseline set' first", path)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return baseline
+}
+
+// saveBaseline stores a baseline and reports where
+func saveBaseline(baseline *inspector.Baseline) {
+	path := inspector.BaselinePath()
+	if err := inspector.SaveBaseline(path, baseline); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Stored the baseline (score %s, %d checks) in %s\n", inspector.FormatScore(baseline.OverallScore), len(baseline.Checks), path)
+}
+
+func init() {
+	baselineSetCmd.Flags().StringVar(&baselineFrom, "from", "", "Take the baseline from an exported summary (JSON, optionally in a report envelope)")
+	baselineCmd.AddCommand(baselineSetCmd, baselineShowCmd, baselineImportCmd, baselineClearCmd)
+	rootCmd.AddCommand(baselineCmd)
+}
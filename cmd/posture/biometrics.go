@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -16,24 +17,40 @@ var biometricsCmd = &cobra.Command{
 
 Shows Touch ID and Face ID availability and enrollment status.
 This command is only available on macOS.
+
+Use --all-users to list enrollment for every local user, for auditing
+shared workstations. Reading other users' enrollment usually requires
+root or Administrator; users that cannot be read are reported with a
+permission_denied error.
+
 Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckBiometrics},
 	Run: func(cmd *cobra.Command, args []string) {
 		if !inspector.IsBiometricsSupported() {
 			fmt.Fprintln(os.Stderr, "Error: Biometrics are only available on macOS")
 			os.Exit(1)
 		}
+		if !inspector.CheckEnabled(inspector.CheckBiometrics) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckBiometrics)))
+			os.Exit(1)
+		}
 
-		result, err := inspector.GetBiometricCapabilities()
+		result, err := inspector.GetBiometricCapabilitiesWithOptionsContext(context.Background(), inspector.BiometricOptions{
+			AllUsers: bioAllUsersFlag,
+		})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
 			os.Exit(1)
 		}
 
-		output := inspector.FormatBiometricCapabilities(result, formatFlag)
+		output := formatted(result, inspector.FormatBiometricCapabilities)
 		fmt.Println(output)
 	},
 }
 
+var bioAllUsersFlag bool
+
 func init() {
+	biometricsCmd.Flags().BoolVar(&bioAllUsersFlag, "all-users", false, "Enumerate enrollment for all local users")
 	rootCmd.AddCommand(biometricsCmd)
 }
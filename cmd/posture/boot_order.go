@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var bootOrderCmd = &cobra.Command{
+	Use:     "boot-order",
+	Aliases: []string{"bootorder"},
+	Short:   "Show the firmware boot order and external boot state",
+	Long: `Display the firmware boot order and whether booting from removable
+media (USB, optical) or the network (PXE) is allowed ahead of the system
+disk.
+
+Reported on:
+  Linux    the UEFI BootOrder and Boot#### variables in efivarfs
+  Windows  the same UEFI variables through GetFirmwareEnvironmentVariable
+           (requires an elevated prompt)
+  macOS    on Intel Macs, whether a firmware password restricts booting
+           from external media; Apple silicon Macs manage external boot
+           in Startup Security Utility, which cannot be read at runtime
+
+Only active entries ahead of the first disk entry are considered. Use
+--format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckBootOrder},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.CheckEnabled(inspector.CheckBootOrder) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckBootOrder)))
+			os.Exit(1)
+		}
+
+		result, err := inspector.GetBootOrderContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fmt.Println(formatted(result, inspector.FormatBootOrder))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bootOrderCmd)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var browserCmd = &cobra.Command{
+	Use:     "browser",
+	Aliases: []string{"browsers"},
+	Short:   "Show browser security settings",
+	Long: `Display the security settings of installed browsers.
+
+Reads the preference stores of the current user's Chrome, Edge, Firefox,
+and (on macOS) Safari profiles and shows each browser's version and how
+long it has run it, whether Safe Browsing (SmartScreen in Edge) is on,
+whether HTTPS-Only mode blocks insecure downloads, and the installed
+extensions with the store they came from. Under sudo the invoking user's
+profiles are read.
+
+A browser that has kept the same version for over 60 days is reported as
+outdated, since Chrome, Edge, and Firefox release every four weeks.
+
+Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckBrowser},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.CheckEnabled(inspector.CheckBrowser) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckBrowser)))
+			os.Exit(1)
+		}
+
+		result, err := inspector.GetBrowserSecurityContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fmt.Println(formatted(result, inspector.FormatBrowserSecurity))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(browserCmd)
+}
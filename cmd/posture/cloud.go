@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var cloudCmd = &cobra.Command{
+	Use:   "cloud",
+	Short: "Show cloud instance context",
+	Long: `Identify the cloud instance posture is running on.
+
+Queries the instance metadata service for AWS (IMDSv2), Azure, and GCP and
+reports the provider, instance ID and type, region, and zone, along with:
+  - Whether tokenless IMDSv1 requests are still accepted (AWS)
+  - Whether a virtual TPM is enabled
+  - Whether confidential computing (SEV, SEV-SNP, TDX) protects the guest
+
+On Linux, DMI data selects the provider, and hosts whose DMI names no cloud
+vendor are not probed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		result := inspector.GetCloudContext(context.Background())
+		fmt.Println(formatted(result, inspector.FormatCloudContext))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cloudCmd)
+}
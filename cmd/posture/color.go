@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+var (
+	colorFlag   string
+	noColorFlag bool
+	themeFlag   string
+)
+
+// applyColor turns table colors on or off from --color and --no-color and
+// selects the --theme, built in or defined in the config file. In auto
+// mode colors are used only when stdout is a terminal.
+func applyColor() error {
+	theme, err := cfg.ResolveTheme(themeFlag)
+	if err != nil {
+		return err
+	}
+	inspector.SetTheme(theme)
+
+	mode := colorFlag
+	if noColorFlag {
+		mode = inspector.ColorNever
+	}
+	enabled, err := inspector.ResolveColor(mode, isTerminal(os.Stdout))
+	if err != nil {
+		return err
+	}
+	inspector.SetColorEnabled(enabled)
+	return nil
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
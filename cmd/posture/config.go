@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/agentplexus/posture/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	configFlag string
+	// cfg is the loaded config file, set by applyConfig before a command runs
+	cfg = &config.Config{}
+)
+
+// applyConfig loads the config file, exports its settings to the
+// environment, and fills in every flag that was not given on the command
+// line from OMNITRUST_<COMMAND>_<FLAG> (or OMNITRUST_<FLAG> for global
+// flags), then from the file
+func applyConfig(cmd *cobra.Command) error {
+	c, err := config.Load(configFlag)
+	if err != nil {
+		return err
+	}
+	cfg = c
+	c.ApplyEnv()
+
+	key := commandKey(cmd)
+	var errs []error
+	for name := range c.Commands[key] {
+		if cmd.Flags().Lookup(name) == nil {
+			errs = append(errs, fmt.Errorf("config %s: commands.%s.%s: unknown flag", c.Path, key, name))
+		}
+	}
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed || f.Name == "config" || f.Name == "help" {
+			return
+		}
+		value, ok := os.LookupEnv(config.FlagEnv(key, f.Name))
+		if !ok && cmd.Root().PersistentFlags().Lookup(f.Name) != nil {
+			value, ok = os.LookupEnv(config.FlagEnv("", f.Name))
+		}
+		if !ok {
+			value, ok = c.FlagValue(key, f.Name)
+		}
+		if !ok {
+			return
+		}
+		if err := cmd.Flags().Set(f.Name, value); err != nil {
+			errs = append(errs, fmt.Errorf("--%s: %w", f.Name, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// commandKey names a command in the config file and environment: its path
+// below the root with spaces replaced by underscores
+func commandKey(cmd *cobra.Command) string {
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name())
+	return strings.ReplaceAll(strings.TrimSpace(path), " ", "_")
+}
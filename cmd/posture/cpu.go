@@ -4,30 +4,36 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/agentplexus/posture/inspector"
 	"github.com/spf13/cobra"
 )
 
+var cpuInterval time.Duration
+
 var cpuCmd = &cobra.Command{
 	Use:   "cpu",
 	Short: "Show CPU usage",
 	Long: `Display current system CPU usage.
 
-Shows overall CPU usage percentage and per-core usage statistics.
+Shows overall CPU usage percentage and per-core usage statistics,
+sampled over --interval (default 500ms), with core counts and load
+averages. An interval of 0 reports usage since boot.
 Use --format=table for a colored ASCII table with progress bars.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		result, err := inspector.GetCPUUsage(context.Background())
+		result, err := inspector.GetCPUUsageWithInterval(context.Background(), cpuInterval)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
 			os.Exit(1)
 		}
 
-		output := inspector.FormatCPUUsage(result, formatFlag)
+		output := formatted(result, inspector.FormatCPUUsage)
 		fmt.Println(output)
 	},
 }
 
 func init() {
+	cpuCmd.Flags().DurationVarP(&cpuInterval, "interval", "i", inspector.DefaultCPUSampleInterval, "Sampling window (e.g. 1s); 0 for usage since boot")
 	rootCmd.AddCommand(cpuCmd)
 }
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var defenderCmd = &cobra.Command{
+	Use:     "defender",
+	Aliases: []string{"av"},
+	Short:   "Show Microsoft Defender status (Windows)",
+	Long: `Display the Microsoft Defender Antivirus configuration.
+
+Shows whether the antivirus and real-time, cloud-delivered, and tamper
+protection are on, how old the antivirus signatures are, when the last
+quick and full scans completed, and which attack surface reduction (ASR)
+rules are enforced. Values are read from Defender's WMI provider
+(root\Microsoft\Windows\Defender).
+
+When another antivirus product is primary, Defender runs in passive mode
+and its own settings are not reported as problems.
+
+Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckDefender},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.IsDefenderSupported() {
+			fmt.Fprintln(os.Stderr, "Error: Microsoft Defender is only checked on Windows")
+			os.Exit(1)
+		}
+		if !inspector.CheckEnabled(inspector.CheckDefender) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckDefender)))
+			os.Exit(1)
+		}
+
+		result, err := inspector.GetDefenderStatusContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fmt.Println(formatted(result, inspector.FormatDefender))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(defenderCmd)
+}
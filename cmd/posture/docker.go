@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var dockerCmd = &cobra.Command{
+	Use:     "container-security",
+	Aliases: []string{"docker"},
+	Short:   "Show Docker daemon security settings",
+	Long: `Display the security configuration of the Docker daemon.
+
+Reads docker info, daemon.json, the docker.service unit on Linux, and
+Docker Desktop's settings, and shows whether the daemon listens on TCP
+without requiring TLS client certificates, whether container root is
+isolated from host root (rootless mode, userns-remap, or Docker Desktop's
+VM), whether live-restore is on, any insecure registries, and running
+containers started with --privileged.
+
+Machines without Docker are reported as not installed. Talking to the
+daemon usually requires root or membership in the docker group.
+
+Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckDocker},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.CheckEnabled(inspector.CheckDocker) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckDocker)))
+			os.Exit(1)
+		}
+
+		result, err := inspector.GetContainerSecurityContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fmt.Println(formatted(result, inspector.FormatContainerSecurity))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dockerCmd)
+}
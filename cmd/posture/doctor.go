@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/agentplexus/posture/server"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that this machine can run every check completely",
+	Long: `Verify the environment the security checks depend on and explain which
+results will be degraded.
+
+doctor checks that:
+  - the external tools each check runs are installed (for example
+    dmsetup and cryptsetup on Linux, fdesetup and diskutil on macOS)
+  - the process has the privileges the checks need
+  - the WMI namespaces the checks query are reachable (Windows)
+  - the MCP server answers over an in-memory session, and the HTTP
+    address is free when the http transport is configured
+
+It then runs each enabled check once and lists the checks whose results
+are degraded, with the reason and a hint. Start here when results show
+"unknown".
+
+Exits with code 1 if an item is in error; warnings do not change the exit
+code.`,
+	Annotations: map[string]string{checksAnnotation: "all"},
+	Run: func(cmd *cobra.Command, args []string) {
+		result := inspector.DoctorContext(context.Background())
+		for _, item := range server.DoctorItems(context.Background(), server.DefaultOptions()) {
+			result.Add(item)
+		}
+		fmt.Println(formatted(result, inspector.FormatDoctor))
+		if !result.Healthy {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+// checksAnnotation names the checks a command runs, as a comma-separated
+// list of check IDs or "all"
+const checksAnnotation = "omnitrust.checks"
+
+// ownDryRunAnnotation marks commands that implement --dry-run themselves
+// instead of printing the access plan
+const ownDryRunAnnotation = "omnitrust.own-dry-run"
+
+var dryRunFlag bool
+
+// preRun runs before every command: it applies the config file, logging,
+// color, template, language, and redaction settings, then --dry-run prints the access plan and exits,
+// otherwise --sudo may re-execute the command elevated
+func preRun(cmd *cobra.Command, args []string) {
+	if err := applyConfig(cmd); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+		os.Exit(1)
+	}
+	if err := applyLog(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+		os.Exit(1)
+	}
+	if err := applyColor(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+		os.Exit(1)
+	}
+	if err := applyFixture(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+		os.Exit(1)
+	}
+	if err := applyProfile(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+		os.Exit(1)
+	}
+	if err := applyTemplate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+		os.Exit(1)
+	}
+	if err := applyLang(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+		os.Exit(1)
+	}
+	if err := applyRedact(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+		os.Exit(1)
+	}
+	inspector.SetEnvelope(envelopeFlag)
+	inspector.SetProvenance(explainFlag)
+	if dryRunFlag && cmd.Annotations[ownDryRunAnnotation] == "" {
+		fmt.Println(formatted(inspector.DryRun(commandChecks(cmd)), inspector.FormatDryRun))
+		os.Exit(0)
+	}
+	elevate(cmd, args)
+}
+
+// commandChecks returns the check IDs a command would run
+func commandChecks(cmd *cobra.Command) []string {
+	checks := cmd.Annotations[checksAnnotation]
+	switch checks {
+	case "":
+		return nil
+	case "all":
+		// The summary also reports the informational keychain check and
+		// probes the environment it runs in
+		return append(append(inspector.PlatformChecks(), inspector.CheckKeychain), inspector.EnvironmentProbes()...)
+	}
+	return strings.Split(checks, ",")
+}
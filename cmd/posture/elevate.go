@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var sudoFlag bool
+
+// elevate re-executes the current command under sudo so that privileged
+// probes (bputil, fdesetup, dmsetup, efivars) return complete results.
+// It does nothing if --sudo was not given or the process is already elevated.
+func elevate(cmd *cobra.Command, args []string) {
+	if !sudoFlag || inspector.IsElevated() {
+		return
+	}
+
+	if runtime.GOOS == "windows" {
+		fmt.Fprintln(os.Stderr, "Error: --sudo is not supported on Windows; run from an elevated (Run as Administrator) prompt")
+		os.Exit(1)
+	}
+
+	sudo, err := exec.LookPath("sudo")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: --sudo requires sudo to be installed")
+		os.Exit(1)
+	}
+	self, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Drop --sudo so the elevated child does not try to elevate again
+	var childArgs []string
+	for _, arg := range os.Args[1:] {
+		if arg != "--sudo" && arg != "--sudo=true" {
+			childArgs = append(childArgs, arg)
+		}
+	}
+
+	// #nosec G204 -- re-executing our own binary with the user's arguments
+	child := exec.Command(sudo, append([]string{"--", self}, childArgs...)...)
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+	if err := child.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
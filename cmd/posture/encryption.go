@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -21,19 +22,24 @@ On Linux, this shows LUKS/dm-crypt encryption status.
 Shows whether encryption is enabled and lists encrypted volumes.
 
 Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckEncryption},
 	Run: func(cmd *cobra.Command, args []string) {
 		if !inspector.IsEncryptionSupported() {
 			fmt.Fprintln(os.Stderr, "Error: Encryption status not supported on this platform")
 			os.Exit(1)
 		}
+		if !inspector.CheckEnabled(inspector.CheckEncryption) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckEncryption)))
+			os.Exit(1)
+		}
 
-		result, err := inspector.GetEncryptionStatus()
+		result, err := inspector.GetEncryptionStatusContext(context.Background())
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
 			os.Exit(1)
 		}
 
-		output := inspector.FormatEncryption(result, formatFlag)
+		output := formatted(result, inspector.FormatEncryption)
 		fmt.Println(output)
 	},
 }
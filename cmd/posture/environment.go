@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var environmentCmd = &cobra.Command{
+	Use:     "environment",
+	Aliases: []string{"env"},
+	Short:   "Show whether posture is running in a container or WSL",
+	Long: `Detect the runtime environment.
+
+Reports whether posture is running inside a container (Docker, Podman,
+Kubernetes, containerd, LXC) and the evidence behind the verdict: marker
+files, environment variables, cgroup paths, and an overlay root filesystem.
+
+Inside a container the summary skips host-only checks (TPM, Secure Boot,
+disk encryption, biometrics) and reports them as not_applicable_in_container.
+Set OMNITRUST_ASSUME_HOST=1 to run them anyway when host devices and mounts
+are passed through.
+
+Under WSL the host-only checks describe the Linux guest and are not scored.
+With interop enabled, the Windows host's TPM, Secure Boot, and BitLocker
+state are read through powershell.exe.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		result := inspector.GetRuntimeEnvironmentContext(context.Background())
+		fmt.Println(formatted(result, inspector.FormatRuntimeEnvironment))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(environmentCmd)
+}
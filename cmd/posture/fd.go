@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var fdTop int
+
+var fdCmd = &cobra.Command{
+	Use:     "fds",
+	Aliases: []string{"fd-usage", "ulimit"},
+	Short:   "Show open file descriptors and limits",
+	Long: `Display open file descriptor usage.
+
+Shows open files against the system-wide limit (Linux, macOS), the
+processes holding the most descriptors with their share of their own
+nofile limit, and this process's nofile, nproc, memlock, stack, and core
+ulimits. On Windows the per-process counts are handles.
+
+Without root only the current user's processes are counted.
+Use --format=table for a colored ASCII table.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		result, err := inspector.GetFDUsage(context.Background(), fdTop)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		printPaged(formatted(result, inspector.FormatFDUsage))
+	},
+}
+
+func init() {
+	fdCmd.Flags().IntVarP(&fdTop, "top", "t", inspector.DefaultFDTopN, "Number of processes to list")
+	rootCmd.AddCommand(fdCmd)
+}
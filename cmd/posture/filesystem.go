@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fsAuditAllow   []string
+	fsAuditPaths   []string
+	fsAuditTimeout time.Duration
+)
+
+var auditFilesystemCmd = &cobra.Command{
+	Use:     "audit-filesystem",
+	Aliases: []string{"fs-audit"},
+	Short:   "Audit SUID/SGID binaries and world-writable PATH directories (Linux)",
+	Long: `Search for unexpected SUID and SGID binaries and for directories in PATH
+that any user can write to.
+
+SUID/SGID binaries are searched for in /bin, /sbin, /usr/bin, /usr/sbin,
+/usr/local/bin, /usr/local/sbin, /usr/libexec, /usr/lib, /opt, and every
+PATH directory, up to three levels deep. Binaries that distributions ship
+SUID (passwd, sudo, su, mount, pkexec, ...) are allowed; add your own with
+--allow, as base names, absolute paths, or globs such as /opt/vendor/*.
+
+PATH directories and their parents are reported if they are
+world-writable, and empty or relative PATH entries are reported since
+they search the current directory.
+
+The audit stops after --timeout and reports what it found so far. Use
+--path to search other directories instead of the defaults.
+
+Use --format=table for a colored listing.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckFilesystem},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.IsFilesystemAuditSupported() {
+			fmt.Fprintln(os.Stderr, "Error: the filesystem audit is only available on Linux")
+			os.Exit(1)
+		}
+		if !inspector.CheckEnabled(inspector.CheckFilesystem) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckFilesystem)))
+			os.Exit(1)
+		}
+
+		opts := inspector.DefaultFilesystemAuditOptions()
+		opts.Allowlist = append(opts.Allowlist, fsAuditAllow...)
+		if len(fsAuditPaths) > 0 {
+			opts.Paths = fsAuditPaths
+		}
+		if fsAuditTimeout > 0 {
+			opts.Timeout = fsAuditTimeout
+		}
+
+		result, err := inspector.AuditFilesystemContext(context.Background(), opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		printPaged(formatted(result, inspector.FormatFilesystemAudit))
+	},
+}
+
+func init() {
+	auditFilesystemCmd.Flags().StringSliceVar(&fsAuditAllow, "allow", nil, "Allowed SUID/SGID binary: base name, absolute path, or glob (repeatable)")
+	auditFilesystemCmd.Flags().StringSliceVar(&fsAuditPaths, "path", nil, "Directory to search for SUID/SGID binaries instead of the defaults (repeatable)")
+	auditFilesystemCmd.Flags().DurationVar(&fsAuditTimeout, "timeout", 0, "Stop the audit after this long (default 10s)")
+	rootCmd.AddCommand(auditFilesystemCmd)
+}
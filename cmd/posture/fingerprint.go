@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fingerprintHashed bool
+	fingerprintSalt   string
+)
+
+var fingerprintCmd = &cobra.Command{
+	Use:     "fingerprint",
+	Aliases: []string{"fp", "device-id"},
+	Short:   "Show a stable device fingerprint",
+	Long: `Compute a stable device identifier for correlating snapshots and fleet
+records. The fingerprint is the SHA-256 of the first identifier that can be
+read, in this order:
+
+  platform_uuid  SMBIOS system UUID (root on Linux; IOPlatformUUID on macOS)
+  tpm_ek         hash of the TPM endorsement key (Linux, Windows)
+  serial_number  system, board, or chassis serial number
+  machine_id     OS installation ID (/etc/machine-id, MachineGuid)
+
+Run with the same privileges on every host so that the same source is used.
+--hashed replaces the identifiers in the output with their hashes, and
+--salt (or OMNITRUST_FINGERPRINT_SALT) keys every hash with a secret so
+that fingerprints cannot be correlated outside the fleet. The fingerprint is
+also included in report envelopes (--envelope).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := inspector.DefaultFingerprintOptions()
+		opts.Hashed = fingerprintHashed
+		if fingerprintSalt != "" {
+			opts.Salt = fingerprintSalt
+		}
+
+		result, err := inspector.GetFingerprintContext(context.Background(), opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fmt.Println(formatted(result, inspector.FormatFingerprint))
+	},
+}
+
+func init() {
+	fingerprintCmd.Flags().BoolVar(&fingerprintHashed, "hashed", false, "Report hashes of the identifiers instead of their raw values")
+	fingerprintCmd.Flags().StringVar(&fingerprintSalt, "salt", "", "Secret that keys the fingerprint and hashes (default from OMNITRUST_FINGERPRINT_SALT)")
+	rootCmd.AddCommand(fingerprintCmd)
+}
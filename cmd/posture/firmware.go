@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var firmwareCmd = &cobra.Command{
+	Use:     "firmware",
+	Aliases: []string{"fwupd"},
+	Short:   "Show firmware version and update status",
+	Long: `Display the system firmware vendor, version, and release date along
+with pending and failed firmware updates.
+
+Update status is read from:
+  Linux    fwupd (fwupdmgr get-updates and fwupdmgr security), including the
+           HSI (Host Security ID) rating and its hardware security attributes
+  Windows  UEFI capsule update state in the ESRT (EFI System Resource Table)
+  macOS    the installed firmware version against the one the running macOS
+           expects
+
+Available updates are a medium-severity finding, or high when the vendor marks
+them high or critical urgency. Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckFirmware},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.CheckEnabled(inspector.CheckFirmware) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckFirmware)))
+			os.Exit(1)
+		}
+
+		result, err := inspector.GetFirmwareStatusContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fmt.Println(formatted(result, inspector.FormatFirmwareStatus))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(firmwareCmd)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fixFindings []string
+	fixAll      bool
+	fixYes      bool
+)
+
+var fixCmd = &cobra.Command{
+	Use:   "fix",
+	Short: "Apply remediation commands for findings",
+	Long: `Run the remediation command of one or more security findings.
+
+Without --finding or --all, fix lists the findings on this machine that
+have a remediation command. With --dry-run, it prints the commands it
+would run. Otherwise every command is shown and run only after you
+confirm it; --yes confirms all of them, for scripted use.
+
+Some remediations prompt for credentials (FileVault, BitLocker) or print
+a recovery password to keep (BitLocker); read each command before
+confirming it. Commands that reboot the machine (a pending restart, or
+the firmware settings for Secure Boot) are left out of --all and run
+last, only when their finding is named with --finding.
+
+Examples:
+  posture fix
+  posture fix --finding encryption_disabled --dry-run
+  posture fix --finding encryption_disabled`,
+	Annotations: map[string]string{checksAnnotation: "all", ownDryRunAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		summary, err := inspector.GetSecuritySummaryContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fixable := inspector.FixableFindings(summary.Findings)
+		if len(fixFindings) == 0 && !fixAll {
+			fmt.Println(formatted(&inspector.FindingsResult{Findings: fixable}, inspector.FormatFindings))
+			return
+		}
+		selected := fixable
+		if !fixAll {
+			selected, err = inspector.SelectFindings(summary.Findings, fixFindings)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+				os.Exit(1)
+			}
+		}
+		// Commands that reboot run last, and only when named with --finding
+		safe, disruptive := inspector.SplitDisruptive(selected)
+		selected = safe
+		if fixAll {
+			for _, f := range disruptive {
+				fmt.Fprintf(os.Stderr, "Skipping %s: its command reboots the machine; run it with --finding %s\n", f.ID, f.ID)
+			}
+		} else {
+			selected = append(selected, disruptive...)
+		}
+		if dryRunFlag || len(selected) == 0 {
+			fmt.Println(formatted(&inspector.FindingsResult{Findings: selected}, inspector.FormatFindings))
+			return
+		}
+		if !fixYes && !isTerminal(os.Stdin) {
+			fmt.Fprintln(os.Stderr, "Error: fix needs confirmation; run it from a terminal or pass --yes")
+			os.Exit(1)
+		}
+
+		failed := false
+		in := bufio.NewReader(os.Stdin)
+		for _, f := range selected {
+			fmt.Printf("%s: %s\n  $ %s\n", f.ID, f.Title, f.RemediationCommand)
+			if f.Disruptive {
+				fmt.Println("  This command reboots the machine.")
+			}
+			if !fixYes && !confirm(in, "Run this command?") {
+				fmt.Println("  skipped")
+				continue
+			}
+			if err := runRemediation(f); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s: %v\n", f.ID, err)
+				failed = true
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// confirm asks a yes/no question on stdout and reads the answer from in;
+// anything but y or yes is no
+func confirm(in *bufio.Reader, question string) bool {
+	fmt.Printf("  %s [y/N] ", question)
+	answer, _ := in.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+// runRemediation runs a finding's remediation command attached to the
+// terminal, since many of them prompt for a password or recovery key
+func runRemediation(f inspector.Finding) error {
+	args := f.RemediationArgs()
+	if len(args) == 0 {
+		return errors.New("no remediation command")
+	}
+	// #nosec G204 -- the command comes from the built-in remediation table
+	c := exec.Command(args[0], args[1:]...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+func init() {
+	fixCmd.Flags().StringSliceVar(&fixFindings, "finding", nil, "Finding ID to fix (repeatable), e.g. encryption_disabled")
+	fixCmd.Flags().BoolVar(&fixAll, "all", false, "Fix every finding that has a remediation command, except those that reboot")
+	fixCmd.Flags().BoolVarP(&fixYes, "yes", "y", false, "Run the commands without asking for confirmation")
+	rootCmd.AddCommand(fixCmd)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+var fixtureFlag string
+
+// applyFixture serves results from the --fixture directory (which
+// applyConfig fills from OMNITRUST_FIXTURE) instead of probing the host
+func applyFixture() error {
+	if fixtureFlag == "" {
+		return nil
+	}
+	if fi, err := os.Stat(fixtureFlag); err != nil || !fi.IsDir() {
+		return fmt.Errorf("--fixture %s is not a directory", fixtureFlag)
+	}
+	return os.Setenv(inspector.FixtureEnv, fixtureFlag)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/report"
+	"github.com/spf13/cobra"
+)
+
+var fleetCmd = &cobra.Command{
+	Use:   "fleet",
+	Short: "Audit security reports collected from many hosts",
+}
+
+var fleetReportCmd = &cobra.Command{
+	Use:   "report <bundle...>",
+	Short: "Aggregate exported summaries into a fleet audit",
+	Long: `Aggregate security summaries collected from many hosts into one audit:
+the score distribution, the findings ordered by how many hosts report
+them, and, per failing check, the hosts that are not compliant.
+
+When several enveloped reports share a machine ID, only the newest counts,
+so a directory of periodic reports can be passed as is:
+  omnitrust fleet report -f table reports/*.json
+  omnitrust fleet report -f html reports/*.json > audit.html`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		fleet, err := report.Fleet(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		output, err := report.FormatFleet(fleet, formatFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		printPaged(output)
+	},
+}
+
+func init() {
+	fleetCmd.AddCommand(fleetReportCmd)
+	rootCmd.AddCommand(fleetCmd)
+}
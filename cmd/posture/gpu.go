@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var gpuCmd = &cobra.Command{
+	Use:     "gpu",
+	Aliases: []string{"gpus", "graphics"},
+	Short:   "Show GPU inventory and usage",
+	Long: `List GPUs with their model, VRAM, and driver version, and their
+utilization where the platform exposes it (amdgpu sysfs, IOAccelerator on
+macOS, nvidia-smi for NVIDIA GPUs).
+Use --format=table for a colored ASCII table.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		result, err := inspector.GetGPUInfo(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		output := formatted(result, inspector.FormatGPUInfo)
+		fmt.Println(output)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gpuCmd)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var (
+	hardenMinSeverity string
+	hardenLog         string
+)
+
+var hardenCmd = &cobra.Command{
+	Use:   "harden",
+	Short: "Walk through findings and fix them interactively",
+	Long: `Walk through the security findings on this machine one at a time, most
+severe first. Each finding is explained along with how to fix it; for
+findings with a remediation command, harden shows the command and runs it
+only if you choose to apply it.
+
+Every remediation that is applied, fails, or is skipped is appended to a
+log as a JSON line (default harden.log next to the config file, or
+OMNITRUST_HARDEN_LOG), so there is a record of what was changed. Some
+fixes only take effect after a reboot; run summary afterwards to verify.
+
+Use fix to apply remediations without the walkthrough, e.g. from scripts.
+
+Examples:
+  posture harden
+  posture harden --min-severity high`,
+	Annotations: map[string]string{checksAnnotation: "all"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !isTerminal(os.Stdin) {
+			fmt.Fprintln(os.Stderr, "Error: harden is interactive; run it from a terminal, or use fix --yes")
+			os.Exit(1)
+		}
+
+		summary, err := inspector.GetSecuritySummaryContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+		findings := summary.Findings
+		if hardenMinSeverity != "" {
+			if findings, err = inspector.FilterFindings(findings, hardenMinSeverity); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+				os.Exit(1)
+			}
+		}
+		inspector.SortFindings(findings)
+		// A reboot ends the walk, so findings fixed by one come last
+		safe, disruptive := inspector.SplitDisruptive(findings)
+		findings = append(safe, disruptive...)
+		if len(findings) == 0 {
+			fmt.Println(inspector.Success(inspector.IconCheck + " No findings, nothing to harden."))
+			return
+		}
+
+		logPath := hardenLog
+		if logPath == "" {
+			logPath = inspector.HardeningLogPath()
+		}
+		counts := map[string]int{}
+		in := bufio.NewReader(os.Stdin)
+	walk:
+		for i, f := range findings {
+			printHardeningStep(i+1, len(findings), f)
+			if f.RemediationCommand == "" {
+				if choose(in, "[n]ext, [q]uit", "n", "q") == "q" {
+					break walk
+				}
+				continue
+			}
+
+			var action string
+			var runErr error
+			switch choose(in, "[a]pply, [s]kip, [q]uit", "s", "a", "q") {
+			case "q":
+				break walk
+			case "a":
+				action = inspector.HardeningApplied
+				if runErr = runRemediation(f); runErr != nil {
+					action = inspector.HardeningFailed
+					fmt.Fprintf(os.Stderr, "Error: %s: %v\n", f.ID, runErr)
+				}
+			default:
+				action = inspector.HardeningSkipped
+			}
+			counts[action]++
+			if err := inspector.AppendHardeningRecord(logPath, inspector.NewHardeningRecord(f, action, runErr)); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not record the change in %s: %v\n", logPath, err)
+			}
+		}
+
+		fmt.Printf("\n%d applied, %d failed, %d skipped.\n",
+			counts[inspector.HardeningApplied], counts[inspector.HardeningFailed], counts[inspector.HardeningSkipped])
+		if len(counts) > 0 {
+			fmt.Println(inspector.Muted("Recorded in " + logPath + ". Run summary to verify the changes."))
+		}
+		if counts[inspector.HardeningFailed] > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// printHardeningStep shows a finding with why its check matters and how to
+// fix it
+func printHardeningStep(n, total int, f inspector.Finding) {
+	fmt.Printf("\n%s %s %s\n", inspector.Muted(fmt.Sprintf("[%d/%d]", n, total)), severityLabel(f.Severity), inspector.BoldText(f.Title))
+	if why := inspector.CheckExplanation(f.Check); why != "" {
+		fmt.Printf("  %s\n", why)
+	}
+	fmt.Printf("  %s %s\n", inspector.Header("Fix:"), f.Remediation)
+	if f.RemediationCommand != "" {
+		fmt.Printf("  %s\n", inspector.Info("$ "+f.RemediationCommand))
+	}
+	if f.Disruptive {
+		fmt.Printf("  %s\n", inspector.Warning("This command reboots the machine."))
+	}
+}
+
+// severityLabel colors a severity by how urgent it is
+func severityLabel(severity string) string {
+	label := strings.ToUpper(severity)
+	switch severity {
+	case inspector.SeverityCritical, inspector.SeverityHigh:
+		return inspector.Danger(label)
+	case inspector.SeverityMedium:
+		return inspector.Warning(label)
+	}
+	return inspector.Info(label)
+}
+
+// choose asks for one of choices, identified by their first letter, and
+// returns it; an empty or unknown answer is def
+func choose(in *bufio.Reader, prompt, def string, choices ...string) string {
+	fmt.Printf("  %s ", prompt)
+	answer, err := in.ReadString('\n')
+	if err != nil && answer == "" {
+		// End of input: stop rather than loop over the remaining findings
+		return "q"
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	for _, c := range append(choices, def) {
+		if answer != "" && strings.HasPrefix(c, answer[:1]) {
+			return c
+		}
+	}
+	return def
+}
+
+func init() {
+	hardenCmd.Flags().StringVar(&hardenMinSeverity, "min-severity", "", "Only walk through findings at least this severe: critical, high, medium, or low")
+	hardenCmd.Flags().StringVar(&hardenLog, "log", "", "Append the record of changes to this file (default harden.log next to the config file)")
+	rootCmd.AddCommand(hardenCmd)
+}
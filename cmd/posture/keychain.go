@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var keychainCmd = &cobra.Command{
+	Use:     "keychain",
+	Aliases: []string{"credentials", "password-managers"},
+	Short:   "Show credential store protection and password managers",
+	Long: `Display how the current user's OS credential store is protected and
+which password managers are installed.
+
+Reported on:
+  macOS    whether the login keychain locks on sleep and after how long
+           idle (security show-keychain-info)
+  Windows  whether the Credential Manager service is enabled, whether
+           DPAPI works under the user's master key, and how many
+           credentials are saved
+  Linux    GNOME Keyring or KWallet, and whether PAM unlocks it at login
+
+Password managers are found as applications and as browser extensions.
+The result is informational and never scored. Use --format=table for a
+colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckKeychain},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.CheckEnabled(inspector.CheckKeychain) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckKeychain)))
+			os.Exit(1)
+		}
+
+		result, err := inspector.GetKeychainContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fmt.Println(formatted(result, inspector.FormatKeychain))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(keychainCmd)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var kubeletCmd = &cobra.Command{
+	Use:     "kubelet",
+	Aliases: []string{"k8s-node"},
+	Short:   "Check the kubelet against CIS Kubernetes node controls (Linux)",
+	Long: `Check the security configuration of a Kubernetes node's kubelet.
+
+Finds the running kubelet in /proc, reads its flags and its config file
+(/var/lib/kubelet/config.yaml by default), and evaluates the CIS Kubernetes
+Benchmark worker node recommendations that can be checked from the node:
+
+  4.1.5   kubelet.conf permissions are 600 or more restrictive
+  4.1.9   kubelet config file permissions are 600 or more restrictive
+  4.2.1   anonymous authentication is disabled
+  4.2.2   authorization mode is not AlwaysAllow
+  4.2.4   the read-only port is disabled
+  4.2.10  client certificate rotation is enabled
+
+The containerd and CRI-O sockets are reported if any local user can
+connect to them. Machines without a kubelet are reported as not detected.
+
+In a node agent pod, mount the node's root filesystem and set
+OMNITRUST_HOST_ROOT to the mount point.
+
+Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckKubelet},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.IsKubeletSupported() {
+			fmt.Fprintln(os.Stderr, "Error: the kubelet check is only available on Linux")
+			os.Exit(1)
+		}
+		if !inspector.CheckEnabled(inspector.CheckKubelet) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckKubelet)))
+			os.Exit(1)
+		}
+
+		result, err := inspector.GetKubeletSecurityContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fmt.Println(formatted(result, inspector.FormatKubeletSecurity))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(kubeletCmd)
+}
@@ -0,0 +1,15 @@
+package main
+
+import "github.com/agentplexus/posture/inspector"
+
+var langFlag string
+
+// applyLang selects the output language from --lang (which applyConfig fills
+// from OMNITRUST_LANG or the config file), or else from the system locale
+func applyLang() error {
+	lang := langFlag
+	if lang == "" {
+		lang = inspector.LocaleFromEnv()
+	}
+	return inspector.SetLocale(lang)
+}
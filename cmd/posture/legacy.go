@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var legacyProtocolsCmd = &cobra.Command{
+	Use:     "legacy-protocols",
+	Aliases: []string{"legacy"},
+	Short:   "Show SMBv1, NTLMv1, LLMNR, and NetBIOS exposure (Windows)",
+	Long: `Display legacy network protocols that attackers use to move laterally.
+
+Shows whether the SMB server accepts SMBv1 and the SMBv1 client is
+installed, whether LM and NTLMv1 responses are sent (LmCompatibilityLevel
+below 3), whether LLMNR multicast name resolution is on, and which
+network interfaces have NetBIOS over TCP/IP enabled. Values are read
+from the registry.
+
+Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckLegacyProtocols},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.IsLegacyProtocolsSupported() {
+			fmt.Fprintln(os.Stderr, "Error: legacy protocols are only checked on Windows")
+			os.Exit(1)
+		}
+		if !inspector.CheckEnabled(inspector.CheckLegacyProtocols) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckLegacyProtocols)))
+			os.Exit(1)
+		}
+
+		result, err := inspector.GetLegacyProtocolsContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fmt.Println(formatted(result, inspector.FormatLegacyProtocols))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(legacyProtocolsCmd)
+}
@@ -0,0 +1,20 @@
+package main
+
+import "github.com/agentplexus/posture/inspector"
+
+var (
+	logLevelFlag  string
+	logFormatFlag string
+	logFileFlag   string
+)
+
+// applyLog sends probe and server logs to stderr or --log-file. Logs never
+// go to stdout, which carries results and the stdio MCP transport.
+func applyLog() error {
+	logger, err := inspector.OpenLogger(logLevelFlag, logFormatFlag, logFileFlag)
+	if err != nil {
+		return err
+	}
+	inspector.SetLogger(logger)
+	return nil
+}
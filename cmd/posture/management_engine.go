@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var managementEngineCmd = &cobra.Command{
+	Use:     "management-engine",
+	Aliases: []string{"amt", "psp"},
+	Short:   "Show Intel ME / AMT and AMD PSP state",
+	Long: `Display the platform's management coprocessor: the Intel Management
+Engine (ME) with Active Management Technology (AMT), or the AMD Platform
+Security Processor (PSP), with its firmware version.
+
+Reported on:
+  Linux    the MEI device in sysfs (firmware version, operation and
+           manufacturing modes, whether the firmware includes AMT), the
+           AMD PSP attributes of the ccp driver, and AMT ports listening
+           in /proc/net/tcp
+  Windows  the Management Engine Interface or AMD PSP device and AMT ports
+           listening in MSFT_NetTCPConnection (WMI)
+
+AMT listening on its plain HTTP ports (16992, 16994) is a high-severity
+finding. Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckManagementEngine},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.CheckEnabled(inspector.CheckManagementEngine) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckManagementEngine)))
+			os.Exit(1)
+		}
+
+		result, err := inspector.GetManagementEngineContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fmt.Println(formatted(result, inspector.FormatManagementEngine))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(managementEngineCmd)
+}
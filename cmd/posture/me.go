@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var meCmd = &cobra.Command{
+	Use:   "me",
+	Short: "Show a friendly one-screen summary for the person using this machine",
+	Long: `Show a short, friendly summary of this machine's security, meant for
+employees rather than administrators: the score, what it means, the three
+most important things to do with the points each one adds, and about how
+long they take.
+
+It runs the same checks as posture summary, in the language set by --lang or
+OMNITRUST_LANG. Unlike the other commands it prints a table unless a format
+is given (--format, OMNITRUST_FORMAT, or the config file).`,
+	Annotations: map[string]string{checksAnnotation: "all"},
+	Run: func(cmd *cobra.Command, args []string) {
+		summary, err := inspector.GetSecuritySummaryContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+		format := formatFlag
+		if !cmd.Flags().Changed("format") {
+			format = inspector.FormatTable
+		}
+		fmt.Println(formattedAs(inspector.NewMeSummary(summary), format, inspector.FormatMeSummary))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(meCmd)
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var memoryTop int
+
 var memoryCmd = &cobra.Command{
 	Use:     "memory",
 	Aliases: []string{"mem"},
@@ -16,19 +18,29 @@ var memoryCmd = &cobra.Command{
 	Long: `Display current system memory usage.
 
 Shows total, used, free, and available memory with human-readable sizes.
+Use --top N to add the N processes with the largest resident memory.
 Use --format=table for a colored ASCII table with progress bars.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		result, err := inspector.GetMemory(context.Background())
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
 			os.Exit(1)
 		}
+		if memoryTop > 0 {
+			top, err := inspector.GetMemoryTop(context.Background(), memoryTop)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+				os.Exit(1)
+			}
+			result.TopProcesses = top
+		}
 
-		output := inspector.FormatMemory(result, formatFlag)
+		output := formatted(result, inspector.FormatMemory)
 		fmt.Println(output)
 	},
 }
 
 func init() {
+	memoryCmd.Flags().IntVarP(&memoryTop, "top", "t", 0, "Also list the N processes using the most memory")
 	rootCmd.AddCommand(memoryCmd)
 }
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// pagerEnv names the pager for omnitrust only, overriding PAGER
+const pagerEnv = "OMNITRUST_PAGER"
+
+var noPagerFlag bool
+
+// printPaged prints output through a pager when stdout is a terminal, as
+// git does: OMNITRUST_PAGER, else PAGER, else "less". less is run with
+// LESS=FRX unless LESS is set, so output that fits on one screen is printed
+// as is and colors survive. --no-pager, an empty pager, or "cat" print
+// directly, as does a pager that cannot be started.
+func printPaged(output string) {
+	pager := pagerCommand()
+	if noPagerFlag || pager == nil || !isTerminal(os.Stdout) {
+		fmt.Println(output)
+		return
+	}
+
+	cmd := exec.Command(pager[0], pager[1:]...) // #nosec G204 -- the user's own pager
+	cmd.Stdin = strings.NewReader(output + "\n")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	if err := cmd.Start(); err != nil {
+		fmt.Println(output)
+		return
+	}
+	_ = cmd.Wait()
+}
+
+// pagerCommand returns the pager and its arguments, or nil for none
+func pagerCommand() []string {
+	pager, ok := os.LookupEnv(pagerEnv)
+	if !ok {
+		pager, ok = os.LookupEnv("PAGER")
+	}
+	if !ok {
+		if runtime.GOOS == "windows" {
+			// more cannot pass colors through, so only an explicit pager is used
+			return nil
+		}
+		pager = "less"
+	}
+	args := strings.Fields(pager)
+	if len(args) == 0 || args[0] == "cat" {
+		return nil
+	}
+	return args
+}
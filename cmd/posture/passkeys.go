@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var passkeysCmd = &cobra.Command{
+	Use:     "passkeys",
+	Aliases: []string{"passkey", "fido2"},
+	Short:   "Show passkey and FIDO2 authenticator readiness",
+	Long: `Display whether this device can create and use passkeys with a
+platform FIDO2 authenticator or a security key.
+
+Reported on:
+  Windows  whether the WebAuthn API (webauthn.dll) is present and Windows
+           Hello is set up as a platform authenticator
+  macOS    whether the release supports passkeys (13 or later), iCloud
+           Keychain is turned on for the user, and a configuration
+           profile blocks it
+  Linux    connected FIDO2 security keys (hidraw) and whether the user can
+           open them with libfido2; Linux has no platform authenticator
+
+The passkeys check is scored on macOS and Windows. Use --format=table for a
+colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckPasskeys},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.CheckEnabled(inspector.CheckPasskeys) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckPasskeys)))
+			os.Exit(1)
+		}
+
+		result, err := inspector.GetPasskeyStatusContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fmt.Println(formatted(result, inspector.FormatPasskeys))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(passkeysCmd)
+}
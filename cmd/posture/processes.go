@@ -10,7 +10,14 @@ import (
 )
 
 var (
-	processLimit int
+	processLimit     int
+	processOffset    int
+	processName      string
+	processUser      string
+	processMinCPU    float64
+	processMinMemory float32
+	processSort      string
+	processFields    []string
 )
 
 var processesCmd = &cobra.Command{
@@ -20,22 +27,41 @@ var processesCmd = &cobra.Command{
 	Long: `List running processes with resource usage.
 
 Shows PID, name, CPU usage, memory usage, and status for each process.
-Results are sorted by CPU usage in descending order.
-Use --limit to restrict the number of processes shown.
+Results are sorted by CPU usage in descending order; use --sort to order
+by memory, pid, or name instead.
+Use --name, --user, --min-cpu, and --min-memory to filter, and --limit
+with --offset to page through the results.
+Use --fields to collect only some columns (user, cpu, memory, status),
+which is much faster on busy hosts; the others are left empty.
 Use --format=table for a colored ASCII table.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		result, err := inspector.ListProcesses(context.Background(), processLimit)
+		result, err := inspector.ListProcessesWithOptions(context.Background(), inspector.ProcessOptions{
+			Name:      processName,
+			User:      processUser,
+			MinCPU:    processMinCPU,
+			MinMemory: processMinMemory,
+			Sort:      processSort,
+			Offset:    processOffset,
+			Limit:     processLimit,
+			Fields:    processFields,
+		})
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
 			os.Exit(1)
 		}
 
-		output := inspector.FormatProcessList(result, formatFlag)
-		fmt.Println(output)
+		printPaged(formatted(result, inspector.FormatProcessList))
 	},
 }
 
 func init() {
 	processesCmd.Flags().IntVarP(&processLimit, "limit", "n", 0, "Maximum number of processes to show (0 for all)")
+	processesCmd.Flags().IntVar(&processOffset, "offset", 0, "Number of matching processes to skip")
+	processesCmd.Flags().StringVar(&processName, "name", "", "Only show processes whose name contains this substring")
+	processesCmd.Flags().StringVarP(&processUser, "user", "u", "", "Only show processes owned by this user")
+	processesCmd.Flags().Float64Var(&processMinCPU, "min-cpu", 0, "Only show processes using at least this CPU percentage")
+	processesCmd.Flags().Float32Var(&processMinMemory, "min-memory", 0, "Only show processes using at least this memory percentage")
+	processesCmd.Flags().StringVarP(&processSort, "sort", "s", "cpu", "Sort by cpu, memory, pid, or name")
+	processesCmd.Flags().StringSliceVar(&processFields, "fields", nil, "Collect only these fields: user, cpu, memory, status (default all)")
 	rootCmd.AddCommand(processesCmd)
 }
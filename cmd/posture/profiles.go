@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var profilesCmd = &cobra.Command{
+	Use:     "profiles",
+	Aliases: []string{"mdm"},
+	Short:   "Show configuration profiles and MDM enrollment (macOS)",
+	Long: `List installed configuration profiles and MDM enrollment.
+
+Shows each profile's payload types and whether it appears to come from
+MDM, whether the Mac is MDM enrolled (user approved or through DEP) and
+supervised, and whether FileVault, firewall, and password policy
+restrictions are set by a managed or a user-installed profile. This tells
+managed posture apart from settings a user could change back.
+
+macOS does not record where a profile came from: the enrollment profile,
+profiles that cannot be removed, and profiles from the enrolling
+organization count as managed. Run with sudo to include profiles of all
+users.
+
+Use --format=table for a colored ASCII table.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.IsConfigProfilesSupported() {
+			fmt.Fprintln(os.Stderr, "Error: configuration profiles are only checked on macOS")
+			os.Exit(1)
+		}
+
+		result, err := inspector.GetConfigProfilesContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		printPaged(formatted(result, inspector.FormatConfigProfiles))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(profilesCmd)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+// spinnerFrames animate a check that is still running
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// checkProgress shows the checks of a scan as they run, on stderr: on a
+// terminal a line per check with a spinner until it finishes and its
+// duration after, otherwise a plain line as each check finishes
+type checkProgress struct {
+	out *os.File
+	tty bool
+
+	mu      sync.Mutex
+	checks  []*checkLine
+	drawn   int
+	frame   int
+	stopped chan struct{}
+	wg      sync.WaitGroup
+}
+
+// checkLine is the state of one check on the terminal
+type checkLine struct {
+	name    string
+	start   time.Time
+	done    bool
+	elapsed time.Duration
+	err     error
+}
+
+// newCheckProgress starts showing progress on stderr
+func newCheckProgress() *checkProgress {
+	p := &checkProgress{out: os.Stderr, tty: isTerminal(os.Stderr), stopped: make(chan struct{})}
+	if p.tty {
+		p.wg.Add(1)
+		go p.animate()
+	}
+	return p
+}
+
+// update records a check starting or finishing; it is the summary's
+// progress callback
+func (p *checkProgress) update(e inspector.CheckProgress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !e.Done {
+		p.checks = append(p.checks, &checkLine{name: e.Check, start: time.Now()})
+		return
+	}
+	for _, c := range p.checks {
+		if c.name == e.Check && !c.done {
+			c.done, c.elapsed, c.err = true, e.Duration, e.Err
+			if !p.tty {
+				fmt.Fprintln(p.out, c.plain())
+			}
+			return
+		}
+	}
+}
+
+// stop draws the final state of every check and ends the animation
+func (p *checkProgress) stop() {
+	close(p.stopped)
+	p.wg.Wait()
+	if p.tty {
+		p.mu.Lock()
+		p.draw()
+		p.mu.Unlock()
+	}
+}
+
+// animate redraws the checks until stopped
+func (p *checkProgress) animate() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stopped:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			p.frame++
+			p.draw()
+			p.mu.Unlock()
+		}
+	}
+}
+
+// draw rewrites the lines drawn before with the current state of each check
+func (p *checkProgress) draw() {
+	var sb strings.Builder
+	if p.drawn > 0 {
+		fmt.Fprintf(&sb, "\033[%dA", p.drawn)
+	}
+	width := 0
+	for _, c := range p.checks {
+		width = max(width, len(c.name))
+	}
+	for _, c := range p.checks {
+		sb.WriteString("\r\033[2K")
+		name := inspector.PadRight(c.name, width)
+		switch {
+		case !c.done:
+			fmt.Fprintf(&sb, "%s %s %s", inspector.Info(spinnerFrames[p.frame%len(spinnerFrames)]), name, inspector.Muted(formatElapsed(time.Since(c.start))))
+		case c.err != nil:
+			fmt.Fprintf(&sb, "%s %s %s %s", inspector.Danger(inspector.IconCross), name, inspector.Muted(formatElapsed(c.elapsed)), inspector.Danger(inspector.ErrorMessage(c.err)))
+		default:
+			fmt.Fprintf(&sb, "%s %s %s", inspector.Success(inspector.IconCheck), name, inspector.Muted(formatElapsed(c.elapsed)))
+		}
+		sb.WriteString("\n")
+	}
+	p.drawn = len(p.checks)
+	fmt.Fprint(p.out, sb.String())
+}
+
+// plain describes a finished check on one line, for logs
+func (c *checkLine) plain() string {
+	if c.err != nil {
+		return fmt.Sprintf("check %s failed after %s: %s", c.name, formatElapsed(c.elapsed), inspector.ErrorMessage(c.err))
+	}
+	return fmt.Sprintf("check %s finished in %s", c.name, formatElapsed(c.elapsed))
+}
+
+// formatElapsed shows a duration to the millisecond, or to a tenth of a
+// second from one second up
+func formatElapsed(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"github.com/agentplexus/posture/inspector"
+	"github.com/agentplexus/posture/redact"
+)
+
+var redactFlag bool
+
+// applyRedact turns on masking of hostnames, usernames, serial numbers,
+// and volume names in all output for --redact
+func applyRedact() error {
+	if !redactFlag {
+		return nil
+	}
+	r, err := redact.NewFromEnv()
+	if err != nil {
+		return err
+	}
+	inspector.SetRedactor(r)
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/report"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Work with exported security reports",
+}
+
+var reportMergeCmd = &cobra.Command{
+	Use:   "merge <bundle...>",
+	Short: "Combine several exported summaries into one comparative report",
+	Long: `Combine security summaries exported from several machines into one
+comparative report showing per-host scores and a matrix of features.
+
+Export a bundle on each machine with:
+  omnitrust summary -f json > $(hostname).json
+
+Then merge them anywhere, without running the server:
+  omnitrust report merge -f table hosts/*.json
+  omnitrust report merge -f html hosts/*.json > report.html`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		merged, err := report.Merge(args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		output, err := report.Format(merged, formatFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(output)
+	},
+}
+
+func init() {
+	reportCmd.AddCommand(reportMergeCmd)
+	rootCmd.AddCommand(reportCmd)
+}
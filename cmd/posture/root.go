@@ -1,11 +1,14 @@
 package main
 
 import (
+	"github.com/agentplexus/posture/inspector"
 	"github.com/spf13/cobra"
 )
 
 var (
-	formatFlag string
+	formatFlag   string
+	envelopeFlag bool
+	explainFlag  bool
 )
 
 var rootCmd = &cobra.Command{
@@ -29,9 +32,39 @@ System Metrics:
 
 Output formats:
   - JSON (default): Structured data for programmatic use
-  - Table: Rich ASCII tables with ANSI colors and UTF-8 icons`,
+  - Table: Rich ASCII tables with ANSI colors and UTF-8 icons. Colors are
+    used only when writing to a terminal; see --color and NO_COLOR.
+  - CSV / NDJSON: One row or line per process, volume, GPU, etc., for
+    spreadsheets and log pipelines
+  - Template: --template '{{.OverallScore}}' renders the result through a Go
+    template (fields use Go names), or --template oneline|csv for the
+    built-in key=value and CSV templates
+
+Configuration:
+  Defaults for every flag can be set in ~/.config/omnitrust/config.yaml
+  (or /etc/omnitrust/config.yaml, or the file named by --config or
+  OMNITRUST_CONFIG). Environment variables override the file and flags
+  override both.`,
+	PersistentPreRun: preRun,
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&formatFlag, "format", "f", "json", "Output format: 'json' (default) or 'table'")
+	rootCmd.PersistentFlags().StringVar(&configFlag, "config", "", "Config file (default ~/.config/omnitrust/config.yaml)")
+	rootCmd.PersistentFlags().StringVarP(&formatFlag, "format", "f", "json", "Output format: 'json' (default), 'table', 'markdown', 'html', 'csv', 'ndjson', or 'template'")
+	rootCmd.PersistentFlags().StringVar(&templateFlag, "template", "", "Go template for --format template, e.g. '{{.OverallScore}}', or a built-in: oneline, csv")
+	rootCmd.PersistentFlags().BoolVar(&envelopeFlag, "envelope", false, "Wrap JSON output in a report envelope with a report ID, machine ID, OS and tool versions, collection duration, and probe errors")
+	rootCmd.PersistentFlags().BoolVar(&explainFlag, "explain", false, "Record which command, file, or API produced each field and when, and add it to JSON (in the report envelope) and table output")
+	rootCmd.PersistentFlags().StringVar(&colorFlag, "color", inspector.ColorAuto, "Color table output: 'auto' (when writing to a terminal), 'always', or 'never'")
+	rootCmd.PersistentFlags().StringVar(&langFlag, "lang", "", "Language of table output and recommendations: 'en', 'de', or 'ja' (default from LANG)")
+	rootCmd.PersistentFlags().StringVar(&themeFlag, "theme", "", "Color theme of table output: 'default', 'dark', 'light', 'high-contrast', 'monochrome', or one defined under themes in the config file")
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Scan profile selecting which checks run: 'quick', 'full', 'boot-security', 'privacy', or one defined under scan_profiles in the config file")
+	rootCmd.PersistentFlags().BoolVar(&noColorFlag, "no-color", false, "Disable colors (same as --color=never; also honors NO_COLOR)")
+	rootCmd.PersistentFlags().StringVar(&logLevelFlag, "log-level", inspector.DefaultLogLevel, "Log level: 'debug' (every external command and its duration), 'info', 'warn', 'error', or 'off'")
+	rootCmd.PersistentFlags().StringVar(&logFormatFlag, "log-format", inspector.LogFormatText, "Log format: 'text' or 'json'")
+	rootCmd.PersistentFlags().StringVar(&logFileFlag, "log-file", "", "Append logs to this file instead of stderr")
+	rootCmd.PersistentFlags().BoolVar(&noPagerFlag, "no-pager", false, "Print long lists (processes, open files, setuid binaries, profiles, secrets, fleet reports) directly instead of through $PAGER or less")
+	rootCmd.PersistentFlags().BoolVar(&redactFlag, "redact", false, "Mask hostnames, usernames, serial numbers, and volume names in the output, for sharing reports")
+	rootCmd.PersistentFlags().StringVar(&fixtureFlag, "fixture", "", "Serve results from the JSON files in this directory instead of probing the host, for demos and client development")
+	rootCmd.PersistentFlags().BoolVar(&dryRunFlag, "dry-run", false, "List the commands, files, and APIs the selected checks would touch, without running them")
+	rootCmd.PersistentFlags().BoolVar(&sudoFlag, "sudo", false, "Re-run with sudo so privileged probes (bputil, fdesetup, dmsetup) are not degraded")
 }
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/agentplexus/posture/archive"
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scanOutput    string
+	scanEncryptTo string
+	scanSign      bool
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Collect the security summary into a signed, encrypted report archive",
+	Long: `Run the security summary and write it, with the debug logs of every probe
+that ran, to a report archive (.otar). The archive's manifest records the
+SHA-256 digest of each member; 'omnitrust verify' detects any change.
+
+The checks run in parallel. On a terminal, scan shows a spinner for each
+check while it runs and its duration when it finishes, so a slow probe
+stands out; otherwise it logs a line to stderr as each check finishes.
+
+--sign signs the manifest with the Ed25519 key in OMNITRUST_SIGNING_KEY
+(config: signing_key), or else signing.key next to the per-user config
+file, which is created on first use. --encrypt-to encrypts the archive to
+an age X25519 recipient, e.g. one made with age-keygen:
+  omnitrust scan --output evidence.otar --sign --encrypt-to age1…
+  omnitrust verify --identity auditor.key evidence.otar`,
+	Annotations: map[string]string{checksAnnotation: "all"},
+	Run: func(cmd *cobra.Command, args []string) {
+		var opts archive.Options
+		if scanEncryptTo != "" {
+			recipient, err := archive.ParseRecipient(scanEncryptTo)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			opts.Recipient = recipient
+		}
+		if scanSign {
+			path := archive.SigningKeyPath()
+			key, created, err := archive.LoadSigningKey(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if created {
+				fmt.Fprintf(os.Stderr, "Created the signing key %s\n", path)
+			}
+			opts.SigningKey = key
+		}
+
+		// The archive keeps the debug logs of this run as collection evidence
+		var logs bytes.Buffer
+		logger, err := inspector.NewLogger(&logs, "debug", inspector.LogFormatJSON)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		previous := inspector.SetLogger(logger)
+		started := time.Now()
+		progress := newCheckProgress()
+		summary, err := inspector.GetSecuritySummaryWithOptionsContext(context.Background(), inspector.SummaryOptions{
+			Parallel: true,
+			Progress: progress.update,
+		})
+		progress.stop()
+		inspector.SetLogger(previous)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		env := inspector.NewEnvelope(summary, started)
+		manifest := archive.Manifest{
+			CreatedAt:   env.Timestamp,
+			Hostname:    env.Hostname,
+			MachineID:   env.MachineID,
+			ToolVersion: env.ToolVersion,
+		}
+		files := []archive.File{
+			{Name: "summary.json", Data: []byte(inspector.FormatOutput(env, nil, inspector.FormatJSON))},
+			{Name: "collection.log", Data: logs.Bytes()},
+		}
+
+		f, err := os.OpenFile(scanOutput, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600) // #nosec G304 -- user-supplied output path
+		if err == nil {
+			err = archive.Write(f, manifest, files, opts)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var sealed []string
+		if opts.SigningKey != nil {
+			sealed = append(sealed, "signed")
+		}
+		if opts.Recipient != nil {
+			sealed = append(sealed, "encrypted to "+scanEncryptTo)
+		}
+		if len(sealed) == 0 {
+			sealed = append(sealed, "unsigned, unencrypted")
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s (score %s, %s)\n", scanOutput, inspector.FormatScore(summary.OverallScore), strings.Join(sealed, ", "))
+	},
+}
+
+func init() {
+	scanCmd.Flags().StringVarP(&scanOutput, "output", "o", "", "Report archive to write, e.g. report"+archive.Extension)
+	scanCmd.Flags().StringVar(&scanEncryptTo, "encrypt-to", "", "Encrypt the archive to this age X25519 recipient (age1…)")
+	scanCmd.Flags().BoolVar(&scanSign, "sign", false, "Sign the archive's manifest with the Ed25519 signing key")
+	_ = scanCmd.MarkFlagRequired("output")
+	rootCmd.AddCommand(scanCmd)
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+var profileFlag string
+
+// applyProfile selects the checks of the --profile scan profile (which
+// applyConfig fills from OMNITRUST_PROFILE or the config file)
+func applyProfile() error {
+	if profileFlag == "" {
+		return nil
+	}
+	if _, err := inspector.LookupScanProfile(profileFlag); err != nil {
+		return err
+	}
+	return os.Setenv(inspector.ScanProfileEnv, profileFlag)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/agentplexus/posture/report"
+	"github.com/spf13/cobra"
+)
+
+var schemaListFlag bool
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [name]",
+	Short: "Print JSON Schemas for command results",
+	Long: fmt.Sprintf(`Print the JSON Schema (draft 2020-12) of a result, generated from the Go
+structs, or of every result when no name is given.
+
+Every JSON result carries a schema_version (currently %s). The minor
+version changes when fields are added and the major version when fields
+are removed, renamed, or change type.
+
+Schemas: %s, report`, inspector.SchemaVersion, strings.Join(inspector.SchemaNames(), ", ")),
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if schemaListFlag {
+			for _, name := range append(inspector.SchemaNames(), "report") {
+				fmt.Println(name)
+			}
+			return
+		}
+
+		var output any
+		var err error
+		switch {
+		case len(args) == 0:
+			schemas, e := inspector.Schemas()
+			if e == nil {
+				merged := map[string]any{}
+				for name, s := range schemas {
+					merged[name] = s
+				}
+				merged["report"], e = reportSchema()
+				output = merged
+			}
+			err = e
+		case args[0] == "report":
+			output, err = reportSchema()
+		default:
+			output, err = inspector.Schema(args[0])
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+		data, _ := json.MarshalIndent(output, "", "  ")
+		fmt.Println(string(data))
+	},
+}
+
+// reportSchema returns the schema of `report merge` output
+func reportSchema() (any, error) {
+	return inspector.SchemaFor(reflect.TypeFor[report.MergedReport](), "report")
+}
+
+func init() {
+	schemaCmd.Flags().BoolVarP(&schemaListFlag, "list", "l", false, "List schema names")
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/agentplexus/posture/secrets"
+	"github.com/spf13/cobra"
+)
+
+var secretsEnableFlag bool
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Scan the environment and dotfiles for exposed credentials (opt-in)",
+	Long: `Scan for credentials exposed in environment variables, shell history,
+and dotfiles such as .bashrc, .env, .netrc, and .npmrc.
+
+Looks for AWS access keys, GitHub, GitLab, Slack, and npm tokens, Google
+API keys, private keys, passwords in URLs and command lines, and values
+assigned to variables named like *_TOKEN or *_SECRET. Under sudo the
+invoking user's files are read.
+
+Secrets are never printed: each finding shows the rule, the variable or
+file and line, and a masked preview. Files that other users can read are
+marked world-readable.
+
+Because it reads sensitive files, the scan only runs with --enable (or
+OMNITRUST_SECRETS_ENABLE=true, or enable: true in the secrets section of
+the config file).
+
+Use --format=table for a colored ASCII table.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !secretsEnableFlag {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", secrets.ErrDisabled)
+			os.Exit(1)
+		}
+
+		result, err := secrets.Scan()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		printPaged(formatted(result, secrets.Format))
+	},
+}
+
+func init() {
+	secretsCmd.Flags().BoolVar(&secretsEnableFlag, "enable", false, "Run the scan (it reads shell history and dotfiles)")
+	rootCmd.AddCommand(secretsCmd)
+}
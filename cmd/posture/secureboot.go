@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -21,19 +22,24 @@ On macOS, this shows Apple Secure Boot status (Full/Reduced/Permissive).
 On Windows and Linux, this shows UEFI Secure Boot status.
 
 Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckSecureBoot},
 	Run: func(cmd *cobra.Command, args []string) {
 		if !inspector.IsSecureBootSupported() {
 			fmt.Fprintln(os.Stderr, "Error: Secure Boot not supported on this platform")
 			os.Exit(1)
 		}
+		if !inspector.CheckEnabled(inspector.CheckSecureBoot) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckSecureBoot)))
+			os.Exit(1)
+		}
 
-		result, err := inspector.GetSecureBootStatus()
+		result, err := inspector.GetSecureBootStatusContext(context.Background())
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
 			os.Exit(1)
 		}
 
-		output := inspector.FormatSecureBoot(result, formatFlag)
+		output := formatted(result, inspector.FormatSecureBoot)
 		fmt.Println(output)
 	},
 }
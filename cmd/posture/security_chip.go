@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -21,19 +22,24 @@ On Windows and Linux, this shows TPM (Trusted Platform Module) presence,
 version, manufacturer, and whether hardware key storage is supported.
 
 Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckTPM},
 	Run: func(cmd *cobra.Command, args []string) {
 		if !inspector.IsTPMSupported() {
 			fmt.Fprintln(os.Stderr, "Error: Platform security chip not supported on this platform")
 			os.Exit(1)
 		}
+		if !inspector.CheckEnabled(inspector.CheckTPM) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckTPM)))
+			os.Exit(1)
+		}
 
-		result, err := inspector.GetTPMStatus()
+		result, err := inspector.GetTPMStatusContext(context.Background())
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
 			os.Exit(1)
 		}
 
-		output := inspector.FormatTPM(result, formatFlag)
+		output := formatted(result, inspector.FormatTPM)
 		fmt.Println(output)
 	},
 }
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Check that inspectors degrade gracefully on this machine",
+	Long: `Run every supported inspector against simulated failures and check
+that each one degrades gracefully instead of crashing or hanging.
+
+Three scenarios are injected into the external tools the inspectors use:
+  - missing_tool:       the tool is not installed
+  - permission_denied:  the tool refuses to run without privileges
+  - malformed_output:   the tool prints truncated or corrupt output
+
+Probes that read files or devices directly still see the real system, so
+the results also reflect this machine's environment.
+
+Exits with code 1 if any inspector panics or hangs.`,
+	Annotations: map[string]string{checksAnnotation: "all"},
+	Run: func(cmd *cobra.Command, args []string) {
+		result := inspector.SelfTest()
+		fmt.Println(formatted(result, inspector.FormatSelfTest))
+		if !result.Passed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(selftestCmd)
+}
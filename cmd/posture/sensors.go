@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var sensorsCmd = &cobra.Command{
+	Use:     "sensors",
+	Aliases: []string{"temps"},
+	Short:   "Show temperatures and fan speeds",
+	Long: `Display CPU, GPU, and disk temperatures and fan speeds.
+
+Temperatures above the sensor's high or critical limit are flagged as
+thermal anomalies. NVIDIA GPUs are read through nvidia-smi when installed.
+Use --format=table for a colored ASCII table.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		result, err := inspector.GetSensors(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		output := formatted(result, inspector.FormatSensors)
+		fmt.Println(output)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(sensorsCmd)
+}
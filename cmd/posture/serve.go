@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/agentplexus/posture/schedule"
+	"github.com/agentplexus/posture/server"
+	"github.com/agentplexus/posture/service"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveTransport       string
+	serveAddress         string
+	serveServiceName     string
+	serveSecretsScan     bool
+	serveHideUnsupported bool
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the MCP server",
+	Long: `Run the Model Context Protocol server, like mcp-posture.
+
+The server speaks MCP over stdio by default. With --transport=http it serves
+the streamable HTTP transport on --address (default 127.0.0.1:8080).
+
+The transport and address can also be set with OMNITRUST_SERVER_TRANSPORT
+and OMNITRUST_SERVER_ADDRESS, or in the server section of the config file.
+
+The scan_secrets tool is only offered with --enable-secrets-scan or
+OMNITRUST_SECRETS_ENABLE=true.
+
+Tools for checks this platform does not have, such as get_defender_status
+on Linux, are listed everywhere and return supported=false, so clients see
+the same tools on every OS. --hide-unsupported (or
+OMNITRUST_SERVER_HIDE_UNSUPPORTED=true) leaves them out instead.
+
+Tools that can expose personal data (process lists, biometric enrollment,
+device identifiers, secrets) follow a consent policy set per tool in
+OMNITRUST_SERVER_CONSENT or the server section's consent map: allow, ask
+(the client must get the user's approval through MCP elicitation for each
+call; the default for these tools), or deny (the tool is not offered), e.g.
+  OMNITRUST_SERVER_CONSENT=sensitive=allow,scan_secrets=deny
+
+With the http transport, which is what "serve install" runs, the server
+also scans on the schedule in OMNITRUST_SCHEDULE_INTERVAL (config:
+schedule.interval, e.g. 6h) and delivers each summary to the configured
+sinks. Each scan is delayed by a random jitter up to
+OMNITRUST_SCHEDULE_JITTER, so a fleet does not scan at once. A due scan is
+deferred, and retried after OMNITRUST_SCHEDULE_RETRY (default 15m), while
+the machine is on battery power below OMNITRUST_SCHEDULE_MIN_BATTERY percent
+(default 20), on battery at all with OMNITRUST_SCHEDULE_AC_ONLY=true, or
+while the user is busy: presenting, on a video call, or watching full-screen
+video, as seen by systemd-logind idle inhibitors, pmset display assertions,
+or powercfg display requests (OMNITRUST_SCHEDULE_DEFER_WHEN_BUSY=false
+turns this off). The omnitrust://schedule resource reports the last and next
+scan times.
+
+Use "serve install" to run the server in the background as a launchd daemon
+(macOS), Windows service, or systemd unit (Linux).`,
+	Annotations: map[string]string{checksAnnotation: "all"},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		opts := server.DefaultOptions()
+		if serveTransport != "" {
+			opts.Transport = serveTransport
+		}
+		if serveAddress != "" {
+			opts.Address = serveAddress
+		}
+		if serveSecretsScan {
+			opts.SecretsScan = true
+		}
+		if serveHideUnsupported {
+			opts.HideUnsupported = true
+		}
+		if opts.Transport == server.TransportHTTP {
+			fmt.Fprintf(os.Stderr, "Serving MCP over HTTP on %s\n", opts.Address)
+			sc, err := schedule.ConfigFromEnv()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			opts.Schedule = schedule.New(sc, scheduledScan)
+		}
+		run := func(ctx context.Context) error {
+			if opts.Schedule != nil {
+				go opts.Schedule.Run(ctx)
+			}
+			return server.Serve(ctx, opts)
+		}
+		if err := service.Run(ctx, serveServiceName, run); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+	},
+}
+
+// scheduledScan runs the security summary and delivers it to the sinks, as
+// posture summary does
+func scheduledScan(ctx context.Context) (*inspector.SecuritySummary, error) {
+	started := time.Now()
+	result, err := inspector.GetSecuritySummaryContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := deliverSummary(ctx, result, started); err != nil {
+		inspector.Logger().Warn("cannot deliver the scheduled scan", "error", err)
+	}
+	return result, nil
+}
+
+var (
+	serviceName        string
+	serviceAddress     string
+	serviceAllowRemote bool
+)
+
+var serveInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Run the HTTP server as a system service",
+	Long: `Register the MCP server over HTTP with the system service manager and
+start it. The service starts at boot and runs with the privileges probes
+need for complete results:
+
+  macOS    launchd daemon in /Library/LaunchDaemons, running as root;
+           output goes to /var/log/omnitrust/omnitrust.log
+  Windows  automatic-start service running as LocalSystem, restarted on
+           failure; events go to the Application event log
+  Linux    systemd unit in /etc/systemd/system, running as root;
+           output goes to the journal (journalctl -u omnitrust)
+
+The service runs this binary from its current location, with the config
+file given by --config if any. On macOS and Linux both must be owned by root
+and not writable by group or others, nor may their directories be: copy them
+to /usr/local/bin and /etc first. The server does not authenticate clients,
+so install refuses an --address beyond loopback unless --allow-remote is
+given. --name may contain letters, digits, '.', '_', and '-'. Run from an
+elevated prompt or with --sudo.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := service.NewConfig(serviceName, serviceAddress, configFlag)
+		if err == nil {
+			// The config file may set the address the service listens on
+			c.Address = serviceAddress
+			if c.Address == "" {
+				c.Address = server.DefaultOptions().Address
+			}
+			c.AllowRemote = serviceAllowRemote
+			err = service.Install(c)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+		fmt.Printf("Installed and started service %s\n", c.Name)
+	},
+}
+
+var serveUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Stop and remove the server service",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := service.Uninstall(serviceName); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+		fmt.Println("Removed service")
+	},
+}
+
+var serveStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the server service is installed and running",
+	Run: func(cmd *cobra.Command, args []string) {
+		st, err := service.Query(serviceName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+		fmt.Println(formatted(st, service.FormatStatus))
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveTransport, "transport", "", "Transport: 'stdio' (default) or 'http'")
+	serveCmd.Flags().StringVar(&serveAddress, "address", "", "Listen address for the http transport (default 127.0.0.1:8080)")
+	serveCmd.Flags().BoolVar(&serveSecretsScan, "enable-secrets-scan", false, "Offer the scan_secrets tool, which reads shell history and dotfiles")
+	serveCmd.Flags().BoolVar(&serveHideUnsupported, "hide-unsupported", false, "Leave out the tools this platform does not support instead of listing them as unsupported")
+	serveCmd.Flags().StringVar(&serveServiceName, "service-name", service.Name, "Name the service was installed under")
+	_ = serveCmd.Flags().MarkHidden("service-name")
+
+	for _, c := range []*cobra.Command{serveInstallCmd, serveUninstallCmd, serveStatusCmd} {
+		c.Flags().StringVar(&serviceName, "name", service.Name, "Service name")
+	}
+	serveInstallCmd.Flags().StringVar(&serviceAddress, "address", "", "Listen address (default 127.0.0.1:8080)")
+	serveInstallCmd.Flags().BoolVar(&serviceAllowRemote, "allow-remote", false, "Allow an --address reachable from other machines")
+	serveCmd.AddCommand(serveInstallCmd, serveUninstallCmd, serveStatusCmd)
+	rootCmd.AddCommand(serveCmd)
+}
@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/agentplexus/posture/inspector"
+	"github.com/agentplexus/posture/sink"
 	"github.com/spf13/cobra"
 )
 
+var summaryMinSeverity string
+
 var summaryCmd = &cobra.Command{
 	Use:     "summary",
 	Aliases: []string{"sum", "status", "security"},
@@ -20,21 +26,94 @@ Checks all security features and provides:
   - Status of Secure Boot
   - Status of disk encryption
   - Status of biometric authentication
-  - Recommendations for improving security
+  - Findings with a severity (critical, high, medium, low) and remediation,
+    grouped by severity in table output; --min-severity hides the rest
+
+Checks listed in OMNITRUST_MANDATORY_CHECKS must pass: if any fails, the
+status is critical and the command exits with code 2. Checks listed in
+OMNITRUST_INFORMATIONAL_CHECKS are reported but never affect the score.
+
+The JSON summary is also delivered to every sink configured in the config
+file (sinks:), wrapped in a report envelope (see --envelope); smtp sinks
+email it rendered as HTML or Markdown instead, pagerduty and opsgenie sinks
+open and resolve an incident for each critical finding, and sinks with
+when: drift only receive summaries that changed from the baseline. Delivery failures
+are reported on stderr but do not change the exit code.
 
 Use --format=table for a colored ASCII table with visual score bar.`,
+	Annotations: map[string]string{checksAnnotation: "all"},
 	Run: func(cmd *cobra.Command, args []string) {
-		result, err := inspector.GetSecuritySummary()
+		started := time.Now()
+		result, err := inspector.GetSecuritySummaryContext(context.Background())
+		if err == nil && summaryMinSeverity != "" {
+			result.Findings, err = inspector.FilterFindings(result.Findings, summaryMinSeverity)
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
 			os.Exit(1)
 		}
 
-		output := inspector.FormatSecuritySummary(result, formatFlag)
+		output := formatted(result, inspector.FormatSecuritySummary)
 		fmt.Println(output)
+
+		if err := deliverSummary(cmd.Context(), result, started); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", err)
+		}
+
+		// Mandatory checks turn the summary into a gate for scripts and CI
+		if len(result.MandatoryFailures) > 0 {
+			os.Exit(2)
+		}
 	},
 }
 
+// deliverSummary sends the JSON summary, in a report envelope, to the
+// configured sinks
+func deliverSummary(ctx context.Context, result *inspector.SecuritySummary, started time.Time) error {
+	if len(cfg.Sinks) == 0 {
+		return nil
+	}
+	sinks, err := sink.NewAll(cfg.Sinks)
+	if err != nil {
+		return err
+	}
+	env := inspector.NewEnvelope(result, started)
+	findings := make([]sink.Finding, len(result.Findings))
+	for i, f := range result.Findings {
+		findings[i] = sink.Finding{ID: f.ID, Check: f.Check, Severity: f.Severity, Title: f.Title, Remediation: f.Remediation, RemediationCommand: f.RemediationCommand}
+	}
+	return sink.Deliver(ctx, sinks, &sink.Report{
+		Hostname:    env.Hostname,
+		Timestamp:   env.Timestamp,
+		Body:        []byte(inspector.FormatOutput(env, nil, inspector.FormatJSON)),
+		ContentType: "application/json",
+		Summary:     reportSummary(result),
+		Drift:       result.DeltaFromBaseline.Drifted(),
+		Render: func(format string) string {
+			return inspector.FormatSecuritySummary(result, format)
+		},
+		Findings:     findings,
+		CheckResults: result.CheckResults,
+	})
+}
+
+// reportSummary sums up a summary in one line for sinks, such as an email
+// subject
+func reportSummary(result *inspector.SecuritySummary) string {
+	line := fmt.Sprintf("%s (%s), %d findings", inspector.FormatScore(result.OverallScore), result.OverallStatus, len(result.Findings))
+	if len(result.Findings) == 1 {
+		line = strings.TrimSuffix(line, "s")
+	}
+	switch d := result.DeltaFromBaseline; {
+	case d.Drifted() && d.Score != 0:
+		line += fmt.Sprintf(", %+d since the baseline", d.Score)
+	case d.Drifted():
+		line += ", changed since the baseline"
+	}
+	return line
+}
+
 func init() {
+	summaryCmd.Flags().StringVar(&summaryMinSeverity, "min-severity", "", "Only report findings at least this severe: critical, high, medium, or low")
 	rootCmd.AddCommand(summaryCmd)
 }
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+var templateFlag string
+
+// applyTemplate validates --template and switches the output to the
+// template format. --template implies --format template.
+func applyTemplate() error {
+	if templateFlag == "" {
+		if strings.EqualFold(formatFlag, inspector.FormatTemplate) {
+			return errors.New("--format template requires --template")
+		}
+		return nil
+	}
+	if _, err := inspector.ParseTemplate(templateFlag); err != nil {
+		return err
+	}
+	formatFlag = inspector.TemplateFormat(templateFlag)
+	return nil
+}
+
+// formatted renders data with formatFunc in --format. A template that fails
+// on the data is an error, so the command exits non-zero instead of printing
+// the error as its output.
+func formatted[T any](data T, formatFunc func(T, string) string) string {
+	return formattedAs(data, formatFlag, formatFunc)
+}
+
+// formattedAs is formatted for a format other than --format
+func formattedAs[T any](data T, format string, formatFunc func(T, string) string) string {
+	output, err := inspector.Render(data, format, formatFunc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+		os.Exit(1)
+	}
+	return output
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var (
+	topInterval time.Duration
+	topCount    int
+	topLimit    int
+)
+
+var topCmd = &cobra.Command{
+	Use:     "top",
+	Aliases: []string{"watch"},
+	Short:   "Watch CPU, memory, and processes live",
+	Long: `Take a snapshot of CPU, memory, and the busiest processes every
+--interval until interrupted (or --count snapshots have been taken).
+
+With --format=table the screen is redrawn for each snapshot. The default
+JSON output writes one snapshot per line (NDJSON) so it can be piped into
+other tools.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		table := strings.EqualFold(formatFlag, inspector.FormatTable)
+		redraw := table && isTerminal(os.Stdout)
+		// CSV and templates render each snapshot with the shared formatters;
+		// JSON and NDJSON both write one snapshot per line
+		perSnapshot := !table && !strings.EqualFold(formatFlag, inspector.FormatJSON) &&
+			!strings.EqualFold(formatFlag, inspector.FormatNDJSON)
+		enc := json.NewEncoder(os.Stdout)
+
+		err := inspector.StreamMetrics(ctx, inspector.StreamOptions{
+			Interval:     topInterval,
+			Count:        topCount,
+			TopProcesses: topLimit,
+		}, func(s *inspector.MetricsSnapshot) error {
+			if perSnapshot {
+				fmt.Println(formatted(s, inspector.FormatMetricsSnapshot))
+				return nil
+			}
+			if !table {
+				return enc.Encode(s)
+			}
+			if redraw {
+				// Move home and clear the screen
+				fmt.Print("\033[H\033[2J")
+			}
+			fmt.Println(inspector.FormatMetricsSnapshotTable(s))
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	topCmd.Flags().DurationVarP(&topInterval, "interval", "i", inspector.DefaultStreamInterval, "Time between snapshots (minimum 1s)")
+	topCmd.Flags().IntVarP(&topCount, "count", "c", 0, "Stop after this many snapshots (0 runs until interrupted)")
+	topCmd.Flags().IntVarP(&topLimit, "limit", "n", inspector.DefaultStreamTopProcesses, "Number of top processes to show (0 to hide)")
+	rootCmd.AddCommand(topCmd)
+}
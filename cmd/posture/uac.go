@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var uacCmd = &cobra.Command{
+	Use:     "uac",
+	Aliases: []string{"smartscreen"},
+	Short:   "Show UAC and SmartScreen settings (Windows)",
+	Long: `Display User Account Control and SmartScreen settings.
+
+Shows whether UAC is on, how administrators are prompted to elevate,
+whether prompts use the secure desktop, Admin Approval Mode for the
+built-in Administrator, and whether SmartScreen checks apps and files
+and sites in Microsoft Edge. Values are read from the registry; settings
+that are not configured take their Windows defaults.
+
+Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckUAC},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.IsUACSupported() {
+			fmt.Fprintln(os.Stderr, "Error: UAC and SmartScreen are only checked on Windows")
+			os.Exit(1)
+		}
+		if !inspector.CheckEnabled(inspector.CheckUAC) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckUAC)))
+			os.Exit(1)
+		}
+
+		result, err := inspector.GetUACStatusContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fmt.Println(formatted(result, inspector.FormatUAC))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uacCmd)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var uptimeCmd = &cobra.Command{
+	Use:   "uptime",
+	Short: "Show uptime and whether a reboot is pending for updates",
+	Long: `Display the uptime, last boot time, and whether a reboot is pending
+to finish installing updates.
+
+A pending reboot is detected from:
+  Linux    /var/run/reboot-required (Debian, Ubuntu), needs-restarting -r
+           (Fedora, RHEL), or a running kernel that is no longer installed
+  Windows  the Component Based Servicing RebootPending and Windows Update
+           RebootRequired registry keys
+  macOS    available updates that require a restart (softwareupdate)
+
+A reboot pending for more than 7 days is a high-severity finding.
+Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckUptime},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.CheckEnabled(inspector.CheckUptime) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckUptime)))
+			os.Exit(1)
+		}
+
+		result, err := inspector.GetUptimeContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fmt.Println(formatted(result, inspector.FormatUptime))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uptimeCmd)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var usbPolicyFlag string
+
+var usbCmd = &cobra.Command{
+	Use:     "usb",
+	Aliases: []string{"usb-devices"},
+	Short:   "Show connected USB devices and whether USB storage is blocked",
+	Long: `Display the connected USB devices with their vendor and product IDs,
+and whether USB mass storage is restricted:
+
+  Linux    modprobe rules that keep usb-storage from loading, USBGuard's
+           implicit policy
+  Windows  the USBSTOR driver's start type, removable storage access
+           policies
+  macOS    mount-controls for external disks set by a configuration profile
+
+With --policy=block (or OMNITRUST_USB_STORAGE_POLICY=block, or
+usb.storage_policy in the config file) the security summary scores the
+check and reports unrestricted USB storage as a finding.
+
+Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckUSBStorage},
+	Run: func(cmd *cobra.Command, args []string) {
+		if !inspector.CheckEnabled(inspector.CheckUSBStorage) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(inspector.CheckDisabledError(inspector.CheckUSBStorage)))
+			os.Exit(1)
+		}
+		switch usbPolicyFlag {
+		case "":
+		case inspector.USBStoragePolicyAllow, inspector.USBStoragePolicyBlock:
+			_ = os.Setenv(inspector.USBStoragePolicyEnv, usbPolicyFlag)
+		default:
+			fmt.Fprintf(os.Stderr, "Error: --policy must be %s or %s\n", inspector.USBStoragePolicyAllow, inspector.USBStoragePolicyBlock)
+			os.Exit(1)
+		}
+
+		result, err := inspector.GetUSBDevicesContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		fmt.Println(formatted(result, inspector.FormatUSBDevices))
+	},
+}
+
+func init() {
+	usbCmd.Flags().StringVar(&usbPolicyFlag, "policy", "", "Removable-media policy to check against: allow or block")
+	rootCmd.AddCommand(usbCmd)
+}
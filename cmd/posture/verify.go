@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/archive"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyIdentity string
+	verifySigner   string
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <archive>",
+	Short: "Verify a report archive written by scan",
+	Long: `Check that every member of a report archive matches the digest in its
+manifest, that no member was added or removed, and that the manifest's
+signature, if any, is good. Exits with code 1 if the archive fails.
+
+A signature only shows the archive is unchanged since it was signed by the
+key it names; pass --signer with the key ID of the scanning host (shown by
+a previous verify) to require that key:
+  omnitrust verify --identity auditor.key --signer <id> evidence.otar`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var opts archive.VerifyOptions
+		opts.Signer = verifySigner
+		if verifyIdentity != "" {
+			identity, err := archive.LoadIdentity(verifyIdentity)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			opts.Identity = identity
+		}
+
+		data, err := os.ReadFile(args[0]) // #nosec G304 -- user-supplied archive path
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		v, err := archive.Verify(data, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(formatted(v, archive.FormatVerification))
+		if !v.Valid {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	verifyCmd.Flags().StringVarP(&verifyIdentity, "identity", "i", "", "age identity file to decrypt an encrypted archive")
+	verifyCmd.Flags().StringVar(&verifySigner, "signer", "", "Require the archive to be signed by this key ID")
+	rootCmd.AddCommand(verifyCmd)
+}
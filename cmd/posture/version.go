@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show version and build information",
+	Long: `Show the version, commit, build date, Go version, and platform of this
+binary, with the version and go.sum checksum of every module compiled into
+it. Use the JSON output to inventory posture itself across a fleet.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(formatted(inspector.GetBuildInfo(), inspector.FormatBuildInfo))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
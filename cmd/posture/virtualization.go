@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var virtualizationCmd = &cobra.Command{
+	Use:     "virtualization",
+	Aliases: []string{"vm", "hypervisor"},
+	Short:   "Show whether the machine is a virtual machine",
+	Long: `Identify whether the machine is a virtual machine.
+
+Detects the hypervisor (KVM, Hyper-V, VMware, Xen, VirtualBox, QEMU,
+Parallels, bhyve, Apple Virtualization) from CPUID and DMI/SMBIOS strings,
+and reports whether the TPM is discrete, firmware, or virtual.
+
+Use --format=table for a colored ASCII table.`,
+	Annotations: map[string]string{checksAnnotation: inspector.CheckTPM},
+	Run: func(cmd *cobra.Command, args []string) {
+		result, err := inspector.GetVirtualizationStatusContext(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", inspector.ErrorMessage(err))
+			os.Exit(1)
+		}
+
+		output := formatted(result, inspector.FormatVirtualizationStatus)
+		fmt.Println(output)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(virtualizationCmd)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/spf13/cobra"
+)
+
+var (
+	waiveReason string
+	waiveUntil  string
+)
+
+var waiveCmd = &cobra.Command{
+	Use:   "waive <finding-id>",
+	Short: "Accept the risk of a finding until a date",
+	Long: `Waive a finding: record that its risk is accepted, why, and until when.
+While the waiver is active, summaries list the finding under waived instead
+of findings, so it is no longer reported as a problem to fix (posture fix
+does not offer it either). The check still fails and loses its points,
+unless OMNITRUST_WAIVERS_SCORE is true (config: waivers.score), which
+scores a failed check as passed, mandatory or not, when all of its findings
+are waived.
+
+--until takes a date (through the end of that day), an RFC 3339 time, or a
+duration from now such as 90d or 12w. Waiving a finding again replaces its
+waiver. Waivers are stored in OMNITRUST_WAIVERS (config: waivers.file), or
+else in waivers.json next to the per-user config file.
+
+Examples:
+  posture waive biometrics_not_configured --reason "Shared kiosk, no enrolled users" --until 2026-12-31
+  posture waive reboot_pending --reason "Change freeze" --until 14d
+  posture waive list
+  posture waive remove reboot_pending`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		until, err := inspector.ParseWaiverExpiry(waiveUntil, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		waiver, err := inspector.NewWaiver(args[0], waiveReason, until)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		waivers := loadWaivers()
+		waivers.Add(waiver)
+		saveWaivers(waivers)
+		fmt.Fprintf(os.Stderr, "Waived %s until %s in %s\n", waiver.Finding, waiver.Until.Local().Format(time.RFC3339), inspector.WaiversPath())
+	},
+}
+
+var waiveListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the waived findings, including expired waivers",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(formatted(loadWaivers(), inspector.FormatWaivers))
+	},
+}
+
+var waiveRemoveCmd = &cobra.Command{
+	Use:   "remove <finding-id>",
+	Short: "Remove the waiver of a finding",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		waivers := loadWaivers()
+		if !waivers.Remove(args[0]) {
+			fmt.Fprintf(os.Stderr, "Error: %s is not waived\n", args[0])
+			os.Exit(1)
+		}
+		saveWaivers(waivers)
+		fmt.Fprintf(os.Stderr, "Removed the waiver of %s\n", args[0])
+	},
+}
+
+// loadWaivers reads the waiver file, exiting if it is unreadable
+func loadWaivers() *inspector.WaiverList {
+	waivers, err := inspector.LoadWaivers(inspector.WaiversPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return waivers
+}
+
+// saveWaivers stores the waiver file
+func saveWaivers(waivers *inspector.WaiverList) {
+	if err := inspector.SaveWaivers(inspector.WaiversPath(), waivers); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	waiveCmd.Flags().StringVar(&waiveReason, "reason", "", "Why the risk is accepted (required)")
+	waiveCmd.Flags().StringVar(&waiveUntil, "until", "", "When the waiver expires: a date, an RFC 3339 time, or a duration such as 90d (required)")
+	_ = waiveCmd.MarkFlagRequired("reason")
+	_ = waiveCmd.MarkFlagRequired("until")
+	waiveCmd.AddCommand(waiveListCmd, waiveRemoveCmd)
+	rootCmd.AddCommand(waiveCmd)
+}
@@ -0,0 +1,544 @@
+// Package config loads the omnitrust configuration file, which lets fleet
+// deployments set output defaults, the checks to run and their scoring
+// weights, report sinks, server transport settings, and any command-line
+// flag in one place.
+//
+// Settings are layered: command-line flags override environment variables,
+// which override the config file, which overrides built-in defaults.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentplexus/posture/archive"
+	"github.com/agentplexus/posture/inspector"
+	"github.com/agentplexus/posture/redact"
+	"github.com/agentplexus/posture/render"
+	"github.com/agentplexus/posture/schedule"
+	"github.com/agentplexus/posture/server"
+	"github.com/agentplexus/posture/sink"
+)
+
+// PathEnv names a config file to load instead of the default locations
+const PathEnv = "OMNITRUST_CONFIG"
+
+// Color modes
+const (
+	ColorAuto   = inspector.ColorAuto
+	ColorAlways = inspector.ColorAlways
+	ColorNever  = inspector.ColorNever
+)
+
+// Config is the parsed config file
+type Config struct {
+	// Format is the default output format (json or table)
+	Format string `yaml:"format,omitempty"`
+	// Color is auto, always, or never
+	Color string `yaml:"color,omitempty"`
+	// Lang is the language of table output and recommendations (en, de, ja)
+	Lang string `yaml:"lang,omitempty"`
+	// Theme colors table output: a built-in theme (default, dark, light,
+	// high-contrast, monochrome) or one defined under Themes
+	Theme string `yaml:"theme,omitempty"`
+	// Themes defines custom themes by name. Each maps style names (header,
+	// success, warning, danger, info, muted, bold, dim) to color specs like
+	// "bold bright-cyan" or "245"; the optional "base" key names the
+	// built-in theme the others override.
+	Themes map[string]map[string]string `yaml:"themes,omitempty"`
+	// Profile is the scan profile that selects which checks run (quick,
+	// full, boot-security, privacy, or one under ScanProfiles)
+	Profile string `yaml:"profile,omitempty"`
+	// ScanProfiles adds scan profiles or replaces built-in ones by name
+	ScanProfiles map[string]inspector.ScanProfile `yaml:"scan_profiles,omitempty"`
+	// Envelope wraps JSON output in a report envelope with the report ID,
+	// machine ID, and collection metadata
+	Envelope bool   `yaml:"envelope,omitempty"`
+	Checks   Checks `yaml:"checks,omitempty"`
+	// CacheTTL is how long the MCP server caches slow probes (e.g. "5m", "0")
+	CacheTTL string `yaml:"cache_ttl,omitempty"`
+	// TPMCADir is a directory of additional TPM EK root certificates
+	TPMCADir string `yaml:"tpm_ca_dir,omitempty"`
+	// TPMVerifyEK set to false skips verifying the TPM EK certificate chain
+	TPMVerifyEK *bool `yaml:"tpm_verify_ek,omitempty"`
+	// CloudProbe turns the summary's instance metadata lookup on or off
+	// regardless of the system vendor
+	CloudProbe *bool `yaml:"cloud_probe,omitempty"`
+	// Baseline is the baseline file summaries are compared against
+	Baseline string `yaml:"baseline,omitempty"`
+	// Waivers sets where accepted findings are stored and how they score
+	Waivers Waivers `yaml:"waivers,omitempty"`
+	// Schedule sets when the daemon scans and what defers a scan
+	Schedule Schedule `yaml:"schedule,omitempty"`
+	// Remediations replaces the remediation text of findings by finding
+	// ID, e.g. with a link to the internal IT portal
+	Remediations map[string]string `yaml:"remediations,omitempty"`
+	// SigningKey is the Ed25519 key that signs report archives
+	SigningKey string        `yaml:"signing_key,omitempty"`
+	Filesystem Filesystem    `yaml:"filesystem,omitempty"`
+	Encryption Encryption    `yaml:"encryption,omitempty"`
+	USB        USB           `yaml:"usb,omitempty"`
+	Server     Server        `yaml:"server,omitempty"`
+	Redact     Redact        `yaml:"redact,omitempty"`
+	Log        Log           `yaml:"log,omitempty"`
+	Sinks      []sink.Config `yaml:"sinks,omitempty"`
+	// Commands sets flag defaults per command, keyed by command name (with
+	// spaces replaced by underscores for subcommands) and then flag name
+	Commands map[string]map[string]any `yaml:"commands,omitempty"`
+
+	// Path is the file the config was loaded from, empty if none was found
+	Path string `yaml:"-"`
+}
+
+// Checks selects, weights, and bounds security checks by check ID
+type Checks struct {
+	Only          []string           `yaml:"only,omitempty"`
+	Disable       []string           `yaml:"disable,omitempty"`
+	Mandatory     []string           `yaml:"mandatory,omitempty"`
+	Informational []string           `yaml:"informational,omitempty"`
+	Weights       map[string]float64 `yaml:"weights,omitempty"`
+	// Timeout bounds every summary check, e.g. "10s"; Timeouts overrides
+	// it per check
+	Timeout  string            `yaml:"timeout,omitempty"`
+	Timeouts map[string]string `yaml:"timeouts,omitempty"`
+}
+
+// Server configures the MCP server transport
+type Server struct {
+	// Transport is stdio or http
+	Transport string `yaml:"transport,omitempty"`
+	// Address is the http listen address
+	Address string `yaml:"address,omitempty"`
+	// Consent sets the consent policy (allow, ask, or deny) per tool name,
+	// or for all sensitive tools with the key "sensitive"
+	Consent map[string]string `yaml:"consent,omitempty"`
+	// HideUnsupported leaves out the tools this platform does not support
+	HideUnsupported bool `yaml:"hide_unsupported,omitempty"`
+}
+
+// Redact configures the masking of identifying values in output
+type Redact struct {
+	// Enable redacts all output, as --redact and the MCP redact option do
+	Enable bool `yaml:"enable,omitempty"`
+	// SaltFile keys stable pseudonyms; without a salt values are masked
+	SaltFile string `yaml:"salt_file,omitempty"`
+	// Rules add fields and patterns to redact.DefaultRules
+	Rules []redact.Rule `yaml:"rules,omitempty"`
+}
+
+// Filesystem scopes the filesystem audit
+type Filesystem struct {
+	// Paths replaces the directories searched for SUID/SGID binaries
+	Paths []string `yaml:"paths,omitempty"`
+	// Allowlist adds allowed SUID/SGID binaries (base names, paths, or globs)
+	Allowlist []string `yaml:"allowlist,omitempty"`
+	// Timeout bounds the audit (e.g. "30s")
+	Timeout string `yaml:"timeout,omitempty"`
+}
+
+// Encryption tunes the disk encryption probe
+type Encryption struct {
+	// LUKSScan is lsblk (list LUKS devices in one call) or cryptsetup
+	// (probe every block device)
+	LUKSScan string `yaml:"luks_scan,omitempty"`
+}
+
+// Waivers configures the findings accepted with posture waive
+type Waivers struct {
+	// File is the waiver file summaries read
+	File string `yaml:"file,omitempty"`
+	// Score scores a failed check as passed when all of its findings are
+	// waived
+	Score bool `yaml:"score,omitempty"`
+}
+
+// Schedule configures the daemon's scheduled scans
+type Schedule struct {
+	// Interval is the time between scans, e.g. "6h"; empty turns them off
+	Interval string `yaml:"interval,omitempty"`
+	// Jitter is the most each scan is randomly delayed by, e.g. "30m"
+	Jitter string `yaml:"jitter,omitempty"`
+	// Retry is how long a deferred scan waits before trying again
+	Retry string `yaml:"retry,omitempty"`
+	// MinBattery defers scans on battery power below this charge in
+	// percent (default 20); 0 ignores the battery
+	MinBattery *int `yaml:"min_battery,omitempty"`
+	// ACOnly defers scans whenever on battery power
+	ACOnly bool `yaml:"ac_only,omitempty"`
+	// DeferWhenBusy set to false scans even while the user presents or is
+	// on a call
+	DeferWhenBusy *bool `yaml:"defer_when_busy,omitempty"`
+}
+
+// USB sets the removable-media policy
+type USB struct {
+	// StoragePolicy is allow (report only) or block (score the check and
+	// raise a finding while USB mass storage is unrestricted)
+	StoragePolicy string `yaml:"storage_policy,omitempty"`
+}
+
+// Log configures diagnostic logging
+type Log struct {
+	// Level is debug, info, warn, error, or off
+	Level string `yaml:"level,omitempty"`
+	// Format is text or json
+	Format string `yaml:"format,omitempty"`
+	// File is appended to instead of writing to stderr
+	File string `yaml:"file,omitempty"`
+}
+
+// DefaultPaths returns the locations searched when no config file is named:
+// the per-user file first, then the system-wide file
+func DefaultPaths() []string {
+	var paths []string
+	if runtime.GOOS == "windows" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			paths = append(paths, filepath.Join(dir, "omnitrust", "config.yaml"))
+		}
+		if dir := os.Getenv("ProgramData"); dir != "" {
+			paths = append(paths, filepath.Join(dir, "omnitrust", "config.yaml"))
+		}
+		return paths
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		paths = append(paths, filepath.Join(dir, "omnitrust", "config.yaml"))
+	} else if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".config", "omnitrust", "config.yaml"))
+	}
+	return append(paths, "/etc/omnitrust/config.yaml")
+}
+
+// Load reads the config file at path. An empty path means the file named by
+// OMNITRUST_CONFIG, or else the first of DefaultPaths that exists; finding
+// no default file is not an error and yields an empty config.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		path = os.Getenv(PathEnv)
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		return Parse(data, path)
+	}
+	for _, p := range DefaultPaths() {
+		data, err := os.ReadFile(p)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("config: %w", err)
+		}
+		return Parse(data, p)
+	}
+	return &Config{}, nil
+}
+
+// Parse decodes and validates a config file. Unknown keys are rejected so
+// that typos do not silently fall back to defaults.
+func Parse(data []byte, path string) (*Config, error) {
+	c := &Config{Path: path}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// validate checks values that would otherwise only fail when used
+func (c *Config) validate() error {
+	var errs []error
+	if c.Color != "" && !slices.Contains([]string{ColorAuto, ColorAlways, ColorNever}, c.Color) {
+		errs = append(errs, fmt.Errorf("color must be %s, %s, or %s", ColorAuto, ColorAlways, ColorNever))
+	}
+	if c.Lang != "" && !slices.Contains(inspector.SupportedLocales(), c.Lang) {
+		errs = append(errs, fmt.Errorf("lang must be one of %s", strings.Join(inspector.SupportedLocales(), ", ")))
+	}
+	for name := range c.Themes {
+		if _, err := c.ResolveTheme(name); err != nil {
+			errs = append(errs, fmt.Errorf("themes.%s: %w", name, err))
+		}
+	}
+	if _, custom := c.Themes[c.Theme]; !custom {
+		if _, err := c.ResolveTheme(c.Theme); err != nil {
+			errs = append(errs, fmt.Errorf("theme: %w", err))
+		}
+	}
+	for name, p := range c.ScanProfiles {
+		if err := inspector.ValidateScanProfile(p); err != nil {
+			errs = append(errs, fmt.Errorf("scan_profiles.%s: %w", name, err))
+		}
+	}
+	if _, custom := c.ScanProfiles[c.Profile]; c.Profile != "" && !custom {
+		if _, err := inspector.LookupScanProfile(c.Profile); err != nil {
+			errs = append(errs, fmt.Errorf("profile: %w", err))
+		}
+	}
+	for _, d := range []struct{ key, value string }{{"interval", c.Schedule.Interval}, {"jitter", c.Schedule.Jitter}, {"retry", c.Schedule.Retry}} {
+		if d.value == "" {
+			continue
+		}
+		if _, err := schedule.ParseDuration(d.value); err != nil {
+			errs = append(errs, fmt.Errorf("schedule.%s: %w", d.key, err))
+		}
+	}
+	if p := c.Schedule.MinBattery; p != nil && (*p < 0 || *p > 100) {
+		errs = append(errs, errors.New("schedule.min_battery must be a percentage"))
+	}
+	for id, text := range c.Remediations {
+		if err := inspector.ValidateRemediation(id, text); err != nil {
+			errs = append(errs, fmt.Errorf("remediations: %w", err))
+		}
+	}
+	if c.CacheTTL != "" {
+		if _, err := time.ParseDuration(c.CacheTTL); err != nil {
+			errs = append(errs, fmt.Errorf("cache_ttl: %w", err))
+		}
+	}
+	if c.Filesystem.Timeout != "" {
+		if _, err := time.ParseDuration(c.Filesystem.Timeout); err != nil {
+			errs = append(errs, fmt.Errorf("filesystem.timeout: %w", err))
+		}
+	}
+	if m := c.Encryption.LUKSScan; m != "" && m != inspector.LUKSScanLsblk && m != inspector.LUKSScanCryptsetup {
+		errs = append(errs, fmt.Errorf("encryption.luks_scan must be %s or %s", inspector.LUKSScanLsblk, inspector.LUKSScanCryptsetup))
+	}
+	if p := c.USB.StoragePolicy; p != "" && p != inspector.USBStoragePolicyAllow && p != inspector.USBStoragePolicyBlock {
+		errs = append(errs, fmt.Errorf("usb.storage_policy must be %s or %s", inspector.USBStoragePolicyAllow, inspector.USBStoragePolicyBlock))
+	}
+	if t := c.Server.Transport; t != "" && t != server.TransportStdio && t != server.TransportHTTP {
+		errs = append(errs, fmt.Errorf("server.transport must be %s or %s", server.TransportStdio, server.TransportHTTP))
+	}
+	if _, err := redact.New(nil, c.Redact.Rules); err != nil {
+		errs = append(errs, fmt.Errorf("redact.rules: %w", err))
+	}
+	if err := server.ValidateConsent(c.Server.Consent); err != nil {
+		errs = append(errs, fmt.Errorf("server.consent: %w", err))
+	}
+	if _, err := inspector.NewLogger(io.Discard, c.Log.Level, c.Log.Format); err != nil {
+		errs = append(errs, fmt.Errorf("log: %w", err))
+	}
+	for id, w := range c.Checks.Weights {
+		if w < 0 {
+			errs = append(errs, fmt.Errorf("checks.weights.%s must not be negative", id))
+		}
+	}
+	if c.Checks.Timeout != "" && c.Checks.Timeout != "0" {
+		if _, err := time.ParseDuration(c.Checks.Timeout); err != nil {
+			errs = append(errs, fmt.Errorf("checks.timeout: %w", err))
+		}
+	}
+	for id, d := range c.Checks.Timeouts {
+		if _, err := time.ParseDuration(d); err != nil {
+			errs = append(errs, fmt.Errorf("checks.timeouts.%s: %w", id, err))
+		}
+	}
+	if _, err := sink.NewAll(c.Sinks); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// ApplyEnv exports the config's language, scan profile, logging, check,
+// cache, TPM, cloud probe, baseline, waiver, schedule, remediation, filesystem audit, encryption, USB policy,
+// server, and redaction settings as the environment variables the
+// inspector and server packages read.
+// Variables that are already set are left alone, so the environment
+// overrides the file.
+func (c *Config) ApplyEnv() {
+	setDefaultEnv(inspector.LangEnv, c.Lang)
+	setDefaultEnv(inspector.LogLevelEnv, c.Log.Level)
+	setDefaultEnv(inspector.LogFormatEnv, c.Log.Format)
+	setDefaultEnv(inspector.LogFileEnv, c.Log.File)
+	setDefaultEnv(inspector.ScanProfileEnv, c.Profile)
+	if len(c.ScanProfiles) > 0 {
+		data, _ := json.Marshal(c.ScanProfiles)
+		setDefaultEnv(inspector.ScanProfilesEnv, string(data))
+	}
+	setDefaultEnv(inspector.OnlyChecksEnv, strings.Join(c.Checks.Only, ","))
+	setDefaultEnv(inspector.DisableChecksEnv, strings.Join(c.Checks.Disable, ","))
+	setDefaultEnv(inspector.MandatoryChecksEnv, strings.Join(c.Checks.Mandatory, ","))
+	setDefaultEnv(inspector.InformationalChecksEnv, strings.Join(c.Checks.Informational, ","))
+	setDefaultEnv(inspector.CheckWeightsEnv, formatWeights(c.Checks.Weights))
+	setDefaultEnv(inspector.CheckTimeoutEnv, c.Checks.Timeout)
+	setDefaultEnv(inspector.CheckTimeoutsEnv, formatTimeouts(c.Checks.Timeouts))
+	setDefaultEnv(server.CacheTTLEnv, c.CacheTTL)
+	setDefaultEnv(inspector.TPMCADirEnv, c.TPMCADir)
+	setDefaultEnv(inspector.BaselineEnv, c.Baseline)
+	setDefaultEnv(inspector.WaiversEnv, c.Waivers.File)
+	if c.Waivers.Score {
+		setDefaultEnv(inspector.WaiversScoreEnv, "true")
+	}
+	setDefaultEnv(schedule.IntervalEnv, c.Schedule.Interval)
+	setDefaultEnv(schedule.JitterEnv, c.Schedule.Jitter)
+	setDefaultEnv(schedule.RetryEnv, c.Schedule.Retry)
+	if c.Schedule.MinBattery != nil {
+		setDefaultEnv(schedule.MinBatteryEnv, strconv.Itoa(*c.Schedule.MinBattery))
+	}
+	if c.Schedule.ACOnly {
+		setDefaultEnv(schedule.ACOnlyEnv, "true")
+	}
+	if c.Schedule.DeferWhenBusy != nil {
+		setDefaultEnv(schedule.DeferWhenBusyEnv, strconv.FormatBool(*c.Schedule.DeferWhenBusy))
+	}
+	if len(c.Remediations) > 0 {
+		data, _ := json.Marshal(c.Remediations)
+		setDefaultEnv(inspector.RemediationsEnv, string(data))
+	}
+	setDefaultEnv(archive.SigningKeyEnv, c.SigningKey)
+	if c.TPMVerifyEK != nil && !*c.TPMVerifyEK {
+		setDefaultEnv(inspector.TPMVerifyEKEnv, "false")
+	}
+	if c.CloudProbe != nil {
+		setDefaultEnv(inspector.CloudProbeEnv, strconv.FormatBool(*c.CloudProbe))
+	}
+	setDefaultEnv(inspector.FilesystemAuditPathsEnv, strings.Join(c.Filesystem.Paths, ","))
+	setDefaultEnv(inspector.SetIDAllowlistEnv, strings.Join(c.Filesystem.Allowlist, ","))
+	setDefaultEnv(inspector.FilesystemAuditTimeoutEnv, c.Filesystem.Timeout)
+	setDefaultEnv(inspector.LUKSScanEnv, c.Encryption.LUKSScan)
+	setDefaultEnv(inspector.USBStoragePolicyEnv, c.USB.StoragePolicy)
+	setDefaultEnv(server.TransportEnv, c.Server.Transport)
+	setDefaultEnv(server.AddressEnv, c.Server.Address)
+	setDefaultEnv(server.ConsentEnv, server.FormatConsent(c.Server.Consent))
+	if c.Server.HideUnsupported {
+		setDefaultEnv(server.HideUnsupportedEnv, "true")
+	}
+	if c.Redact.Enable {
+		setDefaultEnv(redact.EnableEnv, "true")
+	}
+	setDefaultEnv(redact.SaltFileEnv, c.Redact.SaltFile)
+	if len(c.Redact.Rules) > 0 {
+		rules, _ := json.Marshal(c.Redact.Rules)
+		setDefaultEnv(redact.RulesEnv, string(rules))
+	}
+}
+
+// setDefaultEnv sets key to value unless value is empty or key is already set
+func setDefaultEnv(key, value string) {
+	if value == "" {
+		return
+	}
+	if _, ok := os.LookupEnv(key); ok {
+		return
+	}
+	_ = os.Setenv(key, value)
+}
+
+// formatWeights renders weights in the OMNITRUST_CHECK_WEIGHTS syntax
+func formatWeights(weights map[string]float64) string {
+	entries := make([]string, 0, len(weights))
+	for id, w := range weights {
+		entries = append(entries, id+"="+strconv.FormatFloat(w, 'g', -1, 64))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// formatTimeouts renders timeouts in the OMNITRUST_CHECK_TIMEOUTS syntax
+func formatTimeouts(timeouts map[string]string) string {
+	entries := make([]string, 0, len(timeouts))
+	for id, d := range timeouts {
+		entries = append(entries, id+"="+d)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// FlagEnv returns the environment variable that overrides a flag: a global
+// flag such as --format is OMNITRUST_FORMAT, a command flag such as
+// processes --sort is OMNITRUST_PROCESSES_SORT
+func FlagEnv(command, flag string) string {
+	name := flag
+	if command != "" {
+		name = command + "_" + flag
+	}
+	return "OMNITRUST_" + strings.ToUpper(strings.NewReplacer("-", "_", " ", "_").Replace(name))
+}
+
+// themeBase is the key of a custom theme that names its built-in base
+const themeBase = "base"
+
+// ResolveTheme returns the theme called name: a custom theme from Themes,
+// applied over its base, or else a built-in theme. An empty name is the
+// default theme.
+func (c *Config) ResolveTheme(name string) (render.Theme, error) {
+	if name == "" {
+		name = render.ThemeDefault
+	}
+	custom, ok := c.Themes[name]
+	if !ok {
+		t, ok := render.LookupTheme(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown theme %q (use %s, or define it under themes)", name, strings.Join(render.ThemeNames(), ", "))
+		}
+		return t, nil
+	}
+	baseName := custom[themeBase]
+	if baseName == "" {
+		baseName = render.ThemeDefault
+	}
+	base, ok := render.LookupTheme(baseName)
+	if !ok {
+		return nil, fmt.Errorf("base must be a built-in theme (%s)", strings.Join(render.ThemeNames(), ", "))
+	}
+	styles := maps.Clone(custom)
+	delete(styles, themeBase)
+	return render.NewTheme(base, styles)
+}
+
+// FlagValue returns the configured value for a flag: the command's section
+// first, then the top-level format, color, lang, theme, profile, envelope,
+// and log settings. Lists are joined with commas, the syntax of slice flags.
+func (c *Config) FlagValue(command, flag string) (string, bool) {
+	if v, ok := c.Commands[command][flag]; ok && v != nil {
+		if list, ok := v.([]any); ok {
+			items := make([]string, len(list))
+			for i, item := range list {
+				items[i] = fmt.Sprint(item)
+			}
+			return strings.Join(items, ","), true
+		}
+		return fmt.Sprint(v), true
+	}
+	switch flag {
+	case "format":
+		return c.Format, c.Format != ""
+	case "color":
+		return c.Color, c.Color != ""
+	case "lang":
+		return c.Lang, c.Lang != ""
+	case "theme":
+		return c.Theme, c.Theme != ""
+	case "profile":
+		return c.Profile, c.Profile != ""
+	case "envelope":
+		return "true", c.Envelope
+	case "log-level":
+		return c.Log.Level, c.Log.Level != ""
+	case "log-format":
+		return c.Log.Format, c.Log.Format != ""
+	case "log-file":
+		return c.Log.File, c.Log.File != ""
+	}
+	return "", false
+}
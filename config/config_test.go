@@ -0,0 +1,268 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/posture/archive"
+	"github.com/agentplexus/posture/inspector"
+	"github.com/agentplexus/posture/redact"
+	"github.com/agentplexus/posture/render"
+	"github.com/agentplexus/posture/schedule"
+	"github.com/agentplexus/posture/server"
+)
+
+const sampleConfig = `
+format: table
+color: never
+theme: solarized
+themes:
+  solarized:
+    base: dark
+    muted: bright-green
+profile: audit
+scan_profiles:
+  audit:
+    description: Boot chain and disk encryption
+    checks: [tpm, secure_boot, encryption]
+checks:
+  disable: [biometrics]
+  mandatory: [encryption]
+  weights:
+    encryption: 3
+    tpm: 0.5
+  timeout: 5s
+  timeouts:
+    encryption: 30s
+cache_ttl: 5m
+tpm_verify_ek: false
+cloud_probe: true
+baseline: /etc/omnitrust/baseline.json
+waivers:
+  file: /etc/omnitrust/waivers.json
+  score: true
+schedule:
+  interval: 6h
+  jitter: 30m
+  min_battery: 30
+  defer_when_busy: false
+remediations:
+  encryption_disabled: Request disk encryption at https://it.example.com/encryption
+signing_key: /etc/omnitrust/signing.key
+encryption:
+  luks_scan: cryptsetup
+server:
+  transport: http
+  address: 0.0.0.0:9090
+  consent:
+    sensitive: ask
+    scan_secrets: deny
+  hide_unsupported: true
+redact:
+  salt_file: /etc/omnitrust/redact.salt
+  rules:
+    - kind: user
+      fields: [owner_name]
+sinks:
+  - type: file
+    path: /var/lib/omnitrust/{hostname}.json
+commands:
+  processes:
+    sort: memory
+    limit: 20
+  summary:
+    format: json
+  biometrics:
+    all-users: true
+    exclude: [guest, kiosk]
+`
+
+func TestParse(t *testing.T) {
+	c, err := Parse([]byte(sampleConfig), "test.yaml")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if c.Format != "table" || c.Color != ColorNever || c.Server.Transport != server.TransportHTTP {
+		t.Errorf("top-level settings = %q %q %q", c.Format, c.Color, c.Server.Transport)
+	}
+	if len(c.Sinks) != 1 || c.Sinks[0].Type != "file" {
+		t.Errorf("sinks = %+v", c.Sinks)
+	}
+
+	tests := []struct {
+		command, flag string
+		want          string
+		ok            bool
+	}{
+		{"processes", "sort", "memory", true},
+		{"processes", "limit", "20", true},
+		{"processes", "format", "table", true},
+		{"summary", "format", "json", true},
+		{"biometrics", "all-users", "true", true},
+		{"biometrics", "exclude", "guest,kiosk", true},
+		{"", "color", "never", true},
+		{"", "theme", "solarized", true},
+		{"", "profile", "audit", true},
+		{"processes", "user", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := c.FlagValue(tt.command, tt.flag)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("FlagValue(%q, %q) = %q, %v; want %q, %v", tt.command, tt.flag, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown key":       "formatt: table",
+		"bad color":         "color: rainbow",
+		"bad lang":          "lang: fr",
+		"bad log level":     "log: {level: loud}",
+		"bad ttl":           "cache_ttl: soon",
+		"bad transport":     "server: {transport: grpc}",
+		"bad redact rule":   "redact: {rules: [{kind: email, fields: [email]}]}",
+		"bad consent":       "server: {consent: {list_processes: maybe}}",
+		"bad fs timeout":    "filesystem: {timeout: soon}",
+		"bad usb policy":    "usb: {storage_policy: deny}",
+		"bad luks scan":     "encryption: {luks_scan: blkid}",
+		"bad sink":          "sinks: [{type: file}]",
+		"negative weight":   "checks: {weights: {tpm: -1}}",
+		"bad timeout":       "checks: {timeout: soon}",
+		"bad check timeout": "checks: {timeouts: {encryption: 30}}",
+		"unknown theme":     "theme: neon",
+		"bad theme style":   "themes: {mine: {sparkle: red}}",
+		"bad theme color":   "themes: {mine: {muted: mauve}}",
+		"bad theme base":    "themes: {mine: {base: mine}}",
+		"unknown profile":   "profile: thorough",
+		"bad scan profile":  "scan_profiles: {mine: {checks: [tpm, antivirus]}}",
+		"bad remediation":   "remediations: {Encryption-Disabled: Ask IT}",
+		"empty remediation": "remediations: {encryption_disabled: ''}",
+		"bad interval":      "schedule: {interval: daily}",
+		"negative jitter":   "schedule: {jitter: -5m}",
+		"bad min battery":   "schedule: {min_battery: 120}",
+	}
+	for name, data := range tests {
+		if _, err := Parse([]byte(data), "test.yaml"); err == nil {
+			t.Errorf("%s: Parse(%q) should fail", name, data)
+		}
+	}
+	if _, err := Parse(nil, "empty.yaml"); err != nil {
+		t.Errorf("an empty file should be valid: %v", err)
+	}
+}
+
+func TestResolveTheme(t *testing.T) {
+	c, err := Parse([]byte(sampleConfig), "test.yaml")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	theme, err := c.ResolveTheme("solarized")
+	if err != nil {
+		t.Fatalf("ResolveTheme: %v", err)
+	}
+	dark, _ := render.LookupTheme(render.ThemeDark)
+	if theme[render.StyleMuted] != render.BrightGreen || theme[render.StyleHeader] != dark[render.StyleHeader] {
+		t.Errorf("custom theme = %q, want dark with bright green muted text", theme)
+	}
+	if dark[render.StyleMuted] == render.BrightGreen {
+		t.Error("a custom theme must not modify its base")
+	}
+
+	if theme, err := c.ResolveTheme(""); err != nil || theme[render.StyleMuted] != render.BrightBlack {
+		t.Errorf("ResolveTheme(\"\") = %q, %v; want the default theme", theme, err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv(PathEnv, "")
+
+	// No default file is fine, but a named file must exist
+	if c, err := Load(""); err != nil || c.Path != "" && !strings.HasPrefix(c.Path, "/etc/") {
+		t.Errorf("Load without a file = %+v, %v", c, err)
+	}
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("Load of a missing explicit file should fail")
+	}
+
+	path := filepath.Join(dir, "omnitrust", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("format: table\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := Load(""); err != nil || c.Path != path || c.Format != "table" {
+		t.Errorf("Load default = %+v, %v", c, err)
+	}
+
+	other := filepath.Join(dir, "fleet.yaml")
+	if err := os.WriteFile(other, []byte("format: json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(PathEnv, other)
+	if c, err := Load(""); err != nil || c.Path != other {
+		t.Errorf("Load with %s = %+v, %v", PathEnv, c, err)
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	for _, key := range []string{inspector.ScanProfileEnv, inspector.ScanProfilesEnv, inspector.DisableChecksEnv, inspector.CheckWeightsEnv, inspector.CheckTimeoutEnv, inspector.CheckTimeoutsEnv, inspector.TPMVerifyEKEnv, inspector.CloudProbeEnv, inspector.LUKSScanEnv, inspector.BaselineEnv, inspector.WaiversEnv, inspector.WaiversScoreEnv, inspector.RemediationsEnv, schedule.IntervalEnv, schedule.JitterEnv, schedule.RetryEnv, schedule.MinBatteryEnv, schedule.ACOnlyEnv, schedule.DeferWhenBusyEnv, archive.SigningKeyEnv, server.CacheTTLEnv, server.TransportEnv, server.AddressEnv, server.ConsentEnv, server.HideUnsupportedEnv, redact.SaltFileEnv, redact.RulesEnv} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	t.Setenv(inspector.MandatoryChecksEnv, "tpm")
+
+	c, err := Parse([]byte(sampleConfig), "test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.ApplyEnv()
+
+	want := map[string]string{
+		inspector.ScanProfileEnv:     "audit",
+		inspector.ScanProfilesEnv:    `{"audit":{"description":"Boot chain and disk encryption","checks":["tpm","secure_boot","encryption"]}}`,
+		inspector.DisableChecksEnv:   "biometrics",
+		inspector.CheckWeightsEnv:    "encryption=3,tpm=0.5",
+		inspector.CheckTimeoutEnv:    "5s",
+		inspector.CheckTimeoutsEnv:   "encryption=30s",
+		server.CacheTTLEnv:           "5m",
+		inspector.TPMVerifyEKEnv:     "false",
+		inspector.CloudProbeEnv:      "true",
+		inspector.BaselineEnv:        "/etc/omnitrust/baseline.json",
+		inspector.WaiversEnv:         "/etc/omnitrust/waivers.json",
+		inspector.WaiversScoreEnv:    "true",
+		schedule.IntervalEnv:         "6h",
+		schedule.JitterEnv:           "30m",
+		schedule.MinBatteryEnv:       "30",
+		schedule.DeferWhenBusyEnv:    "false",
+		inspector.RemediationsEnv:    `{"encryption_disabled":"Request disk encryption at https://it.example.com/encryption"}`,
+		inspector.LUKSScanEnv:        "cryptsetup",
+		archive.SigningKeyEnv:        "/etc/omnitrust/signing.key",
+		server.ConsentEnv:            "scan_secrets=deny,sensitive=ask",
+		server.HideUnsupportedEnv:    "true",
+		redact.SaltFileEnv:           "/etc/omnitrust/redact.salt",
+		redact.RulesEnv:              `[{"kind":"user","fields":["owner_name"]}]`,
+		inspector.MandatoryChecksEnv: "tpm", // the environment wins over the file
+	}
+	for key, v := range want {
+		if got := os.Getenv(key); got != v {
+			t.Errorf("%s = %q, want %q", key, got, v)
+		}
+	}
+}
+
+func TestFlagEnv(t *testing.T) {
+	if got := FlagEnv("", "format"); got != "OMNITRUST_FORMAT" {
+		t.Errorf("FlagEnv global = %q", got)
+	}
+	if got := FlagEnv("processes", "min-cpu"); got != "OMNITRUST_PROCESSES_MIN_CPU" {
+		t.Errorf("FlagEnv command = %q", got)
+	}
+}
@@ -0,0 +1,67 @@
+//go:build linux
+
+package inspector
+
+import (
+	"context"
+	"os"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// meiConnectClient is IOCTL_MEI_CONNECT_CLIENT, _IOWR('H', 0x01, struct
+// mei_connect_client_data) from linux/mei.h
+const meiConnectClient = 0xc0104801
+
+// amthiClientGUID is amthiClientUUID in the mixed-endian layout of the
+// kernel's uuid_le
+var amthiClientGUID = [16]byte{
+	0x28, 0x00, 0xf8, 0x12, 0xb7, 0xb4, 0x2d, 0x4b,
+	0xac, 0xa8, 0x46, 0xe0, 0xff, 0x65, 0x81, 0x4c,
+}
+
+// readLinuxAMTProvisioningState connects to the AMT host interface client
+// on the MEI device and asks for the provisioning state. It returns "" if
+// the device cannot be opened, which takes root, or does not answer.
+func readLinuxAMTProvisioningState(ctx context.Context, dev string) string {
+	f, err := os.OpenFile(dev, os.O_RDWR, 0)
+	if err != nil {
+		loggerFrom(ctx).Debug("cannot open the MEI device", "device", dev, "err", err)
+		return ""
+	}
+	defer f.Close()
+
+	// The connect data is the client UUID going in and the client
+	// properties coming back
+	data := amthiClientGUID
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return ""
+	}
+	var errno unix.Errno
+	if err := conn.Control(func(fd uintptr) {
+		_, _, errno = unix.Syscall(unix.SYS_IOCTL, fd, meiConnectClient, uintptr(unsafe.Pointer(&data)))
+	}); err != nil || errno != 0 {
+		loggerFrom(ctx).Debug("cannot connect to the AMT host interface", "device", dev, "err", errno)
+		return ""
+	}
+
+	// A read blocks until the ME answers, which it may never do
+	_ = f.SetDeadline(time.Now().Add(2 * time.Second))
+	if _, err := f.Write(amthiRequest(amthiGetProvisioningState)); err != nil {
+		return ""
+	}
+	resp := make([]byte, 64)
+	n, err := f.Read(resp)
+	if err != nil {
+		return ""
+	}
+	state, err := parseAMTProvisioningState(resp[:n])
+	if err != nil {
+		loggerFrom(ctx).Debug("cannot read the AMT provisioning state", "device", dev, "err", err)
+		return ""
+	}
+	return state
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package inspector
+
+import "context"
+
+// readLinuxAMTProvisioningState is only implemented on Linux
+func readLinuxAMTProvisioningState(ctx context.Context, dev string) string {
+	return ""
+}
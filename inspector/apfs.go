@@ -0,0 +1,165 @@
+package inspector
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// apfsVolume is an APFS volume as reported by `diskutil apfs list -plist`
+type apfsVolume struct {
+	Name             string
+	DeviceIdentifier string
+	Container        string
+	Roles            []string
+	Encryption       bool
+	FileVault        bool
+	Locked           bool
+	// CapacityInUse is the space consumed by this volume in bytes
+	CapacityInUse uint64
+	// CapacityQuota is the volume's quota in bytes, or 0 if unlimited
+	CapacityQuota uint64
+	// ContainerCapacity is the size of the container the volume shares
+	ContainerCapacity uint64
+	// PhysicalStore is the partition backing the container (e.g. disk0s2)
+	PhysicalStore string
+}
+
+// parseAPFSList decodes the output of `diskutil apfs list -plist`
+func parseAPFSList(data []byte) ([]apfsVolume, error) {
+	root, err := decodePlist(data)
+	if err != nil {
+		return nil, err
+	}
+	dict, ok := root.(map[string]any)
+	if !ok {
+		return nil, errors.New("diskutil apfs list: expected a dictionary")
+	}
+
+	var volumes []apfsVolume
+	for _, container := range plistDicts(dict, "Containers") {
+		ref := plistString(container, "ContainerReference")
+		store := plistString(container, "DesignatedPhysicalStore")
+		if stores := plistDicts(container, "PhysicalStores"); store == "" && len(stores) > 0 {
+			store = plistString(stores[0], "DeviceIdentifier")
+		}
+		ceiling := plistUint(container, "CapacityCeiling")
+		for _, v := range plistDicts(container, "Volumes") {
+			volumes = append(volumes, apfsVolume{
+				Name:              plistString(v, "Name"),
+				DeviceIdentifier:  plistString(v, "DeviceIdentifier"),
+				Container:         ref,
+				Roles:             plistStrings(v, "Roles"),
+				Encryption:        plistBool(v, "Encryption"),
+				FileVault:         plistBool(v, "FileVault"),
+				Locked:            plistBool(v, "Locked"),
+				CapacityInUse:     plistUint(v, "CapacityInUse"),
+				CapacityQuota:     plistUint(v, "CapacityQuota"),
+				ContainerCapacity: ceiling,
+				PhysicalStore:     store,
+			})
+		}
+	}
+	return volumes, nil
+}
+
+// hasRole reports whether the volume has the given APFS role (System, Data, ...)
+func (v apfsVolume) hasRole(role string) bool {
+	return slices.Contains(v.Roles, role)
+}
+
+// role returns the volume's primary APFS role, or "" for plain volumes
+func (v apfsVolume) role() string {
+	if len(v.Roles) == 0 {
+		return ""
+	}
+	return v.Roles[0]
+}
+
+// capacity returns the space available to the volume: its quota if set,
+// otherwise the capacity of the container it shares
+func (v apfsVolume) capacity() uint64 {
+	if v.CapacityQuota > 0 {
+		return v.CapacityQuota
+	}
+	return v.ContainerCapacity
+}
+
+// mountPoint returns the standard mount point for system and data volumes
+func (v apfsVolume) mountPoint() string {
+	switch {
+	case v.hasRole("System"):
+		return "/"
+	case v.hasRole("Data"):
+		return "/System/Volumes/Data"
+	}
+	return ""
+}
+
+// mountPointIn returns where the volume is mounted according to mounts (see
+// parseMountTable). The sealed system volume is mounted from a snapshot
+// (disk3s1s1), so snapshot devices count too. Falls back to the standard
+// mount point for the volume's role.
+func (v apfsVolume) mountPointIn(mounts map[string]string) string {
+	if mp, ok := mounts[v.DeviceIdentifier]; ok {
+		return mp
+	}
+	if v.DeviceIdentifier != "" {
+		for device, mp := range mounts {
+			if strings.HasPrefix(device, v.DeviceIdentifier+"s") {
+				return mp
+			}
+		}
+	}
+	return v.mountPoint()
+}
+
+// encrypted reports whether the volume is encrypted (FileVault or APFS native)
+func (v apfsVolume) encrypted() bool {
+	return v.Encryption || v.FileVault
+}
+
+// encryptionStatus returns the EncryptedVolume status string for the volume
+func (v apfsVolume) encryptionStatus() string {
+	switch {
+	case !v.encrypted():
+		return "not_encrypted"
+	case v.Locked:
+		return "encrypted_locked"
+	}
+	return "encrypted_unlocked"
+}
+
+// parseMountTable maps device identifiers (disk3s5) to mount points from the
+// output of `mount`, whose lines look like
+// "/dev/disk3s5 on /System/Volumes/Data (apfs, local, journaled)"
+func parseMountTable(data []byte) map[string]string {
+	mounts := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		device, rest, ok := strings.Cut(line, " on ")
+		if !ok || !strings.HasPrefix(device, "/dev/") {
+			continue
+		}
+		// Mount points may contain spaces; the options follow the last " ("
+		if i := strings.LastIndex(rest, " ("); i >= 0 {
+			rest = rest[:i]
+		}
+		mounts[strings.TrimPrefix(device, "/dev/")] = rest
+	}
+	return mounts
+}
+
+// parseDiskInternal reads the Internal flag from `diskutil info -plist <disk>`.
+// ok is false if the output does not say.
+func parseDiskInternal(data []byte) (internal, ok bool) {
+	root, err := decodePlist(data)
+	if err != nil {
+		return false, false
+	}
+	dict, isDict := root.(map[string]any)
+	if !isDict {
+		return false, false
+	}
+	internal, ok = dict["Internal"].(bool)
+	return internal, ok
+}
@@ -0,0 +1,94 @@
+package inspector
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseAPFSList(t *testing.T) {
+	volumes, err := parseAPFSList(fixture(t, "darwin/diskutil_apfs_list.plist"))
+	if err != nil {
+		t.Fatalf("parseAPFSList failed: %v", err)
+	}
+	if len(volumes) != 6 {
+		t.Fatalf("len(volumes) = %d, want 6", len(volumes))
+	}
+
+	system := volumes[0]
+	if system.Name != "Macintosh HD" || system.DeviceIdentifier != "disk3s1" || system.Container != "disk3" {
+		t.Errorf("system volume = %+v", system)
+	}
+	if !slices.Equal(system.Roles, []string{"System"}) || system.mountPoint() != "/" {
+		t.Errorf("system roles = %v, mount point %q", system.Roles, system.mountPoint())
+	}
+
+	tests := []struct {
+		index  int
+		status string
+	}{
+		{0, "encrypted_unlocked"},
+		{1, "not_encrypted"},
+		{2, "encrypted_unlocked"},
+		{3, "encrypted_locked"},
+	}
+	for _, tt := range tests {
+		if got := volumes[tt.index].encryptionStatus(); got != tt.status {
+			t.Errorf("%s status = %q, want %q", volumes[tt.index].Name, got, tt.status)
+		}
+	}
+	if volumes[2].mountPoint() != "/System/Volumes/Data" {
+		t.Errorf("data mount point = %q", volumes[2].mountPoint())
+	}
+
+	// Volumes share their container's capacity unless they have a quota
+	if system.ContainerCapacity != 494384795648 || system.capacity() != 494384795648 || system.PhysicalStore != "disk0s2" {
+		t.Errorf("system capacity = %d, store %q", system.capacity(), system.PhysicalStore)
+	}
+	projects := volumes[5]
+	if projects.Container != "disk5" || projects.PhysicalStore != "disk4s2" || projects.capacity() != 107374182400 {
+		t.Errorf("external volume = %+v", projects)
+	}
+	if volumes[4].role() != "Backup" || projects.role() != "" {
+		t.Errorf("roles = %q, %q", volumes[4].role(), projects.role())
+	}
+}
+
+func TestParseMountTable(t *testing.T) {
+	mounts := parseMountTable(fixture(t, "darwin/mount.txt"))
+	if got := mounts["disk5s1"]; got != "/Volumes/Time Machine" {
+		t.Errorf("disk5s1 = %q, want /Volumes/Time Machine", got)
+	}
+	if _, ok := mounts["auto_home"]; ok {
+		t.Error("non-device mounts should be skipped")
+	}
+
+	// The system volume is mounted from a snapshot of disk3s1
+	volumes, err := parseAPFSList(fixture(t, "darwin/diskutil_apfs_list.plist"))
+	if err != nil {
+		t.Fatalf("parseAPFSList failed: %v", err)
+	}
+	if got := volumes[0].mountPointIn(mounts); got != "/" {
+		t.Errorf("system mount point = %q, want /", got)
+	}
+	if got := volumes[3].mountPointIn(mounts); got != "" {
+		t.Errorf("unmounted volume mount point = %q, want empty", got)
+	}
+}
+
+func TestParseDiskInternal(t *testing.T) {
+	if internal, ok := parseDiskInternal(fixture(t, "darwin/diskutil_info_disk0s2.plist")); !internal || !ok {
+		t.Errorf("disk0s2 = (%v, %v), want internal", internal, ok)
+	}
+	if internal, ok := parseDiskInternal(fixture(t, "darwin/diskutil_info_disk4s2.plist")); internal || !ok {
+		t.Errorf("disk4s2 = (%v, %v), want external", internal, ok)
+	}
+	if _, ok := parseDiskInternal([]byte("not a plist")); ok {
+		t.Error("expected ok=false for invalid output")
+	}
+}
+
+func TestParseAPFSList_Invalid(t *testing.T) {
+	if _, err := parseAPFSList([]byte(`<plist><array/></plist>`)); err == nil {
+		t.Error("expected error for non-dictionary root")
+	}
+}
@@ -0,0 +1,351 @@
+package inspector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BaselineEnv names the baseline file compared against by every summary,
+// instead of the default location (see BaselinePath)
+const BaselineEnv = "OMNITRUST_BASELINE"
+
+// Check outcomes recorded in SecuritySummary.CheckResults and baselines
+const (
+	CheckResultPass = "pass"
+	CheckResultFail = "fail"
+	// CheckResultTimeout checks ran past their timeout (see CheckTimeout);
+	// baselines do not record them
+	CheckResultTimeout = "timeout"
+)
+
+// How a check's outcome changed since the baseline
+const (
+	DeltaUnchanged = "unchanged"
+	DeltaImproved  = "improved"
+	DeltaRegressed = "regressed"
+	// DeltaAdded checks ran now but not when the baseline was taken
+	DeltaAdded = "added"
+	// DeltaRemoved checks ran when the baseline was taken but not now
+	DeltaRemoved = "removed"
+	// DeltaUnverified checks timed out now, so they cannot be compared
+	DeltaUnverified = "unverified"
+)
+
+// Baseline is a recorded security posture that later summaries are
+// compared against. It can be exported and imported as JSON, so one
+// golden baseline can be shared across a fleet.
+type Baseline struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	CreatedAt time.Time `json:"created_at"`
+	// Hostname and Platform describe the machine the baseline was taken on
+	Hostname     string `json:"hostname,omitempty"`
+	Platform     string `json:"platform"`
+	OverallScore int    `json:"overall_score"`
+	// Checks maps each check that ran to pass or fail
+	Checks map[string]string `json:"checks"`
+}
+
+// BaselineDelta compares a summary with the stored baseline
+type BaselineDelta struct {
+	BaselineCreatedAt time.Time `json:"baseline_created_at"`
+	BaselineScore     int       `json:"baseline_score"`
+	// Score is the current score minus the baseline's
+	Score  int          `json:"score"`
+	Checks []CheckDelta `json:"checks"`
+	// Regressed lists the checks that passed in the baseline and fail now
+	Regressed []string `json:"regressed,omitempty"`
+	// Improved lists the checks that failed in the baseline and pass now
+	Improved []string `json:"improved,omitempty"`
+}
+
+// CheckDelta is the change in one check's outcome since the baseline
+type CheckDelta struct {
+	Check string `json:"check"`
+	// Baseline and Current are pass or fail, empty if the check did not run
+	Baseline string `json:"baseline,omitempty"`
+	Current  string `json:"current,omitempty"`
+	// Change is unchanged, improved, regressed, added, or removed
+	Change string `json:"change"`
+}
+
+// Drifted reports whether the score or the outcome of any check changed
+// since the baseline. A nil delta, without a baseline, has not drifted.
+func (d *BaselineDelta) Drifted() bool {
+	if d == nil {
+		return false
+	}
+	if d.Score != 0 {
+		return true
+	}
+	for _, c := range d.Checks {
+		if c.Change != DeltaUnchanged {
+			return true
+		}
+	}
+	return false
+}
+
+// BaselinePath returns the baseline file: OMNITRUST_BASELINE, or else
+// baseline.json next to the per-user config file
+func BaselinePath() string {
+	if path := os.Getenv(BaselineEnv); path != "" {
+		return path
+	}
+	return userConfigFile("baseline.json")
+}
+
+// userConfigFile returns the path of a file next to the per-user config
+// file, or "" when there is no home directory
+func userConfigFile(name string) string {
+	if runtime.GOOS == "windows" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return ""
+		}
+		return filepath.Join(dir, "omnitrust", name)
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "omnitrust", name)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "omnitrust", name)
+}
+
+// NewBaseline records a summary's score and check outcomes as a baseline
+func NewBaseline(summary *SecuritySummary) *Baseline {
+	b := &Baseline{
+		CreatedAt:    time.Now().UTC(),
+		Hostname:     summary.Hostname,
+		Platform:     summary.Platform,
+		OverallScore: summary.OverallScore,
+		Checks:       make(map[string]string, len(summary.CheckResults)),
+	}
+	for id, outcome := range summary.CheckResults {
+		if outcome != CheckResultTimeout {
+			b.Checks[id] = outcome
+		}
+	}
+	return b
+}
+
+// ParseBaseline decodes and validates an exported baseline
+func ParseBaseline(data []byte) (*Baseline, error) {
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("invalid baseline: %w", err)
+	}
+	if err := CheckSchemaVersion(b.SchemaVersion); err != nil {
+		return nil, fmt.Errorf("baseline: %w", err)
+	}
+	if b.Checks == nil || b.CreatedAt.IsZero() {
+		return nil, errors.New("invalid baseline: created_at and checks are required")
+	}
+	for id, outcome := range b.Checks {
+		if outcome != CheckResultPass && outcome != CheckResultFail {
+			return nil, fmt.Errorf("invalid baseline: check %s is %q, want pass or fail", id, outcome)
+		}
+	}
+	return &b, nil
+}
+
+// LoadBaseline reads the baseline at path. A missing file is not an error
+// and yields nil.
+func LoadBaseline(path string) (*Baseline, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- configured baseline path
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseBaseline(data)
+}
+
+// SaveBaseline writes a baseline to path, creating its directory
+func SaveBaseline(path string, b *Baseline) error {
+	if path == "" {
+		return errors.New("no baseline path: set " + BaselineEnv)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// CompareBaseline reports how a summary differs from a baseline, check by
+// check and in overall score
+func CompareBaseline(b *Baseline, summary *SecuritySummary) *BaselineDelta {
+	delta := &BaselineDelta{
+		BaselineCreatedAt: b.CreatedAt,
+		BaselineScore:     b.OverallScore,
+		Checks:            []CheckDelta{},
+	}
+	// A score is only comparable to another score
+	if summary.OverallScore != NoScore && b.OverallScore != NoScore {
+		delta.Score = summary.OverallScore - b.OverallScore
+	}
+	var ids []string
+	for id := range b.Checks {
+		ids = append(ids, id)
+	}
+	for id := range summary.CheckResults {
+		if _, ok := b.Checks[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.SortFunc(ids, compareCheckOrder)
+
+	for _, id := range ids {
+		d := CheckDelta{Check: id, Baseline: b.Checks[id], Current: summary.CheckResults[id]}
+		switch {
+		case d.Current == CheckResultTimeout:
+			d.Change = DeltaUnverified
+		case d.Baseline == "":
+			d.Change = DeltaAdded
+		case d.Current == "":
+			d.Change = DeltaRemoved
+		case d.Baseline == d.Current:
+			d.Change = DeltaUnchanged
+		case d.Current == CheckResultFail:
+			d.Change = DeltaRegressed
+			delta.Regressed = append(delta.Regressed, id)
+		default:
+			d.Change = DeltaImproved
+			delta.Improved = append(delta.Improved, id)
+		}
+		delta.Checks = append(delta.Checks, d)
+	}
+	return delta
+}
+
+// compareCheckOrder sorts check IDs in summary order, unknown IDs last
+func compareCheckOrder(a, b string) int {
+	ia, ib := slices.Index(AllChecks, a), slices.Index(AllChecks, b)
+	switch {
+	case ia < 0 && ib < 0:
+		return strings.Compare(a, b)
+	case ia < 0:
+		return 1
+	case ib < 0:
+		return -1
+	}
+	return ia - ib
+}
+
+// checkResults records the outcome of every check that ran and counted
+func checkResults(passed map[string]bool, notApplicable map[string]string) map[string]string {
+	results := make(map[string]string, len(passed))
+	for id, ok := range passed {
+		if _, na := notApplicable[id]; na {
+			continue
+		}
+		results[id] = CheckResultFail
+		if ok {
+			results[id] = CheckResultPass
+		}
+	}
+	return results
+}
+
+// formatBaselineDelta renders the score change and the checks that changed
+// for the summary table
+func formatBaselineDelta(st Styler, d *BaselineDelta) string {
+	var sb strings.Builder
+	sb.WriteString(st.BoldText(T("Since Baseline:") + " "))
+	change := fmt.Sprintf("%+d", d.Score)
+	switch {
+	case d.Score > 0:
+		change = st.Success(change)
+	case d.Score < 0:
+		change = st.Danger(change)
+	default:
+		change = st.Muted(change)
+	}
+	sb.WriteString(change)
+	baseline := strconv.Itoa(d.BaselineScore)
+	if d.BaselineScore == NoScore {
+		baseline = T("N/A")
+	}
+	sb.WriteString(st.Muted(" " + T("(baseline %s, %s)", baseline, d.BaselineCreatedAt.Local().Format("2006-01-02"))))
+	sb.WriteString("\n")
+	if len(d.Regressed) > 0 {
+		sb.WriteString("  " + st.Danger(IconCross+" "+T("Regressed:")) + " " + strings.Join(d.Regressed, ", "))
+		sb.WriteString("\n")
+	}
+	if len(d.Improved) > 0 {
+		sb.WriteString("  " + st.Success(IconCheck+" "+T("Improved:")) + " " + strings.Join(d.Improved, ", "))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// FormatBaselineTable formats a baseline as a colored table
+func FormatBaselineTable(b *Baseline) string {
+	return FormatBaselineTableWith(CurrentStyler(), b)
+}
+
+// FormatBaselineTableWith is like FormatBaselineTable but styles the table
+// with st
+func FormatBaselineTableWith(st Styler, b *Baseline) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " Security Baseline"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(st.BoldText("Created: "))
+	sb.WriteString(b.CreatedAt.Local().Format(time.RFC3339))
+	if b.Hostname != "" {
+		sb.WriteString(st.Muted(" on " + b.Hostname))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(st.BoldText("Score: "))
+	sb.WriteString(FormatScore(b.OverallScore))
+	sb.WriteString("\n\n")
+
+	ids := make([]string, 0, len(b.Checks))
+	for id := range b.Checks {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, compareCheckOrder)
+	sb.WriteString(st.TableTop(24, 10))
+	sb.WriteString("\n")
+	for _, id := range ids {
+		outcome := st.Success(IconCheck + " pass")
+		if b.Checks[id] == CheckResultFail {
+			outcome = st.Danger(IconCross + " fail")
+		}
+		sb.WriteString(st.TableRowColored(PadRight(id, 24), PadRight(outcome, 10)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(st.TableBottom(24, 10))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// FormatBaseline formats a baseline in the specified format
+func FormatBaseline(b *Baseline, format string) string {
+	return FormatOutputWith(b, func(st Styler) string {
+		return FormatBaselineTableWith(st, b)
+	}, format)
+}
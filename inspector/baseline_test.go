@@ -0,0 +1,107 @@
+package inspector
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCompareBaseline(t *testing.T) {
+	baseline := NewBaseline(&SecuritySummary{
+		Platform:     "linux",
+		OverallScore: 80,
+		CheckResults: map[string]string{CheckTPM: CheckResultPass, CheckEncryption: CheckResultFail, CheckSecureBoot: CheckResultPass, CheckDocker: CheckResultPass},
+	})
+	current := &SecuritySummary{
+		OverallScore: 75,
+		CheckResults: map[string]string{CheckTPM: CheckResultPass, CheckEncryption: CheckResultPass, CheckSecureBoot: CheckResultFail, CheckFirmware: CheckResultPass},
+	}
+	delta := CompareBaseline(baseline, current)
+	if delta.Score != -5 || delta.BaselineScore != 80 {
+		t.Errorf("score delta = %d from %d, want -5 from 80", delta.Score, delta.BaselineScore)
+	}
+	if !slices.Equal(delta.Regressed, []string{CheckSecureBoot}) || !slices.Equal(delta.Improved, []string{CheckEncryption}) {
+		t.Errorf("regressed = %v, improved = %v", delta.Regressed, delta.Improved)
+	}
+	var changes []string
+	for _, c := range delta.Checks {
+		changes = append(changes, c.Check+"="+c.Change)
+	}
+	want := []string{"tpm=unchanged", "secure_boot=regressed", "encryption=improved", "docker=removed", "firmware=added"}
+	if !slices.Equal(changes, want) {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+}
+
+func TestBaselineDelta_Drifted(t *testing.T) {
+	unchanged := []CheckDelta{{Check: CheckTPM, Change: DeltaUnchanged}}
+	tests := []struct {
+		delta *BaselineDelta
+		want  bool
+	}{
+		{nil, false},
+		{&BaselineDelta{Checks: unchanged}, false},
+		{&BaselineDelta{Score: -5, Checks: unchanged}, true},
+		{&BaselineDelta{Checks: append(unchanged, CheckDelta{Check: CheckFirmware, Change: DeltaAdded})}, true},
+	}
+	for i, tt := range tests {
+		if got := tt.delta.Drifted(); got != tt.want {
+			t.Errorf("case %d: Drifted = %v, want %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestCheckResults(t *testing.T) {
+	passed := map[string]bool{CheckTPM: true, CheckEncryption: false, CheckUptime: false}
+	results := checkResults(passed, map[string]string{CheckUptime: StatusNotApplicableInContainer})
+	if len(results) != 2 || results[CheckTPM] != CheckResultPass || results[CheckEncryption] != CheckResultFail {
+		t.Errorf("checkResults = %v", results)
+	}
+}
+
+func TestBaselineRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "omnitrust", "baseline.json")
+	t.Setenv(BaselineEnv, path)
+	if BaselinePath() != path {
+		t.Fatalf("BaselinePath = %q, want %q", BaselinePath(), path)
+	}
+	if b, err := LoadBaseline(path); b != nil || err != nil {
+		t.Errorf("a missing baseline should load as nil, got %+v, %v", b, err)
+	}
+
+	saved := NewBaseline(&SecuritySummary{Hostname: "web-1", Platform: "linux", OverallScore: 90, CheckResults: map[string]string{CheckTPM: CheckResultPass}})
+	if err := SaveBaseline(path, saved); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadBaseline(path)
+	if err != nil || loaded.OverallScore != 90 || loaded.Hostname != "web-1" || loaded.Checks[CheckTPM] != CheckResultPass || !loaded.CreatedAt.Equal(saved.CreatedAt) {
+		t.Errorf("LoadBaseline = %+v, %v", loaded, err)
+	}
+}
+
+func TestParseBaseline_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not json":      `{`,
+		"major version": `{"schema_version":"1.0","created_at":"2026-01-01T00:00:00Z","checks":{}}`,
+		"no checks":     `{"schema_version":"2.0","created_at":"2026-01-01T00:00:00Z"}`,
+		"bad outcome":   `{"schema_version":"2.0","created_at":"2026-01-01T00:00:00Z","checks":{"tpm":"maybe"}}`,
+	}
+	for name, data := range tests {
+		if _, err := ParseBaseline([]byte(data)); err == nil {
+			t.Errorf("%s: ParseBaseline should fail", name)
+		}
+	}
+	if _, err := ParseBaseline([]byte(`{"schema_version":"2.3","created_at":"2026-01-01T00:00:00Z","checks":{"tpm":"pass"}}`)); err != nil {
+		t.Errorf("ParseBaseline of an older 2.x baseline = %v", err)
+	}
+}
+
+func TestFormatSecuritySummaryTable_Baseline(t *testing.T) {
+	summary := &SecuritySummary{Platform: "linux", OverallScore: 70, OverallStatus: "fair"}
+	summary.DeltaFromBaseline = &BaselineDelta{BaselineScore: 80, Score: -10, Regressed: []string{CheckSecureBoot}}
+	out := FormatSecuritySummaryTable(summary)
+	if !strings.Contains(out, "-10") || !strings.Contains(out, CheckSecureBoot) {
+		t.Errorf("table should show the score delta and regressed checks:\n%s", out)
+	}
+}
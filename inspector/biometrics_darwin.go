@@ -8,6 +8,7 @@ package inspector
 
 #import <Foundation/Foundation.h>
 #import <LocalAuthentication/LocalAuthentication.h>
+#include <string.h>
 
 // Biometric info structure
 typedef struct {
@@ -16,10 +17,13 @@ typedef struct {
     int faceIDAvailable;
     int faceIDEnrolled;
     int biometryType;
+    int errorCode;
+    char errorMessage[256];
+    int watchAvailable;
 } BiometricInfo;
 
 BiometricInfo getBiometricInfo() {
-    BiometricInfo info = {0, 0, 0, 0, 0};
+    BiometricInfo info = {0};
 
     LAContext *context = [[LAContext alloc] init];
     NSError *error = nil;
@@ -37,6 +41,8 @@ BiometricInfo getBiometricInfo() {
         }
     } else if (error) {
         info.biometryType = (int)context.biometryType;
+        info.errorCode = (int)error.code;
+        strlcpy(info.errorMessage, [error.localizedDescription UTF8String], sizeof(info.errorMessage));
         if (error.code == LAErrorBiometryNotEnrolled) {
             if (context.biometryType == LABiometryTypeTouchID) {
                 info.touchIDAvailable = 1;
@@ -46,25 +52,36 @@ BiometricInfo getBiometricInfo() {
         }
     }
 
+    // A paired Apple Watch with unlock turned on satisfies the watch policy
+    if (@available(macOS 10.15, *)) {
+        LAContext *watchContext = [[LAContext alloc] init];
+        info.watchAvailable = [watchContext canEvaluatePolicy:LAPolicyDeviceOwnerAuthenticationWithWatch error:nil] ? 1 : 0;
+    }
+
     return info;
 }
 */
 import "C"
 import (
-	"strings"
+	"context"
+	"os"
+	"strconv"
 )
 
 // BiometricCapabilities contains detailed biometric capability information
-type BiometricCapabilities struct {
-	TouchIDAvailable bool   `json:"touch_id_available"`
-	TouchIDEnrolled  bool   `json:"touch_id_enrolled"`
-	FaceIDAvailable  bool   `json:"face_id_available"`
-	FaceIDEnrolled   bool   `json:"face_id_enrolled"`
-	BiometryType     string `json:"biometry_type"`
-}
+type BiometricCapabilities = DarwinBiometricCapabilities
 
 // GetBiometricCapabilities returns detailed biometric capabilities (macOS only)
 func GetBiometricCapabilities() (*BiometricCapabilities, error) {
+	return GetBiometricCapabilitiesContext(context.Background())
+}
+
+// GetBiometricCapabilitiesContext is like GetBiometricCapabilities but runs
+// the probes with ctx's deadline, command runner, and logger
+func GetBiometricCapabilitiesContext(ctx context.Context) (*BiometricCapabilities, error) {
+	if result, ok, err := loadFixture[BiometricCapabilities](ctx, "biometrics"); ok {
+		return result, err
+	}
 	bioInfo := C.getBiometricInfo()
 
 	biometryType := "none"
@@ -75,74 +92,81 @@ func GetBiometricCapabilities() (*BiometricCapabilities, error) {
 		biometryType = "face_id"
 	}
 
-	return &BiometricCapabilities{
-		TouchIDAvailable: bioInfo.touchIDAvailable == 1,
-		TouchIDEnrolled:  bioInfo.touchIDEnrolled == 1,
-		FaceIDAvailable:  bioInfo.faceIDAvailable == 1,
-		FaceIDEnrolled:   bioInfo.faceIDEnrolled == 1,
-		BiometryType:     biometryType,
-	}, nil
+	result := &BiometricCapabilities{
+		TouchIDAvailable:     bioInfo.touchIDAvailable == 1,
+		TouchIDEnrolled:      bioInfo.touchIDEnrolled == 1,
+		FaceIDAvailable:      bioInfo.faceIDAvailable == 1,
+		FaceIDEnrolled:       bioInfo.faceIDEnrolled == 1,
+		BiometryType:         biometryType,
+		WatchUnlockAvailable: bioInfo.watchAvailable == 1,
+		WatchUnlockAllowed:   true,
+	}
+	if bioInfo.errorCode != 0 {
+		result.PolicyError = newLAPolicyError(int(bioInfo.errorCode), C.GoString(&bioInfo.errorMessage[0]))
+	}
+
+	if _, err := os.Stat(applicationAccessPrefs); err == nil {
+		if out, err := runCommand(ctx, "plutil", "-convert", "xml1", "-o", "-", applicationAccessPrefs); err == nil {
+			result.WatchUnlockAllowed = parseAutoUnlockAllowed(out)
+		}
+	}
+	result.SudoPAMFile = sudoTouchIDConfig(os.DirFS("/"))
+	result.SudoTouchID = result.SudoPAMFile != ""
+
+	return result, nil
+}
+
+// enumerateUserBiometrics reports Touch ID enrollment for every local
+// account from `bioutil -c -s`, which lists all users' templates to root
+func enumerateUserBiometrics(ctx context.Context) []UserBiometrics {
+	out, err := runCommand(ctx, "dscl", ".", "-list", "/Users", "UniqueID")
+	if err != nil {
+		loggerFrom(ctx).Warn("cannot list local accounts", "err", err)
+		return nil
+	}
+
+	var counts map[int]int
+	var countErr *ProbeError
+	if bio, err := runCommand(ctx, "bioutil", "-c", "-s"); err == nil {
+		counts = parseBioutilCounts(bio)
+	} else {
+		countErr = classifyExecError(ctx, "bioutil", err)
+	}
+	elevated := IsElevated()
+
+	var users []UserBiometrics
+	for _, u := range parseDsclUsers(out) {
+		ub := UserBiometrics{Username: u.Name, ID: strconv.Itoa(u.UID)}
+		n, listed := counts[u.UID]
+		switch {
+		case countErr != nil:
+			ub.Error = countErr
+		case !listed && !elevated && u.UID != os.Getuid():
+			ub.Error = newProbeError(ctx, ErrPermissionDenied, "bioutil",
+				"reading other users' Touch ID enrollment requires root")
+		default:
+			ub.FingerprintEnrolled = n > 0
+		}
+		users = append(users, ub)
+	}
+	return users
 }
 
 // FormatBiometricCapabilitiesTable formats biometric capabilities as a colored table
 func FormatBiometricCapabilitiesTable(result *BiometricCapabilities) string {
-	var sb strings.Builder
-	sb.WriteString("\n")
-	sb.WriteString(Header(IconFingerprint + " Biometric Capabilities"))
-	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 50)))
-	sb.WriteString("\n\n")
-
-	// Active biometry type
-	sb.WriteString(BoldText("Active Biometry: "))
-	switch result.BiometryType {
-	case "touch_id":
-		sb.WriteString(Success(IconFingerprint + " Touch ID"))
-	case "face_id":
-		sb.WriteString(Success(IconFace + " Face ID"))
-	default:
-		sb.WriteString(Muted("None"))
-	}
-	sb.WriteString("\n\n")
-
-	// Capabilities table
-	sb.WriteString(TableTop(14, 14, 14))
-	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Biometric", 14)),
-		Header(PadRight("Available", 14)),
-		Header(PadRight("Enrolled", 14)),
-	))
-	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(14, 14, 14))
-	sb.WriteString("\n")
-
-	// Touch ID row
-	sb.WriteString(TableRowColored(
-		PadRight(IconFingerprint+" Touch ID", 14),
-		PadRight(BoolToStatusColored(result.TouchIDAvailable), 14),
-		PadRight(BoolToStatusColored(result.TouchIDEnrolled), 14),
-	))
-	sb.WriteString("\n")
-
-	// Face ID row
-	sb.WriteString(TableRowColored(
-		PadRight(IconFace+" Face ID", 14),
-		PadRight(BoolToStatusColored(result.FaceIDAvailable), 14),
-		PadRight(BoolToStatusColored(result.FaceIDEnrolled), 14),
-	))
-	sb.WriteString("\n")
-
-	sb.WriteString(TableBottom(14, 14, 14))
-	sb.WriteString("\n")
-
-	return sb.String()
+	return FormatBiometricCapabilitiesTableWith(CurrentStyler(), result)
+}
+
+// FormatBiometricCapabilitiesTableWith is like
+// FormatBiometricCapabilitiesTable but styles the table with st
+func FormatBiometricCapabilitiesTableWith(st Styler, result *BiometricCapabilities) string {
+	return formatDarwinBiometricsTable(st, result)
 }
 
 // FormatBiometricCapabilities formats biometric capabilities in the specified format
 func FormatBiometricCapabilities(result *BiometricCapabilities, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatBiometricCapabilitiesTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatBiometricCapabilitiesTableWith(st, result)
 	}, format)
 }
 
@@ -3,60 +3,115 @@
 package inspector
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"os"
-	"os/exec"
-	"strings"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/godbus/dbus/v5"
 )
 
-// BiometricCapabilities contains detailed biometric capability information
-type BiometricCapabilities struct {
-	TouchIDAvailable bool   `json:"touch_id_available"`
-	TouchIDEnrolled  bool   `json:"touch_id_enrolled"`
-	FaceIDAvailable  bool   `json:"face_id_available"`
-	FaceIDEnrolled   bool   `json:"face_id_enrolled"`
-	BiometryType     string `json:"biometry_type"`
-	// Linux-specific fields
-	FprintdAvailable bool   `json:"fprintd_available,omitempty"`
-	FprintdEnrolled  bool   `json:"fprintd_enrolled,omitempty"`
-	HowdyAvailable   bool   `json:"howdy_available,omitempty"`
-	HowdyConfigured  bool   `json:"howdy_configured,omitempty"`
-	Platform         string `json:"platform"`
+// fprintd D-Bus service
+const (
+	fprintdService     = "net.reactivated.Fprint"
+	fprintdManagerPath = "/net/reactivated/Fprint/Manager"
+)
+
+// fprintdTimeout bounds the D-Bus calls for one user, so that a hung
+// fprintd cannot stall the biometrics check
+const fprintdTimeout = 3 * time.Second
+
+// pamDir holds the PAM service configuration
+var pamDir = "/etc/pam.d"
+
+// Local account databases, read when enumerating all users
+var (
+	passwdPath    = "/etc/passwd"
+	loginDefsPath = "/etc/login.defs"
+)
+
+// howdyDirs are the install locations of Howdy's config.ini and models/
+var howdyDirs = []string{
+	"/etc/howdy",
+	"/lib/security/howdy",
+	"/usr/lib/security/howdy",
+	"/usr/lib64/security/howdy",
+	"/usr/local/lib/security/howdy",
+	"/var/lib/howdy",
 }
 
+// BiometricCapabilities contains detailed biometric capability information
+type BiometricCapabilities = LinuxBiometricCapabilities
+
+// fprintdDevices lists fprintd's devices and the user's enrolled fingers.
+// It returns errFprintdUnavailable when fprintd is not installed. Replaced
+// in tests.
+var fprintdDevices = dbusFprintdDevices
+
+// errFprintdUnavailable means fprintd is not installed or not activatable
+var errFprintdUnavailable = errors.New("fprintd is not available")
+
 // GetBiometricCapabilities returns biometric capabilities (Linux)
 func GetBiometricCapabilities() (*BiometricCapabilities, error) {
+	return GetBiometricCapabilitiesContext(context.Background())
+}
+
+// GetBiometricCapabilitiesContext is like GetBiometricCapabilities but runs
+// the probes with ctx's deadline, command runner, and logger
+func GetBiometricCapabilitiesContext(ctx context.Context) (*BiometricCapabilities, error) {
+	if result, ok, err := loadFixture[BiometricCapabilities](ctx, "biometrics"); ok {
+		return result, err
+	}
 	result := &BiometricCapabilities{
 		Platform:     "linux",
 		BiometryType: "none",
 	}
+	username := currentUsername()
 
-	// Check for fprintd (fingerprint daemon)
-	if _, err := exec.LookPath("fprintd-list"); err == nil {
+	// Fingerprint readers and enrollments from fprintd
+	devices, err := fprintdDevices(ctx, username)
+	switch {
+	case err == nil:
 		result.FprintdAvailable = true
-		result.TouchIDAvailable = true
-
-		// Check if fingerprints are enrolled
-		// #nosec G204 -- USER env var is trusted system input for current user
-		out, err := exec.Command("fprintd-list", os.Getenv("USER")).Output()
-		if err == nil && strings.Contains(string(out), "fingerprint") {
-			result.FprintdEnrolled = true
-			result.TouchIDEnrolled = true
+		result.TouchIDAvailable = len(devices) > 0
+		result.FprintdDevices = devices
+		for _, d := range devices {
+			if len(d.EnrolledFingers) > 0 {
+				result.FprintdEnrolled = true
+				result.TouchIDEnrolled = true
+			}
 		}
+	case !errors.Is(err, errFprintdUnavailable):
+		result.FprintdAvailable = true
+		result.Error = classifyFprintdError(ctx, err)
 	}
 
-	// Check for Howdy (face recognition for Linux)
-	if _, err := exec.LookPath("howdy"); err == nil {
+	// Howdy (face recognition for Linux) keeps a model file per user
+	if dir, ok := howdyInstallDir(); ok {
 		result.HowdyAvailable = true
 		result.FaceIDAvailable = true
 
-		// Check if face is configured
-		out, err := exec.Command("howdy", "list").Output()
-		if err == nil && !strings.Contains(string(out), "No face models") {
+		configured, err := howdyModelsConfigured(dir, username)
+		if err != nil && errors.Is(err, os.ErrPermission) && result.Error == nil {
+			result.Error = newProbeError(ctx, ErrPermissionDenied, "howdy", "insufficient privileges to read Howdy face models")
+		}
+		if configured {
 			result.HowdyConfigured = true
 			result.FaceIDEnrolled = true
 		}
 	}
 
+	// Installed is not the same as used: check whether PAM asks for biometrics
+	result.PAMRules = inspectPAM(os.DirFS(pamDir))
+	for _, r := range result.PAMRules {
+		result.PAMEnabled = true
+		result.BiometricAuthRequired = result.BiometricAuthRequired || r.Required
+	}
+
 	// Determine biometry type
 	if result.FprintdAvailable && result.HowdyAvailable {
 		result.BiometryType = "fingerprint_and_face"
@@ -69,71 +124,159 @@ func GetBiometricCapabilities() (*BiometricCapabilities, error) {
 	return result, nil
 }
 
+// enumerateUserBiometrics reports fprintd and Howdy enrollment for every
+// interactive local account. fprintd only lists other users' fingers to root.
+func enumerateUserBiometrics(ctx context.Context) []UserBiometrics {
+	data, err := readFile(passwdPath)
+	if err != nil {
+		loggerFrom(ctx).Warn("cannot list local accounts", "path", passwdPath, "err", err)
+		return nil
+	}
+	uidMin := 1000
+	if defs, err := readFile(loginDefsPath); err == nil {
+		uidMin = parseLoginDefsUIDMin(defs)
+	}
+	howdyDir, howdyInstalled := howdyInstallDir()
+
+	var users []UserBiometrics
+	for _, u := range parsePasswd(data, uidMin) {
+		ub := UserBiometrics{Username: u.Name, ID: strconv.Itoa(u.UID)}
+
+		devices, err := fprintdDevices(ctx, u.Name)
+		switch {
+		case err == nil:
+			for _, d := range devices {
+				ub.Fingers = append(ub.Fingers, d.EnrolledFingers...)
+			}
+			ub.FingerprintEnrolled = len(ub.Fingers) > 0
+		case !errors.Is(err, errFprintdUnavailable):
+			ub.Error = classifyFprintdError(ctx, err)
+		}
+
+		if howdyInstalled {
+			configured, err := howdyModelsConfigured(howdyDir, u.Name)
+			ub.FaceEnrolled = configured
+			if err != nil && errors.Is(err, os.ErrPermission) && ub.Error == nil {
+				ub.Error = newProbeError(ctx, ErrPermissionDenied, "howdy", "insufficient privileges to read Howdy face models")
+			}
+		}
+		users = append(users, ub)
+	}
+	return users
+}
+
+// currentUsername returns the login name of the user running the process.
+// Replaced in tests.
+var currentUsername = func() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// dbusFprintdDevices queries fprintd on the system bus
+func dbusFprintdDevices(ctx context.Context, username string) ([]FprintdDevice, error) {
+	ctx, cancel := context.WithTimeout(ctx, fprintdTimeout)
+	defer cancel()
+	conn, err := dbus.ConnectSystemBus(dbus.WithContext(ctx))
+	if err != nil {
+		return nil, errFprintdUnavailable
+	}
+	defer conn.Close()
+
+	var paths []dbus.ObjectPath
+	manager := conn.Object(fprintdService, fprintdManagerPath)
+	if err := manager.CallWithContext(ctx, fprintdService+".Manager.GetDevices", 0).Store(&paths); err != nil {
+		var dbusErr dbus.Error
+		if errors.As(err, &dbusErr) && (dbusErr.Name == "org.freedesktop.DBus.Error.ServiceUnknown" ||
+			dbusErr.Name == "org.freedesktop.DBus.Error.NameHasNoOwner") {
+			return nil, errFprintdUnavailable
+		}
+		return nil, err
+	}
+
+	devices := make([]FprintdDevice, 0, len(paths))
+	for _, p := range paths {
+		obj := conn.Object(fprintdService, p)
+		var d FprintdDevice
+		var v dbus.Variant
+		if err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, fprintdService+".Device", "name").Store(&v); err == nil {
+			d.Name, _ = v.Value().(string)
+		}
+		if err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, fprintdService+".Device", "scan-type").Store(&v); err == nil {
+			d.ScanType, _ = v.Value().(string)
+		}
+
+		err := obj.CallWithContext(ctx, fprintdService+".Device.ListEnrolledFingers", 0, username).Store(&d.EnrolledFingers)
+		var dbusErr dbus.Error
+		if err != nil && !(errors.As(err, &dbusErr) && dbusErr.Name == fprintdService+".Error.NoEnrolledPrints") {
+			return devices, err
+		}
+		devices = append(devices, d)
+	}
+	return devices, nil
+}
+
+// classifyFprintdError turns an fprintd D-Bus error into a ProbeError
+func classifyFprintdError(ctx context.Context, err error) *ProbeError {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return newProbeError(ctx, ErrTimeout, "fprintd", "fprintd did not answer within "+fprintdTimeout.String())
+	}
+	var dbusErr dbus.Error
+	if errors.As(err, &dbusErr) && (dbusErr.Name == fprintdService+".Error.PermissionDenied" ||
+		dbusErr.Name == "org.freedesktop.DBus.Error.AccessDenied") {
+		return newProbeError(ctx, ErrPermissionDenied, "fprintd", "fprintd denied access to enrolled fingerprints")
+	}
+	return newProbeError(ctx, ErrProbeFailed, "fprintd", err.Error())
+}
+
+// howdyInstallDir returns the directory holding Howdy's config.ini
+func howdyInstallDir() (string, bool) {
+	for _, dir := range howdyDirs {
+		if _, err := os.Stat(filepath.Join(dir, "config.ini")); err == nil {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+// howdyModelsConfigured reports whether the user has at least one Howdy face
+// model. Models live in models/<user>.dat next to the config or in any other
+// Howdy directory, as a JSON list.
+func howdyModelsConfigured(installDir, username string) (bool, error) {
+	var lastErr error
+	for _, dir := range append([]string{installDir}, howdyDirs...) {
+		data, err := readFile(filepath.Join(dir, "models", username+".dat"))
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				lastErr = err
+			}
+			continue
+		}
+		var models []json.RawMessage
+		if err := json.Unmarshal(data, &models); err != nil {
+			return false, err
+		}
+		return len(models) > 0, nil
+	}
+	return false, lastErr
+}
+
 // FormatBiometricCapabilitiesTable formats biometric capabilities as a colored table
 func FormatBiometricCapabilitiesTable(result *BiometricCapabilities) string {
-	var sb strings.Builder
-	sb.WriteString("\n")
-	sb.WriteString(Header(IconFingerprint + " Biometric Capabilities"))
-	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 55)))
-	sb.WriteString("\n\n")
-
-	// Platform badge
-	sb.WriteString(BoldText("Platform: "))
-	sb.WriteString(Info(IconChip + " Linux"))
-	sb.WriteString("\n\n")
-
-	// Check if any biometrics available
-	if !result.FprintdAvailable && !result.HowdyAvailable {
-		sb.WriteString(Muted("No biometric authentication services detected."))
-		sb.WriteString("\n")
-		sb.WriteString(Muted("Consider installing:"))
-		sb.WriteString("\n")
-		sb.WriteString(Muted("  - fprintd: for fingerprint authentication"))
-		sb.WriteString("\n")
-		sb.WriteString(Muted("  - howdy: for facial recognition"))
-		sb.WriteString("\n")
-		return sb.String()
-	}
-
-	// Capabilities table
-	sb.WriteString(TableTop(20, 14, 14))
-	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Service", 20)),
-		Header(PadRight("Available", 14)),
-		Header(PadRight("Configured", 14)),
-	))
-	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(20, 14, 14))
-	sb.WriteString("\n")
-
-	// fprintd row
-	sb.WriteString(TableRowColored(
-		PadRight(IconFingerprint+" fprintd", 20),
-		PadRight(BoolToStatusColored(result.FprintdAvailable), 14),
-		PadRight(BoolToStatusColored(result.FprintdEnrolled), 14),
-	))
-	sb.WriteString("\n")
-
-	// Howdy row
-	sb.WriteString(TableRowColored(
-		PadRight(IconFace+" Howdy", 20),
-		PadRight(BoolToStatusColored(result.HowdyAvailable), 14),
-		PadRight(BoolToStatusColored(result.HowdyConfigured), 14),
-	))
-	sb.WriteString("\n")
-
-	sb.WriteString(TableBottom(20, 14, 14))
-	sb.WriteString("\n")
-
-	return sb.String()
+	return FormatBiometricCapabilitiesTableWith(CurrentStyler(), result)
+}
+
+// FormatBiometricCapabilitiesTableWith is like
+// FormatBiometricCapabilitiesTable but styles the table with st
+func FormatBiometricCapabilitiesTableWith(st Styler, result *BiometricCapabilities) string {
+	return formatLinuxBiometricsTable(st, result)
 }
 
 // FormatBiometricCapabilities formats biometric capabilities in the specified format
 func FormatBiometricCapabilities(result *BiometricCapabilities, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatBiometricCapabilitiesTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatBiometricCapabilitiesTableWith(st, result)
 	}, format)
 }
 
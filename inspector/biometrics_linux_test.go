@@ -0,0 +1,127 @@
+//go:build linux
+
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// stubBiometrics points the Linux biometric probe at test fixtures
+func stubBiometrics(t *testing.T, devices []FprintdDevice, fprintdErr error, howdy bool) {
+	t.Helper()
+	prevDevices, prevHowdy, prevPAM := fprintdDevices, howdyDirs, pamDir
+	t.Cleanup(func() { fprintdDevices, howdyDirs, pamDir = prevDevices, prevHowdy, prevPAM })
+
+	fprintdDevices = func(context.Context, string) ([]FprintdDevice, error) { return devices, fprintdErr }
+	howdyDirs = []string{t.TempDir()}
+	if howdy {
+		howdyDirs = []string{filepath.Join("testdata", "linux", "howdy")}
+	}
+	pamDir = filepath.Join("testdata", "linux", "pam.d")
+}
+
+func TestGetBiometricCapabilities_Fixtures(t *testing.T) {
+	reader := FprintdDevice{Name: "Synaptics Sensors", ScanType: "press"}
+	enrolled := reader
+	enrolled.EnrolledFingers = []string{"right-index-finger"}
+
+	tests := []struct {
+		name           string
+		user           string
+		devices        []FprintdDevice
+		fprintdErr     error
+		howdy          bool
+		wantType       string
+		wantFinger     bool
+		wantFace       bool
+		wantFprintdSet bool
+	}{
+		{"none installed", "alice", nil, errFprintdUnavailable, false, "none", false, false, false},
+		{"fingerprint enrolled", "alice", []FprintdDevice{enrolled}, nil, false, "fingerprint", true, false, true},
+		{"fingerprint not enrolled", "alice", []FprintdDevice{reader}, nil, false, "fingerprint", false, false, true},
+		{"both", "alice", []FprintdDevice{enrolled}, nil, true, "fingerprint_and_face", true, true, true},
+		{"face without models", "bob", nil, errFprintdUnavailable, true, "face", false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubBiometrics(t, tt.devices, tt.fprintdErr, tt.howdy)
+			prevUser := currentUsername
+			currentUsername = func() string { return tt.user }
+			defer func() { currentUsername = prevUser }()
+
+			result, err := GetBiometricCapabilitiesContext(context.Background())
+			if err != nil {
+				t.Fatalf("GetBiometricCapabilities failed: %v", err)
+			}
+			if result.BiometryType != tt.wantType {
+				t.Errorf("BiometryType = %q, want %q", result.BiometryType, tt.wantType)
+			}
+			if result.FprintdAvailable != tt.wantFprintdSet {
+				t.Errorf("FprintdAvailable = %v, want %v", result.FprintdAvailable, tt.wantFprintdSet)
+			}
+			if result.FprintdEnrolled != tt.wantFinger {
+				t.Errorf("FprintdEnrolled = %v, want %v", result.FprintdEnrolled, tt.wantFinger)
+			}
+			if result.HowdyConfigured != tt.wantFace {
+				t.Errorf("HowdyConfigured = %v, want %v", result.HowdyConfigured, tt.wantFace)
+			}
+			if !result.PAMEnabled || !result.BiometricAuthRequired {
+				t.Errorf("PAM = (%v, %v), want enabled and required by polkit-1", result.PAMEnabled, result.BiometricAuthRequired)
+			}
+		})
+	}
+}
+
+func TestGetBiometricCapabilities_FprintdError(t *testing.T) {
+	stubBiometrics(t, nil, errors.New("timeout"), false)
+
+	result, err := GetBiometricCapabilitiesContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetBiometricCapabilities failed: %v", err)
+	}
+	if !result.FprintdAvailable || result.Error == nil || result.Error.Code != CodeProbeFailed {
+		t.Errorf("result = (%v, %v), want fprintd available with probe_failed", result.FprintdAvailable, result.Error)
+	}
+}
+
+func TestEnumerateUserBiometrics(t *testing.T) {
+	stubBiometrics(t, nil, nil, true)
+	prevPasswd, prevDefs := passwdPath, loginDefsPath
+	t.Cleanup(func() { passwdPath, loginDefsPath = prevPasswd, prevDefs })
+	passwdPath = filepath.Join("testdata", "linux", "passwd")
+	loginDefsPath = filepath.Join(t.TempDir(), "missing")
+
+	// fprintd lets alice read her own fingers but refuses bob's
+	fprintdDevices = func(_ context.Context, user string) ([]FprintdDevice, error) {
+		if user == "alice" {
+			return []FprintdDevice{{Name: "Synaptics Sensors", EnrolledFingers: []string{"left-thumb"}}}, nil
+		}
+		return nil, dbus.Error{Name: fprintdService + ".Error.PermissionDenied"}
+	}
+
+	users := enumerateUserBiometrics(context.Background())
+	if len(users) != 2 {
+		t.Fatalf("len(users) = %d, want 2", len(users))
+	}
+	alice, bob := users[0], users[1]
+	if alice.Username != "alice" || alice.ID != "1000" || !alice.FingerprintEnrolled || !alice.FaceEnrolled || alice.Error != nil {
+		t.Errorf("alice = %+v", alice)
+	}
+	if bob.FaceEnrolled || bob.Error == nil || bob.Error.Code != CodePermissionDenied {
+		t.Errorf("bob = %+v, want no face model and permission_denied", bob)
+	}
+}
+
+func TestClassifyFprintdError_Timeout(t *testing.T) {
+	err := classifyFprintdError(context.Background(), fmt.Errorf("ListEnrolledFingers: %w", context.DeadlineExceeded))
+	if err.Code != CodeTimeout {
+		t.Errorf("code = %q, want %q", err.Code, CodeTimeout)
+	}
+}
@@ -0,0 +1,87 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"strings"
+)
+
+// applicationAccessPrefs holds the restrictions a configuration profile sets
+// on macOS, including allowAutoUnlock for Apple Watch unlock
+const applicationAccessPrefs = "/Library/Managed Preferences/com.apple.applicationaccess.plist"
+
+// pamSudoFiles are the PAM configurations sudo reads on macOS. sudo_local
+// (Sonoma and later) is included by sudo and survives OS updates.
+var pamSudoFiles = []string{"etc/pam.d/sudo_local", "etc/pam.d/sudo"}
+
+// laErrorNames maps LAError codes to their names in the LocalAuthentication
+// framework
+var laErrorNames = map[int]string{
+	-1:    "authenticationFailed",
+	-2:    "userCancel",
+	-3:    "userFallback",
+	-4:    "systemCancel",
+	-5:    "passcodeNotSet",
+	-6:    "biometryNotAvailable",
+	-7:    "biometryNotEnrolled",
+	-8:    "biometryLockout",
+	-9:    "appCancel",
+	-10:   "invalidContext",
+	-11:   "companionNotAvailable",
+	-12:   "biometryNotPaired",
+	-13:   "biometryDisconnected",
+	-14:   "invalidDimensions",
+	-1004: "notInteractive",
+}
+
+// LAPolicyError is why LocalAuthentication cannot evaluate a policy
+type LAPolicyError struct {
+	Code int `json:"code"`
+	// Name is the LAError name, e.g. biometryNotEnrolled
+	Name    string `json:"name"`
+	Message string `json:"message,omitempty"`
+}
+
+// newLAPolicyError describes an LAError code
+func newLAPolicyError(code int, message string) *LAPolicyError {
+	name, ok := laErrorNames[code]
+	if !ok {
+		name = "unknown"
+	}
+	return &LAPolicyError{Code: code, Name: name, Message: message}
+}
+
+// sudoTouchIDConfig returns the sudo PAM file that enables pam_tid.so for
+// authentication, or "" if biometrics cannot be used for sudo
+func sudoTouchIDConfig(fsys fs.FS) string {
+	for _, name := range pamSudoFiles {
+		data, err := readFSFile(fsys, name)
+		if err != nil {
+			continue
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 3 || strings.HasPrefix(fields[0], "#") || fields[0] != "auth" {
+				continue
+			}
+			if strings.HasSuffix(fields[2], "pam_tid.so") {
+				return "/" + name
+			}
+		}
+	}
+	return ""
+}
+
+// parseAutoUnlockAllowed reads allowAutoUnlock from a com.apple.applicationaccess
+// plist. Apple Watch unlock is allowed unless a profile sets it to false.
+func parseAutoUnlockAllowed(data []byte) bool {
+	v, err := decodePlist(data)
+	if err != nil {
+		return true
+	}
+	prefs, _ := v.(map[string]any)
+	allowed, ok := prefs["allowAutoUnlock"].(bool)
+	return !ok || allowed
+}
@@ -0,0 +1,48 @@
+package inspector
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestSudoTouchIDConfig(t *testing.T) {
+	sudo := "# sudo: auth account password session\nauth       include        sudo_local\nauth       sufficient     pam_smartcard.so\nauth       required       pam_opendirectory.so\n"
+	fsys := fstest.MapFS{
+		"etc/pam.d/sudo":       {Data: []byte(sudo)},
+		"etc/pam.d/sudo_local": {Data: []byte("# sudo_local: local config file which survives system update\n#auth       sufficient     pam_tid.so\n")},
+	}
+	if got := sudoTouchIDConfig(fsys); got != "" {
+		t.Errorf("a commented-out pam_tid.so should not count, got %q", got)
+	}
+
+	fsys["etc/pam.d/sudo_local"] = &fstest.MapFile{Data: []byte("auth       sufficient     pam_tid.so\n")}
+	if got := sudoTouchIDConfig(fsys); got != "/etc/pam.d/sudo_local" {
+		t.Errorf("sudoTouchIDConfig = %q, want /etc/pam.d/sudo_local", got)
+	}
+
+	fsys = fstest.MapFS{"etc/pam.d/sudo": {Data: []byte("auth sufficient /usr/lib/pam/pam_tid.so\n" + sudo)}}
+	if got := sudoTouchIDConfig(fsys); got != "/etc/pam.d/sudo" {
+		t.Errorf("sudoTouchIDConfig = %q, want /etc/pam.d/sudo", got)
+	}
+}
+
+func TestParseAutoUnlockAllowed(t *testing.T) {
+	plist := func(body string) []byte {
+		return []byte(`<?xml version="1.0" encoding="UTF-8"?><plist version="1.0"><dict>` + body + `</dict></plist>`)
+	}
+	if parseAutoUnlockAllowed(plist(`<key>allowAutoUnlock</key><false/>`)) {
+		t.Error("allowAutoUnlock false should disallow Apple Watch unlock")
+	}
+	if !parseAutoUnlockAllowed(plist(`<key>allowCamera</key><false/>`)) {
+		t.Error("a profile without allowAutoUnlock should allow Apple Watch unlock")
+	}
+}
+
+func TestNewLAPolicyError(t *testing.T) {
+	if e := newLAPolicyError(-7, "No identities are enrolled."); e.Name != "biometryNotEnrolled" {
+		t.Errorf("newLAPolicyError(-7) = %+v", e)
+	}
+	if e := newLAPolicyError(-99, ""); e.Name != "unknown" {
+		t.Errorf("newLAPolicyError(-99) = %+v", e)
+	}
+}
@@ -2,25 +2,47 @@
 
 package inspector
 
-import "errors"
-
 // BiometricCapabilities contains detailed biometric capability information
 type BiometricCapabilities struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
 	TouchIDAvailable bool   `json:"touch_id_available"`
 	TouchIDEnrolled  bool   `json:"touch_id_enrolled"`
 	FaceIDAvailable  bool   `json:"face_id_available"`
 	FaceIDEnrolled   bool   `json:"face_id_enrolled"`
 	BiometryType     string `json:"biometry_type"`
 	Platform         string `json:"platform"`
+
+	Users []UserBiometrics `json:"users,omitempty"`
 }
 
 // GetBiometricCapabilities returns an error on unsupported platforms
 func GetBiometricCapabilities() (*BiometricCapabilities, error) {
-	return nil, errors.New("biometric capabilities are not available on this platform")
+	return GetBiometricCapabilitiesContext(context.Background())
+}
+
+// GetBiometricCapabilitiesContext is like GetBiometricCapabilities but runs
+// the probes with ctx's deadline, command runner, and logger
+func GetBiometricCapabilitiesContext(ctx context.Context) (*BiometricCapabilities, error) {
+	if result, ok, err := loadFixture[BiometricCapabilities](ctx, "biometrics"); ok {
+		return result, err
+	}
+	return nil, newProbeError(ctx, ErrUnsupportedPlatform, "biometrics", "biometric capabilities are not available on this platform")
+}
+
+// enumerateUserBiometrics is not available on unsupported platforms
+func enumerateUserBiometrics(ctx context.Context) []UserBiometrics {
+	return nil
 }
 
 // FormatBiometricCapabilitiesTable is not available on unsupported platforms
 func FormatBiometricCapabilitiesTable(result *BiometricCapabilities) string {
+	return FormatBiometricCapabilitiesTableWith(CurrentStyler(), result)
+}
+
+// FormatBiometricCapabilitiesTableWith is like
+// FormatBiometricCapabilitiesTable but styles the table with st
+func FormatBiometricCapabilitiesTableWith(st Styler, result *BiometricCapabilities) string {
 	return "Biometric capabilities are not available on this platform"
 }
 
@@ -0,0 +1,382 @@
+package inspector
+
+import "strings"
+
+// DarwinBiometricCapabilities contains detailed biometric capability
+// information on macOS, where BiometricCapabilities is an alias for it
+type DarwinBiometricCapabilities struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	TouchIDAvailable bool   `json:"touch_id_available"`
+	TouchIDEnrolled  bool   `json:"touch_id_enrolled"`
+	FaceIDAvailable  bool   `json:"face_id_available"`
+	FaceIDEnrolled   bool   `json:"face_id_enrolled"`
+	BiometryType     string `json:"biometry_type"`
+	// PolicyError is why the biometrics policy cannot be evaluated, e.g.
+	// biometryNotEnrolled or biometryLockout
+	PolicyError *LAPolicyError `json:"policy_error,omitempty"`
+
+	// WatchUnlockAvailable is true if a paired Apple Watch can unlock the
+	// Mac, and WatchUnlockAllowed false if a configuration profile forbids it
+	WatchUnlockAvailable bool `json:"watch_unlock_available"`
+	WatchUnlockAllowed   bool `json:"watch_unlock_allowed"`
+	// SudoTouchID is true if pam_tid.so lets sudo authenticate with Touch
+	// ID; SudoPAMFile is the file that enables it
+	SudoTouchID bool   `json:"sudo_touch_id"`
+	SudoPAMFile string `json:"sudo_pam_file,omitempty"`
+
+	// Users lists every local user's enrollment (BiometricOptions.AllUsers)
+	Users []UserBiometrics `json:"users,omitempty"`
+}
+
+// formatDarwinBiometricsTable formats macOS biometric capabilities as a colored table
+func formatDarwinBiometricsTable(st Styler, result *DarwinBiometricCapabilities) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconFingerprint + " " + T("Biometric Capabilities")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	// Active biometry type
+	sb.WriteString(st.BoldText(T("Active Biometry:") + " "))
+	switch result.BiometryType {
+	case "touch_id":
+		sb.WriteString(st.Success(IconFingerprint + " Touch ID"))
+	case "face_id":
+		sb.WriteString(st.Success(IconFace + " Face ID"))
+	default:
+		sb.WriteString(st.Muted(T("None")))
+	}
+	sb.WriteString("\n\n")
+
+	// Capabilities table
+	sb.WriteString(st.TableTop(14, 14, 14))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Biometric"), 14)),
+		st.Header(PadRight(T("Available"), 14)),
+		st.Header(PadRight(T("Enrolled"), 14)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(14, 14, 14))
+	sb.WriteString("\n")
+
+	// Touch ID row
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconFingerprint+" Touch ID", 14),
+		PadRight(st.BoolToStatusColored(result.TouchIDAvailable), 14),
+		PadRight(st.BoolToStatusColored(result.TouchIDEnrolled), 14),
+	))
+	sb.WriteString("\n")
+
+	// Face ID row
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconFace+" Face ID", 14),
+		PadRight(st.BoolToStatusColored(result.FaceIDAvailable), 14),
+		PadRight(st.BoolToStatusColored(result.FaceIDEnrolled), 14),
+	))
+	sb.WriteString("\n")
+
+	sb.WriteString(st.TableBottom(14, 14, 14))
+	sb.WriteString("\n")
+
+	if result.PolicyError != nil {
+		sb.WriteString(st.Warning(IconWarning + T("Biometrics unavailable: %s", result.PolicyError.Name)))
+		if result.PolicyError.Message != "" {
+			sb.WriteString(st.Muted(" (" + result.PolicyError.Message + ")"))
+		}
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString(st.BoldText(T("Apple Watch Unlock:") + " "))
+	switch {
+	case !result.WatchUnlockAllowed:
+		sb.WriteString(st.Warning(T("Blocked by profile")))
+	case result.WatchUnlockAvailable:
+		sb.WriteString(st.Success(IconCheck + " " + T("Available")))
+	default:
+		sb.WriteString(st.Muted(T("Not set up")))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(st.BoldText(T("Touch ID for sudo:") + " "))
+	if result.SudoTouchID {
+		sb.WriteString(st.Success(IconCheck+" "+T("Enabled")) + st.Muted(" ("+result.SudoPAMFile+")"))
+	} else {
+		sb.WriteString(st.Muted(T("Not configured")))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(formatUserBiometrics(st, result.Users))
+
+	return sb.String()
+}
+
+// FprintdDevice is a fingerprint reader managed by fprintd
+type FprintdDevice struct {
+	Name            string   `json:"name"`
+	ScanType        string   `json:"scan_type,omitempty"`
+	EnrolledFingers []string `json:"enrolled_fingers,omitempty"`
+}
+
+// LinuxBiometricCapabilities contains detailed biometric capability
+// information on Linux, where BiometricCapabilities is an alias for it
+type LinuxBiometricCapabilities struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	TouchIDAvailable bool   `json:"touch_id_available"`
+	TouchIDEnrolled  bool   `json:"touch_id_enrolled"`
+	FaceIDAvailable  bool   `json:"face_id_available"`
+	FaceIDEnrolled   bool   `json:"face_id_enrolled"`
+	BiometryType     string `json:"biometry_type"`
+	// Linux-specific fields
+	FprintdAvailable bool   `json:"fprintd_available,omitempty"`
+	FprintdEnrolled  bool   `json:"fprintd_enrolled,omitempty"`
+	HowdyAvailable   bool   `json:"howdy_available,omitempty"`
+	HowdyConfigured  bool   `json:"howdy_configured,omitempty"`
+	Platform         string `json:"platform"`
+
+	// FprintdDevices lists fingerprint readers and the current user's
+	// enrolled fingers, as reported by fprintd over D-Bus
+	FprintdDevices []FprintdDevice `json:"fprintd_devices,omitempty"`
+	// PAMRules lists biometric modules found in PAM auth stacks
+	PAMRules []PAMBiometricRule `json:"pam_rules,omitempty"`
+	// PAMEnabled reports whether any service accepts biometrics for auth
+	PAMEnabled bool `json:"pam_enabled"`
+	// BiometricAuthRequired reports whether any service requires biometrics
+	// rather than accepting them as an alternative to the password
+	BiometricAuthRequired bool        `json:"biometric_auth_required"`
+	Error                 *ProbeError `json:"error,omitempty"`
+
+	// Users lists every local user's enrollment (BiometricOptions.AllUsers)
+	Users []UserBiometrics `json:"users,omitempty"`
+}
+
+// formatLinuxBiometricsTable formats Linux biometric capabilities as a colored table
+func formatLinuxBiometricsTable(st Styler, result *LinuxBiometricCapabilities) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconFingerprint + " " + T("Biometric Capabilities")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	// Platform badge
+	sb.WriteString(st.BoldText(T("Platform:") + " "))
+	sb.WriteString(st.Info(IconChip + " Linux"))
+	sb.WriteString("\n\n")
+
+	// Check if any biometrics available
+	if !result.FprintdAvailable && !result.HowdyAvailable {
+		sb.WriteString(st.Muted(T("No biometric authentication services detected.")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(T("Consider installing:")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted("  - fprintd: " + T("for fingerprint authentication")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted("  - howdy: " + T("for facial recognition")))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	// Capabilities table
+	sb.WriteString(st.TableTop(20, 14, 14))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Service"), 20)),
+		st.Header(PadRight(T("Available"), 14)),
+		st.Header(PadRight(T("Configured"), 14)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(20, 14, 14))
+	sb.WriteString("\n")
+
+	// fprintd row
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconFingerprint+" fprintd", 20),
+		PadRight(st.BoolToStatusColored(result.FprintdAvailable), 14),
+		PadRight(st.BoolToStatusColored(result.FprintdEnrolled), 14),
+	))
+	sb.WriteString("\n")
+
+	// Howdy row
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconFace+" Howdy", 20),
+		PadRight(st.BoolToStatusColored(result.HowdyAvailable), 14),
+		PadRight(st.BoolToStatusColored(result.HowdyConfigured), 14),
+	))
+	sb.WriteString("\n")
+
+	sb.WriteString(st.TableBottom(20, 14, 14))
+	sb.WriteString("\n")
+
+	// PAM usage
+	sb.WriteString("\n")
+	sb.WriteString(st.BoldText(T("PAM Authentication:") + " "))
+	switch {
+	case result.BiometricAuthRequired:
+		sb.WriteString(st.Success(T("Biometrics required")))
+	case result.PAMEnabled:
+		sb.WriteString(st.Info(T("Biometrics accepted (password alternative)")))
+	default:
+		sb.WriteString(st.Warning(T("Not used by any PAM service")))
+	}
+	sb.WriteString("\n")
+	for _, r := range result.PAMRules {
+		line := r.Service + ": " + r.Module + " (" + r.Control + ")"
+		if r.IncludedFrom != "" {
+			line += " " + T("via %s", r.IncludedFrom)
+		}
+		sb.WriteString("  " + IconArrow + " " + st.Muted(line) + "\n")
+	}
+	sb.WriteString(formatUserBiometrics(st, result.Users))
+	sb.WriteString(formatProbeError(st, result.Error))
+
+	return sb.String()
+}
+
+// BiometricUnit is a sensor registered with the Windows Biometric Framework
+type BiometricUnit struct {
+	Factor       string `json:"factor"`
+	Description  string `json:"description,omitempty"`
+	Manufacturer string `json:"manufacturer,omitempty"`
+	Model        string `json:"model,omitempty"`
+	// Enrolled reports whether the current user has enrolled on this unit
+	Enrolled bool `json:"enrolled"`
+}
+
+// WindowsBiometricCapabilities contains detailed biometric capability
+// information on Windows, where BiometricCapabilities is an alias for it
+type WindowsBiometricCapabilities struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	TouchIDAvailable bool   `json:"touch_id_available"`
+	TouchIDEnrolled  bool   `json:"touch_id_enrolled"`
+	FaceIDAvailable  bool   `json:"face_id_available"`
+	FaceIDEnrolled   bool   `json:"face_id_enrolled"`
+	BiometryType     string `json:"biometry_type"`
+	// Windows-specific fields
+	WindowsHelloAvailable  bool `json:"windows_hello_available,omitempty"`
+	WindowsHelloConfigured bool `json:"windows_hello_configured,omitempty"`
+	FingerprintAvailable   bool `json:"fingerprint_available,omitempty"`
+	FingerprintEnrolled    bool `json:"fingerprint_enrolled,omitempty"`
+	FacialRecognition      bool `json:"facial_recognition,omitempty"`
+	PINConfigured          bool `json:"pin_configured,omitempty"`
+
+	// NGCContainerPresent reports whether the Next Generation Credential
+	// store that backs Windows Hello keys exists on this machine
+	NGCContainerPresent bool            `json:"ngc_container_present,omitempty"`
+	BiometricUnits      []BiometricUnit `json:"biometric_units,omitempty"`
+	// IRCameras lists infrared cameras usable for face recognition
+	IRCameras []string `json:"ir_cameras,omitempty"`
+	Platform  string   `json:"platform"`
+
+	// Users lists every local user's enrollment (BiometricOptions.AllUsers)
+	Users []UserBiometrics `json:"users,omitempty"`
+}
+
+// formatWindowsBiometricsTable formats Windows biometric capabilities as a colored table
+func formatWindowsBiometricsTable(st Styler, result *WindowsBiometricCapabilities) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconFingerprint + " " + T("Biometric Capabilities")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	// Platform badge
+	sb.WriteString(st.BoldText(T("Platform:") + " "))
+	sb.WriteString(st.Info(IconChip + " Windows (Windows Hello)"))
+	sb.WriteString("\n\n")
+
+	// Windows Hello status
+	sb.WriteString(st.BoldText("Windows Hello: "))
+	if result.WindowsHelloAvailable {
+		if result.WindowsHelloConfigured {
+			sb.WriteString(st.Success(T("Available & Configured")))
+		} else {
+			sb.WriteString(st.Warning(T("Available (Not Configured)")))
+		}
+	} else {
+		sb.WriteString(st.Muted(T("Not Available")))
+	}
+	sb.WriteString("\n\n")
+
+	// Capabilities table
+	sb.WriteString(st.TableTop(20, 14, 14))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Biometric"), 20)),
+		st.Header(PadRight(T("Available"), 14)),
+		st.Header(PadRight(T("Enrolled"), 14)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(20, 14, 14))
+	sb.WriteString("\n")
+
+	// Fingerprint row
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconFingerprint+" "+T("Fingerprint"), 20),
+		PadRight(st.BoolToStatusColored(result.FingerprintAvailable), 14),
+		PadRight(st.BoolToStatusColored(result.FingerprintEnrolled), 14),
+	))
+	sb.WriteString("\n")
+
+	// Face Recognition row
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconFace+" "+T("Face Recognition"), 20),
+		PadRight(st.BoolToStatusColored(result.FacialRecognition), 14),
+		PadRight(st.BoolToStatusColored(result.FaceIDEnrolled), 14),
+	))
+	sb.WriteString("\n")
+
+	// PIN row (Windows Hello always has a PIN fallback)
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconKey+" PIN", 20),
+		PadRight(st.BoolToStatusColored(true), 14),
+		PadRight(st.BoolToStatusColored(result.PINConfigured), 14),
+	))
+	sb.WriteString("\n")
+
+	sb.WriteString(st.TableBottom(20, 14, 14))
+	sb.WriteString("\n")
+
+	// Biometric sensors
+	if len(result.BiometricUnits) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText(T("Sensors:")))
+		sb.WriteString("\n")
+		for _, u := range result.BiometricUnits {
+			name := u.Description
+			if name == "" {
+				name = u.Model
+			}
+			sb.WriteString("  " + st.BoolToCheckbox(u.Enrolled) + " " + name)
+			if u.Manufacturer != "" {
+				sb.WriteString(st.Muted(" (" + u.Manufacturer + ")"))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if len(result.IRCameras) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText(T("IR Cameras:")))
+		sb.WriteString("\n")
+		for _, c := range result.IRCameras {
+			sb.WriteString("  " + IconArrow + " " + c + "\n")
+		}
+	}
+
+	sb.WriteString(formatUserBiometrics(st, result.Users))
+
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(T("NGC credential store:") + " "))
+	if result.NGCContainerPresent {
+		sb.WriteString(st.Muted(T("present")))
+	} else {
+		sb.WriteString(st.Muted(T("absent")))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
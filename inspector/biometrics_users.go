@@ -0,0 +1,160 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// UserBiometrics is one local user's biometric enrollment status
+type UserBiometrics struct {
+	Username string `json:"username"`
+	// ID is the user's UID (macOS, Linux) or SID (Windows)
+	ID                  string   `json:"id,omitempty"`
+	FingerprintEnrolled bool     `json:"fingerprint_enrolled"`
+	FaceEnrolled        bool     `json:"face_enrolled"`
+	Fingers             []string `json:"fingers,omitempty"`
+	// PINConfigured reports a Windows Hello PIN (Windows only)
+	PINConfigured bool        `json:"pin_configured,omitempty"`
+	Error         *ProbeError `json:"error,omitempty"`
+}
+
+// BiometricOptions selects what GetBiometricCapabilitiesWithOptions reports
+type BiometricOptions struct {
+	// AllUsers also enumerates enrollment for every local user. Reading other
+	// users' enrollments usually requires elevated privileges; users that
+	// cannot be read carry a permission_denied error.
+	AllUsers bool
+}
+
+// GetBiometricCapabilitiesWithOptions returns biometric capabilities and,
+// with AllUsers, per-user enrollment for shared workstation audits
+func GetBiometricCapabilitiesWithOptions(opts BiometricOptions) (*BiometricCapabilities, error) {
+	return GetBiometricCapabilitiesWithOptionsContext(context.Background(), opts)
+}
+
+// GetBiometricCapabilitiesWithOptionsContext is like
+// GetBiometricCapabilitiesWithOptions but runs the probes with ctx's
+// deadline, command runner, and logger
+func GetBiometricCapabilitiesWithOptionsContext(ctx context.Context, opts BiometricOptions) (*BiometricCapabilities, error) {
+	result, err := GetBiometricCapabilitiesContext(ctx)
+	if err != nil || !opts.AllUsers {
+		return result, err
+	}
+	result.Users = enumerateUserBiometrics(ctx)
+	return result, nil
+}
+
+// localUser is an interactive local account
+type localUser struct {
+	Name string
+	UID  int
+}
+
+// parsePasswd returns the interactive accounts in an /etc/passwd file: UIDs
+// from uidMin up (excluding nobody) with a login shell
+func parsePasswd(data []byte, uidMin int) []localUser {
+	var users []localUser
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ":")
+		if len(fields) < 7 {
+			continue
+		}
+		uid, err := strconv.Atoi(fields[2])
+		if err != nil || uid < uidMin || uid == 65534 {
+			continue
+		}
+		shell := fields[6]
+		if strings.HasSuffix(shell, "/nologin") || strings.HasSuffix(shell, "/false") {
+			continue
+		}
+		users = append(users, localUser{Name: fields[0], UID: uid})
+	}
+	return users
+}
+
+// parseLoginDefsUIDMin reads UID_MIN from /etc/login.defs, defaulting to 1000
+func parseLoginDefsUIDMin(data []byte) int {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "UID_MIN" {
+			if n, err := strconv.Atoi(fields[1]); err == nil {
+				return n
+			}
+		}
+	}
+	return 1000
+}
+
+// parseDsclUsers parses `dscl . -list /Users UniqueID`, keeping regular
+// accounts (UID 501 and up, not _service accounts)
+func parseDsclUsers(data []byte) []localUser {
+	var users []localUser
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || strings.HasPrefix(fields[0], "_") {
+			continue
+		}
+		uid, err := strconv.Atoi(fields[1])
+		if err != nil || uid < 501 {
+			continue
+		}
+		users = append(users, localUser{Name: fields[0], UID: uid})
+	}
+	return users
+}
+
+// bioutilUserLine matches "User 501:	2 biometric template(s)"
+var bioutilUserLine = regexp.MustCompile(`User (\d+):\s*(\d+) biometric template`)
+
+// parseBioutilCounts parses `bioutil -c -s` into Touch ID template counts by UID
+func parseBioutilCounts(data []byte) map[int]int {
+	counts := make(map[int]int)
+	for _, m := range bioutilUserLine.FindAllStringSubmatch(string(data), -1) {
+		uid, _ := strconv.Atoi(m[1])
+		n, _ := strconv.Atoi(m[2])
+		counts[uid] = n
+	}
+	return counts
+}
+
+// formatUserBiometrics renders the per-user enrollment list for table output
+func formatUserBiometrics(st Styler, users []UserBiometrics) string {
+	if len(users) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.BoldText("Users:"))
+	sb.WriteString("\n")
+	for _, u := range users {
+		sb.WriteString("  " + PadRight(u.Username, 16))
+		if u.Error != nil {
+			sb.WriteString(st.Muted("unknown (" + string(u.Error.Code) + ")"))
+			sb.WriteString("\n")
+			continue
+		}
+		var methods []string
+		if u.FingerprintEnrolled {
+			methods = append(methods, IconFingerprint+" fingerprint")
+		}
+		if u.FaceEnrolled {
+			methods = append(methods, IconFace+" face")
+		}
+		if u.PINConfigured {
+			methods = append(methods, IconKey+" PIN")
+		}
+		if len(methods) == 0 {
+			sb.WriteString(st.Warning("not enrolled"))
+		} else {
+			sb.WriteString(st.Success(strings.Join(methods, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
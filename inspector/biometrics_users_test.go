@@ -0,0 +1,35 @@
+package inspector
+
+import (
+	"testing"
+)
+
+func TestParsePasswd(t *testing.T) {
+	users := parsePasswd(fixture(t, "linux/passwd"), 1000)
+	if len(users) != 2 || users[0] != (localUser{"alice", 1000}) || users[1] != (localUser{"bob", 1001}) {
+		t.Errorf("users = %+v, want alice and bob", users)
+	}
+}
+
+func TestParseLoginDefsUIDMin(t *testing.T) {
+	if got := parseLoginDefsUIDMin([]byte("# comment\nUID_MIN\t\t\t  500\nUID_MAX 60000\n")); got != 500 {
+		t.Errorf("UID_MIN = %d, want 500", got)
+	}
+	if got := parseLoginDefsUIDMin(nil); got != 1000 {
+		t.Errorf("default UID_MIN = %d, want 1000", got)
+	}
+}
+
+func TestParseDsclUsers(t *testing.T) {
+	users := parseDsclUsers(fixture(t, "darwin/dscl_users.txt"))
+	if len(users) != 2 || users[0].Name != "alice" || users[1].UID != 502 {
+		t.Errorf("users = %+v, want alice (501) and bob (502)", users)
+	}
+}
+
+func TestParseBioutilCounts(t *testing.T) {
+	counts := parseBioutilCounts(fixture(t, "darwin/bioutil_counts.txt"))
+	if counts[501] != 2 || counts[502] != 0 || len(counts) != 2 {
+		t.Errorf("counts = %v, want 501:2 502:0", counts)
+	}
+}
@@ -3,180 +3,369 @@
 package inspector
 
 import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
 )
 
 var (
-	credui                    = syscall.NewLazyDLL("credui.dll")
+	credui                                 = syscall.NewLazyDLL("credui.dll")
 	procCredUIPromptForWindowsCredentialsW = credui.NewProc("CredUIPromptForWindowsCredentialsW")
 
 	// For checking Windows Hello
 	userenv = syscall.NewLazyDLL("userenv.dll")
 )
 
+// Windows Biometric Framework (winbio.dll)
+var (
+	winbio                       = syscall.NewLazyDLL("winbio.dll")
+	procWinBioEnumBiometricUnits = winbio.NewProc("WinBioEnumBiometricUnits")
+	procWinBioOpenSession        = winbio.NewProc("WinBioOpenSession")
+	procWinBioCloseSession       = winbio.NewProc("WinBioCloseSession")
+	procWinBioEnumEnrollments    = winbio.NewProc("WinBioEnumEnrollments")
+	procWinBioFree               = winbio.NewProc("WinBioFree")
+)
+
+// WINBIO_BIOMETRIC_TYPE factors
+const (
+	winbioTypeFacialFeatures = 0x00000002
+	winbioTypeFingerprint    = 0x00000008
+)
+
+const (
+	winbioPoolSystem   = 1 // WINBIO_POOL_SYSTEM
+	winbioFlagDefault  = 0 // WINBIO_FLAG_DEFAULT
+	winbioDBDefault    = 1 // WINBIO_DB_DEFAULT, passed as a GUID pointer
+	winbioIDTypeSID    = 3 // WINBIO_ID_TYPE_SID
+	securityMaxSIDSize = 68
+)
+
+// winbioUnitSchema mirrors WINBIO_UNIT_SCHEMA
+type winbioUnitSchema struct {
+	UnitID           uint32
+	PoolType         uint32
+	BiometricFactor  uint32
+	SensorSubType    uint32
+	Capabilities     uint32
+	DeviceInstanceID [256]uint16
+	Description      [256]uint16
+	Manufacturer     [256]uint16
+	Model            [256]uint16
+	SerialNumber     [256]uint16
+	FirmwareMajor    uint32
+	FirmwareMinor    uint32
+}
+
+// winbioIdentity mirrors WINBIO_IDENTITY with the AccountSid union member
+type winbioIdentity struct {
+	Type    uint32
+	SIDSize uint32
+	SID     [securityMaxSIDSize]byte
+}
+
+// PIN (NGC) credential provider; it keeps a subkey per user SID with a PIN
+const pinCredentialProviderKey = `SOFTWARE\Microsoft\Windows\CurrentVersion\Authentication\Credential Providers\{D6886603-9D2F-4EB2-B667-1971041FA96B}`
+
+// profileListKey has a subkey per SID that has a local user profile
+const profileListKey = `SOFTWARE\Microsoft\Windows NT\CurrentVersion\ProfileList`
+
+// ngcPinCredentialsKey lists user SIDs with a Windows Hello PIN
+const ngcPinCredentialsKey = `SOFTWARE\Microsoft\Windows\CurrentVersion\Authentication\LogonUI\NgcPin\Credentials`
+
 // BiometricCapabilities contains detailed biometric capability information
-type BiometricCapabilities struct {
-	TouchIDAvailable bool   `json:"touch_id_available"`
-	TouchIDEnrolled  bool   `json:"touch_id_enrolled"`
-	FaceIDAvailable  bool   `json:"face_id_available"`
-	FaceIDEnrolled   bool   `json:"face_id_enrolled"`
-	BiometryType     string `json:"biometry_type"`
-	// Windows-specific fields
-	WindowsHelloAvailable  bool   `json:"windows_hello_available,omitempty"`
-	WindowsHelloConfigured bool   `json:"windows_hello_configured,omitempty"`
-	FingerprintAvailable   bool   `json:"fingerprint_available,omitempty"`
-	FingerprintEnrolled    bool   `json:"fingerprint_enrolled,omitempty"`
-	FacialRecognition      bool   `json:"facial_recognition,omitempty"`
-	PINConfigured          bool   `json:"pin_configured,omitempty"`
-	Platform               string `json:"platform"`
+type BiometricCapabilities = WindowsBiometricCapabilities
+
+// Win32_PnPEntity represents a WMI Plug and Play device
+type Win32_PnPEntity struct {
+	Name     string
+	PNPClass string
+	Status   string
 }
 
 // GetBiometricCapabilities returns biometric capabilities (Windows)
 func GetBiometricCapabilities() (*BiometricCapabilities, error) {
+	return GetBiometricCapabilitiesContext(context.Background())
+}
+
+// GetBiometricCapabilitiesContext is like GetBiometricCapabilities but runs
+// the probes with ctx's deadline, command runner, and logger
+func GetBiometricCapabilitiesContext(ctx context.Context) (*BiometricCapabilities, error) {
+	if result, ok, err := loadFixture[BiometricCapabilities](ctx, "biometrics"); ok {
+		return result, err
+	}
 	result := &BiometricCapabilities{
 		Platform:     "windows",
 		BiometryType: "none",
 	}
 
-	// Check Windows Hello availability
-	// This is a simplified check - in production you'd use Windows.Security.Credentials.UI
-	// or WMI queries for more detailed information
+	sid := currentUserSID()
 
-	// Check if Windows Hello is available via registry or system capabilities
-	// For now, we'll check for biometric devices
-
-	// Try to detect fingerprint reader via WMI
-	fingerprintAvailable := checkFingerprintSensor()
-	result.FingerprintAvailable = fingerprintAvailable
-	result.TouchIDAvailable = fingerprintAvailable // Map to TouchID equivalent
+	// Sensors and enrollments from the Windows Biometric Framework
+	result.BiometricUnits = enumBiometricUnits(winbioTypeFingerprint, sid)
+	for _, u := range result.BiometricUnits {
+		result.FingerprintAvailable = true
+		result.FingerprintEnrolled = result.FingerprintEnrolled || u.Enrolled
+	}
+	result.TouchIDAvailable = result.FingerprintAvailable // Map to TouchID equivalent
+	result.TouchIDEnrolled = result.FingerprintEnrolled
 
-	// Check for Windows Hello face recognition (IR camera)
-	faceAvailable := checkFaceRecognition()
+	// Windows Hello face recognition needs an IR camera; WBF registers a
+	// facial features unit for it once the face driver is installed
+	faceUnits := enumBiometricUnits(winbioTypeFacialFeatures, sid)
+	result.BiometricUnits = append(result.BiometricUnits, faceUnits...)
+	result.IRCameras = irCameras()
+	faceAvailable := len(faceUnits) > 0 || len(result.IRCameras) > 0
 	result.FacialRecognition = faceAvailable
 	result.FaceIDAvailable = faceAvailable
+	for _, u := range faceUnits {
+		result.FaceIDEnrolled = result.FaceIDEnrolled || u.Enrolled
+	}
 
-	// Check if Windows Hello is configured
-	helloConfigured := checkWindowsHelloConfigured()
-	result.WindowsHelloConfigured = helloConfigured
-	result.WindowsHelloAvailable = fingerprintAvailable || faceAvailable
+	// Windows Hello always requires a PIN, so a PIN for the current user
+	// means Windows Hello is set up
+	result.PINConfigured = pinConfigured(sid)
+	result.NGCContainerPresent = ngcContainerPresent()
+	result.WindowsHelloConfigured = result.PINConfigured
+	result.WindowsHelloAvailable = result.FingerprintAvailable || faceAvailable || result.PINConfigured
 
 	// Determine biometry type
-	if fingerprintAvailable && faceAvailable {
+	if result.FingerprintAvailable && faceAvailable {
 		result.BiometryType = "fingerprint_and_face"
-	} else if fingerprintAvailable {
+	} else if result.FingerprintAvailable {
 		result.BiometryType = "fingerprint"
-		result.TouchIDEnrolled = helloConfigured
 	} else if faceAvailable {
 		result.BiometryType = "face"
-		result.FaceIDEnrolled = helloConfigured
 	}
 
 	return result, nil
 }
 
-// checkFingerprintSensor checks for fingerprint sensor availability
-func checkFingerprintSensor() bool {
-	// Check for biometric devices in the system
-	// This is a simplified check - actual implementation would query WMI or use Windows Biometric Framework
+// currentUserSID returns the SID of the user running the process, or nil
+func currentUserSID() *windows.SID {
+	user, err := windows.GetCurrentProcessToken().GetTokenUser()
+	if err != nil {
+		return nil
+	}
+	return user.User.Sid
+}
+
+// enumBiometricUnits lists the WBF units for a biometric factor and whether
+// the user identified by sid has enrolled on each
+func enumBiometricUnits(factor uint32, sid *windows.SID) []BiometricUnit {
+	if winbio.Load() != nil || procWinBioEnumBiometricUnits.Find() != nil {
+		return nil
+	}
 
-	// Try to load the Windows Biometric Framework DLL
-	winbio := syscall.NewLazyDLL("winbio.dll")
-	if winbio.Load() == nil {
-		// DLL loaded successfully, biometric framework is available
-		// In a full implementation, you would call WinBioEnumBiometricUnits
-		return true
+	var schemas *winbioUnitSchema
+	var count uintptr
+	hr, _, _ := procWinBioEnumBiometricUnits.Call(uintptr(factor), uintptr(unsafe.Pointer(&schemas)), uintptr(unsafe.Pointer(&count)))
+	if uint32(hr) != 0 || schemas == nil {
+		return nil
 	}
-	return false
+	defer procWinBioFree.Call(uintptr(unsafe.Pointer(schemas))) // #nosec G104 -- nothing to do if freeing fails
+
+	enrolled := winbioEnrolledUnits(factor, sid)
+	units := make([]BiometricUnit, 0, count)
+	for _, schema := range unsafe.Slice(schemas, count) {
+		units = append(units, BiometricUnit{
+			Factor:       winbioFactorName(schema.BiometricFactor),
+			Description:  windows.UTF16ToString(schema.Description[:]),
+			Manufacturer: windows.UTF16ToString(schema.Manufacturer[:]),
+			Model:        windows.UTF16ToString(schema.Model[:]),
+			Enrolled:     enrolled[schema.UnitID],
+		})
+	}
+	return units
 }
 
-// checkFaceRecognition checks for Windows Hello face recognition availability
-func checkFaceRecognition() bool {
-	// Check for IR camera / Windows Hello face recognition
-	// This would typically query the camera capabilities
-	// Simplified implementation
-	return false
+// winbioEnrolledUnits returns the IDs of the system-pool units on which the
+// user has enrolled at least one sub-factor (e.g. a finger)
+func winbioEnrolledUnits(factor uint32, sid *windows.SID) map[uint32]bool {
+	enrolled := make(map[uint32]bool)
+	if sid == nil || sid.Len() > securityMaxSIDSize || winbio.Load() != nil {
+		return enrolled
+	}
+
+	var session uint32
+	hr, _, _ := procWinBioOpenSession.Call(uintptr(factor), winbioPoolSystem, winbioFlagDefault,
+		0, 0, winbioDBDefault, uintptr(unsafe.Pointer(&session)))
+	if uint32(hr) != 0 {
+		return enrolled
+	}
+	defer procWinBioCloseSession.Call(uintptr(session)) // #nosec G104 -- best-effort cleanup
+
+	identity := winbioIdentity{Type: winbioIDTypeSID, SIDSize: uint32(sid.Len())} // #nosec G115 -- bounded above
+	copy(identity.SID[:], unsafe.Slice((*byte)(unsafe.Pointer(sid)), sid.Len()))
+
+	var schemas *winbioUnitSchema
+	var count uintptr
+	hr, _, _ = procWinBioEnumBiometricUnits.Call(uintptr(factor), uintptr(unsafe.Pointer(&schemas)), uintptr(unsafe.Pointer(&count)))
+	if uint32(hr) != 0 || schemas == nil {
+		return enrolled
+	}
+	defer procWinBioFree.Call(uintptr(unsafe.Pointer(schemas))) // #nosec G104 -- nothing to do if freeing fails
+
+	for _, schema := range unsafe.Slice(schemas, count) {
+		var subFactors *byte
+		var subCount uintptr
+		hr, _, _ := procWinBioEnumEnrollments.Call(uintptr(session), uintptr(schema.UnitID),
+			uintptr(unsafe.Pointer(&identity)), uintptr(unsafe.Pointer(&subFactors)), uintptr(unsafe.Pointer(&subCount)))
+		if uint32(hr) != 0 {
+			// WINBIO_E_UNKNOWN_ID: the user has no enrollments on this unit
+			continue
+		}
+		if subFactors != nil {
+			procWinBioFree.Call(uintptr(unsafe.Pointer(subFactors))) // #nosec G104 -- nothing to do if freeing fails
+		}
+		enrolled[schema.UnitID] = subCount > 0
+	}
+	return enrolled
 }
 
-// checkWindowsHelloConfigured checks if Windows Hello is set up for the current user
-func checkWindowsHelloConfigured() bool {
-	// Check NGC (Next Generation Credential) container
-	// This indicates if Windows Hello is configured
-	// Simplified check via registry or credential APIs
+// enumerateUserBiometrics reports WBF enrollment and PIN state for every
+// local user with a profile. Other users' enrollments need Administrator.
+func enumerateUserBiometrics(ctx context.Context) []UserBiometrics {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, profileListKey, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		return nil
+	}
+	names, err := k.ReadSubKeyNames(-1)
+	_ = k.Close()
+	if err != nil {
+		return nil
+	}
+
+	current := currentUserSID()
+	elevated := IsElevated()
 
-	// In production, you would check:
-	// HKEY_CURRENT_USER\SOFTWARE\Microsoft\Windows\CurrentVersion\Authentication\LogonUI\NgcPin
-	// or use Windows.Security.Credentials APIs
+	var users []UserBiometrics
+	for _, name := range names {
+		// Local and domain user accounts; skips SYSTEM and service profiles
+		if !strings.HasPrefix(name, "S-1-5-21-") {
+			continue
+		}
+		sid, err := windows.StringToSid(name)
+		if err != nil {
+			continue
+		}
 
-	return false
+		ub := UserBiometrics{Username: accountName(sid, name), ID: name}
+		ub.PINConfigured = pinConfigured(sid)
+		if !elevated && (current == nil || !sid.Equals(current)) {
+			ub.Error = newProbeError(ctx, ErrPermissionDenied, "WinBioEnumEnrollments",
+				"reading other users' biometric enrollments requires Administrator")
+		} else {
+			for _, ok := range winbioEnrolledUnits(winbioTypeFingerprint, sid) {
+				ub.FingerprintEnrolled = ub.FingerprintEnrolled || ok
+			}
+			for _, ok := range winbioEnrolledUnits(winbioTypeFacialFeatures, sid) {
+				ub.FaceEnrolled = ub.FaceEnrolled || ok
+			}
+		}
+		users = append(users, ub)
+	}
+	return users
+}
+
+// accountName resolves a SID to DOMAIN\user, falling back to the SID string
+func accountName(sid *windows.SID, fallback string) string {
+	account, domain, _, err := sid.LookupAccount("")
+	if err != nil {
+		return fallback
+	}
+	if domain == "" {
+		return account
+	}
+	return domain + `\` + account
+}
+
+// winbioFactorName names a WINBIO_BIOMETRIC_TYPE
+func winbioFactorName(factor uint32) string {
+	switch factor {
+	case winbioTypeFingerprint:
+		return "fingerprint"
+	case winbioTypeFacialFeatures:
+		return "face"
+	}
+	return "other"
+}
+
+// irCameras lists working infrared cameras from the PnP camera, imaging, and
+// biometric device classes
+func irCameras() []string {
+	var devices []Win32_PnPEntity
+	query := "SELECT Name, PNPClass, Status FROM Win32_PnPEntity " +
+		"WHERE PNPClass = 'Camera' OR PNPClass = 'Image' OR PNPClass = 'Biometric'"
+	if err := wmiQuery(query, &devices, ""); err != nil {
+		return nil
+	}
+
+	var cameras []string
+	for _, d := range devices {
+		if d.Status == "OK" && isIRCameraName(d.Name) {
+			cameras = append(cameras, d.Name)
+		}
+	}
+	return cameras
+}
+
+// pinConfigured checks whether the user identified by sid has a Windows
+// Hello PIN, as recorded by the PIN credential provider and LogonUI
+func pinConfigured(sid *windows.SID) bool {
+	if sid == nil {
+		return false
+	}
+	userSID := sid.String()
+
+	if k, err := registry.OpenKey(registry.LOCAL_MACHINE, pinCredentialProviderKey+`\`+userSID, registry.QUERY_VALUE); err == nil {
+		defer k.Close()
+		if v, _, err := k.GetIntegerValue("LogonCredsAvailable"); err == nil && v != 0 {
+			return true
+		}
+	}
+
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE, ngcPinCredentialsKey+`\`+userSID, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	_ = k.Close()
+	return true
+}
+
+// ngcContainerPresent checks for the NGC folder that holds Windows Hello
+// keys. Only SYSTEM can list it, so access denied also means it exists.
+func ngcContainerPresent() bool {
+	windir := os.Getenv("SystemRoot")
+	if windir == "" {
+		windir = `C:\Windows`
+	}
+	_, err := os.Stat(filepath.Join(windir, "ServiceProfiles", "LocalService", "AppData", "Local", "Microsoft", "Ngc"))
+	return err == nil || errors.Is(err, fs.ErrPermission)
 }
 
 // FormatBiometricCapabilitiesTable formats biometric capabilities as a colored table
 func FormatBiometricCapabilitiesTable(result *BiometricCapabilities) string {
-	var sb strings.Builder
-	sb.WriteString("\n")
-	sb.WriteString(Header(IconFingerprint + " Biometric Capabilities"))
-	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 55)))
-	sb.WriteString("\n\n")
-
-	// Platform badge
-	sb.WriteString(BoldText("Platform: "))
-	sb.WriteString(Info(IconChip + " Windows (Windows Hello)"))
-	sb.WriteString("\n\n")
-
-	// Windows Hello status
-	sb.WriteString(BoldText("Windows Hello: "))
-	if result.WindowsHelloAvailable {
-		if result.WindowsHelloConfigured {
-			sb.WriteString(Success("Available & Configured"))
-		} else {
-			sb.WriteString(Warning("Available (Not Configured)"))
-		}
-	} else {
-		sb.WriteString(Muted("Not Available"))
-	}
-	sb.WriteString("\n\n")
-
-	// Capabilities table
-	sb.WriteString(TableTop(20, 14, 14))
-	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Biometric", 20)),
-		Header(PadRight("Available", 14)),
-		Header(PadRight("Enrolled", 14)),
-	))
-	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(20, 14, 14))
-	sb.WriteString("\n")
-
-	// Fingerprint row
-	sb.WriteString(TableRowColored(
-		PadRight(IconFingerprint+" Fingerprint", 20),
-		PadRight(BoolToStatusColored(result.FingerprintAvailable), 14),
-		PadRight(BoolToStatusColored(result.TouchIDEnrolled), 14),
-	))
-	sb.WriteString("\n")
-
-	// Face Recognition row
-	sb.WriteString(TableRowColored(
-		PadRight(IconFace+" Face Recognition", 20),
-		PadRight(BoolToStatusColored(result.FacialRecognition), 14),
-		PadRight(BoolToStatusColored(result.FaceIDEnrolled), 14),
-	))
-	sb.WriteString("\n")
-
-	sb.WriteString(TableBottom(20, 14, 14))
-	sb.WriteString("\n")
-
-	return sb.String()
+	return FormatBiometricCapabilitiesTableWith(CurrentStyler(), result)
+}
+
+// FormatBiometricCapabilitiesTableWith is like
+// FormatBiometricCapabilitiesTable but styles the table with st
+func FormatBiometricCapabilitiesTableWith(st Styler, result *BiometricCapabilities) string {
+	return formatWindowsBiometricsTable(st, result)
 }
 
 // FormatBiometricCapabilities formats biometric capabilities in the specified format
 func FormatBiometricCapabilities(result *BiometricCapabilities, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatBiometricCapabilitiesTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatBiometricCapabilitiesTableWith(st, result)
 	}, format)
 }
 
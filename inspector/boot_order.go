@@ -0,0 +1,421 @@
+package inspector
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"strings"
+	"unicode/utf16"
+)
+
+// efiGlobalVariableGUID is the vendor GUID of BootOrder, BootCurrent, and
+// the Boot#### load options
+const efiGlobalVariableGUID = "8be4df61-93ca-11d2-aa0d-00e098032b8c"
+
+// Load option attributes (UEFI 2.10, 3.1.3)
+const (
+	loadOptionActive       = 0x00000001
+	loadOptionCategoryMask = 0x00001f00
+)
+
+// Boot entry kinds
+const (
+	BootKindDisk      = "disk"
+	BootKindRemovable = "removable"
+	BootKindNetwork   = "network"
+	BootKindOther     = "other"
+)
+
+// BootEntry is a UEFI boot option (Boot####)
+type BootEntry struct {
+	// Number is the four hex digits of the Boot#### variable
+	Number      string `json:"number"`
+	Description string `json:"description"`
+	// Kind is disk, removable (USB, optical, SD), network (PXE, HTTP,
+	// iSCSI), or other (firmware applications such as the setup menu)
+	Kind   string `json:"kind"`
+	Active bool   `json:"active"`
+}
+
+// BootOrderResult reports whether the firmware tries removable media or the
+// network before the system disk, and on Macs whether booting from external
+// media is restricted
+type BootOrderResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string `json:"platform"`
+	// UEFI is true if the boot order was read from UEFI variables
+	UEFI bool `json:"uefi"`
+	// BootCurrent is the Boot#### entry the system started from
+	BootCurrent string `json:"boot_current,omitempty"`
+	// Entries lists the boot options in BootOrder order
+	Entries []BootEntry `json:"entries"`
+	// ExternalBeforeDisk and NetworkBeforeDisk are true if an active
+	// removable media or network entry comes before the first disk entry
+	ExternalBeforeDisk bool `json:"external_before_disk"`
+	NetworkBeforeDisk  bool `json:"network_before_disk"`
+	// FirmwarePassword is whether an Intel Mac has a firmware password,
+	// which is required to start up from other media (macOS)
+	FirmwarePassword *bool `json:"firmware_password,omitempty"`
+	// ExternalBootAllowed is whether a Mac can start up from external media
+	// without a password, where it can be determined (macOS)
+	ExternalBootAllowed *bool  `json:"external_boot_allowed,omitempty"`
+	Details             string `json:"details,omitempty"`
+	// Compliant is true unless external or network boot comes before the
+	// system disk, or a Mac can start up from external media unchallenged
+	Compliant bool        `json:"compliant"`
+	Error     *ProbeError `json:"error,omitempty"`
+}
+
+// IsBootOrderSupported reports whether the boot order check runs on this
+// platform
+func IsBootOrderSupported() bool {
+	switch runtime.GOOS {
+	case "linux", "windows", "darwin":
+		return true
+	}
+	return false
+}
+
+// GetBootOrder reads the UEFI boot order on Linux (efivarfs) and Windows
+// (firmware environment variables), and the firmware password state that
+// controls booting from external media on Intel Macs
+func GetBootOrder() (*BootOrderResult, error) {
+	return GetBootOrderContext(context.Background())
+}
+
+// GetBootOrderContext is like GetBootOrder but runs the probes with ctx's
+// deadline, command runner, and logger
+func GetBootOrderContext(ctx context.Context) (*BootOrderResult, error) {
+	if result, ok, err := loadFixture[BootOrderResult](ctx, "boot_order"); ok {
+		return result, err
+	}
+	if !IsBootOrderSupported() {
+		return nil, newProbeError(ctx, ErrUnsupportedPlatform, "boot_order", "the boot order is not checked on "+runtime.GOOS)
+	}
+	result := &BootOrderResult{Platform: runtime.GOOS}
+	platformBootOrder(ctx, result)
+	if result.Entries == nil {
+		result.Entries = []BootEntry{}
+	}
+	finishBootOrder(result)
+	return result, nil
+}
+
+// efiVariableReader reads a UEFI variable's data, without attributes, by
+// name (e.g. "BootOrder") in the EFI global variable namespace
+type efiVariableReader func(name string) ([]byte, error)
+
+// efivarfsReader reads UEFI variables from efivarfs below root, dropping the
+// four attribute bytes that precede each variable's data
+func efivarfsReader(root fs.FS) efiVariableReader {
+	return func(name string) ([]byte, error) {
+		data, err := readFSFile(root, "sys/firmware/efi/efivars/"+name+"-"+efiGlobalVariableGUID)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < 4 {
+			return nil, errors.New("efivarfs: " + name + " is too short")
+		}
+		return data[4:], nil
+	}
+}
+
+// readBootEntries reads BootCurrent, BootOrder, and each Boot#### option it
+// lists. Options that cannot be read are skipped.
+func readBootEntries(read efiVariableReader, result *BootOrderResult) error {
+	order, err := read("BootOrder")
+	if err != nil {
+		return err
+	}
+	result.UEFI = true
+	if current, err := read("BootCurrent"); err == nil && len(current) >= 2 {
+		result.BootCurrent = fmt.Sprintf("%04X", binary.LittleEndian.Uint16(current))
+	}
+	for i := 0; i+1 < len(order); i += 2 {
+		number := fmt.Sprintf("%04X", binary.LittleEndian.Uint16(order[i:]))
+		data, err := read("Boot" + number)
+		if err != nil {
+			continue
+		}
+		entry, ok := parseLoadOption(data)
+		if !ok {
+			continue
+		}
+		entry.Number = number
+		result.Entries = append(result.Entries, entry)
+	}
+	return nil
+}
+
+// parseLoadOption decodes an EFI_LOAD_OPTION: attributes, the length of the
+// device path list, a NUL-terminated UCS-2 description, then the device
+// paths. Application entries (those with a category) are skipped.
+func parseLoadOption(data []byte) (BootEntry, bool) {
+	if len(data) < 6 {
+		return BootEntry{}, false
+	}
+	attributes := binary.LittleEndian.Uint32(data)
+	pathLen := int(binary.LittleEndian.Uint16(data[4:]))
+	if attributes&loadOptionCategoryMask != 0 {
+		return BootEntry{}, false
+	}
+	var desc []uint16
+	i := 6
+	for ; i+1 < len(data); i += 2 {
+		c := binary.LittleEndian.Uint16(data[i:])
+		if c == 0 {
+			break
+		}
+		desc = append(desc, c)
+	}
+	i += 2
+	var paths []byte
+	if i <= len(data) {
+		paths = data[i:min(len(data), i+pathLen)]
+	}
+	entry := BootEntry{
+		Description: string(utf16.Decode(desc)),
+		Active:      attributes&loadOptionActive != 0,
+	}
+	entry.Kind = classifyDevicePath(paths)
+	if entry.Kind == BootKindOther {
+		entry.Kind = classifyBootDescription(entry.Description)
+	}
+	return entry, true
+}
+
+// classifyDevicePath classifies a boot option by the nodes of its device
+// path. A network node wins over a removable bus, which wins over a hard
+// drive partition, so a partition on a USB stick counts as removable.
+func classifyDevicePath(paths []byte) string {
+	var network, removable, disk bool
+	for i := 0; i+4 <= len(paths); {
+		typ, sub := paths[i], paths[i+1]
+		length := int(binary.LittleEndian.Uint16(paths[i+2:]))
+		if length < 4 || i+length > len(paths) {
+			break
+		}
+		switch typ {
+		case 0x03: // messaging
+			switch sub {
+			case 0x0b, 0x0c, 0x0d, 0x13, 0x18: // MAC, IPv4, IPv6, iSCSI, URI
+				network = true
+			case 0x05, 0x0f, 0x10, 0x1a: // USB, USB class, USB WWID, SD
+				removable = true
+			}
+		case 0x04: // media
+			switch sub {
+			case 0x01: // hard drive partition
+				disk = true
+			case 0x02: // CD-ROM
+				removable = true
+			}
+		case 0x05: // BIOS boot specification
+			if length >= 6 {
+				switch binary.LittleEndian.Uint16(paths[i+4:]) {
+				case 0x02:
+					disk = true
+				case 0x01, 0x03, 0x05: // floppy, CD-ROM, USB
+					removable = true
+				case 0x06, 0x80: // embedded network, BEV
+					network = true
+				}
+			}
+		}
+		if typ == 0x7f && sub == 0xff {
+			break
+		}
+		i += length
+	}
+	switch {
+	case network:
+		return BootKindNetwork
+	case removable:
+		return BootKindRemovable
+	case disk:
+		return BootKindDisk
+	}
+	return BootKindOther
+}
+
+// classifyBootDescription classifies vendor-defined boot options, whose
+// device paths say nothing about the device, by their description
+func classifyBootDescription(desc string) string {
+	lower := strings.ToLower(desc)
+	for _, word := range []string{"pxe", "network", "ipv4", "ipv6", "http"} {
+		if strings.Contains(lower, word) {
+			return BootKindNetwork
+		}
+	}
+	for _, word := range []string{"usb", "cd-rom", "cdrom", "dvd", "optical", "removable", "sd card"} {
+		if strings.Contains(lower, word) {
+			return BootKindRemovable
+		}
+	}
+	for _, word := range []string{"hdd", "ssd", "nvme", "sata", "hard drive", "boot manager"} {
+		if strings.Contains(lower, word) {
+			return BootKindDisk
+		}
+	}
+	return BootKindOther
+}
+
+// finishBootOrder determines whether removable or network boot comes
+// before the first active disk entry, and compliance
+func finishBootOrder(r *BootOrderResult) {
+	r.ExternalBeforeDisk, r.NetworkBeforeDisk = false, false
+	for _, e := range r.Entries {
+		if !e.Active {
+			continue
+		}
+		if e.Kind == BootKindDisk {
+			break
+		}
+		switch e.Kind {
+		case BootKindRemovable:
+			r.ExternalBeforeDisk = true
+		case BootKindNetwork:
+			r.NetworkBeforeDisk = true
+		}
+	}
+	r.Compliant = !r.ExternalBeforeDisk && !r.NetworkBeforeDisk &&
+		(r.ExternalBootAllowed == nil || !*r.ExternalBootAllowed)
+}
+
+// parseFirmwarePasswordCheck parses `firmwarepasswd -check`, which prints
+// "Password Enabled: Yes" or "Password Enabled: No"
+func parseFirmwarePasswordCheck(out []byte) (bool, error) {
+	for _, line := range strings.Split(string(out), "\n") {
+		if _, value, ok := strings.Cut(line, "Password Enabled:"); ok {
+			return strings.EqualFold(strings.TrimSpace(value), "yes"), nil
+		}
+	}
+	return false, errors.New("no Password Enabled line")
+}
+
+// bootOrderFindings returns removable or network boot ahead of the system
+// disk, and external boot without a firmware password, as findings
+func bootOrderFindings(r *BootOrderResult) []Finding {
+	var findings []Finding
+	add := func(id, title, severity, remediation string) {
+		findings = append(findings, Finding{
+			ID:                 id,
+			Title:              title,
+			Severity:           severity,
+			Check:              CheckBootOrder,
+			Remediation:        remediation,
+			RemediationCommand: remediationCommand(id),
+		})
+	}
+	if r.NetworkBeforeDisk {
+		add("boot_network_first", T("Network (PXE) boot comes before the system disk in the boot order"), SeverityMedium,
+			T("Move the system disk ahead of network boot in the firmware setup and protect the setup with a password"))
+	}
+	if r.ExternalBeforeDisk {
+		add("boot_external_first", T("Removable media boot comes before the system disk in the boot order"), SeverityMedium,
+			T("Move the system disk ahead of USB and optical boot in the firmware setup and protect the setup with a password"))
+	}
+	if r.ExternalBootAllowed != nil && *r.ExternalBootAllowed {
+		add("boot_external_allowed", T("The Mac can start up from external media without a firmware password"), SeverityMedium,
+			T("Set a firmware password in macOS Recovery with Startup Security Utility"))
+	}
+	if r.Error != nil && len(findings) == 0 {
+		findings = append(findings, unverifiedFinding("boot_order_unverified", CheckBootOrder, "Boot order", r.Error))
+	}
+	return findings
+}
+
+// FormatBootOrderTable formats the boot order as a colored table
+func FormatBootOrderTable(result *BootOrderResult) string {
+	return FormatBootOrderTableWith(CurrentStyler(), result)
+}
+
+// FormatBootOrderTableWith is like FormatBootOrderTable but styles the table
+// with st
+func FormatBootOrderTableWith(st Styler, result *BootOrderResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconLock + " Boot Order"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n")
+		if result.Error.Hint != "" {
+			sb.WriteString(st.Muted("  " + result.Error.Hint))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(result.Entries) > 0 {
+		sb.WriteString(st.TableTop(6, 32, 10))
+		sb.WriteString("\n")
+		sb.WriteString(st.TableRowColored(
+			st.Header(PadRight("Entry", 6)),
+			st.Header(PadRight("Description", 32)),
+			st.Header(PadRight("Kind", 10)),
+		))
+		sb.WriteString("\n")
+		sb.WriteString(st.TableSeparator(6, 32, 10))
+		sb.WriteString("\n")
+		for _, e := range result.Entries {
+			number := e.Number
+			if e.Number == result.BootCurrent {
+				number = st.BoldText(number)
+			}
+			kind := e.Kind
+			switch {
+			case !e.Active:
+				kind = st.Muted(kind + " (off)")
+			case e.Kind == BootKindNetwork || e.Kind == BootKindRemovable:
+				kind = st.Warning(kind)
+			}
+			desc := e.Description
+			if len(desc) > 32 {
+				desc = desc[:29] + "..."
+			}
+			sb.WriteString(st.TableRowColored(
+				PadRight(number, 6),
+				PadRight(desc, 32),
+				PadRight(kind, 10),
+			))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(st.TableBottom(6, 32, 10))
+		sb.WriteString("\n\n")
+	}
+
+	if result.FirmwarePassword != nil {
+		sb.WriteString(st.BoldText("Firmware password: "))
+		sb.WriteString(st.BoolToStatusColored(*result.FirmwarePassword))
+		sb.WriteString("\n")
+	}
+	switch {
+	case result.NetworkBeforeDisk:
+		sb.WriteString(st.Danger(IconCross + " Network boot comes before the system disk"))
+	case result.ExternalBeforeDisk:
+		sb.WriteString(st.Danger(IconCross + " Removable media boot comes before the system disk"))
+	case result.ExternalBootAllowed != nil && *result.ExternalBootAllowed:
+		sb.WriteString(st.Danger(IconCross + " External boot is allowed without a password"))
+	case result.UEFI:
+		sb.WriteString(st.Success(IconCheck + " The system disk boots first"))
+	default:
+		sb.WriteString(st.Muted(result.Details))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// FormatBootOrder formats the boot order in the specified format
+func FormatBootOrder(result *BootOrderResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatBootOrderTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,42 @@
+//go:build darwin
+
+package inspector
+
+import (
+	"context"
+	"strings"
+)
+
+// platformBootOrder reports whether the Mac can start up from external
+// media. Intel Macs need the firmware password to start up from another
+// volume when one is set; the Allowed Boot Media setting of T2 Macs and the
+// startup options of Apple silicon cannot be read from macOS.
+func platformBootOrder(ctx context.Context, result *BootOrderResult) {
+	if isAppleSilicon() {
+		result.Details = "Apple silicon only starts up from another volume after an administrator authenticates in startup options"
+		return
+	}
+	out, err := runCommand(ctx, "firmwarepasswd", "-check")
+	if err != nil {
+		result.Error = classifyExecError(ctx, "firmwarepasswd", err)
+		return
+	}
+	enabled, err := parseFirmwarePasswordCheck(out)
+	if err != nil {
+		result.Error = newProbeError(ctx, ErrProbeFailed, "firmwarepasswd", "unexpected output: "+err.Error())
+		return
+	}
+	result.FirmwarePassword = &enabled
+	if enabled {
+		allowed := false
+		result.ExternalBootAllowed = &allowed
+		return
+	}
+	// T2 Macs disallow external boot by default in Startup Security Utility
+	if out, err := runCommand(ctx, "system_profiler", "SPiBridgeDataType"); err == nil && strings.Contains(string(out), "T2") {
+		result.Details = "Allowed Boot Media is set in Startup Security Utility and cannot be read from macOS"
+		return
+	}
+	allowed := true
+	result.ExternalBootAllowed = &allowed
+}
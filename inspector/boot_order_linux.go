@@ -0,0 +1,27 @@
+//go:build linux
+
+package inspector
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+)
+
+// platformBootOrder reads the boot order from efivarfs
+func platformBootOrder(ctx context.Context, result *BootOrderResult) {
+	root := os.DirFS("/")
+	if _, err := fs.Stat(root, "sys/firmware/efi"); err != nil {
+		result.Details = "System booted in Legacy BIOS mode; the boot order is kept by the BIOS"
+		return
+	}
+	const path = "/sys/firmware/efi/efivars/BootOrder-" + efiGlobalVariableGUID
+	if err := readBootEntries(efivarfsReader(root), result); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			result.Details = "The firmware has no BootOrder variable"
+			return
+		}
+		result.Error = classifyFileError(ctx, path, err)
+	}
+}
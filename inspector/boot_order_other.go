@@ -0,0 +1,6 @@
+//go:build !linux && !windows && !darwin
+
+package inspector
+
+// platformBootOrder is not implemented on this platform
+func platformBootOrder(ctx context.Context, result *BootOrderResult) {}
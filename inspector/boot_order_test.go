@@ -0,0 +1,141 @@
+package inspector
+
+import (
+	"encoding/binary"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"unicode/utf16"
+)
+
+// Device path nodes used to build test load options
+var (
+	pciNode  = []byte{0x01, 0x01, 0x06, 0x00, 0x00, 0x1c}
+	hdNode   = append([]byte{0x04, 0x01, 0x2a, 0x00}, make([]byte, 38)...)
+	usbNode  = []byte{0x03, 0x05, 0x06, 0x00, 0x00, 0x01}
+	macNode  = append([]byte{0x03, 0x0b, 0x25, 0x00}, make([]byte, 33)...)
+	ipv4Node = append([]byte{0x03, 0x0c, 0x1b, 0x00}, make([]byte, 23)...)
+	endNode  = []byte{0x7f, 0xff, 0x04, 0x00}
+	fvFile   = append([]byte{0x04, 0x06, 0x14, 0x00}, make([]byte, 16)...)
+	bbsCDROM = []byte{0x05, 0x01, 0x09, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00}
+)
+
+// loadOption encodes an EFI_LOAD_OPTION
+func loadOption(attributes uint32, desc string, nodes ...[]byte) []byte {
+	var path []byte
+	for _, n := range nodes {
+		path = append(path, n...)
+	}
+	path = append(path, endNode...)
+	data := binary.LittleEndian.AppendUint32(nil, attributes)
+	data = binary.LittleEndian.AppendUint16(data, uint16(len(path)))
+	for _, c := range utf16.Encode([]rune(desc)) {
+		data = binary.LittleEndian.AppendUint16(data, c)
+	}
+	data = append(data, 0, 0)
+	return append(data, path...)
+}
+
+// efivar prefixes data with the four attribute bytes efivarfs shows
+func efivar(data []byte) *fstest.MapFile {
+	return &fstest.MapFile{Data: append([]byte{0x07, 0x00, 0x00, 0x00}, data...)}
+}
+
+func TestParseLoadOption(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []byte
+		kind   string
+		active bool
+	}{
+		{"disk", loadOption(1, "ubuntu", hdNode), BootKindDisk, true},
+		{"usb partition", loadOption(1, "UEFI: SanDisk", pciNode, usbNode, hdNode), BootKindRemovable, true},
+		{"pxe", loadOption(1, "UEFI: PXE IPv4 Intel(R) Ethernet", pciNode, macNode, ipv4Node), BootKindNetwork, true},
+		{"legacy cdrom", loadOption(0, "CD/DVD Drive", bbsCDROM), BootKindRemovable, false},
+		{"vendor usb", loadOption(1, "USB HDD", fvFile), BootKindRemovable, true},
+		{"setup", loadOption(1, "Enter Setup", fvFile), BootKindOther, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := parseLoadOption(tt.data)
+			if !ok || entry.Kind != tt.kind || entry.Active != tt.active {
+				t.Errorf("parseLoadOption = %+v, %v, want kind %s, active %v", entry, ok, tt.kind, tt.active)
+			}
+		})
+	}
+	// Application entries such as the firmware's diagnostics are not boot options
+	if _, ok := parseLoadOption(loadOption(0x101, "Diagnostics", fvFile)); ok {
+		t.Error("an application load option should be skipped")
+	}
+	if _, ok := parseLoadOption([]byte{1, 0}); ok {
+		t.Error("a truncated load option should be skipped")
+	}
+}
+
+func TestReadBootEntries(t *testing.T) {
+	const dir = "sys/firmware/efi/efivars/"
+	const guid = "-" + efiGlobalVariableGUID
+	fsys := fstest.MapFS{
+		dir + "BootOrder" + guid:   efivar([]byte{0x02, 0x00, 0x01, 0x00, 0x00, 0x00, 0x03, 0x00}),
+		dir + "BootCurrent" + guid: efivar([]byte{0x01, 0x00}),
+		dir + "Boot0000" + guid:    efivar(loadOption(1, "Windows Boot Manager", hdNode)),
+		dir + "Boot0001" + guid:    efivar(loadOption(1, "ubuntu", hdNode)),
+		dir + "Boot0002" + guid:    efivar(loadOption(0, "UEFI: PXE IPv4", pciNode, macNode, ipv4Node)),
+	}
+	result := &BootOrderResult{}
+	if err := readBootEntries(efivarfsReader(fsys), result); err != nil {
+		t.Fatalf("readBootEntries failed: %v", err)
+	}
+	var numbers []string
+	for _, e := range result.Entries {
+		numbers = append(numbers, e.Number)
+	}
+	// Boot0003 is listed but missing
+	if !result.UEFI || result.BootCurrent != "0001" || !slices.Equal(numbers, []string{"0002", "0001", "0000"}) {
+		t.Errorf("result = %+v", result)
+	}
+
+	// The inactive PXE entry does not count
+	finishBootOrder(result)
+	if !result.Compliant || result.NetworkBeforeDisk {
+		t.Errorf("result = %+v, want compliant", result)
+	}
+	result.Entries[0].Active = true
+	finishBootOrder(result)
+	findings := bootOrderFindings(result)
+	if result.Compliant || !result.NetworkBeforeDisk || len(findings) != 1 || findings[0].ID != "boot_network_first" {
+		t.Errorf("findings = %+v, want boot_network_first", findings)
+	}
+}
+
+func TestFinishBootOrder_External(t *testing.T) {
+	result := &BootOrderResult{Entries: []BootEntry{
+		{Number: "0003", Kind: BootKindOther, Active: true},
+		{Number: "0004", Kind: BootKindRemovable, Active: true},
+		{Number: "0001", Kind: BootKindDisk, Active: true},
+		{Number: "0002", Kind: BootKindNetwork, Active: true},
+	}}
+	finishBootOrder(result)
+	if result.Compliant || !result.ExternalBeforeDisk || result.NetworkBeforeDisk {
+		t.Errorf("result = %+v, want removable media before the disk only", result)
+	}
+
+	allowed := true
+	result = &BootOrderResult{ExternalBootAllowed: &allowed}
+	finishBootOrder(result)
+	if f := bootOrderFindings(result); result.Compliant || len(f) != 1 || f[0].ID != "boot_external_allowed" {
+		t.Errorf("findings = %+v, want boot_external_allowed", f)
+	}
+}
+
+func TestParseFirmwarePasswordCheck(t *testing.T) {
+	if enabled, err := parseFirmwarePasswordCheck([]byte("Password Enabled: Yes\n")); err != nil || !enabled {
+		t.Errorf("parseFirmwarePasswordCheck = %v, %v, want enabled", enabled, err)
+	}
+	if enabled, err := parseFirmwarePasswordCheck([]byte("Password Enabled: No\n")); err != nil || enabled {
+		t.Errorf("parseFirmwarePasswordCheck = %v, %v, want disabled", enabled, err)
+	}
+	if _, err := parseFirmwarePasswordCheck([]byte("firmwarepasswd: must be run as root\n")); err == nil {
+		t.Error("unexpected output should be an error")
+	}
+}
@@ -0,0 +1,43 @@
+//go:build windows
+
+package inspector
+
+import (
+	"context"
+	"syscall"
+	"unsafe"
+)
+
+// platformBootOrder reads the boot order through
+// GetFirmwareEnvironmentVariableW, which needs the
+// SeSystemEnvironmentPrivilege held by elevated administrators
+func platformBootOrder(ctx context.Context, result *BootOrderResult) {
+	err := readBootEntries(readFirmwareEnvironmentVariable, result)
+	switch {
+	case err == nil:
+	case err == ERROR_INVALID_FUNCTION:
+		result.Details = "System booted in Legacy BIOS mode; the boot order is kept by the BIOS"
+	case err == ERROR_PRIVILEGE_NOT_HELD || err == syscall.ERROR_ACCESS_DENIED:
+		result.Error = newProbeError(ctx, ErrPermissionDenied, "GetFirmwareEnvironmentVariable",
+			"reading UEFI variables requires the SeSystemEnvironmentPrivilege")
+	default:
+		result.Error = newProbeError(ctx, ErrProbeFailed, "GetFirmwareEnvironmentVariable", err.Error())
+	}
+}
+
+// readFirmwareEnvironmentVariable reads a UEFI global variable
+func readFirmwareEnvironmentVariable(name string) ([]byte, error) {
+	buf := make([]byte, 4096)
+	varName, _ := syscall.UTF16PtrFromString(name)
+	guid, _ := syscall.UTF16PtrFromString("{" + efiGlobalVariableGUID + "}")
+	n, _, err := procGetFirmwareEnvironmentVar.Call(
+		uintptr(unsafe.Pointer(varName)),
+		uintptr(unsafe.Pointer(guid)),
+		uintptr(unsafe.Pointer(&buf[0])),
+		uintptr(len(buf)),
+	)
+	if n == 0 {
+		return nil, err
+	}
+	return buf[:n], nil
+}
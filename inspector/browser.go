@@ -0,0 +1,671 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Browsers reported in BrowserInfo.Browser
+const (
+	BrowserChrome  = "chrome"
+	BrowserEdge    = "edge"
+	BrowserFirefox = "firefox"
+	BrowserSafari  = "safari"
+)
+
+// Safe Browsing modes reported in BrowserInfo.SafeBrowsingMode
+const (
+	SafeBrowsingOff      = "off"
+	SafeBrowsingStandard = "standard"
+	SafeBrowsingEnhanced = "enhanced"
+)
+
+// Extension sources reported in BrowserExtension.Source
+const (
+	ExtensionSourceChromeWebStore = "chrome_web_store"
+	ExtensionSourceEdgeAddons     = "edge_addons"
+	ExtensionSourceFirefoxAddons  = "firefox_addons"
+	ExtensionSourcePolicy         = "policy"
+	ExtensionSourceUnpacked       = "unpacked"
+	ExtensionSourceOther          = "other"
+)
+
+// browserMaxVersionAge is how long a browser may keep the same version.
+// Chrome, Edge, and Firefox ship a release every four weeks, so a version
+// older than two cycles means updates are not being applied.
+const browserMaxVersionAge = 60 * 24 * time.Hour
+
+// BrowserExtension is an extension installed in a browser profile
+type BrowserExtension struct {
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
+	// Source is where the extension was installed from, e.g. "chrome_web_store"
+	// or "unpacked"
+	Source  string `json:"source"`
+	Enabled bool   `json:"enabled"`
+}
+
+// BrowserInfo reports the security settings of one browser profile
+type BrowserInfo struct {
+	Browser string `json:"browser"`
+	Name    string `json:"name"`
+	Profile string `json:"profile,omitempty"`
+	Version string `json:"version,omitempty"`
+	// VersionAgeDays is how long the browser has run this version, taken
+	// from when the browser last recorded a version change
+	VersionAgeDays int  `json:"version_age_days"`
+	Outdated       bool `json:"outdated"`
+	// SafeBrowsingMode is "off", "standard", or "enhanced" (Safe Browsing in
+	// Chrome and Firefox, SmartScreen in Edge, fraudulent website warnings in
+	// Safari)
+	SafeBrowsingMode string `json:"safe_browsing_mode"`
+	// HTTPSOnly is true if the browser upgrades or blocks plain HTTP
+	HTTPSOnly bool `json:"https_only"`
+	// InsecureDownloadsBlocked is unset if the browser has no such setting
+	InsecureDownloadsBlocked *bool              `json:"insecure_downloads_blocked,omitempty"`
+	Extensions               []BrowserExtension `json:"extensions"`
+	Error                    *ProbeError        `json:"error,omitempty"`
+}
+
+// BrowserSecurityResult reports the security settings of the installed
+// browsers for the current user
+type BrowserSecurityResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string        `json:"platform"`
+	User     string        `json:"user,omitempty"`
+	Browsers []BrowserInfo `json:"browsers"`
+	// Secure is true if every browser found is current and has Safe
+	// Browsing on; it is also true if no browser is installed
+	Secure bool `json:"secure"`
+}
+
+// browserLocation is where a browser keeps its profiles
+type browserLocation struct {
+	browser string
+	name    string
+	dir     string
+}
+
+// browserLocations returns the profile directories of each browser on goos
+func browserLocations(goos, home string, getenv func(string) string) []browserLocation {
+	switch goos {
+	case "darwin":
+		support := filepath.Join(home, "Library", "Application Support")
+		return []browserLocation{
+			{BrowserChrome, "Google Chrome", filepath.Join(support, "Google", "Chrome")},
+			{BrowserEdge, "Microsoft Edge", filepath.Join(support, "Microsoft Edge")},
+			{BrowserFirefox, "Firefox", filepath.Join(support, "Firefox", "Profiles")},
+		}
+	case "windows":
+		local, roaming := getenv("LOCALAPPDATA"), getenv("APPDATA")
+		if local == "" {
+			local = filepath.Join(home, "AppData", "Local")
+		}
+		if roaming == "" {
+			roaming = filepath.Join(home, "AppData", "Roaming")
+		}
+		return []browserLocation{
+			{BrowserChrome, "Google Chrome", filepath.Join(local, "Google", "Chrome", "User Data")},
+			{BrowserEdge, "Microsoft Edge", filepath.Join(local, "Microsoft", "Edge", "User Data")},
+			{BrowserFirefox, "Firefox", filepath.Join(roaming, "Mozilla", "Firefox", "Profiles")},
+		}
+	}
+	return []browserLocation{
+		{BrowserChrome, "Google Chrome", filepath.Join(home, ".config", "google-chrome")},
+		{BrowserEdge, "Microsoft Edge", filepath.Join(home, ".config", "microsoft-edge")},
+		{BrowserFirefox, "Firefox", filepath.Join(home, ".mozilla", "firefox")},
+		{BrowserFirefox, "Firefox (snap)", filepath.Join(home, "snap", "firefox", "common", ".mozilla", "firefox")},
+	}
+}
+
+// browserUser returns the user whose browsers are inspected and their home
+// directory. Under sudo that is the invoking user, not root.
+func browserUser() (name, home string, err error) {
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" && runtime.GOOS != "windows" {
+		if u, err := user.Lookup(sudoUser); err == nil {
+			return u.Username, u.HomeDir, nil
+		}
+	}
+	home, err = os.UserHomeDir()
+	if u, uerr := user.Current(); uerr == nil {
+		name = u.Username
+	}
+	return name, home, err
+}
+
+// GetBrowserSecurity inspects Chrome, Edge, Firefox, and Safari profiles of
+// the current user
+func GetBrowserSecurity() (*BrowserSecurityResult, error) {
+	return GetBrowserSecurityContext(context.Background())
+}
+
+// GetBrowserSecurityContext is like GetBrowserSecurity but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetBrowserSecurityContext(ctx context.Context) (*BrowserSecurityResult, error) {
+	if result, ok, err := loadFixture[BrowserSecurityResult](ctx, "browser"); ok {
+		return result, err
+	}
+	name, home, err := browserUser()
+	if err != nil {
+		return nil, newProbeError(ctx, ErrProbeFailed, "browser", "cannot find the home directory: "+err.Error())
+	}
+	result := &BrowserSecurityResult{Platform: runtime.GOOS, User: name, Browsers: []BrowserInfo{}}
+	now := time.Now()
+	for _, loc := range browserLocations(runtime.GOOS, home, os.Getenv) {
+		if _, err := os.Stat(loc.dir); err != nil {
+			continue
+		}
+		var profiles []BrowserInfo
+		if loc.browser == BrowserFirefox {
+			profiles = inspectFirefox(ctx, os.DirFS(loc.dir), now)
+		} else {
+			profiles = inspectChromium(ctx, os.DirFS(loc.dir), loc.browser, now)
+		}
+		for i := range profiles {
+			profiles[i].Name = loc.name
+		}
+		result.Browsers = append(result.Browsers, profiles...)
+	}
+	if runtime.GOOS == "darwin" {
+		if safari, ok := inspectSafari(ctx, home, now); ok {
+			result.Browsers = append(result.Browsers, safari)
+		}
+	}
+	result.Secure = browsersSecure(result.Browsers)
+	return result, nil
+}
+
+// browsersSecure reports whether every browser is current with Safe Browsing on
+func browsersSecure(browsers []BrowserInfo) bool {
+	for _, b := range browsers {
+		if b.Outdated || b.SafeBrowsingMode == SafeBrowsingOff {
+			return false
+		}
+	}
+	return true
+}
+
+// versionAge returns the age in days of a file recording the browser version
+func versionAge(fsys fs.FS, name string, now time.Time) (days int, outdated bool) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return 0, false
+	}
+	age := now.Sub(info.ModTime())
+	return int(age.Hours() / 24), age > browserMaxVersionAge
+}
+
+// jsonValue returns the value at a dotted path in decoded JSON, or nil
+func jsonValue(v any, path ...string) any {
+	for _, key := range path {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+// jsonBool returns the bool at a path, or def if it is not set
+func jsonBool(v any, def bool, path ...string) bool {
+	if b, ok := jsonValue(v, path...).(bool); ok {
+		return b
+	}
+	return def
+}
+
+// Chromium extension install locations (extensions::mojom::ManifestLocation)
+const (
+	chromiumLocationUnpacked          = 4
+	chromiumLocationComponent         = 5
+	chromiumLocationCommandLine       = 8
+	chromiumLocationExternalPolicy    = 9
+	chromiumLocationExternalComponent = 10
+	chromiumLocationPolicyDownload    = 7
+)
+
+// inspectChromium reads the profiles in a Chrome or Edge user data directory
+func inspectChromium(ctx context.Context, fsys fs.FS, browser string, now time.Time) []BrowserInfo {
+	var version string
+	if data, err := readFSFile(fsys, "Last Version"); err == nil {
+		version = strings.TrimSpace(string(data))
+	}
+	age, outdated := versionAge(fsys, "Last Version", now)
+
+	entries, _ := fs.ReadDir(fsys, ".")
+	var profiles []BrowserInfo
+	for _, e := range entries {
+		if !e.IsDir() || !fsExists(fsys, e.Name()+"/Preferences") {
+			continue
+		}
+		info := BrowserInfo{
+			Browser:        browser,
+			Profile:        e.Name(),
+			Version:        version,
+			VersionAgeDays: age,
+			Outdated:       outdated,
+			Extensions:     []BrowserExtension{},
+		}
+		prefs, err := readChromiumPrefs(fsys, e.Name())
+		if err != nil {
+			info.Error = newProbeError(ctx, ErrProbeFailed, browser, err.Error())
+		}
+		applyChromiumPrefs(&info, prefs)
+		profiles = append(profiles, info)
+	}
+	return profiles
+}
+
+// readChromiumPrefs merges a profile's Preferences and Secure Preferences;
+// newer versions keep extension settings in the latter
+func readChromiumPrefs(fsys fs.FS, profile string) (map[string]any, error) {
+	prefs := map[string]any{}
+	for _, name := range []string{"Preferences", "Secure Preferences"} {
+		data, err := readFSFile(fsys, profile+"/"+name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return prefs, err
+		}
+		var m map[string]any
+		if err := json.Unmarshal(data, &m); err != nil {
+			return prefs, fmt.Errorf("%s: %w", name, err)
+		}
+		mergeJSON(prefs, m)
+	}
+	return prefs, nil
+}
+
+// mergeJSON merges src into dst, recursing into objects
+func mergeJSON(dst, src map[string]any) {
+	for k, v := range src {
+		if sm, ok := v.(map[string]any); ok {
+			if dm, ok := dst[k].(map[string]any); ok {
+				mergeJSON(dm, sm)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+// applyChromiumPrefs reads the security settings from a profile's preferences
+func applyChromiumPrefs(info *BrowserInfo, prefs map[string]any) {
+	enabled := jsonBool(prefs, true, "safebrowsing", "enabled")
+	if info.Browser == BrowserEdge {
+		enabled = jsonBool(prefs, true, "smartscreen", "enabled")
+	}
+	switch {
+	case !enabled:
+		info.SafeBrowsingMode = SafeBrowsingOff
+	case jsonBool(prefs, false, "safebrowsing", "enhanced"):
+		info.SafeBrowsingMode = SafeBrowsingEnhanced
+	default:
+		info.SafeBrowsingMode = SafeBrowsingStandard
+	}
+	// HTTPS-First mode puts insecure downloads behind a warning
+	info.HTTPSOnly = jsonBool(prefs, false, "https_only_mode_enabled")
+	blocked := info.HTTPSOnly
+	info.InsecureDownloadsBlocked = &blocked
+
+	settings, _ := jsonValue(prefs, "extensions", "settings").(map[string]any)
+	ids := make([]string, 0, len(settings))
+	for id := range settings {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		s := settings[id]
+		location, _ := jsonValue(s, "location").(float64)
+		// Skip built-in components and settings left behind by uninstalled extensions
+		if location == chromiumLocationComponent || location == chromiumLocationExternalComponent ||
+			jsonValue(s, "manifest") == nil {
+			continue
+		}
+		ext := BrowserExtension{
+			ID:      id,
+			Source:  chromiumExtensionSource(int(location), s),
+			Enabled: chromiumExtensionEnabled(s),
+		}
+		ext.Name, _ = jsonValue(s, "manifest", "name").(string)
+		ext.Version, _ = jsonValue(s, "manifest", "version").(string)
+		info.Extensions = append(info.Extensions, ext)
+	}
+}
+
+// chromiumExtensionEnabled reports whether an extension is enabled. Older
+// versions record a state of 0 when disabled; newer ones list disable reasons.
+func chromiumExtensionEnabled(settings any) bool {
+	if state, ok := jsonValue(settings, "state").(float64); ok && state == 0 {
+		return false
+	}
+	switch reasons := jsonValue(settings, "disable_reasons").(type) {
+	case float64:
+		return reasons == 0
+	case []any:
+		return len(reasons) == 0
+	}
+	return true
+}
+
+// chromiumExtensionSource names where a Chrome or Edge extension came from
+func chromiumExtensionSource(location int, settings any) string {
+	switch location {
+	case chromiumLocationUnpacked, chromiumLocationCommandLine:
+		return ExtensionSourceUnpacked
+	case chromiumLocationExternalPolicy, chromiumLocationPolicyDownload:
+		return ExtensionSourcePolicy
+	}
+	updateURL, _ := jsonValue(settings, "manifest", "update_url").(string)
+	switch {
+	case strings.Contains(updateURL, "clients2.google.com/service/update2/crx"):
+		return ExtensionSourceChromeWebStore
+	case strings.Contains(updateURL, "edge.microsoft.com/extensionwebstorebase"):
+		return ExtensionSourceEdgeAddons
+	case jsonBool(settings, false, "from_webstore"):
+		return ExtensionSourceChromeWebStore
+	}
+	return ExtensionSourceOther
+}
+
+// firefoxPrefPattern matches a user_pref line in prefs.js
+var firefoxPrefPattern = regexp.MustCompile(`^user_pref\("([^"]+)",\s*(.+)\);`)
+
+// parseFirefoxPrefs returns the user preferences in a prefs.js file, with
+// values as written (true, false, numbers, or quoted strings)
+func parseFirefoxPrefs(data []byte) map[string]string {
+	prefs := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if m := firefoxPrefPattern.FindStringSubmatch(strings.TrimSpace(scanner.Text())); m != nil {
+			prefs[m[1]] = strings.TrimSpace(m[2])
+		}
+	}
+	return prefs
+}
+
+// firefoxExtensions is the part of extensions.json that is inspected
+type firefoxExtensions struct {
+	Addons []struct {
+		ID            string `json:"id"`
+		Type          string `json:"type"`
+		Version       string `json:"version"`
+		Location      string `json:"location"`
+		SourceURI     string `json:"sourceURI"`
+		Active        bool   `json:"active"`
+		DefaultLocale struct {
+			Name string `json:"name"`
+		} `json:"defaultLocale"`
+	} `json:"addons"`
+}
+
+// inspectFirefox reads the profiles in a Firefox profiles directory
+func inspectFirefox(ctx context.Context, fsys fs.FS, now time.Time) []BrowserInfo {
+	entries, _ := fs.ReadDir(fsys, ".")
+	var profiles []BrowserInfo
+	for _, e := range entries {
+		if !e.IsDir() || !fsExists(fsys, e.Name()+"/prefs.js") {
+			continue
+		}
+		info := BrowserInfo{Browser: BrowserFirefox, Profile: e.Name(), Extensions: []BrowserExtension{}}
+		if data, err := readFSFile(fsys, e.Name()+"/compatibility.ini"); err == nil {
+			info.Version = firefoxVersion(data)
+			info.VersionAgeDays, info.Outdated = versionAge(fsys, e.Name()+"/compatibility.ini", now)
+		}
+		data, err := readFSFile(fsys, e.Name()+"/prefs.js")
+		if err != nil {
+			info.Error = newProbeError(ctx, ErrProbeFailed, BrowserFirefox, err.Error())
+		}
+		applyFirefoxPrefs(&info, parseFirefoxPrefs(data))
+		if data, err := readFSFile(fsys, e.Name()+"/extensions.json"); err == nil {
+			info.Extensions = parseFirefoxExtensions(data)
+		}
+		profiles = append(profiles, info)
+	}
+	return profiles
+}
+
+// firefoxVersion reads LastVersion=128.0_20240704121409/... from compatibility.ini
+func firefoxVersion(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "LastVersion="); ok {
+			v, _, _ = strings.Cut(v, "_")
+			return v
+		}
+	}
+	return ""
+}
+
+// applyFirefoxPrefs reads the security settings from prefs.js; unset
+// preferences take Firefox's defaults
+func applyFirefoxPrefs(info *BrowserInfo, prefs map[string]string) {
+	off := func(name string) bool { return prefs[name] == "false" }
+	info.SafeBrowsingMode = SafeBrowsingStandard
+	if off("browser.safebrowsing.malware.enabled") || off("browser.safebrowsing.phishing.enabled") {
+		info.SafeBrowsingMode = SafeBrowsingOff
+	}
+	info.HTTPSOnly = prefs["dom.security.https_only_mode"] == "true"
+	blocked := !off("dom.block_download_insecure")
+	info.InsecureDownloadsBlocked = &blocked
+}
+
+// parseFirefoxExtensions lists the extensions in extensions.json, skipping
+// themes, dictionaries, and the extensions Firefox ships with
+func parseFirefoxExtensions(data []byte) []BrowserExtension {
+	var doc firefoxExtensions
+	exts := []BrowserExtension{}
+	if json.Unmarshal(data, &doc) != nil {
+		return exts
+	}
+	for _, a := range doc.Addons {
+		if a.Type != "extension" || strings.HasPrefix(a.Location, "app-system") || a.Location == "app-builtin" {
+			continue
+		}
+		source := ExtensionSourceOther
+		switch {
+		case strings.HasPrefix(a.SourceURI, "https://addons.mozilla.org/"):
+			source = ExtensionSourceFirefoxAddons
+		case a.Location == "temporary-addon":
+			source = ExtensionSourceUnpacked
+		case strings.Contains(a.Location, "policy") || strings.HasPrefix(a.Location, "app-system-share"):
+			source = ExtensionSourcePolicy
+		}
+		exts = append(exts, BrowserExtension{ID: a.ID, Name: a.DefaultLocale.Name, Version: a.Version, Source: source, Enabled: a.Active})
+	}
+	return exts
+}
+
+// inspectSafari reads Safari's version and fraudulent website warning.
+// Reading Safari's container requires Full Disk Access for the terminal.
+func inspectSafari(ctx context.Context, home string, now time.Time) (BrowserInfo, bool) {
+	const app = "/Applications/Safari.app/Contents/Info.plist"
+	appInfo, err := os.Stat(app)
+	if err != nil {
+		return BrowserInfo{}, false
+	}
+	info := BrowserInfo{Browser: BrowserSafari, Name: "Safari", Extensions: []BrowserExtension{}}
+	if out, err := runCommand(ctx, "plutil", "-convert", "xml1", "-o", "-", app); err == nil {
+		if root, err := decodePlist(out); err == nil {
+			dict, _ := root.(map[string]any)
+			info.Version = plistString(dict, "CFBundleShortVersionString")
+		}
+	}
+	age := now.Sub(appInfo.ModTime())
+	info.VersionAgeDays, info.Outdated = int(age.Hours()/24), age > browserMaxVersionAge
+
+	prefs := filepath.Join(home, "Library", "Containers", "com.apple.Safari", "Data", "Library", "Preferences", "com.apple.Safari.plist")
+	out, err := runCommand(ctx, "plutil", "-convert", "xml1", "-o", "-", prefs)
+	if err != nil {
+		info.Error = classifyExecError(ctx, "plutil", err)
+		info.SafeBrowsingMode = SafeBrowsingStandard
+		return info, true
+	}
+	root, err := decodePlist(out)
+	if err != nil {
+		info.Error = newProbeError(ctx, ErrProbeFailed, BrowserSafari, err.Error())
+	}
+	dict, _ := root.(map[string]any)
+	applySafariPrefs(&info, dict)
+	return info, true
+}
+
+// applySafariPrefs reads the fraudulent website warning, on unless turned off
+func applySafariPrefs(info *BrowserInfo, prefs map[string]any) {
+	info.SafeBrowsingMode = SafeBrowsingStandard
+	if warn, ok := prefs["WarnAboutFraudulentWebsites"].(bool); ok && !warn {
+		info.SafeBrowsingMode = SafeBrowsingOff
+	}
+}
+
+// browserFindings returns browsers that are outdated, have Safe Browsing
+// off, allow insecure downloads, or run extensions from outside a store
+func browserFindings(r *BrowserSecurityResult) []Finding {
+	var outdated, unsafe, downloads, sideloaded []string
+	for _, b := range r.Browsers {
+		name := b.Name
+		if b.Profile != "" && b.Profile != "Default" {
+			name += " (" + b.Profile + ")"
+		}
+		if b.Outdated {
+			outdated = append(outdated, name)
+		}
+		if b.SafeBrowsingMode == SafeBrowsingOff {
+			unsafe = append(unsafe, name)
+		}
+		if b.InsecureDownloadsBlocked != nil && !*b.InsecureDownloadsBlocked {
+			downloads = append(downloads, name)
+		}
+		if slices.ContainsFunc(b.Extensions, func(e BrowserExtension) bool {
+			return e.Enabled && (e.Source == ExtensionSourceUnpacked || e.Source == ExtensionSourceOther)
+		}) {
+			sideloaded = append(sideloaded, name)
+		}
+	}
+
+	var findings []Finding
+	add := func(id, title, severity, remediation string) {
+		findings = append(findings, Finding{
+			ID:          id,
+			Title:       title,
+			Severity:    severity,
+			Check:       CheckBrowser,
+			Remediation: remediation,
+		})
+	}
+	if len(unsafe) > 0 {
+		add("browser_safe_browsing_disabled", T("Safe Browsing is turned off in %s", strings.Join(unsafe, ", ")), SeverityHigh,
+			T("Turn on Safe Browsing (SmartScreen in Edge, fraudulent website warnings in Safari)"))
+	}
+	if len(outdated) > 0 {
+		add("browser_outdated", T("Browsers not updated in over 60 days: %s", strings.Join(outdated, ", ")), SeverityMedium,
+			T("Restart the browser to apply pending updates and check that automatic updates are on"))
+	}
+	if len(downloads) > 0 {
+		add("browser_insecure_downloads_allowed", T("Insecure downloads are not blocked in %s", strings.Join(downloads, ", ")), SeverityLow,
+			T("Turn on HTTPS-Only or HTTPS-First mode so downloads over plain HTTP are blocked"))
+	}
+	if len(sideloaded) > 0 {
+		add("browser_extension_not_from_store", T("Extensions not installed from a store are enabled in %s", strings.Join(sideloaded, ", ")), SeverityLow,
+			T("Review extensions that were sideloaded or loaded unpacked and remove the ones you do not need"))
+	}
+	return findings
+}
+
+// safeBrowsingStatus colors a Safe Browsing mode
+func safeBrowsingStatus(st Styler, mode string) string {
+	if mode == SafeBrowsingOff {
+		return st.Danger(IconCross + " " + mode)
+	}
+	return st.Success(IconCheck + " " + mode)
+}
+
+// FormatBrowserSecurityTable formats browser security settings as a colored table
+func FormatBrowserSecurityTable(result *BrowserSecurityResult) string {
+	return FormatBrowserSecurityTableWith(CurrentStyler(), result)
+}
+
+// FormatBrowserSecurityTableWith is like FormatBrowserSecurityTable but
+// styles the table with st
+func FormatBrowserSecurityTableWith(st Styler, result *BrowserSecurityResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " Browser Security"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	if len(result.Browsers) == 0 {
+		sb.WriteString(st.Muted("No Chrome, Edge, Firefox, or Safari profiles found"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	for _, b := range result.Browsers {
+		title := b.Name
+		if b.Profile != "" {
+			title += st.Muted(" (" + b.Profile + ")")
+		}
+		sb.WriteString(st.BoldText(title))
+		sb.WriteString("\n")
+		if b.Error != nil {
+			sb.WriteString(st.Warning(IconWarning + b.Error.Error()))
+			sb.WriteString("\n")
+		}
+		version := b.Version
+		if version == "" {
+			version = "unknown"
+		}
+		version += fmt.Sprintf(" (%d days)", b.VersionAgeDays)
+		if b.Outdated {
+			version = st.Danger(version)
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s\n", st.Muted(PadRight("Version:", 20)), version))
+		sb.WriteString(fmt.Sprintf("  %s %s\n", st.Muted(PadRight("Safe Browsing:", 20)), safeBrowsingStatus(st, b.SafeBrowsingMode)))
+		sb.WriteString(fmt.Sprintf("  %s %s\n", st.Muted(PadRight("HTTPS-Only:", 20)), st.BoolToStatusColored(b.HTTPSOnly)))
+		if b.InsecureDownloadsBlocked != nil {
+			sb.WriteString(fmt.Sprintf("  %s %s\n", st.Muted(PadRight("Insecure Downloads:", 20)), st.BoolToStatusColored(*b.InsecureDownloadsBlocked)))
+		}
+		for _, e := range b.Extensions {
+			name := e.Name
+			if name == "" {
+				name = e.ID
+			}
+			source := st.Muted(e.Source)
+			if e.Source == ExtensionSourceUnpacked || e.Source == ExtensionSourceOther {
+				source = st.Warning(e.Source)
+			}
+			if !e.Enabled {
+				name = st.Muted(name + " (disabled)")
+			}
+			sb.WriteString(fmt.Sprintf("    %s %s %s %s\n", IconArrow, name, st.Muted(e.Version), source))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// FormatBrowserSecurity formats browser security settings in the specified format
+func FormatBrowserSecurity(result *BrowserSecurityResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatBrowserSecurityTableWith(st, result)
+	}, format)
+}
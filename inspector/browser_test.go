@@ -0,0 +1,158 @@
+package inspector
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+var browserNow = time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+func TestInspectChromium(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Last Version":        {Data: []byte("141.0.7390.65\n"), ModTime: browserNow.Add(-90 * 24 * time.Hour)},
+		"Default/Preferences": {Data: []byte(`{"safebrowsing": {"enabled": true, "enhanced": true}, "https_only_mode_enabled": true}`)},
+		"Default/Secure Preferences": {Data: []byte(`{"extensions": {"settings": {
+			"aapbdbdomjkkjkaonfhkkikfgjllcleb": {"location": 1, "state": 1, "manifest": {"name": "Translate", "version": "2.0.14", "update_url": "https://clients2.google.com/service/update2/crx"}},
+			"mhjfbmdgcfjbbpaeojofohoefgiehjai": {"location": 5, "manifest": {"name": "PDF Viewer"}},
+			"dev0000000000000000000000000000a": {"location": 4, "disable_reasons": [], "manifest": {"name": "Local Dev"}},
+			"pol0000000000000000000000000000b": {"location": 9, "disable_reasons": 1, "manifest": {"name": "Corp SSO"}},
+			"gone000000000000000000000000000c": {"location": 1}
+		}}}`)},
+		"Profile 1/Preferences": {Data: []byte(`{"safebrowsing": {"enabled": false}}`)},
+		"Crashpad/settings.dat": {Data: []byte{}},
+	}
+
+	profiles := inspectChromium(context.Background(), fsys, BrowserChrome, browserNow)
+	if len(profiles) != 2 {
+		t.Fatalf("got %d profiles, want Default and Profile 1", len(profiles))
+	}
+	def := profiles[0]
+	if def.Version != "141.0.7390.65" || def.VersionAgeDays != 90 || !def.Outdated {
+		t.Errorf("version = %q, %d days, outdated %v", def.Version, def.VersionAgeDays, def.Outdated)
+	}
+	if def.SafeBrowsingMode != SafeBrowsingEnhanced || !def.HTTPSOnly || !*def.InsecureDownloadsBlocked {
+		t.Errorf("settings = %+v", def)
+	}
+	want := []BrowserExtension{
+		{ID: "aapbdbdomjkkjkaonfhkkikfgjllcleb", Name: "Translate", Version: "2.0.14", Source: ExtensionSourceChromeWebStore, Enabled: true},
+		{ID: "dev0000000000000000000000000000a", Name: "Local Dev", Source: ExtensionSourceUnpacked, Enabled: true},
+		{ID: "pol0000000000000000000000000000b", Name: "Corp SSO", Source: ExtensionSourcePolicy, Enabled: false},
+	}
+	if !slices.Equal(def.Extensions, want) {
+		t.Errorf("extensions = %+v, want %+v", def.Extensions, want)
+	}
+	if p := profiles[1]; p.Profile != "Profile 1" || p.SafeBrowsingMode != SafeBrowsingOff || *p.InsecureDownloadsBlocked {
+		t.Errorf("Profile 1 = %+v", p)
+	}
+}
+
+func TestApplyChromiumPrefsEdge(t *testing.T) {
+	info := BrowserInfo{Browser: BrowserEdge}
+	applyChromiumPrefs(&info, map[string]any{"smartscreen": map[string]any{"enabled": false}})
+	if info.SafeBrowsingMode != SafeBrowsingOff {
+		t.Errorf("Edge with SmartScreen off = %q", info.SafeBrowsingMode)
+	}
+	info = BrowserInfo{Browser: BrowserEdge}
+	applyChromiumPrefs(&info, map[string]any{})
+	if info.SafeBrowsingMode != SafeBrowsingStandard {
+		t.Errorf("Edge defaults = %q, want standard", info.SafeBrowsingMode)
+	}
+}
+
+func TestInspectFirefox(t *testing.T) {
+	fsys := fstest.MapFS{
+		"abcd1234.default-release/prefs.js": {Data: []byte(strings.Join([]string{
+			`// Mozilla User Preferences`,
+			`user_pref("browser.safebrowsing.malware.enabled", false);`,
+			`user_pref("dom.block_download_insecure", false);`,
+			`user_pref("browser.startup.homepage", "https://example.com");`,
+		}, "\n"))},
+		"abcd1234.default-release/compatibility.ini": {
+			Data:    []byte("[Compatibility]\nLastVersion=143.0.4_20251001000000/20251001000000\nLastOSABI=Linux_x86_64-gcc3\n"),
+			ModTime: browserNow.Add(-10 * 24 * time.Hour),
+		},
+		"abcd1234.default-release/extensions.json": {Data: []byte(`{"addons": [
+			{"id": "uBlock0@raymondhill.net", "type": "extension", "version": "1.66.0", "location": "app-profile", "sourceURI": "https://addons.mozilla.org/firefox/downloads/file/1/ublock.xpi", "active": true, "defaultLocale": {"name": "uBlock Origin"}},
+			{"id": "dev@example.com", "type": "extension", "version": "0.1", "location": "temporary-addon", "active": true, "defaultLocale": {"name": "Dev"}},
+			{"id": "formautofill@mozilla.org", "type": "extension", "location": "app-builtin", "active": true},
+			{"id": "default-theme@mozilla.org", "type": "theme", "location": "app-profile", "active": true}
+		]}`)},
+		"Crash Reports/InstallTime": {Data: []byte("1")},
+	}
+
+	profiles := inspectFirefox(context.Background(), fsys, browserNow)
+	if len(profiles) != 1 {
+		t.Fatalf("got %d profiles, want 1", len(profiles))
+	}
+	p := profiles[0]
+	if p.Version != "143.0.4" || p.VersionAgeDays != 10 || p.Outdated {
+		t.Errorf("version = %q, %d days, outdated %v", p.Version, p.VersionAgeDays, p.Outdated)
+	}
+	if p.SafeBrowsingMode != SafeBrowsingOff || p.HTTPSOnly || *p.InsecureDownloadsBlocked {
+		t.Errorf("settings = %+v", p)
+	}
+	var sources []string
+	for _, e := range p.Extensions {
+		sources = append(sources, e.ID+"="+e.Source)
+	}
+	if want := []string{"uBlock0@raymondhill.net=firefox_addons", "dev@example.com=unpacked"}; !slices.Equal(sources, want) {
+		t.Errorf("extensions = %v, want %v", sources, want)
+	}
+}
+
+func TestApplySafariPrefs(t *testing.T) {
+	var info BrowserInfo
+	applySafariPrefs(&info, map[string]any{"WarnAboutFraudulentWebsites": false})
+	if info.SafeBrowsingMode != SafeBrowsingOff {
+		t.Errorf("mode = %q, want off", info.SafeBrowsingMode)
+	}
+	applySafariPrefs(&info, nil)
+	if info.SafeBrowsingMode != SafeBrowsingStandard {
+		t.Errorf("mode = %q, want standard by default", info.SafeBrowsingMode)
+	}
+}
+
+func TestBrowserLocations(t *testing.T) {
+	getenv := func(k string) string { return map[string]string{"LOCALAPPDATA": `C:\Users\a\AppData\Local`}[k] }
+	locs := browserLocations("windows", `C:\Users\a`, getenv)
+	if len(locs) != 3 || !strings.HasSuffix(locs[0].dir, filepath.Join("Chrome", "User Data")) ||
+		!strings.Contains(locs[2].dir, filepath.Join("AppData", "Roaming")) {
+		t.Errorf("windows locations = %+v", locs)
+	}
+	if locs := browserLocations("linux", "/home/a", getenv); locs[0].dir != filepath.Join("/home/a", ".config", "google-chrome") {
+		t.Errorf("linux locations = %+v", locs)
+	}
+}
+
+func TestBrowserFindings(t *testing.T) {
+	allowed := false
+	r := &BrowserSecurityResult{Browsers: []BrowserInfo{
+		{Name: "Google Chrome", Profile: "Default", SafeBrowsingMode: SafeBrowsingStandard, Outdated: true,
+			Extensions: []BrowserExtension{{ID: "x", Source: ExtensionSourceUnpacked, Enabled: true}}},
+		{Name: "Firefox", Profile: "abcd.default", SafeBrowsingMode: SafeBrowsingOff, InsecureDownloadsBlocked: &allowed},
+	}}
+	var got []string
+	for _, f := range browserFindings(r) {
+		if f.Check != CheckBrowser {
+			t.Errorf("finding %s has check %q", f.ID, f.Check)
+		}
+		got = append(got, f.ID+": "+f.Title)
+	}
+	want := []string{
+		"browser_safe_browsing_disabled: Safe Browsing is turned off in Firefox (abcd.default)",
+		"browser_outdated: Browsers not updated in over 60 days: Google Chrome",
+		"browser_insecure_downloads_allowed: Insecure downloads are not blocked in Firefox (abcd.default)",
+		"browser_extension_not_from_store: Extensions not installed from a store are enabled in Google Chrome",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("findings = %q, want %q", got, want)
+	}
+	if browsersSecure(r.Browsers) || !browsersSecure(nil) {
+		t.Error("browsersSecure should fail on outdated or unprotected browsers and pass with none")
+	}
+}
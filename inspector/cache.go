@@ -0,0 +1,152 @@
+package inspector
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CachedInspector memoizes probe results for a fixed TTL, so an agent that
+// embeds the inspector can ask for slow probes (TPM, disk encryption, the
+// security summary) as often as it likes. It is safe for concurrent use.
+// Errors are never cached.
+type CachedInspector struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+	hits    uint64
+	misses  uint64
+	now     func() time.Time
+}
+
+// cacheEntry holds a cached probe result and when it was collected
+type cacheEntry struct {
+	value     any
+	fetchedAt time.Time
+}
+
+// CacheInfo describes whether a value came from the cache and how old it is
+type CacheInfo struct {
+	Hit bool
+	Age time.Duration
+}
+
+// CacheStats counts the lookups a CachedInspector has served
+type CacheStats struct {
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries"`
+	// HitRate is Hits over all lookups, 0 before the first lookup
+	HitRate float64 `json:"hit_rate"`
+}
+
+// NewCachedInspector creates a cache; a zero or negative TTL disables
+// caching, so every call runs the probe
+func NewCachedInspector(ttl time.Duration) *CachedInspector {
+	return &CachedInspector{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+		now:     time.Now,
+	}
+}
+
+// TTL returns how long results are reused
+func (c *CachedInspector) TTL() time.Duration {
+	return c.ttl
+}
+
+// Invalidate drops the cached results for the given keys, or every result
+// when no key is given
+func (c *CachedInspector) Invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(keys) == 0 {
+		clear(c.entries)
+		return
+	}
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+}
+
+// Stats returns the hit and miss counts since the cache was created
+func (c *CachedInspector) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := CacheStats{Hits: c.hits, Misses: c.misses, Entries: len(c.entries)}
+	if total := c.hits + c.misses; total > 0 {
+		s.HitRate = float64(c.hits) / float64(total)
+	}
+	return s
+}
+
+// Cached returns the result cached under key if it is younger than the
+// TTL, otherwise it calls fetch and caches the result. refresh skips the
+// cached result. A nil cache always calls fetch.
+func Cached[T any](c *CachedInspector, key string, refresh bool, fetch func() (T, error)) (T, CacheInfo, error) {
+	if c == nil || c.ttl <= 0 {
+		v, err := fetch()
+		return v, CacheInfo{}, err
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && !refresh {
+		age := c.now().Sub(entry.fetchedAt)
+		if v, ok := entry.value.(T); ok && age < c.ttl {
+			c.hits++
+			c.mu.Unlock()
+			return v, CacheInfo{Hit: true, Age: age}, nil
+		}
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	v, err := fetch()
+	if err != nil {
+		return v, CacheInfo{}, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = cacheEntry{value: v, fetchedAt: c.now()}
+	c.mu.Unlock()
+
+	return v, CacheInfo{}, nil
+}
+
+// TPMStatus returns GetTPMStatus, cached under CheckTPM
+func (c *CachedInspector) TPMStatus(ctx context.Context) (*TPMResult, error) {
+	v, _, err := Cached(c, CheckTPM, false, func() (*TPMResult, error) { return GetTPMStatusContext(ctx) })
+	return v, err
+}
+
+// SecureBootStatus returns GetSecureBootStatus, cached under CheckSecureBoot
+func (c *CachedInspector) SecureBootStatus(ctx context.Context) (*SecureBootResult, error) {
+	v, _, err := Cached(c, CheckSecureBoot, false, func() (*SecureBootResult, error) { return GetSecureBootStatusContext(ctx) })
+	return v, err
+}
+
+// EncryptionStatus returns GetEncryptionStatus, cached under CheckEncryption
+func (c *CachedInspector) EncryptionStatus(ctx context.Context) (*EncryptionResult, error) {
+	v, _, err := Cached(c, CheckEncryption, false, func() (*EncryptionResult, error) { return GetEncryptionStatusContext(ctx) })
+	return v, err
+}
+
+// BiometricCapabilities returns GetBiometricCapabilities, cached under
+// CheckBiometrics
+func (c *CachedInspector) BiometricCapabilities(ctx context.Context) (*BiometricCapabilities, error) {
+	v, _, err := Cached(c, CheckBiometrics, false, func() (*BiometricCapabilities, error) { return GetBiometricCapabilitiesContext(ctx) })
+	return v, err
+}
+
+// FirmwareStatus returns GetFirmwareStatus, cached under CheckFirmware
+func (c *CachedInspector) FirmwareStatus(ctx context.Context) (*FirmwareResult, error) {
+	v, _, err := Cached(c, CheckFirmware, false, func() (*FirmwareResult, error) { return GetFirmwareStatusContext(ctx) })
+	return v, err
+}
+
+// SecuritySummary returns GetSecuritySummary, cached under "summary"
+func (c *CachedInspector) SecuritySummary(ctx context.Context) (*SecuritySummary, error) {
+	v, _, err := Cached(c, "summary", false, func() (*SecuritySummary, error) { return GetSecuritySummaryContext(ctx) })
+	return v, err
+}
@@ -0,0 +1,144 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCached_HitWithinTTL(t *testing.T) {
+	c := NewCachedInspector(time.Minute)
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+
+	calls := 0
+	fetch := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	v, info, err := Cached(c, "k", false, fetch)
+	if err != nil || v != 1 || info.Hit {
+		t.Fatalf("first call = (%d, %+v, %v), want (1, miss, nil)", v, info, err)
+	}
+
+	now = now.Add(30 * time.Second)
+	v, info, err = Cached(c, "k", false, fetch)
+	if err != nil || v != 1 || !info.Hit {
+		t.Fatalf("second call = (%d, %+v, %v), want (1, hit, nil)", v, info, err)
+	}
+	if info.Age != 30*time.Second {
+		t.Errorf("Age = %v, want 30s", info.Age)
+	}
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+}
+
+func TestCached_ExpiredAndRefresh(t *testing.T) {
+	c := NewCachedInspector(time.Minute)
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+
+	calls := 0
+	fetch := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	_, _, _ = Cached(c, "k", false, fetch)
+
+	now = now.Add(2 * time.Minute)
+	v, info, _ := Cached(c, "k", false, fetch)
+	if v != 2 || info.Hit {
+		t.Errorf("expired entry = (%d, %+v), want (2, miss)", v, info)
+	}
+
+	v, info, _ = Cached(c, "k", true, fetch)
+	if v != 3 || info.Hit {
+		t.Errorf("refresh = (%d, %+v), want (3, miss)", v, info)
+	}
+}
+
+func TestCached_ErrorsNotCached(t *testing.T) {
+	c := NewCachedInspector(time.Minute)
+
+	calls := 0
+	fetch := func() (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, errors.New("boom")
+		}
+		return calls, nil
+	}
+
+	if _, _, err := Cached(c, "k", false, fetch); err == nil {
+		t.Fatal("expected error from first fetch")
+	}
+	v, info, err := Cached(c, "k", false, fetch)
+	if err != nil || v != 2 || info.Hit {
+		t.Errorf("after error = (%d, %+v, %v), want (2, miss, nil)", v, info, err)
+	}
+}
+
+func TestCached_Disabled(t *testing.T) {
+	c := NewCachedInspector(0)
+
+	calls := 0
+	fetch := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	_, _, _ = Cached(c, "k", false, fetch)
+	_, info, _ := Cached(c, "k", false, fetch)
+	if calls != 2 || info.Hit {
+		t.Errorf("disabled cache: calls = %d, hit = %v; want 2, false", calls, info.Hit)
+	}
+}
+
+func TestCachedInspector_InvalidateAndStats(t *testing.T) {
+	c := NewCachedInspector(time.Minute)
+	calls := 0
+	fetch := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	if s := c.Stats(); s.HitRate != 0 || s.Entries != 0 {
+		t.Errorf("empty Stats = %+v", s)
+	}
+	_, _, _ = Cached(c, "a", false, fetch)
+	_, _, _ = Cached(c, "a", false, fetch)
+	_, _, _ = Cached(c, "a", false, fetch)
+	_, _, _ = Cached(c, "b", false, fetch)
+	if s := c.Stats(); s.Hits != 2 || s.Misses != 2 || s.Entries != 2 || s.HitRate != 0.5 {
+		t.Errorf("Stats = %+v, want 2 hits, 2 misses, 2 entries", s)
+	}
+
+	c.Invalidate("a")
+	if v, info, _ := Cached(c, "a", false, fetch); info.Hit || v != 3 {
+		t.Errorf("after Invalidate(a) = (%d, %+v), want a fresh fetch", v, info)
+	}
+	if _, info, _ := Cached(c, "b", false, fetch); !info.Hit {
+		t.Error("Invalidate(a) dropped b")
+	}
+	c.Invalidate()
+	if s := c.Stats(); s.Entries != 0 {
+		t.Errorf("Entries after Invalidate() = %d, want 0", s.Entries)
+	}
+}
+
+func TestCachedInspector_Probes(t *testing.T) {
+	defer SetCommandRunner(SetCommandRunner(NewFakeRunner()))
+	c := NewCachedInspector(time.Minute)
+	first, err1 := c.EncryptionStatus(context.Background())
+	second, err2 := c.EncryptionStatus(context.Background())
+	if err1 != nil || err2 != nil {
+		t.Skipf("encryption probe failed: %v, %v", err1, err2)
+	}
+	if first != second || c.Stats().Hits != 1 {
+		t.Errorf("EncryptionStatus was not served from the cache: %+v", c.Stats())
+	}
+}
@@ -0,0 +1,27 @@
+package inspector
+
+import (
+	"strings"
+	"unicode"
+)
+
+// isIRCameraName reports whether a camera device name identifies an
+// infrared camera of the kind Windows Hello face recognition requires,
+// e.g. "Integrated IR Camera", "Windows Hello Face Software Device", or an
+// Intel RealSense depth camera. Ordinary RGB webcams do not qualify.
+func isIRCameraName(name string) bool {
+	lower := strings.ToLower(name)
+	if strings.Contains(lower, "infrared") || strings.Contains(lower, "hello face") ||
+		strings.Contains(lower, "realsense") {
+		return true
+	}
+	words := strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		if w == "ir" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package inspector
+
+import "testing"
+
+func TestIsIRCameraName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Integrated IR Camera", true},
+		{"HP IR Camera", true},
+		{"Microsoft IR Camera Front", true},
+		{"Windows Hello Face Software Device", true},
+		{"Intel(R) RealSense(TM) Camera SR300 Depth", true},
+		{"Infrared Camera", true},
+		{"Integrated Camera", false},
+		{"Logitech BRIO", false},
+		{"Intel(R) AVStream Camera", false},
+		{"Microsoft Camera Rear", false},
+	}
+
+	for _, tt := range tests {
+		if got := isIRCameraName(tt.name); got != tt.want {
+			t.Errorf("isIRCameraName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
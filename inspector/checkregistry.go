@@ -0,0 +1,230 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// registeredCheck is a security check that can be run by ID: by RunCheck,
+// SelfTest, and Doctor
+type registeredCheck struct {
+	id          string
+	description string
+	// supported reports whether the check exists on this platform; nil
+	// means everywhere
+	supported func() bool
+	run       func(ctx context.Context) (any, error)
+	format    func(result any, format string) string
+}
+
+// registerCheck adapts a typed probe and formatter to a registeredCheck
+func registerCheck[T any](id, description string, supported func() bool, run func(context.Context) (*T, error), format func(*T, string) string) registeredCheck {
+	return registeredCheck{
+		id:          id,
+		description: description,
+		supported:   supported,
+		run:         func(ctx context.Context) (any, error) { return run(ctx) },
+		format: func(result any, f string) string {
+			return format(result.(*T), f)
+		},
+	}
+}
+
+// checkRegistry lists the checks that can be run by ID, in summary order
+var checkRegistry = []registeredCheck{
+	registerCheck(CheckTPM, "Platform security chip: the TPM on Windows and Linux, the Secure Enclave on macOS",
+		IsTPMSupported, GetTPMStatusContext, FormatTPM),
+	registerCheck(CheckSecureBoot, "UEFI Secure Boot state and boot policy",
+		IsSecureBootSupported, GetSecureBootStatusContext, FormatSecureBoot),
+	registerCheck(CheckBootOrder, "Whether removable media or network boot comes before the system disk",
+		IsBootOrderSupported, GetBootOrderContext, FormatBootOrder),
+	registerCheck(CheckEncryption, "Full-disk encryption (BitLocker, FileVault, LUKS) of the system volumes",
+		IsEncryptionSupported, GetEncryptionStatusContext, FormatEncryption),
+	registerCheck(CheckBiometrics, "Biometric hardware and enrolled fingerprints or faces",
+		IsBiometricsSupported, GetBiometricCapabilitiesContext, FormatBiometricCapabilities),
+	registerCheck(CheckDefender, "Microsoft Defender real-time protection and signature age",
+		IsDefenderSupported, GetDefenderStatusContext, FormatDefender),
+	registerCheck(CheckUAC, "User Account Control and SmartScreen settings",
+		IsUACSupported, GetUACStatusContext, FormatUAC),
+	registerCheck(CheckLegacyProtocols, "SMBv1, NTLMv1, LLMNR, and NetBIOS",
+		IsLegacyProtocolsSupported, GetLegacyProtocolsContext, FormatLegacyProtocols),
+	registerCheck(CheckBrowser, "Browser versions, auto-update, and risky extensions",
+		nil, GetBrowserSecurityContext, FormatBrowserSecurity),
+	registerCheck(CheckDocker, "Docker daemon configuration and running containers",
+		nil, GetContainerSecurityContext, FormatContainerSecurity),
+	registerCheck(CheckKubelet, "Kubelet and container runtime socket of a Kubernetes node",
+		IsKubeletSupported, GetKubeletSecurityContext, FormatKubeletSecurity),
+	registerCheck(CheckUptime, "Uptime and whether a reboot is pending to finish installing updates",
+		nil, GetUptimeContext, FormatUptime),
+	registerCheck(CheckFirmware, "Pending and failed firmware updates",
+		nil, GetFirmwareStatusContext, FormatFirmwareStatus),
+	registerCheck(CheckManagementEngine, "Intel AMT provisioning and the Intel ME or AMD PSP state",
+		IsManagementEngineSupported, GetManagementEngineContext, FormatManagementEngine),
+	registerCheck(CheckUSBStorage, "Attached USB devices and the USB mass storage policy",
+		nil, GetUSBDevicesContext, FormatUSBDevices),
+	registerCheck(CheckPasskeys, "Platform authenticators set up to hold passkeys",
+		IsPasskeySupported, GetPasskeyStatusContext, FormatPasskeys),
+	registerCheck(CheckKeychain, "OS credential store and installed password managers",
+		IsKeychainSupported, GetKeychainContext, FormatKeychain),
+}
+
+// lookupCheck returns the registered check with the given ID
+func lookupCheck(id string) (registeredCheck, bool) {
+	id = normalizeCheckID(id)
+	for _, c := range checkRegistry {
+		if c.id == id {
+			return c, true
+		}
+	}
+	return registeredCheck{}, false
+}
+
+// isSupported reports whether the check exists on this platform
+func (c registeredCheck) isSupported() bool {
+	return c.supported == nil || c.supported()
+}
+
+// RegisteredChecks returns the IDs of the checks RunCheck accepts, in
+// summary order
+func RegisteredChecks() []string {
+	ids := make([]string, len(checkRegistry))
+	for i, c := range checkRegistry {
+		ids[i] = c.id
+	}
+	return ids
+}
+
+// CheckInfo describes a registered check
+type CheckInfo struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	// Domain is the posture domain the check is rolled up into, if it is
+	// scored
+	Domain    string `json:"domain,omitempty"`
+	Supported bool   `json:"supported"`
+	// Enabled is false when the check selection turns the check off
+	Enabled bool `json:"enabled"`
+}
+
+// CheckListResult lists the registered checks
+type CheckListResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string      `json:"platform"`
+	Checks   []CheckInfo `json:"checks"`
+}
+
+// ListChecks describes every registered check with whether it is supported
+// on this platform and enabled
+func ListChecks() *CheckListResult {
+	result := &CheckListResult{Platform: runtime.GOOS, Checks: []CheckInfo{}}
+	for _, c := range checkRegistry {
+		result.Checks = append(result.Checks, CheckInfo{
+			ID:          c.id,
+			Description: c.description,
+			Domain:      checkDomains[c.id],
+			Supported:   c.isSupported(),
+			Enabled:     CheckEnabled(c.id),
+		})
+	}
+	return result
+}
+
+// CheckSupported reports whether a registered check exists on this
+// platform; it is false for unknown IDs
+func CheckSupported(id string) bool {
+	c, ok := lookupCheck(id)
+	return ok && c.isSupported()
+}
+
+// RunCheck runs the registered check with the given ID under its configured
+// timeout and returns its result, which FormatCheckResult renders. Unknown,
+// unsupported, and disabled checks are errors.
+func RunCheck(id string) (any, error) {
+	return RunCheckContext(context.Background(), id)
+}
+
+// RunCheckContext is like RunCheck but runs the probes with ctx's deadline,
+// command runner, and logger
+func RunCheckContext(ctx context.Context, id string) (any, error) {
+	c, ok := lookupCheck(id)
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("unknown check %q (known checks: %s)", id, strings.Join(RegisteredChecks(), ", "))
+	case !c.isSupported():
+		return nil, newProbeError(ctx, ErrUnsupportedPlatform, c.id, fmt.Sprintf("%s is not supported on %s", c.id, runtime.GOOS))
+	case !CheckEnabled(c.id):
+		return nil, CheckDisabledError(c.id)
+	}
+	return withTimeout(ctx, CheckTimeout(c.id), c.id, c.run)
+}
+
+// FormatCheckResult formats a result of RunCheck in the specified format
+func FormatCheckResult(id string, result any, format string) string {
+	c, ok := lookupCheck(id)
+	if !ok {
+		return FormatOutputWith(result, func(st Styler) string { return fmt.Sprintf("%v\n", result) }, format)
+	}
+	return c.format(result, format)
+}
+
+// FormatCheckListTable formats the registered checks as a colored table
+func FormatCheckListTable(result *CheckListResult) string {
+	return FormatCheckListTableWith(CurrentStyler(), result)
+}
+
+// FormatCheckListTableWith is like FormatCheckListTable but styles the table
+// with st
+func FormatCheckListTableWith(st Styler, result *CheckListResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconInfo + " Registered Checks"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(st.TableTop(19, 12, 20))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight("Check", 19)),
+		st.Header(PadRight("Status", 12)),
+		st.Header(PadRight("Domain", 20)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(19, 12, 20))
+	sb.WriteString("\n")
+
+	for _, c := range result.Checks {
+		status := st.Success("available")
+		switch {
+		case !c.Supported:
+			status = st.Muted("unsupported")
+		case !c.Enabled:
+			status = st.Info("disabled")
+		}
+		sb.WriteString(st.TableRowColored(
+			PadRight(c.ID, 19),
+			PadRight(status, 12),
+			PadRight(st.Muted(c.Domain), 20),
+		))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.TableBottom(19, 12, 20))
+	sb.WriteString("\n")
+	for _, c := range result.Checks {
+		sb.WriteString(fmt.Sprintf("\n%s %s", st.BoldText(c.ID), st.Muted(c.Description)))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// FormatCheckList formats the registered checks in the specified format
+func FormatCheckList(result *CheckListResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatCheckListTableWith(st, result)
+	}, format)
+}
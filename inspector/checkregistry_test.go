@@ -0,0 +1,50 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckRegistry_CoversAllChecks(t *testing.T) {
+	registered := RegisteredChecks()
+	for _, id := range AllChecks {
+		if !slices.Contains(registered, id) {
+			t.Errorf("%s is not registered", id)
+		}
+	}
+	for _, c := range ListChecks().Checks {
+		if c.Description == "" {
+			t.Errorf("%s has no description", c.ID)
+		}
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	t.Setenv(FixtureEnv, filepath.Join("..", "fixtures", "linux"))
+	t.Setenv(OnlyChecksEnv, "")
+	t.Setenv(DisableChecksEnv, "")
+
+	result, err := RunCheckContext(context.Background(), "Uptime")
+	if err != nil {
+		t.Fatalf("RunCheck: %v", err)
+	}
+	uptime, ok := result.(*UptimeResult)
+	if !ok || !uptime.RebootPending {
+		t.Fatalf("result = %#v, want the uptime fixture", result)
+	}
+	if out := FormatCheckResult(CheckUptime, result, FormatJSON); !strings.Contains(out, `"reboot_pending": true`) {
+		t.Errorf("FormatCheckResult = %s", out)
+	}
+
+	if _, err := RunCheckContext(context.Background(), "nonexistent"); err == nil || !strings.Contains(err.Error(), CheckUptime) {
+		t.Errorf("unknown check error = %v, want the known checks", err)
+	}
+	t.Setenv(DisableChecksEnv, CheckUptime)
+	if _, err := RunCheckContext(context.Background(), CheckUptime); !errors.Is(err, ErrCheckDisabled) {
+		t.Errorf("disabled check error = %v", err)
+	}
+}
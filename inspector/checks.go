@@ -0,0 +1,234 @@
+package inspector
+
+import (
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Check IDs accepted by OMNITRUST_DISABLE_CHECKS and OMNITRUST_ONLY_CHECKS
+const (
+	CheckTPM        = "tpm"
+	CheckSecureBoot = "secure_boot"
+	// CheckBootOrder fails while removable media or network boot comes
+	// before the system disk, or a Mac can start up from external media
+	// without a firmware password
+	CheckBootOrder  = "boot_order"
+	CheckEncryption = "encryption"
+	CheckBiometrics = "biometrics"
+	CheckDefender   = "defender"
+	CheckUAC        = "uac"
+	// CheckLegacyProtocols covers SMBv1, NTLMv1, LLMNR, and NetBIOS
+	CheckLegacyProtocols = "legacy_protocols"
+	CheckBrowser         = "browser"
+	// CheckDocker covers the Docker daemon configuration and its containers
+	CheckDocker = "docker"
+	// CheckKubelet covers the kubelet and container runtime socket of a
+	// Kubernetes node
+	CheckKubelet = "kubelet"
+	// CheckUptime fails while a reboot is pending to finish installing updates
+	CheckUptime = "uptime"
+	// CheckFirmware fails while firmware updates are pending or the last
+	// firmware update failed
+	CheckFirmware = "firmware"
+	// CheckManagementEngine fails while Intel AMT is provisioned and
+	// listening, or the Intel ME or AMD PSP is in a debug or manufacturing
+	// state
+	CheckManagementEngine = "management_engine"
+	// CheckUSBStorage fails while USB mass storage is unrestricted; it is
+	// only scored when OMNITRUST_USB_STORAGE_POLICY is block
+	CheckUSBStorage = "usb_storage"
+	// CheckPasskeys fails while no platform authenticator (Windows Hello,
+	// iCloud Keychain) is set up to hold passkeys
+	CheckPasskeys = "passkeys"
+)
+
+// AllChecks lists every security check ID in summary order
+var AllChecks = []string{CheckTPM, CheckSecureBoot, CheckBootOrder, CheckEncryption, CheckBiometrics, CheckDefender, CheckUAC, CheckLegacyProtocols, CheckBrowser, CheckDocker, CheckKubelet, CheckUptime, CheckFirmware, CheckManagementEngine, CheckUSBStorage, CheckPasskeys}
+
+// checkPlatforms lists the platforms of checks that do not exist everywhere
+var checkPlatforms = map[string][]string{
+	CheckDefender: {"windows"},
+	CheckUAC:      {"windows"},
+
+	CheckLegacyProtocols: {"windows"},
+	CheckKubelet:         {"linux"},
+
+	CheckManagementEngine: {"linux", "windows"},
+	CheckPasskeys:         {"darwin", "windows"},
+}
+
+// Posture domains group related checks for domain-level rollups
+const (
+	DomainIdentity           = "identity"
+	DomainDataProtection     = "data_protection"
+	DomainBootIntegrity      = "boot_integrity"
+	DomainNetwork            = "network"
+	DomainEndpointProtection = "endpoint_protection"
+	DomainPatching           = "patching"
+)
+
+// AllDomains lists every posture domain in summary order
+var AllDomains = []string{DomainIdentity, DomainDataProtection, DomainBootIntegrity, DomainNetwork, DomainEndpointProtection, DomainPatching}
+
+// checkDomains maps each check to the domain it is rolled up into
+var checkDomains = map[string]string{
+	CheckBiometrics: DomainIdentity,
+	CheckUAC:        DomainIdentity,
+	CheckPasskeys:   DomainIdentity,
+
+	CheckEncryption: DomainDataProtection,
+	CheckUSBStorage: DomainDataProtection,
+
+	CheckTPM:              DomainBootIntegrity,
+	CheckSecureBoot:       DomainBootIntegrity,
+	CheckBootOrder:        DomainBootIntegrity,
+	CheckManagementEngine: DomainBootIntegrity,
+
+	CheckLegacyProtocols: DomainNetwork,
+	CheckDocker:          DomainNetwork,
+	CheckKubelet:         DomainNetwork,
+
+	CheckDefender: DomainEndpointProtection,
+	CheckBrowser:  DomainEndpointProtection,
+
+	CheckUptime:   DomainPatching,
+	CheckFirmware: DomainPatching,
+}
+
+// CheckDomain returns the posture domain of a check, or "" for an unknown ID
+func CheckDomain(id string) string {
+	return checkDomains[normalizeCheckID(id)]
+}
+
+// optInChecks are only run and scored when their setting asks for them
+var optInChecks = map[string]func() bool{
+	CheckUSBStorage: func() bool { return USBStoragePolicy() == USBStoragePolicyBlock },
+}
+
+// PlatformChecks returns the IDs of the checks that exist on this platform,
+// in summary order. Checks of other platforms are never scored or listed as
+// disabled, and neither are opt-in checks that are not turned on.
+func PlatformChecks() []string {
+	return checksFor(runtime.GOOS)
+}
+
+// checksFor returns the checks that exist on the given platform
+func checksFor(goos string) []string {
+	var checks []string
+	for _, id := range AllChecks {
+		if platforms, ok := checkPlatforms[id]; ok && !slices.Contains(platforms, goos) {
+			continue
+		}
+		if optIn, ok := optInChecks[id]; ok && !optIn() {
+			continue
+		}
+		checks = append(checks, id)
+	}
+	return checks
+}
+
+// Environment variables that trim the set of checks without a config file
+const (
+	DisableChecksEnv = "OMNITRUST_DISABLE_CHECKS"
+	OnlyChecksEnv    = "OMNITRUST_ONLY_CHECKS"
+)
+
+// CheckEnabled reports whether the check with the given ID should run.
+// OMNITRUST_PROFILE restricts checks to those of a scan profile and
+// OMNITRUST_ONLY_CHECKS (comma-separated) to the listed IDs;
+// OMNITRUST_DISABLE_CHECKS removes the listed IDs. All may be combined.
+func CheckEnabled(id string) bool {
+	id = normalizeCheckID(id)
+	if profile := scanProfileChecks(); len(profile) > 0 && !slices.Contains(profile, id) {
+		return false
+	}
+	if only := parseCheckList(os.Getenv(OnlyChecksEnv)); len(only) > 0 && !slices.Contains(only, id) {
+		return false
+	}
+	return !slices.Contains(parseCheckList(os.Getenv(DisableChecksEnv)), id)
+}
+
+// DisabledChecks returns the IDs of checks turned off via the environment
+func DisabledChecks() []string {
+	var disabled []string
+	for _, id := range PlatformChecks() {
+		if !CheckEnabled(id) {
+			disabled = append(disabled, id)
+		}
+	}
+	return disabled
+}
+
+// CheckDisabledError returns the error reported when a disabled check is requested
+func CheckDisabledError(id string) error {
+	return probeError(ErrCheckDisabled, id, "check is disabled")
+}
+
+// parseCheckList splits a comma- or space-separated list of check IDs
+func parseCheckList(v string) []string {
+	var ids []string
+	for _, f := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' }) {
+		ids = append(ids, normalizeCheckID(f))
+	}
+	return ids
+}
+
+// normalizeCheckID lowercases an ID and accepts "secure-boot" for "secure_boot"
+func normalizeCheckID(id string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(id)), "-", "_")
+}
+
+// Enforcement controls how a check's result affects the score and exit code
+type Enforcement string
+
+// Enforcement levels
+const (
+	// EnforcementInformational checks are reported but never affect the score or exit code
+	EnforcementInformational Enforcement = "informational"
+	// EnforcementStandard checks contribute to the score (the default)
+	EnforcementStandard Enforcement = "standard"
+	// EnforcementMandatory checks make the overall status critical if they fail
+	EnforcementMandatory Enforcement = "mandatory"
+)
+
+// Environment variables that set per-check enforcement (comma-separated check IDs)
+const (
+	InformationalChecksEnv = "OMNITRUST_INFORMATIONAL_CHECKS"
+	MandatoryChecksEnv     = "OMNITRUST_MANDATORY_CHECKS"
+)
+
+// CheckEnforcement returns the enforcement level configured for a check.
+// A check listed as both mandatory and informational is treated as mandatory.
+func CheckEnforcement(id string) Enforcement {
+	id = normalizeCheckID(id)
+	switch {
+	case slices.Contains(parseCheckList(os.Getenv(MandatoryChecksEnv)), id):
+		return EnforcementMandatory
+	case slices.Contains(parseCheckList(os.Getenv(InformationalChecksEnv)), id):
+		return EnforcementInformational
+	}
+	return EnforcementStandard
+}
+
+// CheckWeightsEnv sets relative scoring weights per check, e.g.
+// "encryption=3,biometrics=0.5". Unlisted checks weigh 1.
+const CheckWeightsEnv = "OMNITRUST_CHECK_WEIGHTS"
+
+// CheckWeight returns the scoring weight configured for a check. Invalid or
+// negative weights fall back to 1.
+func CheckWeight(id string) float64 {
+	id = normalizeCheckID(id)
+	for _, entry := range strings.FieldsFunc(os.Getenv(CheckWeightsEnv), func(r rune) bool { return r == ',' || r == ' ' }) {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || normalizeCheckID(key) != id {
+			continue
+		}
+		if w, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && w >= 0 {
+			return w
+		}
+	}
+	return 1
+}
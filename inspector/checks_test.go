@@ -0,0 +1,259 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckEnabled(t *testing.T) {
+	tests := []struct {
+		name    string
+		only    string
+		disable string
+		want    []string
+	}{
+		{"defaults", "", "", AllChecks},
+		{"disable", "", "biometrics, encryption", []string{CheckTPM, CheckSecureBoot, CheckBootOrder, CheckDefender, CheckUAC, CheckLegacyProtocols, CheckBrowser, CheckDocker, CheckKubelet, CheckUptime, CheckFirmware, CheckManagementEngine, CheckUSBStorage, CheckPasskeys}},
+		{"only", "tpm,secure-boot", "", []string{CheckTPM, CheckSecureBoot}},
+		{"only and disable", "TPM secure_boot", "secure_boot", []string{CheckTPM}},
+		{"unknown ignored", "", "nonexistent", AllChecks},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(OnlyChecksEnv, tt.only)
+			t.Setenv(DisableChecksEnv, tt.disable)
+
+			var got []string
+			for _, id := range AllChecks {
+				if CheckEnabled(id) {
+					got = append(got, id)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("enabled checks = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisabledChecks(t *testing.T) {
+	t.Setenv(OnlyChecksEnv, "")
+	t.Setenv(DisableChecksEnv, "tpm")
+	if got := DisabledChecks(); !slices.Equal(got, []string{CheckTPM}) {
+		t.Errorf("DisabledChecks = %v, want [tpm]", got)
+	}
+}
+
+func TestCheckDisabledError(t *testing.T) {
+	err := CheckDisabledError(CheckTPM)
+	if !errors.Is(err, ErrCheckDisabled) {
+		t.Errorf("errors.Is(%v, ErrCheckDisabled) = false, want true", err)
+	}
+	var pe *ProbeError
+	if !errors.As(err, &pe) || pe.Code != CodeCheckDisabled || pe.Hint == "" {
+		t.Errorf("CheckDisabledError = %+v, want code %q with hint", pe, CodeCheckDisabled)
+	}
+}
+
+func TestGetSecuritySummary_DisabledChecks(t *testing.T) {
+	t.Setenv(OnlyChecksEnv, "")
+	t.Setenv(DisableChecksEnv, strings.Join(AllChecks, ","))
+
+	result, err := GetSecuritySummaryContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetSecuritySummary failed: %v", err)
+	}
+	if result.TPM != nil || result.SecureBoot != nil || result.BootOrder != nil || result.Encryption != nil || result.Biometrics != nil || result.Defender != nil || result.Browsers != nil || result.Docker != nil || result.Kubelet != nil || result.Uptime != nil || result.Firmware != nil || result.ManagementEngine != nil || result.USB != nil || result.Passkeys != nil {
+		t.Error("disabled checks should not appear in the summary")
+	}
+	if !slices.Equal(result.DisabledChecks, PlatformChecks()) {
+		t.Errorf("DisabledChecks = %v, want %v", result.DisabledChecks, PlatformChecks())
+	}
+}
+
+func TestCheckEnforcement(t *testing.T) {
+	t.Setenv(MandatoryChecksEnv, "encryption, tpm")
+	t.Setenv(InformationalChecksEnv, "biometrics,tpm")
+
+	tests := map[string]Enforcement{
+		CheckEncryption: EnforcementMandatory,
+		CheckTPM:        EnforcementMandatory, // mandatory wins over informational
+		CheckBiometrics: EnforcementInformational,
+		CheckSecureBoot: EnforcementStandard,
+	}
+	for id, want := range tests {
+		if got := CheckEnforcement(id); got != want {
+			t.Errorf("CheckEnforcement(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestScoreChecks(t *testing.T) {
+	tests := []struct {
+		name          string
+		mandatory     string
+		informational string
+		weights       string
+		disabled      string
+		passed        map[string]bool
+		notApplicable map[string]string
+		wantScore     int
+		wantFailures  []string
+	}{
+		{
+			name:      "defaults",
+			passed:    map[string]bool{CheckTPM: true, CheckSecureBoot: true, CheckEncryption: false},
+			wantScore: 50,
+		},
+		{
+			name:          "informational excluded",
+			informational: "biometrics",
+			passed:        map[string]bool{CheckTPM: true, CheckSecureBoot: true, CheckEncryption: true, CheckBiometrics: false},
+			wantScore:     100,
+		},
+		{
+			name:         "mandatory failure",
+			mandatory:    "encryption,biometrics",
+			passed:       map[string]bool{CheckTPM: true, CheckSecureBoot: true, CheckEncryption: false},
+			wantScore:    50,
+			wantFailures: []string{CheckEncryption, CheckBiometrics},
+		},
+		{
+			name:          "all informational",
+			informational: "tpm,secure_boot,encryption,biometrics",
+			passed:        map[string]bool{},
+			wantScore:     NoScore,
+		},
+		{
+			name:          "not applicable in container",
+			mandatory:     "tpm",
+			passed:        map[string]bool{CheckEncryption: true},
+			notApplicable: map[string]string{CheckTPM: StatusNotApplicableInContainer, CheckSecureBoot: StatusNotApplicableInContainer, CheckBiometrics: StatusNotApplicableInContainer},
+			wantScore:     100,
+		},
+		{
+			name:      "disabled excluded",
+			mandatory: "biometrics",
+			disabled:  "encryption,biometrics",
+			passed:    map[string]bool{CheckTPM: true, CheckSecureBoot: true},
+			wantScore: 100,
+		},
+		{
+			name:      "weighted",
+			weights:   "encryption=3,biometrics=0",
+			passed:    map[string]bool{CheckTPM: true, CheckSecureBoot: true, CheckEncryption: false},
+			wantScore: 40,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(OnlyChecksEnv, "")
+			t.Setenv(DisableChecksEnv, tt.disabled)
+			t.Setenv(MandatoryChecksEnv, tt.mandatory)
+			t.Setenv(InformationalChecksEnv, tt.informational)
+			t.Setenv(CheckWeightsEnv, tt.weights)
+
+			checks := []string{CheckTPM, CheckSecureBoot, CheckEncryption, CheckBiometrics}
+			score, failures := scoreChecks(checks, tt.passed, tt.notApplicable)
+			if score != tt.wantScore {
+				t.Errorf("score = %d, want %d", score, tt.wantScore)
+			}
+			if !slices.Equal(failures, tt.wantFailures) {
+				t.Errorf("failures = %v, want %v", failures, tt.wantFailures)
+			}
+		})
+	}
+}
+
+func TestChecksFor(t *testing.T) {
+	t.Setenv(USBStoragePolicyEnv, "")
+	if got := checksFor("linux"); slices.Contains(got, CheckDefender) || slices.Contains(got, CheckUAC) || !slices.Contains(got, CheckKubelet) || len(got) != len(AllChecks)-5 {
+		t.Errorf("checksFor(linux) = %v", got)
+	}
+	if got := checksFor("windows"); slices.Contains(got, CheckKubelet) || len(got) != len(AllChecks)-2 {
+		t.Errorf("checksFor(windows) = %v", got)
+	}
+	if got := checksFor("darwin"); slices.Contains(got, CheckManagementEngine) {
+		t.Errorf("checksFor(darwin) = %v", got)
+	}
+	// The USB storage check only exists when the policy requires blocking
+	t.Setenv(USBStoragePolicyEnv, "Block")
+	if got := checksFor("darwin"); !slices.Contains(got, CheckUSBStorage) {
+		t.Errorf("checksFor(darwin) with the block policy = %v", got)
+	}
+	t.Setenv(USBStoragePolicyEnv, "")
+
+	// A Windows-only check counts on Windows and nowhere else
+	t.Setenv(MandatoryChecksEnv, "")
+	t.Setenv(InformationalChecksEnv, "")
+	t.Setenv(CheckWeightsEnv, "")
+	passed := map[string]bool{CheckTPM: true, CheckSecureBoot: true, CheckBootOrder: true, CheckEncryption: true, CheckBiometrics: true, CheckBrowser: true, CheckDocker: true, CheckKubelet: true, CheckUptime: true, CheckFirmware: true, CheckManagementEngine: true}
+	if score, _ := scoreChecks(checksFor("linux"), passed, nil); score != 100 {
+		t.Errorf("linux score = %d, want 100", score)
+	}
+	if score, _ := scoreChecks(checksFor("windows"), passed, nil); score != 71 {
+		t.Errorf("windows score without the Windows-only checks = %d, want 71", score)
+	}
+}
+
+func TestDomainSummaries(t *testing.T) {
+	for _, id := range AllChecks {
+		if CheckDomain(id) == "" {
+			t.Errorf("check %s has no domain", id)
+		}
+	}
+
+	t.Setenv(MandatoryChecksEnv, "tpm")
+	t.Setenv(InformationalChecksEnv, "biometrics")
+	t.Setenv(CheckWeightsEnv, "")
+	checks := []string{CheckTPM, CheckSecureBoot, CheckBootOrder, CheckEncryption, CheckBiometrics, CheckUptime, CheckDocker}
+	passed := map[string]bool{CheckTPM: false, CheckSecureBoot: true, CheckBootOrder: true, CheckEncryption: true, CheckBiometrics: false}
+	notApplicable := map[string]string{CheckUptime: StatusNotApplicableInContainer}
+	domains := domainSummaries(checks, passed, notApplicable)
+
+	var ids []string
+	for _, d := range domains {
+		ids = append(ids, d.ID)
+	}
+	if !slices.Equal(ids, []string{DomainIdentity, DomainDataProtection, DomainBootIntegrity, DomainNetwork, DomainPatching}) {
+		t.Fatalf("domains = %v", ids)
+	}
+	// Informational checks are listed as failed but leave the domain unscored
+	if d := domains[0]; d.Status != StatusNotScored || !slices.Equal(d.Failed, []string{CheckBiometrics}) {
+		t.Errorf("identity = %+v", d)
+	}
+	if d := domains[1]; d.Score != 100 || d.Status != "excellent" {
+		t.Errorf("data protection = %+v", d)
+	}
+	// A failed mandatory check makes its domain critical
+	if d := domains[2]; d.Score != 66 || d.Status != "critical" || !slices.Equal(d.Failed, []string{CheckTPM}) {
+		t.Errorf("boot integrity = %+v", d)
+	}
+	// Docker did not run
+	if d := domains[3]; d.Status != StatusNotScored || len(d.Failed) != 0 {
+		t.Errorf("network = %+v", d)
+	}
+	if d := domains[4]; d.Status != StatusNotScored {
+		t.Errorf("patching = %+v", d)
+	}
+}
+
+func TestCheckWeight(t *testing.T) {
+	t.Setenv(CheckWeightsEnv, "encryption=3, secure-boot=0.5,tpm=-1,biometrics=abc")
+	tests := map[string]float64{
+		CheckEncryption: 3,
+		CheckSecureBoot: 0.5,
+		CheckTPM:        1, // negative weights are ignored
+		CheckBiometrics: 1, // so are unparseable ones
+	}
+	for id, want := range tests {
+		if got := CheckWeight(id); got != want {
+			t.Errorf("CheckWeight(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
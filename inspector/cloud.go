@@ -0,0 +1,492 @@
+package inspector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cloud providers reported by GetCloudContext
+const (
+	CloudAWS   = "aws"
+	CloudAzure = "azure"
+	CloudGCP   = "gcp"
+)
+
+// CloudContext describes the cloud instance posture is running on
+type CloudContext struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	// Provider is aws, azure, or gcp; empty when not running in a known cloud
+	Provider     string `json:"provider,omitempty"`
+	InstanceID   string `json:"instance_id,omitempty"`
+	InstanceType string `json:"instance_type,omitempty"`
+	Region       string `json:"region,omitempty"`
+	Zone         string `json:"zone,omitempty"`
+	// IMDSv1Enabled reports that the AWS metadata service still answers
+	// unauthenticated requests (HttpTokens=optional), which exposes instance
+	// credentials to SSRF
+	IMDSv1Enabled bool `json:"imdsv1_enabled,omitempty"`
+	// VTPMEnabled reports a virtual TPM, from instance metadata (Azure) or
+	// the guest's TPM devices (Linux); it is unset when neither can tell
+	VTPMEnabled *bool `json:"vtpm_enabled,omitempty"`
+	// SecureBootEnabled is reported by Azure instance metadata only
+	SecureBootEnabled bool `json:"secure_boot_enabled,omitempty"`
+	// SecurityType is the Azure security type (Standard, TrustedLaunch, ConfidentialVM)
+	SecurityType string `json:"security_type,omitempty"`
+	// ConfidentialCompute is the memory encryption technology protecting the
+	// guest (sev, sev-snp, tdx), or empty
+	ConfidentialCompute string      `json:"confidential_compute,omitempty"`
+	Error               *ProbeError `json:"error,omitempty"`
+}
+
+// imdsEndpoint is the link-local instance metadata service; AWS, Azure, and
+// GCP all serve metadata at this address
+var imdsEndpoint = "http://169.254.169.254"
+
+// cloudProbeTimeout bounds metadata queries so non-cloud hosts are not slowed down
+const cloudProbeTimeout = 2 * time.Second
+
+// CloudProbeEnv turns the summary's cloud probe on (true) or off (false).
+// By default a summary only queries the instance metadata service when the
+// DMI/SMBIOS system vendor names a cloud, so that other machines never wait
+// on it; `posture cloud` always queries it.
+const CloudProbeEnv = "OMNITRUST_CLOUD_PROBE"
+
+// summaryProbesCloud reports whether a summary looks up the cloud instance
+func summaryProbesCloud(ctx context.Context) bool {
+	if probe, err := strconv.ParseBool(os.Getenv(CloudProbeEnv)); err == nil {
+		return probe
+	}
+	return FixtureDir() != "" || cloudVendorHint(ctx) != ""
+}
+
+// cloudVendorHint returns the cloud provider named by the system vendor in
+// DMI (Linux) or SMBIOS (Windows, and the hardware model on macOS), or ""
+func cloudVendorHint(ctx context.Context) string {
+	if runtime.GOOS == "linux" {
+		hint, _ := dmiCloudVendor(environmentRoot)
+		return hint
+	}
+	return cloudProductVendor(platformHypervisor(ctx).product)
+}
+
+// cloudProductVendor returns the cloud provider named by a system vendor
+// and model, or ""
+func cloudProductVendor(product string) string {
+	product = strings.ToLower(product)
+	switch {
+	case strings.Contains(product, "amazon"):
+		return CloudAWS
+	case strings.Contains(product, "google"):
+		return CloudGCP
+	// Azure VMs are Hyper-V guests; SMBIOS alone cannot tell them apart
+	case strings.Contains(product, "microsoft corporation virtual machine"):
+		return CloudAzure
+	}
+	return ""
+}
+
+// cloudHTTPClient never uses a proxy: metadata is only reachable directly
+var cloudHTTPClient = &http.Client{
+	Transport: &http.Transport{Proxy: nil},
+	Timeout:   cloudProbeTimeout,
+}
+
+// GetCloudContext identifies the cloud provider and instance through the
+// instance metadata service. On Linux, DMI data decides which provider to
+// query, and hosts whose DMI names no cloud vendor are not probed at all.
+func GetCloudContext(ctx context.Context) *CloudContext {
+	if cloud, ok := loadFixtureOr[CloudContext](ctx, "cloud"); ok {
+		return cloud
+	}
+	ctx, cancel := context.WithTimeout(ctx, cloudProbeTimeout)
+	defer cancel()
+
+	providers := []string{CloudAWS, CloudAzure, CloudGCP}
+	if runtime.GOOS == "linux" {
+		hint, ok := dmiCloudVendor(environmentRoot)
+		switch {
+		case ok && hint == "":
+			return &CloudContext{}
+		case hint != "":
+			providers = []string{hint}
+		}
+	}
+
+	// Query providers concurrently; at most one answers
+	results := make(chan *CloudContext, len(providers))
+	for _, p := range providers {
+		go func(provider string) {
+			switch provider {
+			case CloudAWS:
+				results <- awsCloudContext(ctx)
+			case CloudAzure:
+				results <- azureCloudContext(ctx)
+			default:
+				results <- gcpCloudContext(ctx)
+			}
+		}(p)
+	}
+	cc := &CloudContext{}
+	for range providers {
+		if r := <-results; r != nil && cc.Provider == "" {
+			cc = r
+		}
+	}
+
+	if cc.Provider != "" && runtime.GOOS == "linux" {
+		tpm, confidential := guestCloudFeatures(environmentRoot)
+		// A TPM device in the guest settles it; metadata may not know
+		if tpm != nil && (*tpm || cc.VTPMEnabled == nil) {
+			cc.VTPMEnabled = tpm
+		}
+		if cc.ConfidentialCompute == "" {
+			cc.ConfidentialCompute = confidential
+		}
+	}
+	return cc
+}
+
+// dmiCloudVendor returns the cloud provider named in /sys/class/dmi/id. ok is
+// false when DMI could not be read; hint is empty when DMI names no cloud.
+func dmiCloudVendor(root fs.FS) (hint string, ok bool) {
+	var fields []string
+	for _, name := range []string{"sys_vendor", "product_name", "bios_vendor", "bios_version", "chassis_asset_tag"} {
+		data, err := readFSFile(root, "sys/class/dmi/id/"+name)
+		if err != nil {
+			continue
+		}
+		ok = true
+		fields = append(fields, strings.ToLower(strings.TrimSpace(string(data))))
+	}
+	dmi := strings.Join(fields, "\n")
+	switch {
+	case strings.Contains(dmi, "amazon"):
+		return CloudAWS, ok
+	case strings.Contains(dmi, "google"):
+		return CloudGCP, ok
+	// Azure VMs carry a fixed chassis asset tag; Hyper-V guests elsewhere do not
+	case strings.Contains(dmi, "7783-7084-3265-9085-8269-3286-77"):
+		return CloudAzure, ok
+	}
+	return "", ok
+}
+
+// guestCloudFeatures reports a TPM device and confidential computing guest
+// devices visible inside a Linux guest. tpm is nil when the kernel exposes
+// no TPM class to look in, so whether the instance has one is unknown.
+func guestCloudFeatures(root fs.FS) (tpm *bool, confidential string) {
+	if fsExists(root, "sys/class/tpm") {
+		present := fsExists(root, "sys/class/tpm/tpm0")
+		tpm = &present
+	}
+	switch {
+	case fsExists(root, "dev/tdx_guest") || fsExists(root, "dev/tdx-guest"):
+		confidential = "tdx"
+	case fsExists(root, "dev/sev-guest"):
+		confidential = "sev-snp"
+	case fsExists(root, "dev/sev"):
+		confidential = "sev"
+	}
+	return tpm, confidential
+}
+
+// fsExists reports whether name exists in root
+func fsExists(root fs.FS, name string) bool {
+	_, err := fs.Stat(root, name)
+	return err == nil
+}
+
+// imdsRequest performs a metadata request and returns the body of a 200
+// response. Any other status is returned as an error.
+func imdsRequest(ctx context.Context, method, path string, header map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, imdsEndpoint+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := cloudHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	return body, nil
+}
+
+// awsCloudContext queries the EC2 metadata service with an IMDSv2 session
+// token, then checks whether tokenless IMDSv1 requests are still accepted
+func awsCloudContext(ctx context.Context) *CloudContext {
+	token, err := imdsRequest(ctx, http.MethodPut, "/latest/api/token",
+		map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": "60"})
+	if err != nil {
+		loggerFrom(ctx).Debug("AWS metadata service not available", "err", err)
+		return nil
+	}
+	doc, err := imdsRequest(ctx, http.MethodGet, "/latest/dynamic/instance-identity/document",
+		map[string]string{"X-aws-ec2-metadata-token": string(token)})
+	if err != nil {
+		loggerFrom(ctx).Warn("AWS metadata service issued a token but no identity document", "err", err)
+		return nil
+	}
+	cc, err := parseAWSIdentity(doc)
+	if err != nil {
+		return &CloudContext{Provider: CloudAWS, Error: newProbeError(ctx, ErrProbeFailed, "imds", "unexpected identity document: "+err.Error())}
+	}
+	// With HttpTokens=required, tokenless requests get 401
+	if _, err := imdsRequest(ctx, http.MethodGet, "/latest/meta-data/instance-id", nil); err == nil {
+		cc.IMDSv1Enabled = true
+	}
+	return cc
+}
+
+// parseAWSIdentity parses the EC2 instance identity document
+func parseAWSIdentity(data []byte) (*CloudContext, error) {
+	var doc struct {
+		InstanceID       string `json:"instanceId"`
+		InstanceType     string `json:"instanceType"`
+		Region           string `json:"region"`
+		AvailabilityZone string `json:"availabilityZone"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return &CloudContext{
+		Provider:     CloudAWS,
+		InstanceID:   doc.InstanceID,
+		InstanceType: doc.InstanceType,
+		Region:       doc.Region,
+		Zone:         doc.AvailabilityZone,
+	}, nil
+}
+
+// azureCloudContext queries the Azure Instance Metadata Service
+func azureCloudContext(ctx context.Context) *CloudContext {
+	data, err := imdsRequest(ctx, http.MethodGet, "/metadata/instance?api-version=2021-12-13",
+		map[string]string{"Metadata": "true"})
+	if err != nil {
+		loggerFrom(ctx).Debug("Azure metadata service not available", "err", err)
+		return nil
+	}
+	cc, err := parseAzureInstance(data)
+	if err != nil {
+		return &CloudContext{Provider: CloudAzure, Error: newProbeError(ctx, ErrProbeFailed, "imds", "unexpected instance metadata: "+err.Error())}
+	}
+	return cc
+}
+
+// parseAzureInstance parses Azure instance metadata. securityProfile values
+// are strings ("true"/"false").
+func parseAzureInstance(data []byte) (*CloudContext, error) {
+	var doc struct {
+		Compute struct {
+			VMID            string `json:"vmId"`
+			VMSize          string `json:"vmSize"`
+			Location        string `json:"location"`
+			Zone            string `json:"zone"`
+			SecurityProfile struct {
+				SecureBootEnabled string `json:"secureBootEnabled"`
+				VirtualTpmEnabled string `json:"virtualTpmEnabled"`
+				SecurityType      string `json:"securityType"`
+			} `json:"securityProfile"`
+		} `json:"compute"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	c := doc.Compute
+	cc := &CloudContext{
+		Provider:          CloudAzure,
+		InstanceID:        c.VMID,
+		InstanceType:      c.VMSize,
+		Region:            c.Location,
+		Zone:              c.Zone,
+		SecureBootEnabled: strings.EqualFold(c.SecurityProfile.SecureBootEnabled, "true"),
+		SecurityType:      c.SecurityProfile.SecurityType,
+	}
+	if vtpm, err := strconv.ParseBool(c.SecurityProfile.VirtualTpmEnabled); err == nil {
+		cc.VTPMEnabled = &vtpm
+	}
+	if strings.EqualFold(cc.SecurityType, "ConfidentialVM") {
+		cc.ConfidentialCompute = "sev-snp"
+	}
+	return cc, nil
+}
+
+// gcpCloudContext queries the GCE metadata server
+func gcpCloudContext(ctx context.Context) *CloudContext {
+	data, err := imdsRequest(ctx, http.MethodGet, "/computeMetadata/v1/instance/?recursive=true",
+		map[string]string{"Metadata-Flavor": "Google"})
+	if err != nil {
+		loggerFrom(ctx).Debug("GCE metadata server not available", "err", err)
+		return nil
+	}
+	cc, err := parseGCPInstance(data)
+	if err != nil {
+		return &CloudContext{Provider: CloudGCP, Error: newProbeError(ctx, ErrProbeFailed, "metadata", "unexpected instance metadata: "+err.Error())}
+	}
+	return cc
+}
+
+// parseGCPInstance parses recursive GCE instance metadata. Machine type and
+// zone are resource paths such as "projects/123/zones/us-central1-a".
+func parseGCPInstance(data []byte) (*CloudContext, error) {
+	var doc struct {
+		ID          json.Number `json:"id"`
+		MachineType string      `json:"machineType"`
+		Zone        string      `json:"zone"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	zone := lastPathElement(doc.Zone)
+	region := zone
+	if i := strings.LastIndexByte(zone, '-'); i > 0 {
+		region = zone[:i]
+	}
+	return &CloudContext{
+		Provider:     CloudGCP,
+		InstanceID:   doc.ID.String(),
+		InstanceType: lastPathElement(doc.MachineType),
+		Region:       region,
+		Zone:         zone,
+	}, nil
+}
+
+// lastPathElement returns the part of a resource path after the last slash
+func lastPathElement(p string) string {
+	return p[strings.LastIndexByte(p, '/')+1:]
+}
+
+// cloudFindings returns the findings about a cloud instance
+func cloudFindings(cc *CloudContext) []Finding {
+	if cc == nil || cc.Provider == "" {
+		return nil
+	}
+	var findings []Finding
+	if cc.IMDSv1Enabled {
+		f := Finding{
+			ID:          "cloud_imdsv1_enabled",
+			Title:       T("Instance metadata service accepts IMDSv1 requests"),
+			Severity:    SeverityHigh,
+			Check:       CheckCloud,
+			Remediation: T("Require IMDSv2 on this EC2 instance (HttpTokens=required) to block credential theft via SSRF"),
+		}
+		// Without the instance ID the command would not name an instance
+		if cc.InstanceID != "" {
+			f.RemediationCommand = "aws ec2 modify-instance-metadata-options --http-tokens required --instance-id " + cc.InstanceID
+		}
+		findings = append(findings, f)
+	}
+	// Only a vTPM known to be missing is a finding
+	if cc.VTPMEnabled != nil && !*cc.VTPMEnabled {
+		findings = append(findings, Finding{
+			ID:          "cloud_vtpm_missing",
+			Title:       T("No virtual TPM on this instance"),
+			Severity:    SeverityMedium,
+			Check:       CheckCloud,
+			Remediation: T("Enable a virtual TPM for this instance (NitroTPM, Trusted Launch, or Shielded VM)"),
+		})
+	}
+	return findings
+}
+
+// FormatCloudContextTable formats the cloud context as a colored table
+func FormatCloudContextTable(result *CloudContext) string {
+	return FormatCloudContextTableWith(CurrentStyler(), result)
+}
+
+// FormatCloudContextTableWith is like FormatCloudContextTable but styles the
+// table with st
+func FormatCloudContextTableWith(st Styler, result *CloudContext) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconInfo + " Cloud Context"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	if result.Provider == "" {
+		sb.WriteString(st.Muted("  Not running on a known cloud instance (AWS, Azure, GCP)"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	sb.WriteString(st.TableTop(20, 30))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight("Property", 20)),
+		st.Header(PadRight("Value", 30)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(20, 30))
+	sb.WriteString("\n")
+
+	row := func(name, value string) {
+		if value == "" {
+			return
+		}
+		sb.WriteString(st.TableRowColored(PadRight(name, 20), PadRight(value, 30)))
+		sb.WriteString("\n")
+	}
+	row("Provider", result.Provider)
+	row("Instance ID", result.InstanceID)
+	row("Instance Type", result.InstanceType)
+	row("Region", result.Region)
+	row("Zone", result.Zone)
+	row("Security Type", result.SecurityType)
+	if result.Provider == CloudAWS {
+		imds := st.Success("v2 required")
+		if result.IMDSv1Enabled {
+			imds = st.Danger(IconCross + " v1 enabled")
+		}
+		row("IMDS", imds)
+	}
+	vtpm := st.Muted("Unknown")
+	if result.VTPMEnabled != nil {
+		vtpm = st.BoolToStatusColored(*result.VTPMEnabled)
+	}
+	row("vTPM", vtpm)
+	if result.Provider == CloudAzure {
+		row("Secure Boot", st.BoolToStatusColored(result.SecureBootEnabled))
+	}
+	confidential := st.Muted("No")
+	if result.ConfidentialCompute != "" {
+		confidential = st.Success(IconCheck + " " + result.ConfidentialCompute)
+	}
+	row("Confidential", confidential)
+
+	sb.WriteString(st.TableBottom(20, 30))
+	sb.WriteString("\n")
+
+	if result.Error != nil {
+		sb.WriteString("\n")
+		sb.WriteString(formatProbeError(st, result.Error))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// FormatCloudContext formats the cloud context in the specified format
+func FormatCloudContext(result *CloudContext, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatCloudContextTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,201 @@
+package inspector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// fakeIMDS serves AWS, Azure, or GCP instance metadata, enforcing each
+// provider's required headers
+func fakeIMDS(t *testing.T, provider string, imdsv1 bool) {
+	t.Helper()
+	mux := http.NewServeMux()
+	switch provider {
+	case CloudAWS:
+		mux.HandleFunc("PUT /latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("TOKEN"))
+		})
+		mux.HandleFunc("GET /latest/dynamic/instance-identity/document", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-aws-ec2-metadata-token") != "TOKEN" {
+				http.Error(w, "", http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"accountId":"123456789012","availabilityZone":"eu-west-1b","instanceId":"i-0abc123","instanceType":"m7i.large","region":"eu-west-1"}`))
+		})
+		mux.HandleFunc("GET /latest/meta-data/instance-id", func(w http.ResponseWriter, r *http.Request) {
+			if !imdsv1 && r.Header.Get("X-aws-ec2-metadata-token") == "" {
+				http.Error(w, "", http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte("i-0abc123"))
+		})
+	case CloudAzure:
+		mux.HandleFunc("GET /metadata/instance", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Metadata") != "true" {
+				http.Error(w, "", http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"compute":{"vmId":"6c8f0b9e","vmSize":"Standard_DC4as_v5","location":"westeurope","zone":"2",
+				"securityProfile":{"secureBootEnabled":"true","virtualTpmEnabled":"true","securityType":"ConfidentialVM"}}}`))
+		})
+	case CloudGCP:
+		mux.HandleFunc("GET /computeMetadata/v1/instance/", func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Metadata-Flavor") != "Google" {
+				http.Error(w, "", http.StatusForbidden)
+				return
+			}
+			w.Write([]byte(`{"id":4567890123456789012,"machineType":"projects/123/machineTypes/n2d-standard-4","zone":"projects/123/zones/us-central1-a"}`))
+		})
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	prev := imdsEndpoint
+	t.Cleanup(func() { imdsEndpoint = prev })
+	imdsEndpoint = srv.URL
+}
+
+// stubDMI presents the given sys_vendor to GetCloudContext
+func stubDMI(t *testing.T, vendor string, extra fstest.MapFS) {
+	t.Helper()
+	prev := environmentRoot
+	t.Cleanup(func() { environmentRoot = prev })
+	root := fstest.MapFS{"sys/class/dmi/id/sys_vendor": {Data: []byte(vendor + "\n")}}
+	for k, v := range extra {
+		root[k] = v
+	}
+	environmentRoot = root
+}
+
+func TestGetCloudContext_AWS(t *testing.T) {
+	for _, imdsv1 := range []bool{true, false} {
+		fakeIMDS(t, CloudAWS, imdsv1)
+		stubDMI(t, "Amazon EC2", fstest.MapFS{"sys/class/tpm/tpm0": {}})
+
+		cc := GetCloudContext(context.Background())
+		if cc.Provider != CloudAWS || cc.InstanceID != "i-0abc123" || cc.InstanceType != "m7i.large" || cc.Zone != "eu-west-1b" {
+			t.Fatalf("cc = %+v", cc)
+		}
+		if cc.IMDSv1Enabled != imdsv1 {
+			t.Errorf("IMDSv1Enabled = %v, want %v", cc.IMDSv1Enabled, imdsv1)
+		}
+		if runtime.GOOS == "linux" && (cc.VTPMEnabled == nil || !*cc.VTPMEnabled) {
+			t.Error("NitroTPM device not reported as vTPM")
+		}
+	}
+}
+
+func TestGetCloudContext_Azure(t *testing.T) {
+	fakeIMDS(t, CloudAzure, false)
+	stubDMI(t, "Microsoft Corporation", fstest.MapFS{
+		"sys/class/dmi/id/chassis_asset_tag": {Data: []byte("7783-7084-3265-9085-8269-3286-77\n")},
+	})
+
+	cc := GetCloudContext(context.Background())
+	if cc.VTPMEnabled == nil || !*cc.VTPMEnabled {
+		t.Errorf("VTPMEnabled = %v, want true from the metadata", cc.VTPMEnabled)
+	}
+	cc.VTPMEnabled = nil
+	want := CloudContext{
+		Provider:            CloudAzure,
+		InstanceID:          "6c8f0b9e",
+		InstanceType:        "Standard_DC4as_v5",
+		Region:              "westeurope",
+		Zone:                "2",
+		SecureBootEnabled:   true,
+		SecurityType:        "ConfidentialVM",
+		ConfidentialCompute: "sev-snp",
+	}
+	if *cc != want {
+		t.Errorf("cc = %+v, want %+v", *cc, want)
+	}
+}
+
+func TestGetCloudContext_GCP(t *testing.T) {
+	fakeIMDS(t, CloudGCP, false)
+	stubDMI(t, "Google", fstest.MapFS{"dev/sev-guest": {}})
+
+	cc := GetCloudContext(context.Background())
+	if cc.Provider != CloudGCP || cc.InstanceID != "4567890123456789012" || cc.InstanceType != "n2d-standard-4" ||
+		cc.Region != "us-central1" || cc.Zone != "us-central1-a" {
+		t.Fatalf("cc = %+v", cc)
+	}
+	if runtime.GOOS == "linux" && cc.ConfidentialCompute != "sev-snp" {
+		t.Errorf("ConfidentialCompute = %q, want sev-snp", cc.ConfidentialCompute)
+	}
+	// Without a TPM class in sysfs, the vTPM is unknown rather than missing
+	if cc.VTPMEnabled != nil {
+		t.Errorf("VTPMEnabled = %v, want unknown", *cc.VTPMEnabled)
+	}
+}
+
+func TestSummaryProbesCloud(t *testing.T) {
+	t.Setenv(FixtureEnv, "")
+	t.Setenv(CloudProbeEnv, "true")
+	if !summaryProbesCloud(context.Background()) {
+		t.Error("OMNITRUST_CLOUD_PROBE=true should probe")
+	}
+	t.Setenv(CloudProbeEnv, "false")
+	stubDMI(t, "Amazon EC2", nil)
+	if summaryProbesCloud(context.Background()) {
+		t.Error("OMNITRUST_CLOUD_PROBE=false should not probe")
+	}
+	if runtime.GOOS != "linux" {
+		return
+	}
+	t.Setenv(CloudProbeEnv, "")
+	if !summaryProbesCloud(context.Background()) {
+		t.Error("an Amazon DMI vendor should probe")
+	}
+	stubDMI(t, "LENOVO", nil)
+	if summaryProbesCloud(context.Background()) {
+		t.Error("a non-cloud DMI vendor should not probe")
+	}
+}
+
+func TestGetCloudContext_NotCloud(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("DMI gating is Linux-only")
+	}
+	// Metadata must not be queried when DMI names no cloud vendor
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected metadata request %s %s", r.Method, r.URL)
+	}))
+	defer srv.Close()
+	prev := imdsEndpoint
+	defer func() { imdsEndpoint = prev }()
+	imdsEndpoint = srv.URL
+	stubDMI(t, "LENOVO", nil)
+
+	if cc := GetCloudContext(context.Background()); cc.Provider != "" {
+		t.Errorf("cc = %+v, want no provider", cc)
+	}
+}
+
+func TestCloudFindings(t *testing.T) {
+	absent, present := false, true
+	findings := cloudFindings(&CloudContext{Provider: CloudAWS, InstanceID: "i-0abc", IMDSv1Enabled: true, VTPMEnabled: &absent})
+	if len(findings) != 2 || findings[0].ID != "cloud_imdsv1_enabled" || findings[1].ID != "cloud_vtpm_missing" {
+		t.Fatalf("findings = %+v, want IMDSv2 and vTPM", findings)
+	}
+	if f := findings[0]; f.Severity != SeverityHigh || f.Check != CheckCloud || !strings.HasSuffix(f.RemediationCommand, "--instance-id i-0abc") {
+		t.Errorf("IMDSv1 finding = %+v", f)
+	}
+	// Without an instance ID there is no command to run
+	findings = cloudFindings(&CloudContext{Provider: CloudAWS, IMDSv1Enabled: true, VTPMEnabled: &present})
+	if len(findings) != 1 || findings[0].RemediationCommand != "" || findings[0].Remediation == "" {
+		t.Errorf("IMDSv1 finding without an instance ID = %+v, want remediation text only", findings)
+	}
+	if findings := cloudFindings(&CloudContext{Provider: CloudGCP, VTPMEnabled: &present}); len(findings) != 0 {
+		t.Errorf("findings = %+v, want none", findings)
+	}
+	// An unknown vTPM is not reported as missing
+	if findings := cloudFindings(&CloudContext{Provider: CloudGCP}); len(findings) != 0 {
+		t.Errorf("findings = %+v, want none for an unknown vTPM", findings)
+	}
+}
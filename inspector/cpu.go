@@ -3,25 +3,80 @@ package inspector
 import (
 	"context"
 	"fmt"
+	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/load"
+
+	"github.com/agentplexus/posture/render"
 )
 
+// DefaultCPUSampleInterval is the sampling window GetCPUUsage uses
+const DefaultCPUSampleInterval = 500 * time.Millisecond
+
+// MaxCPUSampleInterval caps the sampling window so a caller cannot block a
+// request indefinitely
+const MaxCPUSampleInterval = 10 * time.Second
+
 // CPUUsageResult contains CPU usage information
 type CPUUsageResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
 	UsagePercent float64   `json:"usage_percent"`
 	PerCore      []float64 `json:"per_core"`
+	// SampleIntervalMs is the window usage was measured over; 0 means usage
+	// since the previous call in this process (or since boot on the first)
+	SampleIntervalMs int64 `json:"sample_interval_ms"`
+	// SamplingWindow describes SampleIntervalMs in words
+	SamplingWindow string `json:"sampling_window"`
+	LogicalCores   int    `json:"logical_cores,omitempty"`
+	PhysicalCores  int    `json:"physical_cores,omitempty"`
+	// Load is the 1/5/15-minute load average (not available on Windows)
+	Load *LoadAverage `json:"load,omitempty"`
 }
 
-// GetCPUUsage returns current CPU usage
+// LoadAverage is the system load average
+type LoadAverage struct {
+	Load1  float64 `json:"load1"`
+	Load5  float64 `json:"load5"`
+	Load15 float64 `json:"load15"`
+}
+
+// GetCPUUsage returns CPU usage sampled over DefaultCPUSampleInterval
 func GetCPUUsage(ctx context.Context) (*CPUUsageResult, error) {
-	overall, err := cpu.PercentWithContext(ctx, 0, false)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get overall CPU usage: %w", err)
+	return GetCPUUsageWithInterval(ctx, DefaultCPUSampleInterval)
+}
+
+// GetCPUUsageWithInterval returns CPU usage sampled over interval, along
+// with core counts and load averages. An interval of 0 returns usage since
+// the previous call, which on the first call is the average since boot.
+func GetCPUUsageWithInterval(ctx context.Context, interval time.Duration) (*CPUUsageResult, error) {
+	if result, ok, err := loadFixture[CPUUsageResult](ctx, "cpu"); ok {
+		return result, err
+	}
+	if interval < 0 || interval > MaxCPUSampleInterval {
+		return nil, fmt.Errorf("CPU sampling interval must be between 0 and %s", MaxCPUSampleInterval)
 	}
 
-	perCore, err := cpu.PercentWithContext(ctx, 0, true)
+	// Sample overall and per-core usage over the same window
+	var (
+		wg         sync.WaitGroup
+		overall    []float64
+		overallErr error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		overall, overallErr = cpu.PercentWithContext(ctx, interval, false)
+	}()
+	perCore, err := cpu.PercentWithContext(ctx, interval, true)
+	wg.Wait()
+	if overallErr != nil {
+		return nil, fmt.Errorf("failed to get overall CPU usage: %w", overallErr)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get per-core CPU usage: %w", err)
 	}
@@ -31,68 +86,134 @@ func GetCPUUsage(ctx context.Context) (*CPUUsageResult, error) {
 		overallUsage = overall[0]
 	}
 
-	return &CPUUsageResult{
-		UsagePercent: overallUsage,
-		PerCore:      perCore,
-	}, nil
+	result := &CPUUsageResult{
+		UsagePercent:     overallUsage,
+		PerCore:          perCore,
+		SampleIntervalMs: interval.Milliseconds(),
+		SamplingWindow:   samplingWindow(interval),
+	}
+	result.LogicalCores, _ = cpu.CountsWithContext(ctx, true)
+	result.PhysicalCores, _ = cpu.CountsWithContext(ctx, false)
+
+	// gopsutil emulates load on Windows from a background sampler that reads
+	// zero until it has run for a minute, so it is not reported there
+	if runtime.GOOS != "windows" {
+		if avg, err := load.AvgWithContext(ctx); err == nil {
+			result.Load = &LoadAverage{Load1: avg.Load1, Load5: avg.Load5, Load15: avg.Load15}
+		}
+	}
+	return result, nil
+}
+
+// samplingWindow describes a sampling interval for display
+func samplingWindow(interval time.Duration) string {
+	if interval == 0 {
+		return "since previous sample (since boot on first call)"
+	}
+	return "sampled over " + interval.String()
 }
 
 // FormatCPUUsageTable formats CPU usage as a colored table
 func FormatCPUUsageTable(result *CPUUsageResult) string {
+	return FormatCPUUsageTableWith(CurrentStyler(), result)
+}
+
+// FormatCPUUsageTableWith is like FormatCPUUsageTable but styles the table
+// with st
+func FormatCPUUsageTableWith(st Styler, result *CPUUsageResult) string {
 	var sb strings.Builder
 	sb.WriteString("\n")
-	sb.WriteString(Header(IconCPU + " CPU Usage"))
+	sb.WriteString(st.Header(IconCPU + " " + T("CPU Usage")))
 	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 40)))
+	sb.WriteString(st.Muted(strings.Repeat("─", 40)))
 	sb.WriteString("\n\n")
 
 	// Overall usage with progress bar
-	sb.WriteString(BoldText("Overall: "))
-	usageColor := UsageColor(result.UsagePercent)
-	sb.WriteString(Colorize(usageColor+Bold, fmt.Sprintf("%.1f%%", result.UsagePercent)))
+	sb.WriteString(st.BoldText(T("Overall:") + " "))
+	usageStyle := render.UsageStyle(result.UsagePercent)
+	sb.WriteString(st.Styled(usageStyle, st.BoldText(fmt.Sprintf("%.1f%%", result.UsagePercent))))
+	sb.WriteString("\n")
+	sb.WriteString(st.ProgressBar(result.UsagePercent, 30))
 	sb.WriteString("\n")
-	sb.WriteString(ProgressBar(result.UsagePercent, 30))
+	sb.WriteString(st.Muted("  " + result.SamplingWindow))
 	sb.WriteString("\n\n")
 
+	if result.LogicalCores > 0 {
+		sb.WriteString(st.BoldText(T("Cores:") + " "))
+		sb.WriteString(T("%d logical", result.LogicalCores))
+		if result.PhysicalCores > 0 {
+			sb.WriteString(T(", %d physical", result.PhysicalCores))
+		}
+		sb.WriteString("\n")
+	}
+	if result.Load != nil {
+		sb.WriteString(st.BoldText(T("Load Average:") + " "))
+		sb.WriteString(formatLoad(st, result.Load.Load1, result.LogicalCores))
+		sb.WriteString(" ")
+		sb.WriteString(formatLoad(st, result.Load.Load5, result.LogicalCores))
+		sb.WriteString(" ")
+		sb.WriteString(formatLoad(st, result.Load.Load15, result.LogicalCores))
+		sb.WriteString(st.Muted("  " + T("(1, 5, 15 min)")))
+		sb.WriteString("\n")
+	}
+	if result.LogicalCores > 0 || result.Load != nil {
+		sb.WriteString("\n")
+	}
+
 	// Per-core table
-	sb.WriteString(BoldText("Per-Core Usage:"))
+	sb.WriteString(st.BoldText(T("Per-Core Usage:")))
 	sb.WriteString("\n")
-	sb.WriteString(TableTop(6, 10, 20))
+	sb.WriteString(st.TableTop(6, 10, 20))
 	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Core", 6)),
-		Header(PadLeft("Usage", 10)),
-		Header(PadRight("", 20)),
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Core"), 6)),
+		st.Header(PadLeft(T("Usage"), 10)),
+		st.Header(PadRight("", 20)),
 	))
 	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(6, 10, 20))
+	sb.WriteString(st.TableSeparator(6, 10, 20))
 	sb.WriteString("\n")
 
 	for i, usage := range result.PerCore {
 		var usageStr string
 		switch {
 		case usage >= 90:
-			usageStr = Danger(fmt.Sprintf("%6.1f%%", usage))
+			usageStr = st.Danger(fmt.Sprintf("%6.1f%%", usage))
 		case usage >= 70:
-			usageStr = Warning(fmt.Sprintf("%6.1f%%", usage))
+			usageStr = st.Warning(fmt.Sprintf("%6.1f%%", usage))
 		default:
-			usageStr = Success(fmt.Sprintf("%6.1f%%", usage))
+			usageStr = st.Success(fmt.Sprintf("%6.1f%%", usage))
 		}
-		sb.WriteString(TableRowColored(
-			Info(PadRight(fmt.Sprintf("%s %d", IconCore, i), 6)),
+		sb.WriteString(st.TableRowColored(
+			st.Info(PadRight(fmt.Sprintf("%s %d", IconCore, i), 6)),
 			PadLeft(usageStr, 10),
-			ProgressBar(usage, 20),
+			st.ProgressBar(usage, 20),
 		))
 		sb.WriteString("\n")
 	}
-	sb.WriteString(TableBottom(6, 10, 20))
+	sb.WriteString(st.TableBottom(6, 10, 20))
 	sb.WriteString("\n")
 	return sb.String()
 }
 
+// formatLoad colors a load average relative to the number of logical cores
+func formatLoad(st Styler, v float64, cores int) string {
+	s := fmt.Sprintf("%.2f", v)
+	if cores <= 0 {
+		return s
+	}
+	switch perCore := v / float64(cores); {
+	case perCore >= 1:
+		return st.Danger(s)
+	case perCore >= 0.7:
+		return st.Warning(s)
+	}
+	return st.Success(s)
+}
+
 // FormatCPUUsage formats CPU usage in the specified format
 func FormatCPUUsage(result *CPUUsageResult, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatCPUUsageTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatCPUUsageTableWith(st, result)
 	}, format)
 }
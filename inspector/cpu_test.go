@@ -3,6 +3,7 @@ package inspector
 import (
 	"context"
 	"encoding/json"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -184,3 +185,45 @@ func TestFormatCPUUsageTable_EmptyCores(t *testing.T) {
 		t.Error("Output should not be empty even with no cores")
 	}
 }
+
+func TestGetCPUUsageWithInterval(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := GetCPUUsageWithInterval(ctx, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("GetCPUUsageWithInterval failed: %v", err)
+	}
+	if result.SampleIntervalMs != 100 {
+		t.Errorf("SampleIntervalMs = %d, want 100", result.SampleIntervalMs)
+	}
+	if result.SamplingWindow != "sampled over 100ms" {
+		t.Errorf("SamplingWindow = %q", result.SamplingWindow)
+	}
+	if result.LogicalCores < 1 {
+		t.Errorf("LogicalCores = %d, want at least 1", result.LogicalCores)
+	}
+	if result.PhysicalCores > result.LogicalCores {
+		t.Errorf("PhysicalCores (%d) should not exceed LogicalCores (%d)", result.PhysicalCores, result.LogicalCores)
+	}
+	if runtime.GOOS == "linux" && result.Load == nil {
+		t.Error("Load should be reported on Linux")
+	}
+}
+
+func TestGetCPUUsageWithInterval_Invalid(t *testing.T) {
+	for _, interval := range []time.Duration{-time.Second, MaxCPUSampleInterval + time.Second} {
+		if _, err := GetCPUUsageWithInterval(context.Background(), interval); err == nil {
+			t.Errorf("interval %s should be rejected", interval)
+		}
+	}
+}
+
+func TestSamplingWindow(t *testing.T) {
+	if got := samplingWindow(0); !strings.Contains(got, "since boot") {
+		t.Errorf("samplingWindow(0) = %q, should mention boot", got)
+	}
+	if got := samplingWindow(2 * time.Second); got != "sampled over 2s" {
+		t.Errorf("samplingWindow(2s) = %q", got)
+	}
+}
@@ -0,0 +1,33 @@
+package inspector
+
+import "encoding/binary"
+
+// cpuidAvailable reports whether cpuidHypervisor reads the CPU
+const cpuidAvailable = true
+
+// cpuid executes the CPUID instruction (cpuid_amd64.s)
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// cpuidHypervisor reports the CPUID hypervisor-present bit (leaf 1, ECX bit
+// 31) and the hypervisor vendor signature from leaf 0x40000000. A Windows
+// host with Hyper-V or VBS enabled runs in the Hyper-V root partition and
+// sees the bit too; the root partition is reported as not virtualized.
+func cpuidHypervisor() (present bool, vendor string) {
+	_, _, ecx, _ := cpuid(1, 0)
+	if ecx&(1<<31) == 0 {
+		return false, ""
+	}
+	_, ebx, ecx, edx := cpuid(0x40000000, 0)
+	sig := make([]byte, 12)
+	binary.LittleEndian.PutUint32(sig[0:], ebx)
+	binary.LittleEndian.PutUint32(sig[4:], ecx)
+	binary.LittleEndian.PutUint32(sig[8:], edx)
+	vendor = string(sig)
+	if vendor == "Microsoft Hv" {
+		// Leaf 0x40000003 EBX bit 0 (CreatePartitions) is only granted to the root partition
+		if _, ebx, _, _ := cpuid(0x40000003, 0); ebx&1 != 0 {
+			return false, ""
+		}
+	}
+	return true, vendor
+}
@@ -0,0 +1,11 @@
+//go:build !amd64
+
+package inspector
+
+// cpuidAvailable reports whether cpuidHypervisor reads the CPU
+const cpuidAvailable = false
+
+// cpuidHypervisor is unavailable without x86 CPUID
+func cpuidHypervisor() (present bool, vendor string) {
+	return false, ""
+}
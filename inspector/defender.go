@@ -0,0 +1,321 @@
+package inspector
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Cloud protection levels reported in DefenderResult.CloudProtectionLevel
+// (the MAPSReporting preference)
+const (
+	CloudProtectionDisabled = "disabled"
+	CloudProtectionBasic    = "basic"
+	CloudProtectionAdvanced = "advanced"
+)
+
+// Attack surface reduction rule actions reported in ASRRule.Action
+const (
+	ASRActionDisabled = "disabled"
+	ASRActionBlock    = "block"
+	ASRActionAudit    = "audit"
+	ASRActionWarn     = "warn"
+)
+
+// defenderMaxSignatureAge is the age in days after which antivirus
+// signatures are outdated. Defender updates them several times a day.
+const defenderMaxSignatureAge = 7
+
+// defenderMaxScanAge is how long a machine may go without a completed scan
+const defenderMaxScanAge = 30 * 24 * time.Hour
+
+// ASRRule is an attack surface reduction rule and what it does when it fires
+type ASRRule struct {
+	ID     string `json:"id"`
+	Action string `json:"action"`
+}
+
+// DefenderResult reports the configuration of Microsoft Defender Antivirus
+type DefenderResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string `json:"platform"`
+	// Available is true if Defender's WMI provider answered
+	Available bool `json:"available"`
+	// RunningMode is "Normal", "Passive Mode" when another antivirus product
+	// is primary, or "EDR Block Mode"
+	RunningMode        string `json:"running_mode,omitempty"`
+	AntivirusEnabled   bool   `json:"antivirus_enabled"`
+	RealTimeProtection bool   `json:"real_time_protection"`
+	CloudProtection    bool   `json:"cloud_protection"`
+	// CloudProtectionLevel is empty if the preferences could not be read
+	CloudProtectionLevel string `json:"cloud_protection_level,omitempty"`
+	TamperProtection     bool   `json:"tamper_protection"`
+	SignatureVersion     string `json:"signature_version,omitempty"`
+	// SignatureAgeDays is the age of the antivirus signatures in days
+	SignatureAgeDays  int        `json:"signature_age_days"`
+	SignaturesUpdated *time.Time `json:"signatures_updated,omitempty"`
+	LastQuickScan     *time.Time `json:"last_quick_scan,omitempty"`
+	LastFullScan      *time.Time `json:"last_full_scan,omitempty"`
+	ASRRules          []ASRRule  `json:"asr_rules,omitempty"`
+	// ASRRulesEnabled counts the attack surface reduction rules in block or
+	// warn mode
+	ASRRulesEnabled int `json:"asr_rules_enabled"`
+	// Protected is true if the antivirus and real-time protection are on and
+	// the signatures are current, or if Defender is passive because another
+	// antivirus product is primary
+	Protected bool        `json:"protected"`
+	Error     *ProbeError `json:"error,omitempty"`
+}
+
+// MSFT_MpComputerStatus represents the Defender WMI status class
+type MSFT_MpComputerStatus struct {
+	AMRunningMode                 string
+	AntivirusEnabled              bool
+	RealTimeProtectionEnabled     bool
+	IsTamperProtected             bool
+	AntivirusSignatureVersion     string
+	AntivirusSignatureAge         uint32
+	AntivirusSignatureLastUpdated time.Time
+	QuickScanEndTime              time.Time
+	FullScanEndTime               time.Time
+}
+
+// MSFT_MpPreference represents the Defender WMI preference class
+type MSFT_MpPreference struct {
+	MAPSReporting                       uint8
+	AttackSurfaceReductionRules_Ids     []string
+	AttackSurfaceReductionRules_Actions []uint8
+}
+
+// newDefenderResult interprets Defender's WMI status and preferences. With
+// no preferences, cloud protection and ASR rules are left unknown.
+func newDefenderResult(status MSFT_MpComputerStatus, pref *MSFT_MpPreference) *DefenderResult {
+	r := &DefenderResult{
+		Platform:           "windows",
+		Available:          true,
+		RunningMode:        status.AMRunningMode,
+		AntivirusEnabled:   status.AntivirusEnabled,
+		RealTimeProtection: status.RealTimeProtectionEnabled,
+		TamperProtection:   status.IsTamperProtected,
+		SignatureVersion:   status.AntivirusSignatureVersion,
+		SignatureAgeDays:   int(status.AntivirusSignatureAge),
+		SignaturesUpdated:  wmiTime(status.AntivirusSignatureLastUpdated),
+		LastQuickScan:      wmiTime(status.QuickScanEndTime),
+		LastFullScan:       wmiTime(status.FullScanEndTime),
+	}
+
+	// In passive mode another antivirus product protects the machine, which
+	// Defender cannot vouch for; its own settings are then moot
+	r.Protected = r.passive() ||
+		r.AntivirusEnabled && r.RealTimeProtection && r.SignatureAgeDays <= defenderMaxSignatureAge
+	if pref == nil {
+		return r
+	}
+
+	switch pref.MAPSReporting {
+	case 0:
+		r.CloudProtectionLevel = CloudProtectionDisabled
+	case 1:
+		r.CloudProtectionLevel = CloudProtectionBasic
+	default:
+		r.CloudProtectionLevel = CloudProtectionAdvanced
+	}
+	r.CloudProtection = r.CloudProtectionLevel != CloudProtectionDisabled
+
+	for i, id := range pref.AttackSurfaceReductionRules_Ids {
+		var action uint8
+		if i < len(pref.AttackSurfaceReductionRules_Actions) {
+			action = pref.AttackSurfaceReductionRules_Actions[i]
+		}
+		rule := ASRRule{ID: strings.ToLower(id), Action: asrAction(action)}
+		if rule.Action == ASRActionBlock || rule.Action == ASRActionWarn {
+			r.ASRRulesEnabled++
+		}
+		r.ASRRules = append(r.ASRRules, rule)
+	}
+	return r
+}
+
+// passive reports whether another antivirus product is primary
+func (r *DefenderResult) passive() bool {
+	return strings.HasPrefix(strings.ToLower(r.RunningMode), "passive")
+}
+
+// asrAction names an attack surface reduction rule action
+func asrAction(action uint8) string {
+	switch action {
+	case 1:
+		return ASRActionBlock
+	case 2:
+		return ASRActionAudit
+	case 6:
+		return ASRActionWarn
+	}
+	return ASRActionDisabled
+}
+
+// wmiTime returns nil for an unset WMI datetime
+func wmiTime(t time.Time) *time.Time {
+	if t.IsZero() || t.Year() <= 1601 {
+		return nil
+	}
+	return &t
+}
+
+// lastScan returns the most recent completed scan, or nil if none ran
+func (r *DefenderResult) lastScan() *time.Time {
+	if r.LastFullScan != nil && (r.LastQuickScan == nil || r.LastFullScan.After(*r.LastQuickScan)) {
+		return r.LastFullScan
+	}
+	return r.LastQuickScan
+}
+
+// defenderFindings returns the problems in a Defender configuration
+func defenderFindings(r *DefenderResult, now time.Time) []Finding {
+	if r.Error != nil {
+		return []Finding{unverifiedFinding("defender_unverified", CheckDefender, "Microsoft Defender", r.Error)}
+	}
+	if r.passive() {
+		return nil
+	}
+	var findings []Finding
+	add := func(id, title, severity, remediation string) {
+		findings = append(findings, Finding{
+			ID:                 id,
+			Title:              title,
+			Severity:           severity,
+			Check:              CheckDefender,
+			Remediation:        remediation,
+			RemediationCommand: remediationCommand(id),
+		})
+	}
+	if !r.AntivirusEnabled {
+		add("defender_antivirus_disabled", T("Microsoft Defender Antivirus is turned off"), SeverityHigh,
+			T("Turn on Microsoft Defender Antivirus, or make sure another antivirus product is active"))
+		return findings
+	}
+	if !r.RealTimeProtection {
+		add("defender_realtime_disabled", T("Real-time protection is turned off"), SeverityHigh,
+			T("Turn on real-time protection in Windows Security"))
+	}
+	if r.SignatureAgeDays > defenderMaxSignatureAge {
+		add("defender_signatures_outdated", T("Antivirus signatures are %d days old", r.SignatureAgeDays), SeverityMedium,
+			T("Update the antivirus signatures and check that Windows Update can reach Microsoft"))
+	}
+	if !r.TamperProtection {
+		add("defender_tamper_protection_disabled", T("Tamper protection is turned off"), SeverityMedium,
+			T("Turn on tamper protection in Windows Security or through Intune"))
+	}
+	if r.CloudProtectionLevel == CloudProtectionDisabled {
+		add("defender_cloud_protection_disabled", T("Cloud-delivered protection is turned off"), SeverityLow,
+			T("Turn on cloud-delivered protection for faster detection of new threats"))
+	}
+	if r.CloudProtectionLevel != "" && r.ASRRulesEnabled == 0 {
+		add("defender_asr_not_configured", T("No attack surface reduction rules are enforced"), SeverityLow,
+			T("Enable attack surface reduction rules in block mode through Intune or Group Policy"))
+	}
+	if last := r.lastScan(); last == nil || now.Sub(*last) > defenderMaxScanAge {
+		add("defender_scan_overdue", T("No antivirus scan has completed in the last 30 days"), SeverityLow,
+			T("Run a quick scan and check the scheduled scan settings"))
+	}
+	return findings
+}
+
+// scanAge describes when a scan last completed
+func scanAge(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// FormatDefenderTable formats Defender status as a colored table
+func FormatDefenderTable(result *DefenderResult) string {
+	return FormatDefenderTableWith(CurrentStyler(), result)
+}
+
+// FormatDefenderTableWith is like FormatDefenderTable but styles the table
+// with st
+func FormatDefenderTableWith(st Styler, result *DefenderResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " Microsoft Defender"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(st.Danger(IconCross + " " + result.Error.Error()))
+		sb.WriteString("\n")
+		if result.Error.Hint != "" {
+			sb.WriteString(st.Muted("  " + result.Error.Hint))
+			sb.WriteString("\n")
+		}
+		return sb.String()
+	}
+
+	sb.WriteString(st.TableTop(28, 24))
+	sb.WriteString("\n")
+	row := func(label, value string) {
+		sb.WriteString(st.TableRowColored(PadRight(label, 28), PadRight(value, 24)))
+		sb.WriteString("\n")
+	}
+	row("Running Mode", Truncate(result.RunningMode, 24))
+	row("Antivirus", st.BoolToStatusColored(result.AntivirusEnabled))
+	row("Real-time Protection", st.BoolToStatusColored(result.RealTimeProtection))
+	row("Tamper Protection", st.BoolToStatusColored(result.TamperProtection))
+	if result.CloudProtectionLevel != "" {
+		row("Cloud Protection", st.BoolToStatusColored(result.CloudProtection)+st.Muted(" "+result.CloudProtectionLevel))
+	} else {
+		row("Cloud Protection", st.Muted("unknown"))
+	}
+	signatures := fmt.Sprintf("%d days old", result.SignatureAgeDays)
+	if result.SignatureAgeDays > defenderMaxSignatureAge {
+		signatures = st.Danger(signatures)
+	} else {
+		signatures = st.Success(signatures)
+	}
+	row("Signatures", signatures)
+	row("Last Quick Scan", scanAge(result.LastQuickScan))
+	row("Last Full Scan", scanAge(result.LastFullScan))
+	if result.CloudProtectionLevel != "" {
+		row("ASR Rules Enforced", fmt.Sprintf("%d of %d", result.ASRRulesEnabled, len(result.ASRRules)))
+	} else {
+		row("ASR Rules Enforced", st.Muted("unknown"))
+	}
+	sb.WriteString(st.TableBottom(28, 24))
+	sb.WriteString("\n")
+
+	if len(result.ASRRules) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText("Attack Surface Reduction Rules:"))
+		sb.WriteString("\n")
+		for _, rule := range result.ASRRules {
+			action := st.Muted(rule.Action)
+			switch rule.Action {
+			case ASRActionBlock, ASRActionWarn:
+				action = st.Success(rule.Action)
+			case ASRActionAudit:
+				action = st.Info(rule.Action)
+			}
+			sb.WriteString(fmt.Sprintf("  %s %s %s\n", IconArrow, rule.ID, action))
+		}
+	}
+
+	sb.WriteString("\n")
+	if result.Protected {
+		sb.WriteString(st.Success(IconCheck + " Defender is protecting this machine"))
+	} else {
+		sb.WriteString(st.Danger(IconCross + " Defender is not fully protecting this machine"))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// FormatDefender formats Defender status in the specified format
+func FormatDefender(result *DefenderResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatDefenderTableWith(st, result)
+	}, format)
+}
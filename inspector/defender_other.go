@@ -0,0 +1,24 @@
+//go:build !windows
+
+package inspector
+
+import "context"
+
+// GetDefenderStatus returns an error on platforms other than Windows
+func GetDefenderStatus() (*DefenderResult, error) {
+	return GetDefenderStatusContext(context.Background())
+}
+
+// GetDefenderStatusContext is like GetDefenderStatus but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetDefenderStatusContext(ctx context.Context) (*DefenderResult, error) {
+	if result, ok, err := loadFixture[DefenderResult](ctx, "defender"); ok {
+		return result, err
+	}
+	return nil, newProbeError(ctx, ErrUnsupportedPlatform, "defender", "Microsoft Defender is only checked on Windows")
+}
+
+// IsDefenderSupported returns false on platforms other than Windows
+func IsDefenderSupported() bool {
+	return false
+}
@@ -0,0 +1,113 @@
+package inspector
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewDefenderResult(t *testing.T) {
+	scanned := time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC)
+	status := MSFT_MpComputerStatus{
+		AMRunningMode:             "Normal",
+		AntivirusEnabled:          true,
+		RealTimeProtectionEnabled: true,
+		IsTamperProtected:         true,
+		AntivirusSignatureAge:     1,
+		QuickScanEndTime:          scanned,
+		FullScanEndTime:           time.Date(1601, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	pref := &MSFT_MpPreference{
+		MAPSReporting:                       1,
+		AttackSurfaceReductionRules_Ids:     []string{"D4F940AB-401B-4EFC-AADC-AD5F3C50688A", "3B576869-A4EC-4529-8536-B80A7769E899"},
+		AttackSurfaceReductionRules_Actions: []uint8{1, 2},
+	}
+
+	r := newDefenderResult(status, pref)
+	if !r.Protected || r.CloudProtectionLevel != CloudProtectionBasic || !r.CloudProtection {
+		t.Errorf("result = %+v", r)
+	}
+	if r.ASRRulesEnabled != 1 || r.ASRRules[0].ID != "d4f940ab-401b-4efc-aadc-ad5f3c50688a" || r.ASRRules[1].Action != ASRActionAudit {
+		t.Errorf("ASR rules = %+v, enabled %d", r.ASRRules, r.ASRRulesEnabled)
+	}
+	if r.LastQuickScan == nil || !r.LastQuickScan.Equal(scanned) || r.LastFullScan != nil {
+		t.Errorf("scans = %v, %v; the 1601 epoch means never", r.LastQuickScan, r.LastFullScan)
+	}
+
+	status.AntivirusSignatureAge = 12
+	if newDefenderResult(status, pref).Protected {
+		t.Error("outdated signatures should not count as protected")
+	}
+	status.RealTimeProtectionEnabled = false
+	status.AMRunningMode = "Passive Mode"
+	if !newDefenderResult(status, pref).Protected {
+		t.Error("passive mode defers to another antivirus product")
+	}
+	if r := newDefenderResult(status, nil); r.CloudProtectionLevel != "" || r.ASRRules != nil {
+		t.Errorf("without preferences, cloud protection and ASR should be unknown: %+v", r)
+	}
+}
+
+func TestDefenderFindings(t *testing.T) {
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	recent := now.Add(-24 * time.Hour)
+	healthy := &DefenderResult{
+		AntivirusEnabled: true, RealTimeProtection: true, TamperProtection: true,
+		CloudProtection: true, CloudProtectionLevel: CloudProtectionAdvanced,
+		ASRRulesEnabled: 3, SignatureAgeDays: 0, LastQuickScan: &recent,
+	}
+	if f := defenderFindings(healthy, now); len(f) != 0 {
+		t.Errorf("healthy Defender has findings: %+v", f)
+	}
+
+	ids := func(r *DefenderResult) []string {
+		var out []string
+		for _, f := range defenderFindings(r, now) {
+			if f.Check != CheckDefender {
+				t.Errorf("%s has check %q", f.ID, f.Check)
+			}
+			out = append(out, f.ID)
+		}
+		return out
+	}
+	weak := *healthy
+	weak.RealTimeProtection = false
+	weak.TamperProtection = false
+	weak.SignatureAgeDays = 30
+	weak.CloudProtectionLevel = CloudProtectionDisabled
+	weak.ASRRulesEnabled = 0
+	weak.LastQuickScan = nil
+	want := []string{
+		"defender_realtime_disabled", "defender_signatures_outdated", "defender_tamper_protection_disabled",
+		"defender_cloud_protection_disabled", "defender_asr_not_configured", "defender_scan_overdue",
+	}
+	if got := ids(&weak); !slices.Equal(got, want) {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+
+	off := DefenderResult{}
+	if got := ids(&off); !slices.Equal(got, []string{"defender_antivirus_disabled"}) {
+		t.Errorf("antivirus off: findings = %v", got)
+	}
+	passive := DefenderResult{RunningMode: "Passive Mode"}
+	if got := ids(&passive); len(got) != 0 {
+		t.Errorf("passive mode: findings = %v", got)
+	}
+	failed := DefenderResult{Error: newProbeError(context.Background(), ErrPermissionDenied, "defender", "access denied querying WMI")}
+	if got := ids(&failed); !slices.Equal(got, []string{"defender_unverified"}) {
+		t.Errorf("probe error: findings = %v", got)
+	}
+}
+
+func TestFormatDefenderTable(t *testing.T) {
+	r := newDefenderResult(MSFT_MpComputerStatus{AMRunningMode: "Normal", AntivirusEnabled: true, RealTimeProtectionEnabled: true},
+		&MSFT_MpPreference{MAPSReporting: 2, AttackSurfaceReductionRules_Ids: []string{"x"}, AttackSurfaceReductionRules_Actions: []uint8{6}})
+	out := StripANSI(FormatDefenderTable(r))
+	for _, want := range []string{"Real-time Protection", "1 of 1", "x warn", "protecting this machine"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table missing %q:\n%s", want, out)
+		}
+	}
+}
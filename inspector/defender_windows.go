@@ -0,0 +1,48 @@
+//go:build windows
+
+package inspector
+
+import "context"
+
+// defenderNamespace is the WMI namespace of the Defender provider
+const defenderNamespace = `root\Microsoft\Windows\Defender`
+
+// GetDefenderStatus returns the Microsoft Defender Antivirus configuration
+// from its WMI provider
+func GetDefenderStatus() (*DefenderResult, error) {
+	return GetDefenderStatusContext(context.Background())
+}
+
+// GetDefenderStatusContext is like GetDefenderStatus but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetDefenderStatusContext(ctx context.Context) (*DefenderResult, error) {
+	if result, ok, err := loadFixture[DefenderResult](ctx, "defender"); ok {
+		return result, err
+	}
+	var statuses []MSFT_MpComputerStatus
+	query := "SELECT AMRunningMode, AntivirusEnabled, RealTimeProtectionEnabled, IsTamperProtected, " +
+		"AntivirusSignatureVersion, AntivirusSignatureAge, AntivirusSignatureLastUpdated, " +
+		"QuickScanEndTime, FullScanEndTime FROM MSFT_MpComputerStatus"
+	if err := wmiQuery(query, &statuses, defenderNamespace); err != nil {
+		// The namespace is missing when Defender was removed or replaced
+		return &DefenderResult{Platform: "windows", Error: classifyWMIError(ctx, defenderNamespace, err)}, nil
+	}
+	if len(statuses) == 0 {
+		return &DefenderResult{Platform: "windows"}, nil
+	}
+
+	// Preferences hold the cloud protection level and ASR rules; without
+	// them the status is still worth reporting
+	var prefs []MSFT_MpPreference
+	query = "SELECT MAPSReporting, AttackSurfaceReductionRules_Ids, AttackSurfaceReductionRules_Actions FROM MSFT_MpPreference"
+	if err := wmiQuery(query, &prefs, defenderNamespace); err != nil || len(prefs) == 0 {
+		loggerFrom(ctx).Warn("cannot read Defender preferences", "err", err)
+		return newDefenderResult(statuses[0], nil), nil
+	}
+	return newDefenderResult(statuses[0], &prefs[0]), nil
+}
+
+// IsDefenderSupported returns true on Windows
+func IsDefenderSupported() bool {
+	return true
+}
@@ -0,0 +1,590 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// maxInspectedContainers bounds the running containers passed to docker inspect
+const maxInspectedContainers = 100
+
+// Where a Docker TCP endpoint is configured
+const (
+	DockerSourceDaemonJSON = "daemon.json"
+	DockerSourceSystemd    = "systemd"
+	DockerSourceDesktop    = "docker_desktop"
+)
+
+// defaultInsecureRegistryCIDRs are the loopback ranges dockerd always trusts
+var defaultInsecureRegistryCIDRs = []string{"127.0.0.0/8", "::1/128"}
+
+// DockerEndpoint is a TCP address the Docker daemon listens on
+type DockerEndpoint struct {
+	Address string `json:"address"`
+	// TLSVerify is true if clients must present a certificate the daemon
+	// trusts; without it anyone who can connect controls the daemon
+	TLSVerify bool `json:"tls_verify"`
+	Loopback  bool `json:"loopback"`
+	// Source is daemon.json, systemd, or docker_desktop
+	Source string `json:"source"`
+}
+
+// DockerContainer is a running container with a risky configuration
+type DockerContainer struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Image      string `json:"image,omitempty"`
+	Privileged bool   `json:"privileged"`
+}
+
+// ContainerSecurityResult reports the security configuration of the Docker
+// daemon and its running containers
+type ContainerSecurityResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string `json:"platform"`
+	// Installed is true if the docker CLI is found
+	Installed bool `json:"installed"`
+	// Running is true if the daemon answered
+	Running bool   `json:"running"`
+	Version string `json:"version,omitempty"`
+	// DockerDesktop is true if the daemon runs in Docker Desktop's VM
+	DockerDesktop bool `json:"docker_desktop"`
+	// Rootless is true if the daemon runs as an unprivileged user
+	Rootless bool `json:"rootless"`
+	// UsernsRemap is true if container root is mapped to an unprivileged
+	// host user (userns-remap)
+	UsernsRemap bool `json:"userns_remap"`
+	// LiveRestore keeps containers running while the daemon restarts
+	LiveRestore  bool             `json:"live_restore"`
+	TCPEndpoints []DockerEndpoint `json:"tcp_endpoints"`
+	// InsecureRegistries are registries pulled from over plain HTTP or
+	// without certificate verification
+	InsecureRegistries   []string          `json:"insecure_registries"`
+	PrivilegedContainers []DockerContainer `json:"privileged_containers"`
+	// Secure is true if the daemon is not exposed over TCP without TLS,
+	// container root is isolated from host root, no insecure registries are
+	// configured, and no container runs privileged
+	Secure bool        `json:"secure"`
+	Error  *ProbeError `json:"error,omitempty"`
+}
+
+// dockerInfo holds the fields read from `docker info --format '{{json .}}'`
+type dockerInfo struct {
+	ServerVersion      string
+	OperatingSystem    string
+	SecurityOptions    []string
+	LiveRestoreEnabled bool
+	RegistryConfig     struct {
+		InsecureRegistryCIDRs []string
+		IndexConfigs          map[string]struct {
+			Name   string
+			Secure bool
+		}
+	}
+}
+
+// dockerDaemonConfig holds the security-relevant keys of daemon.json
+type dockerDaemonConfig struct {
+	Hosts              []string `json:"hosts"`
+	TLSVerify          bool     `json:"tlsverify"`
+	UsernsRemap        string   `json:"userns-remap"`
+	LiveRestore        bool     `json:"live-restore"`
+	InsecureRegistries []string `json:"insecure-registries"`
+}
+
+// GetContainerSecurity inspects the Docker daemon configuration and running
+// containers. A machine without Docker is reported as not installed.
+func GetContainerSecurity() (*ContainerSecurityResult, error) {
+	return GetContainerSecurityContext(context.Background())
+}
+
+// GetContainerSecurityContext is like GetContainerSecurity but runs the
+// probes with ctx's deadline, command runner, and logger
+func GetContainerSecurityContext(ctx context.Context) (*ContainerSecurityResult, error) {
+	if result, ok, err := loadFixture[ContainerSecurityResult](ctx, "docker"); ok {
+		return result, err
+	}
+	result := &ContainerSecurityResult{
+		Platform:             runtime.GOOS,
+		TCPEndpoints:         []DockerEndpoint{},
+		InsecureRegistries:   []string{},
+		PrivilegedContainers: []DockerContainer{},
+	}
+	if _, err := lookPath(ctx, "docker"); err != nil {
+		result.Secure = true
+		return result, nil
+	}
+	result.Installed = true
+
+	var daemon dockerDaemonConfig
+	for _, p := range dockerDaemonConfigPaths() {
+		if data, err := readFile(p); err == nil {
+			if err := json.Unmarshal(data, &daemon); err != nil {
+				result.Error = newProbeError(ctx, ErrProbeFailed, p, err.Error())
+			}
+			break
+		}
+	}
+
+	var info *dockerInfo
+	out, err := runCommand(ctx, "docker", "info", "--format", "{{json .}}")
+	if err == nil {
+		info, err = parseDockerInfo(out)
+		if err != nil {
+			result.Error = newProbeError(ctx, ErrProbeFailed, "docker", err.Error())
+		}
+	} else if pe := classifyExecError(ctx, "docker", err); !strings.Contains(strings.ToLower(pe.Message), "cannot connect to the docker daemon") {
+		result.Error = pe
+	}
+
+	var units [][]byte
+	if runtime.GOOS == "linux" {
+		units = readDockerSystemdUnits()
+	}
+	var desktopSettings []byte
+	if p := dockerDesktopSettingsPath(); p != "" {
+		desktopSettings, _ = readFile(p)
+	}
+	applyDockerConfig(result, info, &daemon, units, desktopSettings)
+
+	if result.Running {
+		result.PrivilegedContainers = listPrivilegedContainers(ctx)
+	}
+	result.Secure = dockerSecure(result)
+	return result, nil
+}
+
+// dockerDaemonConfigPaths lists where daemon.json is looked for, the
+// rootless daemon's per-user file first
+func dockerDaemonConfigPaths() []string {
+	switch runtime.GOOS {
+	case "windows":
+		return []string{filepath.Join(os.Getenv("ProgramData"), "docker", "config", "daemon.json")}
+	case "darwin":
+		if home, err := os.UserHomeDir(); err == nil {
+			return []string{filepath.Join(home, ".docker", "daemon.json")}
+		}
+		return nil
+	}
+	var paths []string
+	if dir, err := os.UserConfigDir(); err == nil && os.Geteuid() != 0 {
+		paths = append(paths, filepath.Join(dir, "docker", "daemon.json"))
+	}
+	return append(paths, "/etc/docker/daemon.json")
+}
+
+// dockerSystemdUnits are the unit files and drop-ins whose ExecStart may
+// pass -H to dockerd
+var dockerSystemdUnits = []string{
+	"/etc/systemd/system/docker.service",
+	"/etc/systemd/system/docker.service.d/*.conf",
+	"/lib/systemd/system/docker.service",
+	"/usr/lib/systemd/system/docker.service",
+}
+
+// readDockerSystemdUnits reads the docker.service unit and its drop-ins
+func readDockerSystemdUnits() [][]byte {
+	var units [][]byte
+	for _, pattern := range dockerSystemdUnits {
+		matches, _ := filepath.Glob(pattern)
+		for _, m := range matches {
+			if data, err := readFile(m); err == nil {
+				units = append(units, data)
+			}
+		}
+	}
+	return units
+}
+
+// dockerDesktopSettingsPath returns Docker Desktop's settings file
+func dockerDesktopSettingsPath() string {
+	switch runtime.GOOS {
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir := filepath.Join(home, "Library", "Group Containers", "group.com.docker")
+		if _, err := os.Stat(filepath.Join(dir, "settings-store.json")); err == nil {
+			return filepath.Join(dir, "settings-store.json")
+		}
+		return filepath.Join(dir, "settings.json")
+	case "windows":
+		dir := filepath.Join(os.Getenv("APPDATA"), "Docker")
+		if _, err := os.Stat(filepath.Join(dir, "settings-store.json")); err == nil {
+			return filepath.Join(dir, "settings-store.json")
+		}
+		return filepath.Join(dir, "settings.json")
+	}
+	return ""
+}
+
+// parseDockerInfo parses `docker info --format '{{json .}}'`
+func parseDockerInfo(data []byte) (*dockerInfo, error) {
+	var info dockerInfo
+	if err := json.Unmarshal(bytes.TrimSpace(data), &info); err != nil {
+		return nil, fmt.Errorf("docker info: %w", err)
+	}
+	if info.ServerVersion == "" {
+		return nil, errors.New("docker info: no server version")
+	}
+	return &info, nil
+}
+
+// applyDockerConfig fills the result from docker info, daemon.json, the
+// systemd units, and Docker Desktop's settings. info is nil if the daemon
+// did not answer; daemon.json still tells how it is configured.
+func applyDockerConfig(result *ContainerSecurityResult, info *dockerInfo, daemon *dockerDaemonConfig, units [][]byte, desktopSettings []byte) {
+	result.UsernsRemap = daemon.UsernsRemap != ""
+	result.LiveRestore = daemon.LiveRestore
+	insecure := slices.Clone(daemon.InsecureRegistries)
+
+	if info != nil {
+		result.Running = true
+		result.Version = info.ServerVersion
+		result.DockerDesktop = strings.Contains(info.OperatingSystem, "Docker Desktop")
+		result.LiveRestore = info.LiveRestoreEnabled
+		for _, opt := range info.SecurityOptions {
+			switch {
+			case strings.Contains(opt, "name=rootless"):
+				result.Rootless = true
+			case strings.Contains(opt, "name=userns"):
+				result.UsernsRemap = true
+			}
+		}
+		for _, cidr := range info.RegistryConfig.InsecureRegistryCIDRs {
+			if !slices.Contains(defaultInsecureRegistryCIDRs, cidr) {
+				insecure = append(insecure, cidr)
+			}
+		}
+		for name, index := range info.RegistryConfig.IndexConfigs {
+			if !index.Secure {
+				insecure = append(insecure, name)
+			}
+		}
+	}
+	slices.Sort(insecure)
+	result.InsecureRegistries = slices.Compact(insecure)
+	if result.InsecureRegistries == nil {
+		result.InsecureRegistries = []string{}
+	}
+
+	add := func(address string, tlsVerify bool, source string) {
+		if !strings.HasPrefix(address, "tcp://") ||
+			slices.ContainsFunc(result.TCPEndpoints, func(e DockerEndpoint) bool { return e.Address == address }) {
+			return
+		}
+		result.TCPEndpoints = append(result.TCPEndpoints, DockerEndpoint{
+			Address: address, TLSVerify: tlsVerify, Loopback: loopbackAddress(address), Source: source,
+		})
+	}
+	for _, h := range daemon.Hosts {
+		add(h, daemon.TLSVerify, DockerSourceDaemonJSON)
+	}
+	for _, unit := range units {
+		hosts, tlsVerify := systemdDockerHosts(unit)
+		for _, h := range hosts {
+			add(h, tlsVerify || daemon.TLSVerify, DockerSourceSystemd)
+		}
+	}
+	if dockerDesktopExposesTCP(desktopSettings) {
+		add("tcp://localhost:2375", false, DockerSourceDesktop)
+	}
+}
+
+// systemdDockerHosts returns the -H/--host addresses passed to dockerd in a
+// unit's ExecStart lines, and whether --tlsverify is passed
+func systemdDockerHosts(unit []byte) (hosts []string, tlsVerify bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(unit))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		cmdline, ok := strings.CutPrefix(line, "ExecStart=")
+		if !ok || !strings.Contains(cmdline, "dockerd") {
+			continue
+		}
+		args := strings.Fields(cmdline)
+		for i, arg := range args {
+			switch {
+			case (arg == "-H" || arg == "--host") && i+1 < len(args):
+				hosts = append(hosts, args[i+1])
+			case strings.HasPrefix(arg, "--host="):
+				hosts = append(hosts, strings.TrimPrefix(arg, "--host="))
+			case strings.HasPrefix(arg, "-H") && len(arg) > 2:
+				hosts = append(hosts, strings.TrimPrefix(arg[2:], "="))
+			case arg == "--tlsverify" || arg == "--tlsverify=true":
+				tlsVerify = true
+			}
+		}
+	}
+	return hosts, tlsVerify
+}
+
+// dockerDesktopExposesTCP reports whether Docker Desktop's "Expose daemon on
+// tcp://localhost:2375 without TLS" setting is on
+func dockerDesktopExposesTCP(settings []byte) bool {
+	if len(settings) == 0 {
+		return false
+	}
+	var s map[string]any
+	if err := json.Unmarshal(settings, &s); err != nil {
+		return false
+	}
+	for key, v := range s {
+		if strings.EqualFold(key, "exposeDockerAPIOnTCP2375") {
+			on, _ := v.(bool)
+			return on
+		}
+	}
+	return false
+}
+
+// loopbackAddress reports whether a tcp:// address only listens on loopback.
+// An empty host listens on every interface.
+func loopbackAddress(address string) bool {
+	u, err := url.Parse(address)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// listPrivilegedContainers returns the running containers started with
+// --privileged
+func listPrivilegedContainers(ctx context.Context) []DockerContainer {
+	containers := []DockerContainer{}
+	out, err := runCommand(ctx, "docker", "ps", "--quiet")
+	if err != nil {
+		return containers
+	}
+	ids := strings.Fields(string(out))
+	if len(ids) == 0 {
+		return containers
+	}
+	if len(ids) > maxInspectedContainers {
+		ids = ids[:maxInspectedContainers]
+	}
+	out, err = runCommand(ctx, "docker", append([]string{"inspect"}, ids...)...)
+	if err != nil {
+		return containers
+	}
+	for _, c := range parseDockerInspect(out) {
+		if c.Privileged {
+			containers = append(containers, c)
+		}
+	}
+	return containers
+}
+
+// parseDockerInspect parses the JSON array printed by `docker inspect`
+func parseDockerInspect(data []byte) []DockerContainer {
+	var inspected []struct {
+		ID     string `json:"Id"`
+		Name   string
+		Config struct {
+			Image string
+		}
+		HostConfig struct {
+			Privileged bool
+		}
+	}
+	if err := json.Unmarshal(data, &inspected); err != nil {
+		return nil
+	}
+	containers := make([]DockerContainer, 0, len(inspected))
+	for _, c := range inspected {
+		id := c.ID
+		if len(id) > 12 {
+			id = id[:12]
+		}
+		containers = append(containers, DockerContainer{
+			ID:         id,
+			Name:       strings.TrimPrefix(c.Name, "/"),
+			Image:      c.Config.Image,
+			Privileged: c.HostConfig.Privileged,
+		})
+	}
+	return containers
+}
+
+// dockerIsolated reports whether root in a container is kept away from root
+// on the host: by a rootless daemon, user namespace remapping, or Docker
+// Desktop's VM
+func dockerIsolated(r *ContainerSecurityResult) bool {
+	return r.Rootless || r.UsernsRemap || r.DockerDesktop
+}
+
+// dockerSecure reports whether the daemon passes the check
+func dockerSecure(r *ContainerSecurityResult) bool {
+	if !r.Installed {
+		return true
+	}
+	for _, e := range r.TCPEndpoints {
+		if !e.TLSVerify {
+			return false
+		}
+	}
+	return len(r.InsecureRegistries) == 0 && len(r.PrivilegedContainers) == 0 &&
+		(!r.Running || dockerIsolated(r))
+}
+
+// containerSecurityFindings returns the Docker daemon settings and
+// containers that weaken isolation from the host
+func containerSecurityFindings(r *ContainerSecurityResult) []Finding {
+	if !r.Installed {
+		return nil
+	}
+	if r.Error != nil {
+		return []Finding{unverifiedFinding("docker_unverified", CheckDocker, "Docker", r.Error)}
+	}
+	var findings []Finding
+	add := func(id, title, severity, remediation string) {
+		findings = append(findings, Finding{
+			ID:                 id,
+			Title:              title,
+			Severity:           severity,
+			Check:              CheckDocker,
+			Remediation:        remediation,
+			RemediationCommand: remediationCommand(id),
+		})
+	}
+	for _, e := range r.TCPEndpoints {
+		if e.TLSVerify {
+			continue
+		}
+		// Any local process can reach a loopback socket; anyone on the
+		// network can reach the others
+		severity := SeverityCritical
+		if e.Loopback {
+			severity = SeverityHigh
+		}
+		add("docker_tcp_unauthenticated", T("The Docker daemon accepts unauthenticated connections on %s", e.Address), severity,
+			T("Stop exposing the Docker daemon over TCP, or require TLS client certificates (tlsverify)"))
+	}
+	for _, c := range r.PrivilegedContainers {
+		add("docker_privileged_container", T("Container %s is running privileged", c.Name), SeverityHigh,
+			T("Recreate the container without --privileged, granting only the capabilities and devices it needs"))
+	}
+	if len(r.InsecureRegistries) > 0 {
+		add("docker_insecure_registry", T("Docker pulls from insecure registries: %s", strings.Join(r.InsecureRegistries, ", ")), SeverityMedium,
+			T("Remove insecure-registries from daemon.json and serve the registries over TLS"))
+	}
+	if r.Running && !dockerIsolated(r) {
+		add("docker_root_not_isolated", T("Container root is root on the host"), SeverityMedium,
+			T("Run Docker in rootless mode, or enable user namespace remapping (userns-remap) in daemon.json"))
+	}
+	if r.Running && !r.LiveRestore && !r.DockerDesktop {
+		add("docker_live_restore_disabled", T("Docker live restore is disabled"), SeverityLow,
+			T("Set live-restore to true in daemon.json so containers keep running while the daemon restarts"))
+	}
+	return findings
+}
+
+// dockerEndpointStatus colors a TCP endpoint by its exposure
+func dockerEndpointStatus(st Styler, e DockerEndpoint) string {
+	if e.TLSVerify {
+		return st.Success(IconCheck + " TLS verified")
+	}
+	if e.Loopback {
+		return st.Warning(IconWarning + "no TLS (loopback)")
+	}
+	return st.Danger(IconCross + " no TLS")
+}
+
+// FormatContainerSecurityTable formats the Docker security posture as a colored table
+func FormatContainerSecurityTable(result *ContainerSecurityResult) string {
+	return FormatContainerSecurityTableWith(CurrentStyler(), result)
+}
+
+// FormatContainerSecurityTableWith is like FormatContainerSecurityTable but
+// styles the table with st
+func FormatContainerSecurityTableWith(st Styler, result *ContainerSecurityResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " Container Security (Docker)"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	if !result.Installed {
+		sb.WriteString(st.Muted("Docker is not installed"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n")
+		if result.Error.Hint != "" {
+			sb.WriteString(st.Muted("  " + result.Error.Hint))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.TableTop(24, 30))
+	sb.WriteString("\n")
+	row := func(label, value string) {
+		sb.WriteString(st.TableRowColored(PadRight(label, 24), PadRight(value, 30)))
+		sb.WriteString("\n")
+	}
+	daemon := st.Muted("not running")
+	if result.Running {
+		daemon = st.Success(IconCheck + " " + result.Version)
+		if result.DockerDesktop {
+			daemon += st.Muted(" (Docker Desktop)")
+		}
+	}
+	row("Daemon", daemon)
+	row("Rootless", st.BoolToStatusColored(result.Rootless))
+	row("User Namespace Remap", st.BoolToStatusColored(result.UsernsRemap))
+	row("Live Restore", st.BoolToStatusColored(result.LiveRestore))
+	if len(result.TCPEndpoints) == 0 {
+		row("TCP Socket", st.Success(IconCheck+" not exposed"))
+	}
+	for _, e := range result.TCPEndpoints {
+		row("TCP "+strings.TrimPrefix(e.Address, "tcp://"), dockerEndpointStatus(st, e))
+	}
+	insecure := st.Success(IconCheck + " none")
+	if len(result.InsecureRegistries) > 0 {
+		insecure = st.Danger(fmt.Sprintf("%s %d", IconCross, len(result.InsecureRegistries)))
+	}
+	row("Insecure Registries", insecure)
+	privileged := st.Success(IconCheck + " none")
+	if len(result.PrivilegedContainers) > 0 {
+		privileged = st.Danger(fmt.Sprintf("%s %d", IconCross, len(result.PrivilegedContainers)))
+	}
+	row("Privileged Containers", privileged)
+	sb.WriteString(st.TableBottom(24, 30))
+	sb.WriteString("\n")
+
+	for _, c := range result.PrivilegedContainers {
+		sb.WriteString(fmt.Sprintf("  %s %s %s\n", IconArrow, c.Name, st.Muted("("+c.ID+" "+c.Image+")")))
+	}
+	for _, r := range result.InsecureRegistries {
+		sb.WriteString(fmt.Sprintf("  %s %s %s\n", IconArrow, r, st.Muted("(insecure registry)")))
+	}
+	return sb.String()
+}
+
+// FormatContainerSecurity formats the Docker security posture in the specified format
+func FormatContainerSecurity(result *ContainerSecurityResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatContainerSecurityTableWith(st, result)
+	}, format)
+}
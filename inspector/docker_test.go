@@ -0,0 +1,241 @@
+package inspector
+
+import (
+	"context"
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+const dockerInfoJSON = `{"ServerVersion": "28.4.0", "OperatingSystem": "Ubuntu 24.04.3 LTS",
+	"SecurityOptions": ["name=apparmor", "name=seccomp,profile=builtin"], "LiveRestoreEnabled": false,
+	"RegistryConfig": {"InsecureRegistryCIDRs": ["::1/128", "127.0.0.0/8", "10.20.0.0/16"],
+		"IndexConfigs": {"docker.io": {"Name": "docker.io", "Secure": true}, "registry.lan:5000": {"Name": "registry.lan:5000", "Secure": false}}}}`
+
+func TestParseDockerInfo(t *testing.T) {
+	info, err := parseDockerInfo([]byte(dockerInfoJSON + "\n"))
+	if err != nil {
+		t.Fatalf("parseDockerInfo failed: %v", err)
+	}
+	if info.ServerVersion != "28.4.0" || len(info.SecurityOptions) != 2 || len(info.RegistryConfig.IndexConfigs) != 2 {
+		t.Errorf("info = %+v", info)
+	}
+	if _, err := parseDockerInfo([]byte(`{"ServerVersion": ""}`)); err == nil {
+		t.Error("docker info without a server version should fail")
+	}
+	if _, err := parseDockerInfo([]byte("not json")); err == nil {
+		t.Error("unparseable docker info should fail")
+	}
+}
+
+func TestApplyDockerConfig(t *testing.T) {
+	info, err := parseDockerInfo([]byte(dockerInfoJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	daemon := &dockerDaemonConfig{
+		Hosts:              []string{"unix:///var/run/docker.sock", "tcp://0.0.0.0:2376"},
+		TLSVerify:          true,
+		InsecureRegistries: []string{"registry.lan:5000"},
+	}
+	units := [][]byte{[]byte("[Service]\nExecStart=\nExecStart=/usr/bin/dockerd -H fd:// -H tcp://127.0.0.1:2375 --containerd=/run/containerd/containerd.sock\n")}
+
+	result := &ContainerSecurityResult{Installed: true}
+	applyDockerConfig(result, info, daemon, units, []byte(`{"exposeDockerAPIOnTCP2375": false}`))
+
+	if !result.Running || result.Version != "28.4.0" || result.Rootless || result.UsernsRemap || result.DockerDesktop {
+		t.Errorf("daemon = %+v", result)
+	}
+	if want := []string{"10.20.0.0/16", "registry.lan:5000"}; !slices.Equal(result.InsecureRegistries, want) {
+		t.Errorf("InsecureRegistries = %v, want %v", result.InsecureRegistries, want)
+	}
+	want := []DockerEndpoint{
+		{Address: "tcp://0.0.0.0:2376", TLSVerify: true, Source: DockerSourceDaemonJSON},
+		{Address: "tcp://127.0.0.1:2375", TLSVerify: true, Loopback: true, Source: DockerSourceSystemd},
+	}
+	if !slices.Equal(result.TCPEndpoints, want) {
+		t.Errorf("TCPEndpoints = %+v, want %+v", result.TCPEndpoints, want)
+	}
+}
+
+func TestApplyDockerConfig_DaemonDown(t *testing.T) {
+	daemon := &dockerDaemonConfig{UsernsRemap: "default", LiveRestore: true}
+	result := &ContainerSecurityResult{Installed: true}
+	applyDockerConfig(result, nil, daemon, nil, []byte(`{"ExposeDockerAPIOnTCP2375": true}`))
+
+	if result.Running || !result.UsernsRemap || !result.LiveRestore {
+		t.Errorf("daemon.json settings not applied: %+v", result)
+	}
+	want := []DockerEndpoint{{Address: "tcp://localhost:2375", Loopback: true, Source: DockerSourceDesktop}}
+	if !slices.Equal(result.TCPEndpoints, want) {
+		t.Errorf("TCPEndpoints = %+v, want %+v", result.TCPEndpoints, want)
+	}
+	if result.InsecureRegistries == nil {
+		t.Error("InsecureRegistries should be empty, not nil")
+	}
+}
+
+func TestApplyDockerConfig_Isolation(t *testing.T) {
+	for _, opt := range []string{"name=rootless", "name=userns"} {
+		info := &dockerInfo{ServerVersion: "28.4.0", SecurityOptions: []string{"name=seccomp,profile=builtin", opt}}
+		result := &ContainerSecurityResult{Installed: true}
+		applyDockerConfig(result, info, &dockerDaemonConfig{}, nil, nil)
+		if !dockerIsolated(result) {
+			t.Errorf("security option %q should isolate container root", opt)
+		}
+	}
+}
+
+func TestSystemdDockerHosts(t *testing.T) {
+	unit := []byte(`[Service]
+Type=notify
+ExecStart=/usr/bin/dockerd --host=tcp://0.0.0.0:2375 -Hunix:///var/run/docker.sock -H=tcp://10.0.0.5:2376 --tlsverify
+ExecReload=/bin/kill -s HUP $MAINPID
+`)
+	hosts, tlsVerify := systemdDockerHosts(unit)
+	if want := []string{"tcp://0.0.0.0:2375", "unix:///var/run/docker.sock", "tcp://10.0.0.5:2376"}; !slices.Equal(hosts, want) {
+		t.Errorf("hosts = %v, want %v", hosts, want)
+	}
+	if !tlsVerify {
+		t.Error("tlsVerify = false, want true")
+	}
+
+	if hosts, _ := systemdDockerHosts([]byte("ExecStart=/usr/bin/containerd -H tcp://0.0.0.0:1\n")); len(hosts) != 0 {
+		t.Errorf("non-dockerd ExecStart parsed: %v", hosts)
+	}
+}
+
+func TestLoopbackAddress(t *testing.T) {
+	tests := map[string]bool{
+		"tcp://127.0.0.1:2375": true,
+		"tcp://localhost:2375": true,
+		"tcp://[::1]:2375":     true,
+		"tcp://0.0.0.0:2375":   false,
+		"tcp://:2375":          false,
+		"tcp://10.0.0.5:2376":  false,
+	}
+	for address, want := range tests {
+		if got := loopbackAddress(address); got != want {
+			t.Errorf("loopbackAddress(%q) = %v, want %v", address, got, want)
+		}
+	}
+}
+
+func TestParseDockerInspect(t *testing.T) {
+	data := []byte(`[
+		{"Id": "3f4e8a1b2c9d0e1f2a3b", "Name": "/buildkit", "Config": {"Image": "moby/buildkit:latest"}, "HostConfig": {"Privileged": true}},
+		{"Id": "9a8b7c6d", "Name": "/web", "Config": {"Image": "nginx"}, "HostConfig": {"Privileged": false}}
+	]`)
+	want := []DockerContainer{
+		{ID: "3f4e8a1b2c9d", Name: "buildkit", Image: "moby/buildkit:latest", Privileged: true},
+		{ID: "9a8b7c6d", Name: "web", Image: "nginx"},
+	}
+	if got := parseDockerInspect(data); !slices.Equal(got, want) {
+		t.Errorf("parseDockerInspect = %+v, want %+v", got, want)
+	}
+}
+
+func TestListPrivilegedContainers(t *testing.T) {
+	fake := NewFakeRunner().
+		Set("docker ps --quiet", []byte("3f4e8a1b2c9d\n9a8b7c6d\n")).
+		Set("docker inspect 3f4e8a1b2c9d 9a8b7c6d", []byte(`[
+			{"Id": "3f4e8a1b2c9d", "Name": "/buildkit", "HostConfig": {"Privileged": true}},
+			{"Id": "9a8b7c6d", "Name": "/web", "HostConfig": {"Privileged": false}}]`))
+	defer SetCommandRunner(SetCommandRunner(fake))
+
+	got := listPrivilegedContainers(context.Background())
+	if len(got) != 1 || got[0].Name != "buildkit" {
+		t.Errorf("listPrivilegedContainers = %+v", got)
+	}
+}
+
+func TestGetContainerSecurity_NotInstalled(t *testing.T) {
+	defer SetCommandRunner(SetCommandRunner(NewFakeRunner()))
+
+	result, err := GetContainerSecurityContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetContainerSecurity failed: %v", err)
+	}
+	if result.Installed || !result.Secure || result.Error != nil {
+		t.Errorf("result = %+v, want not installed and secure", result)
+	}
+	if findings := containerSecurityFindings(result); len(findings) != 0 {
+		t.Errorf("findings = %+v, want none", findings)
+	}
+}
+
+func TestGetContainerSecurity_DaemonDown(t *testing.T) {
+	fake := NewFakeRunner().SetError("docker info --format {{json .}}", &exec.ExitError{
+		Stderr: []byte("Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?\n"),
+	})
+	defer SetCommandRunner(SetCommandRunner(fake))
+
+	result, err := GetContainerSecurityContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetContainerSecurity failed: %v", err)
+	}
+	if !result.Installed || result.Running || result.Error != nil {
+		t.Errorf("result = %+v, want installed, not running, no error", result)
+	}
+	if slices.Contains(fake.Calls(), "docker ps --quiet") {
+		t.Error("containers listed although the daemon is down")
+	}
+}
+
+func TestGetContainerSecurity_PermissionDenied(t *testing.T) {
+	fake := NewFakeRunner().SetError("docker info --format {{json .}}", &exec.ExitError{
+		Stderr: []byte("permission denied while trying to connect to the Docker daemon socket at unix:///var/run/docker.sock\n"),
+	})
+	defer SetCommandRunner(SetCommandRunner(fake))
+
+	result, _ := GetContainerSecurityContext(context.Background())
+	if result.Error == nil || result.Error.Code != CodePermissionDenied {
+		t.Errorf("Error = %+v, want permission denied", result.Error)
+	}
+	findings := containerSecurityFindings(result)
+	if len(findings) != 1 || findings[0].ID != "docker_unverified" {
+		t.Errorf("findings = %+v, want docker_unverified", findings)
+	}
+}
+
+func TestContainerSecurityFindings(t *testing.T) {
+	result := &ContainerSecurityResult{
+		Installed: true,
+		Running:   true,
+		TCPEndpoints: []DockerEndpoint{
+			{Address: "tcp://0.0.0.0:2375", Source: DockerSourceSystemd},
+			{Address: "tcp://127.0.0.1:2375", Loopback: true, Source: DockerSourceDaemonJSON},
+			{Address: "tcp://0.0.0.0:2376", TLSVerify: true, Source: DockerSourceDaemonJSON},
+		},
+		InsecureRegistries:   []string{"registry.lan:5000"},
+		PrivilegedContainers: []DockerContainer{{ID: "3f4e8a1b2c9d", Name: "buildkit", Privileged: true}},
+	}
+	if dockerSecure(result) {
+		t.Error("dockerSecure = true, want false")
+	}
+
+	type idSeverity struct{ id, severity string }
+	var got []idSeverity
+	for _, f := range containerSecurityFindings(result) {
+		got = append(got, idSeverity{f.ID, f.Severity})
+		if f.Check != CheckDocker {
+			t.Errorf("finding %s has check %q", f.ID, f.Check)
+		}
+	}
+	want := []idSeverity{
+		{"docker_tcp_unauthenticated", SeverityCritical},
+		{"docker_tcp_unauthenticated", SeverityHigh},
+		{"docker_privileged_container", SeverityHigh},
+		{"docker_insecure_registry", SeverityMedium},
+		{"docker_root_not_isolated", SeverityMedium},
+		{"docker_live_restore_disabled", SeverityLow},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+
+	desktop := &ContainerSecurityResult{Installed: true, Running: true, DockerDesktop: true}
+	if !dockerSecure(desktop) || len(containerSecurityFindings(desktop)) != 0 {
+		t.Errorf("a default Docker Desktop should pass: %+v", containerSecurityFindings(desktop))
+	}
+}
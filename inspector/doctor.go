@@ -0,0 +1,233 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Doctor item statuses
+const (
+	DoctorOK      = "ok"
+	DoctorWarning = "warning" // some results will be degraded
+	DoctorError   = "error"   // the tool cannot work as intended
+)
+
+// Doctor item categories
+const (
+	DoctorTool      = "tool"
+	DoctorPrivilege = "privilege"
+	DoctorWMI       = "wmi"
+	DoctorTransport = "transport"
+)
+
+// DoctorItem is one environment requirement and whether it is met
+type DoctorItem struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	// Checks lists the security checks that depend on this item
+	Checks []string `json:"checks,omitempty"`
+}
+
+// DegradedCheck is a security check that returned a degraded result on
+// this machine, with the probe error that explains why
+type DegradedCheck struct {
+	Check string      `json:"check"`
+	Error *ProbeError `json:"error"`
+}
+
+// DoctorResult reports whether this machine can run every check completely
+type DoctorResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string       `json:"platform"`
+	Elevated bool         `json:"elevated"`
+	Healthy  bool         `json:"healthy"`
+	Items    []DoctorItem `json:"items"`
+	// Degraded lists the checks whose results will be incomplete
+	Degraded []DegradedCheck `json:"degraded,omitempty"`
+}
+
+// Add appends an item, such as a server transport check made by the
+// caller, and updates Healthy
+func (r *DoctorResult) Add(item DoctorItem) {
+	r.Items = append(r.Items, item)
+	if item.Status == DoctorError {
+		r.Healthy = false
+	}
+}
+
+// Doctor checks the environment the security checks depend on: the
+// external tools in their access plans, the process's privileges, and the
+// WMI namespaces on Windows. It then runs each enabled check once and
+// reports which results are degraded and why.
+func Doctor() *DoctorResult {
+	return DoctorContext(context.Background())
+}
+
+// DoctorContext is like Doctor but runs the probes with ctx's deadline,
+// command runner, and logger
+func DoctorContext(ctx context.Context) *DoctorResult {
+	result := &DoctorResult{Platform: runtime.GOOS, Elevated: IsElevated(), Healthy: true, Items: []DoctorItem{}}
+
+	for _, tool := range planTools() {
+		item := DoctorItem{Category: DoctorTool, Name: tool.name, Checks: tool.checks}
+		if path, err := lookPath(ctx, tool.name); err == nil {
+			item.Status, item.Detail = DoctorOK, path
+		} else {
+			item.Status, item.Detail = DoctorWarning, "not found in PATH"
+		}
+		result.Add(item)
+	}
+	for _, item := range platformDoctorItems(ctx) {
+		result.Add(item)
+	}
+
+	for _, probe := range checkProbes() {
+		v, err := probe.run(ctx)
+		pe := resultProbeError(v)
+		if err != nil {
+			pe = &ProbeError{Code: CodeProbeFailed, Message: ErrorMessage(err)}
+		}
+		if pe != nil {
+			result.Degraded = append(result.Degraded, DegradedCheck{Check: probe.check, Error: pe})
+		}
+	}
+	result.Add(privilegeItem(result.Elevated, result.Degraded))
+	return result
+}
+
+// doctorTool is an external tool and the checks whose plans run it
+type doctorTool struct {
+	name   string
+	checks []string
+}
+
+// planTools returns the external tools named in the access plans of the
+// enabled checks, in check order
+func planTools() []doctorTool {
+	var tools []doctorTool
+	for _, check := range PlatformChecks() {
+		if !CheckEnabled(check) {
+			continue
+		}
+		for _, cmd := range checkAccessPlans[check].Commands {
+			name := strings.Fields(cmd)[0]
+			i := slices.IndexFunc(tools, func(t doctorTool) bool { return t.name == name })
+			if i < 0 {
+				tools = append(tools, doctorTool{name: name})
+				i = len(tools) - 1
+			}
+			if !slices.Contains(tools[i].checks, check) {
+				tools[i].checks = append(tools[i].checks, check)
+			}
+		}
+	}
+	return tools
+}
+
+// privilegeItem reports whether running unelevated degraded any check
+func privilegeItem(elevated bool, degraded []DegradedCheck) DoctorItem {
+	item := DoctorItem{Category: DoctorPrivilege, Name: "elevation", Status: DoctorOK}
+	if elevated {
+		item.Detail = "running elevated"
+		return item
+	}
+	for _, d := range degraded {
+		if d.Error.Code == CodePermissionDenied {
+			item.Checks = append(item.Checks, d.Check)
+		}
+	}
+	if len(item.Checks) == 0 {
+		item.Detail = "not elevated; no check needed it"
+		return item
+	}
+	item.Status = DoctorWarning
+	item.Detail = "not elevated; " + elevationHint()
+	return item
+}
+
+// doctorStatus colors an item status
+func doctorStatus(st Styler, status string) string {
+	switch status {
+	case DoctorOK:
+		return st.Success(IconCheck + " " + status)
+	case DoctorWarning:
+		return st.Warning(IconWarning + status)
+	}
+	return st.Danger(IconCross + " " + status)
+}
+
+// FormatDoctorTable formats a doctor report as a colored table
+func FormatDoctorTable(result *DoctorResult) string {
+	return FormatDoctorTableWith(CurrentStyler(), result)
+}
+
+// FormatDoctorTableWith is like FormatDoctorTable but styles the table with
+// st
+func FormatDoctorTableWith(st Styler, result *DoctorResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconStatus + " " + T("Environment Check")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(st.TableTop(11, 20, 12))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Category"), 11)),
+		st.Header(PadRight(T("Name"), 20)),
+		st.Header(PadRight(T("Status"), 12)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(11, 20, 12))
+	sb.WriteString("\n")
+	for _, item := range result.Items {
+		sb.WriteString(st.TableRowColored(
+			PadRight(item.Category, 11),
+			PadRight(item.Name, 20),
+			PadRight(doctorStatus(st, item.Status), 12),
+		))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(st.TableBottom(11, 20, 12))
+	sb.WriteString("\n")
+
+	for _, item := range result.Items {
+		if item.Status == DoctorOK {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\n  %s %s: %s", IconArrow, item.Name, item.Detail))
+		if len(item.Checks) > 0 {
+			sb.WriteString(st.Muted(" (" + strings.Join(item.Checks, ", ") + ")"))
+		}
+	}
+
+	sb.WriteString("\n\n")
+	if len(result.Degraded) == 0 {
+		sb.WriteString(st.Success(IconCheck + " " + T("Every enabled check returns complete results")))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+	sb.WriteString(st.Warning(IconWarning + T("These checks will return degraded results:")))
+	for _, d := range result.Degraded {
+		sb.WriteString(fmt.Sprintf("\n  %s %s: %s", IconArrow, st.BoldText(d.Check), d.Error.Error()))
+		if d.Error.Hint != "" {
+			sb.WriteString("\n      " + st.Muted(d.Error.Hint))
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// FormatDoctor formats a doctor report in the specified format
+func FormatDoctor(result *DoctorResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatDoctorTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package inspector
+
+import "context"
+
+// platformDoctorItems returns no extra items; the checks on this platform
+// depend only on the tools in their access plans and on privileges
+func platformDoctorItems(ctx context.Context) []DoctorItem {
+	return nil
+}
@@ -0,0 +1,83 @@
+package inspector
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPlanTools(t *testing.T) {
+	for _, tool := range planTools() {
+		if len(tool.checks) == 0 {
+			t.Errorf("%s is not attributed to any check", tool.name)
+		}
+		for _, check := range tool.checks {
+			declared := false
+			for _, c := range checkAccessPlans[check].Commands {
+				declared = declared || strings.Fields(c)[0] == tool.name
+			}
+			if !declared {
+				t.Errorf("%s is attributed to %s, whose plan does not run it", tool.name, check)
+			}
+		}
+	}
+	if runtime.GOOS == "linux" && !slices.ContainsFunc(planTools(), func(t doctorTool) bool { return t.name == "cryptsetup" }) {
+		t.Error("cryptsetup should be listed on Linux")
+	}
+}
+
+func TestPlanTools_SkipsDisabledChecks(t *testing.T) {
+	t.Setenv(OnlyChecksEnv, CheckTPM)
+	for _, tool := range planTools() {
+		if !slices.Equal(tool.checks, []string{CheckTPM}) {
+			t.Errorf("%s is attributed to %v with only tpm enabled", tool.name, tool.checks)
+		}
+	}
+}
+
+func TestDoctor_MissingTools(t *testing.T) {
+	defer SetCommandRunner(SetCommandRunner(NewFakeRunner()))
+	result := DoctorContext(context.Background())
+	if result.Platform != runtime.GOOS || result.Items == nil {
+		t.Fatalf("Doctor() = %+v", result)
+	}
+	for _, item := range result.Items {
+		if item.Category == DoctorTool && item.Status != DoctorWarning {
+			t.Errorf("%s should be reported missing, got %s", item.Name, item.Status)
+		}
+		if item.Status == DoctorError && result.Healthy {
+			t.Errorf("%s is in error but the result is healthy", item.Name)
+		}
+	}
+}
+
+func TestPrivilegeItem(t *testing.T) {
+	denied := []DegradedCheck{
+		{Check: CheckEncryption, Error: &ProbeError{Code: CodePermissionDenied}},
+		{Check: CheckTPM, Error: &ProbeError{Code: CodeToolMissing}},
+	}
+	if item := privilegeItem(true, denied); item.Status != DoctorOK {
+		t.Errorf("elevated: status = %s", item.Status)
+	}
+	if item := privilegeItem(false, denied[1:]); item.Status != DoctorOK {
+		t.Errorf("unelevated without permission errors: status = %s", item.Status)
+	}
+	item := privilegeItem(false, denied)
+	if item.Status != DoctorWarning || !slices.Equal(item.Checks, []string{CheckEncryption}) {
+		t.Errorf("unelevated with a permission error = %+v", item)
+	}
+}
+
+func TestDoctorResultAdd(t *testing.T) {
+	result := &DoctorResult{Healthy: true}
+	result.Add(DoctorItem{Name: "a", Status: DoctorWarning})
+	if !result.Healthy {
+		t.Error("a warning should not make the result unhealthy")
+	}
+	result.Add(DoctorItem{Name: "b", Status: DoctorError})
+	if result.Healthy || len(result.Items) != 2 {
+		t.Errorf("after an error: %+v", result)
+	}
+}
@@ -0,0 +1,40 @@
+//go:build windows
+
+package inspector
+
+import "context"
+
+// wmiProbe is a WMI query that shows whether a namespace is reachable
+type wmiProbe struct {
+	namespace string
+	query     string
+	checks    []string
+}
+
+var wmiProbes = []wmiProbe{
+	{`root\cimv2`, "SELECT Caption FROM Win32_OperatingSystem", []string{CheckTPM, CheckBiometrics}},
+	{`root\cimv2\Security\MicrosoftTpm`, "SELECT SpecVersion FROM Win32_Tpm", []string{CheckTPM}},
+	{`root\cimv2\Security\MicrosoftVolumeEncryption`, "SELECT DriveLetter FROM Win32_EncryptableVolume", []string{CheckEncryption}},
+	{defenderNamespace, "SELECT AMRunningMode FROM MSFT_MpComputerStatus", []string{CheckDefender}},
+}
+
+// platformDoctorItems queries each WMI namespace the checks use. The
+// security namespaces are only readable from an elevated process.
+func platformDoctorItems(ctx context.Context) []DoctorItem {
+	defer openWMISession()()
+	var items []DoctorItem
+	for _, p := range wmiProbes {
+		item := DoctorItem{Category: DoctorWMI, Name: p.namespace, Status: DoctorOK, Checks: p.checks}
+		// Only reachability matters, so no properties are loaded
+		var rows []struct{}
+		if err := wmiQuery(p.query, &rows, p.namespace); err != nil {
+			pe := classifyWMIError(ctx, p.namespace, err)
+			item.Status, item.Detail = DoctorError, pe.Message
+			if pe.Code == CodePermissionDenied {
+				item.Status, item.Detail = DoctorWarning, pe.Message+"; "+pe.Hint
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
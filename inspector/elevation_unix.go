@@ -0,0 +1,10 @@
+//go:build !windows
+
+package inspector
+
+import "os"
+
+// IsElevated reports whether the process is running as root
+func IsElevated() bool {
+	return os.Geteuid() == 0
+}
@@ -0,0 +1,10 @@
+//go:build windows
+
+package inspector
+
+import "golang.org/x/sys/windows"
+
+// IsElevated reports whether the process token is elevated (Run as Administrator)
+func IsElevated() bool {
+	return windows.GetCurrentProcessToken().IsElevated()
+}
@@ -3,41 +3,57 @@
 package inspector
 
 import (
-	"os/exec"
+	"context"
 	"strings"
 )
 
 // EncryptionResult contains disk encryption status information
-type EncryptionResult struct {
-	Enabled          bool              `json:"enabled"`
-	Platform         string            `json:"platform"`
-	Type             string            `json:"type"`
-	Status           string            `json:"status"`
-	EncryptedVolumes []EncryptedVolume `json:"encrypted_volumes,omitempty"`
-	Details          string            `json:"details,omitempty"`
-}
+type EncryptionResult = DarwinEncryptionResult
 
 // EncryptedVolume represents an encrypted volume
-type EncryptedVolume struct {
-	Name       string `json:"name"`
-	MountPoint string `json:"mount_point,omitempty"`
-	Encrypted  bool   `json:"encrypted"`
-	Status     string `json:"status"`
-}
+type EncryptedVolume = DarwinEncryptedVolume
 
 // GetEncryptionStatus returns the disk encryption status (macOS - FileVault)
 func GetEncryptionStatus() (*EncryptionResult, error) {
+	return GetEncryptionStatusContext(context.Background())
+}
+
+// GetEncryptionStatusContext is like GetEncryptionStatus but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetEncryptionStatusContext(ctx context.Context) (*EncryptionResult, error) {
+	if result, ok, err := loadFixture[EncryptionResult](ctx, "encryption"); ok {
+		return result, err
+	}
 	result := &EncryptionResult{
 		Platform: "darwin",
 		Type:     "filevault",
 	}
 
-	// Check FileVault status using fdesetup
-	out, err := exec.Command("fdesetup", "status").Output()
+	// The APFS volume list is structured (plist), so it is stable across
+	// macOS versions and locales
+	apfsVolumes, apfsErr := listAPFSVolumes(ctx)
+	if apfsErr == nil {
+		result.EncryptedVolumes = apfsEncryptedVolumes(ctx, apfsVolumes)
+	} else {
+		result.EncryptedVolumes = rootVolumeFallback(ctx)
+	}
+
+	// Check FileVault status (and conversion progress) using fdesetup
+	out, err := runCommand(ctx, "fdesetup", "status")
 	if err != nil {
-		// fdesetup might require admin privileges
+		if data, ok := apfsDataVolume(apfsVolumes); ok {
+			// The data volume's FileVault flag answers the main question
+			result.Enabled = data.FileVault
+			result.Status = "disabled"
+			if data.FileVault {
+				result.Status = "enabled"
+			}
+			result.Details = "FileVault status read from APFS volume metadata"
+			return result, nil
+		}
 		result.Status = "unknown"
-		result.Details = "Unable to determine FileVault status (may require admin)"
+		result.Details = "Unable to determine FileVault status"
+		result.Error = classifyExecError(ctx, "fdesetup", err)
 		return result, nil
 	}
 
@@ -65,179 +81,126 @@ func GetEncryptionStatus() (*EncryptionResult, error) {
 		result.Details = output
 	}
 
-	// Get list of encrypted volumes using diskutil
-	volumes := getEncryptedVolumes()
-	result.EncryptedVolumes = volumes
-
 	return result, nil
 }
 
-// getEncryptedVolumes returns a list of APFS encrypted volumes
-func getEncryptedVolumes() []EncryptedVolume {
-	var volumes []EncryptedVolume
-
-	// Use diskutil to list APFS containers and check encryption
-	out, err := exec.Command("diskutil", "apfs", "list", "-plist").Output()
+// listAPFSVolumes returns the APFS volumes reported by `diskutil apfs list -plist`
+func listAPFSVolumes(ctx context.Context) ([]apfsVolume, error) {
+	out, err := runCommand(ctx, "diskutil", "apfs", "list", "-plist")
 	if err != nil {
-		// Fallback: check just the root volume
-		out, err := exec.Command("diskutil", "info", "/").Output()
-		if err == nil {
-			output := string(out)
-			vol := EncryptedVolume{
-				Name:       "Macintosh HD",
-				MountPoint: "/",
-			}
-			if strings.Contains(output, "FileVault:") && strings.Contains(output, "Yes") {
-				vol.Encrypted = true
-				vol.Status = "encrypted"
-			} else if strings.Contains(output, "Encrypted:") && strings.Contains(output, "Yes") {
-				vol.Encrypted = true
-				vol.Status = "encrypted"
-			} else {
-				vol.Encrypted = false
-				vol.Status = "not_encrypted"
-			}
-			volumes = append(volumes, vol)
-		}
-		return volumes
+		return nil, err
 	}
+	return parseAPFSList(out)
+}
 
-	// Parse diskutil output to find encrypted volumes
-	// For simplicity, we'll check common volumes
-	output := string(out)
-	if strings.Contains(output, "Encryption") || strings.Contains(output, "FileVault") {
-		// Check root volume
-		rootOut, err := exec.Command("diskutil", "info", "/").Output()
-		if err == nil {
-			rootOutput := string(rootOut)
-			vol := EncryptedVolume{
-				MountPoint: "/",
-			}
+// apfsEncryptedVolumes converts APFS volumes from every container, including
+// external drives, to encrypted volume entries, skipping the hidden Preboot,
+// Recovery, and VM helper volumes
+func apfsEncryptedVolumes(ctx context.Context, volumes []apfsVolume) []EncryptedVolume {
+	var mounts map[string]string
+	if out, err := runCommand(ctx, "mount"); err == nil {
+		mounts = parseMountTable(out)
+	}
 
-			// Get volume name
-			for _, line := range strings.Split(rootOutput, "\n") {
-				if strings.Contains(line, "Volume Name:") {
-					parts := strings.SplitN(line, ":", 2)
-					if len(parts) == 2 {
-						vol.Name = strings.TrimSpace(parts[1])
-					}
-				}
-			}
-			if vol.Name == "" {
-				vol.Name = "System Volume"
-			}
+	// One lookup per physical store; volumes in a container share it
+	internal := make(map[string]bool)
 
-			if strings.Contains(rootOutput, "Yes (Unlocked)") ||
-				(strings.Contains(rootOutput, "Encrypted:") && strings.Contains(rootOutput, "Yes")) {
-				vol.Encrypted = true
-				vol.Status = "encrypted_unlocked"
-			} else {
-				vol.Encrypted = false
-				vol.Status = "not_encrypted"
-			}
-			volumes = append(volumes, vol)
+	var result []EncryptedVolume
+	for _, v := range volumes {
+		if v.hasRole("Preboot") || v.hasRole("Recovery") || v.hasRole("VM") || v.hasRole("Update") {
+			continue
+		}
+		if _, seen := internal[v.PhysicalStore]; !seen {
+			internal[v.PhysicalStore] = physicalStoreInternal(ctx, v.PhysicalStore)
 		}
+		result = append(result, EncryptedVolume{
+			Name:          v.Name,
+			MountPoint:    v.mountPointIn(mounts),
+			Encrypted:     v.encrypted(),
+			Status:        v.encryptionStatus(),
+			Device:        v.DeviceIdentifier,
+			Container:     v.Container,
+			Role:          v.role(),
+			Locked:        v.Locked,
+			External:      !internal[v.PhysicalStore],
+			CapacityBytes: v.capacity(),
+			UsedBytes:     v.CapacityInUse,
+		})
 	}
-
-	return volumes
+	return result
 }
 
-// FormatEncryptionTable formats encryption status as a colored table
-func FormatEncryptionTable(result *EncryptionResult) string {
-	var sb strings.Builder
-	sb.WriteString("\n")
-	sb.WriteString(Header(IconLock + " Disk Encryption Status"))
-	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 55)))
-	sb.WriteString("\n\n")
-
-	// Platform badge
-	sb.WriteString(BoldText("Platform: "))
-	sb.WriteString(Info(IconApple + " macOS (FileVault)"))
-	sb.WriteString("\n\n")
-
-	// Status table
-	sb.WriteString(TableTop(24, 26))
-	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Property", 24)),
-		Header(PadRight("Value", 26)),
-	))
-	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(24, 26))
-	sb.WriteString("\n")
-
-	// Enabled
-	sb.WriteString(TableRowColored(
-		PadRight(IconLock+" FileVault Enabled", 24),
-		PadRight(BoolToStatusColored(result.Enabled), 26),
-	))
-	sb.WriteString("\n")
-
-	// Status
-	var statusDisplay string
-	switch result.Status {
-	case "enabled":
-		statusDisplay = Success("Enabled")
-	case "disabled":
-		statusDisplay = Danger("Disabled")
-	case "encrypting":
-		statusDisplay = Warning("Encrypting...")
-	case "decrypting":
-		statusDisplay = Warning("Decrypting...")
-	default:
-		statusDisplay = Muted(result.Status)
+// physicalStoreInternal reports whether the disk backing an APFS container is
+// internal. Unknown disks are treated as internal so that a failed lookup
+// never flags the startup disk as external.
+func physicalStoreInternal(ctx context.Context, store string) bool {
+	if store == "" {
+		return true
 	}
-	sb.WriteString(TableRowColored(
-		PadRight(IconStatus+" Status", 24),
-		PadRight(statusDisplay, 26),
-	))
-	sb.WriteString("\n")
-
-	sb.WriteString(TableBottom(24, 26))
-	sb.WriteString("\n")
-
-	// Encrypted volumes
-	if len(result.EncryptedVolumes) > 0 {
-		sb.WriteString("\n")
-		sb.WriteString(BoldText("Volumes:"))
-		sb.WriteString("\n")
-		sb.WriteString(Muted(strings.Repeat("─", 40)))
-		sb.WriteString("\n")
-		for _, vol := range result.EncryptedVolumes {
-			icon := IconCross
-			if vol.Encrypted {
-				icon = IconCheck
-			}
-			statusStr := Danger("Not Encrypted")
-			if vol.Encrypted {
-				statusStr = Success("Encrypted")
-			}
-			sb.WriteString("  " + BoolToCheckbox(vol.Encrypted) + " ")
-			sb.WriteString(vol.Name)
-			if vol.MountPoint != "" {
-				sb.WriteString(Muted(" (" + vol.MountPoint + ")"))
-			}
-			sb.WriteString(" - " + statusStr)
-			sb.WriteString("\n")
-			_ = icon // suppress unused warning
+	out, err := runCommand(ctx, "diskutil", "info", "-plist", store)
+	if err != nil {
+		return true
+	}
+	internal, ok := parseDiskInternal(out)
+	return internal || !ok
+}
+
+// apfsDataVolume returns the user data volume, whose FileVault flag reflects
+// the FileVault state of the startup disk
+func apfsDataVolume(volumes []apfsVolume) (apfsVolume, bool) {
+	for _, v := range volumes {
+		if v.hasRole("Data") {
+			return v, true
 		}
 	}
+	return apfsVolume{}, false
+}
 
-	// Details if available
-	if result.Details != "" {
-		sb.WriteString("\n")
-		sb.WriteString(Muted("Details: " + result.Details))
-		sb.WriteString("\n")
+// rootVolumeFallback reports the root volume from `diskutil info /` text
+// output when the APFS plist is unavailable
+func rootVolumeFallback(ctx context.Context) []EncryptedVolume {
+	out, err := runCommand(ctx, "diskutil", "info", "/")
+	if err != nil {
+		loggerFrom(ctx).Warn("cannot read the root volume with diskutil", "err", err)
+		return nil
 	}
 
-	return sb.String()
+	output := string(out)
+	vol := EncryptedVolume{
+		Name:       "Macintosh HD",
+		MountPoint: "/",
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), "Volume Name:"); ok {
+			vol.Name = strings.TrimSpace(name)
+		}
+	}
+	if strings.Contains(output, "Yes (Unlocked)") ||
+		(strings.Contains(output, "Encrypted:") && strings.Contains(output, "Yes")) ||
+		(strings.Contains(output, "FileVault:") && strings.Contains(output, "Yes")) {
+		vol.Encrypted = true
+		vol.Status = "encrypted"
+	} else {
+		vol.Status = "not_encrypted"
+	}
+	return []EncryptedVolume{vol}
+}
+
+// FormatEncryptionTable formats encryption status as a colored table
+func FormatEncryptionTable(result *EncryptionResult) string {
+	return FormatEncryptionTableWith(CurrentStyler(), result)
+}
+
+// FormatEncryptionTableWith is like FormatEncryptionTable but styles the
+// table with st
+func FormatEncryptionTableWith(st Styler, result *EncryptionResult) string {
+	return formatDarwinEncryptionTable(st, result)
 }
 
 // FormatEncryption formats encryption status in the specified format
 func FormatEncryption(result *EncryptionResult, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatEncryptionTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatEncryptionTableWith(st, result)
 	}, format)
 }
 
@@ -0,0 +1,110 @@
+//go:build darwin
+
+package inspector
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+func TestGetEncryptionStatus_Fixtures(t *testing.T) {
+	tests := []struct {
+		name        string
+		fdesetup    string
+		wantStatus  string
+		wantEnabled bool
+	}{
+		{"on", "darwin/fdesetup_status_on.txt", "enabled", true},
+		{"off", "darwin/fdesetup_status_off.txt", "disabled", false},
+		{"encrypting", "darwin/fdesetup_status_encrypting.txt", "encrypting", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := NewFakeRunner().
+				Set("fdesetup status", fixture(t, tt.fdesetup)).
+				Set("diskutil info /", fixture(t, "darwin/diskutil_info_root.txt"))
+			defer SetCommandRunner(SetCommandRunner(fake))
+
+			result, err := GetEncryptionStatusContext(context.Background())
+			if err != nil {
+				t.Fatalf("GetEncryptionStatus failed: %v", err)
+			}
+			if result.Status != tt.wantStatus || result.Enabled != tt.wantEnabled {
+				t.Errorf("status = (%q, %v), want (%q, %v)", result.Status, result.Enabled, tt.wantStatus, tt.wantEnabled)
+			}
+			if len(result.EncryptedVolumes) != 1 || !result.EncryptedVolumes[0].Encrypted {
+				t.Errorf("EncryptedVolumes = %+v, want one encrypted root volume", result.EncryptedVolumes)
+			}
+		})
+	}
+}
+
+func TestGetEncryptionStatus_APFSPlist(t *testing.T) {
+	fake := NewFakeRunner().
+		Set("fdesetup status", fixture(t, "darwin/fdesetup_status_on.txt")).
+		Set("diskutil apfs list -plist", fixture(t, "darwin/diskutil_apfs_list.plist")).
+		Set("mount", fixture(t, "darwin/mount.txt")).
+		Set("diskutil info -plist disk0s2", fixture(t, "darwin/diskutil_info_disk0s2.plist")).
+		Set("diskutil info -plist disk4s2", fixture(t, "darwin/diskutil_info_disk4s2.plist"))
+	defer SetCommandRunner(SetCommandRunner(fake))
+
+	result, err := GetEncryptionStatusContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetEncryptionStatus failed: %v", err)
+	}
+
+	// Preboot is a hidden helper volume and is skipped; the external drive's
+	// volumes are listed alongside the startup disk's
+	const internalSize, externalSize = 494384795648, 1000169668608
+	want := []EncryptedVolume{
+		{Name: "Macintosh HD", MountPoint: "/", Encrypted: true, Status: "encrypted_unlocked",
+			Device: "disk3s1", Container: "disk3", Role: "System", CapacityBytes: internalSize, UsedBytes: 10787856384},
+		{Name: "Macintosh HD - Data", MountPoint: "/System/Volumes/Data", Encrypted: true, Status: "encrypted_unlocked",
+			Device: "disk3s5", Container: "disk3", Role: "Data", CapacityBytes: internalSize, UsedBytes: 164926744166},
+		{Name: "Archive", Encrypted: true, Status: "encrypted_locked",
+			Device: "disk3s7", Container: "disk3", Locked: true, CapacityBytes: internalSize, UsedBytes: 1073741824},
+		{Name: "Time Machine", MountPoint: "/Volumes/Time Machine", Status: "not_encrypted",
+			Device: "disk5s1", Container: "disk5", Role: "Backup", External: true, CapacityBytes: externalSize, UsedBytes: 386547056640},
+		{Name: "Projects", MountPoint: "/Volumes/Projects", Encrypted: true, Status: "encrypted_unlocked",
+			Device: "disk5s2", Container: "disk5", External: true, CapacityBytes: 107374182400, UsedBytes: 1612021760},
+	}
+	if len(result.EncryptedVolumes) != len(want) {
+		t.Fatalf("EncryptedVolumes = %+v, want %+v", result.EncryptedVolumes, want)
+	}
+	for i := range want {
+		if result.EncryptedVolumes[i] != want[i] {
+			t.Errorf("EncryptedVolumes[%d] = %+v, want %+v", i, result.EncryptedVolumes[i], want[i])
+		}
+	}
+}
+
+func TestGetEncryptionStatus_FdesetupFallback(t *testing.T) {
+	fake := NewFakeRunner().
+		SetError("fdesetup status", &exec.ExitError{Stderr: []byte("Error: This command must be run as root.\n")}).
+		Set("diskutil apfs list -plist", fixture(t, "darwin/diskutil_apfs_list.plist"))
+	defer SetCommandRunner(SetCommandRunner(fake))
+
+	result, err := GetEncryptionStatusContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetEncryptionStatus failed: %v", err)
+	}
+	if !result.Enabled || result.Status != "enabled" || result.Error != nil {
+		t.Errorf("result = (%v, %q, %v), want enabled from APFS metadata", result.Enabled, result.Status, result.Error)
+	}
+}
+
+func TestGetEncryptionStatus_PermissionDenied(t *testing.T) {
+	fake := NewFakeRunner().SetError("fdesetup status", &exec.ExitError{Stderr: []byte("Error: This command must be run as root.\n")})
+	defer SetCommandRunner(SetCommandRunner(fake))
+
+	result, err := GetEncryptionStatusContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetEncryptionStatus failed: %v", err)
+	}
+	if result.Status != "unknown" || result.Error == nil || !errors.Is(result.Error, ErrPermissionDenied) {
+		t.Errorf("result = (%q, %v), want unknown with permission_denied", result.Status, result.Error)
+	}
+}
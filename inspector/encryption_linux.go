@@ -3,38 +3,39 @@
 package inspector
 
 import (
+	"context"
+	"errors"
+	"io/fs"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
 // EncryptionResult contains disk encryption status information
-type EncryptionResult struct {
-	Enabled          bool              `json:"enabled"`
-	Platform         string            `json:"platform"`
-	Type             string            `json:"type"`
-	Status           string            `json:"status"`
-	EncryptedVolumes []EncryptedVolume `json:"encrypted_volumes,omitempty"`
-	Details          string            `json:"details,omitempty"`
-}
+type EncryptionResult = LinuxEncryptionResult
 
 // EncryptedVolume represents an encrypted volume
-type EncryptedVolume struct {
-	Name       string `json:"name"`
-	MountPoint string `json:"mount_point,omitempty"`
-	Encrypted  bool   `json:"encrypted"`
-	Status     string `json:"status"`
-}
+type EncryptedVolume = LinuxEncryptedVolume
 
 // GetEncryptionStatus returns the disk encryption status (Linux - LUKS)
 func GetEncryptionStatus() (*EncryptionResult, error) {
+	return GetEncryptionStatusContext(context.Background())
+}
+
+// GetEncryptionStatusContext is like GetEncryptionStatus but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetEncryptionStatusContext(ctx context.Context) (*EncryptionResult, error) {
+	if result, ok, err := loadFixture[EncryptionResult](ctx, "encryption"); ok {
+		return result, err
+	}
 	result := &EncryptionResult{
 		Platform: "linux",
 		Type:     "luks",
 	}
 
 	var encryptedVolumes []EncryptedVolume
+	var dmsetupErr *ProbeError
 
 	// Check for dm-crypt/LUKS encrypted volumes
 	// Look in /dev/mapper for crypt devices
@@ -52,8 +53,11 @@ func GetEncryptionStatus() (*EncryptionResult, error) {
 
 			// Use dmsetup to check if it's a crypt target
 			// #nosec G204 -- entry.Name() comes from trusted /dev/mapper directory listing
-			out, err := exec.Command("dmsetup", "table", entry.Name()).Output()
-			if err == nil && strings.Contains(string(out), "crypt") {
+			out, err := runCommand(ctx, "dmsetup", "table", entry.Name())
+			if err != nil && dmsetupErr == nil {
+				dmsetupErr = classifyExecError(ctx, "dmsetup", err)
+			}
+			if err == nil && isCryptTable(string(out)) {
 				vol := EncryptedVolume{
 					Name:      entry.Name(),
 					Encrypted: true,
@@ -61,7 +65,7 @@ func GetEncryptionStatus() (*EncryptionResult, error) {
 				}
 
 				// Try to find mount point
-				mountOut, err := exec.Command("findmnt", "-n", "-o", "TARGET", devicePath).Output()
+				mountOut, err := runCommand(ctx, "findmnt", "-n", "-o", "TARGET", devicePath)
 				if err == nil {
 					vol.MountPoint = strings.TrimSpace(string(mountOut))
 				}
@@ -73,7 +77,10 @@ func GetEncryptionStatus() (*EncryptionResult, error) {
 	}
 
 	// Also check /etc/crypttab for configured encrypted volumes
-	crypttabData, err := os.ReadFile("/etc/crypttab")
+	crypttabData, err := readFile("/etc/crypttab")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		loggerFrom(ctx).Warn("cannot read /etc/crypttab", "err", err)
+	}
 	if err == nil {
 		lines := strings.Split(string(crypttabData), "\n")
 		for _, line := range lines {
@@ -114,40 +121,7 @@ func GetEncryptionStatus() (*EncryptionResult, error) {
 	}
 
 	// Check for LUKS headers on block devices
-	blockDevices, _ := filepath.Glob("/dev/sd*")
-	blockDevices2, _ := filepath.Glob("/dev/nvme*")
-	blockDevices = append(blockDevices, blockDevices2...)
-
-	for _, dev := range blockDevices {
-		// Skip if it's a partition number > 9 to avoid too many checks
-		if strings.HasSuffix(dev, "0") {
-			continue
-		}
-
-		out, err := exec.Command("cryptsetup", "isLuks", dev).Output()
-		_ = out
-		if err == nil {
-			// This is a LUKS device
-			name := filepath.Base(dev)
-
-			// Check if already mapped
-			found := false
-			for _, vol := range encryptedVolumes {
-				if strings.Contains(vol.Name, name) {
-					found = true
-					break
-				}
-			}
-
-			if !found {
-				encryptedVolumes = append(encryptedVolumes, EncryptedVolume{
-					Name:      name + " (LUKS)",
-					Encrypted: true,
-					Status:    "luks_device",
-				})
-			}
-		}
-	}
+	encryptedVolumes = appendLUKSDevices(ctx, encryptedVolumes)
 
 	result.EncryptedVolumes = encryptedVolumes
 
@@ -155,109 +129,98 @@ func GetEncryptionStatus() (*EncryptionResult, error) {
 		result.Enabled = true
 		result.Status = "enabled"
 		result.Details = "LUKS/dm-crypt encryption detected"
+	} else if dmsetupErr != nil {
+		// Device-mapper targets could not be inspected, so "disabled" would be a guess
+		result.Status = "unknown"
+		result.Details = "Unable to inspect device-mapper targets"
 	} else {
 		result.Enabled = false
 		result.Status = "disabled"
 		result.Details = "No LUKS/dm-crypt encrypted volumes detected"
 	}
+	result.Error = dmsetupErr
 
 	return result, nil
 }
 
-// FormatEncryptionTable formats encryption status as a colored table
-func FormatEncryptionTable(result *EncryptionResult) string {
-	var sb strings.Builder
-	sb.WriteString("\n")
-	sb.WriteString(Header(IconLock + " Disk Encryption Status"))
-	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 55)))
-	sb.WriteString("\n\n")
-
-	// Platform badge
-	sb.WriteString(BoldText("Platform: "))
-	sb.WriteString(Info(IconChip + " Linux (LUKS/dm-crypt)"))
-	sb.WriteString("\n\n")
-
-	// Status table
-	sb.WriteString(TableTop(24, 26))
-	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Property", 24)),
-		Header(PadRight("Value", 26)),
-	))
-	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(24, 26))
-	sb.WriteString("\n")
-
-	// Enabled
-	sb.WriteString(TableRowColored(
-		PadRight(IconLock+" LUKS Encryption", 24),
-		PadRight(BoolToStatusColored(result.Enabled), 26),
-	))
-	sb.WriteString("\n")
-
-	// Status
-	var statusDisplay string
-	switch result.Status {
-	case "enabled":
-		statusDisplay = Success("Enabled")
-	case "disabled":
-		statusDisplay = Warning("Not Detected")
-	default:
-		statusDisplay = Muted(result.Status)
+// appendLUKSDevices adds the LUKS block devices that are not already listed
+// through their dm-crypt mapping
+func appendLUKSDevices(ctx context.Context, volumes []EncryptedVolume) []EncryptedVolume {
+	var devices []luksDevice
+	if LUKSScan() == LUKSScanCryptsetup {
+		devices = cryptsetupLUKSDevices(ctx)
+	} else {
+		out, err := runCommand(ctx, "lsblk", "-J", "-o", "NAME,TYPE,FSTYPE,MOUNTPOINT")
+		if err == nil {
+			devices, err = parseLsblkLUKS(out)
+		}
+		if err != nil {
+			loggerFrom(ctx).Info("cannot list LUKS devices with lsblk", "err", err, "hint", "set "+LUKSScanEnv+"="+LUKSScanCryptsetup+" to probe each device")
+		}
 	}
-	sb.WriteString(TableRowColored(
-		PadRight(IconStatus+" Status", 24),
-		PadRight(statusDisplay, 26),
-	))
-	sb.WriteString("\n")
 
-	sb.WriteString(TableBottom(24, 26))
-	sb.WriteString("\n")
-
-	// Encrypted volumes
-	if len(result.EncryptedVolumes) > 0 {
-		sb.WriteString("\n")
-		sb.WriteString(BoldText("Encrypted Volumes:"))
-		sb.WriteString("\n")
-		sb.WriteString(Muted(strings.Repeat("─", 50)))
-		sb.WriteString("\n")
+	for _, dev := range devices {
+		listed := slices.ContainsFunc(volumes, func(vol EncryptedVolume) bool {
+			return (dev.Mapping != "" && vol.Name == dev.Mapping) || strings.Contains(vol.Name, dev.Name)
+		})
+		if !listed {
+			volumes = append(volumes, EncryptedVolume{
+				Name:       dev.Name + " (LUKS)",
+				MountPoint: dev.MountPoint,
+				Encrypted:  true,
+				Status:     "luks_device",
+			})
+		}
+	}
+	return volumes
+}
 
-		for _, vol := range result.EncryptedVolumes {
-			statusStr := vol.Status
-			switch vol.Status {
-			case "encrypted_active", "configured_active":
-				statusStr = Success("Active")
-			case "configured_inactive":
-				statusStr = Warning("Inactive")
-			case "luks_device":
-				statusStr = Info("LUKS Device")
-			}
+// cryptsetupLUKSDevices runs `cryptsetup isLuks` against every /dev/sd* and
+// /dev/nvme* node
+func cryptsetupLUKSDevices(ctx context.Context) []luksDevice {
+	blockDevices, _ := filepath.Glob("/dev/sd*")
+	blockDevices2, _ := filepath.Glob("/dev/nvme*")
+	blockDevices = append(blockDevices, blockDevices2...)
 
-			sb.WriteString("  " + BoolToCheckbox(vol.Encrypted) + " ")
-			sb.WriteString(vol.Name)
-			if vol.MountPoint != "" {
-				sb.WriteString(Muted(" -> " + vol.MountPoint))
-			}
-			sb.WriteString(" [" + statusStr + "]")
-			sb.WriteString("\n")
+	var devices []luksDevice
+	for _, dev := range blockDevices {
+		// Skip if it's a partition number > 9 to avoid too many checks
+		if strings.HasSuffix(dev, "0") {
+			continue
+		}
+		if _, err := runCommand(ctx, "cryptsetup", "isLuks", dev); err == nil {
+			devices = append(devices, luksDevice{Name: filepath.Base(dev)})
 		}
 	}
+	return devices
+}
 
-	// Details if available
-	if result.Details != "" {
-		sb.WriteString("\n")
-		sb.WriteString(Muted("Details: " + result.Details))
-		sb.WriteString("\n")
+// isCryptTable reports whether `dmsetup table` output describes a dm-crypt
+// target. Each line is "<start> <length> <target> <args...>".
+func isCryptTable(table string) bool {
+	for _, line := range strings.Split(table, "\n") {
+		if fields := strings.Fields(line); len(fields) >= 3 && fields[2] == "crypt" {
+			return true
+		}
 	}
+	return false
+}
+
+// FormatEncryptionTable formats encryption status as a colored table
+func FormatEncryptionTable(result *EncryptionResult) string {
+	return FormatEncryptionTableWith(CurrentStyler(), result)
+}
 
-	return sb.String()
+// FormatEncryptionTableWith is like FormatEncryptionTable but styles the
+// table with st
+func FormatEncryptionTableWith(st Styler, result *EncryptionResult) string {
+	return formatLinuxEncryptionTable(st, result)
 }
 
 // FormatEncryption formats encryption status in the specified format
 func FormatEncryption(result *EncryptionResult, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatEncryptionTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatEncryptionTableWith(st, result)
 	}, format)
 }
 
@@ -0,0 +1,45 @@
+//go:build linux
+
+package inspector
+
+import (
+	"context"
+	"testing"
+)
+
+func TestIsCryptTable(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    bool
+	}{
+		{"linux/dmsetup_table_crypt.txt", true},
+		{"linux/dmsetup_table_linear.txt", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			if got := isCryptTable(string(fixture(t, tt.fixture))); got != tt.want {
+				t.Errorf("isCryptTable = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if isCryptTable("") {
+		t.Error("isCryptTable(\"\") = true, want false")
+	}
+}
+
+func TestAppendLUKSDevices(t *testing.T) {
+	t.Setenv(LUKSScanEnv, "")
+	fake := NewFakeRunner().Set("lsblk -J -o NAME,TYPE,FSTYPE,MOUNTPOINT", fixture(t, "linux/lsblk.json"))
+	defer SetCommandRunner(SetCommandRunner(fake))
+
+	// luks-3f1c was already found through /dev/mapper
+	volumes := appendLUKSDevices(context.Background(), []EncryptedVolume{{Name: "luks-3f1c", Encrypted: true, Status: "encrypted_active"}})
+	if len(volumes) != 2 || volumes[1].Name != "sdb (LUKS)" || volumes[1].Status != "luks_device" {
+		t.Errorf("volumes = %+v, want luks-3f1c and sdb (LUKS)", volumes)
+	}
+	if calls := fake.Calls(); len(calls) != 1 {
+		t.Errorf("calls = %v, want a single lsblk call", calls)
+	}
+}
@@ -0,0 +1,387 @@
+package inspector
+
+import "strings"
+
+// DarwinEncryptionResult contains disk encryption status information on
+// macOS, where EncryptionResult is an alias for it
+type DarwinEncryptionResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Enabled          bool                    `json:"enabled"`
+	Platform         string                  `json:"platform"`
+	Type             string                  `json:"type"`
+	Status           string                  `json:"status"`
+	EncryptedVolumes []DarwinEncryptedVolume `json:"encrypted_volumes,omitempty"`
+	Details          string                  `json:"details,omitempty"`
+	Error            *ProbeError             `json:"error,omitempty"`
+}
+
+// DarwinEncryptedVolume represents an encrypted volume on macOS, where
+// EncryptedVolume is an alias for it
+type DarwinEncryptedVolume struct {
+	Name       string `json:"name"`
+	MountPoint string `json:"mount_point,omitempty"`
+	Encrypted  bool   `json:"encrypted"`
+	Status     string `json:"status"`
+	// Device is the APFS volume's device identifier (e.g. disk3s5)
+	Device string `json:"device,omitempty"`
+	// Container is the APFS container holding the volume (e.g. disk3)
+	Container string `json:"container,omitempty"`
+	// Role is the volume's APFS role (System, Data, Backup, ...), if any
+	Role          string `json:"role,omitempty"`
+	Locked        bool   `json:"locked"`
+	External      bool   `json:"external"`
+	CapacityBytes uint64 `json:"capacity_bytes,omitempty"`
+	UsedBytes     uint64 `json:"used_bytes,omitempty"`
+}
+
+// formatDarwinEncryptionTable formats macOS encryption status as a colored table
+func formatDarwinEncryptionTable(st Styler, result *DarwinEncryptionResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconLock + " " + T("Disk Encryption Status")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	// Platform badge
+	sb.WriteString(st.BoldText(T("Platform:") + " "))
+	sb.WriteString(st.Info(IconApple + " macOS (FileVault)"))
+	sb.WriteString("\n\n")
+
+	// Status table
+	sb.WriteString(st.TableTop(24, 26))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Property"), 24)),
+		st.Header(PadRight(T("Value"), 26)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(24, 26))
+	sb.WriteString("\n")
+
+	// Enabled
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconLock+" "+T("FileVault Enabled"), 24),
+		PadRight(st.BoolToStatusColored(result.Enabled), 26),
+	))
+	sb.WriteString("\n")
+
+	// Status
+	var statusDisplay string
+	switch result.Status {
+	case "enabled":
+		statusDisplay = st.Success(T("Enabled"))
+	case "disabled":
+		statusDisplay = st.Danger(T("Disabled"))
+	case "encrypting":
+		statusDisplay = st.Warning(T("Encrypting..."))
+	case "decrypting":
+		statusDisplay = st.Warning(T("Decrypting..."))
+	default:
+		statusDisplay = st.Muted(Truncate(result.Status, 26))
+	}
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconStatus+" "+T("Status"), 24),
+		PadRight(statusDisplay, 26),
+	))
+	sb.WriteString("\n")
+
+	sb.WriteString(st.TableBottom(24, 26))
+	sb.WriteString("\n")
+
+	// Encrypted volumes
+	if len(result.EncryptedVolumes) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText(T("Volumes:")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(strings.Repeat("─", 40)))
+		sb.WriteString("\n")
+		for _, vol := range result.EncryptedVolumes {
+			icon := IconCross
+			if vol.Encrypted {
+				icon = IconCheck
+			}
+			statusStr := st.Danger(T("Not Encrypted"))
+			if vol.Encrypted {
+				statusStr = st.Success(T("Encrypted"))
+			}
+			sb.WriteString("  " + st.BoolToCheckbox(vol.Encrypted) + " ")
+			sb.WriteString(vol.Name)
+			if vol.MountPoint != "" {
+				sb.WriteString(st.Muted(" (" + vol.MountPoint + ")"))
+			}
+			sb.WriteString(" - " + statusStr)
+			if vol.Locked {
+				sb.WriteString(" " + st.Warning(IconLock+" "+T("Locked")))
+			}
+			if vol.External {
+				sb.WriteString(" " + st.Info(T("External")))
+			}
+			sb.WriteString("\n")
+			if vol.Role != "" || vol.CapacityBytes > 0 {
+				var meta []string
+				if vol.Role != "" {
+					meta = append(meta, vol.Role)
+				}
+				if vol.CapacityBytes > 0 {
+					meta = append(meta, T("%s of %s used", FormatBytes(vol.UsedBytes), FormatBytes(vol.CapacityBytes)))
+				}
+				sb.WriteString("      " + st.Muted(strings.Join(meta, ", ")) + "\n")
+			}
+			_ = icon // suppress unused warning
+		}
+	}
+
+	// Details if available
+	if result.Details != "" {
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(T("Details:") + " " + result.Details))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(formatProbeError(st, result.Error))
+
+	return sb.String()
+}
+
+// LinuxEncryptionResult contains disk encryption status information on
+// Linux, where EncryptionResult is an alias for it
+type LinuxEncryptionResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Enabled          bool                   `json:"enabled"`
+	Platform         string                 `json:"platform"`
+	Type             string                 `json:"type"`
+	Status           string                 `json:"status"`
+	EncryptedVolumes []LinuxEncryptedVolume `json:"encrypted_volumes,omitempty"`
+	Details          string                 `json:"details,omitempty"`
+	Error            *ProbeError            `json:"error,omitempty"`
+}
+
+// LinuxEncryptedVolume represents an encrypted volume on Linux, where
+// EncryptedVolume is an alias for it
+type LinuxEncryptedVolume struct {
+	Name       string `json:"name"`
+	MountPoint string `json:"mount_point,omitempty"`
+	Encrypted  bool   `json:"encrypted"`
+	Status     string `json:"status"`
+}
+
+// formatLinuxEncryptionTable formats Linux encryption status as a colored table
+func formatLinuxEncryptionTable(st Styler, result *LinuxEncryptionResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconLock + " " + T("Disk Encryption Status")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	// Platform badge
+	sb.WriteString(st.BoldText(T("Platform:") + " "))
+	sb.WriteString(st.Info(IconChip + " Linux (LUKS/dm-crypt)"))
+	sb.WriteString("\n\n")
+
+	// Status table
+	sb.WriteString(st.TableTop(24, 26))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Property"), 24)),
+		st.Header(PadRight(T("Value"), 26)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(24, 26))
+	sb.WriteString("\n")
+
+	// Enabled
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconLock+" "+T("LUKS Encryption"), 24),
+		PadRight(st.BoolToStatusColored(result.Enabled), 26),
+	))
+	sb.WriteString("\n")
+
+	// Status
+	var statusDisplay string
+	switch result.Status {
+	case "enabled":
+		statusDisplay = st.Success(T("Enabled"))
+	case "disabled":
+		statusDisplay = st.Warning(T("Not Detected"))
+	default:
+		statusDisplay = st.Muted(Truncate(result.Status, 26))
+	}
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconStatus+" "+T("Status"), 24),
+		PadRight(statusDisplay, 26),
+	))
+	sb.WriteString("\n")
+
+	sb.WriteString(st.TableBottom(24, 26))
+	sb.WriteString("\n")
+
+	// Encrypted volumes
+	if len(result.EncryptedVolumes) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText(T("Encrypted Volumes:")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+		sb.WriteString("\n")
+
+		for _, vol := range result.EncryptedVolumes {
+			statusStr := Truncate(vol.Status, 18)
+			switch vol.Status {
+			case "encrypted_active", "configured_active":
+				statusStr = st.Success(T("Active"))
+			case "configured_inactive":
+				statusStr = st.Warning(T("Inactive"))
+			case "luks_device":
+				statusStr = st.Info(T("LUKS Device"))
+			}
+
+			sb.WriteString("  " + st.BoolToCheckbox(vol.Encrypted) + " ")
+			sb.WriteString(vol.Name)
+			if vol.MountPoint != "" {
+				sb.WriteString(st.Muted(" -> " + vol.MountPoint))
+			}
+			sb.WriteString(" [" + statusStr + "]")
+			sb.WriteString("\n")
+		}
+	}
+
+	// Details if available
+	if result.Details != "" {
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(T("Details:") + " " + result.Details))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(formatProbeError(st, result.Error))
+
+	return sb.String()
+}
+
+// WindowsEncryptionResult contains disk encryption status information on
+// Windows, where EncryptionResult is an alias for it
+type WindowsEncryptionResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Enabled          bool                     `json:"enabled"`
+	Platform         string                   `json:"platform"`
+	Type             string                   `json:"type"`
+	Status           string                   `json:"status"`
+	EncryptedVolumes []WindowsEncryptedVolume `json:"encrypted_volumes,omitempty"`
+	Details          string                   `json:"details,omitempty"`
+	Error            *ProbeError              `json:"error,omitempty"`
+}
+
+// WindowsEncryptedVolume represents an encrypted volume on Windows, where
+// EncryptedVolume is an alias for it
+type WindowsEncryptedVolume struct {
+	Name       string `json:"name"`
+	MountPoint string `json:"mount_point,omitempty"`
+	Encrypted  bool   `json:"encrypted"`
+	Status     string `json:"status"`
+}
+
+// formatWindowsEncryptionTable formats Windows encryption status as a colored table
+func formatWindowsEncryptionTable(st Styler, result *WindowsEncryptionResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconLock + " " + T("Disk Encryption Status")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	// Platform badge
+	sb.WriteString(st.BoldText(T("Platform:") + " "))
+	sb.WriteString(st.Info(IconChip + " Windows (BitLocker)"))
+	sb.WriteString("\n\n")
+
+	// Status table
+	sb.WriteString(st.TableTop(24, 26))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Property"), 24)),
+		st.Header(PadRight(T("Value"), 26)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(24, 26))
+	sb.WriteString("\n")
+
+	// Enabled
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconLock+" "+T("BitLocker Enabled"), 24),
+		PadRight(st.BoolToStatusColored(result.Enabled), 26),
+	))
+	sb.WriteString("\n")
+
+	// Status
+	statusDisplay := result.Status
+	switch result.Status {
+	case "enabled":
+		statusDisplay = st.Success(T("Enabled"))
+	case "disabled":
+		statusDisplay = st.Danger(T("Disabled"))
+	default:
+		statusDisplay = st.Muted(Truncate(result.Status, 26))
+	}
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconStatus+" "+T("Status"), 24),
+		PadRight(statusDisplay, 26),
+	))
+	sb.WriteString("\n")
+
+	sb.WriteString(st.TableBottom(24, 26))
+	sb.WriteString("\n")
+
+	// Encrypted volumes
+	if len(result.EncryptedVolumes) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText(T("Volumes:")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+		sb.WriteString("\n")
+
+		sb.WriteString(st.TableTop(10, 18, 18))
+		sb.WriteString("\n")
+		sb.WriteString(st.TableRowColored(
+			st.Header(PadRight(T("Drive"), 10)),
+			st.Header(PadRight(T("Encrypted"), 18)),
+			st.Header(PadRight(T("Status"), 18)),
+		))
+		sb.WriteString("\n")
+		sb.WriteString(st.TableSeparator(10, 18, 18))
+		sb.WriteString("\n")
+
+		for _, vol := range result.EncryptedVolumes {
+			statusStr := Truncate(vol.Status, 18)
+			switch vol.Status {
+			case "encrypted", "protected":
+				statusStr = st.Success(T("Encrypted"))
+			case "encrypting":
+				statusStr = st.Warning(T("Encrypting..."))
+			case "decrypting":
+				statusStr = st.Warning(T("Decrypting..."))
+			case "not_encrypted":
+				statusStr = st.Danger(T("Not Encrypted"))
+			}
+
+			sb.WriteString(st.TableRowColored(
+				PadRight(Truncate(vol.MountPoint, 10), 10),
+				PadRight(st.BoolToStatusColored(vol.Encrypted), 18),
+				PadRight(statusStr, 18),
+			))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(st.TableBottom(10, 18, 18))
+		sb.WriteString("\n")
+	}
+
+	// Details if available
+	if result.Details != "" {
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(T("Details:") + " " + result.Details))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(formatProbeError(st, result.Error))
+
+	return sb.String()
+}
@@ -3,10 +3,8 @@
 package inspector
 
 import (
+	"context"
 	"fmt"
-	"strings"
-
-	"github.com/yusufpapurcu/wmi"
 )
 
 // Win32_EncryptableVolume represents WMI BitLocker class
@@ -20,25 +18,22 @@ type Win32_EncryptableVolume struct {
 }
 
 // EncryptionResult contains disk encryption status information
-type EncryptionResult struct {
-	Enabled          bool              `json:"enabled"`
-	Platform         string            `json:"platform"`
-	Type             string            `json:"type"`
-	Status           string            `json:"status"`
-	EncryptedVolumes []EncryptedVolume `json:"encrypted_volumes,omitempty"`
-	Details          string            `json:"details,omitempty"`
-}
+type EncryptionResult = WindowsEncryptionResult
 
 // EncryptedVolume represents an encrypted volume
-type EncryptedVolume struct {
-	Name       string `json:"name"`
-	MountPoint string `json:"mount_point,omitempty"`
-	Encrypted  bool   `json:"encrypted"`
-	Status     string `json:"status"`
-}
+type EncryptedVolume = WindowsEncryptedVolume
 
 // GetEncryptionStatus returns the disk encryption status (Windows - BitLocker)
 func GetEncryptionStatus() (*EncryptionResult, error) {
+	return GetEncryptionStatusContext(context.Background())
+}
+
+// GetEncryptionStatusContext is like GetEncryptionStatus but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetEncryptionStatusContext(ctx context.Context) (*EncryptionResult, error) {
+	if result, ok, err := loadFixture[EncryptionResult](ctx, "encryption"); ok {
+		return result, err
+	}
 	result := &EncryptionResult{
 		Platform: "windows",
 		Type:     "bitlocker",
@@ -49,12 +44,18 @@ func GetEncryptionStatus() (*EncryptionResult, error) {
 	// Query WMI for BitLocker status
 	// Note: Requires running as administrator
 	query := "SELECT * FROM Win32_EncryptableVolume"
-	err := wmi.QueryNamespace(query, &volumes, `root\cimv2\Security\MicrosoftVolumeEncryption`)
+	err := wmiQuery(query, &volumes, `root\cimv2\Security\MicrosoftVolumeEncryption`)
 
-	if err != nil || len(volumes) == 0 {
-		// BitLocker not found or not accessible
+	if err != nil {
+		result.Status = "unknown"
+		result.Details = "Unable to query BitLocker status"
+		result.Error = classifyWMIError(ctx, `root\cimv2\Security\MicrosoftVolumeEncryption`, err)
+		return result, nil
+	}
+	if len(volumes) == 0 {
+		// BitLocker not installed on this edition
 		result.Status = "unknown"
-		result.Details = "Unable to query BitLocker status (may require admin privileges)"
+		result.Details = "No BitLocker-capable volumes found"
 		return result, nil
 	}
 
@@ -111,112 +112,19 @@ func GetEncryptionStatus() (*EncryptionResult, error) {
 
 // FormatEncryptionTable formats encryption status as a colored table
 func FormatEncryptionTable(result *EncryptionResult) string {
-	var sb strings.Builder
-	sb.WriteString("\n")
-	sb.WriteString(Header(IconLock + " Disk Encryption Status"))
-	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 55)))
-	sb.WriteString("\n\n")
-
-	// Platform badge
-	sb.WriteString(BoldText("Platform: "))
-	sb.WriteString(Info(IconChip + " Windows (BitLocker)"))
-	sb.WriteString("\n\n")
-
-	// Status table
-	sb.WriteString(TableTop(24, 26))
-	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Property", 24)),
-		Header(PadRight("Value", 26)),
-	))
-	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(24, 26))
-	sb.WriteString("\n")
-
-	// Enabled
-	sb.WriteString(TableRowColored(
-		PadRight(IconLock+" BitLocker Enabled", 24),
-		PadRight(BoolToStatusColored(result.Enabled), 26),
-	))
-	sb.WriteString("\n")
-
-	// Status
-	statusDisplay := result.Status
-	switch result.Status {
-	case "enabled":
-		statusDisplay = Success("Enabled")
-	case "disabled":
-		statusDisplay = Danger("Disabled")
-	default:
-		statusDisplay = Muted(result.Status)
-	}
-	sb.WriteString(TableRowColored(
-		PadRight(IconStatus+" Status", 24),
-		PadRight(statusDisplay, 26),
-	))
-	sb.WriteString("\n")
-
-	sb.WriteString(TableBottom(24, 26))
-	sb.WriteString("\n")
-
-	// Encrypted volumes
-	if len(result.EncryptedVolumes) > 0 {
-		sb.WriteString("\n")
-		sb.WriteString(BoldText("Volumes:"))
-		sb.WriteString("\n")
-		sb.WriteString(Muted(strings.Repeat("─", 50)))
-		sb.WriteString("\n")
-
-		sb.WriteString(TableTop(10, 18, 18))
-		sb.WriteString("\n")
-		sb.WriteString(TableRowColored(
-			Header(PadRight("Drive", 10)),
-			Header(PadRight("Encrypted", 18)),
-			Header(PadRight("Status", 18)),
-		))
-		sb.WriteString("\n")
-		sb.WriteString(TableSeparator(10, 18, 18))
-		sb.WriteString("\n")
-
-		for _, vol := range result.EncryptedVolumes {
-			statusStr := vol.Status
-			switch vol.Status {
-			case "encrypted", "protected":
-				statusStr = Success("Encrypted")
-			case "encrypting":
-				statusStr = Warning("Encrypting...")
-			case "decrypting":
-				statusStr = Warning("Decrypting...")
-			case "not_encrypted":
-				statusStr = Danger("Not Encrypted")
-			}
-
-			sb.WriteString(TableRowColored(
-				PadRight(vol.MountPoint, 10),
-				PadRight(BoolToStatusColored(vol.Encrypted), 18),
-				PadRight(statusStr, 18),
-			))
-			sb.WriteString("\n")
-		}
-		sb.WriteString(TableBottom(10, 18, 18))
-		sb.WriteString("\n")
-	}
-
-	// Details if available
-	if result.Details != "" {
-		sb.WriteString("\n")
-		sb.WriteString(Muted("Details: " + result.Details))
-		sb.WriteString("\n")
-	}
+	return FormatEncryptionTableWith(CurrentStyler(), result)
+}
 
-	return sb.String()
+// FormatEncryptionTableWith is like FormatEncryptionTable but styles the
+// table with st
+func FormatEncryptionTableWith(st Styler, result *EncryptionResult) string {
+	return formatWindowsEncryptionTable(st, result)
 }
 
 // FormatEncryption formats encryption status in the specified format
 func FormatEncryption(result *EncryptionResult, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatEncryptionTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatEncryptionTableWith(st, result)
 	}, format)
 }
 
@@ -0,0 +1,235 @@
+package inspector
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/host"
+)
+
+// Envelope wraps a result with the metadata needed to tell reports from
+// different hosts and runs apart once they are aggregated
+type Envelope struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	// ReportID is a random UUID identifying this report
+	ReportID string `json:"report_id"`
+	// Kind is the schema name of the result (see SchemaNames), empty for
+	// results without a published schema
+	Kind      string    `json:"kind,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Hostname  string    `json:"hostname,omitempty"`
+	// MachineID is a stable per-installation ID: /etc/machine-id on Linux,
+	// the IOPlatformUUID on macOS, and the MachineGuid on Windows
+	MachineID string `json:"machine_id,omitempty"`
+	// Fingerprint is the device fingerprint (see GetFingerprint), keyed
+	// with OMNITRUST_FINGERPRINT_SALT when it is set
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Platform    string `json:"platform"`
+	OSVersion   string `json:"os_version,omitempty"`
+	ToolVersion string `json:"tool_version"`
+	// DurationMS is how long collecting the result took
+	DurationMS int64 `json:"duration_ms"`
+	// Errors lists the probe errors found in the result, by check
+	Errors []CheckError `json:"errors,omitempty"`
+	// Provenance says which commands, files, and APIs produced the fields
+	// of the result, when provenance is on (see SetProvenance)
+	Provenance []FieldProvenance `json:"provenance,omitempty"`
+	Result     any               `json:"result"`
+}
+
+// CheckError is a probe error reported by one check of a result
+type CheckError struct {
+	// Check is the part of the result that failed: the summary field (e.g.
+	// tpm) for a summary, or the result's kind
+	Check string      `json:"check"`
+	Error *ProbeError `json:"error"`
+}
+
+// NewEnvelope wraps a result whose collection started at started
+func NewEnvelope(result any, started time.Time) *Envelope {
+	// Taken first, so the envelope's own reads (machine ID, fingerprint)
+	// are not attributed to the result
+	provenance := Provenance(result)
+	env := &Envelope{
+		ReportID:    newUUID(),
+		Kind:        resultKind(result),
+		Timestamp:   time.Now().UTC(),
+		MachineID:   machineID(),
+		Fingerprint: envelopeFingerprint(),
+		Platform:    runtime.GOOS,
+		OSVersion:   osVersion(),
+		ToolVersion: ToolVersion(),
+		DurationMS:  time.Since(started).Milliseconds(),
+		Result:      result,
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		env.Hostname = hostname
+	}
+	collectProbeErrors(reflect.ValueOf(result), env.Kind, &env.Errors)
+	env.Provenance = provenance
+	return env
+}
+
+// DecodeEnvelope decodes an enveloped report, unmarshaling its result into
+// result. It returns nil without an error if data is not an envelope.
+func DecodeEnvelope(data []byte, result any) (*Envelope, error) {
+	var raw struct {
+		Envelope
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if raw.ReportID == "" || len(raw.Result) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(raw.Result, result); err != nil {
+		return nil, err
+	}
+	env := raw.Envelope
+	env.Result = result
+	return &env, nil
+}
+
+// envelopeEnabled wraps JSON output in an Envelope, timed from envelopeStart
+var (
+	envelopeEnabled bool
+	envelopeStart   time.Time
+)
+
+// SetEnvelope turns wrapping JSON output in an Envelope on or off. The
+// collection duration is measured from this call.
+func SetEnvelope(enabled bool) {
+	envelopeEnabled = enabled
+	envelopeStart = time.Now()
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// resultKind returns the schema name of a result's type
+func resultKind(result any) string {
+	t := reflect.TypeOf(result)
+	if t == nil {
+		return ""
+	}
+	t = derefType(t)
+	for name, rt := range resultTypes {
+		if rt == t {
+			return name
+		}
+	}
+	return ""
+}
+
+// collectProbeErrors appends the probe errors of v and its nested structs.
+// An error belongs to the check named by the JSON field holding its struct,
+// or to check at the top level.
+func collectProbeErrors(v reflect.Value, check string, errs *[]CheckError) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		if pe, ok := v.Interface().(*ProbeError); ok {
+			*errs = append(*errs, CheckError{Check: check, Error: pe})
+			return
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if f.Type == reflect.TypeFor[*ProbeError]() {
+			collectProbeErrors(v.Field(i), check, errs)
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		collectProbeErrors(v.Field(i), name, errs)
+	}
+}
+
+// machineID returns a stable ID of this installation. On Linux it reads the
+// world-readable machine-id rather than the DMI product UUID, which only
+// root can read, so that the ID does not depend on privileges.
+func machineID() string {
+	if runtime.GOOS == "linux" {
+		return linuxMachineID(os.DirFS("/"))
+	}
+	id, _ := host.HostIDWithContext(context.Background())
+	return id
+}
+
+// linuxMachineID reads the systemd or D-Bus machine ID
+func linuxMachineID(fsys fs.FS) string {
+	for _, name := range []string{"etc/machine-id", "var/lib/dbus/machine-id"} {
+		if data, err := fs.ReadFile(fsys, name); err == nil {
+			if id := strings.TrimSpace(string(data)); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// osVersion describes the operating system release, e.g. "Ubuntu 24.04 LTS",
+// "macOS 14.5", or "Microsoft Windows 11 Pro 10.0.22631"
+func osVersion() string {
+	if runtime.GOOS == "linux" {
+		if v := linuxOSRelease(os.DirFS("/")); v != "" {
+			return v
+		}
+	}
+	platform, _, version, err := host.PlatformInformationWithContext(context.Background())
+	if err != nil {
+		return ""
+	}
+	if platform == "darwin" {
+		platform = "macOS"
+	}
+	version, _, _ = strings.Cut(version, " ")
+	return strings.TrimSpace(platform + " " + version)
+}
+
+// linuxOSRelease returns PRETTY_NAME from os-release
+func linuxOSRelease(fsys fs.FS) string {
+	for _, name := range []string{"etc/os-release", "usr/lib/os-release"} {
+		f, err := fsys.Open(name)
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if v, ok := strings.CutPrefix(scanner.Text(), "PRETTY_NAME="); ok {
+				return strings.Trim(v, `"'`)
+			}
+		}
+		return ""
+	}
+	return ""
+}
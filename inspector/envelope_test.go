@@ -0,0 +1,84 @@
+package inspector
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestNewEnvelope(t *testing.T) {
+	summary := &SecuritySummary{
+		Platform:   "linux",
+		TPM:        &TPMSummary{Error: &ProbeError{Code: CodePermissionDenied, Message: "access denied"}},
+		Encryption: &EncSummary{Enabled: true},
+		Firmware:   &FirmwareSummary{Error: &ProbeError{Code: CodeToolMissing, Message: "fwupdmgr not found"}},
+	}
+	env := NewEnvelope(summary, time.Now().Add(-1500*time.Millisecond))
+	if env.Kind != "summary" || env.DurationMS < 1500 || env.Platform == "" || env.ToolVersion == "" {
+		t.Errorf("envelope = %+v", env)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(env.ReportID) {
+		t.Errorf("ReportID = %q, want a version 4 UUID", env.ReportID)
+	}
+	var checks []string
+	for _, e := range env.Errors {
+		checks = append(checks, e.Check)
+	}
+	if !slices.Equal(checks, []string{"tpm", "firmware"}) {
+		t.Errorf("errors = %q, want tpm and firmware", checks)
+	}
+
+	// A top-level error belongs to the result's kind
+	env = NewEnvelope(&UptimeResult{Error: &ProbeError{Code: CodeProbeFailed, Message: "failed"}}, time.Now())
+	if len(env.Errors) != 1 || env.Errors[0].Check != "uptime" {
+		t.Errorf("errors = %+v, want uptime", env.Errors)
+	}
+}
+
+func TestDecodeEnvelope(t *testing.T) {
+	data, err := json.Marshal(NewEnvelope(&SecuritySummary{Platform: "darwin", OverallScore: 75}, time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary SecuritySummary
+	env, err := DecodeEnvelope(data, &summary)
+	if err != nil || env == nil || env.Kind != "summary" || summary.OverallScore != 75 || env.Result != &summary {
+		t.Errorf("DecodeEnvelope = %+v, %v; summary %+v", env, err, summary)
+	}
+
+	// A bare result is not an envelope
+	env, err = DecodeEnvelope([]byte(`{"platform": "linux", "overall_score": 50}`), &summary)
+	if env != nil || err != nil {
+		t.Errorf("DecodeEnvelope(bare) = %+v, %v", env, err)
+	}
+}
+
+func TestFormatOutput_Envelope(t *testing.T) {
+	defer SetEnvelope(false)
+	SetEnvelope(true)
+	out := FormatOutput(&UptimeResult{Platform: "linux"}, nil, FormatJSON)
+	if !strings.Contains(out, `"report_id"`) || !strings.Contains(out, `"kind": "uptime"`) {
+		t.Errorf("JSON output is not enveloped:\n%s", out)
+	}
+	// CSV rows stay bare
+	if out := FormatOutput(&UptimeResult{Platform: "linux"}, nil, FormatCSV); strings.Contains(out, "report_id") {
+		t.Errorf("CSV output is enveloped:\n%s", out)
+	}
+}
+
+func TestLinuxMachineIdentity(t *testing.T) {
+	fsys := fstest.MapFS{
+		"var/lib/dbus/machine-id": {Data: []byte("4b1f0c7e9d2a4e5f8a6b3c2d1e0f9a8b\n")},
+		"usr/lib/os-release":      {Data: []byte("NAME=\"Fedora Linux\"\nPRETTY_NAME=\"Fedora Linux 40 (Workstation Edition)\"\n")},
+	}
+	if got := linuxMachineID(fsys); got != "4b1f0c7e9d2a4e5f8a6b3c2d1e0f9a8b" {
+		t.Errorf("linuxMachineID = %q", got)
+	}
+	if got := linuxOSRelease(fsys); got != "Fedora Linux 40 (Workstation Edition)" {
+		t.Errorf("linuxOSRelease = %q", got)
+	}
+}
@@ -0,0 +1,326 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// Container runtimes reported by GetRuntimeEnvironment
+const (
+	RuntimeDocker     = "docker"
+	RuntimePodman     = "podman"
+	RuntimeKubernetes = "kubernetes"
+	RuntimeContainerd = "containerd"
+	RuntimeLXC        = "lxc"
+	RuntimeUnknown    = "unknown"
+)
+
+// StatusNotApplicableInContainer marks host-only checks that were skipped
+// because posture is running inside a container
+const StatusNotApplicableInContainer = "not_applicable_in_container"
+
+// AssumeHostEnv disables container detection, for containers that are given
+// host devices and mounts (e.g. a privileged DaemonSet with /dev/tpmrm0 and
+// /sys/firmware bind-mounted) and can run host checks meaningfully
+const AssumeHostEnv = "OMNITRUST_ASSUME_HOST"
+
+// hostOnlyChecks inspect hardware, firmware and its boot order, or the
+// host's disks, login stack, desktop browsers, Docker daemon, kubelet,
+// installed updates, management engine, and USB devices, none of which a
+// container can see. A node agent pod mounts the host's root filesystem at
+// OMNITRUST_HOST_ROOT and sets OMNITRUST_ASSUME_HOST to run the kubelet check.
+var hostOnlyChecks = []string{CheckTPM, CheckSecureBoot, CheckBootOrder, CheckEncryption, CheckBiometrics, CheckBrowser, CheckDocker, CheckKubelet, CheckUptime, CheckFirmware, CheckManagementEngine, CheckUSBStorage, CheckPasskeys}
+
+// RuntimeEnvironment describes where posture is running
+type RuntimeEnvironment struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform      string `json:"platform"`
+	Containerized bool   `json:"containerized"`
+	// Runtime is the detected container runtime, or "unknown" if only generic
+	// container indicators were found
+	Runtime string `json:"runtime,omitempty"`
+	// Indicators lists the evidence behind the verdict
+	Indicators []string `json:"indicators,omitempty"`
+	// HostOnlyChecks are reported as not_applicable_in_container when containerized
+	HostOnlyChecks []string `json:"host_only_checks,omitempty"`
+	// WSL is set when running in a Windows Subsystem for Linux guest
+	WSL *WSLInfo `json:"wsl,omitempty"`
+	// AssumeHost reports that detection was overridden with OMNITRUST_ASSUME_HOST
+	AssumeHost bool `json:"assume_host,omitempty"`
+}
+
+// environmentRoot is the filesystem container detection reads from
+var environmentRoot fs.FS = os.DirFS("/")
+
+// GetRuntimeEnvironment detects whether posture is running in a container or
+// a WSL guest. Under WSL with interop enabled, it also queries the Windows
+// host for posture hints.
+func GetRuntimeEnvironment() *RuntimeEnvironment {
+	return GetRuntimeEnvironmentContext(context.Background())
+}
+
+// GetRuntimeEnvironmentContext is like GetRuntimeEnvironment but runs the
+// probes with ctx's deadline, command runner, and logger
+func GetRuntimeEnvironmentContext(ctx context.Context) *RuntimeEnvironment {
+	if env, ok := loadFixtureOr[RuntimeEnvironment](ctx, "environment"); ok {
+		return env
+	}
+	env := &RuntimeEnvironment{Platform: runtime.GOOS}
+	if assumeHost() {
+		env.AssumeHost = true
+		return env
+	}
+	// Only Linux containers are detected; the filesystem heuristics below
+	// are meaningless elsewhere
+	if runtime.GOOS == "linux" {
+		env.Runtime, env.Indicators = detectContainer(environmentRoot, os.Getenv)
+	}
+	if env.Runtime != "" {
+		env.Containerized = true
+		env.HostOnlyChecks = hostOnlyChecks
+		return env
+	}
+	if runtime.GOOS == "linux" {
+		env.WSL = detectWSL(environmentRoot, os.Getenv)
+	}
+	if env.WSL != nil {
+		env.HostOnlyChecks = hostOnlyChecks
+		if env.WSL.Interop {
+			env.WSL.Host = wslHostHints(ctx)
+		}
+	}
+	return env
+}
+
+// notApplicableStatus returns the status given to host-only checks in this
+// environment, or "" on a host
+func (e *RuntimeEnvironment) notApplicableStatus() string {
+	switch {
+	case e.Containerized:
+		return StatusNotApplicableInContainer
+	case e.WSL != nil:
+		return StatusNotApplicableInWSL
+	}
+	return ""
+}
+
+// assumeHost reports whether OMNITRUST_ASSUME_HOST is set to a true value
+func assumeHost() bool {
+	switch strings.ToLower(os.Getenv(AssumeHostEnv)) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
+
+// detectContainer applies marker-file, environment, cgroup, and mount
+// namespace heuristics. It returns the runtime ("" if not containerized)
+// and the indicators that matched.
+func detectContainer(root fs.FS, getenv func(string) string) (string, []string) {
+	var runtimes, indicators []string
+	found := func(rt, indicator string) {
+		runtimes = append(runtimes, rt)
+		indicators = append(indicators, indicator)
+	}
+
+	if _, err := fs.Stat(root, ".dockerenv"); err == nil {
+		found(RuntimeDocker, "/.dockerenv present")
+	}
+	if _, err := fs.Stat(root, "run/.containerenv"); err == nil {
+		found(RuntimePodman, "/run/.containerenv present")
+	}
+	if getenv("KUBERNETES_SERVICE_HOST") != "" {
+		found(RuntimeKubernetes, "KUBERNETES_SERVICE_HOST set")
+	}
+	if v := getenv("container"); v != "" {
+		// Set by systemd-nspawn, podman, and LXC for the container's init
+		found(containerEnvRuntime(v), "container="+v+" set")
+	}
+	if data, err := readFSFile(root, "proc/1/cgroup"); err == nil {
+		if rt := parseCgroupRuntime(data); rt != "" {
+			found(rt, "/proc/1/cgroup names "+rt)
+		}
+	}
+	if data, err := readFSFile(root, "proc/self/mountinfo"); err == nil && overlayRoot(data) {
+		found(RuntimeUnknown, "root filesystem is overlayfs")
+	}
+
+	if len(runtimes) == 0 {
+		return "", nil
+	}
+	// Prefer the orchestrator, then a named runtime over generic evidence
+	for _, rt := range []string{RuntimeKubernetes, RuntimePodman, RuntimeDocker, RuntimeContainerd, RuntimeLXC} {
+		for _, r := range runtimes {
+			if r == rt {
+				return rt, indicators
+			}
+		}
+	}
+	return RuntimeUnknown, indicators
+}
+
+// containerEnvRuntime maps the value of the container environment variable
+func containerEnvRuntime(v string) string {
+	switch v {
+	case "podman", "oci":
+		return RuntimePodman
+	case "docker":
+		return RuntimeDocker
+	case "lxc", "lxc-libvirt":
+		return RuntimeLXC
+	}
+	return RuntimeUnknown
+}
+
+// parseCgroupRuntime returns the runtime named in the cgroup paths of
+// /proc/1/cgroup, or "" if PID 1 is in a host cgroup. With cgroup v2
+// namespaces the path is just "/", so this only catches cgroup v1 and
+// hosts without cgroup namespaces.
+func parseCgroupRuntime(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// hierarchy-ID:controllers:path
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		p := fields[2]
+		switch {
+		case strings.Contains(p, "kubepods"):
+			return RuntimeKubernetes
+		case strings.Contains(p, "/docker/") || strings.Contains(p, "docker-"):
+			return RuntimeDocker
+		case strings.Contains(p, "libpod"):
+			return RuntimePodman
+		case strings.Contains(p, "containerd"):
+			return RuntimeContainerd
+		case strings.Contains(p, "/lxc/") || strings.Contains(p, "lxc.payload"):
+			return RuntimeLXC
+		}
+	}
+	return ""
+}
+
+// overlayRoot reports whether / is an overlay mount in /proc/self/mountinfo,
+// as it is for image-based containers but almost never on a host
+func overlayRoot(data []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// id parent major:minor root mountpoint options [optional...] - fstype source super
+		pre, post, ok := strings.Cut(scanner.Text(), " - ")
+		if !ok {
+			continue
+		}
+		fields, fsFields := strings.Fields(pre), strings.Fields(post)
+		if len(fields) >= 5 && fields[4] == "/" && len(fsFields) > 0 {
+			return fsFields[0] == "overlay"
+		}
+	}
+	return false
+}
+
+// FormatRuntimeEnvironmentTable formats the runtime environment as a colored table
+func FormatRuntimeEnvironmentTable(result *RuntimeEnvironment) string {
+	return FormatRuntimeEnvironmentTableWith(CurrentStyler(), result)
+}
+
+// FormatRuntimeEnvironmentTableWith is like FormatRuntimeEnvironmentTable
+// but styles the table with st
+func FormatRuntimeEnvironmentTableWith(st Styler, result *RuntimeEnvironment) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconInfo + " " + T("Runtime Environment")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(st.TableTop(20, 25))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Property"), 20)),
+		st.Header(PadRight(T("Value"), 25)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(20, 25))
+	sb.WriteString("\n")
+
+	sb.WriteString(st.TableRowColored(PadRight(T("Platform"), 20), PadRight(result.Platform, 25)))
+	sb.WriteString("\n")
+	containerized := st.Success(T("No"))
+	if result.Containerized {
+		containerized = st.Warning(T("Yes"))
+	}
+	sb.WriteString(st.TableRowColored(PadRight(T("Containerized"), 20), PadRight(containerized, 25)))
+	sb.WriteString("\n")
+	if result.Runtime != "" {
+		sb.WriteString(st.TableRowColored(PadRight(T("Runtime"), 20), PadRight(result.Runtime, 25)))
+		sb.WriteString("\n")
+	}
+	if wsl := result.WSL; wsl != nil {
+		sb.WriteString(st.TableRowColored(PadRight("WSL", 20), PadRight(st.Warning(fmt.Sprintf("WSL%d", wsl.Version)), 25)))
+		sb.WriteString("\n")
+		if wsl.Distro != "" {
+			sb.WriteString(st.TableRowColored(PadRight(T("Distribution"), 20), PadRight(wsl.Distro, 25)))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(st.TableRowColored(PadRight(T("Interop"), 20), PadRight(st.BoolToStatusColored(wsl.Interop), 25)))
+		sb.WriteString("\n")
+		if h := wsl.Host; h != nil {
+			sb.WriteString(st.TableRowColored(PadRight(T("Host TPM"), 20), PadRight(hintValue(h.TPM), 25)))
+			sb.WriteString("\n")
+			sb.WriteString(st.TableRowColored(PadRight(T("Host Secure Boot"), 20), PadRight(hintValue(h.SecureBoot), 25)))
+			sb.WriteString("\n")
+			sb.WriteString(st.TableRowColored(PadRight(T("Host BitLocker (C:)"), 20), PadRight(hintValue(h.BitLocker), 25)))
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString(st.TableBottom(20, 25))
+	sb.WriteString("\n")
+
+	if len(result.Indicators) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText(T("Indicators:")))
+		sb.WriteString("\n")
+		for _, ind := range result.Indicators {
+			sb.WriteString(fmt.Sprintf("  %s %s\n", IconArrow, ind))
+		}
+	}
+	if result.WSL != nil {
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted("  " + T("Host-only checks describe the Linux guest and are reported as %s", StatusNotApplicableInWSL)))
+		sb.WriteString("\n")
+		if result.WSL.Host != nil && result.WSL.Host.Error != nil {
+			sb.WriteString(st.Muted("  " + T("Windows host query failed: %s", ErrorMessage(result.WSL.Host.Error))))
+			sb.WriteString("\n")
+		}
+	}
+	if result.Containerized {
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted("  " + T("Host-only checks are reported as %s: %s", StatusNotApplicableInContainer, strings.Join(result.HostOnlyChecks, ", "))))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted("  " + T("Set %s=1 if host devices and mounts are passed through", AssumeHostEnv)))
+		sb.WriteString("\n")
+	}
+	if result.AssumeHost {
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted("  " + T("Container detection disabled by %s", AssumeHostEnv)))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// FormatRuntimeEnvironment formats the runtime environment in the specified format
+func FormatRuntimeEnvironment(result *RuntimeEnvironment, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatRuntimeEnvironmentTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,148 @@
+package inspector
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDetectContainer(t *testing.T) {
+	noEnv := func(string) string { return "" }
+	tests := []struct {
+		name        string
+		root        fstest.MapFS
+		env         map[string]string
+		wantRuntime string
+	}{
+		{
+			name: "host",
+			root: fstest.MapFS{
+				"proc/1/cgroup":       {Data: []byte("0::/init.scope\n")},
+				"proc/self/mountinfo": {Data: []byte("28 1 254:0 / / rw,relatime shared:1 - ext4 /dev/nvme0n1p2 rw\n")},
+			},
+		},
+		{
+			name:        "docker marker",
+			root:        fstest.MapFS{".dockerenv": {}},
+			wantRuntime: RuntimeDocker,
+		},
+		{
+			name:        "podman marker",
+			root:        fstest.MapFS{"run/.containerenv": {}},
+			env:         map[string]string{"container": "podman"},
+			wantRuntime: RuntimePodman,
+		},
+		{
+			name: "kubernetes cgroup v1",
+			root: fstest.MapFS{
+				"proc/1/cgroup": {Data: []byte("12:memory:/kubepods/burstable/pod1234/abcd\n0::/\n")},
+			},
+			wantRuntime: RuntimeKubernetes,
+		},
+		{
+			name:        "kubernetes wins over docker",
+			root:        fstest.MapFS{".dockerenv": {}},
+			env:         map[string]string{"KUBERNETES_SERVICE_HOST": "10.96.0.1"},
+			wantRuntime: RuntimeKubernetes,
+		},
+		{
+			name: "overlay root only",
+			root: fstest.MapFS{
+				"proc/self/mountinfo": {Data: []byte("600 500 0:52 / / rw,relatime master:1 - overlay overlay rw,lowerdir=/var/lib/x\n")},
+			},
+			wantRuntime: RuntimeUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := noEnv
+			if tt.env != nil {
+				getenv = func(k string) string { return tt.env[k] }
+			}
+			rt, indicators := detectContainer(tt.root, getenv)
+			if rt != tt.wantRuntime {
+				t.Errorf("runtime = %q, want %q (indicators %v)", rt, tt.wantRuntime, indicators)
+			}
+			if (rt == "") != (len(indicators) == 0) {
+				t.Errorf("indicators = %v for runtime %q", indicators, rt)
+			}
+		})
+	}
+}
+
+func TestParseCgroupRuntime(t *testing.T) {
+	tests := map[string]string{
+		"0::/":                                   "",
+		"11:devices:/docker/3f2a9c":              RuntimeDocker,
+		"0::/system.slice/docker-3f2a9c.scope":   RuntimeDocker,
+		"0::/machine.slice/libpod-3f2a9c.scope":  RuntimePodman,
+		"3:cpu:/system.slice/containerd.service": RuntimeContainerd,
+		"0::/lxc.payload.web01":                  RuntimeLXC,
+	}
+	for cgroup, want := range tests {
+		if got := parseCgroupRuntime([]byte(cgroup + "\n")); got != want {
+			t.Errorf("parseCgroupRuntime(%q) = %q, want %q", cgroup, got, want)
+		}
+	}
+}
+
+// stubContainer makes GetRuntimeEnvironment detect a Docker container
+func stubContainer(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("container detection is Linux-only")
+	}
+	t.Setenv(AssumeHostEnv, "")
+	prev := environmentRoot
+	t.Cleanup(func() { environmentRoot = prev })
+	environmentRoot = fstest.MapFS{".dockerenv": {}}
+}
+
+func TestGetRuntimeEnvironment_AssumeHost(t *testing.T) {
+	stubContainer(t)
+	t.Setenv(AssumeHostEnv, "1")
+
+	env := GetRuntimeEnvironmentContext(context.Background())
+	if env.Containerized || !env.AssumeHost {
+		t.Errorf("env = %+v, want not containerized with assume_host", env)
+	}
+}
+
+func TestGetSecuritySummary_Container(t *testing.T) {
+	stubContainer(t)
+	t.Setenv(OnlyChecksEnv, "")
+	t.Setenv(DisableChecksEnv, "")
+	t.Setenv(USBStoragePolicyEnv, USBStoragePolicyBlock)
+	t.Setenv(MandatoryChecksEnv, "tpm")
+	t.Setenv(InformationalChecksEnv, "")
+
+	result, err := GetSecuritySummaryContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetSecuritySummary failed: %v", err)
+	}
+	if result.Environment == nil || result.Environment.Runtime != RuntimeDocker {
+		t.Fatalf("Environment = %+v, want docker", result.Environment)
+	}
+	if result.TPM != nil || result.SecureBoot != nil || result.Encryption != nil || result.Biometrics != nil {
+		t.Error("host-only checks should not run in a container")
+	}
+	for _, id := range hostOnlyChecks {
+		// Checks of other platforms are never run
+		if !slices.Contains(PlatformChecks(), id) {
+			continue
+		}
+		if result.NotApplicable[id] != StatusNotApplicableInContainer {
+			t.Errorf("NotApplicable[%q] = %q, want %q", id, result.NotApplicable[id], StatusNotApplicableInContainer)
+		}
+	}
+	// A mandatory host-only check is not a failure when it cannot apply
+	if len(result.MandatoryFailures) != 0 || result.OverallStatus != StatusNotApplicableInContainer {
+		t.Errorf("status = %q, mandatory failures = %v", result.OverallStatus, result.MandatoryFailures)
+	}
+	if !slices.Contains(result.Environment.HostOnlyChecks, CheckTPM) {
+		t.Errorf("HostOnlyChecks = %v", result.Environment.HostOnlyChecks)
+	}
+}
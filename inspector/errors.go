@@ -0,0 +1,197 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrorCode is a machine-readable classification of a probe failure
+type ErrorCode string
+
+// Error codes reported in the `error` field of results
+const (
+	CodePermissionDenied    ErrorCode = "permission_denied"
+	CodeToolMissing         ErrorCode = "tool_missing"
+	CodeUnsupportedPlatform ErrorCode = "unsupported_platform"
+	CodeProbeFailed         ErrorCode = "probe_failed"
+	CodeCheckDisabled       ErrorCode = "check_disabled"
+	CodeTimeout             ErrorCode = "timeout"
+)
+
+// Sentinel errors for use with errors.Is
+var (
+	ErrPermissionDenied    = errors.New("permission denied")
+	ErrToolMissing         = errors.New("required tool not found")
+	ErrUnsupportedPlatform = errors.New("not supported on this platform")
+	ErrProbeFailed         = errors.New("probe failed")
+	ErrCheckDisabled       = errors.New("check disabled")
+	ErrTimeout             = errors.New("check timed out")
+)
+
+// errorCodes maps sentinel errors to their codes
+var errorCodes = map[error]ErrorCode{
+	ErrPermissionDenied:    CodePermissionDenied,
+	ErrToolMissing:         CodeToolMissing,
+	ErrUnsupportedPlatform: CodeUnsupportedPlatform,
+	ErrProbeFailed:         CodeProbeFailed,
+	ErrCheckDisabled:       CodeCheckDisabled,
+	ErrTimeout:             CodeTimeout,
+}
+
+// ProbeError describes why a probe could not produce a complete result.
+// It is returned as an error for hard failures and embedded in results
+// (as the `error` field) when a probe degrades to a partial answer.
+type ProbeError struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	Probe   string    `json:"probe,omitempty"`
+	Hint    string    `json:"hint,omitempty"`
+
+	kind error
+}
+
+// Error implements the error interface
+func (e *ProbeError) Error() string {
+	if e.Probe != "" {
+		return fmt.Sprintf("%s: %s", e.Probe, e.Message)
+	}
+	return e.Message
+}
+
+// Unwrap returns the sentinel error so errors.Is(err, ErrPermissionDenied) works
+func (e *ProbeError) Unwrap() error {
+	return e.kind
+}
+
+// newProbeError creates a ProbeError of the given kind with the default hint
+// and logs it to ctx's logger
+func newProbeError(ctx context.Context, kind error, probe, message string) *ProbeError {
+	pe := probeError(kind, probe, message)
+	// Disabled and unsupported checks are expected; other errors degrade a result
+	level := slog.LevelInfo
+	if pe.kind == ErrCheckDisabled || pe.kind == ErrUnsupportedPlatform {
+		level = slog.LevelDebug
+	}
+	loggerFrom(ctx).Log(ctx, level, "probe degraded", "probe", probe, "code", pe.Code, "message", message)
+	return pe
+}
+
+// probeError creates a ProbeError of the given kind with the default hint,
+// without logging it
+func probeError(kind error, probe, message string) *ProbeError {
+	code, ok := errorCodes[kind]
+	if !ok {
+		kind, code = ErrProbeFailed, CodeProbeFailed
+	}
+	return &ProbeError{
+		Code:    code,
+		Message: message,
+		Probe:   probe,
+		Hint:    defaultHint(kind, probe),
+		kind:    kind,
+	}
+}
+
+// defaultHint returns actionable guidance for an error kind
+func defaultHint(kind error, probe string) string {
+	switch kind {
+	case ErrPermissionDenied:
+		return elevationHint()
+	case ErrToolMissing:
+		if probe != "" {
+			return T("Install %s and make sure it is in PATH", probe)
+		}
+		return T("Install the required tool and make sure it is in PATH")
+	case ErrUnsupportedPlatform:
+		return T("This check is not available on %s", runtime.GOOS)
+	case ErrCheckDisabled:
+		return T("Remove it from %s or add it to %s", DisableChecksEnv, OnlyChecksEnv)
+	case ErrTimeout:
+		return T("Raise its timeout with %s, e.g. %s=%s", CheckTimeoutsEnv, probe, "30s")
+	}
+	return ""
+}
+
+// elevationHint returns the platform-specific advice for privilege errors
+func elevationHint() string {
+	if runtime.GOOS == "windows" {
+		return T("Re-run from an elevated (Run as Administrator) prompt")
+	}
+	return T("Re-run with sudo")
+}
+
+// permissionPatterns are substrings external tools print when they need root
+var permissionPatterns = []string{
+	"permission denied",
+	"operation not permitted",
+	"must be run as root",
+	"must be root",
+	"requires root",
+	"only root can",
+	"access denied",
+	"access is denied", // Windows tools, including through WSL interop
+	"not privileged",
+}
+
+// classifyExecError turns an error from running an external tool into a ProbeError
+func classifyExecError(ctx context.Context, tool string, err error) *ProbeError {
+	if errors.Is(err, exec.ErrNotFound) {
+		return newProbeError(ctx, ErrToolMissing, tool, fmt.Sprintf("%s is not installed", tool))
+	}
+	if errors.Is(err, os.ErrPermission) {
+		return newProbeError(ctx, ErrPermissionDenied, tool, "insufficient privileges to run "+tool)
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		stderr := strings.ToLower(string(exitErr.Stderr))
+		for _, p := range permissionPatterns {
+			if strings.Contains(stderr, p) {
+				return newProbeError(ctx, ErrPermissionDenied, tool, "insufficient privileges to run "+tool)
+			}
+		}
+		if msg := strings.TrimSpace(string(exitErr.Stderr)); msg != "" {
+			return newProbeError(ctx, ErrProbeFailed, tool, msg)
+		}
+	}
+	return newProbeError(ctx, ErrProbeFailed, tool, err.Error())
+}
+
+// classifyFileError turns an error reading a system file into a ProbeError
+func classifyFileError(ctx context.Context, path string, err error) *ProbeError {
+	if errors.Is(err, os.ErrPermission) {
+		return newProbeError(ctx, ErrPermissionDenied, path, "insufficient privileges to read "+path)
+	}
+	return newProbeError(ctx, ErrProbeFailed, path, err.Error())
+}
+
+// ErrorMessage returns a user-facing message for err, including the
+// actionable hint when err is (or wraps) a ProbeError
+func ErrorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	var pe *ProbeError
+	if errors.As(err, &pe) && pe.Hint != "" {
+		return fmt.Sprintf("%s (%s)", err.Error(), pe.Hint)
+	}
+	return err.Error()
+}
+
+// formatProbeError renders a degraded-result notice for table output
+func formatProbeError(st Styler, e *ProbeError) string {
+	if e == nil {
+		return ""
+	}
+	line := "\n" + st.Warning(IconWarning+" "+e.Error())
+	if e.Hint != "" {
+		line += "\n" + st.Muted("   "+IconArrow+" "+e.Hint)
+	}
+	return line + "\n"
+}
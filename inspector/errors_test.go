@@ -0,0 +1,112 @@
+package inspector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestProbeError_Is(t *testing.T) {
+	tests := []struct {
+		kind error
+		code ErrorCode
+	}{
+		{ErrPermissionDenied, CodePermissionDenied},
+		{ErrToolMissing, CodeToolMissing},
+		{ErrUnsupportedPlatform, CodeUnsupportedPlatform},
+		{ErrProbeFailed, CodeProbeFailed},
+		{ErrCheckDisabled, CodeCheckDisabled},
+		{ErrTimeout, CodeTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", newProbeError(context.Background(), tt.kind, "probe", "message"))
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", err, tt.kind)
+			}
+			var pe *ProbeError
+			if !errors.As(err, &pe) || pe.Code != tt.code {
+				t.Errorf("Code = %q, want %q", pe.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestProbeError_UnknownKind(t *testing.T) {
+	err := newProbeError(context.Background(), errors.New("other"), "probe", "message")
+	if err.Code != CodeProbeFailed || !errors.Is(err, ErrProbeFailed) {
+		t.Errorf("Code = %q, want %q", err.Code, CodeProbeFailed)
+	}
+}
+
+func TestProbeError_JSON(t *testing.T) {
+	err := newProbeError(context.Background(), ErrPermissionDenied, "fdesetup", "insufficient privileges to run fdesetup")
+	data, jsonErr := json.Marshal(struct {
+		Error *ProbeError `json:"error,omitempty"`
+	}{err})
+	if jsonErr != nil {
+		t.Fatalf("json.Marshal failed: %v", jsonErr)
+	}
+
+	var decoded map[string]map[string]string
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal failed: %v", err)
+	}
+	if got := decoded["error"]["code"]; got != "permission_denied" {
+		t.Errorf("code = %q, want permission_denied", got)
+	}
+	if decoded["error"]["hint"] == "" {
+		t.Error("hint should be set for permission errors")
+	}
+}
+
+func TestClassifyExecError(t *testing.T) {
+	_, err := exec.LookPath("omnitrust-no-such-tool")
+	if got := classifyExecError(context.Background(), "omnitrust-no-such-tool", err); got.Code != CodeToolMissing {
+		t.Errorf("missing tool: Code = %q, want %q", got.Code, CodeToolMissing)
+	}
+
+	if got := classifyExecError(context.Background(), "tool", os.ErrPermission); got.Code != CodePermissionDenied {
+		t.Errorf("EPERM: Code = %q, want %q", got.Code, CodePermissionDenied)
+	}
+
+	exitErr := &exec.ExitError{Stderr: []byte("Error: This command must be run as root.\n")}
+	if got := classifyExecError(context.Background(), "tool", exitErr); got.Code != CodePermissionDenied {
+		t.Errorf("root required: Code = %q, want %q", got.Code, CodePermissionDenied)
+	}
+
+	exitErr = &exec.ExitError{Stderr: []byte("device busy\n")}
+	got := classifyExecError(context.Background(), "tool", exitErr)
+	if got.Code != CodeProbeFailed || got.Message != "device busy" {
+		t.Errorf("other failure = (%q, %q), want (%q, %q)", got.Code, got.Message, CodeProbeFailed, "device busy")
+	}
+}
+
+func TestClassifyFileError(t *testing.T) {
+	if got := classifyFileError(context.Background(), "/x", &os.PathError{Op: "open", Path: "/x", Err: os.ErrPermission}); got.Code != CodePermissionDenied {
+		t.Errorf("Code = %q, want %q", got.Code, CodePermissionDenied)
+	}
+	if got := classifyFileError(context.Background(), "/x", os.ErrNotExist); got.Code != CodeProbeFailed {
+		t.Errorf("Code = %q, want %q", got.Code, CodeProbeFailed)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	if got := ErrorMessage(nil); got != "" {
+		t.Errorf("ErrorMessage(nil) = %q, want empty", got)
+	}
+	if got := ErrorMessage(errors.New("plain")); got != "plain" {
+		t.Errorf("ErrorMessage(plain) = %q, want %q", got, "plain")
+	}
+
+	got := ErrorMessage(newProbeError(context.Background(), ErrPermissionDenied, "bputil", "insufficient privileges to run bputil"))
+	if !strings.Contains(got, "bputil") || !strings.Contains(got, elevationHint()) {
+		t.Errorf("ErrorMessage = %q, want probe and elevation hint", got)
+	}
+}
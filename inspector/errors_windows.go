@@ -0,0 +1,21 @@
+//go:build windows
+
+package inspector
+
+import (
+	"context"
+	"strings"
+)
+
+// wbemAccessDenied is the WMI WBEM_E_ACCESS_DENIED HRESULT
+const wbemAccessDenied = "0x80041003"
+
+// classifyWMIError turns an error from a WMI query into a ProbeError
+func classifyWMIError(ctx context.Context, namespace string, err error) *ProbeError {
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "access denied") || strings.Contains(msg, "access is denied") ||
+		strings.Contains(msg, wbemAccessDenied) {
+		return newProbeError(ctx, ErrPermissionDenied, namespace, "access denied querying WMI")
+	}
+	return newProbeError(ctx, ErrProbeFailed, namespace, err.Error())
+}
@@ -0,0 +1,296 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/agentplexus/posture/render"
+)
+
+// DefaultFDTopN is the number of processes GetFDUsage returns by default
+const DefaultFDTopN = 10
+
+// ProcessFDs is one process's open file descriptors (handles on Windows)
+type ProcessFDs struct {
+	PID  int32  `json:"pid"`
+	Name string `json:"name"`
+	FDs  int32  `json:"fds"`
+	// SoftLimit is the process's RLIMIT_NOFILE soft limit, where readable
+	SoftLimit uint64 `json:"soft_limit,omitempty"`
+	// UsedPercent is FDs as a percentage of SoftLimit
+	UsedPercent float64 `json:"used_percent,omitempty"`
+}
+
+// Ulimit is a resource limit of this process. -1 means unlimited.
+type Ulimit struct {
+	Resource string `json:"resource"`
+	Soft     int64  `json:"soft"`
+	Hard     int64  `json:"hard"`
+}
+
+// FDUsageResult reports open files against the system and per-process
+// limits, the processes holding the most descriptors, and this process's
+// resource limits
+type FDUsageResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string `json:"platform"`
+	// OpenFiles is the number of open files system-wide (Linux, macOS)
+	OpenFiles uint64 `json:"open_files,omitempty"`
+	// MaxFiles is the system-wide limit; zero if unlimited or unknown
+	MaxFiles    uint64  `json:"max_files,omitempty"`
+	UsedPercent float64 `json:"used_percent,omitempty"`
+	// MaxFilesPerProcess is the ceiling for any process's limit
+	// (fs.nr_open on Linux, kern.maxfilesperproc on macOS)
+	MaxFilesPerProcess uint64       `json:"max_files_per_process,omitempty"`
+	Processes          []ProcessFDs `json:"processes"`
+	// Inaccessible counts processes whose descriptors could not be
+	// counted, usually other users' processes without root
+	Inaccessible int `json:"inaccessible"`
+	// Ulimits are the limits of this process, which its children inherit
+	Ulimits []Ulimit    `json:"ulimits"`
+	Error   *ProbeError `json:"error,omitempty"`
+}
+
+// fileCounts holds the system-wide open file counters
+type fileCounts struct {
+	open, max, perProcessMax uint64
+}
+
+// GetFDUsage returns open file descriptor counts and limits, with the n
+// processes holding the most descriptors (DefaultFDTopN when n <= 0)
+func GetFDUsage(ctx context.Context, n int) (*FDUsageResult, error) {
+	if n <= 0 {
+		n = DefaultFDTopN
+	}
+	result := &FDUsageResult{Platform: runtime.GOOS}
+
+	counts, perr := systemFileCounts(ctx)
+	result.Error = perr
+	result.OpenFiles = counts.open
+	result.MaxFiles = counts.max
+	result.MaxFilesPerProcess = counts.perProcessMax
+	if counts.max > 0 {
+		result.UsedPercent = float64(counts.open) / float64(counts.max) * 100
+	}
+
+	usage, inaccessible, perr := processFDCounts(ctx)
+	if result.Error == nil {
+		result.Error = perr
+	}
+	result.Inaccessible = inaccessible
+	result.Processes = rankByFDs(usage, n)
+	for i := range result.Processes {
+		p := &result.Processes[i]
+		p.SoftLimit = processFDLimit(ctx, p.PID)
+		if p.SoftLimit > 0 {
+			p.UsedPercent = float64(p.FDs) / float64(p.SoftLimit) * 100
+		}
+	}
+
+	result.Ulimits = processUlimits()
+	if result.Ulimits == nil {
+		result.Ulimits = []Ulimit{}
+	}
+	return result, nil
+}
+
+// rankByFDs keeps the n processes holding the most descriptors
+func rankByFDs(usage []ProcessFDs, n int) []ProcessFDs {
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].FDs != usage[j].FDs {
+			return usage[i].FDs > usage[j].FDs
+		}
+		return usage[i].PID < usage[j].PID
+	})
+	if n < len(usage) {
+		usage = usage[:n]
+	}
+	return append([]ProcessFDs{}, usage...)
+}
+
+// parseFileNr parses /proc/sys/fs/file-nr: allocated handles, allocated
+// but unused handles, and the maximum. Kernels without a limit report
+// LONG_MAX, which is returned as zero.
+func parseFileNr(data []byte) (open, max uint64, err error) {
+	fields := strings.Fields(string(data))
+	if len(fields) != 3 {
+		return 0, 0, fmt.Errorf("file-nr: unexpected format %q", strings.TrimSpace(string(data)))
+	}
+	var v [3]uint64
+	for i, f := range fields {
+		if v[i], err = strconv.ParseUint(f, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("file-nr: %w", err)
+		}
+	}
+	if v[2] >= math.MaxInt64 {
+		v[2] = 0
+	}
+	return v[0] - min(v[1], v[0]), v[2], nil
+}
+
+// parseLsofFDs counts the descriptors per process in `lsof -F pcf` output,
+// where each process starts with a p (PID) line, then a c (command) line,
+// and each open file has an f (descriptor) line
+func parseLsofFDs(data []byte) []ProcessFDs {
+	var usage []ProcessFDs
+	var cur *ProcessFDs
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case 'p':
+			pid, err := strconv.ParseInt(line[1:], 10, 32)
+			if err != nil {
+				cur = nil
+				continue
+			}
+			usage = append(usage, ProcessFDs{PID: int32(pid)})
+			cur = &usage[len(usage)-1]
+		case 'c':
+			if cur != nil {
+				cur.Name = line[1:]
+			}
+		case 'f':
+			if cur != nil {
+				cur.FDs++
+			}
+		}
+	}
+	return usage
+}
+
+// formatUlimit formats a limit value, -1 as unlimited
+func formatUlimit(v int64) string {
+	if v < 0 {
+		return "unlimited"
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+// FormatFDUsageTable formats file descriptor usage as a colored table
+func FormatFDUsageTable(result *FDUsageResult) string {
+	return FormatFDUsageTableWith(CurrentStyler(), result)
+}
+
+// FormatFDUsageTableWith is like FormatFDUsageTable but styles the table
+// with st
+func FormatFDUsageTableWith(st Styler, result *FDUsageResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconProcess + " File Descriptors"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n\n")
+	}
+
+	if result.OpenFiles > 0 {
+		sb.WriteString(st.BoldText("Open files: "))
+		if result.MaxFiles > 0 {
+			usageStyle := render.UsageStyle(result.UsedPercent)
+			sb.WriteString(st.Styled(usageStyle, st.BoldText(fmt.Sprintf("%d", result.OpenFiles))))
+			sb.WriteString(st.Muted(" of "))
+			sb.WriteString(st.Info(fmt.Sprintf("%d", result.MaxFiles)))
+			sb.WriteString("\n")
+			sb.WriteString(st.ProgressBar(result.UsedPercent, 40))
+		} else {
+			sb.WriteString(st.Info(fmt.Sprintf("%d", result.OpenFiles)))
+			sb.WriteString(st.Muted(" (no system-wide limit)"))
+		}
+		sb.WriteString("\n")
+		if result.MaxFilesPerProcess > 0 {
+			sb.WriteString(st.Muted(fmt.Sprintf("Per-process ceiling: %d", result.MaxFilesPerProcess)))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	unit := "FDs"
+	if result.Platform == "windows" {
+		unit = "Handles"
+	}
+	sb.WriteString(st.BoldText(fmt.Sprintf("Top %d Processes by Open %s:", len(result.Processes), unit)))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableTop(8, 28, 10, 18))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight("PID", 8)),
+		st.Header(PadRight("Name", 28)),
+		st.Header(PadLeft(unit, 10)),
+		st.Header(PadRight("Of Soft Limit", 18)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(8, 28, 10, 18))
+	sb.WriteString("\n")
+	for _, p := range result.Processes {
+		name := p.Name
+		if len(name) > 28 {
+			name = name[:25] + "..."
+		}
+		limit := st.Muted(PadRight("-", 18))
+		if p.SoftLimit > 0 {
+			limit = PadRight(st.ProgressBar(p.UsedPercent, 10)+fmt.Sprintf(" %5.1f%%", p.UsedPercent), 18)
+		}
+		sb.WriteString(st.TableRowColored(
+			st.Info(PadRight(fmt.Sprintf("%d", p.PID), 8)),
+			PadRight(name, 28),
+			st.Styled(render.UsageStyle(p.UsedPercent), PadLeft(fmt.Sprintf("%d", p.FDs), 10)),
+			limit,
+		))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(st.TableBottom(8, 28, 10, 18))
+	sb.WriteString("\n")
+	if result.Inaccessible > 0 {
+		sb.WriteString(st.Muted(fmt.Sprintf("  %d processes could not be inspected; run as root to include them", result.Inaccessible)))
+		sb.WriteString("\n")
+	}
+
+	if len(result.Ulimits) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText("Limits of this process:"))
+		sb.WriteString("\n")
+		sb.WriteString(st.TableTop(12, 14, 14))
+		sb.WriteString("\n")
+		sb.WriteString(st.TableRowColored(
+			st.Header(PadRight("Resource", 12)),
+			st.Header(PadLeft("Soft", 14)),
+			st.Header(PadLeft("Hard", 14)),
+		))
+		sb.WriteString("\n")
+		sb.WriteString(st.TableSeparator(12, 14, 14))
+		sb.WriteString("\n")
+		for _, l := range result.Ulimits {
+			sb.WriteString(st.TableRowColored(
+				st.Info(PadRight(l.Resource, 12)),
+				PadLeft(formatUlimit(l.Soft), 14),
+				st.Muted(PadLeft(formatUlimit(l.Hard), 14)),
+			))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(st.TableBottom(12, 14, 14))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// FormatFDUsage formats file descriptor usage in the specified format
+func FormatFDUsage(result *FDUsageResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatFDUsageTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,43 @@
+//go:build darwin
+
+package inspector
+
+import (
+	"context"
+
+	"golang.org/x/sys/unix"
+)
+
+// systemFileCounts reads the kernel's open file counters
+func systemFileCounts(ctx context.Context) (fileCounts, *ProbeError) {
+	var counts fileCounts
+	open, err := unix.SysctlUint32("kern.num_files")
+	if err != nil {
+		return counts, newProbeError(ctx, ErrProbeFailed, "sysctl", "kern.num_files: "+err.Error())
+	}
+	counts.open = uint64(open)
+	if v, err := unix.SysctlUint32("kern.maxfiles"); err == nil {
+		counts.max = uint64(v)
+	}
+	if v, err := unix.SysctlUint32("kern.maxfilesperproc"); err == nil {
+		counts.perProcessMax = uint64(v)
+	}
+	return counts, nil
+}
+
+// processFDCounts counts each process's descriptors with lsof, which lists
+// only the current user's processes without root
+func processFDCounts(ctx context.Context) ([]ProcessFDs, int, *ProbeError) {
+	out, err := runCommand(ctx, "lsof", "-n", "-P", "-F", "pcf")
+	if err != nil && len(out) == 0 {
+		return nil, 0, classifyExecError(ctx, "lsof", err)
+	}
+	// lsof exits 1 when some processes could not be read but still lists
+	// the others
+	return parseLsofFDs(out), 0, nil
+}
+
+// processFDLimit is not readable for other processes on macOS
+func processFDLimit(ctx context.Context, pid int32) uint64 {
+	return 0
+}
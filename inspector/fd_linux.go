@@ -0,0 +1,70 @@
+//go:build linux
+
+package inspector
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// systemFileCounts reads the kernel's file handle counters
+func systemFileCounts(ctx context.Context) (fileCounts, *ProbeError) {
+	var counts fileCounts
+	data, err := readFile("/proc/sys/fs/file-nr")
+	if err != nil {
+		return counts, classifyFileError(ctx, "/proc/sys/fs/file-nr", err)
+	}
+	if counts.open, counts.max, err = parseFileNr(data); err != nil {
+		return counts, newProbeError(ctx, ErrProbeFailed, "/proc/sys/fs/file-nr", err.Error())
+	}
+	if data, err := readFile("/proc/sys/fs/nr_open"); err == nil {
+		counts.perProcessMax, _ = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	}
+	return counts, nil
+}
+
+// processFDCounts counts the entries of each process's /proc/<pid>/fd
+func processFDCounts(ctx context.Context) ([]ProcessFDs, int, *ProbeError) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, 0, newProbeError(ctx, ErrProbeFailed, "/proc", err.Error())
+	}
+	var usage []ProcessFDs
+	inaccessible := 0
+	for _, p := range procs {
+		n, err := p.NumFDsWithContext(ctx)
+		if err != nil {
+			// Other users' processes cannot be read without root
+			if os.IsPermission(err) {
+				inaccessible++
+			}
+			continue
+		}
+		name, _ := p.NameWithContext(ctx)
+		usage = append(usage, ProcessFDs{PID: p.Pid, Name: name, FDs: n})
+	}
+	return usage, inaccessible, nil
+}
+
+// processFDLimit returns a process's RLIMIT_NOFILE soft limit from
+// /proc/<pid>/limits, or 0 if unreadable
+func processFDLimit(ctx context.Context, pid int32) uint64 {
+	p, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return 0
+	}
+	limits, err := p.RlimitWithContext(ctx)
+	if err != nil {
+		return 0
+	}
+	for _, l := range limits {
+		if l.Resource == process.RLIMIT_NOFILE {
+			return l.Soft
+		}
+	}
+	return 0
+}
@@ -0,0 +1,25 @@
+//go:build !linux && !darwin && !windows
+
+package inspector
+
+import "context"
+
+// systemFileCounts has no source here
+func systemFileCounts(ctx context.Context) (fileCounts, *ProbeError) {
+	return fileCounts{}, nil
+}
+
+// processFDCounts has no source here
+func processFDCounts(ctx context.Context) ([]ProcessFDs, int, *ProbeError) {
+	return nil, 0, nil
+}
+
+// processFDLimit has no source here
+func processFDLimit(ctx context.Context, pid int32) uint64 {
+	return 0
+}
+
+// processUlimits has no source here
+func processUlimits() []Ulimit {
+	return nil
+}
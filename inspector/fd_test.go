@@ -0,0 +1,67 @@
+package inspector
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestParseFileNr(t *testing.T) {
+	tests := []struct {
+		data      string
+		open, max uint64
+		wantErr   bool
+	}{
+		{"12896\t0\t9223372036854775807\n", 12896, 0, false},
+		{"3200\t200\t1617004\n", 3000, 1617004, false},
+		{"3200 0\n", 0, 0, true},
+		{"a b c\n", 0, 0, true},
+	}
+	for _, tt := range tests {
+		open, max, err := parseFileNr([]byte(tt.data))
+		if (err != nil) != tt.wantErr || open != tt.open || max != tt.max {
+			t.Errorf("parseFileNr(%q) = %d, %d, %v; want %d, %d, error %v", tt.data, open, max, err, tt.open, tt.max, tt.wantErr)
+		}
+	}
+}
+
+func TestParseLsofFDs(t *testing.T) {
+	data := []byte("p1\nclaunchd\nfcwd\nftxt\nf0\nf1\np412\ncSafari\nfcwd\nf3\npbogus\nf9\n")
+	want := []ProcessFDs{{PID: 1, Name: "launchd", FDs: 4}, {PID: 412, Name: "Safari", FDs: 2}}
+	if got := parseLsofFDs(data); !slices.Equal(got, want) {
+		t.Errorf("parseLsofFDs = %+v, want %+v", got, want)
+	}
+}
+
+func TestRankByFDs(t *testing.T) {
+	usage := []ProcessFDs{
+		{PID: 30, Name: "sshd", FDs: 12},
+		{PID: 7, Name: "postgres", FDs: 900},
+		{PID: 5, Name: "nginx", FDs: 12},
+		{PID: 2, Name: "chrome", FDs: 410},
+	}
+	got := rankByFDs(usage, 3)
+	var pids []int32
+	for _, p := range got {
+		pids = append(pids, p.PID)
+	}
+	if want := []int32{7, 2, 5}; !slices.Equal(pids, want) {
+		t.Errorf("ranked PIDs = %v, want %v", pids, want)
+	}
+	if got := rankByFDs(nil, 10); got == nil || len(got) != 0 {
+		t.Errorf("rankByFDs(nil) = %#v, want empty slice", got)
+	}
+}
+
+func TestGetFDUsage(t *testing.T) {
+	result, err := GetFDUsage(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("GetFDUsage failed: %v", err)
+	}
+	if len(result.Processes) > 3 || result.Ulimits == nil {
+		t.Errorf("result = %+v", result)
+	}
+	if result.MaxFiles > 0 && result.UsedPercent <= 0 {
+		t.Errorf("UsedPercent = %v with %d of %d open", result.UsedPercent, result.OpenFiles, result.MaxFiles)
+	}
+}
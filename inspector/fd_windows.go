@@ -0,0 +1,49 @@
+//go:build windows
+
+package inspector
+
+import (
+	"context"
+	"os"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// systemFileCounts has no counterpart on Windows, which limits handles per
+// process only by memory
+func systemFileCounts(ctx context.Context) (fileCounts, *ProbeError) {
+	return fileCounts{}, nil
+}
+
+// processFDCounts counts each process's open handles
+func processFDCounts(ctx context.Context) ([]ProcessFDs, int, *ProbeError) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, 0, newProbeError(ctx, ErrProbeFailed, "process list", err.Error())
+	}
+	var usage []ProcessFDs
+	inaccessible := 0
+	for _, p := range procs {
+		n, err := p.NumFDsWithContext(ctx)
+		if err != nil {
+			// Protected and other users' processes cannot be opened
+			if os.IsPermission(err) {
+				inaccessible++
+			}
+			continue
+		}
+		name, _ := p.NameWithContext(ctx)
+		usage = append(usage, ProcessFDs{PID: p.Pid, Name: name, FDs: n})
+	}
+	return usage, inaccessible, nil
+}
+
+// processFDLimit returns 0: Windows has no per-process handle limit to report
+func processFDLimit(ctx context.Context, pid int32) uint64 {
+	return 0
+}
+
+// processUlimits returns nil: Windows has no rlimits
+func processUlimits() []Ulimit {
+	return nil
+}
@@ -0,0 +1,386 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// CheckFilesystem is the check of filesystem audit findings. The audit is
+// run on request and is not part of the summary.
+const CheckFilesystem = "filesystem"
+
+// Environment variables read by DefaultFilesystemAuditOptions
+const (
+	// SetIDAllowlistEnv lists additional allowed SUID/SGID binaries
+	// (comma-separated base names, absolute paths, or path globs)
+	SetIDAllowlistEnv = "OMNITRUST_SETID_ALLOWLIST"
+	// FilesystemAuditPathsEnv replaces the directories searched for SUID/SGID
+	// binaries (colon- or comma-separated absolute paths)
+	FilesystemAuditPathsEnv = "OMNITRUST_FS_AUDIT_PATHS"
+	// FilesystemAuditTimeoutEnv bounds the audit, e.g. "30s"
+	FilesystemAuditTimeoutEnv = "OMNITRUST_FS_AUDIT_TIMEOUT"
+)
+
+// DefaultFilesystemAuditTimeout bounds the audit when no timeout is set
+const DefaultFilesystemAuditTimeout = 10 * time.Second
+
+// setIDMaxDepth is how many directory levels below each audit path are
+// searched; helpers such as /usr/lib/openssh/ssh-keysign sit at depth 2
+const setIDMaxDepth = 3
+
+// DefaultSetIDPaths are the directories searched for SUID/SGID binaries in
+// addition to the directories in PATH
+var DefaultSetIDPaths = []string{
+	"/bin", "/sbin", "/usr/bin", "/usr/sbin", "/usr/local/bin", "/usr/local/sbin",
+	"/usr/libexec", "/usr/lib", "/opt",
+}
+
+// DefaultSetIDAllowlist names the SUID/SGID binaries that distributions
+// ship for logins, privilege elevation, and user namespaces
+var DefaultSetIDAllowlist = []string{
+	"at", "chage", "chfn", "chsh", "crontab", "dbus-daemon-launch-helper",
+	"expiry", "fusermount", "fusermount3", "gpasswd", "mount", "mount.cifs",
+	"mount.nfs", "newgidmap", "newgrp", "newuidmap", "passwd", "pkexec",
+	"polkit-agent-helper-1", "pppd", "sg", "snap-confine", "ssh-agent",
+	"ssh-keysign", "su", "sudo", "sudoedit", "umount", "unix_chkpwd",
+	"utempter", "wall", "write", "Xorg.wrap",
+}
+
+// FilesystemAuditOptions scopes and bounds the filesystem audit
+type FilesystemAuditOptions struct {
+	// Paths are the directories searched for SUID/SGID binaries
+	Paths []string
+	// Allowlist adds allowed SUID/SGID binaries to DefaultSetIDAllowlist:
+	// base names, absolute paths, or path globs such as "/opt/vendor/bin/*"
+	Allowlist []string
+	// Timeout bounds the whole audit; results found before it expires are
+	// returned with Truncated set
+	Timeout time.Duration
+}
+
+// DefaultFilesystemAuditOptions returns the default options, overridden by
+// OMNITRUST_FS_AUDIT_PATHS, OMNITRUST_SETID_ALLOWLIST, and
+// OMNITRUST_FS_AUDIT_TIMEOUT
+func DefaultFilesystemAuditOptions() FilesystemAuditOptions {
+	opts := FilesystemAuditOptions{
+		Paths:   DefaultSetIDPaths,
+		Timeout: DefaultFilesystemAuditTimeout,
+	}
+	split := func(r rune) bool { return r == ',' || r == ':' }
+	if v := os.Getenv(FilesystemAuditPathsEnv); v != "" {
+		opts.Paths = strings.FieldsFunc(v, split)
+	}
+	opts.Allowlist = strings.FieldsFunc(os.Getenv(SetIDAllowlistEnv), func(r rune) bool { return r == ',' })
+	if v := os.Getenv(FilesystemAuditTimeoutEnv); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			opts.Timeout = d
+		}
+	}
+	return opts
+}
+
+// SetIDBinary is a file with the SUID or SGID bit set
+type SetIDBinary struct {
+	Path string `json:"path"`
+	SUID bool   `json:"suid"`
+	SGID bool   `json:"sgid"`
+	// WorldWritable is true if any user can replace the file's contents
+	WorldWritable bool `json:"world_writable"`
+	// Allowed is true if the binary is on the allowlist
+	Allowed bool `json:"allowed"`
+}
+
+// WritablePathDir is a directory in or above a PATH entry that any user
+// can write to, letting them plant or replace commands
+type WritablePathDir struct {
+	Path string `json:"path"`
+	// Sticky is true if only a file's owner may rename or delete it (as in
+	// /tmp); other users can still add new commands
+	Sticky bool `json:"sticky"`
+	// PathEntry is the PATH entry the directory is in or above
+	PathEntry string `json:"path_entry"`
+}
+
+// FilesystemAuditResult reports SUID/SGID binaries and world-writable PATH
+// directories
+type FilesystemAuditResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string `json:"platform"`
+	// ScannedPaths are the directories searched for SUID/SGID binaries
+	ScannedPaths []string `json:"scanned_paths"`
+	// SetIDBinaries lists every SUID/SGID binary found, allowed or not
+	SetIDBinaries []SetIDBinary `json:"setid_binaries"`
+	// WritablePathDirs lists world-writable directories in or above PATH
+	WritablePathDirs []WritablePathDir `json:"writable_path_dirs"`
+	// RelativePathEntries are PATH entries that resolve against the current
+	// directory, such as "." or an empty entry
+	RelativePathEntries []string  `json:"relative_path_entries,omitempty"`
+	Findings            []Finding `json:"findings"`
+	// Truncated is true if the timeout expired before the audit finished
+	Truncated bool `json:"truncated"`
+	// Clean is true if the audit finished without findings
+	Clean bool        `json:"clean"`
+	Error *ProbeError `json:"error,omitempty"`
+}
+
+// AuditFilesystem searches for unexpected SUID/SGID binaries and
+// world-writable directories in PATH
+func AuditFilesystem(opts FilesystemAuditOptions) (*FilesystemAuditResult, error) {
+	return AuditFilesystemContext(context.Background(), opts)
+}
+
+// AuditFilesystemContext is like AuditFilesystem but runs the probes with
+// ctx's deadline, command runner, and logger
+func AuditFilesystemContext(ctx context.Context, opts FilesystemAuditOptions) (*FilesystemAuditResult, error) {
+	if !IsFilesystemAuditSupported() {
+		return nil, newProbeError(ctx, ErrUnsupportedPlatform, "filesystem", "the filesystem audit is only available on Linux")
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	// Resolve symlinks such as /bin -> /usr/bin so each directory is
+	// searched once; PATH directories are always searched
+	pathEntries := filepath.SplitList(os.Getenv("PATH"))
+	var roots []string
+	for _, p := range slices.Concat(opts.Paths, pathEntries) {
+		if !filepath.IsAbs(p) {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(p); err == nil {
+			p = resolved
+		}
+		if !slices.Contains(roots, p) {
+			roots = append(roots, p)
+		}
+	}
+	return auditFilesystem(ctx, os.DirFS("/"), roots, pathEntries, opts.Allowlist), nil
+}
+
+// IsFilesystemAuditSupported returns true on Linux
+func IsFilesystemAuditSupported() bool {
+	return runtime.GOOS == "linux"
+}
+
+// auditFilesystem runs the audit against fsys, the root filesystem, with
+// absolute roots and PATH entries
+func auditFilesystem(ctx context.Context, fsys fs.FS, roots, pathEntries, allowlist []string) *FilesystemAuditResult {
+	result := &FilesystemAuditResult{
+		Platform:         "linux",
+		ScannedPaths:     []string{},
+		SetIDBinaries:    []SetIDBinary{},
+		WritablePathDirs: []WritablePathDir{},
+	}
+	allowlist = slices.Concat(DefaultSetIDAllowlist, allowlist)
+
+	// PATH directories and their parents are few, so check them first
+	for _, entry := range pathEntries {
+		if !path.IsAbs(entry) {
+			result.RelativePathEntries = append(result.RelativePathEntries, entry)
+			continue
+		}
+		for dir := path.Clean(entry); ; dir = path.Dir(dir) {
+			if info, err := fs.Stat(fsys, fsPath(dir)); err == nil && info.IsDir() && info.Mode().Perm()&0o002 != 0 &&
+				!slices.ContainsFunc(result.WritablePathDirs, func(d WritablePathDir) bool { return d.Path == dir }) {
+				result.WritablePathDirs = append(result.WritablePathDirs, WritablePathDir{
+					Path: dir, Sticky: info.Mode()&fs.ModeSticky != 0, PathEntry: entry,
+				})
+			}
+			if dir == "/" {
+				break
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, root := range roots {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, err := fs.Stat(fsys, fsPath(root)); err != nil {
+			continue
+		}
+		result.ScannedPaths = append(result.ScannedPaths, root)
+		rootDepth := strings.Count(fsPath(root), "/")
+		_ = fs.WalkDir(fsys, fsPath(root), func(p string, d fs.DirEntry, err error) error {
+			if ctx.Err() != nil {
+				return fs.SkipAll
+			}
+			if err != nil {
+				return nil // unreadable directories are skipped
+			}
+			if d.IsDir() {
+				if strings.Count(p, "/")-rootDepth >= setIDMaxDepth {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if d.Type()&fs.ModeSymlink != 0 || seen[p] {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			mode := info.Mode()
+			if mode&(fs.ModeSetuid|fs.ModeSetgid) == 0 {
+				return nil
+			}
+			seen[p] = true
+			abs := "/" + p
+			result.SetIDBinaries = append(result.SetIDBinaries, SetIDBinary{
+				Path:          abs,
+				SUID:          mode&fs.ModeSetuid != 0,
+				SGID:          mode&fs.ModeSetgid != 0,
+				WorldWritable: mode.Perm()&0o002 != 0,
+				Allowed:       setIDAllowed(abs, allowlist),
+			})
+			return nil
+		})
+	}
+	result.Truncated = ctx.Err() != nil
+
+	result.Findings = filesystemFindings(result)
+	result.Clean = !result.Truncated && len(result.Findings) == 0
+	return result
+}
+
+// fsPath converts an absolute path to an io/fs path below "/"
+func fsPath(p string) string {
+	if p = strings.TrimPrefix(path.Clean(p), "/"); p == "" {
+		return "."
+	}
+	return p
+}
+
+// setIDAllowed reports whether a binary matches the allowlist. Entries with
+// a slash match the full path, possibly as a glob; others match the base name.
+func setIDAllowed(p string, allowlist []string) bool {
+	for _, entry := range allowlist {
+		entry = strings.TrimSpace(entry)
+		if !strings.Contains(entry, "/") {
+			if entry == path.Base(p) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(entry, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// filesystemFindings returns unexpected SUID/SGID binaries and writable
+// PATH directories
+func filesystemFindings(r *FilesystemAuditResult) []Finding {
+	findings := []Finding{}
+	add := func(id, title, severity, remediation string) {
+		findings = append(findings, Finding{
+			ID:          id,
+			Title:       title,
+			Severity:    severity,
+			Check:       CheckFilesystem,
+			Remediation: remediation,
+		})
+	}
+	for _, b := range r.SetIDBinaries {
+		switch {
+		case b.WorldWritable:
+			add("setid_world_writable", T("%s is SUID/SGID and world-writable", b.Path), SeverityCritical,
+				T("Remove write permission for other users (chmod o-w) or the SUID/SGID bit"))
+		case b.Allowed:
+		case b.SUID:
+			add("suid_unexpected", T("Unexpected SUID binary %s", b.Path), SeverityHigh,
+				T("Remove the SUID bit (chmod u-s) unless the binary needs it, then add it to the allowlist"))
+		default:
+			add("sgid_unexpected", T("Unexpected SGID binary %s", b.Path), SeverityMedium,
+				T("Remove the SGID bit (chmod g-s) unless the binary needs it, then add it to the allowlist"))
+		}
+	}
+	for _, d := range r.WritablePathDirs {
+		severity := SeverityHigh
+		if d.Sticky {
+			severity = SeverityMedium
+		}
+		add("path_dir_world_writable", T("%s in PATH is world-writable", d.Path), severity,
+			T("Remove write permission for other users (chmod o-w) or take the directory out of PATH"))
+	}
+	if len(r.RelativePathEntries) > 0 {
+		add("path_relative_entry", T("PATH searches the current directory"), SeverityMedium,
+			T("Remove empty and relative entries such as \".\" from PATH"))
+	}
+	if r.Truncated {
+		add("filesystem_audit_incomplete", T("The filesystem audit timed out before it finished"), SeverityLow,
+			T("Run the audit again with a longer timeout or fewer paths"))
+	}
+	return findings
+}
+
+// FormatFilesystemAuditTable formats the filesystem audit as a colored table
+func FormatFilesystemAuditTable(result *FilesystemAuditResult) string {
+	return FormatFilesystemAuditTableWith(CurrentStyler(), result)
+}
+
+// FormatFilesystemAuditTableWith is like FormatFilesystemAuditTable but
+// styles the table with st
+func FormatFilesystemAuditTableWith(st Styler, result *FilesystemAuditResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " Filesystem Audit"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n\n")
+	}
+
+	allowed := 0
+	for _, b := range result.SetIDBinaries {
+		if b.Allowed {
+			allowed++
+		}
+	}
+	sb.WriteString(st.Muted("Searched " + strings.Join(result.ScannedPaths, ", ")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(fmt.Sprintf("%d SUID/SGID binaries, %d allowed", len(result.SetIDBinaries), allowed)))
+	sb.WriteString("\n")
+	if result.Truncated {
+		sb.WriteString(st.Warning(IconWarning + "The audit timed out; results are incomplete"))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	if len(result.Findings) == 0 {
+		sb.WriteString(st.Success(IconCheck + " No unexpected SUID/SGID binaries or writable PATH directories"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+	for _, f := range result.Findings {
+		sb.WriteString(severityStyle(st, f.Severity)(IconArrow + " [" + f.Severity + "] " + f.Title))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted("    " + f.Remediation))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// FormatFilesystemAudit formats the filesystem audit in the specified format
+func FormatFilesystemAudit(result *FilesystemAuditResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatFilesystemAuditTableWith(st, result)
+	}, format)
+}
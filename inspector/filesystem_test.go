@@ -0,0 +1,113 @@
+package inspector
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAuditFilesystem(t *testing.T) {
+	fsys := fstest.MapFS{
+		"usr/bin/passwd":                {Mode: 0o755 | fs.ModeSetuid},
+		"usr/bin/ls":                    {Mode: 0o755},
+		"usr/bin/backdoor":              {Mode: 0o755 | fs.ModeSetuid},
+		"usr/bin/mail-lock":             {Mode: 0o755 | fs.ModeSetgid},
+		"usr/bin/vendor-tool":           {Mode: 0o755 | fs.ModeSetuid},
+		"usr/lib/openssh/ssh-keysign":   {Mode: 0o755 | fs.ModeSetuid},
+		"usr/lib/a/b/c/deep":            {Mode: 0o755 | fs.ModeSetuid}, // beyond the depth limit
+		"usr/local/bin/writable-helper": {Mode: 0o757 | fs.ModeSetuid},
+		"usr/local/bin":                 {Mode: fs.ModeDir | 0o755},
+		"opt/tools/bin":                 {Mode: fs.ModeDir | 0o777},
+		"tmp":                           {Mode: fs.ModeDir | fs.ModeSticky | 0o777},
+	}
+	result := auditFilesystem(context.Background(), fsys,
+		[]string{"/usr/bin", "/usr/lib", "/usr/local/bin"},
+		[]string{"/usr/bin", "/opt/tools/bin", "/tmp", "", "/usr/bin"},
+		[]string{"/usr/bin/vendor-*"})
+
+	allowed := map[string]bool{}
+	for _, b := range result.SetIDBinaries {
+		allowed[b.Path] = b.Allowed
+	}
+	want := map[string]bool{
+		"/usr/bin/passwd":                true,
+		"/usr/bin/backdoor":              false,
+		"/usr/bin/mail-lock":             false,
+		"/usr/bin/vendor-tool":           true,
+		"/usr/lib/openssh/ssh-keysign":   true,
+		"/usr/local/bin/writable-helper": false,
+	}
+	if len(allowed) != len(want) {
+		t.Errorf("setid binaries = %+v", result.SetIDBinaries)
+	}
+	for p, ok := range want {
+		if got, found := allowed[p]; !found || got != ok {
+			t.Errorf("%s: allowed = %v (found %v), want %v", p, got, found, ok)
+		}
+	}
+
+	if len(result.WritablePathDirs) != 2 || result.WritablePathDirs[0].Path != "/opt/tools/bin" ||
+		result.WritablePathDirs[0].Sticky || !result.WritablePathDirs[1].Sticky {
+		t.Errorf("writable PATH dirs = %+v", result.WritablePathDirs)
+	}
+
+	severities := map[string]string{}
+	for _, f := range result.Findings {
+		if f.Check != CheckFilesystem {
+			t.Errorf("%s: check = %q", f.ID, f.Check)
+		}
+		severities[f.ID+" "+f.Title] = f.Severity
+	}
+	wantFindings := map[string]string{
+		"suid_unexpected Unexpected SUID binary /usr/bin/backdoor":                            SeverityHigh,
+		"sgid_unexpected Unexpected SGID binary /usr/bin/mail-lock":                           SeverityMedium,
+		"setid_world_writable /usr/local/bin/writable-helper is SUID/SGID and world-writable": SeverityCritical,
+		"path_dir_world_writable /opt/tools/bin in PATH is world-writable":                    SeverityHigh,
+		"path_dir_world_writable /tmp in PATH is world-writable":                              SeverityMedium,
+		"path_relative_entry PATH searches the current directory":                             SeverityMedium,
+	}
+	if len(severities) != len(wantFindings) {
+		t.Errorf("findings = %+v", result.Findings)
+	}
+	for key, severity := range wantFindings {
+		if severities[key] != severity {
+			t.Errorf("%s: severity = %q, want %q", key, severities[key], severity)
+		}
+	}
+	if result.Truncated || result.Clean {
+		t.Errorf("truncated = %v, clean = %v", result.Truncated, result.Clean)
+	}
+}
+
+func TestAuditFilesystem_Timeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := auditFilesystem(ctx, fstest.MapFS{"usr/bin/backdoor": {Mode: 0o755 | fs.ModeSetuid}},
+		[]string{"/usr/bin"}, nil, nil)
+	if !result.Truncated || result.Clean || len(result.SetIDBinaries) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+	if len(result.Findings) != 1 || result.Findings[0].ID != "filesystem_audit_incomplete" {
+		t.Errorf("findings = %+v", result.Findings)
+	}
+}
+
+func TestSetIDAllowed(t *testing.T) {
+	tests := []struct {
+		path  string
+		allow []string
+		want  bool
+	}{
+		{"/usr/bin/sudo", []string{"sudo"}, true},
+		{"/usr/bin/sudo", []string{"/usr/bin/sudo"}, true},
+		{"/opt/x/bin/helper", []string{"/opt/x/bin/*"}, true},
+		{"/opt/x/bin/helper", []string{"/opt/*"}, false},
+		{"/usr/bin/sudoedit2", []string{"sudoedit"}, false},
+	}
+	for _, tt := range tests {
+		if got := setIDAllowed(tt.path, tt.allow); got != tt.want {
+			t.Errorf("setIDAllowed(%q, %v) = %v, want %v", tt.path, tt.allow, got, tt.want)
+		}
+	}
+}
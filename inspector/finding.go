@@ -0,0 +1,313 @@
+package inspector
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Finding severities, most severe first
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+)
+
+// Severities lists the finding severities, most severe first
+var Severities = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow}
+
+// Finding is a security problem found by a check, with how to fix it
+type Finding struct {
+	// ID identifies the kind of finding, e.g. "secure_boot_disabled"; it is
+	// stable across releases and languages, for filtering and policy rules
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Severity string `json:"severity"`
+	// Check is the check that produced the finding (see AllChecks), or
+	// CheckCloud or CheckWSLHost for findings about the cloud instance or
+	// the Windows host of a WSL guest
+	Check       string `json:"check"`
+	Remediation string `json:"remediation"`
+	// RemediationCommand is a command that fixes the finding, when there is one
+	RemediationCommand string `json:"remediation_command,omitempty"`
+	// Disruptive is set when the remediation command reboots the machine, so
+	// fix --all leaves it out
+	Disruptive bool `json:"disruptive,omitempty"`
+}
+
+// Checks of findings that are not about this machine's own security checks
+const (
+	CheckCloud   = "cloud"
+	CheckWSLHost = "wsl_host"
+)
+
+// remediationCommands are the commands that fix a finding, by finding ID
+// and then platform. Commands must not need shell quoting, since fix runs
+// them split on spaces (see RemediationArgs).
+var remediationCommands = map[string]map[string]string{
+	"secure_boot_disabled": {
+		"linux":   "systemctl reboot --firmware-setup",
+		"windows": "shutdown /r /fw /t 0",
+	},
+	// manage-bde adds a recovery password, which it prints, next to the
+	// default TPM protector, so a TPM reset does not lock the disk for good
+	"encryption_disabled": {
+		"darwin":  "sudo fdesetup enable",
+		"windows": "manage-bde -on C: -RecoveryPassword",
+	},
+	"biometrics_not_configured": {
+		"darwin":  "open x-apple.systempreferences:com.apple.Touch-ID-Settings.extension",
+		"linux":   "fprintd-enroll",
+		"windows": "explorer.exe ms-settings:signinoptions",
+	},
+	"defender_antivirus_disabled": {
+		"windows": "explorer.exe windowsdefender://threat",
+	},
+	"defender_realtime_disabled": {
+		"windows": "powershell.exe -NoProfile -Command Set-MpPreference -DisableRealtimeMonitoring 0",
+	},
+	"defender_signatures_outdated": {
+		"windows": "powershell.exe -NoProfile -Command Update-MpSignature",
+	},
+	"defender_tamper_protection_disabled": {
+		"windows": "explorer.exe windowsdefender://threatsettings",
+	},
+	"defender_cloud_protection_disabled": {
+		"windows": "powershell.exe -NoProfile -Command Set-MpPreference -MAPSReporting Advanced",
+	},
+	"reboot_pending": {
+		"darwin":  "sudo softwareupdate --install --all --restart",
+		"linux":   "systemctl reboot",
+		"windows": "shutdown /r /t 0",
+	},
+	"uac_disabled": {
+		"windows": `reg add HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Policies\System /v EnableLUA /t REG_DWORD /d 1 /f`,
+	},
+	"uac_no_prompt": {
+		"windows": `reg add HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Policies\System /v ConsentPromptBehaviorAdmin /t REG_DWORD /d 2 /f`,
+	},
+	"uac_prompt_below_baseline": {
+		"windows": `reg add HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Policies\System /v ConsentPromptBehaviorAdmin /t REG_DWORD /d 2 /f`,
+	},
+	"uac_secure_desktop_disabled": {
+		"windows": `reg add HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Policies\System /v PromptOnSecureDesktop /t REG_DWORD /d 1 /f`,
+	},
+	"uac_builtin_admin_unfiltered": {
+		"windows": `reg add HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Policies\System /v FilterAdministratorToken /t REG_DWORD /d 1 /f`,
+	},
+	"smartscreen_apps_disabled": {
+		"windows": `reg add HKLM\SOFTWARE\Policies\Microsoft\Windows\System /v EnableSmartScreen /t REG_DWORD /d 1 /f`,
+	},
+	"smb1_server_enabled": {
+		"windows": `powershell.exe -NoProfile -Command Set-SmbServerConfiguration -EnableSMB1Protocol 0 -Force`,
+	},
+	"smb1_client_enabled": {
+		"windows": `powershell.exe -NoProfile -Command Disable-WindowsOptionalFeature -Online -FeatureName SMB1Protocol -NoRestart`,
+	},
+	"ntlmv1_allowed": {
+		"windows": `reg add HKLM\SYSTEM\CurrentControlSet\Control\Lsa /v LmCompatibilityLevel /t REG_DWORD /d 5 /f`,
+	},
+	"defender_scan_overdue": {
+		"windows": "powershell.exe -NoProfile -Command Start-MpScan -ScanType QuickScan",
+	},
+	"firmware_update_available": {
+		"linux": "fwupdmgr update",
+	},
+	"firmware_outdated": {
+		"darwin": "sudo softwareupdate --install --all --restart",
+	},
+	"usb_storage_unrestricted": {
+		"windows": `reg add HKLM\SYSTEM\CurrentControlSet\Services\USBSTOR /v Start /t REG_DWORD /d 4 /f`,
+	},
+}
+
+// remediationCommand returns the command that fixes a finding on this
+// platform, or "" if it has to be fixed by hand
+func remediationCommand(id string) string {
+	return remediationCommands[id][runtime.GOOS]
+}
+
+// disruptiveRemediations are the findings whose remediation commands reboot
+// the machine
+var disruptiveRemediations = map[string]bool{
+	"secure_boot_disabled": true,
+	"reboot_pending":       true,
+	"firmware_outdated":    true,
+}
+
+// remediationDisruptive reports whether the remediation command of a finding
+// on this platform reboots the machine
+func remediationDisruptive(id string) bool {
+	return disruptiveRemediations[id] && remediationCommand(id) != ""
+}
+
+// RemediationArgs returns the finding's remediation command split into the
+// program and its arguments, or nil if there is none
+func (f Finding) RemediationArgs() []string {
+	return strings.Fields(f.RemediationCommand)
+}
+
+// FixableFindings returns the findings that have a remediation command
+func FixableFindings(findings []Finding) []Finding {
+	out := []Finding{}
+	for _, f := range findings {
+		if f.RemediationCommand != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// SplitDisruptive splits findings into those whose remediation commands do
+// not reboot the machine and the disruptive ones, keeping their order
+func SplitDisruptive(findings []Finding) (safe, disruptive []Finding) {
+	safe = []Finding{}
+	for _, f := range findings {
+		if f.Disruptive {
+			disruptive = append(disruptive, f)
+		} else {
+			safe = append(safe, f)
+		}
+	}
+	return safe, disruptive
+}
+
+// SelectFindings returns the findings with the given IDs, in the order
+// given. Every ID must be among the findings and have a remediation command.
+func SelectFindings(findings []Finding, ids []string) ([]Finding, error) {
+	out := []Finding{}
+	var errs []string
+	for _, id := range ids {
+		i := slices.IndexFunc(findings, func(f Finding) bool { return f.ID == id })
+		switch {
+		case i < 0:
+			errs = append(errs, fmt.Sprintf("%s was not found on this machine", id))
+		case findings[i].RemediationCommand == "":
+			errs = append(errs, fmt.Sprintf("%s has no remediation command: %s", id, findings[i].Remediation))
+		default:
+			out = append(out, findings[i])
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("finding %s", strings.Join(errs, "; "))
+	}
+	return out, nil
+}
+
+// FindingsResult is a list of findings, as printed by the fix command
+type FindingsResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Findings []Finding `json:"findings"`
+}
+
+// SeverityRank orders severities from 0 (critical) up; unknown severities
+// rank last
+func SeverityRank(severity string) int {
+	if i := slices.Index(Severities, severity); i >= 0 {
+		return i
+	}
+	return len(Severities)
+}
+
+// SortFindings orders findings by severity, keeping the order of findings
+// with the same severity
+func SortFindings(findings []Finding) {
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return SeverityRank(a.Severity) - SeverityRank(b.Severity)
+	})
+}
+
+// FilterFindings returns the findings at least as severe as minSeverity
+func FilterFindings(findings []Finding, minSeverity string) ([]Finding, error) {
+	limit := SeverityRank(minSeverity)
+	if limit == len(Severities) {
+		return nil, fmt.Errorf("unknown severity %q (use %s)", minSeverity, strings.Join(Severities, ", "))
+	}
+	var out []Finding
+	for _, f := range findings {
+		if SeverityRank(f.Severity) <= limit {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+// unverifiedFinding reports that a check could not be verified; the probe's
+// hint is the remediation
+func unverifiedFinding(id, check, feature string, err *ProbeError) Finding {
+	remediation := T("Make sure the probe can run on this system")
+	if err.Hint != "" {
+		remediation = T(err.Hint)
+	}
+	return Finding{
+		ID:          id,
+		Title:       T("Could not verify %s status", T(feature)),
+		Severity:    SeverityMedium,
+		Check:       check,
+		Remediation: remediation,
+	}
+}
+
+// severityStyle colors a severity heading
+func severityStyle(st Styler, severity string) func(string) string {
+	switch severity {
+	case SeverityCritical, SeverityHigh:
+		return st.Danger
+	case SeverityMedium:
+		return st.Warning
+	}
+	return st.Info
+}
+
+// severityLabel returns the display name of a severity
+func severityLabel(severity string) string {
+	switch severity {
+	case SeverityCritical:
+		return T("Critical")
+	case SeverityHigh:
+		return T("High")
+	case SeverityMedium:
+		return T("Medium")
+	case SeverityLow:
+		return T("Low")
+	}
+	return severity
+}
+
+// FormatFindings formats a list of findings in the specified format
+func FormatFindings(result *FindingsResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		if len(result.Findings) == 0 {
+			return st.Muted(T("No findings")) + "\n"
+		}
+		return formatFindings(st, result.Findings)
+	}, format)
+}
+
+// formatFindings renders findings grouped by severity, most severe first
+func formatFindings(st Styler, findings []Finding) string {
+	var sb strings.Builder
+	sorted := slices.Clone(findings)
+	SortFindings(sorted)
+	for i, f := range sorted {
+		if i == 0 || f.Severity != sorted[i-1].Severity {
+			count := 0
+			for _, g := range sorted {
+				if g.Severity == f.Severity {
+					count++
+				}
+			}
+			style := severityStyle(st, f.Severity)
+			sb.WriteString(fmt.Sprintf("  %s\n", st.BoldText(style(fmt.Sprintf("%s (%d)", severityLabel(f.Severity), count)))))
+		}
+		sb.WriteString(fmt.Sprintf("    %s %s %s\n", st.Warning(IconArrow), f.Title, st.Muted("["+f.ID+"]")))
+		sb.WriteString(fmt.Sprintf("      %s\n", f.Remediation))
+		if f.RemediationCommand != "" {
+			sb.WriteString(fmt.Sprintf("      %s\n", st.Info("$ "+f.RemediationCommand)))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,146 @@
+package inspector
+
+import (
+	"strings"
+	"testing"
+)
+
+func testFindings() []Finding {
+	return []Finding{
+		{ID: "biometrics_not_configured", Severity: SeverityLow, Title: "bio", Remediation: "enroll"},
+		{ID: "encryption_disabled", Severity: SeverityCritical, Title: "enc", Remediation: "encrypt", RemediationCommand: "sudo fdesetup enable"},
+		{ID: "secure_boot_disabled", Severity: SeverityHigh, Title: "sb", Remediation: "enable"},
+		{ID: "tpm_missing", Severity: SeverityHigh, Title: "tpm", Remediation: "enable"},
+	}
+}
+
+func TestSortFindings(t *testing.T) {
+	findings := testFindings()
+	SortFindings(findings)
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, f.ID)
+	}
+	want := "encryption_disabled,secure_boot_disabled,tpm_missing,biometrics_not_configured"
+	if got := strings.Join(ids, ","); got != want {
+		t.Errorf("sorted = %s, want %s", got, want)
+	}
+}
+
+func TestFilterFindings(t *testing.T) {
+	tests := []struct {
+		min  string
+		want int
+	}{
+		{SeverityCritical, 1},
+		{SeverityHigh, 3},
+		{SeverityMedium, 3},
+		{SeverityLow, 4},
+	}
+	for _, tt := range tests {
+		got, err := FilterFindings(testFindings(), tt.min)
+		if err != nil || len(got) != tt.want {
+			t.Errorf("FilterFindings(%s) = %d findings, %v; want %d", tt.min, len(got), err, tt.want)
+		}
+	}
+	if _, err := FilterFindings(testFindings(), "urgent"); err == nil {
+		t.Error("an unknown severity should be rejected")
+	}
+}
+
+func TestFormatFindings(t *testing.T) {
+	out := StripANSI(formatFindings(CurrentStyler(), testFindings()))
+	critical := strings.Index(out, "Critical (1)")
+	high := strings.Index(out, "High (2)")
+	low := strings.Index(out, "Low (1)")
+	if critical < 0 || high < critical || low < high {
+		t.Errorf("findings are not grouped by severity:\n%s", out)
+	}
+	if strings.Contains(out, "Medium") {
+		t.Errorf("empty severities should be omitted:\n%s", out)
+	}
+	if !strings.Contains(out, "$ sudo fdesetup enable") {
+		t.Errorf("remediation command missing:\n%s", out)
+	}
+}
+
+func TestUnverifiedFinding(t *testing.T) {
+	f := unverifiedFinding("tpm_unverified", CheckTPM, "TPM", &ProbeError{Hint: "Re-run with sudo"})
+	if f.Title != "Could not verify TPM status" || f.Remediation != "Re-run with sudo" || f.Severity != SeverityMedium {
+		t.Errorf("finding = %+v", f)
+	}
+	if f := unverifiedFinding("tpm_unverified", CheckTPM, "TPM", &ProbeError{}); f.Remediation == "" {
+		t.Error("a finding without a hint should still have a remediation")
+	}
+}
+
+func TestSelectFindings(t *testing.T) {
+	findings := append(testFindings(), Finding{ID: "tpm_unverified", Severity: SeverityMedium, Remediation: "Re-run with sudo"})
+	got, err := SelectFindings(findings, []string{"encryption_disabled"})
+	if err != nil || len(got) != 1 || got[0].RemediationCommand != "sudo fdesetup enable" {
+		t.Errorf("SelectFindings = %+v, %v", got, err)
+	}
+	if _, err := SelectFindings(findings, []string{"tpm_unverified"}); err == nil || !strings.Contains(err.Error(), "Re-run with sudo") {
+		t.Errorf("a finding without a command should be rejected with its remediation, got %v", err)
+	}
+	if _, err := SelectFindings(findings, []string{"encryption_disabled", "ssh_root_login"}); err == nil {
+		t.Error("a finding that is not present should be rejected")
+	}
+	if fixable := FixableFindings(findings); len(fixable) != 1 || fixable[0].ID != "encryption_disabled" {
+		t.Errorf("FixableFindings = %+v", fixable)
+	}
+}
+
+func TestSplitDisruptive(t *testing.T) {
+	findings := []Finding{
+		{ID: "reboot_pending", RemediationCommand: "systemctl reboot", Disruptive: true},
+		{ID: "encryption_disabled", RemediationCommand: "manage-bde -on C: -RecoveryPassword"},
+		{ID: "secure_boot_disabled", RemediationCommand: "systemctl reboot --firmware-setup", Disruptive: true},
+	}
+	safe, disruptive := SplitDisruptive(findings)
+	if len(safe) != 1 || safe[0].ID != "encryption_disabled" {
+		t.Errorf("safe = %+v", safe)
+	}
+	if len(disruptive) != 2 || disruptive[0].ID != "reboot_pending" || disruptive[1].ID != "secure_boot_disabled" {
+		t.Errorf("disruptive = %+v", disruptive)
+	}
+}
+
+// Every command that restarts the machine must be marked disruptive, so that
+// fix --all does not reboot it
+func TestRemediationCommands_Disruptive(t *testing.T) {
+	for id, commands := range remediationCommands {
+		for goos, command := range commands {
+			reboots := strings.Contains(command, "reboot") || strings.Contains(command, "/r ") || strings.Contains(command, "--restart")
+			if reboots != disruptiveRemediations[id] {
+				t.Errorf("%s on %s: %q reboots = %v, but disruptive = %v", id, goos, command, reboots, disruptiveRemediations[id])
+			}
+		}
+	}
+}
+
+func TestRemediationArgs(t *testing.T) {
+	f := Finding{RemediationCommand: "manage-bde -on C:"}
+	if got := strings.Join(f.RemediationArgs(), "|"); got != "manage-bde|-on|C:" {
+		t.Errorf("RemediationArgs = %q", got)
+	}
+	if args := (Finding{}).RemediationArgs(); len(args) != 0 {
+		t.Errorf("RemediationArgs without a command = %q", args)
+	}
+	for id, commands := range remediationCommands {
+		for goos, command := range commands {
+			if strings.ContainsAny(command, `"'|&;<>$`) {
+				t.Errorf("%s on %s: %q needs a shell", id, goos, command)
+			}
+		}
+	}
+}
+
+func TestFormatFindings_Empty(t *testing.T) {
+	if out := FormatFindings(&FindingsResult{}, "table"); !strings.Contains(out, "No findings") {
+		t.Errorf("empty table = %q", out)
+	}
+	if out := FormatFindings(&FindingsResult{Findings: FixableFindings(nil)}, "json"); !strings.Contains(out, `"findings": []`) {
+		t.Errorf("empty JSON = %q", out)
+	}
+}
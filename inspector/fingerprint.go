@@ -0,0 +1,310 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Fingerprint sources, in order of preference. The fingerprint is derived
+// from the first one that can be read.
+const (
+	// FingerprintSourcePlatformUUID is the SMBIOS system UUID (the
+	// IOPlatformUUID on macOS)
+	FingerprintSourcePlatformUUID = "platform_uuid"
+	// FingerprintSourceTPMEK is the SHA-256 of the TPM endorsement key's
+	// public key
+	FingerprintSourceTPMEK        = "tpm_ek"
+	FingerprintSourceSerialNumber = "serial_number"
+	// FingerprintSourceMachineID is the OS installation ID, which changes
+	// when the OS is reinstalled
+	FingerprintSourceMachineID = "machine_id"
+)
+
+// fingerprintSources lists the sources in order of preference
+var fingerprintSources = []string{FingerprintSourcePlatformUUID, FingerprintSourceTPMEK, FingerprintSourceSerialNumber, FingerprintSourceMachineID}
+
+// FingerprintSaltEnv keys fingerprints with a secret (HMAC-SHA256), so that
+// they cannot be correlated with fingerprints computed by anyone else
+const FingerprintSaltEnv = "OMNITRUST_FINGERPRINT_SALT"
+
+// placeholderIdentifiers are values vendors leave in SMBIOS fields that do
+// not identify anything
+var placeholderIdentifiers = []string{
+	"", "0", "none", "n/a", "default string", "not specified", "not applicable",
+	"system serial number", "to be filled by o.e.m.", "chassis serial number",
+	"00000000-0000-0000-0000-000000000000", "ffffffff-ffff-ffff-ffff-ffffffffffff",
+	"03000200-0400-0500-0006-000700080009",
+}
+
+// FingerprintComponent is one hardware or OS identifier that was read
+type FingerprintComponent struct {
+	Source string `json:"source"`
+	// Value is the identifier, or its keyed hash in hashed mode
+	Value string `json:"value"`
+}
+
+// FingerprintResult is a stable device identifier for correlating snapshots
+// and fleet records
+type FingerprintResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string `json:"platform"`
+	// Fingerprint is the hex SHA-256 (HMAC-SHA256 with a salt) of the
+	// preferred source's identifier
+	Fingerprint string `json:"fingerprint"`
+	// Source is the component the fingerprint was derived from. Reading
+	// the platform UUID needs root on Linux, so run with the same
+	// privileges everywhere to get the same source.
+	Source string `json:"source"`
+	// Hashed is true if component values are hashed rather than raw
+	Hashed bool `json:"hashed"`
+	// Salted is true if the fingerprint is keyed with a salt
+	Salted     bool                   `json:"salted"`
+	Components []FingerprintComponent `json:"components"`
+	Error      *ProbeError            `json:"error,omitempty"`
+}
+
+// FingerprintOptions controls how the fingerprint is derived and reported
+type FingerprintOptions struct {
+	// Hashed replaces the raw identifiers in the components with hashes,
+	// so the result can be shared without disclosing serial numbers
+	Hashed bool
+	// Salt keys the fingerprint and hashed components (HMAC-SHA256)
+	Salt string
+}
+
+// DefaultFingerprintOptions returns raw components with the salt from
+// OMNITRUST_FINGERPRINT_SALT
+func DefaultFingerprintOptions() FingerprintOptions {
+	return FingerprintOptions{Salt: os.Getenv(FingerprintSaltEnv)}
+}
+
+// IsFingerprintSupported returns true on platforms with a fingerprint source
+func IsFingerprintSupported() bool {
+	return runtime.GOOS == "linux" || runtime.GOOS == "darwin" || runtime.GOOS == "windows"
+}
+
+// GetFingerprint computes a stable device identifier from the platform
+// UUID, the TPM endorsement key, the hardware serial number, or the OS
+// machine ID, whichever is found first
+func GetFingerprint(opts FingerprintOptions) (*FingerprintResult, error) {
+	return GetFingerprintContext(context.Background(), opts)
+}
+
+// GetFingerprintContext is like GetFingerprint but runs the probes with
+// ctx's deadline, command runner, and logger
+func GetFingerprintContext(ctx context.Context, opts FingerprintOptions) (*FingerprintResult, error) {
+	ids := map[string]string{}
+	switch runtime.GOOS {
+	case "linux":
+		readLinuxFingerprint(os.DirFS("/"), ids)
+	case "darwin":
+		out, err := runCommand(ctx, "ioreg", "-rd1", "-c", "IOPlatformExpertDevice")
+		if err != nil {
+			return nil, classifyExecError(ctx, "ioreg", err)
+		}
+		parseIORegPlatform(out, ids)
+	case "windows":
+		if err := readWindowsFingerprint(ctx, ids); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, newProbeError(ctx, ErrUnsupportedPlatform, "fingerprint", "fingerprints are not supported on "+runtime.GOOS)
+	}
+	if ek := tpmEKIdentifier(ctx); ek != "" {
+		ids[FingerprintSourceTPMEK] = ek
+	}
+	return buildFingerprint(ctx, ids, opts), nil
+}
+
+// buildFingerprint derives the fingerprint from the preferred identifier
+func buildFingerprint(ctx context.Context, ids map[string]string, opts FingerprintOptions) *FingerprintResult {
+	result := &FingerprintResult{
+		Platform:   runtime.GOOS,
+		Hashed:     opts.Hashed,
+		Salted:     opts.Salt != "",
+		Components: []FingerprintComponent{},
+	}
+	for _, source := range fingerprintSources {
+		value := normalizeIdentifier(ids[source])
+		if isPlaceholderIdentifier(value) {
+			continue
+		}
+		if result.Source == "" {
+			result.Source = source
+			result.Fingerprint = fingerprintDigest(opts.Salt, source+":"+value)
+		}
+		if opts.Hashed {
+			value = fingerprintDigest(opts.Salt, value)
+		}
+		result.Components = append(result.Components, FingerprintComponent{Source: source, Value: value})
+	}
+	if result.Source == "" {
+		result.Error = newProbeError(ctx, ErrProbeFailed, "fingerprint", "no hardware or OS identifier could be read")
+		if runtime.GOOS == "linux" {
+			result.Error.Hint = "Run as root to read the DMI product UUID and serial number"
+		}
+	}
+	return result
+}
+
+// normalizeIdentifier makes identifiers compare equal regardless of how a
+// tool formats them
+func normalizeIdentifier(v string) string {
+	return strings.ToLower(strings.TrimSpace(v))
+}
+
+// isPlaceholderIdentifier reports whether a normalized identifier is empty
+// or a vendor placeholder
+func isPlaceholderIdentifier(v string) bool {
+	return slices.Contains(placeholderIdentifiers, v)
+}
+
+// fingerprintDigest returns the hex SHA-256 of s, keyed with salt if set
+func fingerprintDigest(salt, s string) string {
+	if salt == "" {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	}
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(s))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// readLinuxFingerprint reads the DMI system UUID and serial number, which
+// only root can read, and the machine ID
+func readLinuxFingerprint(fsys fs.FS, ids map[string]string) {
+	read := func(name string) string {
+		data, err := readFSFile(fsys, "sys/class/dmi/id/"+name)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+	ids[FingerprintSourcePlatformUUID] = read("product_uuid")
+	for _, name := range []string{"product_serial", "board_serial", "chassis_serial"} {
+		if v := read(name); !isPlaceholderIdentifier(normalizeIdentifier(v)) {
+			ids[FingerprintSourceSerialNumber] = v
+			break
+		}
+	}
+	ids[FingerprintSourceMachineID] = linuxMachineID(fsys)
+}
+
+// parseIORegPlatform reads the platform UUID and serial number from
+// `ioreg -rd1 -c IOPlatformExpertDevice`
+func parseIORegPlatform(out []byte, ids map[string]string) {
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " = ")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		switch strings.Trim(key, `"`) {
+		case "IOPlatformUUID":
+			ids[FingerprintSourcePlatformUUID] = value
+		case "IOPlatformSerialNumber":
+			ids[FingerprintSourceSerialNumber] = value
+		}
+	}
+}
+
+// ekPublicKeyIdentifier returns the hex SHA-256 of the public key of a PEM
+// EK certificate. The key rather than the certificate identifies the TPM,
+// since the certificate can be reissued.
+func ekPublicKeyIdentifier(certPEM string) string {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return ""
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(sum[:])
+}
+
+// envelopeFingerprint is the fingerprint reported in envelopes, computed
+// once per process
+var envelopeFingerprint = sync.OnceValue(func() string {
+	result, err := GetFingerprintContext(context.Background(), DefaultFingerprintOptions())
+	if err != nil {
+		return ""
+	}
+	return result.Fingerprint
+})
+
+// FormatFingerprintTable formats the fingerprint as a colored table
+func FormatFingerprintTable(result *FingerprintResult) string {
+	return FormatFingerprintTableWith(CurrentStyler(), result)
+}
+
+// FormatFingerprintTableWith is like FormatFingerprintTable but styles the
+// table with st
+func FormatFingerprintTableWith(st Styler, result *FingerprintResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconKey + " Device Fingerprint"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n")
+		if result.Error.Hint != "" {
+			sb.WriteString(st.Muted("  " + result.Error.Hint))
+			sb.WriteString("\n")
+		}
+		return sb.String()
+	}
+
+	sb.WriteString(st.BoldText("Fingerprint: "))
+	sb.WriteString(st.Info(result.Fingerprint))
+	sb.WriteString("\n")
+	mode := "raw identifiers"
+	if result.Hashed {
+		mode = "hashed identifiers"
+	}
+	if result.Salted {
+		mode += ", salted"
+	}
+	sb.WriteString(st.Muted(fmt.Sprintf("Derived from %s (%s)", result.Source, mode)))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(st.TableTop(16, 66))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(st.Header(PadRight("Source", 16)), st.Header(PadRight("Value", 66))))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(16, 66))
+	sb.WriteString("\n")
+	for _, c := range result.Components {
+		sb.WriteString(st.TableRowColored(PadRight(c.Source, 16), PadRight(c.Value, 66)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(st.TableBottom(16, 66))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// FormatFingerprint returns the fingerprint in the specified format
+func FormatFingerprint(result *FingerprintResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatFingerprintTableWith(st, result)
+	}, format)
+}
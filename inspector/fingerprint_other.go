@@ -0,0 +1,10 @@
+//go:build !windows
+
+package inspector
+
+import "context"
+
+// readWindowsFingerprint is only implemented on Windows
+func readWindowsFingerprint(ctx context.Context, ids map[string]string) *ProbeError {
+	return nil
+}
@@ -0,0 +1,97 @@
+package inspector
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBuildFingerprint(t *testing.T) {
+	ids := map[string]string{
+		FingerprintSourcePlatformUUID: "00000000-0000-0000-0000-000000000000",
+		FingerprintSourceSerialNumber: " PF3ABCDE ",
+		FingerprintSourceMachineID:    "fed6b2924c424cf1b9a322f606b4de6d",
+	}
+	result := buildFingerprint(context.Background(), ids, FingerprintOptions{})
+	// The placeholder UUID is skipped and the serial number is normalized
+	if result.Source != FingerprintSourceSerialNumber || result.Fingerprint != fingerprintDigest("", "serial_number:pf3abcde") {
+		t.Errorf("result = %+v, want the serial number", result)
+	}
+	if len(result.Components) != 2 || result.Components[0].Value != "pf3abcde" {
+		t.Errorf("components = %+v", result.Components)
+	}
+
+	// The fingerprint does not depend on the mode, only on the salt
+	hashed := buildFingerprint(context.Background(), ids, FingerprintOptions{Hashed: true})
+	if hashed.Fingerprint != result.Fingerprint || hashed.Components[0].Value == "pf3abcde" {
+		t.Errorf("hashed = %+v", hashed)
+	}
+	salted := buildFingerprint(context.Background(), ids, FingerprintOptions{Salt: "fleet-secret"})
+	if !salted.Salted || salted.Fingerprint == result.Fingerprint || len(salted.Fingerprint) != 64 {
+		t.Errorf("salted = %+v", salted)
+	}
+
+	if r := buildFingerprint(context.Background(), map[string]string{FingerprintSourceSerialNumber: "To Be Filled By O.E.M."}, FingerprintOptions{}); r.Error == nil || r.Fingerprint != "" {
+		t.Errorf("result = %+v, want an error without identifiers", r)
+	}
+}
+
+func TestReadLinuxFingerprint(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sys/class/dmi/id/product_uuid":   {Data: []byte("4C4C4544-0042-3510-8052-B4C04F4A4B32\n")},
+		"sys/class/dmi/id/product_serial": {Data: []byte("System Serial Number\n")},
+		"sys/class/dmi/id/board_serial":   {Data: []byte(".5B2JKJ2.CN1296392L0042.\n")},
+		"etc/machine-id":                  {Data: []byte("fed6b2924c424cf1b9a322f606b4de6d\n")},
+	}
+	ids := map[string]string{}
+	readLinuxFingerprint(fsys, ids)
+	if ids[FingerprintSourcePlatformUUID] != "4C4C4544-0042-3510-8052-B4C04F4A4B32" || ids[FingerprintSourceSerialNumber] != ".5B2JKJ2.CN1296392L0042." || ids[FingerprintSourceMachineID] != "fed6b2924c424cf1b9a322f606b4de6d" {
+		t.Errorf("ids = %v", ids)
+	}
+	if r := buildFingerprint(context.Background(), ids, FingerprintOptions{}); r.Source != FingerprintSourcePlatformUUID {
+		t.Errorf("source = %s, want platform_uuid", r.Source)
+	}
+}
+
+func TestParseIORegPlatform(t *testing.T) {
+	out := []byte(`+-o J314sAP  <class IOPlatformExpertDevice, id 0x100000201, registered, matched, active, busy 0 (117 ms), retain 38>
+    {
+      "IOPlatformSerialNumber" = "C02XK0ABJGH5"
+      "IOPlatformUUID" = "A1B2C3D4-E5F6-7890-ABCD-EF0123456789"
+      "model" = <"MacBookPro18,3">
+    }
+`)
+	ids := map[string]string{}
+	parseIORegPlatform(out, ids)
+	if ids[FingerprintSourcePlatformUUID] != "A1B2C3D4-E5F6-7890-ABCD-EF0123456789" || ids[FingerprintSourceSerialNumber] != "C02XK0ABJGH5" {
+		t.Errorf("ids = %v", ids)
+	}
+}
+
+func TestEKPublicKeyIdentifier(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issue := func(serial int64) string {
+		der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{SerialNumber: big.NewInt(serial)}, &x509.Certificate{SerialNumber: big.NewInt(1)}, &key.PublicKey, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	}
+	// A reissued certificate for the same key identifies the same TPM
+	first, second := ekPublicKeyIdentifier(issue(1)), ekPublicKeyIdentifier(issue(2))
+	if first == "" || first != second {
+		t.Errorf("identifiers = %q, %q, want equal", first, second)
+	}
+	if ekPublicKeyIdentifier("not a certificate") != "" {
+		t.Error("invalid PEM should have no identifier")
+	}
+}
@@ -0,0 +1,15 @@
+//go:build linux || windows
+
+package inspector
+
+import "context"
+
+// tpmEKIdentifier returns the hash of the TPM endorsement key, or "" when
+// the EK certificate cannot be read
+func tpmEKIdentifier(ctx context.Context) string {
+	result, err := GetTPMStatusContext(ctx)
+	if err != nil || result.EKCertificate == nil {
+		return ""
+	}
+	return ekPublicKeyIdentifier(result.EKCertificate.PEM)
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !windows
+
+package inspector
+
+import "context"
+
+// tpmEKIdentifier is only implemented where the EK certificate is readable
+func tpmEKIdentifier(ctx context.Context) string {
+	return ""
+}
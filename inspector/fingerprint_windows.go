@@ -0,0 +1,29 @@
+//go:build windows
+
+package inspector
+
+import "context"
+
+// fpComputerSystemProduct holds the Win32_ComputerSystemProduct properties
+// that identify the hardware
+type fpComputerSystemProduct struct {
+	UUID              string
+	IdentifyingNumber string
+}
+
+// readWindowsFingerprint reads the SMBIOS UUID and serial number from WMI
+// and the MachineGuid from the registry
+func readWindowsFingerprint(ctx context.Context, ids map[string]string) *ProbeError {
+	var products []fpComputerSystemProduct
+	if err := wmiQuery(`SELECT UUID, IdentifyingNumber FROM Win32_ComputerSystemProduct`, &products, ""); err != nil {
+		return classifyWMIError(ctx, `root\cimv2`, err)
+	}
+	if len(products) > 0 {
+		ids[FingerprintSourcePlatformUUID] = products[0].UUID
+		ids[FingerprintSourceSerialNumber] = products[0].IdentifyingNumber
+	}
+	if guid, ok, _ := registryString(ctx, `HKLM\SOFTWARE\Microsoft\Cryptography`, "MachineGuid"); ok {
+		ids[FingerprintSourceMachineID] = guid
+	}
+	return nil
+}
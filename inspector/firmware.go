@@ -0,0 +1,549 @@
+package inspector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Registry keys read by the firmware check
+const (
+	biosKey = `HKLM\HARDWARE\DESCRIPTION\System\BIOS`
+	// esrtKey lists the firmware resources of the EFI System Resource
+	// Table, one subkey per resource GUID, with the state of the last
+	// UEFI capsule update
+	esrtKey = `HKLM\HARDWARE\UEFI\ESRT`
+)
+
+// esrtStatuses describes the ESRT LastAttemptStatus codes
+var esrtStatuses = []string{
+	"success",
+	"unsuccessful",
+	"insufficient resources",
+	"incorrect version",
+	"invalid image format",
+	"authentication error",
+	"power event (AC not connected)",
+	"power event (insufficient battery)",
+	"unsatisfied dependencies",
+}
+
+// hsiIDPattern matches a Host Security ID such as "HSI:2!"
+var hsiIDPattern = regexp.MustCompile(`HSI:(\d)(!?)`)
+
+// FirmwareUpdate is a firmware release newer than the installed version
+type FirmwareUpdate struct {
+	Device         string `json:"device"`
+	CurrentVersion string `json:"current_version,omitempty"`
+	Version        string `json:"version"`
+	// Urgency is low, medium, high, or critical, where the vendor set it
+	Urgency string `json:"urgency,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// HSIAttribute is a platform security attribute fwupd tests to compute the
+// Host Security ID
+type HSIAttribute struct {
+	// ID is the attribute's AppStream ID, e.g. org.fwupd.hsi.Uefi.SecureBoot
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	// Level is the HSI level the attribute belongs to; 0 is a runtime
+	// attribute that only adds the "!" suffix
+	Level  int    `json:"level"`
+	Result string `json:"result,omitempty"`
+	Passed bool   `json:"passed"`
+}
+
+// HostSecurity is fwupd's Host Security ID (HSI) rating: the highest level
+// from 1 to 5 whose attributes, and those of all lower levels, pass
+type HostSecurity struct {
+	// ID is the rating as fwupd prints it, e.g. "HSI:1!"
+	ID    string `json:"id"`
+	Level int    `json:"level"`
+	// RuntimeIssues is true if a runtime attribute failed (the "!" suffix)
+	RuntimeIssues bool           `json:"runtime_issues"`
+	Attributes    []HSIAttribute `json:"attributes"`
+}
+
+// CapsuleUpdate is a firmware resource in the EFI System Resource Table
+// and the outcome of its last UEFI capsule update (Windows)
+type CapsuleUpdate struct {
+	GUID                   string `json:"guid"`
+	Version                uint64 `json:"version"`
+	LowestSupportedVersion uint64 `json:"lowest_supported_version"`
+	LastAttemptVersion     uint64 `json:"last_attempt_version"`
+	LastAttemptStatus      uint64 `json:"last_attempt_status"`
+	Status                 string `json:"status"`
+	// Failed is true if the last attempted update failed and a newer
+	// version has not been installed since
+	Failed bool `json:"failed"`
+}
+
+// FirmwareResult reports the system firmware version, available firmware
+// updates, and platform security ratings
+type FirmwareResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform    string `json:"platform"`
+	Vendor      string `json:"vendor,omitempty"`
+	Version     string `json:"version,omitempty"`
+	ReleaseDate string `json:"release_date,omitempty"`
+	// ExpectedVersion is the firmware version the installed macOS ships
+	// (the OS loader version on Apple silicon)
+	ExpectedVersion string `json:"expected_version,omitempty"`
+	// Updates lists available firmware updates (Linux, from fwupd)
+	Updates []FirmwareUpdate `json:"updates"`
+	// HostSecurity is fwupd's HSI rating (Linux)
+	HostSecurity *HostSecurity `json:"host_security,omitempty"`
+	// Capsules lists the UEFI capsule update state per firmware resource
+	// (Windows)
+	Capsules []CapsuleUpdate `json:"capsules,omitempty"`
+	// Current is true if the update status was read, no firmware update is
+	// pending, no capsule update failed, and the firmware matches the
+	// installed macOS
+	Current bool        `json:"current"`
+	Error   *ProbeError `json:"error,omitempty"`
+}
+
+// GetFirmwareStatus returns the firmware version and update status: fwupd
+// updates and the HSI rating on Linux, the firmware version against the
+// installed macOS on Apple silicon, and UEFI capsule updates on Windows
+func GetFirmwareStatus() (*FirmwareResult, error) {
+	return GetFirmwareStatusContext(context.Background())
+}
+
+// GetFirmwareStatusContext is like GetFirmwareStatus but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetFirmwareStatusContext(ctx context.Context) (*FirmwareResult, error) {
+	if result, ok, err := loadFixture[FirmwareResult](ctx, "firmware"); ok {
+		return result, err
+	}
+	result := &FirmwareResult{Platform: runtime.GOOS}
+	switch runtime.GOOS {
+	case "linux":
+		readDMIFirmware(os.DirFS("/"), result)
+		readFwupd(ctx, result)
+	case "windows":
+		readWindowsFirmware(ctx, result)
+	case "darwin":
+		out, err := runCommand(ctx, "system_profiler", "SPHardwareDataType", "-json")
+		if err != nil {
+			result.Error = classifyExecError(ctx, "system_profiler", err)
+			break
+		}
+		if err := parseSystemProfilerFirmware(out, result); err != nil {
+			result.Error = newProbeError(ctx, ErrProbeFailed, "system_profiler", "unexpected output: "+err.Error())
+		}
+	default:
+		return nil, newProbeError(ctx, ErrUnsupportedPlatform, "firmware", "firmware is not checked on "+runtime.GOOS)
+	}
+	if result.Updates == nil {
+		result.Updates = []FirmwareUpdate{}
+	}
+	// Updates that could not be looked for are not known to be absent
+	result.Current = result.Error == nil && len(result.Updates) == 0 && !firmwareMismatch(result) &&
+		!slices.ContainsFunc(result.Capsules, func(c CapsuleUpdate) bool { return c.Failed })
+	return result, nil
+}
+
+// readDMIFirmware reads the BIOS vendor, version, and date from sysfs
+func readDMIFirmware(root fs.FS, result *FirmwareResult) {
+	read := func(name string) string {
+		data, err := readFSFile(root, "sys/class/dmi/id/"+name)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+	result.Vendor = read("bios_vendor")
+	result.Version = read("bios_version")
+	result.ReleaseDate = read("bios_date")
+}
+
+// readFwupd asks fwupdmgr for available updates and the HSI rating
+func readFwupd(ctx context.Context, result *FirmwareResult) {
+	if _, err := lookPath(ctx, "fwupdmgr"); err != nil {
+		result.Error = classifyExecError(ctx, "fwupdmgr", err)
+		return
+	}
+	out, err := runCommand(ctx, "fwupdmgr", "get-updates", "--json")
+	switch {
+	case err == nil:
+		updates, perr := parseFwupdUpdates(out)
+		if perr != nil {
+			result.Error = newProbeError(ctx, ErrProbeFailed, "fwupdmgr", "unexpected output: "+perr.Error())
+		}
+		result.Updates = updates
+	case !fwupdNothingToDo(err):
+		result.Error = classifyExecError(ctx, "fwupdmgr", err)
+	}
+
+	out, err = runCommand(ctx, "fwupdmgr", "security", "--json")
+	if err != nil {
+		if result.Error == nil {
+			result.Error = classifyExecError(ctx, "fwupdmgr", err)
+		}
+		return
+	}
+	hsi, perr := parseFwupdSecurity(out)
+	if perr != nil && result.Error == nil {
+		result.Error = newProbeError(ctx, ErrProbeFailed, "fwupdmgr", "unexpected output: "+perr.Error())
+	}
+	result.HostSecurity = hsi
+}
+
+// fwupdNothingToDo reports whether fwupdmgr failed only because there are no
+// updates, which it signals with exit status 2
+func fwupdNothingToDo(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	stderr := strings.ToLower(string(exitErr.Stderr))
+	return exitErr.ExitCode() == 2 || strings.Contains(stderr, "no updat") || strings.Contains(stderr, "no updatable")
+}
+
+// parseFwupdUpdates parses `fwupdmgr get-updates --json`, keeping the
+// newest release of each device
+func parseFwupdUpdates(data []byte) ([]FirmwareUpdate, error) {
+	var doc struct {
+		Devices []struct {
+			Name     string `json:"Name"`
+			Version  string `json:"Version"`
+			Releases []struct {
+				Version string `json:"Version"`
+				Urgency string `json:"Urgency"`
+				Summary string `json:"Summary"`
+			} `json:"Releases"`
+		} `json:"Devices"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	var updates []FirmwareUpdate
+	for _, d := range doc.Devices {
+		if len(d.Releases) == 0 {
+			continue
+		}
+		// fwupd lists releases newest first
+		r := d.Releases[0]
+		updates = append(updates, FirmwareUpdate{
+			Device:         d.Name,
+			CurrentVersion: d.Version,
+			Version:        r.Version,
+			Urgency:        r.Urgency,
+			Summary:        r.Summary,
+		})
+	}
+	return updates, nil
+}
+
+// parseFwupdSecurity parses `fwupdmgr security --json`. The HSI level is
+// computed from the attributes the way fwupd does, unless the output
+// carries the Host Security ID itself.
+func parseFwupdSecurity(data []byte) (*HostSecurity, error) {
+	var doc struct {
+		HostSecurityID string `json:"HostSecurityId"`
+		Attributes     []struct {
+			AppstreamID string   `json:"AppstreamId"`
+			Name        string   `json:"Name"`
+			HsiLevel    int      `json:"HsiLevel"`
+			HsiResult   string   `json:"HsiResult"`
+			Flags       []string `json:"Flags"`
+		} `json:"SecurityAttributes"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	hsi := &HostSecurity{Attributes: []HSIAttribute{}}
+	obsoleted := func(flags []string) bool { return slices.Contains(flags, "obsoleted") }
+	for _, a := range doc.Attributes {
+		if obsoleted(a.Flags) {
+			continue
+		}
+		hsi.Attributes = append(hsi.Attributes, HSIAttribute{
+			ID:     a.AppstreamID,
+			Name:   a.Name,
+			Level:  a.HsiLevel,
+			Result: a.HsiResult,
+			Passed: slices.Contains(a.Flags, "success"),
+		})
+	}
+
+	if m := hsiIDPattern.FindStringSubmatch(doc.HostSecurityID); m != nil {
+		hsi.Level, _ = strconv.Atoi(m[1])
+		hsi.RuntimeIssues = m[2] == "!"
+	} else {
+		hsi.Level = 5
+		for _, a := range hsi.Attributes {
+			if a.Level == 0 && !a.Passed {
+				hsi.RuntimeIssues = true
+			}
+			if a.Level > 0 && !a.Passed && a.Level-1 < hsi.Level {
+				hsi.Level = a.Level - 1
+			}
+		}
+		if len(hsi.Attributes) == 0 {
+			hsi.Level = 0
+		}
+	}
+	hsi.ID = fmt.Sprintf("HSI:%d", hsi.Level)
+	if hsi.RuntimeIssues {
+		hsi.ID += "!"
+	}
+	return hsi, nil
+}
+
+// readWindowsFirmware reads the BIOS version and the ESRT capsule update
+// state from the registry
+func readWindowsFirmware(ctx context.Context, result *FirmwareResult) {
+	var perr *ProbeError
+	readString := func(path, name string) string {
+		v, _, err := registryString(ctx, path, name)
+		if err != nil && perr == nil {
+			perr = classifyRegistryError(ctx, path, err)
+		}
+		return v
+	}
+	readInteger := func(path, name string) uint64 {
+		v, _, err := registryInteger(ctx, path, name)
+		if err != nil && perr == nil {
+			perr = classifyRegistryError(ctx, path, err)
+		}
+		return v
+	}
+	result.Vendor = readString(biosKey, "BIOSVendor")
+	result.Version = readString(biosKey, "BIOSVersion")
+	result.ReleaseDate = readString(biosKey, "BIOSReleaseDate")
+
+	guids, err := registrySubKeys(ctx, esrtKey)
+	if err != nil && perr == nil {
+		perr = classifyRegistryError(ctx, esrtKey, err)
+	}
+	for _, guid := range guids {
+		key := esrtKey + `\` + guid
+		c := CapsuleUpdate{
+			GUID:                   strings.Trim(guid, "{}"),
+			Version:                readInteger(key, "Version"),
+			LowestSupportedVersion: readInteger(key, "LowestSupportedVersion"),
+			LastAttemptVersion:     readInteger(key, "LastAttemptVersion"),
+			LastAttemptStatus:      readInteger(key, "LastAttemptStatus"),
+		}
+		c.Status = fmt.Sprintf("unknown (%d)", c.LastAttemptStatus)
+		if c.LastAttemptStatus < uint64(len(esrtStatuses)) {
+			c.Status = esrtStatuses[c.LastAttemptStatus]
+		}
+		c.Failed = c.LastAttemptStatus != 0 && c.LastAttemptVersion > c.Version
+		result.Capsules = append(result.Capsules, c)
+	}
+	result.Error = perr
+}
+
+// parseSystemProfilerFirmware parses `system_profiler SPHardwareDataType
+// -json`. On Apple silicon the OS loader version is the firmware the
+// installed macOS expects; Intel Macs report only the boot ROM version.
+func parseSystemProfilerFirmware(data []byte, result *FirmwareResult) error {
+	var doc struct {
+		Hardware []struct {
+			BootROM  string `json:"boot_rom_version"`
+			OSLoader string `json:"os_loader_version"`
+		} `json:"SPHardwareDataType"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if len(doc.Hardware) == 0 {
+		return errors.New("no hardware overview")
+	}
+	result.Vendor = "Apple"
+	result.Version = doc.Hardware[0].BootROM
+	result.ExpectedVersion = doc.Hardware[0].OSLoader
+	return nil
+}
+
+// firmwareMismatch reports whether the firmware is older than the version
+// the installed macOS expects
+func firmwareMismatch(r *FirmwareResult) bool {
+	return r.ExpectedVersion != "" && r.Version != "" && compareVersions(r.Version, r.ExpectedVersion) < 0
+}
+
+// compareVersions compares dotted numeric versions such as 11881.140.96
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
+
+// firmwareFindings returns pending and failed firmware updates and a low
+// HSI rating as findings
+func firmwareFindings(r *FirmwareResult) []Finding {
+	var findings []Finding
+	add := func(id, title, severity, remediation string) {
+		findings = append(findings, Finding{
+			ID:                 id,
+			Title:              title,
+			Severity:           severity,
+			Check:              CheckFirmware,
+			Remediation:        remediation,
+			RemediationCommand: remediationCommand(id),
+			Disruptive:         remediationDisruptive(id),
+		})
+	}
+	if n := len(r.Updates); n > 0 {
+		severity := SeverityMedium
+		if slices.ContainsFunc(r.Updates, func(u FirmwareUpdate) bool { return u.Urgency == "high" || u.Urgency == "critical" }) {
+			severity = SeverityHigh
+		}
+		add("firmware_update_available", T("%d firmware updates are available", n), severity,
+			T("Install the firmware updates"))
+	}
+	if n := len(slices.DeleteFunc(slices.Clone(r.Capsules), func(c CapsuleUpdate) bool { return !c.Failed })); n > 0 {
+		add("firmware_update_failed", T("The last UEFI firmware update failed for %d devices", n), SeverityMedium,
+			T("Retry the firmware update with the vendor's update tool or Windows Update"))
+	}
+	if firmwareMismatch(r) {
+		add("firmware_outdated", T("System firmware %s is older than the installed macOS expects (%s)", r.Version, r.ExpectedVersion), SeverityMedium,
+			T("Reinstall the latest macOS update to update the firmware"))
+	}
+	if hsi := r.HostSecurity; hsi != nil && hsi.Level == 0 && len(hsi.Attributes) > 0 {
+		add("firmware_hsi_0", T("Host Security ID is %s: basic platform firmware protections are missing", hsi.ID), SeverityLow,
+			T("Review the failed attributes with fwupdmgr security and enable them in the firmware setup"))
+	}
+	if r.Error != nil && len(findings) == 0 {
+		findings = append(findings, unverifiedFinding("firmware_unverified", CheckFirmware, "Firmware", r.Error))
+	}
+	return findings
+}
+
+// firmwareUnverified reports whether an error left the update status
+// unknown, rather than an update showing the firmware out of date
+func firmwareUnverified(r *FirmwareResult) bool {
+	return r.Error != nil && len(r.Updates) == 0 && !firmwareMismatch(r) &&
+		!slices.ContainsFunc(r.Capsules, func(c CapsuleUpdate) bool { return c.Failed })
+}
+
+// FormatFirmwareTable formats the firmware status as a colored table
+func FormatFirmwareTable(result *FirmwareResult) string {
+	return FormatFirmwareTableWith(CurrentStyler(), result)
+}
+
+// FormatFirmwareTableWith is like FormatFirmwareTable but styles the table
+// with st
+func FormatFirmwareTableWith(st Styler, result *FirmwareResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconChip + " Firmware"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n")
+		if result.Error.Hint != "" {
+			sb.WriteString(st.Muted("  " + result.Error.Hint))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.TableTop(20, 36))
+	sb.WriteString("\n")
+	row := func(label, value string) {
+		sb.WriteString(st.TableRowColored(PadRight(label, 20), PadRight(value, 36)))
+		sb.WriteString("\n")
+	}
+	row("Vendor", result.Vendor)
+	row("Version", st.Info(result.Version))
+	if result.ReleaseDate != "" {
+		row("Release Date", result.ReleaseDate)
+	}
+	if result.ExpectedVersion != "" {
+		expected := st.Success(IconCheck + " " + result.ExpectedVersion)
+		if firmwareMismatch(result) {
+			expected = st.Danger(IconCross + " " + result.ExpectedVersion)
+		}
+		row("Expected by macOS", expected)
+	}
+	if hsi := result.HostSecurity; hsi != nil {
+		color := st.Success
+		if hsi.Level == 0 {
+			color = st.Danger
+		} else if hsi.Level == 1 || hsi.RuntimeIssues {
+			color = st.Warning
+		}
+		row("Host Security ID", color(hsi.ID))
+	}
+	status := st.Success(IconCheck + " up to date")
+	if firmwareUnverified(result) {
+		status = st.Warning("not verified")
+	} else if !result.Current {
+		status = st.Danger(IconCross + " action needed")
+	}
+	row("Status", status)
+	sb.WriteString(st.TableBottom(20, 36))
+	sb.WriteString("\n")
+
+	if len(result.Updates) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText("Available updates:"))
+		sb.WriteString("\n")
+		for _, u := range result.Updates {
+			line := fmt.Sprintf("  %s %s: %s → %s", IconArrow, u.Device, u.CurrentVersion, u.Version)
+			if u.Urgency != "" {
+				line += st.Muted(" (" + u.Urgency + ")")
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+	for _, c := range result.Capsules {
+		if c.Failed {
+			sb.WriteString(st.Danger(fmt.Sprintf("  %s %s: update to %d failed: %s", IconCross, c.GUID, c.LastAttemptVersion, c.Status)))
+			sb.WriteString("\n")
+		}
+	}
+	if hsi := result.HostSecurity; hsi != nil {
+		var failed []string
+		for _, a := range hsi.Attributes {
+			if !a.Passed && a.Level > 0 {
+				failed = append(failed, fmt.Sprintf("%s (HSI-%d)", a.Name, a.Level))
+			}
+		}
+		if len(failed) > 0 {
+			sb.WriteString("\n")
+			sb.WriteString(st.BoldText("Failed HSI attributes:"))
+			sb.WriteString("\n")
+			for _, f := range failed {
+				sb.WriteString(fmt.Sprintf("  %s %s\n", st.Warning(IconArrow), f))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// FormatFirmwareStatus formats the firmware status in the specified format
+func FormatFirmwareStatus(result *FirmwareResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatFirmwareTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,226 @@
+package inspector
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/agentplexus/posture/render"
+)
+
+const fwupdSecurityJSON = `{
+  "SecurityAttributes": [
+    {"AppstreamId": "org.fwupd.hsi.Uefi.SecureBoot", "Name": "UEFI secure boot", "HsiLevel": 1, "HsiResult": "enabled", "Flags": ["success"]},
+    {"AppstreamId": "org.fwupd.hsi.Spi.Bioswe", "Name": "SPI write", "HsiLevel": 1, "HsiResult": "not-enabled", "Flags": ["success"]},
+    {"AppstreamId": "org.fwupd.hsi.Tpm.Version20", "Name": "TPM v2.0", "HsiLevel": 1, "HsiResult": "found", "Flags": ["success"]},
+    {"AppstreamId": "org.fwupd.hsi.IntelBootguard.Enabled", "Name": "Intel BootGuard", "HsiLevel": 2, "HsiResult": "not-enabled", "Flags": ["action-contact-oem"]},
+    {"AppstreamId": "org.fwupd.hsi.Iommu", "Name": "IOMMU", "HsiLevel": 3, "HsiResult": "enabled", "Flags": ["success"]},
+    {"AppstreamId": "org.fwupd.hsi.Mei.ManufacturingMode", "Name": "Intel ME manufacturing mode", "HsiLevel": 1, "HsiResult": "locked", "Flags": ["success", "obsoleted"]},
+    {"AppstreamId": "org.fwupd.hsi.Kernel.Tainted", "Name": "Linux kernel", "HsiLevel": 0, "HsiResult": "tainted", "Flags": ["runtime-issue"]}
+  ]
+}`
+
+func TestParseFwupdSecurity(t *testing.T) {
+	hsi, err := parseFwupdSecurity([]byte(fwupdSecurityJSON))
+	if err != nil {
+		t.Fatalf("parseFwupdSecurity failed: %v", err)
+	}
+	if hsi.Level != 1 || !hsi.RuntimeIssues || hsi.ID != "HSI:1!" {
+		t.Errorf("HSI = %s (level %d, runtime issues %v), want HSI:1!", hsi.ID, hsi.Level, hsi.RuntimeIssues)
+	}
+	if len(hsi.Attributes) != 6 {
+		t.Errorf("attributes = %d, want 6 without the obsoleted one", len(hsi.Attributes))
+	}
+
+	// A level 1 failure means HSI:0
+	hsi, _ = parseFwupdSecurity([]byte(`{"SecurityAttributes": [
+		{"AppstreamId": "org.fwupd.hsi.Uefi.SecureBoot", "HsiLevel": 1, "HsiResult": "not-enabled", "Flags": []}
+	]}`))
+	if hsi.ID != "HSI:0" {
+		t.Errorf("HSI = %s, want HSI:0", hsi.ID)
+	}
+
+	// The daemon's own rating wins when the output carries it
+	hsi, _ = parseFwupdSecurity([]byte(`{"HostSecurityId": "HSI:3 (v1.9.16)", "SecurityAttributes": []}`))
+	if hsi.Level != 3 || hsi.RuntimeIssues {
+		t.Errorf("HSI = %+v, want level 3", hsi)
+	}
+}
+
+func TestReadFwupd(t *testing.T) {
+	fake := NewFakeRunner().
+		Set("fwupdmgr get-updates --json", []byte(`{"Devices": [
+			{"Name": "System Firmware", "Version": "0.1.2", "Releases": [
+				{"Version": "0.1.4", "Urgency": "high", "Summary": "Fixes CVE-2024-0762"},
+				{"Version": "0.1.3", "Urgency": "medium"}
+			]},
+			{"Name": "UEFI dbx", "Version": "371", "Releases": []}
+		]}`)).
+		Set("fwupdmgr security --json", []byte(fwupdSecurityJSON))
+	defer SetCommandRunner(SetCommandRunner(fake))
+
+	result := &FirmwareResult{}
+	readFwupd(context.Background(), result)
+	want := []FirmwareUpdate{{Device: "System Firmware", CurrentVersion: "0.1.2", Version: "0.1.4", Urgency: "high", Summary: "Fixes CVE-2024-0762"}}
+	if result.Error != nil || !slices.Equal(result.Updates, want) {
+		t.Errorf("updates = %+v (error %v), want %+v", result.Updates, result.Error, want)
+	}
+	if result.HostSecurity == nil || result.HostSecurity.ID != "HSI:1!" {
+		t.Errorf("HostSecurity = %+v", result.HostSecurity)
+	}
+	findings := firmwareFindings(result)
+	if len(findings) != 1 || findings[0].ID != "firmware_update_available" || findings[0].Severity != SeverityHigh {
+		t.Errorf("findings = %+v, want a high firmware_update_available", findings)
+	}
+
+	// No updates is a failure exit without an error to report
+	SetCommandRunner(NewFakeRunner().
+		SetError("fwupdmgr get-updates --json", &exec.ExitError{Stderr: []byte("No updatable devices\n")}).
+		Set("fwupdmgr security --json", []byte(fwupdSecurityJSON)))
+	result = &FirmwareResult{}
+	readFwupd(context.Background(), result)
+	if result.Error != nil || len(result.Updates) != 0 {
+		t.Errorf("result = %+v, want no updates and no error", result)
+	}
+
+	SetCommandRunner(NewFakeRunner())
+	result = &FirmwareResult{}
+	readFwupd(context.Background(), result)
+	if result.Error == nil || result.Error.Code != CodeToolMissing {
+		t.Errorf("Error = %+v, want fwupdmgr missing", result.Error)
+	}
+	if f := firmwareFindings(result); len(f) != 1 || f[0].ID != "firmware_unverified" {
+		t.Errorf("findings = %+v, want firmware_unverified", f)
+	}
+}
+
+func TestGetSecuritySummary_FwupdMissing(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("fwupd is Linux-only")
+	}
+	t.Setenv(OnlyChecksEnv, CheckFirmware)
+	t.Setenv(DisableChecksEnv, "")
+	t.Setenv(AssumeHostEnv, "")
+	prev := environmentRoot
+	t.Cleanup(func() { environmentRoot = prev })
+	environmentRoot = fstest.MapFS{}
+	defer SetCommandRunner(SetCommandRunner(NewFakeRunner()))
+
+	summary, err := GetSecuritySummaryContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetSecuritySummary failed: %v", err)
+	}
+	if summary.Firmware == nil || summary.Firmware.Current || summary.Firmware.Error == nil {
+		t.Fatalf("Firmware = %+v, want not current with an error", summary.Firmware)
+	}
+	if got := summary.CheckResults[CheckFirmware]; got == CheckResultPass {
+		t.Errorf("CheckResults[firmware] = %q, an unverified check must not pass", got)
+	}
+	if summary.OverallScore != 0 {
+		t.Errorf("OverallScore = %d, want 0", summary.OverallScore)
+	}
+	i := slices.IndexFunc(summary.Domains, func(d DomainSummary) bool { return d.ID == DomainPatching })
+	if i < 0 || summary.Domains[i].Score != 0 {
+		t.Errorf("Domains = %+v, want patching scored 0", summary.Domains)
+	}
+	if len(summary.Findings) != 1 || summary.Findings[0].ID != "firmware_unverified" {
+		t.Errorf("Findings = %+v, want [firmware_unverified]", summary.Findings)
+	}
+	table := StripANSI(FormatSecuritySummaryTableWith(Styler{Renderer: render.Plain{}}, summary))
+	if !strings.Contains(table, "Not verified") || strings.Contains(table, "Current") {
+		t.Errorf("table does not show firmware as not verified:\n%s", table)
+	}
+}
+
+func TestReadDMIFirmware(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sys/class/dmi/id/bios_vendor":  {Data: []byte("LENOVO\n")},
+		"sys/class/dmi/id/bios_version": {Data: []byte("N32ET91W (1.67 )\n")},
+		"sys/class/dmi/id/bios_date":    {Data: []byte("03/12/2024\n")},
+	}
+	result := &FirmwareResult{}
+	readDMIFirmware(fsys, result)
+	if result.Vendor != "LENOVO" || result.Version != "N32ET91W (1.67 )" || result.ReleaseDate != "03/12/2024" {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestReadWindowsFirmware(t *testing.T) {
+	fake := NewFakeRegistry().
+		SetString(biosKey, "BIOSVendor", "Dell Inc.").
+		SetString(biosKey, "BIOSVersion", "1.18.0").
+		SetString(biosKey, "BIOSReleaseDate", "05/14/2024").
+		SetInteger(esrtKey+`\{6BD2EFA0-1B4F-4E5E-9E3C-6C5D2F8A0001}`, "Version", 0x01120000).
+		SetInteger(esrtKey+`\{6BD2EFA0-1B4F-4E5E-9E3C-6C5D2F8A0001}`, "LastAttemptVersion", 0x01130000).
+		SetInteger(esrtKey+`\{6BD2EFA0-1B4F-4E5E-9E3C-6C5D2F8A0001}`, "LastAttemptStatus", 6).
+		SetInteger(esrtKey+`\{C1A2B3D4-0000-4E5E-9E3C-6C5D2F8A0002}`, "Version", 5).
+		SetInteger(esrtKey+`\{C1A2B3D4-0000-4E5E-9E3C-6C5D2F8A0002}`, "LastAttemptVersion", 5).
+		SetInteger(esrtKey+`\{C1A2B3D4-0000-4E5E-9E3C-6C5D2F8A0002}`, "LastAttemptStatus", 0)
+	defer SetRegistryReader(SetRegistryReader(fake))
+
+	result := &FirmwareResult{}
+	readWindowsFirmware(context.Background(), result)
+	if result.Error != nil || result.Vendor != "Dell Inc." || result.Version != "1.18.0" {
+		t.Fatalf("result = %+v", result)
+	}
+	if len(result.Capsules) != 2 {
+		t.Fatalf("capsules = %+v, want 2", result.Capsules)
+	}
+	failed := result.Capsules[0]
+	if !failed.Failed || failed.GUID != "6BD2EFA0-1B4F-4E5E-9E3C-6C5D2F8A0001" || failed.Status != "power event (AC not connected)" {
+		t.Errorf("capsule = %+v, want a failed update", failed)
+	}
+	if result.Capsules[1].Failed || result.Capsules[1].Status != "success" {
+		t.Errorf("capsule = %+v, want success", result.Capsules[1])
+	}
+	findings := firmwareFindings(result)
+	if len(findings) != 1 || findings[0].ID != "firmware_update_failed" {
+		t.Errorf("findings = %+v, want firmware_update_failed", findings)
+	}
+}
+
+func TestParseSystemProfilerFirmware(t *testing.T) {
+	data := []byte(`{"SPHardwareDataType": [{
+		"machine_model": "Mac14,2",
+		"boot_rom_version": "10151.140.19",
+		"os_loader_version": "11881.140.96"
+	}]}`)
+	result := &FirmwareResult{}
+	if err := parseSystemProfilerFirmware(data, result); err != nil {
+		t.Fatalf("parseSystemProfilerFirmware failed: %v", err)
+	}
+	if !firmwareMismatch(result) {
+		t.Errorf("firmware %s should be older than %s", result.Version, result.ExpectedVersion)
+	}
+	findings := firmwareFindings(result)
+	if len(findings) != 1 || findings[0].ID != "firmware_outdated" {
+		t.Errorf("findings = %+v, want firmware_outdated", findings)
+	}
+
+	result.Version = "11881.140.96"
+	if firmwareMismatch(result) || len(firmwareFindings(result)) != 0 {
+		t.Error("matching firmware should be current")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"10151.140.19", "11881.140.96", -1},
+		{"11881.140.96", "11881.140.96", 0},
+		{"11881.140.96.1", "11881.140.96", 1},
+		{"8422.141.2", "8422.141.1", 1},
+	}
+	for _, tt := range tests {
+		got := compareVersions(tt.a, tt.b)
+		if got < 0 && tt.want >= 0 || got > 0 && tt.want <= 0 || got == 0 && tt.want != 0 {
+			t.Errorf("compareVersions(%q, %q) = %d, want sign %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
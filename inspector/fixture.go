@@ -0,0 +1,61 @@
+package inspector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FixtureEnv names a directory of JSON results to serve instead of probing
+// the host, for demos and for developing clients on any platform
+const FixtureEnv = "OMNITRUST_FIXTURE"
+
+// FixtureDir returns the fixture directory, or "" when probing the host
+func FixtureDir() string {
+	return os.Getenv(FixtureEnv)
+}
+
+// loadFixture reads the result called name (its schema name, e.g. "tpm")
+// from <dir>/<name>.json. ok is false when fixtures are off. With fixtures
+// on, the host is never probed: a result without a file is reported as
+// unsupported, as if the fixture platform did not have the check.
+func loadFixture[T any](ctx context.Context, name string) (result *T, ok bool, err error) {
+	dir := FixtureDir()
+	if dir == "" {
+		return nil, false, nil
+	}
+	path := filepath.Join(dir, name+".json")
+	data, err := os.ReadFile(path) // #nosec G304 -- the operator's fixture directory
+	recordSource(SourceFixture, path, err)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, true, newProbeError(ctx, ErrUnsupportedPlatform, name, "no fixture "+path)
+	}
+	if err != nil {
+		return nil, true, fmt.Errorf("fixture %s: %w", path, err)
+	}
+	result = new(T)
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, true, fmt.Errorf("fixture %s: %w", path, err)
+	}
+	return result, true, nil
+}
+
+// loadFixtureOr is loadFixture for results that cannot fail: a missing or
+// unreadable fixture gives the empty result
+func loadFixtureOr[T any](ctx context.Context, name string) (result *T, ok bool) {
+	result, ok, err := loadFixture[T](ctx, name)
+	if !ok {
+		return nil, false
+	}
+	if err != nil {
+		if !errors.Is(err, ErrUnsupportedPlatform) {
+			loggerFrom(ctx).Warn("cannot read the fixture", "name", name, "error", err)
+		}
+		return new(T), true
+	}
+	return result, true
+}
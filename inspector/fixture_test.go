@@ -0,0 +1,84 @@
+package inspector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLoadFixture(t *testing.T) {
+	t.Setenv(FixtureEnv, "")
+	if _, ok, _ := loadFixture[TPMResult](context.Background(), "tpm"); ok {
+		t.Fatal("fixtures should be off without OMNITRUST_FIXTURE")
+	}
+
+	dir := t.TempDir()
+	t.Setenv(FixtureEnv, dir)
+	if err := os.WriteFile(filepath.Join(dir, "tpm.json"), []byte(`{"present": true, "tpm_kind": "virtual"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tpm, err := GetTPMStatusContext(context.Background())
+	if err != nil || !tpm.Present || tpm.Kind != "virtual" {
+		t.Errorf("GetTPMStatus = %+v, %v, want the fixture", tpm, err)
+	}
+	if _, err := GetUptimeContext(context.Background()); !errors.Is(err, ErrUnsupportedPlatform) {
+		t.Errorf("GetUptime without a fixture = %v, want unsupported", err)
+	}
+	if env := GetRuntimeEnvironmentContext(context.Background()); env.Containerized {
+		t.Error("a missing environment fixture should describe a plain host")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "uptime.json"), []byte(`{"uptime_seconds": "long"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetUptimeContext(context.Background()); err == nil || errors.Is(err, ErrUnsupportedPlatform) {
+		t.Errorf("GetUptime with a malformed fixture = %v, want a parse error", err)
+	}
+}
+
+func TestShippedFixtures(t *testing.T) {
+	dirs, err := filepath.Glob(filepath.Join("..", "fixtures", "*"))
+	if err != nil || len(dirs) == 0 {
+		t.Fatalf("no fixtures: %v", err)
+	}
+	for _, dir := range dirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		if len(files) == 0 {
+			t.Errorf("%s has no fixtures", dir)
+		}
+		for _, file := range files {
+			name := strings.TrimSuffix(filepath.Base(file), ".json")
+			typ, ok := resultTypes[name]
+			if !ok {
+				t.Errorf("%s: %s is not a result schema name", file, name)
+				continue
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Result types differ between platforms, so only the fixtures
+			// of this platform must match exactly
+			dec := json.NewDecoder(bytes.NewReader(data))
+			if filepath.Base(dir) == runtime.GOOS {
+				dec.DisallowUnknownFields()
+			}
+			if err := dec.Decode(reflect.New(typ).Interface()); err != nil {
+				t.Errorf("%s: %v", file, err)
+			}
+		}
+	}
+
+	t.Setenv(FixtureEnv, filepath.Join("..", "fixtures", "windows"))
+	summary, err := GetSecuritySummaryContext(context.Background())
+	if err != nil || summary.Platform != "windows" || len(summary.Findings) == 0 {
+		t.Errorf("GetSecuritySummary = %+v, %v, want the Windows fixture", summary, err)
+	}
+}
@@ -3,49 +3,61 @@ package inspector
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 
-	"github.com/mattn/go-runewidth"
+	"github.com/agentplexus/posture/redact"
+	"github.com/agentplexus/posture/render"
 )
 
 // OutputFormat constants
 const (
-	FormatJSON  = "json"
-	FormatTable = "table"
+	FormatJSON     = "json"
+	FormatTable    = "table"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
 )
 
-// ANSI color codes
+// documentRenderers render the table of the document formats
+var documentRenderers = map[string]render.Renderer{
+	FormatMarkdown: render.Markdown{},
+	FormatHTML:     render.HTML{},
+}
+
+// ANSI color codes, kept for callers of Colorize; formatters use styles
 const (
-	Reset     = "\033[0m"
-	Bold      = "\033[1m"
-	Dim       = "\033[2m"
-	Italic    = "\033[3m"
-	Underline = "\033[4m"
+	Reset     = render.Reset
+	Bold      = render.Bold
+	Dim       = render.Dim
+	Italic    = render.Italic
+	Underline = render.Underline
 
 	// Foreground colors
-	Black   = "\033[30m"
-	Red     = "\033[31m"
-	Green   = "\033[32m"
-	Yellow  = "\033[33m"
-	Blue    = "\033[34m"
-	Magenta = "\033[35m"
-	Cyan    = "\033[36m"
-	White   = "\033[37m"
+	Black   = render.Black
+	Red     = render.Red
+	Green   = render.Green
+	Yellow  = render.Yellow
+	Blue    = render.Blue
+	Magenta = render.Magenta
+	Cyan    = render.Cyan
+	White   = render.White
 
 	// Bright foreground colors
-	BrightBlack   = "\033[90m"
-	BrightRed     = "\033[91m"
-	BrightGreen   = "\033[92m"
-	BrightYellow  = "\033[93m"
-	BrightBlue    = "\033[94m"
-	BrightMagenta = "\033[95m"
-	BrightCyan    = "\033[96m"
-	BrightWhite   = "\033[97m"
+	BrightBlack   = render.BrightBlack
+	BrightRed     = render.BrightRed
+	BrightGreen   = render.BrightGreen
+	BrightYellow  = render.BrightYellow
+	BrightBlue    = render.BrightBlue
+	BrightMagenta = render.BrightMagenta
+	BrightCyan    = render.BrightCyan
+	BrightWhite   = render.BrightWhite
 
 	// Background colors
-	BgBlue  = "\033[44m"
-	BgCyan  = "\033[46m"
-	BgWhite = "\033[47m"
+	BgBlue  = render.BgBlue
+	BgCyan  = render.BgCyan
+	BgWhite = render.BgWhite
 )
 
 // UTF-8 icons
@@ -74,51 +86,288 @@ const (
 	IconFace        = "👤"
 	IconApple       = "🍎"
 	IconChip        = "🔲"
+	IconThermometer = "🌡️ "
+	IconFan         = "🌀"
+)
+
+// Color modes accepted by ResolveColor
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
 )
 
-// Colorize wraps text with a color and reset
+// NoColorEnv disables colors when set to any non-empty value (https://no-color.org)
+const NoColorEnv = "NO_COLOR"
+
+// activeRenderer holds the render.Renderer table output is styled with
+var activeRenderer atomic.Value
+
+func init() {
+	if os.Getenv(NoColorEnv) == "" {
+		SetRenderer(render.ANSI{})
+	} else {
+		SetRenderer(render.Plain{})
+	}
+}
+
+// rendererBox wraps renderers so atomic.Value always stores one type
+type rendererBox struct{ render.Renderer }
+
+// SetRenderer styles table output with r and returns the previous renderer.
+// nil selects plain text.
+func SetRenderer(r render.Renderer) render.Renderer {
+	if r == nil {
+		r = render.Plain{}
+	}
+	prev, _ := activeRenderer.Swap(rendererBox{r}).(rendererBox)
+	return prev.Renderer
+}
+
+// CurrentRenderer returns the renderer table output is styled with
+func CurrentRenderer() render.Renderer {
+	return activeRenderer.Load().(rendererBox).Renderer
+}
+
+// colorTheme holds the render.Theme of colored table output
+var colorTheme atomic.Value
+
+// SetTheme colors table output with t from now on; nil selects
+// render.DefaultTheme
+func SetTheme(t render.Theme) {
+	if t == nil {
+		t = render.DefaultTheme
+	}
+	colorTheme.Store(t)
+	if _, ok := CurrentRenderer().(render.ANSI); ok {
+		SetRenderer(render.ANSI{Theme: t})
+	}
+}
+
+// Theme returns the theme of colored table output
+func Theme() render.Theme {
+	if t, ok := colorTheme.Load().(render.Theme); ok {
+		return t
+	}
+	return render.DefaultTheme
+}
+
+// SetColorEnabled turns ANSI colors in table output on or off
+func SetColorEnabled(enabled bool) {
+	if enabled {
+		SetRenderer(render.ANSI{Theme: Theme()})
+	} else {
+		SetRenderer(render.Plain{})
+	}
+}
+
+// ColorEnabled reports whether table output is styled
+func ColorEnabled() bool {
+	_, plain := CurrentRenderer().(render.Plain)
+	return !plain
+}
+
+// ResolveColor decides whether to color output for a color mode. In auto
+// mode output is colored only when it goes to a terminal, NO_COLOR is unset,
+// and TERM is not "dumb".
+func ResolveColor(mode string, terminal bool) (bool, error) {
+	switch strings.ToLower(mode) {
+	case ColorAlways:
+		return true, nil
+	case ColorNever:
+		return false, nil
+	case "", ColorAuto:
+		return terminal && os.Getenv(NoColorEnv) == "" && os.Getenv("TERM") != "dumb", nil
+	}
+	return false, fmt.Errorf("invalid color mode %q (use %s, %s, or %s)", mode, ColorAuto, ColorAlways, ColorNever)
+}
+
+// Styler styles table output with one renderer. Formatters take a Styler
+// rather than reading the process-wide renderer, so a Markdown or HTML
+// document rendered on one goroutine cannot restyle a table being rendered
+// on another.
+type Styler struct {
+	Renderer render.Renderer
+}
+
+// CurrentStyler styles with the current renderer
+func CurrentStyler() Styler {
+	return Styler{Renderer: CurrentRenderer()}
+}
+
+// Styled marks text with a style of the styler's renderer
+func (st Styler) Styled(style render.Style, text string) string {
+	return st.Renderer.Style(style, text)
+}
+
+// BoldText makes text bold
+func (st Styler) BoldText(text string) string {
+	return st.Styled(render.StyleBold, text)
+}
+
+// DimText makes text dimmed
+func (st Styler) DimText(text string) string {
+	return st.Styled(render.StyleDim, text)
+}
+
+// Header formats text as a header (bold cyan)
+func (st Styler) Header(text string) string {
+	return st.Styled(render.StyleHeader, text)
+}
+
+// Success formats text as success (green)
+func (st Styler) Success(text string) string {
+	return st.Styled(render.StyleSuccess, text)
+}
+
+// Warning formats text as warning (yellow)
+func (st Styler) Warning(text string) string {
+	return st.Styled(render.StyleWarning, text)
+}
+
+// Danger formats text as danger (red)
+func (st Styler) Danger(text string) string {
+	return st.Styled(render.StyleDanger, text)
+}
+
+// Info formats text as info (blue)
+func (st Styler) Info(text string) string {
+	return st.Styled(render.StyleInfo, text)
+}
+
+// Muted formats text as muted (gray)
+func (st Styler) Muted(text string) string {
+	return st.Styled(render.StyleMuted, text)
+}
+
+// TableRowColored creates a colored table row
+func (st Styler) TableRowColored(cols ...string) string {
+	return render.TableRow(st.Renderer, cols...)
+}
+
+// TableSeparator creates a separator line for tables
+func (st Styler) TableSeparator(widths ...int) string {
+	return render.TableSeparator(st.Renderer, widths...)
+}
+
+// TableTop creates a top border for tables
+func (st Styler) TableTop(widths ...int) string {
+	return render.TableTop(st.Renderer, widths...)
+}
+
+// TableBottom creates a bottom border for tables
+func (st Styler) TableBottom(widths ...int) string {
+	return render.TableBottom(st.Renderer, widths...)
+}
+
+// ProgressBar creates a colored progress bar
+func (st Styler) ProgressBar(percent float64, width int) string {
+	return render.ProgressBar(st.Renderer, percent, width, render.UsageStyle(percent))
+}
+
+// BoolToStatusColored returns a colored status string
+func (st Styler) BoolToStatusColored(b bool) string {
+	if b {
+		return st.Success(IconCheck + " " + T("Yes"))
+	}
+	return st.Danger(IconCross + " " + T("No"))
+}
+
+// BoolToCheckbox returns a checkbox icon
+func (st Styler) BoolToCheckbox(b bool) string {
+	if b {
+		return st.Success("☑")
+	}
+	return st.Muted("☐")
+}
+
+// Styled marks text with a style of the current renderer
+func Styled(style render.Style, text string) string {
+	return CurrentStyler().Styled(style, text)
+}
+
+// Colorize wraps text with raw ANSI codes and a reset. Only the ANSI
+// renderer shows them; formatters should use Styled instead.
 func Colorize(color, text string) string {
+	if _, ok := CurrentRenderer().(render.ANSI); !ok {
+		return text
+	}
 	return color + text + Reset
 }
 
 // Bold makes text bold
 func BoldText(text string) string {
-	return Bold + text + Reset
+	return CurrentStyler().BoldText(text)
 }
 
 // Dim makes text dimmed
 func DimText(text string) string {
-	return Dim + text + Reset
+	return CurrentStyler().DimText(text)
 }
 
 // Header formats text as a header (bold cyan)
 func Header(text string) string {
-	return Bold + Cyan + text + Reset
+	return CurrentStyler().Header(text)
 }
 
 // Success formats text as success (green)
 func Success(text string) string {
-	return Green + text + Reset
+	return CurrentStyler().Success(text)
 }
 
 // Warning formats text as warning (yellow)
 func Warning(text string) string {
-	return Yellow + text + Reset
+	return CurrentStyler().Warning(text)
 }
 
 // Danger formats text as danger (red)
 func Danger(text string) string {
-	return Red + text + Reset
+	return CurrentStyler().Danger(text)
 }
 
 // Info formats text as info (blue)
 func Info(text string) string {
-	return Blue + text + Reset
+	return CurrentStyler().Info(text)
 }
 
 // Muted formats text as muted (gray)
 func Muted(text string) string {
-	return BrightBlack + text + Reset
+	return CurrentStyler().Muted(text)
+}
+
+// TableRowColored creates a colored table row
+func TableRowColored(cols ...string) string {
+	return CurrentStyler().TableRowColored(cols...)
+}
+
+// TableSeparator creates a separator line for tables
+func TableSeparator(widths ...int) string {
+	return CurrentStyler().TableSeparator(widths...)
+}
+
+// TableTop creates a top border for tables
+func TableTop(widths ...int) string {
+	return CurrentStyler().TableTop(widths...)
+}
+
+// TableBottom creates a bottom border for tables
+func TableBottom(widths ...int) string {
+	return CurrentStyler().TableBottom(widths...)
+}
+
+// ProgressBar creates a colored progress bar
+func ProgressBar(percent float64, width int) string {
+	return CurrentStyler().ProgressBar(percent, width)
+}
+
+// BoolToStatusColored returns a colored status string
+func BoolToStatusColored(b bool) string {
+	return CurrentStyler().BoolToStatusColored(b)
+}
+
+// BoolToCheckbox returns a checkbox icon
+func BoolToCheckbox(b bool) string {
+	return CurrentStyler().BoolToCheckbox(b)
 }
 
 // FormatBytes converts bytes to human-readable format
@@ -146,140 +395,158 @@ func TableRow(cols ...string) string {
 	return "│ " + strings.Join(cols, " │ ") + " │"
 }
 
-// TableRowColored creates a colored table row
-func TableRowColored(cols ...string) string {
-	return Muted("│") + " " + strings.Join(cols, " "+Muted("│")+" ") + " " + Muted("│")
-}
-
-// TableSeparator creates a separator line for tables
-func TableSeparator(widths ...int) string {
-	parts := make([]string, len(widths))
-	for i, w := range widths {
-		parts[i] = strings.Repeat("─", w)
-	}
-	return Muted("├─" + strings.Join(parts, "─┼─") + "─┤")
-}
-
-// TableTop creates a top border for tables
-func TableTop(widths ...int) string {
-	parts := make([]string, len(widths))
-	for i, w := range widths {
-		parts[i] = strings.Repeat("─", w)
-	}
-	return Muted("┌─" + strings.Join(parts, "─┬─") + "─┐")
-}
-
-// TableBottom creates a bottom border for tables
-func TableBottom(widths ...int) string {
-	parts := make([]string, len(widths))
-	for i, w := range widths {
-		parts[i] = strings.Repeat("─", w)
-	}
-	return Muted("└─" + strings.Join(parts, "─┴─") + "─┘")
-}
-
 // PadRight pads a string to the right to reach the specified width
 func PadRight(s string, width int) string {
-	visLen := VisibleLen(s)
-	if visLen >= width {
-		return s
-	}
-	return s + strings.Repeat(" ", width-visLen)
+	return render.PadRight(s, width)
+}
+
+// Truncate shortens plain text to at most width columns, ending it with
+// "..." where it is cut
+func Truncate(s string, width int) string {
+	return render.Truncate(s, width)
 }
 
 // PadLeft pads a string to the left to reach the specified width
 func PadLeft(s string, width int) string {
-	visLen := VisibleLen(s)
-	if visLen >= width {
-		return s
-	}
-	return strings.Repeat(" ", width-visLen) + s
+	return render.PadLeft(s, width)
 }
 
 // StripANSI removes ANSI escape codes from a string
 func StripANSI(s string) string {
-	var result strings.Builder
-	inEscape := false
-	for _, r := range s {
-		if r == '\033' {
-			inEscape = true
-			continue
-		}
-		if inEscape {
-			if r == 'm' {
-				inEscape = false
-			}
-			continue
-		}
-		result.WriteRune(r)
-	}
-	return result.String()
+	return render.StripANSI(s)
 }
 
 // VisibleLen calculates the visible display width of a string
 // (excluding ANSI codes and accounting for wide characters like emojis)
 func VisibleLen(s string) int {
-	return runewidth.StringWidth(StripANSI(s))
+	return render.VisibleLen(s)
 }
 
-// ProgressBar creates a colored progress bar
-func ProgressBar(percent float64, width int) string {
-	filled := int(percent / 100 * float64(width))
-	if filled > width {
-		filled = width
-	}
-	if filled < 0 {
-		filled = 0
+// FormatOutput returns the result in the requested format: json, table,
+// markdown or html (the table as a document), csv or ndjson (one row per
+// element of a list-shaped result), or a template format from
+// TemplateFormat. A result that cannot be rendered yields the error
+// message; callers that must tell it apart use RenderOutput or Render.
+// tableFunc styles the table through the current renderer, which documents
+// swap for their own while it runs; FormatOutputWith passes the renderer
+// instead. After SetRedactor, identifying values are masked.
+func FormatOutput(data any, tableFunc func() string, format string) string {
+	return FormatOutputWith(data, currentTable(tableFunc), format)
+}
+
+// FormatOutputWith is like FormatOutput but renders the table with the
+// Styler it gives tableFunc: the current renderer's for table output, or
+// the document's
+func FormatOutputWith(data any, tableFunc func(Styler) string, format string) string {
+	output, err := RenderOutputWith(data, tableFunc, format)
+	if err != nil {
+		return err.Error()
 	}
+	return output
+}
 
-	var color string
-	switch {
-	case percent >= 90:
-		color = Red
-	case percent >= 70:
-		color = Yellow
-	default:
-		color = Green
+// RenderOutput is FormatOutput returning the error of a result that cannot
+// be rendered in format, such as a template that fails on it
+func RenderOutput(data any, tableFunc func() string, format string) (string, error) {
+	return RenderOutputWith(data, currentTable(tableFunc), format)
+}
+
+// RenderOutputWith is FormatOutputWith returning the error of a result that
+// cannot be rendered in format
+func RenderOutputWith(data any, tableFunc func(Styler) string, format string) (string, error) {
+	output, err := formatOutput(data, tableFunc, format)
+	if err != nil {
+		return "", err
+	}
+	if redactor != nil {
+		redactor.Collect(data)
+		output = redactor.String(output)
 	}
+	return output, nil
+}
+
+// documentMu serializes tables rendered through currentTable, which swap
+// the renderer
+var documentMu sync.Mutex
 
-	bar := color + strings.Repeat(IconBar, filled) + Reset
-	bar += Muted(strings.Repeat(IconBarLight, width-filled))
-	return bar
+// currentTable adapts a table function that styles with the current
+// renderer. The renderer is process-wide, so tables rendered concurrently
+// with one of its documents may pick up the document's styles.
+func currentTable(tableFunc func() string) func(Styler) string {
+	if tableFunc == nil {
+		return nil
+	}
+	return func(st Styler) string {
+		documentMu.Lock()
+		defer documentMu.Unlock()
+		defer SetRenderer(SetRenderer(st.Renderer))
+		return tableFunc()
+	}
 }
 
-// BoolToStatusColored returns a colored status string
-func BoolToStatusColored(b bool) string {
-	if b {
-		return Success(IconCheck + " Yes")
+// Render renders data with formatFunc, one of the Format* functions, and
+// returns the error of a format rendered from the data alone (template,
+// csv, or ndjson) that fails on it instead of the error message
+func Render[T any](data T, format string, formatFunc func(T, string) string) (string, error) {
+	if !dataFormat(format) {
+		return formatFunc(data, format), nil
 	}
-	return Danger(IconCross + " No")
+	return RenderOutputWith(data, nil, format)
 }
 
-// BoolToCheckbox returns a checkbox icon
-func BoolToCheckbox(b bool) string {
-	if b {
-		return Success("☑")
+// dataFormat reports whether format renders the data without its table
+func dataFormat(format string) bool {
+	if _, ok := splitTemplateFormat(format); ok {
+		return true
 	}
-	return Muted("☐")
+	switch strings.ToLower(format) {
+	case FormatCSV, FormatNDJSON:
+		return true
+	}
+	return false
 }
 
-// FormatOutput returns the result in the requested format (json or table)
-func FormatOutput(data any, tableFunc func() string, format string) string {
-	if strings.ToLower(format) == FormatTable {
-		return tableFunc()
+// formatOutput renders data without redaction
+func formatOutput(data any, tableFunc func(Styler) string, format string) (string, error) {
+	if tmpl, ok := splitTemplateFormat(format); ok {
+		return RenderTemplate(data, tmpl)
+	}
+	format = strings.ToLower(format)
+	// Provenance follows the table, and its JSON needs the envelope
+	if ProvenanceEnabled() {
+		table := tableFunc
+		tableFunc = func(st Styler) string { return table(st) + FormatProvenanceTableWith(st, Provenance(data)) }
+	}
+	if r, ok := documentRenderers[format]; ok {
+		return r.Document(tableFunc(Styler{Renderer: r})), nil
+	}
+	switch format {
+	case FormatTable:
+		r := CurrentRenderer()
+		return r.Document(tableFunc(Styler{Renderer: r})), nil
+	case FormatCSV:
+		return formatCSV(data)
+	case FormatNDJSON:
+		return formatNDJSON(data)
+	}
+	if _, wrapped := data.(*Envelope); (envelopeEnabled || ProvenanceEnabled()) && !wrapped {
+		data = NewEnvelope(data, envelopeStart)
 	}
 	resultJSON, _ := json.MarshalIndent(data, "", "  ")
-	return string(resultJSON)
+	return string(resultJSON), nil
 }
 
-// UsageColor returns the appropriate color based on usage percentage
+// redactor masks identifying values in every formatted output, when set
+var redactor *redact.Redactor
+
+// SetRedactor masks the identifying values r finds in every output
+// FormatOutput renders, whatever the format. nil turns redaction off.
+func SetRedactor(r *redact.Redactor) {
+	redactor = r
+}
+
+// UsageColor returns the ANSI color of a usage percentage, for callers of
+// Colorize; formatters use render.UsageStyle
 func UsageColor(percent float64) string {
-	switch {
-	case percent >= 90:
-		return Red
-	case percent >= 70:
-		return Yellow
-	default:
-		return Green
-	}
+	return render.DefaultTheme[render.UsageStyle(percent)]
 }
@@ -2,7 +2,10 @@ package inspector
 
 import (
 	"strings"
+	"sync"
 	"testing"
+
+	"github.com/agentplexus/posture/render"
 )
 
 func TestFormatBytes(t *testing.T) {
@@ -141,6 +144,47 @@ func TestColorize(t *testing.T) {
 	}
 }
 
+func TestColorize_Disabled(t *testing.T) {
+	defer SetColorEnabled(ColorEnabled())
+	SetColorEnabled(false)
+
+	if got := Colorize(Red, "error"); got != "error" {
+		t.Errorf("Colorize with color disabled = %q, want plain text", got)
+	}
+	if got := ProgressBar(50, 10); strings.Contains(got, "\033") {
+		t.Errorf("ProgressBar with color disabled contains escape codes: %q", got)
+	}
+}
+
+func TestResolveColor(t *testing.T) {
+	tests := []struct {
+		mode     string
+		terminal bool
+		noColor  string
+		term     string
+		want     bool
+	}{
+		{"auto", true, "", "xterm-256color", true},
+		{"auto", false, "", "xterm-256color", false},
+		{"", true, "1", "xterm-256color", false},
+		{"auto", true, "", "dumb", false},
+		{"always", false, "1", "dumb", true},
+		{"never", true, "", "xterm", false},
+	}
+	for _, tt := range tests {
+		t.Setenv(NoColorEnv, tt.noColor)
+		t.Setenv("TERM", tt.term)
+		got, err := ResolveColor(tt.mode, tt.terminal)
+		if err != nil || got != tt.want {
+			t.Errorf("ResolveColor(%q, %v) with NO_COLOR=%q TERM=%q = %v, %v; want %v",
+				tt.mode, tt.terminal, tt.noColor, tt.term, got, err, tt.want)
+		}
+	}
+	if _, err := ResolveColor("rainbow", true); err == nil {
+		t.Error("ResolveColor should reject an unknown mode")
+	}
+}
+
 func TestFormattingFunctions(t *testing.T) {
 	tests := []struct {
 		name string
@@ -332,6 +376,59 @@ func TestFormatOutput(t *testing.T) {
 	}
 }
 
+func TestFormatOutput_Documents(t *testing.T) {
+	prev := SetRenderer(render.ANSI{})
+	defer SetRenderer(prev)
+	tableFunc := func(st Styler) string { return st.Header("Status") + " " + st.Danger("<off>") }
+
+	if got, want := FormatOutputWith(nil, tableFunc, FormatMarkdown), "```text\nStatus <off>\n```\n"; got != want {
+		t.Errorf("markdown = %q, want %q", got, want)
+	}
+
+	html := FormatOutputWith(nil, tableFunc, FormatHTML)
+	if !strings.Contains(html, `<span class="header">Status</span> <span class="danger">&lt;off&gt;</span>`) {
+		t.Errorf("html = %q, want styled spans", html)
+	}
+	if strings.Contains(html, "\033") {
+		t.Errorf("html contains escape codes: %q", html)
+	}
+
+	// A table styled through the current renderer gets the document's
+	legacy := func() string { return Header("Status") }
+	if got, want := FormatOutput(nil, legacy, FormatMarkdown), "```text\nStatus\n```\n"; got != want {
+		t.Errorf("markdown from the current renderer = %q, want %q", got, want)
+	}
+
+	if _, ok := CurrentRenderer().(render.ANSI); !ok {
+		t.Errorf("renderer after a document = %T, want it unchanged", CurrentRenderer())
+	}
+}
+
+func TestFormatOutput_ConcurrentDocuments(t *testing.T) {
+	prev := SetRenderer(render.ANSI{})
+	defer SetRenderer(prev)
+	tableFunc := func(st Styler) string { return st.Header("Status") }
+
+	// A table rendered while documents render keeps the current renderer
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if got := FormatOutputWith(nil, tableFunc, FormatTable); !strings.Contains(got, "\033[") {
+				t.Errorf("table = %q, want ANSI styles", got)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if got := FormatOutputWith(nil, tableFunc, FormatMarkdown); strings.Contains(got, "\033[") {
+				t.Errorf("markdown = %q, want no escape codes", got)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 func TestConstants(t *testing.T) {
 	// Verify format constants
 	if FormatJSON != "json" {
@@ -0,0 +1,380 @@
+package inspector
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/agentplexus/posture/render"
+)
+
+// updateGolden rewrites the golden files instead of comparing against them:
+// go test ./inspector -run TestGoldenTables -update
+var updateGolden = flag.Bool("update", false, "rewrite the golden files of TestGoldenTables")
+
+// goldenTable renders a result decoded from JSON with its table formatter
+func goldenTable[T any](format func(Styler, *T) string) func(Styler, []byte) (string, error) {
+	return func(st Styler, data []byte) (string, error) {
+		result := new(T)
+		if err := json.Unmarshal(data, result); err != nil {
+			return "", err
+		}
+		return format(st, result), nil
+	}
+}
+
+// goldenTables are the table formatters with the same output on every
+// platform, by result schema name
+var goldenTables = map[string]func(Styler, []byte) (string, error){
+	"baseline":          goldenTable(FormatBaselineTableWith),
+	"boot_order":        goldenTable(FormatBootOrderTableWith),
+	"browser":           goldenTable(FormatBrowserSecurityTableWith),
+	"checks":            goldenTable(FormatCheckListTableWith),
+	"cloud":             goldenTable(FormatCloudContextTableWith),
+	"cpu":               goldenTable(FormatCPUUsageTableWith),
+	"defender":          goldenTable(FormatDefenderTableWith),
+	"docker":            goldenTable(FormatContainerSecurityTableWith),
+	"doctor":            goldenTable(FormatDoctorTableWith),
+	"dry_run":           goldenTable(FormatDryRunTableWith),
+	"environment":       goldenTable(FormatRuntimeEnvironmentTableWith),
+	"fd_usage":          goldenTable(FormatFDUsageTableWith),
+	"filesystem":        goldenTable(FormatFilesystemAuditTableWith),
+	"fingerprint":       goldenTable(FormatFingerprintTableWith),
+	"firmware":          goldenTable(FormatFirmwareTableWith),
+	"gpu":               goldenTable(FormatGPUInfoTableWith),
+	"keychain":          goldenTable(FormatKeychainTableWith),
+	"kubelet":           goldenTable(FormatKubeletSecurityTableWith),
+	"legacy_protocols":  goldenTable(FormatLegacyProtocolsTableWith),
+	"management_engine": goldenTable(FormatManagementEngineTableWith),
+	"me":                goldenTable(FormatMeSummaryTableWith),
+	"memory":            goldenTable(FormatMemoryTableWith),
+	"memory_top":        goldenTable(FormatMemoryTopTableWith),
+	"metrics_snapshot":  goldenTable(FormatMetricsSnapshotTableWith),
+	"passkeys":          goldenTable(FormatPasskeyTableWith),
+	"processes":         goldenTable(FormatProcessListTableWith),
+	"profiles":          goldenTable(FormatConfigProfilesTableWith),
+	"score_explanation": goldenTable(FormatScoreExplanationTableWith),
+	"selftest":          goldenTable(FormatSelfTestTableWith),
+	"sensors":           goldenTable(FormatSensorsTableWith),
+	"summary":           goldenTable(FormatSecuritySummaryTableWith),
+	"uac":               goldenTable(FormatUACTableWith),
+	"uptime":            goldenTable(FormatUptimeTableWith),
+	"usb":               goldenTable(FormatUSBDevicesTableWith),
+	"version":           goldenTable(FormatBuildInfoTableWith),
+	"virtualization":    goldenTable(FormatVirtualizationStatusTableWith),
+	"waivers":           goldenTable(FormatWaiversTableWith),
+}
+
+// goldenPlatformTables are the table formatters that differ by platform,
+// by result schema name and fixture platform. Every platform's table is
+// compiled everywhere, so all of them are checked on any OS; their goldens
+// live under testdata/golden/<platform>.
+var goldenPlatformTables = map[string]map[string]func(Styler, []byte) (string, error){
+	"biometrics": {
+		"darwin":  goldenTable(formatDarwinBiometricsTable),
+		"linux":   goldenTable(formatLinuxBiometricsTable),
+		"windows": goldenTable(formatWindowsBiometricsTable),
+	},
+	"encryption": {
+		"darwin":  goldenTable(formatDarwinEncryptionTable),
+		"linux":   goldenTable(formatLinuxEncryptionTable),
+		"windows": goldenTable(formatWindowsEncryptionTable),
+	},
+	"secure_boot": {
+		"darwin":  goldenTable(formatDarwinSecureBootTable),
+		"linux":   goldenTable(formatLinuxSecureBootTable),
+		"windows": goldenTable(formatWindowsSecureBootTable),
+	},
+	"tpm": {
+		"darwin":  goldenTable(formatDarwinTPMTable),
+		"linux":   goldenTable(formatLinuxTPMTable),
+		"windows": goldenTable(formatWindowsTPMTable),
+	},
+}
+
+// goldenVariant is one way of rendering every table
+type goldenVariant struct {
+	name     string
+	renderer render.Renderer
+	locale   string
+	// resize rewrites the free-text strings of the result before it is
+	// rendered, or is nil
+	resize func(string) string
+}
+
+// goldenVariants renders with colors in every built-in theme, without
+// colors, in a locale with double-width characters, and with the result's
+// text cut to one character and stretched past any column, which is where
+// column widths go wrong
+func goldenVariants() []goldenVariant {
+	var variants []goldenVariant
+	for _, name := range render.ThemeNames() {
+		theme, _ := render.LookupTheme(name)
+		variants = append(variants, goldenVariant{"theme " + name, render.ANSI{Theme: theme}, DefaultLocale, nil})
+	}
+	return append(variants,
+		goldenVariant{"plain", render.Plain{}, DefaultLocale, nil},
+		goldenVariant{"plain ja", render.Plain{}, "ja", nil},
+		goldenVariant{"plain narrow", render.Plain{}, DefaultLocale, func(s string) string {
+			r, _ := utf8.DecodeRuneInString(s)
+			return string(r)
+		}},
+		goldenVariant{"plain wide", render.Plain{}, DefaultLocale, func(s string) string {
+			return s + strings.Repeat(" wide", 12)
+		}},
+	)
+}
+
+// goldenEnum matches the identifiers, versions, and timestamps a result
+// holds in strings, which tables switch on or parse rather than print
+var goldenEnum = regexp.MustCompile(`^[a-z0-9_.:-]*$|^\d{4}-\d\d-\d\dT`)
+
+// resizeStrings applies resize to every free-text string in a JSON result
+func resizeStrings(data []byte, resize func(string) string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	var walk func(any) any
+	walk = func(v any) any {
+		switch v := v.(type) {
+		case map[string]any:
+			for k, e := range v {
+				v[k] = walk(e)
+			}
+		case []any:
+			for i, e := range v {
+				v[i] = walk(e)
+			}
+		case string:
+			if !goldenEnum.MatchString(v) {
+				return resize(v)
+			}
+		}
+		return v
+	}
+	return json.Marshal(walk(v))
+}
+
+// goldenInputs returns the fixtures of a result by fixture platform, or an
+// empty result when no platform has one
+func goldenInputs(t *testing.T, name string, platforms []string) map[string][]byte {
+	t.Helper()
+	inputs := map[string][]byte{}
+	for _, platform := range platforms {
+		data, err := os.ReadFile(filepath.Join("..", "fixtures", platform, name+".json"))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs[platform] = data
+	}
+	if len(inputs) == 0 {
+		inputs["empty"] = []byte("{}")
+	}
+	return inputs
+}
+
+// renderGolden renders a table from each input in every variant into one
+// document, with ANSI escapes spelled out so the goldens diff readably
+func renderGolden(t *testing.T, format func(Styler, []byte) (string, error), inputs map[string][]byte) string {
+	t.Helper()
+	prevLocale := Locale()
+	defer func() {
+		if err := SetLocale(prevLocale); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	var sb strings.Builder
+	for _, input := range slices.Sorted(maps.Keys(inputs)) {
+		for _, v := range goldenVariants() {
+			if err := SetLocale(v.locale); err != nil {
+				t.Fatal(err)
+			}
+			data := inputs[input]
+			if v.resize != nil {
+				var err error
+				if data, err = resizeStrings(data, v.resize); err != nil {
+					t.Fatalf("%s: %v", input, err)
+				}
+			}
+			out, err := format(Styler{Renderer: v.renderer}, data)
+			if err != nil {
+				t.Fatalf("%s: %v", input, err)
+			}
+			checkRendered(t, fmt.Sprintf("%s, %s", input, v.name), out)
+			fmt.Fprintf(&sb, "=== %s, %s\n%s\n", input, v.name, strings.ReplaceAll(out, "\033", `\e`))
+		}
+	}
+	return sb.String()
+}
+
+// checkRendered fails on output that cannot display correctly whatever the
+// golden says: invalid UTF-8, replacement characters, or the mojibake of
+// UTF-8 read as Latin-1 or Windows-1252, as when box drawing "─" turns
+// into three characters starting with "â"
+func checkRendered(t *testing.T, what, out string) {
+	t.Helper()
+	if !utf8.ValidString(out) {
+		t.Errorf("%s: output is not valid UTF-8", what)
+	}
+	if strings.ContainsRune(out, utf8.RuneError) {
+		t.Errorf("%s: output contains U+FFFD", what)
+	}
+	if m := findMojibake(out); m != "" {
+		t.Errorf("%s: output contains mojibake %q", what, m)
+	}
+	// Every line of a boxed table is as wide as its top border
+	width := 0
+	for i, line := range strings.Split(StripANSI(out), "\n") {
+		line = strings.TrimSpace(line)
+		first, _ := utf8.DecodeRuneInString(line)
+		switch {
+		case first == '┌':
+			width = VisibleLen(line)
+		case width == 0 || !strings.ContainsRune("│├└", first):
+			width = 0
+		case VisibleLen(line) != width:
+			t.Errorf("%s: line %d is %d columns wide, its table %d: %q", what, i+1, VisibleLen(line), width, line)
+		}
+	}
+}
+
+// findMojibake returns the first sequence in s that looks like UTF-8 decoded
+// as Latin-1 or Windows-1252: the lead byte of a multibyte character read
+// as "â" or "Ã", followed by a character from the upper half of those code
+// pages
+func findMojibake(s string) string {
+	runes := []rune(s)
+	for i := 0; i+1 < len(runes); i++ {
+		if runes[i] != '\u00e2' && runes[i] != '\u00c3' {
+			continue
+		}
+		if next := runes[i+1]; next >= 0x80 && next <= 0xFF || strings.ContainsRune(cp1252Upper, next) {
+			return string(runes[i : i+2])
+		}
+	}
+	return ""
+}
+
+// cp1252Upper are the characters Windows-1252 has where Latin-1 has C1
+// controls
+const cp1252Upper = "\u20ac\u201a\u0192\u201e\u2026\u2020\u2021\u02c6\u2030\u0160\u2039\u0152\u017d" +
+	"\u2018\u2019\u201c\u201d\u2022\u2013\u2014\u02dc\u2122\u0161\u203a\u0153\u017e\u0178"
+
+// checkGolden compares got with a golden file, or rewrites it with -update
+func checkGolden(t *testing.T, path, got string) {
+	t.Helper()
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path) // #nosec G304 -- test data
+	if os.IsNotExist(err) {
+		t.Fatalf("no golden %s; record it with go test ./inspector -run TestGoldenTables -update", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		gotLines, wantLines := strings.Split(got, "\n"), strings.Split(string(want), "\n")
+		for i := range max(len(gotLines), len(wantLines)) {
+			var g, w string
+			if i < len(gotLines) {
+				g = gotLines[i]
+			}
+			if i < len(wantLines) {
+				w = wantLines[i]
+			}
+			if g != w {
+				t.Fatalf("%s differs at line %d:\n got: %q\nwant: %q\n(rerun with -update if the change is intended)", path, i+1, g, w)
+			}
+		}
+	}
+}
+
+func TestGoldenTables(t *testing.T) {
+	platforms := []string{"darwin", "linux", "windows"}
+	for _, name := range slices.Sorted(maps.Keys(goldenTables)) {
+		t.Run(name, func(t *testing.T) {
+			got := renderGolden(t, goldenTables[name], goldenInputs(t, name, platforms))
+			checkGolden(t, filepath.Join("testdata", "golden", name+".golden"), got)
+		})
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(goldenPlatformTables)) {
+		for _, platform := range platforms {
+			t.Run(platform+"/"+name, func(t *testing.T) {
+				format, ok := goldenPlatformTables[name][platform]
+				if !ok {
+					t.Fatalf("no %s table for %s", name, platform)
+				}
+				got := renderGolden(t, format, goldenInputs(t, name, []string{platform}))
+				checkGolden(t, filepath.Join("testdata", "golden", platform, name+".golden"), got)
+			})
+		}
+	}
+}
+
+func TestGoldenTables_Complete(t *testing.T) {
+	// Every result with a table must be in the harness; the others are
+	// listed here
+	noTable := map[string]bool{"envelope": true, "findings": true}
+	for name := range resultTypes {
+		_, shared := goldenTables[name]
+		_, platform := goldenPlatformTables[name]
+		if !shared && !platform && !noTable[name] {
+			t.Errorf("%s has no golden table", name)
+		}
+	}
+}
+
+func TestSourcesHaveNoMojibake(t *testing.T) {
+	// Probes and tables built only for other platforms are not rendered
+	// here, so their sources are checked directly
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file) // #nosec G304 -- package sources
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m := findMojibake(string(data)); m != "" {
+			t.Errorf("%s contains mojibake %q", file, m)
+		}
+	}
+}
+
+func TestFindMojibake(t *testing.T) {
+	if m := findMojibake("┌─┬─┐ ✓ Größe"); m != "" {
+		t.Errorf("findMojibake on clean text = %q", m)
+	}
+	// Spelled out so that this file passes TestSourcesHaveNoMojibake
+	if m := findMojibake("\u00e2\u201d\u20ac\u00e2\u201d\u20ac"); m != "\u00e2\u201d" {
+		t.Errorf("findMojibake(box drawing as Windows-1252) = %q", m)
+	}
+	if m := findMojibake("Gr\u00c3\u00b6\u00c3\u0178e"); m != "\u00c3\u00b6" {
+		t.Errorf("findMojibake(umlaut as Latin-1) = %q", m)
+	}
+}
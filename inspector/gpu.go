@@ -0,0 +1,440 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/agentplexus/posture/render"
+)
+
+// GPU vendors
+const (
+	GPUVendorNVIDIA = "nvidia"
+	GPUVendorAMD    = "amd"
+	GPUVendorIntel  = "intel"
+	GPUVendorApple  = "apple"
+	GPUVendorOther  = "other"
+)
+
+// GPUInfo describes one graphics adapter
+type GPUInfo struct {
+	Vendor string `json:"vendor"`
+	Model  string `json:"model"`
+	// BusID is the PCI address (Linux, nvidia-smi) when known
+	BusID         string `json:"bus_id,omitempty"`
+	Driver        string `json:"driver,omitempty"`
+	DriverVersion string `json:"driver_version,omitempty"`
+	// VRAMBytes is dedicated video memory; 0 for integrated GPUs that share
+	// system memory
+	VRAMBytes     uint64 `json:"vram_bytes,omitempty"`
+	VRAMHuman     string `json:"vram_human,omitempty"`
+	VRAMUsedBytes uint64 `json:"vram_used_bytes,omitempty"`
+	// UnifiedMemory reports a GPU that shares system memory (Apple silicon,
+	// most integrated GPUs)
+	UnifiedMemory bool `json:"unified_memory,omitempty"`
+	// UtilizationPercent is nil when the platform does not expose it
+	UtilizationPercent *float64 `json:"utilization_percent,omitempty"`
+	// Source lists where the details came from (sysfs, nvidia-smi, wmi, ...)
+	Source []string `json:"source"`
+}
+
+// GPUResult lists the machine's GPUs
+type GPUResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string      `json:"platform"`
+	GPUs     []GPUInfo   `json:"gpus"`
+	Error    *ProbeError `json:"error,omitempty"`
+}
+
+// pciGPUVendors maps PCI vendor IDs to GPU vendors
+var pciGPUVendors = map[string]string{
+	"0x10de": GPUVendorNVIDIA,
+	"0x1002": GPUVendorAMD,
+	"0x8086": GPUVendorIntel,
+}
+
+// GetGPUInfo returns the GPUs with their model, VRAM, driver version, and
+// utilization where the platform exposes it. NVIDIA GPUs are enriched from
+// nvidia-smi when it is installed.
+func GetGPUInfo(ctx context.Context) (*GPUResult, error) {
+	if result, ok, err := loadFixture[GPUResult](ctx, "gpu"); ok {
+		return result, err
+	}
+	result := &GPUResult{Platform: runtime.GOOS, GPUs: []GPUInfo{}}
+
+	gpus, perr := platformGPUs(ctx)
+	result.GPUs = append(result.GPUs, gpus...)
+
+	if _, err := lookPath(ctx, "nvidia-smi"); err == nil {
+		out, err := runCommand(ctx, "nvidia-smi", "--query-gpu=index,name,memory.total,memory.used,driver_version,utilization.gpu,pci.bus_id", "--format=csv,noheader,nounits")
+		if err != nil {
+			if perr == nil {
+				perr = classifyExecError(ctx, "nvidia-smi", err)
+			}
+		} else {
+			result.GPUs = mergeNvidiaGPUs(result.GPUs, parseNvidiaSMIGPUs(out))
+		}
+	}
+
+	for i := range result.GPUs {
+		if result.GPUs[i].VRAMBytes > 0 {
+			result.GPUs[i].VRAMHuman = FormatBytes(result.GPUs[i].VRAMBytes)
+		}
+	}
+	// A failed probe only matters if it left us with nothing to report
+	if len(result.GPUs) == 0 {
+		result.Error = perr
+	}
+	return result, nil
+}
+
+// gpuVendor normalizes a vendor name or model string
+func gpuVendor(s string) string {
+	l := strings.ToLower(s)
+	switch {
+	case strings.Contains(l, "nvidia"):
+		return GPUVendorNVIDIA
+	case strings.Contains(l, "amd"), strings.Contains(l, "ati "), strings.Contains(l, "radeon"), strings.Contains(l, "advanced micro"):
+		return GPUVendorAMD
+	case strings.Contains(l, "intel"):
+		return GPUVendorIntel
+	case strings.Contains(l, "apple"):
+		return GPUVendorApple
+	}
+	return GPUVendorOther
+}
+
+// parseNvidiaSMIGPUs parses
+// `nvidia-smi --query-gpu=index,name,memory.total,memory.used,driver_version,utilization.gpu,pci.bus_id --format=csv,noheader,nounits`.
+// Memory is reported in MiB; unsupported fields read "[N/A]".
+func parseNvidiaSMIGPUs(data []byte) []GPUInfo {
+	var gpus []GPUInfo
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ",")
+		if len(fields) != 7 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		gpu := GPUInfo{
+			Vendor:        GPUVendorNVIDIA,
+			Model:         fields[1],
+			Driver:        "nvidia",
+			DriverVersion: fields[4],
+			BusID:         normalizePCIBusID(fields[6]),
+			Source:        []string{"nvidia-smi"},
+		}
+		if mib, err := strconv.ParseUint(fields[2], 10, 64); err == nil {
+			gpu.VRAMBytes = mib << 20
+		}
+		if mib, err := strconv.ParseUint(fields[3], 10, 64); err == nil {
+			gpu.VRAMUsedBytes = mib << 20
+		}
+		if util, err := strconv.ParseFloat(fields[5], 64); err == nil {
+			gpu.UtilizationPercent = &util
+		}
+		gpus = append(gpus, gpu)
+	}
+	return gpus
+}
+
+// normalizePCIBusID lowercases a PCI address and trims nvidia-smi's 8-digit
+// domain ("00000000:01:00.0") to the usual 4 digits ("0000:01:00.0")
+func normalizePCIBusID(id string) string {
+	id = strings.ToLower(id)
+	if domain, rest, ok := strings.Cut(id, ":"); ok && len(domain) > 4 {
+		id = domain[len(domain)-4:] + ":" + rest
+	}
+	return id
+}
+
+// mergeNvidiaGPUs folds nvidia-smi details into the platform's NVIDIA
+// entries, matching by PCI address and otherwise by order
+func mergeNvidiaGPUs(gpus, smi []GPUInfo) []GPUInfo {
+	used := make([]bool, len(smi))
+	next := 0
+	for i := range gpus {
+		if gpus[i].Vendor != GPUVendorNVIDIA {
+			continue
+		}
+		match := -1
+		for j, s := range smi {
+			if !used[j] && gpus[i].BusID != "" && gpus[i].BusID == s.BusID {
+				match = j
+				break
+			}
+		}
+		if match < 0 {
+			for next < len(smi) && used[next] {
+				next++
+			}
+			if next == len(smi) {
+				continue
+			}
+			match = next
+		}
+		used[match] = true
+		s := smi[match]
+		g := &gpus[i]
+		g.Model = s.Model
+		g.DriverVersion = s.DriverVersion
+		if s.VRAMBytes > 0 {
+			g.VRAMBytes = s.VRAMBytes
+		}
+		g.VRAMUsedBytes = s.VRAMUsedBytes
+		g.UtilizationPercent = s.UtilizationPercent
+		if g.BusID == "" {
+			g.BusID = s.BusID
+		}
+		g.Source = append(g.Source, "nvidia-smi")
+	}
+	for j, s := range smi {
+		if !used[j] {
+			gpus = append(gpus, s)
+		}
+	}
+	return gpus
+}
+
+// readDRMGPUs reads display adapters from /sys/class/drm (card0, card1, ...;
+// connector entries such as card0-HDMI-A-1 are skipped)
+func readDRMGPUs(root fs.FS) []GPUInfo {
+	cards, _ := fs.Glob(root, "sys/class/drm/card*")
+	var gpus []GPUInfo
+	for _, card := range cards {
+		if strings.Contains(path.Base(card), "-") {
+			continue
+		}
+		dev := path.Join(card, "device")
+		vendorID := readSysString(root, path.Join(dev, "vendor"))
+		if vendorID == "" {
+			continue
+		}
+		vendor := pciGPUVendors[vendorID]
+		if vendor == "" {
+			vendor = GPUVendorOther
+		}
+		gpu := GPUInfo{Vendor: vendor, Source: []string{"sysfs"}}
+
+		uevent := readSysString(root, path.Join(dev, "uevent"))
+		for _, line := range strings.Split(uevent, "\n") {
+			key, value, _ := strings.Cut(line, "=")
+			switch key {
+			case "DRIVER":
+				gpu.Driver = value
+			case "PCI_SLOT_NAME":
+				gpu.BusID = strings.ToLower(value)
+			case "PCI_ID":
+				gpu.Model = fmt.Sprintf("%s device %s", vendorDisplayName(vendor), strings.ToLower(value))
+			}
+		}
+		if gpu.Driver != "" {
+			gpu.DriverVersion = readSysString(root, path.Join("sys/module", gpu.Driver, "version"))
+		}
+		// amdgpu (and some Intel discrete drivers) publish VRAM and load
+		if v, ok := readSysFloat(root, path.Join(dev, "mem_info_vram_total")); ok {
+			gpu.VRAMBytes = uint64(v)
+		}
+		if v, ok := readSysFloat(root, path.Join(dev, "mem_info_vram_used")); ok {
+			gpu.VRAMUsedBytes = uint64(v)
+		}
+		if v, ok := readSysFloat(root, path.Join(dev, "gpu_busy_percent")); ok {
+			gpu.UtilizationPercent = &v
+		}
+		if vendor == GPUVendorIntel && gpu.VRAMBytes == 0 {
+			gpu.UnifiedMemory = true
+		}
+		gpus = append(gpus, gpu)
+	}
+	return gpus
+}
+
+// vendorDisplayName returns a vendor name for display
+func vendorDisplayName(vendor string) string {
+	switch vendor {
+	case GPUVendorNVIDIA:
+		return "NVIDIA"
+	case GPUVendorAMD:
+		return "AMD"
+	case GPUVendorIntel:
+		return "Intel"
+	case GPUVendorApple:
+		return "Apple"
+	}
+	return "Unknown"
+}
+
+// parseLspciModel returns the Device field of `lspci -vmm -s <slot>`
+func parseLspciModel(data []byte) string {
+	var vendor, device string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Vendor":
+			vendor = strings.TrimSpace(value)
+		case "Device":
+			device = strings.TrimSpace(value)
+		}
+	}
+	if device == "" {
+		return ""
+	}
+	// lspci vendors are long ("NVIDIA Corporation"); keep the first word
+	if v, _, _ := strings.Cut(vendor, " "); v != "" && !strings.Contains(device, v) {
+		return v + " " + device
+	}
+	return device
+}
+
+// parseSystemProfilerDisplays parses `system_profiler SPDisplaysDataType -json`
+func parseSystemProfilerDisplays(data []byte) ([]GPUInfo, error) {
+	var doc struct {
+		Displays []struct {
+			Model      string `json:"sppci_model"`
+			Vendor     string `json:"spdisplays_vendor"`
+			VRAM       string `json:"spdisplays_vram"`
+			VRAMShared string `json:"spdisplays_vram_shared"`
+			Bus        string `json:"sppci_bus"`
+			Cores      string `json:"sppci_cores"`
+		} `json:"SPDisplaysDataType"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	var gpus []GPUInfo
+	for _, d := range doc.Displays {
+		gpu := GPUInfo{
+			Vendor: gpuVendor(d.Vendor + " " + d.Model),
+			Model:  d.Model,
+			Source: []string{"system_profiler"},
+		}
+		if d.Cores != "" {
+			gpu.Model = fmt.Sprintf("%s (%s cores)", d.Model, d.Cores)
+		}
+		switch {
+		case d.VRAM != "":
+			gpu.VRAMBytes = parseMacVRAM(d.VRAM)
+		case d.VRAMShared != "" || d.Bus == "spdisplays_builtin":
+			gpu.UnifiedMemory = true
+		}
+		gpus = append(gpus, gpu)
+	}
+	return gpus, nil
+}
+
+// parseMacVRAM parses system_profiler sizes such as "8 GB" or "1536 MB"
+func parseMacVRAM(s string) uint64 {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return 0
+	}
+	n, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0
+	}
+	switch strings.ToUpper(fields[1]) {
+	case "GB":
+		return n << 30
+	case "MB":
+		return n << 20
+	}
+	return 0
+}
+
+// ioAcceleratorUtilization matches the device utilization in
+// `ioreg -r -d 1 -c IOAccelerator` PerformanceStatistics
+var ioAcceleratorUtilization = regexp.MustCompile(`"Device Utilization %"=(\d+)`)
+
+// parseIOAcceleratorUtilization returns the utilization of each GPU in
+// ioreg order
+func parseIOAcceleratorUtilization(data []byte) []float64 {
+	var out []float64
+	for _, m := range ioAcceleratorUtilization.FindAllSubmatch(data, -1) {
+		if v, err := strconv.ParseFloat(string(m[1]), 64); err == nil {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// FormatGPUInfoTable formats GPU inventory as a colored table
+func FormatGPUInfoTable(result *GPUResult) string {
+	return FormatGPUInfoTableWith(CurrentStyler(), result)
+}
+
+// FormatGPUInfoTableWith is like FormatGPUInfoTable but styles the table
+// with st
+func FormatGPUInfoTableWith(st Styler, result *GPUResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconChip + " " + T("GPUs")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	if len(result.GPUs) == 0 {
+		sb.WriteString(st.Muted("  " + T("No GPUs found")))
+		sb.WriteString("\n")
+	}
+	for i, g := range result.GPUs {
+		sb.WriteString(st.BoldText(fmt.Sprintf("GPU %d: ", i)))
+		sb.WriteString(st.Info(g.Model))
+		sb.WriteString("\n")
+		sb.WriteString(st.TableTop(16, 34))
+		sb.WriteString("\n")
+		row := func(name, value string) {
+			if value == "" {
+				return
+			}
+			sb.WriteString(st.TableRowColored(PadRight(name, 16), PadRight(value, 34)))
+			sb.WriteString("\n")
+		}
+		row(T("Vendor"), vendorDisplayName(g.Vendor))
+		row(T("Bus"), g.BusID)
+		driver := g.Driver
+		if g.DriverVersion != "" {
+			driver = strings.TrimSpace(driver + " " + g.DriverVersion)
+		}
+		row(T("Driver"), driver)
+		switch {
+		case g.VRAMBytes > 0 && g.VRAMUsedBytes > 0:
+			pct := float64(g.VRAMUsedBytes) / float64(g.VRAMBytes) * 100
+			row("VRAM", T("%s used of %s", FormatBytes(g.VRAMUsedBytes), g.VRAMHuman)+" "+st.Styled(render.UsageStyle(pct), fmt.Sprintf("(%.0f%%)", pct)))
+		case g.VRAMBytes > 0:
+			row("VRAM", g.VRAMHuman)
+		case g.UnifiedMemory:
+			row("VRAM", st.Muted(T("shared with system memory")))
+		}
+		if g.UtilizationPercent != nil {
+			row(T("Utilization"), st.ProgressBar(*g.UtilizationPercent, 20)+fmt.Sprintf(" %5.1f%%", *g.UtilizationPercent))
+		}
+		row(T("Source"), st.Muted(strings.Join(g.Source, ", ")))
+		sb.WriteString(st.TableBottom(16, 34))
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString(formatProbeError(st, result.Error))
+	return sb.String()
+}
+
+// FormatGPUInfo formats GPU inventory in the specified format
+func FormatGPUInfo(result *GPUResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatGPUInfoTableWith(st, result)
+	}, format)
+}
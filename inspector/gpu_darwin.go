@@ -0,0 +1,32 @@
+//go:build darwin
+
+package inspector
+
+import "context"
+
+// platformGPUs reads GPUs from system_profiler and their utilization from
+// the IOAccelerator performance statistics. macOS does not expose a GPU
+// driver version; it ships with the OS.
+func platformGPUs(ctx context.Context) ([]GPUInfo, *ProbeError) {
+	out, err := runCommand(ctx, "system_profiler", "SPDisplaysDataType", "-json")
+	if err != nil {
+		return nil, classifyExecError(ctx, "system_profiler", err)
+	}
+	gpus, err := parseSystemProfilerDisplays(out)
+	if err != nil {
+		return nil, newProbeError(ctx, ErrProbeFailed, "system_profiler", "unexpected output: "+err.Error())
+	}
+
+	// ioreg lists accelerators in the same order as system_profiler lists
+	// displays on single- and dual-GPU Macs; only apply it when counts agree
+	if out, err := runCommand(ctx, "ioreg", "-r", "-d", "1", "-c", "IOAccelerator"); err == nil {
+		if utils := parseIOAcceleratorUtilization(out); len(utils) == len(gpus) {
+			for i := range gpus {
+				u := utils[i]
+				gpus[i].UtilizationPercent = &u
+				gpus[i].Source = append(gpus[i].Source, "ioreg")
+			}
+		}
+	}
+	return gpus, nil
+}
@@ -0,0 +1,26 @@
+//go:build linux
+
+package inspector
+
+import "context"
+
+// platformGPUs reads GPUs from DRM sysfs and names them with lspci when it
+// is installed
+func platformGPUs(ctx context.Context) ([]GPUInfo, *ProbeError) {
+	gpus := readDRMGPUs(environmentRoot)
+	if _, err := lookPath(ctx, "lspci"); err != nil {
+		return gpus, nil
+	}
+	for i := range gpus {
+		if gpus[i].BusID == "" {
+			continue
+		}
+		if out, err := runCommand(ctx, "lspci", "-vmm", "-s", gpus[i].BusID); err == nil {
+			if model := parseLspciModel(out); model != "" {
+				gpus[i].Model = model
+				gpus[i].Source = append(gpus[i].Source, "lspci")
+			}
+		}
+	}
+	return gpus, nil
+}
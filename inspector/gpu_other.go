@@ -0,0 +1,10 @@
+//go:build !darwin && !windows && !linux
+
+package inspector
+
+import "context"
+
+// platformGPUs has no GPU source here beyond nvidia-smi
+func platformGPUs(ctx context.Context) ([]GPUInfo, *ProbeError) {
+	return nil, nil
+}
@@ -0,0 +1,141 @@
+package inspector
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseNvidiaSMIGPUs(t *testing.T) {
+	out := []byte("0, NVIDIA GeForce RTX 4090, 24564, 1200, 550.54.14, 37, 00000000:01:00.0\n" +
+		"1, NVIDIA A100-SXM4-40GB, 40960, [N/A], 550.54.14, [N/A], 00000000:41:00.0\n")
+	gpus := parseNvidiaSMIGPUs(out)
+	if len(gpus) != 2 {
+		t.Fatalf("got %d GPUs, want 2", len(gpus))
+	}
+	g := gpus[0]
+	if g.Model != "NVIDIA GeForce RTX 4090" || g.DriverVersion != "550.54.14" || g.BusID != "0000:01:00.0" {
+		t.Errorf("gpus[0] = %+v", g)
+	}
+	if g.VRAMBytes != 24564<<20 || g.VRAMUsedBytes != 1200<<20 {
+		t.Errorf("VRAM = %d/%d", g.VRAMUsedBytes, g.VRAMBytes)
+	}
+	if g.UtilizationPercent == nil || *g.UtilizationPercent != 37 {
+		t.Errorf("UtilizationPercent = %v, want 37", g.UtilizationPercent)
+	}
+	if gpus[1].UtilizationPercent != nil || gpus[1].VRAMUsedBytes != 0 {
+		t.Errorf("[N/A] fields should be left unset: %+v", gpus[1])
+	}
+}
+
+func TestReadDRMGPUs(t *testing.T) {
+	root := fstest.MapFS{
+		"sys/class/drm/card0/device/vendor":              {Data: []byte("0x8086\n")},
+		"sys/class/drm/card0/device/uevent":              {Data: []byte("DRIVER=i915\nPCI_ID=8086:A780\nPCI_SLOT_NAME=0000:00:02.0\n")},
+		"sys/class/drm/card0-HDMI-A-1/status":            {Data: []byte("connected\n")},
+		"sys/class/drm/card1/device/vendor":              {Data: []byte("0x1002\n")},
+		"sys/class/drm/card1/device/uevent":              {Data: []byte("DRIVER=amdgpu\nPCI_ID=1002:744C\nPCI_SLOT_NAME=0000:03:00.0\n")},
+		"sys/class/drm/card1/device/mem_info_vram_total": {Data: []byte("25753026560\n")},
+		"sys/class/drm/card1/device/mem_info_vram_used":  {Data: []byte("1073741824\n")},
+		"sys/class/drm/card1/device/gpu_busy_percent":    {Data: []byte("12\n")},
+		"sys/module/amdgpu/version":                      {Data: []byte("6.3.6\n")},
+	}
+	gpus := readDRMGPUs(root)
+	if len(gpus) != 2 {
+		t.Fatalf("got %d GPUs, want 2: %+v", len(gpus), gpus)
+	}
+	intel, amd := gpus[0], gpus[1]
+	if intel.Vendor != GPUVendorIntel || intel.Driver != "i915" || !intel.UnifiedMemory || intel.BusID != "0000:00:02.0" {
+		t.Errorf("intel = %+v", intel)
+	}
+	if amd.Vendor != GPUVendorAMD || amd.DriverVersion != "6.3.6" || amd.VRAMBytes != 25753026560 {
+		t.Errorf("amd = %+v", amd)
+	}
+	if amd.UtilizationPercent == nil || *amd.UtilizationPercent != 12 {
+		t.Errorf("amd utilization = %v, want 12", amd.UtilizationPercent)
+	}
+	if amd.Model != "AMD device 1002:744c" {
+		t.Errorf("amd.Model = %q", amd.Model)
+	}
+}
+
+func TestMergeNvidiaGPUs(t *testing.T) {
+	platform := []GPUInfo{
+		{Vendor: GPUVendorIntel, Model: "Intel iGPU", Source: []string{"sysfs"}},
+		{Vendor: GPUVendorNVIDIA, Model: "NVIDIA device 10de:2684", BusID: "0000:01:00.0", Driver: "nvidia", Source: []string{"sysfs"}},
+	}
+	smi := parseNvidiaSMIGPUs([]byte("0, NVIDIA GeForce RTX 4090, 24564, 1200, 550.54.14, 37, 00000000:01:00.0\n" +
+		"1, NVIDIA RTX A2000, 6138, 0, 550.54.14, 0, 00000000:02:00.0\n"))
+
+	gpus := mergeNvidiaGPUs(platform, smi)
+	if len(gpus) != 3 {
+		t.Fatalf("got %d GPUs, want 3 (the unmatched nvidia-smi GPU is appended)", len(gpus))
+	}
+	if gpus[1].Model != "NVIDIA GeForce RTX 4090" || gpus[1].DriverVersion != "550.54.14" {
+		t.Errorf("merged GPU = %+v", gpus[1])
+	}
+	if len(gpus[1].Source) != 2 {
+		t.Errorf("Source = %v, want sysfs and nvidia-smi", gpus[1].Source)
+	}
+	if gpus[2].Model != "NVIDIA RTX A2000" {
+		t.Errorf("appended GPU = %+v", gpus[2])
+	}
+}
+
+func TestParseSystemProfilerDisplays(t *testing.T) {
+	gpus, err := parseSystemProfilerDisplays(fixture(t, "darwin/system_profiler_displays.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gpus) != 1 || gpus[0].Vendor != GPUVendorApple || !gpus[0].UnifiedMemory || gpus[0].Model != "Apple M2 Pro (19 cores)" {
+		t.Errorf("Apple silicon = %+v", gpus)
+	}
+
+	gpus, err = parseSystemProfilerDisplays(fixture(t, "darwin/system_profiler_displays_intel.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gpus) != 2 {
+		t.Fatalf("got %d GPUs, want 2", len(gpus))
+	}
+	if !gpus[0].UnifiedMemory || gpus[0].Vendor != GPUVendorIntel {
+		t.Errorf("integrated = %+v", gpus[0])
+	}
+	if gpus[1].Vendor != GPUVendorAMD || gpus[1].VRAMBytes != 8<<30 {
+		t.Errorf("discrete = %+v", gpus[1])
+	}
+}
+
+func TestParseIOAcceleratorUtilization(t *testing.T) {
+	out := []byte(`+-o AGXAcceleratorG14X  <class AGXAcceleratorG14X, id 0x100000405>
+    {
+      "PerformanceStatistics" = {"In use system memory"=123,"Device Utilization %"=23,"Renderer Utilization %"=21}
+    }
+`)
+	utils := parseIOAcceleratorUtilization(out)
+	if len(utils) != 1 || utils[0] != 23 {
+		t.Errorf("utilization = %v, want [23]", utils)
+	}
+}
+
+func TestParseLspciModel(t *testing.T) {
+	out := []byte("Slot:\t01:00.0\nClass:\tVGA compatible controller\nVendor:\tNVIDIA Corporation\nDevice:\tAD102 [GeForce RTX 4090]\nRev:\ta1\n")
+	if got := parseLspciModel(out); got != "NVIDIA AD102 [GeForce RTX 4090]" {
+		t.Errorf("parseLspciModel() = %q", got)
+	}
+}
+
+func TestGetGPUInfo(t *testing.T) {
+	defer SetCommandRunner(SetCommandRunner(NewFakeRunner()))
+
+	result, err := GetGPUInfo(context.Background())
+	if err != nil {
+		t.Fatalf("GetGPUInfo() error: %v", err)
+	}
+	if result.GPUs == nil {
+		t.Error("GPUs should be non-nil")
+	}
+	if FormatGPUInfoTable(result) == "" {
+		t.Error("FormatGPUInfoTable() returned empty string")
+	}
+}
@@ -0,0 +1,48 @@
+//go:build windows
+
+package inspector
+
+import (
+	"context"
+	"strings"
+)
+
+// Win32_VideoController represents the WMI class
+type Win32_VideoController struct {
+	Name                 string
+	AdapterCompatibility string
+	// AdapterRAM is a uint32 and saturates at 4 GB
+	AdapterRAM    uint32
+	DriverVersion string
+	PNPDeviceID   string
+}
+
+// platformGPUs reads display adapters from WMI. Utilization is only
+// available for NVIDIA GPUs, through nvidia-smi.
+func platformGPUs(ctx context.Context) ([]GPUInfo, *ProbeError) {
+	var controllers []Win32_VideoController
+	query := "SELECT Name, AdapterCompatibility, AdapterRAM, DriverVersion, PNPDeviceID FROM Win32_VideoController"
+	if err := wmiQuery(query, &controllers, ""); err != nil {
+		return nil, classifyWMIError(ctx, `root\cimv2`, err)
+	}
+	var gpus []GPUInfo
+	for _, c := range controllers {
+		// Skip remote display and indirect display drivers
+		if !strings.HasPrefix(strings.ToUpper(c.PNPDeviceID), `PCI\`) {
+			continue
+		}
+		gpu := GPUInfo{
+			Vendor:        gpuVendor(c.AdapterCompatibility + " " + c.Name),
+			Model:         c.Name,
+			DriverVersion: c.DriverVersion,
+			Source:        []string{"wmi"},
+		}
+		// A saturated value means "4 GB or more"; nvidia-smi replaces it
+		// for NVIDIA GPUs
+		if c.AdapterRAM > 0 && c.AdapterRAM != ^uint32(0) {
+			gpu.VRAMBytes = uint64(c.AdapterRAM)
+		}
+		gpus = append(gpus, gpu)
+	}
+	return gpus, nil
+}
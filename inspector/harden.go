@@ -0,0 +1,126 @@
+package inspector
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// HardeningLogEnv names the file the harden wizard records its changes in
+const HardeningLogEnv = "OMNITRUST_HARDEN_LOG"
+
+// Hardening actions
+const (
+	HardeningApplied = "applied"
+	HardeningFailed  = "failed"
+	HardeningSkipped = "skipped"
+)
+
+// checkExplanations say why each check matters, for the harden wizard
+var checkExplanations = map[string]string{
+	CheckTPM:              "A TPM or Secure Enclave keeps keys in hardware, so they cannot be copied off the disk.",
+	CheckSecureBoot:       "Secure Boot only starts signed boot loaders, which stops bootkits that load before the operating system.",
+	CheckBootOrder:        "Booting from removable media or the network first lets anyone with physical access start another system and read the disk.",
+	CheckEncryption:       "Disk encryption keeps data unreadable if the machine is lost or stolen.",
+	CheckBiometrics:       "Biometric unlock makes strong passwords practical, since they are typed less often.",
+	CheckDefender:         "Real-time malware protection blocks known malicious files before they run.",
+	CheckUAC:              "User Account Control makes programs ask before they gain administrator rights.",
+	CheckLegacyProtocols:  "Legacy network protocols such as SMBv1 and LLMNR are easy to exploit and leak credentials.",
+	CheckBrowser:          "Browser safe browsing and automatic updates protect against phishing and exploited browser bugs.",
+	CheckDocker:           "An exposed or privileged container runtime gives root on the host to anyone who can reach it.",
+	CheckKubelet:          "An unauthenticated kubelet lets anyone on the network run commands in the node's containers.",
+	CheckUptime:           "Security updates only take effect after the pending reboot.",
+	CheckFirmware:         "Firmware updates fix vulnerabilities below the operating system, where malware survives reinstalls.",
+	CheckManagementEngine: "A provisioned management engine can control the machine remotely, independent of the operating system.",
+	CheckUSBStorage:       "Unrestricted USB storage lets data be copied off the machine and malware be brought in.",
+	CheckPasskeys:         "Passkeys replace phishable passwords with keys held by the device.",
+	CheckFilesystem:       "Unexpected setuid binaries run as their owner and are a common way to gain root.",
+	CheckCloud:            "The cloud instance's own settings decide who can reach and control this machine.",
+	CheckWSLHost:          "A WSL guest is only as safe as the Windows host it runs on.",
+}
+
+// CheckExplanation says in a sentence why a check matters, in the current
+// language, or returns "" for a check without an explanation
+func CheckExplanation(check string) string {
+	text, ok := checkExplanations[check]
+	if !ok {
+		return ""
+	}
+	return T(text)
+}
+
+// HardeningRecord is a remediation the harden wizard applied, or that was
+// offered and skipped or failed
+type HardeningRecord struct {
+	Time      time.Time `json:"time"`
+	Hostname  string    `json:"hostname"`
+	FindingID string    `json:"finding_id"`
+	Severity  string    `json:"severity"`
+	Command   string    `json:"command"`
+	// Action is applied, failed, or skipped
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NewHardeningRecord records the action taken on a finding
+func NewHardeningRecord(f Finding, action string, err error) HardeningRecord {
+	hostname, _ := os.Hostname()
+	r := HardeningRecord{
+		Time:      time.Now().UTC(),
+		Hostname:  hostname,
+		FindingID: f.ID,
+		Severity:  f.Severity,
+		Command:   f.RemediationCommand,
+		Action:    action,
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return r
+}
+
+// HardeningLogPath returns the harden wizard's log: OMNITRUST_HARDEN_LOG,
+// or else harden.log next to the per-user config file
+func HardeningLogPath() string {
+	if path := os.Getenv(HardeningLogEnv); path != "" {
+		return path
+	}
+	if runtime.GOOS == "windows" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return ""
+		}
+		return filepath.Join(dir, "omnitrust", "harden.log")
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "omnitrust", "harden.log")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "omnitrust", "harden.log")
+}
+
+// AppendHardeningRecord appends r to the log at path as a JSON line,
+// creating the file and its directory if needed
+func AppendHardeningRecord(path string, r HardeningRecord) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304 -- the operator's log file
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
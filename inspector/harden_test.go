@@ -0,0 +1,68 @@
+package inspector
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCheckExplanation(t *testing.T) {
+	for _, id := range slices.Concat(AllChecks, []string{CheckFilesystem, CheckCloud, CheckWSLHost}) {
+		if CheckExplanation(id) == "" {
+			t.Errorf("%s has no explanation", id)
+		}
+	}
+	if got := CheckExplanation("nonexistent"); got != "" {
+		t.Errorf("CheckExplanation(nonexistent) = %q, want empty", got)
+	}
+
+	for lang, messages := range loadCatalogs() {
+		for _, text := range checkExplanations {
+			if _, ok := messages[text]; !ok {
+				t.Errorf("%s: missing translation for %q", lang, text)
+			}
+		}
+	}
+}
+
+func TestAppendHardeningRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "omnitrust", "harden.log")
+	f := Finding{ID: "encryption_disabled", Severity: SeverityCritical, RemediationCommand: "sudo fdesetup enable"}
+
+	if err := AppendHardeningRecord(path, NewHardeningRecord(f, HardeningApplied, nil)); err != nil {
+		t.Fatalf("AppendHardeningRecord: %v", err)
+	}
+	if err := AppendHardeningRecord(path, NewHardeningRecord(f, HardeningFailed, errors.New("exit status 1"))); err != nil {
+		t.Fatalf("AppendHardeningRecord: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("log has %d lines, want 2:\n%s", len(lines), data)
+	}
+	var r HardeningRecord
+	if err := json.Unmarshal([]byte(lines[1]), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.FindingID != f.ID || r.Action != HardeningFailed || r.Error != "exit status 1" || r.Command != f.RemediationCommand {
+		t.Errorf("record = %+v", r)
+	}
+	if fi, err := os.Stat(path); err == nil && fi.Mode().Perm() != 0o600 {
+		t.Errorf("log mode = %v, want 0600", fi.Mode().Perm())
+	}
+}
+
+func TestHardeningLogPath(t *testing.T) {
+	t.Setenv(HardeningLogEnv, "/tmp/custom.log")
+	if got := HardeningLogPath(); got != "/tmp/custom.log" {
+		t.Errorf("HardeningLogPath = %q, want the environment override", got)
+	}
+}
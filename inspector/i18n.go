@@ -0,0 +1,125 @@
+package inspector
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// LangEnv selects the language of table output and recommendations
+const LangEnv = "OMNITRUST_LANG"
+
+// DefaultLocale is the language messages are written in
+const DefaultLocale = "en"
+
+// localeFiles holds the message catalogs, one JSON object per language
+// mapping English source strings to translations
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var (
+	catalogsOnce sync.Once
+	catalogs     map[string]map[string]string
+	// locale is the active language; tests and library callers get English
+	// unless SetLocale is called
+	locale = DefaultLocale
+)
+
+// loadCatalogs parses the embedded message catalogs
+func loadCatalogs() map[string]map[string]string {
+	catalogsOnce.Do(func() {
+		catalogs = map[string]map[string]string{}
+		entries, _ := localeFiles.ReadDir("locales")
+		for _, e := range entries {
+			data, err := localeFiles.ReadFile(path.Join("locales", e.Name()))
+			if err != nil {
+				continue
+			}
+			var messages map[string]string
+			if json.Unmarshal(data, &messages) == nil {
+				catalogs[strings.TrimSuffix(e.Name(), ".json")] = messages
+			}
+		}
+	})
+	return catalogs
+}
+
+// SupportedLocales returns the languages with message catalogs, including English
+func SupportedLocales() []string {
+	locales := []string{DefaultLocale}
+	for lang := range loadCatalogs() {
+		locales = append(locales, lang)
+	}
+	slices.Sort(locales)
+	return locales
+}
+
+// SetLocale selects the language for table output and recommendations. It
+// accepts language tags such as "de", "de-AT", or "ja_JP.UTF-8".
+func SetLocale(lang string) error {
+	base := baseLanguage(lang)
+	if !slices.Contains(SupportedLocales(), base) {
+		return fmt.Errorf("unsupported language %q (use %s)", lang, strings.Join(SupportedLocales(), ", "))
+	}
+	locale = base
+	return nil
+}
+
+// Locale returns the active language
+func Locale() string {
+	return locale
+}
+
+// LocaleFromEnv returns the language requested by OMNITRUST_LANG, or else
+// the first of LC_ALL, LC_MESSAGES, and LANG with a message catalog
+func LocaleFromEnv() string {
+	if lang := os.Getenv(LangEnv); lang != "" {
+		return lang
+	}
+	supported := SupportedLocales()
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		// The first variable that is set decides, as in POSIX
+		if base := baseLanguage(v); slices.Contains(supported, base) {
+			return base
+		}
+		return DefaultLocale
+	}
+	return DefaultLocale
+}
+
+// baseLanguage reduces a locale name such as "de_DE.UTF-8" to "de"
+func baseLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	if lang == "c" || lang == "posix" {
+		return DefaultLocale
+	}
+	return lang
+}
+
+// T translates an English message into the active language and, given
+// arguments, formats it with fmt.Sprintf. Messages without a translation
+// are returned in English.
+func T(msg string, args ...any) string {
+	if locale != DefaultLocale {
+		if translated, ok := loadCatalogs()[locale][msg]; ok {
+			msg = translated
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
@@ -0,0 +1,175 @@
+package inspector
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// dynamicMessages are translated through T with a variable argument, so the
+// source scan in TestCatalogsCoverSources cannot find them
+var dynamicMessages = []string{
+	"TPM",
+	"disk encryption",
+	"Windows host",
+}
+
+var verbPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogsMatchSourceStrings(t *testing.T) {
+	for lang, messages := range loadCatalogs() {
+		for msg, translated := range messages {
+			if translated == "" {
+				t.Errorf("%s: empty translation for %q", lang, msg)
+			}
+			want := verbPattern.FindAllString(msg, -1)
+			got := verbPattern.FindAllString(translated, -1)
+			if !slices.Equal(got, want) {
+				t.Errorf("%s: %q has verbs %v, want %v", lang, translated, got, want)
+			}
+		}
+	}
+}
+
+func TestCatalogsCoverSources(t *testing.T) {
+	call := regexp.MustCompile(`\bT\(("(?:[^"\\]|\\.)*")`)
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wanted := slices.Clone(dynamicMessages)
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range call.FindAllStringSubmatch(string(src), -1) {
+			msg, err := strconv.Unquote(m[1])
+			if err != nil {
+				t.Fatalf("%s: %v", file, err)
+			}
+			wanted = append(wanted, msg)
+		}
+	}
+	if len(wanted) == len(dynamicMessages) {
+		t.Fatal("found no T calls in the package sources")
+	}
+	for lang, messages := range loadCatalogs() {
+		for _, msg := range wanted {
+			if _, ok := messages[msg]; !ok {
+				t.Errorf("%s: missing translation for %q", lang, msg)
+			}
+		}
+	}
+}
+
+func TestSupportedLocales(t *testing.T) {
+	got := SupportedLocales()
+	for _, lang := range []string{"de", "en", "ja"} {
+		if !slices.Contains(got, lang) {
+			t.Errorf("SupportedLocales() = %v, missing %s", got, lang)
+		}
+	}
+}
+
+func TestSetLocale(t *testing.T) {
+	defer func() { _ = SetLocale(DefaultLocale) }()
+
+	tests := []struct {
+		lang    string
+		want    string
+		wantErr bool
+	}{
+		{"de", "de", false},
+		{"de-AT", "de", false},
+		{"ja_JP.UTF-8", "ja", false},
+		{"EN", "en", false},
+		{"C", "en", false},
+		{"fr", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			_ = SetLocale(DefaultLocale)
+			err := SetLocale(tt.lang)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetLocale(%q) error = %v, wantErr %v", tt.lang, err, tt.wantErr)
+			}
+			if !tt.wantErr && Locale() != tt.want {
+				t.Errorf("Locale() = %q, want %q", Locale(), tt.want)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	defer func() { _ = SetLocale(DefaultLocale) }()
+
+	if got := T("Enabled"); got != "Enabled" {
+		t.Errorf("T(Enabled) in English = %q", got)
+	}
+	if err := SetLocale("de"); err != nil {
+		t.Fatal(err)
+	}
+	if got := T("Enabled"); got != "Aktiviert" {
+		t.Errorf("T(Enabled) in German = %q, want Aktiviert", got)
+	}
+	if got := T("Mandatory checks failed: %s", "tpm"); got != "Verpflichtende Prüfungen fehlgeschlagen: tpm" {
+		t.Errorf("T with arguments = %q", got)
+	}
+	if got := T("no such message"); got != "no such message" {
+		t.Errorf("T fallback = %q, want the English message", got)
+	}
+	if err := SetLocale("ja"); err != nil {
+		t.Fatal(err)
+	}
+	if got := T("Yes"); got != "はい" {
+		t.Errorf("T(Yes) in Japanese = %q, want はい", got)
+	}
+}
+
+func TestLocaleFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"explicit", map[string]string{LangEnv: "ja", "LANG": "de_DE.UTF-8"}, "ja"},
+		{"explicit unsupported", map[string]string{LangEnv: "fr"}, "fr"},
+		{"LANG", map[string]string{"LANG": "de_DE.UTF-8"}, "de"},
+		{"LC_ALL wins", map[string]string{"LC_ALL": "ja_JP.UTF-8", "LANG": "de_DE.UTF-8"}, "ja"},
+		{"unsupported LANG", map[string]string{"LANG": "fr_FR.UTF-8"}, "en"},
+		{"first set decides", map[string]string{"LC_MESSAGES": "fr_FR", "LANG": "de_DE"}, "en"},
+		{"unset", map[string]string{}, "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{LangEnv, "LC_ALL", "LC_MESSAGES", "LANG"} {
+				t.Setenv(key, tt.env[key])
+			}
+			if got := LocaleFromEnv(); got != tt.want {
+				t.Errorf("LocaleFromEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummaryTableLocalized(t *testing.T) {
+	defer func() { _ = SetLocale(DefaultLocale) }()
+	if err := SetLocale("de"); err != nil {
+		t.Fatal(err)
+	}
+	out := FormatSecuritySummaryTable(&SecuritySummary{Platform: "linux", OverallScore: 90, OverallStatus: "excellent"})
+	for _, want := range []string{"Sicherheitsübersicht", "Sicherheitswert:", "Ausgezeichnet"} {
+		if !strings.Contains(StripANSI(out), want) {
+			t.Errorf("German summary table missing %q:\n%s", want, out)
+		}
+	}
+}
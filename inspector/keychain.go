@@ -0,0 +1,509 @@
+package inspector
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CheckKeychain is the check of the OS credential store and password
+// managers. It is informational: reported in the summary but never scored.
+const CheckKeychain = "keychain"
+
+// Credential stores reported in KeychainResult.Store
+const (
+	CredentialStoreLoginKeychain     = "login_keychain"
+	CredentialStoreCredentialManager = "credential_manager"
+	CredentialStoreGNOMEKeyring      = "gnome_keyring"
+	CredentialStoreKWallet           = "kwallet"
+)
+
+// Where a password manager was found
+const (
+	PasswordManagerSourceApp       = "app"
+	PasswordManagerSourceExtension = "extension"
+)
+
+// passwordManagerNames are the password managers recognized by application
+// or extension name
+var passwordManagerNames = []string{
+	"1Password", "Bitwarden", "KeePassXC", "KeePass", "LastPass", "Dashlane",
+	"Keeper", "Proton Pass", "Enpass", "NordPass", "RoboForm",
+}
+
+// passwordManagerExtensions maps the Chrome Web Store and Firefox add-on
+// IDs of password manager extensions to their names
+var passwordManagerExtensions = map[string]string{
+	"aeblfdkhhhdcdjpifhhbdiojplfjncoa": "1Password",
+	"nngceckbapebfimnlniiiahkandclblb": "Bitwarden",
+	"hdokiejnpimakedhajhdlcegeplioahd": "LastPass",
+	"fdjamakpfbbddfjaooikfcpapjohcfmg": "Dashlane",
+	"bfogiafebfohielmmehodmfbbebbbpei": "Keeper",
+	"oboonakemofpalcgghocfoadofidjkkk": "KeePassXC",
+	"ghmbeldphafepmbegfdlkpapadhbakde": "Proton Pass",
+
+	"{d634138d-c276-4fc8-924b-40a0ea21d284}": "1Password",
+	"{446900e4-71c2-419f-a6a7-df9c091e268b}": "Bitwarden",
+	"support@lastpass.com":                   "LastPass",
+	"keepassxc-browser@keepassxc.org":        "KeePassXC",
+}
+
+// keyringPAMModules unlock the login keyring with the login password
+var keyringPAMModules = []string{"pam_gnome_keyring.so", "pam_kwallet.so", "pam_kwallet5.so"}
+
+// keyringPAMServices are the login services whose PAM stacks can unlock
+// the keyring
+var keyringPAMServices = []string{"login", "gdm-password", "sddm", "lightdm", "kde"}
+
+// PasswordManager is an installed password manager application or browser
+// extension
+type PasswordManager struct {
+	Name string `json:"name"`
+	// Source is "app" or "extension"
+	Source string `json:"source"`
+	// Location is the application path, or the browser and profile of an
+	// extension
+	Location string `json:"location,omitempty"`
+}
+
+// KeychainResult reports how the OS credential store is protected and which
+// password managers are installed, for the current user
+type KeychainResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string `json:"platform"`
+	User     string `json:"user,omitempty"`
+	// Store is the OS credential store: login_keychain, credential_manager,
+	// gnome_keyring, or kwallet. It is empty if none was found.
+	Store string `json:"store,omitempty"`
+
+	// LockOnSleep is true if the login keychain locks when the Mac sleeps
+	// (macOS)
+	LockOnSleep *bool `json:"lock_on_sleep,omitempty"`
+	// LockTimeoutSeconds is how long the login keychain stays unlocked
+	// while idle, 0 if it never locks on its own (macOS)
+	LockTimeoutSeconds *int `json:"lock_timeout_seconds,omitempty"`
+
+	// VaultServiceEnabled is false if the Credential Manager service
+	// (VaultSvc) is disabled, which breaks saved credentials (Windows)
+	VaultServiceEnabled *bool `json:"vault_service_enabled,omitempty"`
+	// DPAPIWorking is true if data protected with DPAPI under the user's
+	// master key could be unprotected again (Windows)
+	DPAPIWorking *bool `json:"dpapi_working,omitempty"`
+	// StoredCredentials counts the credentials the user has saved in
+	// Credential Manager (Windows)
+	StoredCredentials *int `json:"stored_credentials,omitempty"`
+
+	// PAMUnlock is true if a login service's PAM stack unlocks the keyring
+	// with the login password (Linux)
+	PAMUnlock *bool `json:"pam_unlock,omitempty"`
+
+	PasswordManagers []PasswordManager `json:"password_managers"`
+	Error            *ProbeError       `json:"error,omitempty"`
+}
+
+// IsKeychainSupported reports whether the credential store can be inspected
+// on this platform
+func IsKeychainSupported() bool {
+	return runtime.GOOS == "linux" || runtime.GOOS == "darwin" || runtime.GOOS == "windows"
+}
+
+// GetKeychain reports the protection of the current user's credential store
+// (login keychain, Credential Manager and DPAPI, GNOME Keyring or KWallet)
+// and the password managers installed as applications or browser
+// extensions
+func GetKeychain() (*KeychainResult, error) {
+	return GetKeychainContext(context.Background())
+}
+
+// GetKeychainContext is like GetKeychain but runs the probes with ctx's
+// deadline, command runner, and logger
+func GetKeychainContext(ctx context.Context) (*KeychainResult, error) {
+	if result, ok, err := loadFixture[KeychainResult](ctx, "keychain"); ok {
+		return result, err
+	}
+	name, home, err := browserUser()
+	if err != nil {
+		return nil, newProbeError(ctx, ErrProbeFailed, "keychain", "cannot find the home directory: "+err.Error())
+	}
+	result := &KeychainResult{Platform: runtime.GOOS, User: name, PasswordManagers: []PasswordManager{}}
+	switch runtime.GOOS {
+	case "darwin":
+		result.Error = readMacKeychain(ctx, home, result)
+		result.PasswordManagers = macPasswordManagers(home)
+	case "windows":
+		result.Store = CredentialStoreCredentialManager
+		result.Error = readWindowsCredentialStore(ctx, result)
+		if enabled, ok := vaultServiceEnabled(ctx); ok {
+			result.VaultServiceEnabled = &enabled
+		}
+		result.PasswordManagers = registryPasswordManagers(ctx)
+	case "linux":
+		result.Store = linuxKeyringStore(os.DirFS(home))
+		unlock := keyringPAMUnlock(os.DirFS("/etc/pam.d"))
+		result.PAMUnlock = &unlock
+		result.PasswordManagers = linuxPasswordManagers(os.DirFS("/"))
+	default:
+		return nil, newProbeError(ctx, ErrUnsupportedPlatform, "keychain", "credential stores are not checked on "+runtime.GOOS)
+	}
+	if browsers, err := GetBrowserSecurityContext(ctx); err == nil {
+		result.PasswordManagers = append(result.PasswordManagers, extensionPasswordManagers(browsers.Browsers)...)
+	}
+	return result, nil
+}
+
+// readMacKeychain reads the lock settings of the user's login keychain
+func readMacKeychain(ctx context.Context, home string, result *KeychainResult) *ProbeError {
+	keychain := filepath.Join(home, "Library", "Keychains", "login.keychain-db")
+	if _, err := os.Stat(keychain); err != nil {
+		return nil
+	}
+	result.Store = CredentialStoreLoginKeychain
+	out, err := runCommand(ctx, "security", "show-keychain-info", keychain)
+	if err != nil {
+		return classifyExecError(ctx, "security", err)
+	}
+	lockOnSleep, timeout, err := parseKeychainInfo(out)
+	if err != nil {
+		return newProbeError(ctx, ErrProbeFailed, "keychain", err.Error())
+	}
+	result.LockOnSleep = &lockOnSleep
+	result.LockTimeoutSeconds = &timeout
+	return nil
+}
+
+// keychainTimeout matches the timeout in `security show-keychain-info`
+var keychainTimeout = regexp.MustCompile(`timeout=(\d+)s`)
+
+// parseKeychainInfo parses `security show-keychain-info`, e.g.
+//
+//	Keychain "/Users/me/Library/Keychains/login.keychain-db" lock-on-sleep timeout=300s
+//
+// or "no-timeout" for a keychain that never locks on its own
+func parseKeychainInfo(out []byte) (lockOnSleep bool, timeout int, err error) {
+	line := strings.TrimSpace(string(out))
+	if !strings.HasPrefix(line, "Keychain ") {
+		return false, 0, fmt.Errorf("unexpected security output: %q", line)
+	}
+	// Only look past the quoted path
+	if i := strings.LastIndex(line, `"`); i >= 0 {
+		line = line[i+1:]
+	}
+	lockOnSleep = strings.Contains(line, "lock-on-sleep")
+	if m := keychainTimeout.FindStringSubmatch(line); m != nil {
+		timeout, _ = strconv.Atoi(m[1])
+	}
+	return lockOnSleep, timeout, nil
+}
+
+// macPasswordManagers finds password manager applications in /Applications
+// and ~/Applications
+func macPasswordManagers(home string) []PasswordManager {
+	managers := []PasswordManager{}
+	for _, dir := range []string{"/Applications", filepath.Join(home, "Applications")} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			app, ok := strings.CutSuffix(entry.Name(), ".app")
+			if !ok {
+				continue
+			}
+			if name := passwordManagerName(app); name != "" {
+				managers = append(managers, PasswordManager{Name: name, Source: PasswordManagerSourceApp, Location: filepath.Join(dir, entry.Name())})
+			}
+		}
+	}
+	return managers
+}
+
+// uninstallKeys are the registry keys listing installed applications
+var uninstallKeys = []string{
+	`HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`,
+	`HKLM\SOFTWARE\WOW6432Node\Microsoft\Windows\CurrentVersion\Uninstall`,
+	`HKCU\SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`,
+}
+
+// vaultServiceKey is the Credential Manager service's registry key
+const vaultServiceKey = `HKLM\SYSTEM\CurrentControlSet\Services\VaultSvc`
+
+// vaultServiceEnabled reports whether the Credential Manager service may
+// start; ok is false if it is not installed
+func vaultServiceEnabled(ctx context.Context) (enabled, ok bool) {
+	start, ok, err := registryInteger(ctx, vaultServiceKey, "Start")
+	if err != nil || !ok {
+		return false, false
+	}
+	// SERVICE_DISABLED
+	return start != 4, true
+}
+
+// registryPasswordManagers finds password managers among the installed
+// applications
+func registryPasswordManagers(ctx context.Context) []PasswordManager {
+	managers := []PasswordManager{}
+	for _, key := range uninstallKeys {
+		subKeys, err := registrySubKeys(ctx, key)
+		if err != nil {
+			continue
+		}
+		for _, sub := range subKeys {
+			display, ok, _ := registryString(ctx, key+`\`+sub, "DisplayName")
+			if !ok {
+				continue
+			}
+			name := passwordManagerName(display)
+			if name == "" || slices.ContainsFunc(managers, func(m PasswordManager) bool { return m.Name == name }) {
+				continue
+			}
+			location, _, _ := registryString(ctx, key+`\`+sub, "InstallLocation")
+			managers = append(managers, PasswordManager{Name: name, Source: PasswordManagerSourceApp, Location: location})
+		}
+	}
+	return managers
+}
+
+// linuxPasswordManagerPaths maps the install locations of Linux password
+// managers, including snaps and Flatpaks, to their names
+var linuxPasswordManagerPaths = map[string]string{
+	"opt/1Password":     "1Password",
+	"usr/bin/1password": "1Password",
+	"opt/Bitwarden":     "Bitwarden",
+	"usr/bin/bitwarden": "Bitwarden",
+	"snap/bitwarden":    "Bitwarden",
+	"usr/bin/keepassxc": "KeePassXC",
+	"snap/keepassxc":    "KeePassXC",
+	"usr/bin/keepass2":  "KeePass",
+	"opt/enpass":        "Enpass",
+	"usr/bin/nordpass":  "NordPass",
+	"snap/nordpass":     "NordPass",
+
+	"var/lib/flatpak/app/com.bitwarden.desktop":   "Bitwarden",
+	"var/lib/flatpak/app/org.keepassxc.KeePassXC": "KeePassXC",
+	"var/lib/flatpak/app/me.proton.Pass":          "Proton Pass",
+}
+
+// linuxPasswordManagers finds password managers at their install locations
+func linuxPasswordManagers(fsys fs.FS) []PasswordManager {
+	managers := []PasswordManager{}
+	paths := make([]string, 0, len(linuxPasswordManagerPaths))
+	for p := range linuxPasswordManagerPaths {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+	for _, p := range paths {
+		name := linuxPasswordManagerPaths[p]
+		if _, err := fs.Stat(fsys, p); err != nil || slices.ContainsFunc(managers, func(m PasswordManager) bool { return m.Name == name }) {
+			continue
+		}
+		managers = append(managers, PasswordManager{Name: name, Source: PasswordManagerSourceApp, Location: "/" + p})
+	}
+	return managers
+}
+
+// linuxKeyringStore returns the keyring the user has, from the files in
+// their home directory
+func linuxKeyringStore(home fs.FS) string {
+	if _, err := fs.Stat(home, ".local/share/keyrings"); err == nil {
+		return CredentialStoreGNOMEKeyring
+	}
+	for _, dir := range []string{".local/share/kwalletd", ".kde/share/apps/kwallet"} {
+		if _, err := fs.Stat(home, dir); err == nil {
+			return CredentialStoreKWallet
+		}
+	}
+	return ""
+}
+
+// keyringPAMUnlock reports whether a login service's PAM auth stack, or a
+// file it includes, unlocks the keyring with the login password
+func keyringPAMUnlock(fsys fs.FS) bool {
+	seen := map[string]bool{}
+	queue := slices.Clone(keyringPAMServices)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		data, err := readFSFile(fsys, name)
+		if err != nil {
+			continue
+		}
+		rules, includes := parsePAMAuth(data)
+		for _, rule := range rules {
+			if slices.Contains(keyringPAMModules, path.Base(rule.module)) {
+				return true
+			}
+		}
+		queue = append(queue, includes...)
+	}
+	return false
+}
+
+// extensionPasswordManagers finds password manager extensions in the
+// user's browser profiles
+func extensionPasswordManagers(browsers []BrowserInfo) []PasswordManager {
+	managers := []PasswordManager{}
+	for _, b := range browsers {
+		for _, ext := range b.Extensions {
+			name := passwordManagerExtensions[ext.ID]
+			if name == "" {
+				name = passwordManagerName(ext.Name)
+			}
+			if name == "" || !ext.Enabled {
+				continue
+			}
+			location := b.Name
+			if b.Profile != "" {
+				location += " (" + b.Profile + ")"
+			}
+			managers = append(managers, PasswordManager{Name: name, Source: PasswordManagerSourceExtension, Location: location})
+		}
+	}
+	return managers
+}
+
+// passwordManagerName returns the password manager an application or
+// extension name belongs to, or ""
+func passwordManagerName(s string) string {
+	lower := strings.ToLower(s)
+	for _, name := range passwordManagerNames {
+		if strings.HasPrefix(lower, strings.ToLower(name)) {
+			return name
+		}
+	}
+	return ""
+}
+
+// keychainStoreName returns the display name of a credential store
+func keychainStoreName(store string) string {
+	switch store {
+	case CredentialStoreLoginKeychain:
+		return "Login Keychain"
+	case CredentialStoreCredentialManager:
+		return "Credential Manager"
+	case CredentialStoreGNOMEKeyring:
+		return "GNOME Keyring"
+	case CredentialStoreKWallet:
+		return "KWallet"
+	}
+	return "None found"
+}
+
+// errDPAPIRoundTrip is reported when DPAPI returns different data than was
+// protected
+var errDPAPIRoundTrip = errors.New("DPAPI round trip returned different data")
+
+// dpapiRoundTrip checks that unprotect(protect(data)) returns data
+func dpapiRoundTrip(protect, unprotect func([]byte) ([]byte, error)) error {
+	data := []byte("omnitrust dpapi check")
+	blob, err := protect(data)
+	if err != nil {
+		return err
+	}
+	plain, err := unprotect(blob)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(plain, data) {
+		return errDPAPIRoundTrip
+	}
+	return nil
+}
+
+// FormatKeychainTable formats the credential store as a colored table
+func FormatKeychainTable(result *KeychainResult) string {
+	return FormatKeychainTableWith(CurrentStyler(), result)
+}
+
+// FormatKeychainTableWith is like FormatKeychainTable but styles the table
+// with st
+func FormatKeychainTableWith(st Styler, result *KeychainResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconKey + " Keychain and Password Managers"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n")
+		if result.Error.Hint != "" {
+			sb.WriteString(st.Muted("  " + result.Error.Hint))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.TableTop(24, 30))
+	sb.WriteString("\n")
+	row := func(label, value string) {
+		sb.WriteString(st.TableRowColored(PadRight(label, 24), PadRight(value, 30)))
+		sb.WriteString("\n")
+	}
+	row("Credential Store", keychainStoreName(result.Store))
+	if result.LockOnSleep != nil {
+		row("Lock on Sleep", st.BoolToStatusColored(*result.LockOnSleep))
+	}
+	if result.LockTimeoutSeconds != nil {
+		timeout := st.Warning("Never")
+		if *result.LockTimeoutSeconds > 0 {
+			timeout = fmt.Sprintf("%d min", *result.LockTimeoutSeconds/60)
+		}
+		row("Lock After Idle", timeout)
+	}
+	if result.VaultServiceEnabled != nil {
+		row("Credential Manager", st.BoolToStatusColored(*result.VaultServiceEnabled))
+	}
+	if result.DPAPIWorking != nil {
+		row("DPAPI", st.BoolToStatusColored(*result.DPAPIWorking))
+	}
+	if result.StoredCredentials != nil {
+		row("Saved Credentials", strconv.Itoa(*result.StoredCredentials))
+	}
+	if result.PAMUnlock != nil {
+		row("Unlocked at Login", st.BoolToStatusColored(*result.PAMUnlock))
+	}
+	sb.WriteString(st.TableBottom(24, 30))
+	sb.WriteString("\n\n")
+
+	if len(result.PasswordManagers) == 0 {
+		sb.WriteString(st.Muted("No password managers found"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+	sb.WriteString(st.TableTop(16, 10, 40))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(st.Header(PadRight("Password Manager", 16)), st.Header(PadRight("Type", 10)), st.Header(PadRight("Location", 40))))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(16, 10, 40))
+	sb.WriteString("\n")
+	for _, m := range result.PasswordManagers {
+		sb.WriteString(st.TableRowColored(PadRight(m.Name, 16), PadRight(m.Source, 10), PadRight(m.Location, 40)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(st.TableBottom(16, 10, 40))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// FormatKeychain formats the credential store in the specified format
+func FormatKeychain(result *KeychainResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatKeychainTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package inspector
+
+import "context"
+
+// readWindowsCredentialStore is only implemented on Windows
+func readWindowsCredentialStore(ctx context.Context, result *KeychainResult) *ProbeError {
+	return nil
+}
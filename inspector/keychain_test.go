@@ -0,0 +1,91 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseKeychainInfo(t *testing.T) {
+	lockOnSleep, timeout, err := parseKeychainInfo([]byte(`Keychain "/Users/me/Library/Keychains/login.keychain-db" lock-on-sleep timeout=300s` + "\n"))
+	if err != nil || !lockOnSleep || timeout != 300 {
+		t.Errorf("parseKeychainInfo = %v, %d, %v, want lock on sleep after 300s", lockOnSleep, timeout, err)
+	}
+	// A path containing lock-on-sleep must not count
+	lockOnSleep, timeout, err = parseKeychainInfo([]byte(`Keychain "/Users/lock-on-sleep/login.keychain-db" no-timeout`))
+	if err != nil || lockOnSleep || timeout != 0 {
+		t.Errorf("parseKeychainInfo = %v, %d, %v, want never locking", lockOnSleep, timeout, err)
+	}
+	if _, _, err := parseKeychainInfo([]byte("security: SecKeychainCopySettings: The specified keychain could not be found.")); err == nil {
+		t.Error("unexpected output should be an error")
+	}
+}
+
+func TestKeyringPAMUnlock(t *testing.T) {
+	fsys := fstest.MapFS{
+		"gdm-password":   {Data: []byte("auth requisite pam_nologin.so\n@include common-auth\nauth optional pam_gnome_keyring.so\n")},
+		"common-auth":    {Data: []byte("auth [success=1 default=ignore] pam_unix.so nullok\n")},
+		"sddm":           {Data: []byte("auth include common-account\n")},
+		"common-account": {Data: []byte("auth optional /usr/lib/x86_64-linux-gnu/security/pam_kwallet5.so\n")},
+	}
+	if !keyringPAMUnlock(fsys) {
+		t.Error("pam_gnome_keyring.so in gdm-password should unlock the keyring")
+	}
+	delete(fsys, "gdm-password")
+	if !keyringPAMUnlock(fsys) {
+		t.Error("pam_kwallet5.so included from sddm should unlock the keyring")
+	}
+	delete(fsys, "common-account")
+	if keyringPAMUnlock(fsys) {
+		t.Error("no keyring module should mean no unlock at login")
+	}
+}
+
+func TestPasswordManagers(t *testing.T) {
+	fsys := fstest.MapFS{
+		"opt/1Password/1password":                             {},
+		"var/lib/flatpak/app/org.keepassxc.KeePassXC/current": {},
+		"usr/bin/keepassxc":                                   {},
+	}
+	managers := linuxPasswordManagers(fsys)
+	if len(managers) != 2 || managers[0].Name != "1Password" || managers[1].Name != "KeePassXC" {
+		t.Errorf("linuxPasswordManagers = %+v", managers)
+	}
+
+	const uninstall = `HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`
+	fake := NewFakeRegistry().
+		SetString(uninstall+`\Bitwarden`, "DisplayName", "Bitwarden 2024.6.0").
+		SetString(uninstall+`\Bitwarden`, "InstallLocation", `C:\Program Files\Bitwarden`).
+		SetString(uninstall+`\{0E0B3B1B}`, "DisplayName", "Microsoft Edge").
+		SetInteger(`HKLM\SYSTEM\CurrentControlSet\Services\VaultSvc`, "Start", 4)
+	defer SetRegistryReader(SetRegistryReader(fake))
+	managers = registryPasswordManagers(context.Background())
+	if len(managers) != 1 || managers[0].Name != "Bitwarden" || managers[0].Location != `C:\Program Files\Bitwarden` {
+		t.Errorf("registryPasswordManagers = %+v", managers)
+	}
+	if enabled, ok := vaultServiceEnabled(context.Background()); !ok || enabled {
+		t.Errorf("vaultServiceEnabled = %v, %v, want disabled", enabled, ok)
+	}
+
+	browsers := []BrowserInfo{{Name: "Google Chrome", Profile: "Default", Extensions: []BrowserExtension{
+		{ID: "nngceckbapebfimnlniiiahkandclblb", Name: "__MSG_extName__", Enabled: true},
+		{ID: "cjpalhdlnbpafiamejdnhcphjbkeiagm", Name: "uBlock Origin", Enabled: true},
+		{ID: "hdokiejnpimakedhajhdlcegeplioahd", Name: "LastPass", Enabled: false},
+	}}}
+	managers = extensionPasswordManagers(browsers)
+	if len(managers) != 1 || managers[0].Name != "Bitwarden" || managers[0].Location != "Google Chrome (Default)" {
+		t.Errorf("extensionPasswordManagers = %+v", managers)
+	}
+}
+
+func TestDPAPIRoundTrip(t *testing.T) {
+	identity := func(b []byte) ([]byte, error) { return b, nil }
+	if err := dpapiRoundTrip(identity, identity); err != nil {
+		t.Errorf("dpapiRoundTrip = %v", err)
+	}
+	broken := func(b []byte) ([]byte, error) { return []byte("other"), nil }
+	if err := dpapiRoundTrip(identity, broken); !errors.Is(err, errDPAPIRoundTrip) {
+		t.Errorf("dpapiRoundTrip = %v, want %v", err, errDPAPIRoundTrip)
+	}
+}
@@ -0,0 +1,66 @@
+//go:build windows
+
+package inspector
+
+import (
+	"context"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Credential Manager (advapi32.dll)
+var (
+	advapi32           = syscall.NewLazyDLL("advapi32.dll")
+	procCredEnumerateW = advapi32.NewProc("CredEnumerateW")
+	procCredFree       = advapi32.NewProc("CredFree")
+)
+
+// readWindowsCredentialStore checks that DPAPI works under the user's
+// master key and counts the credentials saved in Credential Manager
+func readWindowsCredentialStore(ctx context.Context, result *KeychainResult) *ProbeError {
+	working := dpapiRoundTrip(dpapiProtect, dpapiUnprotect) == nil
+	result.DPAPIWorking = &working
+
+	if procCredEnumerateW.Find() != nil {
+		return nil
+	}
+	var count uint32
+	var creds uintptr
+	ret, _, err := procCredEnumerateW.Call(0, 0, uintptr(unsafe.Pointer(&count)), uintptr(unsafe.Pointer(&creds)))
+	switch {
+	case ret != 0:
+		procCredFree.Call(creds) // #nosec G104 -- nothing to do if freeing fails
+	case err == syscall.Errno(windows.ERROR_NOT_FOUND):
+		// No saved credentials
+		count = 0
+	default:
+		return newProbeError(ctx, ErrProbeFailed, "keychain", "CredEnumerate failed: "+err.Error())
+	}
+	stored := int(count)
+	result.StoredCredentials = &stored
+	return nil
+}
+
+// dpapiProtect encrypts data with the user's DPAPI master key
+func dpapiProtect(data []byte) ([]byte, error) {
+	in := windows.DataBlob{Size: uint32(len(data)), Data: &data[0]} // #nosec G115 -- small test payload
+	var out windows.DataBlob
+	if err := windows.CryptProtectData(&in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, err
+	}
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data))) // #nosec G104 -- nothing to do if freeing fails
+	return append([]byte(nil), unsafe.Slice(out.Data, out.Size)...), nil
+}
+
+// dpapiUnprotect decrypts data protected by dpapiProtect
+func dpapiUnprotect(data []byte) ([]byte, error) {
+	in := windows.DataBlob{Size: uint32(len(data)), Data: &data[0]} // #nosec G115 -- small DPAPI blob
+	var out windows.DataBlob
+	if err := windows.CryptUnprotectData(&in, nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, err
+	}
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data))) // #nosec G104 -- nothing to do if freeing fails
+	return append([]byte(nil), unsafe.Slice(out.Data, out.Size)...), nil
+}
@@ -0,0 +1,489 @@
+package inspector
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HostRootEnv names the directory the node's root filesystem is mounted at,
+// for posture running in a pod as a node agent. The kubelet check reads the
+// node's /proc, kubelet configuration, and runtime sockets below it.
+const HostRootEnv = "OMNITRUST_HOST_ROOT"
+
+// Default locations of the kubelet's files, as written by kubeadm
+const (
+	defaultKubeletConfig     = "/var/lib/kubelet/config.yaml"
+	defaultKubeletKubeconfig = "/etc/kubernetes/kubelet.conf"
+)
+
+// defaultRuntimeSockets are the CRI sockets checked besides the kubelet's
+// configured container runtime endpoint
+var defaultRuntimeSockets = []string{
+	"/run/containerd/containerd.sock",
+	"/run/crio/crio.sock",
+}
+
+// KubeletControl is a CIS Kubernetes Benchmark worker node recommendation
+type KubeletControl struct {
+	// ID is the CIS recommendation number, e.g. "4.2.1"
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Passed bool   `json:"passed"`
+	// Value is the setting or file mode that was checked
+	Value string `json:"value"`
+}
+
+// RuntimeSocket is a container runtime socket on the node
+type RuntimeSocket struct {
+	Path string `json:"path"`
+	Mode string `json:"mode"`
+	// WorldAccessible is true if any local user can connect, which gives
+	// them control of every container on the node
+	WorldAccessible bool `json:"world_accessible"`
+}
+
+// KubeletResult reports the security configuration of a Kubernetes node's
+// kubelet and container runtime socket
+type KubeletResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string `json:"platform"`
+	// Detected is true if a kubelet process or configuration file is found
+	Detected       bool   `json:"detected"`
+	Running        bool   `json:"running"`
+	ConfigPath     string `json:"config_path,omitempty"`
+	KubeconfigPath string `json:"kubeconfig_path,omitempty"`
+	// AnonymousAuth is true if requests without credentials are served
+	AnonymousAuth     bool   `json:"anonymous_auth"`
+	AuthorizationMode string `json:"authorization_mode,omitempty"`
+	// ReadOnlyPort serves pod and node information without authentication;
+	// 0 turns it off
+	ReadOnlyPort       int              `json:"read_only_port"`
+	RotateCertificates bool             `json:"rotate_certificates"`
+	RuntimeEndpoint    string           `json:"runtime_endpoint,omitempty"`
+	RuntimeSockets     []RuntimeSocket  `json:"runtime_sockets"`
+	Controls           []KubeletControl `json:"controls"`
+	// Secure is true if every control passes and no runtime socket is
+	// world-accessible
+	Secure bool        `json:"secure"`
+	Error  *ProbeError `json:"error,omitempty"`
+}
+
+// kubeletConfig holds the security-relevant fields of a KubeletConfiguration;
+// nil fields were not set
+type kubeletConfig struct {
+	Authentication struct {
+		Anonymous struct {
+			Enabled *bool `yaml:"enabled"`
+		} `yaml:"anonymous"`
+	} `yaml:"authentication"`
+	Authorization struct {
+		Mode *string `yaml:"mode"`
+	} `yaml:"authorization"`
+	ReadOnlyPort             *int    `yaml:"readOnlyPort"`
+	RotateCertificates       *bool   `yaml:"rotateCertificates"`
+	ContainerRuntimeEndpoint *string `yaml:"containerRuntimeEndpoint"`
+}
+
+// GetKubeletSecurity inspects the kubelet of a Kubernetes node. A machine
+// without a kubelet is reported as not detected.
+func GetKubeletSecurity() (*KubeletResult, error) {
+	return GetKubeletSecurityContext(context.Background())
+}
+
+// GetKubeletSecurityContext is like GetKubeletSecurity but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetKubeletSecurityContext(ctx context.Context) (*KubeletResult, error) {
+	if result, ok, err := loadFixture[KubeletResult](ctx, "kubelet"); ok {
+		return result, err
+	}
+	root := "/"
+	if v := os.Getenv(HostRootEnv); v != "" {
+		root = v
+	}
+	return inspectKubelet(ctx, os.DirFS(root)), nil
+}
+
+// IsKubeletSupported reports whether the kubelet check runs on this platform
+func IsKubeletSupported() bool {
+	return runtime.GOOS == "linux"
+}
+
+// inspectKubelet inspects the kubelet below fsys, the node's root filesystem.
+// Settings come from the kubelet's defaults, then its config file, then its
+// command-line flags, which take precedence as they do in the kubelet.
+func inspectKubelet(ctx context.Context, fsys fs.FS) *KubeletResult {
+	result := &KubeletResult{
+		Platform:       runtime.GOOS,
+		RuntimeSockets: []RuntimeSocket{},
+		Controls:       []KubeletControl{},
+	}
+
+	flags, running := kubeletFlags(fsys)
+	result.Running = running
+	result.ConfigPath = flags["config"]
+	if !running {
+		if _, err := fs.Stat(fsys, fsPath(defaultKubeletConfig)); err != nil {
+			result.Secure = true
+			return result
+		}
+		result.ConfigPath = defaultKubeletConfig
+	}
+	result.Detected = true
+
+	result.KubeconfigPath = flags["kubeconfig"]
+	if result.KubeconfigPath == "" {
+		result.KubeconfigPath = defaultKubeletKubeconfig
+	}
+
+	// Without a config file the kubelet's flag defaults apply, which are
+	// far more permissive than the KubeletConfiguration defaults
+	if result.ConfigPath == "" {
+		result.AnonymousAuth = true
+		result.AuthorizationMode = "AlwaysAllow"
+		result.ReadOnlyPort = 10255
+	} else {
+		result.AuthorizationMode = "Webhook"
+		data, err := readFSFile(fsys, fsPath(result.ConfigPath))
+		if err == nil {
+			err = applyKubeletConfig(result, data)
+		}
+		if err != nil {
+			result.Error = classifyFileError(ctx, result.ConfigPath, err)
+		}
+	}
+	if err := applyKubeletFlags(result, flags); err != nil && result.Error == nil {
+		result.Error = newProbeError(ctx, ErrProbeFailed, "kubelet", err.Error())
+	}
+
+	result.RuntimeSockets = runtimeSockets(fsys, result.RuntimeEndpoint)
+	result.Controls = kubeletControls(fsys, result)
+	result.Secure = kubeletSecure(result)
+	return result
+}
+
+// kubeletFlags returns the command-line flags of the running kubelet, by
+// name without dashes, and whether a kubelet process was found
+func kubeletFlags(fsys fs.FS) (map[string]string, bool) {
+	entries, err := fs.ReadDir(fsys, "proc")
+	if err != nil {
+		return nil, false
+	}
+	for _, e := range entries {
+		if _, err := strconv.Atoi(e.Name()); err != nil {
+			continue
+		}
+		data, err := readFSFile(fsys, "proc/"+e.Name()+"/cmdline")
+		if err != nil {
+			continue
+		}
+		args := strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+		if path.Base(args[0]) == "kubelet" {
+			return parseKubeletFlags(args[1:]), true
+		}
+	}
+	return nil, false
+}
+
+// kubeletBoolFlags are the flags that may be given without a value
+var kubeletBoolFlags = []string{"anonymous-auth", "rotate-certificates"}
+
+// parseKubeletFlags parses --name=value and --name value arguments
+func parseKubeletFlags(args []string) map[string]string {
+	flags := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		name, ok := strings.CutPrefix(args[i], "--")
+		if !ok {
+			continue
+		}
+		if name, value, ok := strings.Cut(name, "="); ok {
+			flags[name] = value
+			continue
+		}
+		switch {
+		case slices.Contains(kubeletBoolFlags, name):
+			flags[name] = "true"
+		case i+1 < len(args) && !strings.HasPrefix(args[i+1], "-"):
+			flags[name] = args[i+1]
+			i++
+		}
+	}
+	return flags
+}
+
+// applyKubeletConfig applies the settings of a KubeletConfiguration file
+func applyKubeletConfig(result *KubeletResult, data []byte) error {
+	var c kubeletConfig
+	if err := yaml.Unmarshal(bytes.TrimSpace(data), &c); err != nil {
+		return fmt.Errorf("kubelet config: %w", err)
+	}
+	if c.Authentication.Anonymous.Enabled != nil {
+		result.AnonymousAuth = *c.Authentication.Anonymous.Enabled
+	}
+	if c.Authorization.Mode != nil {
+		result.AuthorizationMode = *c.Authorization.Mode
+	}
+	if c.ReadOnlyPort != nil {
+		result.ReadOnlyPort = *c.ReadOnlyPort
+	}
+	if c.RotateCertificates != nil {
+		result.RotateCertificates = *c.RotateCertificates
+	}
+	if c.ContainerRuntimeEndpoint != nil {
+		result.RuntimeEndpoint = *c.ContainerRuntimeEndpoint
+	}
+	return nil
+}
+
+// applyKubeletFlags applies command-line flags over the config file
+func applyKubeletFlags(result *KubeletResult, flags map[string]string) error {
+	var errs []error
+	parseBool := func(name string, dst *bool) {
+		if v, ok := flags[name]; ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("--%s: %w", name, err))
+				return
+			}
+			*dst = b
+		}
+	}
+	parseBool("anonymous-auth", &result.AnonymousAuth)
+	parseBool("rotate-certificates", &result.RotateCertificates)
+	if v, ok := flags["read-only-port"]; ok {
+		port, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("--read-only-port: %w", err))
+		} else {
+			result.ReadOnlyPort = port
+		}
+	}
+	if v, ok := flags["authorization-mode"]; ok {
+		result.AuthorizationMode = v
+	}
+	if v, ok := flags["container-runtime-endpoint"]; ok {
+		result.RuntimeEndpoint = v
+	}
+	return errors.Join(errs...)
+}
+
+// runtimeSockets returns the container runtime sockets that exist, the
+// kubelet's configured endpoint first
+func runtimeSockets(fsys fs.FS, endpoint string) []RuntimeSocket {
+	paths := slices.Clone(defaultRuntimeSockets)
+	if p, ok := strings.CutPrefix(endpoint, "unix://"); ok {
+		paths = append([]string{p}, paths...)
+	}
+	sockets := []RuntimeSocket{}
+	var seen []string
+	for _, p := range paths {
+		p = path.Clean(p)
+		if slices.Contains(seen, p) {
+			continue
+		}
+		seen = append(seen, p)
+		info, err := fs.Stat(fsys, fsPath(p))
+		if err != nil {
+			continue
+		}
+		sockets = append(sockets, RuntimeSocket{
+			Path:            p,
+			Mode:            fmt.Sprintf("%04o", info.Mode().Perm()),
+			WorldAccessible: info.Mode().Perm()&0o002 != 0,
+		})
+	}
+	return sockets
+}
+
+// kubeletControls evaluates the CIS Kubernetes Benchmark worker node
+// recommendations that can be checked from the node's files
+func kubeletControls(fsys fs.FS, r *KubeletResult) []KubeletControl {
+	var controls []KubeletControl
+	fileMode := func(id, title, p string) {
+		if p == "" {
+			return
+		}
+		info, err := fs.Stat(fsys, fsPath(p))
+		if err != nil {
+			return
+		}
+		controls = append(controls, KubeletControl{
+			ID:     id,
+			Title:  title,
+			Passed: info.Mode().Perm()&0o077 == 0,
+			Value:  fmt.Sprintf("%s %04o", p, info.Mode().Perm()),
+		})
+	}
+	fileMode("4.1.5", "kubelet.conf permissions are 600 or more restrictive", r.KubeconfigPath)
+	fileMode("4.1.9", "kubelet config file permissions are 600 or more restrictive", r.ConfigPath)
+
+	controls = append(controls,
+		KubeletControl{ID: "4.2.1", Title: "Anonymous authentication is disabled", Passed: !r.AnonymousAuth, Value: strconv.FormatBool(r.AnonymousAuth)},
+		KubeletControl{ID: "4.2.2", Title: "Authorization mode is not AlwaysAllow", Passed: !slices.Contains(strings.Split(r.AuthorizationMode, ","), "AlwaysAllow"), Value: r.AuthorizationMode},
+		KubeletControl{ID: "4.2.4", Title: "Read-only port is disabled", Passed: r.ReadOnlyPort == 0, Value: strconv.Itoa(r.ReadOnlyPort)},
+		KubeletControl{ID: "4.2.10", Title: "Client certificate rotation is enabled", Passed: r.RotateCertificates, Value: strconv.FormatBool(r.RotateCertificates)},
+	)
+	return controls
+}
+
+// kubeletSecure reports whether the kubelet passes the check
+func kubeletSecure(r *KubeletResult) bool {
+	if !r.Detected {
+		return true
+	}
+	if r.Error != nil {
+		return false
+	}
+	for _, c := range r.Controls {
+		if !c.Passed {
+			return false
+		}
+	}
+	return !slices.ContainsFunc(r.RuntimeSockets, func(s RuntimeSocket) bool { return s.WorldAccessible })
+}
+
+// kubeletFailedControls counts the controls that did not pass
+func kubeletFailedControls(r *KubeletResult) int {
+	failed := 0
+	for _, c := range r.Controls {
+		if !c.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// kubeletFindings returns the kubelet settings that fail CIS Kubernetes
+// Benchmark worker node recommendations, and exposed runtime sockets
+func kubeletFindings(r *KubeletResult) []Finding {
+	if !r.Detected {
+		return nil
+	}
+	var findings []Finding
+	add := func(id, title, severity, remediation string) {
+		findings = append(findings, Finding{
+			ID:                 id,
+			Title:              title,
+			Severity:           severity,
+			Check:              CheckKubelet,
+			Remediation:        remediation,
+			RemediationCommand: remediationCommand(id),
+		})
+	}
+	for _, s := range r.RuntimeSockets {
+		if s.WorldAccessible {
+			add("container_runtime_socket_exposed", T("Any local user can control containers through %s", s.Path), SeverityCritical,
+				T("Restrict the socket to root (chmod 660, owned by root:root)"))
+		}
+	}
+	if r.Error != nil {
+		return append(findings, unverifiedFinding("kubelet_unverified", CheckKubelet, "Kubelet", r.Error))
+	}
+	for _, c := range r.Controls {
+		if c.Passed {
+			continue
+		}
+		switch c.ID {
+		case "4.1.5", "4.1.9":
+			add("kubelet_file_permissions", T("Kubelet file is readable by other users: %s (CIS %s)", c.Value, c.ID), SeverityMedium,
+				T("Restrict the file to its owner (chmod 600)"))
+		case "4.2.1":
+			add("kubelet_anonymous_auth", T("Kubelet serves anonymous requests (CIS %s)", c.ID), SeverityHigh,
+				T("Set authentication.anonymous.enabled to false in the kubelet config, or pass --anonymous-auth=false"))
+		case "4.2.2":
+			add("kubelet_authorization_always_allow", T("Kubelet authorizes every request (CIS %s)", c.ID), SeverityHigh,
+				T("Set authorization.mode to Webhook in the kubelet config, or pass --authorization-mode=Webhook"))
+		case "4.2.4":
+			add("kubelet_read_only_port", T("Kubelet read-only port %s is open (CIS %s)", c.Value, c.ID), SeverityMedium,
+				T("Set readOnlyPort to 0 in the kubelet config, or pass --read-only-port=0"))
+		case "4.2.10":
+			add("kubelet_cert_rotation_disabled", T("Kubelet client certificate rotation is disabled (CIS %s)", c.ID), SeverityLow,
+				T("Set rotateCertificates to true in the kubelet config, or pass --rotate-certificates"))
+		}
+	}
+	return findings
+}
+
+// FormatKubeletSecurityTable formats the kubelet security posture as a colored table
+func FormatKubeletSecurityTable(result *KubeletResult) string {
+	return FormatKubeletSecurityTableWith(CurrentStyler(), result)
+}
+
+// FormatKubeletSecurityTableWith is like FormatKubeletSecurityTable but
+// styles the table with st
+func FormatKubeletSecurityTableWith(st Styler, result *KubeletResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " Kubernetes Node (kubelet)"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 67)))
+	sb.WriteString("\n\n")
+
+	if !result.Detected {
+		sb.WriteString(st.Muted("No kubelet found on this machine"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n")
+		if result.Error.Hint != "" {
+			sb.WriteString(st.Muted("  " + result.Error.Hint))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+	if result.ConfigPath != "" {
+		sb.WriteString(st.Muted("Config: " + result.ConfigPath))
+		sb.WriteString("\n")
+	}
+	if !result.Running {
+		sb.WriteString(st.Muted("kubelet is not running; showing its config file"))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(st.TableTop(8, 42, 12))
+	sb.WriteString("\n")
+	sb.WriteString(TableRow(PadRight("CIS", 8), PadRight("Recommendation", 42), PadRight("Status", 12)))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(8, 42, 12))
+	sb.WriteString("\n")
+	for _, c := range result.Controls {
+		status := st.Success(IconCheck + " pass")
+		if !c.Passed {
+			status = st.Danger(IconCross + " fail")
+		}
+		sb.WriteString(st.TableRowColored(PadRight(c.ID, 8), PadRight(c.Title, 42), PadRight(status, 12)))
+		sb.WriteString("\n")
+	}
+	for _, s := range result.RuntimeSockets {
+		status := st.Success(IconCheck + " " + s.Mode)
+		if s.WorldAccessible {
+			status = st.Danger(IconCross + " " + s.Mode)
+		}
+		sb.WriteString(st.TableRowColored(PadRight("-", 8), PadRight(s.Path, 42), PadRight(status, 12)))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(st.TableBottom(8, 42, 12))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// FormatKubeletSecurity formats the kubelet security posture in the specified format
+func FormatKubeletSecurity(result *KubeletResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatKubeletSecurityTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,155 @@
+package inspector
+
+import (
+	"context"
+	"io/fs"
+	"maps"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func kubeletNode(cmdline string) fstest.MapFS {
+	return fstest.MapFS{
+		"proc/1/cmdline":    {Data: []byte("/sbin/init\x00")},
+		"proc/self/cmdline": {Data: []byte("posture\x00")},
+		"proc/812/cmdline":  {Data: []byte(cmdline)},
+		"var/lib/kubelet/config.yaml": {Data: []byte(`apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+authentication:
+  anonymous:
+    enabled: false
+  webhook:
+    enabled: true
+authorization:
+  mode: Webhook
+rotateCertificates: true
+containerRuntimeEndpoint: unix:///run/containerd/containerd.sock
+`), Mode: 0o600},
+		"etc/kubernetes/kubelet.conf":    {Data: []byte("apiVersion: v1\n"), Mode: 0o600},
+		"run/containerd/containerd.sock": {Mode: fs.ModeSocket | 0o660},
+	}
+}
+
+func TestInspectKubelet_Hardened(t *testing.T) {
+	fsys := kubeletNode("/usr/bin/kubelet\x00--config=/var/lib/kubelet/config.yaml\x00--kubeconfig\x00/etc/kubernetes/kubelet.conf\x00")
+	result := inspectKubelet(context.Background(), fsys)
+
+	if !result.Detected || !result.Running || result.Error != nil {
+		t.Fatalf("result = %+v", result)
+	}
+	if result.AnonymousAuth || result.AuthorizationMode != "Webhook" || result.ReadOnlyPort != 0 || !result.RotateCertificates {
+		t.Errorf("settings = %+v", result)
+	}
+	want := []RuntimeSocket{{Path: "/run/containerd/containerd.sock", Mode: "0660"}}
+	if !slices.Equal(result.RuntimeSockets, want) {
+		t.Errorf("RuntimeSockets = %+v, want %+v", result.RuntimeSockets, want)
+	}
+	var ids []string
+	for _, c := range result.Controls {
+		ids = append(ids, c.ID)
+		if !c.Passed {
+			t.Errorf("control %s failed: %s", c.ID, c.Value)
+		}
+	}
+	if want := []string{"4.1.5", "4.1.9", "4.2.1", "4.2.2", "4.2.4", "4.2.10"}; !slices.Equal(ids, want) {
+		t.Errorf("controls = %v, want %v", ids, want)
+	}
+	if !result.Secure || len(kubeletFindings(result)) != 0 {
+		t.Errorf("a hardened kubelet should pass: %+v", kubeletFindings(result))
+	}
+}
+
+func TestInspectKubelet_FlagsOverrideConfig(t *testing.T) {
+	fsys := kubeletNode("kubelet\x00--config\x00/var/lib/kubelet/config.yaml\x00--anonymous-auth\x00--read-only-port=10255\x00--authorization-mode=AlwaysAllow\x00")
+	fsys["var/lib/kubelet/config.yaml"].Mode = 0o644
+	fsys["run/containerd/containerd.sock"].Mode = fs.ModeSocket | 0o666
+
+	result := inspectKubelet(context.Background(), fsys)
+	if !result.AnonymousAuth || result.AuthorizationMode != "AlwaysAllow" || result.ReadOnlyPort != 10255 {
+		t.Errorf("flags not applied: %+v", result)
+	}
+	if result.Secure {
+		t.Error("Secure = true, want false")
+	}
+
+	type idSeverity struct{ id, severity string }
+	var got []idSeverity
+	for _, f := range kubeletFindings(result) {
+		got = append(got, idSeverity{f.ID, f.Severity})
+	}
+	want := []idSeverity{
+		{"container_runtime_socket_exposed", SeverityCritical},
+		{"kubelet_file_permissions", SeverityMedium},
+		{"kubelet_anonymous_auth", SeverityHigh},
+		{"kubelet_authorization_always_allow", SeverityHigh},
+		{"kubelet_read_only_port", SeverityMedium},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+}
+
+func TestInspectKubelet_FlagDefaults(t *testing.T) {
+	// Without --config the kubelet's permissive flag defaults apply
+	fsys := fstest.MapFS{"proc/77/cmdline": {Data: []byte("/usr/local/bin/kubelet\x00--kubeconfig=/etc/kubernetes/kubelet.conf\x00")}}
+	result := inspectKubelet(context.Background(), fsys)
+
+	if result.ConfigPath != "" || !result.AnonymousAuth || result.AuthorizationMode != "AlwaysAllow" || result.ReadOnlyPort != 10255 {
+		t.Errorf("defaults = %+v", result)
+	}
+	if got := kubeletFailedControls(result); got != 4 {
+		t.Errorf("failed controls = %d, want 4", got)
+	}
+}
+
+func TestInspectKubelet_NotRunning(t *testing.T) {
+	fsys := kubeletNode("")
+	delete(fsys, "proc/812/cmdline")
+
+	result := inspectKubelet(context.Background(), fsys)
+	if !result.Detected || result.Running || result.ConfigPath != defaultKubeletConfig {
+		t.Errorf("result = %+v, want the config file of a stopped kubelet", result)
+	}
+	if !result.Secure {
+		t.Errorf("Secure = false, controls = %+v", result.Controls)
+	}
+}
+
+func TestInspectKubelet_NotDetected(t *testing.T) {
+	result := inspectKubelet(context.Background(), fstest.MapFS{"proc/1/cmdline": {Data: []byte("/sbin/init\x00")}})
+	if result.Detected || !result.Secure || len(kubeletFindings(result)) != 0 {
+		t.Errorf("result = %+v, want not detected and secure", result)
+	}
+}
+
+func TestInspectKubelet_BadConfig(t *testing.T) {
+	fsys := kubeletNode("kubelet\x00--config=/var/lib/kubelet/config.yaml\x00")
+	fsys["var/lib/kubelet/config.yaml"] = &fstest.MapFile{Data: []byte("authentication: [\n"), Mode: 0o600}
+
+	result := inspectKubelet(context.Background(), fsys)
+	if result.Error == nil || result.Secure {
+		t.Fatalf("result = %+v, want an error", result)
+	}
+	findings := kubeletFindings(result)
+	if len(findings) != 1 || findings[0].ID != "kubelet_unverified" {
+		t.Errorf("findings = %+v, want kubelet_unverified", findings)
+	}
+}
+
+func TestParseKubeletFlags(t *testing.T) {
+	got := parseKubeletFlags([]string{
+		"--config=/var/lib/kubelet/config.yaml", "--anonymous-auth", "--rotate-certificates",
+		"--read-only-port", "0", "-v", "2", "--node-ip=10.0.0.4",
+	})
+	want := map[string]string{
+		"config":              "/var/lib/kubelet/config.yaml",
+		"anonymous-auth":      "true",
+		"rotate-certificates": "true",
+		"read-only-port":      "0",
+		"node-ip":             "10.0.0.4",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("parseKubeletFlags = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,227 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Registry keys read by the legacy protocols check
+const (
+	smbServerParamsKey = `HKLM\SYSTEM\CurrentControlSet\Services\LanmanServer\Parameters`
+	smb1ServerDriver   = `HKLM\SYSTEM\CurrentControlSet\Services\srv`
+	smb1ClientDriver   = `HKLM\SYSTEM\CurrentControlSet\Services\mrxsmb10`
+	lsaKey             = `HKLM\SYSTEM\CurrentControlSet\Control\Lsa`
+	dnsClientPolicyKey = `HKLM\SOFTWARE\Policies\Microsoft\Windows NT\DNSClient`
+	netbtInterfacesKey = `HKLM\SYSTEM\CurrentControlSet\Services\NetBT\Parameters\Interfaces`
+)
+
+// Windows defaults for unset values
+const (
+	defaultLmCompatibilityLevel = 3 // send NTLMv2 only
+	serviceStartDisabled        = 4
+	netbiosDisabled             = 2
+)
+
+// LegacyProtocolsResult reports legacy network protocols that attackers use
+// to move laterally: SMBv1, NTLMv1, and multicast name resolution
+type LegacyProtocolsResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string `json:"platform"`
+	// SMBv1Server is true if the SMB server accepts SMBv1 connections
+	SMBv1Server bool `json:"smb1_server"`
+	// SMBv1Client is true if the SMBv1 client driver (mrxsmb10) is enabled
+	SMBv1Client bool `json:"smb1_client"`
+	// LmCompatibilityLevel is the LAN Manager authentication level, 0-5
+	LmCompatibilityLevel int `json:"lm_compatibility_level"`
+	// NTLMv1Allowed is true if the machine sends LM or NTLMv1 responses
+	// (LmCompatibilityLevel below 3)
+	NTLMv1Allowed bool `json:"ntlmv1_allowed"`
+	// LLMNR is true unless Group Policy turns off multicast name resolution
+	LLMNR bool `json:"llmnr"`
+	// NetBIOSInterfaces lists the network interfaces with NetBIOS over
+	// TCP/IP enabled, including those that take the setting from DHCP
+	NetBIOSInterfaces []string `json:"netbios_interfaces,omitempty"`
+	NetBIOS           bool     `json:"netbios"`
+	// Hardened is true if none of the legacy protocols are enabled
+	Hardened bool        `json:"hardened"`
+	Error    *ProbeError `json:"error,omitempty"`
+}
+
+// GetLegacyProtocols returns which legacy protocols are enabled
+func GetLegacyProtocols() (*LegacyProtocolsResult, error) {
+	return GetLegacyProtocolsContext(context.Background())
+}
+
+// GetLegacyProtocolsContext is like GetLegacyProtocols but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetLegacyProtocolsContext(ctx context.Context) (*LegacyProtocolsResult, error) {
+	if result, ok, err := loadFixture[LegacyProtocolsResult](ctx, "legacy_protocols"); ok {
+		return result, err
+	}
+	if !IsLegacyProtocolsSupported() {
+		return nil, newProbeError(ctx, ErrUnsupportedPlatform, "legacy_protocols", "legacy protocols are only checked on Windows")
+	}
+	return readLegacyProtocols(ctx), nil
+}
+
+// IsLegacyProtocolsSupported returns true on Windows
+func IsLegacyProtocolsSupported() bool {
+	return runtime.GOOS == "windows"
+}
+
+// readLegacyProtocols reads the settings through the current RegistryReader
+func readLegacyProtocols(ctx context.Context) *LegacyProtocolsResult {
+	result := &LegacyProtocolsResult{Platform: "windows"}
+	readInt := func(path, name string) (uint64, bool) {
+		v, ok, err := registryInteger(ctx, path, name)
+		if err != nil && result.Error == nil {
+			result.Error = classifyRegistryError(ctx, path, err)
+		}
+		return v, ok
+	}
+	// driverEnabled reports whether a driver is installed and not disabled
+	driverEnabled := func(path string) bool {
+		start, ok := readInt(path, "Start")
+		return ok && start != serviceStartDisabled
+	}
+
+	// An explicit SMB1 value wins; otherwise SMBv1 is served if the SMB 1.0
+	// feature is installed
+	if smb1, ok := readInt(smbServerParamsKey, "SMB1"); ok {
+		result.SMBv1Server = smb1 != 0
+	} else {
+		result.SMBv1Server = driverEnabled(smb1ServerDriver)
+	}
+	result.SMBv1Client = driverEnabled(smb1ClientDriver)
+
+	result.LmCompatibilityLevel = defaultLmCompatibilityLevel
+	if level, ok := readInt(lsaKey, "LmCompatibilityLevel"); ok {
+		result.LmCompatibilityLevel = int(level)
+	}
+	result.NTLMv1Allowed = result.LmCompatibilityLevel < 3
+
+	multicast, ok := readInt(dnsClientPolicyKey, "EnableMulticast")
+	result.LLMNR = !ok || multicast != 0
+
+	interfaces, err := registrySubKeys(ctx, netbtInterfacesKey)
+	if err != nil && result.Error == nil {
+		result.Error = classifyRegistryError(ctx, netbtInterfacesKey, err)
+	}
+	for _, iface := range interfaces {
+		if opt, _ := readInt(netbtInterfacesKey+`\`+iface, "NetbiosOptions"); opt != netbiosDisabled {
+			result.NetBIOSInterfaces = append(result.NetBIOSInterfaces, strings.TrimPrefix(iface, "Tcpip_"))
+		}
+	}
+	result.NetBIOS = len(result.NetBIOSInterfaces) > 0
+
+	result.Hardened = !result.SMBv1Server && !result.SMBv1Client && !result.NTLMv1Allowed &&
+		!result.LLMNR && !result.NetBIOS
+	return result
+}
+
+// legacyProtocolFindings returns the enabled legacy protocols
+func legacyProtocolFindings(r *LegacyProtocolsResult) []Finding {
+	if r.Error != nil {
+		return []Finding{unverifiedFinding("legacy_protocols_unverified", CheckLegacyProtocols, "Legacy protocols", r.Error)}
+	}
+	var findings []Finding
+	add := func(id, title, severity, remediation string) {
+		findings = append(findings, Finding{
+			ID:                 id,
+			Title:              title,
+			Severity:           severity,
+			Check:              CheckLegacyProtocols,
+			Remediation:        remediation,
+			RemediationCommand: remediationCommand(id),
+		})
+	}
+	if r.SMBv1Server {
+		add("smb1_server_enabled", T("The SMB server accepts SMBv1"), SeverityHigh,
+			T("Turn off SMBv1 on the SMB server and remove the SMB 1.0/CIFS feature"))
+	}
+	if r.SMBv1Client {
+		add("smb1_client_enabled", T("The SMBv1 client is enabled"), SeverityMedium,
+			T("Remove the SMB 1.0/CIFS feature"))
+	}
+	if r.NTLMv1Allowed {
+		add("ntlmv1_allowed", T("LM and NTLMv1 authentication are allowed"), SeverityHigh,
+			T("Send NTLMv2 responses only and refuse LM and NTLM (LmCompatibilityLevel=5)"))
+	}
+	if r.LLMNR {
+		add("llmnr_enabled", T("LLMNR multicast name resolution is enabled"), SeverityMedium,
+			T("Turn off multicast name resolution through Group Policy (Computer Configuration > Administrative Templates > Network > DNS Client)"))
+	}
+	if r.NetBIOS {
+		add("netbios_enabled", T("NetBIOS over TCP/IP is enabled on %d network interfaces", len(r.NetBIOSInterfaces)), SeverityMedium,
+			T("Disable NetBIOS over TCP/IP in the WINS settings of each network adapter, or through DHCP"))
+	}
+	return findings
+}
+
+// legacyStatus marks a protocol that should be off
+func legacyStatus(st Styler, enabled bool) string {
+	if enabled {
+		return st.Danger(IconCross + " Enabled")
+	}
+	return st.Success(IconCheck + " Disabled")
+}
+
+// FormatLegacyProtocolsTable formats legacy protocol exposure as a colored table
+func FormatLegacyProtocolsTable(result *LegacyProtocolsResult) string {
+	return FormatLegacyProtocolsTableWith(CurrentStyler(), result)
+}
+
+// FormatLegacyProtocolsTableWith is like FormatLegacyProtocolsTable but
+// styles the table with st
+func FormatLegacyProtocolsTableWith(st Styler, result *LegacyProtocolsResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " Legacy Protocols"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n")
+		if result.Error.Hint != "" {
+			sb.WriteString(st.Muted("  " + result.Error.Hint))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.TableTop(28, 24))
+	sb.WriteString("\n")
+	row := func(label, value string) {
+		sb.WriteString(st.TableRowColored(PadRight(label, 28), PadRight(value, 24)))
+		sb.WriteString("\n")
+	}
+	row("SMBv1 Server", legacyStatus(st, result.SMBv1Server))
+	row("SMBv1 Client", legacyStatus(st, result.SMBv1Client))
+	row("NTLMv1 / LM", legacyStatus(st, result.NTLMv1Allowed)+st.Muted(fmt.Sprintf(" level %d", result.LmCompatibilityLevel)))
+	row("LLMNR", legacyStatus(st, result.LLMNR))
+	row("NetBIOS over TCP/IP", legacyStatus(st, result.NetBIOS))
+	sb.WriteString(st.TableBottom(28, 24))
+	sb.WriteString("\n")
+
+	if len(result.NetBIOSInterfaces) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText("Interfaces with NetBIOS:"))
+		sb.WriteString("\n")
+		for _, iface := range result.NetBIOSInterfaces {
+			sb.WriteString(fmt.Sprintf("  %s %s\n", IconArrow, iface))
+		}
+	}
+	return sb.String()
+}
+
+// FormatLegacyProtocols formats legacy protocol exposure in the specified format
+func FormatLegacyProtocols(result *LegacyProtocolsResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatLegacyProtocolsTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,83 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestReadLegacyProtocols(t *testing.T) {
+	iface := netbtInterfacesKey + `\Tcpip_{6B1F}`
+	fake := NewFakeRegistry().
+		SetInteger(smb1ServerDriver, "Start", 3).
+		SetInteger(smb1ClientDriver, "Start", serviceStartDisabled).
+		SetInteger(lsaKey, "LmCompatibilityLevel", 1).
+		SetInteger(iface, "NetbiosOptions", 0).
+		SetInteger(netbtInterfacesKey+`\Tcpip_{A2C0}`, "NetbiosOptions", netbiosDisabled)
+	defer SetRegistryReader(SetRegistryReader(fake))
+
+	r := readLegacyProtocols(context.Background())
+	if !r.SMBv1Server || r.SMBv1Client {
+		t.Errorf("SMBv1 server %v, client %v; want the installed server driver only", r.SMBv1Server, r.SMBv1Client)
+	}
+	if !r.NTLMv1Allowed || r.LmCompatibilityLevel != 1 {
+		t.Errorf("NTLM = %v, level %d", r.NTLMv1Allowed, r.LmCompatibilityLevel)
+	}
+	if !r.LLMNR {
+		t.Error("LLMNR is on unless a policy turns it off")
+	}
+	if !slices.Equal(r.NetBIOSInterfaces, []string{"{6B1F}"}) || !r.NetBIOS {
+		t.Errorf("NetBIOS interfaces = %v", r.NetBIOSInterfaces)
+	}
+	if r.Hardened || r.Error != nil {
+		t.Errorf("result = %+v", r)
+	}
+
+	// An explicit SMB1=0 wins over the installed driver
+	fake.SetInteger(smbServerParamsKey, "SMB1", 0).
+		SetInteger(lsaKey, "LmCompatibilityLevel", 5).
+		SetInteger(dnsClientPolicyKey, "EnableMulticast", 0).
+		SetInteger(iface, "NetbiosOptions", netbiosDisabled)
+	if r := readLegacyProtocols(context.Background()); !r.Hardened {
+		t.Errorf("result = %+v, want hardened", r)
+	}
+
+	fake.SetError(lsaKey, "LmCompatibilityLevel", errors.New("Access is denied."))
+	if r := readLegacyProtocols(context.Background()); !errors.Is(r.Error, ErrPermissionDenied) {
+		t.Errorf("error = %v, want permission denied", r.Error)
+	}
+}
+
+func TestReadLegacyProtocolsDefaults(t *testing.T) {
+	defer SetRegistryReader(SetRegistryReader(NewFakeRegistry()))
+
+	r := readLegacyProtocols(context.Background())
+	if r.SMBv1Server || r.SMBv1Client || r.NTLMv1Allowed || r.NetBIOS || r.LmCompatibilityLevel != defaultLmCompatibilityLevel {
+		t.Errorf("without SMBv1 drivers or NetBT interfaces only LLMNR should be on: %+v", r)
+	}
+	if !r.LLMNR || r.Error != nil {
+		t.Errorf("result = %+v", r)
+	}
+}
+
+func TestLegacyProtocolFindings(t *testing.T) {
+	r := &LegacyProtocolsResult{
+		SMBv1Server: true, SMBv1Client: true, NTLMv1Allowed: true, LLMNR: true,
+		NetBIOS: true, NetBIOSInterfaces: []string{"{6B1F}"},
+	}
+	var got []string
+	for _, f := range legacyProtocolFindings(r) {
+		if f.Check != CheckLegacyProtocols {
+			t.Errorf("finding %s has check %q", f.ID, f.Check)
+		}
+		got = append(got, f.ID)
+	}
+	want := []string{"smb1_server_enabled", "smb1_client_enabled", "ntlmv1_allowed", "llmnr_enabled", "netbios_enabled"}
+	if !slices.Equal(got, want) {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+	if f := legacyProtocolFindings(&LegacyProtocolsResult{Hardened: true}); len(f) != 0 {
+		t.Errorf("hardened findings = %v", f)
+	}
+}
@@ -0,0 +1,121 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Environment variables that configure logging (see LoggerFromEnv)
+const (
+	LogLevelEnv  = "OMNITRUST_LOG_LEVEL"
+	LogFileEnv   = "OMNITRUST_LOG_FILE"
+	LogFormatEnv = "OMNITRUST_LOG_FORMAT"
+)
+
+// Log formats
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// DefaultLogLevel keeps logs to problems that are not visible in results
+const DefaultLogLevel = "warn"
+
+// logOff is above every level slog defines, so nothing is logged
+const logOff = slog.Level(100)
+
+// logger receives the inspector's logs. The library is silent until
+// SetLogger is called.
+var logger atomic.Pointer[slog.Logger]
+
+func init() {
+	logger.Store(slog.New(slog.DiscardHandler))
+}
+
+// SetLogger replaces the logger used by probes and returns the previous
+// one. At debug level every external command is logged with its duration.
+func SetLogger(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		l = slog.New(slog.DiscardHandler)
+	}
+	return logger.Swap(l)
+}
+
+// Logger returns the logger used by probes
+func Logger() *slog.Logger {
+	return logger.Load()
+}
+
+// loggerKey is the context key of a logger set by WithLogger
+type loggerKey struct{}
+
+// WithLogger returns a context whose probes log to l instead of the logger
+// set by SetLogger, so one caller's logs can be kept apart from another's
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// loggerFrom returns the logger set on ctx by WithLogger, or Logger()
+func loggerFrom(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && l != nil {
+		return l
+	}
+	return Logger()
+}
+
+// ParseLogLevel parses debug, info, warn, error, or off
+func ParseLogLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return ParseLogLevel(DefaultLogLevel)
+	case "off", "none":
+		return logOff, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q (use debug, info, warn, error, or off)", s)
+	}
+	return level, nil
+}
+
+// NewLogger returns a logger writing text or JSON records at or above level
+func NewLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	lvl, err := ParseLogLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case "", LogFormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case LogFormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q (use %s or %s)", format, LogFormatText, LogFormatJSON)
+}
+
+// OpenLogger returns a logger writing to file, appending to it, or to
+// stderr if file is empty or "-". The file stays open for the life of the
+// process.
+func OpenLogger(level, format, file string) (*slog.Logger, error) {
+	w := io.Writer(os.Stderr)
+	if file != "" && file != "-" {
+		f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600) // #nosec G304 -- operator-chosen log file
+		if err != nil {
+			return nil, fmt.Errorf("log file: %w", err)
+		}
+		w = f
+	}
+	return NewLogger(w, level, format)
+}
+
+// LoggerFromEnv returns the logger configured by OMNITRUST_LOG_LEVEL,
+// OMNITRUST_LOG_FORMAT, and OMNITRUST_LOG_FILE
+func LoggerFromEnv() (*slog.Logger, error) {
+	return OpenLogger(os.Getenv(LogLevelEnv), os.Getenv(LogFormatEnv), os.Getenv(LogFileEnv))
+}
@@ -0,0 +1,120 @@
+package inspector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"debug", slog.LevelDebug, false},
+		{"INFO", slog.LevelInfo, false},
+		{"warn", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"", slog.LevelWarn, false},
+		{"off", logOff, false},
+		{"loud", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLogLevel(tt.in)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("ParseLogLevel(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := NewLogger(&buf, "info", LogFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Debug("hidden")
+	l.Info("shown", "probe", "fdesetup")
+	var rec map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil || rec["msg"] != "shown" || rec["probe"] != "fdesetup" {
+		t.Errorf("JSON log = %q, %v", buf.String(), err)
+	}
+	if _, err := NewLogger(&buf, "info", "xml"); err == nil {
+		t.Error("an unknown format should be rejected")
+	}
+}
+
+// captureLogs routes the inspector's logs to a buffer for the rest of the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	l, err := NewLogger(&buf, "debug", LogFormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := SetLogger(l)
+	t.Cleanup(func() { SetLogger(prev) })
+	return &buf
+}
+
+func TestRunCommandLogs(t *testing.T) {
+	logs := captureLogs(t)
+	fake := NewFakeRunner().
+		Set("fdesetup status", []byte("FileVault is On.\n")).
+		SetError("bputil -d", &exec.ExitError{Stderr: []byte("must be run as root\n")})
+	defer SetCommandRunner(SetCommandRunner(fake))
+
+	_, _ = runCommand(context.Background(), "fdesetup", "status")
+	_, _ = runCommand(context.Background(), "bputil", "-d")
+	out := logs.String()
+	for _, want := range []string{
+		`msg="command finished" command="fdesetup status" duration=`,
+		`msg="command failed" command="bputil -d" duration=`,
+		`stderr="must be run as root"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("logs missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	global := captureLogs(t)
+	var buf bytes.Buffer
+	l, err := NewLogger(&buf, "debug", LogFormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithLogger(context.Background(), l)
+	newProbeError(ctx, ErrPermissionDenied, "bputil", "insufficient privileges")
+	if !strings.Contains(buf.String(), `msg="probe degraded" probe=bputil`) {
+		t.Errorf("context logger missing the probe error:\n%s", buf.String())
+	}
+	if global.Len() != 0 {
+		t.Errorf("process logger should be bypassed:\n%s", global.String())
+	}
+}
+
+func TestProbeErrorLogs(t *testing.T) {
+	logs := captureLogs(t)
+	newProbeError(context.Background(), ErrPermissionDenied, "bputil", "insufficient privileges")
+	newProbeError(context.Background(), ErrCheckDisabled, "tpm", "disabled")
+	out := logs.String()
+	if !strings.Contains(out, `level=INFO msg="probe degraded" probe=bputil code=permission_denied`) {
+		t.Errorf("degraded probe not logged at info:\n%s", out)
+	}
+	if !strings.Contains(out, `level=DEBUG msg="probe degraded" probe=tpm code=check_disabled`) {
+		t.Errorf("disabled check not logged at debug:\n%s", out)
+	}
+}
+
+func TestLoggerSilentByDefault(t *testing.T) {
+	if Logger().Enabled(t.Context(), slog.LevelError) {
+		t.Error("the library should not log until SetLogger is called")
+	}
+}
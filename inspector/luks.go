@@ -0,0 +1,76 @@
+package inspector
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// LUKSScanEnv selects how the Linux encryption probe finds LUKS block
+// devices: "lsblk" (the default) lists them in one call, "cryptsetup" runs
+// `cryptsetup isLuks` against every /dev/sd* and /dev/nvme* node, which is
+// slow on hosts with many disks but works without lsblk
+const LUKSScanEnv = "OMNITRUST_LUKS_SCAN"
+
+// LUKS scan modes
+const (
+	LUKSScanLsblk      = "lsblk"
+	LUKSScanCryptsetup = "cryptsetup"
+)
+
+// LUKSScan returns the LUKS scan mode set by OMNITRUST_LUKS_SCAN:
+// cryptsetup, or lsblk for anything else
+func LUKSScan() string {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv(LUKSScanEnv)), LUKSScanCryptsetup) {
+		return LUKSScanCryptsetup
+	}
+	return LUKSScanLsblk
+}
+
+// luksDevice is a block device with a LUKS header
+type luksDevice struct {
+	Name string
+	// Mapping is the name of the opened dm-crypt device, empty while the
+	// device is locked
+	Mapping    string
+	MountPoint string
+}
+
+// lsblkDevice is a node of `lsblk -J -o NAME,TYPE,FSTYPE,MOUNTPOINT` output
+type lsblkDevice struct {
+	Name       string        `json:"name"`
+	Type       string        `json:"type"`
+	FSType     string        `json:"fstype"`
+	MountPoint string        `json:"mountpoint"`
+	Children   []lsblkDevice `json:"children"`
+}
+
+// parseLsblkLUKS finds the crypto_LUKS devices in lsblk's JSON device tree,
+// with the dm-crypt mapping opened on each
+func parseLsblkLUKS(data []byte) ([]luksDevice, error) {
+	var tree struct {
+		BlockDevices []lsblkDevice `json:"blockdevices"`
+	}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	var devices []luksDevice
+	var walk func([]lsblkDevice)
+	walk = func(nodes []lsblkDevice) {
+		for _, n := range nodes {
+			if n.FSType == "crypto_LUKS" {
+				dev := luksDevice{Name: n.Name}
+				for _, c := range n.Children {
+					if c.Type == "crypt" {
+						dev.Mapping, dev.MountPoint = c.Name, c.MountPoint
+						break
+					}
+				}
+				devices = append(devices, dev)
+			}
+			walk(n.Children)
+		}
+	}
+	walk(tree.BlockDevices)
+	return devices, nil
+}
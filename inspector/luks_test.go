@@ -0,0 +1,33 @@
+package inspector
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseLsblkLUKS(t *testing.T) {
+	devices, err := parseLsblkLUKS(fixture(t, "linux/lsblk.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []luksDevice{
+		{Name: "sda2", Mapping: "luks-3f1c", MountPoint: "/"},
+		{Name: "sdb"},
+	}
+	if !slices.Equal(devices, want) {
+		t.Errorf("parseLsblkLUKS = %+v, want %+v", devices, want)
+	}
+
+	if _, err := parseLsblkLUKS([]byte("NAME TYPE")); err == nil {
+		t.Error("parseLsblkLUKS accepted non-JSON output")
+	}
+}
+
+func TestLUKSScan(t *testing.T) {
+	for value, want := range map[string]string{"": LUKSScanLsblk, "lsblk": LUKSScanLsblk, " Cryptsetup ": LUKSScanCryptsetup, "bogus": LUKSScanLsblk} {
+		t.Setenv(LUKSScanEnv, value)
+		if got := LUKSScan(); got != want {
+			t.Errorf("LUKSScan with %q = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,489 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// amthiClientUUID is the MEI client of the AMT host interface, present in
+// ME firmware that includes AMT
+const amthiClientUUID = "12f80028-b4b7-4b2d-aca8-46e0ff65814c"
+
+// AMT host interface (AMTHI) messages: a provisioning state request and the
+// command of its response
+const (
+	amthiGetProvisioningState         = 0x04000011
+	amthiGetProvisioningStateResponse = 0x04800011
+)
+
+// amtProvisioningStates names the provisioning states AMTHI reports
+var amtProvisioningStates = map[uint32]string{
+	0: "pre-provisioning",
+	1: "in provisioning",
+	2: "post-provisioning",
+}
+
+// amtPorts are the ports AMT and Intel's Local Manageability Service (LMS)
+// listen on once AMT is provisioned: web UI and WS-Management over HTTP
+// and HTTPS, then Serial-over-LAN and IDE redirection without and with TLS
+var amtPorts = []int{16992, 16993, 16994, 16995}
+
+// amtPlaintextPorts are the AMT ports that do not use TLS
+var amtPlaintextPorts = []int{16992, 16994}
+
+// meOperationModes describes the operation mode field (bits 16-19) of the
+// ME's first firmware status register (HFSTS1)
+var meOperationModes = map[uint32]string{
+	0: "normal",
+	2: "debug",
+	3: "disabled",
+	4: "override jumper",
+	5: "override",
+}
+
+// meUnsafeModes are operation modes in which the ME's security is bypassed
+var meUnsafeModes = []string{"debug", "override jumper", "override"}
+
+// ManagementEngineResult reports the state of the platform's management
+// coprocessor: the Intel Management Engine with Active Management
+// Technology (AMT), or the AMD Platform Security Processor (PSP)
+type ManagementEngineResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string `json:"platform"`
+	// Detected is true if an Intel ME or AMD PSP interface was found
+	Detected bool `json:"detected"`
+	// Vendor is Intel or AMD
+	Vendor string `json:"vendor,omitempty"`
+	// Interface is the MEI character device on Linux or the device name
+	// on Windows
+	Interface       string `json:"interface,omitempty"`
+	FirmwareVersion string `json:"firmware_version,omitempty"`
+	// OperationMode is the ME operation mode from the firmware status
+	// register: normal, debug, disabled, override jumper, or override
+	// (Intel, Linux)
+	OperationMode string `json:"operation_mode,omitempty"`
+	// ManufacturingMode is true if the ME was left in manufacturing mode,
+	// which leaves the flash descriptor and fuses unlocked (Intel, Linux)
+	ManufacturingMode bool `json:"manufacturing_mode"`
+	// DebugUnlocked is true if PSP debug is unlocked, and Fused is false
+	// if the part is not fused for production (AMD, Linux)
+	DebugUnlocked bool  `json:"debug_unlocked"`
+	Fused         *bool `json:"fused,omitempty"`
+	// AMTCapable is true if the ME firmware includes AMT (Linux)
+	AMTCapable bool `json:"amt_capable"`
+	// AMTProvisioningState is pre-provisioning, in provisioning, or
+	// post-provisioning, as the ME reports it, or empty if it could not be
+	// read (Linux, as root)
+	AMTProvisioningState string `json:"amt_provisioning_state,omitempty"`
+	// AMTPorts lists the AMT ports listening on a network address of this
+	// host. The ports LMS opens on loopback are not listed: they are only
+	// reachable from this host.
+	AMTPorts []int `json:"amt_ports"`
+	// AMTExposed is true if AMT is provisioned, so that it answers on the
+	// network adapter out of band, or its ports listen on the network
+	AMTExposed bool `json:"amt_exposed"`
+	// Compliant is true unless AMT is exposed, the ME is in manufacturing
+	// or an override mode, or PSP debug is unlocked
+	Compliant bool        `json:"compliant"`
+	Error     *ProbeError `json:"error,omitempty"`
+}
+
+// IsManagementEngineSupported reports whether the management engine check
+// runs on this platform
+func IsManagementEngineSupported() bool {
+	return runtime.GOOS == "linux" || runtime.GOOS == "windows"
+}
+
+// GetManagementEngine detects the Intel ME or AMD PSP, reads its firmware
+// version and security state from sysfs on Linux or the device from WMI on
+// Windows, and reports whether AMT is provisioned and listening
+func GetManagementEngine() (*ManagementEngineResult, error) {
+	return GetManagementEngineContext(context.Background())
+}
+
+// GetManagementEngineContext is like GetManagementEngine but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetManagementEngineContext(ctx context.Context) (*ManagementEngineResult, error) {
+	if result, ok, err := loadFixture[ManagementEngineResult](ctx, "management_engine"); ok {
+		return result, err
+	}
+	result := &ManagementEngineResult{Platform: runtime.GOOS}
+	switch runtime.GOOS {
+	case "linux":
+		root := os.DirFS("/")
+		readLinuxMEI(root, result)
+		if !result.Detected {
+			readLinuxPSP(root, result)
+		}
+		if result.AMTCapable {
+			result.AMTProvisioningState = readLinuxAMTProvisioningState(ctx, result.Interface)
+		}
+		result.AMTPorts, result.Error = linuxListeningPorts(ctx, root, amtPorts)
+	case "windows":
+		result.Error = readWindowsManagementEngine(ctx, result)
+		var portsErr *ProbeError
+		result.AMTPorts, portsErr = windowsListeningPorts(ctx, amtPorts)
+		if result.Error == nil {
+			result.Error = portsErr
+		}
+	default:
+		return nil, newProbeError(ctx, ErrUnsupportedPlatform, "management_engine", "the management engine is not checked on "+runtime.GOOS)
+	}
+	if result.AMTPorts == nil {
+		result.AMTPorts = []int{}
+	}
+	finishManagementEngine(result)
+	return result, nil
+}
+
+// finishManagementEngine derives AMT exposure and compliance
+func finishManagementEngine(r *ManagementEngineResult) {
+	r.AMTExposed = r.AMTProvisioningState == "post-provisioning" || len(r.AMTPorts) > 0
+	r.Compliant = !r.AMTExposed && !r.ManufacturingMode && !r.DebugUnlocked &&
+		!slices.Contains(meUnsafeModes, r.OperationMode) && (r.Fused == nil || *r.Fused)
+}
+
+// readLinuxMEI reads the first MEI device: its firmware version from
+// fw_ver, the operation and manufacturing modes from fw_status, and
+// whether the AMT host interface client is present on the MEI bus
+func readLinuxMEI(root fs.FS, result *ManagementEngineResult) {
+	entries, err := fs.ReadDir(root, "sys/class/mei")
+	if err != nil || len(entries) == 0 {
+		return
+	}
+	dir := path.Join("sys/class/mei", entries[0].Name())
+	result.Detected = true
+	result.Vendor = "Intel"
+	result.Interface = "/dev/" + entries[0].Name()
+
+	// fw_ver lists one version per firmware partition as
+	// "platform:major.minor.hotfix.build"; the first is the running ME
+	if data, err := readFSFile(root, dir+"/fw_ver"); err == nil {
+		line, _, _ := strings.Cut(strings.TrimSpace(string(data)), "\n")
+		if _, version, ok := strings.Cut(line, ":"); ok {
+			result.FirmwareVersion = version
+		}
+	}
+	if data, err := readFSFile(root, dir+"/fw_status"); err == nil {
+		line, _, _ := strings.Cut(strings.TrimSpace(string(data)), "\n")
+		if hfsts1, err := strconv.ParseUint(strings.TrimSpace(line), 16, 32); err == nil {
+			result.ManufacturingMode, result.OperationMode = parseHFSTS1(uint32(hfsts1))
+		}
+	}
+
+	uuids, _ := fs.Glob(root, "sys/bus/mei/devices/*/uuid")
+	for _, name := range uuids {
+		if data, err := readFSFile(root, name); err == nil && strings.EqualFold(strings.TrimSpace(string(data)), amthiClientUUID) {
+			result.AMTCapable = true
+			break
+		}
+	}
+}
+
+// amthiRequest returns an AMTHI message with no payload: version 1.1, a
+// reserved word, the command, and the payload length
+func amthiRequest(command uint32) []byte {
+	msg := make([]byte, 12)
+	msg[0], msg[1] = 1, 1
+	binary.LittleEndian.PutUint32(msg[4:], command)
+	return msg
+}
+
+// parseAMTProvisioningState reads the AMTHI response to a provisioning
+// state request: the message header, a status, and the state
+func parseAMTProvisioningState(resp []byte) (string, error) {
+	if len(resp) < 20 {
+		return "", fmt.Errorf("short AMTHI response (%d bytes)", len(resp))
+	}
+	if command := binary.LittleEndian.Uint32(resp[4:]); command != amthiGetProvisioningStateResponse {
+		return "", fmt.Errorf("unexpected AMTHI response %#x", command)
+	}
+	if status := binary.LittleEndian.Uint32(resp[12:]); status != 0 {
+		return "", fmt.Errorf("AMTHI status %#x", status)
+	}
+	state := binary.LittleEndian.Uint32(resp[16:])
+	if name, ok := amtProvisioningStates[state]; ok {
+		return name, nil
+	}
+	return fmt.Sprintf("unknown (%d)", state), nil
+}
+
+// parseHFSTS1 returns the manufacturing mode bit (4) and the operation mode
+// (bits 16-19) of the ME's first firmware status register
+func parseHFSTS1(hfsts1 uint32) (manufacturing bool, mode string) {
+	manufacturing = hfsts1&(1<<4) != 0
+	op := (hfsts1 >> 16) & 0xf
+	mode, ok := meOperationModes[op]
+	if !ok {
+		mode = fmt.Sprintf("unknown (%d)", op)
+	}
+	return manufacturing, mode
+}
+
+// readLinuxPSP reads the AMD PSP security attributes the ccp driver
+// exposes in sysfs
+func readLinuxPSP(root fs.FS, result *ManagementEngineResult) {
+	matches, _ := fs.Glob(root, "sys/bus/pci/drivers/ccp/*/fused_part")
+	if len(matches) == 0 {
+		return
+	}
+	dir := path.Dir(matches[0])
+	read := func(name string) string {
+		data, err := readFSFile(root, dir+"/"+name)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+	result.Detected = true
+	result.Vendor = "AMD"
+	result.Interface = path.Base(dir)
+	result.FirmwareVersion = read("bootloader_version")
+	result.DebugUnlocked = read("debug_lock_on") == "0"
+	fused := read("fused_part") != "0"
+	result.Fused = &fused
+}
+
+// linuxListeningPorts returns which of the given TCP ports are in the
+// LISTEN state on a non-loopback address in /proc/net/tcp and /proc/net/tcp6
+func linuxListeningPorts(ctx context.Context, root fs.FS, ports []int) ([]int, *ProbeError) {
+	var listening []int
+	for _, name := range []string{"proc/net/tcp", "proc/net/tcp6"} {
+		data, err := readFSFile(root, name)
+		if err != nil {
+			if name == "proc/net/tcp6" && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return listening, classifyFileError(ctx, "/"+name, err)
+		}
+		for _, port := range parseProcNetTCPListening(data) {
+			if slices.Contains(ports, port) && !slices.Contains(listening, port) {
+				listening = append(listening, port)
+			}
+		}
+	}
+	slices.Sort(listening)
+	return listening, nil
+}
+
+// parseProcNetTCPListening returns the local ports of sockets in the
+// LISTEN state (0A) from /proc/net/tcp, leaving out those bound to loopback
+func parseProcNetTCPListening(data []byte) []int {
+	var ports []int
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[3] != "0A" {
+			continue
+		}
+		hexAddr, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		if ip := parseProcNetAddr(hexAddr); ip != nil && ip.IsLoopback() {
+			continue
+		}
+		if port, err := strconv.ParseUint(hexPort, 16, 16); err == nil {
+			ports = append(ports, int(port))
+		}
+	}
+	return ports
+}
+
+// parseProcNetAddr decodes a /proc/net/tcp address: 32-bit words in host
+// (little-endian) byte order, one for IPv4 and four for IPv6
+func parseProcNetAddr(s string) net.IP {
+	b, err := hex.DecodeString(s)
+	if err != nil || (len(b) != net.IPv4len && len(b) != net.IPv6len) {
+		return nil
+	}
+	for i := 0; i < len(b); i += 4 {
+		b[i], b[i+1], b[i+2], b[i+3] = b[i+3], b[i+2], b[i+1], b[i]
+	}
+	return net.IP(b)
+}
+
+// netTCPConnection holds the MSFT_NetTCPConnection properties of a TCP
+// socket, the class behind Get-NetTCPConnection
+type netTCPConnection struct {
+	LocalAddress string
+	LocalPort    uint16
+}
+
+// netTCPListeningPorts returns which of the given ports the listening
+// sockets in conns are bound to, leaving out those bound to loopback
+func netTCPListeningPorts(conns []netTCPConnection, ports []int) []int {
+	var listening []int
+	for _, c := range conns {
+		if ip := net.ParseIP(c.LocalAddress); ip != nil && ip.IsLoopback() {
+			continue
+		}
+		port := int(c.LocalPort)
+		if slices.Contains(ports, port) && !slices.Contains(listening, port) {
+			listening = append(listening, port)
+		}
+	}
+	slices.Sort(listening)
+	return listening
+}
+
+// joinPorts formats port numbers as a comma-separated list
+func joinPorts(ports []int) string {
+	s := make([]string, len(ports))
+	for i, p := range ports {
+		s[i] = strconv.Itoa(p)
+	}
+	return strings.Join(s, ", ")
+}
+
+// managementEngineFindings returns exposed AMT and unsafe ME or PSP states
+// as findings
+func managementEngineFindings(r *ManagementEngineResult) []Finding {
+	var findings []Finding
+	add := func(id, title, severity, remediation string) {
+		findings = append(findings, Finding{
+			ID:                 id,
+			Title:              title,
+			Severity:           severity,
+			Check:              CheckManagementEngine,
+			Remediation:        remediation,
+			RemediationCommand: remediationCommand(id),
+		})
+	}
+	if r.AMTExposed {
+		severity := SeverityMedium
+		if slices.ContainsFunc(r.AMTPorts, func(p int) bool { return slices.Contains(amtPlaintextPorts, p) }) {
+			severity = SeverityHigh
+		}
+		title := T("Intel AMT is provisioned and reachable over the network")
+		if len(r.AMTPorts) > 0 {
+			title = T("Intel AMT is provisioned and listening on ports %s", joinPorts(r.AMTPorts))
+		}
+		add("amt_exposed", title, severity,
+			T("Unprovision Intel AMT in the MEBx setup or disable it in the firmware settings unless it is used for remote management"))
+	}
+	if r.ManufacturingMode {
+		add("me_manufacturing_mode", T("Intel ME is in manufacturing mode"), SeverityHigh,
+			T("Install the vendor's firmware update that closes manufacturing mode"))
+	}
+	if slices.Contains(meUnsafeModes, r.OperationMode) {
+		add("me_override_mode", T("Intel ME security is bypassed (%s mode)", r.OperationMode), SeverityHigh,
+			T("Remove the flash descriptor override jumper and restore the ME to normal mode in the firmware settings"))
+	}
+	if r.DebugUnlocked {
+		add("psp_debug_unlocked", T("AMD PSP debug is unlocked"), SeverityHigh,
+			T("Contact the vendor: production systems should ship with PSP debug locked"))
+	}
+	if r.Fused != nil && !*r.Fused {
+		add("psp_not_fused", T("AMD platform is not fused for production"), SeverityMedium,
+			T("Contact the vendor: production systems should ship fused"))
+	}
+	if r.Error != nil && len(findings) == 0 {
+		findings = append(findings, unverifiedFinding("management_engine_unverified", CheckManagementEngine, "Management engine", r.Error))
+	}
+	return findings
+}
+
+// FormatManagementEngineTable formats the management engine state as a
+// colored table
+func FormatManagementEngineTable(result *ManagementEngineResult) string {
+	return FormatManagementEngineTableWith(CurrentStyler(), result)
+}
+
+// FormatManagementEngineTableWith is like FormatManagementEngineTable but
+// styles the table with st
+func FormatManagementEngineTableWith(st Styler, result *ManagementEngineResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconChip + " Management Engine"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n")
+		if result.Error.Hint != "" {
+			sb.WriteString(st.Muted("  " + result.Error.Hint))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	if !result.Detected && !result.AMTExposed {
+		sb.WriteString(st.Muted("No Intel ME or AMD PSP interface found"))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	sb.WriteString(st.TableTop(20, 36))
+	sb.WriteString("\n")
+	row := func(label, value string) {
+		sb.WriteString(st.TableRowColored(PadRight(label, 20), PadRight(value, 36)))
+		sb.WriteString("\n")
+	}
+	check := func(ok bool, good, bad string) string {
+		if ok {
+			return st.Success(IconCheck + " " + good)
+		}
+		return st.Danger(IconCross + " " + bad)
+	}
+	engine := "Intel ME"
+	if result.Vendor == "AMD" {
+		engine = "AMD PSP"
+	}
+	row("Engine", engine)
+	if result.Interface != "" {
+		row("Interface", result.Interface)
+	}
+	if result.FirmwareVersion != "" {
+		row("Firmware", st.Info(result.FirmwareVersion))
+	}
+	if result.OperationMode != "" {
+		row("Operation Mode", check(!slices.Contains(meUnsafeModes, result.OperationMode), result.OperationMode, result.OperationMode))
+		row("Manufacturing Mode", check(!result.ManufacturingMode, "closed", "open"))
+	}
+	if result.Fused != nil {
+		row("Debug", check(!result.DebugUnlocked, "locked", "unlocked"))
+		row("Production Fused", check(*result.Fused, "yes", "no"))
+	}
+	if result.Vendor != "AMD" {
+		amt := st.Muted("not listening")
+		switch {
+		case len(result.AMTPorts) > 0:
+			amt = st.Danger(IconCross + " listening on " + joinPorts(result.AMTPorts))
+		case result.AMTExposed:
+			amt = st.Danger(IconCross + " provisioned")
+		case result.AMTProvisioningState != "":
+			amt = st.Success(IconCheck + " " + result.AMTProvisioningState)
+		case result.AMTCapable:
+			amt = st.Success(IconCheck + " not provisioned")
+		}
+		row("AMT", amt)
+	}
+	sb.WriteString(st.TableBottom(20, 36))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// FormatManagementEngine formats the management engine state in the
+// specified format
+func FormatManagementEngine(result *ManagementEngineResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatManagementEngineTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package inspector
+
+import "context"
+
+// readWindowsManagementEngine is only implemented on Windows
+func readWindowsManagementEngine(ctx context.Context, result *ManagementEngineResult) *ProbeError {
+	return nil
+}
+
+// windowsListeningPorts is only implemented on Windows
+func windowsListeningPorts(ctx context.Context, ports []int) ([]int, *ProbeError) {
+	return nil, nil
+}
@@ -0,0 +1,169 @@
+package inspector
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadLinuxMEI(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sys/class/mei/mei0/fw_ver":    {Data: []byte("0:16.1.25.2091\n0:16.1.25.2091\n0:16.0.15.1810\n")},
+		"sys/class/mei/mei0/fw_status": {Data: []byte("90000245\n09F10506\n00000020\n00004000\n00041F03\nC7E003CB\n")},
+		"sys/bus/mei/devices/0000:00:16.0-8e6a6715-9abc-4043-88ef-9e39c6f63e0f/uuid": {Data: []byte("8e6a6715-9abc-4043-88ef-9e39c6f63e0f\n")},
+		"sys/bus/mei/devices/0000:00:16.0-12f80028-b4b7-4b2d-aca8-46e0ff65814c/uuid": {Data: []byte("12f80028-b4b7-4b2d-aca8-46e0ff65814c\n")},
+	}
+	result := &ManagementEngineResult{}
+	readLinuxMEI(fsys, result)
+	if !result.Detected || result.Vendor != "Intel" || result.Interface != "/dev/mei0" || result.FirmwareVersion != "16.1.25.2091" {
+		t.Errorf("result = %+v", result)
+	}
+	if result.OperationMode != "normal" || result.ManufacturingMode || !result.AMTCapable {
+		t.Errorf("mode = %q, manufacturing %v, AMT capable %v", result.OperationMode, result.ManufacturingMode, result.AMTCapable)
+	}
+	finishManagementEngine(result)
+	if !result.Compliant || len(managementEngineFindings(result)) != 0 {
+		t.Errorf("an unprovisioned ME in normal mode should comply: %+v", result)
+	}
+}
+
+func TestParseHFSTS1(t *testing.T) {
+	tests := []struct {
+		hfsts1        uint32
+		manufacturing bool
+		mode          string
+	}{
+		{0x90000245, false, "normal"},
+		{0x90000245 | 1<<4, true, "normal"},
+		{0x90040245, false, "override jumper"},
+		{0x90030245, false, "disabled"},
+		{0x90070245, false, "unknown (7)"},
+	}
+	for _, tt := range tests {
+		manufacturing, mode := parseHFSTS1(tt.hfsts1)
+		if manufacturing != tt.manufacturing || mode != tt.mode {
+			t.Errorf("parseHFSTS1(%#x) = %v, %q, want %v, %q", tt.hfsts1, manufacturing, mode, tt.manufacturing, tt.mode)
+		}
+	}
+}
+
+func TestReadLinuxPSP(t *testing.T) {
+	const dir = "sys/bus/pci/drivers/ccp/0000:c4:00.2/"
+	fsys := fstest.MapFS{
+		dir + "fused_part":         {Data: []byte("1\n")},
+		dir + "debug_lock_on":      {Data: []byte("0\n")},
+		dir + "bootloader_version": {Data: []byte("00.2d.00.78\n")},
+	}
+	result := &ManagementEngineResult{}
+	readLinuxPSP(fsys, result)
+	if !result.Detected || result.Vendor != "AMD" || result.FirmwareVersion != "00.2d.00.78" || result.Fused == nil || !*result.Fused {
+		t.Errorf("result = %+v", result)
+	}
+	finishManagementEngine(result)
+	findings := managementEngineFindings(result)
+	if result.Compliant || len(findings) != 1 || findings[0].ID != "psp_debug_unlocked" {
+		t.Errorf("findings = %+v, want psp_debug_unlocked", findings)
+	}
+}
+
+func TestLinuxListeningPorts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/net/tcp": {Data: []byte(`  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:4260 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 21811 1 0000000000000000 100 0 0 10 0
+   1: 0F02000A:4261 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 21812 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:0277 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 21813 1 0000000000000000 100 0 0 10 0
+   3: 0F02000A:4262 0100000A:D431 01 00000000:00000000 00:00000000 00000000  1000        0 33412 1 0000000000000000 20 4 30 10 -1
+   4: 0100007F:4263 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 21814 1 0000000000000000 100 0 0 10 0
+`)},
+		"proc/net/tcp6": {Data: []byte(`  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000001000000:4262 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 21815 1 0000000000000000 100 0 0 10 0
+   1: 0000000000000000FFFF00000100007F:4263 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 21816 1 0000000000000000 100 0 0 10 0
+`)},
+	}
+	ports, perr := linuxListeningPorts(context.Background(), fsys, amtPorts)
+	if perr != nil || !slices.Equal(ports, []int{16992, 16993}) {
+		t.Errorf("ports = %v, %v, want 16992 and 16993 without the loopback listeners", ports, perr)
+	}
+
+	result := &ManagementEngineResult{Detected: true, Vendor: "Intel", AMTPorts: ports}
+	finishManagementEngine(result)
+	findings := managementEngineFindings(result)
+	if !result.AMTExposed || len(findings) != 1 || findings[0].ID != "amt_exposed" || findings[0].Severity != SeverityHigh {
+		t.Errorf("findings = %+v, want a high amt_exposed", findings)
+	}
+
+	// TLS-only ports are still exposed, at a lower severity
+	result = &ManagementEngineResult{AMTPorts: []int{16993}}
+	finishManagementEngine(result)
+	if f := managementEngineFindings(result); len(f) != 1 || f[0].Severity != SeverityMedium {
+		t.Errorf("findings = %+v, want a medium amt_exposed", f)
+	}
+
+	if _, perr := linuxListeningPorts(context.Background(), fstest.MapFS{}, amtPorts); perr == nil {
+		t.Error("a missing /proc/net/tcp should be an error")
+	}
+}
+
+func TestAMTProvisioning(t *testing.T) {
+	req := amthiRequest(amthiGetProvisioningState)
+	if !slices.Equal(req, []byte{1, 1, 0, 0, 0x11, 0, 0, 0x04, 0, 0, 0, 0}) {
+		t.Errorf("request = % x", req)
+	}
+
+	resp := []byte{1, 1, 0, 0, 0x11, 0, 0x80, 0x04, 8, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0}
+	if state, err := parseAMTProvisioningState(resp); err != nil || state != "post-provisioning" {
+		t.Errorf("state = %q, %v, want post-provisioning", state, err)
+	}
+	resp[12] = 1
+	if _, err := parseAMTProvisioningState(resp); err == nil {
+		t.Error("a failed status should be an error")
+	}
+	if _, err := parseAMTProvisioningState(resp[:12]); err == nil {
+		t.Error("a short response should be an error")
+	}
+
+	// LMS listening on loopback alone does not expose AMT; provisioning does
+	result := &ManagementEngineResult{Detected: true, Vendor: "Intel", AMTCapable: true, AMTProvisioningState: "pre-provisioning"}
+	finishManagementEngine(result)
+	if result.AMTExposed || !result.Compliant {
+		t.Errorf("unprovisioned AMT should comply: %+v", result)
+	}
+	result = &ManagementEngineResult{Detected: true, Vendor: "Intel", AMTCapable: true, AMTProvisioningState: "post-provisioning"}
+	finishManagementEngine(result)
+	if f := managementEngineFindings(result); !result.AMTExposed || len(f) != 1 || f[0].ID != "amt_exposed" || f[0].Severity != SeverityMedium {
+		t.Errorf("findings = %+v, want a medium amt_exposed", f)
+	}
+}
+
+func TestNetTCPListeningPorts(t *testing.T) {
+	conns := []netTCPConnection{
+		{"0.0.0.0", 135},
+		{"0.0.0.0", 16992},
+		{"127.0.0.1", 16993},
+		{"::1", 16994},
+		{"::", 16995},
+		{"10.0.0.15", 16992},
+	}
+	if got := netTCPListeningPorts(conns, amtPorts); !slices.Equal(got, []int{16992, 16995}) {
+		t.Errorf("netTCPListeningPorts = %v", got)
+	}
+}
+
+func TestManagementEngineFindings_Override(t *testing.T) {
+	result := &ManagementEngineResult{Detected: true, Vendor: "Intel", OperationMode: "override jumper", ManufacturingMode: true}
+	finishManagementEngine(result)
+	var ids []string
+	for _, f := range managementEngineFindings(result) {
+		ids = append(ids, f.ID)
+	}
+	if result.Compliant || !slices.Equal(ids, []string{"me_manufacturing_mode", "me_override_mode"}) {
+		t.Errorf("findings = %q, want manufacturing and override mode", ids)
+	}
+
+	result = &ManagementEngineResult{Error: &ProbeError{Code: CodePermissionDenied, Message: "access denied"}}
+	finishManagementEngine(result)
+	if f := managementEngineFindings(result); len(f) != 1 || f[0].ID != "management_engine_unverified" {
+		t.Errorf("findings = %+v, want management_engine_unverified", f)
+	}
+}
@@ -0,0 +1,46 @@
+//go:build windows
+
+package inspector
+
+import (
+	"context"
+	"strings"
+)
+
+// mePnPEntity holds the Win32_PnPEntity properties of a management engine
+// interface device
+type mePnPEntity struct {
+	Name    string
+	Service string
+}
+
+// readWindowsManagementEngine finds the Intel MEI or AMD PSP device in WMI
+func readWindowsManagementEngine(ctx context.Context, result *ManagementEngineResult) *ProbeError {
+	var entities []mePnPEntity
+	query := `SELECT Name, Service FROM Win32_PnPEntity WHERE Name LIKE '%Management Engine Interface%' OR Service = 'amdpsp'`
+	if err := wmiQuery(query, &entities, ""); err != nil {
+		return classifyWMIError(ctx, `root\cimv2`, err)
+	}
+	if len(entities) == 0 {
+		return nil
+	}
+	result.Detected = true
+	result.Interface = entities[0].Name
+	result.Vendor = "Intel"
+	if strings.EqualFold(entities[0].Service, "amdpsp") {
+		result.Vendor = "AMD"
+	}
+	return nil
+}
+
+// windowsListeningPorts returns which of the given TCP ports are listening
+// on a non-loopback address. MSFT_NetTCPConnection reports the state as a
+// number (2 is Listen), where netstat prints it in the display language.
+func windowsListeningPorts(ctx context.Context, ports []int) ([]int, *ProbeError) {
+	var conns []netTCPConnection
+	query := `SELECT LocalAddress, LocalPort FROM MSFT_NetTCPConnection WHERE State = 2`
+	if err := wmiQuery(query, &conns, `root\StandardCimv2`); err != nil {
+		return nil, classifyWMIError(ctx, `root\StandardCimv2`, err)
+	}
+	return netTCPListeningPorts(conns, ports), nil
+}
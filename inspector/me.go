@@ -0,0 +1,202 @@
+package inspector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// meActions is how many actions the personal summary suggests
+const meActions = 3
+
+// fixMinutes is about how long fixing a finding takes its user, by finding
+// ID; other findings take defaultFixMinutes
+var fixMinutes = map[string]int{
+	"reboot_pending":              5,
+	"biometrics_not_configured":   5,
+	"encryption_disabled":         10,
+	"secure_boot_disabled":        15,
+	"tpm_missing":                 15,
+	"firmware_update_available":   20,
+	"firmware_outdated":           20,
+	"firmware_hsi_low":            30,
+	"wsl_host_bitlocker_disabled": 10,
+}
+
+// Fix times of findings missing from fixMinutes, with and without a
+// remediation command
+const (
+	defaultCommandFixMinutes = 5
+	defaultFixMinutes        = 15
+)
+
+// MeSummary is a short, friendly summary for the person using the machine,
+// such as an employee shown it by IT: the score, what it means, and the few
+// actions that matter most, with about how long they take
+type MeSummary struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Score  int    `json:"score"`
+	Status string `json:"status"`
+	// Message says what the score means, in the user's language
+	Message string `json:"message"`
+	// Actions are the most severe findings to fix, at most three
+	Actions []MeAction `json:"actions"`
+	// MoreActions counts the findings left out of Actions
+	MoreActions int `json:"more_actions,omitempty"`
+	// EstimatedMinutes is about how long the actions take altogether
+	EstimatedMinutes int `json:"estimated_minutes"`
+	// ScoreAfter is the score once the actions make their checks pass
+	ScoreAfter int `json:"score_after"`
+}
+
+// MeAction is one thing for the user to do
+type MeAction struct {
+	// Finding is the ID of the finding the action fixes
+	Finding  string `json:"finding"`
+	Title    string `json:"title"`
+	Severity string `json:"severity"`
+	// Action is the finding's remediation
+	Action  string `json:"action"`
+	Command string `json:"command,omitempty"`
+	// Minutes is about how long the action takes
+	Minutes int `json:"minutes"`
+	// Points is what the action adds to the score when it makes its check
+	// pass, after the actions before it
+	Points int `json:"points"`
+}
+
+// NewMeSummary turns a security summary into the personal summary: its most
+// severe findings become the actions
+func NewMeSummary(summary *SecuritySummary) *MeSummary {
+	me := &MeSummary{
+		Score:   summary.OverallScore,
+		Status:  summary.OverallStatus,
+		Actions: []MeAction{},
+	}
+	me.Message = meMessage(me.Status)
+
+	findings := append([]Finding(nil), summary.Findings...)
+	SortFindings(findings)
+	if len(findings) > meActions {
+		me.MoreActions = len(findings) - meActions
+		findings = findings[:meActions]
+	}
+	checks := summary.scoredChecks()
+	passed := scoredResults(summary)
+	score, _ := scoreChecks(checks, passed, summary.NotApplicable)
+	me.ScoreAfter = summary.OverallScore
+	for _, f := range findings {
+		a := MeAction{
+			Finding:  f.ID,
+			Title:    f.Title,
+			Severity: f.Severity,
+			Action:   f.Remediation,
+			Command:  f.RemediationCommand,
+			Minutes:  findingFixMinutes(f),
+		}
+		if _, ran := passed[f.Check]; ran && !passed[f.Check] {
+			passed[f.Check] = true
+			after, _ := scoreChecks(checks, passed, summary.NotApplicable)
+			a.Points, score = max(after-score, 0), after
+		}
+		me.EstimatedMinutes += a.Minutes
+		me.ScoreAfter = min(me.ScoreAfter+a.Points, 100)
+		me.Actions = append(me.Actions, a)
+	}
+	return me
+}
+
+// findingFixMinutes estimates how long fixing a finding takes
+func findingFixMinutes(f Finding) int {
+	if m, ok := fixMinutes[f.ID]; ok {
+		return m
+	}
+	if f.RemediationCommand != "" {
+		return defaultCommandFixMinutes
+	}
+	return defaultFixMinutes
+}
+
+// meMessage says what an overall status means to the user
+func meMessage(status string) string {
+	switch status {
+	case "excellent":
+		return T("Great job! Your device is fully protected.")
+	case "good":
+		return T("Looking good. A few quick fixes will make your device even safer.")
+	case "fair":
+		return T("Not bad, but your device needs some attention.")
+	case "needs_improvement":
+		return T("Your device needs attention. The actions below help the most.")
+	case "critical":
+		return T("Your device is at risk. Please take the actions below as soon as you can.")
+	}
+	return T("There is nothing to score on this device.")
+}
+
+// FormatMeSummaryTable formats the personal summary as a friendly colored
+// screen
+func FormatMeSummaryTable(me *MeSummary) string {
+	return FormatMeSummaryTableWith(CurrentStyler(), me)
+}
+
+// FormatMeSummaryTableWith is like FormatMeSummaryTable but styles the table
+// with st
+func FormatMeSummaryTableWith(st Styler, me *MeSummary) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " " + T("Your Device Security")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	// Containers and WSL guests have no score of their own
+	if me.Status != StatusNotApplicableInContainer && me.Status != StatusNotApplicableInWSL && me.Score != NoScore {
+		sb.WriteString("  " + st.BoldText(FormatScore(me.Score)) + "  ")
+		sb.WriteString(securityScoreBar(st, me.Score, 30))
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString("  " + me.Message)
+	sb.WriteString("\n\n")
+
+	if len(me.Actions) == 0 {
+		sb.WriteString("  " + st.Success(IconCheck+" "+T("Nothing to do. Keep it up!")))
+		sb.WriteString("\n")
+		return sb.String()
+	}
+
+	sb.WriteString(st.BoldText(T("What to do next:")))
+	sb.WriteString("\n")
+	for i, a := range me.Actions {
+		sb.WriteString(fmt.Sprintf("\n  %s %s\n", severityStyle(st, a.Severity)(fmt.Sprintf("%d.", i+1)), st.BoldText(a.Action)))
+		sb.WriteString("     " + st.Muted(a.Title) + "\n")
+		var detail []string
+		if a.Points > 0 {
+			detail = append(detail, st.Success(T("+%d points", a.Points)))
+		}
+		detail = append(detail, T("about %d min", a.Minutes))
+		sb.WriteString("     " + strings.Join(detail, st.Muted(" · ")) + "\n")
+		if a.Command != "" {
+			sb.WriteString("     " + st.Info("$ "+a.Command) + "\n")
+		}
+	}
+	if me.MoreActions > 0 {
+		sb.WriteString("\n  " + st.Muted(T("…and %d more (posture summary lists them all)", me.MoreActions)) + "\n")
+	}
+
+	sb.WriteString("\n")
+	if me.ScoreAfter > me.Score {
+		sb.WriteString(st.BoldText(IconArrow + " " + T("About %d min to reach %d/100", me.EstimatedMinutes, me.ScoreAfter)))
+	} else {
+		sb.WriteString(st.BoldText(IconArrow + " " + T("About %d min in all", me.EstimatedMinutes)))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// FormatMeSummary formats the personal summary in the specified format
+func FormatMeSummary(me *MeSummary, format string) string {
+	return FormatOutputWith(me, func(st Styler) string {
+		return FormatMeSummaryTableWith(st, me)
+	}, format)
+}
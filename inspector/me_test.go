@@ -0,0 +1,57 @@
+package inspector
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNewMeSummary(t *testing.T) {
+	t.Setenv(MandatoryChecksEnv, "")
+	t.Setenv(InformationalChecksEnv, "")
+	t.Setenv(CheckWeightsEnv, "")
+	summary := &SecuritySummary{
+		Platform:      "linux",
+		OverallScore:  50,
+		OverallStatus: "fair",
+		CheckResults: map[string]string{
+			CheckTPM: CheckResultPass, CheckSecureBoot: CheckResultFail,
+			CheckEncryption: CheckResultFail, CheckBiometrics: CheckResultFail,
+		},
+		Findings: []Finding{
+			{ID: "biometrics_not_configured", Severity: SeverityLow, Check: CheckBiometrics, Remediation: "Enroll a fingerprint"},
+			{ID: "encryption_disabled", Severity: SeverityCritical, Check: CheckEncryption, Remediation: "Encrypt the disk"},
+			{ID: "secure_boot_disabled", Severity: SeverityHigh, Check: CheckSecureBoot, Remediation: "Enable Secure Boot"},
+			{ID: "cloud_imdsv1_enabled", Severity: SeverityHigh, Check: CheckCloud, Remediation: "Require IMDSv2", RemediationCommand: "aws ec2 modify-instance-metadata-options"},
+		},
+	}
+	me := NewMeSummary(summary)
+	var ids []string
+	for _, a := range me.Actions {
+		ids = append(ids, a.Finding)
+	}
+	// Most severe first; the cloud finding is as severe as Secure Boot but
+	// adds no points
+	if got := strings.Join(ids, ","); got != "encryption_disabled,secure_boot_disabled,cloud_imdsv1_enabled" {
+		t.Fatalf("actions = %s", got)
+	}
+	if me.MoreActions != 1 || me.Message != meMessage("fair") {
+		t.Errorf("more actions %d, message %q", me.MoreActions, me.Message)
+	}
+	if a := me.Actions[0]; a.Points <= 0 || a.Minutes != fixMinutes["encryption_disabled"] || a.Action != "Encrypt the disk" {
+		t.Errorf("first action = %+v", a)
+	}
+	if a := me.Actions[2]; a.Points != 0 || a.Minutes != defaultCommandFixMinutes {
+		t.Errorf("cloud action = %+v, want no points and the command fix time", a)
+	}
+	if me.EstimatedMinutes != 10+15+5 || me.ScoreAfter != me.Score+me.Actions[0].Points+me.Actions[1].Points {
+		t.Errorf("estimate %d min to %d/100", me.EstimatedMinutes, me.ScoreAfter)
+	}
+
+	table := FormatMeSummaryTable(me)
+	for _, want := range []string{"Encrypt the disk", fmt.Sprintf("+%d points", me.Actions[0].Points), "posture summary", "min to reach"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("table is missing %q:\n%s", want, table)
+		}
+	}
+}
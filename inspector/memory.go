@@ -6,10 +6,14 @@ import (
 	"strings"
 
 	"github.com/shirou/gopsutil/v4/mem"
+
+	"github.com/agentplexus/posture/render"
 )
 
 // MemoryResult contains memory usage information
 type MemoryResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
 	TotalBytes     uint64  `json:"total_bytes"`
 	UsedBytes      uint64  `json:"used_bytes"`
 	FreeBytes      uint64  `json:"free_bytes"`
@@ -18,10 +22,16 @@ type MemoryResult struct {
 	TotalHuman     string  `json:"total_human"`
 	UsedHuman      string  `json:"used_human"`
 	AvailableHuman string  `json:"available_human"`
+	// TopProcesses is set when the caller asked for the largest processes
+	// (see GetMemoryTop)
+	TopProcesses *MemoryTopResult `json:"top_processes,omitempty"`
 }
 
 // GetMemory returns current memory usage
 func GetMemory(ctx context.Context) (*MemoryResult, error) {
+	if result, ok, err := loadFixture[MemoryResult](ctx, "memory"); ok {
+		return result, err
+	}
 	vmStat, err := mem.VirtualMemoryWithContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get memory stats: %w", err)
@@ -41,76 +51,87 @@ func GetMemory(ctx context.Context) (*MemoryResult, error) {
 
 // FormatMemoryTable formats memory usage as a colored table
 func FormatMemoryTable(result *MemoryResult) string {
+	return FormatMemoryTableWith(CurrentStyler(), result)
+}
+
+// FormatMemoryTableWith is like FormatMemoryTable but styles the table with
+// st
+func FormatMemoryTableWith(st Styler, result *MemoryResult) string {
 	var sb strings.Builder
 	sb.WriteString("\n")
-	sb.WriteString(Header(IconMemory + " Memory Usage"))
+	sb.WriteString(st.Header(IconMemory + " " + T("Memory Usage")))
 	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 50)))
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
 	sb.WriteString("\n\n")
 
 	// Usage summary with progress bar
-	sb.WriteString(BoldText("Usage: "))
-	usageColor := UsageColor(result.UsedPercent)
-	sb.WriteString(Colorize(usageColor+Bold, fmt.Sprintf("%.1f%%", result.UsedPercent)))
-	sb.WriteString(Muted(" of "))
-	sb.WriteString(Info(result.TotalHuman))
+	sb.WriteString(st.BoldText(T("Usage:") + " "))
+	usageStyle := render.UsageStyle(result.UsedPercent)
+	sb.WriteString(st.Styled(usageStyle, st.BoldText(fmt.Sprintf("%.1f%%", result.UsedPercent))))
+	sb.WriteString(st.Muted(" " + T("of") + " "))
+	sb.WriteString(st.Info(result.TotalHuman))
 	sb.WriteString("\n")
-	sb.WriteString(ProgressBar(result.UsedPercent, 40))
+	sb.WriteString(st.ProgressBar(result.UsedPercent, 40))
 	sb.WriteString("\n\n")
 
 	// Memory details table
-	sb.WriteString(TableTop(12, 14, 20))
+	sb.WriteString(st.TableTop(12, 14, 20))
 	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Metric", 12)),
-		Header(PadLeft("Size", 14)),
-		Header(PadLeft("Bytes", 20)),
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Metric"), 12)),
+		st.Header(PadLeft(T("Size"), 14)),
+		st.Header(PadLeft(T("Bytes"), 20)),
 	))
 	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(12, 14, 20))
+	sb.WriteString(st.TableSeparator(12, 14, 20))
 	sb.WriteString("\n")
 
 	// Total
-	sb.WriteString(TableRowColored(
-		Info(PadRight(IconDiamond+" Total", 12)),
-		PadLeft(result.TotalHuman, 14),
-		Muted(PadLeft(fmt.Sprintf("%d", result.TotalBytes), 20)),
+	sb.WriteString(st.TableRowColored(
+		st.Info(PadRight(IconDiamond+" "+T("Total"), 12)),
+		PadLeft(Truncate(result.TotalHuman, 14), 14),
+		st.Muted(PadLeft(fmt.Sprintf("%d", result.TotalBytes), 20)),
 	))
 	sb.WriteString("\n")
 
 	// Used
-	usedColor := UsageColor(result.UsedPercent)
-	sb.WriteString(TableRowColored(
-		Colorize(usedColor, PadRight(IconCircle+" Used", 12)),
-		Colorize(usedColor, PadLeft(result.UsedHuman, 14)),
-		Muted(PadLeft(fmt.Sprintf("%d", result.UsedBytes), 20)),
+	usedStyle := render.UsageStyle(result.UsedPercent)
+	sb.WriteString(st.TableRowColored(
+		st.Styled(usedStyle, PadRight(IconCircle+" "+T("Used"), 12)),
+		st.Styled(usedStyle, PadLeft(Truncate(result.UsedHuman, 14), 14)),
+		st.Muted(PadLeft(fmt.Sprintf("%d", result.UsedBytes), 20)),
 	))
 	sb.WriteString("\n")
 
 	// Free
-	sb.WriteString(TableRowColored(
-		Success(PadRight(IconCircle+" Free", 12)),
-		Success(PadLeft(FormatBytes(result.FreeBytes), 14)),
-		Muted(PadLeft(fmt.Sprintf("%d", result.FreeBytes), 20)),
+	sb.WriteString(st.TableRowColored(
+		st.Success(PadRight(IconCircle+" "+T("Free"), 12)),
+		st.Success(PadLeft(FormatBytes(result.FreeBytes), 14)),
+		st.Muted(PadLeft(fmt.Sprintf("%d", result.FreeBytes), 20)),
 	))
 	sb.WriteString("\n")
 
 	// Available
-	sb.WriteString(TableRowColored(
-		Success(PadRight(IconCircle+" Available", 12)),
-		Success(PadLeft(result.AvailableHuman, 14)),
-		Muted(PadLeft(fmt.Sprintf("%d", result.AvailableBytes), 20)),
+	sb.WriteString(st.TableRowColored(
+		st.Success(PadRight(IconCircle+" "+T("Available"), 12)),
+		st.Success(PadLeft(Truncate(result.AvailableHuman, 14), 14)),
+		st.Muted(PadLeft(fmt.Sprintf("%d", result.AvailableBytes), 20)),
 	))
 	sb.WriteString("\n")
 
-	sb.WriteString(TableBottom(12, 14, 20))
+	sb.WriteString(st.TableBottom(12, 14, 20))
 	sb.WriteString("\n")
+
+	if result.TopProcesses != nil && len(result.TopProcesses.Processes) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(formatMemoryTopSection(st, result.TopProcesses))
+	}
 	return sb.String()
 }
 
 // FormatMemory formats memory usage in the specified format
 func FormatMemory(result *MemoryResult, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatMemoryTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatMemoryTableWith(st, result)
 	}, format)
 }
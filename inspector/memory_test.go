@@ -207,3 +207,56 @@ func TestFormatMemoryTable_LowUsage(t *testing.T) {
 		t.Error("Low usage should use green color")
 	}
 }
+
+func TestRankByRSS(t *testing.T) {
+	const gib = 1 << 30
+	usage := []ProcessMemory{
+		{PID: 3, Name: "small", RSSBytes: 100 << 20},
+		{PID: 1, Name: "big", RSSBytes: 2 * gib},
+		{PID: 2, Name: "mid", RSSBytes: gib},
+		{PID: 4, Name: "tie", RSSBytes: gib},
+	}
+	result := rankByRSS(usage, 3, 8*gib)
+
+	if len(result.Processes) != 3 {
+		t.Fatalf("got %d processes, want 3", len(result.Processes))
+	}
+	wantPIDs := []int32{1, 2, 4}
+	for i, p := range result.Processes {
+		if p.PID != wantPIDs[i] {
+			t.Errorf("Processes[%d].PID = %d, want %d", i, p.PID, wantPIDs[i])
+		}
+	}
+	if got := result.Processes[0].SharePercent; got != 25 {
+		t.Errorf("SharePercent = %.2f, want 25", got)
+	}
+	if result.Processes[0].RSSHuman == "" || result.TotalHuman == "" {
+		t.Error("human-readable sizes should be set")
+	}
+	if result.TopRSSBytes != 4*gib {
+		t.Errorf("TopRSSBytes = %d, want %d", result.TopRSSBytes, uint64(4*gib))
+	}
+}
+
+func TestGetMemoryTop(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := GetMemoryTop(ctx, 5)
+	if err != nil {
+		t.Fatalf("GetMemoryTop failed: %v", err)
+	}
+	if len(result.Processes) == 0 || len(result.Processes) > 5 {
+		t.Errorf("got %d processes, want 1-5", len(result.Processes))
+	}
+	for i := 1; i < len(result.Processes); i++ {
+		if result.Processes[i].RSSBytes > result.Processes[i-1].RSSBytes {
+			t.Errorf("processes not sorted by RSS at %d", i)
+		}
+	}
+
+	mem := &MemoryResult{TotalHuman: "1 GB", TopProcesses: result}
+	if !strings.Contains(FormatMemoryTable(mem), "Processes by Memory") {
+		t.Error("memory table should include the top processes section")
+	}
+}
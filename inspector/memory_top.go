@@ -0,0 +1,165 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// DefaultMemoryTopN is the number of processes GetMemoryTop returns by default
+const DefaultMemoryTopN = 10
+
+// ProcessMemory is one process's resident memory
+type ProcessMemory struct {
+	PID      int32  `json:"pid"`
+	Name     string `json:"name"`
+	RSSBytes uint64 `json:"rss_bytes"`
+	RSSHuman string `json:"rss_human"`
+	// SharePercent is RSS as a percentage of total physical memory. Shared
+	// pages are counted in every process that maps them, so shares can sum
+	// to more than the memory in use.
+	SharePercent float64 `json:"share_percent"`
+}
+
+// MemoryTopResult lists the processes using the most resident memory
+type MemoryTopResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Processes  []ProcessMemory `json:"processes"`
+	TotalBytes uint64          `json:"total_bytes"`
+	TotalHuman string          `json:"total_human"`
+	// TopRSSBytes is the combined RSS of the listed processes
+	TopRSSBytes uint64 `json:"top_rss_bytes"`
+	TopRSSHuman string `json:"top_rss_human"`
+}
+
+// GetMemoryTop returns the n processes with the largest resident set
+// (DefaultMemoryTopN when n <= 0)
+func GetMemoryTop(ctx context.Context, n int) (*MemoryTopResult, error) {
+	if n <= 0 {
+		n = DefaultMemoryTopN
+	}
+	vmStat, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get memory stats: %w", err)
+	}
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	var usage []ProcessMemory
+	for _, p := range procs {
+		info, err := p.MemoryInfoWithContext(ctx)
+		// Processes that exited or that we may not inspect are skipped
+		if err != nil || info == nil || info.RSS == 0 {
+			continue
+		}
+		name, _ := p.NameWithContext(ctx)
+		usage = append(usage, ProcessMemory{PID: p.Pid, Name: name, RSSBytes: info.RSS})
+	}
+	return rankByRSS(usage, n, vmStat.Total), nil
+}
+
+// rankByRSS keeps the n largest processes and fills in sizes and shares
+func rankByRSS(usage []ProcessMemory, n int, total uint64) *MemoryTopResult {
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].RSSBytes != usage[j].RSSBytes {
+			return usage[i].RSSBytes > usage[j].RSSBytes
+		}
+		return usage[i].PID < usage[j].PID
+	})
+	if n < len(usage) {
+		usage = usage[:n]
+	}
+
+	result := &MemoryTopResult{
+		Processes:  []ProcessMemory{},
+		TotalBytes: total,
+		TotalHuman: FormatBytes(total),
+	}
+	for _, p := range usage {
+		p.RSSHuman = FormatBytes(p.RSSBytes)
+		if total > 0 {
+			p.SharePercent = float64(p.RSSBytes) / float64(total) * 100
+		}
+		result.TopRSSBytes += p.RSSBytes
+		result.Processes = append(result.Processes, p)
+	}
+	result.TopRSSHuman = FormatBytes(result.TopRSSBytes)
+	return result
+}
+
+// formatMemoryTopSection renders the top processes by RSS as a table
+func formatMemoryTopSection(st Styler, result *MemoryTopResult) string {
+	var sb strings.Builder
+	sb.WriteString(st.BoldText(T("Top %d Processes by Memory:", len(result.Processes))))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableTop(8, 28, 12, 18))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight("PID", 8)),
+		st.Header(PadRight(T("Name"), 28)),
+		st.Header(PadLeft("RSS", 12)),
+		st.Header(PadRight(T("Share"), 18)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(8, 28, 12, 18))
+	sb.WriteString("\n")
+	for _, p := range result.Processes {
+		name := p.Name
+		if len(name) > 28 {
+			name = name[:25] + "..."
+		}
+		var rss string
+		switch {
+		case p.SharePercent >= 10:
+			rss = st.Danger(PadLeft(p.RSSHuman, 12))
+		case p.SharePercent >= 5:
+			rss = st.Warning(PadLeft(p.RSSHuman, 12))
+		default:
+			rss = PadLeft(p.RSSHuman, 12)
+		}
+		sb.WriteString(st.TableRowColored(
+			st.Info(PadRight(fmt.Sprintf("%d", p.PID), 8)),
+			PadRight(name, 28),
+			rss,
+			PadRight(st.ProgressBar(p.SharePercent, 10)+fmt.Sprintf(" %5.1f%%", p.SharePercent), 18),
+		))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(st.TableBottom(8, 28, 12, 18))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted("  " + T("Listed processes hold %s of %s (shared pages counted per process)", result.TopRSSHuman, result.TotalHuman)))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// FormatMemoryTopTable formats the top processes by memory as a colored table
+func FormatMemoryTopTable(result *MemoryTopResult) string {
+	return FormatMemoryTopTableWith(CurrentStyler(), result)
+}
+
+// FormatMemoryTopTableWith is like FormatMemoryTopTable but styles the table
+// with st
+func FormatMemoryTopTableWith(st Styler, result *MemoryTopResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconMemory + " " + T("Memory by Process")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+	sb.WriteString(formatMemoryTopSection(st, result))
+	return sb.String()
+}
+
+// FormatMemoryTop formats the top processes by memory in the specified format
+func FormatMemoryTop(result *MemoryTopResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatMemoryTopTableWith(st, result)
+	}, format)
+}
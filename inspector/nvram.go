@@ -0,0 +1,65 @@
+package inspector
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// appleSecureBootPolicyVar is the NVRAM variable holding the T2 Secure Boot policy
+const appleSecureBootPolicyVar = "94b73556-2197-4702-82a8-3e1337dafbfb:AppleSecureBootPolicy"
+
+// Apple Secure Boot policy values stored in AppleSecureBootPolicy
+const (
+	secureBootPolicyNone   = 0
+	secureBootPolicyMedium = 1
+	secureBootPolicyFull   = 2
+)
+
+// parseNvramPolicyPlist decodes the output of `nvram -x <var>`, a dictionary
+// mapping the variable name to its raw bytes
+func parseNvramPolicyPlist(data []byte) (int, error) {
+	root, err := decodePlist(data)
+	if err != nil {
+		return 0, err
+	}
+	dict, ok := root.(map[string]any)
+	if !ok {
+		return 0, errors.New("nvram: expected a dictionary")
+	}
+	value, ok := dict[appleSecureBootPolicyVar].([]byte)
+	if !ok || len(value) == 0 {
+		return 0, errors.New("nvram: AppleSecureBootPolicy not set")
+	}
+	return int(value[0]), nil
+}
+
+// parseNvramPolicyText parses the text output of `nvram <var>`, which is the
+// variable name, a tab, and the value with non-printable bytes as %XX
+func parseNvramPolicyText(output string) (int, error) {
+	_, value, ok := strings.Cut(strings.TrimSpace(output), "\t")
+	if !ok || value == "" {
+		return 0, errors.New("nvram: unexpected output")
+	}
+	if strings.HasPrefix(value, "%") && len(value) >= 3 {
+		n, err := strconv.ParseUint(value[1:3], 16, 8)
+		if err != nil {
+			return 0, errors.New("nvram: invalid value " + value)
+		}
+		return int(n), nil
+	}
+	return int(value[0]), nil
+}
+
+// secureBootPolicyMode maps an AppleSecureBootPolicy value to a mode and description
+func secureBootPolicyMode(policy int) (enabled bool, mode, details string) {
+	switch policy {
+	case secureBootPolicyFull:
+		return true, "full", "Full Security"
+	case secureBootPolicyMedium:
+		return true, "medium", "Medium Security"
+	case secureBootPolicyNone:
+		return false, "none", "No Security"
+	}
+	return false, "unknown", "Unrecognized Secure Boot policy value " + strconv.Itoa(policy)
+}
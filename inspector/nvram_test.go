@@ -0,0 +1,46 @@
+package inspector
+
+import "testing"
+
+func TestParseNvramPolicy(t *testing.T) {
+	if got, err := parseNvramPolicyPlist(fixture(t, "darwin/nvram_secureboot_full.plist")); err != nil || got != secureBootPolicyFull {
+		t.Errorf("parseNvramPolicyPlist = (%d, %v), want (%d, nil)", got, err, secureBootPolicyFull)
+	}
+
+	tests := []struct {
+		fixture string
+		want    int
+	}{
+		{"darwin/nvram_secureboot_full.txt", secureBootPolicyFull},
+		{"darwin/nvram_secureboot_medium.txt", secureBootPolicyMedium},
+	}
+	for _, tt := range tests {
+		got, err := parseNvramPolicyText(string(fixture(t, tt.fixture)))
+		if err != nil || got != tt.want {
+			t.Errorf("parseNvramPolicyText(%s) = (%d, %v), want (%d, nil)", tt.fixture, got, err, tt.want)
+		}
+	}
+
+	if _, err := parseNvramPolicyText("nvram: Error getting variable"); err == nil {
+		t.Error("expected error for unexpected output")
+	}
+}
+
+func TestSecureBootPolicyMode(t *testing.T) {
+	tests := []struct {
+		policy  int
+		enabled bool
+		mode    string
+	}{
+		{secureBootPolicyFull, true, "full"},
+		{secureBootPolicyMedium, true, "medium"},
+		{secureBootPolicyNone, false, "none"},
+		{7, false, "unknown"},
+	}
+	for _, tt := range tests {
+		enabled, mode, _ := secureBootPolicyMode(tt.policy)
+		if enabled != tt.enabled || mode != tt.mode {
+			t.Errorf("secureBootPolicyMode(%d) = (%v, %q), want (%v, %q)", tt.policy, enabled, mode, tt.enabled, tt.mode)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// PAM modules that provide biometric authentication
+var pamBiometricModules = map[string]string{
+	"pam_fprintd.so": "fingerprint",
+	"pam_howdy.so":   "face",
+}
+
+// pamAuthServices are the PAM services whose auth stacks are inspected:
+// console and display manager logins, screen unlock, and privilege elevation
+var pamAuthServices = []string{
+	"login", "sudo", "su", "polkit-1", "sshd",
+	"gdm-password", "gdm-fingerprint", "sddm", "lightdm", "kde",
+	"xscreensaver", "gnome-screensaver", "swaylock", "i3lock",
+}
+
+// PAMBiometricRule is a biometric module in a service's PAM auth stack
+type PAMBiometricRule struct {
+	Service string `json:"service"`
+	Module  string `json:"module"`
+	Method  string `json:"method"`
+	Control string `json:"control"`
+	// Required reports whether the module must succeed (required/requisite)
+	// rather than being an alternative to the password (sufficient)
+	Required bool `json:"required"`
+	// IncludedFrom is the file the rule was found in, if not the service's own
+	IncludedFrom string `json:"included_from,omitempty"`
+}
+
+// pamLine is one auth rule from a PAM service file
+type pamLine struct {
+	control string
+	module  string
+	args    []string
+}
+
+// parsePAMAuth returns the auth rules in a PAM service file and the files it
+// pulls in through @include, include, or substack
+func parsePAMAuth(data []byte) (rules []pamLine, includes []string) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		// Fields are separated by any whitespace; normalize to single spaces
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			continue
+		}
+		if name, ok := strings.CutPrefix(line, "@include"); ok {
+			includes = append(includes, strings.TrimSpace(name))
+			continue
+		}
+
+		// Optional leading "-" suppresses logging when the module is missing
+		typ, rest, ok := strings.Cut(line, " ")
+		if !ok || strings.TrimPrefix(typ, "-") != "auth" {
+			continue
+		}
+
+		// Control is a keyword or a bracketed [value=action ...] list
+		var control string
+		if strings.HasPrefix(rest, "[") {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				continue
+			}
+			control, rest = rest[:end+1], strings.TrimSpace(rest[end+1:])
+		} else {
+			control, rest, _ = strings.Cut(rest, " ")
+		}
+
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		if control == "include" || control == "substack" {
+			includes = append(includes, fields[0])
+			continue
+		}
+		rules = append(rules, pamLine{control: control, module: path.Base(fields[0]), args: fields[1:]})
+	}
+	return rules, includes
+}
+
+// pamBiometricMethod returns the biometric method a PAM rule provides, or ""
+func pamBiometricMethod(rule pamLine) string {
+	if method, ok := pamBiometricModules[rule.module]; ok {
+		return method
+	}
+	// Howdy 2.x is loaded through pam_python
+	if rule.module == "pam_python.so" {
+		for _, arg := range rule.args {
+			if strings.Contains(arg, "howdy") {
+				return "face"
+			}
+		}
+	}
+	return ""
+}
+
+// pamControlRequired reports whether a PAM control makes the module mandatory
+func pamControlRequired(control string) bool {
+	switch control {
+	case "required", "requisite":
+		return true
+	}
+	// [success=ok default=die] and similar fail the stack unless the module succeeds
+	return strings.HasPrefix(control, "[") && strings.Contains(control, "default=die")
+}
+
+// inspectPAM finds biometric modules in the auth stacks of the services in
+// fsys (normally /etc/pam.d), following includes
+func inspectPAM(fsys fs.FS) []PAMBiometricRule {
+	var found []PAMBiometricRule
+	for _, service := range pamAuthServices {
+		visited := map[string]bool{}
+		var walk func(name string, depth int)
+		walk = func(name string, depth int) {
+			if visited[name] || depth > 8 {
+				return
+			}
+			visited[name] = true
+			data, err := readFSFile(fsys, name)
+			if err != nil {
+				return
+			}
+			rules, includes := parsePAMAuth(data)
+			for _, rule := range rules {
+				method := pamBiometricMethod(rule)
+				if method == "" {
+					continue
+				}
+				r := PAMBiometricRule{
+					Service:  service,
+					Module:   rule.module,
+					Method:   method,
+					Control:  rule.control,
+					Required: pamControlRequired(rule.control),
+				}
+				if name != service {
+					r.IncludedFrom = name
+				}
+				found = append(found, r)
+			}
+			for _, inc := range includes {
+				walk(path.Base(inc), depth+1)
+			}
+		}
+		walk(service, 0)
+	}
+	return found
+}
@@ -0,0 +1,75 @@
+package inspector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParsePAMAuth(t *testing.T) {
+	rules, includes := parsePAMAuth(fixture(t, "linux/pam.d/common-auth"))
+	if len(includes) != 0 {
+		t.Errorf("includes = %v, want none", includes)
+	}
+	if len(rules) != 5 {
+		t.Fatalf("len(rules) = %d, want 5", len(rules))
+	}
+	if rules[0].module != "pam_fprintd.so" || rules[0].control != "[success=2 default=ignore]" {
+		t.Errorf("rules[0] = %+v", rules[0])
+	}
+
+	_, includes = parsePAMAuth(fixture(t, "linux/pam.d/polkit-1"))
+	if len(includes) != 1 || includes[0] != "system-auth" {
+		t.Errorf("polkit-1 includes = %v, want [system-auth] (account include is ignored)", includes)
+	}
+}
+
+func TestInspectPAM(t *testing.T) {
+	rules := inspectPAM(os.DirFS(filepath.Join("testdata", "linux", "pam.d")))
+
+	type key struct{ service, module string }
+	got := make(map[key]PAMBiometricRule)
+	for _, r := range rules {
+		got[key{r.Service, r.Module}] = r
+	}
+
+	tests := []struct {
+		service, module, method, from string
+		required                      bool
+	}{
+		{"sudo", "pam_fprintd.so", "fingerprint", "common-auth", false},
+		{"login", "pam_fprintd.so", "fingerprint", "common-auth", false},
+		{"gdm-password", "pam_python.so", "face", "", false},
+		{"gdm-password", "pam_fprintd.so", "fingerprint", "common-auth", false},
+		{"polkit-1", "pam_fprintd.so", "fingerprint", "system-auth", true},
+	}
+	for _, tt := range tests {
+		r, ok := got[key{tt.service, tt.module}]
+		if !ok {
+			t.Errorf("%s: %s not found", tt.service, tt.module)
+			continue
+		}
+		if r.Method != tt.method || r.IncludedFrom != tt.from || r.Required != tt.required {
+			t.Errorf("%s/%s = %+v", tt.service, tt.module, r)
+		}
+	}
+	if len(rules) != len(tests) {
+		t.Errorf("len(rules) = %d, want %d: %+v", len(rules), len(tests), rules)
+	}
+}
+
+func TestPAMControlRequired(t *testing.T) {
+	tests := map[string]bool{
+		"required":                   true,
+		"requisite":                  true,
+		"sufficient":                 false,
+		"optional":                   false,
+		"[success=2 default=ignore]": false,
+		"[success=ok default=die]":   true,
+	}
+	for control, want := range tests {
+		if got := pamControlRequired(control); got != want {
+			t.Errorf("pamControlRequired(%q) = %v, want %v", control, got, want)
+		}
+	}
+}
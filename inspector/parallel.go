@@ -0,0 +1,92 @@
+package inspector
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// SummaryOptions controls GetSecuritySummaryWithOptions
+type SummaryOptions struct {
+	// Parallel starts every check at once instead of one after another.
+	// The summary is the same either way; only the order the checks finish
+	// in, and so the order of the scan stats, differs.
+	Parallel bool
+	// Progress, if set, is told when each check starts and finishes. It is
+	// called from the goroutines running the checks, but never concurrently.
+	Progress func(CheckProgress)
+}
+
+// CheckProgress reports a summary check starting or finishing
+type CheckProgress struct {
+	Check string
+	// Done is false when the check starts and true when it finishes
+	Done bool
+	// Duration is how long the check ran, once it is done
+	Duration time.Duration
+	// Err is the error the check failed with, such as a timeout
+	Err error
+}
+
+// pendingCheck is a check started ahead of the summary that needs it
+type pendingCheck struct {
+	done   chan struct{}
+	result any
+	err    error
+}
+
+// startChecks starts the probes of the checks for which runs is true, each
+// in its own goroutine; runCheck then waits for their results in order
+func (r *scanRecorder) startChecks(ctx context.Context, probes []checkProbe, runs func(id string) bool) {
+	r.pending = make(map[string]*pendingCheck)
+	for _, probe := range probes {
+		if !runs(probe.check) {
+			continue
+		}
+		p := &pendingCheck{done: make(chan struct{})}
+		r.pending[probe.check] = p
+		go func() {
+			defer close(p.done)
+			r.track(ctx, probe.check, func(ctx context.Context) error {
+				p.result, p.err = withTimeout(ctx, CheckTimeout(probe.check), probe.check, probe.run)
+				return p.err
+			})
+		}()
+	}
+}
+
+// summaryCheckSupported reports whether GetSecuritySummary runs a check on
+// this platform and in env, before the check selection applies
+func summaryCheckSupported(id string, env *RuntimeEnvironment) bool {
+	switch id {
+	case CheckTPM:
+		return IsTPMSupported()
+	case CheckSecureBoot:
+		return IsSecureBootSupported()
+	case CheckBootOrder:
+		return IsBootOrderSupported()
+	case CheckEncryption:
+		return IsEncryptionSupported()
+	case CheckBiometrics:
+		return IsBiometricsSupported()
+	case CheckDefender:
+		return IsDefenderSupported()
+	case CheckUAC:
+		return IsUACSupported()
+	case CheckLegacyProtocols:
+		return IsLegacyProtocolsSupported()
+	case CheckKubelet:
+		return IsKubeletSupported()
+	case CheckManagementEngine:
+		return IsManagementEngineSupported()
+	case CheckUSBStorage:
+		// Attached storage only matters when policy blocks it
+		return USBStoragePolicy() == USBStoragePolicyBlock
+	case CheckPasskeys:
+		return slices.Contains(PlatformChecks(), CheckPasskeys)
+	case CheckKeychain:
+		// A container has no credential store or password managers
+		return IsKeychainSupported() && !env.Containerized
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package inspector
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestGetSecuritySummaryWithOptions_Parallel(t *testing.T) {
+	sequential, err := GetSecuritySummaryContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetSecuritySummary: %v", err)
+	}
+
+	started := map[string]int{}
+	finished := map[string]int{}
+	parallel, err := GetSecuritySummaryWithOptionsContext(context.Background(), SummaryOptions{
+		Parallel: true,
+		Progress: func(e CheckProgress) {
+			if e.Done {
+				if started[e.Check] == 0 {
+					t.Errorf("%s finished before it started", e.Check)
+				}
+				finished[e.Check]++
+			} else {
+				started[e.Check]++
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetSecuritySummaryWithOptions: %v", err)
+	}
+
+	if parallel.OverallScore != sequential.OverallScore || parallel.OverallStatus != sequential.OverallStatus {
+		t.Errorf("parallel score %d (%s), sequential %d (%s)",
+			parallel.OverallScore, parallel.OverallStatus, sequential.OverallScore, sequential.OverallStatus)
+	}
+	if !maps.Equal(parallel.CheckResults, sequential.CheckResults) {
+		t.Errorf("parallel CheckResults = %v, sequential %v", parallel.CheckResults, sequential.CheckResults)
+	}
+	findingIDs := func(s *SecuritySummary) []string {
+		var ids []string
+		for _, f := range s.Findings {
+			ids = append(ids, f.ID)
+		}
+		return ids
+	}
+	if !slices.Equal(findingIDs(parallel), findingIDs(sequential)) {
+		t.Errorf("parallel findings = %v, sequential %v", findingIDs(parallel), findingIDs(sequential))
+	}
+
+	// Every check that ran reported its start and finish once
+	if !maps.Equal(started, finished) {
+		t.Errorf("started %v, finished %v", started, finished)
+	}
+	for _, c := range parallel.Scan.Checks {
+		if finished[c.Name] != 1 {
+			t.Errorf("%s finished %d times, want 1", c.Name, finished[c.Name])
+		}
+	}
+	if len(finished) != len(parallel.Scan.Checks) {
+		t.Errorf("%d checks reported progress, %d ran", len(finished), len(parallel.Scan.Checks))
+	}
+}
+
+func TestSummaryCheckSupported(t *testing.T) {
+	env := &RuntimeEnvironment{Containerized: true}
+	if IsKeychainSupported() && summaryCheckSupported(CheckKeychain, env) {
+		t.Error("the keychain check should not run in a container")
+	}
+	if !summaryCheckSupported(CheckBrowser, &RuntimeEnvironment{}) {
+		t.Error("the browser check runs everywhere")
+	}
+}
@@ -0,0 +1,366 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/host"
+)
+
+// Platform authenticators that can hold passkeys
+const (
+	PasskeyAuthenticatorWindowsHello   = "windows_hello"
+	PasskeyAuthenticatorICloudKeychain = "icloud_keychain"
+)
+
+// minPasskeyMacOS is the first macOS release that syncs passkeys in iCloud
+// Keychain
+const minPasskeyMacOS = 13
+
+// fidoUsagePage is the HID report descriptor item that declares the FIDO
+// Alliance usage page (0xF1D0), which every FIDO2 security key exposes
+var fidoUsagePage = []byte{0x06, 0xd0, 0xf1}
+
+// SecurityKey is a connected FIDO2 security key, a roaming authenticator
+// that libfido2 can use
+type SecurityKey struct {
+	// Device is the hidraw device node, e.g. /dev/hidraw3
+	Device    string `json:"device"`
+	Name      string `json:"name,omitempty"`
+	VendorID  string `json:"vendor_id,omitempty"`
+	ProductID string `json:"product_id,omitempty"`
+	// Accessible is true if the current user can open the device, which
+	// libfido2 needs; udev rules grant it to the logged-in user
+	Accessible bool `json:"accessible"`
+}
+
+// PasskeyResult reports whether the device can create and use passkeys:
+// with a platform FIDO2 authenticator (Windows Hello, iCloud Keychain) or a
+// connected security key
+type PasskeyResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string `json:"platform"`
+	// Authenticator is the platform authenticator, windows_hello or
+	// icloud_keychain. Linux has none.
+	Authenticator string `json:"authenticator,omitempty"`
+	// Available is true if the OS provides a platform authenticator for
+	// passkeys (Windows 10 1903 or later, macOS 13 or later)
+	Available bool `json:"available"`
+	// Configured is true if the platform authenticator is set up: Windows
+	// Hello is enrolled, or iCloud Keychain is syncing
+	Configured bool `json:"configured"`
+	// WebAuthnAPIVersion is the version of webauthn.dll (Windows)
+	WebAuthnAPIVersion int `json:"webauthn_api_version,omitempty"`
+	// KeychainSyncBlocked is true if a configuration profile turns off
+	// iCloud Keychain (macOS)
+	KeychainSyncBlocked bool `json:"keychain_sync_blocked,omitempty"`
+	// SecurityKeys lists the connected FIDO2 security keys (Linux)
+	SecurityKeys []SecurityKey `json:"security_keys"`
+	// Ready is true if the platform authenticator is configured or an
+	// accessible security key is connected
+	Ready bool        `json:"ready"`
+	Error *ProbeError `json:"error,omitempty"`
+}
+
+// IsPasskeySupported reports whether passkey readiness can be checked on
+// this platform
+func IsPasskeySupported() bool {
+	return runtime.GOOS == "linux" || runtime.GOOS == "darwin" || runtime.GOOS == "windows"
+}
+
+// GetPasskeyStatus reports whether a platform authenticator is available
+// and set up for passkeys: Windows Hello through webauthn.dll on Windows,
+// iCloud Keychain on macOS, and FIDO2 security keys found through hidraw on
+// Linux, which has no platform authenticator
+func GetPasskeyStatus() (*PasskeyResult, error) {
+	return GetPasskeyStatusContext(context.Background())
+}
+
+// GetPasskeyStatusContext is like GetPasskeyStatus but runs the probes with
+// ctx's deadline, command runner, and logger
+func GetPasskeyStatusContext(ctx context.Context) (*PasskeyResult, error) {
+	if result, ok, err := loadFixture[PasskeyResult](ctx, "passkeys"); ok {
+		return result, err
+	}
+	result := &PasskeyResult{Platform: runtime.GOOS, SecurityKeys: []SecurityKey{}}
+	switch runtime.GOOS {
+	case "linux":
+		result.SecurityKeys, result.Error = linuxSecurityKeys(ctx, os.DirFS("/"))
+	case "darwin":
+		result.Authenticator = PasskeyAuthenticatorICloudKeychain
+		readDarwinPasskeys(ctx, result)
+	case "windows":
+		result.Authenticator = PasskeyAuthenticatorWindowsHello
+		result.Error = readWindowsPasskeys(ctx, result)
+	default:
+		return nil, newProbeError(ctx, ErrUnsupportedPlatform, "passkeys", "passkeys are not checked on "+runtime.GOOS)
+	}
+	finishPasskeys(result)
+	return result, nil
+}
+
+// finishPasskeys derives readiness
+func finishPasskeys(r *PasskeyResult) {
+	r.Ready = r.Available && r.Configured
+	for _, key := range r.SecurityKeys {
+		if key.Accessible {
+			r.Ready = true
+		}
+	}
+}
+
+// readDarwinPasskeys checks the macOS release, whether iCloud Keychain is
+// turned on for the user, and whether a profile blocks it
+func readDarwinPasskeys(ctx context.Context, result *PasskeyResult) {
+	_, _, version, err := host.PlatformInformationWithContext(ctx)
+	if err == nil {
+		major, _, _ := strings.Cut(version, ".")
+		if n, err := strconv.Atoi(major); err == nil {
+			result.Available = n >= minPasskeyMacOS
+		}
+	}
+
+	if _, err := os.Stat(applicationAccessPrefs); err == nil {
+		if out, err := runCommand(ctx, "plutil", "-convert", "xml1", "-o", "-", applicationAccessPrefs); err == nil {
+			result.KeychainSyncBlocked = !parseKeychainSyncAllowed(out)
+		}
+	}
+
+	_, home, err := browserUser()
+	if err != nil {
+		result.Error = newProbeError(ctx, ErrProbeFailed, "passkeys", "cannot find the home directory: "+err.Error())
+		return
+	}
+	accounts := filepath.Join(home, "Library", "Preferences", "MobileMeAccounts.plist")
+	if _, err := os.Stat(accounts); err != nil {
+		// Not signed in to iCloud
+		return
+	}
+	out, err := runCommand(ctx, "plutil", "-convert", "xml1", "-o", "-", accounts)
+	if err != nil {
+		result.Error = classifyExecError(ctx, "plutil", err)
+		return
+	}
+	result.Configured = parseKeychainSyncEnabled(out) && !result.KeychainSyncBlocked
+}
+
+// parseKeychainSyncEnabled reports whether an iCloud account in a
+// MobileMeAccounts plist has the KEYCHAIN_SYNC service turned on
+func parseKeychainSyncEnabled(data []byte) bool {
+	v, err := decodePlist(data)
+	if err != nil {
+		return false
+	}
+	prefs, _ := v.(map[string]any)
+	for _, account := range plistDicts(prefs, "Accounts") {
+		for _, service := range plistDicts(account, "Services") {
+			if plistString(service, "Name") == "KEYCHAIN_SYNC" && plistBool(service, "Enabled") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseKeychainSyncAllowed reads allowCloudKeychainSync from a
+// com.apple.applicationaccess plist. iCloud Keychain is allowed unless a
+// profile sets it to false.
+func parseKeychainSyncAllowed(data []byte) bool {
+	v, err := decodePlist(data)
+	if err != nil {
+		return true
+	}
+	prefs, _ := v.(map[string]any)
+	allowed, ok := prefs["allowCloudKeychainSync"].(bool)
+	return !ok || allowed
+}
+
+// linuxSecurityKeys lists the hidraw devices whose report descriptor
+// declares the FIDO usage page
+func linuxSecurityKeys(ctx context.Context, root fs.FS) ([]SecurityKey, *ProbeError) {
+	keys := []SecurityKey{}
+	entries, err := fs.ReadDir(root, "sys/class/hidraw")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return keys, nil
+		}
+		return keys, classifyFileError(ctx, "/sys/class/hidraw", err)
+	}
+	for _, entry := range entries {
+		dir := path.Join("sys/class/hidraw", entry.Name(), "device")
+		desc, err := readFSFile(root, path.Join(dir, "report_descriptor"))
+		if err != nil || !bytes.Contains(desc, fidoUsagePage) {
+			continue
+		}
+		key := SecurityKey{Device: "/dev/" + entry.Name()}
+		if uevent, err := readFSFile(root, path.Join(dir, "uevent")); err == nil {
+			parseHIDUevent(uevent, &key)
+		}
+		if f, err := os.OpenFile(key.Device, os.O_RDWR, 0); err == nil { // #nosec G304 -- hidraw device node from sysfs
+			key.Accessible = true
+			_ = f.Close()
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// parseHIDUevent reads the device name and the vendor and product IDs from
+// a HID device's uevent, e.g. HID_ID=0003:00001050:00000407
+func parseHIDUevent(data []byte, key *SecurityKey) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		k, v, _ := strings.Cut(scanner.Text(), "=")
+		switch k {
+		case "HID_NAME":
+			key.Name = v
+		case "HID_ID":
+			parts := strings.Split(v, ":")
+			if len(parts) != 3 {
+				continue
+			}
+			vendor, err1 := strconv.ParseUint(parts[1], 16, 32)
+			product, err2 := strconv.ParseUint(parts[2], 16, 32)
+			if err1 == nil && err2 == nil {
+				key.VendorID = fmt.Sprintf("%04x", vendor)
+				key.ProductID = fmt.Sprintf("%04x", product)
+			}
+		}
+	}
+}
+
+// passkeyFindings returns a missing or unconfigured passkey authenticator
+// as a finding
+func passkeyFindings(r *PasskeyResult) []Finding {
+	if r.Ready {
+		return nil
+	}
+	if r.Error != nil {
+		return []Finding{unverifiedFinding("passkeys_unverified", CheckPasskeys, "Passkey authenticator", r.Error)}
+	}
+	var findings []Finding
+	add := func(id, title, severity, remediation string) {
+		findings = append(findings, Finding{
+			ID:                 id,
+			Title:              title,
+			Severity:           severity,
+			Check:              CheckPasskeys,
+			Remediation:        remediation,
+			RemediationCommand: remediationCommand(id),
+		})
+	}
+	switch {
+	case r.Authenticator != "" && !r.Available:
+		add("passkeys_unsupported", T("The OS has no platform authenticator for passkeys"), SeverityMedium,
+			T("Update to Windows 10 1903 or macOS 13 or later"))
+	case r.KeychainSyncBlocked:
+		add("passkeys_keychain_blocked", T("iCloud Keychain, which stores passkeys, is turned off by a configuration profile"), SeverityLow,
+			T("Allow iCloud Keychain in the profile, or issue FIDO2 security keys"))
+	case r.Authenticator == PasskeyAuthenticatorWindowsHello:
+		add("passkeys_not_configured", T("Windows Hello is not set up, so passkeys cannot be created"), SeverityLow,
+			T("Set up Windows Hello in Settings > Accounts > Sign-in options"))
+	case r.Authenticator == PasskeyAuthenticatorICloudKeychain:
+		add("passkeys_not_configured", T("iCloud Keychain is off, so passkeys cannot be created"), SeverityLow,
+			T("Turn on iCloud Keychain in System Settings > Apple Account > iCloud > Passwords & Keychain"))
+	default:
+		add("passkeys_no_security_key", T("No FIDO2 security key is connected and accessible"), SeverityLow,
+			T("Connect a FIDO2 security key and install the libfido2 udev rules"))
+	}
+	return findings
+}
+
+// FormatPasskeyTable formats passkey readiness as a colored table
+func FormatPasskeyTable(result *PasskeyResult) string {
+	return FormatPasskeyTableWith(CurrentStyler(), result)
+}
+
+// FormatPasskeyTableWith is like FormatPasskeyTable but styles the table
+// with st
+func FormatPasskeyTableWith(st Styler, result *PasskeyResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconKey + " Passkeys"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n")
+		if result.Error.Hint != "" {
+			sb.WriteString(st.Muted("  " + result.Error.Hint))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.BoldText("Ready: "))
+	sb.WriteString(st.BoolToStatusColored(result.Ready))
+	sb.WriteString("\n\n")
+
+	if result.Authenticator != "" {
+		sb.WriteString(st.TableTop(24, 30))
+		sb.WriteString("\n")
+		row := func(label, value string) {
+			sb.WriteString(st.TableRowColored(PadRight(label, 24), PadRight(value, 30)))
+			sb.WriteString("\n")
+		}
+		name := "Windows Hello"
+		if result.Authenticator == PasskeyAuthenticatorICloudKeychain {
+			name = "iCloud Keychain"
+		}
+		row("Platform Authenticator", name)
+		row("Available", st.BoolToStatusColored(result.Available))
+		row("Configured", st.BoolToStatusColored(result.Configured))
+		if result.WebAuthnAPIVersion > 0 {
+			row("WebAuthn API", strconv.Itoa(result.WebAuthnAPIVersion))
+		}
+		if result.KeychainSyncBlocked {
+			row("Profile", st.Warning("iCloud Keychain blocked"))
+		}
+		sb.WriteString(st.TableBottom(24, 30))
+		sb.WriteString("\n")
+	}
+
+	if result.Platform == "linux" {
+		if len(result.SecurityKeys) == 0 {
+			sb.WriteString(st.Muted("No FIDO2 security keys connected"))
+			sb.WriteString("\n")
+			return sb.String()
+		}
+		sb.WriteString(st.TableTop(14, 36, 12))
+		sb.WriteString("\n")
+		sb.WriteString(st.TableRowColored(st.Header(PadRight("Device", 14)), st.Header(PadRight("Security Key", 36)), st.Header(PadRight("Accessible", 12))))
+		sb.WriteString("\n")
+		sb.WriteString(st.TableSeparator(14, 36, 12))
+		sb.WriteString("\n")
+		for _, key := range result.SecurityKeys {
+			name := key.Name
+			if key.VendorID != "" {
+				name += st.Muted(" (" + key.VendorID + ":" + key.ProductID + ")")
+			}
+			sb.WriteString(st.TableRowColored(PadRight(key.Device, 14), PadRight(name, 36), PadRight(st.BoolToStatusColored(key.Accessible), 12)))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(st.TableBottom(14, 36, 12))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// FormatPasskeys formats passkey readiness in the specified format
+func FormatPasskeys(result *PasskeyResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatPasskeyTableWith(st, result)
+	}, format)
+}
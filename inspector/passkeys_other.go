@@ -0,0 +1,10 @@
+//go:build !windows
+
+package inspector
+
+import "context"
+
+// readWindowsPasskeys is only implemented on Windows
+func readWindowsPasskeys(ctx context.Context, result *PasskeyResult) *ProbeError {
+	return nil
+}
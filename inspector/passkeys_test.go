@@ -0,0 +1,85 @@
+package inspector
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLinuxSecurityKeys(t *testing.T) {
+	// A YubiKey's FIDO interface and a keyboard
+	fido := []byte{0x06, 0xd0, 0xf1, 0x09, 0x01, 0xa1, 0x01, 0x09, 0x20, 0x15, 0x00}
+	keyboard := []byte{0x05, 0x01, 0x09, 0x06, 0xa1, 0x01, 0x05, 0x07}
+	fsys := fstest.MapFS{
+		"sys/class/hidraw/hidraw0/device/report_descriptor": {Data: keyboard},
+		"sys/class/hidraw/hidraw0/device/uevent":            {Data: []byte("HID_NAME=Logitech USB Keyboard\n")},
+		"sys/class/hidraw/hidraw1/device/report_descriptor": {Data: fido},
+		"sys/class/hidraw/hidraw1/device/uevent":            {Data: []byte("DRIVER=hid-generic\nHID_ID=0003:00001050:00000407\nHID_NAME=Yubico YubiKey OTP+FIDO+CCID\n")},
+	}
+	keys, err := linuxSecurityKeys(context.Background(), fsys)
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("linuxSecurityKeys = %+v, %v, want one key", keys, err)
+	}
+	if k := keys[0]; k.Device != "/dev/hidraw1" || k.Name != "Yubico YubiKey OTP+FIDO+CCID" || k.VendorID != "1050" || k.ProductID != "0407" {
+		t.Errorf("key = %+v", k)
+	}
+
+	if keys, err := linuxSecurityKeys(context.Background(), fstest.MapFS{}); err != nil || len(keys) != 0 {
+		t.Errorf("without hidraw, linuxSecurityKeys = %+v, %v", keys, err)
+	}
+}
+
+func TestParseKeychainSync(t *testing.T) {
+	plist := func(body string) []byte {
+		return []byte(`<?xml version="1.0" encoding="UTF-8"?><plist version="1.0"><dict>` + body + `</dict></plist>`)
+	}
+	accounts := func(enabled string) []byte {
+		return plist(`<key>Accounts</key><array><dict><key>AccountID</key><string>user@icloud.com</string><key>Services</key><array>` +
+			`<dict><key>Name</key><string>MOBILE_DOCUMENTS</string><key>Enabled</key><true/></dict>` +
+			`<dict><key>Name</key><string>KEYCHAIN_SYNC</string><key>Enabled</key><` + enabled + `/></dict>` +
+			`</array></dict></array>`)
+	}
+	if !parseKeychainSyncEnabled(accounts("true")) {
+		t.Error("KEYCHAIN_SYNC enabled should turn on iCloud Keychain")
+	}
+	if parseKeychainSyncEnabled(accounts("false")) || parseKeychainSyncEnabled(plist("")) {
+		t.Error("iCloud Keychain should be off without an enabled KEYCHAIN_SYNC service")
+	}
+
+	if parseKeychainSyncAllowed(plist(`<key>allowCloudKeychainSync</key><false/>`)) {
+		t.Error("allowCloudKeychainSync false should block iCloud Keychain")
+	}
+	if !parseKeychainSyncAllowed(plist(`<key>allowCamera</key><false/>`)) {
+		t.Error("a profile without allowCloudKeychainSync should allow iCloud Keychain")
+	}
+}
+
+func TestPasskeyFindings(t *testing.T) {
+	tests := []struct {
+		name   string
+		result PasskeyResult
+		want   string
+	}{
+		{"ready", PasskeyResult{Authenticator: PasskeyAuthenticatorWindowsHello, Available: true, Configured: true}, ""},
+		{"old windows", PasskeyResult{Authenticator: PasskeyAuthenticatorWindowsHello}, "passkeys_unsupported"},
+		{"hello not set up", PasskeyResult{Authenticator: PasskeyAuthenticatorWindowsHello, Available: true}, "passkeys_not_configured"},
+		{"keychain blocked", PasskeyResult{Authenticator: PasskeyAuthenticatorICloudKeychain, Available: true, KeychainSyncBlocked: true}, "passkeys_keychain_blocked"},
+		{"security key", PasskeyResult{SecurityKeys: []SecurityKey{{Device: "/dev/hidraw1", Accessible: true}}}, ""},
+		{"inaccessible security key", PasskeyResult{SecurityKeys: []SecurityKey{{Device: "/dev/hidraw1"}}}, "passkeys_no_security_key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finishPasskeys(&tt.result)
+			findings := passkeyFindings(&tt.result)
+			if tt.want == "" {
+				if !tt.result.Ready || len(findings) != 0 {
+					t.Errorf("result = %+v, findings = %+v, want ready", tt.result, findings)
+				}
+				return
+			}
+			if tt.result.Ready || len(findings) != 1 || findings[0].ID != tt.want {
+				t.Errorf("findings = %+v, want %s", findings, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+//go:build windows
+
+package inspector
+
+import (
+	"context"
+	"syscall"
+	"unsafe"
+)
+
+// Windows WebAuthn API (webauthn.dll), present since Windows 10 1903
+var (
+	webauthn                                                  = syscall.NewLazyDLL("webauthn.dll")
+	procWebAuthNGetApiVersionNumber                           = webauthn.NewProc("WebAuthNGetApiVersionNumber")
+	procWebAuthNIsUserVerifyingPlatformAuthenticatorAvailable = webauthn.NewProc("WebAuthNIsUserVerifyingPlatformAuthenticatorAvailable")
+)
+
+// readWindowsPasskeys asks the WebAuthn API whether Windows Hello can act as
+// a platform authenticator. It is available once the user has set up a
+// Windows Hello PIN, face, or fingerprint.
+func readWindowsPasskeys(ctx context.Context, result *PasskeyResult) *ProbeError {
+	if webauthn.Load() != nil || procWebAuthNGetApiVersionNumber.Find() != nil {
+		// Windows before 1903 has no WebAuthn API
+		return nil
+	}
+	version, _, _ := procWebAuthNGetApiVersionNumber.Call()
+	result.WebAuthnAPIVersion = int(version) // #nosec G115 -- small API version number
+	result.Available = true
+
+	if procWebAuthNIsUserVerifyingPlatformAuthenticatorAvailable.Find() != nil {
+		return nil
+	}
+	var available int32
+	hr, _, _ := procWebAuthNIsUserVerifyingPlatformAuthenticatorAvailable.Call(uintptr(unsafe.Pointer(&available)))
+	if uint32(hr) != 0 {
+		return newProbeError(ctx, ErrProbeFailed, "passkeys", "WebAuthNIsUserVerifyingPlatformAuthenticatorAvailable failed")
+	}
+	result.Configured = available != 0
+	return nil
+}
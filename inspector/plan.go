@@ -0,0 +1,119 @@
+package inspector
+
+import (
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// AccessPlan lists what a check touches on this platform: the external
+// commands it may run, the files and devices it may read, and the OS APIs it
+// may call. Entries with placeholders (<user>, <device>) are expanded at run
+// time. Plans are declared next to each platform's probes and are checked
+// against the commands probes actually run in tests.
+type AccessPlan struct {
+	Check    string   `json:"check"`
+	Commands []string `json:"commands,omitempty"`
+	Files    []string `json:"files,omitempty"`
+	APIs     []string `json:"apis,omitempty"`
+}
+
+// DryRunResult lists the access plans for the checks a command would run
+type DryRunResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string       `json:"platform"`
+	Checks   []AccessPlan `json:"checks"`
+	// Skipped lists requested checks that are disabled or unsupported here
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// environmentProbes are probed by summaries besides their checks, to
+// describe where they run; the check selection does not turn them off
+var environmentProbes = []string{CheckWSLHost, CheckCloud}
+
+// EnvironmentProbes returns the environment probes that touch anything on
+// this platform, for the access plan of a summary
+func EnvironmentProbes() []string {
+	var probes []string
+	for _, id := range environmentProbes {
+		if _, ok := checkAccessPlans[id]; ok {
+			probes = append(probes, id)
+		}
+	}
+	return probes
+}
+
+// DryRun returns the access plans for the given checks without running
+// anything. Checks that are disabled or unsupported on this platform are
+// listed as skipped.
+func DryRun(checks []string) *DryRunResult {
+	result := &DryRunResult{Platform: runtime.GOOS, Checks: []AccessPlan{}}
+	for _, id := range checks {
+		plan, ok := checkAccessPlans[id]
+		if !ok || !CheckEnabled(id) && !slices.Contains(environmentProbes, id) {
+			result.Skipped = append(result.Skipped, id)
+			continue
+		}
+		plan.Check = id
+		result.Checks = append(result.Checks, plan)
+	}
+	return result
+}
+
+// FormatDryRunTable formats access plans as a colored listing
+func FormatDryRunTable(result *DryRunResult) string {
+	return FormatDryRunTableWith(CurrentStyler(), result)
+}
+
+// FormatDryRunTableWith is like FormatDryRunTable but styles the table with
+// st
+func FormatDryRunTableWith(st Styler, result *DryRunResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconInfo + " Dry Run: Planned System Access"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n")
+
+	if len(result.Checks) == 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted("This command runs no security checks"))
+		sb.WriteString("\n")
+	}
+
+	for _, plan := range result.Checks {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText(plan.Check))
+		sb.WriteString("\n")
+		writePlanSection(st, &sb, "Commands", plan.Commands)
+		writePlanSection(st, &sb, "Files", plan.Files)
+		writePlanSection(st, &sb, "APIs", plan.APIs)
+	}
+
+	if len(result.Skipped) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted("Skipped (disabled or unsupported): " + strings.Join(result.Skipped, ", ")))
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// writePlanSection writes one labeled list of a plan
+func writePlanSection(st Styler, sb *strings.Builder, label string, entries []string) {
+	if len(entries) == 0 {
+		return
+	}
+	sb.WriteString("  " + st.Muted(label+":") + "\n")
+	for _, e := range entries {
+		sb.WriteString("    " + IconArrow + " " + e + "\n")
+	}
+}
+
+// FormatDryRun formats access plans in the specified format
+func FormatDryRun(result *DryRunResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatDryRunTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,114 @@
+//go:build darwin
+
+package inspector
+
+// checkAccessPlans declares what each check touches on macOS
+var checkAccessPlans = map[string]AccessPlan{
+	CheckTPM: {
+		APIs: []string{
+			"sysctl hw.optional.arm64",
+			"SecKeyCreateRandomKey (ephemeral Secure Enclave key, never stored)",
+		},
+	},
+	CheckSecureBoot: {
+		Commands: []string{
+			"bputil -d",
+			"system_profiler SPiBridgeDataType",
+			"nvram -x 94b73556-2197-4702-82a8-3e1337dafbfb:AppleSecureBootPolicy",
+			"nvram 94b73556-2197-4702-82a8-3e1337dafbfb:AppleSecureBootPolicy",
+		},
+		APIs: []string{
+			"sysctl hw.optional.arm64",
+			"IORegistry IODeviceTree:/options (AppleSecureBootPolicy)",
+		},
+	},
+	CheckBootOrder: {
+		Commands: []string{
+			"firmwarepasswd -check (Intel)",
+			"system_profiler SPiBridgeDataType",
+		},
+		APIs: []string{
+			"sysctl hw.optional.arm64",
+		},
+	},
+	CheckEncryption: {
+		Commands: []string{
+			"diskutil apfs list -plist",
+			"diskutil info -plist <physical store>",
+			"diskutil info /",
+			"mount",
+			"fdesetup status",
+		},
+	},
+	CheckBiometrics: {
+		Commands: []string{
+			"dscl . -list /Users UniqueID (--all-users)",
+			"bioutil -c -s (--all-users)",
+		},
+		APIs: []string{
+			"LAContext canEvaluatePolicy (LocalAuthentication)",
+		},
+	},
+	CheckBrowser: {
+		Commands: []string{
+			"plutil -convert xml1 -o - /Applications/Safari.app/Contents/Info.plist",
+			"plutil -convert xml1 -o - ~/Library/Containers/com.apple.Safari/Data/Library/Preferences/com.apple.Safari.plist",
+		},
+		Files: []string{
+			"~/Library/Application Support/Google/Chrome/{Last Version,<profile>/Preferences,<profile>/Secure Preferences}",
+			"~/Library/Application Support/Microsoft Edge/{Last Version,<profile>/Preferences,<profile>/Secure Preferences}",
+			"~/Library/Application Support/Firefox/Profiles/<profile>/{prefs.js,compatibility.ini,extensions.json}",
+		},
+	},
+	CheckDocker: {
+		Commands: []string{
+			"docker info --format {{json .}}",
+			"docker ps --quiet",
+			"docker inspect <container>...",
+		},
+		Files: []string{
+			"~/.docker/daemon.json",
+			"~/Library/Group Containers/group.com.docker/{settings-store.json,settings.json}",
+		},
+	},
+	CheckUptime: {
+		Commands: []string{
+			"softwareupdate --list --no-scan",
+		},
+		APIs: []string{
+			"sysctl kern.boottime",
+		},
+	},
+	CheckFirmware: {
+		Commands: []string{
+			"system_profiler SPHardwareDataType -json",
+		},
+	},
+	CheckUSBStorage: {
+		Commands: []string{
+			"system_profiler SPUSBDataType -json",
+			`plutil -convert xml1 -o - "/Library/Managed Preferences/com.apple.systemuiserver.plist"`,
+		},
+	},
+	CheckKeychain: {
+		Commands: []string{
+			"security show-keychain-info ~/Library/Keychains/login.keychain-db",
+		},
+		Files: []string{
+			"/Applications, ~/Applications (directory listings)",
+			"Browser profiles, as for the browser check (extensions)",
+		},
+	},
+	CheckCloud: {
+		APIs: []string{
+			"sysctl hw.model",
+			"HTTP to the instance metadata service at 169.254.169.254 (AWS IMDSv2 token, identity document, and a tokenless instance-id request; Azure /metadata/instance; GCE /computeMetadata/v1/instance/), when the system vendor names a cloud or OMNITRUST_CLOUD_PROBE=true",
+		},
+	},
+	CheckPasskeys: {
+		Commands: []string{
+			"plutil -convert xml1 -o - ~/Library/Preferences/MobileMeAccounts.plist",
+			`plutil -convert xml1 -o - "/Library/Managed Preferences/com.apple.applicationaccess.plist"`,
+		},
+	},
+}
@@ -0,0 +1,156 @@
+//go:build linux
+
+package inspector
+
+// checkAccessPlans declares what each check touches on Linux
+var checkAccessPlans = map[string]AccessPlan{
+	CheckTPM: {
+		Files: []string{
+			"/sys/class/tpm/tpm*/tpm_version_major",
+			"/sys/class/tpm/tpm*/tpm_version_minor",
+			"/sys/class/tpm/tpm*/device/vendor",
+			"/dev/tpmrm<n> or /dev/tpm<n> (read-only TPM2 queries)",
+			"$OMNITRUST_TPM_CA_DIR/* (if set)",
+			"/sys/class/dmi/id/* (hypervisor detection for tpm_kind)",
+		},
+		APIs: []string{
+			"TPM2_GetCapability (properties, algorithms)",
+			"TPM2_NV_Read (EK certificate)",
+			"CPUID (hypervisor detection for tpm_kind)",
+		},
+	},
+	CheckSecureBoot: {
+		Files: []string{
+			"/sys/firmware/efi",
+			"/sys/firmware/efi/efivars/SecureBoot-8be4df61-93ca-11d2-aa0d-00e098032b8c",
+			"/sys/firmware/efi/efivars/SetupMode-8be4df61-93ca-11d2-aa0d-00e098032b8c",
+		},
+	},
+	CheckBootOrder: {
+		Files: []string{
+			"/sys/firmware/efi",
+			"/sys/firmware/efi/efivars/BootOrder-8be4df61-93ca-11d2-aa0d-00e098032b8c",
+			"/sys/firmware/efi/efivars/BootCurrent-8be4df61-93ca-11d2-aa0d-00e098032b8c",
+			"/sys/firmware/efi/efivars/Boot<nnnn>-8be4df61-93ca-11d2-aa0d-00e098032b8c",
+		},
+	},
+	CheckEncryption: {
+		Commands: []string{
+			"dmsetup table <mapping>",
+			"findmnt -n -o TARGET <device>",
+			"lsblk -J -o NAME,TYPE,FSTYPE,MOUNTPOINT",
+			"cryptsetup isLuks <device> (OMNITRUST_LUKS_SCAN=cryptsetup)",
+		},
+		Files: []string{
+			"/dev/mapper/*",
+			"/etc/crypttab",
+			"/dev/sd*, /dev/nvme* (OMNITRUST_LUKS_SCAN=cryptsetup)",
+		},
+	},
+	CheckBiometrics: {
+		Files: []string{
+			"/etc/pam.d/* (auth stacks of login, sudo, display managers, and lock screens)",
+			"<howdy dir>/config.ini",
+			"<howdy dir>/models/<user>.dat",
+			"/etc/passwd, /etc/login.defs (--all-users)",
+		},
+		APIs: []string{
+			"D-Bus system bus: net.reactivated.Fprint Manager.GetDevices, Device.ListEnrolledFingers(<user>)",
+		},
+	},
+	CheckBrowser: {
+		Files: []string{
+			"~/.config/google-chrome/{Last Version,<profile>/Preferences,<profile>/Secure Preferences}",
+			"~/.config/microsoft-edge/{Last Version,<profile>/Preferences,<profile>/Secure Preferences}",
+			"~/.mozilla/firefox/<profile>/{prefs.js,compatibility.ini,extensions.json} (and the snap profile directory)",
+		},
+	},
+	CheckFilesystem: {
+		Files: []string{
+			"/bin, /sbin, /usr/bin, /usr/sbin, /usr/local/{bin,sbin}, /usr/libexec, /usr/lib, /opt (directory listings and file modes, 3 levels deep; $OMNITRUST_FS_AUDIT_PATHS if set)",
+			"$PATH directories and their parents (file modes)",
+		},
+	},
+	CheckKeychain: {
+		Files: []string{
+			"~/.local/share/keyrings, ~/.local/share/kwalletd (existence)",
+			"/etc/pam.d/{login,gdm-password,sddm,lightdm,kde} and the files they include",
+			"/opt, /usr/bin, /snap, /var/lib/flatpak/app (password manager install locations)",
+			"Browser profiles, as for the browser check (extensions)",
+		},
+	},
+	CheckDocker: {
+		Commands: []string{
+			"docker info --format {{json .}}",
+			"docker ps --quiet",
+			"docker inspect <container>...",
+		},
+		Files: []string{
+			"/etc/docker/daemon.json (and ~/.config/docker/daemon.json for rootless Docker)",
+			"/etc/systemd/system/docker.service{,.d/*.conf}, /lib/systemd/system/docker.service, /usr/lib/systemd/system/docker.service",
+		},
+	},
+	CheckKubelet: {
+		Files: []string{
+			"/proc/<pid>/cmdline (to find the kubelet and its flags)",
+			"/var/lib/kubelet/config.yaml (or the kubelet's --config)",
+			"/etc/kubernetes/kubelet.conf (or the kubelet's --kubeconfig; file mode only)",
+			"/run/containerd/containerd.sock, /run/crio/crio.sock, and the kubelet's container runtime endpoint (file mode only)",
+			"all of the above below $OMNITRUST_HOST_ROOT if set",
+		},
+	},
+	CheckUptime: {
+		Commands: []string{
+			"needs-restarting -r (Fedora, RHEL)",
+		},
+		Files: []string{
+			"/proc/stat (boot time)",
+			"/var/run/reboot-required, /var/run/reboot-required.pkgs",
+			"/proc/sys/kernel/osrelease, /lib/modules/<release>",
+		},
+	},
+	CheckFirmware: {
+		Commands: []string{
+			"fwupdmgr get-updates --json",
+			"fwupdmgr security --json",
+		},
+		Files: []string{
+			"/sys/class/dmi/id/bios_vendor, bios_version, bios_date",
+		},
+	},
+	CheckManagementEngine: {
+		Files: []string{
+			"/sys/class/mei/mei0/fw_ver, fw_status",
+			"/sys/bus/mei/devices/*/uuid",
+			"/sys/bus/pci/drivers/ccp/*/{fused_part,debug_lock_on,bootloader_version}",
+			"/proc/net/tcp, /proc/net/tcp6",
+		},
+	},
+	CheckCloud: {
+		Files: []string{
+			"/sys/class/dmi/id/{sys_vendor,product_name,bios_vendor,bios_version,chassis_asset_tag}",
+			"/sys/class/tpm/tpm0 (vTPM)",
+			"/dev/tdx_guest, /dev/tdx-guest, /dev/sev-guest, /dev/sev (confidential computing)",
+		},
+		APIs: []string{
+			"HTTP to the instance metadata service at 169.254.169.254 (AWS IMDSv2 token, identity document, and a tokenless instance-id request; Azure /metadata/instance; GCE /computeMetadata/v1/instance/), when the system vendor names a cloud or OMNITRUST_CLOUD_PROBE=true",
+		},
+	},
+	CheckWSLHost: {
+		Commands: []string{
+			"powershell.exe -NoProfile -NonInteractive -Command <Secure Boot registry value, C: BitLocker protection, and TPM Plug and Play IDs> (WSL with interop)",
+		},
+		Files: []string{
+			"/proc/sys/kernel/osrelease",
+			"/proc/sys/fs/binfmt_misc/WSLInterop",
+		},
+	},
+	CheckUSBStorage: {
+		Files: []string{
+			"/proc/modules",
+			"/etc/modprobe.d/*.conf, /run/modprobe.d/*.conf, /usr/lib/modprobe.d/*.conf, /lib/modprobe.d/*.conf",
+			"/etc/usbguard/usbguard-daemon.conf",
+			"/sys/bus/usb/devices/*/{idVendor,idProduct,manufacturer,product,bDeviceClass,bInterfaceClass}",
+		},
+	},
+}
@@ -0,0 +1,83 @@
+package inspector
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingRunner records command lines and fails them like faultRunner
+type recordingRunner struct {
+	faultRunner
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recordingRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	r.mu.Lock()
+	r.calls = append(r.calls, strings.Join(append([]string{name}, args...), " "))
+	r.mu.Unlock()
+	return r.faultRunner.Output(ctx, name, args...)
+}
+
+func TestDryRun(t *testing.T) {
+	t.Setenv(DisableChecksEnv, CheckBiometrics)
+
+	result := DryRun(AllChecks)
+	for _, plan := range result.Checks {
+		if plan.Check == CheckBiometrics {
+			t.Error("disabled check should not be planned")
+		}
+		if len(plan.Commands)+len(plan.Files)+len(plan.APIs) == 0 {
+			t.Errorf("%s: empty access plan", plan.Check)
+		}
+	}
+	found := false
+	for _, id := range result.Skipped {
+		found = found || id == CheckBiometrics
+	}
+	if !found {
+		t.Errorf("Skipped = %v, want %s", result.Skipped, CheckBiometrics)
+	}
+}
+
+func TestDryRun_EnvironmentProbes(t *testing.T) {
+	t.Setenv(OnlyChecksEnv, CheckTPM)
+	t.Setenv(DisableChecksEnv, "")
+
+	// The check selection does not turn the environment probes off
+	result := DryRun(EnvironmentProbes())
+	if len(result.Checks) != len(EnvironmentProbes()) || len(result.Skipped) > 0 {
+		t.Errorf("DryRun = %+v, want every environment probe planned", result)
+	}
+}
+
+// TestAccessPlans_CoverCommands runs each probe and checks that every
+// command it runs is declared in its access plan
+func TestAccessPlans_CoverCommands(t *testing.T) {
+	probes := checkProbes()
+	// The environment probes run outside the check registry
+	if _, ok := checkAccessPlans[CheckWSLHost]; ok {
+		probes = append(probes, checkProbe{check: CheckWSLHost, run: func(ctx context.Context) (any, error) { return wslHostHints(ctx), nil }})
+	}
+	for _, probe := range probes {
+		// Malformed output lets probes past LookPath so every command is tried
+		rec := &recordingRunner{faultRunner: faultRunner{fault: FaultMalformedOutput}}
+		prev := SetCommandRunner(rec)
+		_, _ = probe.run(context.Background())
+		SetCommandRunner(prev)
+
+		plan := checkAccessPlans[probe.check]
+		for _, call := range rec.calls {
+			tool := strings.Fields(call)[0]
+			declared := false
+			for _, c := range plan.Commands {
+				declared = declared || strings.Fields(c)[0] == tool
+			}
+			if !declared {
+				t.Errorf("%s runs %q, which its access plan does not declare", probe.check, call)
+			}
+		}
+	}
+}
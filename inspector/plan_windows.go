@@ -0,0 +1,130 @@
+//go:build windows
+
+package inspector
+
+// checkAccessPlans declares what each check touches on Windows
+var checkAccessPlans = map[string]AccessPlan{
+	CheckTPM: {
+		Files: []string{
+			"$OMNITRUST_TPM_CA_DIR/* (if set)",
+		},
+		APIs: []string{
+			`WMI root\cimv2\Security\MicrosoftTpm: Win32_Tpm`,
+			"TBS (TPM Base Services): TPM2_GetCapability, TPM2_NV_Read",
+			`WMI root\cimv2: Win32_ComputerSystem, CPUID (hypervisor detection for tpm_kind)`,
+		},
+	},
+	CheckSecureBoot: {
+		APIs: []string{
+			"GetFirmwareEnvironmentVariableW (EFI SecureBoot variable)",
+		},
+	},
+	CheckBootOrder: {
+		APIs: []string{
+			"GetFirmwareEnvironmentVariableW (EFI BootOrder, BootCurrent, and Boot#### variables)",
+		},
+	},
+	CheckEncryption: {
+		APIs: []string{
+			`WMI root\cimv2\Security\MicrosoftVolumeEncryption: Win32_EncryptableVolume`,
+		},
+	},
+	CheckBiometrics: {
+		Files: []string{
+			`%SystemRoot%\ServiceProfiles\LocalService\AppData\Local\Microsoft\Ngc (existence only)`,
+		},
+		APIs: []string{
+			"WinBioEnumBiometricUnits, WinBioOpenSession, WinBioEnumEnrollments (fingerprint, facial features)",
+			`WMI root\cimv2: Win32_PnPEntity (Camera, Image, and Biometric classes)`,
+			`Registry HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Authentication (PIN credential provider, NgcPin)`,
+			`Registry HKLM\SOFTWARE\Microsoft\Windows NT\CurrentVersion\ProfileList, LookupAccountSid (--all-users)`,
+		},
+	},
+	CheckDefender: {
+		APIs: []string{
+			`WMI root\Microsoft\Windows\Defender: MSFT_MpComputerStatus, MSFT_MpPreference`,
+		},
+	},
+	CheckUAC: {
+		APIs: []string{
+			`Registry HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Policies\System (EnableLUA, ConsentPromptBehaviorAdmin, PromptOnSecureDesktop, FilterAdministratorToken)`,
+			`Registry HKLM\SOFTWARE\Policies\Microsoft\Windows\System, HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Explorer (SmartScreen for apps)`,
+			`Registry HKLM and HKCU\SOFTWARE\Policies\Microsoft\Edge (SmartScreen in Edge)`,
+		},
+	},
+	CheckLegacyProtocols: {
+		APIs: []string{
+			`Registry HKLM\SYSTEM\CurrentControlSet\Services: LanmanServer\Parameters (SMB1), srv, mrxsmb10 (SMBv1 drivers)`,
+			`Registry HKLM\SYSTEM\CurrentControlSet\Control\Lsa (LmCompatibilityLevel)`,
+			`Registry HKLM\SOFTWARE\Policies\Microsoft\Windows NT\DNSClient (EnableMulticast)`,
+			`Registry HKLM\SYSTEM\CurrentControlSet\Services\NetBT\Parameters\Interfaces (NetbiosOptions)`,
+		},
+	},
+	CheckBrowser: {
+		Files: []string{
+			`%LOCALAPPDATA%\Google\Chrome\User Data\{Last Version,<profile>\Preferences,<profile>\Secure Preferences}`,
+			`%LOCALAPPDATA%\Microsoft\Edge\User Data\{Last Version,<profile>\Preferences,<profile>\Secure Preferences}`,
+			`%APPDATA%\Mozilla\Firefox\Profiles\<profile>\{prefs.js,compatibility.ini,extensions.json}`,
+		},
+	},
+	CheckDocker: {
+		Commands: []string{
+			"docker info --format {{json .}}",
+			"docker ps --quiet",
+			"docker inspect <container>...",
+		},
+		Files: []string{
+			`%ProgramData%\docker\config\daemon.json`,
+			`%APPDATA%\Docker\{settings-store.json,settings.json}`,
+		},
+	},
+	CheckUptime: {
+		APIs: []string{
+			"GetTickCount64 (uptime)",
+			`Registry HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Component Based Servicing (RebootPending subkey)`,
+			`Registry HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\WindowsUpdate\Auto Update (RebootRequired subkey)`,
+		},
+	},
+	CheckFirmware: {
+		APIs: []string{
+			`Registry HKLM\HARDWARE\DESCRIPTION\System\BIOS (BIOSVendor, BIOSVersion, BIOSReleaseDate)`,
+			`Registry HKLM\HARDWARE\UEFI\ESRT (firmware resources and their last capsule update)`,
+		},
+	},
+	CheckManagementEngine: {
+		APIs: []string{
+			`WMI root\cimv2: Win32_PnPEntity (Intel Management Engine Interface, AMD PSP)`,
+			`WMI root\StandardCimv2: MSFT_NetTCPConnection (listening sockets on the AMT ports)`,
+		},
+	},
+	CheckUSBStorage: {
+		APIs: []string{
+			`Registry HKLM\SYSTEM\CurrentControlSet\Services\USBSTOR (Start)`,
+			`Registry HKLM\SOFTWARE\Policies\Microsoft\Windows\RemovableStorageDevices (Deny_All, removable disk Deny_Read and Deny_Write)`,
+			`WMI root\cimv2: Win32_PnPEntity (USB devices)`,
+		},
+	},
+	CheckKeychain: {
+		Files: []string{
+			"Browser profiles, as for the browser check (extensions)",
+		},
+		APIs: []string{
+			`Registry HKLM\SYSTEM\CurrentControlSet\Services\VaultSvc (Start)`,
+			`Registry HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall, HKCU\SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall (DisplayName, InstallLocation)`,
+			"CryptProtectData, CryptUnprotectData (DPAPI round trip)",
+			"CredEnumerateW (count of saved credentials)",
+		},
+	},
+	CheckCloud: {
+		APIs: []string{
+			`WMI root\cimv2: Win32_ComputerSystem (system vendor)`,
+			"HTTP to the instance metadata service at 169.254.169.254 (AWS IMDSv2 token, identity document, and a tokenless instance-id request; Azure /metadata/instance; GCE /computeMetadata/v1/instance/), when the system vendor names a cloud or OMNITRUST_CLOUD_PROBE=true",
+		},
+	},
+	CheckPasskeys: {
+		APIs: []string{
+			"webauthn.dll WebAuthNGetApiVersionNumber",
+			"webauthn.dll WebAuthNIsUserVerifyingPlatformAuthenticatorAvailable",
+		},
+	},
+}
@@ -0,0 +1,206 @@
+package inspector
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// decodePlist decodes an XML property list, as produced by `diskutil -plist`
+// and `nvram -x`, into Go values: dictionaries become map[string]any, arrays
+// []any, and scalars string, int64, float64, bool, []byte, or time.Time.
+func decodePlist(data []byte) (any, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("plist: no <plist> element")
+			}
+			return nil, fmt.Errorf("plist: %w", err)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			if start.Name.Local != "plist" {
+				return nil, fmt.Errorf("plist: unexpected root element <%s>", start.Name.Local)
+			}
+			value, err := decodePlistElement(dec, nil)
+			if err != nil {
+				return nil, err
+			}
+			return value, nil
+		}
+	}
+}
+
+// decodePlistElement decodes the next value element. If start is nil the
+// next start element is read from the decoder first.
+func decodePlistElement(dec *xml.Decoder, start *xml.StartElement) (any, error) {
+	if start == nil {
+		for {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, fmt.Errorf("plist: %w", err)
+			}
+			if s, ok := tok.(xml.StartElement); ok {
+				start = &s
+				break
+			}
+			if _, ok := tok.(xml.EndElement); ok {
+				return nil, errors.New("plist: missing value")
+			}
+		}
+	}
+
+	switch start.Name.Local {
+	case "dict":
+		return decodePlistDict(dec)
+	case "array":
+		return decodePlistArray(dec)
+	case "true", "false":
+		if err := dec.Skip(); err != nil {
+			return nil, fmt.Errorf("plist: %w", err)
+		}
+		return start.Name.Local == "true", nil
+	}
+
+	var text string
+	if err := dec.DecodeElement(&text, start); err != nil {
+		return nil, fmt.Errorf("plist: %w", err)
+	}
+	switch start.Name.Local {
+	case "string":
+		return text, nil
+	case "integer":
+		n, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+		if err != nil {
+			// Unsigned 64-bit values (e.g. byte capacities) may exceed int64
+			u, uerr := strconv.ParseUint(strings.TrimSpace(text), 10, 64)
+			if uerr != nil {
+				return nil, fmt.Errorf("plist: invalid integer %q", text)
+			}
+			return int64(u), nil // #nosec G115 -- capacities are far below 2^63
+		}
+		return n, nil
+	case "real":
+		f, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return nil, fmt.Errorf("plist: invalid real %q", text)
+		}
+		return f, nil
+	case "data":
+		clean := strings.Join(strings.Fields(text), "")
+		b, err := base64.StdEncoding.DecodeString(clean)
+		if err != nil {
+			return nil, fmt.Errorf("plist: invalid data: %w", err)
+		}
+		return b, nil
+	case "date":
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("plist: invalid date %q", text)
+		}
+		return t, nil
+	}
+	return nil, fmt.Errorf("plist: unsupported element <%s>", start.Name.Local)
+}
+
+// decodePlistDict decodes <key>/value pairs until </dict>
+func decodePlistDict(dec *xml.Decoder) (map[string]any, error) {
+	dict := make(map[string]any)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("plist: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return dict, nil
+		case xml.StartElement:
+			if t.Name.Local != "key" {
+				return nil, fmt.Errorf("plist: expected <key>, got <%s>", t.Name.Local)
+			}
+			var key string
+			if err := dec.DecodeElement(&key, &t); err != nil {
+				return nil, fmt.Errorf("plist: %w", err)
+			}
+			value, err := decodePlistElement(dec, nil)
+			if err != nil {
+				return nil, err
+			}
+			dict[key] = value
+		}
+	}
+}
+
+// decodePlistArray decodes values until </array>
+func decodePlistArray(dec *xml.Decoder) ([]any, error) {
+	array := []any{}
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("plist: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return array, nil
+		case xml.StartElement:
+			value, err := decodePlistElement(dec, &t)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+	}
+}
+
+// plistString returns dict[key] as a string, or "" if absent or not a string
+func plistString(dict map[string]any, key string) string {
+	s, _ := dict[key].(string)
+	return s
+}
+
+// plistBool returns dict[key] as a bool, or false if absent or not a bool
+func plistBool(dict map[string]any, key string) bool {
+	b, _ := dict[key].(bool)
+	return b
+}
+
+// plistUint returns dict[key] as a non-negative integer, or 0 if absent,
+// negative, or not an integer
+func plistUint(dict map[string]any, key string) uint64 {
+	n, _ := dict[key].(int64)
+	if n < 0 {
+		return 0
+	}
+	return uint64(n)
+}
+
+// plistDicts returns dict[key] as a slice of dictionaries, skipping other values
+func plistDicts(dict map[string]any, key string) []map[string]any {
+	array, _ := dict[key].([]any)
+	var dicts []map[string]any
+	for _, v := range array {
+		if d, ok := v.(map[string]any); ok {
+			dicts = append(dicts, d)
+		}
+	}
+	return dicts
+}
+
+// plistStrings returns dict[key] as a slice of strings, skipping other values
+func plistStrings(dict map[string]any, key string) []string {
+	array, _ := dict[key].([]any)
+	var strs []string
+	for _, v := range array {
+		if s, ok := v.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
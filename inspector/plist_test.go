@@ -0,0 +1,87 @@
+package inspector
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestDecodePlist(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Name</key><string>Macintosh HD</string>
+	<key>Size</key><integer>18446744073709551615</integer>
+	<key>Count</key><integer>-3</integer>
+	<key>Ratio</key><real>0.5</real>
+	<key>Encrypted</key><true/>
+	<key>Locked</key><false/>
+	<key>Raw</key><data>
+	AAEC
+	</data>
+	<key>Created</key><date>2024-03-02T09:14:51Z</date>
+	<key>Roles</key><array><string>System</string><string>Data</string></array>
+	<key>Empty</key><array/>
+	<key>Nested</key><dict><key>Inner</key><string>x</string></dict>
+</dict>
+</plist>`)
+
+	root, err := decodePlist(data)
+	if err != nil {
+		t.Fatalf("decodePlist failed: %v", err)
+	}
+	dict, ok := root.(map[string]any)
+	if !ok {
+		t.Fatalf("root = %T, want map[string]any", root)
+	}
+
+	if got := plistString(dict, "Name"); got != "Macintosh HD" {
+		t.Errorf("Name = %q, want %q", got, "Macintosh HD")
+	}
+	if got := dict["Count"]; got != int64(-3) {
+		t.Errorf("Count = %v, want -3", got)
+	}
+	if _, ok := dict["Size"].(int64); !ok {
+		t.Errorf("Size = %T, want int64", dict["Size"])
+	}
+	if got := dict["Ratio"]; got != 0.5 {
+		t.Errorf("Ratio = %v, want 0.5", got)
+	}
+	if !plistBool(dict, "Encrypted") || plistBool(dict, "Locked") {
+		t.Error("Encrypted/Locked booleans decoded incorrectly")
+	}
+	if got, _ := dict["Raw"].([]byte); !bytes.Equal(got, []byte{0, 1, 2}) {
+		t.Errorf("Raw = %v, want [0 1 2]", got)
+	}
+	if got, _ := dict["Created"].(time.Time); !got.Equal(time.Date(2024, 3, 2, 9, 14, 51, 0, time.UTC)) {
+		t.Errorf("Created = %v", got)
+	}
+	if got := plistStrings(dict, "Roles"); len(got) != 2 || got[1] != "Data" {
+		t.Errorf("Roles = %v, want [System Data]", got)
+	}
+	if got := plistStrings(dict, "Empty"); len(got) != 0 {
+		t.Errorf("Empty = %v, want []", got)
+	}
+	nested, _ := dict["Nested"].(map[string]any)
+	if plistString(nested, "Inner") != "x" {
+		t.Errorf("Nested = %v", dict["Nested"])
+	}
+}
+
+func TestDecodePlist_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"empty":        ``,
+		"not a plist":  `<html></html>`,
+		"bad integer":  `<plist><integer>abc</integer></plist>`,
+		"missing key":  `<plist><dict><string>x</string></dict></plist>`,
+		"unterminated": `<plist><dict><key>a</key>`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := decodePlist([]byte(data)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io/fs"
+	"path"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PowerState is how the machine is powered and whether its user is busy,
+// which decides whether a scheduled scan may run now
+type PowerState struct {
+	// OnBattery is true while the machine runs from its battery
+	OnBattery bool `json:"on_battery"`
+	// BatteryPercent is the charge of the battery, or -1 without one
+	BatteryPercent int `json:"battery_percent"`
+	// Busy is true while something keeps the display awake for the user,
+	// such as a presentation, a video call, or full-screen video
+	Busy bool `json:"busy"`
+	// BusyReason names what keeps the display awake
+	BusyReason string `json:"busy_reason,omitempty"`
+}
+
+// GetPowerState reads the power source from /sys/class/power_supply
+// (Linux), pmset (macOS), or Win32_Battery (Windows), and whether the user
+// is busy from the idle inhibitors of systemd-logind, the display sleep
+// assertions of pmset, or the display requests of powercfg. What cannot be
+// read is left unset: no battery, not busy.
+func GetPowerState() *PowerState {
+	return GetPowerStateContext(context.Background())
+}
+
+// GetPowerStateContext is like GetPowerState but runs the probes with ctx's
+// deadline, command runner, and logger
+func GetPowerStateContext(ctx context.Context) *PowerState {
+	if result, ok, err := loadFixture[PowerState](ctx, "power"); ok && err == nil {
+		return result
+	}
+	p := &PowerState{BatteryPercent: -1}
+	switch runtime.GOOS {
+	case "linux":
+		readPowerSupplies(p, environmentRoot)
+		if out, err := runCommand(ctx, "systemd-inhibit", "--list", "--no-pager"); err == nil {
+			p.BusyReason = parseIdleInhibitors(out)
+		}
+	case "darwin":
+		if out, err := runCommand(ctx, "pmset", "-g", "batt"); err == nil {
+			parsePmsetBatt(p, out)
+		}
+		if out, err := runCommand(ctx, "pmset", "-g", "assertions"); err == nil {
+			p.BusyReason = parsePmsetAssertions(out)
+		}
+	case "windows":
+		windowsBattery(p)
+		if out, err := runCommand(ctx, "powercfg", "/requests"); err == nil {
+			p.BusyReason = parsePowercfgRequests(out)
+		}
+	}
+	p.Busy = p.BusyReason != ""
+	return p
+}
+
+// readPowerSupplies reads the batteries and AC adapters the kernel lists
+// under sys/class/power_supply. Batteries of peripherals such as mice are
+// ignored, and the charge of several batteries is averaged.
+func readPowerSupplies(p *PowerState, root fs.FS) {
+	const dir = "sys/class/power_supply"
+	entries, err := fs.ReadDir(root, dir)
+	if err != nil {
+		return
+	}
+	read := func(name, attr string) string {
+		data, _ := fs.ReadFile(root, path.Join(dir, name, attr))
+		return strings.TrimSpace(string(data))
+	}
+	var percents []int
+	var adapters, online, discharging bool
+	for _, e := range entries {
+		switch read(e.Name(), "type") {
+		case "Mains", "USB":
+			adapters = true
+			online = online || read(e.Name(), "online") == "1"
+		case "Battery":
+			if read(e.Name(), "scope") == "Device" {
+				continue
+			}
+			if n, err := strconv.Atoi(read(e.Name(), "capacity")); err == nil {
+				percents = append(percents, n)
+			}
+			discharging = discharging || read(e.Name(), "status") == "Discharging"
+		}
+	}
+	if len(percents) == 0 {
+		return
+	}
+	sum := 0
+	for _, n := range percents {
+		sum += n
+	}
+	p.BatteryPercent = sum / len(percents)
+	// Without an adapter entry, a discharging battery means no AC power
+	p.OnBattery = adapters && !online || !adapters && discharging
+}
+
+// parseIdleInhibitors returns who blocks idling in systemd-inhibit --list
+// output, or "". Its columns (WHO UID USER PID COMM WHAT WHY MODE) are
+// padded to the widest value and found by their header.
+func parseIdleInhibitors(out []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	if !scanner.Scan() {
+		return ""
+	}
+	header := scanner.Text()
+	who, what, why, mode := strings.Index(header, "WHO"), strings.Index(header, "WHAT"), strings.Index(header, "WHY"), strings.Index(header, "MODE")
+	if who < 0 || what < 0 || why < what || mode < why {
+		return ""
+	}
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(line) <= mode {
+			continue
+		}
+		whats := strings.Split(strings.TrimSpace(line[what:why]), ":")
+		if strings.TrimSpace(line[mode:]) == "block" && slices.Contains(whats, "idle") {
+			return strings.TrimSpace(line[who:strings.Index(header, "UID")]) + ": " + strings.TrimSpace(line[why:mode])
+		}
+	}
+	return ""
+}
+
+// pmsetPercent matches the charge in pmset -g batt output
+var pmsetPercent = regexp.MustCompile(`\t(\d+)%;`)
+
+// parsePmsetBatt reads the power source and the internal battery's charge
+// from pmset -g batt
+func parsePmsetBatt(p *PowerState, out []byte) {
+	p.OnBattery = bytes.Contains(out, []byte("'Battery Power'"))
+	if m := pmsetPercent.FindSubmatch(out); m != nil {
+		p.BatteryPercent, _ = strconv.Atoi(string(m[1]))
+	}
+}
+
+// pmsetDisplayAssertion matches a process holding the display awake in
+// pmset -g assertions output
+var pmsetDisplayAssertion = regexp.MustCompile(`pid \d+\(([^)]+)\).*PreventUserIdleDisplaySleep named: "([^"]*)"`)
+
+// parsePmsetAssertions returns what keeps the display awake in pmset -g
+// assertions output, or ""
+func parsePmsetAssertions(out []byte) string {
+	held := false
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if f := strings.Fields(scanner.Text()); len(f) == 2 && f[0] == "PreventUserIdleDisplaySleep" && f[1] == "1" {
+			held = true
+		}
+	}
+	if !held {
+		return ""
+	}
+	if m := pmsetDisplayAssertion.FindSubmatch(out); m != nil {
+		return string(m[1]) + ": " + string(m[2])
+	}
+	return "PreventUserIdleDisplaySleep"
+}
+
+// parsePowercfgRequests returns the first display request in powercfg
+// /requests output, e.g. PowerPoint presenting, or ""
+func parsePowercfgRequests(out []byte) string {
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+		case strings.HasSuffix(line, ":") && !strings.Contains(line, " "):
+			section = strings.TrimSuffix(line, ":")
+		case section == "DISPLAY" && line != "None.":
+			// [PROCESS] \Device\HarddiskVolume3\...\POWERPNT.EXE
+			if _, request, ok := strings.Cut(line, "] "); ok {
+				line = request
+			}
+			return line[strings.LastIndex(line, `\`)+1:]
+		}
+	}
+	return ""
+}
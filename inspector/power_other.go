@@ -0,0 +1,6 @@
+//go:build !windows
+
+package inspector
+
+// windowsBattery is only used on Windows
+func windowsBattery(p *PowerState) {}
@@ -0,0 +1,117 @@
+package inspector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadPowerSupplies(t *testing.T) {
+	laptop := func(online, status string) fstest.MapFS {
+		return fstest.MapFS{
+			"sys/class/power_supply/AC/type":          {Data: []byte("Mains\n")},
+			"sys/class/power_supply/AC/online":        {Data: []byte(online + "\n")},
+			"sys/class/power_supply/BAT0/type":        {Data: []byte("Battery\n")},
+			"sys/class/power_supply/BAT0/capacity":    {Data: []byte("14\n")},
+			"sys/class/power_supply/BAT0/status":      {Data: []byte(status + "\n")},
+			"sys/class/power_supply/hidpp_0/type":     {Data: []byte("Battery\n")},
+			"sys/class/power_supply/hidpp_0/scope":    {Data: []byte("Device\n")},
+			"sys/class/power_supply/hidpp_0/capacity": {Data: []byte("90\n")},
+		}
+	}
+	tests := []struct {
+		name      string
+		root      fstest.MapFS
+		onBattery bool
+		percent   int
+	}{
+		{"on battery", laptop("0", "Discharging"), true, 14},
+		{"charging", laptop("1", "Charging"), false, 14},
+		{"no adapter entry", fstest.MapFS{
+			"sys/class/power_supply/BAT1/type":     {Data: []byte("Battery\n")},
+			"sys/class/power_supply/BAT1/capacity": {Data: []byte("55\n")},
+			"sys/class/power_supply/BAT1/status":   {Data: []byte("Discharging\n")},
+		}, true, 55},
+		{"desktop", fstest.MapFS{
+			"sys/class/power_supply/AC/type":   {Data: []byte("Mains\n")},
+			"sys/class/power_supply/AC/online": {Data: []byte("1\n")},
+		}, false, -1},
+		{"no power supplies", fstest.MapFS{}, false, -1},
+	}
+	for _, tt := range tests {
+		p := &PowerState{BatteryPercent: -1}
+		readPowerSupplies(p, tt.root)
+		if p.OnBattery != tt.onBattery || p.BatteryPercent != tt.percent {
+			t.Errorf("%s: on battery %v at %d%%, want %v at %d%%", tt.name, p.OnBattery, p.BatteryPercent, tt.onBattery, tt.percent)
+		}
+	}
+}
+
+func TestParseIdleInhibitors(t *testing.T) {
+	out := []byte(`WHO          UID  USER  PID  COMM         WHAT  WHY                                 MODE
+ModemManager 0    root  812  ModemManager sleep ModemManager needs to reset devices delay
+GNOME Shell  1000 alice 2301 gnome-shell  idle  Presenting                          block
+
+2 inhibitors listed.
+`)
+	if got := parseIdleInhibitors(out); got != "GNOME Shell: Presenting" {
+		t.Errorf("parseIdleInhibitors = %q", got)
+	}
+	if got := parseIdleInhibitors(out[:176]); got != "" {
+		t.Errorf("a sleep delay is not busy, got %q", got)
+	}
+	if got := parseIdleInhibitors([]byte("No inhibitors.\n")); got != "" {
+		t.Errorf("no inhibitors, got %q", got)
+	}
+}
+
+func TestParsePmset(t *testing.T) {
+	p := &PowerState{BatteryPercent: -1}
+	parsePmsetBatt(p, []byte("Now drawing from 'Battery Power'\n -InternalBattery-0 (id=4653155)\t18%; discharging; 0:41 remaining present: true\n"))
+	if !p.OnBattery || p.BatteryPercent != 18 {
+		t.Errorf("pmset -g batt = %+v", p)
+	}
+	p = &PowerState{BatteryPercent: -1}
+	parsePmsetBatt(p, []byte("Now drawing from 'AC Power'\n -InternalBattery-0 (id=4653155)\t100%; charged; 0:00 remaining present: true\n"))
+	if p.OnBattery || p.BatteryPercent != 100 {
+		t.Errorf("pmset -g batt on AC = %+v", p)
+	}
+
+	assertions := `Assertion status system-wide:
+   BackgroundTask                 0
+   PreventUserIdleDisplaySleep    1
+   UserIsActive                   1
+Listed by owning process:
+   pid 512(Keynote): [0x0000a1b2000194f3] 00:12:03 PreventUserIdleDisplaySleep named: "Keynote is playing a slideshow"
+`
+	if got := parsePmsetAssertions([]byte(assertions)); got != "Keynote: Keynote is playing a slideshow" {
+		t.Errorf("parsePmsetAssertions = %q", got)
+	}
+	if got := parsePmsetAssertions([]byte("Assertion status system-wide:\n   PreventUserIdleDisplaySleep    0\n")); got != "" {
+		t.Errorf("no display assertion, got %q", got)
+	}
+}
+
+func TestParsePowercfgRequests(t *testing.T) {
+	out := []byte("DISPLAY:\r\n[PROCESS] \\Device\\HarddiskVolume3\\Program Files\\Microsoft Office\\root\\Office16\\POWERPNT.EXE\r\n\r\nSYSTEM:\r\nNone.\r\n\r\nAWAYMODE:\r\nNone.\r\n")
+	if got := parsePowercfgRequests(out); got != "POWERPNT.EXE" {
+		t.Errorf("parsePowercfgRequests = %q", got)
+	}
+	idle := []byte("DISPLAY:\r\nNone.\r\n\r\nSYSTEM:\r\n[DRIVER] Realtek High Definition Audio\r\nAn audio stream is in use.\r\n")
+	if got := parsePowercfgRequests(idle); got != "" {
+		t.Errorf("only a system request, got %q", got)
+	}
+}
+
+func TestGetPowerState_Fixture(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(FixtureEnv, dir)
+	if err := os.WriteFile(filepath.Join(dir, "power.json"), []byte(`{"on_battery": true, "battery_percent": 12, "busy": false}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if p := GetPowerStateContext(context.Background()); !p.OnBattery || p.BatteryPercent != 12 {
+		t.Errorf("GetPowerState = %+v, want the fixture", p)
+	}
+}
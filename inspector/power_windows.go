@@ -0,0 +1,24 @@
+//go:build windows
+
+package inspector
+
+// Win32_Battery is a battery as reported by WMI
+type Win32_Battery struct {
+	// BatteryStatus is 1 while discharging and 2 on AC power
+	BatteryStatus            uint16
+	EstimatedChargeRemaining uint16
+}
+
+// windowsBattery reads the power source and charge from Win32_Battery
+func windowsBattery(p *PowerState) {
+	var batteries []Win32_Battery
+	if err := wmiQuery("SELECT BatteryStatus, EstimatedChargeRemaining FROM Win32_Battery", &batteries, ""); err != nil || len(batteries) == 0 {
+		return
+	}
+	sum := 0
+	for _, b := range batteries {
+		sum += int(b.EstimatedChargeRemaining)
+		p.OnBattery = p.OnBattery || b.BatteryStatus == 1
+	}
+	p.BatteryPercent = sum / len(batteries)
+}
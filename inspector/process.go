@@ -1,18 +1,47 @@
 package inspector
 
 import (
+	"container/heap"
 	"context"
 	"fmt"
+	"runtime"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/shirou/gopsutil/v4/mem"
 	"github.com/shirou/gopsutil/v4/process"
 )
 
+// Process sort keys
+const (
+	ProcessSortCPU    = "cpu"
+	ProcessSortMemory = "memory"
+	ProcessSortPID    = "pid"
+	ProcessSortName   = "name"
+)
+
+// Process fields ProcessOptions.Fields selects. The PID and name are always
+// collected.
+const (
+	ProcessFieldPID    = "pid"
+	ProcessFieldName   = "name"
+	ProcessFieldUser   = "user"
+	ProcessFieldCPU    = "cpu"
+	ProcessFieldMemory = "memory"
+	ProcessFieldStatus = "status"
+)
+
+// ProcessFields lists the fields ProcessOptions.Fields accepts
+var ProcessFields = []string{ProcessFieldPID, ProcessFieldName, ProcessFieldUser, ProcessFieldCPU, ProcessFieldMemory, ProcessFieldStatus}
+
 // ProcessInfo contains information about a single process
 type ProcessInfo struct {
 	PID           int32   `json:"pid"`
 	Name          string  `json:"name"`
+	User          string  `json:"user,omitempty"`
 	CPUPercent    float64 `json:"cpu_percent"`
 	MemoryPercent float32 `json:"memory_percent"`
 	Status        string  `json:"status"`
@@ -20,93 +49,424 @@ type ProcessInfo struct {
 
 // ProcessListResult contains the process list result
 type ProcessListResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
 	Processes []ProcessInfo `json:"processes"`
-	Total     int           `json:"total"`
+	// Total is the number of running processes
+	Total int `json:"total"`
+	// Matched is the number of processes that passed the filters, before
+	// pagination
+	Matched int `json:"matched"`
+	Offset  int `json:"offset,omitempty"`
+}
+
+// ProcessOptions selects, orders, and pages the processes ListProcessesWithOptions returns
+type ProcessOptions struct {
+	// Name keeps processes whose name contains this substring (case-insensitive)
+	Name string
+	// User keeps processes owned by this user (case-insensitive; on Windows
+	// either "DOMAIN\user" or the bare user name)
+	User string
+	// MinCPU and MinMemory keep processes at or above these percentages
+	MinCPU    float64
+	MinMemory float32
+	// Sort is cpu (default), memory, pid, or name. CPU and memory sort
+	// descending, pid and name ascending.
+	Sort string
+	// Offset skips this many matching processes; Limit caps the page (0 for all)
+	Offset int
+	Limit  int
+	// Fields selects the fields to collect (see ProcessFields); empty
+	// collects all of them. Fields the filters or sort key need are always
+	// collected, and the others are left zero.
+	Fields []string
+	// Workers caps how many processes are inspected in parallel (0 for
+	// GOMAXPROCS)
+	Workers int
+}
+
+// processFieldSet is the set of optional fields to collect per process
+type processFieldSet struct {
+	user, cpu, memory, status bool
+}
+
+// without returns the fields of f that are not in other
+func (f processFieldSet) without(other processFieldSet) processFieldSet {
+	return processFieldSet{
+		user:   f.user && !other.user,
+		cpu:    f.cpu && !other.cpu,
+		memory: f.memory && !other.memory,
+		status: f.status && !other.status,
+	}
 }
 
-// ListProcesses returns a list of running processes
+// ListProcesses returns a list of running processes sorted by CPU usage
 func ListProcesses(ctx context.Context, limit int) (*ProcessListResult, error) {
+	return ListProcessesWithOptions(ctx, ProcessOptions{Limit: limit})
+}
+
+// ListProcessesWithOptions returns the running processes that match opts,
+// sorted and paginated
+func ListProcessesWithOptions(ctx context.Context, opts ProcessOptions) (*ProcessListResult, error) {
+	less, err := processLess(opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted, needed, err := processFields(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	procs, err := process.ProcessesWithContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list processes: %w", err)
 	}
 
-	var procInfos []ProcessInfo
+	// With a limit, every process only has the fields the filters and sort
+	// key need collected; the others are filled in for the page alone
+	first := wanted
+	if opts.Limit > 0 {
+		first = needed
+	}
+	procInfos := collectProcesses(ctx, procs, opts, first)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result := selectProcesses(procInfos, opts, less)
+	if rest := wanted.without(first); rest != (processFieldSet{}) {
+		fillProcesses(ctx, procs, result.Processes, opts, rest)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	result.Total = len(procs)
+	return result, nil
+}
+
+// processFields resolves the fields to collect: the ones asked for, plus
+// the ones the filters and sort key need, and those needed fields alone
+func processFields(opts ProcessOptions) (wanted, needed processFieldSet, err error) {
+	sortKey := strings.ToLower(opts.Sort)
+	needed = processFieldSet{
+		user:   opts.User != "",
+		cpu:    opts.MinCPU > 0 || sortKey == "" || sortKey == ProcessSortCPU,
+		memory: opts.MinMemory > 0 || sortKey == ProcessSortMemory || sortKey == "mem",
+	}
+	if len(opts.Fields) == 0 {
+		return processFieldSet{user: true, cpu: true, memory: true, status: true}, needed, nil
+	}
+	wanted = needed
+	for _, name := range opts.Fields {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case ProcessFieldPID, ProcessFieldName:
+		case ProcessFieldUser:
+			wanted.user = true
+		case ProcessFieldCPU:
+			wanted.cpu = true
+		case ProcessFieldMemory, "mem":
+			wanted.memory = true
+		case ProcessFieldStatus:
+			wanted.status = true
+		default:
+			return wanted, needed, fmt.Errorf("unknown process field %q (use %s)", name, strings.Join(ProcessFields, ", "))
+		}
+	}
+	return wanted, needed, nil
+}
+
+// collectProcesses inspects the processes with a pool of workers, since
+// each field is a separate system call (or several) per process. The order
+// of procs is kept.
+func collectProcesses(ctx context.Context, procs []*process.Process, opts ProcessOptions, fields processFieldSet) []ProcessInfo {
+	totalMemory := processTotalMemory(ctx, fields)
+	infos := make([]ProcessInfo, len(procs))
+	keep := make([]bool, len(procs))
+	inParallel(ctx, opts.Workers, len(procs), func(i int) {
+		infos[i], keep[i] = inspectProcess(ctx, procs[i], opts, fields, totalMemory)
+	})
+
+	procInfos := infos[:0]
+	for i, info := range infos {
+		if keep[i] {
+			procInfos = append(procInfos, info)
+		}
+	}
+	return procInfos
+}
+
+// fillProcesses collects more fields for the selected processes
+func fillProcesses(ctx context.Context, procs []*process.Process, infos []ProcessInfo, opts ProcessOptions, fields processFieldSet) {
+	byPID := make(map[int32]*process.Process, len(procs))
 	for _, p := range procs {
-		name, _ := p.NameWithContext(ctx)
-		cpuPercent, _ := p.CPUPercentWithContext(ctx)
-		memPercent, _ := p.MemoryPercentWithContext(ctx)
-		status, _ := p.StatusWithContext(ctx)
-
-		statusStr := "unknown"
-		if len(status) > 0 {
-			statusStr = status[0]
+		byPID[p.Pid] = p
+	}
+	totalMemory := processTotalMemory(ctx, fields)
+	inParallel(ctx, opts.Workers, len(infos), func(i int) {
+		if p, ok := byPID[infos[i].PID]; ok {
+			collectProcessFields(ctx, p, &infos[i], fields, totalMemory)
 		}
+	})
+}
+
+// inParallel calls fn for 0..n-1 from up to workers goroutines (GOMAXPROCS
+// when workers <= 0), stopping early if ctx is done
+func inParallel(ctx context.Context, workers, n int, fn func(i int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range min(workers, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(next.Add(1)) - 1
+				if i >= n {
+					return
+				}
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
 
-		procInfos = append(procInfos, ProcessInfo{
-			PID:           p.Pid,
-			Name:          name,
-			CPUPercent:    cpuPercent,
-			MemoryPercent: memPercent,
-			Status:        statusStr,
+// processTotalMemory reads total memory once, so the memory share of every
+// process is taken against the same reading rather than one per process
+func processTotalMemory(ctx context.Context, fields processFieldSet) uint64 {
+	if !fields.memory {
+		return 0
+	}
+	vm, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return 0
+	}
+	return vm.Total
+}
+
+// inspectProcess collects the selected fields of one process, reporting
+// false if it fails the name or user filter
+func inspectProcess(ctx context.Context, p *process.Process, opts ProcessOptions, fields processFieldSet, totalMemory uint64) (ProcessInfo, bool) {
+	info := ProcessInfo{PID: p.Pid}
+	info.Name, _ = p.NameWithContext(ctx)
+	// Apply the cheap filters before sampling CPU and memory
+	if !matchProcessName(info.Name, opts.Name) {
+		return info, false
+	}
+	if fields.user {
+		info.User, _ = p.UsernameWithContext(ctx)
+		if !matchProcessUser(info.User, opts.User) {
+			return info, false
+		}
+	}
+	collectProcessFields(ctx, p, &info, fields.without(processFieldSet{user: true}), totalMemory)
+	return info, true
+}
+
+// collectProcessFields fills in the given fields of info
+func collectProcessFields(ctx context.Context, p *process.Process, info *ProcessInfo, fields processFieldSet, totalMemory uint64) {
+	if fields.user {
+		info.User, _ = p.UsernameWithContext(ctx)
+	}
+	if fields.cpu {
+		info.CPUPercent, _ = p.CPUPercentWithContext(ctx)
+	}
+	if fields.memory && totalMemory > 0 {
+		if m, err := p.MemoryInfoWithContext(ctx); err == nil {
+			info.MemoryPercent = float32(100 * float64(m.RSS) / float64(totalMemory))
+		}
+	} else if fields.memory {
+		info.MemoryPercent, _ = p.MemoryPercentWithContext(ctx)
+	}
+	if fields.status {
+		info.Status = "unknown"
+		if status, _ := p.StatusWithContext(ctx); len(status) > 0 {
+			info.Status = status[0]
+		}
+	}
+}
+
+// processLess returns the ordering for a sort key
+func processLess(key string) (func(a, b ProcessInfo) bool, error) {
+	switch strings.ToLower(key) {
+	case "", ProcessSortCPU:
+		return func(a, b ProcessInfo) bool { return a.CPUPercent > b.CPUPercent }, nil
+	case ProcessSortMemory, "mem":
+		return func(a, b ProcessInfo) bool { return a.MemoryPercent > b.MemoryPercent }, nil
+	case ProcessSortPID:
+		return func(a, b ProcessInfo) bool { return a.PID < b.PID }, nil
+	case ProcessSortName:
+		return func(a, b ProcessInfo) bool {
+			an, bn := strings.ToLower(a.Name), strings.ToLower(b.Name)
+			if an != bn {
+				return an < bn
+			}
+			return a.PID < b.PID
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown sort key %q (use cpu, memory, pid, or name)", key)
+}
+
+// matchProcessName reports whether name contains the filter substring
+func matchProcessName(name, filter string) bool {
+	return filter == "" || strings.Contains(strings.ToLower(name), strings.ToLower(filter))
+}
+
+// matchProcessUser reports whether user matches the filter, ignoring a
+// Windows domain prefix unless the filter has one
+func matchProcessUser(user, filter string) bool {
+	if filter == "" {
+		return true
+	}
+	if strings.EqualFold(user, filter) {
+		return true
+	}
+	if !strings.Contains(filter, `\`) {
+		if i := strings.LastIndex(user, `\`); i >= 0 {
+			return strings.EqualFold(user[i+1:], filter)
+		}
+	}
+	return false
+}
+
+// selectProcesses applies the usage filters, sorting, and pagination. With
+// a limit, only the processes up to the end of the page are sorted.
+func selectProcesses(procInfos []ProcessInfo, opts ProcessOptions, less func(a, b ProcessInfo) bool) *ProcessListResult {
+	matched := []ProcessInfo{}
+	for _, p := range procInfos {
+		if p.CPUPercent < opts.MinCPU || p.MemoryPercent < opts.MinMemory {
+			continue
+		}
+		matched = append(matched, p)
+	}
+
+	result := &ProcessListResult{Matched: len(matched), Offset: opts.Offset}
+	page := matched
+	if opts.Limit > 0 {
+		page = topProcesses(matched, opts.Offset+opts.Limit, less)
+	} else {
+		sort.SliceStable(page, func(i, j int) bool {
+			return less(page[i], page[j])
 		})
 	}
+	if opts.Offset > 0 {
+		page = page[min(opts.Offset, len(page)):]
+	}
+	if opts.Limit > 0 && opts.Limit < len(page) {
+		page = page[:opts.Limit]
+	}
+	result.Processes = page
+	return result
+}
 
-	// Sort by CPU usage descending
-	sort.Slice(procInfos, func(i, j int) bool {
-		return procInfos[i].CPUPercent > procInfos[j].CPUPercent
-	})
+// rankedProcess is a process and its position in the input, which breaks
+// ties so partial selection orders like a stable sort
+type rankedProcess struct {
+	info ProcessInfo
+	seq  int
+}
+
+// processHeap holds the best processes seen so far, the worst on top
+type processHeap struct {
+	items  []rankedProcess
+	before func(a, b rankedProcess) bool
+}
 
-	total := len(procInfos)
-	if limit > 0 && limit < len(procInfos) {
-		procInfos = procInfos[:limit]
+func (h *processHeap) Len() int           { return len(h.items) }
+func (h *processHeap) Less(i, j int) bool { return h.before(h.items[j], h.items[i]) }
+func (h *processHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *processHeap) Push(x any)         { h.items = append(h.items, x.(rankedProcess)) }
+func (h *processHeap) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// topProcesses returns the first k processes in less order, as a stable
+// sort would, keeping a heap of k processes instead of sorting them all
+func topProcesses(procs []ProcessInfo, k int, less func(a, b ProcessInfo) bool) []ProcessInfo {
+	before := func(a, b rankedProcess) bool {
+		if less(a.info, b.info) {
+			return true
+		}
+		return !less(b.info, a.info) && a.seq < b.seq
+	}
+	h := &processHeap{items: make([]rankedProcess, 0, min(k, len(procs))), before: before}
+	for i, p := range procs {
+		item := rankedProcess{info: p, seq: i}
+		switch {
+		case h.Len() < k:
+			heap.Push(h, item)
+		case before(item, h.items[0]):
+			h.items[0] = item
+			heap.Fix(h, 0)
+		}
 	}
 
-	return &ProcessListResult{
-		Processes: procInfos,
-		Total:     total,
-	}, nil
+	slices.SortFunc(h.items, func(a, b rankedProcess) int {
+		if before(a, b) {
+			return -1
+		}
+		return 1
+	})
+	top := make([]ProcessInfo, len(h.items))
+	for i, item := range h.items {
+		top[i] = item.info
+	}
+	return top
 }
 
 // formatStatus returns a colored status string
-func formatStatus(status string) string {
+func formatStatus(st Styler, status string) string {
 	switch status {
 	case "R", "running":
-		return Success(IconCircle + " Run")
+		return st.Success(IconCircle + " " + T("Run"))
 	case "S", "sleep":
-		return Info(IconCircle + " Sleep")
+		return st.Info(IconCircle + " " + T("Sleep"))
 	case "I", "idle":
-		return Muted(IconCircle + " Idle")
+		return st.Muted(IconCircle + " " + T("Idle"))
 	case "Z", "zombie":
-		return Danger(IconCircle + " Zombie")
+		return st.Danger(IconCircle + " " + T("Zombie"))
 	case "T", "stop":
-		return Warning(IconCircle + " Stop")
+		return st.Warning(IconCircle + " " + T("Stop"))
 	default:
-		return Muted(IconCircle + " " + status)
+		return st.Muted(IconCircle + " " + status)
 	}
 }
 
 // FormatProcessListTable formats process list as a colored table
 func FormatProcessListTable(result *ProcessListResult) string {
+	return FormatProcessListTableWith(CurrentStyler(), result)
+}
+
+// FormatProcessListTableWith is like FormatProcessListTable but styles the
+// table with st
+func FormatProcessListTableWith(st Styler, result *ProcessListResult) string {
 	var sb strings.Builder
 	sb.WriteString("\n")
-	sb.WriteString(Header(fmt.Sprintf("%s Processes (Total: %d)", IconProcess, result.Total)))
+	title := IconProcess + " " + T("Processes (Total: %d)", result.Total)
+	if result.Matched != result.Total {
+		title = IconProcess + " " + T("Processes (Matched: %d of %d)", result.Matched, result.Total)
+	}
+	sb.WriteString(st.Header(title))
 	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 70)))
+	sb.WriteString(st.Muted(strings.Repeat("─", 70)))
 	sb.WriteString("\n\n")
 
 	// Process table
-	sb.WriteString(TableTop(8, 28, 9, 9, 10))
+	sb.WriteString(st.TableTop(8, 28, 9, 9, 10))
 	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("PID", 8)),
-		Header(PadRight("Name", 28)),
-		Header(PadLeft("CPU %", 9)),
-		Header(PadLeft("Mem %", 9)),
-		Header(PadRight("Status", 10)),
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight("PID", 8)),
+		st.Header(PadRight(T("Name"), 28)),
+		st.Header(PadLeft("CPU %", 9)),
+		st.Header(PadLeft("Mem %", 9)),
+		st.Header(PadRight(T("Status"), 10)),
 	))
 	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(8, 28, 9, 9, 10))
+	sb.WriteString(st.TableSeparator(8, 28, 9, 9, 10))
 	sb.WriteString("\n")
 
 	for _, proc := range result.Processes {
@@ -114,9 +474,9 @@ func FormatProcessListTable(result *ProcessListResult) string {
 		var cpuStr string
 		switch {
 		case proc.CPUPercent >= 50:
-			cpuStr = Danger(fmt.Sprintf("%9.1f", proc.CPUPercent))
+			cpuStr = st.Danger(fmt.Sprintf("%9.1f", proc.CPUPercent))
 		case proc.CPUPercent >= 25:
-			cpuStr = Warning(fmt.Sprintf("%9.1f", proc.CPUPercent))
+			cpuStr = st.Warning(fmt.Sprintf("%9.1f", proc.CPUPercent))
 		default:
 			cpuStr = fmt.Sprintf("%9.1f", proc.CPUPercent)
 		}
@@ -125,9 +485,9 @@ func FormatProcessListTable(result *ProcessListResult) string {
 		var memStr string
 		switch {
 		case proc.MemoryPercent >= 10:
-			memStr = Danger(fmt.Sprintf("%9.1f", proc.MemoryPercent))
+			memStr = st.Danger(fmt.Sprintf("%9.1f", proc.MemoryPercent))
 		case proc.MemoryPercent >= 5:
-			memStr = Warning(fmt.Sprintf("%9.1f", proc.MemoryPercent))
+			memStr = st.Warning(fmt.Sprintf("%9.1f", proc.MemoryPercent))
 		default:
 			memStr = fmt.Sprintf("%9.1f", proc.MemoryPercent)
 		}
@@ -138,24 +498,31 @@ func FormatProcessListTable(result *ProcessListResult) string {
 			name = name[:25] + "..."
 		}
 
-		sb.WriteString(TableRowColored(
-			Info(PadRight(fmt.Sprintf("%d", proc.PID), 8)),
+		sb.WriteString(st.TableRowColored(
+			st.Info(PadRight(fmt.Sprintf("%d", proc.PID), 8)),
 			PadRight(name, 28),
 			cpuStr,
 			memStr,
-			PadRight(formatStatus(proc.Status), 10),
+			PadRight(formatStatus(st, proc.Status), 10),
 		))
 		sb.WriteString("\n")
 	}
 
-	sb.WriteString(TableBottom(8, 28, 9, 9, 10))
+	sb.WriteString(st.TableBottom(8, 28, 9, 9, 10))
 	sb.WriteString("\n")
+
+	// Pagination footer
+	if shown := len(result.Processes); shown > 0 && result.Offset+shown < result.Matched {
+		sb.WriteString(st.Muted(T("Showing %d-%d of %d; use offset %d for the next page",
+			result.Offset+1, result.Offset+shown, result.Matched, result.Offset+shown)))
+		sb.WriteString("\n")
+	}
 	return sb.String()
 }
 
 // FormatProcessList formats process list in the specified format
 func FormatProcessList(result *ProcessListResult, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatProcessListTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatProcessListTableWith(st, result)
 	}, format)
 }
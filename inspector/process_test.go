@@ -3,6 +3,10 @@ package inspector
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -231,7 +235,7 @@ func TestFormatStatus(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.status, func(t *testing.T) {
-			result := formatStatus(tt.status)
+			result := formatStatus(CurrentStyler(), tt.status)
 			stripped := StripANSI(result)
 			if !strings.Contains(stripped, tt.contains) {
 				t.Errorf("formatStatus(%q) = %q, should contain %q", tt.status, stripped, tt.contains)
@@ -291,3 +295,231 @@ func TestFormatProcessListTable_HighUsage(t *testing.T) {
 		t.Error("High usage should use warning/danger colors")
 	}
 }
+
+func TestSelectProcesses(t *testing.T) {
+	procs := []ProcessInfo{
+		{PID: 30, Name: "chrome", CPUPercent: 12, MemoryPercent: 8},
+		{PID: 10, Name: "sshd", CPUPercent: 0.1, MemoryPercent: 0.2},
+		{PID: 20, Name: "Chrome Helper", CPUPercent: 40, MemoryPercent: 3},
+		{PID: 40, Name: "postgres", CPUPercent: 5, MemoryPercent: 15},
+	}
+	pids := func(r *ProcessListResult) []int32 {
+		var out []int32
+		for _, p := range r.Processes {
+			out = append(out, p.PID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		opts    ProcessOptions
+		want    []int32
+		matched int
+	}{
+		{"default cpu order", ProcessOptions{}, []int32{20, 30, 40, 10}, 4},
+		{"memory order", ProcessOptions{Sort: "memory"}, []int32{40, 30, 20, 10}, 4},
+		{"pid order", ProcessOptions{Sort: "pid"}, []int32{10, 20, 30, 40}, 4},
+		{"name order", ProcessOptions{Sort: "name"}, []int32{30, 20, 40, 10}, 4},
+		{"min cpu", ProcessOptions{MinCPU: 5}, []int32{20, 30, 40}, 3},
+		{"min memory", ProcessOptions{MinMemory: 5, Sort: "pid"}, []int32{30, 40}, 2},
+		{"first page", ProcessOptions{Sort: "pid", Limit: 2}, []int32{10, 20}, 4},
+		{"second page", ProcessOptions{Sort: "pid", Offset: 2, Limit: 2}, []int32{30, 40}, 4},
+		{"offset past end", ProcessOptions{Offset: 10}, nil, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			less, err := processLess(tt.opts.Sort)
+			if err != nil {
+				t.Fatal(err)
+			}
+			in := append([]ProcessInfo(nil), procs...)
+			r := selectProcesses(in, tt.opts, less)
+			if got := pids(r); !slices.Equal(got, tt.want) {
+				t.Errorf("PIDs = %v, want %v", got, tt.want)
+			}
+			if r.Matched != tt.matched {
+				t.Errorf("Matched = %d, want %d", r.Matched, tt.matched)
+			}
+		})
+	}
+
+	if _, err := processLess("rss"); err == nil {
+		t.Error("processLess(rss) should reject an unknown sort key")
+	}
+}
+
+func TestMatchProcessFilters(t *testing.T) {
+	if !matchProcessName("Google Chrome Helper", "chrome") {
+		t.Error("name filter should be a case-insensitive substring match")
+	}
+	if matchProcessName("sshd", "chrome") {
+		t.Error("name filter matched an unrelated process")
+	}
+	tests := []struct {
+		user, filter string
+		want         bool
+	}{
+		{"root", "", true},
+		{"root", "ROOT", true},
+		{`CORP\alice`, "alice", true},
+		{`CORP\alice`, `corp\alice`, true},
+		{`CORP\alice`, `OTHER\alice`, false},
+		{"alice", "bob", false},
+	}
+	for _, tt := range tests {
+		if got := matchProcessUser(tt.user, tt.filter); got != tt.want {
+			t.Errorf("matchProcessUser(%q, %q) = %v, want %v", tt.user, tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestListProcessesWithOptions_NameFilter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	self, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot resolve test binary name")
+	}
+	name := filepath.Base(self)
+	if len(name) > 15 {
+		// Linux truncates comm to 15 characters
+		name = name[:15]
+	}
+	result, err := ListProcessesWithOptions(ctx, ProcessOptions{Name: name})
+	if err != nil {
+		t.Fatalf("ListProcessesWithOptions failed: %v", err)
+	}
+	if result.Matched == 0 {
+		t.Errorf("expected the test process %q to match", name)
+	}
+	if result.Matched > result.Total {
+		t.Errorf("Matched (%d) should be <= Total (%d)", result.Matched, result.Total)
+	}
+}
+
+func TestProcessFields(t *testing.T) {
+	all := processFieldSet{user: true, cpu: true, memory: true, status: true}
+	tests := []struct {
+		name           string
+		opts           ProcessOptions
+		wanted, needed processFieldSet
+	}{
+		{"all by default", ProcessOptions{}, all, processFieldSet{cpu: true}},
+		{"names only", ProcessOptions{Fields: []string{"pid", "name"}, Sort: ProcessSortPID}, processFieldSet{}, processFieldSet{}},
+		{"sort key", ProcessOptions{Fields: []string{"name"}}, processFieldSet{cpu: true}, processFieldSet{cpu: true}},
+		{"filters", ProcessOptions{Fields: []string{"Status"}, Sort: "mem", User: "root", MinCPU: 1}, all, processFieldSet{user: true, cpu: true, memory: true}},
+	}
+	for _, tt := range tests {
+		wanted, needed, err := processFields(tt.opts)
+		if err != nil || wanted != tt.wanted || needed != tt.needed {
+			t.Errorf("%s: processFields = %+v, %+v, %v, want %+v, %+v", tt.name, wanted, needed, err, tt.wanted, tt.needed)
+		}
+	}
+	if _, _, err := processFields(ProcessOptions{Fields: []string{"threads"}}); err == nil {
+		t.Error("processFields accepted an unknown field")
+	}
+}
+
+func TestTopProcesses(t *testing.T) {
+	var procs []ProcessInfo
+	for i := range 200 {
+		// Many ties, so the selection must order them like a stable sort
+		procs = append(procs, ProcessInfo{PID: int32(i), CPUPercent: float64((i * 37) % 11)})
+	}
+	less, _ := processLess(ProcessSortCPU)
+	sorted := slices.Clone(procs)
+	slices.SortStableFunc(sorted, func(a, b ProcessInfo) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		}
+		return 0
+	})
+	for _, k := range []int{1, 7, 50, 200, 500} {
+		if got := topProcesses(procs, k, less); !slices.Equal(got, sorted[:min(k, len(sorted))]) {
+			t.Errorf("topProcesses(%d) differs from a stable sort", k)
+		}
+	}
+}
+
+func TestListProcessesWithOptions_FillsPage(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := ListProcessesWithOptions(ctx, ProcessOptions{Sort: ProcessSortPID, Limit: 3})
+	if err != nil {
+		t.Fatalf("ListProcessesWithOptions failed: %v", err)
+	}
+	for i, p := range result.Processes {
+		// The status is not needed to pick the page, so it is filled in after
+		if p.Status == "" {
+			t.Errorf("Process[%d] = %+v, want its status filled in", i, p)
+		}
+	}
+}
+
+func TestListProcessesWithOptions_Fields(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := ListProcessesWithOptions(ctx, ProcessOptions{Fields: []string{ProcessFieldName}, Sort: ProcessSortPID, Workers: 2})
+	if err != nil {
+		t.Fatalf("ListProcessesWithOptions failed: %v", err)
+	}
+	if len(result.Processes) == 0 || result.Matched != result.Total {
+		t.Fatalf("got %d of %d processes, want all", result.Matched, result.Total)
+	}
+	for i, p := range result.Processes {
+		if p.User != "" || p.CPUPercent != 0 || p.MemoryPercent != 0 || p.Status != "" {
+			t.Fatalf("Process[%d] = %+v, want only the PID and name", i, p)
+		}
+		if i > 0 && result.Processes[i-1].PID > p.PID {
+			t.Fatalf("processes are not sorted by PID at %d", i)
+		}
+	}
+}
+
+// BenchmarkListProcesses compares inspecting processes one at a time with
+// the worker pool, collecting every field with names only, and a full
+// listing with a top-10 page
+func BenchmarkListProcesses(b *testing.B) {
+	benchmarks := []struct {
+		name string
+		opts ProcessOptions
+	}{
+		{"sequential", ProcessOptions{Workers: 1}},
+		{"parallel", ProcessOptions{}},
+		{"names_only", ProcessOptions{Fields: []string{ProcessFieldName}, Sort: ProcessSortPID}},
+		{"top_10", ProcessOptions{Limit: 10}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := ListProcessesWithOptions(context.Background(), bm.opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSelectProcesses compares sorting a large process table with
+// selecting the top 10 from it
+func BenchmarkSelectProcesses(b *testing.B) {
+	procs := make([]ProcessInfo, 50000)
+	for i := range procs {
+		procs[i] = ProcessInfo{PID: int32(i), CPUPercent: float64((i * 7919) % 1000)}
+	}
+	less, _ := processLess(ProcessSortCPU)
+	for _, limit := range []int{0, 10} {
+		b.Run(fmt.Sprintf("limit_%d", limit), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				selectProcesses(slices.Clone(procs), ProcessOptions{Limit: limit}, less)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package inspector
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Profile scopes reported in ConfigProfile.Scope
+const (
+	ProfileScopeComputer = "computer"
+	ProfileScopeUser     = "user"
+)
+
+// mdmPayloadType is the payload of the MDM enrollment profile itself
+const mdmPayloadType = "com.apple.mdm"
+
+// criticalRestrictions maps the restrictions worth knowing the source of to
+// the payload types that configure them
+var criticalRestrictions = []struct {
+	name     string
+	payloads []string
+}{
+	{"filevault", []string{"com.apple.MCX.FileVault2"}},
+	{"firewall", []string{"com.apple.security.firewall"}},
+	{"password_policy", []string{"com.apple.mobiledevice.passwordpolicy"}},
+}
+
+// ConfigProfile is an installed configuration profile
+type ConfigProfile struct {
+	Identifier   string `json:"identifier"`
+	DisplayName  string `json:"display_name,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	UUID         string `json:"uuid,omitempty"`
+	// Scope is "computer" or "user"; User names the user of a user profile
+	Scope        string   `json:"scope"`
+	User         string   `json:"user,omitempty"`
+	InstallDate  string   `json:"install_date,omitempty"`
+	PayloadTypes []string `json:"payload_types"`
+	// RemovalDisallowed is true if the profile cannot be removed locally
+	RemovalDisallowed bool `json:"removal_disallowed"`
+	// Managed is true if the profile appears to be delivered by MDM: it is
+	// the enrollment profile, cannot be removed locally, or comes from the
+	// enrolling organization
+	Managed bool `json:"managed"`
+}
+
+// ManagedRestriction reports whether a security setting is set by a profile
+// and whether that profile is managed
+type ManagedRestriction struct {
+	Name       string `json:"name"`
+	Configured bool   `json:"configured"`
+	Managed    bool   `json:"managed"`
+	// Profile is the identifier of the profile that sets it
+	Profile string `json:"profile,omitempty"`
+}
+
+// ConfigProfilesResult reports configuration profiles and MDM enrollment on macOS
+type ConfigProfilesResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform       string `json:"platform"`
+	MDMEnrolled    bool   `json:"mdm_enrolled"`
+	UserApproved   bool   `json:"user_approved"`
+	EnrolledViaDEP bool   `json:"enrolled_via_dep"`
+	MDMServer      string `json:"mdm_server,omitempty"`
+	// Supervised is true for Macs enrolled through Automated Device
+	// Enrollment (DEP), which macOS 11 and later supervises
+	Supervised   bool                 `json:"supervised"`
+	Profiles     []ConfigProfile      `json:"profiles"`
+	Restrictions []ManagedRestriction `json:"restrictions"`
+	Error        *ProbeError          `json:"error,omitempty"`
+}
+
+// GetConfigProfiles lists configuration profiles and MDM enrollment. Without
+// root only the current user's profiles may be listed.
+func GetConfigProfiles() (*ConfigProfilesResult, error) {
+	return GetConfigProfilesContext(context.Background())
+}
+
+// GetConfigProfilesContext is like GetConfigProfiles but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetConfigProfilesContext(ctx context.Context) (*ConfigProfilesResult, error) {
+	if result, ok, err := loadFixture[ConfigProfilesResult](ctx, "profiles"); ok {
+		return result, err
+	}
+	if !IsConfigProfilesSupported() {
+		return nil, newProbeError(ctx, ErrUnsupportedPlatform, "profiles", "configuration profiles are only checked on macOS")
+	}
+	result := &ConfigProfilesResult{Platform: "darwin", Profiles: []ConfigProfile{}}
+
+	if out, err := runCommand(ctx, "profiles", "status", "-type", "enrollment"); err == nil {
+		parseEnrollmentStatus(string(out), result)
+	} else {
+		result.Error = classifyExecError(ctx, "profiles", err)
+	}
+
+	out, err := runCommand(ctx, "profiles", "show", "-output", "stdout-xml")
+	if err != nil {
+		if result.Error == nil {
+			result.Error = classifyExecError(ctx, "profiles", err)
+		}
+	} else if profiles, err := parseProfilesPlist(out); err != nil {
+		result.Error = newProbeError(ctx, ErrProbeFailed, "profiles", err.Error())
+	} else {
+		result.Profiles = profiles
+	}
+
+	markManagedProfiles(result)
+	result.Restrictions = profileRestrictions(result.Profiles)
+	return result, nil
+}
+
+// IsConfigProfilesSupported returns true on macOS
+func IsConfigProfilesSupported() bool {
+	return runtime.GOOS == "darwin"
+}
+
+// parseEnrollmentStatus parses `profiles status -type enrollment`:
+//
+//	Enrolled via DEP: Yes
+//	MDM enrollment: Yes (User Approved)
+//	MDM server: https://mdm.example.com/mdm
+func parseEnrollmentStatus(output string, result *ConfigProfilesResult) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		yes := strings.HasPrefix(strings.ToLower(value), "yes")
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "enrolled via dep":
+			result.EnrolledViaDEP = yes
+		case "mdm enrollment":
+			result.MDMEnrolled = yes
+			result.UserApproved = yes && strings.Contains(strings.ToLower(value), "user approved")
+		case "mdm server":
+			result.MDMServer = value
+		}
+	}
+	// Automated Device Enrollment implies user approval and supervision
+	if result.EnrolledViaDEP && result.MDMEnrolled {
+		result.UserApproved = true
+		result.Supervised = true
+	}
+}
+
+// parseProfilesPlist parses `profiles show -output stdout-xml`, a dictionary
+// of profile arrays keyed by "_computerlevel" or the user name
+func parseProfilesPlist(data []byte) ([]ConfigProfile, error) {
+	root, err := decodePlist(data)
+	if err != nil {
+		return nil, err
+	}
+	dict, ok := root.(map[string]any)
+	if !ok {
+		return nil, errors.New("profiles: expected a dictionary")
+	}
+
+	owners := make([]string, 0, len(dict))
+	for owner := range dict {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	profiles := []ConfigProfile{}
+	for _, owner := range owners {
+		for _, p := range plistDicts(dict, owner) {
+			profile := ConfigProfile{
+				Identifier:        plistString(p, "ProfileIdentifier"),
+				DisplayName:       plistString(p, "ProfileDisplayName"),
+				Organization:      plistString(p, "ProfileOrganization"),
+				UUID:              plistString(p, "ProfileUUID"),
+				Scope:             ProfileScopeComputer,
+				InstallDate:       plistString(p, "ProfileInstallDate"),
+				PayloadTypes:      []string{},
+				RemovalDisallowed: plistFlag(p, "ProfileRemovalDisallowed"),
+			}
+			if owner != "_computerlevel" {
+				profile.Scope, profile.User = ProfileScopeUser, owner
+			}
+			for _, item := range plistDicts(p, "ProfileItems") {
+				if t := plistString(item, "PayloadType"); t != "" && !slices.Contains(profile.PayloadTypes, t) {
+					profile.PayloadTypes = append(profile.PayloadTypes, t)
+				}
+			}
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, nil
+}
+
+// plistFlag returns dict[key] as a bool; `profiles` writes some flags as
+// the strings "TRUE" and "FALSE"
+func plistFlag(dict map[string]any, key string) bool {
+	if s, ok := dict[key].(string); ok {
+		return strings.EqualFold(s, "true")
+	}
+	return plistBool(dict, key)
+}
+
+// markManagedProfiles decides which profiles came from MDM. macOS does not
+// record the source of a profile, so this is a heuristic: the enrollment
+// profile, profiles that cannot be removed, and profiles from the same
+// organization as the enrollment profile count as managed.
+func markManagedProfiles(result *ConfigProfilesResult) {
+	if !result.MDMEnrolled {
+		return
+	}
+	var mdmOrg string
+	for _, p := range result.Profiles {
+		if slices.Contains(p.PayloadTypes, mdmPayloadType) {
+			mdmOrg = p.Organization
+			break
+		}
+	}
+	for i := range result.Profiles {
+		p := &result.Profiles[i]
+		p.Managed = slices.Contains(p.PayloadTypes, mdmPayloadType) || p.RemovalDisallowed ||
+			mdmOrg != "" && p.Organization == mdmOrg
+	}
+}
+
+// profileRestrictions reports which critical restrictions profiles set,
+// preferring a managed profile when several set the same one
+func profileRestrictions(profiles []ConfigProfile) []ManagedRestriction {
+	restrictions := make([]ManagedRestriction, 0, len(criticalRestrictions))
+	for _, c := range criticalRestrictions {
+		r := ManagedRestriction{Name: c.name}
+		for _, p := range profiles {
+			if !slices.ContainsFunc(p.PayloadTypes, func(t string) bool { return slices.Contains(c.payloads, t) }) {
+				continue
+			}
+			if !r.Configured || p.Managed && !r.Managed {
+				r.Configured, r.Managed, r.Profile = true, p.Managed, p.Identifier
+			}
+		}
+		restrictions = append(restrictions, r)
+	}
+	return restrictions
+}
+
+// restrictionSource describes who sets a restriction
+func restrictionSource(st Styler, r ManagedRestriction) string {
+	switch {
+	case !r.Configured:
+		return st.Muted("not configured")
+	case r.Managed:
+		return st.Success(IconCheck + " MDM")
+	}
+	return st.Warning("user-installed")
+}
+
+// FormatConfigProfilesTable formats configuration profiles as a colored table
+func FormatConfigProfilesTable(result *ConfigProfilesResult) string {
+	return FormatConfigProfilesTableWith(CurrentStyler(), result)
+}
+
+// FormatConfigProfilesTableWith is like FormatConfigProfilesTable but styles
+// the table with st
+func FormatConfigProfilesTableWith(st Styler, result *ConfigProfilesResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " Configuration Profiles"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n")
+		if result.Error.Hint != "" {
+			sb.WriteString(st.Muted("  " + result.Error.Hint))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.TableTop(24, 28))
+	sb.WriteString("\n")
+	row := func(label, value string) {
+		sb.WriteString(st.TableRowColored(PadRight(label, 24), PadRight(value, 28)))
+		sb.WriteString("\n")
+	}
+	row("MDM Enrolled", st.BoolToStatusColored(result.MDMEnrolled))
+	row("User Approved", st.BoolToStatusColored(result.UserApproved))
+	row("Enrolled via DEP", st.BoolToStatusColored(result.EnrolledViaDEP))
+	row("Supervised", st.BoolToStatusColored(result.Supervised))
+	sb.WriteString(st.TableSeparator(24, 28))
+	sb.WriteString("\n")
+	for _, r := range result.Restrictions {
+		row(strings.ReplaceAll(r.Name, "_", " "), restrictionSource(st, r))
+	}
+	sb.WriteString(st.TableBottom(24, 28))
+	sb.WriteString("\n")
+	if result.MDMServer != "" {
+		sb.WriteString(st.Muted("MDM server: " + result.MDMServer))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(st.BoldText(fmt.Sprintf("Installed Profiles (%d):", len(result.Profiles))))
+	sb.WriteString("\n")
+	for _, p := range result.Profiles {
+		name := p.DisplayName
+		if name == "" {
+			name = p.Identifier
+		}
+		source := st.Warning("user-installed")
+		if p.Managed {
+			source = st.Success("MDM")
+		}
+		scope := p.Scope
+		if p.User != "" {
+			scope += " " + p.User
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s %s %s\n", IconArrow, name, source, st.Muted("("+scope+")")))
+		if len(p.PayloadTypes) > 0 {
+			sb.WriteString(st.Muted("      " + strings.Join(p.PayloadTypes, ", ")))
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// FormatConfigProfiles formats configuration profiles in the specified format
+func FormatConfigProfiles(result *ConfigProfilesResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatConfigProfilesTableWith(st, result)
+	}, format)
+}
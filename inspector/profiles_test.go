@@ -0,0 +1,80 @@
+package inspector
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseEnrollmentStatus(t *testing.T) {
+	var r ConfigProfilesResult
+	parseEnrollmentStatus(string(fixture(t, "darwin/profiles_status_enrollment.txt")), &r)
+	if !r.MDMEnrolled || !r.UserApproved || !r.EnrolledViaDEP || !r.Supervised || r.MDMServer != "https://mdm.example.com/mdm" {
+		t.Errorf("result = %+v", r)
+	}
+
+	r = ConfigProfilesResult{}
+	parseEnrollmentStatus("Enrolled via DEP: No\nMDM enrollment: Yes\n", &r)
+	if !r.MDMEnrolled || r.UserApproved || r.Supervised {
+		t.Errorf("enrollment that is not user approved should not be supervised: %+v", r)
+	}
+
+	r = ConfigProfilesResult{}
+	parseEnrollmentStatus("Enrolled via DEP: No\nMDM enrollment: No\n", &r)
+	if r.MDMEnrolled || r.UserApproved {
+		t.Errorf("result = %+v", r)
+	}
+}
+
+func TestParseProfilesPlist(t *testing.T) {
+	profiles, err := parseProfilesPlist(fixture(t, "darwin/profiles_show.plist"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 3 {
+		t.Fatalf("got %d profiles, want 3", len(profiles))
+	}
+	mdm := profiles[0]
+	if mdm.Identifier != "com.example.mdm" || mdm.Scope != ProfileScopeComputer || !mdm.RemovalDisallowed ||
+		!slices.Equal(mdm.PayloadTypes, []string{"com.apple.mdm", "com.apple.security.pkcs12"}) {
+		t.Errorf("MDM profile = %+v", mdm)
+	}
+	if p := profiles[2]; p.Scope != ProfileScopeUser || p.User != "alice" || p.RemovalDisallowed {
+		t.Errorf("user profile = %+v", p)
+	}
+
+	if _, err := parseProfilesPlist([]byte("<plist><array/></plist>")); err == nil {
+		t.Error("a non-dictionary root should fail")
+	}
+}
+
+func TestProfileRestrictions(t *testing.T) {
+	profiles, err := parseProfilesPlist(fixture(t, "darwin/profiles_show.plist"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &ConfigProfilesResult{MDMEnrolled: true, Profiles: profiles}
+	markManagedProfiles(result)
+	if !profiles[0].Managed || !profiles[1].Managed || profiles[2].Managed {
+		t.Errorf("managed = %v, %v, %v; want the enrolling organization's profiles only",
+			profiles[0].Managed, profiles[1].Managed, profiles[2].Managed)
+	}
+
+	want := []ManagedRestriction{
+		{Name: "filevault", Configured: true, Managed: true, Profile: "com.example.baseline"},
+		{Name: "firewall", Configured: true, Managed: true, Profile: "com.example.baseline"},
+		{Name: "password_policy", Configured: true, Managed: false, Profile: "local.passcode"},
+	}
+	if got := profileRestrictions(profiles); !slices.Equal(got, want) {
+		t.Errorf("restrictions = %+v, want %+v", got, want)
+	}
+
+	// Without MDM enrollment nothing is managed
+	unmanaged := &ConfigProfilesResult{Profiles: []ConfigProfile{{Identifier: "x", RemovalDisallowed: true}}}
+	markManagedProfiles(unmanaged)
+	if unmanaged.Profiles[0].Managed {
+		t.Error("profiles on an unenrolled Mac should not be managed")
+	}
+	if got := profileRestrictions(nil); len(got) != 3 || got[0].Configured {
+		t.Errorf("restrictions without profiles = %+v", got)
+	}
+}
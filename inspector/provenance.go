@@ -0,0 +1,247 @@
+package inspector
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Kinds of provenance sources
+const (
+	SourceCommand = "command"
+	SourceFile    = "file"
+	SourceAPI     = "api"
+	SourceFixture = "fixture"
+)
+
+// ProvenanceSource is one read a probe made: a command it ran, a file it
+// read, an OS API it called, or the fixture it was served from
+type ProvenanceSource struct {
+	Kind string `json:"kind"`
+	// Detail is the command line, file path, or API call
+	Detail string    `json:"detail"`
+	Time   time.Time `json:"time"`
+	// Error is set when the read failed, so the field holds a default
+	Error string `json:"error,omitempty"`
+}
+
+// FieldProvenance lists the reads that produced a field of a result
+type FieldProvenance struct {
+	// Field is the JSON field of the result: a check's field (e.g.
+	// encryption) or a finding (e.g. findings[2]) of a summary, or the
+	// result's kind (e.g. tpm) for all of a single check's result
+	Field string `json:"field"`
+	// Check is the check the reads were made by, for a summary
+	Check   string             `json:"check,omitempty"`
+	Sources []ProvenanceSource `json:"sources"`
+}
+
+// provenanceRecorder collects the reads made while provenance is on, by
+// the check they were made for ("" outside a summary check)
+type provenanceRecorder struct {
+	mu      sync.Mutex
+	check   string
+	checks  []string
+	sources map[string][]ProvenanceSource
+}
+
+// activeProvenance is the recorder reads are noted in, nil when off
+var activeProvenance atomic.Pointer[provenanceRecorder]
+
+// explainMu runs Explain calls one at a time, since reads are recorded
+// process-wide
+var explainMu sync.Mutex
+
+// SetProvenance turns recording where results come from on or off. Turning
+// it on discards earlier records. While it is on, the summary runs its
+// checks one at a time so each read is attributed to its check, and JSON
+// and table output include the provenance of the result.
+func SetProvenance(enabled bool) {
+	if enabled {
+		activeProvenance.Store(newProvenanceRecorder())
+	} else {
+		activeProvenance.Store(nil)
+	}
+}
+
+// ProvenanceEnabled reports whether provenance is being recorded
+func ProvenanceEnabled() bool {
+	return activeProvenance.Load() != nil
+}
+
+// Provenance returns where the fields of result came from, from the reads
+// recorded since SetProvenance(true), or nil when provenance is off
+func Provenance(result any) []FieldProvenance {
+	if r := activeProvenance.Load(); r != nil {
+		return r.fields(result)
+	}
+	return nil
+}
+
+// Explain runs collect with provenance recorded and returns its result with
+// the provenance of the result's fields. Explained collections run one at a
+// time; reads other goroutines make meanwhile are recorded as well.
+func Explain[T any](collect func() (T, error)) (T, []FieldProvenance, error) {
+	explainMu.Lock()
+	defer explainMu.Unlock()
+	r := newProvenanceRecorder()
+	prev := activeProvenance.Swap(r)
+	defer activeProvenance.Store(prev)
+	result, err := collect()
+	return result, r.fields(result), err
+}
+
+func newProvenanceRecorder() *provenanceRecorder {
+	return &provenanceRecorder{sources: make(map[string][]ProvenanceSource)}
+}
+
+// recordSource notes a read for provenance, when it is on
+func recordSource(kind, detail string, err error) {
+	r := activeProvenance.Load()
+	if r == nil {
+		return
+	}
+	s := ProvenanceSource{Kind: kind, Detail: detail, Time: time.Now().UTC()}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.sources[r.check]; !ok {
+		r.checks = append(r.checks, r.check)
+	}
+	r.sources[r.check] = append(r.sources[r.check], s)
+}
+
+// provenanceCheck attributes the reads that follow to check, until the
+// returned function is called
+func provenanceCheck(check string) func() {
+	r := activeProvenance.Load()
+	if r == nil {
+		return func() {}
+	}
+	r.mu.Lock()
+	prev := r.check
+	r.check = check
+	r.mu.Unlock()
+	return func() {
+		r.mu.Lock()
+		r.check = prev
+		r.mu.Unlock()
+	}
+}
+
+// summaryFields maps the checks whose summary field is not named after
+// them to that field
+var summaryFields = map[string]string{
+	CheckBrowser:    "browsers",
+	CheckUSBStorage: "usb",
+}
+
+// fields attributes the recorded reads to the fields of result
+func (r *provenanceRecorder) fields(result any) []FieldProvenance {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kind := resultKind(result)
+	summary, ok := result.(*SecuritySummary)
+	if !ok || summary == nil {
+		var all []ProvenanceSource
+		for _, check := range r.checks {
+			all = append(all, r.sources[check]...)
+		}
+		if len(all) == 0 {
+			return nil
+		}
+		return []FieldProvenance{{Field: kind, Sources: all}}
+	}
+
+	var fields []FieldProvenance
+	for _, check := range r.checks {
+		field, ok := summaryFields[check]
+		switch {
+		case check == "":
+			field = kind
+		case !ok:
+			field = check
+		}
+		fields = append(fields, FieldProvenance{Field: field, Check: check, Sources: r.sources[check]})
+	}
+	for i, f := range summary.Findings {
+		check := f.Check
+		if check == CheckWSLHost {
+			check = "environment"
+		}
+		if sources, ok := r.sources[check]; ok {
+			fields = append(fields, FieldProvenance{Field: fmt.Sprintf("findings[%d]", i), Check: f.Check, Sources: sources})
+		}
+	}
+	return fields
+}
+
+// readFile reads a file for a probe, recording it for provenance
+func readFile(name string) ([]byte, error) {
+	data, err := os.ReadFile(name) // #nosec G304 -- probes read fixed system paths
+	recordSource(SourceFile, name, err)
+	return data, err
+}
+
+// readFSFile reads name from fsys like fs.ReadFile, recording it for
+// provenance under its host path when fsys is an os.DirFS
+func readFSFile(fsys fs.FS, name string) ([]byte, error) {
+	data, err := fs.ReadFile(fsys, name)
+	recordSource(SourceFile, hostPath(fsys, name), err)
+	return data, err
+}
+
+// hostPath returns the path on the host of name in fsys, or name itself
+// when fsys is not a directory of the host (os.DirFS)
+func hostPath(fsys fs.FS, name string) string {
+	if v := reflect.ValueOf(fsys); v.Kind() == reflect.String {
+		return filepath.Join(v.String(), filepath.FromSlash(name))
+	}
+	return name
+}
+
+// FormatProvenanceTable formats provenance as a colored listing
+func FormatProvenanceTable(fields []FieldProvenance) string {
+	return FormatProvenanceTableWith(CurrentStyler(), fields)
+}
+
+// FormatProvenanceTableWith is like FormatProvenanceTable but styles the
+// listing with st
+func FormatProvenanceTableWith(st Styler, fields []FieldProvenance) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconInfo + " " + T("Provenance")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n")
+	if len(fields) == 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(T("No commands, files, or APIs were read")))
+		sb.WriteString("\n")
+	}
+	for _, f := range fields {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText(f.Field))
+		if f.Check != "" && f.Check != f.Field {
+			sb.WriteString(st.Muted(" (" + f.Check + ")"))
+		}
+		sb.WriteString("\n")
+		for _, s := range f.Sources {
+			line := fmt.Sprintf("  %s %s %s", st.Muted(s.Time.Local().Format("15:04:05.000")), PadRight(s.Kind, 7), s.Detail)
+			if s.Error != "" {
+				line += " " + st.Warning(s.Error)
+			}
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,88 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestExplain(t *testing.T) {
+	fake := NewFakeRunner().Set("fdesetup status", []byte("FileVault is On.\n"))
+	defer SetCommandRunner(SetCommandRunner(fake))
+
+	out, provenance, err := Explain(func() ([]byte, error) {
+		_, _ = readFSFile(fstest.MapFS{}, "proc/missing")
+		return runCommand(context.Background(), "fdesetup", "status")
+	})
+	if err != nil || string(out) != "FileVault is On.\n" {
+		t.Fatalf("Explain = %q, %v", out, err)
+	}
+	if len(provenance) != 1 || len(provenance[0].Sources) != 2 {
+		t.Fatalf("provenance = %+v, want one field with two sources", provenance)
+	}
+	file, cmd := provenance[0].Sources[0], provenance[0].Sources[1]
+	if file.Kind != SourceFile || file.Detail != "proc/missing" || file.Error == "" {
+		t.Errorf("file source = %+v, want a failed read", file)
+	}
+	if cmd.Kind != SourceCommand || cmd.Detail != "fdesetup status" || cmd.Error != "" || cmd.Time.IsZero() {
+		t.Errorf("command source = %+v", cmd)
+	}
+
+	if ProvenanceEnabled() {
+		t.Error("Explain should turn provenance off again")
+	}
+	if _, err := runCommand(context.Background(), "fdesetup", "status"); err != nil || Provenance(out) != nil {
+		t.Error("reads outside Explain should not be recorded")
+	}
+}
+
+func TestProvenance_Summary(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(FixtureEnv, dir)
+	t.Setenv(OnlyChecksEnv, "tpm,encryption")
+	t.Setenv(DisableChecksEnv, "")
+	t.Setenv(ScanProfileEnv, "")
+	if err := os.WriteFile(filepath.Join(dir, "tpm.json"), []byte(`{"present": false, "type": "none"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, provenance, err := Explain(func() (*SecuritySummary, error) {
+		return GetSecuritySummaryWithOptionsContext(context.Background(), SummaryOptions{Parallel: true})
+	})
+	if err != nil {
+		t.Fatalf("GetSecuritySummary: %v", err)
+	}
+	byField := map[string]FieldProvenance{}
+	for _, f := range provenance {
+		byField[f.Field] = f
+	}
+	tpm, ok := byField["tpm"]
+	if !ok || tpm.Check != CheckTPM || tpm.Sources[0].Detail != filepath.Join(dir, "tpm.json") || tpm.Sources[0].Error != "" {
+		t.Errorf("tpm provenance = %+v, want the fixture", tpm)
+	}
+	// Encryption has no fixture, so its read failed
+	if enc := byField["encryption"]; len(enc.Sources) == 0 || enc.Sources[0].Error == "" {
+		t.Errorf("encryption provenance = %+v, want the missing fixture", enc)
+	}
+	// Findings point at the reads of their check
+	i := slices.IndexFunc(summary.Findings, func(f Finding) bool { return f.Check == CheckTPM })
+	if i < 0 {
+		t.Fatal("no TPM finding")
+	}
+	if f := byField[fmt.Sprintf("findings[%d]", i)]; f.Check != CheckTPM || len(f.Sources) != len(tpm.Sources) {
+		t.Errorf("finding provenance = %+v, want the TPM reads", f)
+	}
+}
+
+func TestHostPath(t *testing.T) {
+	if got, want := hostPath(os.DirFS("/"), "sys/class/tpm"), filepath.Join("/", "sys", "class", "tpm"); got != want {
+		t.Errorf("hostPath(os.DirFS) = %q, want %q", got, want)
+	}
+	if got := hostPath(fstest.MapFS{}, "proc/1/cgroup"); got != "proc/1/cgroup" {
+		t.Errorf("hostPath(MapFS) = %q, want the name", got)
+	}
+}
@@ -0,0 +1,195 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrRegistryNotFound is returned by a RegistryReader for a missing key or value
+var ErrRegistryNotFound = errors.New("registry value not found")
+
+// RegistryReader reads the Windows registry for registry-backed checks.
+// Paths start with the hive, HKLM or HKCU, e.g.
+// `HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Policies\System`.
+// Replace it with SetRegistryReader to inject values in tests.
+type RegistryReader interface {
+	// Integer returns a REG_DWORD or REG_QWORD value
+	Integer(path, name string) (uint64, error)
+	// String returns a REG_SZ or REG_EXPAND_SZ value
+	String(path, name string) (string, error)
+	// SubKeys returns the names of a key's subkeys
+	SubKeys(path string) ([]string, error)
+}
+
+var (
+	registryMu sync.RWMutex
+	regReader  RegistryReader = systemRegistry{}
+)
+
+// SetRegistryReader replaces the reader used by all registry-backed checks
+// and returns the previous one:
+//
+//	defer inspector.SetRegistryReader(inspector.SetRegistryReader(fake))
+func SetRegistryReader(r RegistryReader) RegistryReader {
+	if r == nil {
+		r = systemRegistry{}
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	prev := regReader
+	regReader = r
+	return prev
+}
+
+// currentRegistry returns the reader in effect
+func currentRegistry() RegistryReader {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return regReader
+}
+
+// registryInteger reads an integer value; ok is false if it is not set
+func registryInteger(ctx context.Context, path, name string) (value uint64, ok bool, err error) {
+	value, err = currentRegistry().Integer(path, name)
+	return registryResult(ctx, path, name, value, err)
+}
+
+// registryString reads a string value; ok is false if it is not set
+func registryString(ctx context.Context, path, name string) (value string, ok bool, err error) {
+	value, err = currentRegistry().String(path, name)
+	return registryResult(ctx, path, name, value, err)
+}
+
+// registrySubKeys lists a key's subkeys; a missing key has none
+func registrySubKeys(ctx context.Context, path string) ([]string, error) {
+	keys, err := currentRegistry().SubKeys(path)
+	keys, _, err = registryResult(ctx, path, "", keys, err)
+	return keys, err
+}
+
+// registryResult turns a missing value into ok=false and logs and records
+// the read
+func registryResult[T any](ctx context.Context, path, name string, value T, err error) (T, bool, error) {
+	recordSource(SourceAPI, `registry `+path+`\`+name, err)
+	log := loggerFrom(ctx).With("key", path, "value", name)
+	switch {
+	case errors.Is(err, ErrRegistryNotFound):
+		log.Debug("registry value not set")
+		return value, false, nil
+	case err != nil:
+		log.Debug("registry read failed", "err", err)
+		return value, false, err
+	}
+	log.Debug("registry read", "data", value)
+	return value, true, nil
+}
+
+// classifyRegistryError turns an error from reading the registry into a ProbeError
+func classifyRegistryError(ctx context.Context, path string, err error) *ProbeError {
+	if strings.Contains(strings.ToLower(err.Error()), "access is denied") {
+		return newProbeError(ctx, ErrPermissionDenied, path, "access denied reading the registry")
+	}
+	return newProbeError(ctx, ErrProbeFailed, path, err.Error())
+}
+
+// FakeRegistry is a RegistryReader that returns values set in advance.
+// Values that were not set are reported as missing.
+type FakeRegistry struct {
+	mu     sync.Mutex
+	values map[string]any
+	errs   map[string]error
+	// paths maps the lowercased paths of set values to their spelling
+	paths map[string]string
+}
+
+// NewFakeRegistry creates an empty FakeRegistry
+func NewFakeRegistry() *FakeRegistry {
+	return &FakeRegistry{values: make(map[string]any), errs: make(map[string]error), paths: make(map[string]string)}
+}
+
+// registryKey joins a path and value name case-insensitively, like the registry
+func registryKey(path, name string) string {
+	return strings.ToLower(path + `\` + name)
+}
+
+// SetInteger sets an integer value
+func (f *FakeRegistry) SetInteger(path, name string, value uint64) *FakeRegistry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[registryKey(path, name)] = value
+	f.paths[strings.ToLower(path)] = path
+	return f
+}
+
+// SetString sets a string value
+func (f *FakeRegistry) SetString(path, name, value string) *FakeRegistry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[registryKey(path, name)] = value
+	f.paths[strings.ToLower(path)] = path
+	return f
+}
+
+// SetError makes reading the value fail with err, e.g. access denied
+func (f *FakeRegistry) SetError(path, name string, err error) *FakeRegistry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errs[registryKey(path, name)] = err
+	return f
+}
+
+// Integer returns the integer value set for path and name
+func (f *FakeRegistry) Integer(path, name string) (uint64, error) {
+	return fakeRegistryValue[uint64](f, path, name)
+}
+
+// String returns the string value set for path and name
+func (f *FakeRegistry) String(path, name string) (string, error) {
+	return fakeRegistryValue[string](f, path, name)
+}
+
+// SubKeys returns the subkeys of path that hold values set on the fake
+func (f *FakeRegistry) SubKeys(path string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	prefix := strings.ToLower(path) + `\`
+	var keys []string
+	for lower, spelled := range f.paths {
+		if !strings.HasPrefix(lower, prefix) {
+			continue
+		}
+		name, _, _ := strings.Cut(spelled[len(prefix):], `\`)
+		if !slices.Contains(keys, name) {
+			keys = append(keys, name)
+		}
+	}
+	if keys == nil {
+		return nil, fmt.Errorf("%s: %w", path, ErrRegistryNotFound)
+	}
+	slices.Sort(keys)
+	return keys, nil
+}
+
+// fakeRegistryValue looks up a value of the expected type
+func fakeRegistryValue[T any](f *FakeRegistry, path, name string) (T, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var zero T
+	key := registryKey(path, name)
+	if err := f.errs[key]; err != nil {
+		return zero, err
+	}
+	v, ok := f.values[key]
+	if !ok {
+		return zero, fmt.Errorf("%s\\%s: %w", path, name, ErrRegistryNotFound)
+	}
+	typed, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("%s\\%s has type %T, not %T", path, name, v, zero)
+	}
+	return typed, nil
+}
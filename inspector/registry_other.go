@@ -0,0 +1,26 @@
+//go:build !windows
+
+package inspector
+
+import "errors"
+
+// errNoRegistry is returned when reading the registry outside Windows
+var errNoRegistry = errors.New("the registry is only available on Windows")
+
+// systemRegistry reports every read as failed outside Windows
+type systemRegistry struct{}
+
+// Integer fails outside Windows
+func (systemRegistry) Integer(path, name string) (uint64, error) {
+	return 0, errNoRegistry
+}
+
+// String fails outside Windows
+func (systemRegistry) String(path, name string) (string, error) {
+	return "", errNoRegistry
+}
+
+// SubKeys fails outside Windows
+func (systemRegistry) SubKeys(path string) ([]string, error) {
+	return nil, errNoRegistry
+}
@@ -0,0 +1,58 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRegistryHelpers(t *testing.T) {
+	fake := NewFakeRegistry().
+		SetInteger(`HKLM\SOFTWARE\Test`, "Level", 2).
+		SetString(`HKLM\SOFTWARE\Test`, "Mode", "Block").
+		SetError(`HKLM\SOFTWARE\Test`, "Locked", errors.New("Access is denied."))
+	defer SetRegistryReader(SetRegistryReader(fake))
+
+	if v, ok, err := registryInteger(context.Background(), `hklm\software\test`, "level"); v != 2 || !ok || err != nil {
+		t.Errorf("registryInteger = %d, %v, %v; lookups are case-insensitive", v, ok, err)
+	}
+	if v, ok, err := registryString(context.Background(), `HKLM\SOFTWARE\Test`, "Mode"); v != "Block" || !ok || err != nil {
+		t.Errorf("registryString = %q, %v, %v", v, ok, err)
+	}
+	if _, ok, err := registryInteger(context.Background(), `HKLM\SOFTWARE\Test`, "Missing"); ok || err != nil {
+		t.Errorf("a missing value should be ok=false without an error, got %v, %v", ok, err)
+	}
+	if _, ok, err := registryInteger(context.Background(), `HKLM\SOFTWARE\Test`, "Mode"); ok || err == nil {
+		t.Error("reading a string as an integer should fail")
+	}
+
+	_, _, err := registryInteger(context.Background(), `HKLM\SOFTWARE\Test`, "Locked")
+	if pe := classifyRegistryError(context.Background(), `HKLM\SOFTWARE\Test`, err); !errors.Is(pe, ErrPermissionDenied) {
+		t.Errorf("classifyRegistryError = %v, want permission denied", pe)
+	}
+}
+
+func TestSetRegistryReaderNil(t *testing.T) {
+	prev := SetRegistryReader(nil)
+	defer SetRegistryReader(prev)
+	if _, ok := currentRegistry().(systemRegistry); !ok {
+		t.Errorf("SetRegistryReader(nil) should restore the system registry, got %T", currentRegistry())
+	}
+}
+
+func TestFakeRegistrySubKeys(t *testing.T) {
+	fake := NewFakeRegistry().
+		SetInteger(`HKLM\SYSTEM\Interfaces\Tcpip_{B}`, "NetbiosOptions", 2).
+		SetInteger(`HKLM\SYSTEM\Interfaces\Tcpip_{A}\Nested`, "Value", 1).
+		SetInteger(`HKLM\SYSTEM\Other`, "Value", 1)
+	defer SetRegistryReader(SetRegistryReader(fake))
+
+	keys, err := registrySubKeys(context.Background(), `HKLM\SYSTEM\Interfaces`)
+	if err != nil || !slices.Equal(keys, []string{"Tcpip_{A}", "Tcpip_{B}"}) {
+		t.Errorf("registrySubKeys = %v, %v", keys, err)
+	}
+	if keys, err := registrySubKeys(context.Background(), `HKLM\SYSTEM\Missing`); keys != nil || err != nil {
+		t.Errorf("a missing key should have no subkeys and no error, got %v, %v", keys, err)
+	}
+}
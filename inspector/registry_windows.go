@@ -0,0 +1,71 @@
+//go:build windows
+
+package inspector
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// systemRegistry reads the Windows registry
+type systemRegistry struct{}
+
+// openRegistryKey opens a key from a path that starts with its hive
+func openRegistryKey(path string, access uint32) (registry.Key, error) {
+	hive, subkey, _ := strings.Cut(path, `\`)
+	var root registry.Key
+	switch strings.ToUpper(hive) {
+	case "HKLM", "HKEY_LOCAL_MACHINE":
+		root = registry.LOCAL_MACHINE
+	case "HKCU", "HKEY_CURRENT_USER":
+		root = registry.CURRENT_USER
+	default:
+		return 0, fmt.Errorf("unsupported registry hive %q", hive)
+	}
+	k, err := registry.OpenKey(root, subkey, access)
+	if errors.Is(err, registry.ErrNotExist) {
+		return 0, fmt.Errorf("%s: %w", path, ErrRegistryNotFound)
+	}
+	return k, err
+}
+
+// Integer returns a REG_DWORD or REG_QWORD value
+func (systemRegistry) Integer(path, name string) (uint64, error) {
+	k, err := openRegistryKey(path, registry.QUERY_VALUE)
+	if err != nil {
+		return 0, err
+	}
+	defer k.Close()
+	v, _, err := k.GetIntegerValue(name)
+	if errors.Is(err, registry.ErrNotExist) {
+		return 0, fmt.Errorf("%s\\%s: %w", path, name, ErrRegistryNotFound)
+	}
+	return v, err
+}
+
+// String returns a REG_SZ or REG_EXPAND_SZ value
+func (systemRegistry) String(path, name string) (string, error) {
+	k, err := openRegistryKey(path, registry.QUERY_VALUE)
+	if err != nil {
+		return "", err
+	}
+	defer k.Close()
+	v, _, err := k.GetStringValue(name)
+	if errors.Is(err, registry.ErrNotExist) {
+		return "", fmt.Errorf("%s\\%s: %w", path, name, ErrRegistryNotFound)
+	}
+	return v, err
+}
+
+// SubKeys returns the names of a key's subkeys
+func (systemRegistry) SubKeys(path string) ([]string, error) {
+	k, err := openRegistryKey(path, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		return nil, err
+	}
+	defer k.Close()
+	return k.ReadSubKeyNames(-1)
+}
@@ -0,0 +1,53 @@
+package inspector
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RemediationsEnv replaces the remediation of findings, as a JSON object of
+// remediation text by finding ID, e.g. to point users to an internal IT
+// portal instead of the generic advice
+const RemediationsEnv = "OMNITRUST_REMEDIATIONS"
+
+// Remediations returns the remediation overrides from OMNITRUST_REMEDIATIONS
+// by finding ID
+func Remediations() (map[string]string, error) {
+	v := strings.TrimSpace(os.Getenv(RemediationsEnv))
+	if v == "" {
+		return nil, nil
+	}
+	var overrides map[string]string
+	if err := json.Unmarshal([]byte(v), &overrides); err != nil {
+		return nil, fmt.Errorf("%s: %w", RemediationsEnv, err)
+	}
+	for id, text := range overrides {
+		if err := ValidateRemediation(id, text); err != nil {
+			return nil, fmt.Errorf("%s: %w", RemediationsEnv, err)
+		}
+	}
+	return overrides, nil
+}
+
+// ValidateRemediation checks a remediation override of the finding id
+func ValidateRemediation(id, text string) error {
+	if !findingIDPattern.MatchString(id) {
+		return fmt.Errorf("invalid finding ID %q (e.g. secure_boot_disabled; see the findings of posture summary -f json)", id)
+	}
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("the remediation of %s is empty", id)
+	}
+	return nil
+}
+
+// overrideRemediations replaces the remediation of the findings that have
+// an override. The remediation command is kept, so fix can still run it.
+func overrideRemediations(findings []Finding, overrides map[string]string) {
+	for i, f := range findings {
+		if text, ok := overrides[f.ID]; ok {
+			findings[i].Remediation = strings.TrimSpace(text)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package inspector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRemediations(t *testing.T) {
+	t.Setenv(RemediationsEnv, "")
+	if overrides, err := Remediations(); err != nil || overrides != nil {
+		t.Errorf("unset = %v, %v; want none", overrides, err)
+	}
+
+	t.Setenv(RemediationsEnv, `{"tpm_missing": "Open a ticket at https://it.example.com"}`)
+	overrides, err := Remediations()
+	if err != nil || overrides["tpm_missing"] != "Open a ticket at https://it.example.com" {
+		t.Errorf("Remediations = %v, %v", overrides, err)
+	}
+
+	for _, v := range []string{`{"tpm_missing": ""}`, `{"TPM missing": "Ask IT"}`, `["tpm_missing"]`} {
+		t.Setenv(RemediationsEnv, v)
+		if _, err := Remediations(); err == nil || !strings.Contains(err.Error(), RemediationsEnv) {
+			t.Errorf("%s = %s: err = %v, want an error naming the variable", RemediationsEnv, v, err)
+		}
+	}
+}
+
+func TestSummary_Remediations(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(FixtureEnv, dir)
+	t.Setenv(OnlyChecksEnv, CheckTPM)
+	t.Setenv(DisableChecksEnv, "")
+	t.Setenv(ScanProfileEnv, "")
+	t.Setenv(BaselineEnv, filepath.Join(dir, "baseline.json"))
+	t.Setenv(WaiversEnv, filepath.Join(dir, "waivers.json"))
+	t.Setenv(RemediationsEnv, `{"tpm_missing": "  Request a TPM-equipped laptop at https://it.example.com  "}`)
+	if err := os.WriteFile(filepath.Join(dir, "tpm.json"), []byte(`{"present": false, "type": "none"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := GetSecuritySummaryContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := slices.IndexFunc(summary.Findings, func(f Finding) bool { return f.ID == "tpm_missing" })
+	if i < 0 {
+		t.Fatal("no tpm_missing finding")
+	}
+	if got := summary.Findings[i].Remediation; got != "Request a TPM-equipped laptop at https://it.example.com" {
+		t.Errorf("remediation = %q, want the override", got)
+	}
+	for _, format := range []string{"table", "markdown", "json"} {
+		if out := FormatSecuritySummary(summary, format); !strings.Contains(out, "https://it.example.com") {
+			t.Errorf("%s output is missing the override", format)
+		}
+	}
+
+	// An invalid override is ignored rather than failing the summary
+	t.Setenv(RemediationsEnv, `{"tpm_missing": ""}`)
+	if summary, err = GetSecuritySummaryContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if i := slices.IndexFunc(summary.Findings, func(f Finding) bool { return f.ID == "tpm_missing" }); i < 0 || strings.Contains(summary.Findings[i].Remediation, "example.com") {
+		t.Errorf("findings = %+v, want the generic remediation", summary.Findings)
+	}
+}
@@ -0,0 +1,202 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CommandRunner runs the external tools that probes shell out to (fdesetup,
+// diskutil, bputil, dmsetup, cryptsetup, ...). Replace it with
+// SetCommandRunner to inject canned output in tests or to sandbox execution.
+type CommandRunner interface {
+	// Output runs the command and returns its standard output, stopping it
+	// once ctx is done. Failures are reported like exec.Cmd.Output:
+	// *exec.ExitError (with Stderr) for non-zero exits and an error wrapping
+	// exec.ErrNotFound for missing tools.
+	Output(ctx context.Context, name string, args ...string) ([]byte, error)
+	// LookPath reports where the named tool is installed, like exec.LookPath
+	LookPath(file string) (string, error)
+}
+
+// ExecRunner is the default CommandRunner, backed by os/exec
+type ExecRunner struct{}
+
+// commandWaitDelay is how long a killed command's output may keep being
+// read, in case it left children holding its pipes open
+const commandWaitDelay = time.Second
+
+// Output runs the command with os/exec, in the C locale: probes parse the
+// text tools print, which many translate. The command is killed once ctx is
+// done, so a probe that timed out does not leave it running.
+func (ExecRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...) // #nosec G204 -- probes run fixed system tools
+	cmd.Env = cLocaleEnv(os.Environ())
+	cmd.WaitDelay = commandWaitDelay
+	return cmd.Output()
+}
+
+// cLocaleEnv returns env with the locale forced to C: LC_ALL and LANG are
+// set to C, and the per-category LC_* variables and gettext's LANGUAGE list,
+// which can otherwise still pick a translation, are removed
+func cLocaleEnv(env []string) []string {
+	out := make([]string, 0, len(env)+2)
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		if key == "LANG" || key == "LANGUAGE" || strings.HasPrefix(key, "LC_") {
+			continue
+		}
+		out = append(out, kv)
+	}
+	return append(out, "LC_ALL=C", "LANG=C")
+}
+
+// LookPath searches PATH with os/exec
+func (ExecRunner) LookPath(file string) (string, error) {
+	return exec.LookPath(file)
+}
+
+var (
+	runnerMu sync.RWMutex
+	runner   CommandRunner = ExecRunner{}
+)
+
+// SetCommandRunner replaces the runner used by all probes and returns the
+// previous one, so callers can restore it:
+//
+//	defer inspector.SetCommandRunner(inspector.SetCommandRunner(fake))
+func SetCommandRunner(r CommandRunner) CommandRunner {
+	if r == nil {
+		r = ExecRunner{}
+	}
+	runnerMu.Lock()
+	defer runnerMu.Unlock()
+	prev := runner
+	runner = r
+	return prev
+}
+
+// runnerKey is the context key of a runner set by WithCommandRunner
+type runnerKey struct{}
+
+// WithCommandRunner returns a context whose probes run their commands
+// through r instead of the runner set by SetCommandRunner, so callers can
+// sandbox or fake one scan without affecting others running concurrently
+func WithCommandRunner(ctx context.Context, r CommandRunner) context.Context {
+	return context.WithValue(ctx, runnerKey{}, r)
+}
+
+// currentRunner returns the runner in effect for ctx: the one set by
+// WithCommandRunner, or else the process-wide one
+func currentRunner(ctx context.Context) CommandRunner {
+	if r, ok := ctx.Value(runnerKey{}).(CommandRunner); ok && r != nil {
+		return r
+	}
+	runnerMu.RLock()
+	defer runnerMu.RUnlock()
+	return runner
+}
+
+// runCommand runs an external tool through the current runner until ctx is
+// done, counts it toward the check and scan ctx belongs to, records it for
+// provenance, and logs it at debug level
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	countSubprocess(ctx)
+	start := time.Now()
+	out, err := currentRunner(ctx).Output(ctx, name, args...)
+	cmdline := strings.Join(append([]string{name}, args...), " ")
+	recordSource(SourceCommand, cmdline, err)
+	log := loggerFrom(ctx).With("command", cmdline, "duration", time.Since(start))
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			log = log.With("stderr", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		log.Debug("command failed", "err", err)
+	} else {
+		log.Debug("command finished", "bytes", len(out))
+	}
+	return out, err
+}
+
+// lookPath reports whether an external tool is available via ctx's runner
+func lookPath(ctx context.Context, file string) (string, error) {
+	return currentRunner(ctx).LookPath(file)
+}
+
+// FakeRunner is a CommandRunner that returns canned output instead of running
+// anything. Commands are matched by their full command line, e.g.
+// "fdesetup status". Unknown commands fail as if the tool were not installed.
+type FakeRunner struct {
+	mu      sync.Mutex
+	outputs map[string]fakeOutput
+	calls   []string
+}
+
+type fakeOutput struct {
+	stdout []byte
+	err    error
+}
+
+// NewFakeRunner creates an empty FakeRunner
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{outputs: make(map[string]fakeOutput)}
+}
+
+// Set makes cmdline succeed with the given standard output
+func (f *FakeRunner) Set(cmdline string, stdout []byte) *FakeRunner {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.outputs[cmdline] = fakeOutput{stdout: stdout}
+	return f
+}
+
+// SetError makes cmdline fail with err. Use an *exec.ExitError with Stderr
+// set to simulate a tool that ran and failed.
+func (f *FakeRunner) SetError(cmdline string, err error) *FakeRunner {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.outputs[cmdline] = fakeOutput{err: err}
+	return f
+}
+
+// Calls returns the command lines run so far
+func (f *FakeRunner) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// Output returns the canned result for the command line, or ctx's error
+// once it is done
+func (f *FakeRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmdline := strings.Join(append([]string{name}, args...), " ")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, cmdline)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	out, ok := f.outputs[cmdline]
+	if !ok {
+		return nil, &exec.Error{Name: name, Err: exec.ErrNotFound}
+	}
+	return out.stdout, out.err
+}
+
+// LookPath reports a tool as installed if any command line for it is configured
+func (f *FakeRunner) LookPath(file string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for cmdline := range f.outputs {
+		if cmdline == file || strings.HasPrefix(cmdline, file+" ") {
+			return "/usr/bin/" + file, nil
+		}
+	}
+	return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+}
@@ -0,0 +1,141 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixture returns the contents of a golden output file under testdata/
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+func TestFakeRunner(t *testing.T) {
+	exitErr := &exec.ExitError{Stderr: []byte("must be run as root")}
+	fake := NewFakeRunner().
+		Set("fdesetup status", []byte("FileVault is On.\n")).
+		SetError("bputil -d", exitErr)
+
+	out, err := fake.Output(context.Background(), "fdesetup", "status")
+	if err != nil || string(out) != "FileVault is On.\n" {
+		t.Errorf("Output(fdesetup status) = (%q, %v)", out, err)
+	}
+	if _, err := fake.Output(context.Background(), "bputil", "-d"); !errors.Is(err, exitErr) {
+		t.Errorf("Output(bputil -d) error = %v, want %v", err, exitErr)
+	}
+	if _, err := fake.Output(context.Background(), "diskutil", "list"); !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("Output(unknown) error = %v, want exec.ErrNotFound", err)
+	}
+
+	if _, err := fake.LookPath("fdesetup"); err != nil {
+		t.Errorf("LookPath(fdesetup) failed: %v", err)
+	}
+	if _, err := fake.LookPath("fdesetu"); err == nil {
+		t.Error("LookPath should not match tool name prefixes")
+	}
+
+	want := []string{"fdesetup status", "bputil -d", "diskutil list"}
+	if got := fake.Calls(); !slices.Equal(got, want) {
+		t.Errorf("Calls = %v, want %v", got, want)
+	}
+}
+
+func TestSetCommandRunner(t *testing.T) {
+	fake := NewFakeRunner().Set("echo hi", []byte("hi"))
+	prev := SetCommandRunner(fake)
+	defer SetCommandRunner(prev)
+
+	if _, ok := prev.(ExecRunner); !ok {
+		t.Errorf("default runner = %T, want ExecRunner", prev)
+	}
+	if out, _ := runCommand(context.Background(), "echo", "hi"); string(out) != "hi" {
+		t.Errorf("runCommand via fake = %q, want %q", out, "hi")
+	}
+
+	// A nil runner restores the default
+	SetCommandRunner(nil)
+	if _, ok := currentRunner(context.Background()).(ExecRunner); !ok {
+		t.Errorf("runner after SetCommandRunner(nil) = %T, want ExecRunner", currentRunner(context.Background()))
+	}
+}
+
+func TestWithCommandRunner(t *testing.T) {
+	global := NewFakeRunner().Set("echo hi", []byte("global"))
+	defer SetCommandRunner(SetCommandRunner(global))
+	ctx := WithCommandRunner(context.Background(), NewFakeRunner().Set("echo hi", []byte("ctx")))
+
+	if out, _ := runCommand(ctx, "echo", "hi"); string(out) != "ctx" {
+		t.Errorf("runCommand with a context runner = %q, want %q", out, "ctx")
+	}
+	if _, err := lookPath(ctx, "echo"); err != nil {
+		t.Errorf("lookPath with a context runner: %v", err)
+	}
+	if out, _ := runCommand(context.Background(), "echo", "hi"); string(out) != "global" {
+		t.Errorf("runCommand without one = %q, want %q", out, "global")
+	}
+}
+
+func TestCLocaleEnv(t *testing.T) {
+	env := cLocaleEnv([]string{"PATH=/usr/bin", "LANG=de_DE.UTF-8", "LC_ALL=ja_JP.UTF-8", "LC_MESSAGES=de_DE.UTF-8", "LANGUAGE=de:en", "LCOV=1"})
+	want := []string{"PATH=/usr/bin", "LCOV=1", "LC_ALL=C", "LANG=C"}
+	if !slices.Equal(env, want) {
+		t.Errorf("cLocaleEnv = %q, want %q", env, want)
+	}
+}
+
+func TestExecRunner_CLocale(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows tools do not take their language from the environment")
+	}
+	// A tool that translates its output like gettext does
+	tool := filepath.Join(t.TempDir(), "tool")
+	script := `#!/bin/sh
+case "${LC_ALL:-${LC_MESSAGES:-$LANG}}" in
+de*) echo "Ein Neustart ist erforderlich" ;;
+ja*) echo "再起動が必要です" ;;
+*) echo "Reboot is required" ;;
+esac
+`
+	if err := os.WriteFile(tool, []byte(script), 0o700); err != nil { // #nosec G306 -- an executable test script
+		t.Fatal(err)
+	}
+	for _, lang := range []string{"de_DE.UTF-8", "ja_JP.UTF-8"} {
+		t.Setenv("LANG", lang)
+		t.Setenv("LC_MESSAGES", lang)
+		out, err := ExecRunner{}.Output(context.Background(), tool)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(string(out)); got != "Reboot is required" {
+			t.Errorf("with LANG=%s the tool printed %q, want the untranslated message", lang, got)
+		}
+	}
+}
+
+func TestExecRunner_Canceled(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep is not a Windows tool")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := (ExecRunner{}).Output(ctx, "sleep", "10"); err == nil {
+		t.Error("a killed command should fail")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Output returned after %v; the command was not killed", elapsed)
+	}
+}
@@ -0,0 +1,106 @@
+package inspector
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+const (
+	// ScanProfileEnv selects a scan profile, e.g. "quick"
+	ScanProfileEnv = "OMNITRUST_PROFILE"
+	// ScanProfilesEnv adds or replaces scan profiles, as a JSON object of
+	// ScanProfile by name
+	ScanProfilesEnv = "OMNITRUST_SCAN_PROFILES"
+)
+
+// ScanProfileFull runs every check
+const ScanProfileFull = "full"
+
+// ScanProfile is a named set of checks to run
+type ScanProfile struct {
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Checks are the check IDs the profile runs; empty runs every check
+	Checks []string `json:"checks" yaml:"checks"`
+}
+
+// builtinScanProfilesJSON defines the profiles that ship with omnitrust
+//
+//go:embed scanprofiles.json
+var builtinScanProfilesJSON []byte
+
+// builtinScanProfiles are the parsed built-in profiles
+var builtinScanProfiles = func() map[string]ScanProfile {
+	var profiles map[string]ScanProfile
+	if err := json.Unmarshal(builtinScanProfilesJSON, &profiles); err != nil {
+		panic("scanprofiles.json: " + err.Error())
+	}
+	return profiles
+}()
+
+// ScanProfiles returns the built-in profiles with the ones from
+// OMNITRUST_SCAN_PROFILES added or replacing them
+func ScanProfiles() (map[string]ScanProfile, error) {
+	profiles := maps.Clone(builtinScanProfiles)
+	v := strings.TrimSpace(os.Getenv(ScanProfilesEnv))
+	if v == "" {
+		return profiles, nil
+	}
+	var custom map[string]ScanProfile
+	if err := json.Unmarshal([]byte(v), &custom); err != nil {
+		return nil, fmt.Errorf("%s: %w", ScanProfilesEnv, err)
+	}
+	for name, p := range custom {
+		if err := ValidateScanProfile(p); err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", ScanProfilesEnv, name, err)
+		}
+		profiles[name] = p
+	}
+	return profiles, nil
+}
+
+// ValidateScanProfile checks that a profile only names known checks
+func ValidateScanProfile(p ScanProfile) error {
+	for _, id := range p.Checks {
+		if !slices.Contains(AllChecks, normalizeCheckID(id)) {
+			return fmt.Errorf("unknown check %q (use %s)", id, strings.Join(AllChecks, ", "))
+		}
+	}
+	return nil
+}
+
+// LookupScanProfile returns the profile called name
+func LookupScanProfile(name string) (ScanProfile, error) {
+	profiles, err := ScanProfiles()
+	if err != nil {
+		return ScanProfile{}, err
+	}
+	p, ok := profiles[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return ScanProfile{}, fmt.Errorf("unknown profile %q (use %s)", name, strings.Join(slices.Sorted(maps.Keys(profiles)), ", "))
+	}
+	return p, nil
+}
+
+// scanProfileChecks returns the checks the OMNITRUST_PROFILE profile runs,
+// or nil for every check. An unknown profile runs every check; the CLI
+// rejects it before any check runs.
+func scanProfileChecks() []string {
+	name := os.Getenv(ScanProfileEnv)
+	if name == "" {
+		return nil
+	}
+	p, err := LookupScanProfile(name)
+	if err != nil {
+		return nil
+	}
+	checks := make([]string, len(p.Checks))
+	for i, id := range p.Checks {
+		checks[i] = normalizeCheckID(id)
+	}
+	return checks
+}
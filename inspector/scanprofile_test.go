@@ -0,0 +1,77 @@
+package inspector
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuiltinScanProfiles(t *testing.T) {
+	for _, name := range []string{"quick", ScanProfileFull, "boot-security", "privacy"} {
+		p, ok := builtinScanProfiles[name]
+		if !ok {
+			t.Errorf("built-in profile %s is missing", name)
+			continue
+		}
+		if err := ValidateScanProfile(p); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if p.Description == "" {
+			t.Errorf("%s has no description", name)
+		}
+	}
+	if len(builtinScanProfiles[ScanProfileFull].Checks) != 0 {
+		t.Error("the full profile should run every check")
+	}
+}
+
+func TestCheckEnabled_Profile(t *testing.T) {
+	t.Setenv(OnlyChecksEnv, "")
+	t.Setenv(DisableChecksEnv, "")
+	t.Setenv(ScanProfilesEnv, "")
+
+	t.Setenv(ScanProfileEnv, "boot-security")
+	if !CheckEnabled(CheckSecureBoot) || CheckEnabled(CheckBrowser) {
+		t.Error("boot-security should run secure_boot and not browser")
+	}
+
+	t.Setenv(ScanProfileEnv, ScanProfileFull)
+	if !CheckEnabled(CheckBrowser) {
+		t.Error("the full profile should run every check")
+	}
+
+	// Profiles combine with the other check lists
+	t.Setenv(ScanProfileEnv, "boot-security")
+	t.Setenv(DisableChecksEnv, "tpm")
+	if CheckEnabled(CheckTPM) {
+		t.Error("a disabled check should not run even if its profile includes it")
+	}
+}
+
+func TestScanProfiles_Override(t *testing.T) {
+	t.Setenv(ScanProfilesEnv, `{"quick": {"checks": ["encryption"]}, "audit": {"checks": ["TPM", "secure-boot"]}}`)
+	profiles, err := ScanProfiles()
+	if err != nil {
+		t.Fatalf("ScanProfiles: %v", err)
+	}
+	if !slices.Equal(profiles["quick"].Checks, []string{"encryption"}) {
+		t.Errorf("quick = %v, want the override", profiles["quick"].Checks)
+	}
+	if _, ok := profiles["privacy"]; !ok {
+		t.Error("built-in profiles should remain alongside overrides")
+	}
+
+	t.Setenv(ScanProfileEnv, "audit")
+	t.Setenv(OnlyChecksEnv, "")
+	t.Setenv(DisableChecksEnv, "")
+	if !CheckEnabled(CheckSecureBoot) || CheckEnabled(CheckEncryption) {
+		t.Error("a custom profile should select its checks, normalizing their IDs")
+	}
+
+	t.Setenv(ScanProfilesEnv, `{"bad": {"checks": ["antivirus"]}}`)
+	if _, err := ScanProfiles(); err == nil {
+		t.Error("ScanProfiles should reject an unknown check")
+	}
+	if _, err := LookupScanProfile("nope"); err == nil {
+		t.Error("LookupScanProfile should reject an unknown profile")
+	}
+}
@@ -0,0 +1,154 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// subprocessCounter counts the external commands started under a context.
+// Counters nest, so a command counts toward its check and toward the scan.
+type subprocessCounter struct {
+	n      atomic.Int64
+	parent *subprocessCounter
+}
+
+// subprocessCounterKey is the context key of the innermost counter
+type subprocessCounterKey struct{}
+
+// withSubprocessCounter returns a context whose commands are counted by a
+// new counter, as well as by the counters already on ctx
+func withSubprocessCounter(ctx context.Context) (context.Context, *subprocessCounter) {
+	parent, _ := ctx.Value(subprocessCounterKey{}).(*subprocessCounter)
+	c := &subprocessCounter{parent: parent}
+	return context.WithValue(ctx, subprocessCounterKey{}, c), c
+}
+
+// countSubprocess counts a command toward every counter on ctx
+func countSubprocess(ctx context.Context) {
+	c, _ := ctx.Value(subprocessCounterKey{}).(*subprocessCounter)
+	for ; c != nil; c = c.parent {
+		c.n.Add(1)
+	}
+}
+
+// ScanStats reports the resource cost of the scan itself
+type ScanStats struct {
+	WallTimeMs   float64      `json:"wall_time_ms"`
+	CPUTimeMs    float64      `json:"cpu_time_ms"`
+	PeakRSSBytes uint64       `json:"peak_rss_bytes,omitempty"`
+	Subprocesses int64        `json:"subprocesses"`
+	Checks       []CheckStats `json:"checks,omitempty"`
+}
+
+// CheckStats reports the cost of a single check within a scan
+type CheckStats struct {
+	Name       string  `json:"name"`
+	WallTimeMs float64 `json:"wall_time_ms"`
+	// Subprocesses counts the commands the check started, even in a
+	// parallel scan where other checks run alongside it
+	Subprocesses int64 `json:"subprocesses"`
+	// TimedOut is set when the check ran past its timeout and was abandoned
+	TimedOut bool `json:"timed_out,omitempty"`
+}
+
+// scanRecorder measures a scan and the checks it runs. Checks may be
+// tracked from several goroutines at once.
+type scanRecorder struct {
+	start    time.Time
+	startCPU time.Duration
+	subproc  *subprocessCounter
+	// progress, if set, is told when each check starts and finishes
+	progress func(CheckProgress)
+
+	mu     sync.Mutex
+	checks []CheckStats
+	// timeouts lists the checks that ran past their timeout
+	timeouts []string
+	// pending holds the checks started ahead of the summary in a parallel scan
+	pending map[string]*pendingCheck
+}
+
+// newScanRecorder starts measuring a scan. The checks of the scan must run
+// under the returned context for their commands to be counted.
+func newScanRecorder(ctx context.Context) (context.Context, *scanRecorder) {
+	ctx, subproc := withSubprocessCounter(ctx)
+	return ctx, &scanRecorder{
+		start:    time.Now(),
+		startCPU: processCPUTime(),
+		subproc:  subproc,
+	}
+}
+
+// track runs fn and records its wall time and subprocess count under name.
+// fn must run its commands under the context it is given, which counts them
+// toward this check alone.
+func (r *scanRecorder) track(ctx context.Context, name string, fn func(context.Context) error) {
+	r.notify(CheckProgress{Check: name})
+	start := time.Now()
+	ctx, subproc := withSubprocessCounter(ctx)
+	endReads := provenanceCheck(name)
+	err := fn(ctx)
+	endReads()
+	elapsed := time.Since(start)
+	stats := CheckStats{
+		Name:         name,
+		WallTimeMs:   durationMs(elapsed),
+		Subprocesses: subproc.n.Load(),
+		TimedOut:     errors.Is(err, ErrTimeout),
+	}
+	loggerFrom(ctx).Debug("check finished", "check", name, "wall_time_ms", stats.WallTimeMs, "subprocesses", stats.Subprocesses)
+	r.mu.Lock()
+	r.checks = append(r.checks, stats)
+	r.mu.Unlock()
+	r.notify(CheckProgress{Check: name, Done: true, Duration: elapsed, Err: err})
+}
+
+// notify passes a progress event to the progress callback, one at a time
+func (r *scanRecorder) notify(e CheckProgress) {
+	if r.progress == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.progress(e)
+}
+
+// timedOut records that a check ran past its timeout
+func (r *scanRecorder) timedOut(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timeouts = append(r.timeouts, name)
+}
+
+// finish returns the stats for the whole scan
+func (r *scanRecorder) finish() *ScanStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &ScanStats{
+		WallTimeMs:   durationMs(time.Since(r.start)),
+		CPUTimeMs:    durationMs(processCPUTime() - r.startCPU),
+		PeakRSSBytes: processPeakRSS(),
+		Subprocesses: r.subproc.n.Load(),
+		Checks:       r.checks,
+	}
+}
+
+// durationMs converts a duration to fractional milliseconds
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// formatScanStats renders a one-line scan cost footer for table output
+func formatScanStats(st Styler, s *ScanStats) string {
+	if s == nil {
+		return ""
+	}
+	line := T("Scan: %.0fms wall, %.0fms CPU, %d subprocesses", s.WallTimeMs, s.CPUTimeMs, s.Subprocesses)
+	if s.PeakRSSBytes > 0 {
+		line += T(", peak RSS %s", FormatBytes(s.PeakRSSBytes))
+	}
+	return st.Muted(line) + "\n"
+}
@@ -0,0 +1,86 @@
+package inspector
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScanRecorder(t *testing.T) {
+	defer SetCommandRunner(SetCommandRunner(NewFakeRunner().Set("true", nil)))
+
+	ctx, rec := newScanRecorder(context.Background())
+	rec.track(ctx, "slow", func(ctx context.Context) error {
+		time.Sleep(5 * time.Millisecond)
+		_, _ = runCommand(ctx, "true")
+		_, _ = runCommand(ctx, "true")
+		return nil
+	})
+	rec.track(ctx, "fast", func(context.Context) error { return nil })
+
+	stats := rec.finish()
+	if len(stats.Checks) != 2 {
+		t.Fatalf("len(Checks) = %d, want 2", len(stats.Checks))
+	}
+	if stats.Checks[0].Name != "slow" || stats.Checks[0].WallTimeMs < 5 {
+		t.Errorf("Checks[0] = %+v, want slow with >= 5ms", stats.Checks[0])
+	}
+	if stats.Checks[0].Subprocesses != 2 || stats.Checks[1].Subprocesses != 0 {
+		t.Errorf("subprocesses = %d/%d, want 2/0", stats.Checks[0].Subprocesses, stats.Checks[1].Subprocesses)
+	}
+	if stats.Subprocesses != 2 {
+		t.Errorf("Subprocesses = %d, want 2", stats.Subprocesses)
+	}
+	if stats.WallTimeMs < stats.Checks[0].WallTimeMs {
+		t.Errorf("WallTimeMs = %v, want >= %v", stats.WallTimeMs, stats.Checks[0].WallTimeMs)
+	}
+}
+
+func TestScanRecorder_Parallel(t *testing.T) {
+	defer SetCommandRunner(SetCommandRunner(NewFakeRunner().Set("true", nil)))
+
+	// Checks running at once each count only their own commands, and
+	// commands outside the scan are not counted at all
+	ctx, rec := newScanRecorder(context.Background())
+	var started, wg sync.WaitGroup
+	started.Add(3)
+	for i, name := range []string{"one", "two", "three"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec.track(ctx, name, func(ctx context.Context) error {
+				started.Done()
+				started.Wait()
+				for range i + 1 {
+					_, _ = runCommand(ctx, "true")
+				}
+				return nil
+			})
+		}()
+	}
+	_, _ = runCommand(context.Background(), "true")
+	wg.Wait()
+
+	stats := rec.finish()
+	want := map[string]int64{"one": 1, "two": 2, "three": 3}
+	for _, c := range stats.Checks {
+		if c.Subprocesses != want[c.Name] {
+			t.Errorf("%s: Subprocesses = %d, want %d", c.Name, c.Subprocesses, want[c.Name])
+		}
+	}
+	if len(stats.Checks) != 3 || stats.Subprocesses != 6 {
+		t.Errorf("scan = %d checks, %d subprocesses; want 3 and 6", len(stats.Checks), stats.Subprocesses)
+	}
+}
+
+func TestFormatScanStats(t *testing.T) {
+	if got := formatScanStats(CurrentStyler(), nil); got != "" {
+		t.Errorf("formatScanStats(nil) = %q, want empty", got)
+	}
+	got := StripANSI(formatScanStats(CurrentStyler(), &ScanStats{WallTimeMs: 120, CPUTimeMs: 30, Subprocesses: 4}))
+	if !strings.Contains(got, "120ms wall") || !strings.Contains(got, "4 subprocesses") {
+		t.Errorf("formatScanStats = %q", got)
+	}
+}
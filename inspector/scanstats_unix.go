@@ -0,0 +1,35 @@
+//go:build !windows
+
+package inspector
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// processCPUTime returns the user and system CPU time consumed by this
+// process and its finished subprocesses
+func processCPUTime() time.Duration {
+	var total time.Duration
+	for _, who := range []int{syscall.RUSAGE_SELF, syscall.RUSAGE_CHILDREN} {
+		var ru syscall.Rusage
+		if err := syscall.Getrusage(who, &ru); err == nil {
+			total += time.Duration(ru.Utime.Nano() + ru.Stime.Nano())
+		}
+	}
+	return total
+}
+
+// processPeakRSS returns the peak resident set size of this process in bytes
+func processPeakRSS() uint64 {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil || ru.Maxrss <= 0 {
+		return 0
+	}
+	// ru_maxrss is reported in bytes on macOS and kilobytes elsewhere
+	if runtime.GOOS == "darwin" {
+		return uint64(ru.Maxrss)
+	}
+	return uint64(ru.Maxrss) * 1024
+}
@@ -0,0 +1,53 @@
+//go:build windows
+
+package inspector
+
+import (
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// processMemoryCounters mirrors PROCESS_MEMORY_COUNTERS
+type processMemoryCounters struct {
+	CB                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+var procGetProcessMemoryInfo = kernel32.NewProc("K32GetProcessMemoryInfo")
+
+// processCPUTime returns the user and kernel CPU time consumed by this process
+func processCPUTime() time.Duration {
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(windows.CurrentProcess(), &creation, &exit, &kernel, &user); err != nil {
+		return 0
+	}
+	// FILETIME values are in 100-nanosecond intervals
+	ticks := int64(kernel.HighDateTime)<<32 | int64(kernel.LowDateTime)
+	ticks += int64(user.HighDateTime)<<32 | int64(user.LowDateTime)
+	return time.Duration(ticks * 100)
+}
+
+// processPeakRSS returns the peak working set of this process in bytes
+func processPeakRSS() uint64 {
+	var counters processMemoryCounters
+	counters.CB = uint32(unsafe.Sizeof(counters))
+	ret, _, _ := procGetProcessMemoryInfo.Call(
+		uintptr(windows.CurrentProcess()),
+		uintptr(unsafe.Pointer(&counters)),
+		uintptr(counters.CB),
+	)
+	if ret == 0 {
+		return 0
+	}
+	return uint64(counters.PeakWorkingSetSize)
+}
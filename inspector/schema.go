@@ -0,0 +1,164 @@
+package inspector
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// SchemaVersion is the version of the JSON result schemas. The minor version
+// is bumped when fields are added and the major version when fields are
+// removed, renamed, or change type, so parsers can reject results they do
+// not understand.
+const SchemaVersion = "2.27"
+
+// ResultVersion is the schema_version field of every result. Its zero value
+// stands for the current SchemaVersion, so results do not have to set it.
+type ResultVersion string
+
+// String returns the version, defaulting to SchemaVersion
+func (v ResultVersion) String() string {
+	if v == "" {
+		return SchemaVersion
+	}
+	return string(v)
+}
+
+// MarshalJSON encodes the version, defaulting to SchemaVersion
+func (v ResultVersion) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
+}
+
+// CheckSchemaVersion reports an error if a result was written with a schema
+// whose major version differs from this build's. Results without a version
+// predate versioning and are accepted.
+func CheckSchemaVersion(v ResultVersion) error {
+	major, _, _ := strings.Cut(v.String(), ".")
+	current, _, _ := strings.Cut(SchemaVersion, ".")
+	if major != current {
+		return fmt.Errorf("schema version %s is not compatible with %s", v, SchemaVersion)
+	}
+	return nil
+}
+
+// resultTypes names every result type with a published schema
+var resultTypes = map[string]reflect.Type{
+	"cpu":               reflect.TypeFor[CPUUsageResult](),
+	"memory":            reflect.TypeFor[MemoryResult](),
+	"memory_top":        reflect.TypeFor[MemoryTopResult](),
+	"fd_usage":          reflect.TypeFor[FDUsageResult](),
+	"processes":         reflect.TypeFor[ProcessListResult](),
+	"sensors":           reflect.TypeFor[SensorsResult](),
+	"gpu":               reflect.TypeFor[GPUResult](),
+	"metrics_snapshot":  reflect.TypeFor[MetricsSnapshot](),
+	"tpm":               reflect.TypeFor[TPMResult](),
+	"secure_boot":       reflect.TypeFor[SecureBootResult](),
+	"boot_order":        reflect.TypeFor[BootOrderResult](),
+	"encryption":        reflect.TypeFor[EncryptionResult](),
+	"biometrics":        reflect.TypeFor[BiometricCapabilities](),
+	"defender":          reflect.TypeFor[DefenderResult](),
+	"uac":               reflect.TypeFor[UACResult](),
+	"legacy_protocols":  reflect.TypeFor[LegacyProtocolsResult](),
+	"profiles":          reflect.TypeFor[ConfigProfilesResult](),
+	"browser":           reflect.TypeFor[BrowserSecurityResult](),
+	"filesystem":        reflect.TypeFor[FilesystemAuditResult](),
+	"docker":            reflect.TypeFor[ContainerSecurityResult](),
+	"kubelet":           reflect.TypeFor[KubeletResult](),
+	"uptime":            reflect.TypeFor[UptimeResult](),
+	"usb":               reflect.TypeFor[USBDevicesResult](),
+	"firmware":          reflect.TypeFor[FirmwareResult](),
+	"management_engine": reflect.TypeFor[ManagementEngineResult](),
+	"passkeys":          reflect.TypeFor[PasskeyResult](),
+	"keychain":          reflect.TypeFor[KeychainResult](),
+	"baseline":          reflect.TypeFor[Baseline](),
+	"waivers":           reflect.TypeFor[WaiverList](),
+	"summary":           reflect.TypeFor[SecuritySummary](),
+	"score_explanation": reflect.TypeFor[ScoreExplanation](),
+	"me":                reflect.TypeFor[MeSummary](),
+	"findings":          reflect.TypeFor[FindingsResult](),
+	"environment":       reflect.TypeFor[RuntimeEnvironment](),
+	"cloud":             reflect.TypeFor[CloudContext](),
+	"virtualization":    reflect.TypeFor[VirtualizationResult](),
+	"selftest":          reflect.TypeFor[SelfTestResult](),
+	"dry_run":           reflect.TypeFor[DryRunResult](),
+	"checks":            reflect.TypeFor[CheckListResult](),
+	"doctor":            reflect.TypeFor[DoctorResult](),
+	"version":           reflect.TypeFor[BuildInfo](),
+	"envelope":          reflect.TypeFor[Envelope](),
+	"fingerprint":       reflect.TypeFor[FingerprintResult](),
+}
+
+// SchemaNames returns the names of the published result schemas, sorted
+func SchemaNames() []string {
+	names := make([]string, 0, len(resultTypes))
+	for name := range resultTypes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Schema returns the JSON Schema of the named result (see SchemaNames)
+func Schema(name string) (*jsonschema.Schema, error) {
+	t, ok := resultTypes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema %q", name)
+	}
+	return SchemaFor(t, name)
+}
+
+// SchemaFor generates the JSON Schema of a result type from its struct
+// definition. The schema_version property is pinned to SchemaVersion.
+func SchemaFor(t reflect.Type, name string) (*jsonschema.Schema, error) {
+	var version any = SchemaVersion
+	s, err := jsonschema.ForType(t, &jsonschema.ForOptions{
+		TypeSchemas: map[reflect.Type]*jsonschema.Schema{
+			reflect.TypeFor[ResultVersion](): {Type: "string", Const: &version},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	allowAdditionalProperties(s)
+	s.Schema = "https://json-schema.org/draft/2020-12/schema"
+	s.Title = name
+	s.Description = fmt.Sprintf("omnitrust %s result, schema version %s", name, SchemaVersion)
+	return s, nil
+}
+
+// allowAdditionalProperties lifts the ban on unknown properties that
+// jsonschema-go puts on structs, so that results from a newer minor version
+// (which may add fields) still validate
+func allowAdditionalProperties(s *jsonschema.Schema) {
+	if s == nil {
+		return
+	}
+	if s.Properties != nil {
+		s.AdditionalProperties = nil
+	}
+	allowAdditionalProperties(s.AdditionalProperties)
+	allowAdditionalProperties(s.Items)
+	for _, p := range s.Properties {
+		allowAdditionalProperties(p)
+	}
+	for _, d := range s.Defs {
+		allowAdditionalProperties(d)
+	}
+}
+
+// Schemas returns the JSON Schema of every result, keyed by name
+func Schemas() (map[string]*jsonschema.Schema, error) {
+	schemas := make(map[string]*jsonschema.Schema, len(resultTypes))
+	for _, name := range SchemaNames() {
+		s, err := Schema(name)
+		if err != nil {
+			return nil, err
+		}
+		schemas[name] = s
+	}
+	return schemas, nil
+}
@@ -0,0 +1,77 @@
+package inspector
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchemas(t *testing.T) {
+	schemas, err := Schemas()
+	if err != nil {
+		t.Fatalf("Schemas: %v", err)
+	}
+	if len(schemas) != len(SchemaNames()) {
+		t.Errorf("got %d schemas for %d names", len(schemas), len(SchemaNames()))
+	}
+	for name, s := range schemas {
+		if s.AdditionalProperties != nil {
+			t.Errorf("%s: results must allow additional properties for minor version additions", name)
+		}
+		prop, ok := s.Properties["schema_version"]
+		if !ok || prop.Const == nil || *prop.Const != SchemaVersion {
+			t.Errorf("%s: schema_version property = %+v, want const %q", name, prop, SchemaVersion)
+		}
+	}
+
+	summary, err := Schema("summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := summary.Properties["overall_score"]; !ok {
+		t.Error("summary schema is missing overall_score")
+	}
+	if _, err := Schema("nonexistent"); err == nil {
+		t.Error("Schema should reject an unknown name")
+	}
+}
+
+// Every published result must carry a schema_version so parsers can detect
+// incompatible changes
+func TestResultTypesHaveSchemaVersion(t *testing.T) {
+	for name, typ := range resultTypes {
+		f, ok := typ.FieldByName("SchemaVersion")
+		if !ok || f.Type != reflect.TypeFor[ResultVersion]() {
+			t.Errorf("%s (%s) has no SchemaVersion field", name, typ.Name())
+		}
+	}
+}
+
+func TestResultVersion(t *testing.T) {
+	data, err := json.Marshal(&MemoryResult{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), `{"schema_version":"`+SchemaVersion+`"`) {
+		t.Errorf("zero version should marshal as the current version: %s", data)
+	}
+
+	var decoded MemoryResult
+	if err := json.Unmarshal([]byte(`{"schema_version":"1.7"}`), &decoded); err != nil || decoded.SchemaVersion != "1.7" {
+		t.Errorf("decoded version = %q, %v", decoded.SchemaVersion, err)
+	}
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	for _, v := range []ResultVersion{"", SchemaVersion, "2.99"} {
+		if err := CheckSchemaVersion(v); err != nil {
+			t.Errorf("CheckSchemaVersion(%q) = %v", v, err)
+		}
+	}
+	for _, v := range []ResultVersion{"1.0", "3.0"} {
+		if err := CheckSchemaVersion(v); err == nil {
+			t.Errorf("CheckSchemaVersion(%q): a different major version should be rejected", v)
+		}
+	}
+}
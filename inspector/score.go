@@ -0,0 +1,332 @@
+package inspector
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Outcomes of a check in a score explanation, besides the check results
+// (CheckResultPass, CheckResultFail, CheckResultTimeout)
+const (
+	CheckResultNotRun        = "not_run"
+	CheckResultDisabled      = "disabled"
+	CheckResultUnsupported   = "unsupported"
+	CheckResultNotApplicable = "not_applicable"
+	// CheckResultWaived checks failed but are scored as passed, since all
+	// of their findings are waived (see WaiversScoreEnv)
+	CheckResultWaived = "waived"
+)
+
+// ScoreExplanation shows how the overall score of a summary follows from
+// its checks: what each check counts for, what it earned and why, and the
+// single change that would raise the score the most
+type ScoreExplanation struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string `json:"platform"`
+	Score    int    `json:"score"`
+	Status   string `json:"status"`
+	// StatusReason explains the status: the score band it falls in, or the
+	// mandatory checks that make it critical
+	StatusReason string `json:"status_reason"`
+	// Formula is how the score is computed from the points
+	Formula        string       `json:"formula"`
+	EarnedPoints   float64      `json:"earned_points"`
+	PossiblePoints float64      `json:"possible_points"`
+	Checks         []CheckScore `json:"checks"`
+	// MandatoryFailures lists mandatory checks that did not pass
+	MandatoryFailures []string `json:"mandatory_failures,omitempty"`
+	// BestImprovement is the check whose passing would raise the score the
+	// most; it is unset when no scored check can gain points
+	BestImprovement *ScoreImprovement `json:"best_improvement,omitempty"`
+}
+
+// CheckScore is what one check contributes to the score
+type CheckScore struct {
+	Check       string      `json:"check"`
+	Domain      string      `json:"domain,omitempty"`
+	Enforcement Enforcement `json:"enforcement"`
+	// Weight is the check's scoring weight (see CheckWeightsEnv)
+	Weight float64 `json:"weight"`
+	// PossiblePoints is 25 × weight, or 0 for checks excluded from the
+	// score (informational, not applicable, disabled, and unsupported
+	// checks)
+	PossiblePoints float64 `json:"possible_points"`
+	AwardedPoints  float64 `json:"awarded_points"`
+	// Result is pass, fail, waived, timeout, not_run, disabled,
+	// unsupported, or not_applicable
+	Result string `json:"result"`
+	// Reason explains the points awarded, from the check's findings when
+	// it failed
+	Reason string `json:"reason"`
+}
+
+// ScoreImprovement is a single change and what it would do to the score
+type ScoreImprovement struct {
+	Check string `json:"check"`
+	// Action is what to change: the remediation of the check's most severe
+	// finding, when it has one
+	Action string `json:"action"`
+	// Command fixes the finding, when there is one (see Finding)
+	Command string `json:"command,omitempty"`
+	// Points are the points the check would gain
+	Points float64 `json:"points"`
+	// Score and Status are the overall score and status after the change
+	Score  int    `json:"score"`
+	Status string `json:"status"`
+}
+
+// ExplainScore explains how the overall score of summary was computed,
+// from its check results with the enforcement levels and weights
+// configured now
+func ExplainScore(summary *SecuritySummary) *ScoreExplanation {
+	passed := scoredResults(summary)
+
+	e := &ScoreExplanation{
+		Platform:          summary.Platform,
+		Score:             summary.OverallScore,
+		Status:            summary.OverallStatus,
+		Formula:           fmt.Sprintf("score = floor(earned_points × 100 / possible_points); each scored check counts for %d × weight points", checkPoints),
+		Checks:            []CheckScore{},
+		MandatoryFailures: summary.MandatoryFailures,
+	}
+	for _, id := range checksFor(summary.Platform) {
+		c := CheckScore{
+			Check:       id,
+			Domain:      CheckDomain(id),
+			Enforcement: CheckEnforcement(id),
+			Weight:      CheckWeight(id),
+			Result:      checkOutcome(summary, id),
+		}
+		excluded := c.Result == CheckResultNotApplicable || c.Result == CheckResultDisabled || c.Result == CheckResultUnsupported
+		if !excluded && c.Enforcement != EnforcementInformational {
+			c.PossiblePoints = checkPoints * c.Weight
+		}
+		if c.Result == CheckResultPass || c.Result == CheckResultWaived {
+			c.AwardedPoints = c.PossiblePoints
+		}
+		c.Reason = scoreReason(summary, c)
+		e.PossiblePoints += c.PossiblePoints
+		e.EarnedPoints += c.AwardedPoints
+		e.Checks = append(e.Checks, c)
+	}
+	e.StatusReason = statusReason(e)
+	e.BestImprovement = bestImprovement(summary, e, summary.scoredChecks(), passed)
+	return e
+}
+
+// scoredResults returns whether each check of summary that has an outcome
+// passed, as scored: checks whose findings are all waived count as passed
+func scoredResults(summary *SecuritySummary) map[string]bool {
+	passed := make(map[string]bool, len(summary.CheckResults))
+	for id, result := range summary.CheckResults {
+		if result != CheckResultTimeout {
+			passed[id] = result == CheckResultPass
+		}
+	}
+	for _, id := range summary.WaivedChecks {
+		passed[id] = true
+	}
+	return passed
+}
+
+// checkOutcome returns the result of a check in summary, or why it has none
+func checkOutcome(summary *SecuritySummary, id string) string {
+	if _, na := summary.NotApplicable[id]; na {
+		return CheckResultNotApplicable
+	}
+	if slices.Contains(summary.WaivedChecks, id) {
+		return CheckResultWaived
+	}
+	if result, ok := summary.CheckResults[id]; ok {
+		return result
+	}
+	if slices.Contains(summary.UnsupportedChecks, id) {
+		return CheckResultUnsupported
+	}
+	if slices.Contains(summary.DisabledChecks, id) || !CheckEnabled(id) {
+		return CheckResultDisabled
+	}
+	return CheckResultNotRun
+}
+
+// checkFindings returns the findings of a check in summary, most severe
+// first
+func checkFindings(summary *SecuritySummary, id string) []Finding {
+	var findings []Finding
+	for _, f := range summary.Findings {
+		if f.Check == id {
+			findings = append(findings, f)
+		}
+	}
+	SortFindings(findings)
+	return findings
+}
+
+// scoreReason explains the points a check was awarded
+func scoreReason(summary *SecuritySummary, c CheckScore) string {
+	var titles, waivers []string
+	for _, f := range checkFindings(summary, c.Check) {
+		titles = append(titles, f.Title)
+	}
+	for _, w := range summary.Waived {
+		if w.Check == c.Check {
+			waivers = append(waivers, w.Waiver.Reason)
+		}
+	}
+	var reason string
+	switch {
+	case c.Result == CheckResultNotApplicable:
+		return T("Excluded from the score: %s", summary.NotApplicable[c.Check])
+	case c.Enforcement == EnforcementInformational:
+		return T("Informational, so not scored")
+	case c.Result == CheckResultPass:
+		return T("Passed: full points")
+	case c.Result == CheckResultWaived:
+		return T("Waived: %s", strings.Join(waivers, "; "))
+	case c.Result == CheckResultDisabled:
+		return T("Disabled, so not scored")
+	case c.Result == CheckResultUnsupported:
+		return T("Not supported on this machine, so not scored")
+	case c.Result == CheckResultTimeout:
+		reason = T("Ran past its timeout: no points")
+	case len(titles) > 0:
+		reason = strings.Join(titles, "; ")
+	case len(waivers) > 0:
+		reason = T("Failed: its findings are waived, but waivers are only scored with %s=true", WaiversScoreEnv)
+	case c.Result == CheckResultFail:
+		reason = T("Failed: no points")
+	default:
+		reason = T("Could not run: no points")
+	}
+	if c.Enforcement == EnforcementMandatory {
+		reason += " " + T("(mandatory: the status is critical until it passes)")
+	}
+	return reason
+}
+
+// statusReason explains the overall status of a score explanation
+func statusReason(e *ScoreExplanation) string {
+	if len(e.MandatoryFailures) > 0 {
+		return T("Mandatory checks failed: %s", strings.Join(e.MandatoryFailures, ", "))
+	}
+	if e.Status != scoreStatus(e.Score) {
+		return T("No scored check could run here")
+	}
+	return T("%d/100 is %s: excellent at 100, good from 75, fair from 50, needs improvement from 25, critical below", e.Score, e.Status)
+}
+
+// bestImprovement finds the check that did not pass whose passing would
+// raise the score the most. Ties go to a check whose failure makes the
+// status critical, then to the check with the most severe finding.
+func bestImprovement(summary *SecuritySummary, e *ScoreExplanation, checks []string, passed map[string]bool) *ScoreImprovement {
+	var best *ScoreImprovement
+	var bestRank [2]int
+	for _, c := range e.Checks {
+		if c.PossiblePoints == 0 || c.AwardedPoints > 0 {
+			continue
+		}
+		after := maps.Clone(passed)
+		after[c.Check] = true
+		score, mandatoryFailures := scoreChecks(checks, after, summary.NotApplicable)
+		status := scoreStatus(score)
+		if len(mandatoryFailures) > 0 {
+			status = "critical"
+		}
+		imp := &ScoreImprovement{Check: c.Check, Points: c.PossiblePoints, Score: score, Status: status}
+		findings := checkFindings(summary, c.Check)
+		switch {
+		case len(findings) > 0:
+			imp.Action, imp.Command = findings[0].Remediation, findings[0].RemediationCommand
+		case c.Result == CheckResultTimeout:
+			imp.Action = T("Let the %s check finish: raise %s", c.Check, CheckTimeoutEnv)
+		default:
+			imp.Action = T("Make the %s check pass", c.Check)
+		}
+
+		// Lower ranks win ties on the score
+		rank := [2]int{1, len(Severities)}
+		if slices.Contains(e.MandatoryFailures, c.Check) {
+			rank[0] = 0
+		}
+		if len(findings) > 0 {
+			rank[1] = SeverityRank(findings[0].Severity)
+		}
+		if best == nil || score > best.Score || score == best.Score && (rank[0] < bestRank[0] || rank[0] == bestRank[0] && rank[1] < bestRank[1]) {
+			best, bestRank = imp, rank
+		}
+	}
+	return best
+}
+
+// FormatScoreExplanationTable formats a score explanation as a colored table
+func FormatScoreExplanationTable(e *ScoreExplanation) string {
+	return FormatScoreExplanationTableWith(CurrentStyler(), e)
+}
+
+// FormatScoreExplanationTableWith is like FormatScoreExplanationTable but
+// styles the table with st
+func FormatScoreExplanationTableWith(st Styler, e *ScoreExplanation) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " " + T("Score Explanation")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 60)))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(st.BoldText(T("Score:") + " "))
+	sb.WriteString(fmt.Sprintf("%s (%s)", FormatScore(e.Score), e.Status))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(e.StatusReason))
+	sb.WriteString("\n")
+	sb.WriteString(st.BoldText(T("Points:") + " "))
+	sb.WriteString(fmt.Sprintf("%s / %s", formatPoints(e.EarnedPoints), formatPoints(e.PossiblePoints)))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(e.Formula))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(st.BoldText(T("Checks:")))
+	sb.WriteString("\n")
+	for _, c := range e.Checks {
+		icon, points := st.Muted(IconInfo), st.Muted(PadRight("-", 11))
+		switch {
+		case c.PossiblePoints == 0:
+		case c.AwardedPoints == c.PossiblePoints:
+			icon = st.Success(IconCheck)
+			points = st.Success(PadRight(formatPoints(c.AwardedPoints)+"/"+formatPoints(c.PossiblePoints), 11))
+		default:
+			icon = st.Danger(IconCross)
+			points = st.Danger(PadRight(formatPoints(c.AwardedPoints)+"/"+formatPoints(c.PossiblePoints), 11))
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s %s %s %s\n", icon, PadRight(c.Check, 18), points, st.Muted(PadRight("×"+formatPoints(c.Weight), 5)), c.Reason))
+	}
+
+	if b := e.BestImprovement; b != nil {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText(IconArrow + " " + T("Best improvement:") + " "))
+		sb.WriteString(T("pass %s for +%s points, to %d/100 (%s)", b.Check, formatPoints(b.Points), b.Score, b.Status))
+		sb.WriteString("\n")
+		sb.WriteString("  " + b.Action)
+		sb.WriteString("\n")
+		if b.Command != "" {
+			sb.WriteString("  " + st.Muted("$ "+b.Command))
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// formatPoints shows points without decimals when they are whole
+func formatPoints(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// FormatScoreExplanation formats a score explanation in the specified format
+func FormatScoreExplanation(e *ScoreExplanation, format string) string {
+	return FormatOutputWith(e, func(st Styler) string {
+		return FormatScoreExplanationTableWith(st, e)
+	}, format)
+}
@@ -0,0 +1,119 @@
+package inspector
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplainScore(t *testing.T) {
+	t.Setenv(CheckWeightsEnv, "encryption=2")
+	t.Setenv(InformationalChecksEnv, "")
+	t.Setenv(MandatoryChecksEnv, "")
+	t.Setenv(OnlyChecksEnv, "")
+	t.Setenv(DisableChecksEnv, "")
+	t.Setenv(ScanProfileEnv, "")
+	t.Setenv(USBStoragePolicyEnv, "")
+	summary := &SecuritySummary{
+		Platform:      "linux",
+		OverallScore:  66,
+		OverallStatus: "fair",
+		CheckResults: map[string]string{
+			CheckTPM: CheckResultPass, CheckSecureBoot: CheckResultFail, CheckEncryption: CheckResultPass,
+			CheckBiometrics: CheckResultFail, CheckBootOrder: CheckResultPass, CheckBrowser: CheckResultPass,
+			CheckDocker: CheckResultPass, CheckKubelet: CheckResultPass, CheckUptime: CheckResultTimeout,
+			CheckManagementEngine: CheckResultPass,
+		},
+		Findings: []Finding{
+			{ID: "biometrics_not_configured", Severity: SeverityLow, Check: CheckBiometrics, Title: "Biometrics off", Remediation: "Enroll a fingerprint"},
+			{ID: "secure_boot_disabled", Severity: SeverityHigh, Check: CheckSecureBoot, Title: "Secure Boot off", Remediation: "Turn on Secure Boot", RemediationCommand: "systemctl reboot --firmware-setup"},
+		},
+	}
+
+	e := ExplainScore(summary)
+	// Eleven checks at 25 points, encryption at 50; firmware did not run
+	if e.PossiblePoints != 300 || e.EarnedPoints != 200 {
+		t.Errorf("points = %v/%v, want 200/300", e.EarnedPoints, e.PossiblePoints)
+	}
+	byCheck := byCheckOf(e)
+	if c := byCheck[CheckEncryption]; c.Weight != 2 || c.AwardedPoints != 50 {
+		t.Errorf("encryption = %+v, want 50 points at weight 2", c)
+	}
+	if c := byCheck[CheckSecureBoot]; c.Result != CheckResultFail || c.AwardedPoints != 0 || c.Reason != "Secure Boot off" {
+		t.Errorf("secure_boot = %+v, want failed for its finding", c)
+	}
+	if c := byCheck[CheckUptime]; c.Result != CheckResultTimeout {
+		t.Errorf("uptime = %+v, want a timeout", c)
+	}
+	if c := byCheck[CheckFirmware]; c.Result != CheckResultNotRun || c.PossiblePoints != 25 {
+		t.Errorf("firmware = %+v, want not run and still counted", c)
+	}
+	if !strings.Contains(e.StatusReason, "fair from 50") {
+		t.Errorf("status reason = %q", e.StatusReason)
+	}
+
+	// Every failure is worth the same, so the most severe finding wins
+	b := e.BestImprovement
+	if b == nil || b.Check != CheckSecureBoot || b.Points != 25 || b.Score != 75 || b.Status != "good" ||
+		b.Action != "Turn on Secure Boot" || b.Command != "systemctl reboot --firmware-setup" {
+		t.Errorf("best improvement = %+v, want secure_boot to 75", b)
+	}
+
+	// A heavier check beats a more severe finding
+	t.Setenv(CheckWeightsEnv, "biometrics=3")
+	if b := ExplainScore(summary).BestImprovement; b == nil || b.Check != CheckBiometrics || b.Points != 75 {
+		t.Errorf("best improvement = %+v, want biometrics", b)
+	}
+
+	// A mandatory failure makes the status critical after any other fix
+	t.Setenv(CheckWeightsEnv, "")
+	t.Setenv(MandatoryChecksEnv, CheckBiometrics)
+	summary.MandatoryFailures = []string{CheckBiometrics}
+	e = ExplainScore(summary)
+	if b := e.BestImprovement; b == nil || b.Check != CheckBiometrics || b.Status == "critical" {
+		t.Errorf("best improvement = %+v, want the mandatory check", b)
+	}
+	if reason := byCheckOf(e)[CheckBiometrics].Reason; !strings.Contains(reason, "mandatory") {
+		t.Errorf("biometrics reason = %q, want it marked mandatory", reason)
+	}
+}
+
+func TestExplainScore_SkippedChecks(t *testing.T) {
+	t.Setenv(CheckWeightsEnv, "")
+	t.Setenv(InformationalChecksEnv, "")
+	t.Setenv(MandatoryChecksEnv, "")
+	t.Setenv(OnlyChecksEnv, "")
+	t.Setenv(DisableChecksEnv, CheckBiometrics)
+	t.Setenv(ScanProfileEnv, "")
+	t.Setenv(USBStoragePolicyEnv, "")
+	summary := &SecuritySummary{
+		Platform:          "linux",
+		CheckResults:      map[string]string{CheckTPM: CheckResultPass, CheckEncryption: CheckResultPass},
+		DisabledChecks:    []string{CheckBiometrics},
+		UnsupportedChecks: []string{CheckSecureBoot},
+	}
+
+	// Disabled and unsupported checks are left out of the points possible
+	e := ExplainScore(summary)
+	byCheck := byCheckOf(e)
+	if c := byCheck[CheckBiometrics]; c.Result != CheckResultDisabled || c.PossiblePoints != 0 {
+		t.Errorf("biometrics = %+v, want disabled and not scored", c)
+	}
+	if c := byCheck[CheckSecureBoot]; c.Result != CheckResultUnsupported || c.PossiblePoints != 0 {
+		t.Errorf("secure_boot = %+v, want unsupported and not scored", c)
+	}
+	if want := float64(checkPoints * (len(checksFor("linux")) - 2)); e.PossiblePoints != want {
+		t.Errorf("possible points = %v, want %v", e.PossiblePoints, want)
+	}
+	if b := e.BestImprovement; b != nil && (b.Check == CheckBiometrics || b.Check == CheckSecureBoot) {
+		t.Errorf("best improvement = %+v, want a scored check", b)
+	}
+}
+
+// byCheckOf indexes the checks of a score explanation
+func byCheckOf(e *ScoreExplanation) map[string]CheckScore {
+	checks := map[string]CheckScore{}
+	for _, c := range e.Checks {
+		checks[c.Check] = c
+	}
+	return checks
+}
@@ -2,38 +2,65 @@
 
 package inspector
 
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/IOKitLib.h>
+
+// Read the AppleSecureBootPolicy NVRAM variable from the I/O Registry.
+// Returns the policy byte, or -1 if the variable is not present.
+static int sb_readSecureBootPolicy() {
+    io_registry_entry_t options = IORegistryEntryFromPath(MACH_PORT_NULL, "IODeviceTree:/options");
+    if (options == MACH_PORT_NULL) {
+        return -1;
+    }
+
+    CFStringRef key = CFSTR("94b73556-2197-4702-82a8-3e1337dafbfb:AppleSecureBootPolicy");
+    CFTypeRef value = IORegistryEntryCreateCFProperty(options, key, kCFAllocatorDefault, 0);
+    IOObjectRelease(options);
+    if (value == NULL) {
+        return -1;
+    }
+
+    int policy = -1;
+    if (CFGetTypeID(value) == CFDataGetTypeID() && CFDataGetLength((CFDataRef)value) > 0) {
+        policy = CFDataGetBytePtr((CFDataRef)value)[0];
+    }
+    CFRelease(value);
+    return policy;
+}
+*/
+import "C"
 import (
-	"os/exec"
+	"context"
 	"strings"
-)
 
-// SecureBootResult contains Secure Boot status information
-type SecureBootResult struct {
-	Enabled        bool   `json:"enabled"`
-	Platform       string `json:"platform"`
-	Mode           string `json:"mode"`
-	PolicyVersion  string `json:"policy_version,omitempty"`
-	SecureBootType string `json:"secure_boot_type"`
-	Details        string `json:"details,omitempty"`
-}
+	"golang.org/x/sys/unix"
+)
 
 // GetSecureBootStatus returns the Secure Boot status (macOS)
 func GetSecureBootStatus() (*SecureBootResult, error) {
+	return GetSecureBootStatusContext(context.Background())
+}
+
+// GetSecureBootStatusContext is like GetSecureBootStatus but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetSecureBootStatusContext(ctx context.Context) (*SecureBootResult, error) {
+	if result, ok, err := loadFixture[SecureBootResult](ctx, "secure_boot"); ok {
+		return result, err
+	}
 	result := &SecureBootResult{
 		Platform: "darwin",
 	}
 
-	// Check boot policy using bputil (Apple Silicon) or csrutil/nvram (Intel)
-	// First, check if we're on Apple Silicon
-	out, err := exec.Command("sysctl", "-n", "hw.optional.arm64").Output()
-	isAppleSilicon := err == nil && strings.TrimSpace(string(out)) == "1"
-
-	if isAppleSilicon {
+	// Check boot policy using bputil (Apple Silicon) or the NVRAM policy (Intel T2)
+	if isAppleSilicon() {
 		// Apple Silicon - use bputil
 		result.SecureBootType = "apple_secure_boot"
 
 		// Try to get security mode
-		out, err := exec.Command("bputil", "-d").Output()
+		out, err := runCommand(ctx, "bputil", "-d")
 		if err == nil {
 			output := string(out)
 			if strings.Contains(output, "Full Security") {
@@ -57,36 +84,27 @@ func GetSecureBootStatus() (*SecureBootResult, error) {
 			// bputil requires admin privileges, assume enabled by default on Apple Silicon
 			result.Enabled = true
 			result.Mode = "assumed_full"
-			result.Details = "Apple Silicon default (verification requires admin)"
+			result.Details = "Apple Silicon default (not verified)"
+			result.Error = classifyExecError(ctx, "bputil", err)
 		}
 	} else {
 		// Intel Mac - check for T2 secure boot
 		result.SecureBootType = "t2_secure_boot"
 
-		// Try nvram to check secure boot
-		out, err := exec.Command("nvram", "94b73556-2197-4702-82a8-3e1337dafbfb:AppleSecureBootPolicy").Output()
+		// Read the policy from the I/O Registry, falling back to nvram(8)
+		policy, err := readSecureBootPolicy(ctx)
 		if err == nil {
-			output := strings.TrimSpace(string(out))
-			if strings.Contains(output, "%02") || strings.Contains(output, "2") {
-				result.Enabled = true
-				result.Mode = "full"
-				result.Details = "Full Security"
-			} else if strings.Contains(output, "%01") || strings.Contains(output, "1") {
-				result.Enabled = true
-				result.Mode = "medium"
-				result.Details = "Medium Security"
-			} else {
-				result.Enabled = false
-				result.Mode = "none"
-				result.Details = "No Security"
-			}
+			result.Enabled, result.Mode, result.Details = secureBootPolicyMode(policy)
 		} else {
+			nvramErr := classifyExecError(ctx, "nvram", err)
+
 			// Check if T2 is present (indicates secure boot capability)
-			out, err := exec.Command("system_profiler", "SPiBridgeDataType").Output()
+			out, err := runCommand(ctx, "system_profiler", "SPiBridgeDataType")
 			if err == nil && strings.Contains(string(out), "T2") {
 				result.Enabled = true
 				result.Mode = "assumed"
-				result.Details = "T2 chip detected (verification requires admin)"
+				result.Details = "T2 chip detected (not verified)"
+				result.Error = nvramErr
 			} else {
 				// No T2, no secure boot on Intel
 				result.Enabled = false
@@ -100,89 +118,52 @@ func GetSecureBootStatus() (*SecureBootResult, error) {
 	return result, nil
 }
 
-// FormatSecureBootTable formats Secure Boot status as a colored table
-func FormatSecureBootTable(result *SecureBootResult) string {
-	var sb strings.Builder
-	sb.WriteString("\n")
-	sb.WriteString(Header(IconLock + " Secure Boot Status"))
-	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 55)))
-	sb.WriteString("\n\n")
-
-	// Platform badge
-	sb.WriteString(BoldText("Platform: "))
-	sb.WriteString(Info(IconApple + " macOS"))
-	sb.WriteString("\n\n")
-
-	// Status table
-	sb.WriteString(TableTop(24, 26))
-	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Property", 24)),
-		Header(PadRight("Value", 26)),
-	))
-	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(24, 26))
-	sb.WriteString("\n")
-
-	// Enabled
-	sb.WriteString(TableRowColored(
-		PadRight(IconLock+" Secure Boot Enabled", 24),
-		PadRight(BoolToStatusColored(result.Enabled), 26),
-	))
-	sb.WriteString("\n")
-
-	// Type
-	var typeDisplay string
-	switch result.SecureBootType {
-	case "apple_secure_boot":
-		typeDisplay = "Apple Secure Boot"
-	case "t2_secure_boot":
-		typeDisplay = "T2 Secure Boot"
-	default:
-		typeDisplay = result.SecureBootType
+// Native probes, replaceable in tests
+var (
+	isAppleSilicon       = sysctlAppleSilicon
+	readSecureBootPolicy = secureBootPolicy
+)
+
+// sysctlAppleSilicon reports whether the hardware is Apple Silicon, even when
+// running under Rosetta
+func sysctlAppleSilicon() bool {
+	v, err := unix.SysctlUint32("hw.optional.arm64")
+	return err == nil && v == 1
+}
+
+// secureBootPolicy returns the AppleSecureBootPolicy NVRAM value, reading the
+// I/O Registry directly and falling back to `nvram -x` and plain `nvram`
+func secureBootPolicy(ctx context.Context) (int, error) {
+	if policy := int(C.sb_readSecureBootPolicy()); policy >= 0 {
+		return policy, nil
 	}
-	sb.WriteString(TableRowColored(
-		PadRight(IconShield+" Type", 24),
-		PadRight(typeDisplay, 26),
-	))
-	sb.WriteString("\n")
-
-	// Mode
-	modeDisplay := result.Mode
-	switch result.Mode {
-	case "full":
-		modeDisplay = Success("Full Security")
-	case "reduced":
-		modeDisplay = Warning("Reduced Security")
-	case "permissive", "none":
-		modeDisplay = Danger("Permissive/None")
-	case "medium":
-		modeDisplay = Warning("Medium Security")
+	if out, err := runCommand(ctx, "nvram", "-x", appleSecureBootPolicyVar); err == nil {
+		if policy, err := parseNvramPolicyPlist(out); err == nil {
+			return policy, nil
+		}
 	}
-	sb.WriteString(TableRowColored(
-		PadRight(IconStatus+" Mode", 24),
-		PadRight(modeDisplay, 26),
-	))
-	sb.WriteString("\n")
-
-	sb.WriteString(TableBottom(24, 26))
-	sb.WriteString("\n")
-
-	// Details if available
-	if result.Details != "" {
-		sb.WriteString("\n")
-		sb.WriteString(Muted("Details: " + result.Details))
-		sb.WriteString("\n")
+	out, err := runCommand(ctx, "nvram", appleSecureBootPolicyVar)
+	if err != nil {
+		return 0, err
 	}
+	return parseNvramPolicyText(string(out))
+}
+
+// FormatSecureBootTable formats Secure Boot status as a colored table
+func FormatSecureBootTable(result *SecureBootResult) string {
+	return FormatSecureBootTableWith(CurrentStyler(), result)
+}
 
-	return sb.String()
+// FormatSecureBootTableWith is like FormatSecureBootTable but styles the
+// table with st
+func FormatSecureBootTableWith(st Styler, result *SecureBootResult) string {
+	return formatDarwinSecureBootTable(st, result)
 }
 
 // FormatSecureBoot formats Secure Boot status in the specified format
 func FormatSecureBoot(result *SecureBootResult, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatSecureBootTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatSecureBootTableWith(st, result)
 	}, format)
 }
 
@@ -0,0 +1,78 @@
+//go:build darwin
+
+package inspector
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+func TestGetSecureBootStatus_AppleSilicon(t *testing.T) {
+	tests := []struct {
+		name     string
+		bputil   string
+		wantMode string
+	}{
+		{"full", "darwin/bputil_full.txt", "full"},
+		{"reduced", "darwin/bputil_reduced.txt", "reduced"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubAppleSilicon(t, true)
+			fake := NewFakeRunner().Set("bputil -d", fixture(t, tt.bputil))
+			defer SetCommandRunner(SetCommandRunner(fake))
+
+			result, err := GetSecureBootStatusContext(context.Background())
+			if err != nil {
+				t.Fatalf("GetSecureBootStatus failed: %v", err)
+			}
+			if result.Mode != tt.wantMode || !result.Enabled {
+				t.Errorf("Mode = %q (enabled %v), want %q (enabled)", result.Mode, result.Enabled, tt.wantMode)
+			}
+			if result.SecureBootType != "apple_secure_boot" {
+				t.Errorf("SecureBootType = %q, want apple_secure_boot", result.SecureBootType)
+			}
+		})
+	}
+}
+
+func TestGetSecureBootStatus_BputilDenied(t *testing.T) {
+	stubAppleSilicon(t, true)
+	fake := NewFakeRunner().
+		SetError("bputil -d", &exec.ExitError{Stderr: []byte("bputil: Operation not permitted\n")})
+	defer SetCommandRunner(SetCommandRunner(fake))
+
+	result, err := GetSecureBootStatusContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetSecureBootStatus failed: %v", err)
+	}
+	if result.Mode != "assumed_full" || !errors.Is(result.Error, ErrPermissionDenied) {
+		t.Errorf("result = (%q, %v), want assumed_full with permission_denied", result.Mode, result.Error)
+	}
+}
+
+func TestGetSecureBootStatus_IntelPolicy(t *testing.T) {
+	stubAppleSilicon(t, false)
+	prev := readSecureBootPolicy
+	readSecureBootPolicy = func(context.Context) (int, error) { return secureBootPolicyMedium, nil }
+	t.Cleanup(func() { readSecureBootPolicy = prev })
+
+	result, err := GetSecureBootStatusContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetSecureBootStatus failed: %v", err)
+	}
+	if result.Mode != "medium" || !result.Enabled || result.SecureBootType != "t2_secure_boot" {
+		t.Errorf("result = (%q, %v, %q), want (medium, true, t2_secure_boot)", result.Mode, result.Enabled, result.SecureBootType)
+	}
+}
+
+// stubAppleSilicon overrides hardware detection for the duration of the test
+func stubAppleSilicon(t *testing.T, appleSilicon bool) {
+	t.Helper()
+	prev := isAppleSilicon
+	isAppleSilicon = func() bool { return appleSilicon }
+	t.Cleanup(func() { isAppleSilicon = prev })
+}
@@ -3,22 +3,21 @@
 package inspector
 
 import (
+	"context"
 	"os"
-	"strings"
 )
 
-// SecureBootResult contains Secure Boot status information
-type SecureBootResult struct {
-	Enabled        bool   `json:"enabled"`
-	Platform       string `json:"platform"`
-	Mode           string `json:"mode"`
-	PolicyVersion  string `json:"policy_version,omitempty"`
-	SecureBootType string `json:"secure_boot_type"`
-	Details        string `json:"details,omitempty"`
-}
-
 // GetSecureBootStatus returns the Secure Boot status (Linux)
 func GetSecureBootStatus() (*SecureBootResult, error) {
+	return GetSecureBootStatusContext(context.Background())
+}
+
+// GetSecureBootStatusContext is like GetSecureBootStatus but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetSecureBootStatusContext(ctx context.Context) (*SecureBootResult, error) {
+	if result, ok, err := loadFixture[SecureBootResult](ctx, "secure_boot"); ok {
+		return result, err
+	}
 	result := &SecureBootResult{
 		Platform: "linux",
 	}
@@ -40,11 +39,12 @@ func GetSecureBootStatus() (*SecureBootResult, error) {
 	// /sys/firmware/efi/efivars/SecureBoot-8be4df61-93ca-11d2-aa0d-00e098032b8c
 	secureBootPath := "/sys/firmware/efi/efivars/SecureBoot-8be4df61-93ca-11d2-aa0d-00e098032b8c"
 
-	data, err := os.ReadFile(secureBootPath)
+	data, err := readFile(secureBootPath)
 	if err != nil {
 		// Try alternative path or mokutil
 		result.Mode = "unknown"
-		result.Details = "Unable to read Secure Boot variable (may require root)"
+		result.Details = "Unable to read Secure Boot variable"
+		result.Error = classifyFileError(ctx, secureBootPath, err)
 
 		// Check if secureboot directory exists as fallback
 		if _, err := os.Stat("/sys/firmware/efi/efivars"); err == nil {
@@ -74,7 +74,7 @@ func GetSecureBootStatus() (*SecureBootResult, error) {
 
 	// Check SetupMode (indicates if keys can be modified)
 	setupModePath := "/sys/firmware/efi/efivars/SetupMode-8be4df61-93ca-11d2-aa0d-00e098032b8c"
-	if data, err := os.ReadFile(setupModePath); err == nil && len(data) >= 5 {
+	if data, err := readFile(setupModePath); err == nil && len(data) >= 5 {
 		if data[4] == 1 {
 			result.Details += " (Setup Mode active - keys can be modified)"
 		}
@@ -85,84 +85,19 @@ func GetSecureBootStatus() (*SecureBootResult, error) {
 
 // FormatSecureBootTable formats Secure Boot status as a colored table
 func FormatSecureBootTable(result *SecureBootResult) string {
-	var sb strings.Builder
-	sb.WriteString("\n")
-	sb.WriteString(Header(IconLock + " Secure Boot Status"))
-	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 55)))
-	sb.WriteString("\n\n")
-
-	// Platform badge
-	sb.WriteString(BoldText("Platform: "))
-	sb.WriteString(Info(IconChip + " Linux"))
-	sb.WriteString("\n\n")
-
-	// Status table
-	sb.WriteString(TableTop(24, 26))
-	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Property", 24)),
-		Header(PadRight("Value", 26)),
-	))
-	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(24, 26))
-	sb.WriteString("\n")
-
-	// Enabled
-	sb.WriteString(TableRowColored(
-		PadRight(IconLock+" Secure Boot Enabled", 24),
-		PadRight(BoolToStatusColored(result.Enabled), 26),
-	))
-	sb.WriteString("\n")
-
-	// Type
-	typeDisplay := result.SecureBootType
-	if result.SecureBootType == "uefi_secure_boot" {
-		typeDisplay = "UEFI Secure Boot"
-	} else if result.SecureBootType == "none" {
-		typeDisplay = Muted("Not Available")
-	}
-	sb.WriteString(TableRowColored(
-		PadRight(IconShield+" Type", 24),
-		PadRight(typeDisplay, 26),
-	))
-	sb.WriteString("\n")
-
-	// Mode
-	var modeDisplay string
-	switch result.Mode {
-	case "enabled":
-		modeDisplay = Success("Enabled")
-	case "disabled":
-		modeDisplay = Warning("Disabled")
-	case "legacy_bios":
-		modeDisplay = Danger("Legacy BIOS")
-	default:
-		modeDisplay = Muted(result.Mode)
-	}
-	sb.WriteString(TableRowColored(
-		PadRight(IconStatus+" Mode", 24),
-		PadRight(modeDisplay, 26),
-	))
-	sb.WriteString("\n")
-
-	sb.WriteString(TableBottom(24, 26))
-	sb.WriteString("\n")
-
-	// Details if available
-	if result.Details != "" {
-		sb.WriteString("\n")
-		sb.WriteString(Muted("Details: " + result.Details))
-		sb.WriteString("\n")
-	}
+	return FormatSecureBootTableWith(CurrentStyler(), result)
+}
 
-	return sb.String()
+// FormatSecureBootTableWith is like FormatSecureBootTable but styles the
+// table with st
+func FormatSecureBootTableWith(st Styler, result *SecureBootResult) string {
+	return formatLinuxSecureBootTable(st, result)
 }
 
 // FormatSecureBoot formats Secure Boot status in the specified format
 func FormatSecureBoot(result *SecureBootResult, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatSecureBootTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatSecureBootTableWith(st, result)
 	}, format)
 }
 
@@ -0,0 +1,250 @@
+package inspector
+
+import "strings"
+
+// SecureBootResult contains Secure Boot status information
+type SecureBootResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Enabled        bool        `json:"enabled"`
+	Platform       string      `json:"platform"`
+	Mode           string      `json:"mode"`
+	PolicyVersion  string      `json:"policy_version,omitempty"`
+	SecureBootType string      `json:"secure_boot_type"`
+	Details        string      `json:"details,omitempty"`
+	Error          *ProbeError `json:"error,omitempty"`
+}
+
+// formatDarwinSecureBootTable formats macOS Secure Boot status as a colored table
+func formatDarwinSecureBootTable(st Styler, result *SecureBootResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconLock + " " + T("Secure Boot Status")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	// Platform badge
+	sb.WriteString(st.BoldText(T("Platform:") + " "))
+	sb.WriteString(st.Info(IconApple + " macOS"))
+	sb.WriteString("\n\n")
+
+	// Status table
+	sb.WriteString(st.TableTop(24, 26))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Property"), 24)),
+		st.Header(PadRight(T("Value"), 26)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(24, 26))
+	sb.WriteString("\n")
+
+	// Enabled
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconLock+" "+T("Secure Boot Enabled"), 24),
+		PadRight(st.BoolToStatusColored(result.Enabled), 26),
+	))
+	sb.WriteString("\n")
+
+	// Type
+	var typeDisplay string
+	switch result.SecureBootType {
+	case "apple_secure_boot":
+		typeDisplay = "Apple Secure Boot"
+	case "t2_secure_boot":
+		typeDisplay = "T2 Secure Boot"
+	default:
+		typeDisplay = Truncate(result.SecureBootType, 26)
+	}
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconShield+" "+T("Type"), 24),
+		PadRight(typeDisplay, 26),
+	))
+	sb.WriteString("\n")
+
+	// Mode
+	modeDisplay := result.Mode
+	switch result.Mode {
+	case "full":
+		modeDisplay = st.Success(T("Full Security"))
+	case "reduced":
+		modeDisplay = st.Warning(T("Reduced Security"))
+	case "permissive", "none":
+		modeDisplay = st.Danger(T("Permissive/None"))
+	case "medium":
+		modeDisplay = st.Warning(T("Medium Security"))
+	}
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconStatus+" "+T("Mode"), 24),
+		PadRight(modeDisplay, 26),
+	))
+	sb.WriteString("\n")
+
+	sb.WriteString(st.TableBottom(24, 26))
+	sb.WriteString("\n")
+
+	// Details if available
+	if result.Details != "" {
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(T("Details:") + " " + result.Details))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(formatProbeError(st, result.Error))
+
+	return sb.String()
+}
+
+// formatLinuxSecureBootTable formats Linux Secure Boot status as a colored table
+func formatLinuxSecureBootTable(st Styler, result *SecureBootResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconLock + " " + T("Secure Boot Status")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	// Platform badge
+	sb.WriteString(st.BoldText(T("Platform:") + " "))
+	sb.WriteString(st.Info(IconChip + " Linux"))
+	sb.WriteString("\n\n")
+
+	// Status table
+	sb.WriteString(st.TableTop(24, 26))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Property"), 24)),
+		st.Header(PadRight(T("Value"), 26)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(24, 26))
+	sb.WriteString("\n")
+
+	// Enabled
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconLock+" "+T("Secure Boot Enabled"), 24),
+		PadRight(st.BoolToStatusColored(result.Enabled), 26),
+	))
+	sb.WriteString("\n")
+
+	// Type
+	typeDisplay := Truncate(result.SecureBootType, 26)
+	if result.SecureBootType == "uefi_secure_boot" {
+		typeDisplay = "UEFI Secure Boot"
+	} else if result.SecureBootType == "none" {
+		typeDisplay = st.Muted(T("Not Available"))
+	}
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconShield+" "+T("Type"), 24),
+		PadRight(typeDisplay, 26),
+	))
+	sb.WriteString("\n")
+
+	// Mode
+	var modeDisplay string
+	switch result.Mode {
+	case "enabled":
+		modeDisplay = st.Success(T("Enabled"))
+	case "disabled":
+		modeDisplay = st.Warning(T("Disabled"))
+	case "legacy_bios":
+		modeDisplay = st.Danger(T("Legacy BIOS"))
+	default:
+		modeDisplay = st.Muted(result.Mode)
+	}
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconStatus+" "+T("Mode"), 24),
+		PadRight(modeDisplay, 26),
+	))
+	sb.WriteString("\n")
+
+	sb.WriteString(st.TableBottom(24, 26))
+	sb.WriteString("\n")
+
+	// Details if available
+	if result.Details != "" {
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(T("Details:") + " " + result.Details))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(formatProbeError(st, result.Error))
+
+	return sb.String()
+}
+
+// formatWindowsSecureBootTable formats Windows Secure Boot status as a colored table
+func formatWindowsSecureBootTable(st Styler, result *SecureBootResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconLock + " " + T("Secure Boot Status")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	// Platform badge
+	sb.WriteString(st.BoldText(T("Platform:") + " "))
+	sb.WriteString(st.Info(IconChip + " Windows"))
+	sb.WriteString("\n\n")
+
+	// Status table
+	sb.WriteString(st.TableTop(24, 26))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Property"), 24)),
+		st.Header(PadRight(T("Value"), 26)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(24, 26))
+	sb.WriteString("\n")
+
+	// Enabled
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconLock+" "+T("Secure Boot Enabled"), 24),
+		PadRight(st.BoolToStatusColored(result.Enabled), 26),
+	))
+	sb.WriteString("\n")
+
+	// Type
+	typeDisplay := Truncate(result.SecureBootType, 26)
+	if result.SecureBootType == "uefi_secure_boot" {
+		typeDisplay = "UEFI Secure Boot"
+	} else if result.SecureBootType == "none" {
+		typeDisplay = st.Muted(T("Not Available"))
+	}
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconShield+" "+T("Type"), 24),
+		PadRight(typeDisplay, 26),
+	))
+	sb.WriteString("\n")
+
+	// Mode
+	modeDisplay := result.Mode
+	switch result.Mode {
+	case "enabled":
+		modeDisplay = st.Success(T("Enabled"))
+	case "disabled":
+		modeDisplay = st.Warning(T("Disabled"))
+	case "legacy_bios":
+		modeDisplay = st.Danger(T("Legacy BIOS"))
+	default:
+		modeDisplay = st.Muted(result.Mode)
+	}
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconStatus+" "+T("Mode"), 24),
+		PadRight(modeDisplay, 26),
+	))
+	sb.WriteString("\n")
+
+	sb.WriteString(st.TableBottom(24, 26))
+	sb.WriteString("\n")
+
+	// Details if available
+	if result.Details != "" {
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(T("Details:") + " " + result.Details))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(formatProbeError(st, result.Error))
+
+	return sb.String()
+}
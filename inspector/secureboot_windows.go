@@ -3,24 +3,15 @@
 package inspector
 
 import (
-	"strings"
+	"context"
 	"syscall"
 	"unsafe"
 )
 
-// SecureBootResult contains Secure Boot status information
-type SecureBootResult struct {
-	Enabled        bool   `json:"enabled"`
-	Platform       string `json:"platform"`
-	Mode           string `json:"mode"`
-	PolicyVersion  string `json:"policy_version,omitempty"`
-	SecureBootType string `json:"secure_boot_type"`
-	Details        string `json:"details,omitempty"`
-}
-
 // Windows error codes not exported by syscall package
 const (
-	ERROR_INVALID_FUNCTION = syscall.Errno(1)
+	ERROR_INVALID_FUNCTION   = syscall.Errno(1)
+	ERROR_PRIVILEGE_NOT_HELD = syscall.Errno(1314)
 )
 
 var (
@@ -30,6 +21,15 @@ var (
 
 // GetSecureBootStatus returns the Secure Boot status (Windows)
 func GetSecureBootStatus() (*SecureBootResult, error) {
+	return GetSecureBootStatusContext(context.Background())
+}
+
+// GetSecureBootStatusContext is like GetSecureBootStatus but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetSecureBootStatusContext(ctx context.Context) (*SecureBootResult, error) {
+	if result, ok, err := loadFixture[SecureBootResult](ctx, "secure_boot"); ok {
+		return result, err
+	}
 	result := &SecureBootResult{
 		Platform:       "windows",
 		SecureBootType: "uefi_secure_boot",
@@ -50,6 +50,11 @@ func GetSecureBootStatus() (*SecureBootResult, error) {
 		uintptr(unsafe.Pointer(&buf[0])),
 		uintptr(len(buf)),
 	)
+	var readErr error
+	if ret == 0 {
+		readErr = err
+	}
+	recordSource(SourceAPI, "GetFirmwareEnvironmentVariable "+secureBootVar+"-"+guid, readErr)
 
 	if ret == 0 {
 		// Function failed - might be in Legacy BIOS mode or no permission
@@ -61,7 +66,13 @@ func GetSecureBootStatus() (*SecureBootResult, error) {
 		} else {
 			result.Enabled = false
 			result.Mode = "unknown"
-			result.Details = "Unable to read Secure Boot status (may require admin)"
+			result.Details = "Unable to read Secure Boot status"
+			if err == ERROR_PRIVILEGE_NOT_HELD || err == syscall.ERROR_ACCESS_DENIED {
+				result.Error = newProbeError(ctx, ErrPermissionDenied, "GetFirmwareEnvironmentVariable",
+					"reading UEFI variables requires the SeSystemEnvironmentPrivilege")
+			} else {
+				result.Error = newProbeError(ctx, ErrProbeFailed, "GetFirmwareEnvironmentVariable", err.Error())
+			}
 		}
 		return result, nil
 	}
@@ -82,84 +93,19 @@ func GetSecureBootStatus() (*SecureBootResult, error) {
 
 // FormatSecureBootTable formats Secure Boot status as a colored table
 func FormatSecureBootTable(result *SecureBootResult) string {
-	var sb strings.Builder
-	sb.WriteString("\n")
-	sb.WriteString(Header(IconLock + " Secure Boot Status"))
-	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 55)))
-	sb.WriteString("\n\n")
-
-	// Platform badge
-	sb.WriteString(BoldText("Platform: "))
-	sb.WriteString(Info(IconChip + " Windows"))
-	sb.WriteString("\n\n")
-
-	// Status table
-	sb.WriteString(TableTop(24, 26))
-	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Property", 24)),
-		Header(PadRight("Value", 26)),
-	))
-	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(24, 26))
-	sb.WriteString("\n")
-
-	// Enabled
-	sb.WriteString(TableRowColored(
-		PadRight(IconLock+" Secure Boot Enabled", 24),
-		PadRight(BoolToStatusColored(result.Enabled), 26),
-	))
-	sb.WriteString("\n")
-
-	// Type
-	typeDisplay := result.SecureBootType
-	if result.SecureBootType == "uefi_secure_boot" {
-		typeDisplay = "UEFI Secure Boot"
-	} else if result.SecureBootType == "none" {
-		typeDisplay = Muted("Not Available")
-	}
-	sb.WriteString(TableRowColored(
-		PadRight(IconShield+" Type", 24),
-		PadRight(typeDisplay, 26),
-	))
-	sb.WriteString("\n")
-
-	// Mode
-	modeDisplay := result.Mode
-	switch result.Mode {
-	case "enabled":
-		modeDisplay = Success("Enabled")
-	case "disabled":
-		modeDisplay = Warning("Disabled")
-	case "legacy_bios":
-		modeDisplay = Danger("Legacy BIOS")
-	default:
-		modeDisplay = Muted(result.Mode)
-	}
-	sb.WriteString(TableRowColored(
-		PadRight(IconStatus+" Mode", 24),
-		PadRight(modeDisplay, 26),
-	))
-	sb.WriteString("\n")
-
-	sb.WriteString(TableBottom(24, 26))
-	sb.WriteString("\n")
-
-	// Details if available
-	if result.Details != "" {
-		sb.WriteString("\n")
-		sb.WriteString(Muted("Details: " + result.Details))
-		sb.WriteString("\n")
-	}
+	return FormatSecureBootTableWith(CurrentStyler(), result)
+}
 
-	return sb.String()
+// FormatSecureBootTableWith is like FormatSecureBootTable but styles the
+// table with st
+func FormatSecureBootTableWith(st Styler, result *SecureBootResult) string {
+	return formatWindowsSecureBootTable(st, result)
 }
 
 // FormatSecureBoot formats Secure Boot status in the specified format
 func FormatSecureBoot(result *SecureBootResult, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatSecureBootTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatSecureBootTableWith(st, result)
 	}, format)
 }
 
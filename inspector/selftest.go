@@ -0,0 +1,254 @@
+package inspector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Fault scenarios injected by SelfTest
+const (
+	FaultMissingTool      = "missing_tool"
+	FaultPermissionDenied = "permission_denied"
+	FaultMalformedOutput  = "malformed_output"
+)
+
+// Self-test outcomes. A probe passes unless it panics or hangs.
+const (
+	OutcomeOK       = "ok"       // returned a result with no error
+	OutcomeDegraded = "degraded" // returned a result carrying a probe error
+	OutcomeError    = "error"    // returned an error instead of a result
+	OutcomePanic    = "panic"
+	OutcomeTimeout  = "timeout"
+)
+
+// selfTestTimeout bounds each probe run; a probe that exceeds it is reported
+// as hanging
+const selfTestTimeout = 10 * time.Second
+
+// malformedOutput is returned for every command in the malformed output
+// scenario: truncated plist, binary noise, and half a table
+var malformedOutput = []byte("<?xml version=\"1.0\"?>\n<plist version=\"1.0\"><dict><key>Containers\x00\xff\xfe\n" +
+	"FileVault is\n- #\n0 1024 crypt\n{\"unterminated\": ")
+
+// SelfTestCase is the outcome of one probe under one fault scenario
+type SelfTestCase struct {
+	Check    string `json:"check"`
+	Scenario string `json:"scenario"`
+	Outcome  string `json:"outcome"`
+	Passed   bool   `json:"passed"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// SelfTestResult reports how each inspector degrades under injected faults
+type SelfTestResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string         `json:"platform"`
+	Passed   bool           `json:"passed"`
+	Cases    []SelfTestCase `json:"cases"`
+}
+
+// faultRunner is a CommandRunner that fails every command the same way
+type faultRunner struct {
+	fault string
+}
+
+// Output simulates the fault for any command
+func (f faultRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	switch f.fault {
+	case FaultPermissionDenied:
+		return nil, &exec.ExitError{Stderr: []byte(name + ": Permission denied\n")}
+	case FaultMalformedOutput:
+		return malformedOutput, nil
+	}
+	return nil, &exec.Error{Name: name, Err: exec.ErrNotFound}
+}
+
+// LookPath reports every tool as missing in the missing tool scenario and
+// installed otherwise, so the fault reaches the command itself
+func (f faultRunner) LookPath(file string) (string, error) {
+	if f.fault == FaultMissingTool {
+		return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+	}
+	return "/usr/bin/" + file, nil
+}
+
+// checkProbe runs one security check inspector, for SelfTest and Doctor
+type checkProbe struct {
+	check string
+	run   func(ctx context.Context) (any, error)
+}
+
+// checkProbes returns the supported, enabled inspectors
+func checkProbes() []checkProbe {
+	var probes []checkProbe
+	for _, c := range checkRegistry {
+		if c.isSupported() && CheckEnabled(c.id) {
+			probes = append(probes, checkProbe{check: c.id, run: c.run})
+		}
+	}
+	return probes
+}
+
+// SelfTest runs every supported inspector against simulated missing tools,
+// permission-denied failures, and malformed command output, and checks that
+// each one degrades gracefully instead of panicking or hanging.
+//
+// Faults are injected through SetCommandRunner, so SelfTest must not run
+// concurrently with other scans. Probes that read files or devices directly
+// (sysfs, efivars, WMI) still see the real system.
+func SelfTest() *SelfTestResult {
+	result := &SelfTestResult{Platform: runtime.GOOS, Passed: true}
+
+	for _, fault := range []string{FaultMissingTool, FaultPermissionDenied, FaultMalformedOutput} {
+		prev := SetCommandRunner(faultRunner{fault: fault})
+		for _, probe := range checkProbes() {
+			c := runSelfTestProbe(probe)
+			c.Scenario = fault
+			if !c.Passed {
+				result.Passed = false
+			}
+			result.Cases = append(result.Cases, c)
+		}
+		SetCommandRunner(prev)
+	}
+	return result
+}
+
+// runSelfTestProbe runs one probe, recovering panics and bounding its
+// runtime. A probe that hangs has its context canceled.
+func runSelfTestProbe(probe checkProbe) SelfTestCase {
+	ctx, cancel := context.WithTimeout(context.Background(), selfTestTimeout)
+	defer cancel()
+
+	type outcome struct {
+		value any
+		err   error
+		panic any
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{panic: r}
+			}
+		}()
+		v, err := probe.run(ctx)
+		done <- outcome{value: v, err: err}
+	}()
+
+	c := SelfTestCase{Check: probe.check}
+	select {
+	case o := <-done:
+		switch {
+		case o.panic != nil:
+			c.Outcome = OutcomePanic
+			c.Detail = fmt.Sprint(o.panic)
+		case o.err != nil:
+			c.Outcome = OutcomeError
+			c.Detail = ErrorMessage(o.err)
+			c.Passed = true
+		default:
+			c.Outcome = OutcomeOK
+			if pe := resultProbeError(o.value); pe != nil {
+				c.Outcome = OutcomeDegraded
+				c.Detail = pe.Error()
+			}
+			c.Passed = true
+		}
+	case <-ctx.Done():
+		c.Outcome = OutcomeTimeout
+		c.Detail = fmt.Sprintf("no result after %s", selfTestTimeout)
+	}
+	return c
+}
+
+// resultProbeError returns the probe error a result carries in its "error"
+// field, if any. Result types differ per platform, so this goes through JSON.
+func resultProbeError(v any) *ProbeError {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var carrier struct {
+		Error *ProbeError `json:"error"`
+	}
+	if json.Unmarshal(data, &carrier) != nil {
+		return nil
+	}
+	return carrier.Error
+}
+
+// FormatSelfTestTable formats self-test results as a colored table
+func FormatSelfTestTable(result *SelfTestResult) string {
+	return FormatSelfTestTableWith(CurrentStyler(), result)
+}
+
+// FormatSelfTestTableWith is like FormatSelfTestTable but styles the table
+// with st
+func FormatSelfTestTableWith(st Styler, result *SelfTestResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconStatus + " Inspector Self-Test"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(st.TableTop(14, 19, 10))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight("Check", 14)),
+		st.Header(PadRight("Scenario", 19)),
+		st.Header(PadRight("Outcome", 10)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(14, 19, 10))
+	sb.WriteString("\n")
+
+	for _, c := range result.Cases {
+		var outcome string
+		switch c.Outcome {
+		case OutcomeOK:
+			outcome = st.Success(c.Outcome)
+		case OutcomeDegraded, OutcomeError:
+			outcome = st.Info(c.Outcome)
+		default:
+			outcome = st.Danger(c.Outcome)
+		}
+		sb.WriteString(st.TableRowColored(
+			PadRight(c.Check, 14),
+			PadRight(c.Scenario, 19),
+			PadRight(outcome, 10),
+		))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.TableBottom(14, 19, 10))
+	sb.WriteString("\n\n")
+
+	if result.Passed {
+		sb.WriteString(st.Success(IconCheck + " All inspectors degrade gracefully"))
+	} else {
+		sb.WriteString(st.Danger(IconCross + " Some inspectors failed under injected faults:"))
+		for _, c := range result.Cases {
+			if !c.Passed {
+				sb.WriteString(fmt.Sprintf("\n  %s %s (%s): %s", IconArrow, c.Check, c.Scenario, c.Detail))
+			}
+		}
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// FormatSelfTest formats self-test results in the specified format
+func FormatSelfTest(result *SelfTestResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatSelfTestTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,73 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSelfTest(t *testing.T) {
+	result := SelfTest()
+	if !result.Passed {
+		for _, c := range result.Cases {
+			if !c.Passed {
+				t.Errorf("%s under %s: %s (%s)", c.Check, c.Scenario, c.Outcome, c.Detail)
+			}
+		}
+	}
+	if len(result.Cases)%3 != 0 {
+		t.Errorf("len(Cases) = %d, want one case per probe per scenario", len(result.Cases))
+	}
+
+	// The real runner is restored afterwards
+	if _, ok := currentRunner(context.Background()).(ExecRunner); !ok {
+		t.Errorf("runner after SelfTest = %T, want ExecRunner", currentRunner(context.Background()))
+	}
+}
+
+func TestFaultRunner(t *testing.T) {
+	_, err := faultRunner{fault: FaultMissingTool}.Output(context.Background(), "fdesetup", "status")
+	if got := classifyExecError(context.Background(), "fdesetup", err); got.Code != CodeToolMissing {
+		t.Errorf("missing tool: Code = %q, want %q", got.Code, CodeToolMissing)
+	}
+
+	_, err = faultRunner{fault: FaultPermissionDenied}.Output(context.Background(), "bputil", "-d")
+	if got := classifyExecError(context.Background(), "bputil", err); got.Code != CodePermissionDenied {
+		t.Errorf("permission denied: Code = %q, want %q", got.Code, CodePermissionDenied)
+	}
+
+	out, err := faultRunner{fault: FaultMalformedOutput}.Output(context.Background(), "diskutil", "apfs", "list", "-plist")
+	if err != nil || len(out) == 0 {
+		t.Errorf("malformed output = (%q, %v), want garbage and no error", out, err)
+	}
+	if _, err := parseAPFSList(out); err == nil {
+		t.Error("parseAPFSList accepted malformed output")
+	}
+}
+
+func TestRunSelfTestProbe(t *testing.T) {
+	tests := []struct {
+		name    string
+		run     func(context.Context) (any, error)
+		outcome string
+		passed  bool
+	}{
+		{"ok", func(context.Context) (any, error) { return struct{}{}, nil }, OutcomeOK, true},
+		{"degraded", func(context.Context) (any, error) {
+			return &struct {
+				Error *ProbeError `json:"error,omitempty"`
+			}{newProbeError(context.Background(), ErrToolMissing, "dmsetup", "dmsetup is not installed")}, nil
+		}, OutcomeDegraded, true},
+		{"error", func(context.Context) (any, error) { return nil, errors.New("boom") }, OutcomeError, true},
+		{"panic", func(context.Context) (any, error) { panic("nil map") }, OutcomePanic, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := runSelfTestProbe(checkProbe{check: "test", run: tt.run})
+			if c.Outcome != tt.outcome || c.Passed != tt.passed {
+				t.Errorf("case = (%q, %v), want (%q, %v)", c.Outcome, c.Passed, tt.outcome, tt.passed)
+			}
+		})
+	}
+}
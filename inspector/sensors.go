@@ -0,0 +1,397 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/sensors"
+)
+
+// Sensor kinds
+const (
+	SensorCPU     = "cpu"
+	SensorGPU     = "gpu"
+	SensorDisk    = "disk"
+	SensorBattery = "battery"
+	SensorOther   = "other"
+)
+
+// Sensor statuses
+const (
+	SensorOK       = "ok"
+	SensorHigh     = "high"
+	SensorCritical = "critical"
+)
+
+// Thresholds used when a sensor does not report its own limits
+const (
+	defaultHighCelsius     = 85.0
+	defaultCriticalCelsius = 95.0
+)
+
+// TemperatureSensor is one temperature reading
+type TemperatureSensor struct {
+	Name    string  `json:"name"`
+	Kind    string  `json:"kind"`
+	Celsius float64 `json:"celsius"`
+	// HighCelsius and CriticalCelsius are the limits the sensor reports, if any
+	HighCelsius     float64 `json:"high_celsius,omitempty"`
+	CriticalCelsius float64 `json:"critical_celsius,omitempty"`
+	Status          string  `json:"status"`
+}
+
+// FanSensor is one fan speed reading
+type FanSensor struct {
+	Name   string  `json:"name"`
+	RPM    float64 `json:"rpm,omitempty"`
+	MaxRPM float64 `json:"max_rpm,omitempty"`
+	// Percent is the duty cycle, for fans that report it instead of RPM (NVIDIA GPUs)
+	Percent float64 `json:"percent,omitempty"`
+}
+
+// SensorsResult contains temperature and fan sensor readings
+type SensorsResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Temperatures []TemperatureSensor `json:"temperatures"`
+	Fans         []FanSensor         `json:"fans"`
+	// Anomalies lists sensors at or above their high threshold
+	Anomalies []string `json:"anomalies,omitempty"`
+	// Error is set when no temperature source could be read
+	Error *ProbeError `json:"error,omitempty"`
+}
+
+// GetSensors returns temperature and fan readings from gopsutil, platform
+// fallbacks (hwmon fans on Linux, thermal zone counters on Windows), and
+// nvidia-smi for NVIDIA GPUs
+func GetSensors(ctx context.Context) (*SensorsResult, error) {
+	if result, ok, err := loadFixture[SensorsResult](ctx, "sensors"); ok {
+		return result, err
+	}
+	result := &SensorsResult{
+		Temperatures: []TemperatureSensor{},
+		Fans:         []FanSensor{},
+	}
+
+	temps, err := sensors.TemperaturesWithContext(ctx)
+	var warnings *sensors.Warnings
+	if err != nil && !errors.As(err, &warnings) {
+		result.Error = newProbeError(ctx, ErrProbeFailed, "sensors", err.Error())
+	}
+	for _, t := range temps {
+		// Unpopulated sensors read as zero or below
+		if t.Temperature <= 0 {
+			continue
+		}
+		result.Temperatures = append(result.Temperatures, newTemperatureSensor(t.SensorKey, t.Temperature, t.High, t.Critical))
+	}
+	if len(result.Temperatures) == 0 {
+		fallback, ferr := platformTemperatures(ctx)
+		result.Temperatures = append(result.Temperatures, fallback...)
+		if ferr != nil && result.Error == nil {
+			result.Error = ferr
+		}
+	}
+	if len(result.Temperatures) > 0 {
+		result.Error = nil
+	}
+
+	result.Fans = append(result.Fans, platformFans()...)
+
+	// NVIDIA GPUs are invisible to hwmon and WMI without vendor drivers
+	if _, err := lookPath(ctx, "nvidia-smi"); err == nil {
+		if out, err := runCommand(ctx, "nvidia-smi", "--query-gpu=index,name,temperature.gpu,fan.speed", "--format=csv,noheader,nounits"); err == nil {
+			gpuTemps, gpuFans := parseNvidiaSMISensors(out)
+			result.Temperatures = append(result.Temperatures, gpuTemps...)
+			result.Fans = append(result.Fans, gpuFans...)
+		}
+	}
+
+	for _, t := range result.Temperatures {
+		if t.Status != SensorOK {
+			result.Anomalies = append(result.Anomalies, fmt.Sprintf("%s at %.0f°C (%s)", t.Name, t.Celsius, t.Status))
+		}
+	}
+	return result, nil
+}
+
+// newTemperatureSensor classifies a reading and rates it against the
+// sensor's own limits, or the defaults when it reports none
+func newTemperatureSensor(name string, celsius, high, critical float64) TemperatureSensor {
+	t := TemperatureSensor{
+		Name:            name,
+		Kind:            sensorKind(name),
+		Celsius:         celsius,
+		HighCelsius:     high,
+		CriticalCelsius: critical,
+	}
+	// Some drivers report placeholder limits (e.g. 0 or absurdly high values)
+	if high <= 0 || high > 150 {
+		high = defaultHighCelsius
+	}
+	if critical <= 0 || critical > 150 {
+		critical = defaultCriticalCelsius
+	}
+	switch {
+	case celsius >= critical:
+		t.Status = SensorCritical
+	case celsius >= high:
+		t.Status = SensorHigh
+	default:
+		t.Status = SensorOK
+	}
+	return t
+}
+
+// sensorKindPatterns maps sensor name substrings to kinds, most specific first
+var sensorKindPatterns = []struct {
+	substr string
+	kind   string
+}{
+	{"amdgpu", SensorGPU},
+	{"nouveau", SensorGPU},
+	{"radeon", SensorGPU},
+	{"gpu", SensorGPU},
+	{"nvme", SensorDisk},
+	{"drivetemp", SensorDisk},
+	{"ssd", SensorDisk},
+	{"battery", SensorBattery},
+	{"bat", SensorBattery},
+	{"coretemp", SensorCPU},
+	{"k10temp", SensorCPU},
+	{"zenpower", SensorCPU},
+	{"cpu", SensorCPU},
+	{"package", SensorCPU},
+	{"tctl", SensorCPU},
+	{"tdie", SensorCPU},
+	{"core", SensorCPU},
+	{"soc", SensorCPU},
+	{"tc0", SensorCPU}, // Intel Mac SMC keys (TC0P, TC0D, ...)
+}
+
+// sensorKind guesses what a sensor measures from its name
+func sensorKind(name string) string {
+	n := strings.ToLower(name)
+	for _, p := range sensorKindPatterns {
+		if strings.Contains(n, p.substr) {
+			return p.kind
+		}
+	}
+	return SensorOther
+}
+
+// readHwmonFans reads fan speeds from /sys/class/hwmon (fanN_input, with
+// optional fanN_label and fanN_max)
+func readHwmonFans(root fs.FS) []FanSensor {
+	inputs, _ := fs.Glob(root, "sys/class/hwmon/hwmon*/fan*_input")
+	var fans []FanSensor
+	for _, input := range inputs {
+		dir := path.Dir(input)
+		base := strings.TrimSuffix(path.Base(input), "_input")
+		rpm, ok := readSysFloat(root, input)
+		if !ok {
+			continue
+		}
+		chip := readSysString(root, path.Join(dir, "name"))
+		name := readSysString(root, path.Join(dir, base+"_label"))
+		if name == "" {
+			name = base
+		}
+		if chip != "" {
+			name = chip + " " + name
+		}
+		fan := FanSensor{Name: name, RPM: rpm}
+		if max, ok := readSysFloat(root, path.Join(dir, base+"_max")); ok {
+			fan.MaxRPM = max
+		}
+		fans = append(fans, fan)
+	}
+	return fans
+}
+
+// readSysString reads a trimmed sysfs attribute from root
+func readSysString(root fs.FS, name string) string {
+	data, err := readFSFile(root, name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readSysFloat reads a numeric sysfs attribute from root
+func readSysFloat(root fs.FS, name string) (float64, bool) {
+	v, err := strconv.ParseFloat(readSysString(root, name), 64)
+	return v, err == nil
+}
+
+// parseNvidiaSMISensors parses
+// `nvidia-smi --query-gpu=index,name,temperature.gpu,fan.speed --format=csv,noheader,nounits`.
+// Passively cooled GPUs report "[N/A]" for fan speed.
+func parseNvidiaSMISensors(data []byte) ([]TemperatureSensor, []FanSensor) {
+	var temps []TemperatureSensor
+	var fans []FanSensor
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ",")
+		if len(fields) != 4 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		name := fmt.Sprintf("GPU %s %s", fields[0], fields[1])
+		if c, err := strconv.ParseFloat(fields[2], 64); err == nil {
+			t := newTemperatureSensor(name, c, 0, 0)
+			t.Kind = SensorGPU
+			temps = append(temps, t)
+		}
+		if pct, err := strconv.ParseFloat(fields[3], 64); err == nil {
+			fans = append(fans, FanSensor{Name: name, Percent: pct})
+		}
+	}
+	return temps, fans
+}
+
+// FormatSensorsTable formats sensor readings as a colored table
+func FormatSensorsTable(result *SensorsResult) string {
+	return FormatSensorsTableWith(CurrentStyler(), result)
+}
+
+// FormatSensorsTableWith is like FormatSensorsTable but styles the table
+// with st
+func FormatSensorsTableWith(st Styler, result *SensorsResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconThermometer + " " + T("Sensors")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(formatProbeError(st, result.Error))
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString(st.BoldText(T("Temperatures:")))
+	sb.WriteString("\n")
+	if len(result.Temperatures) == 0 {
+		sb.WriteString(st.Muted("  " + T("No temperature sensors found")))
+		sb.WriteString("\n")
+	} else {
+		// Hottest first within each kind keeps the table scannable
+		temps := slices.Clone(result.Temperatures)
+		slices.SortStableFunc(temps, func(a, b TemperatureSensor) int {
+			if a.Kind != b.Kind {
+				return strings.Compare(a.Kind, b.Kind)
+			}
+			switch {
+			case a.Celsius > b.Celsius:
+				return -1
+			case a.Celsius < b.Celsius:
+				return 1
+			}
+			return 0
+		})
+
+		sb.WriteString(st.TableTop(28, 7, 10, 10))
+		sb.WriteString("\n")
+		sb.WriteString(st.TableRowColored(
+			st.Header(PadRight(T("Sensor"), 28)),
+			st.Header(PadRight(T("Kind"), 7)),
+			st.Header(PadLeft(T("Temp"), 10)),
+			st.Header(PadRight(T("Status"), 10)),
+		))
+		sb.WriteString("\n")
+		sb.WriteString(st.TableSeparator(28, 7, 10, 10))
+		sb.WriteString("\n")
+		for _, t := range temps {
+			temp := fmt.Sprintf("%.1f°C", t.Celsius)
+			var status string
+			switch t.Status {
+			case SensorCritical:
+				temp, status = st.Danger(temp), st.Danger(IconCross+" "+T("crit"))
+			case SensorHigh:
+				temp, status = st.Warning(temp), st.Warning(IconWarning+T("high"))
+			default:
+				temp, status = st.Success(temp), st.Success(IconCheck+" "+T("ok"))
+			}
+			name := t.Name
+			if len(name) > 28 {
+				name = name[:25] + "..."
+			}
+			sb.WriteString(st.TableRowColored(
+				PadRight(name, 28),
+				PadRight(t.Kind, 7),
+				PadLeft(temp, 10),
+				PadRight(status, 10),
+			))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(st.TableBottom(28, 7, 10, 10))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(st.BoldText(T("Fans:")))
+	sb.WriteString("\n")
+	if len(result.Fans) == 0 {
+		sb.WriteString(st.Muted("  " + T("No fan sensors found")))
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString(st.TableTop(28, 12))
+		sb.WriteString("\n")
+		sb.WriteString(st.TableRowColored(
+			st.Header(PadRight(T("Fan"), 28)),
+			st.Header(PadLeft(T("Speed"), 12)),
+		))
+		sb.WriteString("\n")
+		sb.WriteString(st.TableSeparator(28, 12))
+		sb.WriteString("\n")
+		for _, f := range result.Fans {
+			speed := fmt.Sprintf("%.0f RPM", f.RPM)
+			if f.RPM == 0 && f.Percent > 0 {
+				speed = fmt.Sprintf("%.0f%%", f.Percent)
+			}
+			if f.RPM == 0 && f.Percent == 0 {
+				speed = st.Muted(T("stopped"))
+			}
+			name := f.Name
+			if len(name) > 25 {
+				name = name[:22] + "..."
+			}
+			sb.WriteString(st.TableRowColored(
+				st.Info(PadRight(IconFan+" "+name, 28)),
+				PadLeft(speed, 12),
+			))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(st.TableBottom(28, 12))
+		sb.WriteString("\n")
+	}
+
+	if len(result.Anomalies) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText(IconWarning + " " + T("Thermal Anomalies:")))
+		sb.WriteString("\n")
+		for _, a := range result.Anomalies {
+			sb.WriteString(fmt.Sprintf("  %s %s\n", st.Warning(IconArrow), a))
+		}
+	}
+
+	return sb.String()
+}
+
+// FormatSensors formats sensor readings in the specified format
+func FormatSensors(result *SensorsResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatSensorsTableWith(st, result)
+	}, format)
+}
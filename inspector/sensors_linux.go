@@ -0,0 +1,16 @@
+//go:build linux
+
+package inspector
+
+import "context"
+
+// platformTemperatures has no fallback on Linux: gopsutil already reads
+// hwmon and the legacy thermal zones
+func platformTemperatures(ctx context.Context) ([]TemperatureSensor, *ProbeError) {
+	return nil, nil
+}
+
+// platformFans reads fan speeds from hwmon
+func platformFans() []FanSensor {
+	return readHwmonFans(environmentRoot)
+}
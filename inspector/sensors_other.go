@@ -0,0 +1,15 @@
+//go:build !windows && !linux
+
+package inspector
+
+import "context"
+
+// platformTemperatures has no fallback beyond gopsutil here
+func platformTemperatures(ctx context.Context) ([]TemperatureSensor, *ProbeError) {
+	return nil, nil
+}
+
+// platformFans has no fan source here
+func platformFans() []FanSensor {
+	return nil
+}
@@ -0,0 +1,111 @@
+package inspector
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSensorKind(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"coretemp_package_id_0", SensorCPU},
+		{"k10temp_tctl", SensorCPU},
+		{"amdgpu_edge", SensorGPU},
+		{"nvme_composite", SensorDisk},
+		{"BAT0", SensorBattery},
+		{"TC0P", SensorCPU},
+		{"acpitz", SensorOther},
+	}
+	for _, tt := range tests {
+		if got := sensorKind(tt.name); got != tt.want {
+			t.Errorf("sensorKind(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewTemperatureSensor(t *testing.T) {
+	tests := []struct {
+		name                    string
+		celsius, high, critical float64
+		want                    string
+	}{
+		{"below limits", 45, 80, 100, SensorOK},
+		{"at high", 80, 80, 100, SensorHigh},
+		{"at critical", 100, 80, 100, SensorCritical},
+		{"default limits", 90, 0, 0, SensorHigh},
+		{"placeholder limits", 96, 255, 255, SensorCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTemperatureSensor("cpu", tt.celsius, tt.high, tt.critical)
+			if s.Status != tt.want {
+				t.Errorf("Status = %q, want %q", s.Status, tt.want)
+			}
+			if s.HighCelsius != tt.high || s.CriticalCelsius != tt.critical {
+				t.Errorf("limits = %v/%v, want the reported %v/%v", s.HighCelsius, s.CriticalCelsius, tt.high, tt.critical)
+			}
+		})
+	}
+}
+
+func TestParseNvidiaSMISensors(t *testing.T) {
+	out := []byte("0, NVIDIA GeForce RTX 4090, 62, 35\n1, NVIDIA A100-SXM4-40GB, 97, [N/A]\n")
+	temps, fans := parseNvidiaSMISensors(out)
+	if len(temps) != 2 {
+		t.Fatalf("got %d temperatures, want 2", len(temps))
+	}
+	if temps[0].Name != "GPU 0 NVIDIA GeForce RTX 4090" || temps[0].Kind != SensorGPU || temps[0].Celsius != 62 {
+		t.Errorf("temps[0] = %+v", temps[0])
+	}
+	if temps[1].Status != SensorCritical {
+		t.Errorf("temps[1].Status = %q, want critical", temps[1].Status)
+	}
+	// The passively cooled A100 has no fan
+	if len(fans) != 1 || fans[0].Percent != 35 {
+		t.Errorf("fans = %+v, want one fan at 35%%", fans)
+	}
+}
+
+func TestReadHwmonFans(t *testing.T) {
+	root := fstest.MapFS{
+		"sys/class/hwmon/hwmon2/name":        {Data: []byte("nct6775\n")},
+		"sys/class/hwmon/hwmon2/fan1_input":  {Data: []byte("1180\n")},
+		"sys/class/hwmon/hwmon2/fan1_label":  {Data: []byte("CPU Fan\n")},
+		"sys/class/hwmon/hwmon2/fan1_max":    {Data: []byte("2400\n")},
+		"sys/class/hwmon/hwmon2/fan2_input":  {Data: []byte("0\n")},
+		"sys/class/hwmon/hwmon3/temp1_input": {Data: []byte("45000\n")},
+	}
+	fans := readHwmonFans(root)
+	if len(fans) != 2 {
+		t.Fatalf("got %d fans, want 2: %+v", len(fans), fans)
+	}
+	if fans[0].Name != "nct6775 CPU Fan" || fans[0].RPM != 1180 || fans[0].MaxRPM != 2400 {
+		t.Errorf("fans[0] = %+v", fans[0])
+	}
+	if fans[1].Name != "nct6775 fan2" || fans[1].RPM != 0 {
+		t.Errorf("fans[1] = %+v", fans[1])
+	}
+}
+
+func TestGetSensors(t *testing.T) {
+	defer SetCommandRunner(SetCommandRunner(NewFakeRunner()))
+
+	result, err := GetSensors(context.Background())
+	if err != nil {
+		t.Fatalf("GetSensors() error: %v", err)
+	}
+	if result.Temperatures == nil || result.Fans == nil {
+		t.Error("Temperatures and Fans should be non-nil")
+	}
+	for _, s := range result.Temperatures {
+		if s.Status == "" || s.Kind == "" {
+			t.Errorf("sensor %q missing status or kind", s.Name)
+		}
+	}
+	if FormatSensorsTable(result) == "" {
+		t.Error("FormatSensorsTable() returned empty string")
+	}
+}
@@ -0,0 +1,40 @@
+//go:build windows
+
+package inspector
+
+import (
+	"context"
+)
+
+// Win32_PerfFormattedData_Counters_ThermalZoneInformation represents the WMI
+// thermal zone performance counters, which unlike MSAcpi_ThermalZoneTemperature
+// are readable without elevation
+type Win32_PerfFormattedData_Counters_ThermalZoneInformation struct {
+	Name string
+	// HighPrecisionTemperature is in tenths of a kelvin
+	HighPrecisionTemperature uint32
+}
+
+// platformTemperatures reads ACPI thermal zones through performance counters
+func platformTemperatures(ctx context.Context) ([]TemperatureSensor, *ProbeError) {
+	var zones []Win32_PerfFormattedData_Counters_ThermalZoneInformation
+	query := "SELECT Name, HighPrecisionTemperature FROM Win32_PerfFormattedData_Counters_ThermalZoneInformation"
+	if err := wmiQuery(query, &zones, ""); err != nil {
+		return nil, classifyWMIError(ctx, `root\cimv2`, err)
+	}
+	var temps []TemperatureSensor
+	for _, z := range zones {
+		if z.HighPrecisionTemperature == 0 {
+			continue
+		}
+		celsius := float64(z.HighPrecisionTemperature)/10 - 273.15
+		temps = append(temps, newTemperatureSensor(z.Name, celsius, 0, 0))
+	}
+	return temps, nil
+}
+
+// platformFans returns nothing on Windows: fan speeds are only exposed
+// through vendor drivers (Win32_Fan carries no readings)
+func platformFans() []FanSensor {
+	return nil
+}
@@ -0,0 +1,176 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/agentplexus/posture/render"
+)
+
+// Streaming defaults and limits
+const (
+	DefaultStreamInterval     = 5 * time.Second
+	MinStreamInterval         = time.Second
+	DefaultStreamTopProcesses = 5
+)
+
+// MetricsSnapshot is one sample of CPU, memory, and the busiest processes
+type MetricsSnapshot struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Sequence  int                `json:"sequence"`
+	Timestamp time.Time          `json:"timestamp"`
+	CPU       *CPUUsageResult    `json:"cpu,omitempty"`
+	Memory    *MemoryResult      `json:"memory,omitempty"`
+	Processes *ProcessListResult `json:"processes,omitempty"`
+}
+
+// StreamOptions controls StreamMetrics
+type StreamOptions struct {
+	// Interval between snapshots (minimum MinStreamInterval)
+	Interval time.Duration
+	// Count stops the stream after this many snapshots; 0 streams until the
+	// context is cancelled
+	Count int
+	// TopProcesses is the number of processes by CPU usage to include in each
+	// snapshot; 0 omits the process list
+	TopProcesses int
+}
+
+// StreamMetrics collects a snapshot every opts.Interval and passes it to
+// emit until the context is cancelled, Count snapshots have been sent, or
+// emit returns an error. Cancellation ends the stream without an error.
+func StreamMetrics(ctx context.Context, opts StreamOptions, emit func(*MetricsSnapshot) error) error {
+	if opts.Interval == 0 {
+		opts.Interval = DefaultStreamInterval
+	}
+	if opts.Interval < MinStreamInterval {
+		return fmt.Errorf("stream interval must be at least %s", MinStreamInterval)
+	}
+	if opts.Count < 0 || opts.TopProcesses < 0 {
+		return fmt.Errorf("stream count and process limit must not be negative")
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for seq := 1; ; seq++ {
+		snap, err := collectMetricsSnapshot(ctx, seq, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if err := emit(snap); err != nil {
+			return err
+		}
+		if seq == opts.Count {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// collectMetricsSnapshot takes one snapshot. CPU usage is sampled over at
+// most DefaultCPUSampleInterval so that collection stays well inside the
+// stream interval.
+func collectMetricsSnapshot(ctx context.Context, seq int, opts StreamOptions) (*MetricsSnapshot, error) {
+	snap := &MetricsSnapshot{Sequence: seq, Timestamp: time.Now().UTC()}
+
+	cpu, err := GetCPUUsageWithInterval(ctx, min(opts.Interval, DefaultCPUSampleInterval))
+	if err != nil {
+		return nil, err
+	}
+	snap.CPU = cpu
+
+	memory, err := GetMemory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap.Memory = memory
+
+	if opts.TopProcesses > 0 {
+		procs, err := ListProcessesWithOptions(ctx, ProcessOptions{Limit: opts.TopProcesses})
+		if err != nil {
+			return nil, err
+		}
+		snap.Processes = procs
+	}
+	return snap, nil
+}
+
+// FormatMetricsSnapshotLine summarizes a snapshot on one line, for progress
+// messages and logs
+func FormatMetricsSnapshotLine(s *MetricsSnapshot) string {
+	var sb strings.Builder
+	sb.WriteString(s.Timestamp.Format(time.RFC3339))
+	if s.CPU != nil {
+		sb.WriteString(fmt.Sprintf(" cpu=%.1f%%", s.CPU.UsagePercent))
+		if s.CPU.Load != nil {
+			sb.WriteString(fmt.Sprintf(" load=%.2f", s.CPU.Load.Load1))
+		}
+	}
+	if s.Memory != nil {
+		sb.WriteString(fmt.Sprintf(" mem=%.1f%%", s.Memory.UsedPercent))
+	}
+	if s.Processes != nil && len(s.Processes.Processes) > 0 {
+		top := s.Processes.Processes[0]
+		sb.WriteString(fmt.Sprintf(" top=%s(%d) %.1f%%", top.Name, top.PID, top.CPUPercent))
+	}
+	return sb.String()
+}
+
+// FormatMetricsSnapshotTable formats a snapshot as a compact colored dashboard
+func FormatMetricsSnapshotTable(s *MetricsSnapshot) string {
+	return FormatMetricsSnapshotTableWith(CurrentStyler(), s)
+}
+
+// FormatMetricsSnapshotTableWith is like FormatMetricsSnapshotTable but
+// styles the table with st
+func FormatMetricsSnapshotTableWith(st Styler, s *MetricsSnapshot) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(fmt.Sprintf("%s Live Metrics #%d", IconCPU, s.Sequence)))
+	sb.WriteString(st.Muted("  " + s.Timestamp.Local().Format("15:04:05")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	if s.CPU != nil {
+		sb.WriteString(st.BoldText(PadRight("CPU:", 8)))
+		sb.WriteString(st.ProgressBar(s.CPU.UsagePercent, 30))
+		sb.WriteString(" ")
+		sb.WriteString(st.Styled(render.UsageStyle(s.CPU.UsagePercent), st.BoldText(fmt.Sprintf("%5.1f%%", s.CPU.UsagePercent))))
+		if s.CPU.Load != nil {
+			sb.WriteString(st.Muted("  load "))
+			sb.WriteString(formatLoad(st, s.CPU.Load.Load1, s.CPU.LogicalCores))
+		}
+		sb.WriteString("\n")
+	}
+	if s.Memory != nil {
+		sb.WriteString(st.BoldText(PadRight("Memory:", 8)))
+		sb.WriteString(st.ProgressBar(s.Memory.UsedPercent, 30))
+		sb.WriteString(" ")
+		sb.WriteString(st.Styled(render.UsageStyle(s.Memory.UsedPercent), st.BoldText(fmt.Sprintf("%5.1f%%", s.Memory.UsedPercent))))
+		sb.WriteString(st.Muted(fmt.Sprintf("  %s of %s", s.Memory.UsedHuman, s.Memory.TotalHuman)))
+		sb.WriteString("\n")
+	}
+	if s.Processes != nil {
+		sb.WriteString(FormatProcessListTableWith(st, s.Processes))
+	}
+	return sb.String()
+}
+
+// FormatMetricsSnapshot formats a snapshot in the specified format
+func FormatMetricsSnapshot(s *MetricsSnapshot, format string) string {
+	return FormatOutputWith(s, func(st Styler) string {
+		return FormatMetricsSnapshotTableWith(st, s)
+	}, format)
+}
@@ -0,0 +1,89 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamMetrics_Count(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var snaps []*MetricsSnapshot
+	err := StreamMetrics(ctx, StreamOptions{Interval: time.Second, Count: 2, TopProcesses: 3}, func(s *MetricsSnapshot) error {
+		snaps = append(snaps, s)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamMetrics failed: %v", err)
+	}
+	if len(snaps) != 2 {
+		t.Fatalf("got %d snapshots, want 2", len(snaps))
+	}
+	for i, s := range snaps {
+		if s.Sequence != i+1 {
+			t.Errorf("snapshot %d has Sequence %d", i, s.Sequence)
+		}
+		if s.CPU == nil || s.Memory == nil || s.Processes == nil {
+			t.Errorf("snapshot %d is missing a section: %+v", i, s)
+		}
+		if s.Processes != nil && len(s.Processes.Processes) > 3 {
+			t.Errorf("snapshot %d has %d processes, want at most 3", i, len(s.Processes.Processes))
+		}
+	}
+	if !snaps[1].Timestamp.After(snaps[0].Timestamp) {
+		t.Error("snapshot timestamps should increase")
+	}
+}
+
+func TestStreamMetrics_StopsOnCancelAndEmitError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	n := 0
+	err := StreamMetrics(ctx, StreamOptions{Interval: time.Second}, func(*MetricsSnapshot) error {
+		n++
+		cancel()
+		return nil
+	})
+	if err != nil || n != 1 {
+		t.Errorf("cancelled stream: err = %v after %d snapshots, want nil after 1", err, n)
+	}
+
+	stop := errors.New("client went away")
+	err = StreamMetrics(context.Background(), StreamOptions{Interval: time.Second}, func(*MetricsSnapshot) error {
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("err = %v, want the emit error", err)
+	}
+}
+
+func TestStreamMetrics_InvalidOptions(t *testing.T) {
+	for _, opts := range []StreamOptions{
+		{Interval: 100 * time.Millisecond},
+		{Interval: time.Second, Count: -1},
+		{Interval: time.Second, TopProcesses: -1},
+	} {
+		if err := StreamMetrics(context.Background(), opts, func(*MetricsSnapshot) error { return nil }); err == nil {
+			t.Errorf("StreamMetrics(%+v) should fail", opts)
+		}
+	}
+}
+
+func TestFormatMetricsSnapshotLine(t *testing.T) {
+	s := &MetricsSnapshot{
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		CPU:       &CPUUsageResult{UsagePercent: 12.34, Load: &LoadAverage{Load1: 0.5}},
+		Memory:    &MemoryResult{UsedPercent: 45.6},
+		Processes: &ProcessListResult{Processes: []ProcessInfo{{PID: 42, Name: "chrome", CPUPercent: 30}}},
+	}
+	want := "2025-01-02T03:04:05Z cpu=12.3% load=0.50 mem=45.6% top=chrome(42) 30.0%"
+	if got := FormatMetricsSnapshotLine(s); got != want {
+		t.Errorf("FormatMetricsSnapshotLine() = %q, want %q", got, want)
+	}
+	if !strings.Contains(FormatMetricsSnapshotTable(s), "Live Metrics #0") {
+		t.Error("table should include the snapshot header")
+	}
+}
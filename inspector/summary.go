@@ -1,103 +1,481 @@
 package inspector
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"runtime"
+	"slices"
 	"strings"
+	"time"
+
+	"github.com/agentplexus/posture/render"
 )
 
 // SecuritySummary contains a unified security posture overview
 type SecuritySummary struct {
-	Platform        string       `json:"platform"`
-	OverallScore    int          `json:"overall_score"`
-	OverallStatus   string       `json:"overall_status"`
-	TPM             *TPMSummary  `json:"tpm"`
-	SecureBoot      *BootSummary `json:"secure_boot"`
-	Encryption      *EncSummary  `json:"encryption"`
-	Biometrics      *BioSummary  `json:"biometrics"`
-	Recommendations []string     `json:"recommendations,omitempty"`
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Hostname      string `json:"hostname,omitempty"`
+	Platform      string `json:"platform"`
+	OverallScore  int    `json:"overall_score"`
+	OverallStatus string `json:"overall_status"`
+	// Domains roll the checks up into posture domains, each with its own
+	// score; they are not rows of CSV output
+	Domains []DomainSummary `json:"domains,omitempty" tabular:"-"`
+	// CheckResults maps each check that ran and counted to pass or fail,
+	// and each check that ran past its timeout to timeout
+	CheckResults map[string]string `json:"check_results,omitempty" tabular:"-"`
+	// DeltaFromBaseline compares the summary with the stored baseline (see
+	// BaselinePath); it is unset if no baseline was taken
+	DeltaFromBaseline *BaselineDelta    `json:"delta_from_baseline,omitempty" tabular:"-"`
+	TPM               *TPMSummary       `json:"tpm"`
+	SecureBoot        *BootSummary      `json:"secure_boot"`
+	BootOrder         *BootOrderSummary `json:"boot_order,omitempty"`
+	Encryption        *EncSummary       `json:"encryption"`
+	Biometrics        *BioSummary       `json:"biometrics"`
+	// Defender is set on Windows
+	Defender *DefenderSummary `json:"defender,omitempty"`
+	// UAC is set on Windows
+	UAC *UACSummary `json:"uac,omitempty"`
+	// LegacyProtocols is set on Windows
+	LegacyProtocols *LegacyProtocolsSummary `json:"legacy_protocols,omitempty"`
+	Browsers        *BrowserSummary         `json:"browsers,omitempty"`
+	Docker          *DockerSummary          `json:"docker,omitempty"`
+	// Kubelet is set on Linux
+	Kubelet  *KubeletSummary  `json:"kubelet,omitempty"`
+	Uptime   *UptimeSummary   `json:"uptime,omitempty"`
+	Firmware *FirmwareSummary `json:"firmware,omitempty"`
+	// ManagementEngine is set on Linux and Windows
+	ManagementEngine *ManagementEngineSummary `json:"management_engine,omitempty"`
+	// USB is set when OMNITRUST_USB_STORAGE_POLICY is block
+	USB *USBSummary `json:"usb,omitempty"`
+	// Passkeys is set on macOS and Windows
+	Passkeys *PasskeySummary `json:"passkeys,omitempty"`
+	// Keychain is informational and never scored
+	Keychain *KeychainSummary `json:"keychain,omitempty"`
+	// Findings are the problems found, with remediations; they are not rows
+	// of CSV output, which has one row per summary
+	Findings []Finding `json:"findings,omitempty" tabular:"-"`
+	// Waived are the findings accepted by an active waiver (see
+	// WaiversPath), left out of Findings
+	Waived []WaivedFinding `json:"waived,omitempty" tabular:"-"`
+	// WaivedChecks lists the failed checks scored as passed because all of
+	// their findings are waived, when OMNITRUST_WAIVERS_SCORE is true
+	WaivedChecks []string `json:"waived_checks,omitempty"`
+	// MandatoryFailures lists mandatory checks that did not pass
+	MandatoryFailures []string `json:"mandatory_failures,omitempty"`
+	// RequiresElevation lists probes that returned degraded results because
+	// the process lacked privileges (e.g. bputil, fdesetup, WMI security namespaces)
+	RequiresElevation []string `json:"requires_elevation,omitempty"`
+	// DisabledChecks lists checks skipped via OMNITRUST_DISABLE_CHECKS / OMNITRUST_ONLY_CHECKS
+	DisabledChecks []string `json:"disabled_checks,omitempty"`
+	// UnsupportedChecks lists checks of the platform this machine cannot
+	// run (e.g. no Secure Boot on a BIOS machine); like disabled checks,
+	// they are left out of the score
+	UnsupportedChecks []string `json:"unsupported_checks,omitempty"`
+	// Scan reports the resource cost of producing this summary
+	Scan *ScanStats `json:"scan,omitempty"`
+	// Environment is set when running in a container
+	Environment *RuntimeEnvironment `json:"environment,omitempty"`
+	// Cloud identifies the cloud instance, when running on AWS, Azure, or GCP
+	Cloud *CloudContext `json:"cloud,omitempty"`
+	// NotApplicable maps host-only checks skipped in a container to
+	// not_applicable_in_container; they are excluded from the score
+	NotApplicable map[string]string `json:"not_applicable,omitempty"`
+}
+
+// DomainSummary rolls up the checks of one posture domain (see AllDomains)
+type DomainSummary struct {
+	ID string `json:"id"`
+	// Score is scored like the overall score, over the domain's checks
+	// only; it is NoScore when the domain is not scored
+	Score int `json:"score"`
+	// Status is excellent, good, fair, needs_improvement, or critical like
+	// the overall status, or not_scored when none of its checks counted
+	Status string   `json:"status"`
+	Checks []string `json:"checks"`
+	// Failed lists the domain's checks that ran and did not pass
+	Failed []string `json:"failed,omitempty"`
+}
+
+// StatusNotScored marks a summary or domain none of whose checks ran and
+// counted toward the score
+const StatusNotScored = "not_scored"
+
+// NoScore is the score of a summary or domain with nothing to score, when
+// every check is informational, not applicable, disabled, or unsupported.
+// Its status is StatusNotScored, or why the checks do not apply here.
+const NoScore = -1
+
+// FormatScore renders a score as "72/100", or N/A for NoScore
+func FormatScore(score int) string {
+	if score == NoScore {
+		return T("N/A")
+	}
+	return fmt.Sprintf("%d/100", score)
 }
 
 // TPMSummary contains TPM summary info
 type TPMSummary struct {
-	Present bool   `json:"present"`
-	Enabled bool   `json:"enabled"`
-	Type    string `json:"type"`
+	Present     bool        `json:"present"`
+	Enabled     bool        `json:"enabled"`
+	Type        string      `json:"type"`
+	Kind        string      `json:"tpm_kind,omitempty"`
+	Error       *ProbeError `json:"error,omitempty"`
+	Enforcement Enforcement `json:"enforcement"`
 }
 
 // BootSummary contains Secure Boot summary info
 type BootSummary struct {
-	Enabled bool   `json:"enabled"`
-	Mode    string `json:"mode"`
+	Enabled     bool        `json:"enabled"`
+	Mode        string      `json:"mode"`
+	Error       *ProbeError `json:"error,omitempty"`
+	Enforcement Enforcement `json:"enforcement"`
+}
+
+// BootOrderSummary contains boot order summary info
+type BootOrderSummary struct {
+	Compliant          bool `json:"compliant"`
+	ExternalBeforeDisk bool `json:"external_before_disk"`
+	NetworkBeforeDisk  bool `json:"network_before_disk"`
+	// ExternalBootAllowed is set on Macs where it can be determined
+	ExternalBootAllowed *bool       `json:"external_boot_allowed,omitempty"`
+	FirmwarePassword    *bool       `json:"firmware_password,omitempty"`
+	Error               *ProbeError `json:"error,omitempty"`
+	Enforcement         Enforcement `json:"enforcement"`
 }
 
 // EncSummary contains encryption summary info
 type EncSummary struct {
-	Enabled bool   `json:"enabled"`
-	Type    string `json:"type"`
-	Status  string `json:"status"`
+	Enabled     bool        `json:"enabled"`
+	Type        string      `json:"type"`
+	Status      string      `json:"status"`
+	Error       *ProbeError `json:"error,omitempty"`
+	Enforcement Enforcement `json:"enforcement"`
 }
 
 // BioSummary contains biometrics summary info
 type BioSummary struct {
-	Available  bool   `json:"available"`
-	Configured bool   `json:"configured"`
-	Type       string `json:"type"`
+	Available   bool        `json:"available"`
+	Configured  bool        `json:"configured"`
+	Type        string      `json:"type"`
+	Enforcement Enforcement `json:"enforcement"`
+}
+
+// DefenderSummary contains Microsoft Defender summary info
+type DefenderSummary struct {
+	Protected          bool        `json:"protected"`
+	RealTimeProtection bool        `json:"real_time_protection"`
+	TamperProtection   bool        `json:"tamper_protection"`
+	SignatureAgeDays   int         `json:"signature_age_days"`
+	RunningMode        string      `json:"running_mode,omitempty"`
+	Error              *ProbeError `json:"error,omitempty"`
+	Enforcement        Enforcement `json:"enforcement"`
+}
+
+// UACSummary contains User Account Control and SmartScreen summary info
+type UACSummary struct {
+	Protected           bool        `json:"protected"`
+	Enabled             bool        `json:"enabled"`
+	AdminPromptBehavior string      `json:"admin_prompt_behavior"`
+	SmartScreenApps     string      `json:"smartscreen_apps"`
+	SmartScreenEdge     string      `json:"smartscreen_edge"`
+	Error               *ProbeError `json:"error,omitempty"`
+	Enforcement         Enforcement `json:"enforcement"`
+}
+
+// LegacyProtocolsSummary contains legacy protocol exposure summary info
+type LegacyProtocolsSummary struct {
+	Hardened      bool        `json:"hardened"`
+	SMBv1         bool        `json:"smb1"`
+	NTLMv1Allowed bool        `json:"ntlmv1_allowed"`
+	LLMNR         bool        `json:"llmnr"`
+	NetBIOS       bool        `json:"netbios"`
+	Error         *ProbeError `json:"error,omitempty"`
+	Enforcement   Enforcement `json:"enforcement"`
+}
+
+// BrowserSummary contains browser security summary info
+type BrowserSummary struct {
+	Secure bool `json:"secure"`
+	// Browsers counts the browser profiles found
+	Browsers        int         `json:"browsers"`
+	Outdated        int         `json:"outdated"`
+	SafeBrowsingOff int         `json:"safe_browsing_off"`
+	Enforcement     Enforcement `json:"enforcement"`
+}
+
+// DockerSummary contains Docker daemon security summary info
+type DockerSummary struct {
+	Secure    bool `json:"secure"`
+	Installed bool `json:"installed"`
+	Running   bool `json:"running"`
+	// Isolated is true if the daemon is rootless, remaps user namespaces,
+	// or runs in Docker Desktop's VM
+	Isolated bool `json:"isolated"`
+	// TCPExposed is true if the daemon accepts connections over TCP without TLS
+	TCPExposed           bool        `json:"tcp_exposed"`
+	InsecureRegistries   int         `json:"insecure_registries"`
+	PrivilegedContainers int         `json:"privileged_containers"`
+	Error                *ProbeError `json:"error,omitempty"`
+	Enforcement          Enforcement `json:"enforcement"`
+}
+
+// KubeletSummary contains Kubernetes node security summary info
+type KubeletSummary struct {
+	Secure   bool `json:"secure"`
+	Detected bool `json:"detected"`
+	// FailedControls counts the CIS Kubernetes Benchmark recommendations
+	// that did not pass
+	FailedControls int `json:"failed_controls"`
+	// RuntimeSocketExposed is true if any local user can connect to the
+	// container runtime socket
+	RuntimeSocketExposed bool        `json:"runtime_socket_exposed"`
+	Error                *ProbeError `json:"error,omitempty"`
+	Enforcement          Enforcement `json:"enforcement"`
+}
+
+// UptimeSummary contains uptime and pending reboot summary info
+type UptimeSummary struct {
+	UptimeDays    int  `json:"uptime_days"`
+	RebootPending bool `json:"reboot_pending"`
+	// PendingDays is how long the reboot has been pending, where known
+	PendingDays int         `json:"pending_days,omitempty"`
+	Error       *ProbeError `json:"error,omitempty"`
+	Enforcement Enforcement `json:"enforcement"`
+}
+
+// FirmwareSummary contains firmware update summary info
+type FirmwareSummary struct {
+	Current bool   `json:"current"`
+	Version string `json:"version,omitempty"`
+	// UpdatesAvailable counts the pending firmware updates (Linux)
+	UpdatesAvailable int `json:"updates_available"`
+	// FailedUpdates counts firmware resources whose last capsule update
+	// failed (Windows)
+	FailedUpdates int `json:"failed_updates"`
+	// HostSecurityID is fwupd's HSI rating, e.g. "HSI:2" (Linux)
+	HostSecurityID string      `json:"host_security_id,omitempty"`
+	Error          *ProbeError `json:"error,omitempty"`
+	Enforcement    Enforcement `json:"enforcement"`
+}
+
+// ManagementEngineSummary contains Intel ME and AMD PSP summary info
+type ManagementEngineSummary struct {
+	Detected        bool        `json:"detected"`
+	Vendor          string      `json:"vendor,omitempty"`
+	FirmwareVersion string      `json:"firmware_version,omitempty"`
+	AMTExposed      bool        `json:"amt_exposed"`
+	Compliant       bool        `json:"compliant"`
+	Error           *ProbeError `json:"error,omitempty"`
+	Enforcement     Enforcement `json:"enforcement"`
+}
+
+// USBSummary contains USB storage policy summary info
+type USBSummary struct {
+	StorageRestricted bool `json:"storage_restricted"`
+	// StorageDevices counts the connected mass storage devices
+	StorageDevices int         `json:"storage_devices"`
+	Error          *ProbeError `json:"error,omitempty"`
+	Enforcement    Enforcement `json:"enforcement"`
+}
+
+// PasskeySummary contains passkey authenticator summary info
+type PasskeySummary struct {
+	Authenticator string      `json:"authenticator"`
+	Available     bool        `json:"available"`
+	Configured    bool        `json:"configured"`
+	Ready         bool        `json:"ready"`
+	Error         *ProbeError `json:"error,omitempty"`
+	Enforcement   Enforcement `json:"enforcement"`
+}
+
+// KeychainSummary contains credential store and password manager info
+type KeychainSummary struct {
+	Store string `json:"store,omitempty"`
+	// PasswordManagers names the password managers found, apps and
+	// extensions alike
+	PasswordManagers []string    `json:"password_managers"`
+	Error            *ProbeError `json:"error,omitempty"`
 }
 
 // GetSecuritySummary returns a unified security posture overview
 func GetSecuritySummary() (*SecuritySummary, error) {
+	return GetSecuritySummaryContext(context.Background())
+}
+
+// GetSecuritySummaryContext is like GetSecuritySummary but runs the probes
+// with ctx's deadline, command runner, and logger
+func GetSecuritySummaryContext(ctx context.Context) (*SecuritySummary, error) {
+	return GetSecuritySummaryWithOptionsContext(ctx, SummaryOptions{})
+}
+
+// GetSecuritySummaryWithOptions returns the security posture overview,
+// running the checks in parallel and reporting their progress as opts asks
+func GetSecuritySummaryWithOptions(opts SummaryOptions) (*SecuritySummary, error) {
+	return GetSecuritySummaryWithOptionsContext(context.Background(), opts)
+}
+
+// GetSecuritySummaryWithOptionsContext is like GetSecuritySummaryWithOptions
+// but runs the probes with ctx's deadline, command runner, and logger
+func GetSecuritySummaryWithOptionsContext(ctx context.Context, opts SummaryOptions) (*SecuritySummary, error) {
+	// A fixture summary is served as is; without one the summary is built
+	// from the fixtures of its checks
+	if summary, ok, err := loadFixture[SecuritySummary](ctx, "summary"); ok && !errors.Is(err, ErrUnsupportedPlatform) {
+		return summary, err
+	}
 	summary := &SecuritySummary{
 		Platform: runtime.GOOS,
 	}
+	if hostname, err := os.Hostname(); err == nil {
+		summary.Hostname = hostname
+	}
+
+	// Host-only checks are meaningless inside a container: skip them rather
+	// than report missing hardware as a failure. Under WSL they still run to
+	// show the Linux guest's view, but are excluded from the score.
+	endReads := provenanceCheck("environment")
+	env := GetRuntimeEnvironmentContext(ctx)
+	endReads()
+	naStatus := env.notApplicableStatus()
+	if naStatus != "" {
+		summary.Environment = env
+	}
+	applicable := func(id string) bool {
+		if naStatus != "" && slices.Contains(env.HostOnlyChecks, id) {
+			if summary.NotApplicable == nil {
+				summary.NotApplicable = make(map[string]string)
+			}
+			summary.NotApplicable[id] = naStatus
+			return naStatus != StatusNotApplicableInContainer
+		}
+		return true
+	}
+	// runs reports whether a check runs in this scan
+	runs := func(id string) bool {
+		return summaryCheckSupported(id, env) && CheckEnabled(id) && applicable(id)
+	}
+	// report adds a finding unless its check does not apply here. A failed
+	// mandatory check is always critical.
+	var findings []Finding
+	report := func(f Finding) {
+		if summary.NotApplicable[f.Check] != "" {
+			return
+		}
+		if CheckEnforcement(f.Check) == EnforcementMandatory {
+			f.Severity = SeverityCritical
+		}
+		findings = append(findings, f)
+	}
 
-	var score int
-	var recommendations []string
+	ctx, rec := newScanRecorder(ctx)
+	rec.progress = opts.Progress
+	// The Windows probes share one WMI connection for the whole scan
+	defer openWMISession()()
+	// With provenance on, checks run one at a time so that each read is
+	// attributed to the check that made it
+	if opts.Parallel && !ProvenanceEnabled() {
+		rec.startChecks(ctx, checkProbes(), runs)
+	}
+	// passed records the outcome of every check that ran
+	passed := make(map[string]bool)
 
 	// Get TPM status
-	if IsTPMSupported() {
-		tpmResult, err := GetTPMStatus()
+	if runs(CheckTPM) {
+		tpmResult, err := runCheck(ctx, rec, CheckTPM, GetTPMStatusContext)
 		if err == nil {
+			passed[CheckTPM] = false
 			summary.TPM = &TPMSummary{
-				Present: tpmResult.Present,
-				Enabled: tpmResult.Enabled,
-				Type:    tpmResult.Type,
+				Present:     tpmResult.Present,
+				Enabled:     tpmResult.Enabled,
+				Type:        tpmResult.Type,
+				Kind:        tpmResult.Kind,
+				Error:       tpmResult.Error,
+				Enforcement: CheckEnforcement(CheckTPM),
 			}
 			if tpmResult.Present && tpmResult.Enabled {
-				score += 25
+				passed[CheckTPM] = true
+			} else if tpmResult.Error != nil {
+				report(unverifiedFinding("tpm_unverified", CheckTPM, "TPM", tpmResult.Error))
 			} else if !tpmResult.Present {
-				recommendations = append(recommendations, "Hardware security module (TPM/Secure Enclave) not detected")
+				report(Finding{
+					ID:          "tpm_missing",
+					Title:       T("Hardware security module (TPM/Secure Enclave) not detected"),
+					Severity:    SeverityHigh,
+					Check:       CheckTPM,
+					Remediation: T("Enable the TPM in the firmware settings, or use hardware that has one"),
+				})
 			}
 		}
 	}
 
 	// Get Secure Boot status
-	if IsSecureBootSupported() {
-		bootResult, err := GetSecureBootStatus()
+	if runs(CheckSecureBoot) {
+		bootResult, err := runCheck(ctx, rec, CheckSecureBoot, GetSecureBootStatusContext)
 		if err == nil {
+			passed[CheckSecureBoot] = false
 			summary.SecureBoot = &BootSummary{
-				Enabled: bootResult.Enabled,
-				Mode:    bootResult.Mode,
+				Enabled:     bootResult.Enabled,
+				Mode:        bootResult.Mode,
+				Error:       bootResult.Error,
+				Enforcement: CheckEnforcement(CheckSecureBoot),
 			}
 			if bootResult.Enabled {
-				score += 25
+				passed[CheckSecureBoot] = true
+			} else if bootResult.Error != nil {
+				report(unverifiedFinding("secure_boot_unverified", CheckSecureBoot, "Secure Boot", bootResult.Error))
 			} else {
-				recommendations = append(recommendations, "Enable Secure Boot for enhanced boot security")
+				report(Finding{
+					ID:                 "secure_boot_disabled",
+					Title:              T("Secure Boot is disabled"),
+					Severity:           SeverityHigh,
+					Check:              CheckSecureBoot,
+					Remediation:        T("Enable Secure Boot for enhanced boot security"),
+					RemediationCommand: remediationCommand("secure_boot_disabled"),
+					Disruptive:         remediationDisruptive("secure_boot_disabled"),
+				})
+			}
+		}
+	}
+
+	// Get the boot order
+	if runs(CheckBootOrder) {
+		order, err := runCheck(ctx, rec, CheckBootOrder, GetBootOrderContext)
+		if err == nil {
+			passed[CheckBootOrder] = order.Compliant
+			summary.BootOrder = &BootOrderSummary{
+				Compliant:           order.Compliant,
+				ExternalBeforeDisk:  order.ExternalBeforeDisk,
+				NetworkBeforeDisk:   order.NetworkBeforeDisk,
+				ExternalBootAllowed: order.ExternalBootAllowed,
+				FirmwarePassword:    order.FirmwarePassword,
+				Error:               order.Error,
+				Enforcement:         CheckEnforcement(CheckBootOrder),
+			}
+			for _, f := range bootOrderFindings(order) {
+				report(f)
 			}
 		}
 	}
 
 	// Get Encryption status
-	if IsEncryptionSupported() {
-		encResult, err := GetEncryptionStatus()
+	if runs(CheckEncryption) {
+		encResult, err := runCheck(ctx, rec, CheckEncryption, GetEncryptionStatusContext)
 		if err == nil {
+			passed[CheckEncryption] = false
 			summary.Encryption = &EncSummary{
-				Enabled: encResult.Enabled,
-				Type:    encResult.Type,
-				Status:  encResult.Status,
+				Enabled:     encResult.Enabled,
+				Type:        encResult.Type,
+				Status:      encResult.Status,
+				Error:       encResult.Error,
+				Enforcement: CheckEnforcement(CheckEncryption),
 			}
 			if encResult.Enabled {
-				score += 25
+				passed[CheckEncryption] = true
+			} else if encResult.Error != nil {
+				report(unverifiedFinding("encryption_unverified", CheckEncryption, "disk encryption", encResult.Error))
 			} else {
 				encType := "disk encryption"
 				switch runtime.GOOS {
@@ -108,45 +486,366 @@ func GetSecuritySummary() (*SecuritySummary, error) {
 				case "linux":
 					encType = "LUKS"
 				}
-				recommendations = append(recommendations, fmt.Sprintf("Enable %s to protect data at rest", encType))
+				report(Finding{
+					ID:                 "encryption_disabled",
+					Title:              T("Disk encryption is disabled"),
+					Severity:           SeverityCritical,
+					Check:              CheckEncryption,
+					Remediation:        T("Enable %s to protect data at rest", encType),
+					RemediationCommand: remediationCommand("encryption_disabled"),
+				})
 			}
 		}
 	}
 
 	// Get Biometrics status
-	if IsBiometricsSupported() {
-		bioResult, err := GetBiometricCapabilities()
+	if runs(CheckBiometrics) {
+		bioResult, err := runCheck(ctx, rec, CheckBiometrics, GetBiometricCapabilitiesContext)
 		if err == nil {
+			passed[CheckBiometrics] = false
 			available := bioResult.TouchIDAvailable || bioResult.FaceIDAvailable
 			configured := bioResult.TouchIDEnrolled || bioResult.FaceIDEnrolled
 			summary.Biometrics = &BioSummary{
-				Available:  available,
-				Configured: configured,
-				Type:       bioResult.BiometryType,
+				Available:   available,
+				Configured:  configured,
+				Type:        bioResult.BiometryType,
+				Enforcement: CheckEnforcement(CheckBiometrics),
 			}
 			if configured {
-				score += 25
+				passed[CheckBiometrics] = true
 			} else if available {
-				recommendations = append(recommendations, "Configure biometric authentication for enhanced security")
+				report(Finding{
+					ID:                 "biometrics_not_configured",
+					Title:              T("Biometric authentication is not configured"),
+					Severity:           SeverityLow,
+					Check:              CheckBiometrics,
+					Remediation:        T("Configure biometric authentication for enhanced security"),
+					RemediationCommand: remediationCommand("biometrics_not_configured"),
+				})
+			}
+		}
+	}
+
+	// Get Microsoft Defender status
+	if runs(CheckDefender) {
+		defResult, err := runCheck(ctx, rec, CheckDefender, GetDefenderStatusContext)
+		if err == nil {
+			passed[CheckDefender] = defResult.Protected
+			summary.Defender = &DefenderSummary{
+				Protected:          defResult.Protected,
+				RealTimeProtection: defResult.RealTimeProtection,
+				TamperProtection:   defResult.TamperProtection,
+				SignatureAgeDays:   defResult.SignatureAgeDays,
+				RunningMode:        defResult.RunningMode,
+				Error:              defResult.Error,
+				Enforcement:        CheckEnforcement(CheckDefender),
+			}
+			for _, f := range defenderFindings(defResult, time.Now()) {
+				report(f)
+			}
+		}
+	}
+
+	// Get UAC and SmartScreen settings
+	if runs(CheckUAC) {
+		uacResult, err := runCheck(ctx, rec, CheckUAC, GetUACStatusContext)
+		if err == nil {
+			passed[CheckUAC] = uacResult.Protected
+			summary.UAC = &UACSummary{
+				Protected:           uacResult.Protected,
+				Enabled:             uacResult.Enabled,
+				AdminPromptBehavior: uacResult.AdminPromptBehavior,
+				SmartScreenApps:     uacResult.SmartScreenApps,
+				SmartScreenEdge:     uacResult.SmartScreenEdge,
+				Error:               uacResult.Error,
+				Enforcement:         CheckEnforcement(CheckUAC),
+			}
+			for _, f := range uacFindings(uacResult) {
+				report(f)
+			}
+		}
+	}
+
+	// Get legacy protocol exposure
+	if runs(CheckLegacyProtocols) {
+		legacy, err := runCheck(ctx, rec, CheckLegacyProtocols, GetLegacyProtocolsContext)
+		if err == nil {
+			passed[CheckLegacyProtocols] = legacy.Hardened
+			summary.LegacyProtocols = &LegacyProtocolsSummary{
+				Hardened:      legacy.Hardened,
+				SMBv1:         legacy.SMBv1Server || legacy.SMBv1Client,
+				NTLMv1Allowed: legacy.NTLMv1Allowed,
+				LLMNR:         legacy.LLMNR,
+				NetBIOS:       legacy.NetBIOS,
+				Error:         legacy.Error,
+				Enforcement:   CheckEnforcement(CheckLegacyProtocols),
+			}
+			for _, f := range legacyProtocolFindings(legacy) {
+				report(f)
 			}
 		}
 	}
 
+	// Get browser security settings
+	if runs(CheckBrowser) {
+		browsers, err := runCheck(ctx, rec, CheckBrowser, GetBrowserSecurityContext)
+		if err == nil {
+			passed[CheckBrowser] = browsers.Secure
+			summary.Browsers = &BrowserSummary{
+				Secure:      browsers.Secure,
+				Browsers:    len(browsers.Browsers),
+				Enforcement: CheckEnforcement(CheckBrowser),
+			}
+			for _, b := range browsers.Browsers {
+				if b.Outdated {
+					summary.Browsers.Outdated++
+				}
+				if b.SafeBrowsingMode == SafeBrowsingOff {
+					summary.Browsers.SafeBrowsingOff++
+				}
+			}
+			for _, f := range browserFindings(browsers) {
+				report(f)
+			}
+		}
+	}
+
+	// Get Docker daemon security
+	if runs(CheckDocker) {
+		docker, err := runCheck(ctx, rec, CheckDocker, GetContainerSecurityContext)
+		if err == nil {
+			passed[CheckDocker] = docker.Secure
+			summary.Docker = &DockerSummary{
+				Secure:               docker.Secure,
+				Installed:            docker.Installed,
+				Running:              docker.Running,
+				Isolated:             dockerIsolated(docker),
+				TCPExposed:           slices.ContainsFunc(docker.TCPEndpoints, func(e DockerEndpoint) bool { return !e.TLSVerify }),
+				InsecureRegistries:   len(docker.InsecureRegistries),
+				PrivilegedContainers: len(docker.PrivilegedContainers),
+				Error:                docker.Error,
+				Enforcement:          CheckEnforcement(CheckDocker),
+			}
+			for _, f := range containerSecurityFindings(docker) {
+				report(f)
+			}
+		}
+	}
+
+	// Get Kubernetes node security
+	if runs(CheckKubelet) {
+		kubelet, err := runCheck(ctx, rec, CheckKubelet, GetKubeletSecurityContext)
+		if err == nil {
+			passed[CheckKubelet] = kubelet.Secure
+			summary.Kubelet = &KubeletSummary{
+				Secure:               kubelet.Secure,
+				Detected:             kubelet.Detected,
+				FailedControls:       kubeletFailedControls(kubelet),
+				RuntimeSocketExposed: slices.ContainsFunc(kubelet.RuntimeSockets, func(s RuntimeSocket) bool { return s.WorldAccessible }),
+				Error:                kubelet.Error,
+				Enforcement:          CheckEnforcement(CheckKubelet),
+			}
+			for _, f := range kubeletFindings(kubelet) {
+				report(f)
+			}
+		}
+	}
+
+	// Get uptime and pending reboots
+	if runs(CheckUptime) {
+		uptime, err := runCheck(ctx, rec, CheckUptime, GetUptimeContext)
+		if err == nil {
+			passed[CheckUptime] = !uptime.RebootPending
+			summary.Uptime = &UptimeSummary{
+				UptimeDays:    int(uptime.UptimeSeconds / 86400),
+				RebootPending: uptime.RebootPending,
+				PendingDays:   uptime.PendingDays,
+				Error:         uptime.Error,
+				Enforcement:   CheckEnforcement(CheckUptime),
+			}
+			for _, f := range uptimeFindings(uptime) {
+				report(f)
+			}
+		}
+	}
+
+	// Get firmware update status
+	if runs(CheckFirmware) {
+		fw, err := runCheck(ctx, rec, CheckFirmware, GetFirmwareStatusContext)
+		if err == nil {
+			passed[CheckFirmware] = fw.Current
+			summary.Firmware = &FirmwareSummary{
+				Current:          fw.Current,
+				Version:          fw.Version,
+				UpdatesAvailable: len(fw.Updates),
+				FailedUpdates:    len(slices.DeleteFunc(slices.Clone(fw.Capsules), func(c CapsuleUpdate) bool { return !c.Failed })),
+				Error:            fw.Error,
+				Enforcement:      CheckEnforcement(CheckFirmware),
+			}
+			if fw.HostSecurity != nil {
+				summary.Firmware.HostSecurityID = fw.HostSecurity.ID
+			}
+			for _, f := range firmwareFindings(fw) {
+				report(f)
+			}
+		}
+	}
+
+	// Get management engine state
+	if runs(CheckManagementEngine) {
+		me, err := runCheck(ctx, rec, CheckManagementEngine, GetManagementEngineContext)
+		if err == nil {
+			passed[CheckManagementEngine] = me.Compliant
+			summary.ManagementEngine = &ManagementEngineSummary{
+				Detected:        me.Detected,
+				Vendor:          me.Vendor,
+				FirmwareVersion: me.FirmwareVersion,
+				AMTExposed:      me.AMTExposed,
+				Compliant:       me.Compliant,
+				Error:           me.Error,
+				Enforcement:     CheckEnforcement(CheckManagementEngine),
+			}
+			for _, f := range managementEngineFindings(me) {
+				report(f)
+			}
+		}
+	}
+
+	// Get USB storage restrictions, only when the policy requires blocking
+	if runs(CheckUSBStorage) {
+		usb, err := runCheck(ctx, rec, CheckUSBStorage, GetUSBDevicesContext)
+		if err == nil {
+			passed[CheckUSBStorage] = usb.StorageRestricted
+			summary.USB = &USBSummary{
+				StorageRestricted: usb.StorageRestricted,
+				StorageDevices:    usb.StorageDevices,
+				Error:             usb.Error,
+				Enforcement:       CheckEnforcement(CheckUSBStorage),
+			}
+			for _, f := range usbFindings(usb) {
+				report(f)
+			}
+		}
+	}
+
+	// Get the passkey authenticator; Linux has no platform authenticator
+	if runs(CheckPasskeys) {
+		pk, err := runCheck(ctx, rec, CheckPasskeys, GetPasskeyStatusContext)
+		if err == nil {
+			passed[CheckPasskeys] = pk.Ready
+			summary.Passkeys = &PasskeySummary{
+				Authenticator: pk.Authenticator,
+				Available:     pk.Available,
+				Configured:    pk.Configured,
+				Ready:         pk.Ready,
+				Error:         pk.Error,
+				Enforcement:   CheckEnforcement(CheckPasskeys),
+			}
+			for _, f := range passkeyFindings(pk) {
+				report(f)
+			}
+		}
+	}
+
+	// Get the credential store and password managers, informational only;
+	// a container has neither
+	if runs(CheckKeychain) {
+		kc, err := runCheck(ctx, rec, CheckKeychain, GetKeychainContext)
+		if err == nil {
+			summary.Keychain = &KeychainSummary{Store: kc.Store, PasswordManagers: []string{}, Error: kc.Error}
+			for _, m := range kc.PasswordManagers {
+				if !slices.Contains(summary.Keychain.PasswordManagers, m.Name) {
+					summary.Keychain.PasswordManagers = append(summary.Keychain.PasswordManagers, m.Name)
+				}
+			}
+		}
+	}
+
+	if env.WSL != nil {
+		findings = append(findings, wslFindings(env.WSL.Host)...)
+	}
+
+	// Cloud instance context and its findings (IMDSv1, missing vTPM), on
+	// machines whose firmware names a cloud vendor
+	cloud := &CloudContext{}
+	if summaryProbesCloud(ctx) {
+		rec.track(ctx, CheckCloud, func(ctx context.Context) error {
+			cloud = GetCloudContext(ctx)
+			return nil
+		})
+	}
+	if cloud.Provider != "" {
+		summary.Cloud = cloud
+		for _, f := range cloudFindings(cloud) {
+			// A container cannot see the instance's TPM device
+			if env.Containerized && f.ID == "cloud_vtpm_missing" {
+				continue
+			}
+			findings = append(findings, f)
+		}
+	}
+
+	// A check that timed out has no outcome; it earns no points, like a
+	// check that could not run
+	for _, id := range rec.timeouts {
+		report(timeoutFinding(id))
+	}
+
+	// The organization's own advice replaces the generic remediations
+	if overrides, err := Remediations(); err != nil {
+		loggerFrom(ctx).Warn("ignoring the remediation overrides", "error", err)
+	} else {
+		overrideRemediations(findings, overrides)
+	}
+
+	// Accepted risks are listed apart from the findings and, if asked for,
+	// scored as passed
+	scored := passed
+	if waivers, err := LoadWaivers(WaiversPath()); err != nil {
+		loggerFrom(ctx).Warn("cannot read the waivers", "path", WaiversPath(), "error", err)
+	} else {
+		findings, summary.Waived = applyWaivers(findings, waivers.Waivers, time.Now())
+		if WaiversScored() {
+			scored, summary.WaivedChecks = waivedChecks(passed, findings, summary.Waived)
+		}
+	}
+
+	for _, id := range PlatformChecks() {
+		if !summaryCheckSupported(id, env) {
+			summary.UnsupportedChecks = append(summary.UnsupportedChecks, id)
+		}
+	}
+	summary.DisabledChecks = DisabledChecks()
+	checks := summary.scoredChecks()
+	score, mandatoryFailures := scoreChecks(checks, scored, summary.NotApplicable)
 	summary.OverallScore = score
-	summary.Recommendations = recommendations
+	summary.MandatoryFailures = mandatoryFailures
+	summary.Domains = domainSummaries(checks, scored, summary.NotApplicable)
+	summary.CheckResults = checkResults(passed, summary.NotApplicable)
+	for _, id := range rec.timeouts {
+		summary.CheckResults[id] = CheckResultTimeout
+	}
+	if baseline, err := LoadBaseline(BaselinePath()); err != nil {
+		loggerFrom(ctx).Warn("cannot read the baseline", "path", BaselinePath(), "error", err)
+	} else if baseline != nil {
+		summary.DeltaFromBaseline = CompareBaseline(baseline, summary)
+	}
+	SortFindings(findings)
+	summary.Findings = findings
+	summary.RequiresElevation = elevationRequired(summary)
+	summary.Scan = rec.finish()
 
-	// Determine overall status
+	// Determine overall status; a failed mandatory check is always critical
 	switch {
-	case score >= 100:
-		summary.OverallStatus = "excellent"
-	case score >= 75:
-		summary.OverallStatus = "good"
-	case score >= 50:
-		summary.OverallStatus = "fair"
-	case score >= 25:
-		summary.OverallStatus = "needs_improvement"
-	default:
+	case len(mandatoryFailures) > 0:
 		summary.OverallStatus = "critical"
+	case naStatus != "" && !scoredCheckRan(passed, summary.NotApplicable):
+		// Nothing that counts could be verified from inside the container or guest
+		summary.OverallStatus = naStatus
+	case score == NoScore:
+		summary.OverallStatus = StatusNotScored
+	default:
+		summary.OverallStatus = scoreStatus(score)
 	}
 
 	return summary, nil
@@ -154,11 +853,17 @@ func GetSecuritySummary() (*SecuritySummary, error) {
 
 // FormatSecuritySummaryTable formats security summary as a colored table
 func FormatSecuritySummaryTable(result *SecuritySummary) string {
+	return FormatSecuritySummaryTableWith(CurrentStyler(), result)
+}
+
+// FormatSecuritySummaryTableWith is like FormatSecuritySummaryTable but
+// styles the table with st
+func FormatSecuritySummaryTableWith(st Styler, result *SecuritySummary) string {
 	var sb strings.Builder
 	sb.WriteString("\n")
-	sb.WriteString(Header(IconShield + " Security Summary"))
+	sb.WriteString(st.Header(IconShield + " " + T("Security Summary")))
 	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 60)))
+	sb.WriteString(st.Muted(strings.Repeat("─", 60)))
 	sb.WriteString("\n\n")
 
 	// Platform
@@ -173,87 +878,123 @@ func FormatSecuritySummaryTable(result *SecuritySummary) string {
 	case "linux":
 		platformName = "Linux"
 	}
-	sb.WriteString(BoldText("Platform: "))
-	sb.WriteString(Info(platformIcon + " " + platformName))
-	sb.WriteString("\n\n")
+	sb.WriteString(st.BoldText(T("Platform:") + " "))
+	sb.WriteString(st.Info(platformIcon + " " + platformName))
+	sb.WriteString("\n")
+	if c := result.Cloud; c != nil {
+		sb.WriteString(st.BoldText(T("Cloud:") + " "))
+		sb.WriteString(st.Info(strings.TrimSpace(fmt.Sprintf("%s %s %s", c.Provider, c.InstanceType, c.Region))))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
 
 	// Overall Score with visual bar
-	sb.WriteString(BoldText("Security Score: "))
-	scoreColor := UsageColor(float64(100 - result.OverallScore)) // Invert for security (higher is better)
-	sb.WriteString(Colorize(scoreColor+Bold, fmt.Sprintf("%d/100", result.OverallScore)))
+	sb.WriteString(st.BoldText(T("Security Score:") + " "))
+	if result.OverallScore == NoScore {
+		sb.WriteString(st.Muted(FormatScore(NoScore)))
+		sb.WriteString("\n")
+	} else {
+		scoreStyle := render.UsageStyle(float64(100 - result.OverallScore)) // Invert for security (higher is better)
+		sb.WriteString(st.Styled(scoreStyle, st.BoldText(FormatScore(result.OverallScore))))
+		sb.WriteString("\n")
+		sb.WriteString(securityScoreBar(st, result.OverallScore, 40))
+		sb.WriteString("\n")
+	}
+	if result.DeltaFromBaseline != nil {
+		sb.WriteString(formatBaselineDelta(st, result.DeltaFromBaseline))
+	}
 	sb.WriteString("\n")
-	sb.WriteString(securityScoreBar(result.OverallScore, 40))
-	sb.WriteString("\n\n")
 
 	// Overall Status Badge
-	sb.WriteString(BoldText("Status: "))
+	sb.WriteString(st.BoldText(T("Status:") + " "))
 	switch result.OverallStatus {
 	case "excellent":
-		sb.WriteString(Success(IconCheck + " Excellent"))
+		sb.WriteString(st.Success(IconCheck + " " + T("Excellent")))
 	case "good":
-		sb.WriteString(Success(IconCheck + " Good"))
+		sb.WriteString(st.Success(IconCheck + " " + T("Good")))
 	case "fair":
-		sb.WriteString(Warning(IconWarning + " Fair"))
+		sb.WriteString(st.Warning(IconWarning + " " + T("Fair")))
 	case "needs_improvement":
-		sb.WriteString(Warning(IconWarning + " Needs Improvement"))
+		sb.WriteString(st.Warning(IconWarning + " " + T("Needs Improvement")))
 	case "critical":
-		sb.WriteString(Danger(IconCross + " Critical"))
+		sb.WriteString(st.Danger(IconCross + " " + T("Critical")))
+	case StatusNotApplicableInContainer:
+		sb.WriteString(st.Info(IconInfo + " " + T("Not applicable in container")))
+	case StatusNotApplicableInWSL:
+		sb.WriteString(st.Info(IconInfo + " " + T("Not applicable in WSL")))
+	case StatusNotScored:
+		sb.WriteString(st.Info(IconInfo + " " + T("Not scored")))
 	}
-	sb.WriteString("\n\n")
+	sb.WriteString("\n")
+	if len(result.MandatoryFailures) > 0 {
+		sb.WriteString(st.Danger(IconCross + " " + T("Mandatory checks failed: %s", strings.Join(result.MandatoryFailures, ", "))))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
 
 	// Security Features Table
-	sb.WriteString(BoldText("Security Features:"))
+	sb.WriteString(st.BoldText(T("Security Features:")))
 	sb.WriteString("\n")
-	sb.WriteString(TableTop(24, 12, 18))
+	sb.WriteString(st.TableTop(24, 12, 18))
 	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Feature", 24)),
-		Header(PadRight("Status", 12)),
-		Header(PadRight("Details", 18)),
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Feature"), 24)),
+		st.Header(PadRight(T("Status"), 12)),
+		st.Header(PadRight(T("Details"), 18)),
 	))
 	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(24, 12, 18))
+	sb.WriteString(st.TableSeparator(24, 12, 18))
 	sb.WriteString("\n")
 
 	// TPM / Secure Enclave
 	var tpmName string
 	switch result.Platform {
 	case "darwin":
-		tpmName = "Secure Enclave"
+		tpmName = T("Secure Enclave")
 	default:
 		tpmName = "TPM"
 	}
 	if result.TPM != nil {
-		sb.WriteString(TableRowColored(
+		sb.WriteString(st.TableRowColored(
 			PadRight(IconShield+" "+tpmName, 24),
-			PadRight(featureStatus(result.TPM.Present && result.TPM.Enabled), 12),
-			PadRight(result.TPM.Type, 18),
+			PadRight(rowStatus(st, result, CheckTPM, result.TPM.Present && result.TPM.Enabled), 12),
+			PadRight(Truncate(tpmDetail(result.TPM), 18), 18),
 		))
 	} else {
-		sb.WriteString(TableRowColored(
+		sb.WriteString(st.TableRowColored(
 			PadRight(IconShield+" "+tpmName, 24),
-			PadRight(Muted("N/A"), 12),
-			PadRight(Muted("-"), 18),
+			PadRight(st.Muted(T("N/A")), 12),
+			PadRight(st.Muted(unavailableDetail(result, CheckTPM)), 18),
 		))
 	}
 	sb.WriteString("\n")
 
 	// Secure Boot
 	if result.SecureBoot != nil {
-		sb.WriteString(TableRowColored(
-			PadRight(IconLock+" Secure Boot", 24),
-			PadRight(featureStatus(result.SecureBoot.Enabled), 12),
-			PadRight(result.SecureBoot.Mode, 18),
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconLock+" "+T("Secure Boot"), 24),
+			PadRight(rowStatus(st, result, CheckSecureBoot, result.SecureBoot.Enabled), 12),
+			PadRight(Truncate(result.SecureBoot.Mode, 18), 18),
 		))
 	} else {
-		sb.WriteString(TableRowColored(
-			PadRight(IconLock+" Secure Boot", 24),
-			PadRight(Muted("N/A"), 12),
-			PadRight(Muted("-"), 18),
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconLock+" "+T("Secure Boot"), 24),
+			PadRight(st.Muted(T("N/A")), 12),
+			PadRight(st.Muted(unavailableDetail(result, CheckSecureBoot)), 18),
 		))
 	}
 	sb.WriteString("\n")
 
+	// Boot order, next to Secure Boot
+	if result.BootOrder != nil {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconLock+" "+T("Boot Order"), 24),
+			PadRight(bootOrderStatus(st, result), 12),
+			PadRight(Truncate(bootOrderDetail(result.BootOrder), 18), 18),
+		))
+		sb.WriteString("\n")
+	}
+
 	// Disk Encryption
 	var encName string
 	switch result.Platform {
@@ -264,94 +1005,745 @@ func FormatSecuritySummaryTable(result *SecuritySummary) string {
 	case "linux":
 		encName = "LUKS"
 	default:
-		encName = "Disk Encryption"
+		encName = T("Disk Encryption")
 	}
 	if result.Encryption != nil {
-		sb.WriteString(TableRowColored(
+		sb.WriteString(st.TableRowColored(
 			PadRight(IconLock+" "+encName, 24),
-			PadRight(featureStatus(result.Encryption.Enabled), 12),
-			PadRight(result.Encryption.Status, 18),
+			PadRight(rowStatus(st, result, CheckEncryption, result.Encryption.Enabled), 12),
+			PadRight(Truncate(result.Encryption.Status, 18), 18),
 		))
 	} else {
-		sb.WriteString(TableRowColored(
+		sb.WriteString(st.TableRowColored(
 			PadRight(IconLock+" "+encName, 24),
-			PadRight(Muted("N/A"), 12),
-			PadRight(Muted("-"), 18),
+			PadRight(st.Muted(T("N/A")), 12),
+			PadRight(st.Muted(unavailableDetail(result, CheckEncryption)), 18),
 		))
 	}
 	sb.WriteString("\n")
 
 	// Biometrics
 	if result.Biometrics != nil {
-		sb.WriteString(TableRowColored(
-			PadRight(IconFingerprint+" Biometrics", 24),
-			PadRight(featureStatus(result.Biometrics.Configured), 12),
-			PadRight(result.Biometrics.Type, 18),
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconFingerprint+" "+T("Biometrics"), 24),
+			PadRight(rowStatus(st, result, CheckBiometrics, result.Biometrics.Configured), 12),
+			PadRight(Truncate(result.Biometrics.Type, 18), 18),
 		))
 	} else {
-		sb.WriteString(TableRowColored(
-			PadRight(IconFingerprint+" Biometrics", 24),
-			PadRight(Muted("N/A"), 12),
-			PadRight(Muted("-"), 18),
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconFingerprint+" "+T("Biometrics"), 24),
+			PadRight(st.Muted(T("N/A")), 12),
+			PadRight(st.Muted(unavailableDetail(result, CheckBiometrics)), 18),
 		))
 	}
 	sb.WriteString("\n")
 
-	sb.WriteString(TableBottom(24, 12, 18))
+	// Microsoft Defender (Windows only)
+	if result.Defender != nil {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconShield+" "+T("Microsoft Defender"), 24),
+			PadRight(rowStatus(st, result, CheckDefender, result.Defender.Protected), 12),
+			PadRight(Truncate(defenderDetail(result.Defender), 18), 18),
+		))
+		sb.WriteString("\n")
+	} else if result.Platform == "windows" {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconShield+" "+T("Microsoft Defender"), 24),
+			PadRight(st.Muted(T("N/A")), 12),
+			PadRight(st.Muted(unavailableDetail(result, CheckDefender)), 18),
+		))
+		sb.WriteString("\n")
+	}
+
+	// UAC and SmartScreen (Windows only)
+	if result.UAC != nil {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconShield+" "+T("UAC / SmartScreen"), 24),
+			PadRight(rowStatus(st, result, CheckUAC, result.UAC.Protected), 12),
+			PadRight(Truncate(uacDetail(result.UAC), 18), 18),
+		))
+		sb.WriteString("\n")
+	} else if result.Platform == "windows" {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconShield+" "+T("UAC / SmartScreen"), 24),
+			PadRight(st.Muted(T("N/A")), 12),
+			PadRight(st.Muted(unavailableDetail(result, CheckUAC)), 18),
+		))
+		sb.WriteString("\n")
+	}
+
+	// Legacy protocols (Windows only)
+	if result.LegacyProtocols != nil {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconShield+" "+T("Legacy Protocols"), 24),
+			PadRight(rowStatus(st, result, CheckLegacyProtocols, result.LegacyProtocols.Hardened), 12),
+			PadRight(Truncate(legacyDetail(result.LegacyProtocols), 18), 18),
+		))
+		sb.WriteString("\n")
+	} else if result.Platform == "windows" {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconShield+" "+T("Legacy Protocols"), 24),
+			PadRight(st.Muted(T("N/A")), 12),
+			PadRight(st.Muted(unavailableDetail(result, CheckLegacyProtocols)), 18),
+		))
+		sb.WriteString("\n")
+	}
+
+	// Browsers (all platforms)
+	if result.Browsers != nil {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconShield+" "+T("Browsers"), 24),
+			PadRight(rowStatus(st, result, CheckBrowser, result.Browsers.Secure), 12),
+			PadRight(Truncate(browserDetail(result.Browsers), 18), 18),
+		))
+		sb.WriteString("\n")
+	}
+
+	// Docker (all platforms)
+	if result.Docker != nil {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconShield+" "+T("Docker"), 24),
+			PadRight(dockerStatus(st, result), 12),
+			PadRight(Truncate(dockerDetail(result.Docker), 18), 18),
+		))
+		sb.WriteString("\n")
+	}
+
+	// Kubelet (Linux, only on Kubernetes nodes)
+	if result.Kubelet != nil && result.Kubelet.Detected {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconShield+" "+T("Kubelet"), 24),
+			PadRight(rowStatus(st, result, CheckKubelet, result.Kubelet.Secure), 12),
+			PadRight(Truncate(kubeletDetail(result.Kubelet), 18), 18),
+		))
+		sb.WriteString("\n")
+	}
+
+	// Uptime (all platforms)
+	if result.Uptime != nil {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconShield+" "+T("Pending Reboot"), 24),
+			PadRight(uptimeStatus(st, result), 12),
+			PadRight(Truncate(uptimeDetail(result.Uptime), 18), 18),
+		))
+		sb.WriteString("\n")
+	}
+
+	// Firmware (all platforms)
+	if result.Firmware != nil {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconChip+" "+T("Firmware"), 24),
+			PadRight(firmwareStatus(st, result), 12),
+			PadRight(Truncate(firmwareDetail(result.Firmware), 18), 18),
+		))
+		sb.WriteString("\n")
+	}
+
+	// Management engine (Linux and Windows, when one was found)
+	if result.ManagementEngine != nil && (result.ManagementEngine.Detected || result.ManagementEngine.AMTExposed) {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconChip+" "+T("Management Engine"), 24),
+			PadRight(managementEngineStatus(st, result), 12),
+			PadRight(Truncate(managementEngineDetail(result.ManagementEngine), 18), 18),
+		))
+		sb.WriteString("\n")
+	}
+
+	// USB storage (all platforms, when the policy requires blocking)
+	if result.USB != nil {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconShield+" "+T("USB Storage"), 24),
+			PadRight(usbStatus(st, result), 12),
+			PadRight(Truncate(usbDetail(result.USB), 18), 18),
+		))
+		sb.WriteString("\n")
+	}
+
+	// Passkeys (macOS and Windows)
+	if result.Passkeys != nil {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconKey+" "+T("Passkeys"), 24),
+			PadRight(passkeyStatus(st, result), 12),
+			PadRight(Truncate(passkeyDetail(result.Passkeys), 18), 18),
+		))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.TableBottom(24, 12, 18))
 	sb.WriteString("\n")
 
-	// Recommendations
-	if len(result.Recommendations) > 0 {
+	// Password managers (informational)
+	if k := result.Keychain; k != nil {
+		sb.WriteString(st.BoldText(T("Password Managers:") + " "))
+		if len(k.PasswordManagers) > 0 {
+			sb.WriteString(st.Info(strings.Join(k.PasswordManagers, ", ")))
+		} else {
+			sb.WriteString(st.Muted(T("none found")))
+		}
 		sb.WriteString("\n")
-		sb.WriteString(BoldText(IconWarning + " Recommendations:"))
+	}
+
+	// Domain rollups
+	if len(result.Domains) > 0 {
 		sb.WriteString("\n")
-		sb.WriteString(Muted(strings.Repeat("─", 50)))
+		sb.WriteString(st.BoldText(T("Domains:")))
 		sb.WriteString("\n")
-		for i, rec := range result.Recommendations {
-			sb.WriteString(fmt.Sprintf("  %d. %s\n", i+1, Warning(rec)))
+		for _, d := range result.Domains {
+			sb.WriteString("  " + PadRight(domainName(d.ID), 22))
+			if d.Status == StatusNotScored {
+				sb.WriteString(st.Muted(T("Not scored")))
+			} else {
+				sb.WriteString(securityScoreBar(st, d.Score, 20))
+				sb.WriteString(fmt.Sprintf(" %3d/100", d.Score))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	// Findings, grouped by severity
+	if len(result.Findings) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText(IconWarning + " " + T("Findings:")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+		sb.WriteString("\n")
+		sb.WriteString(formatFindings(st, result.Findings))
+	}
+
+	// Findings accepted by a waiver
+	if len(result.Waived) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText(IconInfo + " " + T("Waived:")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+		sb.WriteString("\n")
+		for _, w := range result.Waived {
+			sb.WriteString(fmt.Sprintf("  %s %s %s\n", st.Muted(IconArrow), w.Title, st.Muted("("+w.ID+")")))
+			sb.WriteString(st.Muted("    " + T("%s (until %s)", w.Waiver.Reason, w.Waiver.Until.Local().Format(time.DateOnly))))
+			sb.WriteString("\n")
+		}
+		if len(result.WaivedChecks) > 0 {
+			sb.WriteString(st.Muted("  " + T("Scored as passed: %s", strings.Join(result.WaivedChecks, ", "))))
+			sb.WriteString("\n")
+		}
+	}
+
+	// Probes degraded by insufficient privileges
+	if len(result.RequiresElevation) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText(IconLock + " " + T("Requires Elevation:")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+		sb.WriteString("\n")
+		for _, probe := range result.RequiresElevation {
+			sb.WriteString(fmt.Sprintf("  %s %s\n", st.Warning(IconArrow), probe))
+		}
+		sb.WriteString(st.Muted("  " + T("%s for complete results", elevationHint())))
+		sb.WriteString("\n")
+	}
+	// Host-only checks skipped in a container or describing a WSL guest
+	if env := result.Environment; env != nil && len(result.NotApplicable) > 0 {
+		sb.WriteString("\n")
+		if env.WSL != nil {
+			sb.WriteString(st.Info(IconInfo + " " + T("Running under WSL%d: host-only checks show the Linux guest and are not scored", env.WSL.Version)))
+			sb.WriteString("\n")
+			if env.WSL.Host != nil {
+				sb.WriteString(st.Muted("  " + T("Windows host: %s", formatWSLHost(env.WSL.Host))))
+				sb.WriteString("\n")
+			}
+		} else {
+			sb.WriteString(st.Info(IconInfo + " " + T("Running in a container (%s): host-only checks are not applicable", env.Runtime)))
+			sb.WriteString("\n")
+			sb.WriteString(st.Muted("  " + T("Run on the host, or set %s=1 if host devices are passed through", AssumeHostEnv)))
+			sb.WriteString("\n")
 		}
 	}
 	sb.WriteString("\n")
+	sb.WriteString(formatScanStats(st, result.Scan))
 
 	return sb.String()
 }
 
-// securityScoreBar creates a security score progress bar (green = good)
-func securityScoreBar(score int, width int) string {
-	filled := score * width / 100
-	if filled > width {
-		filled = width
+// elevationRequired returns the probes in the summary that were degraded
+// because the process lacked privileges
+func elevationRequired(summary *SecuritySummary) []string {
+	var errs []*ProbeError
+	if summary.TPM != nil {
+		errs = append(errs, summary.TPM.Error)
+	}
+	if summary.SecureBoot != nil {
+		errs = append(errs, summary.SecureBoot.Error)
+	}
+	if summary.BootOrder != nil {
+		errs = append(errs, summary.BootOrder.Error)
+	}
+	if summary.Encryption != nil {
+		errs = append(errs, summary.Encryption.Error)
+	}
+	if summary.Defender != nil {
+		errs = append(errs, summary.Defender.Error)
+	}
+	if summary.UAC != nil {
+		errs = append(errs, summary.UAC.Error)
+	}
+	if summary.LegacyProtocols != nil {
+		errs = append(errs, summary.LegacyProtocols.Error)
+	}
+	if summary.Docker != nil {
+		errs = append(errs, summary.Docker.Error)
+	}
+	if summary.Kubelet != nil {
+		errs = append(errs, summary.Kubelet.Error)
+	}
+	if summary.Uptime != nil {
+		errs = append(errs, summary.Uptime.Error)
+	}
+	if summary.Firmware != nil {
+		errs = append(errs, summary.Firmware.Error)
+	}
+	if summary.ManagementEngine != nil {
+		errs = append(errs, summary.ManagementEngine.Error)
+	}
+	if summary.USB != nil {
+		errs = append(errs, summary.USB.Error)
+	}
+	if summary.Passkeys != nil {
+		errs = append(errs, summary.Passkeys.Error)
+	}
+	if summary.Keychain != nil {
+		errs = append(errs, summary.Keychain.Error)
+	}
+
+	var probes []string
+	for _, e := range errs {
+		if e != nil && errors.Is(e, ErrPermissionDenied) && !slices.Contains(probes, e.Probe) {
+			probes = append(probes, e.Probe)
+		}
 	}
-	if filled < 0 {
-		filled = 0
+	return probes
+}
+
+// checkPoints is the score contributed by each passing security check
+const checkPoints = 25
+
+// scoreChecks computes the overall score from the outcomes of the given
+// checks (see scoredChecks). Informational, not applicable, and disabled
+// checks are excluded from the score entirely; the remaining checks are
+// weighted (see CheckWeight) and scaled to 100, or NoScore when none
+// remain. It also returns the mandatory checks that did not pass.
+func scoreChecks(checks []string, passed map[string]bool, notApplicable map[string]string) (int, []string) {
+	var possible, earned float64
+	var mandatoryFailures []string
+	for _, id := range checks {
+		enforcement := CheckEnforcement(id)
+		if _, na := notApplicable[id]; na || enforcement == EnforcementInformational || !CheckEnabled(id) {
+			continue
+		}
+		points := checkPoints * CheckWeight(id)
+		possible += points
+		ok, ran := passed[id]
+		if ok {
+			earned += points
+		}
+		if enforcement == EnforcementMandatory && (!ran || !ok) {
+			mandatoryFailures = append(mandatoryFailures, id)
+		}
 	}
+	if possible == 0 {
+		return NoScore, mandatoryFailures
+	}
+	return int(earned * 100 / possible), mandatoryFailures
+}
+
+// scoredChecks returns the checks of the summary's platform that count
+// toward its score: those that are enabled and that this machine supports
+func (s *SecuritySummary) scoredChecks() []string {
+	var checks []string
+	for _, id := range checksFor(s.Platform) {
+		if CheckEnabled(id) && !slices.Contains(s.DisabledChecks, id) && !slices.Contains(s.UnsupportedChecks, id) {
+			checks = append(checks, id)
+		}
+	}
+	return checks
+}
 
-	var color string
+// scoreStatus returns the status for a score
+func scoreStatus(score int) string {
 	switch {
+	case score >= 100:
+		return "excellent"
 	case score >= 75:
-		color = Green
+		return "good"
 	case score >= 50:
-		color = Yellow
-	default:
-		color = Red
+		return "fair"
+	case score >= 25:
+		return "needs_improvement"
+	}
+	return "critical"
+}
+
+// domainSummaries scores each posture domain over its checks, in
+// AllDomains order. Domains with no checks on this platform are left out.
+func domainSummaries(checks []string, passed map[string]bool, notApplicable map[string]string) []DomainSummary {
+	var domains []DomainSummary
+	for _, domain := range AllDomains {
+		var ids []string
+		for _, id := range checks {
+			if CheckDomain(id) == domain {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		d := DomainSummary{ID: domain, Checks: ids}
+		scored := false
+		for _, id := range ids {
+			if _, na := notApplicable[id]; na {
+				continue
+			}
+			ok, ran := passed[id]
+			if ran && !ok {
+				d.Failed = append(d.Failed, id)
+			}
+			if ran && CheckEnforcement(id) != EnforcementInformational {
+				scored = true
+			}
+		}
+		score, mandatoryFailures := scoreChecks(ids, passed, notApplicable)
+		switch {
+		case !scored:
+			d.Score = NoScore
+			d.Status = StatusNotScored
+		case len(mandatoryFailures) > 0:
+			d.Score = score
+			d.Status = "critical"
+		default:
+			d.Score = score
+			d.Status = scoreStatus(score)
+		}
+		domains = append(domains, d)
+	}
+	return domains
+}
+
+// domainName returns the display name of a posture domain
+func domainName(id string) string {
+	switch id {
+	case DomainIdentity:
+		return T("Identity")
+	case DomainDataProtection:
+		return T("Data Protection")
+	case DomainBootIntegrity:
+		return T("Boot Integrity")
+	case DomainNetwork:
+		return T("Network")
+	case DomainEndpointProtection:
+		return T("Endpoint Protection")
+	case DomainPatching:
+		return T("Patching")
+	}
+	return id
+}
+
+// securityScoreBar creates a security score progress bar (green = good)
+func securityScoreBar(st Styler, score int, width int) string {
+	style := render.StyleDanger
+	switch {
+	case score >= 75:
+		style = render.StyleSuccess
+	case score >= 50:
+		style = render.StyleWarning
+	}
+	return render.ProgressBar(st.Renderer, float64(score), width, style)
+}
+
+// scoredCheckRan reports whether any check that ran counts toward the score
+func scoredCheckRan(passed map[string]bool, notApplicable map[string]string) bool {
+	for id := range passed {
+		if _, na := notApplicable[id]; !na {
+			return true
+		}
 	}
+	return false
+}
+
+// unavailableDetail explains why a feature row has no result
+func unavailableDetail(result *SecuritySummary, id string) string {
+	if result.NotApplicable[id] != "" {
+		return T("in container")
+	}
+	return "-"
+}
 
-	bar := color + strings.Repeat(IconBar, filled) + Reset
-	bar += Muted(strings.Repeat(IconBarLight, width-filled))
-	return bar
+// tpmDetail describes the TPM type and, for TPMs, its kind
+func tpmDetail(t *TPMSummary) string {
+	if t.Kind == "" || t.Kind == TPMKindSecureEnclave {
+		return t.Type
+	}
+	return fmt.Sprintf("%s (%s)", t.Type, t.Kind)
+}
+
+// defenderDetail describes Defender's mode or signature age
+func defenderDetail(d *DefenderSummary) string {
+	if d.Error != nil {
+		return "-"
+	}
+	if strings.HasPrefix(strings.ToLower(d.RunningMode), "passive") {
+		return T("passive")
+	}
+	return T("signatures %dd", d.SignatureAgeDays)
+}
+
+// uacDetail names the first UAC or SmartScreen setting that is off, or how
+// administrators are prompted
+func uacDetail(u *UACSummary) string {
+	switch {
+	case u.Error != nil:
+		return "-"
+	case !u.Enabled:
+		return T("UAC off")
+	case u.SmartScreenApps == SmartScreenOff || u.SmartScreenEdge == SmartScreenOff:
+		return T("SmartScreen off")
+	case u.AdminPromptBehavior == uacAdminBehaviors[0]:
+		return T("no prompt")
+	}
+	return T("prompting")
+}
+
+// legacyDetail lists the legacy protocols that are enabled
+func legacyDetail(l *LegacyProtocolsSummary) string {
+	if l.Error != nil {
+		return "-"
+	}
+	var on []string
+	for _, p := range []struct {
+		name    string
+		enabled bool
+	}{{"SMBv1", l.SMBv1}, {"NTLMv1", l.NTLMv1Allowed}, {"LLMNR", l.LLMNR}, {"NetBIOS", l.NetBIOS}} {
+		if p.enabled {
+			on = append(on, p.name)
+		}
+	}
+	if len(on) == 0 {
+		return T("none enabled")
+	}
+	return strings.Join(on, ", ")
+}
+
+// browserDetail counts the browser profiles found or the problems with them
+func browserDetail(b *BrowserSummary) string {
+	switch {
+	case b.Browsers == 0:
+		return T("none found")
+	case b.SafeBrowsingOff > 0:
+		return T("Safe Browsing off")
+	case b.Outdated > 0:
+		return T("%d outdated", b.Outdated)
+	}
+	return T("%d profiles", b.Browsers)
+}
+
+// dockerStatus returns the status cell of the Docker row; a machine without
+// Docker has nothing to secure
+func dockerStatus(st Styler, result *SecuritySummary) string {
+	if !result.Docker.Installed {
+		return st.Muted(T("N/A"))
+	}
+	return rowStatus(st, result, CheckDocker, result.Docker.Secure)
+}
+
+// dockerDetail names the most serious Docker problem
+func dockerDetail(d *DockerSummary) string {
+	switch {
+	case !d.Installed:
+		return T("not installed")
+	case d.TCPExposed:
+		return T("TCP without TLS")
+	case d.PrivilegedContainers > 0:
+		return T("%d privileged", d.PrivilegedContainers)
+	case d.InsecureRegistries > 0:
+		return T("insecure registries")
+	case !d.Running:
+		return T("not running")
+	case !d.Isolated:
+		return T("root not isolated")
+	}
+	return T("isolated")
+}
+
+// kubeletDetail names the most serious kubelet problem
+func kubeletDetail(k *KubeletSummary) string {
+	switch {
+	case k.RuntimeSocketExposed:
+		return T("runtime socket open")
+	case k.FailedControls > 0:
+		return T("%d CIS controls failed", k.FailedControls)
+	}
+	return T("CIS controls pass")
+}
+
+// uptimeStatus returns the status cell of the pending reboot row
+func uptimeStatus(st Styler, result *SecuritySummary) string {
+	if result.NotApplicable[CheckUptime] != "" {
+		return st.Muted(T("Not scored"))
+	}
+	if result.Uptime.RebootPending {
+		return st.Danger(IconCross + " " + T("Pending"))
+	}
+	return st.Success(IconCheck + " " + T("None"))
+}
+
+// uptimeDetail shows how long a reboot has been pending, or the uptime
+func uptimeDetail(u *UptimeSummary) string {
+	if u.RebootPending && u.PendingDays > 0 {
+		return T("for %d days", u.PendingDays)
+	}
+	return T("up %d days", u.UptimeDays)
+}
+
+// firmwareStatus shows whether the firmware is up to date
+func firmwareStatus(st Styler, result *SecuritySummary) string {
+	if result.NotApplicable[CheckFirmware] != "" {
+		return st.Muted(T("Not scored"))
+	}
+	if result.Firmware.Current {
+		return st.Success(IconCheck + " " + T("Current"))
+	}
+	if f := result.Firmware; f.Error != nil && f.UpdatesAvailable == 0 && f.FailedUpdates == 0 {
+		return st.Warning(T("Not verified"))
+	}
+	return st.Danger(IconCross + " " + T("Outdated"))
+}
+
+// firmwareDetail names pending or failed updates, else the HSI rating or
+// firmware version
+func firmwareDetail(f *FirmwareSummary) string {
+	switch {
+	case f.UpdatesAvailable > 0:
+		return T("%d updates", f.UpdatesAvailable)
+	case f.FailedUpdates > 0:
+		return T("%d failed", f.FailedUpdates)
+	case f.HostSecurityID != "":
+		return f.HostSecurityID
+	}
+	return f.Version
+}
+
+// bootOrderStatus shows whether booting from other media is restricted
+func bootOrderStatus(st Styler, result *SecuritySummary) string {
+	if result.NotApplicable[CheckBootOrder] != "" {
+		return st.Muted(T("Not scored"))
+	}
+	if result.BootOrder.Compliant {
+		return st.Success(IconCheck + " " + T("Restricted"))
+	}
+	return st.Danger(IconCross + " " + T("Allowed"))
+}
+
+// bootOrderDetail shows what boots before the system disk, or whether a Mac
+// has a firmware password
+func bootOrderDetail(b *BootOrderSummary) string {
+	switch {
+	case b.NetworkBeforeDisk:
+		return T("network first")
+	case b.ExternalBeforeDisk:
+		return T("removable first")
+	case b.FirmwarePassword != nil && *b.FirmwarePassword:
+		return T("firmware password")
+	case b.FirmwarePassword != nil:
+		return T("no firmware password")
+	}
+	return T("disk first")
+}
+
+// managementEngineStatus shows whether the management engine is locked down
+func managementEngineStatus(st Styler, result *SecuritySummary) string {
+	if result.NotApplicable[CheckManagementEngine] != "" {
+		return st.Muted(T("Not scored"))
+	}
+	if result.ManagementEngine.AMTExposed {
+		return st.Danger(IconCross + " " + T("Exposed"))
+	}
+	if !result.ManagementEngine.Compliant {
+		return st.Danger(IconCross + " " + T("Unsafe"))
+	}
+	return st.Success(IconCheck + " " + T("Secure"))
+}
+
+// managementEngineDetail shows the engine and its firmware version
+func managementEngineDetail(m *ManagementEngineSummary) string {
+	engine := "ME"
+	if m.Vendor == "AMD" {
+		engine = "PSP"
+	}
+	if m.FirmwareVersion != "" {
+		return engine + " " + m.FirmwareVersion
+	}
+	return engine
+}
+
+// usbStatus shows whether USB mass storage is blocked
+func usbStatus(st Styler, result *SecuritySummary) string {
+	if result.NotApplicable[CheckUSBStorage] != "" {
+		return st.Muted(T("Not scored"))
+	}
+	if result.USB.StorageRestricted {
+		return st.Success(IconCheck + " " + T("Blocked"))
+	}
+	return st.Danger(IconCross + " " + T("Allowed"))
+}
+
+// usbDetail shows how many USB storage devices are connected
+func usbDetail(u *USBSummary) string {
+	if u.StorageDevices > 0 {
+		return T("%d connected", u.StorageDevices)
+	}
+	return T("none connected")
+}
+
+// passkeyStatus shows whether passkeys can be created
+func passkeyStatus(st Styler, result *SecuritySummary) string {
+	if result.NotApplicable[CheckPasskeys] != "" {
+		return st.Muted(T("Not scored"))
+	}
+	if result.Passkeys.Ready {
+		return st.Success(IconCheck + " " + T("Ready"))
+	}
+	return st.Warning(IconWarning + T("Not set up"))
+}
+
+// passkeyDetail shows the platform authenticator
+func passkeyDetail(p *PasskeySummary) string {
+	switch {
+	case !p.Available:
+		return T("unsupported")
+	case p.Authenticator == PasskeyAuthenticatorICloudKeychain:
+		return "iCloud Keychain"
+	}
+	return "Windows Hello"
+}
+
+// rowStatus returns the status cell for a feature that ran; checks that only
+// describe a WSL guest are marked as not scored
+func rowStatus(st Styler, result *SecuritySummary, id string, enabled bool) string {
+	if result.NotApplicable[id] != "" {
+		return st.Muted(T("Not scored"))
+	}
+	return featureStatus(st, enabled)
 }
 
 // featureStatus returns a colored status indicator
-func featureStatus(enabled bool) string {
+func featureStatus(st Styler, enabled bool) string {
 	if enabled {
-		return Success(IconCheck + " Enabled")
+		return st.Success(IconCheck + " " + T("Enabled"))
 	}
-	return Danger(IconCross + " Disabled")
+	return st.Danger(IconCross + " " + T("Disabled"))
 }
 
 // FormatSecuritySummary formats security summary in the specified format
 func FormatSecuritySummary(result *SecuritySummary, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatSecuritySummaryTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatSecuritySummaryTableWith(st, result)
 	}, format)
 }
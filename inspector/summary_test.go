@@ -1,6 +1,7 @@
 package inspector
 
 import (
+	"context"
 	"encoding/json"
 	"runtime"
 	"strings"
@@ -22,13 +23,13 @@ func TestGetSecuritySummary(t *testing.T) {
 		t.Errorf("Platform = %q, want %q", result.Platform, runtime.GOOS)
 	}
 
-	// Score should be between 0 and 100
-	if result.OverallScore < 0 || result.OverallScore > 100 {
+	// Score should be between 0 and 100, unless nothing could be scored
+	if (result.OverallScore < 0 || result.OverallScore > 100) && result.OverallScore != NoScore {
 		t.Errorf("OverallScore = %d, want between 0 and 100", result.OverallScore)
 	}
 
 	// Status should be one of the valid values
-	validStatuses := []string{"excellent", "good", "fair", "needs_improvement", "critical"}
+	validStatuses := []string{"excellent", "good", "fair", "needs_improvement", "critical", StatusNotApplicableInContainer, StatusNotScored}
 	found := false
 	for _, s := range validStatuses {
 		if result.OverallStatus == s {
@@ -101,7 +102,7 @@ func TestSecuritySummary_JSON(t *testing.T) {
 			Configured: true,
 			Type:       "touch_id",
 		},
-		Recommendations: []string{"Enable FileVault"},
+		Findings: []Finding{{ID: "encryption_disabled", Title: "Disk encryption is disabled", Severity: SeverityCritical, Check: CheckEncryption, Remediation: "Enable FileVault"}},
 	}
 
 	data, err := json.Marshal(result)
@@ -259,7 +260,9 @@ func TestFormatSecuritySummaryTable(t *testing.T) {
 			Configured: true,
 			Type:       "touch_id",
 		},
-		Recommendations: []string{"Enable FileVault to protect data at rest"},
+		Findings: []Finding{
+			{ID: "encryption_disabled", Title: "Disk encryption is disabled", Severity: SeverityCritical, Check: CheckEncryption, Remediation: "Enable FileVault to protect data at rest", RemediationCommand: "sudo fdesetup enable"},
+		},
 	}
 
 	output := FormatSecuritySummaryTable(result)
@@ -292,9 +295,11 @@ func TestFormatSecuritySummaryTable(t *testing.T) {
 		}
 	}
 
-	// Should contain recommendations
-	if !strings.Contains(output, "Recommendations") {
-		t.Error("Output should contain 'Recommendations' section")
+	// Should contain findings
+	for _, want := range []string{"Findings", "Critical (1)", "Disk encryption is disabled", "$ sudo fdesetup enable"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output should contain %q", want)
+		}
 	}
 
 	// Should have table characters
@@ -326,12 +331,12 @@ func TestFormatSecuritySummary(t *testing.T) {
 	}
 }
 
-func TestFormatSecuritySummaryTable_NoRecommendations(t *testing.T) {
+func TestFormatSecuritySummaryTable_NoFindings(t *testing.T) {
 	result := &SecuritySummary{
-		Platform:        "darwin",
-		OverallScore:    100,
-		OverallStatus:   "excellent",
-		Recommendations: []string{},
+		Platform:      "darwin",
+		OverallScore:  100,
+		OverallStatus: "excellent",
+		Findings:      []Finding{},
 	}
 
 	output := FormatSecuritySummaryTable(result)
@@ -393,6 +398,17 @@ func TestFormatSecuritySummaryTable_AllStatuses(t *testing.T) {
 	}
 }
 
+func TestFormatSecuritySummary_NoScore(t *testing.T) {
+	result := &SecuritySummary{Platform: "linux", OverallScore: NoScore, OverallStatus: StatusNotScored}
+	table := FormatSecuritySummaryTable(result)
+	if !strings.Contains(table, "N/A") || strings.Contains(table, "-1/100") {
+		t.Errorf("table should show N/A for the score:\n%s", table)
+	}
+	if out := FormatSecuritySummary(result, FormatJSON); !strings.Contains(out, `"overall_score": -1`) || !strings.Contains(out, `"overall_status": "not_scored"`) {
+		t.Errorf("JSON = %s", out)
+	}
+}
+
 func TestSecurityScoreBar(t *testing.T) {
 	tests := []struct {
 		score       int
@@ -409,7 +425,7 @@ func TestSecurityScoreBar(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run("", func(t *testing.T) {
-			result := securityScoreBar(tt.score, tt.width)
+			result := securityScoreBar(CurrentStyler(), tt.score, tt.width)
 
 			if tt.expectGreen && !strings.Contains(result, Green) {
 				t.Errorf("Score %d should produce green bar", tt.score)
@@ -422,7 +438,7 @@ func TestSecurityScoreBar(t *testing.T) {
 }
 
 func TestFeatureStatus(t *testing.T) {
-	enabled := featureStatus(true)
+	enabled := featureStatus(CurrentStyler(), true)
 	if !strings.Contains(enabled, IconCheck) {
 		t.Error("Enabled status should contain check icon")
 	}
@@ -430,7 +446,7 @@ func TestFeatureStatus(t *testing.T) {
 		t.Error("Enabled status should contain 'Enabled'")
 	}
 
-	disabled := featureStatus(false)
+	disabled := featureStatus(CurrentStyler(), false)
 	if !strings.Contains(disabled, IconCross) {
 		t.Error("Disabled status should contain cross icon")
 	}
@@ -438,3 +454,21 @@ func TestFeatureStatus(t *testing.T) {
 		t.Error("Disabled status should contain 'Disabled'")
 	}
 }
+
+func TestElevationRequired(t *testing.T) {
+	denied := newProbeError(context.Background(), ErrPermissionDenied, "fdesetup", "insufficient privileges to run fdesetup")
+	summary := &SecuritySummary{
+		TPM:        &TPMSummary{Error: newProbeError(context.Background(), ErrToolMissing, "tpm2_getcap", "not installed")},
+		SecureBoot: &BootSummary{Error: newProbeError(context.Background(), ErrPermissionDenied, "bputil", "insufficient privileges to run bputil")},
+		Encryption: &EncSummary{Error: denied},
+	}
+
+	got := elevationRequired(summary)
+	if len(got) != 2 || got[0] != "bputil" || got[1] != "fdesetup" {
+		t.Errorf("elevationRequired = %v, want [bputil fdesetup]", got)
+	}
+
+	if got := elevationRequired(&SecuritySummary{}); got != nil {
+		t.Errorf("elevationRequired(empty) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,123 @@
+package inspector
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strings"
+)
+
+// Formats for loading results into spreadsheets and log pipelines
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// listRows returns the rows of a list-shaped result: the elements of a
+// slice, or of the first field of a struct that is a slice of structs (such
+// as ProcessListResult.Processes) that is not tagged tabular:"-". Other
+// results are a single row. The row type is returned too, so that an empty
+// list still has CSV columns.
+func listRows(data any) ([]any, reflect.Type) {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return sliceElems(v), derefType(v.Type().Elem())
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if !f.IsExported() || f.Tag.Get("json") == "-" || f.Tag.Get("tabular") == "-" || f.Type.Kind() != reflect.Slice {
+				continue
+			}
+			if elem := derefType(f.Type.Elem()); elem.Kind() == reflect.Struct {
+				return sliceElems(v.Field(i)), elem
+			}
+		}
+	}
+	return []any{data}, nil
+}
+
+// derefType returns the type a pointer type points to
+func derefType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// sliceElems returns the elements of a slice or array value
+func sliceElems(v reflect.Value) []any {
+	rows := make([]any, v.Len())
+	for i := range rows {
+		rows[i] = v.Index(i).Interface()
+	}
+	return rows
+}
+
+// formatCSV renders a result as CSV: one row per list element with nested
+// fields flattened into dotted columns (see flattenFields)
+func formatCSV(data any) (string, error) {
+	var columns []string
+	seen := map[string]bool{}
+	var rows []map[string]string
+	list, rowType := listRows(data)
+	if len(list) == 0 && rowType != nil && rowType.Kind() == reflect.Struct {
+		fields, err := flattenFields(reflect.New(rowType).Elem().Interface())
+		if err != nil {
+			return "", err
+		}
+		for _, f := range fields {
+			columns = append(columns, f.key)
+		}
+	}
+	for _, row := range list {
+		fields, err := flattenFields(row)
+		if err != nil {
+			return "", err
+		}
+		values := make(map[string]string, len(fields))
+		for _, f := range fields {
+			if !seen[f.key] {
+				seen[f.key] = true
+				columns = append(columns, f.key)
+			}
+			values[f.key] = f.value
+		}
+		rows = append(rows, values)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write(columns)
+	record := make([]string, len(columns))
+	for _, values := range rows {
+		for i, c := range columns {
+			record[i] = values[c]
+		}
+		_ = w.Write(record)
+	}
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n"), w.Error()
+}
+
+// formatNDJSON renders a result as newline-delimited JSON, one compact
+// object per list element
+func formatNDJSON(data any) (string, error) {
+	lines := []string{}
+	list, _ := listRows(data)
+	for _, row := range list {
+		line, err := marshalCompact(row)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, string(line))
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,69 @@
+package inspector
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatOutput_CSV(t *testing.T) {
+	result := &ProcessListResult{
+		Processes: []ProcessInfo{
+			{PID: 1, Name: "init", User: "root", CPUPercent: 0.5, Status: "sleep"},
+			{PID: 42, Name: "web, server", User: "www", CPUPercent: 12, Status: "running"},
+		},
+		Total: 2,
+	}
+	got := FormatProcessList(result, FormatCSV)
+	lines := strings.Split(got, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("CSV has %d lines, want header and 2 rows:\n%s", len(lines), got)
+	}
+	if !strings.HasPrefix(lines[0], "pid,name,user,cpu_percent") {
+		t.Errorf("header = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[2], `42,"web, server",www,12`) {
+		t.Errorf("row with a comma = %q", lines[2])
+	}
+
+	empty := FormatProcessList(&ProcessListResult{}, FormatCSV)
+	if !strings.HasPrefix(empty, "pid,name") || strings.Contains(empty, "\n") {
+		t.Errorf("an empty list should still have a header, got %q", empty)
+	}
+}
+
+func TestFormatOutput_NDJSON(t *testing.T) {
+	result := &ProcessListResult{
+		Processes: []ProcessInfo{{PID: 1, Name: "init"}, {PID: 2, Name: "kthreadd"}},
+	}
+	got := FormatProcessList(result, FormatNDJSON)
+	lines := strings.Split(got, "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], `{"pid":1,"name":"init"`) || !strings.HasPrefix(lines[1], `{"pid":2,`) {
+		t.Errorf("NDJSON = %q", got)
+	}
+}
+
+func TestFormatOutput_SingleRow(t *testing.T) {
+	result := &MemoryResult{TotalBytes: 100, UsedPercent: 25}
+	csv := FormatMemory(result, FormatCSV)
+	if lines := strings.Split(csv, "\n"); len(lines) != 2 || !strings.HasPrefix(lines[1], SchemaVersion+",100,") {
+		t.Errorf("non-list CSV = %q", csv)
+	}
+	if nd := FormatMemory(result, FormatNDJSON); strings.Contains(nd, "\n") || !strings.HasPrefix(nd, `{"schema_version":"`+SchemaVersion+`","total_bytes":100`) {
+		t.Errorf("non-list NDJSON = %q", nd)
+	}
+}
+
+func TestListRows(t *testing.T) {
+	rows, rowType := listRows([]GPUInfo{{Model: "a"}, {Model: "b"}})
+	if len(rows) != 2 || rowType.Name() != "GPUInfo" {
+		t.Errorf("slice rows = %d, %v", len(rows), rowType)
+	}
+	rows, rowType = listRows(&GPUResult{GPUs: []GPUInfo{{Model: "a"}}})
+	if len(rows) != 1 || rowType.Name() != "GPUInfo" {
+		t.Errorf("struct with a list field rows = %d, %v", len(rows), rowType)
+	}
+	rows, rowType = listRows(&MemoryResult{})
+	if len(rows) != 1 || rowType != nil {
+		t.Errorf("non-list rows = %d, %v", len(rows), rowType)
+	}
+}
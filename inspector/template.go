@@ -0,0 +1,203 @@
+package inspector
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// FormatTemplate renders results through a Go template. The template travels
+// with the format as "template:<text>" (see TemplateFormat) so that every
+// Format* function supports it through FormatOutput.
+const FormatTemplate = "template"
+
+// BuiltinTemplates are the named templates accepted in place of template text
+var BuiltinTemplates = map[string]string{
+	// oneline prints every scalar field as key=value on one line
+	"oneline": `{{oneline .}}`,
+	// csv prints a header row of field names and a row of values
+	"csv": `{{csv .}}`,
+}
+
+// templateFuncs are available to every template
+var templateFuncs = template.FuncMap{
+	"json":    templateJSON,
+	"oneline": templateOneline,
+	"csv":     templateCSV,
+	"join":    strings.Join,
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+}
+
+// TemplateFormat returns the format string that renders results through
+// tmpl, which is Go template text or the name of a built-in template
+func TemplateFormat(tmpl string) string {
+	return FormatTemplate + ":" + tmpl
+}
+
+// splitTemplateFormat returns the template carried by a template format
+func splitTemplateFormat(format string) (string, bool) {
+	name, tmpl, ok := strings.Cut(format, ":")
+	if !ok || !strings.EqualFold(name, FormatTemplate) {
+		return "", false
+	}
+	return tmpl, true
+}
+
+// ParseTemplate parses Go template text or a built-in template name.
+// Fields are the Go field names of the result, e.g. {{.OverallScore}}.
+func ParseTemplate(text string) (*template.Template, error) {
+	if builtin, ok := BuiltinTemplates[text]; ok {
+		text = builtin
+	}
+	return template.New("output").Funcs(templateFuncs).Parse(text)
+}
+
+// RenderTemplate renders data through a template (see ParseTemplate)
+func RenderTemplate(data any, text string) (string, error) {
+	tmpl, err := ParseTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// templateJSON encodes a value as compact JSON
+func templateJSON(v any) (string, error) {
+	data, err := marshalCompact(v)
+	return string(data), err
+}
+
+// marshalCompact encodes v as compact JSON without escaping <, >, and &,
+// which are common in probe descriptions
+func marshalCompact(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// templateOneline renders the flattened fields of v as key=value pairs
+func templateOneline(v any) (string, error) {
+	fields, err := flattenFields(v)
+	if err != nil {
+		return "", err
+	}
+	pairs := make([]string, len(fields))
+	for i, f := range fields {
+		value := f.value
+		if value == "" || strings.ContainsAny(value, " \t\"=") {
+			value = strconv.Quote(value)
+		}
+		pairs[i] = f.key + "=" + value
+	}
+	return strings.Join(pairs, " "), nil
+}
+
+// templateCSV renders the flattened fields of v as a header and a value row
+func templateCSV(v any) (string, error) {
+	fields, err := flattenFields(v)
+	if err != nil {
+		return "", err
+	}
+	header := make([]string, len(fields))
+	row := make([]string, len(fields))
+	for i, f := range fields {
+		header[i], row[i] = f.key, f.value
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write(header)
+	_ = w.Write(row)
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n"), w.Error()
+}
+
+// flatField is one scalar field of a flattened result
+type flatField struct {
+	key, value string
+}
+
+// flattenFields flattens v's JSON form into dotted keys in field order.
+// Arrays become one field: scalars joined with ";", objects as JSON.
+func flattenFields(v any) ([]flatField, error) {
+	data, err := marshalCompact(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var fields []flatField
+	if err := flattenValue(dec, "", &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// flattenValue appends the fields of the next JSON value under key
+func flattenValue(dec *json.Decoder, key string, fields *[]flatField) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			for dec.More() {
+				name, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				child := fmt.Sprint(name)
+				if key != "" {
+					child = key + "." + child
+				}
+				if err := flattenValue(dec, child, fields); err != nil {
+					return err
+				}
+			}
+			_, err := dec.Token()
+			return err
+		}
+		var items []string
+		for dec.More() {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			var s string
+			if json.Unmarshal(raw, &s) == nil {
+				items = append(items, s)
+			} else {
+				items = append(items, string(raw))
+			}
+		}
+		*fields = append(*fields, flatField{fieldKey(key), strings.Join(items, ";")})
+		_, err := dec.Token()
+		return err
+	case nil:
+		*fields = append(*fields, flatField{fieldKey(key), ""})
+	default:
+		*fields = append(*fields, flatField{fieldKey(key), fmt.Sprint(t)})
+	}
+	return nil
+}
+
+// fieldKey names a result that is itself a scalar or an array
+func fieldKey(key string) string {
+	if key == "" {
+		return "value"
+	}
+	return key
+}
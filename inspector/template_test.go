@@ -0,0 +1,120 @@
+package inspector
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	summary := &SecuritySummary{
+		Platform:      "linux",
+		OverallScore:  75,
+		OverallStatus: "good",
+		Findings: []Finding{
+			{ID: "secure_boot_disabled", Severity: SeverityHigh},
+			{ID: "biometrics_not_configured", Severity: SeverityLow},
+		},
+	}
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{"field", "{{.OverallScore}}", "75"},
+		{"funcs", "{{upper .Platform}}:{{range .Findings}} {{.ID}}{{end}}", "LINUX: secure_boot_disabled biometrics_not_configured"},
+		{"json", "{{json (index .Findings 1)}}", `{"id":"biometrics_not_configured","title":"","severity":"low","check":"","remediation":""}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTemplate(summary, tt.tmpl)
+			if err != nil || got != tt.want {
+				t.Errorf("RenderTemplate(%q) = %q, %v; want %q", tt.tmpl, got, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := RenderTemplate(summary, "{{.NoSuchField}}"); err == nil {
+		t.Error("an unknown field should be an error")
+	}
+	if _, err := ParseTemplate("{{.OverallScore"); err == nil {
+		t.Error("unterminated action should be a parse error")
+	}
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	result := &MemoryResult{TotalBytes: 1024, UsedPercent: 50, TotalHuman: "1.0 KB"}
+
+	oneline, err := RenderTemplate(result, "oneline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(oneline, "schema_version="+SchemaVersion+" total_bytes=1024 ") || !strings.Contains(oneline, "used_percent=50") ||
+		!strings.Contains(oneline, `total_human="1.0 KB"`) {
+		t.Errorf("oneline = %q", oneline)
+	}
+
+	csv, err := RenderTemplate(result, "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(csv, "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "schema_version,total_bytes,") || !strings.HasPrefix(lines[1], SchemaVersion+",1024,") {
+		t.Errorf("csv = %q", csv)
+	}
+}
+
+func TestFlattenFields(t *testing.T) {
+	data := map[string]any{
+		"cpu":   map[string]any{"load": map[string]any{"load1": 0.5}},
+		"cores": []any{1, 2},
+		"users": []any{"alice", "bob"},
+		"none":  nil,
+	}
+	fields, err := flattenFields(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, f := range fields {
+		got[f.key] = f.value
+	}
+	want := map[string]string{"cpu.load.load1": "0.5", "cores": "1;2", "users": "alice;bob", "none": ""}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("field %s = %q, want %q", k, got[k], v)
+		}
+	}
+
+	scalar, _ := flattenFields(42)
+	if len(scalar) != 1 || scalar[0].key != "value" || scalar[0].value != "42" {
+		t.Errorf("scalar result = %+v", scalar)
+	}
+}
+
+func TestFormatOutput_Template(t *testing.T) {
+	result := &MemoryResult{UsedPercent: 42.5}
+	if got := FormatMemory(result, TemplateFormat("{{.UsedPercent}}")); got != "42.5" {
+		t.Errorf("FormatMemory with template = %q, want 42.5", got)
+	}
+	if got := FormatMemory(result, TemplateFormat("{{.Bogus}}")); !strings.Contains(got, "Bogus") {
+		t.Errorf("template error should be reported, got %q", got)
+	}
+}
+
+func TestRender_TemplateError(t *testing.T) {
+	result := &MemoryResult{UsedPercent: 42.5}
+	if _, err := Render(result, TemplateFormat("{{.Bogus}}"), FormatMemory); err == nil {
+		t.Error("Render should return the error of a failing template")
+	}
+	if _, err := RenderOutput(result, nil, TemplateFormat("{{.Bogus}}")); err == nil {
+		t.Error("RenderOutput should return the error of a failing template")
+	}
+	got, err := Render(result, TemplateFormat("{{.UsedPercent}}"), FormatMemory)
+	if err != nil || got != "42.5" {
+		t.Errorf("Render with template = %q, %v; want 42.5", got, err)
+	}
+	if got, err := Render(result, FormatTable, FormatMemory); err != nil || got != FormatMemory(result, FormatTable) {
+		t.Errorf("Render of a table = %q, %v; want the FormatMemory table", got, err)
+	}
+}
@@ -0,0 +1,119 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// CheckTimeoutEnv bounds every check of the security summary, e.g.
+	// "10s"; "0" turns the limit off
+	CheckTimeoutEnv = "OMNITRUST_CHECK_TIMEOUT"
+	// CheckTimeoutsEnv overrides the limit per check, e.g.
+	// "encryption=30s,firmware=1m"
+	CheckTimeoutsEnv = "OMNITRUST_CHECK_TIMEOUTS"
+)
+
+// DefaultCheckTimeout bounds each summary check when no timeout is set
+const DefaultCheckTimeout = 10 * time.Second
+
+// CheckTimeout returns how long a summary check may run before it is
+// reported as timed out. Zero means no limit; invalid values fall back to
+// the default.
+func CheckTimeout(id string) time.Duration {
+	id = normalizeCheckID(id)
+	for _, entry := range strings.FieldsFunc(os.Getenv(CheckTimeoutsEnv), func(r rune) bool { return r == ',' || r == ' ' }) {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || normalizeCheckID(key) != id {
+			continue
+		}
+		if d, err := time.ParseDuration(strings.TrimSpace(value)); err == nil && d >= 0 {
+			return d
+		}
+	}
+	if v := strings.TrimSpace(os.Getenv(CheckTimeoutEnv)); v != "" {
+		if v == "0" {
+			return 0
+		}
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return DefaultCheckTimeout
+}
+
+// CheckTimeoutError returns the error reported when a check runs past its
+// timeout
+func CheckTimeoutError(id string, timeout time.Duration) *ProbeError {
+	return probeError(ErrTimeout, id, "check timed out after "+timeout.String())
+}
+
+// runCheck runs a summary check under its configured timeout and records
+// its cost. A probe that is still running when the timeout expires is
+// abandoned: its context is canceled, which kills the commands it runs, its
+// result is discarded, and a timeout error returned, so one slow probe (a
+// LUKS device scan, a WMI query) cannot hold up the summary. A check already
+// started by a parallel scan is waited for instead.
+func runCheck[T any](ctx context.Context, rec *scanRecorder, id string, probe func(context.Context) (T, error)) (T, error) {
+	var result T
+	var err error
+	if p := rec.pending[id]; p != nil {
+		<-p.done
+		result, _ = p.result.(T)
+		err = p.err
+	} else {
+		rec.track(ctx, id, func(ctx context.Context) error {
+			result, err = withTimeout(ctx, CheckTimeout(id), id, probe)
+			return err
+		})
+	}
+	if errors.Is(err, ErrTimeout) {
+		rec.timedOut(id)
+	}
+	return result, err
+}
+
+// withTimeout runs probe, giving up once timeout has passed or ctx is done.
+// The probe's context is canceled when withTimeout returns, so the commands
+// an abandoned probe runs are killed and its goroutine can finish.
+func withTimeout[T any](ctx context.Context, timeout time.Duration, id string, probe func(context.Context) (T, error)) (T, error) {
+	if timeout <= 0 {
+		return probe(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		result T
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := probe(ctx)
+		done <- outcome{result, err}
+	}()
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-ctx.Done():
+		var zero T
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return zero, ctx.Err()
+		}
+		return zero, CheckTimeoutError(id, timeout)
+	}
+}
+
+// timeoutFinding reports a check that did not finish in time
+func timeoutFinding(id string) Finding {
+	return Finding{
+		ID:          id + "_timeout",
+		Title:       T("The %s check timed out after %s", id, CheckTimeout(id)),
+		Severity:    SeverityMedium,
+		Check:       id,
+		Remediation: T("Raise its timeout with %s, e.g. %s=%s", CheckTimeoutsEnv, id, "30s"),
+	}
+}
@@ -0,0 +1,92 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCheckTimeout(t *testing.T) {
+	t.Setenv(CheckTimeoutEnv, "")
+	t.Setenv(CheckTimeoutsEnv, "")
+	if got := CheckTimeout(CheckEncryption); got != DefaultCheckTimeout {
+		t.Errorf("default = %v, want %v", got, DefaultCheckTimeout)
+	}
+
+	t.Setenv(CheckTimeoutEnv, "3s")
+	t.Setenv(CheckTimeoutsEnv, "encryption=30s, Secure-Boot=0s, tpm=bogus")
+	tests := map[string]time.Duration{
+		CheckEncryption: 30 * time.Second,
+		CheckSecureBoot: 0,
+		CheckTPM:        3 * time.Second, // invalid override falls back
+		CheckFirmware:   3 * time.Second,
+	}
+	for id, want := range tests {
+		if got := CheckTimeout(id); got != want {
+			t.Errorf("CheckTimeout(%s) = %v, want %v", id, got, want)
+		}
+	}
+
+	t.Setenv(CheckTimeoutEnv, "0")
+	if got := CheckTimeout(CheckFirmware); got != 0 {
+		t.Errorf("CheckTimeout with %s=0 = %v, want no limit", CheckTimeoutEnv, got)
+	}
+}
+
+func TestRunCheck_Timeout(t *testing.T) {
+	t.Setenv(CheckTimeoutsEnv, "encryption=20ms")
+	release := make(chan struct{})
+	defer close(release)
+
+	ctx, rec := newScanRecorder(context.Background())
+	start := time.Now()
+	canceled := make(chan struct{})
+	result, err := runCheck(ctx, rec, CheckEncryption, func(ctx context.Context) (*EncryptionResult, error) {
+		select {
+		case <-ctx.Done():
+			close(canceled)
+		case <-release:
+		}
+		return &EncryptionResult{}, nil
+	})
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("runCheck waited %v for a hung probe", elapsed)
+	}
+	var pe *ProbeError
+	if result != nil || !errors.Is(err, ErrTimeout) || !errors.As(err, &pe) || pe.Code != CodeTimeout || pe.Hint == "" {
+		t.Fatalf("runCheck = %v, %v, want a timeout error with a hint", result, err)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("the abandoned probe's context was not canceled")
+	}
+	if _, err := runCheck(ctx, rec, CheckTPM, func(context.Context) (*TPMResult, error) { return &TPMResult{Present: true}, nil }); err != nil {
+		t.Errorf("fast check: %v", err)
+	}
+
+	stats := rec.finish()
+	if !slices.Equal(rec.timeouts, []string{CheckEncryption}) {
+		t.Errorf("timeouts = %v, want [encryption]", rec.timeouts)
+	}
+	if len(stats.Checks) != 2 || !stats.Checks[0].TimedOut || stats.Checks[1].TimedOut {
+		t.Errorf("Checks = %+v, want only encryption timed out", stats.Checks)
+	}
+}
+
+func TestCompareBaseline_Timeout(t *testing.T) {
+	baseline := NewBaseline(&SecuritySummary{
+		CheckResults: map[string]string{CheckTPM: CheckResultPass, CheckEncryption: CheckResultTimeout},
+	})
+	if _, ok := baseline.Checks[CheckEncryption]; ok {
+		t.Errorf("baseline recorded a timed-out check: %v", baseline.Checks)
+	}
+	delta := CompareBaseline(baseline, &SecuritySummary{
+		CheckResults: map[string]string{CheckTPM: CheckResultTimeout},
+	})
+	if len(delta.Checks) != 1 || delta.Checks[0].Change != DeltaUnverified || len(delta.Regressed) != 0 {
+		t.Errorf("delta = %+v, want tpm unverified", delta)
+	}
+}
@@ -82,25 +82,22 @@ const char* tpm_getPlatformType() {
 }
 */
 import "C"
-import (
-	"fmt"
-	"strings"
-)
+import "context"
 
 // TPMResult contains TPM/Secure Enclave status information
-type TPMResult struct {
-	Present            bool     `json:"present"`
-	Enabled            bool     `json:"enabled"`
-	Version            string   `json:"version"`
-	Manufacturer       string   `json:"manufacturer"`
-	Type               string   `json:"type"`
-	Platform           string   `json:"platform"`
-	Capabilities       []string `json:"capabilities"`
-	HardwareKeySupport bool     `json:"hardware_key_support"`
-}
+type TPMResult = DarwinTPMResult
 
 // GetTPMStatus returns the TPM/Secure Enclave status (macOS)
 func GetTPMStatus() (*TPMResult, error) {
+	return GetTPMStatusContext(context.Background())
+}
+
+// GetTPMStatusContext is like GetTPMStatus but runs the probes with ctx's
+// deadline, command runner, and logger
+func GetTPMStatusContext(ctx context.Context) (*TPMResult, error) {
+	if result, ok, err := loadFixture[TPMResult](ctx, "tpm"); ok {
+		return result, err
+	}
 	seAvailable := C.tpm_testSecureEnclaveAvailable() == 1
 	platform := C.GoString(C.tpm_getPlatformType())
 
@@ -128,104 +125,37 @@ func GetTPMStatus() (*TPMResult, error) {
 		}
 	}
 
-	return &TPMResult{
+	result := &TPMResult{
 		Present:            seAvailable,
 		Enabled:            seAvailable,
 		Version:            version,
 		Manufacturer:       "Apple",
+		ManufacturerName:   "Apple",
 		Type:               tpmType,
 		Platform:           platform,
 		Capabilities:       capabilities,
 		HardwareKeySupport: seAvailable,
-	}, nil
+	}
+	if seAvailable {
+		result.Kind = TPMKindSecureEnclave
+	}
+	return result, nil
 }
 
 // FormatTPMTable formats TPM status as a colored table
 func FormatTPMTable(result *TPMResult) string {
-	var sb strings.Builder
-	sb.WriteString("\n")
-	sb.WriteString(Header(IconShield + " TPM / Secure Enclave Status"))
-	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 55)))
-	sb.WriteString("\n\n")
-
-	// Platform badge
-	var platformIcon string
-	if result.Platform == "apple_silicon" {
-		platformIcon = IconApple + " Apple Silicon"
-	} else {
-		platformIcon = IconChip + " Intel (T2)"
-	}
-	sb.WriteString(BoldText("Platform: "))
-	sb.WriteString(Info(platformIcon))
-	sb.WriteString("\n\n")
-
-	// Status table
-	sb.WriteString(TableTop(28, 22))
-	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Property", 28)),
-		Header(PadRight("Value", 22)),
-	))
-	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(28, 22))
-	sb.WriteString("\n")
-
-	// Present
-	sb.WriteString(TableRowColored(
-		PadRight(IconShield+" TPM/SE Present", 28),
-		PadRight(BoolToStatusColored(result.Present), 22),
-	))
-	sb.WriteString("\n")
-
-	// Enabled
-	sb.WriteString(TableRowColored(
-		PadRight(IconCheck+" Enabled", 28),
-		PadRight(BoolToStatusColored(result.Enabled), 22),
-	))
-	sb.WriteString("\n")
-
-	// Version
-	sb.WriteString(TableRowColored(
-		PadRight(IconInfo+" Version", 28),
-		PadRight(Info(result.Version), 22),
-	))
-	sb.WriteString("\n")
-
-	// Manufacturer
-	sb.WriteString(TableRowColored(
-		PadRight(IconDiamond+" Manufacturer", 28),
-		PadRight(result.Manufacturer, 22),
-	))
-	sb.WriteString("\n")
-
-	// Hardware Key Support
-	sb.WriteString(TableRowColored(
-		PadRight(IconKey+" Hardware Key Support", 28),
-		PadRight(BoolToStatusColored(result.HardwareKeySupport), 22),
-	))
-	sb.WriteString("\n")
-
-	sb.WriteString(TableBottom(28, 22))
-	sb.WriteString("\n\n")
-
-	// Capabilities section
-	sb.WriteString(BoldText("Capabilities:"))
-	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 35)))
-	sb.WriteString("\n")
-	for _, cap := range result.Capabilities {
-		sb.WriteString(fmt.Sprintf("  %s %s\n", Success(IconCheck), cap))
-	}
-	sb.WriteString("\n")
+	return FormatTPMTableWith(CurrentStyler(), result)
+}
 
-	return sb.String()
+// FormatTPMTableWith is like FormatTPMTable but styles the table with st
+func FormatTPMTableWith(st Styler, result *TPMResult) string {
+	return formatDarwinTPMTable(st, result)
 }
 
 // FormatTPM formats TPM status in the specified format
 func FormatTPM(result *TPMResult, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatTPMTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatTPMTableWith(st, result)
 	}, format)
 }
 
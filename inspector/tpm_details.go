@@ -0,0 +1,390 @@
+package inspector
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TPMLockoutState describes the TPM dictionary-attack lockout state
+type TPMLockoutState struct {
+	InLockout       bool   `json:"in_lockout"`
+	FailedTries     uint32 `json:"failed_tries"`
+	MaxTries        uint32 `json:"max_tries"`
+	IntervalSeconds uint32 `json:"interval_seconds"`
+	RecoverySeconds uint32 `json:"recovery_seconds"`
+}
+
+// TPMAuthState describes which TPM hierarchies are enabled and have an
+// authorization value set (TPM_PT_PERMANENT and TPM_PT_STARTUP_CLEAR)
+type TPMAuthState struct {
+	// OwnerAuthSet is true once the TPM has been provisioned with an owner
+	// (storage hierarchy) authorization value
+	OwnerAuthSet       bool `json:"owner_auth_set"`
+	EndorsementAuthSet bool `json:"endorsement_auth_set"`
+	LockoutAuthSet     bool `json:"lockout_auth_set"`
+	// ClearDisabled is true if TPM2_Clear is blocked
+	ClearDisabled      bool `json:"clear_disabled"`
+	StorageEnabled     bool `json:"storage_hierarchy_enabled"`
+	EndorsementEnabled bool `json:"endorsement_hierarchy_enabled"`
+}
+
+// EKCertificate summarizes the TPM endorsement key certificate
+type EKCertificate struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serial_number"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	KeyAlgorithm string    `json:"key_algorithm"`
+	// Manufacturer is the TCG vendor ID from the certificate's subject
+	// alternative name (e.g. IFX), and ManufacturerName the vendor it
+	// stands for (e.g. Infineon)
+	Manufacturer     string `json:"manufacturer,omitempty"`
+	ManufacturerName string `json:"manufacturer_name,omitempty"`
+	Model            string `json:"model,omitempty"`
+	// Version is the TPM firmware version the certificate was issued for
+	Version string `json:"version,omitempty"`
+	PEM     string `json:"pem"`
+	// ChainVerified reports whether the certificate chains to a trusted TPM manufacturer CA
+	ChainVerified bool   `json:"chain_verified"`
+	ChainRoot     string `json:"chain_root,omitempty"`
+	ChainError    string `json:"chain_error,omitempty"`
+	// ChainSkipped is true if verification was turned off with
+	// OMNITRUST_TPM_VERIFY_EK
+	ChainSkipped bool `json:"chain_skipped,omitempty"`
+	// ChainNoRoot is true if neither the bundled CAs nor those in
+	// OMNITRUST_TPM_CA_DIR cover the issuer, so the chain is unverified
+	// rather than failed
+	ChainNoRoot bool `json:"chain_no_root,omitempty"`
+}
+
+// TCG attribute OIDs carried in the EK certificate's directoryName SAN
+// (TCG EK Credential Profile, section 3.1.2)
+var (
+	oidTPMManufacturer = asn1.ObjectIdentifier{2, 23, 133, 2, 1}
+	oidTPMModel        = asn1.ObjectIdentifier{2, 23, 133, 2, 2}
+	oidTPMVersion      = asn1.ObjectIdentifier{2, 23, 133, 2, 3}
+)
+
+// TPM_PT_PERMANENT attribute bits (TPM 2.0 Part 2, section 8.6)
+const (
+	tpmPermanentOwnerAuthSet       = 1 << 0
+	tpmPermanentEndorsementAuthSet = 1 << 1
+	tpmPermanentLockoutAuthSet     = 1 << 2
+	tpmPermanentDisableClear       = 1 << 8
+	tpmPermanentInLockout          = 1 << 9
+)
+
+// TPM_PT_STARTUP_CLEAR attribute bits (TPM 2.0 Part 2, section 8.7)
+const (
+	tpmStartupClearSHEnable = 1 << 1
+	tpmStartupClearEHEnable = 1 << 2
+)
+
+// tpmAuthState decodes the TPM_PT_PERMANENT and TPM_PT_STARTUP_CLEAR properties
+func tpmAuthState(permanent, startupClear uint32) *TPMAuthState {
+	return &TPMAuthState{
+		OwnerAuthSet:       permanent&tpmPermanentOwnerAuthSet != 0,
+		EndorsementAuthSet: permanent&tpmPermanentEndorsementAuthSet != 0,
+		LockoutAuthSet:     permanent&tpmPermanentLockoutAuthSet != 0,
+		ClearDisabled:      permanent&tpmPermanentDisableClear != 0,
+		StorageEnabled:     startupClear&tpmStartupClearSHEnable != 0,
+		EndorsementEnabled: startupClear&tpmStartupClearEHEnable != 0,
+	}
+}
+
+// tpmNotOwnedReason is the not-ready reason for a TPM that is not owned
+const tpmNotOwnedReason = "TPM not owned (provisioning incomplete)"
+
+// tpmNotReadyReasons lists why a TPM is not ready for use, in the spirit of
+// Get-Tpm's TpmReady: it must be present, enabled, activated, and owned, not
+// locked out, and have its storage and endorsement hierarchies enabled
+func tpmNotReadyReasons(present, enabled, activated, owned bool, lockout *TPMLockoutState, auth *TPMAuthState) []string {
+	if !present {
+		return []string{"TPM not present"}
+	}
+	var reasons []string
+	if !enabled {
+		reasons = append(reasons, "TPM disabled")
+	}
+	if !activated {
+		reasons = append(reasons, "TPM not activated")
+	}
+	if !owned {
+		reasons = append(reasons, tpmNotOwnedReason)
+	}
+	if lockout != nil && lockout.InLockout {
+		reasons = append(reasons, "TPM in dictionary attack lockout")
+	}
+	if auth != nil {
+		if !auth.StorageEnabled {
+			reasons = append(reasons, "storage hierarchy disabled")
+		}
+		if !auth.EndorsementEnabled {
+			reasons = append(reasons, "endorsement hierarchy disabled")
+		}
+	}
+	return reasons
+}
+
+// tpmAttestationCapable reports whether the TPM can attest keys: it must be
+// an enabled TPM 2.0 whose endorsement and storage hierarchies are enabled,
+// since attestation keys are certified against the endorsement key
+func tpmAttestationCapable(tpmType string, enabled bool, auth *TPMAuthState) bool {
+	return tpmType == "tpm_2.0" && enabled && auth != nil && auth.StorageEnabled && auth.EndorsementEnabled
+}
+
+// tpmAlgorithmNames maps TPM_ALG_ID values to their canonical names
+var tpmAlgorithmNames = map[uint16]string{
+	0x0001: "rsa",
+	0x0003: "tdes",
+	0x0004: "sha1",
+	0x0005: "hmac",
+	0x0006: "aes",
+	0x0007: "mgf1",
+	0x0008: "keyedhash",
+	0x000A: "xor",
+	0x000B: "sha256",
+	0x000C: "sha384",
+	0x000D: "sha512",
+	0x0010: "null",
+	0x0012: "sm3_256",
+	0x0013: "sm4",
+	0x0014: "rsassa",
+	0x0015: "rsaes",
+	0x0016: "rsapss",
+	0x0017: "oaep",
+	0x0018: "ecdsa",
+	0x0019: "ecdh",
+	0x001A: "ecdaa",
+	0x001B: "sm2",
+	0x001C: "ecschnorr",
+	0x001D: "ecmqv",
+	0x0020: "kdf1_sp800_56a",
+	0x0021: "kdf2",
+	0x0022: "kdf1_sp800_108",
+	0x0023: "ecc",
+	0x0025: "symcipher",
+	0x0026: "camellia",
+	0x0027: "sha3_256",
+	0x0028: "sha3_384",
+	0x0029: "sha3_512",
+	0x0040: "ctr",
+	0x0041: "ofb",
+	0x0042: "cbc",
+	0x0043: "cfb",
+	0x0044: "ecb",
+}
+
+// tpmAlgorithmName returns the canonical name of a TPM algorithm ID
+func tpmAlgorithmName(id uint16) string {
+	if name, ok := tpmAlgorithmNames[id]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%04x", id)
+}
+
+// tpmVendorString decodes a TPM_PT_MANUFACTURER value (four ASCII characters)
+func tpmVendorString(v uint32) string {
+	b := []byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+	return strings.TrimSpace(strings.TrimRight(string(b), "\x00"))
+}
+
+// tpmFirmwareVersion formats TPM_PT_FIRMWARE_VERSION_1/2 as a dotted version
+func tpmFirmwareVersion(v1, v2 uint32) string {
+	return fmt.Sprintf("%d.%d.%d.%d", v1>>16, v1&0xffff, v2>>16, v2&0xffff)
+}
+
+// parseEKCertificate parses a DER-encoded EK certificate as read from TPM NV.
+// NV indices are often padded past the end of the certificate, so any
+// trailing bytes after the outer ASN.1 SEQUENCE are ignored.
+func parseEKCertificate(der []byte) (*EKCertificate, error) {
+	var raw asn1.RawValue
+	rest, err := asn1.Unmarshal(der, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode EK certificate: %w", err)
+	}
+	der = der[:len(der)-len(rest)]
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse EK certificate: %w", err)
+	}
+	if cert == nil {
+		return nil, errors.New("empty EK certificate")
+	}
+
+	ek := &EKCertificate{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.String(),
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		KeyAlgorithm: strings.ToLower(cert.PublicKeyAlgorithm.String()),
+		PEM:          string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+	parseEKSubjectAltName(cert, ek)
+
+	if !tpmVerifyEKEnabled() {
+		ek.ChainSkipped = true
+		return ek, nil
+	}
+	roots, intermediates := tpmCAPools()
+	root, err := verifyEKChain(cert, roots, intermediates)
+	switch {
+	case errors.Is(err, errNoEKRoot):
+		ek.ChainNoRoot = true
+	case err != nil:
+		ek.ChainError = err.Error()
+	default:
+		ek.ChainVerified = true
+		ek.ChainRoot = root
+	}
+
+	return ek, nil
+}
+
+// parseEKSubjectAltName reads the TPM manufacturer, model, and version from
+// the directoryName in the EK certificate's subject alternative name. Some
+// manufacturers put the attributes in the subject instead, so that is used
+// as a fallback.
+func parseEKSubjectAltName(cert *x509.Certificate, ek *EKCertificate) {
+	attrs := cert.Subject.Names
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidSubjectAltName) {
+			continue
+		}
+		var names []asn1.RawValue
+		if _, err := asn1.Unmarshal(ext.Value, &names); err != nil {
+			return
+		}
+		for _, name := range names {
+			// directoryName [4] is explicitly tagged
+			if name.Class != asn1.ClassContextSpecific || name.Tag != 4 {
+				continue
+			}
+			var rdns pkix.RDNSequence
+			if _, err := asn1.Unmarshal(name.Bytes, &rdns); err != nil {
+				continue
+			}
+			for _, rdn := range rdns {
+				attrs = append(attrs, rdn...)
+			}
+		}
+	}
+
+	for _, attr := range attrs {
+		value, ok := attr.Value.(string)
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case attr.Type.Equal(oidTPMManufacturer) && ek.Manufacturer == "":
+			ek.Manufacturer = decodeTPMVendorID(value)
+			ek.ManufacturerName = tpmVendorName(ek.Manufacturer)
+		case attr.Type.Equal(oidTPMModel) && ek.Model == "":
+			ek.Model = value
+		case attr.Type.Equal(oidTPMVersion) && ek.Version == "":
+			ek.Version = strings.TrimPrefix(value, "id:")
+		}
+	}
+}
+
+// formatTPMDetailsSection renders the go-tpm derived details shared by the
+// Linux and Windows TPM tables
+func formatTPMDetailsSection(st Styler, algorithms []string, lockout *TPMLockoutState, auth *TPMAuthState, ek *EKCertificate) string {
+	var sb strings.Builder
+
+	if lockout != nil {
+		sb.WriteString(st.BoldText(T("Dictionary Attack Lockout:")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(strings.Repeat("─", 35)))
+		sb.WriteString("\n")
+		if lockout.InLockout {
+			sb.WriteString("  " + st.Danger(IconCross+" "+T("TPM is in lockout")))
+		} else {
+			sb.WriteString("  " + st.Success(IconCheck+" "+T("Not in lockout")))
+		}
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("  %s %d / %d\n", PadRight(T("Failed attempts:"), 16), lockout.FailedTries, lockout.MaxTries))
+		sb.WriteString(fmt.Sprintf("  %s %ds\n", PadRight(T("Recovery time:"), 16), lockout.RecoverySeconds))
+		sb.WriteString("\n")
+	}
+
+	if auth != nil {
+		// Whether auth values are set is informational; disabled hierarchies are not
+		yesNo := func(b bool) string {
+			if b {
+				return T("Yes")
+			}
+			return T("No")
+		}
+		sb.WriteString(st.BoldText(T("Hierarchies:")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(strings.Repeat("─", 35)))
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("  %s %s\n", PadRight(T("Storage enabled:"), 20), st.BoolToStatusColored(auth.StorageEnabled)))
+		sb.WriteString(fmt.Sprintf("  %s %s\n", PadRight(T("Endorsement enabled:"), 20), st.BoolToStatusColored(auth.EndorsementEnabled)))
+		sb.WriteString(fmt.Sprintf("  %s %s\n", PadRight(T("Owner auth set:"), 20), yesNo(auth.OwnerAuthSet)))
+		sb.WriteString(fmt.Sprintf("  %s %s\n", PadRight(T("Lockout auth set:"), 20), yesNo(auth.LockoutAuthSet)))
+		sb.WriteString(fmt.Sprintf("  %s %s\n", PadRight(T("Clear disabled:"), 20), yesNo(auth.ClearDisabled)))
+		sb.WriteString("\n")
+	}
+
+	if len(algorithms) > 0 {
+		sb.WriteString(st.BoldText(T("Algorithms:")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(strings.Repeat("─", 35)))
+		sb.WriteString("\n")
+		sb.WriteString("  " + strings.Join(algorithms, ", "))
+		sb.WriteString("\n\n")
+	}
+
+	if ek != nil {
+		sb.WriteString(st.BoldText(IconKey + " " + T("Endorsement Key Certificate:")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(strings.Repeat("─", 35)))
+		sb.WriteString("\n")
+		if ek.Manufacturer != "" {
+			vendor := ek.ManufacturerName
+			if vendor != ek.Manufacturer {
+				vendor += " (" + ek.Manufacturer + ")"
+			}
+			if ek.Model != "" {
+				vendor += ", " + ek.Model
+			}
+			if ek.Version != "" {
+				vendor += ", " + T("version %s", ek.Version)
+			}
+			sb.WriteString(fmt.Sprintf("  %s %s\n", PadRight("TPM:", 10), vendor))
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s\n", PadRight(T("Issuer:"), 10), ek.Issuer))
+		sb.WriteString(fmt.Sprintf("  %s %s\n", PadRight(T("Serial:"), 10), ek.SerialNumber))
+		sb.WriteString(fmt.Sprintf("  %s %s\n", PadRight(T("Algorithm:"), 10), ek.KeyAlgorithm))
+		sb.WriteString(fmt.Sprintf("  %s %s - %s\n", PadRight(T("Valid:"), 10),
+			ek.NotBefore.Format("2006-01-02"), ek.NotAfter.Format("2006-01-02")))
+		chain := "  " + PadRight(T("Chain:"), 10) + " "
+		if ek.ChainSkipped {
+			sb.WriteString(chain + st.Muted(T("Not checked")))
+		} else if ek.ChainVerified {
+			sb.WriteString(chain + st.Success(IconCheck+" "+T("Verified")) + st.Muted(" ("+ek.ChainRoot+")"))
+		} else if ek.ChainNoRoot {
+			sb.WriteString(chain + st.Warning(T("Not verified")) + st.Muted(" ("+T("no bundled root for this manufacturer")+")"))
+		} else {
+			sb.WriteString(chain + st.Danger(IconCross+" "+T("Failed")))
+			if ek.ChainError != "" {
+				sb.WriteString(st.Muted(" (" + ek.ChainError + ")"))
+			}
+		}
+		sb.WriteString("\n\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,204 @@
+package inspector
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCertificateDER creates a self-signed certificate for parser tests
+func testCertificateDER(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(4242),
+		Subject:      pkix.Name{CommonName: "Test EK"},
+		Issuer:       pkix.Name{CommonName: "Test EK"},
+		NotBefore:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+	return der
+}
+
+func TestTPMVendorString(t *testing.T) {
+	tests := []struct {
+		value    uint32
+		expected string
+	}{
+		{0x49465800, "IFX"},
+		{0x494E5443, "INTC"},
+		{0x53544D20, "STM"},
+		{0, ""},
+	}
+
+	for _, tt := range tests {
+		if got := tpmVendorString(tt.value); got != tt.expected {
+			t.Errorf("tpmVendorString(0x%08x) = %q, want %q", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestTPMFirmwareVersion(t *testing.T) {
+	got := tpmFirmwareVersion(0x0007003F, 0x00060000)
+	if got != "7.63.6.0" {
+		t.Errorf("tpmFirmwareVersion = %q, want %q", got, "7.63.6.0")
+	}
+}
+
+func TestTPMAlgorithmName(t *testing.T) {
+	if got := tpmAlgorithmName(0x000B); got != "sha256" {
+		t.Errorf("tpmAlgorithmName(0x000B) = %q, want sha256", got)
+	}
+	if got := tpmAlgorithmName(0x7FFF); got != "0x7fff" {
+		t.Errorf("tpmAlgorithmName(0x7FFF) = %q, want 0x7fff", got)
+	}
+}
+
+func TestTPMAuthState(t *testing.T) {
+	auth := tpmAuthState(0x00000205, 0x80000007)
+	if !auth.OwnerAuthSet || auth.EndorsementAuthSet || !auth.LockoutAuthSet || auth.ClearDisabled ||
+		!auth.StorageEnabled || !auth.EndorsementEnabled {
+		t.Errorf("tpmAuthState = %+v", auth)
+	}
+	if !tpmAttestationCapable("tpm_2.0", true, auth) {
+		t.Error("an enabled TPM 2.0 with both hierarchies should be attestation capable")
+	}
+
+	auth = tpmAuthState(1<<8, 1<<1)
+	if !auth.ClearDisabled || auth.EndorsementEnabled {
+		t.Errorf("tpmAuthState = %+v", auth)
+	}
+	if tpmAttestationCapable("tpm_2.0", true, auth) || tpmAttestationCapable("tpm_1.2", true, nil) {
+		t.Error("a disabled endorsement hierarchy or TPM 1.2 should not be attestation capable")
+	}
+}
+
+func TestTPMNotReadyReasons(t *testing.T) {
+	auth := &TPMAuthState{StorageEnabled: true, EndorsementEnabled: true}
+	if reasons := tpmNotReadyReasons(true, true, true, true, &TPMLockoutState{}, auth); len(reasons) != 0 {
+		t.Errorf("reasons = %q, want ready", reasons)
+	}
+	reasons := tpmNotReadyReasons(true, true, true, false, &TPMLockoutState{InLockout: true}, &TPMAuthState{StorageEnabled: true})
+	want := []string{tpmNotOwnedReason, "TPM in dictionary attack lockout", "endorsement hierarchy disabled"}
+	if !slices.Equal(reasons, want) {
+		t.Errorf("reasons = %q, want %q", reasons, want)
+	}
+	if reasons := tpmNotReadyReasons(false, false, false, false, nil, nil); !slices.Equal(reasons, []string{"TPM not present"}) {
+		t.Errorf("reasons = %q, want only not present", reasons)
+	}
+}
+
+func TestParseEKCertificate(t *testing.T) {
+	der := testCertificateDER(t)
+
+	// NV indices are commonly padded beyond the certificate length
+	padded := append(append([]byte{}, der...), make([]byte, 64)...)
+
+	ek, err := parseEKCertificate(padded)
+	if err != nil {
+		t.Fatalf("parseEKCertificate failed: %v", err)
+	}
+	if !strings.Contains(ek.Subject, "Test EK") {
+		t.Errorf("Subject = %q, want it to contain 'Test EK'", ek.Subject)
+	}
+	if ek.SerialNumber != "4242" {
+		t.Errorf("SerialNumber = %q, want 4242", ek.SerialNumber)
+	}
+	if ek.KeyAlgorithm != "ecdsa" {
+		t.Errorf("KeyAlgorithm = %q, want ecdsa", ek.KeyAlgorithm)
+	}
+	if !strings.HasPrefix(ek.PEM, "-----BEGIN CERTIFICATE-----") {
+		t.Error("PEM should be a PEM-encoded certificate")
+	}
+}
+
+func TestParseEKCertificate_Invalid(t *testing.T) {
+	if _, err := parseEKCertificate([]byte{0xff, 0xff, 0xff}); err == nil {
+		t.Error("expected error for garbage input")
+	}
+	if _, err := parseEKCertificate(nil); err == nil {
+		t.Error("expected error for empty input")
+	}
+}
+
+func TestParseEKCertificate_Manufacturer(t *testing.T) {
+	_, leaf := testEKChain(t)
+	ek, err := parseEKCertificate(leaf.Raw)
+	if err != nil {
+		t.Fatalf("parseEKCertificate failed: %v", err)
+	}
+	if ek.Manufacturer != "INTC" || ek.ManufacturerName != "Intel" || ek.Model != "PTT" || ek.Version != "000B0002" {
+		t.Errorf("manufacturer = %q (%q), model %q, version %q", ek.Manufacturer, ek.ManufacturerName, ek.Model, ek.Version)
+	}
+	// No bundled root covers the test CA, which leaves the chain unverified
+	if ek.ChainVerified || ek.ChainSkipped || !ek.ChainNoRoot || ek.ChainError != "" {
+		t.Errorf("chain verified %v, skipped %v, no root %v, error %q, want no root", ek.ChainVerified, ek.ChainSkipped, ek.ChainNoRoot, ek.ChainError)
+	}
+
+	t.Setenv(TPMVerifyEKEnv, "false")
+	ek, err = parseEKCertificate(leaf.Raw)
+	if err != nil {
+		t.Fatalf("parseEKCertificate failed: %v", err)
+	}
+	if !ek.ChainSkipped || ek.ChainVerified || ek.ChainError != "" {
+		t.Errorf("chain verified %v, skipped %v, error %q, want skipped", ek.ChainVerified, ek.ChainSkipped, ek.ChainError)
+	}
+}
+
+func TestDecodeTPMVendorID(t *testing.T) {
+	tests := map[string]string{
+		"id:49465800": "IFX",
+		"id:53544D20": "STM",
+		"id:zz":       "zz",
+		"NTC":         "NTC",
+	}
+	for value, want := range tests {
+		if got := decodeTPMVendorID(value); got != want {
+			t.Errorf("decodeTPMVendorID(%q) = %q, want %q", value, got, want)
+		}
+	}
+	if got := tpmVendorName("stm"); got != "STMicroelectronics" {
+		t.Errorf("tpmVendorName(stm) = %q", got)
+	}
+}
+
+func TestFormatTPMDetailsSection(t *testing.T) {
+	if got := formatTPMDetailsSection(CurrentStyler(), nil, nil, nil, nil); got != "" {
+		t.Errorf("empty details should render nothing, got %q", got)
+	}
+
+	output := StripANSI(formatTPMDetailsSection(CurrentStyler(),
+		[]string{"rsa", "sha256"},
+		&TPMLockoutState{InLockout: true, FailedTries: 3, MaxTries: 32},
+		&TPMAuthState{StorageEnabled: true, LockoutAuthSet: true},
+		&EKCertificate{Issuer: "CN=Vendor CA", SerialNumber: "1", Manufacturer: "IFX", ManufacturerName: "Infineon", Model: "SLB9670"},
+	))
+
+	for _, want := range []string{"rsa, sha256", "TPM is in lockout", "3 / 32", "CN=Vendor CA", "Infineon (IFX), SLB9670", "Lockout auth set:    Yes"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q", want)
+		}
+	}
+	noRoot := StripANSI(formatTPMDetailsSection(CurrentStyler(), nil, nil, nil, &EKCertificate{ChainNoRoot: true}))
+	if !strings.Contains(noRoot, "Not verified (no bundled root for this manufacturer)") {
+		t.Errorf("chain without a root = %q, want not verified", noRoot)
+	}
+	failed := StripANSI(formatTPMDetailsSection(CurrentStyler(), nil, nil, nil, &EKCertificate{ChainError: "certificate has expired"}))
+	if !strings.Contains(failed, IconCross+" Failed (certificate has expired)") {
+		t.Errorf("failed chain = %q, want failed", failed)
+	}
+}
@@ -0,0 +1,172 @@
+//go:build linux || windows
+
+package inspector
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/go-tpm/tpm2"
+	"github.com/google/go-tpm/tpm2/transport"
+)
+
+// NV indices holding the EK certificates (TCG EK Credential Profile, section 2.2.1.4)
+const (
+	ekCertNVIndexRSA = 0x01C00002
+	ekCertNVIndexECC = 0x01C0000A
+)
+
+// defaultNVBufferMax is used when the TPM does not report TPM_PT_NV_BUFFER_MAX
+const defaultNVBufferMax = 512
+
+// tpmDetails contains properties read directly from a TPM 2.0 device
+type tpmDetails struct {
+	Manufacturer    string
+	FirmwareVersion string
+	Algorithms      []string
+	Lockout         *TPMLockoutState
+	Auth            *TPMAuthState
+	EKCertificate   *EKCertificate
+}
+
+// readTPMDetails queries TPM properties, algorithms, and the EK certificate.
+// Property reads must succeed; the EK certificate is optional.
+func readTPMDetails(t transport.TPM) (*tpmDetails, error) {
+	props, err := readTPMProperties(t)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &tpmDetails{
+		Manufacturer:    tpmVendorString(props[tpm2.TPMPTManufacturer]),
+		FirmwareVersion: tpmFirmwareVersion(props[tpm2.TPMPTFirmwareVersion1], props[tpm2.TPMPTFirmwareVersion2]),
+		Lockout: &TPMLockoutState{
+			InLockout:       props[tpm2.TPMPTPermanent]&tpmPermanentInLockout != 0,
+			FailedTries:     props[tpm2.TPMPTLockoutCounter],
+			MaxTries:        props[tpm2.TPMPTMaxAuthFail],
+			IntervalSeconds: props[tpm2.TPMPTLockoutInterval],
+			RecoverySeconds: props[tpm2.TPMPTLockoutRecovery],
+		},
+		Auth: tpmAuthState(props[tpm2.TPMPTPermanent], props[tpm2.TPMPTStartupClear]),
+	}
+
+	if algs, err := readTPMAlgorithms(t); err == nil {
+		details.Algorithms = algs
+	}
+
+	bufMax := props[tpm2.TPMPTNVBufferMax]
+	if bufMax == 0 {
+		bufMax = defaultNVBufferMax
+	}
+	for _, idx := range []uint32{ekCertNVIndexRSA, ekCertNVIndexECC} {
+		der, err := readTPMNV(t, idx, uint16(bufMax)) // #nosec G115 -- TPM_PT_NV_BUFFER_MAX fits in uint16
+		if err != nil {
+			continue
+		}
+		if ek, err := parseEKCertificate(der); err == nil {
+			details.EKCertificate = ek
+			break
+		}
+	}
+
+	return details, nil
+}
+
+// applyTPMDetails merges properties read via go-tpm into the result
+func applyTPMDetails(result *TPMResult, details *tpmDetails) {
+	if details.Manufacturer != "" {
+		result.Manufacturer = details.Manufacturer
+	}
+	result.FirmwareVersion = details.FirmwareVersion
+	result.Algorithms = details.Algorithms
+	result.Lockout = details.Lockout
+	result.Auth = details.Auth
+	result.EKCertificate = details.EKCertificate
+}
+
+// readTPMProperties returns the fixed and variable TPM properties keyed by TPM_PT
+func readTPMProperties(t transport.TPM) (map[tpm2.TPMPT]uint32, error) {
+	props := make(map[tpm2.TPMPT]uint32)
+	for _, start := range []tpm2.TPMPT{tpm2.TPMPTFamilyIndicator, tpm2.TPMPTPermanent} {
+		rsp, err := tpm2.GetCapability{
+			Capability:    tpm2.TPMCapTPMProperties,
+			Property:      uint32(start),
+			PropertyCount: 64,
+		}.Execute(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TPM properties: %w", err)
+		}
+		tagged, err := rsp.CapabilityData.Data.TPMProperties()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode TPM properties: %w", err)
+		}
+		for _, p := range tagged.TPMProperty {
+			props[p.Property] = p.Value
+		}
+	}
+	return props, nil
+}
+
+// readTPMAlgorithms returns the sorted names of algorithms the TPM implements
+func readTPMAlgorithms(t transport.TPM) ([]string, error) {
+	rsp, err := tpm2.GetCapability{
+		Capability:    tpm2.TPMCapAlgs,
+		Property:      0,
+		PropertyCount: 128,
+	}.Execute(t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TPM algorithms: %w", err)
+	}
+	list, err := rsp.CapabilityData.Data.Algorithms()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode TPM algorithms: %w", err)
+	}
+
+	algs := make([]string, 0, len(list.AlgProperties))
+	for _, a := range list.AlgProperties {
+		algs = append(algs, tpmAlgorithmName(uint16(a.Alg)))
+	}
+	sort.Strings(algs)
+	return algs, nil
+}
+
+// readTPMNV reads the full contents of an NV index using owner authorization
+// with an empty password, in chunks of at most bufMax bytes
+func readTPMNV(t transport.TPM, index uint32, bufMax uint16) ([]byte, error) {
+	handle := tpm2.TPMHandle(index)
+	pub, err := tpm2.NVReadPublic{NVIndex: handle}.Execute(t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read NV public area: %w", err)
+	}
+	nvPublic, err := pub.NVPublic.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode NV public area: %w", err)
+	}
+
+	size := nvPublic.DataSize
+	data := make([]byte, 0, size)
+	for off := uint16(0); off < size; {
+		n := size - off
+		if n > bufMax {
+			n = bufMax
+		}
+		rsp, err := tpm2.NVRead{
+			AuthHandle: tpm2.AuthHandle{
+				Handle: tpm2.TPMRHOwner,
+				Auth:   tpm2.PasswordAuth(nil),
+			},
+			NVIndex: tpm2.NamedHandle{
+				Handle: handle,
+				Name:   pub.NVName,
+			},
+			Size:   n,
+			Offset: off,
+		}.Execute(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read NV index 0x%08x: %w", index, err)
+		}
+		data = append(data, rsp.Data.Buffer...)
+		off += n
+	}
+	return data, nil
+}
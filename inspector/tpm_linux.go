@@ -3,30 +3,35 @@
 package inspector
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/google/go-tpm/tpm2/transport/linuxtpm"
 )
 
 // TPMResult contains TPM status information
-type TPMResult struct {
-	Present            bool     `json:"present"`
-	Enabled            bool     `json:"enabled"`
-	Version            string   `json:"version"`
-	Manufacturer       string   `json:"manufacturer"`
-	Type               string   `json:"type"`
-	Platform           string   `json:"platform"`
-	Capabilities       []string `json:"capabilities"`
-	HardwareKeySupport bool     `json:"hardware_key_support"`
-}
+type TPMResult = LinuxTPMResult
 
 // GetTPMStatus returns the TPM status (Linux)
 func GetTPMStatus() (*TPMResult, error) {
+	return GetTPMStatusContext(context.Background())
+}
+
+// GetTPMStatusContext is like GetTPMStatus but runs the probes with ctx's
+// deadline, command runner, and logger
+func GetTPMStatusContext(ctx context.Context) (*TPMResult, error) {
+	if result, ok, err := loadFixture[TPMResult](ctx, "tpm"); ok {
+		return result, err
+	}
 	// Check for TPM devices in /sys/class/tpm/
 	tpmPath := "/sys/class/tpm"
 
 	entries, err := os.ReadDir(tpmPath)
+	recordSource(SourceFile, tpmPath, err)
 	if err != nil || len(entries) == 0 {
 		// No TPM found
 		return &TPMResult{
@@ -46,8 +51,8 @@ func GetTPMStatus() (*TPMResult, error) {
 	devicePath := filepath.Join(tpmPath, tpmDevice)
 
 	// Read TPM version
-	version := readSysFile(filepath.Join(devicePath, "tpm_version_major"))
-	versionMinor := readSysFile(filepath.Join(devicePath, "tpm_version_minor"))
+	version := readSysFile(ctx, filepath.Join(devicePath, "tpm_version_major"))
+	versionMinor := readSysFile(ctx, filepath.Join(devicePath, "tpm_version_minor"))
 
 	tpmType := "tpm_1.2"
 	versionStr := "1.2"
@@ -59,15 +64,16 @@ func GetTPMStatus() (*TPMResult, error) {
 		versionStr = fmt.Sprintf("%s.%s", version, versionMinor)
 	}
 
-	// Read manufacturer info from device
-	manufacturer := readSysFile(filepath.Join(devicePath, "device/vendor"))
+	// Identify the vendor from sysfs; the TPM's own TPM_PT_MANUFACTURER
+	// replaces it below when the device can be opened
+	manufacturer, driver := readSysfsTPMVendor(os.DirFS("/"), strings.TrimPrefix(devicePath, "/"))
 	if manufacturer == "" {
-		// Try to get from caps
 		manufacturer = "Unknown"
 	}
 
 	// Check if device is accessible (enabled)
 	_, devErr := os.Stat("/dev/" + tpmDevice)
+	recordSource(SourceFile, "/dev/"+tpmDevice, devErr)
 	enabled := devErr == nil
 
 	capabilities := []string{}
@@ -82,7 +88,7 @@ func GetTPMStatus() (*TPMResult, error) {
 		}
 	}
 
-	return &TPMResult{
+	result := &TPMResult{
 		Present:            true,
 		Enabled:            enabled,
 		Version:            fmt.Sprintf("TPM %s", versionStr),
@@ -91,13 +97,55 @@ func GetTPMStatus() (*TPMResult, error) {
 		Platform:           "linux",
 		Capabilities:       capabilities,
 		HardwareKeySupport: enabled,
-	}, nil
+	}
+
+	// Query the TPM directly for details sysfs does not expose
+	if enabled && tpmType == "tpm_2.0" {
+		details, err := readLinuxTPMDetails(tpmDevice)
+		if err == nil {
+			applyTPMDetails(result, details)
+		} else if errors.Is(err, os.ErrPermission) {
+			result.Error = newProbeError(ctx, ErrPermissionDenied, "/dev/"+tpmDevice,
+				"insufficient privileges to open the TPM device")
+		}
+	}
+
+	// A TPM device under /sys/devices/virtual is emulated (vtpm_proxy)
+	virtualized := detectHypervisor(ctx).hypervisor != ""
+	if target, err := filepath.EvalSymlinks(devicePath); err == nil && strings.Contains(target, "/devices/virtual/") {
+		virtualized = true
+	}
+	if result.Manufacturer != "Unknown" {
+		result.ManufacturerName = tpmVendorName(result.Manufacturer)
+	}
+	result.Kind = linuxTPMKind(result.Manufacturer, driver, virtualized)
+
+	return result, nil
+}
+
+// readLinuxTPMDetails opens the TPM (preferring the kernel resource manager)
+// and reads its properties
+func readLinuxTPMDetails(tpmDevice string) (*tpmDetails, error) {
+	var lastErr error
+	for _, path := range []string{"/dev/tpmrm" + strings.TrimPrefix(tpmDevice, "tpm"), "/dev/" + tpmDevice} {
+		t, err := linuxtpm.Open(path)
+		recordSource(SourceAPI, "TPM2 GetCapability via "+path, err)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		details, err := readTPMDetails(t)
+		_ = t.Close()
+		return details, err
+	}
+	return nil, lastErr
 }
 
 // readSysFile reads a sysfs file and returns trimmed content
-func readSysFile(path string) string {
-	data, err := os.ReadFile(path)
+func readSysFile(ctx context.Context, path string) string {
+	data, err := readFile(path)
 	if err != nil {
+		loggerFrom(ctx).Debug("cannot read sysfs file", "path", path, "err", err)
 		return ""
 	}
 	return strings.TrimSpace(string(data))
@@ -105,99 +153,18 @@ func readSysFile(path string) string {
 
 // FormatTPMTable formats TPM status as a colored table
 func FormatTPMTable(result *TPMResult) string {
-	var sb strings.Builder
-	sb.WriteString("\n")
-	sb.WriteString(Header(IconShield + " TPM Status"))
-	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 55)))
-	sb.WriteString("\n\n")
-
-	// Platform badge
-	sb.WriteString(BoldText("Platform: "))
-	sb.WriteString(Info(IconChip + " Linux"))
-	sb.WriteString("\n\n")
-
-	// Status table
-	sb.WriteString(TableTop(28, 22))
-	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Property", 28)),
-		Header(PadRight("Value", 22)),
-	))
-	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(28, 22))
-	sb.WriteString("\n")
-
-	// Present
-	sb.WriteString(TableRowColored(
-		PadRight(IconShield+" TPM Present", 28),
-		PadRight(BoolToStatusColored(result.Present), 22),
-	))
-	sb.WriteString("\n")
-
-	// Enabled
-	sb.WriteString(TableRowColored(
-		PadRight(IconCheck+" Enabled", 28),
-		PadRight(BoolToStatusColored(result.Enabled), 22),
-	))
-	sb.WriteString("\n")
-
-	// Version
-	sb.WriteString(TableRowColored(
-		PadRight(IconInfo+" Version", 28),
-		PadRight(Info(result.Version), 22),
-	))
-	sb.WriteString("\n")
-
-	// Manufacturer
-	sb.WriteString(TableRowColored(
-		PadRight(IconDiamond+" Manufacturer", 28),
-		PadRight(result.Manufacturer, 22),
-	))
-	sb.WriteString("\n")
-
-	// Type
-	typeDisplay := result.Type
-	if result.Type == "tpm_2.0" {
-		typeDisplay = Success("TPM 2.0")
-	} else if result.Type == "tpm_1.2" {
-		typeDisplay = Warning("TPM 1.2")
-	}
-	sb.WriteString(TableRowColored(
-		PadRight(IconChip+" Type", 28),
-		PadRight(typeDisplay, 22),
-	))
-	sb.WriteString("\n")
-
-	// Hardware Key Support
-	sb.WriteString(TableRowColored(
-		PadRight(IconKey+" Hardware Key Support", 28),
-		PadRight(BoolToStatusColored(result.HardwareKeySupport), 22),
-	))
-	sb.WriteString("\n")
-
-	sb.WriteString(TableBottom(28, 22))
-	sb.WriteString("\n\n")
-
-	// Capabilities section
-	if len(result.Capabilities) > 0 {
-		sb.WriteString(BoldText("Capabilities:"))
-		sb.WriteString("\n")
-		sb.WriteString(Muted(strings.Repeat("─", 35)))
-		sb.WriteString("\n")
-		for _, cap := range result.Capabilities {
-			sb.WriteString(fmt.Sprintf("  %s %s\n", Success(IconCheck), cap))
-		}
-		sb.WriteString("\n")
-	}
+	return FormatTPMTableWith(CurrentStyler(), result)
+}
 
-	return sb.String()
+// FormatTPMTableWith is like FormatTPMTable but styles the table with st
+func FormatTPMTableWith(st Styler, result *TPMResult) string {
+	return formatLinuxTPMTable(st, result)
 }
 
 // FormatTPM formats TPM status in the specified format
 func FormatTPM(result *TPMResult, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatTPMTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatTPMTableWith(st, result)
 	}, format)
 }
 
@@ -0,0 +1,157 @@
+package inspector
+
+import (
+	"bytes"
+	"crypto/x509"
+	"embed"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// bundledTPMRoots contains the EK CA certificates shipped with the binary
+//
+//go:embed tpmroots/*.cer tpmroots/*.crt
+var bundledTPMRoots embed.FS
+
+// errNoEKRoot means no trusted CA covers an EK certificate's issuer: the
+// chain could not be checked, which is not the same as a chain that failed
+var errNoEKRoot = errors.New("no bundled root for this manufacturer")
+
+// oidSubjectAltName is the X.509 Subject Alternative Name extension. EK
+// certificates carry the TPM manufacturer, model, and version as a critical
+// directoryName SAN, which crypto/x509 does not handle.
+var oidSubjectAltName = asn1.ObjectIdentifier{2, 5, 29, 17}
+
+const (
+	// TPMCADirEnv names a directory of additional TPM EK root and
+	// intermediate certificates (PEM or DER)
+	TPMCADirEnv = "OMNITRUST_TPM_CA_DIR"
+	// TPMVerifyEKEnv turns EK certificate chain verification off when set
+	// to a false value (0, false, no, off)
+	TPMVerifyEKEnv = "OMNITRUST_TPM_VERIFY_EK"
+)
+
+// tpmVerifyEKEnabled reports whether EK certificate chains should be verified
+func tpmVerifyEKEnabled() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(TPMVerifyEKEnv))) {
+	case "0", "false", "no", "off":
+		return false
+	}
+	return true
+}
+
+var (
+	tpmCAOnce          sync.Once
+	tpmCARoots         *x509.CertPool
+	tpmCAIntermediates *x509.CertPool
+)
+
+// tpmCAPools returns the trusted EK roots and intermediates: the bundled
+// certificates plus any found in the directory named by OMNITRUST_TPM_CA_DIR
+func tpmCAPools() (*x509.CertPool, *x509.CertPool) {
+	tpmCAOnce.Do(func() {
+		tpmCARoots = x509.NewCertPool()
+		tpmCAIntermediates = x509.NewCertPool()
+
+		var certs []*x509.Certificate
+		_ = fs.WalkDir(bundledTPMRoots, "tpmroots", func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			data, err := bundledTPMRoots.ReadFile(path)
+			if err == nil {
+				certs = append(certs, parseCertificates(data)...)
+			}
+			return nil
+		})
+
+		if dir := os.Getenv(TPMCADirEnv); dir != "" {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				Logger().Warn("cannot read TPM CA directory", "dir", dir, "err", err)
+			}
+			for _, entry := range entries {
+				if entry.IsDir() {
+					continue
+				}
+				data, err := os.ReadFile(filepath.Join(dir, entry.Name())) // #nosec G304 -- operator-configured CA directory
+				if err != nil {
+					Logger().Warn("cannot read TPM CA certificate", "file", entry.Name(), "err", err)
+					continue
+				}
+				certs = append(certs, parseCertificates(data)...)
+			}
+		}
+
+		for _, cert := range certs {
+			if bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil {
+				tpmCARoots.AddCert(cert)
+			} else {
+				tpmCAIntermediates.AddCert(cert)
+			}
+		}
+	})
+	return tpmCARoots, tpmCAIntermediates
+}
+
+// parseCertificates decodes every certificate in a PEM bundle or a single DER file
+func parseCertificates(data []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+	if len(certs) == 0 {
+		if cert, err := x509.ParseCertificate(data); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+	return certs
+}
+
+// verifyEKChain verifies an EK certificate against the given CA pools and
+// returns the subject of the root that anchors the chain. The error wraps
+// errNoEKRoot when none of the CAs issued it.
+func verifyEKChain(cert *x509.Certificate, roots, intermediates *x509.CertPool) (string, error) {
+	// Work on a copy so the critical TPM SAN can be marked as handled
+	ek := *cert
+	ek.UnhandledCriticalExtensions = nil
+	for _, oid := range cert.UnhandledCriticalExtensions {
+		if !oid.Equal(oidSubjectAltName) {
+			ek.UnhandledCriticalExtensions = append(ek.UnhandledCriticalExtensions, oid)
+		}
+	}
+
+	chains, err := ek.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		// EK certificates use the TCG tcg-kp-EKCertificate EKU
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		var unknown x509.UnknownAuthorityError
+		if errors.As(err, &unknown) {
+			return "", fmt.Errorf("%w (issuer %q)", errNoEKRoot, cert.Issuer.String())
+		}
+		return "", err
+	}
+	chain := chains[0]
+	return chain[len(chain)-1].Subject.String(), nil
+}
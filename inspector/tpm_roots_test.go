@@ -0,0 +1,150 @@
+package inspector
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testEKChain creates a CA and an EK-style leaf with a critical directoryName SAN
+func testEKChain(t *testing.T) (ca, leaf *x509.Certificate) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test TPM Root CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate (CA) failed: %v", err)
+	}
+	ca, _ = x509.ParseCertificate(caDER)
+
+	// TPM manufacturer info is encoded as a directoryName in a critical SAN
+	dirName, _ := asn1.Marshal(pkix.Name{ExtraNames: []pkix.AttributeTypeAndValue{
+		{Type: oidTPMManufacturer, Value: "id:494E5443"},
+		{Type: oidTPMModel, Value: "PTT"},
+		{Type: oidTPMVersion, Value: "id:000B0002"},
+	}}.ToRDNSequence())
+	san, _ := asn1.Marshal([]asn1.RawValue{{Class: asn1.ClassContextSpecific, Tag: 4, IsCompound: true, Bytes: dirName}})
+
+	leafKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	leafTmpl := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(time.Hour),
+		KeyUsage:        x509.KeyUsageKeyEncipherment,
+		ExtraExtensions: []pkix.Extension{{Id: oidSubjectAltName, Critical: true, Value: san}},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTmpl, ca, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate (leaf) failed: %v", err)
+	}
+	leaf, err = x509.ParseCertificate(leafDER)
+	if err != nil {
+		t.Fatalf("ParseCertificate (leaf) failed: %v", err)
+	}
+	return ca, leaf
+}
+
+func TestVerifyEKChain(t *testing.T) {
+	ca, leaf := testEKChain(t)
+	if len(leaf.UnhandledCriticalExtensions) == 0 {
+		t.Fatal("test leaf should carry an unhandled critical SAN")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	root, err := verifyEKChain(leaf, roots, x509.NewCertPool())
+	if err != nil {
+		t.Fatalf("verifyEKChain failed: %v", err)
+	}
+	if !strings.Contains(root, "Test TPM Root CA") {
+		t.Errorf("root = %q, want it to contain 'Test TPM Root CA'", root)
+	}
+
+	// The caller's certificate must not be modified
+	if len(leaf.UnhandledCriticalExtensions) == 0 {
+		t.Error("verifyEKChain should not modify the input certificate")
+	}
+}
+
+func TestVerifyEKChain_UntrustedIssuer(t *testing.T) {
+	_, leaf := testEKChain(t)
+
+	_, err := verifyEKChain(leaf, x509.NewCertPool(), x509.NewCertPool())
+	if !errors.Is(err, errNoEKRoot) {
+		t.Errorf("error = %v, want no bundled root", err)
+	}
+}
+
+func TestVerifyEKChain_Failed(t *testing.T) {
+	ca, leaf := testEKChain(t)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	// A chain to a trusted root that does not validate is not a missing root
+	expired := *leaf
+	expired.NotAfter = time.Now().Add(-time.Minute)
+	_, err := verifyEKChain(&expired, roots, x509.NewCertPool())
+	if err == nil || errors.Is(err, errNoEKRoot) {
+		t.Errorf("error = %v, want a failed chain", err)
+	}
+}
+
+func TestTPMCAPools_Bundled(t *testing.T) {
+	roots, intermediates := tpmCAPools()
+	if roots == nil || intermediates == nil {
+		t.Fatal("tpmCAPools returned nil pools")
+	}
+
+	// The bundled Google Cloud intermediate must chain to the bundled root
+	data, err := bundledTPMRoots.ReadFile("tpmroots/gce_tpm_ek_intermediate_2.crt")
+	if err != nil {
+		t.Fatalf("bundled intermediate missing: %v", err)
+	}
+	certs := parseCertificates(data)
+	if len(certs) != 1 {
+		t.Fatalf("parseCertificates returned %d certs, want 1", len(certs))
+	}
+	if _, err := verifyEKChain(certs[0], roots, intermediates); err != nil {
+		t.Errorf("bundled intermediate should verify against bundled root: %v", err)
+	}
+}
+
+func TestParseCertificates_PEMBundle(t *testing.T) {
+	ca, leaf := testEKChain(t)
+	bundle := append(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw})...,
+	)
+
+	if got := len(parseCertificates(bundle)); got != 2 {
+		t.Errorf("parseCertificates(PEM bundle) returned %d certs, want 2", got)
+	}
+	if got := len(parseCertificates(ca.Raw)); got != 1 {
+		t.Errorf("parseCertificates(DER) returned %d certs, want 1", got)
+	}
+	if got := len(parseCertificates([]byte("not a cert"))); got != 0 {
+		t.Errorf("parseCertificates(garbage) returned %d certs, want 0", got)
+	}
+}
@@ -0,0 +1,442 @@
+package inspector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DarwinTPMResult contains TPM/Secure Enclave status information on macOS,
+// where TPMResult is an alias for it
+type DarwinTPMResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Present      bool   `json:"present"`
+	Enabled      bool   `json:"enabled"`
+	Version      string `json:"version"`
+	Manufacturer string `json:"manufacturer"`
+	// ManufacturerName is the vendor behind the Manufacturer ID (e.g.
+	// Infineon for IFX)
+	ManufacturerName   string   `json:"manufacturer_name,omitempty"`
+	Type               string   `json:"type"`
+	Platform           string   `json:"platform"`
+	Capabilities       []string `json:"capabilities"`
+	HardwareKeySupport bool     `json:"hardware_key_support"`
+	// Details read directly from the TPM via go-tpm (Linux/Windows TPM 2.0 only)
+	FirmwareVersion string           `json:"firmware_version,omitempty"`
+	Algorithms      []string         `json:"algorithms,omitempty"`
+	Lockout         *TPMLockoutState `json:"lockout,omitempty"`
+	Auth            *TPMAuthState    `json:"auth,omitempty"`
+	EKCertificate   *EKCertificate   `json:"ek_certificate,omitempty"`
+	Error           *ProbeError      `json:"error,omitempty"`
+
+	// Kind is discrete, firmware, or virtual (secure_enclave on macOS)
+	Kind string `json:"tpm_kind,omitempty"`
+}
+
+// formatDarwinTPMTable formats macOS TPM status as a colored table
+func formatDarwinTPMTable(st Styler, result *DarwinTPMResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " " + T("TPM / Secure Enclave Status")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	// Platform badge
+	var platformIcon string
+	if result.Platform == "apple_silicon" {
+		platformIcon = IconApple + " Apple Silicon"
+	} else {
+		platformIcon = IconChip + " Intel (T2)"
+	}
+	sb.WriteString(st.BoldText(T("Platform:") + " "))
+	sb.WriteString(st.Info(platformIcon))
+	sb.WriteString("\n\n")
+
+	// Status table
+	sb.WriteString(st.TableTop(28, 22))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Property"), 28)),
+		st.Header(PadRight(T("Value"), 22)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(28, 22))
+	sb.WriteString("\n")
+
+	// Present
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconShield+" "+T("TPM/SE Present"), 28),
+		PadRight(st.BoolToStatusColored(result.Present), 22),
+	))
+	sb.WriteString("\n")
+
+	// Enabled
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconCheck+" "+T("Enabled"), 28),
+		PadRight(st.BoolToStatusColored(result.Enabled), 22),
+	))
+	sb.WriteString("\n")
+
+	// Version
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconInfo+" "+T("Version"), 28),
+		PadRight(st.Info(Truncate(result.Version, 22)), 22),
+	))
+	sb.WriteString("\n")
+
+	// Manufacturer
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconDiamond+" "+T("Manufacturer"), 28),
+		PadRight(Truncate(result.Manufacturer, 22), 22),
+	))
+	sb.WriteString("\n")
+
+	// Hardware Key Support
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconKey+" "+T("Hardware Key Support"), 28),
+		PadRight(st.BoolToStatusColored(result.HardwareKeySupport), 22),
+	))
+	sb.WriteString("\n")
+
+	sb.WriteString(st.TableBottom(28, 22))
+	sb.WriteString("\n\n")
+
+	// Capabilities section
+	sb.WriteString(st.BoldText(T("Capabilities:")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 35)))
+	sb.WriteString("\n")
+	for _, cap := range result.Capabilities {
+		sb.WriteString(fmt.Sprintf("  %s %s\n", st.Success(IconCheck), cap))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// LinuxTPMResult contains TPM status information on Linux, where TPMResult
+// is an alias for it
+type LinuxTPMResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Present      bool   `json:"present"`
+	Enabled      bool   `json:"enabled"`
+	Version      string `json:"version"`
+	Manufacturer string `json:"manufacturer"`
+	// ManufacturerName is the vendor behind the Manufacturer ID (e.g.
+	// Infineon for IFX)
+	ManufacturerName   string   `json:"manufacturer_name,omitempty"`
+	Type               string   `json:"type"`
+	Platform           string   `json:"platform"`
+	Capabilities       []string `json:"capabilities"`
+	HardwareKeySupport bool     `json:"hardware_key_support"`
+	// Details read directly from the TPM via go-tpm (Linux/Windows TPM 2.0 only)
+	FirmwareVersion string           `json:"firmware_version,omitempty"`
+	Algorithms      []string         `json:"algorithms,omitempty"`
+	Lockout         *TPMLockoutState `json:"lockout,omitempty"`
+	Auth            *TPMAuthState    `json:"auth,omitempty"`
+	EKCertificate   *EKCertificate   `json:"ek_certificate,omitempty"`
+	Error           *ProbeError      `json:"error,omitempty"`
+
+	// Kind is discrete, firmware, or virtual (secure_enclave on macOS)
+	Kind string `json:"tpm_kind,omitempty"`
+}
+
+// formatLinuxTPMTable formats Linux TPM status as a colored table
+func formatLinuxTPMTable(st Styler, result *LinuxTPMResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " " + T("TPM Status")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	// Platform badge
+	sb.WriteString(st.BoldText(T("Platform:") + " "))
+	sb.WriteString(st.Info(IconChip + " Linux"))
+	sb.WriteString("\n\n")
+
+	// Status table
+	sb.WriteString(st.TableTop(28, 22))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Property"), 28)),
+		st.Header(PadRight(T("Value"), 22)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(28, 22))
+	sb.WriteString("\n")
+
+	// Present
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconShield+" "+T("TPM Present"), 28),
+		PadRight(st.BoolToStatusColored(result.Present), 22),
+	))
+	sb.WriteString("\n")
+
+	// Enabled
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconCheck+" "+T("Enabled"), 28),
+		PadRight(st.BoolToStatusColored(result.Enabled), 22),
+	))
+	sb.WriteString("\n")
+
+	// Version
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconInfo+" "+T("Version"), 28),
+		PadRight(st.Info(Truncate(result.Version, 22)), 22),
+	))
+	sb.WriteString("\n")
+
+	// Manufacturer
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconDiamond+" "+T("Manufacturer"), 28),
+		PadRight(Truncate(tpmManufacturerDisplay(result.Manufacturer, result.ManufacturerName), 22), 22),
+	))
+	sb.WriteString("\n")
+
+	// Type
+	typeDisplay := Truncate(result.Type, 22)
+	if result.Type == "tpm_2.0" {
+		typeDisplay = st.Success("TPM 2.0")
+	} else if result.Type == "tpm_1.2" {
+		typeDisplay = st.Warning("TPM 1.2")
+	}
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconChip+" "+T("Type"), 28),
+		PadRight(typeDisplay, 22),
+	))
+	sb.WriteString("\n")
+
+	// Kind
+	if result.Kind != "" {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconChip+" "+T("Kind"), 28),
+			PadRight(Truncate(result.Kind, 22), 22),
+		))
+		sb.WriteString("\n")
+	}
+
+	// Hardware Key Support
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconKey+" "+T("Hardware Key Support"), 28),
+		PadRight(st.BoolToStatusColored(result.HardwareKeySupport), 22),
+	))
+	sb.WriteString("\n")
+
+	// Firmware
+	if result.FirmwareVersion != "" {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconInfo+" "+T("Firmware"), 28),
+			PadRight(Truncate(result.FirmwareVersion, 22), 22),
+		))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.TableBottom(28, 22))
+	sb.WriteString("\n\n")
+
+	// Capabilities section
+	if len(result.Capabilities) > 0 {
+		sb.WriteString(st.BoldText(T("Capabilities:")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(strings.Repeat("─", 35)))
+		sb.WriteString("\n")
+		for _, cap := range result.Capabilities {
+			sb.WriteString(fmt.Sprintf("  %s %s\n", st.Success(IconCheck), cap))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(formatTPMDetailsSection(st, result.Algorithms, result.Lockout, result.Auth, result.EKCertificate))
+	sb.WriteString(formatProbeError(st, result.Error))
+
+	return sb.String()
+}
+
+// WindowsTPMResult contains TPM status information on Windows, where
+// TPMResult is an alias for it
+type WindowsTPMResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Present      bool   `json:"present"`
+	Enabled      bool   `json:"enabled"`
+	Version      string `json:"version"`
+	Manufacturer string `json:"manufacturer"`
+	// ManufacturerName is the vendor behind the Manufacturer ID (e.g.
+	// Infineon for IFX)
+	ManufacturerName   string   `json:"manufacturer_name,omitempty"`
+	Type               string   `json:"type"`
+	Platform           string   `json:"platform"`
+	Capabilities       []string `json:"capabilities"`
+	HardwareKeySupport bool     `json:"hardware_key_support"`
+	Activated          bool     `json:"activated"`
+	Owned              bool     `json:"owned"`
+	// Ready mirrors Get-Tpm's TpmReady, and NotReadyReasons explains why
+	// the TPM is not ready
+	Ready           bool     `json:"ready"`
+	NotReadyReasons []string `json:"not_ready_reasons,omitempty"`
+	// AttestationCapable is true if the TPM can attest keys (TPM 2.0 with
+	// the endorsement and storage hierarchies enabled)
+	AttestationCapable bool `json:"attestation_capable"`
+	// Details read directly from the TPM via go-tpm (Linux/Windows TPM 2.0 only)
+	FirmwareVersion string           `json:"firmware_version,omitempty"`
+	Algorithms      []string         `json:"algorithms,omitempty"`
+	Lockout         *TPMLockoutState `json:"lockout,omitempty"`
+	Auth            *TPMAuthState    `json:"auth,omitempty"`
+	EKCertificate   *EKCertificate   `json:"ek_certificate,omitempty"`
+	Error           *ProbeError      `json:"error,omitempty"`
+
+	// Kind is discrete, firmware, or virtual (secure_enclave on macOS)
+	Kind string `json:"tpm_kind,omitempty"`
+}
+
+// formatWindowsTPMTable formats Windows TPM status as a colored table
+func formatWindowsTPMTable(st Styler, result *WindowsTPMResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " " + T("TPM Status")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	// Platform badge
+	sb.WriteString(st.BoldText(T("Platform:") + " "))
+	sb.WriteString(st.Info(IconChip + " Windows"))
+	sb.WriteString("\n\n")
+
+	// Status table
+	sb.WriteString(st.TableTop(28, 22))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Property"), 28)),
+		st.Header(PadRight(T("Value"), 22)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(28, 22))
+	sb.WriteString("\n")
+
+	// Present
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconShield+" "+T("TPM Present"), 28),
+		PadRight(st.BoolToStatusColored(result.Present), 22),
+	))
+	sb.WriteString("\n")
+
+	// Enabled
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconCheck+" "+T("Enabled"), 28),
+		PadRight(st.BoolToStatusColored(result.Enabled), 22),
+	))
+	sb.WriteString("\n")
+
+	// Version
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconInfo+" "+T("Version"), 28),
+		PadRight(st.Info(Truncate(result.Version, 22)), 22),
+	))
+	sb.WriteString("\n")
+
+	// Manufacturer
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconDiamond+" "+T("Manufacturer"), 28),
+		PadRight(Truncate(tpmManufacturerDisplay(result.Manufacturer, result.ManufacturerName), 22), 22),
+	))
+	sb.WriteString("\n")
+
+	// Type
+	typeDisplay := Truncate(result.Type, 22)
+	if result.Type == "tpm_2.0" {
+		typeDisplay = st.Success("TPM 2.0")
+	} else if result.Type == "tpm_1.2" {
+		typeDisplay = st.Warning("TPM 1.2")
+	}
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconChip+" "+T("Type"), 28),
+		PadRight(typeDisplay, 22),
+	))
+	sb.WriteString("\n")
+
+	// Kind
+	if result.Kind != "" {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconChip+" "+T("Kind"), 28),
+			PadRight(Truncate(result.Kind, 22), 22),
+		))
+		sb.WriteString("\n")
+	}
+
+	// Hardware Key Support
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconKey+" "+T("Hardware Key Support"), 28),
+		PadRight(st.BoolToStatusColored(result.HardwareKeySupport), 22),
+	))
+	sb.WriteString("\n")
+
+	// Activated / Owned
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconCheck+" "+T("Activated"), 28),
+		PadRight(st.BoolToStatusColored(result.Activated), 22),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconKey+" "+T("Owned"), 28),
+		PadRight(st.BoolToStatusColored(result.Owned), 22),
+	))
+	sb.WriteString("\n")
+
+	// Ready
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconShield+" "+T("Ready"), 28),
+		PadRight(st.BoolToStatusColored(result.Ready), 22),
+	))
+	sb.WriteString("\n")
+
+	// Attestation
+	sb.WriteString(st.TableRowColored(
+		PadRight(IconKey+" "+T("Attestation Capable"), 28),
+		PadRight(st.BoolToStatusColored(result.AttestationCapable), 22),
+	))
+	sb.WriteString("\n")
+
+	// Firmware
+	if result.FirmwareVersion != "" {
+		sb.WriteString(st.TableRowColored(
+			PadRight(IconInfo+" "+T("Firmware"), 28),
+			PadRight(Truncate(result.FirmwareVersion, 22), 22),
+		))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.TableBottom(28, 22))
+	sb.WriteString("\n\n")
+
+	if len(result.NotReadyReasons) > 0 {
+		sb.WriteString(st.BoldText(T("Not Ready:")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(strings.Repeat("─", 35)))
+		sb.WriteString("\n")
+		for _, reason := range result.NotReadyReasons {
+			sb.WriteString(fmt.Sprintf("  %s%s\n", st.Warning(IconWarning), reason))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Capabilities section
+	if len(result.Capabilities) > 0 {
+		sb.WriteString(st.BoldText(T("Capabilities:")))
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(strings.Repeat("─", 35)))
+		sb.WriteString("\n")
+		for _, cap := range result.Capabilities {
+			sb.WriteString(fmt.Sprintf("  %s %s\n", st.Success(IconCheck), cap))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(formatTPMDetailsSection(st, result.Algorithms, result.Lockout, result.Auth, result.EKCertificate))
+	sb.WriteString(formatProbeError(st, result.Error))
+
+	return sb.String()
+}
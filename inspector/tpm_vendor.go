@@ -0,0 +1,226 @@
+package inspector
+
+import (
+	"bufio"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// tpmVendorNames maps TCG vendor IDs (TCG TPM Vendor ID Registry) to names
+var tpmVendorNames = map[string]string{
+	"AMD":  "AMD",
+	"AMZN": "Amazon",
+	"ATML": "Atmel",
+	"BRCM": "Broadcom",
+	"CROS": "Google (Titan/Cr50)",
+	"CSCO": "Cisco",
+	"FLYS": "Flyslice",
+	"GOOG": "Google",
+	"HISI": "Huawei",
+	"HPE":  "HPE",
+	"IBM":  "IBM",
+	"IFX":  "Infineon",
+	"INTC": "Intel",
+	"LEN":  "Lenovo",
+	"MSFT": "Microsoft",
+	"NSM":  "National Semiconductor",
+	"NTC":  "Nuvoton",
+	"NTZ":  "Nationz",
+	"QCOM": "Qualcomm",
+	"ROCC": "Rockchip",
+	"SMSC": "SMSC",
+	"SMSN": "Samsung",
+	"SNS":  "Sinosun",
+	"STM":  "STMicroelectronics",
+	"TXN":  "Texas Instruments",
+	"VMW":  "VMware",
+	"WEC":  "Winbond",
+}
+
+// tpmVendorName returns the vendor behind a TCG vendor ID, or the ID itself
+// if it is not registered
+func tpmVendorName(id string) string {
+	if name, ok := tpmVendorNames[strings.ToUpper(id)]; ok {
+		return name
+	}
+	return id
+}
+
+// decodeTPMVendorID decodes a tpmManufacturer attribute, "id:" followed by
+// the hex of the four-character vendor ID (e.g. id:49465800 for IFX)
+func decodeTPMVendorID(value string) string {
+	id, ok := strings.CutPrefix(value, "id:")
+	if !ok {
+		return value
+	}
+	v, err := strconv.ParseUint(id, 16, 32)
+	if err != nil {
+		return id
+	}
+	return tpmVendorString(uint32(v))
+}
+
+// acpiTPMVendors maps the vendor prefix of ACPI/PNP TPM hardware IDs (e.g.
+// IFX0102) to TCG vendor IDs. The generic MSFT0101 ID is used by TPMs of
+// every vendor, so it is not listed.
+var acpiTPMVendors = map[string]string{
+	"ATM":  "ATML",
+	"BCM":  "BRCM",
+	"IFX":  "IFX",
+	"INTC": "INTC",
+	"NTC":  "NTC",
+	"NSC":  "NSM",
+	"SMO":  "STM",
+	"STM":  "STM",
+	"WEC":  "WEC",
+}
+
+// deviceTreeTPMVendors maps device tree compatible vendor prefixes (e.g.
+// infineon,slb9670) to TCG vendor IDs
+var deviceTreeTPMVendors = map[string]string{
+	"atmel":    "ATML",
+	"infineon": "IFX",
+	"nuvoton":  "NTC",
+	"st":       "STM",
+	"winbond":  "WEC",
+}
+
+// descriptionTPMVendors maps substrings of a TPM device description to TCG
+// vendor IDs
+var descriptionTPMVendors = []struct{ substr, vendor string }{
+	{"infineon", "IFX"},
+	{"nuvoton", "NTC"},
+	{"stmicro", "STM"},
+	{"platform trust", "INTC"},
+	{"amd", "AMD"},
+	{"pluton", "MSFT"},
+}
+
+// pciTPMVendors maps PCI vendor IDs of CRB TPMs to TCG vendor IDs
+var pciTPMVendors = map[string]string{
+	"0x8086": "INTC",
+	"0x1022": "AMD",
+}
+
+// tpmDriverKinds maps Linux TPM drivers to the kind of TPM they serve.
+// Firmware TPMs (Intel PTT, AMD fTPM) use the CRB interface, while discrete
+// chips are attached over TIS (LPC, SPI, or I2C).
+var tpmDriverKinds = map[string]string{
+	"tpm_crb":          TPMKindFirmware,
+	"tpm_ftpm_tee":     TPMKindFirmware,
+	"tpm_tis":          TPMKindDiscrete,
+	"tpm_tis_spi":      TPMKindDiscrete,
+	"tpm_tis_i2c":      TPMKindDiscrete,
+	"tpm_tis_i2c_cr50": TPMKindDiscrete,
+	"tpm_i2c_infineon": TPMKindDiscrete,
+	"tpm_i2c_nuvoton":  TPMKindDiscrete,
+	"tpm_i2c_atmel":    TPMKindDiscrete,
+	"tpm_atmel":        TPMKindDiscrete,
+	"tpm_infineon":     TPMKindDiscrete,
+	"tpm_nsc":          TPMKindDiscrete,
+	"tpm_st33zp24_spi": TPMKindDiscrete,
+	"tpm_st33zp24_i2c": TPMKindDiscrete,
+	"tpm_vtpm_proxy":   TPMKindVirtual,
+	"tpm_ibmvtpm":      TPMKindVirtual,
+	"xen-tpmfront":     TPMKindVirtual,
+}
+
+// readSysfsTPMVendor identifies a TPM's vendor and driver from sysfs without
+// opening the device, which needs root or the tss group. dir is the TPM's
+// class directory relative to fsys (e.g. sys/class/tpm/tpm0). The vendor is
+// a TCG vendor ID, or empty if none of the sources name one.
+func readSysfsTPMVendor(fsys fs.FS, dir string) (vendor, driver string) {
+	read := func(name string) string {
+		data, err := readFSFile(fsys, path.Join(dir, "device", name))
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+
+	var compatible []string
+	var modalias string
+	scanner := bufio.NewScanner(strings.NewReader(read("uevent")))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), "=")
+		switch {
+		case key == "DRIVER":
+			driver = value
+		case key == "MODALIAS":
+			modalias = value
+		case strings.HasPrefix(key, "OF_COMPATIBLE_"):
+			compatible = append(compatible, value)
+		}
+	}
+
+	// TPM 1.2 drivers expose the manufacturer in caps
+	for _, line := range strings.Split(read("caps"), "\n") {
+		if v, ok := strings.CutPrefix(line, "Manufacturer: 0x"); ok {
+			if id, err := strconv.ParseUint(strings.TrimSpace(v), 16, 32); err == nil {
+				if vendor := tpmVendorString(uint32(id)); vendor != "" {
+					return vendor, driver
+				}
+			}
+		}
+	}
+
+	// ACPI and PNP hardware IDs, e.g. acpi:IFX0102: or a PNP id file
+	ids := strings.Fields(read("id"))
+	if hid, ok := strings.CutPrefix(modalias, "acpi:"); ok {
+		ids = append(ids, strings.Split(hid, ":")...)
+	}
+	ids = append(ids, read("hid"), read("firmware_node/hid"))
+	for _, id := range ids {
+		if vendor := acpiTPMVendor(id); vendor != "" {
+			return vendor, driver
+		}
+	}
+
+	for _, c := range compatible {
+		prefix, _, _ := strings.Cut(c, ",")
+		if vendor, ok := deviceTreeTPMVendors[prefix]; ok {
+			return vendor, driver
+		}
+	}
+
+	description := strings.ToLower(read("description"))
+	for _, d := range descriptionTPMVendors {
+		if strings.Contains(description, d.substr) {
+			return d.vendor, driver
+		}
+	}
+
+	if vendor, ok := pciTPMVendors[strings.ToLower(read("vendor"))]; ok {
+		return vendor, driver
+	}
+	return "", driver
+}
+
+// acpiTPMVendor returns the TCG vendor ID behind an ACPI or PNP hardware
+// ID (e.g. IFX0102), or "" if it is not a known TPM vendor's
+func acpiTPMVendor(id string) string {
+	// PNP IDs are three letters and ACPI IDs four, followed by four hex digits
+	if len(id) != 7 && len(id) != 8 {
+		return ""
+	}
+	return acpiTPMVendors[strings.ToUpper(id[:len(id)-4])]
+}
+
+// linuxTPMKind classifies a TPM by its vendor, falling back to its driver
+// when the vendor is unknown
+func linuxTPMKind(manufacturer, driver string, virtualized bool) string {
+	if kind := classifyTPMKind(manufacturer, virtualized); kind != "" {
+		return kind
+	}
+	return tpmDriverKinds[driver]
+}
+
+// tpmManufacturerDisplay renders a vendor ID with its name, e.g. "Infineon (IFX)"
+func tpmManufacturerDisplay(id, name string) string {
+	if name == "" || name == id {
+		return id
+	}
+	return name + " (" + id + ")"
+}
@@ -0,0 +1,83 @@
+package inspector
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestReadSysfsTPMVendor(t *testing.T) {
+	const dir = "sys/class/tpm/tpm0"
+	tests := []struct {
+		name   string
+		files  map[string]string
+		vendor string
+		driver string
+	}{
+		{
+			"tpm 1.2 caps",
+			map[string]string{"caps": "Manufacturer: 0x49465800\nTCG version: 1.2\n", "uevent": "DRIVER=tpm_tis\n"},
+			"IFX", "tpm_tis",
+		},
+		{
+			"acpi id",
+			map[string]string{"uevent": "DRIVER=tpm_tis\nMODALIAS=acpi:NTC0702:MSFT0101:\n"},
+			"NTC", "tpm_tis",
+		},
+		{
+			"pnp id",
+			map[string]string{"id": "IFX0102\n"},
+			"IFX", "",
+		},
+		{
+			"device tree",
+			map[string]string{"uevent": "DRIVER=tpm_tis_spi\nOF_COMPATIBLE_0=infineon,slb9670\nOF_COMPATIBLE_N=1\n"},
+			"IFX", "tpm_tis_spi",
+		},
+		{
+			"description",
+			map[string]string{"uevent": "DRIVER=tpm_crb\nMODALIAS=acpi:MSFT0101:\n", "description": "Intel(R) Platform Trust Technology\n"},
+			"INTC", "tpm_crb",
+		},
+		{
+			"generic crb",
+			map[string]string{"uevent": "DRIVER=tpm_crb\nMODALIAS=acpi:MSFT0101:\n", "description": "TPM 2.0 Device\n"},
+			"", "tpm_crb",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, data := range tt.files {
+				fsys[dir+"/device/"+name] = &fstest.MapFile{Data: []byte(data)}
+			}
+			vendor, driver := readSysfsTPMVendor(fsys, dir)
+			if vendor != tt.vendor || driver != tt.driver {
+				t.Errorf("readSysfsTPMVendor = %q, %q, want %q, %q", vendor, driver, tt.vendor, tt.driver)
+			}
+		})
+	}
+}
+
+func TestLinuxTPMKind(t *testing.T) {
+	tests := []struct {
+		manufacturer, driver string
+		virtualized          bool
+		want                 string
+	}{
+		{"IFX", "tpm_tis", false, TPMKindDiscrete},
+		{"INTC", "tpm_crb", false, TPMKindFirmware},
+		{"Unknown", "tpm_crb", false, TPMKindFirmware},
+		{"Unknown", "tpm_tis_spi", false, TPMKindDiscrete},
+		{"Unknown", "tpm_vtpm_proxy", false, TPMKindVirtual},
+		{"Unknown", "tpm_crb", true, TPMKindVirtual},
+		{"Unknown", "", false, ""},
+	}
+	for _, tt := range tests {
+		if got := linuxTPMKind(tt.manufacturer, tt.driver, tt.virtualized); got != tt.want {
+			t.Errorf("linuxTPMKind(%q, %q, %v) = %q, want %q", tt.manufacturer, tt.driver, tt.virtualized, got, tt.want)
+		}
+	}
+	if got := tpmManufacturerDisplay("IFX", tpmVendorName("IFX")); got != "Infineon (IFX)" {
+		t.Errorf("tpmManufacturerDisplay = %q", got)
+	}
+}
@@ -3,10 +3,13 @@
 package inspector
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
-	"github.com/yusufpapurcu/wmi"
+	"github.com/google/go-tpm/tpm2/transport/windowstpm"
 )
 
 // Win32_Tpm represents WMI TPM class
@@ -24,29 +27,33 @@ type Win32_Tpm struct {
 }
 
 // TPMResult contains TPM status information
-type TPMResult struct {
-	Present            bool     `json:"present"`
-	Enabled            bool     `json:"enabled"`
-	Version            string   `json:"version"`
-	Manufacturer       string   `json:"manufacturer"`
-	Type               string   `json:"type"`
-	Platform           string   `json:"platform"`
-	Capabilities       []string `json:"capabilities"`
-	HardwareKeySupport bool     `json:"hardware_key_support"`
-}
+type TPMResult = WindowsTPMResult
 
 // GetTPMStatus returns the TPM status (Windows)
 func GetTPMStatus() (*TPMResult, error) {
+	return GetTPMStatusContext(context.Background())
+}
+
+// GetTPMStatusContext is like GetTPMStatus but runs the probes with ctx's
+// deadline, command runner, and logger
+func GetTPMStatusContext(ctx context.Context) (*TPMResult, error) {
+	if result, ok, err := loadFixture[TPMResult](ctx, "tpm"); ok {
+		return result, err
+	}
 	var tpmInfo []Win32_Tpm
 
 	// Query WMI for TPM information
 	// Note: Requires running as administrator for full access
 	query := "SELECT * FROM Win32_Tpm"
-	err := wmi.QueryNamespace(query, &tpmInfo, `root\cimv2\Security\MicrosoftTpm`)
+	err := wmiQuery(query, &tpmInfo, `root\cimv2\Security\MicrosoftTpm`)
 
 	if err != nil || len(tpmInfo) == 0 {
 		// TPM not found or not accessible
-		return &TPMResult{
+		var probeErr *ProbeError
+		if err != nil {
+			probeErr = classifyWMIError(ctx, `root\cimv2\Security\MicrosoftTpm`, err)
+		}
+		result := &TPMResult{
 			Present:            false,
 			Enabled:            false,
 			Version:            "Not detected",
@@ -55,7 +62,17 @@ func GetTPMStatus() (*TPMResult, error) {
 			Platform:           "windows",
 			Capabilities:       []string{},
 			HardwareKeySupport: false,
-		}, nil
+			Error:              probeErr,
+		}
+		// Win32_Tpm is only readable by administrators, but TBS answers
+		// standard users, so the TPM can still be detected and inspected
+		if errors.Is(probeErr, ErrPermissionDenied) {
+			probeErr.Hint = T("Win32_Tpm is only readable by administrators: re-run from an elevated prompt to read activation and ownership")
+			if details, err := readWindowsTPMDetails(); err == nil {
+				applyTBSOnlyTPM(ctx, result, details)
+			}
+		}
+		return result, nil
 	}
 
 	tpm := tpmInfo[0]
@@ -84,7 +101,7 @@ func GetTPMStatus() (*TPMResult, error) {
 		manufacturer = fmt.Sprintf("ID: %d", tpm.ManufacturerId)
 	}
 
-	return &TPMResult{
+	result := &TPMResult{
 		Present:            true,
 		Enabled:            tpm.IsEnabled_InitialValue,
 		Version:            version,
@@ -93,104 +110,77 @@ func GetTPMStatus() (*TPMResult, error) {
 		Platform:           "windows",
 		Capabilities:       capabilities,
 		HardwareKeySupport: tpm.IsEnabled_InitialValue && tpm.IsActivated_InitialValue,
-	}, nil
+		Activated:          tpm.IsActivated_InitialValue,
+		Owned:              tpm.IsOwned_InitialValue,
+	}
+
+	// Query the TPM directly through TBS for details WMI does not expose
+	if tpmType == "tpm_2.0" {
+		if details, err := readWindowsTPMDetails(); err == nil {
+			applyTPMDetails(result, details)
+		}
+	}
+	finishWindowsTPM(ctx, result)
+
+	return result, nil
 }
 
-// FormatTPMTable formats TPM status as a colored table
-func FormatTPMTable(result *TPMResult) string {
-	var sb strings.Builder
-	sb.WriteString("\n")
-	sb.WriteString(Header(IconShield + " TPM Status"))
-	sb.WriteString("\n")
-	sb.WriteString(Muted(strings.Repeat("─", 55)))
-	sb.WriteString("\n\n")
-
-	// Platform badge
-	sb.WriteString(BoldText("Platform: "))
-	sb.WriteString(Info(IconChip + " Windows"))
-	sb.WriteString("\n\n")
-
-	// Status table
-	sb.WriteString(TableTop(28, 22))
-	sb.WriteString("\n")
-	sb.WriteString(TableRowColored(
-		Header(PadRight("Property", 28)),
-		Header(PadRight("Value", 22)),
-	))
-	sb.WriteString("\n")
-	sb.WriteString(TableSeparator(28, 22))
-	sb.WriteString("\n")
-
-	// Present
-	sb.WriteString(TableRowColored(
-		PadRight(IconShield+" TPM Present", 28),
-		PadRight(BoolToStatusColored(result.Present), 22),
-	))
-	sb.WriteString("\n")
-
-	// Enabled
-	sb.WriteString(TableRowColored(
-		PadRight(IconCheck+" Enabled", 28),
-		PadRight(BoolToStatusColored(result.Enabled), 22),
-	))
-	sb.WriteString("\n")
-
-	// Version
-	sb.WriteString(TableRowColored(
-		PadRight(IconInfo+" Version", 28),
-		PadRight(Info(result.Version), 22),
-	))
-	sb.WriteString("\n")
-
-	// Manufacturer
-	sb.WriteString(TableRowColored(
-		PadRight(IconDiamond+" Manufacturer", 28),
-		PadRight(result.Manufacturer, 22),
-	))
-	sb.WriteString("\n")
-
-	// Type
-	typeDisplay := result.Type
-	if result.Type == "tpm_2.0" {
-		typeDisplay = Success("TPM 2.0")
-	} else if result.Type == "tpm_1.2" {
-		typeDisplay = Warning("TPM 1.2")
+// applyTBSOnlyTPM fills in a TPM found through TBS when WMI was denied. A
+// TPM that answers TBS commands is present, enabled, and activated;
+// ownership cannot be told without WMI.
+func applyTBSOnlyTPM(ctx context.Context, result *TPMResult, details *tpmDetails) {
+	result.Present = true
+	result.Enabled = true
+	result.Activated = true
+	result.Version = "2.0"
+	result.Type = "tpm_2.0"
+	result.Manufacturer = details.Manufacturer
+	result.HardwareKeySupport = true
+	applyTPMDetails(result, details)
+	finishWindowsTPM(ctx, result)
+	// Ownership is unknown, so readiness cannot be judged
+	result.NotReadyReasons = slices.DeleteFunc(result.NotReadyReasons, func(r string) bool {
+		return r == tpmNotOwnedReason
+	})
+	result.Ready = false
+}
+
+// finishWindowsTPM derives readiness, attestation support, and the kind
+func finishWindowsTPM(ctx context.Context, result *TPMResult) {
+	result.NotReadyReasons = tpmNotReadyReasons(result.Present, result.Enabled, result.Activated, result.Owned, result.Lockout, result.Auth)
+	result.Ready = len(result.NotReadyReasons) == 0
+	result.AttestationCapable = tpmAttestationCapable(result.Type, result.Enabled, result.Auth)
+	if result.Present {
+		result.ManufacturerName = tpmVendorName(result.Manufacturer)
 	}
-	sb.WriteString(TableRowColored(
-		PadRight(IconChip+" Type", 28),
-		PadRight(typeDisplay, 22),
-	))
-	sb.WriteString("\n")
-
-	// Hardware Key Support
-	sb.WriteString(TableRowColored(
-		PadRight(IconKey+" Hardware Key Support", 28),
-		PadRight(BoolToStatusColored(result.HardwareKeySupport), 22),
-	))
-	sb.WriteString("\n")
-
-	sb.WriteString(TableBottom(28, 22))
-	sb.WriteString("\n\n")
-
-	// Capabilities section
-	if len(result.Capabilities) > 0 {
-		sb.WriteString(BoldText("Capabilities:"))
-		sb.WriteString("\n")
-		sb.WriteString(Muted(strings.Repeat("─", 35)))
-		sb.WriteString("\n")
-		for _, cap := range result.Capabilities {
-			sb.WriteString(fmt.Sprintf("  %s %s\n", Success(IconCheck), cap))
-		}
-		sb.WriteString("\n")
+	result.Kind = classifyTPMKind(result.Manufacturer, detectHypervisor(ctx).hypervisor != "")
+}
+
+// readWindowsTPMDetails opens the TPM via the TPM Base Services and reads its properties
+func readWindowsTPMDetails() (*tpmDetails, error) {
+	t, err := windowstpm.Open()
+	recordSource(SourceAPI, "TPM2 GetCapability via TPM Base Services", err)
+	if err != nil {
+		return nil, err
 	}
+	defer t.Close()
+	return readTPMDetails(t)
+}
+
+// FormatTPMTable formats TPM status as a colored table
+func FormatTPMTable(result *TPMResult) string {
+	return FormatTPMTableWith(CurrentStyler(), result)
+}
 
-	return sb.String()
+// FormatTPMTableWith is like FormatTPMTable but styles the table with st
+func FormatTPMTableWith(st Styler, result *TPMResult) string {
+	return formatWindowsTPMTable(st, result)
 }
 
 // FormatTPM formats TPM status in the specified format
 func FormatTPM(result *TPMResult, format string) string {
-	return FormatOutput(result, func() string {
-		return FormatTPMTable(result)
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatTPMTableWith(st, result)
 	}, format)
 }
 
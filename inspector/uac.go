@@ -0,0 +1,275 @@
+package inspector
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Registry keys read by the UAC check
+const (
+	uacPoliciesKey          = `HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Policies\System`
+	smartScreenPolicyKey    = `HKLM\SOFTWARE\Policies\Microsoft\Windows\System`
+	smartScreenExplorerKey  = `HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Explorer`
+	edgePolicyKey           = `HKLM\SOFTWARE\Policies\Microsoft\Edge`
+	edgeUserPolicyKey       = `HKCU\SOFTWARE\Policies\Microsoft\Edge`
+	uacDefaultAdminBehavior = 5
+)
+
+// SmartScreen states reported in UACResult
+const (
+	SmartScreenBlock = "block" // warns and does not let the user continue
+	SmartScreenWarn  = "warn"  // warns and lets the user continue
+	SmartScreenOff   = "off"
+)
+
+// Where a SmartScreen state comes from
+const (
+	SettingSourcePolicy  = "policy"
+	SettingSourceSetting = "setting"
+	SettingSourceDefault = "default"
+)
+
+// uacAdminBehaviors names the ConsentPromptBehaviorAdmin values
+var uacAdminBehaviors = map[uint64]string{
+	0: "elevate_without_prompting",
+	1: "credentials_on_secure_desktop",
+	2: "consent_on_secure_desktop",
+	3: "credentials",
+	4: "consent",
+	5: "consent_for_non_windows_binaries",
+}
+
+// UACResult reports User Account Control and SmartScreen settings
+type UACResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string `json:"platform"`
+	// Enabled is EnableLUA: UAC and Admin Approval Mode for administrators
+	Enabled bool `json:"enabled"`
+	// AdminPromptBehavior is how administrators are prompted to elevate
+	// (ConsentPromptBehaviorAdmin), e.g. "consent_on_secure_desktop"
+	AdminPromptBehavior string `json:"admin_prompt_behavior"`
+	AdminPromptLevel    int    `json:"admin_prompt_level"`
+	// SecureDesktop is true if elevation prompts dim the desktop
+	// (PromptOnSecureDesktop)
+	SecureDesktop bool `json:"secure_desktop"`
+	// BuiltinAdminApproval is Admin Approval Mode for the built-in
+	// Administrator account (FilterAdministratorToken)
+	BuiltinAdminApproval bool `json:"builtin_admin_approval"`
+
+	// SmartScreenApps is SmartScreen for apps and files in Explorer
+	SmartScreenApps       string `json:"smartscreen_apps"`
+	SmartScreenAppsSource string `json:"smartscreen_apps_source"`
+	// SmartScreenEdge is Microsoft Defender SmartScreen in Edge
+	SmartScreenEdge       string `json:"smartscreen_edge"`
+	SmartScreenEdgeSource string `json:"smartscreen_edge_source"`
+
+	// Protected is true if UAC prompts on the secure desktop and SmartScreen
+	// is on for apps and Edge
+	Protected bool        `json:"protected"`
+	Error     *ProbeError `json:"error,omitempty"`
+}
+
+// GetUACStatus returns the UAC and SmartScreen settings from the registry
+func GetUACStatus() (*UACResult, error) {
+	return GetUACStatusContext(context.Background())
+}
+
+// GetUACStatusContext is like GetUACStatus but runs the probes with ctx's
+// deadline, command runner, and logger
+func GetUACStatusContext(ctx context.Context) (*UACResult, error) {
+	if result, ok, err := loadFixture[UACResult](ctx, "uac"); ok {
+		return result, err
+	}
+	if !IsUACSupported() {
+		return nil, newProbeError(ctx, ErrUnsupportedPlatform, "uac", "UAC and SmartScreen are only checked on Windows")
+	}
+	return readUACStatus(ctx), nil
+}
+
+// IsUACSupported returns true on Windows
+func IsUACSupported() bool {
+	return runtime.GOOS == "windows"
+}
+
+// readUACStatus reads the settings through the current RegistryReader;
+// unset values take their Windows defaults
+func readUACStatus(ctx context.Context) *UACResult {
+	result := &UACResult{Platform: "windows"}
+	readInt := func(path, name string, def uint64) uint64 {
+		v, ok, err := registryInteger(ctx, path, name)
+		if err != nil && result.Error == nil {
+			result.Error = classifyRegistryError(ctx, path, err)
+		}
+		if !ok {
+			return def
+		}
+		return v
+	}
+
+	result.Enabled = readInt(uacPoliciesKey, "EnableLUA", 1) != 0
+	level := readInt(uacPoliciesKey, "ConsentPromptBehaviorAdmin", uacDefaultAdminBehavior)
+	result.AdminPromptLevel = int(level)
+	result.AdminPromptBehavior = uacAdminBehaviors[level]
+	if result.AdminPromptBehavior == "" {
+		result.AdminPromptBehavior = fmt.Sprintf("unknown_%d", level)
+	}
+	result.SecureDesktop = readInt(uacPoliciesKey, "PromptOnSecureDesktop", 1) != 0
+	result.BuiltinAdminApproval = readInt(uacPoliciesKey, "FilterAdministratorToken", 0) != 0
+
+	// SmartScreen for apps: Group Policy wins over the Windows Security setting
+	result.SmartScreenApps, result.SmartScreenAppsSource = SmartScreenWarn, SettingSourceDefault
+	if enabled, ok, _ := registryInteger(ctx, smartScreenPolicyKey, "EnableSmartScreen"); ok {
+		result.SmartScreenAppsSource = SettingSourcePolicy
+		result.SmartScreenApps = SmartScreenOff
+		if enabled != 0 {
+			result.SmartScreenApps = SmartScreenWarn
+			if level, _, _ := registryString(ctx, smartScreenPolicyKey, "ShellSmartScreenLevel"); strings.EqualFold(level, "Block") {
+				result.SmartScreenApps = SmartScreenBlock
+			}
+		}
+	} else if setting, ok, _ := registryString(ctx, smartScreenExplorerKey, "SmartScreenEnabled"); ok {
+		result.SmartScreenAppsSource = SettingSourceSetting
+		switch strings.ToLower(setting) {
+		case "off":
+			result.SmartScreenApps = SmartScreenOff
+		case "requireadmin":
+			result.SmartScreenApps = SmartScreenBlock
+		}
+	}
+
+	// SmartScreen in Edge is on unless a machine or user policy turns it off
+	result.SmartScreenEdge, result.SmartScreenEdgeSource = SmartScreenWarn, SettingSourceDefault
+	for _, key := range []string{edgePolicyKey, edgeUserPolicyKey} {
+		enabled, ok, _ := registryInteger(ctx, key, "SmartScreenEnabled")
+		if !ok {
+			continue
+		}
+		result.SmartScreenEdgeSource = SettingSourcePolicy
+		result.SmartScreenEdge = SmartScreenOff
+		if enabled != 0 {
+			result.SmartScreenEdge = SmartScreenWarn
+			if prevent, _, _ := registryInteger(ctx, key, "PreventSmartScreenPromptOverride"); prevent != 0 {
+				result.SmartScreenEdge = SmartScreenBlock
+			}
+		}
+		break
+	}
+
+	result.Protected = result.Enabled && level != 0 && result.SecureDesktop &&
+		result.SmartScreenApps != SmartScreenOff && result.SmartScreenEdge != SmartScreenOff
+	return result
+}
+
+// uacFindings returns the UAC and SmartScreen settings below the
+// recommended baseline
+func uacFindings(r *UACResult) []Finding {
+	if r.Error != nil {
+		return []Finding{unverifiedFinding("uac_unverified", CheckUAC, "UAC", r.Error)}
+	}
+	var findings []Finding
+	add := func(id, title, severity, remediation string) {
+		findings = append(findings, Finding{
+			ID:                 id,
+			Title:              title,
+			Severity:           severity,
+			Check:              CheckUAC,
+			Remediation:        remediation,
+			RemediationCommand: remediationCommand(id),
+		})
+	}
+	if !r.Enabled {
+		add("uac_disabled", T("User Account Control is turned off"), SeverityCritical,
+			T("Turn on User Account Control (EnableLUA) and restart"))
+	} else {
+		switch r.AdminPromptLevel {
+		case 0:
+			add("uac_no_prompt", T("Administrators are elevated without a prompt"), SeverityHigh,
+				T("Prompt administrators for consent on the secure desktop (ConsentPromptBehaviorAdmin=2)"))
+		case 1, 2:
+		default:
+			add("uac_prompt_below_baseline", T("UAC does not prompt for every elevation"), SeverityLow,
+				T("Prompt administrators for consent on the secure desktop (ConsentPromptBehaviorAdmin=2)"))
+		}
+		if !r.SecureDesktop {
+			add("uac_secure_desktop_disabled", T("UAC prompts are not shown on the secure desktop"), SeverityMedium,
+				T("Show elevation prompts on the secure desktop (PromptOnSecureDesktop=1)"))
+		}
+		if !r.BuiltinAdminApproval {
+			add("uac_builtin_admin_unfiltered", T("Admin Approval Mode is off for the built-in Administrator"), SeverityLow,
+				T("Turn on Admin Approval Mode for the built-in Administrator (FilterAdministratorToken=1)"))
+		}
+	}
+	if r.SmartScreenApps == SmartScreenOff {
+		add("smartscreen_apps_disabled", T("SmartScreen is turned off for apps and files"), SeverityMedium,
+			T("Turn on SmartScreen for apps and files in Windows Security or through Group Policy"))
+	}
+	if r.SmartScreenEdge == SmartScreenOff {
+		add("smartscreen_edge_disabled", T("SmartScreen is turned off in Microsoft Edge"), SeverityMedium,
+			T("Remove the policy that turns off SmartScreen in Microsoft Edge"))
+	}
+	return findings
+}
+
+// smartScreenStatus colors a SmartScreen state
+func smartScreenStatus(st Styler, state, source string) string {
+	s := st.Success(IconCheck + " " + state)
+	if state == SmartScreenOff {
+		s = st.Danger(IconCross + " " + state)
+	}
+	return s + st.Muted(" ("+source+")")
+}
+
+// FormatUACTable formats UAC and SmartScreen settings as a colored table
+func FormatUACTable(result *UACResult) string {
+	return FormatUACTableWith(CurrentStyler(), result)
+}
+
+// FormatUACTableWith is like FormatUACTable but styles the table with st
+func FormatUACTableWith(st Styler, result *UACResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " UAC and SmartScreen"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n")
+		if result.Error.Hint != "" {
+			sb.WriteString(st.Muted("  " + result.Error.Hint))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.TableTop(28, 36))
+	sb.WriteString("\n")
+	row := func(label, value string) {
+		sb.WriteString(st.TableRowColored(PadRight(label, 28), PadRight(value, 36)))
+		sb.WriteString("\n")
+	}
+	row("User Account Control", st.BoolToStatusColored(result.Enabled))
+	prompt := result.AdminPromptBehavior
+	if result.AdminPromptLevel == 0 {
+		prompt = st.Danger(prompt)
+	}
+	row("Admin Prompt", prompt)
+	row("Secure Desktop", st.BoolToStatusColored(result.SecureDesktop))
+	row("Built-in Admin Approval", st.BoolToStatusColored(result.BuiltinAdminApproval))
+	row("SmartScreen (apps)", smartScreenStatus(st, result.SmartScreenApps, result.SmartScreenAppsSource))
+	row("SmartScreen (Edge)", smartScreenStatus(st, result.SmartScreenEdge, result.SmartScreenEdgeSource))
+	sb.WriteString(st.TableBottom(28, 36))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// FormatUAC formats UAC and SmartScreen settings in the specified format
+func FormatUAC(result *UACResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatUACTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,95 @@
+package inspector
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestReadUACStatusDefaults(t *testing.T) {
+	defer SetRegistryReader(SetRegistryReader(NewFakeRegistry()))
+
+	r := readUACStatus(context.Background())
+	if !r.Enabled || r.AdminPromptLevel != 5 || !r.SecureDesktop || r.BuiltinAdminApproval {
+		t.Errorf("unset values should take the Windows defaults: %+v", r)
+	}
+	if r.SmartScreenApps != SmartScreenWarn || r.SmartScreenAppsSource != SettingSourceDefault ||
+		r.SmartScreenEdge != SmartScreenWarn || r.SmartScreenEdgeSource != SettingSourceDefault {
+		t.Errorf("SmartScreen = %s (%s), %s (%s)", r.SmartScreenApps, r.SmartScreenAppsSource, r.SmartScreenEdge, r.SmartScreenEdgeSource)
+	}
+	if !r.Protected || r.Error != nil {
+		t.Errorf("a default install should be protected: %+v", r)
+	}
+}
+
+func TestReadUACStatus(t *testing.T) {
+	fake := NewFakeRegistry().
+		SetInteger(uacPoliciesKey, "ConsentPromptBehaviorAdmin", 0).
+		SetInteger(uacPoliciesKey, "FilterAdministratorToken", 1).
+		SetInteger(smartScreenPolicyKey, "EnableSmartScreen", 1).
+		SetString(smartScreenPolicyKey, "ShellSmartScreenLevel", "Block").
+		SetString(smartScreenExplorerKey, "SmartScreenEnabled", "Off").
+		SetInteger(edgeUserPolicyKey, "SmartScreenEnabled", 0)
+	defer SetRegistryReader(SetRegistryReader(fake))
+
+	r := readUACStatus(context.Background())
+	if r.AdminPromptBehavior != "elevate_without_prompting" || !r.BuiltinAdminApproval {
+		t.Errorf("UAC = %+v", r)
+	}
+	if r.SmartScreenApps != SmartScreenBlock || r.SmartScreenAppsSource != SettingSourcePolicy {
+		t.Errorf("policy should win over the Explorer setting: %s (%s)", r.SmartScreenApps, r.SmartScreenAppsSource)
+	}
+	if r.SmartScreenEdge != SmartScreenOff || r.SmartScreenEdgeSource != SettingSourcePolicy {
+		t.Errorf("Edge = %s (%s)", r.SmartScreenEdge, r.SmartScreenEdgeSource)
+	}
+	if r.Protected {
+		t.Error("no prompt and SmartScreen off in Edge should not be protected")
+	}
+
+	fake.SetError(uacPoliciesKey, "EnableLUA", errors.New("Access is denied."))
+	if r := readUACStatus(context.Background()); r.Error == nil || !errors.Is(r.Error, ErrPermissionDenied) {
+		t.Errorf("error = %v, want permission denied", r.Error)
+	}
+}
+
+func TestUACFindings(t *testing.T) {
+	ids := func(findings []Finding) []string {
+		var got []string
+		for _, f := range findings {
+			if f.Check != CheckUAC {
+				t.Errorf("finding %s has check %q", f.ID, f.Check)
+			}
+			got = append(got, f.ID)
+		}
+		return got
+	}
+
+	baseline := &UACResult{
+		Enabled: true, AdminPromptLevel: 2, SecureDesktop: true, BuiltinAdminApproval: true,
+		SmartScreenApps: SmartScreenWarn, SmartScreenEdge: SmartScreenBlock,
+	}
+	if f := uacFindings(baseline); len(f) != 0 {
+		t.Errorf("baseline settings should have no findings, got %v", ids(f))
+	}
+
+	weak := &UACResult{
+		Enabled: true, AdminPromptLevel: 0, SecureDesktop: false,
+		SmartScreenApps: SmartScreenOff, SmartScreenEdge: SmartScreenOff,
+	}
+	want := []string{"uac_no_prompt", "uac_secure_desktop_disabled", "uac_builtin_admin_unfiltered",
+		"smartscreen_apps_disabled", "smartscreen_edge_disabled"}
+	if got := ids(uacFindings(weak)); !slices.Equal(got, want) {
+		t.Errorf("findings = %v, want %v", got, want)
+	}
+
+	if got := ids(uacFindings(&UACResult{AdminPromptLevel: 5, SmartScreenApps: SmartScreenWarn, SmartScreenEdge: SmartScreenWarn})); !slices.Equal(got, []string{"uac_disabled"}) {
+		t.Errorf("UAC off should only report uac_disabled, got %v", got)
+	}
+	if got := ids(uacFindings(&UACResult{Enabled: true, AdminPromptLevel: 5, SecureDesktop: true, BuiltinAdminApproval: true})); !slices.Contains(got, "uac_prompt_below_baseline") {
+		t.Errorf("the default prompt level is below the baseline, got %v", got)
+	}
+	if got := ids(uacFindings(&UACResult{Error: newProbeError(context.Background(), ErrPermissionDenied, "uac", "denied")})); !slices.Equal(got, []string{"uac_unverified"}) {
+		t.Errorf("findings = %v, want uac_unverified", got)
+	}
+}
@@ -0,0 +1,38 @@
+//go:build linux || darwin
+
+package inspector
+
+import "golang.org/x/sys/unix"
+
+// ulimitResources are the limits reported by GetFDUsage, in display order
+var ulimitResources = []struct {
+	name     string
+	resource int
+}{
+	{"nofile", unix.RLIMIT_NOFILE},
+	{"nproc", unix.RLIMIT_NPROC},
+	{"memlock", unix.RLIMIT_MEMLOCK},
+	{"stack", unix.RLIMIT_STACK},
+	{"core", unix.RLIMIT_CORE},
+}
+
+// processUlimits returns this process's resource limits
+func processUlimits() []Ulimit {
+	var limits []Ulimit
+	for _, r := range ulimitResources {
+		var rlim unix.Rlimit
+		if err := unix.Getrlimit(r.resource, &rlim); err != nil {
+			continue
+		}
+		limits = append(limits, Ulimit{Resource: r.name, Soft: rlimitValue(rlim.Cur), Hard: rlimitValue(rlim.Max)})
+	}
+	return limits
+}
+
+// rlimitValue converts RLIM_INFINITY to -1
+func rlimitValue(v uint64) int64 {
+	if v == unix.RLIM_INFINITY || v > 1<<62 {
+		return -1
+	}
+	return int64(v)
+}
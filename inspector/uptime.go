@@ -0,0 +1,340 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/host"
+)
+
+// rebootPendingGraceDays is how long a reboot may stay pending before the
+// finding is raised to high severity
+const rebootPendingGraceDays = 7
+
+// Registry keys whose RebootPending and RebootRequired subkeys Windows
+// creates when servicing needs a restart
+const (
+	cbsKey           = `HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Component Based Servicing`
+	windowsUpdateKey = `HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\WindowsUpdate\Auto Update`
+)
+
+// UptimeResult reports how long the machine has been up and whether a
+// reboot is pending to finish installing updates
+type UptimeResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform      string    `json:"platform"`
+	BootTime      time.Time `json:"boot_time"`
+	UptimeSeconds uint64    `json:"uptime_seconds"`
+	UptimeHuman   string    `json:"uptime_human"`
+	RebootPending bool      `json:"reboot_pending"`
+	// RebootReasons names what signals the pending reboot, e.g.
+	// /var/run/reboot-required or Component Based Servicing
+	RebootReasons []string `json:"reboot_reasons"`
+	// Packages lists the updates waiting for the reboot, where known
+	Packages []string `json:"packages"`
+	// PendingSince is when the reboot became pending, where known
+	PendingSince *time.Time  `json:"pending_since,omitempty"`
+	PendingDays  int         `json:"pending_days,omitempty"`
+	Error        *ProbeError `json:"error,omitempty"`
+}
+
+// GetUptime returns the uptime, last boot time, and whether a reboot is
+// pending for updates: /var/run/reboot-required, needs-restarting, or a
+// removed running kernel on Linux, the CBS and Windows Update reboot keys
+// on Windows, and available updates that require a restart on macOS
+func GetUptime() (*UptimeResult, error) {
+	return GetUptimeContext(context.Background())
+}
+
+// GetUptimeContext is like GetUptime but runs the probes with ctx's
+// deadline, command runner, and logger
+func GetUptimeContext(ctx context.Context) (*UptimeResult, error) {
+	if result, ok, err := loadFixture[UptimeResult](ctx, "uptime"); ok {
+		return result, err
+	}
+	boot, err := host.BootTimeWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get boot time: %w", err)
+	}
+	result := &UptimeResult{Platform: runtime.GOOS}
+	setUptime(result, time.Unix(int64(boot), 0), time.Now())
+
+	var p pendingReboot
+	switch runtime.GOOS {
+	case "linux":
+		p = linuxPendingReboot(os.DirFS("/"))
+		np := needsRestarting(ctx)
+		p.reasons = append(p.reasons, np.reasons...)
+		p.packages = append(p.packages, np.packages...)
+		p.err = np.err
+	case "windows":
+		p = windowsPendingReboot(ctx)
+	case "darwin":
+		p = darwinPendingReboot(ctx)
+	}
+	applyPendingReboot(result, p, time.Now())
+	return result, nil
+}
+
+// pendingReboot is what a platform reports about a pending reboot
+type pendingReboot struct {
+	reasons  []string
+	packages []string
+	since    time.Time
+	err      *ProbeError
+}
+
+// setUptime fills the boot time and uptime
+func setUptime(result *UptimeResult, boot, now time.Time) {
+	result.BootTime = boot.UTC()
+	uptime := now.Sub(boot)
+	if uptime < 0 {
+		uptime = 0
+	}
+	result.UptimeSeconds = uint64(uptime / time.Second)
+	result.UptimeHuman = formatUptime(uptime)
+}
+
+// applyPendingReboot fills the pending reboot fields
+func applyPendingReboot(result *UptimeResult, p pendingReboot, now time.Time) {
+	result.Error = p.err
+	result.RebootReasons = p.reasons
+	if result.RebootReasons == nil {
+		result.RebootReasons = []string{}
+	}
+	slices.Sort(p.packages)
+	result.Packages = slices.Compact(p.packages)
+	if result.Packages == nil {
+		result.Packages = []string{}
+	}
+	result.RebootPending = len(result.RebootReasons) > 0
+	if result.RebootPending && !p.since.IsZero() {
+		since := p.since.UTC()
+		result.PendingSince = &since
+		result.PendingDays = int(now.Sub(p.since).Hours() / 24)
+	}
+}
+
+// linuxPendingReboot checks the reboot-required marker Debian and Ubuntu
+// write after updates, and whether the running kernel's modules were
+// removed by a kernel upgrade
+func linuxPendingReboot(fsys fs.FS) pendingReboot {
+	var p pendingReboot
+	if info, err := fs.Stat(fsys, "var/run/reboot-required"); err == nil {
+		p.reasons = append(p.reasons, "/var/run/reboot-required")
+		p.since = info.ModTime()
+		if data, err := readFSFile(fsys, "var/run/reboot-required.pkgs"); err == nil {
+			p.packages = append(p.packages, strings.Fields(string(data))...)
+		}
+	}
+	if data, err := readFSFile(fsys, "proc/sys/kernel/osrelease"); err == nil {
+		release := strings.TrimSpace(string(data))
+		if _, err := fs.Stat(fsys, "lib/modules"); err == nil && release != "" {
+			if _, err := fs.Stat(fsys, "lib/modules/"+release); errors.Is(err, fs.ErrNotExist) {
+				p.reasons = append(p.reasons, "running kernel "+release+" is no longer installed")
+			}
+		}
+	}
+	return p
+}
+
+// needsRestarting asks dnf's needs-restarting on Fedora and RHEL, which
+// exits 1 when core packages were updated since boot
+func needsRestarting(ctx context.Context) pendingReboot {
+	var p pendingReboot
+	if _, err := lookPath(ctx, "needs-restarting"); err != nil {
+		return p
+	}
+	out, err := runCommand(ctx, "needs-restarting", "-r")
+	if err == nil {
+		return p
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || len(bytes.TrimSpace(exitErr.Stderr)) > 0 {
+		p.err = classifyExecError(ctx, "needs-restarting", err)
+		return p
+	}
+	p.reasons = append(p.reasons, "needs-restarting -r")
+	p.packages = parseNeedsRestarting(out)
+	return p
+}
+
+// parseNeedsRestarting returns the packages listed as "  * name" by
+// needs-restarting -r
+func parseNeedsRestarting(out []byte) []string {
+	var packages []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "* "); ok {
+			packages = append(packages, strings.TrimSpace(name))
+		}
+	}
+	return packages
+}
+
+// windowsPendingReboot checks the keys Component Based Servicing and
+// Windows Update create while a restart is needed
+func windowsPendingReboot(ctx context.Context) pendingReboot {
+	var p pendingReboot
+	for _, k := range []struct{ parent, key, reason string }{
+		{cbsKey, "RebootPending", "Component Based Servicing"},
+		{windowsUpdateKey, "RebootRequired", "Windows Update"},
+	} {
+		keys, err := registrySubKeys(ctx, k.parent)
+		if err != nil {
+			if p.err == nil {
+				p.err = classifyRegistryError(ctx, k.parent, err)
+			}
+			continue
+		}
+		if slices.Contains(keys, k.key) {
+			p.reasons = append(p.reasons, k.reason)
+		}
+	}
+	return p
+}
+
+// darwinPendingReboot lists the available updates that require a restart,
+// from softwareupdate's last scan
+func darwinPendingReboot(ctx context.Context) pendingReboot {
+	var p pendingReboot
+	out, err := runCommand(ctx, "softwareupdate", "--list", "--no-scan")
+	if err != nil {
+		p.err = classifyExecError(ctx, "softwareupdate", err)
+		return p
+	}
+	if p.packages = parseSoftwareUpdateRestart(out); len(p.packages) > 0 {
+		p.reasons = append(p.reasons, "softwareupdate")
+	}
+	return p
+}
+
+// parseSoftwareUpdateRestart returns the labels of the updates listed by
+// `softwareupdate --list` whose details say "Action: restart"
+func parseSoftwareUpdateRestart(out []byte) []string {
+	var labels []string
+	var label string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if l, ok := strings.CutPrefix(line, "* Label:"); ok {
+			label = strings.TrimSpace(l)
+			continue
+		}
+		if label != "" && strings.Contains(line, "Action: restart") {
+			labels = append(labels, label)
+			label = ""
+		}
+	}
+	return labels
+}
+
+// formatUptime formats a duration as days, hours, and minutes
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
+
+// uptimeFindings returns a pending reboot as a finding
+func uptimeFindings(r *UptimeResult) []Finding {
+	if r.Error != nil && !r.RebootPending {
+		return []Finding{unverifiedFinding("uptime_unverified", CheckUptime, "Pending reboot", r.Error)}
+	}
+	if !r.RebootPending {
+		return nil
+	}
+	title := T("A reboot is pending to finish installing updates")
+	severity := SeverityMedium
+	if r.PendingDays > 0 {
+		title = T("A reboot has been pending for %d days to finish installing updates", r.PendingDays)
+	}
+	if r.PendingDays > rebootPendingGraceDays {
+		severity = SeverityHigh
+	}
+	return []Finding{{
+		ID:                 "reboot_pending",
+		Title:              title,
+		Severity:           severity,
+		Check:              CheckUptime,
+		Remediation:        T("Restart the machine to finish installing updates"),
+		RemediationCommand: remediationCommand("reboot_pending"),
+		Disruptive:         remediationDisruptive("reboot_pending"),
+	}}
+}
+
+// FormatUptimeTable formats uptime and pending reboots as a colored table
+func FormatUptimeTable(result *UptimeResult) string {
+	return FormatUptimeTableWith(CurrentStyler(), result)
+}
+
+// FormatUptimeTableWith is like FormatUptimeTable but styles the table with
+// st
+func FormatUptimeTableWith(st Styler, result *UptimeResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconStatus + " Uptime"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n")
+		if result.Error.Hint != "" {
+			sb.WriteString(st.Muted("  " + result.Error.Hint))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.TableTop(20, 30))
+	sb.WriteString("\n")
+	row := func(label, value string) {
+		sb.WriteString(st.TableRowColored(PadRight(label, 20), PadRight(value, 30)))
+		sb.WriteString("\n")
+	}
+	row("Uptime", st.Info(Truncate(result.UptimeHuman, 30)))
+	row("Last Boot", result.BootTime.Local().Format("2006-01-02 15:04 MST"))
+	pending := st.Success(IconCheck + " no")
+	if result.RebootPending {
+		pending = st.Danger(IconCross + " yes")
+		if result.PendingDays > 0 {
+			pending = st.Danger(fmt.Sprintf("%s yes, %d days", IconCross, result.PendingDays))
+		}
+	}
+	row("Reboot Pending", pending)
+	sb.WriteString(st.TableBottom(20, 30))
+	sb.WriteString("\n")
+
+	for _, reason := range result.RebootReasons {
+		sb.WriteString(fmt.Sprintf("  %s %s\n", IconArrow, reason))
+	}
+	if len(result.Packages) > 0 {
+		sb.WriteString(st.Muted("  Updates waiting: " + strings.Join(result.Packages, ", ")))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// FormatUptime formats uptime and pending reboots in the specified format
+func FormatUptime(result *UptimeResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatUptimeTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,176 @@
+package inspector
+
+import (
+	"context"
+	"io/fs"
+	"os/exec"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLinuxPendingReboot(t *testing.T) {
+	marked := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"var/run/reboot-required":      {Data: []byte("*** System restart required ***\n"), ModTime: marked},
+		"var/run/reboot-required.pkgs": {Data: []byte("linux-image-6.8.0-52-generic\nlibc6\n")},
+		"proc/sys/kernel/osrelease":    {Data: []byte("6.8.0-49-generic\n")},
+		"lib/modules/6.8.0-52-generic": {Mode: fs.ModeDir | 0o755},
+	}
+
+	p := linuxPendingReboot(fsys)
+	want := []string{"/var/run/reboot-required", "running kernel 6.8.0-49-generic is no longer installed"}
+	if !slices.Equal(p.reasons, want) {
+		t.Errorf("reasons = %q, want %q", p.reasons, want)
+	}
+	if !slices.Equal(p.packages, []string{"linux-image-6.8.0-52-generic", "libc6"}) {
+		t.Errorf("packages = %q", p.packages)
+	}
+	if !p.since.Equal(marked) {
+		t.Errorf("since = %v, want %v", p.since, marked)
+	}
+
+	result := &UptimeResult{}
+	applyPendingReboot(result, p, marked.Add(10*24*time.Hour))
+	if !result.RebootPending || result.PendingDays != 10 || result.PendingSince == nil {
+		t.Fatalf("result = %+v, want pending for 10 days", result)
+	}
+	if !slices.Equal(result.Packages, []string{"libc6", "linux-image-6.8.0-52-generic"}) {
+		t.Errorf("Packages = %q, want sorted", result.Packages)
+	}
+}
+
+func TestLinuxPendingReboot_Current(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/sys/kernel/osrelease":    {Data: []byte("6.8.0-52-generic\n")},
+		"lib/modules/6.8.0-52-generic": {Mode: fs.ModeDir | 0o755},
+	}
+	if p := linuxPendingReboot(fsys); p.reasons != nil {
+		t.Errorf("reasons = %q, want none", p.reasons)
+	}
+	// Containers often have no /lib/modules at all
+	if p := linuxPendingReboot(fstest.MapFS{"proc/sys/kernel/osrelease": {Data: []byte("6.8.0\n")}}); p.reasons != nil {
+		t.Errorf("reasons = %q without /lib/modules, want none", p.reasons)
+	}
+}
+
+func TestNeedsRestarting(t *testing.T) {
+	out := []byte("Core libraries or services have been updated since boot-up:\n  * kernel\n  * systemd\n\nReboot is required to fully utilize these updates.\n")
+	if got := parseNeedsRestarting(out); !slices.Equal(got, []string{"kernel", "systemd"}) {
+		t.Errorf("parseNeedsRestarting = %q", got)
+	}
+	// Translated headings do not matter, only the package list
+	out = []byte("Kernbibliotheken oder Dienste wurden seit dem Systemstart aktualisiert:\n  * kernel\n\nEin Neustart ist erforderlich, um diese Aktualisierungen vollständig zu nutzen.\n")
+	if got := parseNeedsRestarting(out); !slices.Equal(got, []string{"kernel"}) {
+		t.Errorf("parseNeedsRestarting(German) = %q", got)
+	}
+
+	// Exit status 1 without stderr means a reboot is needed
+	fake := NewFakeRunner().SetError("needs-restarting -r", &exec.ExitError{})
+	defer SetCommandRunner(SetCommandRunner(fake))
+	if p := needsRestarting(context.Background()); p.err != nil || !slices.Equal(p.reasons, []string{"needs-restarting -r"}) {
+		t.Errorf("pending = %+v", p)
+	}
+
+	SetCommandRunner(NewFakeRunner().Set("needs-restarting -r", []byte("No core libraries or services have been updated since boot-up.\n")))
+	if p := needsRestarting(context.Background()); p.err != nil || p.reasons != nil {
+		t.Errorf("up to date = %+v", p)
+	}
+
+	SetCommandRunner(NewFakeRunner().SetError("needs-restarting -r", &exec.ExitError{Stderr: []byte("Error: This command has to be run with superuser privileges\n")}))
+	if p := needsRestarting(context.Background()); p.err == nil || p.reasons != nil {
+		t.Errorf("failure = %+v, want an error", p)
+	}
+
+	SetCommandRunner(NewFakeRunner())
+	if p := needsRestarting(context.Background()); p.err != nil || p.reasons != nil {
+		t.Errorf("not installed = %+v, want nothing", p)
+	}
+}
+
+func TestWindowsPendingReboot(t *testing.T) {
+	fake := NewFakeRegistry().
+		SetInteger(cbsKey+`\RebootPending`, "", 0).
+		SetInteger(cbsKey+`\SessionsPending`, "Exclusive", 0).
+		SetString(windowsUpdateKey+`\Results\Install`, "LastSuccessTime", "2026-03-01 06:00:00")
+	defer SetRegistryReader(SetRegistryReader(fake))
+
+	p := windowsPendingReboot(context.Background())
+	if p.err != nil || !slices.Equal(p.reasons, []string{"Component Based Servicing"}) {
+		t.Errorf("pending = %+v, want Component Based Servicing", p)
+	}
+
+	fake.SetInteger(windowsUpdateKey+`\RebootRequired`, "{5f6e}", 1)
+	if p := windowsPendingReboot(context.Background()); !slices.Equal(p.reasons, []string{"Component Based Servicing", "Windows Update"}) {
+		t.Errorf("reasons = %q", p.reasons)
+	}
+}
+
+func TestParseSoftwareUpdateRestart(t *testing.T) {
+	out := []byte(`Software Update Tool
+
+Software Update found the following new or updated software:
+* Label: macOS Sequoia 15.3.1-24D70
+	Title: macOS Sequoia 15.3.1, Version: 15.3.1, Size: 1536000KiB, Recommended: YES, Action: restart,
+* Label: Safari18.3.1SequoiaAuto-18.3.1
+	Title: Safari, Version: 18.3.1, Size: 189000KiB, Recommended: YES,
+* Label: Command Line Tools for Xcode-16.2
+	Title: Command Line Tools for Xcode, Version: 16.2, Size: 878000KiB, Recommended: YES,
+`)
+	if got := parseSoftwareUpdateRestart(out); !slices.Equal(got, []string{"macOS Sequoia 15.3.1-24D70"}) {
+		t.Errorf("parseSoftwareUpdateRestart = %q", got)
+	}
+}
+
+func TestUptimeFindings(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   UptimeResult
+		id       string
+		severity string
+	}{
+		{"none", UptimeResult{}, "", ""},
+		{"pending", UptimeResult{RebootPending: true, RebootReasons: []string{"softwareupdate"}}, "reboot_pending", SeverityMedium},
+		{"within grace", UptimeResult{RebootPending: true, PendingDays: rebootPendingGraceDays}, "reboot_pending", SeverityMedium},
+		{"overdue", UptimeResult{RebootPending: true, PendingDays: 30}, "reboot_pending", SeverityHigh},
+		{"unverified", UptimeResult{Error: &ProbeError{Code: CodePermissionDenied, Message: "access denied"}}, "uptime_unverified", SeverityMedium},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := uptimeFindings(&tt.result)
+			if tt.id == "" {
+				if len(findings) != 0 {
+					t.Errorf("findings = %+v, want none", findings)
+				}
+				return
+			}
+			if len(findings) != 1 || findings[0].ID != tt.id || findings[0].Severity != tt.severity {
+				t.Errorf("findings = %+v, want %s (%s)", findings, tt.id, tt.severity)
+			}
+		})
+	}
+}
+
+func TestFormatUptime(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{42 * time.Minute, "0h 42m"},
+		{5*time.Hour + 3*time.Minute, "5h 3m"},
+		{3*24*time.Hour + 2*time.Hour + 15*time.Minute, "3d 2h 15m"},
+	}
+	for _, tt := range tests {
+		if got := formatUptime(tt.d); got != tt.want {
+			t.Errorf("formatUptime(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+
+	result := &UptimeResult{}
+	boot := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	setUptime(result, boot, boot.Add(90*time.Minute))
+	if result.UptimeSeconds != 5400 || result.UptimeHuman != "1h 30m" {
+		t.Errorf("setUptime = %+v", result)
+	}
+}
@@ -0,0 +1,526 @@
+package inspector
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// USBStoragePolicyEnv sets the removable-media policy the USB storage check
+// enforces: "allow" (the default) only reports USB storage, "block" scores
+// the check and raises a finding while USB mass storage is unrestricted
+const USBStoragePolicyEnv = "OMNITRUST_USB_STORAGE_POLICY"
+
+// USB storage policies
+const (
+	USBStoragePolicyAllow = "allow"
+	USBStoragePolicyBlock = "block"
+)
+
+// usbMassStorageClass is the USB interface class of mass storage devices
+const usbMassStorageClass = "08"
+
+// Registry locations that disable USB mass storage on Windows
+const (
+	usbstorServiceKey   = `HKLM\SYSTEM\CurrentControlSet\Services\USBSTOR`
+	removableStorageKey = `HKLM\SOFTWARE\Policies\Microsoft\Windows\RemovableStorageDevices`
+	// removableDisksKey is the policy for the removable disk device class
+	removableDisksKey = removableStorageKey + `\{53f5630d-b6bf-11d0-94f2-00a0c91efb8b}`
+)
+
+// systemUIServerPrefs holds the mount-controls a configuration profile sets
+// on macOS
+const systemUIServerPrefs = "/Library/Managed Preferences/com.apple.systemuiserver.plist"
+
+// modprobeDirs are the directories modprobe reads configuration from
+var modprobeDirs = []string{"etc/modprobe.d", "run/modprobe.d", "usr/lib/modprobe.d", "lib/modprobe.d"}
+
+// USBDevice is a connected USB device
+type USBDevice struct {
+	// VendorID and ProductID are four lowercase hex digits, e.g. "0781"
+	VendorID  string `json:"vendor_id"`
+	ProductID string `json:"product_id"`
+	Vendor    string `json:"vendor,omitempty"`
+	Product   string `json:"product,omitempty"`
+	// Storage is true for USB mass storage devices
+	Storage bool `json:"storage"`
+}
+
+// USBDevicesResult reports whether USB mass storage is restricted and the
+// USB devices currently connected
+type USBDevicesResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform string `json:"platform"`
+	// Policy is the configured removable-media policy, allow or block
+	Policy string `json:"policy"`
+	// StorageRestricted is true if the OS is configured to block USB mass
+	// storage; RestrictedBy names the mechanisms that block it
+	StorageRestricted bool     `json:"storage_restricted"`
+	RestrictedBy      []string `json:"restricted_by"`
+	// ModuleLoaded is true on Linux while the usb_storage module is loaded,
+	// in which case modprobe rules have not taken effect yet
+	ModuleLoaded bool        `json:"module_loaded,omitempty"`
+	Devices      []USBDevice `json:"devices"`
+	// StorageDevices counts the connected mass storage devices
+	StorageDevices int `json:"storage_devices"`
+	// Compliant is true unless the policy is block and USB mass storage is
+	// unrestricted
+	Compliant bool        `json:"compliant"`
+	Error     *ProbeError `json:"error,omitempty"`
+}
+
+// USBStoragePolicy returns the removable-media policy set by
+// OMNITRUST_USB_STORAGE_POLICY: block, or allow for anything else
+func USBStoragePolicy() string {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv(USBStoragePolicyEnv)), USBStoragePolicyBlock) {
+		return USBStoragePolicyBlock
+	}
+	return USBStoragePolicyAllow
+}
+
+// GetUSBDevices reports whether USB mass storage is restricted (modprobe
+// rules and USBGuard on Linux, the USBSTOR driver and removable storage
+// policies on Windows, managed mount-controls on macOS) and lists the
+// connected USB devices with their vendor and product IDs
+func GetUSBDevices() (*USBDevicesResult, error) {
+	return GetUSBDevicesContext(context.Background())
+}
+
+// GetUSBDevicesContext is like GetUSBDevices but runs the probes with ctx's
+// deadline, command runner, and logger
+func GetUSBDevicesContext(ctx context.Context) (*USBDevicesResult, error) {
+	if result, ok, err := loadFixture[USBDevicesResult](ctx, "usb"); ok {
+		return result, err
+	}
+	result := &USBDevicesResult{Platform: runtime.GOOS, Policy: USBStoragePolicy()}
+	switch runtime.GOOS {
+	case "linux":
+		root := os.DirFS("/")
+		result.RestrictedBy, result.ModuleLoaded = linuxUSBStorageRestrictions(root)
+		result.Devices = linuxUSBDevices(root)
+	case "windows":
+		result.RestrictedBy, result.Error = windowsUSBStorageRestrictions(ctx)
+		devices, perr := windowsUSBDevices(ctx)
+		result.Devices = devices
+		if result.Error == nil {
+			result.Error = perr
+		}
+	case "darwin":
+		result.RestrictedBy = darwinUSBStorageRestrictions(ctx)
+		out, err := runCommand(ctx, "system_profiler", "SPUSBDataType", "-json")
+		if err != nil {
+			result.Error = classifyExecError(ctx, "system_profiler", err)
+			break
+		}
+		devices, err := parseSystemProfilerUSB(out)
+		if err != nil {
+			result.Error = newProbeError(ctx, ErrProbeFailed, "system_profiler", "unexpected output: "+err.Error())
+		}
+		result.Devices = devices
+	default:
+		return nil, newProbeError(ctx, ErrUnsupportedPlatform, "usb", "USB devices are not supported on "+runtime.GOOS)
+	}
+	finishUSBDevices(result)
+	return result, nil
+}
+
+// finishUSBDevices sorts the devices and fills the derived fields
+func finishUSBDevices(result *USBDevicesResult) {
+	if result.RestrictedBy == nil {
+		result.RestrictedBy = []string{}
+	}
+	if result.Devices == nil {
+		result.Devices = []USBDevice{}
+	}
+	slices.SortFunc(result.Devices, func(a, b USBDevice) int {
+		return strings.Compare(a.VendorID+a.ProductID+a.Product, b.VendorID+b.ProductID+b.Product)
+	})
+	result.StorageDevices = 0
+	for _, d := range result.Devices {
+		if d.Storage {
+			result.StorageDevices++
+		}
+	}
+	result.StorageRestricted = len(result.RestrictedBy) > 0
+	result.Compliant = result.Policy != USBStoragePolicyBlock || result.StorageRestricted
+}
+
+// linuxUSBStorageRestrictions reads modprobe rules that keep usb_storage
+// from loading and USBGuard's default policy. A modprobe rule does not count
+// while the module is already loaded.
+func linuxUSBStorageRestrictions(root fs.FS) (restrictedBy []string, loaded bool) {
+	if data, err := readFSFile(root, "proc/modules"); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if name, _, _ := strings.Cut(line, " "); name == "usb_storage" {
+				loaded = true
+			}
+		}
+	}
+	if !loaded {
+		for _, dir := range modprobeDirs {
+			entries, err := fs.ReadDir(root, dir)
+			if err != nil {
+				continue
+			}
+			for _, e := range entries {
+				if e.IsDir() || !strings.HasSuffix(e.Name(), ".conf") {
+					continue
+				}
+				data, err := readFSFile(root, path.Join(dir, e.Name()))
+				if err != nil {
+					continue
+				}
+				for _, rule := range parseModprobeUSBStorage(data) {
+					restrictedBy = append(restrictedBy, rule+" (/"+path.Join(dir, e.Name())+")")
+				}
+			}
+		}
+	}
+	if data, err := readFSFile(root, "etc/usbguard/usbguard-daemon.conf"); err == nil {
+		if target := iniValue(data, "ImplicitPolicyTarget"); target == "block" || target == "reject" {
+			restrictedBy = append(restrictedBy, "USBGuard ImplicitPolicyTarget="+target)
+		}
+	}
+	return restrictedBy, loaded
+}
+
+// parseModprobeUSBStorage returns the modprobe.d rules that stop usb_storage
+// from loading: an install command that does nothing (/bin/true or
+// /bin/false) or a blacklist entry, which stops hotplug from loading it
+func parseModprobeUSBStorage(data []byte) []string {
+	var rules []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.ReplaceAll(fields[1], "-", "_") != "usb_storage" {
+			continue
+		}
+		switch fields[0] {
+		case "blacklist":
+			rules = append(rules, "blacklist usb-storage")
+		case "install":
+			if len(fields) == 3 && slices.Contains([]string{"true", "false"}, path.Base(fields[2])) {
+				rules = append(rules, "install usb-storage "+fields[2])
+			}
+		}
+	}
+	return rules
+}
+
+// iniValue returns the value of a key=value line, ignoring comments
+func iniValue(data []byte, key string) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok && strings.TrimSpace(k) == key {
+			return strings.ToLower(strings.TrimSpace(v))
+		}
+	}
+	return ""
+}
+
+// linuxUSBDevices lists USB devices from /sys/bus/usb/devices. Entries with
+// a colon are interfaces, used to tell which devices are mass storage; root
+// hubs (usbN) are skipped.
+func linuxUSBDevices(root fs.FS) []USBDevice {
+	const dir = "sys/bus/usb/devices"
+	entries, err := fs.ReadDir(root, dir)
+	if err != nil {
+		return nil
+	}
+	attr := func(name, file string) string {
+		data, err := readFSFile(root, path.Join(dir, name, file))
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+	storage := make(map[string]bool)
+	for _, e := range entries {
+		if device, _, ok := strings.Cut(e.Name(), ":"); ok && attr(e.Name(), "bInterfaceClass") == usbMassStorageClass {
+			storage[device] = true
+		}
+	}
+	var devices []USBDevice
+	for _, e := range entries {
+		name := e.Name()
+		if strings.Contains(name, ":") || strings.HasPrefix(name, "usb") {
+			continue
+		}
+		vendorID, productID := attr(name, "idVendor"), attr(name, "idProduct")
+		if vendorID == "" || productID == "" {
+			continue
+		}
+		devices = append(devices, USBDevice{
+			VendorID:  strings.ToLower(vendorID),
+			ProductID: strings.ToLower(productID),
+			Vendor:    attr(name, "manufacturer"),
+			Product:   attr(name, "product"),
+			Storage:   storage[name] || attr(name, "bDeviceClass") == usbMassStorageClass,
+		})
+	}
+	return devices
+}
+
+// windowsUSBStorageRestrictions reads the USBSTOR driver's start type and
+// the removable storage access policies
+func windowsUSBStorageRestrictions(ctx context.Context) ([]string, *ProbeError) {
+	var restrictedBy []string
+	var perr *ProbeError
+	read := func(path, name string) uint64 {
+		v, ok, err := registryInteger(ctx, path, name)
+		if err != nil && perr == nil {
+			perr = classifyRegistryError(ctx, path, err)
+		}
+		if !ok {
+			return 0
+		}
+		return v
+	}
+	if read(usbstorServiceKey, "Start") == serviceStartDisabled {
+		restrictedBy = append(restrictedBy, "USBSTOR driver disabled")
+	}
+	if read(removableStorageKey, "Deny_All") == 1 {
+		restrictedBy = append(restrictedBy, "Policy: all removable storage classes denied")
+	}
+	if read(removableDisksKey, "Deny_Read") == 1 {
+		restrictedBy = append(restrictedBy, "Policy: removable disks deny read")
+	}
+	if read(removableDisksKey, "Deny_Write") == 1 {
+		restrictedBy = append(restrictedBy, "Policy: removable disks deny write")
+	}
+	return restrictedBy, perr
+}
+
+// parseUSBPnPDeviceID returns the vendor and product ID of a PnP device ID
+// such as USB\VID_0781&PID_5581\4C530001. Interfaces of composite devices
+// (&MI_xx) are not devices of their own.
+func parseUSBPnPDeviceID(id string) (vendorID, productID string, ok bool) {
+	parts := strings.Split(strings.ToUpper(id), `\`)
+	if len(parts) < 2 || parts[0] != "USB" || strings.Contains(parts[1], "&MI_") {
+		return "", "", false
+	}
+	for _, field := range strings.Split(parts[1], "&") {
+		if v, found := strings.CutPrefix(field, "VID_"); found {
+			vendorID = strings.ToLower(v)
+		}
+		if p, found := strings.CutPrefix(field, "PID_"); found {
+			productID = strings.ToLower(p)
+		}
+	}
+	return vendorID, productID, vendorID != "" && productID != ""
+}
+
+// darwinUSBStorageRestrictions reads the mount-controls a configuration
+// profile sets for external disks; macOS has no other switch for USB storage
+func darwinUSBStorageRestrictions(ctx context.Context) []string {
+	if _, err := os.Stat(systemUIServerPrefs); err != nil {
+		return nil
+	}
+	out, err := runCommand(ctx, "plutil", "-convert", "xml1", "-o", "-", systemUIServerPrefs)
+	if err != nil {
+		return nil
+	}
+	return parseMountControls(out)
+}
+
+// parseMountControls returns the restrictions on external disks in a
+// com.apple.systemuiserver plist: "deny" blocks mounting them, "read-only"
+// blocks writing to them
+func parseMountControls(data []byte) []string {
+	v, err := decodePlist(data)
+	if err != nil {
+		return nil
+	}
+	prefs, _ := v.(map[string]any)
+	controls, _ := prefs["mount-controls"].(map[string]any)
+	actions := plistStrings(controls, "harddisk-external")
+	for _, action := range []string{"deny", "read-only"} {
+		if slices.Contains(actions, action) {
+			return []string{"mount-controls harddisk-external " + action}
+		}
+	}
+	return nil
+}
+
+// systemProfilerUSBItem is a device in `system_profiler SPUSBDataType -json`;
+// hubs and buses nest their devices in _items
+type systemProfilerUSBItem struct {
+	Name         string                  `json:"_name"`
+	VendorID     string                  `json:"vendor_id"`
+	ProductID    string                  `json:"product_id"`
+	Manufacturer string                  `json:"manufacturer"`
+	Media        []json.RawMessage       `json:"Media"`
+	Items        []systemProfilerUSBItem `json:"_items"`
+}
+
+// parseSystemProfilerUSB parses `system_profiler SPUSBDataType -json`. IDs
+// look like "0x0781  (SanDisk Corporation)"; devices with Media are mass
+// storage.
+func parseSystemProfilerUSB(data []byte) ([]USBDevice, error) {
+	var doc struct {
+		Buses []systemProfilerUSBItem `json:"SPUSBDataType"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	hexID := func(s string) string {
+		id, _, _ := strings.Cut(strings.TrimSpace(s), " ")
+		return strings.ToLower(strings.TrimPrefix(id, "0x"))
+	}
+	var devices []USBDevice
+	var walk func(items []systemProfilerUSBItem)
+	walk = func(items []systemProfilerUSBItem) {
+		for _, item := range items {
+			if item.VendorID != "" && item.ProductID != "" {
+				vendor := item.Manufacturer
+				if _, name, ok := strings.Cut(item.VendorID, "("); ok && vendor == "" {
+					vendor = strings.TrimSuffix(name, ")")
+				}
+				devices = append(devices, USBDevice{
+					VendorID:  hexID(item.VendorID),
+					ProductID: hexID(item.ProductID),
+					Vendor:    vendor,
+					Product:   item.Name,
+					Storage:   len(item.Media) > 0,
+				})
+			}
+			walk(item.Items)
+		}
+	}
+	walk(doc.Buses)
+	return devices, nil
+}
+
+// usbFindings returns unrestricted USB storage as a finding when the
+// policy requires blocking it
+func usbFindings(r *USBDevicesResult) []Finding {
+	if r.Policy != USBStoragePolicyBlock || r.StorageRestricted {
+		return nil
+	}
+	if r.Error != nil {
+		return []Finding{unverifiedFinding("usb_storage_unverified", CheckUSBStorage, "USB storage policy", r.Error)}
+	}
+	title := T("USB mass storage is not blocked")
+	severity := SeverityMedium
+	if r.StorageDevices > 0 {
+		title = T("USB mass storage is not blocked and %d storage devices are connected", r.StorageDevices)
+		severity = SeverityHigh
+	}
+	return []Finding{{
+		ID:                 "usb_storage_unrestricted",
+		Title:              title,
+		Severity:           severity,
+		Check:              CheckUSBStorage,
+		Remediation:        T("Block USB mass storage to comply with the removable-media policy"),
+		RemediationCommand: remediationCommand("usb_storage_unrestricted"),
+	}}
+}
+
+// FormatUSBDevicesTable formats the USB storage policy and devices as a
+// colored table
+func FormatUSBDevicesTable(result *USBDevicesResult) string {
+	return FormatUSBDevicesTableWith(CurrentStyler(), result)
+}
+
+// FormatUSBDevicesTableWith is like FormatUSBDevicesTable but styles the
+// table with st
+func FormatUSBDevicesTableWith(st Styler, result *USBDevicesResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " USB Devices"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	if result.Error != nil {
+		sb.WriteString(st.Warning(IconWarning + result.Error.Error()))
+		sb.WriteString("\n")
+		if result.Error.Hint != "" {
+			sb.WriteString(st.Muted("  " + result.Error.Hint))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.BoldText("Mass storage: "))
+	if result.StorageRestricted {
+		sb.WriteString(st.Success(IconCheck + " restricted"))
+	} else if result.Policy == USBStoragePolicyBlock {
+		sb.WriteString(st.Danger(IconCross + " unrestricted (policy requires blocking)"))
+	} else {
+		sb.WriteString(st.Warning("unrestricted"))
+	}
+	sb.WriteString("\n")
+	for _, by := range result.RestrictedBy {
+		sb.WriteString(fmt.Sprintf("  %s %s\n", IconArrow, by))
+	}
+	if result.ModuleLoaded {
+		sb.WriteString(st.Muted("  usb_storage module is loaded"))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(st.TableTop(11, 28, 22, 8))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight("ID", 11)),
+		st.Header(PadRight("Product", 28)),
+		st.Header(PadRight("Vendor", 22)),
+		st.Header(PadRight("Storage", 8)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(11, 28, 22, 8))
+	sb.WriteString("\n")
+	if len(result.Devices) == 0 {
+		sb.WriteString(st.TableRowColored(
+			st.Muted(PadRight("-", 11)),
+			st.Muted(PadRight("no USB devices", 28)),
+			PadRight("", 22),
+			PadRight("", 8),
+		))
+		sb.WriteString("\n")
+	}
+	fit := func(s string, width int) string {
+		if len(s) > width {
+			return s[:width-3] + "..."
+		}
+		return s
+	}
+	for _, d := range result.Devices {
+		storage := st.Muted(PadRight("no", 8))
+		if d.Storage {
+			storage = st.Warning(PadRight("yes", 8))
+		}
+		sb.WriteString(st.TableRowColored(
+			st.Info(PadRight(d.VendorID+":"+d.ProductID, 11)),
+			PadRight(fit(d.Product, 28), 28),
+			PadRight(fit(d.Vendor, 22), 22),
+			storage,
+		))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(st.TableBottom(11, 28, 22, 8))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// FormatUSBDevices formats the USB storage policy and devices in the
+// specified format
+func FormatUSBDevices(result *USBDevicesResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatUSBDevicesTableWith(st, result)
+	}, format)
+}
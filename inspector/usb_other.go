@@ -0,0 +1,10 @@
+//go:build !windows
+
+package inspector
+
+import "context"
+
+// windowsUSBDevices is only implemented on Windows
+func windowsUSBDevices(ctx context.Context) ([]USBDevice, *ProbeError) {
+	return nil, nil
+}
@@ -0,0 +1,179 @@
+package inspector
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLinuxUSBStorageRestrictions(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/modules":                      {Data: []byte("uas 28672 0 - Live 0x0000000000000000\nusbhid 65536 0 - Live 0x0000000000000000\n")},
+		"etc/modprobe.d/cis.conf":           {Data: []byte("# CIS 1.1.1.9\ninstall usb-storage /bin/true\nblacklist usb_storage\n")},
+		"etc/modprobe.d/other.conf":         {Data: []byte("install usb-storage /sbin/modprobe --ignore-install usb-storage\n")},
+		"etc/modprobe.d/readme":             {Data: []byte("blacklist usb-storage\n")},
+		"etc/usbguard/usbguard-daemon.conf": {Data: []byte("RuleFile=/etc/usbguard/rules.conf\n#ImplicitPolicyTarget=allow\nImplicitPolicyTarget=block\n")},
+	}
+	restrictedBy, loaded := linuxUSBStorageRestrictions(fsys)
+	want := []string{
+		"install usb-storage /bin/true (/etc/modprobe.d/cis.conf)",
+		"blacklist usb-storage (/etc/modprobe.d/cis.conf)",
+		"USBGuard ImplicitPolicyTarget=block",
+	}
+	if loaded || !slices.Equal(restrictedBy, want) {
+		t.Errorf("restrictions = %q (loaded %v), want %q", restrictedBy, loaded, want)
+	}
+
+	// A loaded module means the modprobe rules have not taken effect
+	fsys["proc/modules"] = &fstest.MapFile{Data: []byte("usb_storage 81920 1 uas, Live 0x0000000000000000\n")}
+	restrictedBy, loaded = linuxUSBStorageRestrictions(fsys)
+	if !loaded || !slices.Equal(restrictedBy, []string{"USBGuard ImplicitPolicyTarget=block"}) {
+		t.Errorf("restrictions = %q (loaded %v), want USBGuard only", restrictedBy, loaded)
+	}
+}
+
+func TestLinuxUSBDevices(t *testing.T) {
+	const dir = "sys/bus/usb/devices/"
+	fsys := fstest.MapFS{
+		dir + "usb1/idVendor":           {Data: []byte("1d6b\n")},
+		dir + "usb1/idProduct":          {Data: []byte("0002\n")},
+		dir + "1-1/idVendor":            {Data: []byte("0781\n")},
+		dir + "1-1/idProduct":           {Data: []byte("5581\n")},
+		dir + "1-1/manufacturer":        {Data: []byte(" USB\n")},
+		dir + "1-1/product":             {Data: []byte(" SanDisk 3.2Gen1\n")},
+		dir + "1-1/bDeviceClass":        {Data: []byte("00\n")},
+		dir + "1-1:1.0/bInterfaceClass": {Data: []byte("08\n")},
+		dir + "1-2/idVendor":            {Data: []byte("046D\n")},
+		dir + "1-2/idProduct":           {Data: []byte("C52B\n")},
+		dir + "1-2/product":             {Data: []byte("USB Receiver\n")},
+		dir + "1-2:1.0/bInterfaceClass": {Data: []byte("03\n")},
+		dir + "1-2:1.1/bInterfaceClass": {Data: []byte("03\n")},
+		dir + "1-0:1.0/bInterfaceClass": {Data: []byte("09\n")},
+	}
+	result := &USBDevicesResult{Policy: USBStoragePolicyBlock, Devices: linuxUSBDevices(fsys)}
+	finishUSBDevices(result)
+
+	want := []USBDevice{
+		{VendorID: "046d", ProductID: "c52b", Product: "USB Receiver"},
+		{VendorID: "0781", ProductID: "5581", Vendor: "USB", Product: "SanDisk 3.2Gen1", Storage: true},
+	}
+	if !slices.Equal(result.Devices, want) {
+		t.Errorf("Devices = %+v, want %+v", result.Devices, want)
+	}
+	if result.StorageDevices != 1 || result.StorageRestricted || result.Compliant {
+		t.Errorf("result = %+v, want one unrestricted storage device", result)
+	}
+	findings := usbFindings(result)
+	if len(findings) != 1 || findings[0].ID != "usb_storage_unrestricted" || findings[0].Severity != SeverityHigh {
+		t.Errorf("findings = %+v, want usb_storage_unrestricted (high)", findings)
+	}
+}
+
+func TestWindowsUSBStorageRestrictions(t *testing.T) {
+	fake := NewFakeRegistry().
+		SetInteger(usbstorServiceKey, "Start", 3).
+		SetInteger(removableDisksKey, "Deny_Write", 1)
+	defer SetRegistryReader(SetRegistryReader(fake))
+
+	restrictedBy, perr := windowsUSBStorageRestrictions(context.Background())
+	if perr != nil || !slices.Equal(restrictedBy, []string{"Policy: removable disks deny write"}) {
+		t.Errorf("restrictions = %q, %v", restrictedBy, perr)
+	}
+
+	fake.SetInteger(usbstorServiceKey, "Start", serviceStartDisabled)
+	if restrictedBy, _ := windowsUSBStorageRestrictions(context.Background()); len(restrictedBy) != 2 || restrictedBy[0] != "USBSTOR driver disabled" {
+		t.Errorf("restrictions = %q, want the disabled driver first", restrictedBy)
+	}
+}
+
+func TestParseUSBPnPDeviceID(t *testing.T) {
+	tests := []struct {
+		id                  string
+		vendorID, productID string
+		ok                  bool
+	}{
+		{`USB\VID_0781&PID_5581\4C530001`, "0781", "5581", true},
+		{`USB\VID_046D&PID_C52B&MI_00\7&1A2B3C&0&0000`, "", "", false},
+		{`USBSTOR\DISK&VEN_SANDISK&PROD_3.2GEN1\4C530001&0`, "", "", false},
+		{`USB\ROOT_HUB30\4&2F5A0D7&0&0`, "", "", false},
+	}
+	for _, tt := range tests {
+		vendorID, productID, ok := parseUSBPnPDeviceID(tt.id)
+		if vendorID != tt.vendorID || productID != tt.productID || ok != tt.ok {
+			t.Errorf("parseUSBPnPDeviceID(%q) = %q, %q, %v", tt.id, vendorID, productID, ok)
+		}
+	}
+}
+
+func TestParseSystemProfilerUSB(t *testing.T) {
+	data := []byte(`{"SPUSBDataType": [{
+		"_name": "USB31Bus",
+		"_items": [{
+			"_name": "USB3.0 Hub",
+			"vendor_id": "0x2109  (VIA Labs, Inc.)",
+			"product_id": "0x0817",
+			"_items": [{
+				"_name": "Extreme SSD",
+				"manufacturer": "SanDisk",
+				"vendor_id": "0x0781  (SanDisk Corporation)",
+				"product_id": "0x55ae",
+				"Media": [{"_name": "Extreme SSD", "size": "1 TB"}]
+			}]
+		}]
+	}]}`)
+	devices, err := parseSystemProfilerUSB(data)
+	if err != nil {
+		t.Fatalf("parseSystemProfilerUSB failed: %v", err)
+	}
+	want := []USBDevice{
+		{VendorID: "2109", ProductID: "0817", Vendor: "VIA Labs, Inc.", Product: "USB3.0 Hub"},
+		{VendorID: "0781", ProductID: "55ae", Vendor: "SanDisk", Product: "Extreme SSD", Storage: true},
+	}
+	if !slices.Equal(devices, want) {
+		t.Errorf("devices = %+v, want %+v", devices, want)
+	}
+}
+
+func TestParseMountControls(t *testing.T) {
+	plist := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0"><dict>
+	<key>mount-controls</key>
+	<dict>
+		<key>harddisk-external</key><array><string>authenticate</string><string>read-only</string></array>
+		<key>dvd</key><array><string>deny</string></array>
+	</dict>
+</dict></plist>`)
+	if got := parseMountControls(plist); !slices.Equal(got, []string{"mount-controls harddisk-external read-only"}) {
+		t.Errorf("parseMountControls = %q", got)
+	}
+}
+
+func TestUSBFindings_Policy(t *testing.T) {
+	result := &USBDevicesResult{Policy: USBStoragePolicyAllow}
+	finishUSBDevices(result)
+	if !result.Compliant || len(usbFindings(result)) != 0 {
+		t.Error("unrestricted USB storage is not a finding without the block policy")
+	}
+
+	result = &USBDevicesResult{Policy: USBStoragePolicyBlock, RestrictedBy: []string{"USBSTOR driver disabled"}}
+	finishUSBDevices(result)
+	if !result.Compliant || len(usbFindings(result)) != 0 {
+		t.Errorf("restricted USB storage should comply: %+v", result)
+	}
+
+	result = &USBDevicesResult{Policy: USBStoragePolicyBlock}
+	finishUSBDevices(result)
+	if f := usbFindings(result); len(f) != 1 || f[0].Severity != SeverityMedium {
+		t.Errorf("findings = %+v, want one medium finding", f)
+	}
+
+	t.Setenv(USBStoragePolicyEnv, " BLOCK ")
+	if got := USBStoragePolicy(); got != USBStoragePolicyBlock {
+		t.Errorf("USBStoragePolicy = %q, want block", got)
+	}
+	t.Setenv(USBStoragePolicyEnv, "deny")
+	if got := USBStoragePolicy(); got != USBStoragePolicyAllow {
+		t.Errorf("USBStoragePolicy = %q, want allow for unknown values", got)
+	}
+}
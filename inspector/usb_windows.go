@@ -0,0 +1,41 @@
+//go:build windows
+
+package inspector
+
+import (
+	"context"
+	"strings"
+)
+
+// usbPnPEntity holds the Win32_PnPEntity properties of a USB device
+type usbPnPEntity struct {
+	Name         string
+	Manufacturer string
+	PNPDeviceID  string
+	Service      string
+}
+
+// windowsUSBDevices lists present USB devices from WMI. Mass storage
+// devices are driven by the USBSTOR service.
+func windowsUSBDevices(ctx context.Context) ([]USBDevice, *ProbeError) {
+	var entities []usbPnPEntity
+	query := `SELECT Name, Manufacturer, PNPDeviceID, Service FROM Win32_PnPEntity WHERE PNPDeviceID LIKE 'USB\\VID_%'`
+	if err := wmiQuery(query, &entities, ""); err != nil {
+		return nil, classifyWMIError(ctx, `root\cimv2`, err)
+	}
+	var devices []USBDevice
+	for _, e := range entities {
+		vendorID, productID, ok := parseUSBPnPDeviceID(e.PNPDeviceID)
+		if !ok {
+			continue
+		}
+		devices = append(devices, USBDevice{
+			VendorID:  vendorID,
+			ProductID: productID,
+			Vendor:    e.Manufacturer,
+			Product:   e.Name,
+			Storage:   strings.EqualFold(e.Service, "USBSTOR"),
+		})
+	}
+	return devices, nil
+}
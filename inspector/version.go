@@ -0,0 +1,163 @@
+package inspector
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Build metadata, set at link time by release builds:
+//
+//	go build -ldflags "-X github.com/agentplexus/posture/inspector.Version=v0.3.0 \
+//	  -X github.com/agentplexus/posture/inspector.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/agentplexus/posture/inspector.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Unset values are filled from the build info the Go toolchain embeds: the
+// module version for "go install ...@version" builds, and the VCS revision
+// and commit time for builds from a git checkout.
+var (
+	Version   string
+	Commit    string
+	BuildDate string
+)
+
+// develVersion is reported when neither ldflags nor the module version say
+// which release this is
+const develVersion = "devel"
+
+// ModuleInfo is a module compiled into the binary
+type ModuleInfo struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	// Sum is the go.sum checksum of the module, empty for replaced modules
+	Sum     string `json:"sum,omitempty"`
+	Replace string `json:"replace,omitempty"`
+}
+
+// BuildInfo describes the posture binary itself, for inventory of the tool
+// across a fleet
+type BuildInfo struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+	// Modified is true if the binary was built from a checkout with
+	// uncommitted changes
+	Modified  bool         `json:"modified,omitempty"`
+	GoVersion string       `json:"go_version"`
+	Platform  string       `json:"platform"`
+	Module    string       `json:"module,omitempty"`
+	Modules   []ModuleInfo `json:"modules,omitempty"`
+}
+
+// GetBuildInfo returns the version, commit, build date, and module
+// checksums of the running binary
+func GetBuildInfo() *BuildInfo {
+	info := &BuildInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		applyBuildInfo(info, bi)
+	}
+	if info.Version == "" {
+		info.Version = develVersion
+	}
+	return info
+}
+
+// applyBuildInfo fills what ldflags left unset from the toolchain's build info
+func applyBuildInfo(info *BuildInfo, bi *debug.BuildInfo) {
+	info.Module = bi.Main.Path
+	if info.Version == "" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = s.Value
+			}
+		case "vcs.time":
+			if info.BuildDate == "" {
+				info.BuildDate = s.Value
+			}
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	for _, dep := range bi.Deps {
+		m := ModuleInfo{Path: dep.Path, Version: dep.Version, Sum: dep.Sum}
+		if dep.Replace != nil {
+			m.Replace = dep.Replace.Path
+			if dep.Replace.Version != "" {
+				m.Replace += "@" + dep.Replace.Version
+			}
+			m.Sum = dep.Replace.Sum
+		}
+		info.Modules = append(info.Modules, m)
+	}
+}
+
+// ToolVersion returns the version of the running binary, as reported to MCP
+// clients
+func ToolVersion() string {
+	return GetBuildInfo().Version
+}
+
+// FormatBuildInfoTable formats build info as a colored listing
+func FormatBuildInfoTable(info *BuildInfo) string {
+	return FormatBuildInfoTableWith(CurrentStyler(), info)
+}
+
+// FormatBuildInfoTableWith is like FormatBuildInfoTable but styles the table
+// with st
+func FormatBuildInfoTableWith(st Styler, info *BuildInfo) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconInfo + " posture " + info.Version))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	row := func(label, value string) {
+		if value != "" {
+			sb.WriteString(fmt.Sprintf("  %s %s\n", st.Muted(PadRight(label+":", 12)), value))
+		}
+	}
+	commit := info.Commit
+	if info.Modified {
+		commit += " " + st.Warning("(modified)")
+	}
+	row("Commit", commit)
+	row("Built", info.BuildDate)
+	row("Go", info.GoVersion)
+	row("Platform", info.Platform)
+	row("Module", info.Module)
+
+	if len(info.Modules) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText("Modules:"))
+		sb.WriteString("\n")
+		for _, m := range info.Modules {
+			version := m.Version
+			if m.Replace != "" {
+				version += " => " + m.Replace
+			}
+			sb.WriteString(fmt.Sprintf("  %s %s\n", PadRight(m.Path, 45), st.Muted(version)))
+		}
+	}
+	return sb.String()
+}
+
+// FormatBuildInfo formats build info in the specified format
+func FormatBuildInfo(info *BuildInfo, format string) string {
+	return FormatOutputWith(info, func(st Styler) string {
+		return FormatBuildInfoTableWith(st, info)
+	}, format)
+}
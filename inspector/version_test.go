@@ -0,0 +1,53 @@
+package inspector
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestApplyBuildInfo(t *testing.T) {
+	bi := &debug.BuildInfo{
+		Main: debug.Module{Path: "github.com/agentplexus/posture", Version: "v0.3.0"},
+		Deps: []*debug.Module{
+			{Path: "github.com/spf13/cobra", Version: "v1.8.1", Sum: "h1:abc="},
+			{Path: "golang.org/x/sys", Version: "v0.39.0", Replace: &debug.Module{Path: "../sys"}},
+		},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "489d25cc"},
+			{Key: "vcs.time", Value: "2026-10-01T12:00:00Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	info := &BuildInfo{}
+	applyBuildInfo(info, bi)
+	if info.Version != "v0.3.0" || info.Commit != "489d25cc" || info.BuildDate != "2026-10-01T12:00:00Z" || !info.Modified {
+		t.Errorf("applyBuildInfo = %+v", info)
+	}
+	if len(info.Modules) != 2 || info.Modules[0].Sum != "h1:abc=" || info.Modules[1].Replace != "../sys" {
+		t.Errorf("Modules = %+v", info.Modules)
+	}
+
+	// ldflags take precedence over the toolchain's build info
+	info = &BuildInfo{Version: "v0.4.0", Commit: "release"}
+	applyBuildInfo(info, bi)
+	if info.Version != "v0.4.0" || info.Commit != "release" {
+		t.Errorf("ldflags values were overwritten: %+v", info)
+	}
+
+	info = &BuildInfo{}
+	applyBuildInfo(info, &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}})
+	if info.Version != "" {
+		t.Errorf("(devel) should not be reported as a version, got %q", info.Version)
+	}
+}
+
+func TestGetBuildInfo(t *testing.T) {
+	info := GetBuildInfo()
+	if info.Version == "" || info.GoVersion == "" || info.Platform == "" {
+		t.Errorf("GetBuildInfo() = %+v", info)
+	}
+	if ToolVersion() != info.Version {
+		t.Errorf("ToolVersion() = %q, want %q", ToolVersion(), info.Version)
+	}
+}
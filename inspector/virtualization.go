@@ -0,0 +1,259 @@
+package inspector
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Hypervisors reported by GetVirtualizationStatus
+const (
+	HypervisorKVM        = "kvm"
+	HypervisorHyperV     = "hyperv"
+	HypervisorVMware     = "vmware"
+	HypervisorXen        = "xen"
+	HypervisorVirtualBox = "virtualbox"
+	HypervisorQEMU       = "qemu"
+	HypervisorParallels  = "parallels"
+	HypervisorBhyve      = "bhyve"
+	HypervisorApple      = "apple"
+	HypervisorUnknown    = "unknown"
+)
+
+// TPM kinds reported in TPMResult.Kind
+const (
+	TPMKindDiscrete      = "discrete"
+	TPMKindFirmware      = "firmware"
+	TPMKindVirtual       = "virtual"
+	TPMKindSecureEnclave = "secure_enclave"
+)
+
+// VirtualizationResult reports whether the machine is a virtual machine
+type VirtualizationResult struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Platform    string `json:"platform"`
+	Virtualized bool   `json:"virtualized"`
+	// Hypervisor is the detected hypervisor, or "unknown" if only generic
+	// virtualization signals were found
+	Hypervisor string `json:"hypervisor,omitempty"`
+	// CPUIDVendor is the hypervisor signature from CPUID leaf 0x40000000 (x86 only)
+	CPUIDVendor string `json:"cpuid_vendor,omitempty"`
+	// Product is the system vendor and model from DMI/SMBIOS
+	Product string `json:"product,omitempty"`
+	// Sources lists the signals that identified the VM (dmi, cpuid, sysctl)
+	Sources []string `json:"sources,omitempty"`
+	// VTPM reports that the TPM in use is provided by the hypervisor
+	VTPM    bool        `json:"vtpm"`
+	TPMKind string      `json:"tpm_kind,omitempty"`
+	Error   *ProbeError `json:"error,omitempty"`
+}
+
+// hypervisorInfo is the outcome of the cheap, TPM-independent VM probes
+type hypervisorInfo struct {
+	hypervisor  string
+	cpuidVendor string
+	product     string
+	sources     []string
+	err         *ProbeError
+}
+
+// cpuidHypervisors maps CPUID leaf 0x40000000 signatures to hypervisors
+var cpuidHypervisors = map[string]string{
+	"KVMKVMKVM\x00\x00\x00": HypervisorKVM,
+	"Microsoft Hv":          HypervisorHyperV,
+	"VMwareVMware":          HypervisorVMware,
+	"XenVMMXenVMM":          HypervisorXen,
+	"VBoxVBoxVBox":          HypervisorVirtualBox,
+	"TCGTCGTCGTCG":          HypervisorQEMU,
+	" lrpepyh  vr":          HypervisorParallels,
+	"prl hyperv  ":          HypervisorParallels,
+	"bhyve bhyve ":          HypervisorBhyve,
+}
+
+// dmiHypervisors maps substrings of lowercased DMI vendor and product
+// strings to hypervisors, most specific first
+var dmiHypervisors = []struct {
+	substr     string
+	hypervisor string
+}{
+	{"amazon ec2", HypervisorKVM}, // Nitro is KVM-based
+	{"google compute engine", HypervisorKVM},
+	{"openstack", HypervisorKVM},
+	{"kvm", HypervisorKVM},
+	{"qemu", HypervisorQEMU},
+	{"vmware", HypervisorVMware},
+	{"virtualbox", HypervisorVirtualBox},
+	{"innotek", HypervisorVirtualBox},
+	{"xen", HypervisorXen},
+	{"parallels", HypervisorParallels},
+	{"bhyve", HypervisorBhyve},
+	{"virtual machine", HypervisorHyperV}, // Microsoft Corporation "Virtual Machine"
+	{"apple virtualization", HypervisorApple},
+	{"virtualmac", HypervisorApple},
+}
+
+// firmwareTPMVendors are TPM manufacturer IDs of TPMs implemented in CPU or
+// chipset firmware (Intel PTT, AMD fTPM, Qualcomm, Microsoft Pluton)
+var firmwareTPMVendors = []string{"INTC", "AMD", "QCOM", "MSFT"}
+
+// virtualTPMVendors are TPM manufacturer IDs used by hypervisor vTPMs and
+// software TPMs (swtpm reports IBM)
+var virtualTPMVendors = []string{"IBM", "GOOG", "AMZN", "VMW"}
+
+// hypervisorFromDMI identifies a hypervisor from DMI/SMBIOS strings. Cloud
+// bare metal instances (EC2 *.metal) carry the cloud's vendor string
+// without a hypervisor, so their instance type is checked first.
+func hypervisorFromDMI(fields ...string) string {
+	dmi := strings.ToLower(strings.Join(fields, "\n"))
+	if strings.Contains(dmi, ".metal") {
+		return ""
+	}
+	for _, h := range dmiHypervisors {
+		if strings.Contains(dmi, h.substr) {
+			return h.hypervisor
+		}
+	}
+	return ""
+}
+
+// classifyTPMKind decides whether a TPM is discrete, firmware, or virtual
+// from its TPM_PT_MANUFACTURER ID. Inside a VM every TPM is treated as
+// virtual: hypervisors present their own vTPM, and Hyper-V's reports MSFT
+// like Pluton does.
+func classifyTPMKind(manufacturer string, virtualized bool) string {
+	id := strings.ToUpper(strings.TrimSpace(manufacturer))
+	switch {
+	case id == "" || id == "UNKNOWN" || strings.HasPrefix(id, "ID:"):
+		if virtualized {
+			return TPMKindVirtual
+		}
+		return ""
+	case virtualized || slices.Contains(virtualTPMVendors, id):
+		return TPMKindVirtual
+	case slices.Contains(firmwareTPMVendors, id):
+		return TPMKindFirmware
+	}
+	return TPMKindDiscrete
+}
+
+// detectHypervisor combines CPUID with the platform's DMI or sysctl signals
+func detectHypervisor(ctx context.Context) hypervisorInfo {
+	present, vendor := cpuidHypervisor()
+	return combineHypervisor(platformHypervisor(ctx), cpuidAvailable, present, vendor)
+}
+
+// combineHypervisor adds what CPUID reported (when cpuidKnown) to the
+// platform's signals. A cloud vendor string only names the platform, so
+// when CPUID can be read and reports no hypervisor, the instance is bare
+// metal.
+func combineHypervisor(info hypervisorInfo, cpuidKnown, present bool, vendor string) hypervisorInfo {
+	if cpuidKnown && !present && cloudProductVendor(info.product) != "" {
+		info.hypervisor, info.sources = "", nil
+	}
+	if present {
+		info.cpuidVendor = strings.TrimRight(vendor, "\x00")
+		info.sources = append(info.sources, "cpuid")
+		// CPUID names the hypervisor the guest is actually talking to, which
+		// is more precise than DMI (e.g. KVM behind a "QEMU" product string)
+		if h, ok := cpuidHypervisors[vendor]; ok {
+			info.hypervisor = h
+		} else if info.hypervisor == "" {
+			info.hypervisor = HypervisorUnknown
+		}
+	}
+	return info
+}
+
+// GetVirtualizationStatus identifies whether the machine is a VM, which
+// hypervisor it runs on, and whether its TPM is a virtual TPM
+func GetVirtualizationStatus() (*VirtualizationResult, error) {
+	return GetVirtualizationStatusContext(context.Background())
+}
+
+// GetVirtualizationStatusContext is like GetVirtualizationStatus but runs
+// the probes with ctx's deadline, command runner, and logger
+func GetVirtualizationStatusContext(ctx context.Context) (*VirtualizationResult, error) {
+	if result, ok, err := loadFixture[VirtualizationResult](ctx, "virtualization"); ok {
+		return result, err
+	}
+	info := detectHypervisor(ctx)
+	result := &VirtualizationResult{
+		Platform:    runtime.GOOS,
+		Virtualized: info.hypervisor != "",
+		Hypervisor:  info.hypervisor,
+		CPUIDVendor: info.cpuidVendor,
+		Product:     info.product,
+		Sources:     info.sources,
+		Error:       info.err,
+	}
+	if IsTPMSupported() && CheckEnabled(CheckTPM) {
+		if tpm, err := GetTPMStatusContext(ctx); err == nil && tpm.Present {
+			result.TPMKind = tpm.Kind
+			result.VTPM = tpm.Kind == TPMKindVirtual
+		}
+	}
+	return result, nil
+}
+
+// FormatVirtualizationStatusTable formats virtualization status as a colored table
+func FormatVirtualizationStatusTable(result *VirtualizationResult) string {
+	return FormatVirtualizationStatusTableWith(CurrentStyler(), result)
+}
+
+// FormatVirtualizationStatusTableWith is like
+// FormatVirtualizationStatusTable but styles the table with st
+func FormatVirtualizationStatusTableWith(st Styler, result *VirtualizationResult) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconChip + " " + T("Virtualization")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(st.TableTop(20, 30))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(PadRight(T("Property"), 20)),
+		st.Header(PadRight(T("Value"), 30)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(20, 30))
+	sb.WriteString("\n")
+
+	row := func(name, value string) {
+		if value == "" {
+			return
+		}
+		sb.WriteString(st.TableRowColored(PadRight(name, 20), PadRight(value, 30)))
+		sb.WriteString("\n")
+	}
+	vm := st.Info(T("No (bare metal)"))
+	if result.Virtualized {
+		vm = st.Info(T("Yes"))
+	}
+	row(T("Virtual Machine"), vm)
+	row(T("Hypervisor"), result.Hypervisor)
+	row(T("CPUID Signature"), result.CPUIDVendor)
+	row(T("Product"), result.Product)
+	row(T("Detected By"), strings.Join(result.Sources, ", "))
+	row(T("TPM Kind"), result.TPMKind)
+
+	sb.WriteString(st.TableBottom(20, 30))
+	sb.WriteString("\n")
+
+	if result.Error != nil {
+		sb.WriteString("\n")
+		sb.WriteString(formatProbeError(st, result.Error))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// FormatVirtualizationStatus formats virtualization status in the specified format
+func FormatVirtualizationStatus(result *VirtualizationResult, format string) string {
+	return FormatOutputWith(result, func(st Styler) string {
+		return FormatVirtualizationStatusTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,27 @@
+//go:build darwin
+
+package inspector
+
+import (
+	"context"
+	"golang.org/x/sys/unix"
+)
+
+// platformHypervisor identifies a macOS guest from kern.hv_vmm_present, which
+// is set under Apple Virtualization.framework, VMware, and Parallels, and the
+// hardware model (VirtualMac on Apple silicon guests)
+func platformHypervisor(ctx context.Context) hypervisorInfo {
+	var info hypervisorInfo
+	model, _ := unix.Sysctl("hw.model")
+	info.product = model
+	if h := hypervisorFromDMI(model); h != "" {
+		info.hypervisor = h
+		info.sources = append(info.sources, "sysctl")
+		return info
+	}
+	if v, err := unix.SysctlUint32("kern.hv_vmm_present"); err == nil && v == 1 {
+		info.hypervisor = HypervisorUnknown
+		info.sources = append(info.sources, "sysctl")
+	}
+	return info
+}
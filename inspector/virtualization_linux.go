@@ -0,0 +1,29 @@
+//go:build linux
+
+package inspector
+
+import (
+	"context"
+	"strings"
+)
+
+// platformHypervisor identifies a hypervisor from DMI (/sys/class/dmi/id)
+func platformHypervisor(ctx context.Context) hypervisorInfo {
+	var info hypervisorInfo
+	var fields []string
+	for _, name := range []string{"sys_vendor", "product_name", "bios_vendor", "board_vendor"} {
+		data, err := readFSFile(environmentRoot, "sys/class/dmi/id/"+name)
+		if err != nil {
+			continue
+		}
+		fields = append(fields, strings.TrimSpace(string(data)))
+	}
+	if len(fields) >= 2 {
+		info.product = strings.TrimSpace(fields[0] + " " + fields[1])
+	}
+	if h := hypervisorFromDMI(fields...); h != "" {
+		info.hypervisor = h
+		info.sources = append(info.sources, "dmi")
+	}
+	return info
+}
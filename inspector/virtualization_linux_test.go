@@ -0,0 +1,35 @@
+//go:build linux
+
+package inspector
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPlatformHypervisor_DMI(t *testing.T) {
+	prev := environmentRoot
+	t.Cleanup(func() { environmentRoot = prev })
+	environmentRoot = fstest.MapFS{
+		"sys/class/dmi/id/sys_vendor":   {Data: []byte("QEMU\n")},
+		"sys/class/dmi/id/product_name": {Data: []byte("Standard PC (Q35 + ICH9, 2009)\n")},
+		"sys/class/dmi/id/bios_vendor":  {Data: []byte("EDK II\n")},
+	}
+
+	info := platformHypervisor(context.Background())
+	if info.hypervisor != HypervisorQEMU || info.product != "QEMU Standard PC (Q35 + ICH9, 2009)" {
+		t.Errorf("info = %+v", info)
+	}
+	if len(info.sources) != 1 || info.sources[0] != "dmi" {
+		t.Errorf("sources = %v, want [dmi]", info.sources)
+	}
+
+	environmentRoot = fstest.MapFS{
+		"sys/class/dmi/id/sys_vendor":   {Data: []byte("Dell Inc.\n")},
+		"sys/class/dmi/id/product_name": {Data: []byte("XPS 13 9340\n")},
+	}
+	if info := platformHypervisor(context.Background()); info.hypervisor != "" {
+		t.Errorf("bare metal detected as %q", info.hypervisor)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !darwin && !windows && !linux
+
+package inspector
+
+// platformHypervisor has no platform signals beyond CPUID here
+func platformHypervisor(ctx context.Context) hypervisorInfo {
+	return hypervisorInfo{}
+}
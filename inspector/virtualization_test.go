@@ -0,0 +1,93 @@
+package inspector
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+func TestHypervisorFromDMI(t *testing.T) {
+	tests := []struct {
+		fields []string
+		want   string
+	}{
+		{[]string{"QEMU", "Standard PC (Q35 + ICH9, 2009)"}, HypervisorQEMU},
+		{[]string{"Amazon EC2", "m7i.large"}, HypervisorKVM},
+		{[]string{"Amazon EC2", "m5.metal"}, ""},
+		{[]string{"Amazon EC2", "m7i.metal-24xl"}, ""},
+		{[]string{"Microsoft Corporation", "Virtual Machine"}, HypervisorHyperV},
+		{[]string{"VMware, Inc.", "VMware20,1"}, HypervisorVMware},
+		{[]string{"innotek GmbH", "VirtualBox"}, HypervisorVirtualBox},
+		{[]string{"Xen", "HVM domU"}, HypervisorXen},
+		{[]string{"Parallels Software International Inc.", "Parallels ARM Virtual Machine"}, HypervisorParallels},
+		{[]string{"VirtualMac2,1"}, HypervisorApple},
+		{[]string{"LENOVO", "21K5CTO1WW"}, ""},
+		{[]string{"Microsoft Corporation", "Surface Laptop 5"}, ""},
+	}
+	for _, tt := range tests {
+		if got := hypervisorFromDMI(tt.fields...); got != tt.want {
+			t.Errorf("hypervisorFromDMI(%q) = %q, want %q", tt.fields, got, tt.want)
+		}
+	}
+}
+
+func TestCombineHypervisor(t *testing.T) {
+	ec2 := hypervisorInfo{hypervisor: HypervisorKVM, product: "Amazon EC2 c6i.large", sources: []string{"dmi"}}
+
+	// Without the CPUID hypervisor bit an EC2 instance is bare metal
+	if info := combineHypervisor(ec2, true, false, ""); info.hypervisor != "" || len(info.sources) != 0 {
+		t.Errorf("bare metal EC2 = %+v, want no hypervisor", info)
+	}
+	if info := combineHypervisor(ec2, true, true, "KVMKVMKVM\x00\x00\x00"); info.hypervisor != HypervisorKVM || info.cpuidVendor != "KVMKVMKVM" {
+		t.Errorf("Nitro guest = %+v, want kvm", info)
+	}
+	// Where CPUID cannot be read, DMI stands
+	if info := combineHypervisor(ec2, false, false, ""); info.hypervisor != HypervisorKVM {
+		t.Errorf("EC2 without CPUID = %+v, want kvm from DMI", info)
+	}
+	// A hypervisor hiding its CPUID bit is still found through DMI
+	vmware := hypervisorInfo{hypervisor: HypervisorVMware, product: "VMware, Inc. VMware20,1", sources: []string{"dmi"}}
+	if info := combineHypervisor(vmware, true, false, ""); info.hypervisor != HypervisorVMware {
+		t.Errorf("VMware = %+v, want vmware from DMI", info)
+	}
+}
+
+func TestClassifyTPMKind(t *testing.T) {
+	tests := []struct {
+		manufacturer string
+		virtualized  bool
+		want         string
+	}{
+		{"IFX", false, TPMKindDiscrete},
+		{"NTC", false, TPMKindDiscrete},
+		{"INTC", false, TPMKindFirmware},
+		{"AMD", false, TPMKindFirmware},
+		{"MSFT", false, TPMKindFirmware}, // Pluton
+		{"MSFT", true, TPMKindVirtual},   // Hyper-V vTPM
+		{"IBM", false, TPMKindVirtual},   // swtpm
+		{"GOOG", true, TPMKindVirtual},
+		{"Unknown", false, ""},
+		{"Unknown", true, TPMKindVirtual},
+	}
+	for _, tt := range tests {
+		if got := classifyTPMKind(tt.manufacturer, tt.virtualized); got != tt.want {
+			t.Errorf("classifyTPMKind(%q, %v) = %q, want %q", tt.manufacturer, tt.virtualized, got, tt.want)
+		}
+	}
+}
+
+func TestGetVirtualizationStatus(t *testing.T) {
+	result, err := GetVirtualizationStatusContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetVirtualizationStatus failed: %v", err)
+	}
+	if result.Platform != runtime.GOOS {
+		t.Errorf("Platform = %q, want %q", result.Platform, runtime.GOOS)
+	}
+	if result.Virtualized != (result.Hypervisor != "") {
+		t.Errorf("Virtualized = %v with hypervisor %q", result.Virtualized, result.Hypervisor)
+	}
+	if result.VTPM && result.TPMKind != TPMKindVirtual {
+		t.Errorf("VTPM with tpm_kind %q", result.TPMKind)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build windows
+
+package inspector
+
+import (
+	"context"
+	"strings"
+)
+
+// Win32_ComputerSystem represents the WMI class (system vendor and model)
+type Win32_ComputerSystem struct {
+	Manufacturer string
+	Model        string
+}
+
+// platformHypervisor identifies a hypervisor from the SMBIOS system vendor
+// and model reported by WMI
+func platformHypervisor(ctx context.Context) hypervisorInfo {
+	var info hypervisorInfo
+	var systems []Win32_ComputerSystem
+	if err := wmiQuery("SELECT Manufacturer, Model FROM Win32_ComputerSystem", &systems, ""); err != nil {
+		info.err = classifyWMIError(ctx, `root\cimv2`, err)
+		return info
+	}
+	if len(systems) == 0 {
+		return info
+	}
+	sys := systems[0]
+	info.product = strings.TrimSpace(sys.Manufacturer + " " + sys.Model)
+	if h := hypervisorFromDMI(sys.Manufacturer, sys.Model); h != "" {
+		info.hypervisor = h
+		info.sources = append(info.sources, "dmi")
+	}
+	return info
+}
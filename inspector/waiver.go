@@ -0,0 +1,258 @@
+package inspector
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// WaiversEnv names the waiver file read by every summary, instead of
+	// the default location (see WaiversPath)
+	WaiversEnv = "OMNITRUST_WAIVERS"
+	// WaiversScoreEnv set to true scores a failed check as passed when all
+	// of its findings are waived
+	WaiversScoreEnv = "OMNITRUST_WAIVERS_SCORE"
+)
+
+// Waiver accepts the risk of a finding until it expires: while it is
+// active, summaries list the finding under waived instead of findings
+type Waiver struct {
+	// Finding is the ID of the waived finding (see Finding.ID)
+	Finding   string    `json:"finding"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	// Until is when the waiver expires and the finding is reported again
+	Until time.Time `json:"until"`
+	// By is the user who waived the finding
+	By string `json:"by,omitempty"`
+}
+
+// WaiverList is the waiver file
+type WaiverList struct {
+	SchemaVersion ResultVersion `json:"schema_version"`
+
+	Waivers []Waiver `json:"waivers"`
+}
+
+// WaivedFinding is a finding left out of a summary's findings, with the
+// waiver that accepts it
+type WaivedFinding struct {
+	Finding
+	Waiver Waiver `json:"waiver"`
+}
+
+// findingIDPattern matches finding IDs (see Finding.ID)
+var findingIDPattern = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+// WaiversPath returns the waiver file: OMNITRUST_WAIVERS, or else
+// waivers.json next to the per-user config file
+func WaiversPath() string {
+	if path := os.Getenv(WaiversEnv); path != "" {
+		return path
+	}
+	return userConfigFile("waivers.json")
+}
+
+// WaiversScored reports whether waived checks are scored as passed
+func WaiversScored() bool {
+	scored, _ := strconv.ParseBool(os.Getenv(WaiversScoreEnv))
+	return scored
+}
+
+// Active reports whether the waiver has not expired at now
+func (w Waiver) Active(now time.Time) bool {
+	return now.Before(w.Until)
+}
+
+// NewWaiver waives a finding until the given time, for a reason
+func NewWaiver(finding, reason string, until time.Time) (Waiver, error) {
+	w := Waiver{Finding: finding, Reason: strings.TrimSpace(reason), CreatedAt: time.Now().UTC(), Until: until.UTC()}
+	if name := os.Getenv("USER"); name != "" {
+		w.By = name
+	} else if name := os.Getenv("USERNAME"); name != "" {
+		w.By = name
+	}
+	return w, w.validate(w.CreatedAt)
+}
+
+// validate checks a waiver being added at now
+func (w Waiver) validate(now time.Time) error {
+	switch {
+	case !findingIDPattern.MatchString(w.Finding):
+		return fmt.Errorf("invalid finding ID %q (e.g. secure_boot_disabled; see the findings of posture summary -f json)", w.Finding)
+	case w.Reason == "":
+		return errors.New("a waiver needs a reason")
+	case !w.Active(now):
+		return fmt.Errorf("the waiver of %s would already have expired on %s", w.Finding, w.Until.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ParseWaiverExpiry parses when a waiver expires: a date (YYYY-MM-DD,
+// through the end of that day in local time), an RFC 3339 time, or a
+// duration from now in days or weeks (30d, 12w) or Go syntax (720h)
+func ParseWaiverExpiry(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if n, unit := strings.TrimRight(s, "dw"), strings.TrimLeft(s, "0123456789"); n != s && (unit == "d" || unit == "w") {
+		days, err := strconv.Atoi(n)
+		if err == nil && days > 0 {
+			if unit == "w" {
+				days *= 7
+			}
+			return now.AddDate(0, 0, days), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid expiry %q (use a date like 2026-12-31, an RFC 3339 time, or a duration like 90d)", s)
+}
+
+// Add stores a waiver, replacing an earlier waiver of the same finding
+func (l *WaiverList) Add(w Waiver) {
+	l.Remove(w.Finding)
+	l.Waivers = append(l.Waivers, w)
+	slices.SortFunc(l.Waivers, func(a, b Waiver) int { return strings.Compare(a.Finding, b.Finding) })
+}
+
+// Remove deletes the waiver of a finding, reporting whether there was one
+func (l *WaiverList) Remove(finding string) bool {
+	n := len(l.Waivers)
+	l.Waivers = slices.DeleteFunc(l.Waivers, func(w Waiver) bool { return w.Finding == finding })
+	return len(l.Waivers) != n
+}
+
+// LoadWaivers reads the waiver file at path. A missing file is not an
+// error and yields an empty list.
+func LoadWaivers(path string) (*WaiverList, error) {
+	l := &WaiverList{Waivers: []Waiver{}}
+	if path == "" {
+		return l, nil
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- configured waiver path
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("invalid waivers %s: %w", path, err)
+	}
+	if err := CheckSchemaVersion(l.SchemaVersion); err != nil {
+		return nil, fmt.Errorf("waivers %s: %w", path, err)
+	}
+	for _, w := range l.Waivers {
+		if w.Finding == "" || w.Until.IsZero() {
+			return nil, fmt.Errorf("invalid waivers %s: every waiver needs a finding and until", path)
+		}
+	}
+	return l, nil
+}
+
+// SaveWaivers writes the waiver file to path, creating its directory
+func SaveWaivers(path string, l *WaiverList) error {
+	if path == "" {
+		return errors.New("no waiver path: set " + WaiversEnv)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// applyWaivers moves the findings accepted by an active waiver out of
+// findings
+func applyWaivers(findings []Finding, waivers []Waiver, now time.Time) ([]Finding, []WaivedFinding) {
+	var kept []Finding
+	var waived []WaivedFinding
+	for _, f := range findings {
+		i := slices.IndexFunc(waivers, func(w Waiver) bool { return w.Finding == f.ID && w.Active(now) })
+		if i < 0 {
+			kept = append(kept, f)
+			continue
+		}
+		waived = append(waived, WaivedFinding{Finding: f, Waiver: waivers[i]})
+	}
+	return kept, waived
+}
+
+// waivedChecks returns passed with the failed checks whose findings are
+// all waived scored as passed, and those checks
+func waivedChecks(passed map[string]bool, findings []Finding, waived []WaivedFinding) (map[string]bool, []string) {
+	scored := make(map[string]bool, len(passed))
+	var checks []string
+	for id, ok := range passed {
+		scored[id] = ok
+		if ok || slices.ContainsFunc(findings, func(f Finding) bool { return f.Check == id }) {
+			continue
+		}
+		if slices.ContainsFunc(waived, func(w WaivedFinding) bool { return w.Check == id }) {
+			scored[id] = true
+			checks = append(checks, id)
+		}
+	}
+	slices.SortFunc(checks, compareCheckOrder)
+	return scored, checks
+}
+
+// FormatWaiversTable formats the waiver file as a colored listing
+func FormatWaiversTable(l *WaiverList) string {
+	return FormatWaiversTableWith(CurrentStyler(), l)
+}
+
+// FormatWaiversTableWith is like FormatWaiversTable but styles the table
+// with st
+func FormatWaiversTableWith(st Styler, l *WaiverList) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(IconShield + " " + T("Waivers")))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n")
+	if len(l.Waivers) == 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.Muted(T("No findings are waived")))
+		sb.WriteString("\n")
+	}
+	now := time.Now()
+	for _, w := range l.Waivers {
+		sb.WriteString("\n")
+		sb.WriteString(st.BoldText(w.Finding))
+		if w.Active(now) {
+			sb.WriteString(" " + st.Muted(T("until %s", w.Until.Local().Format(time.DateOnly))))
+		} else {
+			sb.WriteString(" " + st.Warning(T("expired %s", w.Until.Local().Format(time.DateOnly))))
+		}
+		sb.WriteString("\n  " + w.Reason + "\n")
+		if w.By != "" {
+			sb.WriteString("  " + st.Muted(T("by %s on %s", w.By, w.CreatedAt.Local().Format(time.DateOnly))) + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// FormatWaivers formats the waiver file in the specified format
+func FormatWaivers(l *WaiverList, format string) string {
+	return FormatOutputWith(l, func(st Styler) string {
+		return FormatWaiversTableWith(st, l)
+	}, format)
+}
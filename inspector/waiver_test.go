@@ -0,0 +1,162 @@
+package inspector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseWaiverExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"2026-12-31":           time.Date(2026, 12, 31, 23, 59, 59, 0, time.Local),
+		"2026-11-01T09:00:00Z": time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC),
+		"90d":                  now.AddDate(0, 0, 90),
+		"2w":                   now.AddDate(0, 0, 14),
+		"36h":                  now.Add(36 * time.Hour),
+	}
+	for in, want := range tests {
+		if got, err := ParseWaiverExpiry(in, now); err != nil || !got.Equal(want) {
+			t.Errorf("ParseWaiverExpiry(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "soon", "0d", "-5d", "-1h", "2026-13-01"} {
+		if _, err := ParseWaiverExpiry(in, now); err == nil {
+			t.Errorf("ParseWaiverExpiry(%q) should fail", in)
+		}
+	}
+}
+
+func TestNewWaiver_Invalid(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	if _, err := NewWaiver("Secure Boot", "reason", future); err == nil {
+		t.Error("a finding ID with spaces should be rejected")
+	}
+	if _, err := NewWaiver("reboot_pending", " ", future); err == nil {
+		t.Error("a waiver without a reason should be rejected")
+	}
+	if _, err := NewWaiver("reboot_pending", "change freeze", time.Now().Add(-time.Hour)); err == nil {
+		t.Error("an expired waiver should be rejected")
+	}
+}
+
+func TestWaiversRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "omnitrust", "waivers.json")
+	t.Setenv(WaiversEnv, path)
+	if WaiversPath() != path {
+		t.Fatalf("WaiversPath = %q, want %q", WaiversPath(), path)
+	}
+	l, err := LoadWaivers(path)
+	if err != nil || len(l.Waivers) != 0 {
+		t.Fatalf("a missing waiver file should load empty, got %+v, %v", l, err)
+	}
+
+	first, err := NewWaiver("reboot_pending", "change freeze", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := first
+	second.Reason = "extended freeze"
+	l.Add(first)
+	l.Add(second)
+	if err := SaveWaivers(path, l); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadWaivers(path)
+	if err != nil || len(loaded.Waivers) != 1 || loaded.Waivers[0].Reason != "extended freeze" {
+		t.Fatalf("LoadWaivers = %+v, %v, want the replaced waiver", loaded, err)
+	}
+	if !loaded.Remove("reboot_pending") || loaded.Remove("reboot_pending") || len(loaded.Waivers) != 0 {
+		t.Errorf("Remove left %+v", loaded.Waivers)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"waivers": [{"finding": "reboot_pending"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadWaivers(path); err == nil {
+		t.Error("a waiver without until should be rejected")
+	}
+}
+
+func TestApplyWaivers(t *testing.T) {
+	now := time.Now()
+	findings := []Finding{
+		{ID: "reboot_pending", Check: CheckUptime},
+		{ID: "firmware_update_available", Check: CheckFirmware},
+		{ID: "biometrics_not_configured", Check: CheckBiometrics},
+	}
+	waivers := []Waiver{
+		{Finding: "reboot_pending", Reason: "change freeze", Until: now.Add(time.Hour)},
+		{Finding: "biometrics_not_configured", Reason: "expired", Until: now.Add(-time.Hour)},
+	}
+	kept, waived := applyWaivers(findings, waivers, now)
+	if len(kept) != 2 || len(waived) != 1 || waived[0].ID != "reboot_pending" || waived[0].Waiver.Reason != "change freeze" {
+		t.Fatalf("applyWaivers = %+v, %+v", kept, waived)
+	}
+
+	// Only a check with no findings left is scored as passed
+	waived = append(waived, WaivedFinding{Finding: Finding{ID: "firmware_hsi_low", Check: CheckFirmware}})
+	passed := map[string]bool{CheckUptime: false, CheckFirmware: false, CheckTPM: true}
+	scored, checks := waivedChecks(passed, kept, waived)
+	if !slices.Equal(checks, []string{CheckUptime}) || !scored[CheckUptime] || scored[CheckFirmware] || !scored[CheckTPM] {
+		t.Errorf("waivedChecks = %v, %v", scored, checks)
+	}
+	if passed[CheckUptime] {
+		t.Error("waivedChecks should not change passed")
+	}
+}
+
+func TestSummary_Waivers(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(FixtureEnv, dir)
+	t.Setenv(OnlyChecksEnv, CheckTPM)
+	t.Setenv(DisableChecksEnv, "")
+	t.Setenv(ScanProfileEnv, "")
+	t.Setenv(BaselineEnv, filepath.Join(dir, "baseline.json"))
+	t.Setenv(WaiversEnv, filepath.Join(dir, "waivers.json"))
+	t.Setenv(WaiversScoreEnv, "")
+	if err := os.WriteFile(filepath.Join(dir, "tpm.json"), []byte(`{"present": false, "type": "none"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWaiver("tpm_missing", "virtual desktop", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveWaivers(WaiversPath(), &WaiverList{Waivers: []Waiver{w}}); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := GetSecuritySummaryContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.ContainsFunc(summary.Findings, func(f Finding) bool { return f.ID == "tpm_missing" }) {
+		t.Error("the waived finding is still among the findings")
+	}
+	if len(summary.Waived) != 1 || summary.Waived[0].Waiver.Reason != "virtual desktop" {
+		t.Errorf("waived = %+v", summary.Waived)
+	}
+	if summary.CheckResults[CheckTPM] != CheckResultFail || summary.WaivedChecks != nil {
+		t.Errorf("without %s the check should still fail, got %v, %v", WaiversScoreEnv, summary.CheckResults, summary.WaivedChecks)
+	}
+	failedScore := summary.OverallScore
+
+	t.Setenv(WaiversScoreEnv, "true")
+	summary, err = GetSecuritySummaryContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(summary.WaivedChecks, []string{CheckTPM}) || summary.OverallScore <= failedScore {
+		t.Errorf("with %s: waived checks %v, score %d (was %d)", WaiversScoreEnv, summary.WaivedChecks, summary.OverallScore, failedScore)
+	}
+	// The check result is the check's own outcome, as baselines record it
+	if summary.CheckResults[CheckTPM] != CheckResultFail {
+		t.Errorf("check results = %v", summary.CheckResults)
+	}
+	if c := byCheckOf(ExplainScore(summary))[CheckTPM]; c.Result != CheckResultWaived || c.AwardedPoints != c.PossiblePoints {
+		t.Errorf("score explanation = %+v, want the waived check scored", c)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package inspector
+
+// openWMISession is a no-op outside Windows
+func openWMISession() func() {
+	return func() {}
+}
@@ -0,0 +1,81 @@
+//go:build windows
+
+package inspector
+
+import (
+	"sync"
+
+	"github.com/yusufpapurcu/wmi"
+)
+
+// wmiSession is the WMI connection shared by the probes of a scan. It keeps
+// one COM-initialized thread and SWbemLocator for every query instead of
+// initializing COM and creating a locator per query.
+var wmiSession struct {
+	mu       sync.Mutex
+	services *wmi.SWbemServices
+	refs     int
+}
+
+// openWMISession starts sharing one WMI connection across queries until the
+// returned function is called. Sessions nest: the connection is released
+// when the last one ends. If the connection cannot be set up, queries fall
+// back to connecting on their own.
+func openWMISession() func() {
+	wmiSession.mu.Lock()
+	defer wmiSession.mu.Unlock()
+	if wmiSession.refs == 0 {
+		services, err := wmi.InitializeSWbemServices(wmi.DefaultClient)
+		if err != nil {
+			Logger().Info("cannot open a shared WMI session", "err", err)
+			return func() {}
+		}
+		wmiSession.services = services
+	}
+	wmiSession.refs++
+
+	var once sync.Once
+	return func() { once.Do(closeWMISession) }
+}
+
+// closeWMISession ends one session, releasing the connection after the last
+func closeWMISession() {
+	wmiSession.mu.Lock()
+	defer wmiSession.mu.Unlock()
+	if wmiSession.refs--; wmiSession.refs > 0 {
+		return
+	}
+	services := wmiSession.services
+	wmiSession.services = nil
+	// Close waits for queued queries, which a probe that timed out may
+	// still hold, so the scan does not wait for it
+	go func() {
+		if err := services.Close(); err != nil {
+			Logger().Debug("cannot close the shared WMI session", "err", err)
+		}
+	}()
+}
+
+// wmiQuery runs a WQL query in namespace (the default namespace if empty),
+// through the shared session when one is open
+func wmiQuery(query string, dst any, namespace string) error {
+	var args []any
+	if namespace != "" {
+		args = []any{nil, namespace}
+	}
+	wmiSession.mu.Lock()
+	services := wmiSession.services
+	wmiSession.mu.Unlock()
+	var err error
+	if services != nil {
+		err = services.Query(query, dst, args...)
+	} else {
+		err = wmi.Query(query, dst, args...)
+	}
+	detail := "WMI " + query
+	if namespace != "" {
+		detail = "WMI " + namespace + ": " + query
+	}
+	recordSource(SourceAPI, detail, err)
+	return err
+}
@@ -0,0 +1,212 @@
+package inspector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// StatusNotApplicableInWSL marks checks that describe the WSL guest rather
+// than the Windows host; they are reported but excluded from the score
+const StatusNotApplicableInWSL = "not_applicable_in_wsl"
+
+// WSLInfo describes a Windows Subsystem for Linux guest
+type WSLInfo struct {
+	// Version is 1 or 2
+	Version int    `json:"version"`
+	Distro  string `json:"distro,omitempty"`
+	// Interop reports whether Windows executables can be launched from the guest
+	Interop bool `json:"interop"`
+	// Host carries posture hints read from the Windows host through interop
+	Host *WSLHostHints `json:"host,omitempty"`
+}
+
+// WSLHostHints are the Windows host's security features as seen through
+// interop, using only queries that do not require an elevated Windows
+// session. They are hints: the guest cannot verify them.
+//
+// Each hint is empty when the host did not report it.
+type WSLHostHints struct {
+	// TPM is the TPM version (e.g. "2.0"), or "absent"
+	TPM             string `json:"tpm,omitempty"`
+	TPMManufacturer string `json:"tpm_manufacturer,omitempty"`
+	// SecureBoot is "on" or "off"
+	SecureBoot string `json:"secure_boot,omitempty"`
+	// BitLocker is the protection status of C: (on, off, encrypting,
+	// decrypting, suspended, locked)
+	BitLocker string `json:"bitlocker,omitempty"`
+	// Error is set when the interop query failed
+	Error *ProbeError `json:"error,omitempty"`
+}
+
+// wslHostScript reads Secure Boot state from the registry, C: BitLocker
+// protection from the shell property store, and the hardware IDs of the
+// TPM's Plug and Play device; none needs elevation. The Windows display
+// language is not set by the environment like a Unix locale, so the script
+// switches to the invariant culture itself and reports only numbers and
+// device IDs, which are never translated (tpmtool's labels are).
+const wslHostScript = `[Threading.Thread]::CurrentThread.CurrentCulture = [Globalization.CultureInfo]::InvariantCulture; ` +
+	`[Threading.Thread]::CurrentThread.CurrentUICulture = [Globalization.CultureInfo]::InvariantCulture; ` +
+	`$sb = (Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Control\SecureBoot\State' -ErrorAction SilentlyContinue).UEFISecureBootEnabled; ` +
+	`$bl = (New-Object -ComObject Shell.Application).NameSpace('C:').Self.ExtendedProperty('System.Volume.BitLockerProtection'); ` +
+	`$tpm = Get-CimInstance Win32_PnPEntity -Filter 'PNPDeviceID LIKE ''ACPI%''' | Where-Object { (@($_.HardwareID) + @($_.CompatibleID)) -match '^ACPI\\(MSFT0101|PNP0C31)$' } | Select-Object -First 1; ` +
+	`$ids = @(if ($tpm) { $tpm.HardwareID; $tpm.CompatibleID }); ` +
+	`[pscustomobject]@{SecureBoot=$sb; BitLocker=$bl; TPM=$ids} | ConvertTo-Json -Compress`
+
+// bitLockerProtection maps System.Volume.BitLockerProtection values
+var bitLockerProtection = map[int]string{
+	1: "on",
+	2: "off",
+	3: "encrypting",
+	4: "decrypting",
+	5: "suspended",
+	6: "locked",
+}
+
+// detectWSL identifies a WSL guest from the kernel release and the interop
+// binfmt handler. It returns nil outside WSL.
+func detectWSL(root fs.FS, getenv func(string) string) *WSLInfo {
+	release, err := readFSFile(root, "proc/sys/kernel/osrelease")
+	if err != nil || !strings.Contains(strings.ToLower(string(release)), "microsoft") {
+		return nil
+	}
+	info := &WSLInfo{Version: 1, Distro: getenv("WSL_DISTRO_NAME")}
+	// WSL2 kernels are "...-microsoft-standard-WSL2"; WSL1 reports "...-Microsoft"
+	if strings.Contains(string(release), "WSL2") || strings.Contains(string(release), "microsoft-standard") {
+		info.Version = 2
+	}
+	if _, err := fs.Stat(root, "proc/sys/fs/binfmt_misc/WSLInterop"); err == nil {
+		info.Interop = true
+	}
+	return info
+}
+
+// wslHostHints queries the Windows host through interop
+func wslHostHints(ctx context.Context) *WSLHostHints {
+	out, err := runCommand(ctx, "powershell.exe", "-NoProfile", "-NonInteractive", "-Command", wslHostScript)
+	if err != nil {
+		return &WSLHostHints{Error: classifyExecError(ctx, "powershell.exe", err)}
+	}
+	hints, err := parseWSLHostScript(out)
+	if err != nil {
+		return &WSLHostHints{Error: newProbeError(ctx, ErrProbeFailed, "powershell.exe", "unexpected output: "+err.Error())}
+	}
+	return hints
+}
+
+// parseWSLHostScript parses the JSON written by wslHostScript
+func parseWSLHostScript(data []byte) (*WSLHostHints, error) {
+	var v struct {
+		SecureBoot *int     `json:"SecureBoot"`
+		BitLocker  *int     `json:"BitLocker"`
+		TPM        []string `json:"TPM"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(data), &v); err != nil {
+		return nil, err
+	}
+	hints := &WSLHostHints{}
+	if v.SecureBoot != nil {
+		hints.SecureBoot = "off"
+		if *v.SecureBoot == 1 {
+			hints.SecureBoot = "on"
+		}
+	}
+	if v.BitLocker != nil {
+		hints.BitLocker = bitLockerProtection[*v.BitLocker]
+	}
+	if v.TPM != nil {
+		hints.TPM, hints.TPMManufacturer = parseTPMHardwareIDs(v.TPM)
+	}
+	return hints, nil
+}
+
+// parseTPMHardwareIDs identifies a TPM from the hardware and compatible IDs
+// of its Plug and Play device, e.g. ACPI\NTC0702 and ACPI\MSFT0101 for a
+// Nuvoton TPM 2.0. It returns the TPM version, or "absent" without IDs, and
+// the vendor when a hardware ID names one.
+func parseTPMHardwareIDs(ids []string) (tpm, manufacturer string) {
+	if len(ids) == 0 {
+		return "absent", ""
+	}
+	tpm = "present"
+	for _, id := range ids {
+		id = strings.TrimPrefix(strings.TrimPrefix(strings.ToUpper(id), `ACPI\`), "*")
+		// Windows also lists ACPI IDs as VEN_NTC&DEV_0702
+		if vendor, dev, ok := strings.Cut(strings.TrimPrefix(id, "VEN_"), "&DEV_"); ok {
+			id = vendor + dev
+		}
+		switch id {
+		case "MSFT0101":
+			tpm = "2.0"
+		case "PNP0C31":
+			if tpm != "2.0" {
+				tpm = "1.2"
+			}
+		}
+		if vendor := acpiTPMVendor(id); vendor != "" && manufacturer == "" {
+			manufacturer = tpmVendorName(vendor)
+		}
+	}
+	return tpm, manufacturer
+}
+
+// wslFindings turns host hints into findings for the Windows host
+func wslFindings(h *WSLHostHints) []Finding {
+	if h == nil {
+		return nil
+	}
+	var findings []Finding
+	if h.Error != nil {
+		findings = append(findings, unverifiedFinding("wsl_host_unverified", CheckWSLHost, "Windows host", h.Error))
+	}
+	if h.TPM == "absent" {
+		findings = append(findings, Finding{
+			ID:          "wsl_host_tpm_missing",
+			Title:       T("Windows host reports no TPM"),
+			Severity:    SeverityHigh,
+			Check:       CheckWSLHost,
+			Remediation: T("Enable the TPM in the Windows host's firmware settings"),
+		})
+	}
+	if h.SecureBoot == "off" {
+		findings = append(findings, Finding{
+			ID:          "wsl_host_secure_boot_disabled",
+			Title:       T("Secure Boot is disabled on the Windows host"),
+			Severity:    SeverityHigh,
+			Check:       CheckWSLHost,
+			Remediation: T("Enable Secure Boot on the Windows host"),
+		})
+	}
+	switch h.BitLocker {
+	case "off", "decrypting", "suspended":
+		findings = append(findings, Finding{
+			ID:                 "wsl_host_bitlocker_disabled",
+			Title:              T("BitLocker is not protecting the Windows host system drive"),
+			Severity:           SeverityCritical,
+			Check:              CheckWSLHost,
+			Remediation:        T("Enable BitLocker on the Windows host system drive"),
+			RemediationCommand: "manage-bde.exe -on C:",
+		})
+	}
+	return findings
+}
+
+// formatWSLHost renders the host hints as one line for table output
+func formatWSLHost(h *WSLHostHints) string {
+	if h == nil {
+		return ""
+	}
+	return fmt.Sprintf("TPM %s, Secure Boot %s, BitLocker %s",
+		hintValue(h.TPM), hintValue(h.SecureBoot), hintValue(h.BitLocker))
+}
+
+// hintValue returns a host hint for display, or "unknown"
+func hintValue(v string) string {
+	if v == "" {
+		return T("unknown")
+	}
+	return v
+}
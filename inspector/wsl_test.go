@@ -0,0 +1,155 @@
+package inspector
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDetectWSL(t *testing.T) {
+	env := func(k string) string {
+		if k == "WSL_DISTRO_NAME" {
+			return "Ubuntu-24.04"
+		}
+		return ""
+	}
+
+	wsl2 := fstest.MapFS{
+		"proc/sys/kernel/osrelease":          {Data: []byte("5.15.167.4-microsoft-standard-WSL2\n")},
+		"proc/sys/fs/binfmt_misc/WSLInterop": {},
+	}
+	info := detectWSL(wsl2, env)
+	if info == nil || info.Version != 2 || info.Distro != "Ubuntu-24.04" || !info.Interop {
+		t.Errorf("WSL2 = %+v", info)
+	}
+
+	wsl1 := fstest.MapFS{"proc/sys/kernel/osrelease": {Data: []byte("4.4.0-19041-Microsoft\n")}}
+	if info := detectWSL(wsl1, env); info == nil || info.Version != 1 || info.Interop {
+		t.Errorf("WSL1 = %+v", info)
+	}
+
+	host := fstest.MapFS{"proc/sys/kernel/osrelease": {Data: []byte("6.8.0-45-generic\n")}}
+	if info := detectWSL(host, env); info != nil {
+		t.Errorf("bare metal detected as WSL: %+v", info)
+	}
+}
+
+func TestParseTPMHardwareIDs(t *testing.T) {
+	tests := []struct {
+		ids              []string
+		wantTPM, wantMfr string
+	}{
+		{[]string{`ACPI\VEN_NTC&DEV_0702`, `ACPI\NTC0702`, "*NTC0702", `ACPI\MSFT0101`, "*MSFT0101"}, "2.0", "Nuvoton"},
+		{[]string{`ACPI\MSFT0101`, "*MSFT0101"}, "2.0", ""},
+		{[]string{`ACPI\IFX0102`, `ACPI\PNP0C31`, "*PNP0C31"}, "1.2", "Infineon"},
+		{[]string{}, "absent", ""},
+	}
+	for _, tt := range tests {
+		tpm, manufacturer := parseTPMHardwareIDs(tt.ids)
+		if tpm != tt.wantTPM || manufacturer != tt.wantMfr {
+			t.Errorf("parseTPMHardwareIDs(%q) = %q, %q, want %q, %q", tt.ids, tpm, manufacturer, tt.wantTPM, tt.wantMfr)
+		}
+	}
+}
+
+func TestParseWSLHostScript(t *testing.T) {
+	hints, err := parseWSLHostScript(fixture(t, "wsl/host.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := WSLHostHints{TPM: "2.0", TPMManufacturer: "Intel", SecureBoot: "on", BitLocker: "off"}
+	if *hints != want {
+		t.Errorf("parseWSLHostScript = %+v, want %+v", *hints, want)
+	}
+
+	tests := []struct {
+		out  string
+		want WSLHostHints
+	}{
+		{`{"SecureBoot":1,"BitLocker":1,"TPM":[]}`, WSLHostHints{TPM: "absent", SecureBoot: "on", BitLocker: "on"}},
+		{`{"SecureBoot":0,"BitLocker":2}`, WSLHostHints{SecureBoot: "off", BitLocker: "off"}},
+		{`{"SecureBoot":null,"BitLocker":5,"TPM":null}`, WSLHostHints{BitLocker: "suspended"}},
+	}
+	for _, tt := range tests {
+		hints, err := parseWSLHostScript([]byte(tt.out + "\r\n"))
+		if err != nil || *hints != tt.want {
+			t.Errorf("parseWSLHostScript(%s) = %+v, %v, want %+v", tt.out, hints, err, tt.want)
+		}
+	}
+	if _, err := parseWSLHostScript([]byte("Get-ItemProperty : Access denied")); err == nil {
+		t.Error("expected error for non-JSON output")
+	}
+}
+
+// stubWSL makes GetRuntimeEnvironment detect a WSL2 guest whose Windows
+// host has a TPM, Secure Boot, and BitLocker turned off
+func stubWSL(t *testing.T) *FakeRunner {
+	t.Helper()
+	stubContainer(t)
+	environmentRoot = fstest.MapFS{
+		"proc/sys/kernel/osrelease":          {Data: []byte("5.15.167.4-microsoft-standard-WSL2\n")},
+		"proc/sys/fs/binfmt_misc/WSLInterop": {},
+	}
+	fake := NewFakeRunner().
+		Set("powershell.exe -NoProfile -NonInteractive -Command "+wslHostScript, fixture(t, "wsl/host.json"))
+	prev := SetCommandRunner(fake)
+	t.Cleanup(func() { SetCommandRunner(prev) })
+	return fake
+}
+
+func TestGetRuntimeEnvironment_WSL(t *testing.T) {
+	stubWSL(t)
+
+	env := GetRuntimeEnvironmentContext(context.Background())
+	if env.Containerized || env.WSL == nil || env.WSL.Host == nil {
+		t.Fatalf("env = %+v, want WSL with host hints", env)
+	}
+	want := WSLHostHints{TPM: "2.0", TPMManufacturer: "Intel", SecureBoot: "on", BitLocker: "off"}
+	if *env.WSL.Host != want {
+		t.Errorf("host = %+v, want %+v", *env.WSL.Host, want)
+	}
+}
+
+func TestWSLHostHints_InteropFailure(t *testing.T) {
+	fake := NewFakeRunner().
+		SetError("powershell.exe -NoProfile -NonInteractive -Command "+wslHostScript, &exec.ExitError{Stderr: []byte("Access is denied.\r\n")})
+	defer SetCommandRunner(SetCommandRunner(fake))
+
+	hints := wslHostHints(context.Background())
+	if hints.Error == nil || hints.Error.Code != CodePermissionDenied {
+		t.Errorf("Error = %+v, want permission_denied", hints.Error)
+	}
+	if hints.TPM != "" || hints.SecureBoot != "" {
+		t.Errorf("hints = %+v, want unknown", hints)
+	}
+}
+
+func TestGetSecuritySummary_WSL(t *testing.T) {
+	stubWSL(t)
+	t.Setenv(OnlyChecksEnv, "")
+	t.Setenv(DisableChecksEnv, "")
+	t.Setenv(MandatoryChecksEnv, "secure_boot")
+	t.Setenv(InformationalChecksEnv, "")
+
+	result, err := GetSecuritySummaryContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetSecuritySummary failed: %v", err)
+	}
+	if result.Environment == nil || result.Environment.WSL == nil {
+		t.Fatalf("Environment = %+v, want WSL", result.Environment)
+	}
+	for id, status := range result.NotApplicable {
+		if status != StatusNotApplicableInWSL {
+			t.Errorf("NotApplicable[%q] = %q, want %q", id, status, StatusNotApplicableInWSL)
+		}
+	}
+	if len(result.MandatoryFailures) != 0 {
+		t.Errorf("MandatoryFailures = %v, guest checks must not fail the gate", result.MandatoryFailures)
+	}
+	// Findings come from the host hints, not the guest's missing hardware
+	want := "wsl_host_bitlocker_disabled"
+	if len(result.Findings) != 1 || result.Findings[0].ID != want {
+		t.Errorf("Findings = %+v, want [%q]", result.Findings, want)
+	}
+}
@@ -0,0 +1,151 @@
+// Package omnitrust is the entry point for programs that embed the posture
+// checks. A Client runs the probes with its own timeout, command runner,
+// logger, and result cache, and returns plain result structs, so callers do
+// not need the inspector package's globals or its formatting helpers:
+//
+//	client := omnitrust.New(omnitrust.WithTimeout(30 * time.Second))
+//	summary, err := client.Summary(ctx)
+//
+// The inspector package keeps its functions (GetSecuritySummary,
+// GetTPMStatus, ...) for existing callers; Client wraps their Context
+// variants (GetSecuritySummaryContext, GetTPMStatusContext, ...).
+package omnitrust
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+// Results returned by a Client
+type (
+	SecuritySummary  = inspector.SecuritySummary
+	EncryptionResult = inspector.EncryptionResult
+	TPMResult        = inspector.TPMResult
+	Envelope         = inspector.Envelope
+	CommandRunner    = inspector.CommandRunner
+	CacheStats       = inspector.CacheStats
+)
+
+// Client runs posture probes. It is safe for concurrent use.
+type Client struct {
+	timeout time.Duration
+	runner  CommandRunner
+	logger  *slog.Logger
+	cache   *inspector.CachedInspector
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithTimeout bounds every call; zero (the default) leaves calls bounded
+// only by their context
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) { c.timeout = d }
+}
+
+// WithRunner runs the probes' external commands through r, for sandboxing,
+// auditing, or canned output in tests (see inspector.FakeRunner)
+func WithRunner(r CommandRunner) Option {
+	return func(c *Client) { c.runner = r }
+}
+
+// WithLogger sends the probes' diagnostics to l
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) { c.logger = l }
+}
+
+// WithCache reuses results younger than ttl; see Client.Invalidate
+func WithCache(ttl time.Duration) Option {
+	return func(c *Client) { c.cache = inspector.NewCachedInspector(ttl) }
+}
+
+// New creates a Client
+func New(opts ...Option) *Client {
+	c := &Client{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Summary runs every enabled check and scores the security posture
+func (c *Client) Summary(ctx context.Context) (*SecuritySummary, error) {
+	return call(ctx, c, "summary", inspector.GetSecuritySummaryContext)
+}
+
+// Encryption reports disk encryption (FileVault, BitLocker, or LUKS)
+func (c *Client) Encryption(ctx context.Context) (*EncryptionResult, error) {
+	return call(ctx, c, inspector.CheckEncryption, inspector.GetEncryptionStatusContext)
+}
+
+// TPM reports the platform security chip (TPM or Secure Enclave)
+func (c *Client) TPM(ctx context.Context) (*TPMResult, error) {
+	return call(ctx, c, inspector.CheckTPM, inspector.GetTPMStatusContext)
+}
+
+// Scan runs the security summary and wraps it in a report envelope with
+// the machine's identity and the probe errors, ready to ship to a sink
+func (c *Client) Scan(ctx context.Context) (*Envelope, error) {
+	started := time.Now()
+	summary, err := c.Summary(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return inspector.NewEnvelope(summary, started), nil
+}
+
+// Invalidate drops cached results for the given keys ("summary",
+// inspector.CheckEncryption, inspector.CheckTPM), or all of them when no
+// key is given. It does nothing without WithCache.
+func (c *Client) Invalidate(keys ...string) {
+	if c.cache != nil {
+		c.cache.Invalidate(keys...)
+	}
+}
+
+// CacheStats returns the cache's hit and miss counts, zero without
+// WithCache
+func (c *Client) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return c.cache.Stats()
+}
+
+// call runs probe with the client's cache, runner, logger, and timeout. A
+// probe still running when the context is done is abandoned, the commands
+// it runs are killed, and its result is discarded.
+func call[T any](ctx context.Context, c *Client, key string, probe func(context.Context) (T, error)) (T, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	if c.runner != nil {
+		ctx = inspector.WithCommandRunner(ctx, c.runner)
+	}
+	if c.logger != nil {
+		ctx = inspector.WithLogger(ctx, c.logger)
+	}
+
+	type outcome struct {
+		result T
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, _, err := inspector.Cached(c.cache, key, false, func() (T, error) { return probe(ctx) })
+		done <- outcome{result, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
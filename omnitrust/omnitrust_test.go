@@ -0,0 +1,120 @@
+package omnitrust
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+func TestCall_Timeout(t *testing.T) {
+	c := New(WithTimeout(10 * time.Millisecond))
+	release := make(chan struct{})
+	defer close(release)
+
+	_, err := call(context.Background(), c, "slow", func(context.Context) (int, error) {
+		<-release
+		return 1, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestCall_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	release := make(chan struct{})
+	defer close(release)
+
+	_, err := call(ctx, New(), "slow", func(context.Context) (int, error) {
+		<-release
+		return 1, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestClient_RunnerAndLogger(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("BitLocker is read from WMI, not with commands")
+	}
+	global := inspector.NewFakeRunner()
+	defer inspector.SetCommandRunner(inspector.SetCommandRunner(global))
+
+	// Two clients scanning at once each see only their own runner and logger
+	type client struct {
+		runner *inspector.FakeRunner
+		logs   bytes.Buffer
+	}
+	clients := make([]*client, 2)
+	var wg sync.WaitGroup
+	for i := range clients {
+		cl := &client{runner: inspector.NewFakeRunner()}
+		clients[i] = cl
+		logger := slog.New(slog.NewTextHandler(&cl.logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		c := New(WithRunner(cl.runner), WithLogger(logger))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Encryption(context.Background()); err != nil {
+				t.Errorf("Encryption failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, cl := range clients {
+		calls := cl.runner.Calls()
+		if len(calls) == 0 {
+			t.Errorf("client %d: no commands ran through its runner", i)
+		}
+		for _, cmdline := range calls {
+			if !strings.Contains(cl.logs.String(), cmdline) {
+				t.Errorf("client %d: %q not in its logs:\n%s", i, cmdline, cl.logs.String())
+			}
+		}
+	}
+	if calls := global.Calls(); len(calls) != 0 {
+		t.Errorf("process-wide runner ran %q, want nothing", calls)
+	}
+}
+
+func TestCall_Cache(t *testing.T) {
+	c := New(WithCache(time.Minute))
+	calls := 0
+	probe := func(context.Context) (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	for range 2 {
+		if v, err := call(context.Background(), c, "probe", probe); err != nil || v != 1 {
+			t.Fatalf("call = (%d, %v), want (1, nil)", v, err)
+		}
+	}
+	if s := c.CacheStats(); s.Hits != 1 || s.Misses != 1 {
+		t.Errorf("CacheStats = %+v, want 1 hit and 1 miss", s)
+	}
+
+	c.Invalidate("probe")
+	if v, _ := call(context.Background(), c, "probe", probe); v != 2 {
+		t.Errorf("call after Invalidate = %d, want a fresh result", v)
+	}
+}
+
+func TestClient_NoCache(t *testing.T) {
+	c := New()
+	c.Invalidate()
+	if s := c.CacheStats(); s != (CacheStats{}) {
+		t.Errorf("CacheStats = %+v, want zero without WithCache", s)
+	}
+}
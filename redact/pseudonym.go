@@ -0,0 +1,100 @@
+// Package redact masks identifying values (hostnames, usernames, serial
+// numbers, volume names) in reports so they can be shared outside the
+// organization.
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Kind identifies the category of an identifying value
+type Kind string
+
+// Kinds of identifying values
+const (
+	KindHostname Kind = "host"
+	KindUser     Kind = "user"
+	KindSerial   Kind = "serial"
+	KindVolume   Kind = "volume"
+)
+
+// Environment variables used to configure the pseudonymization salt
+const (
+	SaltEnv     = "OMNITRUST_REDACT_SALT"
+	SaltFileEnv = "OMNITRUST_REDACT_SALT_FILE"
+)
+
+// Mask is the replacement used when no salt is configured
+const Mask = "[redacted]"
+
+// pseudonymLength is the number of hex characters kept from the HMAC
+const pseudonymLength = 12
+
+// Pseudonymizer replaces identifying values with stable pseudonymous IDs.
+// IDs are derived with HMAC-SHA256 keyed by a per-deployment salt, so the
+// same host or user maps to the same ID in every report produced with that
+// salt, while the original value cannot be recovered without it.
+type Pseudonymizer struct {
+	salt []byte
+}
+
+// NewPseudonymizer creates a pseudonymizer keyed by salt. A nil or empty
+// salt yields a pseudonymizer that masks values instead, since an unsalted
+// hash of a hostname or username is trivially reversible by dictionary.
+func NewPseudonymizer(salt []byte) *Pseudonymizer {
+	return &Pseudonymizer{salt: salt}
+}
+
+// Stable reports whether the pseudonymizer produces stable IDs (a salt is set)
+func (p *Pseudonymizer) Stable() bool {
+	return p != nil && len(p.salt) > 0
+}
+
+// Pseudonym returns the pseudonymous ID for value, e.g. "host-3fa9c2e1b7d4"
+func (p *Pseudonymizer) Pseudonym(kind Kind, value string) string {
+	if value == "" {
+		return ""
+	}
+	if !p.Stable() {
+		return Mask
+	}
+
+	value = strings.TrimSpace(value)
+	if kind == KindHostname {
+		// Hostnames are case-insensitive; "Laptop-01" and "laptop-01" are one host
+		value = strings.ToLower(value)
+	}
+
+	mac := hmac.New(sha256.New, p.salt)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	sum := hex.EncodeToString(mac.Sum(nil))
+
+	return string(kind) + "-" + sum[:pseudonymLength]
+}
+
+// LoadSalt returns the salt configured via OMNITRUST_REDACT_SALT or the file
+// named by OMNITRUST_REDACT_SALT_FILE. It returns nil if neither is set.
+func LoadSalt() ([]byte, error) {
+	if salt := os.Getenv(SaltEnv); salt != "" {
+		return []byte(salt), nil
+	}
+	if path := os.Getenv(SaltFileEnv); path != "" {
+		data, err := os.ReadFile(path) // #nosec G304 -- operator-configured salt file
+		if err != nil {
+			return nil, fmt.Errorf("failed to read redaction salt file: %w", err)
+		}
+		salt := strings.TrimSpace(string(data))
+		if salt == "" {
+			return nil, fmt.Errorf("redaction salt file %s is empty", path)
+		}
+		return []byte(salt), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,96 @@
+package redact
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPseudonym_Stable(t *testing.T) {
+	p := NewPseudonymizer([]byte("deployment-salt"))
+
+	a := p.Pseudonym(KindHostname, "laptop-01")
+	b := p.Pseudonym(KindHostname, "laptop-01")
+	if a != b {
+		t.Errorf("pseudonyms differ for the same input: %q vs %q", a, b)
+	}
+	if !strings.HasPrefix(a, "host-") || len(a) != len("host-")+pseudonymLength {
+		t.Errorf("Pseudonym = %q, want host-<%d hex chars>", a, pseudonymLength)
+	}
+	if strings.Contains(a, "laptop") {
+		t.Errorf("pseudonym %q leaks the original value", a)
+	}
+}
+
+func TestPseudonym_HostnameCaseInsensitive(t *testing.T) {
+	p := NewPseudonymizer([]byte("salt"))
+	if p.Pseudonym(KindHostname, "Laptop-01") != p.Pseudonym(KindHostname, "laptop-01") {
+		t.Error("hostname pseudonyms should be case-insensitive")
+	}
+	if p.Pseudonym(KindUser, "Alice") == p.Pseudonym(KindUser, "alice") {
+		t.Error("user pseudonyms should be case-sensitive")
+	}
+}
+
+func TestPseudonym_DependsOnSaltAndKind(t *testing.T) {
+	p1 := NewPseudonymizer([]byte("salt-one"))
+	p2 := NewPseudonymizer([]byte("salt-two"))
+
+	if p1.Pseudonym(KindUser, "bob") == p2.Pseudonym(KindUser, "bob") {
+		t.Error("different salts should produce different pseudonyms")
+	}
+
+	h := p1.Pseudonym(KindHostname, "bob")
+	u := p1.Pseudonym(KindUser, "bob")
+	if strings.TrimPrefix(h, "host-") == strings.TrimPrefix(u, "user-") {
+		t.Error("different kinds should produce different pseudonyms")
+	}
+}
+
+func TestPseudonym_NoSaltMasks(t *testing.T) {
+	for _, p := range []*Pseudonymizer{nil, NewPseudonymizer(nil), NewPseudonymizer([]byte{})} {
+		if p.Stable() {
+			t.Error("pseudonymizer without salt should not be stable")
+		}
+		if got := p.Pseudonym(KindUser, "alice"); got != Mask {
+			t.Errorf("Pseudonym without salt = %q, want %q", got, Mask)
+		}
+	}
+}
+
+func TestPseudonym_Empty(t *testing.T) {
+	p := NewPseudonymizer([]byte("salt"))
+	if got := p.Pseudonym(KindUser, ""); got != "" {
+		t.Errorf("Pseudonym of empty value = %q, want empty", got)
+	}
+}
+
+func TestLoadSalt(t *testing.T) {
+	t.Setenv(SaltEnv, "")
+	t.Setenv(SaltFileEnv, "")
+	if salt, err := LoadSalt(); err != nil || salt != nil {
+		t.Errorf("LoadSalt with nothing set = (%q, %v), want (nil, nil)", salt, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "salt")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(SaltFileEnv, path)
+	if salt, err := LoadSalt(); err != nil || string(salt) != "from-file" {
+		t.Errorf("LoadSalt from file = (%q, %v), want (from-file, nil)", salt, err)
+	}
+
+	// The environment variable takes precedence over the file
+	t.Setenv(SaltEnv, "from-env")
+	if salt, _ := LoadSalt(); string(salt) != "from-env" {
+		t.Errorf("LoadSalt = %q, want from-env", salt)
+	}
+
+	t.Setenv(SaltEnv, "")
+	t.Setenv(SaltFileEnv, filepath.Join(t.TempDir(), "missing"))
+	if _, err := LoadSalt(); err == nil {
+		t.Error("expected error for missing salt file")
+	}
+}
@@ -0,0 +1,284 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// EnableEnv set to true redacts all output, as --redact does
+const EnableEnv = "OMNITRUST_REDACT"
+
+// RulesEnv adds redaction rules to DefaultRules, as a JSON array of rules,
+// e.g. [{"kind":"user","fields":["owner"]},{"kind":"host","pattern":"corp-[a-z0-9]+"}]
+const RulesEnv = "OMNITRUST_REDACT_RULES"
+
+// minValueLength is the length below which collected values are left
+// alone; masking one-letter values would garble unrelated text
+const minValueLength = 2
+
+// Rule selects identifying values of one kind: the string values of the
+// JSON fields named in Fields, and any text matching Pattern. A field is a
+// property name, optionally qualified by its parent's names to narrow it
+// (encrypted_volumes.name matches the name of an encrypted volume, not
+// every name).
+type Rule struct {
+	Kind    Kind     `json:"kind" yaml:"kind"`
+	Fields  []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+	Pattern string   `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+}
+
+// DefaultRules cover the identifying fields of omnitrust's results
+var DefaultRules = []Rule{
+	{Kind: KindHostname, Fields: []string{"hostname"}},
+	{Kind: KindUser, Fields: []string{"user", "username", "owner"}},
+	{Kind: KindSerial, Fields: []string{"serial_number", "machine_id", "fingerprint", "components.value"}},
+	{Kind: KindVolume, Fields: []string{"encrypted_volumes.name", "volume_name"}},
+}
+
+// Redactor masks identifying values in rendered output. Values are found
+// three ways: in the fields its rules name (see Collect), by its rules'
+// patterns, and from Add, which AddLocal uses for this machine's hostname
+// and the current user. Each is replaced with its pseudonym, or Mask
+// without a salt. A Redactor is safe for concurrent use.
+type Redactor struct {
+	p        *Pseudonymizer
+	fields   map[string]Kind
+	patterns []compiledPattern
+
+	mu     sync.Mutex
+	values map[string]Kind
+}
+
+type compiledPattern struct {
+	kind Kind
+	re   *regexp.Regexp
+}
+
+// New builds a redactor from rules, pseudonymizing with p
+func New(p *Pseudonymizer, rules []Rule) (*Redactor, error) {
+	r := &Redactor{p: p, fields: make(map[string]Kind), values: make(map[string]Kind)}
+	for _, rule := range rules {
+		if !slices.Contains([]Kind{KindHostname, KindUser, KindSerial, KindVolume}, rule.Kind) {
+			return nil, fmt.Errorf("redaction rule kind %q must be host, user, serial, or volume", rule.Kind)
+		}
+		if len(rule.Fields) == 0 && rule.Pattern == "" {
+			return nil, fmt.Errorf("redaction rule for %s needs fields or a pattern", rule.Kind)
+		}
+		for _, field := range rule.Fields {
+			r.fields[field] = rule.Kind
+		}
+		if rule.Pattern != "" {
+			re, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("redaction rule for %s: %w", rule.Kind, err)
+			}
+			r.patterns = append(r.patterns, compiledPattern{kind: rule.Kind, re: re})
+		}
+	}
+	return r, nil
+}
+
+// LoadRules returns DefaultRules followed by the rules in RulesEnv
+func LoadRules() ([]Rule, error) {
+	rules := slices.Clone(DefaultRules)
+	if v := os.Getenv(RulesEnv); v != "" {
+		var extra []Rule
+		if err := json.Unmarshal([]byte(v), &extra); err != nil {
+			return nil, fmt.Errorf("%s: %w", RulesEnv, err)
+		}
+		rules = append(rules, extra...)
+	}
+	return rules, nil
+}
+
+// NewFromEnv builds a redactor from the configured salt and rules that
+// also masks this machine's hostname and the current user
+func NewFromEnv() (*Redactor, error) {
+	salt, err := LoadSalt()
+	if err != nil {
+		return nil, err
+	}
+	rules, err := LoadRules()
+	if err != nil {
+		return nil, err
+	}
+	r, err := New(NewPseudonymizer(salt), rules)
+	if err != nil {
+		return nil, err
+	}
+	r.AddLocal()
+	return r, nil
+}
+
+// AddLocal adds this machine's hostname, with and without its domain, and
+// the current user's name
+func (r *Redactor) AddLocal() {
+	if host, err := os.Hostname(); err == nil {
+		r.Add(KindHostname, host)
+		short, _, _ := strings.Cut(host, ".")
+		r.Add(KindHostname, short)
+	}
+	if u, err := user.Current(); err == nil {
+		r.Add(KindUser, u.Username)
+		// Windows reports DOMAIN\user
+		if i := strings.LastIndexByte(u.Username, '\\'); i >= 0 {
+			r.Add(KindUser, u.Username[i+1:])
+		}
+	}
+}
+
+// Add marks value as identifying
+func (r *Redactor) Add(kind Kind, value string) {
+	value = strings.TrimSpace(value)
+	if len(value) < minValueLength || value == Mask {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.values[value]; !ok {
+		r.values[value] = kind
+	}
+}
+
+// Collect adds the values of the fields the rules name, found by encoding
+// data as JSON
+func (r *Redactor) Collect(data any) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return
+	}
+	r.collect(v, nil)
+}
+
+// collect walks a decoded JSON value; path holds the property names
+// leading to it, without array indices
+func (r *Redactor) collect(v any, path []string) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			r.collect(child, append(path, key))
+		}
+	case []any:
+		for _, child := range v {
+			r.collect(child, path)
+		}
+	case string:
+		if kind, ok := r.fieldKind(path); ok {
+			r.Add(kind, v)
+		}
+	}
+}
+
+// fieldKind matches the end of path against the rules' fields
+func (r *Redactor) fieldKind(path []string) (Kind, bool) {
+	for i := range path {
+		if kind, ok := r.fields[strings.Join(path[i:], ".")]; ok {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+// String replaces every identifying value in s. Values are replaced
+// longest first and only where they are not part of a longer word;
+// hostnames match regardless of case.
+func (r *Redactor) String(s string) string {
+	r.mu.Lock()
+	values := make([]string, 0, len(r.values))
+	for value := range r.values {
+		values = append(values, value)
+	}
+	kinds := make(map[string]Kind, len(r.values))
+	for value, kind := range r.values {
+		kinds[value] = kind
+	}
+	r.mu.Unlock()
+
+	slices.SortFunc(values, func(a, b string) int { return len(b) - len(a) })
+	for _, value := range values {
+		kind := kinds[value]
+		pseudonym := r.p.Pseudonym(kind, value)
+		s = replaceWord(s, value, pseudonym, kind == KindHostname)
+		// JSON output escapes backslashes and quotes (DOMAIN\\user)
+		if quoted, _ := json.Marshal(value); string(quoted[1:len(quoted)-1]) != value {
+			s = replaceWord(s, string(quoted[1:len(quoted)-1]), pseudonym, kind == KindHostname)
+		}
+	}
+	for _, p := range r.patterns {
+		s = p.re.ReplaceAllStringFunc(s, func(match string) string {
+			return r.p.Pseudonym(p.kind, match)
+		})
+	}
+	return s
+}
+
+// JSON encodes data as JSON with its identifying values replaced
+func (r *Redactor) JSON(data any) (json.RawMessage, error) {
+	r.Collect(data)
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(r.String(string(raw))), nil
+}
+
+// replaceWord replaces the occurrences of old in s that are not preceded
+// or followed by a letter, digit, underscore, or hyphen
+func replaceWord(s, old, replacement string, foldCase bool) string {
+	haystack := s
+	// Lowercasing can change byte offsets, so fold case only when it
+	// does not
+	if lower, lowerOld := strings.ToLower(s), strings.ToLower(old); foldCase && len(lower) == len(s) && len(lowerOld) == len(old) {
+		haystack, old = lower, lowerOld
+	}
+	var b strings.Builder
+	last := 0
+	for i := 0; i <= len(haystack)-len(old); {
+		j := strings.Index(haystack[i:], old)
+		if j < 0 {
+			break
+		}
+		start, end := i+j, i+j+len(old)
+		if isWordByte(s, start-1) && !endsWithANSI(s[:start]) || isWordByte(s, end) {
+			i = start + 1
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(replacement)
+		last, i = end, end
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// ansiSuffix matches an ANSI color sequence at the end of a string, which
+// ends in a letter but does not join the text after it into a word
+var ansiSuffix = regexp.MustCompile(`\x1b\[[0-9;]*m$`)
+
+// endsWithANSI reports whether s ends with an ANSI color sequence
+func endsWithANSI(s string) bool {
+	return strings.HasSuffix(s, "m") && ansiSuffix.MatchString(s)
+}
+
+// isWordByte reports whether the rune at byte offset i continues a word
+func isWordByte(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return false
+	}
+	c := rune(s[i])
+	return c == '_' || c == '-' || c < 0x80 && (unicode.IsLetter(c) || unicode.IsDigit(c)) || c >= 0x80
+}
@@ -0,0 +1,120 @@
+package redact
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type volume struct {
+	Name       string `json:"name"`
+	MountPoint string `json:"mount_point"`
+}
+
+type result struct {
+	Hostname string   `json:"hostname"`
+	User     string   `json:"user"`
+	Name     string   `json:"name"`
+	Volumes  []volume `json:"encrypted_volumes"`
+	Notes    []string `json:"notes"`
+}
+
+func sample() result {
+	return result{
+		Hostname: "Laptop-01",
+		User:     `CORP\alice`,
+		Name:     "Data",
+		Volumes:  []volume{{Name: "Data", MountPoint: "/data"}},
+		Notes:    []string{"laptop-01 volume Data is not encrypted", "alicex is another user", "DataSet stays"},
+	}
+}
+
+func TestRedactor_CollectsRuleFields(t *testing.T) {
+	r, err := New(NewPseudonymizer([]byte("salt")), DefaultRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := r.JSON(sample())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(raw)
+	for _, leak := range []string{"Laptop-01", "laptop-01", `CORP\\alice`, `"Data"`, "volume Data "} {
+		if strings.Contains(out, leak) {
+			t.Errorf("output leaks %q:\n%s", leak, out)
+		}
+	}
+	for _, kept := range []string{"alicex", "DataSet", "/data"} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("output lost %q, which is not an identifying value:\n%s", kept, out)
+		}
+	}
+	var back result
+	if err := json.Unmarshal(raw, &back); err != nil {
+		t.Fatalf("redacted JSON does not parse: %v", err)
+	}
+	if want := r.p.Pseudonym(KindHostname, "laptop-01"); back.Hostname != want {
+		t.Errorf("hostname = %q, want %q", back.Hostname, want)
+	}
+}
+
+func TestRedactor_MasksWithoutSalt(t *testing.T) {
+	r, err := New(NewPseudonymizer(nil), DefaultRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Add(KindUser, "bob")
+	if got := r.String("owner bob, not bobby"); got != "owner "+Mask+", not bobby" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestRedactor_Patterns(t *testing.T) {
+	r, err := New(NewPseudonymizer([]byte("salt")), []Rule{{Kind: KindSerial, Pattern: `SN-[0-9]+`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := r.String("asset SN-1234 and SN-1234")
+	want := "asset " + r.p.Pseudonym(KindSerial, "SN-1234") + " and " + r.p.Pseudonym(KindSerial, "SN-1234")
+	if got != want {
+		t.Errorf("String = %q, want %q", got, want)
+	}
+}
+
+func TestNew_InvalidRules(t *testing.T) {
+	for _, rules := range [][]Rule{
+		{{Kind: "email", Fields: []string{"email"}}},
+		{{Kind: KindUser}},
+		{{Kind: KindUser, Pattern: "("}},
+	} {
+		if _, err := New(nil, rules); err == nil {
+			t.Errorf("New(%+v) should fail", rules)
+		}
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	t.Setenv(RulesEnv, `[{"kind":"user","fields":["owner_name"]}]`)
+	rules, err := LoadRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != len(DefaultRules)+1 || rules[len(rules)-1].Fields[0] != "owner_name" {
+		t.Errorf("LoadRules = %+v", rules)
+	}
+	t.Setenv(RulesEnv, "user=owner")
+	if _, err := LoadRules(); err == nil {
+		t.Error("LoadRules should reject rules that are not JSON")
+	}
+}
+
+func TestRedactor_ColoredText(t *testing.T) {
+	r, err := New(NewPseudonymizer(nil), DefaultRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Add(KindHostname, "laptop-01")
+	if got := r.String("Host: \x1b[32mlaptop-01\x1b[0m"); got != "Host: \x1b[32m"+Mask+"\x1b[0m" {
+		t.Errorf("String = %q", got)
+	}
+}
@@ -0,0 +1,74 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// htmlMarkBase offsets the private SGR codes HTML marks styles with, above
+// every code a terminal defines
+const htmlMarkBase = 200
+
+// DefaultCSS styles the classes of HTML output
+const DefaultCSS = `pre.omnitrust { background: #1e1e1e; color: #d4d4d4; padding: 1em; }
+pre.omnitrust .bold { font-weight: bold; }
+pre.omnitrust .dim { opacity: 0.7; }
+pre.omnitrust .header { font-weight: bold; color: #4ec9b0; }
+pre.omnitrust .success { color: #6a9955; }
+pre.omnitrust .warning { color: #d7ba7d; }
+pre.omnitrust .danger { color: #f44747; }
+pre.omnitrust .info { color: #569cd6; }
+pre.omnitrust .muted { color: #808080; }`
+
+// HTML renders tables as a preformatted block with a span per styled run,
+// classed by style name. Styles are marked with private escape codes while
+// the table is built, so padding still measures the visible text, and
+// Document turns them into spans.
+type HTML struct {
+	// CSS is the style sheet emitted before the table; empty uses
+	// DefaultCSS
+	CSS string
+}
+
+func (HTML) Name() string { return "html" }
+
+func (HTML) Style(style Style, text string) string {
+	return fmt.Sprintf("\033[%dm", htmlMarkBase+int(style)) + text + Reset
+}
+
+// sgrPattern matches an ANSI SGR escape sequence
+var sgrPattern = regexp.MustCompile("\033\\[([0-9;]*)m")
+
+func (r HTML) Document(body string) string {
+	css := r.CSS
+	if css == "" {
+		css = DefaultCSS
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<style>\n" + css + "\n</style>\n")
+	sb.WriteString(`<pre class="omnitrust">`)
+	open := 0
+	last := 0
+	for _, m := range sgrPattern.FindAllStringSubmatchIndex(body, -1) {
+		sb.WriteString(html.EscapeString(body[last:m[0]]))
+		last = m[1]
+		code, _ := strconv.Atoi(body[m[2]:m[3]])
+		switch style := Style(code - htmlMarkBase); {
+		case code == 0:
+			// A reset ends every open style, as it does on a terminal
+			sb.WriteString(strings.Repeat("</span>", open))
+			open = 0
+		case style.String() != "unknown":
+			sb.WriteString(`<span class="` + style.String() + `">`)
+			open++
+		}
+	}
+	sb.WriteString(html.EscapeString(body[last:]))
+	sb.WriteString(strings.Repeat("</span>", open))
+	sb.WriteString("</pre>\n")
+	return sb.String()
+}
@@ -0,0 +1,156 @@
+// Package render turns the styled text that table formatters produce into
+// terminal, plain-text, Markdown, or HTML output. Formatters mark text with
+// a Style (header, success, muted, ...) rather than a color, and the active
+// Renderer decides what the mark looks like, so a new output format is a
+// new Renderer rather than a change to every formatter.
+package render
+
+import "strings"
+
+// ANSI escape codes
+const (
+	Reset     = "\033[0m"
+	Bold      = "\033[1m"
+	Dim       = "\033[2m"
+	Italic    = "\033[3m"
+	Underline = "\033[4m"
+
+	// Foreground colors
+	Black   = "\033[30m"
+	Red     = "\033[31m"
+	Green   = "\033[32m"
+	Yellow  = "\033[33m"
+	Blue    = "\033[34m"
+	Magenta = "\033[35m"
+	Cyan    = "\033[36m"
+	White   = "\033[37m"
+
+	// Bright foreground colors
+	BrightBlack   = "\033[90m"
+	BrightRed     = "\033[91m"
+	BrightGreen   = "\033[92m"
+	BrightYellow  = "\033[93m"
+	BrightBlue    = "\033[94m"
+	BrightMagenta = "\033[95m"
+	BrightCyan    = "\033[96m"
+	BrightWhite   = "\033[97m"
+
+	// Background colors
+	BgBlue  = "\033[44m"
+	BgCyan  = "\033[46m"
+	BgWhite = "\033[47m"
+)
+
+// Style is the meaning of a piece of text, which a Renderer maps to a look
+type Style int
+
+// Styles used by the table formatters
+const (
+	StyleBold Style = iota
+	StyleDim
+	StyleHeader
+	StyleSuccess
+	StyleWarning
+	StyleDanger
+	StyleInfo
+	StyleMuted
+)
+
+// styleNames are the names of the styles, as used in CSS classes
+var styleNames = [...]string{"bold", "dim", "header", "success", "warning", "danger", "info", "muted"}
+
+// Styles returns every style
+func Styles() []Style {
+	styles := make([]Style, len(styleNames))
+	for i := range styles {
+		styles[i] = Style(i)
+	}
+	return styles
+}
+
+func (s Style) String() string {
+	if s < 0 || int(s) >= len(styleNames) {
+		return "unknown"
+	}
+	return styleNames[s]
+}
+
+// ParseStyle looks up a style by name
+func ParseStyle(name string) (Style, bool) {
+	for i, n := range styleNames {
+		if strings.EqualFold(n, name) {
+			return Style(i), true
+		}
+	}
+	return 0, false
+}
+
+// Renderer decides how styled text looks. Style must not change the visible
+// width of text apart from ANSI escape codes, which VisibleLen ignores, so
+// that tables line up whatever the renderer.
+type Renderer interface {
+	// Name identifies the renderer, e.g. "ansi" or "html"
+	Name() string
+	// Style marks text with a style
+	Style(style Style, text string) string
+	// Document turns a complete rendered table into the final output
+	Document(body string) string
+}
+
+// Theme maps each style to the ANSI escape codes a terminal shows it with.
+// A style missing from the theme is left unstyled.
+type Theme map[Style]string
+
+// DefaultTheme is the theme of ANSI renderers that do not set one
+var DefaultTheme = Theme{
+	StyleBold:    Bold,
+	StyleDim:     Dim,
+	StyleHeader:  Bold + Cyan,
+	StyleSuccess: Green,
+	StyleWarning: Yellow,
+	StyleDanger:  Red,
+	StyleInfo:    Blue,
+	StyleMuted:   BrightBlack,
+}
+
+// ANSI renders styles as terminal escape codes
+type ANSI struct {
+	Theme Theme
+}
+
+func (ANSI) Name() string { return "ansi" }
+
+func (r ANSI) Style(style Style, text string) string {
+	theme := r.Theme
+	if theme == nil {
+		theme = DefaultTheme
+	}
+	code := theme[style]
+	if code == "" {
+		return text
+	}
+	return code + text + Reset
+}
+
+func (ANSI) Document(body string) string { return body }
+
+// Plain drops every style, for terminals without color and for files
+type Plain struct{}
+
+func (Plain) Name() string { return "plain" }
+
+func (Plain) Style(_ Style, text string) string { return text }
+
+func (Plain) Document(body string) string { return body }
+
+// Markdown renders tables as a fenced code block, since their box drawing
+// and alignment do not survive Markdown's own table syntax
+type Markdown struct{}
+
+func (Markdown) Name() string { return "markdown" }
+
+func (Markdown) Style(_ Style, text string) string { return text }
+
+func (Markdown) Document(body string) string {
+	return "```text\n" + strings.Trim(body, "\n") + "\n```\n"
+}
@@ -0,0 +1,180 @@
+package render
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestANSI_Style(t *testing.T) {
+	if got := (ANSI{}).Style(StyleDanger, "error"); got != Red+"error"+Reset {
+		t.Errorf("default theme = %q, want red", got)
+	}
+	theme := Theme{StyleDanger: BrightRed}
+	if got := (ANSI{Theme: theme}).Style(StyleDanger, "error"); got != BrightRed+"error"+Reset {
+		t.Errorf("custom theme = %q, want bright red", got)
+	}
+	if got := (ANSI{Theme: theme}).Style(StyleMuted, "note"); got != "note" {
+		t.Errorf("style missing from theme = %q, want plain text", got)
+	}
+}
+
+func TestPlainAndMarkdown(t *testing.T) {
+	if got := (Plain{}).Style(StyleHeader, "Title"); got != "Title" {
+		t.Errorf("Plain.Style = %q, want plain text", got)
+	}
+	got := (Markdown{}).Document("\nTitle\n" + (Markdown{}).Style(StyleSuccess, "ok") + "\n")
+	if want := "```text\nTitle\nok\n```\n"; got != want {
+		t.Errorf("Markdown.Document = %q, want %q", got, want)
+	}
+}
+
+func TestHTML_Document(t *testing.T) {
+	r := HTML{CSS: "pre {}"}
+	body := r.Style(StyleHeader, "<Status>") + " " + r.Style(StyleSuccess, r.Style(StyleBold, "A&B")) + " done"
+	got := r.Document(body)
+
+	want := "<style>\npre {}\n</style>\n" +
+		`<pre class="omnitrust"><span class="header">&lt;Status&gt;</span> ` +
+		`<span class="success"><span class="bold">A&amp;B</span></span> done</pre>` + "\n"
+	if got != want {
+		t.Errorf("HTML.Document =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHTML_PaddingIgnoresMarks(t *testing.T) {
+	r := HTML{}
+	cell := PadRight(r.Style(StyleInfo, "abc"), 6)
+	if VisibleLen(cell) != 6 {
+		t.Errorf("VisibleLen(%q) = %d, want 6", cell, VisibleLen(cell))
+	}
+	if doc := r.Document(cell); !strings.Contains(doc, `<span class="info">abc</span>   `) {
+		t.Errorf("Document = %q, want the padding after the span", doc)
+	}
+}
+
+func TestTable(t *testing.T) {
+	r := Plain{}
+	if got := TableTop(r, 2, 3); got != "┌────┬─────┐" {
+		t.Errorf("TableTop = %q", got)
+	}
+	if got := TableRow(r, "ab", "cde"); got != "│ ab │ cde │" {
+		t.Errorf("TableRow = %q", got)
+	}
+	if got := TableBottom(r, 2, 3); got != "└────┴─────┘" {
+		t.Errorf("TableBottom = %q", got)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"Macintosh HD", 12, "Macintosh HD"},
+		{"Macintosh HD - Data", 12, "Macintosh..."},
+		{"日本語のボリューム", 10, "日本語..."},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.in, tt.width); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	if got := ProgressBar(Plain{}, 50, 4, StyleSuccess); got != "██░░" {
+		t.Errorf("ProgressBar(50%%) = %q, want half filled", got)
+	}
+	if got := ProgressBar(Plain{}, 150, 4, StyleDanger); got != "████" {
+		t.Errorf("ProgressBar(150%%) = %q, want full", got)
+	}
+}
+
+func TestUsageStyle(t *testing.T) {
+	tests := []struct {
+		percent float64
+		want    Style
+	}{
+		{10, StyleSuccess},
+		{70, StyleWarning},
+		{95, StyleDanger},
+	}
+	for _, tt := range tests {
+		if got := UsageStyle(tt.percent); got != tt.want {
+			t.Errorf("UsageStyle(%v) = %v, want %v", tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	for _, style := range Styles() {
+		if got, ok := ParseStyle(strings.ToUpper(style.String())); !ok || got != style {
+			t.Errorf("ParseStyle(%q) = %v, %v", style, got, ok)
+		}
+	}
+	if _, ok := ParseStyle("sparkly"); ok {
+		t.Error("ParseStyle should reject an unknown style")
+	}
+}
+
+func TestThemes(t *testing.T) {
+	want := []string{ThemeDark, ThemeDefault, ThemeHighContrast, ThemeLight, ThemeMonochrome}
+	if got := ThemeNames(); !slices.Equal(got, want) {
+		t.Errorf("ThemeNames = %v, want %v", got, want)
+	}
+	mono, ok := LookupTheme("Monochrome")
+	if !ok {
+		t.Fatal("LookupTheme should ignore case")
+	}
+	for style, code := range mono {
+		for _, color := range []string{Red, Green, Yellow, Blue, Cyan, BrightBlack} {
+			if strings.Contains(code, color) {
+				t.Errorf("monochrome %s uses a color: %q", style, code)
+			}
+		}
+	}
+	if dark, _ := LookupTheme(ThemeDark); dark[StyleMuted] == BrightBlack {
+		t.Error("the dark theme should not draw muted text in bright black")
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"red", Red},
+		{"bold bright-cyan", Bold + BrightCyan},
+		{"Bold+Underline", Bold + Underline},
+		{"245", "\033[38;5;245m"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got, err := ParseColor(tt.spec); err != nil || got != tt.want {
+			t.Errorf("ParseColor(%q) = %q, %v; want %q", tt.spec, got, err, tt.want)
+		}
+	}
+	for _, spec := range []string{"mauve", "256", "bold -1"} {
+		if _, err := ParseColor(spec); err == nil {
+			t.Errorf("ParseColor(%q) should fail", spec)
+		}
+	}
+}
+
+func TestNewTheme(t *testing.T) {
+	theme, err := NewTheme(DefaultTheme, map[string]string{"muted": "white", "info": ""})
+	if err != nil {
+		t.Fatalf("NewTheme: %v", err)
+	}
+	if theme[StyleMuted] != White || theme[StyleInfo] != "" || theme[StyleDanger] != Red {
+		t.Errorf("NewTheme = %q", theme)
+	}
+	if DefaultTheme[StyleMuted] != BrightBlack {
+		t.Error("NewTheme must not modify its base")
+	}
+	if _, err := NewTheme(DefaultTheme, map[string]string{"sparkle": "red"}); err == nil {
+		t.Error("NewTheme should reject an unknown style")
+	}
+}
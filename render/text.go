@@ -0,0 +1,121 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Bar characters of progress bars
+const (
+	barFilled = "█"
+	barEmpty  = "░"
+)
+
+// StripANSI removes ANSI escape codes from a string
+func StripANSI(s string) string {
+	var result strings.Builder
+	inEscape := false
+	for _, r := range s {
+		if r == '\033' {
+			inEscape = true
+			continue
+		}
+		if inEscape {
+			if r == 'm' {
+				inEscape = false
+			}
+			continue
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
+
+// VisibleLen calculates the visible display width of a string
+// (excluding ANSI codes and accounting for wide characters like emojis)
+func VisibleLen(s string) int {
+	return runewidth.StringWidth(StripANSI(s))
+}
+
+// PadRight pads a string to the right to reach the specified width
+func PadRight(s string, width int) string {
+	visLen := VisibleLen(s)
+	if visLen >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-visLen)
+}
+
+// Truncate shortens plain text to at most width columns, ending it with
+// "..." where it is cut, so it fits a table column
+func Truncate(s string, width int) string {
+	if runewidth.StringWidth(s) <= width {
+		return s
+	}
+	return runewidth.Truncate(s, width, "...")
+}
+
+// PadLeft pads a string to the left to reach the specified width
+func PadLeft(s string, width int) string {
+	visLen := VisibleLen(s)
+	if visLen >= width {
+		return s
+	}
+	return strings.Repeat(" ", width-visLen) + s
+}
+
+// TableRow creates a table row with muted box-drawing borders
+func TableRow(r Renderer, cols ...string) string {
+	bar := r.Style(StyleMuted, "│")
+	return bar + " " + strings.Join(cols, " "+bar+" ") + " " + bar
+}
+
+// TableTop creates a top border for tables
+func TableTop(r Renderer, widths ...int) string {
+	return tableRule(r, "┌─", "─┬─", "─┐", widths)
+}
+
+// TableSeparator creates a separator line for tables
+func TableSeparator(r Renderer, widths ...int) string {
+	return tableRule(r, "├─", "─┼─", "─┤", widths)
+}
+
+// TableBottom creates a bottom border for tables
+func TableBottom(r Renderer, widths ...int) string {
+	return tableRule(r, "└─", "─┴─", "─┘", widths)
+}
+
+// tableRule draws a horizontal table border
+func tableRule(r Renderer, left, join, right string, widths []int) string {
+	parts := make([]string, len(widths))
+	for i, w := range widths {
+		parts[i] = strings.Repeat("─", w)
+	}
+	return r.Style(StyleMuted, left+strings.Join(parts, join)+right)
+}
+
+// ProgressBar draws a bar width characters wide, percent filled in style
+func ProgressBar(r Renderer, percent float64, width int, style Style) string {
+	filled := int(percent / 100 * float64(width))
+	if filled > width {
+		filled = width
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	return r.Style(style, strings.Repeat(barFilled, filled)) +
+		r.Style(StyleMuted, strings.Repeat(barEmpty, width-filled))
+}
+
+// UsageStyle styles a usage percentage: danger from 90%, warning from 70%
+func UsageStyle(percent float64) Style {
+	switch {
+	case percent >= 90:
+		return StyleDanger
+	case percent >= 70:
+		return StyleWarning
+	default:
+		return StyleSuccess
+	}
+}
@@ -0,0 +1,130 @@
+package render
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Built-in theme names
+const (
+	ThemeDefault      = "default"
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+	ThemeMonochrome   = "monochrome"
+)
+
+// themes are the built-in themes
+var themes = map[string]Theme{
+	ThemeDefault: DefaultTheme,
+	// Dark backgrounds: bright colors, and white rather than bright black
+	// for muted text, which some palettes draw in the background color
+	ThemeDark: {
+		StyleBold:    Bold,
+		StyleDim:     Dim,
+		StyleHeader:  Bold + BrightCyan,
+		StyleSuccess: BrightGreen,
+		StyleWarning: BrightYellow,
+		StyleDanger:  BrightRed,
+		StyleInfo:    BrightBlue,
+		StyleMuted:   White,
+	},
+	// Light backgrounds: yellow washes out on white, so warnings are magenta
+	ThemeLight: {
+		StyleBold:    Bold,
+		StyleDim:     Dim,
+		StyleHeader:  Bold + Blue,
+		StyleSuccess: Green,
+		StyleWarning: Magenta,
+		StyleDanger:  Red,
+		StyleInfo:    Blue,
+		StyleMuted:   Dim,
+	},
+	ThemeHighContrast: {
+		StyleBold:    Bold,
+		StyleDim:     White,
+		StyleHeader:  Bold + Underline + BrightWhite,
+		StyleSuccess: Bold + BrightGreen,
+		StyleWarning: Bold + BrightYellow,
+		StyleDanger:  Bold + BrightRed,
+		StyleInfo:    Bold + BrightCyan,
+		StyleMuted:   White,
+	},
+	// No colors at all: severity is told apart by weight and underline
+	ThemeMonochrome: {
+		StyleBold:    Bold,
+		StyleDim:     Dim,
+		StyleHeader:  Bold,
+		StyleWarning: Underline,
+		StyleDanger:  Bold + Underline,
+		StyleMuted:   Dim,
+	},
+}
+
+// ThemeNames returns the names of the built-in themes
+func ThemeNames() []string {
+	return slices.Sorted(maps.Keys(themes))
+}
+
+// LookupTheme returns a built-in theme by name
+func LookupTheme(name string) (Theme, bool) {
+	t, ok := themes[strings.ToLower(name)]
+	return t, ok
+}
+
+// NewTheme copies base and overrides the styles in styles, which maps a
+// style name to a color spec (see ParseColor). An empty spec leaves the
+// style unstyled.
+func NewTheme(base Theme, styles map[string]string) (Theme, error) {
+	t := maps.Clone(base)
+	if t == nil {
+		t = Theme{}
+	}
+	for name, spec := range styles {
+		style, ok := ParseStyle(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown style %q (use %s)", name, strings.Join(styleNames[:], ", "))
+		}
+		code, err := ParseColor(spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		t[style] = code
+	}
+	return t, nil
+}
+
+// colorWords are the words of a color spec
+var colorWords = map[string]string{
+	"bold": Bold, "dim": Dim, "italic": Italic, "underline": Underline,
+	"black": Black, "red": Red, "green": Green, "yellow": Yellow,
+	"blue": Blue, "magenta": Magenta, "cyan": Cyan, "white": White,
+	"bright-black": BrightBlack, "gray": BrightBlack, "grey": BrightBlack,
+	"bright-red": BrightRed, "bright-green": BrightGreen, "bright-yellow": BrightYellow,
+	"bright-blue": BrightBlue, "bright-magenta": BrightMagenta, "bright-cyan": BrightCyan,
+	"bright-white": BrightWhite, "bg-blue": BgBlue, "bg-cyan": BgCyan, "bg-white": BgWhite,
+}
+
+// ParseColor turns a color spec into ANSI escape codes. A spec is a list of
+// words separated by spaces or "+": attributes (bold, dim, italic,
+// underline), colors (red, bright-black, bg-blue, ...), or a number from 0
+// to 255 for a color of the 256-color palette, e.g. "bold bright-cyan" or
+// "245".
+func ParseColor(spec string) (string, error) {
+	var code strings.Builder
+	for _, word := range strings.FieldsFunc(strings.ToLower(spec), func(r rune) bool { return r == ' ' || r == '+' }) {
+		if c, ok := colorWords[word]; ok {
+			code.WriteString(c)
+			continue
+		}
+		if n, err := strconv.Atoi(word); err == nil && n >= 0 && n <= 255 {
+			code.WriteString("\033[38;5;" + word + "m")
+			continue
+		}
+		return "", fmt.Errorf("unknown color %q", word)
+	}
+	return code.String(), nil
+}
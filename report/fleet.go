@@ -0,0 +1,271 @@
+package report
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+// ScoreStatuses lists the overall statuses in the score distribution, best
+// first
+var ScoreStatuses = []string{"excellent", "good", "fair", "needs_improvement", "critical"}
+
+// FleetHost is one machine in a fleet report
+type FleetHost struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	// MachineID and ReportID come from the report envelope, when the
+	// bundle has one
+	MachineID string `json:"machine_id,omitempty"`
+	ReportID  string `json:"report_id,omitempty"`
+	Platform  string `json:"platform"`
+	Score     int    `json:"score"`
+	Status    string `json:"status"`
+	// Failed lists the checks that ran and did not pass
+	Failed []string `json:"failed,omitempty"`
+	// MandatoryFailures lists the mandatory checks that did not pass
+	MandatoryFailures []string `json:"mandatory_failures,omitempty"`
+	Findings          int      `json:"findings"`
+}
+
+// ScoreDistribution describes the spread of overall scores across a fleet.
+// Hosts with nothing to score are left out; when no host was scored, the
+// statistics are inspector.NoScore.
+type ScoreDistribution struct {
+	Average int `json:"average"`
+	Median  int `json:"median"`
+	Min     int `json:"min"`
+	Max     int `json:"max"`
+	// ByStatus counts the hosts with each overall status (see ScoreStatuses)
+	ByStatus map[string]int `json:"by_status"`
+}
+
+// FleetFinding is a finding and the hosts it was reported on
+type FleetFinding struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Severity string `json:"severity"`
+	Check    string `json:"check"`
+	// Prevalence is the percentage of hosts reporting the finding
+	Prevalence int      `json:"prevalence"`
+	Hosts      []string `json:"hosts"`
+}
+
+// NonCompliantCheck lists the hosts on which a check did not pass
+type NonCompliantCheck struct {
+	Check string   `json:"check"`
+	Hosts []string `json:"hosts"`
+}
+
+// FleetReport aggregates the security summaries of many hosts
+type FleetReport struct {
+	SchemaVersion inspector.ResultVersion `json:"schema_version"`
+
+	GeneratedAt time.Time `json:"generated_at"`
+	// Hosts are ordered from the lowest score up
+	Hosts  []FleetHost       `json:"hosts"`
+	Scores ScoreDistribution `json:"scores"`
+	// Findings are ordered by prevalence, then severity
+	Findings []FleetFinding `json:"findings"`
+	// Waived are the findings hosts accepted with a waiver, ordered like
+	// Findings
+	Waived []FleetFinding `json:"waived,omitempty"`
+	// NonCompliant lists, per failing check, the hosts that failed it
+	NonCompliant []NonCompliantCheck `json:"non_compliant"`
+	// MandatoryFailures lists the hosts that failed a mandatory check
+	MandatoryFailures []string `json:"mandatory_failures,omitempty"`
+	// Superseded counts the reports dropped because a newer report from
+	// the same machine ID was given
+	Superseded int `json:"superseded,omitempty"`
+}
+
+// fleetBundle is a loaded bundle and where it came from
+type fleetBundle struct {
+	path    string
+	summary *inspector.SecuritySummary
+	env     *inspector.Envelope
+}
+
+// Fleet loads every bundle and aggregates them into a fleet report. When
+// several enveloped reports come from the same machine, only the newest is
+// counted.
+func Fleet(paths []string) (*FleetReport, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no bundles given")
+	}
+
+	var bundles []fleetBundle
+	latest := make(map[string]int)
+	superseded := 0
+	for _, path := range paths {
+		summary, env, err := loadBundle(path)
+		if err != nil {
+			return nil, err
+		}
+		b := fleetBundle{path: path, summary: summary, env: env}
+		if env == nil || env.MachineID == "" {
+			bundles = append(bundles, b)
+			continue
+		}
+		if i, ok := latest[env.MachineID]; ok {
+			superseded++
+			if env.Timestamp.After(bundles[i].env.Timestamp) {
+				bundles[i] = b
+			}
+			continue
+		}
+		latest[env.MachineID] = len(bundles)
+		bundles = append(bundles, b)
+	}
+
+	report := &FleetReport{
+		GeneratedAt:  time.Now().UTC(),
+		Findings:     []FleetFinding{},
+		NonCompliant: []NonCompliantCheck{},
+		Superseded:   superseded,
+	}
+	findings := make(map[string]*FleetFinding)
+	waived := make(map[string]*FleetFinding)
+	failing := make(map[string][]string)
+	for _, b := range bundles {
+		host := fleetHost(b)
+		report.Hosts = append(report.Hosts, host)
+		if len(host.MandatoryFailures) > 0 {
+			report.MandatoryFailures = append(report.MandatoryFailures, host.Name)
+		}
+		for _, check := range host.Failed {
+			failing[check] = append(failing[check], host.Name)
+		}
+		for _, f := range b.summary.Findings {
+			addFleetFinding(findings, f, host.Name)
+		}
+		for _, w := range b.summary.Waived {
+			addFleetFinding(waived, w.Finding, host.Name)
+		}
+	}
+	report.Findings = sortedFleetFindings(findings, len(report.Hosts))
+	if len(waived) > 0 {
+		report.Waived = sortedFleetFindings(waived, len(report.Hosts))
+	}
+
+	for check, hosts := range failing {
+		report.NonCompliant = append(report.NonCompliant, NonCompliantCheck{Check: check, Hosts: hosts})
+	}
+	slices.SortFunc(report.NonCompliant, func(a, b NonCompliantCheck) int {
+		return cmp.Or(len(b.Hosts)-len(a.Hosts), cmp.Compare(a.Check, b.Check))
+	})
+
+	slices.SortStableFunc(report.Hosts, func(a, b FleetHost) int {
+		return cmp.Or(a.Score-b.Score, cmp.Compare(a.Name, b.Name))
+	})
+	report.Scores = scoreDistribution(report.Hosts)
+	return report, nil
+}
+
+// addFleetFinding counts a finding reported on a host
+func addFleetFinding(findings map[string]*FleetFinding, f inspector.Finding, host string) {
+	ff, ok := findings[f.ID]
+	if !ok {
+		ff = &FleetFinding{ID: f.ID, Title: f.Title, Severity: f.Severity, Check: f.Check}
+		findings[f.ID] = ff
+	}
+	if !slices.Contains(ff.Hosts, host) {
+		ff.Hosts = append(ff.Hosts, host)
+	}
+}
+
+// sortedFleetFindings orders findings by prevalence among hosts, then
+// severity
+func sortedFleetFindings(findings map[string]*FleetFinding, hosts int) []FleetFinding {
+	sorted := make([]FleetFinding, 0, len(findings))
+	for _, ff := range findings {
+		ff.Prevalence = len(ff.Hosts) * 100 / hosts
+		sorted = append(sorted, *ff)
+	}
+	slices.SortFunc(sorted, func(a, b FleetFinding) int {
+		return cmp.Or(
+			len(b.Hosts)-len(a.Hosts),
+			inspector.SeverityRank(a.Severity)-inspector.SeverityRank(b.Severity),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+	return sorted
+}
+
+// fleetHost builds a fleet report row from a bundle
+func fleetHost(b fleetBundle) FleetHost {
+	row := hostFromSummary(b.summary, b.path)
+	host := FleetHost{
+		Name:              row.Name,
+		Source:            row.Source,
+		Platform:          row.Platform,
+		Score:             row.Score,
+		Status:            row.Status,
+		MandatoryFailures: b.summary.MandatoryFailures,
+		Findings:          len(b.summary.Findings),
+	}
+	if host.Status == "" {
+		host.Status = overallStatus(host.Score)
+	}
+	if b.env != nil {
+		host.MachineID = b.env.MachineID
+		host.ReportID = b.env.ReportID
+		if b.summary.Hostname == "" && b.env.Hostname != "" {
+			host.Name = b.env.Hostname
+		}
+	}
+	for check, result := range b.summary.CheckResults {
+		if result == inspector.CheckResultFail {
+			host.Failed = append(host.Failed, check)
+		}
+	}
+	slices.Sort(host.Failed)
+	return host
+}
+
+// scoreDistribution summarizes the hosts' scores
+func scoreDistribution(hosts []FleetHost) ScoreDistribution {
+	d := ScoreDistribution{ByStatus: make(map[string]int, len(ScoreStatuses))}
+	for _, status := range ScoreStatuses {
+		d.ByStatus[status] = 0
+	}
+	scores := make([]int, 0, len(hosts))
+	total := 0
+	for _, h := range hosts {
+		d.ByStatus[h.Status]++
+		if h.Score != inspector.NoScore {
+			scores = append(scores, h.Score)
+			total += h.Score
+		}
+	}
+	if len(scores) == 0 {
+		d.Average, d.Median, d.Min, d.Max = inspector.NoScore, inspector.NoScore, inspector.NoScore, inspector.NoScore
+		return d
+	}
+	slices.Sort(scores)
+	d.Min, d.Max = scores[0], scores[len(scores)-1]
+	d.Average = total / len(scores)
+	d.Median = scores[len(scores)/2]
+	if len(scores)%2 == 0 {
+		d.Median = (scores[len(scores)/2-1] + scores[len(scores)/2]) / 2
+	}
+	return d
+}
+
+// overallStatus mirrors the summary's status for bundles that lack one
+func overallStatus(score int) string {
+	switch {
+	case score >= 100:
+		return "excellent"
+	case score >= 75:
+		return "good"
+	case score >= 50:
+		return "fair"
+	case score >= 25:
+		return "needs_improvement"
+	}
+	return "critical"
+}
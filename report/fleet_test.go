@@ -0,0 +1,185 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+func fleetBundles(t *testing.T) []string {
+	dir := t.TempDir()
+	noEncryption := inspector.Finding{ID: "encryption_disabled", Title: "Disk encryption is disabled", Severity: inspector.SeverityHigh, Check: inspector.CheckEncryption}
+	noSecureBoot := inspector.Finding{ID: "secure_boot_disabled", Title: "Secure Boot is disabled", Severity: inspector.SeverityCritical, Check: inspector.CheckSecureBoot}
+	return []string{
+		writeBundle(t, dir, "mac.json", &inspector.SecuritySummary{
+			Hostname:      "mac-01",
+			Platform:      "darwin",
+			OverallScore:  100,
+			OverallStatus: "excellent",
+			CheckResults:  map[string]string{inspector.CheckEncryption: inspector.CheckResultPass},
+			Waived: []inspector.WaivedFinding{{
+				Finding: inspector.Finding{ID: "biometrics_not_configured", Title: "Biometric authentication is not configured", Severity: inspector.SeverityLow, Check: inspector.CheckBiometrics},
+				Waiver:  inspector.Waiver{Finding: "biometrics_not_configured", Reason: "shared kiosk", Until: time.Now().Add(time.Hour)},
+			}},
+		}),
+		writeBundle(t, dir, "web.json", &inspector.SecuritySummary{
+			Hostname:      "web-01",
+			Platform:      "linux",
+			OverallScore:  50,
+			OverallStatus: "fair",
+			CheckResults:  map[string]string{inspector.CheckEncryption: inspector.CheckResultFail, inspector.CheckSecureBoot: inspector.CheckResultPass},
+			Findings:      []inspector.Finding{noEncryption},
+		}),
+		writeBundle(t, dir, "build.json", &inspector.SecuritySummary{
+			Hostname:          "build-01",
+			Platform:          "linux",
+			OverallScore:      20,
+			OverallStatus:     "critical",
+			CheckResults:      map[string]string{inspector.CheckEncryption: inspector.CheckResultFail, inspector.CheckSecureBoot: inspector.CheckResultFail},
+			Findings:          []inspector.Finding{noSecureBoot, noEncryption},
+			MandatoryFailures: []string{inspector.CheckSecureBoot},
+		}),
+	}
+}
+
+func TestFleet(t *testing.T) {
+	r, err := Fleet(fleetBundles(t))
+	if err != nil {
+		t.Fatalf("Fleet failed: %v", err)
+	}
+
+	var names []string
+	for _, h := range r.Hosts {
+		names = append(names, h.Name)
+	}
+	if !slices.Equal(names, []string{"build-01", "web-01", "mac-01"}) {
+		t.Errorf("hosts = %v, want lowest score first", names)
+	}
+	if got := r.Hosts[0].Failed; !slices.Equal(got, []string{inspector.CheckEncryption, inspector.CheckSecureBoot}) {
+		t.Errorf("build-01 failed = %v", got)
+	}
+
+	s := r.Scores
+	if s.Average != 56 || s.Median != 50 || s.Min != 20 || s.Max != 100 {
+		t.Errorf("scores = %+v", s)
+	}
+	if s.ByStatus["excellent"] != 1 || s.ByStatus["fair"] != 1 || s.ByStatus["critical"] != 1 || s.ByStatus["good"] != 0 {
+		t.Errorf("by status = %v", s.ByStatus)
+	}
+
+	// The more widespread finding comes first even though it is less severe
+	if len(r.Findings) != 2 || r.Findings[0].ID != "encryption_disabled" || r.Findings[0].Prevalence != 66 ||
+		!slices.Equal(r.Findings[0].Hosts, []string{"web-01", "build-01"}) {
+		t.Errorf("findings = %+v", r.Findings)
+	}
+	if len(r.Waived) != 1 || r.Waived[0].ID != "biometrics_not_configured" || !slices.Equal(r.Waived[0].Hosts, []string{"mac-01"}) {
+		t.Errorf("waived = %+v", r.Waived)
+	}
+
+	if len(r.NonCompliant) != 2 || r.NonCompliant[0].Check != inspector.CheckEncryption || len(r.NonCompliant[0].Hosts) != 2 ||
+		r.NonCompliant[1].Check != inspector.CheckSecureBoot {
+		t.Errorf("non-compliant = %+v", r.NonCompliant)
+	}
+	if !slices.Equal(r.MandatoryFailures, []string{"build-01"}) {
+		t.Errorf("mandatory failures = %v", r.MandatoryFailures)
+	}
+}
+
+func TestFleet_Superseded(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, score int, at time.Time) string {
+		env := inspector.NewEnvelope(&inspector.SecuritySummary{Hostname: "web-01", Platform: "linux", OverallScore: score}, time.Now())
+		env.MachineID = "0f3c9a5e2b7d4e61a8c1d2e3f4a5b6c7"
+		env.Timestamp = at
+		data, err := json.Marshal(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	now := time.Now().UTC()
+	paths := []string{write("old.json", 25, now.Add(-24*time.Hour)), write("new.json", 75, now), write("older.json", 0, now.Add(-48*time.Hour))}
+
+	r, err := Fleet(paths)
+	if err != nil {
+		t.Fatalf("Fleet failed: %v", err)
+	}
+	if len(r.Hosts) != 1 || r.Hosts[0].Score != 75 || r.Superseded != 2 {
+		t.Errorf("hosts = %+v, superseded = %d; want only the newest report", r.Hosts, r.Superseded)
+	}
+}
+
+func TestFleet_NoScore(t *testing.T) {
+	dir := t.TempDir()
+	paths := []string{
+		writeBundle(t, dir, "web.json", &inspector.SecuritySummary{Hostname: "web-01", Platform: "linux", OverallScore: 60, OverallStatus: "fair"}),
+		writeBundle(t, dir, "ci.json", &inspector.SecuritySummary{Hostname: "ci-01", Platform: "linux", OverallScore: inspector.NoScore, OverallStatus: inspector.StatusNotScored}),
+	}
+	r, err := Fleet(paths)
+	if err != nil {
+		t.Fatalf("Fleet failed: %v", err)
+	}
+	// A host with nothing to score does not drag the statistics down
+	if s := r.Scores; s.Average != 60 || s.Min != 60 || s.Max != 60 {
+		t.Errorf("scores = %+v, want only web-01", s)
+	}
+	if table := FormatFleetTable(r); !strings.Contains(table, "N/A") {
+		t.Errorf("table should show N/A for ci-01:\n%s", table)
+	}
+
+	r, err = Fleet(paths[1:])
+	if err != nil {
+		t.Fatalf("Fleet failed: %v", err)
+	}
+	if r.Scores.Average != inspector.NoScore {
+		t.Errorf("average = %d, want NoScore", r.Scores.Average)
+	}
+}
+
+func TestFleet_Errors(t *testing.T) {
+	if _, err := Fleet(nil); err == nil {
+		t.Error("expected error for no bundles")
+	}
+	if _, err := Fleet([]string{filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Error("expected error for missing bundle")
+	}
+}
+
+func TestFormatFleet(t *testing.T) {
+	r, err := Fleet(fleetBundles(t))
+	if err != nil {
+		t.Fatalf("Fleet failed: %v", err)
+	}
+
+	table, _ := FormatFleet(r, "table")
+	plain := inspector.StripANSI(table)
+	for _, want := range []string{"average 56, median 50", "Disk encryption is disabled", "web-01, build-01", "Failing mandatory checks: build-01"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("table output missing %q:\n%s", want, plain)
+		}
+	}
+
+	html, err := FormatFleet(r, "html")
+	if err != nil {
+		t.Fatalf("FormatFleet(html) failed: %v", err)
+	}
+	if !strings.Contains(html, "<td>Secure Boot is disabled</td>") || !strings.Contains(html, `class="fail"`) {
+		t.Errorf("html output missing findings or hosts:\n%s", html)
+	}
+
+	js, _ := FormatFleet(r, "json")
+	var decoded FleetReport
+	if err := json.Unmarshal([]byte(js), &decoded); err != nil || len(decoded.Hosts) != 3 || len(decoded.Findings) != 2 {
+		t.Errorf("json output did not round-trip: %v", err)
+	}
+}
@@ -0,0 +1,379 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+// Column widths for the table view
+const (
+	hostWidth     = 20
+	platformWidth = 9
+	scoreWidth    = 7
+	featureWidth  = 12
+)
+
+// FormatTable renders the merged report as a colored table
+func FormatTable(r *MergedReport) string {
+	return FormatTableWith(inspector.CurrentStyler(), r)
+}
+
+// FormatTableWith is like FormatTable but styles the table with st
+func FormatTableWith(st inspector.Styler, r *MergedReport) string {
+	widths := []int{hostWidth, platformWidth, scoreWidth}
+	for range Features {
+		widths = append(widths, featureWidth)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(inspector.IconShield + " Fleet Security Report"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 60)))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(st.BoldText("Hosts: "))
+	sb.WriteString(fmt.Sprintf("%d", len(r.Hosts)))
+	sb.WriteString(st.BoldText("   Average Score: "))
+	sb.WriteString(inspector.FormatScore(r.AverageScore))
+	sb.WriteString("\n\n")
+
+	header := []string{
+		st.Header(inspector.PadRight("Host", hostWidth)),
+		st.Header(inspector.PadRight("Platform", platformWidth)),
+		st.Header(inspector.PadRight("Score", scoreWidth)),
+	}
+	for _, f := range Features {
+		header = append(header, st.Header(inspector.PadRight(featureTitle(f), featureWidth)))
+	}
+
+	sb.WriteString(st.TableTop(widths...))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(header...))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(widths...))
+	sb.WriteString("\n")
+
+	for _, h := range r.Hosts {
+		name := h.Name
+		if len(name) > hostWidth {
+			name = name[:hostWidth-1] + "…"
+		}
+		row := []string{
+			inspector.PadRight(name, hostWidth),
+			inspector.PadRight(h.Platform, platformWidth),
+			inspector.PadRight(scoreCell(st, h.Score), scoreWidth),
+		}
+		for _, f := range Features {
+			row = append(row, inspector.PadRight(featureCell(st, h.Features[f]), featureWidth))
+		}
+		sb.WriteString(st.TableRowColored(row...))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.TableBottom(widths...))
+	sb.WriteString("\n\n")
+
+	// Coverage summary
+	sb.WriteString(st.BoldText("Coverage:"))
+	sb.WriteString("\n")
+	for _, f := range Features {
+		sb.WriteString(fmt.Sprintf("  %-12s %d/%d hosts\n", featureTitle(f), r.FeatureCoverage[f], len(r.Hosts)))
+	}
+
+	return sb.String()
+}
+
+// scoreText renders a score without the scale, or N/A for a host with
+// nothing to score
+func scoreText(score int) string {
+	if score == inspector.NoScore {
+		return "N/A"
+	}
+	return strconv.Itoa(score)
+}
+
+// scored reports whether a score is not inspector.NoScore
+func scored(score int) bool {
+	return score != inspector.NoScore
+}
+
+// scoreCell colors a score like the summary's score bar
+func scoreCell(st inspector.Styler, score int) string {
+	s := scoreText(score)
+	switch {
+	case score == inspector.NoScore:
+		return st.Muted(s)
+	case score >= 75:
+		return st.Success(s)
+	case score >= 50:
+		return st.Warning(s)
+	}
+	return st.Danger(s)
+}
+
+// featureCell renders a matrix cell
+func featureCell(st inspector.Styler, outcome string) string {
+	switch outcome {
+	case FeaturePass:
+		return st.Success(inspector.IconCheck + " Pass")
+	case FeatureFail:
+		return st.Danger(inspector.IconCross + " Fail")
+	}
+	return st.Muted("N/A")
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"title":  featureTitle,
+	"score":  scoreText,
+	"scored": scored,
+	"cell": func(h Host, feature string) string {
+		return h.Features[feature]
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Fleet Security Report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.8em; text-align: left; }
+th { background: #f4f4f4; }
+.pass { background: #e6f4ea; color: #137333; }
+.fail { background: #fce8e6; color: #a50e0e; }
+.na { color: #888; }
+</style>
+</head>
+<body>
+<h1>Fleet Security Report</h1>
+<p>{{len .Hosts}} hosts, average score {{if scored .AverageScore}}{{.AverageScore}}/100{{else}}N/A{{end}}. Generated {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}.</p>
+<table>
+<tr><th>Host</th><th>Platform</th><th>Score</th><th>Status</th>{{range $f := .Features}}<th>{{title $f}}</th>{{end}}</tr>
+{{- range $h := .Hosts}}
+<tr><td>{{$h.Name}}</td><td>{{$h.Platform}}</td><td>{{score $h.Score}}</td><td>{{$h.Status}}</td>
+{{- range $f := $.Features}}{{$c := cell $h $f}}<td class="{{if eq $c "pass"}}pass{{else if eq $c "fail"}}fail{{else}}na{{end}}">{{$c}}</td>{{end}}</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// FormatHTMLPage renders the merged report as a standalone HTML page
+func FormatHTMLPage(r *MergedReport) (string, error) {
+	var buf bytes.Buffer
+	err := htmlTemplate.Execute(&buf, struct {
+		*MergedReport
+		Features []string
+	}{r, Features})
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Format renders the merged report as json (default), table, or html
+func Format(r *MergedReport, format string) (string, error) {
+	if strings.ToLower(format) == FormatHTML {
+		return FormatHTMLPage(r)
+	}
+	return inspector.RenderOutputWith(r, func(st inspector.Styler) string {
+		return FormatTableWith(st, r)
+	}, format)
+}
+
+// fleetTopFindings is how many findings the fleet table lists
+const fleetTopFindings = 10
+
+// FormatFleetTable renders a fleet report as a colored summary
+func FormatFleetTable(r *FleetReport) string {
+	return FormatFleetTableWith(inspector.CurrentStyler(), r)
+}
+
+// FormatFleetTableWith is like FormatFleetTable but styles the table with st
+func FormatFleetTableWith(st inspector.Styler, r *FleetReport) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(inspector.IconShield + " Fleet Security Audit"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 60)))
+	sb.WriteString("\n\n")
+
+	s := r.Scores
+	sb.WriteString(st.BoldText("Hosts: "))
+	sb.WriteString(fmt.Sprintf("%d", len(r.Hosts)))
+	if r.Superseded > 0 {
+		sb.WriteString(st.Muted(fmt.Sprintf(" (%d older reports superseded)", r.Superseded)))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(st.BoldText("Scores: "))
+	if s.Average == inspector.NoScore {
+		sb.WriteString(st.Muted("no host was scored"))
+	} else {
+		sb.WriteString(fmt.Sprintf("average %d, median %d, min %d, max %d", s.Average, s.Median, s.Min, s.Max))
+	}
+	sb.WriteString("\n\n")
+
+	sb.WriteString(st.BoldText("Score Distribution:"))
+	sb.WriteString("\n")
+	for _, status := range ScoreStatuses {
+		n := s.ByStatus[status]
+		sb.WriteString(fmt.Sprintf("  %-18s %3d %s\n", status, n, st.Muted(strings.Repeat("█", n*30/len(r.Hosts)))))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(st.BoldText("Top Findings:"))
+	sb.WriteString("\n")
+	if len(r.Findings) == 0 {
+		sb.WriteString(st.Muted("  No findings") + "\n")
+	}
+	for i, f := range r.Findings {
+		if i == fleetTopFindings {
+			sb.WriteString(st.Muted(fmt.Sprintf("  … %d more (see -f json)", len(r.Findings)-i)) + "\n")
+			break
+		}
+		sb.WriteString(fmt.Sprintf("  %3d%% %-8s %s %s\n", f.Prevalence, f.Severity, f.Title,
+			st.Muted(fmt.Sprintf("(%d/%d hosts)", len(f.Hosts), len(r.Hosts)))))
+	}
+	sb.WriteString("\n")
+
+	if len(r.Waived) > 0 {
+		sb.WriteString(st.BoldText("Waived Findings:"))
+		sb.WriteString("\n")
+		for _, f := range r.Waived {
+			sb.WriteString(fmt.Sprintf("  %3d%% %-8s %s %s\n", f.Prevalence, f.Severity, f.Title,
+				st.Muted(fmt.Sprintf("(%d/%d hosts)", len(f.Hosts), len(r.Hosts)))))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(st.BoldText("Non-Compliant Hosts:"))
+	sb.WriteString("\n")
+	if len(r.NonCompliant) == 0 {
+		sb.WriteString(st.Success("  "+inspector.IconCheck+" Every host passes every check") + "\n")
+	}
+	for _, c := range r.NonCompliant {
+		sb.WriteString(fmt.Sprintf("  %-20s %s\n", c.Check, strings.Join(c.Hosts, ", ")))
+	}
+	if len(r.MandatoryFailures) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(st.Danger(inspector.IconCross + " Failing mandatory checks: "))
+		sb.WriteString(strings.Join(r.MandatoryFailures, ", "))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	widths := []int{hostWidth, platformWidth, scoreWidth, featureWidth + 6}
+	sb.WriteString(st.TableTop(widths...))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableRowColored(
+		st.Header(inspector.PadRight("Host", hostWidth)),
+		st.Header(inspector.PadRight("Platform", platformWidth)),
+		st.Header(inspector.PadRight("Score", scoreWidth)),
+		st.Header(inspector.PadRight("Failed Checks", featureWidth+6)),
+	))
+	sb.WriteString("\n")
+	sb.WriteString(st.TableSeparator(widths...))
+	sb.WriteString("\n")
+	for _, h := range r.Hosts {
+		name := h.Name
+		if len(name) > hostWidth {
+			name = name[:hostWidth-1] + "…"
+		}
+		failed := st.Success(inspector.IconCheck + " none")
+		if len(h.Failed) > 0 {
+			failed = st.Danger(fmt.Sprintf("%d", len(h.Failed)))
+		}
+		sb.WriteString(st.TableRowColored(
+			inspector.PadRight(name, hostWidth),
+			inspector.PadRight(h.Platform, platformWidth),
+			inspector.PadRight(scoreCell(st, h.Score), scoreWidth),
+			inspector.PadRight(failed, featureWidth+6),
+		))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(st.TableBottom(widths...))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+var fleetHTMLTemplate = template.Must(template.New("fleet").Funcs(template.FuncMap{
+	"join":   strings.Join,
+	"score":  scoreText,
+	"scored": scored,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Fleet Security Audit</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.8em; text-align: left; }
+th { background: #f4f4f4; }
+.pass { background: #e6f4ea; color: #137333; }
+.fail { background: #fce8e6; color: #a50e0e; }
+</style>
+</head>
+<body>
+<h1>Fleet Security Audit</h1>
+<p>{{len .Hosts}} hosts: {{if scored .Scores.Average}}average score {{.Scores.Average}}/100, median {{.Scores.Median}}, min {{.Scores.Min}}, max {{.Scores.Max}}{{else}}no host was scored{{end}}. Generated {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}.</p>
+<h2>Score Distribution</h2>
+<table>
+<tr><th>Status</th><th>Hosts</th></tr>
+{{- range $s := .Statuses}}
+<tr><td>{{$s}}</td><td>{{index $.Scores.ByStatus $s}}</td></tr>
+{{- end}}
+</table>
+<h2>Findings by Prevalence</h2>
+<table>
+<tr><th>Finding</th><th>Severity</th><th>Prevalence</th><th>Hosts</th></tr>
+{{- range .Findings}}
+<tr><td>{{.Title}}</td><td>{{.Severity}}</td><td>{{.Prevalence}}%</td><td>{{join .Hosts ", "}}</td></tr>
+{{- end}}
+</table>
+<h2>Non-Compliant Hosts</h2>
+<table>
+<tr><th>Check</th><th>Hosts</th></tr>
+{{- range .NonCompliant}}
+<tr><td>{{.Check}}</td><td class="fail">{{join .Hosts ", "}}</td></tr>
+{{- end}}
+</table>
+<h2>Hosts</h2>
+<table>
+<tr><th>Host</th><th>Platform</th><th>Score</th><th>Status</th><th>Failed Checks</th></tr>
+{{- range .Hosts}}
+<tr><td>{{.Name}}</td><td>{{.Platform}}</td><td>{{score .Score}}</td><td>{{.Status}}</td><td class="{{if .Failed}}fail{{else}}pass{{end}}">{{join .Failed ", "}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// FormatFleetHTMLPage renders a fleet report as a standalone HTML page
+func FormatFleetHTMLPage(r *FleetReport) (string, error) {
+	var buf bytes.Buffer
+	err := fleetHTMLTemplate.Execute(&buf, struct {
+		*FleetReport
+		Statuses []string
+	}{r, ScoreStatuses})
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// FormatFleet renders a fleet report as json (default), table, or html
+func FormatFleet(r *FleetReport, format string) (string, error) {
+	if strings.ToLower(format) == FormatHTML {
+		return FormatFleetHTMLPage(r)
+	}
+	return inspector.RenderOutputWith(r, func(st inspector.Styler) string {
+		return FormatFleetTableWith(st, r)
+	}, format)
+}
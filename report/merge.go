@@ -0,0 +1,196 @@
+// Package report combines exported security summaries from several machines
+// into a single comparative report.
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+// FormatHTML renders the merged report as a standalone HTML page
+const FormatHTML = "html"
+
+// Feature outcomes in the comparison matrix
+const (
+	FeaturePass        = "pass"
+	FeatureFail        = "fail"
+	FeatureUnavailable = "n/a"
+)
+
+// Features lists the matrix columns in display order
+var Features = []string{
+	inspector.CheckTPM,
+	inspector.CheckSecureBoot,
+	inspector.CheckEncryption,
+	inspector.CheckBiometrics,
+}
+
+// Host is one machine's row in a merged report
+type Host struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	// MachineID and ReportID come from the report envelope, when the
+	// bundle has one
+	MachineID string            `json:"machine_id,omitempty"`
+	ReportID  string            `json:"report_id,omitempty"`
+	Platform  string            `json:"platform"`
+	Score     int               `json:"score"`
+	Status    string            `json:"status"`
+	Features  map[string]string `json:"features"`
+}
+
+// MergedReport is a comparative report across several machines
+type MergedReport struct {
+	SchemaVersion inspector.ResultVersion `json:"schema_version"`
+
+	GeneratedAt time.Time `json:"generated_at"`
+	Hosts       []Host    `json:"hosts"`
+	// AverageScore is over the hosts with a score, or inspector.NoScore
+	// when none has one
+	AverageScore int `json:"average_score"`
+	// FeatureCoverage counts, per feature, how many hosts pass it
+	FeatureCoverage map[string]int `json:"feature_coverage"`
+}
+
+// LoadBundle reads a security summary exported with `summary -f json`,
+// with or without a report envelope
+func LoadBundle(path string) (*inspector.SecuritySummary, error) {
+	summary, _, err := loadBundle(path)
+	return summary, err
+}
+
+// loadBundle reads a security summary and its envelope, nil if it has none
+func loadBundle(path string) (*inspector.SecuritySummary, *inspector.Envelope, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- user-supplied bundle path
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	var summary inspector.SecuritySummary
+	env, err := inspector.DecodeEnvelope(data, &summary)
+	if err == nil && env == nil {
+		err = json.Unmarshal(data, &summary)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse bundle %s: %w", path, err)
+	}
+	if summary.Platform == "" || env != nil && env.Kind != "summary" {
+		return nil, nil, fmt.Errorf("bundle %s is not a security summary", path)
+	}
+	if env != nil {
+		if err := inspector.CheckSchemaVersion(env.SchemaVersion); err != nil {
+			return nil, nil, fmt.Errorf("bundle %s: %w", path, err)
+		}
+	}
+	if err := inspector.CheckSchemaVersion(summary.SchemaVersion); err != nil {
+		return nil, nil, fmt.Errorf("bundle %s: %w", path, err)
+	}
+	return &summary, env, nil
+}
+
+// Merge loads every bundle and builds a comparative report
+func Merge(paths []string) (*MergedReport, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("no bundles given")
+	}
+
+	report := &MergedReport{
+		GeneratedAt:     time.Now().UTC(),
+		FeatureCoverage: make(map[string]int),
+	}
+	total, scoredHosts := 0, 0
+	for _, path := range paths {
+		summary, env, err := loadBundle(path)
+		if err != nil {
+			return nil, err
+		}
+		host := hostFromSummary(summary, path)
+		if env != nil {
+			host.MachineID = env.MachineID
+			host.ReportID = env.ReportID
+			if summary.Hostname == "" && env.Hostname != "" {
+				host.Name = env.Hostname
+			}
+		}
+		for feature, outcome := range host.Features {
+			if outcome == FeaturePass {
+				report.FeatureCoverage[feature]++
+			}
+		}
+		if host.Score != inspector.NoScore {
+			total += host.Score
+			scoredHosts++
+		}
+		report.Hosts = append(report.Hosts, host)
+	}
+	report.AverageScore = inspector.NoScore
+	if scoredHosts > 0 {
+		report.AverageScore = total / scoredHosts
+	}
+	return report, nil
+}
+
+// hostFromSummary builds a report row from a summary
+func hostFromSummary(summary *inspector.SecuritySummary, path string) Host {
+	name := summary.Hostname
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	features := map[string]string{
+		inspector.CheckTPM:        FeatureUnavailable,
+		inspector.CheckSecureBoot: FeatureUnavailable,
+		inspector.CheckEncryption: FeatureUnavailable,
+		inspector.CheckBiometrics: FeatureUnavailable,
+	}
+	if summary.TPM != nil {
+		features[inspector.CheckTPM] = outcome(summary.TPM.Present && summary.TPM.Enabled)
+	}
+	if summary.SecureBoot != nil {
+		features[inspector.CheckSecureBoot] = outcome(summary.SecureBoot.Enabled)
+	}
+	if summary.Encryption != nil {
+		features[inspector.CheckEncryption] = outcome(summary.Encryption.Enabled)
+	}
+	if summary.Biometrics != nil {
+		features[inspector.CheckBiometrics] = outcome(summary.Biometrics.Configured)
+	}
+
+	return Host{
+		Name:     name,
+		Source:   path,
+		Platform: summary.Platform,
+		Score:    summary.OverallScore,
+		Status:   summary.OverallStatus,
+		Features: features,
+	}
+}
+
+// outcome converts a pass/fail boolean to a matrix cell value
+func outcome(pass bool) string {
+	if pass {
+		return FeaturePass
+	}
+	return FeatureFail
+}
+
+// featureTitle returns the column header for a feature
+func featureTitle(feature string) string {
+	switch feature {
+	case inspector.CheckTPM:
+		return "TPM/SE"
+	case inspector.CheckSecureBoot:
+		return "Secure Boot"
+	case inspector.CheckEncryption:
+		return "Encryption"
+	case inspector.CheckBiometrics:
+		return "Biometrics"
+	}
+	return feature
+}
@@ -0,0 +1,160 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+// writeBundle writes a summary to a temporary JSON bundle and returns its path
+func writeBundle(t *testing.T, dir, name string, summary *inspector.SecuritySummary) string {
+	t.Helper()
+	data, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func testBundles(t *testing.T) []string {
+	dir := t.TempDir()
+	return []string{
+		writeBundle(t, dir, "mac.json", &inspector.SecuritySummary{
+			Hostname:      "mac-01",
+			Platform:      "darwin",
+			OverallScore:  100,
+			OverallStatus: "excellent",
+			TPM:           &inspector.TPMSummary{Present: true, Enabled: true},
+			SecureBoot:    &inspector.BootSummary{Enabled: true},
+			Encryption:    &inspector.EncSummary{Enabled: true},
+			Biometrics:    &inspector.BioSummary{Configured: true},
+		}),
+		writeBundle(t, dir, "build-box.json", &inspector.SecuritySummary{
+			Platform:      "linux",
+			OverallScore:  25,
+			OverallStatus: "needs_improvement",
+			TPM:           &inspector.TPMSummary{Present: true, Enabled: true},
+			SecureBoot:    &inspector.BootSummary{Enabled: false},
+			Encryption:    &inspector.EncSummary{Enabled: false},
+		}),
+	}
+}
+
+func TestMerge(t *testing.T) {
+	r, err := Merge(testBundles(t))
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	if len(r.Hosts) != 2 {
+		t.Fatalf("len(Hosts) = %d, want 2", len(r.Hosts))
+	}
+	if r.Hosts[0].Name != "mac-01" {
+		t.Errorf("Hosts[0].Name = %q, want mac-01", r.Hosts[0].Name)
+	}
+	// Without a hostname the bundle file name is used
+	if r.Hosts[1].Name != "build-box" {
+		t.Errorf("Hosts[1].Name = %q, want build-box", r.Hosts[1].Name)
+	}
+	if r.AverageScore != 62 {
+		t.Errorf("AverageScore = %d, want 62", r.AverageScore)
+	}
+
+	linux := r.Hosts[1].Features
+	if linux[inspector.CheckTPM] != FeaturePass || linux[inspector.CheckEncryption] != FeatureFail ||
+		linux[inspector.CheckBiometrics] != FeatureUnavailable {
+		t.Errorf("linux features = %v", linux)
+	}
+	if r.FeatureCoverage[inspector.CheckTPM] != 2 || r.FeatureCoverage[inspector.CheckEncryption] != 1 {
+		t.Errorf("FeatureCoverage = %v", r.FeatureCoverage)
+	}
+}
+
+func TestMerge_Envelope(t *testing.T) {
+	dir := t.TempDir()
+	env := inspector.NewEnvelope(&inspector.SecuritySummary{Platform: "linux", OverallScore: 50}, time.Now())
+	env.Hostname = "web-01"
+	env.MachineID = "0f3c9a5e2b7d4e61a8c1d2e3f4a5b6c7"
+	data, err := json.Marshal(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "web.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Merge([]string{path})
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	h := r.Hosts[0]
+	if h.Name != "web-01" || h.MachineID != env.MachineID || h.ReportID != env.ReportID || h.Score != 50 {
+		t.Errorf("host = %+v", h)
+	}
+
+	// An envelope around another result is not a bundle
+	data, _ = json.Marshal(inspector.NewEnvelope(&inspector.UptimeResult{Platform: "linux"}, time.Now()))
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Merge([]string{path}); err == nil || !strings.Contains(err.Error(), "not a security summary") {
+		t.Errorf("Merge(uptime envelope) error = %v", err)
+	}
+}
+
+func TestMerge_Errors(t *testing.T) {
+	if _, err := Merge(nil); err == nil {
+		t.Error("expected error for no bundles")
+	}
+
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"cpu": 1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Merge([]string{bad}); err == nil || !strings.Contains(err.Error(), "not a security summary") {
+		t.Errorf("Merge(non-summary) error = %v", err)
+	}
+	if _, err := Merge([]string{filepath.Join(dir, "missing.json")}); err == nil {
+		t.Error("expected error for missing bundle")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	r, err := Merge(testBundles(t))
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	table, _ := Format(r, "table")
+	plain := inspector.StripANSI(table)
+	for _, want := range []string{"mac-01", "build-box", "Secure Boot", "Average Score: 62/100"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("table output missing %q", want)
+		}
+	}
+
+	html, err := Format(r, "html")
+	if err != nil {
+		t.Fatalf("Format(html) failed: %v", err)
+	}
+	if !strings.Contains(html, "<td>mac-01</td>") || !strings.Contains(html, `class="fail"`) {
+		t.Errorf("html output missing host rows or cells:\n%s", html)
+	}
+
+	js, _ := Format(r, "json")
+	var decoded MergedReport
+	if err := json.Unmarshal([]byte(js), &decoded); err != nil || len(decoded.Hosts) != 2 {
+		t.Errorf("json output did not round-trip: %v", err)
+	}
+}
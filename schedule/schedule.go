@@ -0,0 +1,277 @@
+// Package schedule runs security scans periodically while the server runs
+// as a daemon, with random jitter so a fleet does not scan all at once, and
+// defers scans while the machine is low on battery or its user is busy.
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+// Environment variables that configure the schedule
+const (
+	// IntervalEnv is the time between scans, e.g. "6h"; unset or "0"
+	// turns scheduled scans off
+	IntervalEnv = "OMNITRUST_SCHEDULE_INTERVAL"
+	// JitterEnv is the most each scan is randomly delayed by, e.g. "30m"
+	JitterEnv = "OMNITRUST_SCHEDULE_JITTER"
+	// RetryEnv is how long a deferred scan waits before trying again
+	RetryEnv = "OMNITRUST_SCHEDULE_RETRY"
+	// MinBatteryEnv defers scans on battery power below this charge in
+	// percent; 0 ignores the battery
+	MinBatteryEnv = "OMNITRUST_SCHEDULE_MIN_BATTERY"
+	// ACOnlyEnv set to true defers scans whenever on battery power
+	ACOnlyEnv = "OMNITRUST_SCHEDULE_AC_ONLY"
+	// DeferWhenBusyEnv set to false scans even while the user presents or
+	// is on a call
+	DeferWhenBusyEnv = "OMNITRUST_SCHEDULE_DEFER_WHEN_BUSY"
+)
+
+// Defaults for the settings that are not set
+const (
+	DefaultRetry      = 15 * time.Minute
+	DefaultMinBattery = 20
+)
+
+// Config is when scans run and what defers them
+type Config struct {
+	// Interval is the time between scans; zero turns the schedule off
+	Interval time.Duration
+	// Jitter is the most each scan is randomly delayed by
+	Jitter time.Duration
+	// Retry is how long a deferred scan waits before trying again
+	Retry time.Duration
+	// MinBattery defers scans on battery power below this charge in
+	// percent; 0 ignores the battery
+	MinBattery int
+	// ACOnly defers scans whenever the machine is on battery power
+	ACOnly bool
+	// DeferWhenBusy defers scans while something keeps the display awake,
+	// such as a presentation or a video call
+	DeferWhenBusy bool
+}
+
+// ConfigFromEnv reads the schedule from the OMNITRUST_SCHEDULE_* variables
+func ConfigFromEnv() (Config, error) {
+	c := Config{Retry: DefaultRetry, MinBattery: DefaultMinBattery, DeferWhenBusy: true}
+	var errs []error
+	duration := func(key string, d *time.Duration) {
+		if v := os.Getenv(key); v != "" {
+			parsed, err := ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*d = parsed
+		}
+	}
+	boolean := func(key string, b *bool) {
+		if v := os.Getenv(key); v != "" {
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+				return
+			}
+			*b = parsed
+		}
+	}
+	duration(IntervalEnv, &c.Interval)
+	duration(JitterEnv, &c.Jitter)
+	duration(RetryEnv, &c.Retry)
+	boolean(ACOnlyEnv, &c.ACOnly)
+	boolean(DeferWhenBusyEnv, &c.DeferWhenBusy)
+	if v := os.Getenv(MinBatteryEnv); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > 100 {
+			errs = append(errs, fmt.Errorf("%s: %q is not a percentage", MinBatteryEnv, v))
+		} else {
+			c.MinBattery = n
+		}
+	}
+	if c.Retry <= 0 {
+		c.Retry = DefaultRetry
+	}
+	return c, errors.Join(errs...)
+}
+
+// ParseDuration parses a schedule duration: Go syntax ("6h", "30m") or
+// "0", which is never negative
+func ParseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s is negative", s)
+	}
+	return d, nil
+}
+
+// Enabled reports whether scans are scheduled
+func (c Config) Enabled() bool {
+	return c.Interval > 0
+}
+
+// DeferReason explains why a scan should not run in the given power state,
+// or returns "" if it may run
+func (c Config) DeferReason(p *inspector.PowerState) string {
+	switch {
+	case p.OnBattery && c.ACOnly:
+		return "on battery power"
+	case p.OnBattery && p.BatteryPercent >= 0 && p.BatteryPercent < c.MinBattery:
+		return fmt.Sprintf("on battery power at %d%%, below %d%%", p.BatteryPercent, c.MinBattery)
+	case p.Busy && c.DeferWhenBusy:
+		return "the user is busy (" + p.BusyReason + ")"
+	}
+	return ""
+}
+
+// ScanFunc runs one scan and returns its summary
+type ScanFunc func(ctx context.Context) (*inspector.SecuritySummary, error)
+
+// Status is the state of the schedule: when it last scanned and with what
+// outcome, and when it scans next
+type Status struct {
+	Enabled bool `json:"enabled"`
+	// Interval, Jitter, and Retry are the configured durations, e.g. "6h0m0s"
+	Interval string `json:"interval,omitempty"`
+	Jitter   string `json:"jitter,omitempty"`
+	Retry    string `json:"retry,omitempty"`
+
+	LastScan *time.Time `json:"last_scan,omitempty"`
+	// LastScore and LastStatus are the outcome of the last scan that
+	// succeeded
+	LastScore  *int   `json:"last_score,omitempty"`
+	LastStatus string `json:"last_status,omitempty"`
+	// LastError is why the last scan failed, if it did
+	LastError string     `json:"last_error,omitempty"`
+	NextScan  *time.Time `json:"next_scan,omitempty"`
+	// Deferred is why the next scan was put off, while it is
+	Deferred string `json:"deferred,omitempty"`
+	// Deferrals counts the checks that put a scan off since the server
+	// started
+	Deferrals int `json:"deferrals"`
+	// Power is the power state at the last check
+	Power *inspector.PowerState `json:"power,omitempty"`
+}
+
+// Scheduler runs scans on a schedule. Its zero value is not usable; create
+// one with New.
+type Scheduler struct {
+	config Config
+	scan   ScanFunc
+	// power reads the power state; tests replace it
+	power func(context.Context) *inspector.PowerState
+	// jitter returns a random delay up to the configured jitter
+	jitter func() time.Duration
+
+	mu     sync.Mutex
+	status Status
+}
+
+// New returns a scheduler that runs scan as configured
+func New(c Config, scan ScanFunc) *Scheduler {
+	s := &Scheduler{
+		config: c,
+		scan:   scan,
+		power:  inspector.GetPowerStateContext,
+		jitter: func() time.Duration {
+			if c.Jitter <= 0 {
+				return 0
+			}
+			return rand.N(c.Jitter + 1) // #nosec G404 -- spreading load, not security
+		},
+		status: Status{Enabled: c.Enabled()},
+	}
+	if c.Enabled() {
+		s.status.Interval = c.Interval.String()
+		s.status.Jitter = c.Jitter.String()
+		s.status.Retry = c.Retry.String()
+	}
+	return s
+}
+
+// Status returns the current state of the schedule. A nil scheduler
+// reports a disabled schedule.
+func (s *Scheduler) Status() Status {
+	if s == nil {
+		return Status{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status
+	if s.status.Power != nil {
+		power := *s.status.Power
+		status.Power = &power
+	}
+	return status
+}
+
+// Run scans on the schedule until ctx is done. The first scan runs after
+// a random delay up to the jitter, and each next one an interval plus a
+// random delay after the previous one started. A scan that is due while a
+// defer condition holds waits Retry and checks again.
+func (s *Scheduler) Run(ctx context.Context) {
+	if !s.config.Enabled() {
+		return
+	}
+	next := time.Now().Add(s.jitter())
+	s.setNext(next, "")
+	for {
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		next = s.tick(ctx, time.Now())
+	}
+}
+
+// tick runs or defers the scan that is due at now, and returns when the
+// next one is due
+func (s *Scheduler) tick(ctx context.Context, now time.Time) time.Time {
+	power := s.power(ctx)
+	if reason := s.config.DeferReason(power); reason != "" {
+		inspector.Logger().Info("deferring the scheduled scan", "reason", reason, "retry", s.config.Retry)
+		s.mu.Lock()
+		s.status.Power = power
+		s.status.Deferrals++
+		s.mu.Unlock()
+		return s.setNext(now.Add(s.config.Retry), reason)
+	}
+
+	summary, err := s.scan(ctx)
+	s.mu.Lock()
+	s.status.Power = power
+	s.status.LastScan = &now
+	s.status.LastError = ""
+	if err != nil {
+		inspector.Logger().Warn("the scheduled scan failed", "error", err)
+		s.status.LastError = err.Error()
+	} else {
+		score := summary.OverallScore
+		s.status.LastScore, s.status.LastStatus = &score, summary.OverallStatus
+	}
+	s.mu.Unlock()
+	return s.setNext(now.Add(s.config.Interval+s.jitter()), "")
+}
+
+// setNext records when the next scan is due and why it was deferred, and
+// returns next
+func (s *Scheduler) setNext(next time.Time, deferred string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.NextScan = &next
+	s.status.Deferred = deferred
+	return next
+}
@@ -0,0 +1,111 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	for _, key := range []string{IntervalEnv, JitterEnv, RetryEnv, MinBatteryEnv, ACOnlyEnv, DeferWhenBusyEnv} {
+		t.Setenv(key, "")
+	}
+	c, err := ConfigFromEnv()
+	if err != nil || c.Enabled() || c.Retry != DefaultRetry || c.MinBattery != DefaultMinBattery || !c.DeferWhenBusy {
+		t.Errorf("defaults = %+v, %v", c, err)
+	}
+
+	t.Setenv(IntervalEnv, "6h")
+	t.Setenv(JitterEnv, "30m")
+	t.Setenv(MinBatteryEnv, "0")
+	t.Setenv(DeferWhenBusyEnv, "false")
+	c, err = ConfigFromEnv()
+	if err != nil || c.Interval != 6*time.Hour || c.Jitter != 30*time.Minute || c.MinBattery != 0 || c.DeferWhenBusy {
+		t.Errorf("ConfigFromEnv = %+v, %v", c, err)
+	}
+
+	t.Setenv(JitterEnv, "-1m")
+	t.Setenv(MinBatteryEnv, "150")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Error("a negative jitter and a charge above 100% should fail")
+	}
+}
+
+func TestDeferReason(t *testing.T) {
+	c := Config{MinBattery: 20, DeferWhenBusy: true}
+	tests := []struct {
+		name     string
+		c        Config
+		power    inspector.PowerState
+		deferred bool
+	}{
+		{"on AC", c, inspector.PowerState{BatteryPercent: 5}, false},
+		{"low battery", c, inspector.PowerState{OnBattery: true, BatteryPercent: 12}, true},
+		{"charged battery", c, inspector.PowerState{OnBattery: true, BatteryPercent: 80}, false},
+		{"AC only", Config{ACOnly: true}, inspector.PowerState{OnBattery: true, BatteryPercent: 80}, true},
+		{"presenting", c, inspector.PowerState{BatteryPercent: -1, Busy: true, BusyReason: "POWERPNT.EXE"}, true},
+		{"busy allowed", Config{}, inspector.PowerState{BatteryPercent: -1, Busy: true}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.c.DeferReason(&tt.power); (got != "") != tt.deferred {
+			t.Errorf("%s: DeferReason = %q, want deferred %v", tt.name, got, tt.deferred)
+		}
+	}
+}
+
+func TestTick(t *testing.T) {
+	scans := 0
+	s := New(Config{Interval: time.Hour, Jitter: time.Minute, Retry: 10 * time.Minute, MinBattery: 20, DeferWhenBusy: true}, func(ctx context.Context) (*inspector.SecuritySummary, error) {
+		scans++
+		return &inspector.SecuritySummary{OverallScore: 80, OverallStatus: "good"}, nil
+	})
+	power := &inspector.PowerState{OnBattery: true, BatteryPercent: 9}
+	s.power = func(context.Context) *inspector.PowerState { return power }
+	s.jitter = func() time.Duration { return time.Minute }
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	// Low on battery: deferred by the retry time
+	if next := s.tick(context.Background(), now); !next.Equal(now.Add(10*time.Minute)) || scans != 0 {
+		t.Fatalf("deferred tick: next %v after %d scans", next, scans)
+	}
+	st := s.Status()
+	if st.Deferred == "" || st.Deferrals != 1 || st.LastScan != nil || st.Power.BatteryPercent != 9 {
+		t.Errorf("deferred status = %+v", st)
+	}
+
+	// Plugged in: scans, and the next scan is an interval plus jitter away
+	power = &inspector.PowerState{BatteryPercent: 9}
+	now = now.Add(10 * time.Minute)
+	if next := s.tick(context.Background(), now); !next.Equal(now.Add(time.Hour+time.Minute)) || scans != 1 {
+		t.Fatalf("tick: next %v after %d scans", next, scans)
+	}
+	st = s.Status()
+	if st.Deferred != "" || !st.LastScan.Equal(now) || *st.LastScore != 80 || st.LastStatus != "good" || !st.NextScan.Equal(now.Add(61*time.Minute)) {
+		t.Errorf("status = %+v", st)
+	}
+
+	// A failed scan keeps the last score and reports the error
+	s.scan = func(ctx context.Context) (*inspector.SecuritySummary, error) { return nil, errors.New("probe failed") }
+	s.tick(context.Background(), now.Add(time.Hour))
+	if st = s.Status(); st.LastError != "probe failed" || *st.LastScore != 80 {
+		t.Errorf("failed scan status = %+v", st)
+	}
+}
+
+func TestRun_Disabled(t *testing.T) {
+	s := New(Config{}, func(ctx context.Context) (*inspector.SecuritySummary, error) {
+		t.Error("a disabled schedule should not scan")
+		return nil, nil
+	})
+	s.Run(context.Background())
+	if st := s.Status(); st.Enabled || st.NextScan != nil {
+		t.Errorf("disabled status = %+v", st)
+	}
+	var none *Scheduler
+	if st := none.Status(); st.Enabled {
+		t.Errorf("nil scheduler status = %+v", st)
+	}
+}
@@ -0,0 +1,388 @@
+// Package secrets scans for credentials exposed in the environment, shell
+// history, and dotfiles of the current user: AWS access keys, GitHub,
+// GitLab, Slack, and npm tokens, private keys, and secret-looking variable
+// assignments.
+//
+// The scanner reads sensitive files, so it is opt-in: commands and the MCP
+// server only run it when enabled with --enable or OMNITRUST_SECRETS_ENABLE.
+// Results never contain a secret; each finding names the rule, where the
+// secret was found, and a masked preview.
+package secrets
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+// EnableEnv enables the scanner. It is also the environment form of the
+// secrets command's --enable flag.
+const EnableEnv = "OMNITRUST_SECRETS_ENABLE"
+
+// ErrDisabled is returned when a scan is requested without enabling it
+var ErrDisabled = errors.New("the secrets scan is disabled; pass --enable or set " + EnableEnv + "=true")
+
+// Enabled reports whether OMNITRUST_SECRETS_ENABLE turns the scanner on
+func Enabled() bool {
+	v, err := strconv.ParseBool(os.Getenv(EnableEnv))
+	return err == nil && v
+}
+
+// Where a secret was found
+const (
+	SourceEnvironment = "environment"
+	SourceFile        = "file"
+)
+
+// maxLineLength bounds the lines read from a file; longer lines are skipped
+const maxLineLength = 1 << 20
+
+// Files lists the history files and dotfiles scanned, relative to the home
+// directory
+var Files = []string{
+	".bash_history",
+	".zsh_history",
+	".sh_history",
+	".history",
+	".local/share/fish/fish_history",
+	".python_history",
+	".psql_history",
+	".mysql_history",
+	".node_repl_history",
+	"AppData/Roaming/Microsoft/Windows/PowerShell/PSReadLine/ConsoleHost_history.txt",
+	".bashrc",
+	".bash_profile",
+	".profile",
+	".zshrc",
+	".zprofile",
+	".zshenv",
+	".config/fish/config.fish",
+	".env",
+	".netrc",
+	".npmrc",
+}
+
+// rule detects one kind of secret. The secret is the first capture group,
+// or the whole match if the pattern has none.
+type rule struct {
+	id          string
+	description string
+	severity    string
+	pattern     *regexp.Regexp
+}
+
+// rules are tried in order; a match that overlaps an earlier match on the
+// same line is dropped, so specific rules come before generic ones
+var rules = []rule{
+	{"private_key", "Private key", inspector.SeverityHigh,
+		regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )*PRIVATE KEY-----`)},
+	{"aws_access_key_id", "AWS access key ID", inspector.SeverityHigh,
+		regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`)},
+	{"aws_secret_access_key", "AWS secret access key", inspector.SeverityHigh,
+		regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[=:]\s*["']?([A-Za-z0-9/+]{40})\b`)},
+	{"github_token", "GitHub token", inspector.SeverityHigh,
+		regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"gitlab_token", "GitLab token", inspector.SeverityHigh,
+		regexp.MustCompile(`\b(glpat-[A-Za-z0-9_-]{20,})`)},
+	{"slack_token", "Slack token", inspector.SeverityHigh,
+		regexp.MustCompile(`\b(xox[abposr]-[A-Za-z0-9-]{10,})`)},
+	{"npm_token", "npm token", inspector.SeverityHigh,
+		regexp.MustCompile(`\b(npm_[A-Za-z0-9]{36})\b|_authToken\s*=\s*["']?([^\s"'$]{8,})`)},
+	{"google_api_key", "Google API key", inspector.SeverityMedium,
+		regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})`)},
+	{"url_credentials", "Password in a URL", inspector.SeverityMedium,
+		regexp.MustCompile(`[a-z][a-z0-9+.-]*://[^/\s:@]+:([^/\s:@$]{4,})@`)},
+	{"password_argument", "Password passed on the command line", inspector.SeverityMedium,
+		regexp.MustCompile(`--password[= ]\s*["']?([^\s"'$-][^\s"']{3,})`)},
+	{"secret_assignment", "Secret assigned to a variable", inspector.SeverityMedium,
+		regexp.MustCompile(`(?i)\b[A-Z0-9_]*(?:TOKEN|SECRET|PASSWORD|PASSWD|API_?KEY|ACCESS_?KEY)[A-Z0-9_]*["']?\s*[=:]\s*["']?([^\s"'$]{8,})`)},
+}
+
+// fileRules apply only to the named file, before the general rules
+var fileRules = map[string][]rule{
+	".netrc": {{"netrc_password", "Password in .netrc", inspector.SeverityMedium,
+		regexp.MustCompile(`(?:^|\s)password\s+([^\s$]{4,})`)}},
+}
+
+// secretNamePattern matches environment variable names that hold secrets
+var secretNamePattern = regexp.MustCompile(`(?i)TOKEN|SECRET|PASSWORD|PASSWD|API_?KEY|ACCESS_?KEY|PRIVATE_?KEY|CREDENTIAL`)
+
+// Finding is a credential found by the scanner. It never contains the secret.
+type Finding struct {
+	// Rule identifies the kind of secret, e.g. "aws_access_key_id"
+	Rule        string `json:"rule"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+	// Source is "environment" or "file"
+	Source string `json:"source"`
+	// Location is the variable name or the file path relative to the home
+	// directory, e.g. "~/.bash_history"
+	Location string `json:"location"`
+	// Line is the first line the secret appears on
+	Line int `json:"line,omitempty"`
+	// Occurrences counts the lines of the file the same secret appears on
+	Occurrences int `json:"occurrences,omitempty"`
+	// Redacted is a masked preview such as "AKIA****************"
+	Redacted string `json:"redacted"`
+	// WorldReadable is true if other users can read the file
+	WorldReadable bool `json:"world_readable,omitempty"`
+}
+
+// Result reports credentials exposed in the environment and dotfiles
+type Result struct {
+	SchemaVersion inspector.ResultVersion `json:"schema_version"`
+
+	User string `json:"user,omitempty"`
+	// Environment is true if the process environment was scanned
+	Environment bool `json:"environment"`
+	// Files lists the files that exist and were scanned
+	Files    []string  `json:"files"`
+	Findings []Finding `json:"findings"`
+	// Clean is true if nothing was found
+	Clean bool `json:"clean"`
+	// Errors lists files that could not be read
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Scan scans the environment and the dotfiles of the current user, or of
+// the invoking user under sudo. Callers decide whether the scan is enabled.
+func Scan() (*Result, error) {
+	name, home, err := scanUser()
+	if err != nil {
+		return nil, err
+	}
+	result := ScanEnvironment(os.Environ())
+	result.User = name
+	files := ScanFiles(os.DirFS(home), runtime.GOOS != "windows")
+	result.Files = files.Files
+	result.Findings = append(result.Findings, files.Findings...)
+	result.Errors = files.Errors
+	result.Clean = len(result.Findings) == 0
+	return result, nil
+}
+
+// scanUser returns the user whose files are scanned: the invoking user
+// under sudo, since root's own history is rarely the interesting one
+func scanUser() (name, home string, err error) {
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" && runtime.GOOS != "windows" {
+		if u, err := user.Lookup(sudoUser); err == nil {
+			return u.Username, u.HomeDir, nil
+		}
+	}
+	home, err = os.UserHomeDir()
+	if u, uerr := user.Current(); uerr == nil {
+		name = u.Username
+	}
+	return name, home, err
+}
+
+// ScanEnvironment scans environment entries in "NAME=value" form. A value is
+// reported if it matches a rule or its name looks like it holds a secret.
+func ScanEnvironment(environ []string) *Result {
+	result := &Result{Environment: true, Files: []string{}, Findings: []Finding{}}
+	for _, entry := range environ {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || value == "" || name == EnableEnv {
+			continue
+		}
+		matches := matchLine("", value)
+		if len(matches) == 0 && secretNamePattern.MatchString(name) && len(value) >= 8 && !looksLikeReference(value) {
+			matches = []match{{rule: rule{id: "secret_environment_variable", description: "Secret in an environment variable", severity: inspector.SeverityMedium}, secret: value}}
+		}
+		for _, m := range matches {
+			result.Findings = append(result.Findings, Finding{
+				Rule:        m.rule.id,
+				Description: m.rule.description,
+				Severity:    m.rule.severity,
+				Source:      SourceEnvironment,
+				Location:    name,
+				Redacted:    Mask(m.secret),
+			})
+		}
+	}
+	slices.SortStableFunc(result.Findings, func(a, b Finding) int { return strings.Compare(a.Location, b.Location) })
+	result.Clean = len(result.Findings) == 0
+	return result
+}
+
+// looksLikeReference reports whether an environment value points at a
+// secret instead of holding one, e.g. a file path or a secret manager URI
+func looksLikeReference(value string) bool {
+	return strings.HasPrefix(value, "/") || strings.HasPrefix(value, "~") ||
+		strings.Contains(value, "://") && !strings.Contains(value, "@")
+}
+
+// ScanFiles scans the history files and dotfiles in Files under home.
+// checkMode reports files other users can read; it is off on Windows,
+// where file modes do not reflect ACLs.
+func ScanFiles(home fs.FS, checkMode bool) *Result {
+	result := &Result{Files: []string{}, Findings: []Finding{}}
+	for _, name := range Files {
+		info, err := fs.Stat(home, name)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		location := path.Join("~", name)
+		result.Files = append(result.Files, location)
+		findings, err := scanFile(home, name)
+		if err != nil {
+			result.Errors = append(result.Errors, location+": "+err.Error())
+		}
+		for i := range findings {
+			findings[i].Location = location
+			findings[i].WorldReadable = checkMode && info.Mode().Perm()&0o004 != 0
+		}
+		result.Findings = append(result.Findings, findings...)
+	}
+	result.Clean = len(result.Findings) == 0
+	return result
+}
+
+// scanFile scans one file line by line. The same secret found again on a
+// later line is counted instead of reported twice.
+func scanFile(fsys fs.FS, name string) ([]Finding, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var findings []Finding
+	seen := map[string]int{} // rule and secret -> index in findings
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+	for line := 1; scanner.Scan(); line++ {
+		for _, m := range matchLine(name, scanner.Text()) {
+			key := m.rule.id + "\x00" + m.secret
+			if i, ok := seen[key]; ok {
+				findings[i].Occurrences++
+				continue
+			}
+			seen[key] = len(findings)
+			findings = append(findings, Finding{
+				Rule:        m.rule.id,
+				Description: m.rule.description,
+				Severity:    m.rule.severity,
+				Source:      SourceFile,
+				Line:        line,
+				Occurrences: 1,
+				Redacted:    Mask(m.secret),
+			})
+		}
+	}
+	return findings, scanner.Err()
+}
+
+// match is a secret found on a line
+type match struct {
+	rule   rule
+	secret string
+}
+
+// matchLine applies the rules to a line of the named file, dropping matches
+// that overlap an earlier rule's match
+func matchLine(file, line string) []match {
+	var matches []match
+	var taken [][2]int
+	for _, r := range slices.Concat(fileRules[file], rules) {
+		for _, loc := range r.pattern.FindAllStringSubmatchIndex(line, -1) {
+			start, end := loc[0], loc[1]
+			for g := 2; g+1 < len(loc); g += 2 {
+				if loc[g] >= 0 {
+					start, end = loc[g], loc[g+1]
+					break
+				}
+			}
+			if slices.ContainsFunc(taken, func(t [2]int) bool { return start < t[1] && t[0] < end }) {
+				continue
+			}
+			taken = append(taken, [2]int{start, end})
+			matches = append(matches, match{rule: r, secret: line[start:end]})
+		}
+	}
+	return matches
+}
+
+// Mask redacts a secret, keeping only the first four characters of long
+// secrets: enough to tell an AKIA key from a ghp_ token, not to use either
+func Mask(secret string) string {
+	keep := 0
+	if len(secret) >= 16 {
+		keep = 4
+	}
+	return secret[:keep] + strings.Repeat("*", min(len(secret)-keep, 16))
+}
+
+// FormatTable formats scan results as a colored table
+func FormatTable(result *Result) string {
+	return FormatTableWith(inspector.CurrentStyler(), result)
+}
+
+// FormatTableWith is like FormatTable but styles the table with st
+func FormatTableWith(st inspector.Styler, result *Result) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(inspector.IconKey + " Exposed Secrets"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 55)))
+	sb.WriteString("\n\n")
+
+	scanned := fmt.Sprintf("Scanned %d files", len(result.Files))
+	if result.Environment {
+		scanned += " and the environment"
+	}
+	if result.User != "" {
+		scanned += " of " + result.User
+	}
+	sb.WriteString(st.Muted(scanned))
+	sb.WriteString("\n\n")
+
+	if result.Clean {
+		sb.WriteString(st.Success(inspector.IconCheck + " No exposed secrets found"))
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString(st.TableTop(34, 36, 22))
+		sb.WriteString("\n")
+		for _, f := range result.Findings {
+			location := f.Location
+			if f.Line > 0 {
+				location += ":" + strconv.Itoa(f.Line)
+			}
+			if f.WorldReadable {
+				location += st.Danger(" (world-readable)")
+			}
+			description := st.Warning(f.Description)
+			if f.Severity == inspector.SeverityHigh {
+				description = st.Danger(f.Description)
+			}
+			sb.WriteString(st.TableRowColored(inspector.PadRight(location, 34), inspector.PadRight(description, 36), inspector.PadRight(f.Redacted, 22)))
+			sb.WriteString("\n")
+		}
+		sb.WriteString(st.TableBottom(34, 36, 22))
+		sb.WriteString("\n")
+	}
+
+	for _, e := range result.Errors {
+		sb.WriteString(st.Warning(inspector.IconWarning + e))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Format formats scan results in the specified format
+func Format(result *Result, format string) string {
+	return inspector.FormatOutputWith(result, func(st inspector.Styler) string {
+		return FormatTableWith(st, result)
+	}, format)
+}
@@ -0,0 +1,11 @@
+package server
+
+import "github.com/agentplexus/posture/inspector"
+
+// cacheMeta returns the cache state for the tool response metadata
+func cacheMeta(info inspector.CacheInfo) map[string]any {
+	return map[string]any{
+		"cached":            info.Hit,
+		"cache_age_seconds": int(info.Age.Seconds()),
+	}
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDefaultOptions_EnvOverride(t *testing.T) {
+	t.Setenv("OMNITRUST_CACHE_TTL", "5m")
+	if got := DefaultOptions().CacheTTL; got != 5*time.Minute {
+		t.Errorf("CacheTTL = %v, want 5m", got)
+	}
+
+	t.Setenv("OMNITRUST_CACHE_TTL", "bogus")
+	if got := DefaultOptions().CacheTTL; got != DefaultCacheTTL {
+		t.Errorf("CacheTTL with invalid env = %v, want %v", got, DefaultCacheTTL)
+	}
+}
+
+func TestDefaultOptions_TransportEnv(t *testing.T) {
+	if opts := DefaultOptions(); opts.Transport != TransportStdio || opts.Address != DefaultAddress {
+		t.Errorf("defaults = %q %q, want stdio on %s", opts.Transport, opts.Address, DefaultAddress)
+	}
+	t.Setenv(TransportEnv, "HTTP")
+	t.Setenv(AddressEnv, ":9090")
+	if opts := DefaultOptions(); opts.Transport != TransportHTTP || opts.Address != ":9090" {
+		t.Errorf("env override = %q %q, want http on :9090", opts.Transport, opts.Address)
+	}
+}
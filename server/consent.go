@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+// Consent policies for tool calls
+const (
+	// ConsentAllow returns results without asking
+	ConsentAllow = "allow"
+	// ConsentAsk asks the host user to approve each call through MCP
+	// elicitation, and refuses the call if the client cannot ask
+	ConsentAsk = "ask"
+	// ConsentDeny does not offer the tool at all
+	ConsentDeny = "deny"
+)
+
+// ConsentPolicies lists the valid consent policies
+var ConsentPolicies = []string{ConsentAllow, ConsentAsk, ConsentDeny}
+
+// ConsentEnv sets the consent policy per tool, e.g.
+// "list_processes=ask,scan_secrets=deny". The key "sensitive" sets the
+// policy of every tool in SensitiveTools that is not listed itself.
+const ConsentEnv = "OMNITRUST_SERVER_CONSENT"
+
+// ConsentSensitive is the consent key that stands for all SensitiveTools
+const ConsentSensitive = "sensitive"
+
+// SensitiveTools are the tools whose results can expose personal data,
+// with a description of that data for the consent prompt
+var SensitiveTools = map[string]string{
+	"list_processes":             "the names, owners, and resource usage of running processes",
+	"get_memory_top":             "the names and owners of the processes using the most memory",
+	"get_fd_usage":               "the processes holding the most open files",
+	"stream_metrics":             "the processes using the most CPU, sampled over time",
+	"get_biometric_capabilities": "which local users have enrolled fingerprints or faces",
+	"get_device_fingerprint":     "hardware identifiers of this machine",
+	"scan_secrets":               "where credentials were found in the environment, shell history, and dotfiles",
+}
+
+// ParseConsent parses the ConsentEnv syntax into a policy per tool
+func ParseConsent(s string) (map[string]string, error) {
+	policies := make(map[string]string)
+	for _, entry := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		tool, policy, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("consent entry %q is not tool=policy", entry)
+		}
+		policies[strings.TrimSpace(tool)] = strings.ToLower(strings.TrimSpace(policy))
+	}
+	return policies, ValidateConsent(policies)
+}
+
+// ValidateConsent reports the first policy that is not one of
+// ConsentPolicies
+func ValidateConsent(policies map[string]string) error {
+	tools := make([]string, 0, len(policies))
+	for tool := range policies {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	for _, tool := range tools {
+		if !slices.Contains(ConsentPolicies, policies[tool]) {
+			return fmt.Errorf("consent policy for %s must be %s", tool, strings.Join(ConsentPolicies, ", "))
+		}
+	}
+	return nil
+}
+
+// FormatConsent renders policies in the ConsentEnv syntax
+func FormatConsent(policies map[string]string) string {
+	entries := make([]string, 0, len(policies))
+	for tool, policy := range policies {
+		entries = append(entries, tool+"="+policy)
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// consentPolicy returns the policy for a tool: its own entry, then for
+// SensitiveTools the sensitive entry and otherwise ask, then allow
+func consentPolicy(policies map[string]string, tool string) string {
+	if policy, ok := policies[tool]; ok {
+		return policy
+	}
+	if _, ok := SensitiveTools[tool]; ok {
+		if policy, ok := policies[ConsentSensitive]; ok {
+			return policy
+		}
+		return ConsentAsk
+	}
+	return ConsentAllow
+}
+
+// checkTools are the dedicated tools of the checks run_check runs, by check
+// ID
+var checkTools = map[string]string{
+	inspector.CheckTPM:              "get_platform_security_chip",
+	inspector.CheckSecureBoot:       "get_secure_boot_status",
+	inspector.CheckBootOrder:        "get_boot_order",
+	inspector.CheckEncryption:       "get_encryption_status",
+	inspector.CheckBiometrics:       "get_biometric_capabilities",
+	inspector.CheckDefender:         "get_defender_status",
+	inspector.CheckUAC:              "get_uac_status",
+	inspector.CheckLegacyProtocols:  "get_legacy_protocols",
+	inspector.CheckBrowser:          "get_browser_security",
+	inspector.CheckDocker:           "get_container_security",
+	inspector.CheckKubelet:          "get_kubelet_security",
+	inspector.CheckUptime:           "get_uptime",
+	inspector.CheckUSBStorage:       "get_usb_devices",
+	inspector.CheckFirmware:         "get_firmware_status",
+	inspector.CheckManagementEngine: "get_management_engine",
+	inspector.CheckPasskeys:         "get_passkeys",
+	inspector.CheckKeychain:         "get_keychain",
+}
+
+// callPolicy returns the tool whose policy applies to a call, and that
+// policy. A run_check call is held to the policy of the check's dedicated
+// tool when it is stricter (ConsentPolicies is ordered from least to most
+// strict), so that it cannot get around it.
+func callPolicy(policies map[string]string, call *mcp.CallToolRequest) (string, string) {
+	tool := call.Params.Name
+	policy := consentPolicy(policies, tool)
+	if tool != "run_check" {
+		return tool, policy
+	}
+	var args struct {
+		Check string `json:"check"`
+	}
+	_ = json.Unmarshal(call.Params.Arguments, &args)
+	if checkTool, ok := checkTools[args.Check]; ok {
+		if p := consentPolicy(policies, checkTool); slices.Index(ConsentPolicies, p) > slices.Index(ConsentPolicies, policy) {
+			return checkTool, p
+		}
+	}
+	return tool, policy
+}
+
+// deniedTools returns the tools whose policy is deny
+func deniedTools(policies map[string]string) []string {
+	var denied []string
+	for tool := range policies {
+		if tool != ConsentSensitive && policies[tool] == ConsentDeny {
+			denied = append(denied, tool)
+		}
+	}
+	for tool := range SensitiveTools {
+		if _, ok := policies[tool]; !ok && consentPolicy(policies, tool) == ConsentDeny {
+			denied = append(denied, tool)
+		}
+	}
+	return denied
+}
+
+// requireConsent asks the host user, through elicitation, to approve calls
+// to tools whose policy is ask, and refuses calls whose policy is deny, such
+// as run_check for a check whose dedicated tool is denied. Declined calls,
+// and calls from clients that do not support elicitation, fail without
+// running the tool.
+func requireConsent(policies map[string]string) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			call, ok := req.(*mcp.CallToolRequest)
+			if !ok {
+				return next(ctx, method, req)
+			}
+			tool, policy := callPolicy(policies, call)
+			var err error
+			switch policy {
+			case ConsentAllow:
+				return next(ctx, method, req)
+			case ConsentDeny:
+				err = fmt.Errorf("%s is denied by the consent policy", tool)
+			default:
+				err = askConsent(ctx, call, tool)
+			}
+			if err != nil {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: err.Error()},
+					},
+					IsError: true,
+				}, nil
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// askConsent elicits the host user's approval of a call subject to the
+// policy of tool
+func askConsent(ctx context.Context, call *mcp.CallToolRequest, tool string) error {
+	message := fmt.Sprintf("Allow the assistant to call %s?", tool)
+	if data, ok := SensitiveTools[tool]; ok {
+		message = fmt.Sprintf("Allow the assistant to call %s? It returns %s.", tool, data)
+	}
+	res, err := call.Session.Elicit(ctx, &mcp.ElicitParams{
+		Message:         message,
+		RequestedSchema: &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{}},
+	})
+	if err != nil {
+		return fmt.Errorf("%s requires the user's consent, which could not be asked for (%v); set its policy to %s in %s to allow it without asking", tool, err, ConsentAllow, ConsentEnv)
+	}
+	if res.Action != "accept" {
+		return fmt.Errorf("the user did not consent to %s (%s)", tool, res.Action)
+	}
+	return nil
+}
+
+// consentFromEnv reads ConsentEnv, ignoring it if it does not parse
+func consentFromEnv() map[string]string {
+	policies, err := ParseConsent(os.Getenv(ConsentEnv))
+	if err != nil {
+		return nil
+	}
+	return policies
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+func TestParseConsent(t *testing.T) {
+	policies, err := ParseConsent("list_processes=ask, sensitive=DENY")
+	if err != nil {
+		t.Fatalf("ParseConsent: %v", err)
+	}
+	if policies["list_processes"] != ConsentAsk || policies[ConsentSensitive] != ConsentDeny {
+		t.Errorf("ParseConsent = %v", policies)
+	}
+	if got := FormatConsent(policies); got != "list_processes=ask,sensitive=deny" {
+		t.Errorf("FormatConsent = %q", got)
+	}
+	for _, bad := range []string{"list_processes", "list_processes=maybe"} {
+		if _, err := ParseConsent(bad); err == nil {
+			t.Errorf("ParseConsent(%q) should fail", bad)
+		}
+	}
+}
+
+func TestConsentPolicy(t *testing.T) {
+	policies := map[string]string{ConsentSensitive: ConsentAsk, "get_device_fingerprint": ConsentAllow, "get_uptime": ConsentDeny}
+	for tool, want := range map[string]string{
+		"list_processes":         ConsentAsk,
+		"get_device_fingerprint": ConsentAllow,
+		"get_uptime":             ConsentDeny,
+		"get_memory":             ConsentAllow,
+	} {
+		if got := consentPolicy(policies, tool); got != want {
+			t.Errorf("consentPolicy(%s) = %s, want %s", tool, got, want)
+		}
+	}
+
+	// Without a policy, sensitive tools ask and the rest are allowed
+	for tool, want := range map[string]string{"scan_secrets": ConsentAsk, "get_memory": ConsentAllow} {
+		if got := consentPolicy(nil, tool); got != want {
+			t.Errorf("default consentPolicy(%s) = %s, want %s", tool, got, want)
+		}
+	}
+}
+
+func TestConsent_RunCheckAsksByDefault(t *testing.T) {
+	cs := connect(t, nil)
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "run_check", Arguments: map[string]any{"check": inspector.CheckBiometrics}})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "get_biometric_capabilities requires the user's consent") {
+		t.Errorf("run_check biometrics without consent = %+v", res.Content)
+	}
+}
+
+func TestConsent_Deny(t *testing.T) {
+	cs := connectWith(t, &Options{Consent: map[string]string{ConsentSensitive: ConsentDeny}}, nil)
+	tools, err := cs.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	for _, tool := range tools.Tools {
+		if _, ok := SensitiveTools[tool.Name]; ok {
+			t.Errorf("denied tool %s is offered", tool.Name)
+		}
+	}
+	if !slices.ContainsFunc(tools.Tools, func(tool *mcp.Tool) bool { return tool.Name == "get_memory" }) {
+		t.Error("get_memory should still be offered")
+	}
+}
+
+func TestConsent_Ask(t *testing.T) {
+	var asked []string
+	action := "accept"
+	cs := connectWith(t, &Options{Consent: map[string]string{"list_processes": ConsentAsk}}, &mcp.ClientOptions{
+		ElicitationHandler: func(_ context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			asked = append(asked, req.Params.Message)
+			return &mcp.ElicitResult{Action: action}, nil
+		},
+	})
+	call := func() *mcp.CallToolResult {
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "list_processes", Arguments: map[string]any{"limit": 1}})
+		if err != nil {
+			t.Fatalf("CallTool: %v", err)
+		}
+		return res
+	}
+
+	if res := call(); res.IsError {
+		t.Errorf("accepted call failed: %v", res.Content)
+	}
+	if len(asked) != 1 || !strings.Contains(asked[0], "list_processes") {
+		t.Errorf("elicitation messages = %q", asked)
+	}
+
+	action = "decline"
+	if res := call(); !res.IsError || res.StructuredContent != nil {
+		t.Errorf("declined call returned %+v", res)
+	}
+
+	if _, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "get_uptime"}); err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if len(asked) != 2 {
+		t.Errorf("asked %d times, want 2 (get_uptime needs no consent)", len(asked))
+	}
+}
+
+func TestConsent_AskWithoutElicitation(t *testing.T) {
+	cs := connectWith(t, &Options{Consent: map[string]string{ConsentSensitive: ConsentAsk}}, nil)
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "list_processes"})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, ConsentEnv) {
+		t.Errorf("call without a way to ask = %+v", res.Content)
+	}
+}
+
+func TestConsent_RunCheckFollowsDedicatedTool(t *testing.T) {
+	for _, id := range inspector.RegisteredChecks() {
+		if _, ok := checkTools[id]; !ok {
+			t.Errorf("check %s has no dedicated tool", id)
+		}
+	}
+
+	cs := connectWith(t, &Options{Consent: map[string]string{ConsentSensitive: ConsentDeny}}, nil)
+	run := func(check string) *mcp.CallToolResult {
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "run_check", Arguments: map[string]any{"check": check}})
+		if err != nil {
+			t.Fatalf("CallTool: %v", err)
+		}
+		return res
+	}
+	if res := run(inspector.CheckBiometrics); !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "get_biometric_capabilities") {
+		t.Errorf("run_check biometrics = %+v, want it denied", res.Content)
+	}
+	if res := run(inspector.CheckUptime); res.IsError {
+		t.Errorf("run_check uptime failed: %v", res.Content)
+	}
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// doctorTimeout bounds the in-memory MCP session opened by DoctorItems
+const doctorTimeout = 10 * time.Second
+
+// DoctorItems checks that the MCP server works on this machine: a client
+// connects over an in-memory transport and lists the tools, and for the
+// http transport the listen address is free to bind.
+func DoctorItems(ctx context.Context, opts *Options) []inspector.DoctorItem {
+	items := []inspector.DoctorItem{sessionItem(ctx, opts)}
+	if opts.Transport == TransportHTTP {
+		items = append(items, listenItem(opts.Address))
+	}
+	return items
+}
+
+// sessionItem opens an in-memory MCP session and lists the server's tools
+func sessionItem(ctx context.Context, opts *Options) inspector.DoctorItem {
+	item := inspector.DoctorItem{Category: inspector.DoctorTransport, Name: "mcp session", Status: inspector.DoctorError}
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := NewMCPServerWithOptions(opts).Connect(ctx, serverTransport, nil)
+	if err != nil {
+		item.Detail = fmt.Sprintf("server connect: %v", err)
+		return item
+	}
+	defer func() { _ = ss.Close() }()
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "doctor"}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		item.Detail = fmt.Sprintf("client connect: %v", err)
+		return item
+	}
+	defer func() { _ = cs.Close() }()
+	tools, err := cs.ListTools(ctx, nil)
+	if err != nil {
+		item.Detail = fmt.Sprintf("list tools: %v", err)
+		return item
+	}
+	item.Status, item.Detail = inspector.DoctorOK, fmt.Sprintf("%d tools", len(tools.Tools))
+	return item
+}
+
+// listenItem reports whether the http transport's address can be bound; it
+// is in use while the server is running as a service
+func listenItem(address string) inspector.DoctorItem {
+	item := inspector.DoctorItem{Category: inspector.DoctorTransport, Name: "http " + address, Status: inspector.DoctorOK}
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		item.Status, item.Detail = inspector.DoctorWarning, err.Error()
+		return item
+	}
+	_ = l.Close()
+	return item
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// logRequests logs every MCP request with its duration: tool calls at info
+// level (warn if the tool failed) and other methods at debug level
+func logRequests(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		start := time.Now()
+		result, err := next(ctx, method, req)
+		log := inspector.Logger().With("method", method, "duration", time.Since(start))
+		level := slog.LevelDebug
+		if call, ok := req.(*mcp.CallToolRequest); ok {
+			log = log.With("tool", call.Params.Name)
+			level = slog.LevelInfo
+			if res, ok := result.(*mcp.CallToolResult); ok && res != nil && res.IsError {
+				level = slog.LevelWarn
+			}
+		}
+		if err != nil {
+			log.Warn("request failed", "err", err)
+		} else {
+			log.Log(ctx, level, "request handled")
+		}
+		return result, err
+	}
+}
@@ -2,276 +2,1791 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/agentplexus/posture/inspector"
+	"github.com/agentplexus/posture/redact"
+	"github.com/agentplexus/posture/schedule"
+	"github.com/agentplexus/posture/secrets"
 )
 
 // Tool argument types - System metrics
 type GetCPUUsageArgs struct {
-	Format string `json:"format,omitempty" jsonschema:"Output format: json (default) or table"`
+	IntervalMs *int   `json:"interval_ms,omitempty" jsonschema:"Sampling window in milliseconds (default 500, max 10000); 0 returns usage since the previous call"`
+	Format     string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template   string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact     bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
 }
 
 type GetMemoryArgs struct {
-	Format string `json:"format,omitempty" jsonschema:"Output format: json (default) or table"`
+	Top      int    `json:"top,omitempty" jsonschema:"Also include the N processes using the most resident memory"`
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetMemoryTopArgs struct {
+	Limit    int    `json:"limit,omitempty" jsonschema:"Number of processes to return (default 10)"`
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetFDUsageArgs struct {
+	Limit    int    `json:"limit,omitempty" jsonschema:"Number of processes to return (default 10)"`
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetGPUInfoArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetSensorsArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type StreamMetricsArgs struct {
+	IntervalSeconds int    `json:"interval_seconds,omitempty" jsonschema:"Seconds between snapshots (default 5, 1-60)"`
+	Count           int    `json:"count,omitempty" jsonschema:"Number of snapshots to take before returning (default 12, max 120)"`
+	Top             *int   `json:"top,omitempty" jsonschema:"Number of top processes by CPU in each snapshot (default 5, 0 to omit)"`
+	Format          string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template        string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact          bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
 }
 
 type ListProcessesArgs struct {
-	Limit  int    `json:"limit,omitempty" jsonschema:"Maximum number of processes to return (0 for all)"`
-	Format string `json:"format,omitempty" jsonschema:"Output format: json (default) or table"`
+	Limit     int      `json:"limit,omitempty" jsonschema:"Maximum number of processes to return (0 for all)"`
+	Offset    int      `json:"offset,omitempty" jsonschema:"Number of matching processes to skip, for pagination"`
+	Name      string   `json:"name,omitempty" jsonschema:"Only processes whose name contains this substring (case-insensitive)"`
+	User      string   `json:"user,omitempty" jsonschema:"Only processes owned by this user"`
+	MinCPU    float64  `json:"min_cpu,omitempty" jsonschema:"Only processes using at least this CPU percentage"`
+	MinMemory float32  `json:"min_memory,omitempty" jsonschema:"Only processes using at least this memory percentage"`
+	Sort      string   `json:"sort,omitempty" jsonschema:"Sort key: cpu (default), memory, pid, or name"`
+	Fields    []string `json:"fields,omitempty" jsonschema:"Only collect these fields (default all); fields that are not collected are left empty, which is much faster on busy hosts"`
+	Format    string   `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template  string   `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact    bool     `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+// Tool argument types - Security tools
+type GetPlatformSecurityChipArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Refresh  bool   `json:"refresh,omitempty" jsonschema:"Bypass the result cache and re-run the probe"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+	Explain  bool   `json:"explain,omitempty" jsonschema:"Record which command, file, or API produced each field and when, returned in the response metadata as provenance"`
+}
+
+type GetDefenderStatusArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetUACStatusArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetLegacyProtocolsArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetConfigProfilesArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetBrowserSecurityArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetContainerSecurityArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetKubeletSecurityArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetUptimeArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetUSBDevicesArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetFirmwareStatusArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetBootOrderArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetManagementEngineArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetPasskeysArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetKeychainArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type AuditFilesystemArgs struct {
+	Allow    []string `json:"allow,omitempty" jsonschema:"Additional allowed SUID/SGID binaries: base names, absolute paths, or globs such as /opt/vendor/*"`
+	Format   string   `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string   `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool     `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type ScanSecretsArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetSecureBootStatusArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+	Explain  bool   `json:"explain,omitempty" jsonschema:"Record which command, file, or API produced each field and when, returned in the response metadata as provenance"`
+}
+
+type GetEncryptionStatusArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Refresh  bool   `json:"refresh,omitempty" jsonschema:"Bypass the result cache and re-run the probe"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+	Explain  bool   `json:"explain,omitempty" jsonschema:"Record which command, file, or API produced each field and when, returned in the response metadata as provenance"`
+}
+
+type GetBiometricCapabilitiesArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	AllUsers bool   `json:"all_users,omitempty" jsonschema:"Also list enrollment for every local user (other users usually require elevated privileges)"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+	Explain  bool   `json:"explain,omitempty" jsonschema:"Record which command, file, or API produced each field and when, returned in the response metadata as provenance"`
+}
+
+type GetSecuritySummaryArgs struct {
+	Format      string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template    string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	MinSeverity string `json:"min_severity,omitempty" jsonschema:"Only report findings at least this severe: critical, high, medium, or low"`
+	Redact      bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+	Explain     bool   `json:"explain,omitempty" jsonschema:"Record which command, file, or API produced each field and finding and when, returned in the response metadata as provenance"`
+}
+
+type ExplainScoreArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.Score}}), or a built-in: oneline, csv"`
+}
+
+type ListChecksArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.Platform}}), or a built-in: oneline, csv"`
+}
+
+type RunCheckArgs struct {
+	Check    string `json:"check" jsonschema:"ID of the check to run, as listed by list_checks"`
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the check result's Go field names, or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetRuntimeEnvironmentArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetCloudContextArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetFingerprintArgs struct {
+	Raw      bool   `json:"raw,omitempty" jsonschema:"Report the raw platform UUID, serial number, and machine ID instead of their hashes"`
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+type GetVirtualizationStatusArgs struct {
+	Format   string `json:"format,omitempty" jsonschema:"Output format: json (default), table, csv, ndjson, or template"`
+	Template string `json:"template,omitempty" jsonschema:"Go template for format=template using the result's Go field names (e.g. {{.OverallScore}}), or a built-in: oneline, csv"`
+	Redact   bool   `json:"redact,omitempty" jsonschema:"Mask hostnames, usernames, serial numbers, and volume names in the result"`
+}
+
+// System metric handlers
+
+func handleGetCPUUsage(ctx context.Context, req *mcp.CallToolRequest, args GetCPUUsageArgs) (*mcp.CallToolResult, *inspector.CPUUsageResult, error) {
+	interval := inspector.DefaultCPUSampleInterval
+	if args.IntervalMs != nil {
+		interval = time.Duration(*args.IntervalMs) * time.Millisecond
+	}
+	result, err := inspector.GetCPUUsageWithInterval(ctx, interval)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatCPUUsage)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetMemory(ctx context.Context, req *mcp.CallToolRequest, args GetMemoryArgs) (*mcp.CallToolResult, *inspector.MemoryResult, error) {
+	result, err := inspector.GetMemory(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	if args.Top > 0 {
+		top, err := inspector.GetMemoryTop(ctx, args.Top)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+				},
+				IsError: true,
+			}, nil, nil
+		}
+		result.TopProcesses = top
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatMemory)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetMemoryTop(ctx context.Context, req *mcp.CallToolRequest, args GetMemoryTopArgs) (*mcp.CallToolResult, *inspector.MemoryTopResult, error) {
+	result, err := inspector.GetMemoryTop(ctx, args.Limit)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatMemoryTop)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetFDUsage(ctx context.Context, req *mcp.CallToolRequest, args GetFDUsageArgs) (*mcp.CallToolResult, *inspector.FDUsageResult, error) {
+	result, err := inspector.GetFDUsage(ctx, args.Limit)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatFDUsage)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetGPUInfo(ctx context.Context, req *mcp.CallToolRequest, args GetGPUInfoArgs) (*mcp.CallToolResult, *inspector.GPUResult, error) {
+	result, err := inspector.GetGPUInfo(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatGPUInfo)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetSensors(ctx context.Context, req *mcp.CallToolRequest, args GetSensorsArgs) (*mcp.CallToolResult, *inspector.SensorsResult, error) {
+	result, err := inspector.GetSensors(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatSensors)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+// Limits for stream_metrics, which holds the tool call open while it samples
+const (
+	defaultStreamCount = 12
+	maxStreamCount     = 120
+	maxStreamInterval  = 60
+)
+
+// StreamMetricsResult is the structured result of stream_metrics
+type StreamMetricsResult struct {
+	Snapshots []*inspector.MetricsSnapshot `json:"snapshots"`
+}
+
+func handleStreamMetrics(ctx context.Context, req *mcp.CallToolRequest, args StreamMetricsArgs) (*mcp.CallToolResult, *StreamMetricsResult, error) {
+	opts := inspector.StreamOptions{
+		Interval:     inspector.DefaultStreamInterval,
+		Count:        defaultStreamCount,
+		TopProcesses: inspector.DefaultStreamTopProcesses,
+	}
+	if args.IntervalSeconds < 0 || args.IntervalSeconds > maxStreamInterval || args.Count < 0 || args.Count > maxStreamCount {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("interval_seconds must be 1-%d and count 1-%d", maxStreamInterval, maxStreamCount)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	if args.IntervalSeconds > 0 {
+		opts.Interval = time.Duration(args.IntervalSeconds) * time.Second
+	}
+	if args.Count > 0 {
+		opts.Count = args.Count
+	}
+	if args.Top != nil {
+		opts.TopProcesses = *args.Top
+	}
+
+	// Each snapshot is sent as a progress notification as soon as it is
+	// taken, so clients that passed a progress token can follow along
+	token := req.Params.GetProgressToken()
+	redactor := newRedactor(args.Redact)
+	snapshots := []*inspector.MetricsSnapshot{}
+	err := inspector.StreamMetrics(ctx, opts, func(s *inspector.MetricsSnapshot) error {
+		snapshots = append(snapshots, s)
+		if token == nil || req.Session == nil {
+			return nil
+		}
+		msg := inspector.FormatMetricsSnapshotLine(s)
+		switch {
+		case args.Template != "":
+			var err error
+			if msg, err = inspector.Render(s, inspector.TemplateFormat(args.Template), inspector.FormatMetricsSnapshot); err != nil {
+				return err
+			}
+		case !strings.EqualFold(args.Format, inspector.FormatTable):
+			if data, err := json.Marshal(s); err == nil {
+				msg = string(data)
+			}
+		}
+		if redactor != nil {
+			redactor.Collect(s)
+			msg = redactor.String(msg)
+		}
+		_ = req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Message:       msg,
+			Progress:      float64(s.Sequence),
+			Total:         float64(opts.Count),
+		})
+		return nil
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.RenderOutputWith(snapshots, func(st inspector.Styler) string {
+		var sb strings.Builder
+		for _, s := range snapshots {
+			sb.WriteString(inspector.FormatMetricsSnapshotTableWith(st, s))
+		}
+		return sb.String()
+	}, outputFormat(args.Format, args.Template))
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, &StreamMetricsResult{Snapshots: snapshots}, nil
+}
+
+func handleListProcesses(ctx context.Context, req *mcp.CallToolRequest, args ListProcessesArgs) (*mcp.CallToolResult, *inspector.ProcessListResult, error) {
+	result, err := inspector.ListProcessesWithOptions(ctx, inspector.ProcessOptions{
+		Name:      args.Name,
+		User:      args.User,
+		MinCPU:    args.MinCPU,
+		MinMemory: args.MinMemory,
+		Sort:      args.Sort,
+		Offset:    args.Offset,
+		Limit:     args.Limit,
+		Fields:    args.Fields,
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatProcessList)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+// Security tool handlers
+
+func handleGetPlatformSecurityChip(cache *inspector.CachedInspector) mcp.ToolHandlerFor[GetPlatformSecurityChipArgs, *inspector.TPMResult] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args GetPlatformSecurityChipArgs) (*mcp.CallToolResult, *inspector.TPMResult, error) {
+		var info inspector.CacheInfo
+		result, provenance, err := collect(ctx, args.Explain, func(ctx context.Context) (*inspector.TPMResult, error) {
+			result, i, err := inspector.Cached(cache, inspector.CheckTPM, args.Refresh || args.Explain, func() (*inspector.TPMResult, error) { return inspector.GetTPMStatusContext(ctx) })
+			info = i
+			return result, err
+		})
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+				},
+				IsError: true,
+			}, nil, nil
+		}
+
+		output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatTPM)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+				},
+				IsError: true,
+			}, nil, nil
+		}
+		return &mcp.CallToolResult{
+			Meta: provenanceMeta(cacheMeta(info), provenance),
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: output},
+			},
+		}, result, nil
+	}
+}
+
+func handleGetSecureBootStatus(ctx context.Context, req *mcp.CallToolRequest, args GetSecureBootStatusArgs) (*mcp.CallToolResult, *inspector.SecureBootResult, error) {
+	result, provenance, err := collect(ctx, args.Explain, inspector.GetSecureBootStatusContext)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatSecureBoot)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Meta: provenanceMeta(nil, provenance),
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetDefenderStatus(ctx context.Context, req *mcp.CallToolRequest, args GetDefenderStatusArgs) (*mcp.CallToolResult, *inspector.DefenderResult, error) {
+	result, err := inspector.GetDefenderStatusContext(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatDefender)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetUACStatus(ctx context.Context, req *mcp.CallToolRequest, args GetUACStatusArgs) (*mcp.CallToolResult, *inspector.UACResult, error) {
+	result, err := inspector.GetUACStatusContext(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatUAC)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetLegacyProtocols(ctx context.Context, req *mcp.CallToolRequest, args GetLegacyProtocolsArgs) (*mcp.CallToolResult, *inspector.LegacyProtocolsResult, error) {
+	result, err := inspector.GetLegacyProtocolsContext(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatLegacyProtocols)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetConfigProfiles(ctx context.Context, req *mcp.CallToolRequest, args GetConfigProfilesArgs) (*mcp.CallToolResult, *inspector.ConfigProfilesResult, error) {
+	result, err := inspector.GetConfigProfilesContext(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatConfigProfiles)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetBrowserSecurity(ctx context.Context, req *mcp.CallToolRequest, args GetBrowserSecurityArgs) (*mcp.CallToolResult, *inspector.BrowserSecurityResult, error) {
+	result, err := inspector.GetBrowserSecurityContext(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatBrowserSecurity)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetContainerSecurity(ctx context.Context, req *mcp.CallToolRequest, args GetContainerSecurityArgs) (*mcp.CallToolResult, *inspector.ContainerSecurityResult, error) {
+	result, err := inspector.GetContainerSecurityContext(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatContainerSecurity)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetKubeletSecurity(ctx context.Context, req *mcp.CallToolRequest, args GetKubeletSecurityArgs) (*mcp.CallToolResult, *inspector.KubeletResult, error) {
+	result, err := inspector.GetKubeletSecurityContext(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatKubeletSecurity)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetUptime(ctx context.Context, req *mcp.CallToolRequest, args GetUptimeArgs) (*mcp.CallToolResult, *inspector.UptimeResult, error) {
+	result, err := inspector.GetUptimeContext(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatUptime)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetUSBDevices(ctx context.Context, req *mcp.CallToolRequest, args GetUSBDevicesArgs) (*mcp.CallToolResult, *inspector.USBDevicesResult, error) {
+	result, err := inspector.GetUSBDevicesContext(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatUSBDevices)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetFirmwareStatus(ctx context.Context, req *mcp.CallToolRequest, args GetFirmwareStatusArgs) (*mcp.CallToolResult, *inspector.FirmwareResult, error) {
+	result, err := inspector.GetFirmwareStatusContext(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatFirmwareStatus)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleGetBootOrder(ctx context.Context, req *mcp.CallToolRequest, args GetBootOrderArgs) (*mcp.CallToolResult, *inspector.BootOrderResult, error) {
+	result, err := inspector.GetBootOrderContext(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatBootOrder)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
 }
 
-// Tool argument types - Security tools
-type GetPlatformSecurityChipArgs struct {
-	Format string `json:"format,omitempty" jsonschema:"Output format: json (default) or table"`
-}
+func handleGetManagementEngine(ctx context.Context, req *mcp.CallToolRequest, args GetManagementEngineArgs) (*mcp.CallToolResult, *inspector.ManagementEngineResult, error) {
+	result, err := inspector.GetManagementEngineContext(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
 
-type GetSecureBootStatusArgs struct {
-	Format string `json:"format,omitempty" jsonschema:"Output format: json (default) or table"`
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatManagementEngine)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
 }
 
-type GetEncryptionStatusArgs struct {
-	Format string `json:"format,omitempty" jsonschema:"Output format: json (default) or table"`
-}
+func handleGetKeychain(ctx context.Context, req *mcp.CallToolRequest, args GetKeychainArgs) (*mcp.CallToolResult, *inspector.KeychainResult, error) {
+	result, err := inspector.GetKeychainContext(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
 
-type GetBiometricCapabilitiesArgs struct {
-	Format string `json:"format,omitempty" jsonschema:"Output format: json (default) or table"`
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatKeychain)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
 }
 
-type GetSecuritySummaryArgs struct {
-	Format string `json:"format,omitempty" jsonschema:"Output format: json (default) or table"`
-}
+func handleGetPasskeys(ctx context.Context, req *mcp.CallToolRequest, args GetPasskeysArgs) (*mcp.CallToolResult, *inspector.PasskeyResult, error) {
+	result, err := inspector.GetPasskeyStatusContext(ctx)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
 
-// System metric handlers
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatPasskeys)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
 
-func handleGetCPUUsage(ctx context.Context, req *mcp.CallToolRequest, args GetCPUUsageArgs) (*mcp.CallToolResult, any, error) {
-	result, err := inspector.GetCPUUsage(ctx)
+func handleAuditFilesystem(ctx context.Context, req *mcp.CallToolRequest, args AuditFilesystemArgs) (*mcp.CallToolResult, *inspector.FilesystemAuditResult, error) {
+	opts := inspector.DefaultFilesystemAuditOptions()
+	opts.Allowlist = append(opts.Allowlist, args.Allow...)
+	result, err := inspector.AuditFilesystemContext(ctx, opts)
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
-				&mcp.TextContent{Text: err.Error()},
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
 			},
 			IsError: true,
 		}, nil, nil
 	}
 
-	output := inspector.FormatCPUUsage(result, args.Format)
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatFilesystemAudit)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: output},
 		},
-	}, nil, nil
+	}, result, nil
 }
 
-func handleGetMemory(ctx context.Context, req *mcp.CallToolRequest, args GetMemoryArgs) (*mcp.CallToolResult, any, error) {
-	result, err := inspector.GetMemory(ctx)
+func handleGetFingerprint(ctx context.Context, req *mcp.CallToolRequest, args GetFingerprintArgs) (*mcp.CallToolResult, *inspector.FingerprintResult, error) {
+	opts := inspector.DefaultFingerprintOptions()
+	opts.Hashed = !args.Raw
+	result, err := inspector.GetFingerprintContext(ctx, opts)
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
-				&mcp.TextContent{Text: err.Error()},
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
 			},
 			IsError: true,
 		}, nil, nil
 	}
 
-	output := inspector.FormatMemory(result, args.Format)
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatFingerprint)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: output},
 		},
-	}, nil, nil
+	}, result, nil
 }
 
-func handleListProcesses(ctx context.Context, req *mcp.CallToolRequest, args ListProcessesArgs) (*mcp.CallToolResult, any, error) {
-	result, err := inspector.ListProcesses(ctx, args.Limit)
+func handleScanSecrets(ctx context.Context, req *mcp.CallToolRequest, args ScanSecretsArgs) (*mcp.CallToolResult, *secrets.Result, error) {
+	result, err := secrets.Scan()
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
-				&mcp.TextContent{Text: err.Error()},
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
 			},
 			IsError: true,
 		}, nil, nil
 	}
 
-	output := inspector.FormatProcessList(result, args.Format)
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), secrets.Format)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: output},
 		},
-	}, nil, nil
+	}, result, nil
 }
 
-// Security tool handlers
+func handleGetEncryptionStatus(cache *inspector.CachedInspector) mcp.ToolHandlerFor[GetEncryptionStatusArgs, *inspector.EncryptionResult] {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args GetEncryptionStatusArgs) (*mcp.CallToolResult, *inspector.EncryptionResult, error) {
+		var info inspector.CacheInfo
+		result, provenance, err := collect(ctx, args.Explain, func(ctx context.Context) (*inspector.EncryptionResult, error) {
+			result, i, err := inspector.Cached(cache, inspector.CheckEncryption, args.Refresh || args.Explain, func() (*inspector.EncryptionResult, error) { return inspector.GetEncryptionStatusContext(ctx) })
+			info = i
+			return result, err
+		})
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+				},
+				IsError: true,
+			}, nil, nil
+		}
+
+		output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatEncryption)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+				},
+				IsError: true,
+			}, nil, nil
+		}
+		return &mcp.CallToolResult{
+			Meta: provenanceMeta(cacheMeta(info), provenance),
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: output},
+			},
+		}, result, nil
+	}
+}
+
+func handleGetBiometricCapabilities(ctx context.Context, req *mcp.CallToolRequest, args GetBiometricCapabilitiesArgs) (*mcp.CallToolResult, *inspector.BiometricCapabilities, error) {
+	result, provenance, err := collect(ctx, args.Explain, func(ctx context.Context) (*inspector.BiometricCapabilities, error) {
+		return inspector.GetBiometricCapabilitiesWithOptionsContext(ctx, inspector.BiometricOptions{
+			AllUsers: args.AllUsers,
+		})
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatBiometricCapabilities)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Meta: provenanceMeta(nil, provenance),
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
 
-func handleGetPlatformSecurityChip(_ context.Context, req *mcp.CallToolRequest, args GetPlatformSecurityChipArgs) (*mcp.CallToolResult, any, error) {
-	result, err := inspector.GetTPMStatus()
+func handleGetSecuritySummary(ctx context.Context, req *mcp.CallToolRequest, args GetSecuritySummaryArgs) (*mcp.CallToolResult, *inspector.SecuritySummary, error) {
+	result, provenance, err := collect(ctx, args.Explain, inspector.GetSecuritySummaryContext)
+	if err == nil && args.MinSeverity != "" {
+		result.Findings, err = inspector.FilterFindings(result.Findings, args.MinSeverity)
+	}
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
-				&mcp.TextContent{Text: err.Error()},
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
 			},
 			IsError: true,
 		}, nil, nil
 	}
 
-	output := inspector.FormatTPM(result, args.Format)
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatSecuritySummary)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
 	return &mcp.CallToolResult{
+		Meta: provenanceMeta(nil, provenance),
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: output},
 		},
-	}, nil, nil
+	}, result, nil
 }
 
-func handleGetSecureBootStatus(_ context.Context, req *mcp.CallToolRequest, args GetSecureBootStatusArgs) (*mcp.CallToolResult, any, error) {
-	result, err := inspector.GetSecureBootStatus()
+func handleExplainScore(ctx context.Context, req *mcp.CallToolRequest, args ExplainScoreArgs) (*mcp.CallToolResult, *inspector.ScoreExplanation, error) {
+	summary, err := inspector.GetSecuritySummaryContext(ctx)
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
-				&mcp.TextContent{Text: err.Error()},
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
 			},
 			IsError: true,
 		}, nil, nil
 	}
 
-	output := inspector.FormatSecureBoot(result, args.Format)
+	result := inspector.ExplainScore(summary)
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatScoreExplanation)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
+
+func handleListChecks(ctx context.Context, req *mcp.CallToolRequest, args ListChecksArgs) (*mcp.CallToolResult, *inspector.CheckListResult, error) {
+	result := inspector.ListChecks()
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatCheckList)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: output},
 		},
-	}, nil, nil
+	}, result, nil
 }
 
-func handleGetEncryptionStatus(_ context.Context, req *mcp.CallToolRequest, args GetEncryptionStatusArgs) (*mcp.CallToolResult, any, error) {
-	result, err := inspector.GetEncryptionStatus()
+// RunCheckResult is the structured result of run_check: the check's own
+// result, or why it could not run here
+type RunCheckResult struct {
+	Check     string `json:"check"`
+	Supported bool   `json:"supported"`
+	Reason    string `json:"reason,omitempty"`
+	// Result is the result the check's dedicated tool returns
+	Result any `json:"result,omitempty"`
+}
+
+func handleRunCheck(ctx context.Context, req *mcp.CallToolRequest, args RunCheckArgs) (*mcp.CallToolResult, *RunCheckResult, error) {
+	result, err := inspector.RunCheckContext(ctx, args.Check)
+	if errors.Is(err, inspector.ErrUnsupportedPlatform) {
+		return nil, &RunCheckResult{Check: args.Check, Reason: inspector.ErrorMessage(err)}, nil
+	}
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
-				&mcp.TextContent{Text: err.Error()},
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
 			},
 			IsError: true,
 		}, nil, nil
 	}
 
-	output := inspector.FormatEncryption(result, args.Format)
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), func(result any, format string) string {
+		return inspector.FormatCheckResult(args.Check, result, format)
+	})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: output},
 		},
-	}, nil, nil
+	}, &RunCheckResult{Check: args.Check, Supported: true, Result: result}, nil
 }
 
-func handleGetBiometricCapabilities(_ context.Context, req *mcp.CallToolRequest, args GetBiometricCapabilitiesArgs) (*mcp.CallToolResult, any, error) {
-	result, err := inspector.GetBiometricCapabilities()
+func handleGetRuntimeEnvironment(ctx context.Context, req *mcp.CallToolRequest, args GetRuntimeEnvironmentArgs) (*mcp.CallToolResult, *inspector.RuntimeEnvironment, error) {
+	result := inspector.GetRuntimeEnvironmentContext(ctx)
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatRuntimeEnvironment)
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
-				&mcp.TextContent{Text: err.Error()},
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
 			},
 			IsError: true,
 		}, nil, nil
 	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: output},
+		},
+	}, result, nil
+}
 
-	output := inspector.FormatBiometricCapabilities(result, args.Format)
+func handleGetCloudContext(ctx context.Context, req *mcp.CallToolRequest, args GetCloudContextArgs) (*mcp.CallToolResult, *inspector.CloudContext, error) {
+	result := inspector.GetCloudContext(ctx)
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatCloudContext)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: output},
 		},
-	}, nil, nil
+	}, result, nil
 }
 
-func handleGetSecuritySummary(_ context.Context, req *mcp.CallToolRequest, args GetSecuritySummaryArgs) (*mcp.CallToolResult, any, error) {
-	result, err := inspector.GetSecuritySummary()
+func handleGetVirtualizationStatus(ctx context.Context, req *mcp.CallToolRequest, args GetVirtualizationStatusArgs) (*mcp.CallToolResult, *inspector.VirtualizationResult, error) {
+	result, err := inspector.GetVirtualizationStatusContext(ctx)
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
-				&mcp.TextContent{Text: err.Error()},
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
 			},
 			IsError: true,
 		}, nil, nil
 	}
 
-	output := inspector.FormatSecuritySummary(result, args.Format)
+	output, err := inspector.Render(result, outputFormat(args.Format, args.Template), inspector.FormatVirtualizationStatus)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+			},
+			IsError: true,
+		}, nil, nil
+	}
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: output},
 		},
-	}, nil, nil
+	}, result, nil
+}
+
+// outputFormat combines the format and template arguments; a template
+// implies format=template
+func outputFormat(format, tmpl string) string {
+	if tmpl != "" {
+		return inspector.TemplateFormat(tmpl)
+	}
+	return format
+}
+
+// DefaultCacheTTL is how long expensive probe results are reused by default
+const DefaultCacheTTL = 60 * time.Second
+
+// Server transports
+const (
+	TransportStdio = "stdio"
+	TransportHTTP  = "http"
+)
+
+// DefaultAddress is where the HTTP transport listens by default. It is bound
+// to loopback so the server is not exposed to the network by accident.
+const DefaultAddress = "127.0.0.1:8080"
+
+// Environment variables read by DefaultOptions
+const (
+	CacheTTLEnv  = "OMNITRUST_CACHE_TTL"
+	TransportEnv = "OMNITRUST_SERVER_TRANSPORT"
+	AddressEnv   = "OMNITRUST_SERVER_ADDRESS"
+	// HideUnsupportedEnv leaves out the tools this platform does not support
+	HideUnsupportedEnv = "OMNITRUST_SERVER_HIDE_UNSUPPORTED"
+)
+
+// Options configures the MCP server
+type Options struct {
+	// CacheTTL controls how long TPM and encryption results are cached.
+	// Zero disables caching.
+	CacheTTL time.Duration
+	// Transport is stdio (default) or http (streamable HTTP)
+	Transport string
+	// Address is the listen address for the http transport
+	Address string
+	// SecretsScan registers the scan_secrets tool, which reads the
+	// environment, shell history, and dotfiles of the server's user
+	SecretsScan bool
+	// Redact masks identifying values in every result, as if each call
+	// passed redact=true
+	Redact bool
+	// Consent sets the consent policy (allow, ask, or deny) per tool name;
+	// the key "sensitive" covers every tool in SensitiveTools. SensitiveTools
+	// without a policy ask, and other tools are allowed.
+	Consent map[string]string
+	// HideUnsupported leaves out the tools of checks this platform does not
+	// have, instead of listing them with a supported=false result
+	HideUnsupported bool
+	// Schedule runs the scheduled scans whose last and next times the
+	// schedule resource reports; nil reports a disabled schedule
+	Schedule *schedule.Scheduler
+}
+
+// DefaultOptions returns the default server options. The cache TTL can be
+// overridden with the OMNITRUST_CACHE_TTL environment variable (e.g. "5m", "0"),
+// the transport with OMNITRUST_SERVER_TRANSPORT and OMNITRUST_SERVER_ADDRESS,
+// OMNITRUST_SECRETS_ENABLE turns on the secrets scan,
+// OMNITRUST_SERVER_CONSENT sets the consent policies,
+// OMNITRUST_SERVER_HIDE_UNSUPPORTED leaves out unsupported tools, and
+// OMNITRUST_REDACT redacts every result.
+func DefaultOptions() *Options {
+	opts := &Options{
+		CacheTTL:    DefaultCacheTTL,
+		Transport:   TransportStdio,
+		Address:     DefaultAddress,
+		SecretsScan: secrets.Enabled(),
+		Consent:     consentFromEnv(),
+	}
+	if v, err := strconv.ParseBool(os.Getenv(redact.EnableEnv)); err == nil {
+		opts.Redact = v
+	}
+	if v, err := strconv.ParseBool(os.Getenv(HideUnsupportedEnv)); err == nil {
+		opts.HideUnsupported = v
+	}
+	if v := os.Getenv(CacheTTLEnv); v != "" {
+		if ttl, err := time.ParseDuration(v); err == nil {
+			opts.CacheTTL = ttl
+		}
+	}
+	if v := os.Getenv(TransportEnv); v != "" {
+		opts.Transport = strings.ToLower(v)
+	}
+	if v := os.Getenv(AddressEnv); v != "" {
+		opts.Address = v
+	}
+	return opts
 }
 
-// NewMCPServer creates and configures a new MCP server
+// NewMCPServer creates and configures a new MCP server with default options
 func NewMCPServer() *mcp.Server {
+	return NewMCPServerWithOptions(DefaultOptions())
+}
+
+// NewMCPServerWithOptions creates and configures a new MCP server
+func NewMCPServerWithOptions(opts *Options) *mcp.Server {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	cache := inspector.NewCachedInspector(opts.CacheTTL)
+
 	server := mcp.NewServer(&mcp.Implementation{
 		Name:    "posture",
-		Version: "1.0.0",
-	}, nil)
+		Version: inspector.ToolVersion(),
+	}, &mcp.ServerOptions{Logger: inspector.Logger()})
+	server.AddReceivingMiddleware(logRequests, requireConsent(opts.Consent), redactResults(opts.Redact))
 
 	// ============================================
 	// Security Tools (Primary Focus)
 	// ============================================
 
 	// Platform Security Chip status (TPM on Windows/Linux, Secure Enclave on macOS)
-	if inspector.IsTPMSupported() {
-		mcp.AddTool(server, &mcp.Tool{
+	if inspector.CheckEnabled(inspector.CheckTPM) {
+		addPlatformTool(server, opts, inspector.IsTPMSupported(), &mcp.Tool{
 			Name:        "get_platform_security_chip",
-			Description: "Returns platform security chip status: Secure Enclave on macOS, TPM (Trusted Platform Module) on Windows/Linux. Includes presence, version, manufacturer, and hardware key support capabilities. Use format='table' for colored ASCII table output.",
-		}, handleGetPlatformSecurityChip)
+			Description: "Returns platform security chip status: Secure Enclave on macOS, TPM (Trusted Platform Module) on Windows/Linux. Includes presence, version, manufacturer, tpm_kind (discrete, firmware, or virtual), and hardware key support capabilities. Results are cached briefly; pass refresh=true to re-run the probe. Use format='table' for colored ASCII table output.",
+		}, handleGetPlatformSecurityChip(cache))
 	}
 
 	// Secure Boot status (all platforms)
-	if inspector.IsSecureBootSupported() {
-		mcp.AddTool(server, &mcp.Tool{
+	if inspector.CheckEnabled(inspector.CheckSecureBoot) {
+		addPlatformTool(server, opts, inspector.IsSecureBootSupported(), &mcp.Tool{
 			Name:        "get_secure_boot_status",
 			Description: "Returns UEFI Secure Boot status including whether it's enabled, the security mode, and boot policy. Use format='table' for colored ASCII table output.",
 		}, handleGetSecureBootStatus)
 	}
 
+	// Boot order and external boot (all platforms)
+	if inspector.CheckEnabled(inspector.CheckBootOrder) {
+		addPlatformTool(server, opts, inspector.IsBootOrderSupported(), &mcp.Tool{
+			Name:        "get_boot_order",
+			Description: "Returns the firmware boot order and whether removable media (USB, optical) or network (PXE) boot entries come ahead of the system disk. On Linux and Windows it reads the UEFI BootOrder and Boot#### variables (efivarfs, GetFirmwareEnvironmentVariable); on Intel Macs it reports whether a firmware password restricts booting from external media. External or network boot ahead of the disk is a finding in the security summary. Use format='table' for colored ASCII table output.",
+		}, handleGetBootOrder)
+	}
+
 	// Disk Encryption status (all platforms)
-	if inspector.IsEncryptionSupported() {
-		mcp.AddTool(server, &mcp.Tool{
+	if inspector.CheckEnabled(inspector.CheckEncryption) {
+		addPlatformTool(server, opts, inspector.IsEncryptionSupported(), &mcp.Tool{
 			Name:        "get_encryption_status",
-			Description: "Returns disk encryption status (FileVault on macOS, BitLocker on Windows, LUKS on Linux) including whether encryption is enabled and which volumes are encrypted. Use format='table' for colored ASCII table output.",
-		}, handleGetEncryptionStatus)
+			Description: "Returns disk encryption status (FileVault on macOS, BitLocker on Windows, LUKS on Linux) including whether encryption is enabled and which volumes are encrypted. Results are cached briefly; pass refresh=true to re-run the probe. Use format='table' for colored ASCII table output.",
+		}, handleGetEncryptionStatus(cache))
 	}
 
 	// Biometric capabilities (all platforms)
-	if inspector.IsBiometricsSupported() {
-		mcp.AddTool(server, &mcp.Tool{
+	if inspector.CheckEnabled(inspector.CheckBiometrics) {
+		addPlatformTool(server, opts, inspector.IsBiometricsSupported(), &mcp.Tool{
 			Name:        "get_biometric_capabilities",
-			Description: "Returns biometric authentication capabilities including Touch ID/fingerprint, Face ID/facial recognition availability and enrollment status. On Windows this includes Windows Hello status. Use format='table' for colored ASCII table output.",
+			Description: "Returns biometric authentication capabilities including Touch ID/fingerprint, Face ID/facial recognition availability and enrollment status. On Windows this includes Windows Hello status. Pass all_users=true to list enrollment for every local user (shared workstation audits). Use format='table' for colored ASCII table output.",
 		}, handleGetBiometricCapabilities)
 	}
 
+	// Microsoft Defender (Windows)
+	if inspector.CheckEnabled(inspector.CheckDefender) {
+		addPlatformTool(server, opts, inspector.IsDefenderSupported(), &mcp.Tool{
+			Name:        "get_defender_status",
+			Description: "Returns Microsoft Defender Antivirus configuration on Windows: running mode, real-time, cloud-delivered, and tamper protection, antivirus signature age, last quick and full scan times, and attack surface reduction (ASR) rules with their actions. Use format='table' for colored ASCII table output.",
+		}, handleGetDefenderStatus)
+	}
+
+	// UAC and SmartScreen (Windows)
+	if inspector.CheckEnabled(inspector.CheckUAC) {
+		addPlatformTool(server, opts, inspector.IsUACSupported(), &mcp.Tool{
+			Name:        "get_uac_status",
+			Description: "Returns User Account Control and SmartScreen settings on Windows, read from the registry: whether UAC is on, how administrators are prompted to elevate, whether prompts use the secure desktop, Admin Approval Mode for the built-in Administrator, and SmartScreen for apps and in Microsoft Edge with where each setting comes from (policy, setting, or default). Use format='table' for colored ASCII table output.",
+		}, handleGetUACStatus)
+	}
+
+	// Legacy protocols (Windows)
+	if inspector.CheckEnabled(inspector.CheckLegacyProtocols) {
+		addPlatformTool(server, opts, inspector.IsLegacyProtocolsSupported(), &mcp.Tool{
+			Name:        "get_legacy_protocols",
+			Description: "Returns legacy network protocols enabled on Windows that enable lateral movement: SMBv1 server and client, LM/NTLMv1 authentication (LmCompatibilityLevel), LLMNR, and NetBIOS over TCP/IP per network interface, with findings and remediations in the security summary. Use format='table' for colored ASCII table output.",
+		}, handleGetLegacyProtocols)
+	}
+
+	// Browser security settings (all platforms)
+	if inspector.CheckEnabled(inspector.CheckBrowser) {
+		addTool(server, &mcp.Tool{
+			Name:        "get_browser_security",
+			Description: "Returns the security settings of the current user's Chrome, Edge, Firefox, and Safari profiles, read from their preference stores: version and days since it last changed (outdated after 60 days), Safe Browsing/SmartScreen mode, HTTPS-Only mode, whether insecure downloads are blocked, and installed extensions with where they came from (chrome_web_store, edge_addons, firefox_addons, policy, unpacked, other). Use format='table' for colored ASCII table output.",
+		}, handleGetBrowserSecurity)
+	}
+
+	// Docker daemon security (all platforms)
+	if inspector.CheckEnabled(inspector.CheckDocker) {
+		addTool(server, &mcp.Tool{
+			Name:        "get_container_security",
+			Description: "Returns the security configuration of the Docker daemon when Docker is installed, from docker info, daemon.json, the docker.service unit, and Docker Desktop's settings: TCP sockets and whether they require TLS client certificates, rootless mode, user namespace remapping (userns-remap), live-restore, insecure registries, and running containers started with --privileged. Reports installed=false on machines without Docker. Use format='table' for colored ASCII table output.",
+		}, handleGetContainerSecurity)
+	}
+
+	// Kubernetes node (Linux only)
+	if inspector.CheckEnabled(inspector.CheckKubelet) {
+		addPlatformTool(server, opts, inspector.IsKubeletSupported(), &mcp.Tool{
+			Name:        "get_kubelet_security",
+			Description: "Returns the security configuration of a Kubernetes node's kubelet, read from its command line and config file, evaluated against CIS Kubernetes Benchmark worker node recommendations: kubelet.conf and config file permissions (4.1.5, 4.1.9), anonymous authentication (4.2.1), authorization mode (4.2.2), the read-only port (4.2.4), and certificate rotation (4.2.10). Also reports whether the containerd or CRI-O socket is world-accessible. Reports detected=false on machines without a kubelet; in a node agent pod, set OMNITRUST_HOST_ROOT to the host's root filesystem mount. Use format='table' for colored ASCII table output.",
+		}, handleGetKubeletSecurity)
+	}
+
+	// Uptime and pending reboots (all platforms)
+	if inspector.CheckEnabled(inspector.CheckUptime) {
+		addTool(server, &mcp.Tool{
+			Name:        "get_uptime",
+			Description: "Returns the uptime, last boot time, and whether a reboot is pending to finish installing updates: /var/run/reboot-required, needs-restarting -r, or a removed running kernel on Linux, the Component Based Servicing RebootPending and Windows Update RebootRequired keys on Windows, and available updates that require a restart on macOS. Lists the waiting packages and, where known, how many days the reboot has been pending; a reboot pending for more than 7 days is a high-severity finding in the security summary. Use format='table' for colored ASCII table output.",
+		}, handleGetUptime)
+	}
+
+	// USB devices and removable-media policy (all platforms)
+	if inspector.CheckEnabled(inspector.CheckUSBStorage) {
+		addTool(server, &mcp.Tool{
+			Name:        "get_usb_devices",
+			Description: "Returns whether USB mass storage is restricted and the USB devices currently connected, with vendor and product IDs and whether each is a mass storage device. Restrictions are read from modprobe rules for usb-storage and USBGuard on Linux, the USBSTOR driver and removable storage access policies on Windows, and managed mount-controls for external disks on macOS. When OMNITRUST_USB_STORAGE_POLICY is block, unrestricted USB storage is scored and reported as a finding in the security summary. Use format='table' for colored ASCII table output.",
+		}, handleGetUSBDevices)
+	}
+
+	// Firmware updates and host security (all platforms)
+	if inspector.CheckEnabled(inspector.CheckFirmware) {
+		addTool(server, &mcp.Tool{
+			Name:        "get_firmware_status",
+			Description: "Returns the system firmware vendor, version, and release date with firmware update status: pending updates and the HSI (Host Security ID) rating with each hardware security attribute from fwupd on Linux, UEFI capsule update state from the ESRT on Windows, including updates that failed to apply, and the installed firmware version against the one expected by the running macOS. Available and failed updates are findings in the security summary. Requires fwupdmgr on Linux. Use format='table' for colored ASCII table output.",
+		}, handleGetFirmwareStatus)
+	}
+
+	// Intel ME / AMT and AMD PSP (Linux and Windows)
+	if inspector.CheckEnabled(inspector.CheckManagementEngine) {
+		addPlatformTool(server, opts, inspector.IsManagementEngineSupported(), &mcp.Tool{
+			Name:        "get_management_engine",
+			Description: "Returns the state of the platform's management coprocessor: whether an Intel Management Engine or AMD Platform Security Processor was found, its firmware version, and whether Intel AMT is provisioned and listening on its ports (16992-16995). On Linux it also reports the ME operation and manufacturing modes from the MEI firmware status registers, whether the ME firmware includes AMT, and whether AMD PSP debug is locked and the part is fused for production. Network-exposed AMT, manufacturing or override modes, and unlocked PSP debug are findings in the security summary. Use format='table' for colored ASCII table output.",
+		}, handleGetManagementEngine)
+	}
+
+	// Passkey authenticators (all platforms)
+	if inspector.CheckEnabled(inspector.CheckPasskeys) {
+		addPlatformTool(server, opts, inspector.IsPasskeySupported(), &mcp.Tool{
+			Name:        "get_passkeys",
+			Description: "Returns whether the device can create and use passkeys: on Windows, whether the WebAuthn API is present and Windows Hello is set up as a platform authenticator; on macOS, whether the release supports passkeys, iCloud Keychain is turned on, and a configuration profile blocks it; on Linux, which has no platform authenticator, the connected FIDO2 security keys and whether the user can open them. ready=false is a finding in the security summary on macOS and Windows. Use format='table' for colored ASCII table output.",
+		}, handleGetPasskeys)
+	}
+
+	// Credential store and password managers (all platforms)
+	if inspector.CheckEnabled(inspector.CheckKeychain) {
+		addPlatformTool(server, opts, inspector.IsKeychainSupported(), &mcp.Tool{
+			Name:        "get_keychain",
+			Description: "Returns the current user's OS credential store and the password managers installed. On macOS it reports whether the login keychain locks on sleep and after how long idle (0 = never); on Windows whether the Credential Manager service is enabled, whether a DPAPI protect/unprotect round trip works under the user's master key, and how many credentials are saved; on Linux whether GNOME Keyring or KWallet is in use and unlocked at login through PAM. Password managers (1Password, Bitwarden, KeePassXC, LastPass, and others) are found as installed applications and as browser extensions. The result is informational and never scored. Use format='table' for colored ASCII table output.",
+		}, handleGetKeychain)
+	}
+
+	// SUID/SGID and PATH permission audit (Linux only)
+	if inspector.CheckEnabled(inspector.CheckFilesystem) {
+		addPlatformTool(server, opts, inspector.IsFilesystemAuditSupported(), &mcp.Tool{
+			Name:        "audit_filesystem",
+			Description: "Audits filesystem hygiene on Linux: SUID and SGID binaries in system directories and PATH that are not on the allowlist of binaries distributions ship (pass allow to extend it), SUID/SGID binaries that are world-writable, world-writable directories in or above PATH, and relative PATH entries. Returns findings with severities. The search is bounded to three levels below each directory and by a timeout (OMNITRUST_FS_AUDIT_TIMEOUT, default 10s); truncated=true means it stopped early. Use format='table' for colored output.",
+		}, handleAuditFilesystem)
+	}
+
+	// Exposed secrets (all platforms, opt-in)
+	if opts.SecretsScan {
+		addTool(server, &mcp.Tool{
+			Name:        "scan_secrets",
+			Description: "Scans the server user's environment variables, shell history, and dotfiles (.bashrc, .env, .netrc, .npmrc, PowerShell history) for exposed credentials: AWS access keys, GitHub, GitLab, Slack, and npm tokens, Google API keys, private keys, passwords in URLs and command lines, and *_TOKEN or *_SECRET assignments. Secrets are never returned; each finding has the rule, the variable or file and line, and a masked preview. Only available when the server runs with OMNITRUST_SECRETS_ENABLE=true or serve --enable-secrets-scan. Use format='table' for colored ASCII table output.",
+		}, handleScanSecrets)
+	}
+
+	// Configuration profiles and MDM (macOS)
+	addPlatformTool(server, opts, inspector.IsConfigProfilesSupported(), &mcp.Tool{
+		Name:        "get_config_profiles",
+		Description: "Returns installed configuration profiles on macOS with their payload types, MDM enrollment (user approved, DEP), supervision, and whether FileVault, firewall, and password policy restrictions come from an MDM-managed or a user-installed profile. Without root only the current user's profiles may be listed. Use format='table' for colored ASCII table output.",
+	}, handleGetConfigProfiles)
+
 	// Security Summary (all platforms)
-	mcp.AddTool(server, &mcp.Tool{
+	addTool(server, &mcp.Tool{
 		Name:        "get_security_summary",
-		Description: "Returns a unified security posture overview including platform security chip (Secure Enclave/TPM), Secure Boot, disk encryption, and biometric status with an overall security score and recommendations. Use format='table' for colored ASCII table output.",
+		Description: "Returns a unified security posture overview including platform security chip (Secure Enclave/TPM), Secure Boot, whether external or network boot comes ahead of the system disk, disk encryption, biometric, browser, and Docker daemon security status, plus Microsoft Defender, UAC, SmartScreen, and legacy protocols on Windows and the kubelet on Kubernetes nodes, whether a reboot is pending for updates, firmware update status, network-exposed Intel AMT, with an overall security score, sub-scores for the identity, data protection, boot integrity, network, endpoint protection, and patching domains, and findings (each with an id, severity, affected check, and remediation), plus the cloud instance when running on AWS, Azure, or GCP. Inside a container or WSL, host-only checks are reported as not_applicable_in_container or not_applicable_in_wsl and excluded from the score. Use format='table' for colored ASCII table output.",
 	}, handleGetSecuritySummary)
 
+	// Scoring math of the security summary (all platforms)
+	addTool(server, &mcp.Tool{
+		Name:        "explain_score",
+		Description: "Explains how the security summary's overall score is computed, instead of reverse-engineering it from the thresholds: each check's enforcement (standard, mandatory, informational), weight, possible and awarded points (25 × weight for a pass), result (pass, fail, timeout, not_run, disabled, not_applicable) and why, from its findings when it failed. The score is floor(earned × 100 / possible); the status is excellent at 100, good from 75, fair from 50, needs_improvement from 25, and critical below or when a mandatory check fails. best_improvement names the single check whose passing would raise the score the most, with its remediation and the score and status after. Use format='table' for colored output.",
+	}, handleExplainScore)
+
+	// Check registry (all platforms)
+	addTool(server, &mcp.Tool{
+		Name:        "list_checks",
+		Description: "Lists the security checks that run_check can run, with each check's ID, description, posture domain, whether it is supported on this platform, and whether the check selection (profile, OMNITRUST_ONLY_CHECKS, OMNITRUST_DISABLE_CHECKS) enables it. Use format='table' for colored ASCII table output.",
+	}, handleListChecks)
+
+	addTool(server, &mcp.Tool{
+		Name:        "run_check",
+		Description: "Runs one security check by its ID from list_checks and returns its result, the same result as the check's dedicated tool, under result, so checks without a dedicated tool are reachable too. The call is held to the consent policy of the check's dedicated tool when that is stricter. A check this platform does not support returns supported=false with the reason; a disabled check is an error. Use format='table' for the check's colored ASCII table output.",
+	}, handleRunCheck)
+
+	// Runtime environment (all platforms)
+	addTool(server, &mcp.Tool{
+		Name:        "get_runtime_environment",
+		Description: "Detects whether the server is running inside a container (Docker, Podman, Kubernetes, containerd, LXC) using marker files, environment, cgroup, and mount heuristics, or in a WSL guest, and lists the host-only checks that are not applicable there. Under WSL it includes TPM, Secure Boot, and BitLocker hints from the Windows host. Use format='table' for colored ASCII table output.",
+	}, handleGetRuntimeEnvironment)
+
+	// Cloud instance context (all platforms)
+	addTool(server, &mcp.Tool{
+		Name:        "get_cloud_context",
+		Description: "Identifies the cloud instance (AWS, Azure, GCP) through the instance metadata service: provider, instance ID and type, region, and zone. Flags AWS instances that still accept IMDSv1 and reports whether a vTPM and confidential computing (SEV-SNP, TDX) are enabled. Use format='table' for colored ASCII table output.",
+	}, handleGetCloudContext)
+
+	// Device fingerprint (all platforms)
+	addPlatformTool(server, opts, inspector.IsFingerprintSupported(), &mcp.Tool{
+		Name:        "get_device_fingerprint",
+		Description: "Returns a stable device identifier for correlating snapshots and fleet records, derived from the first readable of the SMBIOS platform UUID (IOPlatformUUID on macOS), the TPM endorsement key, the hardware serial number, or the OS machine ID. The identifiers it was computed from are hashed unless raw=true. Use format='table' for colored ASCII table output.",
+	}, handleGetFingerprint)
+
+	// Virtualization and hypervisor detection (all platforms)
+	addTool(server, &mcp.Tool{
+		Name:        "get_virtualization_status",
+		Description: "Identifies whether the machine is a virtual machine and which hypervisor it runs on (KVM, Hyper-V, VMware, Xen, VirtualBox, QEMU, Parallels, bhyve, Apple Virtualization) from CPUID and DMI/SMBIOS strings, and whether the TPM is discrete, firmware, or virtual (tpm_kind). Use format='table' for colored ASCII table output.",
+	}, handleGetVirtualizationStatus)
+
 	// ============================================
 	// System Metrics Tools (Bonus utilities)
 	// ============================================
 
-	mcp.AddTool(server, &mcp.Tool{
+	addTool(server, &mcp.Tool{
 		Name:        "get_cpu_usage",
-		Description: "Returns current system CPU usage percentage, both overall and per-core. Use format='table' for colored ASCII table output with progress bars.",
+		Description: "Returns system CPU usage percentage, both overall and per-core, sampled over interval_ms (default 500ms), together with logical and physical core counts and 1/5/15-minute load averages. The result states its sampling window. Use format='table' for colored ASCII table output with progress bars.",
 	}, handleGetCPUUsage)
 
-	mcp.AddTool(server, &mcp.Tool{
+	addTool(server, &mcp.Tool{
 		Name:        "get_memory",
-		Description: "Returns current system memory usage including total, used, free, and available memory. Use format='table' for colored ASCII table output with progress bars.",
+		Description: "Returns current system memory usage including total, used, free, and available memory. Pass top=N to also list the N processes using the most resident memory. Use format='table' for colored ASCII table output with progress bars.",
 	}, handleGetMemory)
 
-	mcp.AddTool(server, &mcp.Tool{
+	addTool(server, &mcp.Tool{
+		Name:        "get_memory_top",
+		Description: "Returns the top processes by resident memory (RSS, default 10) with human-readable sizes and each process's share of total physical memory. Shared pages are counted in every process that maps them. Use format='table' for colored ASCII table output.",
+	}, handleGetMemoryTop)
+
+	addTool(server, &mcp.Tool{
+		Name:        "get_fd_usage",
+		Description: "Returns open file descriptor usage: open files against the system-wide limit and the per-process ceiling (Linux, macOS), the processes holding the most descriptors (default 10; handles on Windows) with their share of their own RLIMIT_NOFILE soft limit on Linux, and the server's nofile, nproc, memlock, stack, and core ulimits (-1 is unlimited). Without root only the current user's processes are counted; inaccessible reports how many were skipped. Use format='table' for colored ASCII table output.",
+	}, handleGetFDUsage)
+
+	addTool(server, &mcp.Tool{
+		Name:        "get_sensors",
+		Description: "Returns CPU, GPU, and disk temperatures and fan speeds, with each temperature rated ok, high, or critical against the sensor's own limits. Readings that are high or critical are listed as anomalies. Use format='table' for colored ASCII table output.",
+	}, handleGetSensors)
+
+	addTool(server, &mcp.Tool{
+		Name:        "get_gpu_info",
+		Description: "Lists GPUs with vendor, model, PCI address, driver and driver version, dedicated VRAM (or whether memory is shared with the system), and utilization where obtainable: sysfs and lspci on Linux, WMI on Windows, system_profiler and IOAccelerator on macOS, and nvidia-smi for NVIDIA GPUs. Use format='table' for colored ASCII table output.",
+	}, handleGetGPUInfo)
+
+	addTool(server, &mcp.Tool{
 		Name:        "list_processes",
-		Description: "Lists running processes with their PID, name, CPU usage, memory usage, and status. Results are sorted by CPU usage. Use format='table' for colored ASCII table output.",
+		Description: "Lists running processes with their PID, name, user, CPU usage, memory usage, and status. Filter by name substring, user, or minimum CPU/memory; sort by cpu (default), memory, pid, or name; and page through results with offset and limit. 'matched' reports how many processes passed the filters. Use format='table' for colored ASCII table output.",
 	}, handleListProcesses)
 
+	addTool(server, &mcp.Tool{
+		Name:        "stream_metrics",
+		Description: "Samples CPU, memory, and the top processes by CPU every interval_seconds (default 5) for count snapshots (default 12), instead of polling get_cpu_usage and list_processes repeatedly. When the request carries a progress token, each snapshot is sent as a progress notification as soon as it is taken; the tool result contains all snapshots. Use format='table' for colored ASCII output.",
+	}, handleStreamMetrics)
+
+	// ============================================
+	// Resources
+	// ============================================
+
+	server.AddResource(&mcp.Resource{
+		URI:         ScheduleURI,
+		Name:        "schedule",
+		Title:       "Scan schedule",
+		Description: "The daemon's scan schedule: whether it is enabled, its interval and jitter, when the last scan ran and its score, when the next scan is due, and why it is deferred (on battery power or the user presenting), with the power state seen at the last check.",
+		MIMEType:    "application/json",
+	}, handleScheduleResource(opts.Schedule))
+
+	server.RemoveTools(deniedTools(opts.Consent)...)
 	return server
 }
 
-// Run starts the MCP server on stdio
+// ScheduleURI is the resource with the scan schedule's status
+const ScheduleURI = "omnitrust://schedule"
+
+// handleScheduleResource reads the status of the scan schedule
+func handleScheduleResource(s *schedule.Scheduler) mcp.ResourceHandler {
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		data, err := json.MarshalIndent(s.Status(), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{
+			URI:      req.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(data),
+		}}}, nil
+	}
+}
+
+// Run starts the MCP server with the default options
 func Run() error {
-	server := NewMCPServer()
-	return server.Run(context.Background(), &mcp.StdioTransport{})
+	return Serve(context.Background(), DefaultOptions())
+}
+
+// Serve runs the MCP server on the configured transport until the context
+// is cancelled or the client disconnects
+func Serve(ctx context.Context, opts *Options) error {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	server := NewMCPServerWithOptions(opts)
+
+	switch opts.Transport {
+	case "", TransportStdio:
+		return server.Run(ctx, &mcp.StdioTransport{})
+	case TransportHTTP:
+		httpServer := &http.Server{
+			Addr: opts.Address,
+			Handler: mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+				return server
+			}, nil),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			<-ctx.Done()
+			_ = httpServer.Close()
+		}()
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown transport %q (use %s or %s)", opts.Transport, TransportStdio, TransportHTTP)
 }
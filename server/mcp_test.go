@@ -0,0 +1,424 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/agentplexus/posture/schedule"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// connect starts the server on an in-memory transport and returns a client
+// session for it
+func connect(t *testing.T, opts *mcp.ClientOptions) *mcp.ClientSession {
+	t.Helper()
+	return connectWith(t, &Options{}, opts)
+}
+
+// allowSensitive lets tests call SensitiveTools without elicitation
+var allowSensitive = &Options{Consent: map[string]string{ConsentSensitive: ConsentAllow}}
+
+// connectWith is connect with server options
+func connectWith(t *testing.T, serverOpts *Options, opts *mcp.ClientOptions) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := NewMCPServerWithOptions(serverOpts).Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	t.Cleanup(func() { _ = ss.Close() })
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, opts).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	t.Cleanup(func() { _ = cs.Close() })
+	return cs
+}
+
+func TestStreamMetrics_Progress(t *testing.T) {
+	var mu sync.Mutex
+	var progress []*mcp.ProgressNotificationParams
+	cs := connectWith(t, allowSensitive, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			progress = append(progress, req.Params)
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// SetProgressToken does not allocate Meta when it is nil, so set it directly
+	params := &mcp.CallToolParams{
+		Meta:      mcp.Meta{"progressToken": "stream-1"},
+		Name:      "stream_metrics",
+		Arguments: map[string]any{"interval_seconds": 1, "count": 2, "top": 1},
+	}
+	res, err := cs.CallTool(ctx, params)
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if res.IsError {
+		t.Fatalf("stream_metrics failed: %v", res.Content)
+	}
+
+	var snapshots []map[string]any
+	if err := json.Unmarshal([]byte(res.Content[0].(*mcp.TextContent).Text), &snapshots); err != nil {
+		t.Fatalf("result is not a JSON array of snapshots: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Errorf("got %d snapshots, want 2", len(snapshots))
+	}
+
+	// Notifications may trail the result slightly
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		n := len(progress)
+		mu.Unlock()
+		if n >= 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(progress) != 2 {
+		t.Fatalf("got %d progress notifications, want 2", len(progress))
+	}
+	for i, p := range progress {
+		if p.ProgressToken != "stream-1" || p.Progress != float64(i+1) || p.Total != 2 {
+			t.Errorf("progress[%d] = %+v", i, p)
+		}
+		var snap map[string]any
+		if err := json.Unmarshal([]byte(p.Message), &snap); err != nil {
+			t.Errorf("progress[%d] message is not a JSON snapshot: %v", i, err)
+		}
+	}
+}
+
+func TestStreamMetrics_RejectsLongStreams(t *testing.T) {
+	cs := connectWith(t, allowSensitive, nil)
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "stream_metrics",
+		Arguments: map[string]any{"count": maxStreamCount + 1},
+	})
+	if err == nil {
+		t.Errorf("count above the maximum should be rejected, got %+v", res)
+	}
+}
+
+func TestServe_UnknownTransport(t *testing.T) {
+	if err := Serve(context.Background(), &Options{Transport: "carrier-pigeon"}); err == nil {
+		t.Error("Serve should reject an unknown transport")
+	}
+}
+
+func TestTemplateArgument(t *testing.T) {
+	cs := connect(t, nil)
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "get_memory",
+		Arguments: map[string]any{"template": "{{if gt .TotalBytes 0}}ok{{end}}"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if got := res.Content[0].(*mcp.TextContent).Text; got != "ok" {
+		t.Errorf("templated output = %q, want ok", got)
+	}
+}
+
+func TestTemplateArgument_Error(t *testing.T) {
+	cs := connect(t, nil)
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "get_memory",
+		Arguments: map[string]any{"template": "{{.NoSuchField}}"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if !res.IsError {
+		t.Errorf("a template that fails on the result should be a tool error, got %q", res.Content[0].(*mcp.TextContent).Text)
+	}
+}
+
+func TestSecuritySummary_RejectsUnknownSeverity(t *testing.T) {
+	cs := connect(t, nil)
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "get_security_summary",
+		Arguments: map[string]any{"min_severity": "urgent"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "min_severity") {
+		t.Errorf("an unknown min_severity should be rejected, got %+v, %v", res, err)
+	}
+}
+
+func TestLogRequests(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := inspector.NewLogger(&buf, "info", inspector.LogFormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inspector.SetLogger(inspector.SetLogger(logger))
+
+	cs := connect(t, nil)
+	_, err = cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "get_security_summary",
+		Arguments: map[string]any{"min_severity": "urgent"},
+	})
+	if err == nil {
+		t.Fatal("an unknown min_severity should be rejected")
+	}
+	if out := buf.String(); !strings.Contains(out, `level=WARN msg="request failed" method=tools/call`) ||
+		!strings.Contains(out, "tool=get_security_summary") {
+		t.Errorf("failed tool call not logged:\n%s", out)
+	}
+}
+
+func TestDoctorItems(t *testing.T) {
+	items := DoctorItems(context.Background(), &Options{Transport: TransportStdio})
+	if len(items) != 1 || items[0].Status != inspector.DoctorOK {
+		t.Fatalf("DoctorItems(stdio) = %+v", items)
+	}
+	items = DoctorItems(context.Background(), &Options{Transport: TransportHTTP, Address: "127.0.0.1:0"})
+	if len(items) != 2 || items[1].Status != inspector.DoctorOK {
+		t.Errorf("DoctorItems(http) = %+v", items)
+	}
+}
+
+func TestScanSecrets_OptIn(t *testing.T) {
+	ctx := context.Background()
+	hasTool := func(opts *Options) bool {
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		ss, err := NewMCPServerWithOptions(opts).Connect(ctx, serverTransport, nil)
+		if err != nil {
+			t.Fatalf("server connect: %v", err)
+		}
+		defer ss.Close()
+		cs, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(ctx, clientTransport, nil)
+		if err != nil {
+			t.Fatalf("client connect: %v", err)
+		}
+		defer cs.Close()
+		tools, err := cs.ListTools(ctx, nil)
+		if err != nil {
+			t.Fatalf("ListTools: %v", err)
+		}
+		for _, tool := range tools.Tools {
+			if tool.Name == "scan_secrets" {
+				return true
+			}
+		}
+		return false
+	}
+	if hasTool(&Options{}) {
+		t.Error("scan_secrets is registered without opting in")
+	}
+	if !hasTool(&Options{SecretsScan: true}) {
+		t.Error("scan_secrets is not registered with SecretsScan")
+	}
+}
+
+func TestUnsupportedTools(t *testing.T) {
+	// Defender is Windows-only and the kubelet Linux-only, so one of them is
+	// unsupported wherever the test runs
+	name := "get_defender_status"
+	if inspector.IsDefenderSupported() {
+		name = "get_kubelet_security"
+	}
+	ctx := context.Background()
+	listed := func(cs *mcp.ClientSession) bool {
+		tools, err := cs.ListTools(ctx, nil)
+		if err != nil {
+			t.Fatalf("ListTools: %v", err)
+		}
+		for _, tool := range tools.Tools {
+			if tool.Name == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	cs := connect(t, nil)
+	if !listed(cs) {
+		t.Fatalf("%s is not listed", name)
+	}
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: map[string]any{}})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	out, _ := res.StructuredContent.(map[string]any)
+	if res.IsError || out["supported"] != false || out["tool"] != name || out["reason"] == "" {
+		t.Errorf("%s = %v, want supported=false with a reason", name, res.StructuredContent)
+	}
+
+	if listed(connectWith(t, &Options{HideUnsupported: true}, nil)) {
+		t.Errorf("%s is listed with HideUnsupported", name)
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	t.Setenv(inspector.FixtureEnv, filepath.Join("..", "fixtures", "linux"))
+	ctx := context.Background()
+	cs := connect(t, nil)
+
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "list_checks"})
+	if err != nil || res.IsError {
+		t.Fatalf("list_checks = %v, %v", res, err)
+	}
+	data, _ := json.Marshal(res.StructuredContent)
+	var list inspector.CheckListResult
+	if err := json.Unmarshal(data, &list); err != nil || len(list.Checks) != len(inspector.RegisteredChecks()) {
+		t.Fatalf("list_checks = %s, %v", data, err)
+	}
+
+	res, err = cs.CallTool(ctx, &mcp.CallToolParams{Name: "run_check", Arguments: map[string]any{"check": inspector.CheckUptime}})
+	if err != nil || res.IsError {
+		t.Fatalf("run_check = %v, %v", res, err)
+	}
+	out, _ := res.StructuredContent.(map[string]any)
+	result, _ := out["result"].(map[string]any)
+	if out["supported"] != true || result["reboot_pending"] != true {
+		t.Errorf("run_check uptime = %v, want the uptime fixture", res.StructuredContent)
+	}
+
+	// The fixture has no Docker result, so the check is unsupported
+	res, err = cs.CallTool(ctx, &mcp.CallToolParams{Name: "run_check", Arguments: map[string]any{"check": inspector.CheckDocker}})
+	if err != nil || res.IsError {
+		t.Fatalf("run_check = %v, %v", res, err)
+	}
+	if out, _ := res.StructuredContent.(map[string]any); out["supported"] != false || out["reason"] == "" {
+		t.Errorf("run_check docker = %v, want supported=false with a reason", res.StructuredContent)
+	}
+
+	if _, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "run_check", Arguments: map[string]any{"check": "nonexistent"}}); err == nil {
+		t.Error("an unknown check should be rejected")
+	}
+}
+
+func TestExplainArgument(t *testing.T) {
+	t.Setenv(inspector.FixtureEnv, filepath.Join("..", "fixtures", "linux"))
+	cs := connect(t, nil)
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "get_security_summary",
+		Arguments: map[string]any{"explain": true},
+	})
+	if err != nil || res.IsError {
+		t.Fatalf("CallTool = %v, %v", res, err)
+	}
+	data, _ := json.Marshal(res.Meta["provenance"])
+	var provenance []inspector.FieldProvenance
+	if err := json.Unmarshal(data, &provenance); err != nil || len(provenance) == 0 {
+		t.Fatalf("provenance = %s, %v", data, err)
+	}
+	if s := provenance[0].Sources[0]; provenance[0].Field != "summary" || s.Kind != inspector.SourceFixture || !strings.HasSuffix(s.Detail, "summary.json") {
+		t.Errorf("provenance = %+v, want the summary fixture", provenance)
+	}
+
+	res, err = cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "get_security_summary"})
+	if err != nil || res.Meta["provenance"] != nil {
+		t.Errorf("provenance without explain = %v, %v", res.Meta["provenance"], err)
+	}
+}
+
+func TestExplainScore(t *testing.T) {
+	t.Setenv(inspector.FixtureEnv, filepath.Join("..", "fixtures", "linux"))
+	cs := connect(t, nil)
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "explain_score"})
+	if err != nil || res.IsError {
+		t.Fatalf("CallTool = %v, %v", res, err)
+	}
+	data, _ := json.Marshal(res.StructuredContent)
+	var e inspector.ScoreExplanation
+	if err := json.Unmarshal(data, &e); err != nil {
+		t.Fatal(err)
+	}
+	// The fixture passes 8 of its 11 checks
+	if e.Score != 72 || e.EarnedPoints != 200 || e.PossiblePoints != 275 {
+		t.Errorf("score = %d from %v/%v, want 72 from 200/275", e.Score, e.EarnedPoints, e.PossiblePoints)
+	}
+	// Its failures weigh the same, so the most severe finding wins
+	if b := e.BestImprovement; b == nil || b.Check != inspector.CheckUptime || b.Score != 81 || b.Action == "" {
+		t.Errorf("best improvement = %+v, want uptime to 81", b)
+	}
+}
+
+func TestRemediationOverrides(t *testing.T) {
+	// Fixture summaries are served as is, so build one from a check fixture
+	dir := t.TempDir()
+	t.Setenv(inspector.FixtureEnv, dir)
+	t.Setenv(inspector.OnlyChecksEnv, inspector.CheckTPM)
+	t.Setenv(inspector.DisableChecksEnv, "")
+	t.Setenv(inspector.ScanProfileEnv, "")
+	t.Setenv(inspector.BaselineEnv, filepath.Join(dir, "baseline.json"))
+	t.Setenv(inspector.WaiversEnv, filepath.Join(dir, "waivers.json"))
+	t.Setenv(inspector.RemediationsEnv, `{"tpm_missing": "Request a replacement laptop at https://it.example.com"}`)
+	if err := os.WriteFile(filepath.Join(dir, "tpm.json"), []byte(`{"present": false, "type": "none"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cs := connect(t, nil)
+
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "get_security_summary"})
+	if err != nil || res.IsError {
+		t.Fatalf("CallTool = %v, %v", res, err)
+	}
+	data, _ := json.Marshal(res.StructuredContent)
+	var summary inspector.SecuritySummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatal(err)
+	}
+	i := slices.IndexFunc(summary.Findings, func(f inspector.Finding) bool { return f.ID == "tpm_missing" })
+	if i < 0 || summary.Findings[i].Remediation != "Request a replacement laptop at https://it.example.com" {
+		t.Fatalf("findings = %+v, want the override", summary.Findings)
+	}
+
+	// The score explanation suggests the same remediation
+	res, err = cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "explain_score"})
+	if err != nil || res.IsError {
+		t.Fatalf("CallTool = %v, %v", res, err)
+	}
+	data, _ = json.Marshal(res.StructuredContent)
+	var e inspector.ScoreExplanation
+	if err := json.Unmarshal(data, &e); err != nil {
+		t.Fatal(err)
+	}
+	if b := e.BestImprovement; b == nil || b.Action != summary.Findings[i].Remediation {
+		t.Errorf("best improvement = %+v, want the override", b)
+	}
+}
+
+func TestScheduleResource(t *testing.T) {
+	read := func(opts *Options) schedule.Status {
+		t.Helper()
+		res, err := connectWith(t, opts, nil).ReadResource(context.Background(), &mcp.ReadResourceParams{URI: ScheduleURI})
+		if err != nil || len(res.Contents) != 1 {
+			t.Fatalf("ReadResource = %v, %v", res, err)
+		}
+		var st schedule.Status
+		if err := json.Unmarshal([]byte(res.Contents[0].Text), &st); err != nil {
+			t.Fatal(err)
+		}
+		return st
+	}
+
+	if st := read(&Options{}); st.Enabled || st.NextScan != nil {
+		t.Errorf("without a schedule = %+v, want disabled", st)
+	}
+	s := schedule.New(schedule.Config{Interval: 6 * time.Hour, Retry: schedule.DefaultRetry}, func(ctx context.Context) (*inspector.SecuritySummary, error) {
+		return &inspector.SecuritySummary{}, nil
+	})
+	if st := read(&Options{Schedule: s}); !st.Enabled || st.Interval != "6h0m0s" {
+		t.Errorf("schedule = %+v", st)
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/agentplexus/posture/inspector"
+	"github.com/agentplexus/posture/redact"
+)
+
+// redactResults masks identifying values in the results of tool calls that
+// pass redact=true. With always set, every call is treated as if it did,
+// so handlers that send progress notifications redact those too.
+func redactResults(always bool) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			call, ok := req.(*mcp.CallToolRequest)
+			if !ok {
+				return next(ctx, method, req)
+			}
+			var args map[string]any
+			if len(call.Params.Arguments) > 0 {
+				if err := json.Unmarshal(call.Params.Arguments, &args); err != nil {
+					// Leave malformed arguments to the tool's own validation
+					return next(ctx, method, req)
+				}
+			}
+			if always {
+				if args == nil {
+					args = make(map[string]any)
+				}
+				args["redact"] = true
+				call.Params.Arguments, _ = json.Marshal(args)
+			}
+			if enabled, _ := args["redact"].(bool); !enabled {
+				return next(ctx, method, req)
+			}
+
+			r, err := redact.NewFromEnv()
+			if err != nil {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: inspector.ErrorMessage(err)},
+					},
+					IsError: true,
+				}, nil
+			}
+			result, err := next(ctx, method, req)
+			if res, ok := result.(*mcp.CallToolResult); ok && err == nil && res != nil {
+				redactResult(r, res)
+			}
+			return result, err
+		}
+	}
+}
+
+// redactResult masks the values r finds in a result's structured content
+// in the structured and the text content and in the provenance metadata,
+// whose paths and command lines can name users and volumes
+func redactResult(r *redact.Redactor, res *mcp.CallToolResult) {
+	if res.StructuredContent != nil {
+		if raw, err := r.JSON(res.StructuredContent); err == nil {
+			res.StructuredContent = raw
+		}
+	}
+	for _, c := range res.Content {
+		if text, ok := c.(*mcp.TextContent); ok {
+			text.Text = r.String(text.Text)
+		}
+	}
+	if provenance, ok := res.Meta["provenance"]; ok {
+		// Unredacted provenance is dropped rather than returned
+		delete(res.Meta, "provenance")
+		if data, err := json.Marshal(provenance); err == nil {
+			var masked any
+			if json.Unmarshal([]byte(r.String(string(data))), &masked) == nil {
+				res.Meta["provenance"] = masked
+			}
+		}
+	}
+}
+
+// newRedactor returns the redactor for a call that passed redact=true, or
+// nil if it did not
+func newRedactor(enabled bool) *redact.Redactor {
+	if !enabled {
+		return nil
+	}
+	r, err := redact.NewFromEnv()
+	if err != nil {
+		inspector.Logger().Warn("redaction is misconfigured", "err", err)
+		return nil
+	}
+	return r
+}
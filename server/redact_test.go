@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/agentplexus/posture/redact"
+)
+
+func TestRedactOption(t *testing.T) {
+	t.Setenv(redact.SaltEnv, "")
+	host, err := os.Hostname()
+	if err != nil || len(host) < 2 {
+		t.Skip("no usable hostname")
+	}
+	for name, tc := range map[string]struct {
+		opts *Options
+		args map[string]any
+	}{
+		"argument": {&Options{}, map[string]any{"redact": true}},
+		"option":   {&Options{Redact: true}, nil},
+	} {
+		cs := connectWith(t, tc.opts, nil)
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "get_security_summary", Arguments: tc.args})
+		if err != nil {
+			t.Fatalf("%s: CallTool: %v", name, err)
+		}
+		summary, _ := res.StructuredContent.(map[string]any)
+		if summary["hostname"] != redact.Mask {
+			t.Errorf("%s: structured hostname = %v, want %s", name, summary["hostname"], redact.Mask)
+		}
+		if text := res.Content[0].(*mcp.TextContent).Text; strings.Contains(text, `"hostname": "`+host+`"`) {
+			t.Errorf("%s: text content leaks the hostname", name)
+		}
+	}
+}
+
+func TestRedactOption_Off(t *testing.T) {
+	cs := connect(t, nil)
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "get_security_summary"})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if summary, _ := res.StructuredContent.(map[string]any); summary["hostname"] == redact.Mask {
+		t.Error("hostname is redacted without redact=true")
+	}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+// addTool registers a tool with a constrained input schema (see
+// argConstraints) and an output schema inferred from the handler's result
+// type. Arguments the schema cannot express are checked before the handler
+// runs. A successful call returns the result as structured content, typed
+// per the output schema, next to the text rendered in the requested format;
+// failed calls carry only the error text.
+func addTool[In, Out any](server *mcp.Server, tool *mcp.Tool, handler mcp.ToolHandlerFor[In, Out]) {
+	if tool.InputSchema == nil {
+		tool.InputSchema = inputSchema[In]()
+	}
+	if tool.OutputSchema == nil {
+		tool.OutputSchema = outputSchema[Out](tool.Name)
+	}
+	mcp.AddTool(server, tool, func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, any, error) {
+		if err := validateArgs(args); err != nil {
+			return nil, nil, &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: err.Error()}
+		}
+		res, out, err := handler(ctx, req, args)
+		if err != nil || res != nil && res.IsError || reflect.ValueOf(&out).Elem().IsZero() {
+			return res, nil, err
+		}
+		return res, out, nil
+	})
+}
+
+// UnsupportedResult is the result of a tool whose check does not exist on
+// this platform
+type UnsupportedResult struct {
+	Tool      string `json:"tool"`
+	Supported bool   `json:"supported"`
+	Platform  string `json:"platform"`
+	Reason    string `json:"reason"`
+}
+
+// addPlatformTool registers a tool that only works on some platforms. Where
+// supported is false the tool is still listed, so clients see the same tools
+// on every OS, but every call returns an UnsupportedResult; with
+// HideUnsupported it is not registered at all.
+func addPlatformTool[In, Out any](server *mcp.Server, opts *Options, supported bool, tool *mcp.Tool, handler mcp.ToolHandlerFor[In, Out]) {
+	if supported {
+		addTool(server, tool, handler)
+		return
+	}
+	if opts.HideUnsupported {
+		return
+	}
+	result := &UnsupportedResult{
+		Tool:     tool.Name,
+		Platform: runtime.GOOS,
+		Reason:   fmt.Sprintf("%s is not supported on %s", tool.Name, runtime.GOOS),
+	}
+	tool.Description = fmt.Sprintf("Not supported on %s; every call returns supported=false. ", runtime.GOOS) + tool.Description
+	addTool(server, tool, func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, *UnsupportedResult, error) {
+		return nil, result, nil
+	})
+}
+
+// outputSchema generates the schema of a tool's result type, a pointer to
+// a struct, the way the published result schemas are generated
+func outputSchema[Out any](name string) *jsonschema.Schema {
+	t := reflect.TypeFor[Out]()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	schema, err := inspector.SchemaFor(t, name)
+	if err != nil {
+		panic(fmt.Sprintf("output schema of %v: %v", t, err))
+	}
+	return schema
+}
+
+// collect runs a probe, recording the provenance of its result when
+// explain is set
+func collect[T any](ctx context.Context, explain bool, probe func(context.Context) (T, error)) (T, []inspector.FieldProvenance, error) {
+	if explain {
+		return inspector.Explain(func() (T, error) { return probe(ctx) })
+	}
+	result, err := probe(ctx)
+	return result, nil, err
+}
+
+// provenanceMeta adds the provenance of an explained result to the tool
+// response metadata
+func provenanceMeta(meta map[string]any, provenance []inspector.FieldProvenance) map[string]any {
+	if provenance == nil {
+		return meta
+	}
+	if meta == nil {
+		meta = make(map[string]any)
+	}
+	meta["provenance"] = provenance
+	return meta
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+// maxToolLimit bounds the limit and top arguments of process listings
+const maxToolLimit = 1000
+
+// toolFormats are the output formats tools accept
+var toolFormats = []string{inspector.FormatJSON, inspector.FormatTable, inspector.FormatCSV, inspector.FormatNDJSON, inspector.FormatTemplate}
+
+// argConstraints narrow the input schemas inferred from the argument
+// structs, by JSON property name. The SDK validates every call against its
+// tool's schema, so out-of-range arguments are rejected with an invalid
+// params error before any probe runs.
+var argConstraints = map[string]func(*jsonschema.Schema){
+	"format":           enumOf(toolFormats...),
+	"check":            enumOf(inspector.RegisteredChecks()...),
+	"min_severity":     enumOf(inspector.Severities...),
+	"sort":             enumOf(inspector.ProcessSortCPU, inspector.ProcessSortMemory, "mem", inspector.ProcessSortPID, inspector.ProcessSortName),
+	"fields":           itemsOf(inspector.ProcessFields...),
+	"limit":            between(0, maxToolLimit),
+	"top":              between(0, maxToolLimit),
+	"offset":           between(0, -1),
+	"interval_ms":      between(0, float64(inspector.MaxCPUSampleInterval.Milliseconds())),
+	"interval_seconds": between(0, maxStreamInterval),
+	"count":            between(0, maxStreamCount),
+	"min_cpu":          between(0, -1),
+	"min_memory":       between(0, 100),
+}
+
+// enumOf restricts a string property to the given values
+func enumOf(values ...string) func(*jsonschema.Schema) {
+	return func(s *jsonschema.Schema) {
+		s.Enum = make([]any, len(values))
+		for i, v := range values {
+			s.Enum[i] = v
+		}
+	}
+}
+
+// itemsOf restricts the items of an array property to the given values
+func itemsOf(values ...string) func(*jsonschema.Schema) {
+	return func(s *jsonschema.Schema) {
+		if s.Items != nil {
+			enumOf(values...)(s.Items)
+		}
+	}
+}
+
+// between bounds a numeric property; a negative maximum leaves it unbounded
+func between(minimum, maximum float64) func(*jsonschema.Schema) {
+	return func(s *jsonschema.Schema) {
+		s.Minimum = &minimum
+		if maximum >= 0 {
+			s.Maximum = &maximum
+		}
+	}
+}
+
+// inputSchema infers the input schema of an argument struct and applies
+// argConstraints to its properties
+func inputSchema[In any]() *jsonschema.Schema {
+	schema, err := jsonschema.For[In](nil)
+	if err != nil {
+		panic(fmt.Sprintf("input schema of %T: %v", *new(In), err))
+	}
+	for name, prop := range schema.Properties {
+		if constrain, ok := argConstraints[name]; ok {
+			constrain(prop)
+		}
+	}
+	return schema
+}
+
+// validateArgs checks the output format and template arguments, which every
+// tool shares: format=template needs a template, and the template must parse
+func validateArgs(args any) error {
+	v := reflect.Indirect(reflect.ValueOf(args))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	var format, tmpl string
+	if f := v.FieldByName("Format"); f.IsValid() && f.Kind() == reflect.String {
+		format = f.String()
+	}
+	if f := v.FieldByName("Template"); f.IsValid() && f.Kind() == reflect.String {
+		tmpl = f.String()
+	}
+	if strings.EqualFold(format, inspector.FormatTemplate) && tmpl == "" {
+		return fmt.Errorf("format=%s requires a template", inspector.FormatTemplate)
+	}
+	if tmpl != "" {
+		if _, err := inspector.ParseTemplate(tmpl); err != nil {
+			return fmt.Errorf("invalid template: %v", err)
+		}
+	}
+	return nil
+}
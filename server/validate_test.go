@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+func TestToolSchemas_Constrained(t *testing.T) {
+	cs := connect(t, nil)
+	tools, err := cs.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	for _, tool := range tools.Tools {
+		schema, ok := tool.InputSchema.(map[string]any)
+		if !ok {
+			t.Fatalf("%s: input schema is %T", tool.Name, tool.InputSchema)
+		}
+		props, _ := schema["properties"].(map[string]any)
+		if format, ok := props["format"].(map[string]any); ok {
+			enum, _ := format["enum"].([]any)
+			if !slices.Contains(enum, any("ndjson")) {
+				t.Errorf("%s: format enum = %v", tool.Name, format["enum"])
+			}
+		}
+		if limit, ok := props["limit"].(map[string]any); ok && limit["maximum"] != float64(maxToolLimit) {
+			t.Errorf("%s: limit maximum = %v, want %d", tool.Name, limit["maximum"], maxToolLimit)
+		}
+	}
+}
+
+func TestToolArguments_Rejected(t *testing.T) {
+	cs := connectWith(t, allowSensitive, nil)
+	for _, tc := range []struct {
+		tool string
+		args map[string]any
+	}{
+		{"get_memory", map[string]any{"format": "yaml"}},
+		{"get_memory", map[string]any{"format": "template"}},
+		{"get_memory", map[string]any{"template": "{{.Missing"}},
+		{"list_processes", map[string]any{"limit": maxToolLimit + 1}},
+		{"list_processes", map[string]any{"sort": "age"}},
+		{"list_processes", map[string]any{"fields": []string{"threads"}}},
+	} {
+		_, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: tc.tool, Arguments: tc.args})
+		var rpcErr *jsonrpc.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != jsonrpc.CodeInvalidParams {
+			t.Errorf("%s %v: err = %v, want invalid params", tc.tool, tc.args, err)
+		}
+	}
+}
+
+func TestToolResults_Structured(t *testing.T) {
+	cs := connect(t, nil)
+	tools, err := cs.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	for _, tool := range tools.Tools {
+		schema, _ := tool.OutputSchema.(map[string]any)
+		if schema["type"] != "object" {
+			t.Errorf("%s: output schema type = %v, want object", tool.Name, schema["type"])
+			continue
+		}
+		args := map[string]any{"format": "table"}
+		switch tool.Name {
+		case "stream_metrics":
+			args = map[string]any{"format": "table", "count": 1, "interval_seconds": 1}
+		case "run_check":
+			args = map[string]any{"format": "table", "check": inspector.CheckUptime}
+		}
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: tool.Name, Arguments: args})
+		if err != nil {
+			t.Errorf("%s: %v", tool.Name, err)
+			continue
+		}
+		if res.IsError {
+			if res.StructuredContent != nil {
+				t.Errorf("%s: failed call has structured content", tool.Name)
+			}
+			continue
+		}
+		if _, ok := res.StructuredContent.(map[string]any); !ok {
+			t.Errorf("%s: structured content = %T, want an object", tool.Name, res.StructuredContent)
+		}
+	}
+}
+
+func TestSecuritySummary_StructuredScore(t *testing.T) {
+	cs := connect(t, nil)
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "get_security_summary"})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	summary, _ := res.StructuredContent.(map[string]any)
+	if _, ok := summary["overall_score"].(float64); !ok {
+		t.Errorf("overall_score = %#v, want a number", summary["overall_score"])
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/agentplexus/posture/inspector"
+)
+
+// FormatStatus formats a service status in the specified format
+func FormatStatus(status *Status, format string) string {
+	return inspector.FormatOutputWith(status, func(st inspector.Styler) string {
+		return formatStatusTable(st, status)
+	}, format)
+}
+
+// formatStatusTable formats a service status as a colored table
+func formatStatusTable(st inspector.Styler, status *Status) string {
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(st.Header(inspector.IconShield + " Server Service"))
+	sb.WriteString("\n")
+	sb.WriteString(st.Muted(strings.Repeat("─", 50)))
+	sb.WriteString("\n\n")
+
+	sb.WriteString(st.TableTop(14, 36))
+	sb.WriteString("\n")
+	row := func(name, value string) {
+		if value == "" {
+			return
+		}
+		sb.WriteString(st.TableRowColored(inspector.PadRight(name, 14), inspector.PadRight(value, 36)))
+		sb.WriteString("\n")
+	}
+	row("Name", status.Name)
+	row("Manager", status.Manager)
+	row("Installed", st.BoolToStatusColored(status.Installed))
+	if status.Installed {
+		running := st.Danger(inspector.IconCross + " " + status.State)
+		if status.Running {
+			running = st.Success(inspector.IconCheck + " " + status.State)
+		}
+		row("State", running)
+	}
+	if status.PID > 0 {
+		row("PID", strconv.Itoa(status.PID))
+	}
+	row("Definition", status.Path)
+	sb.WriteString(st.TableBottom(14, 36))
+	sb.WriteString("\n")
+	return sb.String()
+}
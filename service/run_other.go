@@ -0,0 +1,11 @@
+//go:build !windows
+
+package service
+
+import "context"
+
+// runService runs the server directly; only Windows services need to talk
+// to the service manager
+func runService(ctx context.Context, _ string, run func(context.Context) error) error {
+	return run(ctx)
+}
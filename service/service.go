@@ -0,0 +1,294 @@
+// Package service registers the omnitrust MCP server with the operating
+// system's service manager: launchd on macOS, the Service Control Manager
+// on Windows, and systemd on Linux. The installed service runs
+// "posture serve" over the HTTP transport with the privileges probes need
+// for complete results (root or LocalSystem).
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Name is the service name used when none is given
+const Name = "omnitrust"
+
+// Label is the launchd label of the service
+const Label = "com.agentplexus.omnitrust"
+
+// ErrUnsupported is returned on platforms without a supported service manager
+var ErrUnsupported = errors.New("service management is not supported on this platform")
+
+// ErrNotInstalled is returned when uninstalling a service that does not exist
+var ErrNotInstalled = errors.New("service is not installed")
+
+// ErrExposed is returned when installing a service whose HTTP server would
+// listen beyond loopback without Config.AllowRemote
+var ErrExposed = errors.New("the service would serve MCP beyond loopback")
+
+// namePattern is what a service name may contain; the name becomes part of
+// the unit file and plist paths
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9._-]*$`)
+
+// Config describes the service to install
+type Config struct {
+	// Name is the service name (default Name); on macOS it is the last
+	// element of the launchd label
+	Name string
+	// Executable is the absolute path of the posture binary
+	Executable string
+	// ConfigFile is the absolute path of the config file the service reads,
+	// if any
+	ConfigFile string
+	// Address is the address the HTTP server listens on ("" for the
+	// loopback default)
+	Address string
+	// AllowRemote allows an Address beyond loopback. The server does not
+	// authenticate clients, so anyone who can reach it can run its tools.
+	AllowRemote bool
+	// Args are the arguments the service is started with
+	Args []string
+	// LogDir is where launchd writes the server's output; Windows logs to
+	// the Event Log and systemd to the journal
+	LogDir string
+}
+
+// Status reports whether the service is installed and running
+type Status struct {
+	Name      string `json:"name"`
+	Manager   string `json:"manager"`
+	Installed bool   `json:"installed"`
+	Running   bool   `json:"running"`
+	PID       int    `json:"pid,omitempty"`
+	// State is the service manager's own state name, e.g. "running",
+	// "active", or "stopped"
+	State string `json:"state,omitempty"`
+	// Path is the service definition: plist, unit file, or binary path
+	Path string `json:"path,omitempty"`
+}
+
+// NewConfig returns the config for a service that runs this executable's
+// serve command over HTTP on address, with an optional config file
+func NewConfig(name, address, configPath string) (*Config, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = Name
+	}
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	args := []string{"serve", "--transport", "http"}
+	if name != Name {
+		args = append(args, "--service-name", name)
+	}
+	if address != "" {
+		args = append(args, "--address", address)
+	}
+	if configPath != "" {
+		if configPath, err = filepath.Abs(configPath); err != nil {
+			return nil, err
+		}
+		args = append(args, "--config", configPath)
+	}
+	return &Config{Name: name, Executable: exe, ConfigFile: configPath, Address: address, Args: args, LogDir: defaultLogDir}, nil
+}
+
+// ValidateName checks a service name: letters, digits, ".", "_", and "-",
+// not starting with "." or "-"
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid service name %q: use letters, digits, '.', '_', and '-', starting with a letter, digit, or '_'", name)
+	}
+	return nil
+}
+
+// loopbackAddress reports whether a listen address is on loopback only
+func loopbackAddress(address string) bool {
+	if address == "" {
+		return true
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Install registers and starts the service. The service runs with full
+// privileges, so its executable and config file must not be replaceable by
+// other users (on macOS and Linux: owned by root and not group- or
+// world-writable, as are their directories), and it only listens beyond
+// loopback with AllowRemote.
+func Install(c *Config) error {
+	if c.Name == "" {
+		c.Name = Name
+	}
+	if err := ValidateName(c.Name); err != nil {
+		return err
+	}
+	if !filepath.IsAbs(c.Executable) {
+		return fmt.Errorf("service executable %q must be an absolute path", c.Executable)
+	}
+	if !c.AllowRemote && !loopbackAddress(c.Address) {
+		return fmt.Errorf("%w: %s is reachable from other machines and the server does not authenticate clients; listen on 127.0.0.1 or allow it explicitly", ErrExposed, c.Address)
+	}
+	return install(c)
+}
+
+// Uninstall stops and removes the service
+func Uninstall(name string) error {
+	if name == "" {
+		name = Name
+	}
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	return uninstall(name)
+}
+
+// Query returns the status of the service
+func Query(name string) (*Status, error) {
+	if name == "" {
+		name = Name
+	}
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	return query(name)
+}
+
+// Run runs the server. When the process was started by the Windows Service
+// Control Manager it reports its state to the manager and cancels ctx when
+// the service is stopped; everywhere else it simply calls run.
+func Run(ctx context.Context, name string, run func(context.Context) error) error {
+	if name == "" {
+		name = Name
+	}
+	return runService(ctx, name, run)
+}
+
+// launchdLabel returns the launchd label for a service name
+func launchdLabel(name string) string {
+	if name == Name {
+		return Label
+	}
+	return "com.agentplexus." + name
+}
+
+// launchdPlist renders the launchd daemon definition. The daemon starts at
+// boot, is restarted if it exits, and logs to LogDir.
+func launchdPlist(c *Config) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	fmt.Fprintf(&sb, "\t<key>Label</key>\n\t<string>%s</string>\n", xmlEscape(launchdLabel(c.Name)))
+	sb.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range append([]string{c.Executable}, c.Args...) {
+		fmt.Fprintf(&sb, "\t\t<string>%s</string>\n", xmlEscape(arg))
+	}
+	sb.WriteString("\t</array>\n")
+	sb.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	sb.WriteString("\t<key>KeepAlive</key>\n\t<true/>\n")
+	if c.LogDir != "" {
+		log := filepath.Join(c.LogDir, c.Name+".log")
+		fmt.Fprintf(&sb, "\t<key>StandardOutPath</key>\n\t<string>%s</string>\n", xmlEscape(log))
+		fmt.Fprintf(&sb, "\t<key>StandardErrorPath</key>\n\t<string>%s</string>\n", xmlEscape(log))
+	}
+	sb.WriteString("</dict>\n</plist>\n")
+	return sb.String()
+}
+
+// xmlEscape escapes text for a plist string element
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// parseLaunchctlPrint reads the state and pid from "launchctl print" output
+func parseLaunchctlPrint(out string, st *Status) {
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " = ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "state":
+			if st.State == "" {
+				st.State = value
+			}
+		case "pid":
+			if st.PID == 0 {
+				_, _ = fmt.Sscan(value, &st.PID)
+			}
+		}
+	}
+	st.Running = st.State == "running"
+}
+
+// systemdUnit renders the systemd unit. Output goes to the journal.
+func systemdUnit(c *Config) string {
+	args := make([]string, 0, len(c.Args)+1)
+	for _, arg := range append([]string{c.Executable}, c.Args...) {
+		args = append(args, systemdQuote(arg))
+	}
+	return fmt.Sprintf(`[Unit]
+Description=omnitrust security posture MCP server
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+ExecStart=%s
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=multi-user.target
+`, strings.Join(args, " "))
+}
+
+// systemdQuote quotes an ExecStart argument if it contains spaces or quotes
+func systemdQuote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'\\") {
+		return arg
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
+
+// parseSystemctlShow reads "systemctl show" properties into a status
+func parseSystemctlShow(out string, st *Status) {
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "LoadState":
+			st.Installed = value == "loaded"
+		case "ActiveState":
+			st.State = value
+		case "MainPID":
+			_, _ = fmt.Sscan(value, &st.PID)
+		case "FragmentPath":
+			st.Path = value
+		}
+	}
+	st.Running = st.State == "active"
+}
@@ -0,0 +1,72 @@
+//go:build darwin
+
+package service
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// defaultLogDir is where the launchd daemon's output is written
+const defaultLogDir = "/var/log/omnitrust"
+
+// plistPath returns where the daemon definition is installed
+func plistPath(name string) string {
+	return filepath.Join("/Library/LaunchDaemons", launchdLabel(name)+".plist")
+}
+
+// install writes the launchd daemon definition and loads it into the
+// system domain, so it runs as root at boot
+func install(c *Config) error {
+	if err := requireRoot(); err != nil {
+		return err
+	}
+	if err := checkServiceFiles(c); err != nil {
+		return err
+	}
+	if c.LogDir != "" {
+		if err := os.MkdirAll(c.LogDir, 0o750); err != nil {
+			return err
+		}
+	}
+	path := plistPath(c.Name)
+	if err := os.WriteFile(path, []byte(launchdPlist(c)), 0o644); err != nil { // #nosec G306 -- launchd requires world-readable plists
+		return err
+	}
+	_, err := runTool("launchctl", "bootstrap", "system", path)
+	return err
+}
+
+// uninstall unloads the daemon and removes its definition
+func uninstall(name string) error {
+	path := plistPath(name)
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return ErrNotInstalled
+	}
+	if err := requireRoot(); err != nil {
+		return err
+	}
+	// bootout fails if the daemon is not loaded, which is fine here
+	_, _ = runTool("launchctl", "bootout", "system/"+launchdLabel(name))
+	return os.Remove(path)
+}
+
+// query reads the daemon's state from launchctl
+func query(name string) (*Status, error) {
+	st := &Status{Name: launchdLabel(name), Manager: "launchd", Path: plistPath(name)}
+	if _, err := os.Stat(st.Path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return st, nil
+		}
+		return nil, err
+	}
+	st.Installed = true
+	if out, err := runTool("launchctl", "print", "system/"+st.Name); err == nil {
+		parseLaunchctlPrint(out, st)
+	} else {
+		st.State = "not loaded"
+	}
+	return st, nil
+}
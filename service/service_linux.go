@@ -0,0 +1,74 @@
+//go:build linux
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// defaultLogDir is empty: systemd sends the server's output to the journal
+const defaultLogDir = ""
+
+// unitPath returns where the systemd unit is installed
+func unitPath(name string) string {
+	return filepath.Join("/etc/systemd/system", name+".service")
+}
+
+// install writes a systemd unit and enables and starts it
+func install(c *Config) error {
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return fmt.Errorf("%w: systemctl not found", ErrUnsupported)
+	}
+	if err := requireRoot(); err != nil {
+		return err
+	}
+	if err := checkServiceFiles(c); err != nil {
+		return err
+	}
+	if err := os.WriteFile(unitPath(c.Name), []byte(systemdUnit(c)), 0o644); err != nil { // #nosec G306 -- unit files are world-readable
+		return err
+	}
+	if _, err := runTool("systemctl", "daemon-reload"); err != nil {
+		return err
+	}
+	_, err := runTool("systemctl", "enable", "--now", c.Name+".service")
+	return err
+}
+
+// uninstall stops and disables the unit and removes it
+func uninstall(name string) error {
+	path := unitPath(name)
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return ErrNotInstalled
+	}
+	if err := requireRoot(); err != nil {
+		return err
+	}
+	if _, err := runTool("systemctl", "disable", "--now", name+".service"); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	_, err := runTool("systemctl", "daemon-reload")
+	return err
+}
+
+// query reads the unit's state from systemctl
+func query(name string) (*Status, error) {
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return nil, fmt.Errorf("%w: systemctl not found", ErrUnsupported)
+	}
+	st := &Status{Name: name, Manager: "systemd"}
+	out, err := runTool("systemctl", "show", name+".service", "--property=LoadState,ActiveState,MainPID,FragmentPath")
+	if err != nil {
+		return nil, err
+	}
+	parseSystemctlShow(out, st)
+	return st, nil
+}
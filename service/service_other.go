@@ -0,0 +1,18 @@
+//go:build !darwin && !linux && !windows
+
+package service
+
+// defaultLogDir is unused on platforms without a service manager
+const defaultLogDir = ""
+
+func install(*Config) error {
+	return ErrUnsupported
+}
+
+func uninstall(string) error {
+	return ErrUnsupported
+}
+
+func query(string) (*Status, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewConfig(t *testing.T) {
+	c, err := NewConfig("", "0.0.0.0:9000", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"serve", "--transport", "http", "--address", "0.0.0.0:9000"}
+	if c.Name != Name || !slices.Equal(c.Args, want) {
+		t.Errorf("config = %+v, want args %q", c, want)
+	}
+
+	c, err = NewConfig("posture-test", "", "testdata/config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(c.Args, "--service-name") || !strings.HasSuffix(c.Args[len(c.Args)-1], "/testdata/config.yaml") {
+		t.Errorf("args = %q, want the service name and an absolute config path", c.Args)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"omnitrust", "posture-test", "omni_trust.2"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "../evil", "a/b", "a b", "-x", ".hidden", "x\ny"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) accepted", name)
+		}
+	}
+	if _, err := NewConfig("../../tmp/x", "", ""); err == nil {
+		t.Error("NewConfig accepted a path as the name")
+	}
+}
+
+func TestInstall_Exposed(t *testing.T) {
+	for _, address := range []string{"0.0.0.0:9000", ":9000", "192.0.2.1:8080", "[::]:8080"} {
+		err := Install(&Config{Executable: "/usr/local/bin/posture", Address: address})
+		if !errors.Is(err, ErrExposed) {
+			t.Errorf("Install(%q) = %v, want ErrExposed", address, err)
+		}
+	}
+	for _, address := range []string{"", "127.0.0.1:9000", "localhost:8080", "[::1]:8080"} {
+		if !loopbackAddress(address) {
+			t.Errorf("loopbackAddress(%q) = false", address)
+		}
+	}
+}
+
+func TestLaunchdPlist(t *testing.T) {
+	plist := launchdPlist(&Config{
+		Name:       Name,
+		Executable: "/usr/local/bin/posture",
+		Args:       []string{"serve", "--config", "/etc/a&b.yaml"},
+		LogDir:     "/var/log/omnitrust",
+	})
+	for _, want := range []string{
+		"<string>com.agentplexus.omnitrust</string>",
+		"<string>/usr/local/bin/posture</string>\n\t\t<string>serve</string>",
+		"<string>/etc/a&amp;b.yaml</string>",
+		"<key>KeepAlive</key>",
+		"<string>/var/log/omnitrust/omnitrust.log</string>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("plist missing %q:\n%s", want, plist)
+		}
+	}
+	if got := launchdLabel("posture-test"); got != "com.agentplexus.posture-test" {
+		t.Errorf("launchdLabel = %q", got)
+	}
+}
+
+func TestSystemdUnit(t *testing.T) {
+	unit := systemdUnit(&Config{
+		Executable: "/opt/omni trust/posture",
+		Args:       []string{"serve", "--transport", "http"},
+	})
+	if !strings.Contains(unit, `ExecStart="/opt/omni trust/posture" serve --transport http`+"\n") {
+		t.Errorf("unit ExecStart not quoted:\n%s", unit)
+	}
+	if !strings.Contains(unit, "WantedBy=multi-user.target") {
+		t.Errorf("unit is not enabled at boot:\n%s", unit)
+	}
+}
+
+func TestParseLaunchctlPrint(t *testing.T) {
+	out := `system/com.agentplexus.omnitrust = {
+	active count = 1
+	path = /Library/LaunchDaemons/com.agentplexus.omnitrust.plist
+	state = running
+	program = /usr/local/bin/posture
+	pid = 412
+	endpoints = {
+		state = active
+	}
+}`
+	st := &Status{}
+	parseLaunchctlPrint(out, st)
+	if !st.Running || st.State != "running" || st.PID != 412 {
+		t.Errorf("status = %+v", st)
+	}
+}
+
+func TestParseSystemctlShow(t *testing.T) {
+	tests := []struct {
+		name      string
+		out       string
+		installed bool
+		running   bool
+		pid       int
+	}{
+		{"running", "LoadState=loaded\nActiveState=active\nMainPID=981\nFragmentPath=/etc/systemd/system/omnitrust.service\n", true, true, 981},
+		{"stopped", "LoadState=loaded\nActiveState=inactive\nMainPID=0\n", true, false, 0},
+		{"missing", "LoadState=not-found\nActiveState=inactive\nMainPID=0\nFragmentPath=\n", false, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := &Status{}
+			parseSystemctlShow(tt.out, st)
+			if st.Installed != tt.installed || st.Running != tt.running || st.PID != tt.pid {
+				t.Errorf("status = %+v", st)
+			}
+		})
+	}
+}
+
+func TestFormatStatus(t *testing.T) {
+	st := &Status{Name: Name, Manager: "systemd", Installed: true, Running: true, State: "active", PID: 42}
+	if out := FormatStatus(st, "json"); !strings.Contains(out, `"running": true`) {
+		t.Errorf("json = %s", out)
+	}
+	if out := FormatStatus(st, "table"); !strings.Contains(out, "active") || !strings.Contains(out, "42") {
+		t.Errorf("table = %s", out)
+	}
+}
@@ -0,0 +1,187 @@
+//go:build windows
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// defaultLogDir is empty: the service logs to the Windows Event Log
+const defaultLogDir = ""
+
+// install creates an automatic-start service running as LocalSystem that
+// is restarted if it fails, registers its Event Log source, and starts it
+func install(c *Config) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connecting to the service manager (run from an elevated prompt): %w", err)
+	}
+	defer func() { _ = m.Disconnect() }()
+
+	s, err := m.CreateService(c.Name, c.Executable, mgr.Config{
+		DisplayName: "omnitrust security posture server",
+		Description: "Serves omnitrust security posture checks over MCP (HTTP transport).",
+		StartType:   mgr.StartAutomatic,
+	}, c.Args...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = s.Close() }()
+	_ = s.SetRecoveryActions([]mgr.RecoveryAction{{Type: mgr.ServiceRestart, Delay: 5 * time.Second}}, 24*60*60)
+
+	if err := eventlog.InstallAsEventCreate(c.Name, eventlog.Error|eventlog.Warning|eventlog.Info); err != nil {
+		_ = s.Delete()
+		return fmt.Errorf("registering the event log source: %w", err)
+	}
+	return s.Start()
+}
+
+// uninstall stops and deletes the service and its Event Log source
+func uninstall(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connecting to the service manager (run from an elevated prompt): %w", err)
+	}
+	defer func() { _ = m.Disconnect() }()
+
+	s, err := m.OpenService(name)
+	if errors.Is(err, windows.ERROR_SERVICE_DOES_NOT_EXIST) {
+		return ErrNotInstalled
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = s.Close() }()
+	// Control fails if the service is already stopped, which is fine here
+	_, _ = s.Control(svc.Stop)
+	if err := s.Delete(); err != nil {
+		return err
+	}
+	_ = eventlog.Remove(name)
+	return nil
+}
+
+// query reads the service state from the service manager
+func query(name string) (*Status, error) {
+	st := &Status{Name: name, Manager: "scm"}
+	m, err := mgr.Connect()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = m.Disconnect() }()
+
+	s, err := m.OpenService(name)
+	if errors.Is(err, windows.ERROR_SERVICE_DOES_NOT_EXIST) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = s.Close() }()
+	st.Installed = true
+	if cfg, err := s.Config(); err == nil {
+		st.Path = cfg.BinaryPathName
+	}
+	status, err := s.Query()
+	if err != nil {
+		return nil, err
+	}
+	st.State = stateNames[status.State]
+	st.Running = status.State == svc.Running
+	st.PID = int(status.ProcessId)
+	return st, nil
+}
+
+// stateNames names service states
+var stateNames = map[svc.State]string{
+	svc.Stopped:         "stopped",
+	svc.StartPending:    "start_pending",
+	svc.StopPending:     "stop_pending",
+	svc.Running:         "running",
+	svc.ContinuePending: "continue_pending",
+	svc.PausePending:    "pause_pending",
+	svc.Paused:          "paused",
+}
+
+// runService runs the server under the Service Control Manager when the
+// process was started as a service, and directly otherwise
+func runService(ctx context.Context, name string, run func(context.Context) error) error {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return run(ctx)
+	}
+	h := &handler{ctx: ctx, run: run}
+	if elog, err := eventlog.Open(name); err == nil {
+		h.elog = elog
+		defer func() { _ = elog.Close() }()
+	}
+	if err := svc.Run(name, h); err != nil {
+		return err
+	}
+	return h.err
+}
+
+// handler is the svc.Handler that runs the server
+type handler struct {
+	ctx  context.Context
+	run  func(context.Context) error
+	elog *eventlog.Log
+	err  error
+}
+
+// Execute starts the server and stops it when the service manager asks
+func (h *handler) Execute(_ []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	ctx, cancel := context.WithCancel(h.ctx)
+	defer cancel()
+	status <- svc.Status{State: svc.StartPending}
+	done := make(chan error, 1)
+	go func() { done <- h.run(ctx) }()
+	status <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	h.info("omnitrust server started")
+
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				h.err = err
+				h.error("omnitrust server failed: " + err.Error())
+				return false, 1
+			}
+			return false, 0
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				status <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				cancel()
+				select {
+				case <-done:
+				case <-time.After(10 * time.Second):
+				}
+				h.info("omnitrust server stopped")
+				return false, 0
+			}
+		}
+	}
+}
+
+func (h *handler) info(msg string) {
+	if h.elog != nil {
+		_ = h.elog.Info(1, msg)
+	}
+}
+
+func (h *handler) error(msg string) {
+	if h.elog != nil {
+		_ = h.elog.Error(1, msg)
+	}
+}
@@ -0,0 +1,78 @@
+//go:build darwin || linux
+
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// runTool runs a service manager command, including its output in the error
+func runTool(name string, args ...string) (string, error) {
+	// #nosec G204 -- launchctl and systemctl with arguments we build
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			return "", fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+		}
+		return "", fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, msg)
+	}
+	return string(out), nil
+}
+
+// requireRoot fails unless the process runs as root, which installing a
+// system service needs
+func requireRoot() error {
+	if os.Geteuid() != 0 {
+		return errors.New("installing a system service requires root; re-run with sudo")
+	}
+	return nil
+}
+
+// checkServiceFiles fails unless the executable and config file of a service
+// that runs as root can only be changed by root
+func checkServiceFiles(c *Config) error {
+	for _, path := range []string{c.Executable, c.ConfigFile} {
+		if path == "" {
+			continue
+		}
+		if err := checkRootOwned(path); err != nil {
+			return fmt.Errorf("%w; the service runs it as root, so copy it to a root-owned location such as /usr/local/bin or /etc", err)
+		}
+	}
+	return nil
+}
+
+// checkRootOwned fails unless path and every directory above it are owned
+// by root and not writable by group or others
+func checkRootOwned(path string) error {
+	path, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+	for p := path; ; p = filepath.Dir(p) {
+		info, err := os.Stat(p)
+		if err != nil {
+			return err
+		}
+		st, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			return fmt.Errorf("cannot read the owner of %s", p)
+		}
+		switch {
+		case st.Uid != 0:
+			return fmt.Errorf("%s is not owned by root", p)
+		case info.Mode().Perm()&0o022 != 0:
+			return fmt.Errorf("%s is writable by group or others", p)
+		}
+		if parent := filepath.Dir(p); parent == p {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,34 @@
+//go:build darwin || linux
+
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckRootOwned(t *testing.T) {
+	if err := checkRootOwned("/"); err != nil {
+		t.Errorf("checkRootOwned(/) = %v", err)
+	}
+
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "posture")
+	if err := os.WriteFile(exe, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if os.Geteuid() != 0 {
+		if err := checkServiceFiles(&Config{Executable: exe}); err == nil || !strings.Contains(err.Error(), "not owned by root") {
+			t.Errorf("checkServiceFiles = %v, want not owned by root", err)
+		}
+		return
+	}
+	if err := os.Chmod(exe, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkServiceFiles(&Config{Executable: exe}); err == nil || !strings.Contains(err.Error(), "writable by group or others") {
+		t.Errorf("checkServiceFiles = %v, want writable by group or others", err)
+	}
+}
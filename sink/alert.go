@@ -0,0 +1,342 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Default endpoints of the alerting services
+const (
+	DefaultPagerDutyEndpoint = "https://events.pagerduty.com/v2/enqueue"
+	DefaultOpsgenieEndpoint  = "https://api.opsgenie.com"
+)
+
+// alertSeverities lists the finding severities, most severe first
+var alertSeverities = []string{"critical", "high", "medium", "low"}
+
+// alertSink opens an incident for each finding at least as severe as
+// minSeverity, keyed by host and finding so that later reports of the same
+// finding do not open another, and resolves the incident once a report
+// shows the finding's check ran without it. The incidents it opened are
+// kept in a state file.
+type alertSink struct {
+	name        string
+	service     alertService
+	minSeverity int
+	stateFile   string
+	timeout     time.Duration
+}
+
+// alertService is the incident management API of an alerting sink
+type alertService interface {
+	trigger(ctx context.Context, a *alert) error
+	resolve(ctx context.Context, a *alert) error
+}
+
+// alert is an incident for one finding on one host
+type alert struct {
+	// Key deduplicates the incident: omnitrust/<hostname>/<finding>
+	Key      string    `json:"key"`
+	Hostname string    `json:"hostname"`
+	Finding  string    `json:"finding"`
+	Check    string    `json:"check"`
+	OpenedAt time.Time `json:"opened_at"`
+
+	// details is the finding, set while it is reported
+	details *Finding
+}
+
+// alertState is an alerting sink's state file
+type alertState struct {
+	// Open are the incidents triggered and not yet resolved, by key
+	Open map[string]*alert `json:"open"`
+}
+
+// newAlertSink validates a PagerDuty or Opsgenie sink's config
+func newAlertSink(c Config, name string, timeout time.Duration) (*alertSink, error) {
+	s := &alertSink{name: name, stateFile: c.StateFile, timeout: timeout}
+	switch strings.ToLower(c.Type) {
+	case TypePagerDuty:
+		if c.RoutingKey == "" {
+			return nil, fmt.Errorf("sink %s: routing_key is required", name)
+		}
+		endpoint := c.Endpoint
+		if endpoint == "" {
+			endpoint = DefaultPagerDutyEndpoint
+		}
+		s.service = &pagerDuty{endpoint: endpoint, routingKey: c.RoutingKey}
+	case TypeOpsgenie:
+		if c.APIKey == "" {
+			return nil, fmt.Errorf("sink %s: api_key is required", name)
+		}
+		endpoint := c.Endpoint
+		if endpoint == "" {
+			endpoint = DefaultOpsgenieEndpoint
+		}
+		s.service = &opsgenie{endpoint: strings.TrimSuffix(endpoint, "/"), apiKey: c.APIKey}
+	}
+	if c.Endpoint != "" && !strings.HasPrefix(c.Endpoint, "https://") && !strings.HasPrefix(c.Endpoint, "http://") {
+		return nil, fmt.Errorf("sink %s: endpoint must be http:// or https://", name)
+	}
+	minSeverity := strings.ToLower(c.MinSeverity)
+	if minSeverity == "" {
+		minSeverity = "critical"
+	}
+	if s.minSeverity = slices.Index(alertSeverities, minSeverity); s.minSeverity < 0 {
+		return nil, fmt.Errorf("sink %s: min_severity must be one of %s", name, strings.Join(alertSeverities, ", "))
+	}
+	if s.stateFile == "" {
+		if s.stateFile = defaultStateFile(name); s.stateFile == "" {
+			return nil, fmt.Errorf("sink %s: state_file is required without a home directory", name)
+		}
+	}
+	return s, nil
+}
+
+// defaultStateFile returns alerts-<name>.json next to the per-user config
+// file
+func defaultStateFile(name string) string {
+	file := "alerts-" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name) + ".json"
+	if runtime.GOOS == "windows" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return ""
+		}
+		return filepath.Join(dir, "omnitrust", file)
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "omnitrust", file)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "omnitrust", file)
+}
+
+func (s *alertSink) Name() string { return s.name }
+
+// Send triggers an incident for each severe finding of the report, which
+// the service merges into the open incident with the same key, and
+// resolves the open incidents of findings that cleared. A finding whose
+// check did not run or timed out has not cleared. Failed calls are retried
+// with the next report.
+func (s *alertSink) Send(ctx context.Context, r *Report) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	state, err := loadAlertState(s.stateFile)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	current := map[string]bool{}
+	for i := range r.Findings {
+		f := &r.Findings[i]
+		if rank := slices.Index(alertSeverities, f.Severity); rank < 0 || rank > s.minSeverity {
+			continue
+		}
+		a := &alert{Key: alertKey(r.Hostname, f.ID), Hostname: r.Hostname, Finding: f.ID, Check: f.Check, OpenedAt: r.Timestamp, details: f}
+		if current[a.Key] {
+			continue
+		}
+		current[a.Key] = true
+		if open, ok := state.Open[a.Key]; ok {
+			a.OpenedAt = open.OpenedAt
+		}
+		if err := s.service.trigger(ctx, a); err != nil {
+			errs = append(errs, fmt.Errorf("trigger %s: %w", a.Finding, err))
+			continue
+		}
+		state.Open[a.Key] = a
+	}
+	for key, a := range state.Open {
+		if current[key] || a.Hostname != r.Hostname {
+			continue
+		}
+		if result := r.CheckResults[a.Check]; result != "pass" && result != "fail" {
+			continue
+		}
+		if err := s.service.resolve(ctx, a); err != nil {
+			errs = append(errs, fmt.Errorf("resolve %s: %w", a.Finding, err))
+			continue
+		}
+		delete(state.Open, key)
+	}
+	if err := saveAlertState(s.stateFile, state); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// alertKey deduplicates the incidents of a finding on a host
+func alertKey(hostname, finding string) string {
+	return "omnitrust/" + hostname + "/" + finding
+}
+
+// loadAlertState reads a state file; a missing file has no open incidents
+func loadAlertState(path string) (*alertState, error) {
+	state := &alertState{Open: map[string]*alert{}}
+	data, err := os.ReadFile(path) // #nosec G304 -- configured state file
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid alert state %s: %w", path, err)
+	}
+	if state.Open == nil {
+		state.Open = map[string]*alert{}
+	}
+	return state, nil
+}
+
+// saveAlertState writes a state file
+func saveAlertState(path string, state *alertState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// alertSummary is the one-line title of an incident
+func alertSummary(a *alert) string {
+	return a.Hostname + ": " + a.details.Title
+}
+
+// postJSON posts body as JSON and treats any non-2xx response as a failure
+func postJSON(ctx context.Context, endpoint string, headers map[string]string, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "omnitrust")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s: %s", endpoint, resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// pagerDuty sends events to the PagerDuty Events API v2
+type pagerDuty struct {
+	endpoint   string
+	routingKey string
+}
+
+// pagerDutySeverities maps finding severities to event severities
+var pagerDutySeverities = map[string]string{
+	"critical": "critical",
+	"high":     "error",
+	"medium":   "warning",
+	"low":      "info",
+}
+
+func (p *pagerDuty) trigger(ctx context.Context, a *alert) error {
+	return postJSON(ctx, p.endpoint, nil, map[string]any{
+		"routing_key":  os.ExpandEnv(p.routingKey),
+		"event_action": "trigger",
+		"dedup_key":    a.Key,
+		"payload": map[string]any{
+			"summary":   alertSummary(a),
+			"source":    a.Hostname,
+			"severity":  pagerDutySeverities[a.details.Severity],
+			"component": a.Check,
+			"class":     a.Finding,
+			"custom_details": map[string]string{
+				"finding":             a.Finding,
+				"remediation":         a.details.Remediation,
+				"remediation_command": a.details.RemediationCommand,
+			},
+		},
+	})
+}
+
+func (p *pagerDuty) resolve(ctx context.Context, a *alert) error {
+	return postJSON(ctx, p.endpoint, nil, map[string]any{
+		"routing_key":  os.ExpandEnv(p.routingKey),
+		"event_action": "resolve",
+		"dedup_key":    a.Key,
+	})
+}
+
+// opsgenie creates and closes alerts with the Opsgenie Alert API
+type opsgenie struct {
+	endpoint string
+	apiKey   string
+}
+
+// opsgeniePriorities maps finding severities to alert priorities
+var opsgeniePriorities = map[string]string{
+	"critical": "P1",
+	"high":     "P2",
+	"medium":   "P3",
+	"low":      "P4",
+}
+
+func (o *opsgenie) headers() map[string]string {
+	return map[string]string{"Authorization": "GenieKey " + os.ExpandEnv(o.apiKey)}
+}
+
+func (o *opsgenie) trigger(ctx context.Context, a *alert) error {
+	// Opsgenie truncates longer messages
+	message := []rune(alertSummary(a))
+	if len(message) > 130 {
+		message = append(message[:129], '…')
+	}
+	return postJSON(ctx, o.endpoint+"/v2/alerts", o.headers(), map[string]any{
+		"message":     string(message),
+		"alias":       a.Key,
+		"description": a.details.Remediation,
+		"source":      "omnitrust",
+		"entity":      a.Hostname,
+		"priority":    opsgeniePriorities[a.details.Severity],
+		"tags":        []string{"omnitrust", a.Check, a.details.Severity},
+		"details": map[string]string{
+			"finding":             a.Finding,
+			"check":               a.Check,
+			"remediation_command": a.details.RemediationCommand,
+		},
+	})
+}
+
+func (o *opsgenie) resolve(ctx context.Context, a *alert) error {
+	endpoint := o.endpoint + "/v2/alerts/" + url.PathEscape(a.Key) + "/close?identifierType=alias"
+	return postJSON(ctx, endpoint, o.headers(), map[string]string{
+		"source": "omnitrust",
+		"note":   "The finding cleared on the next scan",
+	})
+}
@@ -0,0 +1,159 @@
+package sink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// alertRequest is one request the fake alerting service received
+type alertRequest struct {
+	path, auth string
+	body       map[string]any
+}
+
+// fakeAlertService records requests and answers them with status
+func fakeAlertService(t *testing.T, status int) (*httptest.Server, *[]alertRequest) {
+	t.Helper()
+	var requests []alertRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := alertRequest{path: r.URL.EscapedPath() + "?" + r.URL.RawQuery, auth: r.Header.Get("Authorization")}
+		if err := json.NewDecoder(r.Body).Decode(&req.body); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		requests = append(requests, req)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+// alertReport is a test report with the given findings and check results
+func alertReport(results map[string]string, findings ...Finding) *Report {
+	r := testReport()
+	r.Findings = findings
+	r.CheckResults = results
+	return r
+}
+
+var (
+	encryptionDisabled = Finding{ID: "encryption_disabled", Check: "encryption", Severity: "critical", Title: "Disk encryption is disabled", Remediation: "Enable disk encryption"}
+	biometricsMissing  = Finding{ID: "biometrics_not_configured", Check: "biometrics", Severity: "low", Title: "No biometrics", Remediation: "Enroll a fingerprint"}
+	secureBootDisabled = Finding{ID: "secure_boot_disabled", Check: "secure_boot", Severity: "high", Title: "Secure Boot is disabled", Remediation: "Enable Secure Boot"}
+)
+
+func TestPagerDutySink(t *testing.T) {
+	t.Setenv("PD_ROUTING_KEY", "R0UTING")
+	srv, requests := fakeAlertService(t, http.StatusAccepted)
+	state := filepath.Join(t.TempDir(), "alerts.json")
+	s, err := New(Config{Type: TypePagerDuty, Endpoint: srv.URL, RoutingKey: "${PD_ROUTING_KEY}", StateFile: state})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the critical finding opens an incident
+	r := alertReport(map[string]string{"encryption": "fail", "biometrics": "fail", "secure_boot": "fail"}, encryptionDisabled, biometricsMissing, secureBootDisabled)
+	if err := s.Send(context.Background(), r); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("requests = %+v, want one trigger", *requests)
+	}
+	body := (*requests)[0].body
+	payload, _ := body["payload"].(map[string]any)
+	if body["routing_key"] != "R0UTING" || body["event_action"] != "trigger" || body["dedup_key"] != "omnitrust/build-01/encryption_disabled" ||
+		payload["severity"] != "critical" || payload["source"] != "build-01" || payload["summary"] != "build-01: Disk encryption is disabled" {
+		t.Errorf("trigger = %+v", body)
+	}
+
+	// A check that timed out has not cleared its finding
+	if err := s.Send(context.Background(), alertReport(map[string]string{"encryption": "timeout"})); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("requests = %+v, want no resolve while the check timed out", *requests)
+	}
+
+	if err := s.Send(context.Background(), alertReport(map[string]string{"encryption": "pass"})); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(*requests) != 2 || (*requests)[1].body["event_action"] != "resolve" || (*requests)[1].body["dedup_key"] != "omnitrust/build-01/encryption_disabled" {
+		t.Fatalf("requests = %+v, want a resolve", *requests)
+	}
+	if open, err := loadAlertState(state); err != nil || len(open.Open) != 0 {
+		t.Errorf("state = %+v, %v; want no open incidents", open, err)
+	}
+}
+
+func TestOpsgenieSink(t *testing.T) {
+	t.Setenv("OPSGENIE_API_KEY", "g3nie")
+	srv, requests := fakeAlertService(t, http.StatusAccepted)
+	s, err := New(Config{Type: TypeOpsgenie, Endpoint: srv.URL + "/", APIKey: "${OPSGENIE_API_KEY}", MinSeverity: "high",
+		StateFile: filepath.Join(t.TempDir(), "alerts.json")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := alertReport(map[string]string{"encryption": "fail", "secure_boot": "fail"}, encryptionDisabled, secureBootDisabled)
+	if err := s.Send(context.Background(), r); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(*requests) != 2 {
+		t.Fatalf("requests = %+v, want two alerts", *requests)
+	}
+	create := (*requests)[1]
+	if create.path != "/v2/alerts?" || create.auth != "GenieKey g3nie" || create.body["alias"] != "omnitrust/build-01/secure_boot_disabled" ||
+		create.body["priority"] != "P2" || create.body["entity"] != "build-01" {
+		t.Errorf("create = %+v", create)
+	}
+
+	// Secure Boot was turned back on; encryption is still off
+	r = alertReport(map[string]string{"encryption": "fail", "secure_boot": "pass"}, encryptionDisabled)
+	if err := s.Send(context.Background(), r); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	var closed []string
+	for _, req := range (*requests)[2:] {
+		if strings.HasSuffix(req.path, "/close?identifierType=alias") {
+			closed = append(closed, req.path)
+		}
+	}
+	if len(closed) != 1 || closed[0] != "/v2/alerts/omnitrust%2Fbuild-01%2Fsecure_boot_disabled/close?identifierType=alias" {
+		t.Errorf("closed = %v", closed)
+	}
+}
+
+func TestAlertSink_FailedTriggerIsNotRecorded(t *testing.T) {
+	srv, _ := fakeAlertService(t, http.StatusServiceUnavailable)
+	state := filepath.Join(t.TempDir(), "alerts.json")
+	s, _ := New(Config{Type: TypePagerDuty, Endpoint: srv.URL, RoutingKey: "key", StateFile: state})
+	err := s.Send(context.Background(), alertReport(map[string]string{"encryption": "fail"}, encryptionDisabled))
+	if err == nil || !strings.Contains(err.Error(), "trigger encryption_disabled") {
+		t.Errorf("Send error = %v", err)
+	}
+	if open, err := loadAlertState(state); err != nil || len(open.Open) != 0 {
+		t.Errorf("state = %+v, %v; a failed trigger should not be recorded", open, err)
+	}
+}
+
+func TestNewAlertSink_Invalid(t *testing.T) {
+	tests := []Config{
+		{Type: TypePagerDuty},
+		{Type: TypeOpsgenie},
+		{Type: TypePagerDuty, RoutingKey: "key", MinSeverity: "urgent"},
+		{Type: TypeOpsgenie, APIKey: "key", Endpoint: "api.eu.opsgenie.com"},
+	}
+	for _, c := range tests {
+		if _, err := New(c); err == nil {
+			t.Errorf("New(%+v) should fail", c)
+		}
+	}
+	s, err := New(Config{Type: TypePagerDuty, Name: "on call", RoutingKey: "key"})
+	if err != nil || !strings.HasSuffix(s.(*alertSink).stateFile, filepath.Join("omnitrust", "alerts-on_call.json")) {
+		t.Errorf("default state file: %v, %v", s, err)
+	}
+}
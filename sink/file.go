@@ -0,0 +1,42 @@
+package sink
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+)
+
+// fileSink writes each report to a file, replacing any previous report at
+// the same path
+type fileSink struct {
+	name string
+	path string
+}
+
+func (s *fileSink) Name() string { return s.name }
+
+// Send writes the report atomically so readers never see a partial file
+func (s *fileSink) Send(_ context.Context, r *Report) error {
+	return writeFileAtomic(Expand(s.path, r), r.Body)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames
+// it into place, creating the directory
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".omnitrust-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,289 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultMQTTTopic is the topic template of MQTT sinks that do not set one
+const DefaultMQTTTopic = "omnitrust/{hostname}/posture"
+
+// MQTT 3.1.1 control packet types, shifted into the fixed header
+const (
+	mqttConnect    = 1 << 4
+	mqttConnack    = 2 << 4
+	mqttPublish    = 3 << 4
+	mqttPuback     = 4 << 4
+	mqttPubrec     = 5 << 4
+	mqttPubrel     = 6<<4 | 0x02
+	mqttPubcomp    = 7 << 4
+	mqttDisconnect = 14 << 4
+)
+
+// mqttConnackErrors explains the CONNACK return codes
+var mqttConnackErrors = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "client identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// mqttSink publishes each report to an MQTT broker (MQTT 3.1.1). It
+// connects for each report and disconnects once the broker has
+// acknowledged it, so it needs no background connection.
+type mqttSink struct {
+	name     string
+	addr     string
+	tls      *tls.Config
+	topic    string
+	clientID string
+	qos      byte
+	retain   bool
+	username string
+	password string
+	timeout  time.Duration
+}
+
+// newMQTTSink validates an MQTT sink's config. The URL scheme is mqtt or
+// tcp for plain connections and mqtts, ssl, or tls for TLS.
+func newMQTTSink(c Config, name string, timeout time.Duration) (*mqttSink, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("sink %s: url must be mqtt://host:port or mqtts://host:port", name)
+	}
+	s := &mqttSink{
+		name:     name,
+		addr:     u.Host,
+		topic:    c.Topic,
+		clientID: c.ClientID,
+		retain:   c.Retain,
+		username: c.Username,
+		password: c.Password,
+		timeout:  timeout,
+	}
+	port := "1883"
+	switch strings.ToLower(u.Scheme) {
+	case "mqtt", "tcp":
+	case "mqtts", "ssl", "tls":
+		port = "8883"
+		if s.tls, err = clientTLSConfig(c, u.Hostname()); err != nil {
+			return nil, fmt.Errorf("sink %s: %w", name, err)
+		}
+	default:
+		return nil, fmt.Errorf("sink %s: url must be mqtt://host:port or mqtts://host:port", name)
+	}
+	if u.Port() == "" {
+		s.addr = net.JoinHostPort(u.Hostname(), port)
+	}
+	if c.QoS < 0 || c.QoS > 2 {
+		return nil, fmt.Errorf("sink %s: qos must be 0, 1, or 2", name)
+	}
+	s.qos = byte(c.QoS)
+	if s.topic == "" {
+		s.topic = DefaultMQTTTopic
+	}
+	if strings.ContainsAny(s.topic, "+#") {
+		return nil, fmt.Errorf("sink %s: topic must not contain the wildcards + or #", name)
+	}
+	if s.clientID == "" {
+		s.clientID = "omnitrust-{hostname}"
+	}
+	return s, nil
+}
+
+// clientTLSConfig loads the CA and client certificate of a TLS connection
+func clientTLSConfig(c Config, serverName string) (*tls.Config, error) {
+	cfg := &tls.Config{ServerName: serverName, MinVersion: tls.VersionTLS12}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile) // #nosec G304 -- operator-configured CA file
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in %s", c.CAFile)
+		}
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+func (s *mqttSink) Name() string { return s.name }
+
+// Send connects, publishes the report at the configured QoS, waits for the
+// broker's acknowledgement, and disconnects
+func (s *mqttSink) Send(ctx context.Context, r *Report) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	var err error
+	if s.tls != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: s.tls}).DialContext(ctx, "tcp", s.addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", s.addr)
+	}
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	rd := bufio.NewReader(conn)
+	if err := s.connect(conn, rd, Expand(s.clientID, r)); err != nil {
+		return err
+	}
+	if err := s.publish(conn, rd, Expand(s.topic, r), r.Body); err != nil {
+		return err
+	}
+	_, err = conn.Write([]byte{mqttDisconnect, 0})
+	return err
+}
+
+// connect sends CONNECT with a clean session and checks the CONNACK
+func (s *mqttSink) connect(w io.Writer, rd *bufio.Reader, clientID string) error {
+	var flags byte = 0x02 // clean session
+	body := mqttString("MQTT")
+	payload := mqttString(clientID)
+	if s.username != "" {
+		flags |= 0x80
+		payload = append(payload, mqttString(os.ExpandEnv(s.username))...)
+	}
+	if s.password != "" {
+		flags |= 0x40
+		payload = append(payload, mqttString(os.ExpandEnv(s.password))...)
+	}
+	body = append(body, 4, flags, 0, 60) // protocol level 4, keep alive 60s
+	if _, err := w.Write(mqttPacket(mqttConnect, append(body, payload...))); err != nil {
+		return err
+	}
+
+	typ, ack, err := readMQTTPacket(rd)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	if typ != mqttConnack || len(ack) != 2 {
+		return errors.New("connect: broker did not answer with CONNACK")
+	}
+	if ack[1] != 0 {
+		reason, ok := mqttConnackErrors[ack[1]]
+		if !ok {
+			reason = fmt.Sprintf("return code %d", ack[1])
+		}
+		return fmt.Errorf("broker refused the connection: %s", reason)
+	}
+	return nil
+}
+
+// publish sends PUBLISH and completes the QoS 1 or 2 handshake
+func (s *mqttSink) publish(w io.Writer, rd *bufio.Reader, topic string, payload []byte) error {
+	const packetID = 1
+	header := byte(mqttPublish) | s.qos<<1
+	if s.retain {
+		header |= 0x01
+	}
+	body := mqttString(topic)
+	if s.qos > 0 {
+		body = binary.BigEndian.AppendUint16(body, packetID)
+	}
+	if _, err := w.Write(mqttPacket(header, append(body, payload...))); err != nil {
+		return err
+	}
+
+	switch s.qos {
+	case 1:
+		return expectMQTTAck(rd, mqttPuback, packetID)
+	case 2:
+		if err := expectMQTTAck(rd, mqttPubrec, packetID); err != nil {
+			return err
+		}
+		if _, err := w.Write(mqttPacket(mqttPubrel, binary.BigEndian.AppendUint16(nil, packetID))); err != nil {
+			return err
+		}
+		return expectMQTTAck(rd, mqttPubcomp, packetID)
+	}
+	return nil
+}
+
+// expectMQTTAck reads an acknowledgement of the given type for packetID
+func expectMQTTAck(rd *bufio.Reader, want byte, packetID uint16) error {
+	typ, body, err := readMQTTPacket(rd)
+	if err != nil {
+		return fmt.Errorf("publish: %w", err)
+	}
+	if typ&0xf0 != want || len(body) < 2 || binary.BigEndian.Uint16(body) != packetID {
+		return fmt.Errorf("publish: unexpected packet type %d from broker", typ>>4)
+	}
+	return nil
+}
+
+// mqttString encodes a length-prefixed UTF-8 string
+func mqttString(s string) []byte {
+	return append(binary.BigEndian.AppendUint16(nil, uint16(len(s))), s...)
+}
+
+// mqttPacket frames a packet body with its fixed header
+func mqttPacket(header byte, body []byte) []byte {
+	out := []byte{header}
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(out, body...)
+}
+
+// readMQTTPacket reads one packet, returning its fixed header byte and body
+func readMQTTPacket(rd *bufio.Reader) (byte, []byte, error) {
+	header, err := rd.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	n, shift := 0, 0
+	for {
+		b, err := rd.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n |= int(b&0x7f) << shift
+		if b&0x80 == 0 {
+			break
+		}
+		if shift += 7; shift > 21 {
+			return 0, nil, errors.New("malformed remaining length")
+		}
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(rd, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
@@ -0,0 +1,207 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mqttMessage is what the fake broker received
+type mqttMessage struct {
+	clientID, username, password string
+	topic, payload               string
+	header                       byte
+	disconnected                 bool
+}
+
+// fakeBroker accepts one connection, answers CONNACK with code, and
+// acknowledges a publish at its QoS
+func fakeBroker(t *testing.T, ln net.Listener, code byte) <-chan mqttMessage {
+	t.Helper()
+	done := make(chan mqttMessage, 1)
+	go func() {
+		var m mqttMessage
+		defer func() { done <- m }()
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		rd := bufio.NewReader(conn)
+
+		_, body, err := readMQTTPacket(rd)
+		if err != nil {
+			return
+		}
+		flags := body[7]
+		fields := mqttStrings(body[10:])
+		m.clientID = fields[0]
+		if flags&0x80 != 0 {
+			m.username = fields[1]
+		}
+		if flags&0x40 != 0 {
+			m.password = fields[2]
+		}
+		conn.Write([]byte{mqttConnack, 2, 0, code})
+		if code != 0 {
+			return
+		}
+
+		header, body, err := readMQTTPacket(rd)
+		if err != nil {
+			return
+		}
+		m.header = header
+		n := int(binary.BigEndian.Uint16(body))
+		m.topic = string(body[2 : 2+n])
+		rest := body[2+n:]
+		switch (header >> 1) & 3 {
+		case 1:
+			conn.Write(append([]byte{mqttPuback, 2}, rest[:2]...))
+			rest = rest[2:]
+		case 2:
+			conn.Write(append([]byte{mqttPubrec, 2}, rest[:2]...))
+			if typ, _, err := readMQTTPacket(rd); err != nil || typ != mqttPubrel {
+				return
+			}
+			conn.Write(append([]byte{mqttPubcomp, 2}, rest[:2]...))
+			rest = rest[2:]
+		}
+		m.payload = string(rest)
+		typ, _, err := readMQTTPacket(rd)
+		m.disconnected = err == nil && typ == mqttDisconnect
+	}()
+	return done
+}
+
+// mqttStrings decodes consecutive length-prefixed strings
+func mqttStrings(b []byte) []string {
+	var out []string
+	for len(b) >= 2 {
+		n := int(binary.BigEndian.Uint16(b))
+		out = append(out, string(b[2:2+n]))
+		b = b[2+n:]
+	}
+	return append(out, "", "")
+}
+
+func TestMQTTSink(t *testing.T) {
+	t.Setenv("MQTT_PASSWORD", "hunter2")
+	for _, qos := range []int{0, 1, 2} {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ln.Close()
+		done := fakeBroker(t, ln, 0)
+
+		s, err := New(Config{Type: TypeMQTT, URL: "mqtt://" + ln.Addr().String(), Topic: "fleet/{hostname}/posture",
+			QoS: qos, Retain: true, Username: "edge", Password: "${MQTT_PASSWORD}"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Send(context.Background(), testReport()); err != nil {
+			t.Fatalf("qos %d: Send: %v", qos, err)
+		}
+
+		m := <-done
+		if m.clientID != "omnitrust-build-01" || m.username != "edge" || m.password != "hunter2" {
+			t.Errorf("qos %d: connect = %+v", qos, m)
+		}
+		if m.topic != "fleet/build-01/posture" || m.payload != `{"overall_score":75}` || m.header&0x01 == 0 ||
+			int(m.header>>1&3) != qos || !m.disconnected {
+			t.Errorf("qos %d: publish = %+v", qos, m)
+		}
+	}
+}
+
+func TestMQTTSink_Refused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	fakeBroker(t, ln, 5)
+
+	s, _ := New(Config{Type: TypeMQTT, URL: "tcp://" + ln.Addr().String()})
+	if err := s.Send(context.Background(), testReport()); err == nil || !strings.Contains(err.Error(), "not authorized") {
+		t.Errorf("Send error = %v", err)
+	}
+}
+
+// testCertificate creates a self-signed certificate for 127.0.0.1 and
+// writes it to a CA file
+func testCertificate(t *testing.T) (tls.Certificate, string) {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, caFile
+}
+
+func TestMQTTSink_TLS(t *testing.T) {
+	cert, caFile := testCertificate(t)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	done := fakeBroker(t, ln, 0)
+
+	s, err := New(Config{Type: TypeMQTT, URL: "mqtts://" + ln.Addr().String(), CAFile: caFile, QoS: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(context.Background(), testReport()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if m := <-done; m.topic != "omnitrust/build-01/posture" {
+		t.Errorf("publish = %+v", m)
+	}
+}
+
+func TestNewMQTTSink_Invalid(t *testing.T) {
+	tests := []Config{
+		{Type: TypeMQTT},
+		{Type: TypeMQTT, URL: "https://broker.example.com"},
+		{Type: TypeMQTT, URL: "mqtt://broker.example.com", QoS: 3},
+		{Type: TypeMQTT, URL: "mqtt://broker.example.com", Topic: "fleet/+/posture"},
+		{Type: TypeMQTT, URL: "mqtts://broker.example.com", CAFile: "/nonexistent/ca.pem"},
+	}
+	for _, c := range tests {
+		if _, err := New(c); err == nil {
+			t.Errorf("New(%+v) should fail", c)
+		}
+	}
+	s, err := New(Config{Type: TypeMQTT, URL: "mqtts://broker.example.com"})
+	if err != nil || s.(*mqttSink).addr != "broker.example.com:8883" {
+		t.Errorf("default TLS port: %v, %v", s, err)
+	}
+}
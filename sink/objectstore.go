@@ -0,0 +1,389 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultObjectKey is the key template of object storage sinks that do not
+// set one
+const DefaultObjectKey = "{hostname}/{date}/{timestamp}.json"
+
+// DefaultRetries is how many times object storage sinks retry a failed
+// upload when they do not set retries
+const DefaultRetries = 3
+
+// retryBackoff is the delay before the first retry; it doubles each time
+var retryBackoff = 500 * time.Millisecond
+
+// gceTokenURL serves access tokens for the service account of a Compute
+// Engine, GKE, or Cloud Run workload
+const gceTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// objectSink uploads each report as an object to S3 (or an S3-compatible
+// store), Google Cloud Storage, or Azure Blob Storage
+type objectSink struct {
+	name     string
+	provider string
+	bucket   string
+	key      string
+	endpoint string
+	region   string
+	account  string
+	// sse is the S3 server-side encryption (AES256 or aws:kms)
+	sse string
+	// kmsKey is the S3 KMS key ID or the GCS Cloud KMS key name
+	kmsKey string
+	// encryptionScope is the Azure encryption scope
+	encryptionScope string
+	retries         int
+	timeout         time.Duration
+}
+
+// newObjectSink validates an object storage sink's config
+func newObjectSink(c Config, name string, timeout time.Duration) (*objectSink, error) {
+	provider := strings.ToLower(c.Type)
+	if c.Bucket == "" {
+		return nil, fmt.Errorf("sink %s: bucket is required", name)
+	}
+	if provider == TypeAzureBlob && c.Account == "" && c.Endpoint == "" {
+		return nil, fmt.Errorf("sink %s: account or endpoint is required", name)
+	}
+	if c.Endpoint != "" && !strings.HasPrefix(c.Endpoint, "https://") && !strings.HasPrefix(c.Endpoint, "http://") {
+		return nil, fmt.Errorf("sink %s: endpoint must be http:// or https://", name)
+	}
+	switch c.ServerSideEncryption {
+	case "":
+	case "AES256", "aws:kms":
+		if provider != TypeS3 {
+			return nil, fmt.Errorf("sink %s: server_side_encryption applies to s3 sinks only", name)
+		}
+	default:
+		return nil, fmt.Errorf("sink %s: unknown server_side_encryption %q (use AES256 or aws:kms)", name, c.ServerSideEncryption)
+	}
+	if c.EncryptionScope != "" && provider != TypeAzureBlob {
+		return nil, fmt.Errorf("sink %s: encryption_scope applies to azure_blob sinks only", name)
+	}
+
+	s := &objectSink{
+		name:            name,
+		provider:        provider,
+		bucket:          c.Bucket,
+		key:             c.Key,
+		endpoint:        strings.TrimSuffix(c.Endpoint, "/"),
+		region:          c.Region,
+		account:         c.Account,
+		sse:             c.ServerSideEncryption,
+		kmsKey:          c.KMSKey,
+		encryptionScope: c.EncryptionScope,
+		retries:         DefaultRetries,
+		timeout:         timeout,
+	}
+	if s.key == "" {
+		s.key = DefaultObjectKey
+	}
+	if c.Retries != nil {
+		s.retries = max(*c.Retries, 0)
+	}
+	if s.region == "" {
+		s.region = os.Getenv("AWS_REGION")
+	}
+	if s.region == "" {
+		s.region = "us-east-1"
+	}
+	return s, nil
+}
+
+func (s *objectSink) Name() string { return s.name }
+
+// Send uploads the report, retrying network errors, throttling, and server
+// errors with exponential backoff
+func (s *objectSink) Send(ctx context.Context, r *Report) error {
+	key := strings.TrimPrefix(Expand(s.key, r), "/")
+	delay := retryBackoff
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = s.put(ctx, key, r); err == nil || attempt == s.retries || !retryable(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// statusError is an upload rejected with an HTTP status
+type statusError struct {
+	code int
+	msg  string
+}
+
+func (e *statusError) Error() string { return e.msg }
+
+// retryable reports whether a failed upload may succeed if repeated:
+// connection errors, throttling, and server errors are, while missing
+// credentials and client errors are not
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code == http.StatusTooManyRequests || se.code >= 500
+	}
+	var ue *url.Error
+	return errors.As(err, &ue)
+}
+
+// put makes one upload attempt
+func (s *objectSink) put(ctx context.Context, key string, r *Report) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var req *http.Request
+	var err error
+	switch s.provider {
+	case TypeS3:
+		req, err = s.s3Request(ctx, key, r)
+	case TypeGCS:
+		req, err = s.gcsRequest(ctx, key, r)
+	default:
+		req, err = s.azureRequest(ctx, key, r)
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &statusError{code: resp.StatusCode, msg: fmt.Sprintf("upload of %s returned %s: %s", key, resp.Status, bytes.TrimSpace(body))}
+	}
+	return nil
+}
+
+// s3Request builds a PutObject request signed with AWS Signature Version 4
+// from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and AWS_SESSION_TOKEN.
+// Custom endpoints (MinIO, Ceph, R2, GCS interoperability) are addressed
+// path-style.
+func (s *objectSink) s3Request(ctx context.Context, key string, r *Report) (*http.Request, error) {
+	accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return nil, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+	}
+	target := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, uriEncode(key, false))
+	if s.endpoint != "" {
+		target = fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucket, uriEncode(key, false))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", r.ContentType)
+	req.Header.Set("User-Agent", "omnitrust")
+	if s.sse != "" {
+		req.Header.Set("X-Amz-Server-Side-Encryption", s.sse)
+	}
+	if s.sse == "aws:kms" && s.kmsKey != "" {
+		req.Header.Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", s.kmsKey)
+	}
+	if token := os.Getenv("AWS_SESSION_TOKEN"); token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+	sum := sha256.Sum256(r.Body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+	signV4(req, hex.EncodeToString(sum[:]), accessKey, secretKey, s.region, "s3", time.Now())
+	return req, nil
+}
+
+// signV4 signs req with AWS Signature Version 4, covering the host,
+// content type, and x-amz-* headers
+func signV4(req *http.Request, payloadHash, accessKey, secretKey, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonical := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", now.Format("20060102"), region, service)
+	hash := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{now.Format("20060102"), region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalQuery sorts and encodes query parameters for signing
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var parts []string
+	for _, k := range keys {
+		values := slices.Clone(q[k])
+		slices.Sort(values)
+		for _, v := range values {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything but RFC 3986 unreserved characters,
+// and slashes unless encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	var sb strings.Builder
+	for i := range len(s) {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			sb.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+// gcsRequest builds a JSON API media upload authorized with
+// GOOGLE_OAUTH_ACCESS_TOKEN or, on Google Cloud, the workload's service
+// account
+func (s *objectSink) gcsRequest(ctx context.Context, key string, r *Report) (*http.Request, error) {
+	token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")
+	if token == "" {
+		var err error
+		if token, err = gceToken(ctx); err != nil {
+			return nil, fmt.Errorf("no GOOGLE_OAUTH_ACCESS_TOKEN and no metadata server token: %v", err)
+		}
+	}
+	endpoint := s.endpoint
+	if endpoint == "" {
+		endpoint = "https://storage.googleapis.com"
+	}
+	q := url.Values{"uploadType": {"media"}, "name": {key}}
+	if s.kmsKey != "" {
+		q.Set("kmsKeyName", s.kmsKey)
+	}
+	target := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?%s", endpoint, url.PathEscape(s.bucket), q.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", r.ContentType)
+	req.Header.Set("User-Agent", "omnitrust")
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req, nil
+}
+
+// gceToken fetches an access token from the Google Cloud metadata server
+func gceToken(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gceTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata server returned %s", resp.Status)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// azureRequest builds a Put Blob request authorized with the SAS token in
+// AZURE_STORAGE_SAS_TOKEN
+func (s *objectSink) azureRequest(ctx context.Context, key string, r *Report) (*http.Request, error) {
+	sas := strings.TrimPrefix(os.Getenv("AZURE_STORAGE_SAS_TOKEN"), "?")
+	if sas == "" {
+		return nil, errors.New("AZURE_STORAGE_SAS_TOKEN must be set")
+	}
+	endpoint := s.endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", s.account)
+	}
+	target := fmt.Sprintf("%s/%s/%s?%s", endpoint, url.PathEscape(s.bucket), uriEncode(key, false), sas)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target, bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", r.ContentType)
+	req.Header.Set("User-Agent", "omnitrust")
+	req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+	req.Header.Set("X-Ms-Version", "2021-08-06")
+	if s.encryptionScope != "" {
+		req.Header.Set("X-Ms-Encryption-Scope", s.encryptionScope)
+	}
+	return req, nil
+}
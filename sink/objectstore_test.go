@@ -0,0 +1,169 @@
+package sink
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignV4(t *testing.T) {
+	// get-vanilla from the AWS Signature Version 4 test suite
+	req, _ := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	empty := sha256.Sum256(nil)
+	signV4(req, hex.EncodeToString(empty[:]), "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		"us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization = %q, want %q", got, want)
+	}
+}
+
+// objectServer records the last upload and answers with the given statuses
+// in turn, then 200
+type objectServer struct {
+	*httptest.Server
+	req      *http.Request
+	body     string
+	attempts int
+}
+
+func newObjectServer(t *testing.T, statuses ...int) *objectServer {
+	s := &objectServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.req, s.body = r, string(body)
+		s.attempts++
+		if s.attempts <= len(statuses) {
+			w.WriteHeader(statuses[s.attempts-1])
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestS3Sink(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	srv := newObjectServer(t)
+
+	s, err := New(Config{Type: TypeS3, Bucket: "evidence", Endpoint: srv.URL, Region: "eu-west-1",
+		Key: "posture/{hostname}/{date}.json", ServerSideEncryption: "aws:kms", KMSKey: "alias/posture"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(context.Background(), testReport()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	r := srv.req
+	if r.Method != http.MethodPut || r.URL.Path != "/evidence/posture/build-01/2025-03-04.json" || srv.body != `{"overall_score":75}` {
+		t.Errorf("upload = %s %s %q", r.Method, r.URL.Path, srv.body)
+	}
+	if r.Header.Get("X-Amz-Server-Side-Encryption") != "aws:kms" || r.Header.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id") != "alias/posture" {
+		t.Errorf("encryption headers = %v", r.Header)
+	}
+	if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+		!strings.Contains(auth, "/eu-west-1/s3/aws4_request") || !strings.Contains(auth, "x-amz-server-side-encryption") {
+		t.Errorf("Authorization = %q", auth)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	if err := s.Send(context.Background(), testReport()); err == nil || !strings.Contains(err.Error(), "AWS_ACCESS_KEY_ID") {
+		t.Errorf("Send without credentials error = %v", err)
+	}
+}
+
+func TestGCSSink(t *testing.T) {
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "ya29.token")
+	srv := newObjectServer(t)
+
+	s, err := New(Config{Type: TypeGCS, Bucket: "evidence", Endpoint: srv.URL, KMSKey: "projects/p/locations/l/keyRings/r/cryptoKeys/k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(context.Background(), testReport()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	r := srv.req
+	q := r.URL.Query()
+	if r.URL.Path != "/upload/storage/v1/b/evidence/o" || q.Get("name") != "build-01/2025-03-04/20250304T050607Z.json" ||
+		q.Get("kmsKeyName") != "projects/p/locations/l/keyRings/r/cryptoKeys/k" {
+		t.Errorf("upload = %s", r.URL)
+	}
+	if r.Header.Get("Authorization") != "Bearer ya29.token" {
+		t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+	}
+}
+
+func TestAzureBlobSink(t *testing.T) {
+	t.Setenv("AZURE_STORAGE_SAS_TOKEN", "?sv=2021-08-06&sig=abc")
+	srv := newObjectServer(t, http.StatusCreated)
+
+	s, err := New(Config{Type: TypeAzureBlob, Bucket: "evidence", Endpoint: srv.URL, EncryptionScope: "posture-scope"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(context.Background(), testReport()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	r := srv.req
+	if r.Method != http.MethodPut || r.URL.Path != "/evidence/build-01/2025-03-04/20250304T050607Z.json" || r.URL.Query().Get("sig") != "abc" {
+		t.Errorf("upload = %s %s", r.Method, r.URL)
+	}
+	if r.Header.Get("X-Ms-Blob-Type") != "BlockBlob" || r.Header.Get("X-Ms-Encryption-Scope") != "posture-scope" {
+		t.Errorf("headers = %v", r.Header)
+	}
+}
+
+func TestObjectSink_Retries(t *testing.T) {
+	defer func(d time.Duration) { retryBackoff = d }(retryBackoff)
+	retryBackoff = time.Millisecond
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "token")
+
+	srv := newObjectServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	s, _ := New(Config{Type: TypeGCS, Bucket: "evidence", Endpoint: srv.URL})
+	if err := s.Send(context.Background(), testReport()); err != nil || srv.attempts != 3 {
+		t.Errorf("Send = %v after %d attempts, want success after 3", err, srv.attempts)
+	}
+
+	// Client errors are not retried
+	srv = newObjectServer(t, http.StatusForbidden)
+	s, _ = New(Config{Type: TypeGCS, Bucket: "evidence", Endpoint: srv.URL})
+	if err := s.Send(context.Background(), testReport()); err == nil || srv.attempts != 1 {
+		t.Errorf("Send = %v after %d attempts, want failure after 1", err, srv.attempts)
+	}
+
+	// retries: 0 gives up after the first failure
+	none := 0
+	srv = newObjectServer(t, http.StatusInternalServerError)
+	s, _ = New(Config{Type: TypeGCS, Bucket: "evidence", Endpoint: srv.URL, Retries: &none})
+	if err := s.Send(context.Background(), testReport()); err == nil || srv.attempts != 1 {
+		t.Errorf("Send = %v after %d attempts, want failure after 1", err, srv.attempts)
+	}
+}
+
+func TestNewObjectSink_Invalid(t *testing.T) {
+	tests := []Config{
+		{Type: TypeS3},
+		{Type: TypeAzureBlob, Bucket: "evidence"},
+		{Type: TypeS3, Bucket: "evidence", ServerSideEncryption: "rot13"},
+		{Type: TypeGCS, Bucket: "evidence", ServerSideEncryption: "AES256"},
+		{Type: TypeS3, Bucket: "evidence", EncryptionScope: "scope"},
+		{Type: TypeS3, Bucket: "evidence", Endpoint: "minio:9000"},
+	}
+	for _, c := range tests {
+		if _, err := New(c); err == nil {
+			t.Errorf("New(%+v) should fail", c)
+		}
+	}
+}
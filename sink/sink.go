@@ -0,0 +1,284 @@
+// Package sink delivers security reports to destinations configured by the
+// operator, such as a file on a shared volume, an HTTP collector, a cloud
+// storage bucket, a mailbox, or an incident management service.
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Sink types
+const (
+	TypeFile    = "file"
+	TypeWebhook = "webhook"
+	// TypeS3 uploads to Amazon S3 or an S3-compatible store
+	TypeS3        = "s3"
+	TypeGCS       = "gcs"
+	TypeAzureBlob = "azure_blob"
+	TypeMQTT      = "mqtt"
+	// TypeSMTP emails the rendered report
+	TypeSMTP = "smtp"
+	// TypePagerDuty and TypeOpsgenie open an incident for each severe
+	// finding and resolve it once the finding clears
+	TypePagerDuty = "pagerduty"
+	TypeOpsgenie  = "opsgenie"
+)
+
+// When a sink delivers reports
+const (
+	// WhenAlways delivers every report
+	WhenAlways = "always"
+	// WhenDrift delivers only reports that drifted from the baseline
+	WhenDrift = "drift"
+)
+
+// Config describes one sink in the config file
+type Config struct {
+	// Type is file, webhook, s3, gcs, azure_blob, mqtt, smtp, pagerduty, or
+	// opsgenie
+	Type string `yaml:"type" json:"type"`
+	// Name identifies the sink in errors; it defaults to the type
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// When is always (the default) or drift, to deliver only the reports
+	// that changed from the baseline
+	When string `yaml:"when,omitempty" json:"when,omitempty"`
+	// Path is the file sink's destination. It may contain {hostname},
+	// {date}, and {timestamp} placeholders.
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+	// URL is the webhook sink's endpoint, the MQTT broker
+	// (mqtt://host:1883 or mqtts://host:8883), or the SMTP server
+	// (smtp://host:587 with STARTTLS or smtps://host:465)
+	URL string `yaml:"url,omitempty" json:"url,omitempty"`
+	// Headers are sent with webhook requests. Values are expanded from the
+	// environment ("Bearer ${COLLECTOR_TOKEN}") so secrets stay out of the file.
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	// Timeout bounds a single delivery (default 10s)
+	Timeout time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+
+	// Bucket is the object storage sink's bucket, or container on Azure
+	Bucket string `yaml:"bucket,omitempty" json:"bucket,omitempty"`
+	// Key is the object key template, with the same placeholders as Path
+	// (default DefaultObjectKey)
+	Key string `yaml:"key,omitempty" json:"key,omitempty"`
+	// Endpoint replaces the provider's default endpoint, e.g. for MinIO or
+	// Opsgenie's EU instance
+	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint,omitempty"`
+	// Region is the S3 region (default AWS_REGION, or us-east-1)
+	Region string `yaml:"region,omitempty" json:"region,omitempty"`
+	// Account is the Azure storage account
+	Account string `yaml:"account,omitempty" json:"account,omitempty"`
+	// ServerSideEncryption is the S3 encryption, AES256 or aws:kms
+	ServerSideEncryption string `yaml:"server_side_encryption,omitempty" json:"server_side_encryption,omitempty"`
+	// KMSKey is the S3 KMS key ID (with aws:kms) or the GCS Cloud KMS key name
+	KMSKey string `yaml:"kms_key,omitempty" json:"kms_key,omitempty"`
+	// EncryptionScope is the Azure encryption scope
+	EncryptionScope string `yaml:"encryption_scope,omitempty" json:"encryption_scope,omitempty"`
+	// Retries is how many times a failed upload is retried (default
+	// DefaultRetries)
+	Retries *int `yaml:"retries,omitempty" json:"retries,omitempty"`
+
+	// Topic is the MQTT topic template, with the same placeholders as Path
+	// (default DefaultMQTTTopic)
+	Topic string `yaml:"topic,omitempty" json:"topic,omitempty"`
+	// ClientID is the MQTT client ID template (default omnitrust-{hostname})
+	ClientID string `yaml:"client_id,omitempty" json:"client_id,omitempty"`
+	// QoS is the MQTT quality of service, 0, 1, or 2
+	QoS int `yaml:"qos,omitempty" json:"qos,omitempty"`
+	// Retain asks the broker to keep the last report for new subscribers
+	Retain bool `yaml:"retain,omitempty" json:"retain,omitempty"`
+	// Username and Password authenticate to the MQTT broker or SMTP server.
+	// They are expanded from the environment like webhook headers.
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+	Password string `yaml:"password,omitempty" json:"password,omitempty"`
+	// CAFile verifies the MQTT broker's or SMTP server's certificate
+	// instead of the system roots; CertFile and KeyFile are the client
+	// certificate for mutual TLS
+	CAFile   string `yaml:"ca_file,omitempty" json:"ca_file,omitempty"`
+	CertFile string `yaml:"cert_file,omitempty" json:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty" json:"key_file,omitempty"`
+
+	// From and To are the SMTP sink's sender and recipients
+	From string   `yaml:"from,omitempty" json:"from,omitempty"`
+	To   []string `yaml:"to,omitempty" json:"to,omitempty"`
+	// Subject is the email subject template, with the same placeholders as
+	// Path and {summary} (default DefaultEmailSubject)
+	Subject string `yaml:"subject,omitempty" json:"subject,omitempty"`
+	// Format is the email body, html (the default) or markdown
+	Format string `yaml:"format,omitempty" json:"format,omitempty"`
+	// Token is an OAuth2 access token that authenticates Username to the
+	// SMTP server with XOAUTH2 instead of Password. It is expanded from the
+	// environment.
+	Token string `yaml:"token,omitempty" json:"token,omitempty"`
+
+	// RoutingKey is the PagerDuty Events API v2 integration key, and APIKey
+	// the Opsgenie API key. They are expanded from the environment.
+	RoutingKey string `yaml:"routing_key,omitempty" json:"routing_key,omitempty"`
+	APIKey     string `yaml:"api_key,omitempty" json:"api_key,omitempty"`
+	// MinSeverity is the least severe finding that opens an incident
+	// (default critical)
+	MinSeverity string `yaml:"min_severity,omitempty" json:"min_severity,omitempty"`
+	// StateFile is where an alerting sink remembers the incidents it
+	// opened, to resolve them later (default alerts-<name>.json next to the
+	// per-user config file)
+	StateFile string `yaml:"state_file,omitempty" json:"state_file,omitempty"`
+}
+
+// DefaultTimeout bounds a delivery when the sink does not set one
+const DefaultTimeout = 10 * time.Second
+
+// Report is a rendered report ready for delivery
+type Report struct {
+	Hostname  string
+	Timestamp time.Time
+	// Body is the encoded report and ContentType its media type
+	Body        []byte
+	ContentType string
+	// Summary is the report in one line, e.g. for an email subject
+	Summary string
+	// Drift is true when the report changed from the baseline
+	Drift bool
+	// Render renders the report for people, in the html or markdown format;
+	// it is nil when only Body is available
+	Render func(format string) string
+	// Findings are the report's findings, and CheckResults the outcome of
+	// each check that ran (pass, fail, or timeout), for alerting sinks
+	Findings     []Finding
+	CheckResults map[string]string
+}
+
+// Finding is one finding of a report
+type Finding struct {
+	ID                 string
+	Check              string
+	Severity           string
+	Title              string
+	Remediation        string
+	RemediationCommand string
+}
+
+// Sink delivers reports to one destination
+type Sink interface {
+	Name() string
+	Send(ctx context.Context, r *Report) error
+}
+
+// New creates the sink described by c
+func New(c Config) (Sink, error) {
+	name := c.Name
+	if name == "" {
+		name = c.Type
+	}
+	s, err := newSink(c, name)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(c.When) {
+	case "", WhenAlways:
+		return s, nil
+	case WhenDrift:
+		return driftSink{s}, nil
+	}
+	return nil, fmt.Errorf("sink %s: when must be %s or %s", name, WhenAlways, WhenDrift)
+}
+
+// newSink creates the sink of c's type
+func newSink(c Config, name string) (Sink, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	switch strings.ToLower(c.Type) {
+	case TypeFile:
+		if c.Path == "" {
+			return nil, fmt.Errorf("sink %s: path is required", name)
+		}
+		return &fileSink{name: name, path: c.Path}, nil
+	case TypeWebhook:
+		if !strings.HasPrefix(c.URL, "https://") && !strings.HasPrefix(c.URL, "http://") {
+			return nil, fmt.Errorf("sink %s: url must be http:// or https://", name)
+		}
+		return &webhookSink{name: name, url: c.URL, headers: c.Headers, timeout: timeout}, nil
+	case TypeS3, TypeGCS, TypeAzureBlob:
+		s, err := newObjectSink(c, name, timeout)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case TypeMQTT:
+		s, err := newMQTTSink(c, name, timeout)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case TypeSMTP:
+		s, err := newSMTPSink(c, name, timeout)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case TypePagerDuty, TypeOpsgenie:
+		s, err := newAlertSink(c, name, timeout)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	case "":
+		return nil, fmt.Errorf("sink %s: type is required", name)
+	}
+	return nil, fmt.Errorf("sink %s: unknown type %q (use %s, %s, %s, %s, %s, %s, %s, %s, or %s)", name, c.Type,
+		TypeFile, TypeWebhook, TypeS3, TypeGCS, TypeAzureBlob, TypeMQTT, TypeSMTP, TypePagerDuty, TypeOpsgenie)
+}
+
+// driftSink delivers only the reports that drifted from the baseline
+type driftSink struct {
+	Sink
+}
+
+func (s driftSink) Send(ctx context.Context, r *Report) error {
+	if !r.Drift {
+		return nil
+	}
+	return s.Sink.Send(ctx, r)
+}
+
+// NewAll creates every configured sink, reporting all invalid entries at once
+func NewAll(configs []Config) ([]Sink, error) {
+	var sinks []Sink
+	var errs []error
+	for _, c := range configs {
+		s, err := New(c)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sinks = append(sinks, s)
+	}
+	return sinks, errors.Join(errs...)
+}
+
+// Deliver sends r to every sink and returns the failures. A failing sink
+// does not stop delivery to the others.
+func Deliver(ctx context.Context, sinks []Sink, r *Report) error {
+	var errs []error
+	for _, s := range sinks {
+		if err := s.Send(ctx, r); err != nil {
+			errs = append(errs, fmt.Errorf("sink %s: %w", s.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Expand replaces the {hostname}, {date} (YYYY-MM-DD), and {timestamp}
+// (YYYYMMDDTHHMMSSZ) placeholders in a path or key template
+func Expand(template string, r *Report) string {
+	ts := r.Timestamp.UTC()
+	return strings.NewReplacer(
+		"{hostname}", r.Hostname,
+		"{date}", ts.Format("2006-01-02"),
+		"{timestamp}", ts.Format("20060102T150405Z"),
+	).Replace(template)
+}
@@ -0,0 +1,136 @@
+package sink
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testReport() *Report {
+	return &Report{
+		Hostname:    "build-01",
+		Timestamp:   time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC),
+		Body:        []byte(`{"overall_score":75}`),
+		ContentType: "application/json",
+	}
+}
+
+func TestExpand(t *testing.T) {
+	got := Expand("reports/{hostname}/{date}/{timestamp}.json", testReport())
+	want := "reports/build-01/2025-03-04/20250304T050607Z.json"
+	if got != want {
+		t.Errorf("Expand() = %q, want %q", got, want)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	tests := []Config{
+		{},
+		{Type: "carrier-pigeon"},
+		{Type: TypeFile},
+		{Type: TypeWebhook, URL: "ftp://example.com"},
+		{Type: TypeFile, Path: "report.json", When: "hourly"},
+	}
+	for _, c := range tests {
+		if _, err := New(c); err == nil {
+			t.Errorf("New(%+v) should fail", c)
+		}
+	}
+	if _, err := NewAll(tests); err == nil || strings.Count(err.Error(), "\n") != len(tests)-1 {
+		t.Errorf("NewAll should report every invalid sink, got %v", err)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	dir := t.TempDir()
+	s, err := New(Config{Type: TypeFile, Path: filepath.Join(dir, "{hostname}", "latest.json")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(context.Background(), testReport()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "build-01", "latest.json"))
+	if err != nil || string(data) != `{"overall_score":75}` {
+		t.Errorf("file = %q, %v", data, err)
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, "build-01"))
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestNew_WhenDrift(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	s, err := New(Config{Type: TypeFile, Path: path, When: WhenDrift})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := testReport()
+	if err := s.Send(context.Background(), r); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("a report without drift should not be delivered")
+	}
+	r.Drift = true
+	if err := s.Send(context.Background(), r); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("a drifted report should be delivered: %v", err)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	t.Setenv("COLLECTOR_TOKEN", "s3cret")
+	var gotAuth, gotType, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	s, err := New(Config{Type: TypeWebhook, URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer ${COLLECTOR_TOKEN}"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(context.Background(), testReport()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if gotAuth != "Bearer s3cret" || gotType != "application/json" || gotBody != `{"overall_score":75}` {
+		t.Errorf("request = auth %q, type %q, body %q", gotAuth, gotType, gotBody)
+	}
+}
+
+func TestDeliver_ContinuesPastFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "collector down", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	sinks, err := NewAll([]Config{
+		{Type: TypeWebhook, Name: "collector", URL: srv.URL},
+		{Type: TypeFile, Path: path},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Deliver(context.Background(), sinks, testReport())
+	if err == nil || !strings.Contains(err.Error(), "sink collector") || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Deliver error = %v, want the collector failure", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("file sink should still run after the webhook failed: %v", err)
+	}
+}
@@ -0,0 +1,238 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultEmailSubject is the subject template of SMTP sinks that do not set
+// one
+const DefaultEmailSubject = "[omnitrust] {hostname}: {summary}"
+
+// Formats of the SMTP sink's email body
+const (
+	emailFormatHTML     = "html"
+	emailFormatMarkdown = "markdown"
+)
+
+// smtpSink emails each report, rendered as HTML or Markdown, to its
+// recipients. It connects for each report, like the MQTT sink.
+type smtpSink struct {
+	name string
+	addr string
+	host string
+	// implicitTLS is set for smtps:// URLs; smtp:// URLs upgrade with
+	// STARTTLS when the server offers it
+	implicitTLS bool
+	tls         *tls.Config
+	from        *mail.Address
+	to          []*mail.Address
+	subject     string
+	format      string
+	username    string
+	password    string
+	token       string
+	timeout     time.Duration
+}
+
+// newSMTPSink validates an SMTP sink's config
+func newSMTPSink(c Config, name string, timeout time.Duration) (*smtpSink, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("sink %s: url must be smtp://host:port or smtps://host:port", name)
+	}
+	s := &smtpSink{
+		name:     name,
+		addr:     u.Host,
+		host:     u.Hostname(),
+		subject:  c.Subject,
+		format:   strings.ToLower(c.Format),
+		username: c.Username,
+		password: c.Password,
+		token:    c.Token,
+		timeout:  timeout,
+	}
+	port := "587"
+	switch strings.ToLower(u.Scheme) {
+	case "smtp":
+	case "smtps":
+		port = "465"
+		s.implicitTLS = true
+	default:
+		return nil, fmt.Errorf("sink %s: url must be smtp://host:port or smtps://host:port", name)
+	}
+	if u.Port() == "" {
+		s.addr = net.JoinHostPort(u.Hostname(), port)
+	}
+	if s.tls, err = clientTLSConfig(c, s.host); err != nil {
+		return nil, fmt.Errorf("sink %s: %w", name, err)
+	}
+	if s.from, err = mail.ParseAddress(c.From); err != nil {
+		return nil, fmt.Errorf("sink %s: from: %w", name, err)
+	}
+	if len(c.To) == 0 {
+		return nil, fmt.Errorf("sink %s: to is required", name)
+	}
+	for _, to := range c.To {
+		addr, err := mail.ParseAddress(to)
+		if err != nil {
+			return nil, fmt.Errorf("sink %s: to %q: %w", name, to, err)
+		}
+		s.to = append(s.to, addr)
+	}
+	switch s.format {
+	case "":
+		s.format = emailFormatHTML
+	case emailFormatHTML, emailFormatMarkdown:
+	default:
+		return nil, fmt.Errorf("sink %s: format must be %s or %s", name, emailFormatHTML, emailFormatMarkdown)
+	}
+	if s.subject == "" {
+		s.subject = DefaultEmailSubject
+	}
+	if (s.password != "" || s.token != "") && s.username == "" {
+		return nil, fmt.Errorf("sink %s: username is required with password or token", name)
+	}
+	return s, nil
+}
+
+func (s *smtpSink) Name() string { return s.name }
+
+// Send connects, upgrades to TLS, authenticates, and sends the email
+func (s *smtpSink) Send(ctx context.Context, r *Report) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	var err error
+	if s.implicitTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: s.tls}).DialContext(ctx, "tcp", s.addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", s.addr)
+	}
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if !s.implicitTLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(s.tls); err != nil {
+				return fmt.Errorf("starttls: %w", err)
+			}
+		}
+	}
+	// Both mechanisms refuse to send credentials without TLS, except to
+	// localhost
+	switch {
+	case s.token != "":
+		err = c.Auth(&xoauth2Auth{host: s.host, username: os.ExpandEnv(s.username), token: os.ExpandEnv(s.token)})
+	case s.password != "":
+		err = c.Auth(smtp.PlainAuth("", os.ExpandEnv(s.username), os.ExpandEnv(s.password), s.host))
+	}
+	if err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+
+	if err := c.Mail(s.from.Address); err != nil {
+		return err
+	}
+	for _, to := range s.to {
+		if err := c.Rcpt(to.Address); err != nil {
+			return fmt.Errorf("recipient %s: %w", to.Address, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(s.message(r)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// message builds the email: the report rendered in the sink's format, or
+// its body as plain text when it cannot be rendered
+func (s *smtpSink) message(r *Report) []byte {
+	body, contentType := string(r.Body), "text/plain"
+	if r.Render != nil {
+		body = r.Render(s.format)
+		if s.format == emailFormatHTML {
+			contentType = "text/html"
+		}
+	}
+	subject := strings.ReplaceAll(Expand(s.subject, r), "{summary}", r.Summary)
+	to := make([]string, len(s.to))
+	for i, addr := range s.to {
+		to[i] = addr.String()
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", r.Timestamp.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: %s; charset=utf-8\r\n", contentType)
+	msg.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	qp := quotedprintable.NewWriter(&msg)
+	_, _ = qp.Write([]byte(body))
+	_ = qp.Close()
+	return msg.Bytes()
+}
+
+// xoauth2Auth authenticates with an OAuth2 access token, as Gmail and
+// Microsoft 365 expect of clients without a password
+type xoauth2Auth struct {
+	host, username, token string
+}
+
+func (a *xoauth2Auth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	return "XOAUTH2", []byte("user=" + a.username + "\x01auth=Bearer " + a.token + "\x01\x01"), nil
+}
+
+// Next answers the server's error challenge with an empty response, after
+// which the server fails the authentication with its final status
+func (a *xoauth2Auth) Next(_ []byte, more bool) ([]byte, error) {
+	if more {
+		return []byte{}, nil
+	}
+	return nil, nil
+}
+
+// isLocalhost reports whether host is the loopback interface, where
+// credentials may be sent without TLS
+func isLocalhost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
@@ -0,0 +1,269 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// smtpMessage is what the fake SMTP server received
+type smtpMessage struct {
+	tls      bool
+	auth     string
+	from     string
+	to       []string
+	data     string
+	quit     bool
+	rejected bool
+}
+
+// fakeSMTP accepts one connection and records one email. It offers
+// STARTTLS when starttls is set, and rejects credentials other than
+// validAuth.
+func fakeSMTP(t *testing.T, ln net.Listener, starttls *tls.Config, validAuth string) <-chan smtpMessage {
+	t.Helper()
+	done := make(chan smtpMessage, 1)
+	go func() {
+		var m smtpMessage
+		defer func() { done <- m }()
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { conn.Close() }()
+		rd := bufio.NewReader(conn)
+		reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+
+		reply("220 fake ESMTP")
+		for {
+			line, err := rd.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd, arg, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+			switch strings.ToUpper(cmd) {
+			case "EHLO":
+				if starttls != nil && !m.tls {
+					reply("250-fake")
+					reply("250-STARTTLS")
+				} else {
+					reply("250-fake")
+				}
+				reply("250 AUTH PLAIN XOAUTH2")
+			case "STARTTLS":
+				reply("220 ready")
+				conn = tls.Server(conn, starttls)
+				rd = bufio.NewReader(conn)
+				m.tls = true
+			case "AUTH":
+				_, resp, _ := strings.Cut(arg, " ")
+				decoded, _ := base64.StdEncoding.DecodeString(resp)
+				m.auth = string(decoded)
+				if m.auth != validAuth {
+					m.rejected = true
+					reply("535 authentication failed")
+					continue
+				}
+				reply("235 ok")
+			case "MAIL":
+				m.from = arg
+				reply("250 ok")
+			case "RCPT":
+				m.to = append(m.to, arg)
+				reply("250 ok")
+			case "DATA":
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					line, err := rd.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				m.data = data.String()
+				reply("250 queued")
+			case "QUIT":
+				m.quit = true
+				reply("221 bye")
+				return
+			default:
+				reply("502 unknown command")
+			}
+		}
+	}()
+	return done
+}
+
+// renderedReport is a test report that can be rendered
+func renderedReport() *Report {
+	r := testReport()
+	r.Summary = "75/100 (good), 2 findings"
+	r.Render = func(format string) string {
+		if format == emailFormatHTML {
+			return "<h1>Security Summary</h1>"
+		}
+		return "# Security Summary — café"
+	}
+	return r
+}
+
+// readEmail parses the email in m, returning its subject, content type,
+// and decoded body
+func readEmail(t *testing.T, m smtpMessage) (string, string, string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(m.data))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v (%q)", err, m.data)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return subject, msg.Header.Get("Content-Type"), strings.TrimSuffix(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n")
+}
+
+func TestSMTPSink(t *testing.T) {
+	t.Setenv("SMTP_PASSWORD", "hunter2")
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	done := fakeSMTP(t, ln, nil, "\x00reports\x00hunter2")
+
+	s, err := New(Config{Type: TypeSMTP, URL: "smtp://" + ln.Addr().String(), Format: "markdown",
+		From: "Posture <posture@example.com>", To: []string{"secops@example.com", "it@example.com"},
+		Username: "reports", Password: "${SMTP_PASSWORD}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(context.Background(), renderedReport()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	m := <-done
+	if m.from != "FROM:<posture@example.com>" || len(m.to) != 2 || m.to[1] != "TO:<it@example.com>" || !m.quit {
+		t.Errorf("envelope = %+v", m)
+	}
+	subject, contentType, body := readEmail(t, m)
+	if subject != "[omnitrust] build-01: 75/100 (good), 2 findings" {
+		t.Errorf("subject = %q", subject)
+	}
+	if contentType != "text/plain; charset=utf-8" || body != "# Security Summary — café" {
+		t.Errorf("body = %s %q", contentType, body)
+	}
+}
+
+func TestSMTPSink_STARTTLSWithXOAUTH2(t *testing.T) {
+	t.Setenv("SMTP_TOKEN", "ya29.token")
+	cert, caFile := testCertificate(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	done := fakeSMTP(t, ln, &tls.Config{Certificates: []tls.Certificate{cert}}, "user=reports@example.com\x01auth=Bearer ya29.token\x01\x01")
+
+	s, err := New(Config{Type: TypeSMTP, URL: "smtp://" + ln.Addr().String(), CAFile: caFile,
+		From: "reports@example.com", To: []string{"secops@example.com"}, Subject: "Posture of {hostname} on {date}",
+		Username: "reports@example.com", Token: "${SMTP_TOKEN}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(context.Background(), renderedReport()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	m := <-done
+	if !m.tls || m.rejected {
+		t.Errorf("session = %+v", m)
+	}
+	subject, contentType, body := readEmail(t, m)
+	if subject != "Posture of build-01 on 2025-03-04" || contentType != "text/html; charset=utf-8" || body != "<h1>Security Summary</h1>" {
+		t.Errorf("email = %q, %s, %q", subject, contentType, body)
+	}
+}
+
+func TestSMTPSink_ImplicitTLS(t *testing.T) {
+	cert, caFile := testCertificate(t)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	done := fakeSMTP(t, ln, nil, "")
+
+	s, err := New(Config{Type: TypeSMTP, URL: "smtps://" + ln.Addr().String(), CAFile: caFile,
+		From: "reports@example.com", To: []string{"secops@example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without a renderer the JSON body is sent as plain text
+	if err := s.Send(context.Background(), testReport()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	_, contentType, body := readEmail(t, <-done)
+	if contentType != "text/plain; charset=utf-8" || body != `{"overall_score":75}` {
+		t.Errorf("body = %s %q", contentType, body)
+	}
+}
+
+func TestSMTPSink_AuthFailed(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	fakeSMTP(t, ln, nil, "\x00reports\x00right")
+
+	s, _ := New(Config{Type: TypeSMTP, URL: "smtp://" + ln.Addr().String(),
+		From: "reports@example.com", To: []string{"secops@example.com"}, Username: "reports", Password: "wrong"})
+	if err := s.Send(context.Background(), renderedReport()); err == nil || !strings.Contains(err.Error(), "auth") {
+		t.Errorf("Send error = %v", err)
+	}
+}
+
+func TestNewSMTPSink_Invalid(t *testing.T) {
+	valid := Config{Type: TypeSMTP, URL: "smtp://mail.example.com", From: "reports@example.com", To: []string{"secops@example.com"}}
+	tests := []func(c *Config){
+		func(c *Config) { c.URL = "" },
+		func(c *Config) { c.URL = "https://mail.example.com" },
+		func(c *Config) { c.From = "" },
+		func(c *Config) { c.To = nil },
+		func(c *Config) { c.To = []string{"not an address"} },
+		func(c *Config) { c.Format = "pdf" },
+		func(c *Config) { c.Token = "${SMTP_TOKEN}" },
+		func(c *Config) { c.CAFile = "/nonexistent/ca.pem" },
+	}
+	for i, modify := range tests {
+		c := valid
+		modify(&c)
+		if _, err := New(c); err == nil {
+			t.Errorf("case %d: New(%+v) should fail", i, c)
+		}
+	}
+	s, err := New(valid)
+	if err != nil || s.(*smtpSink).addr != "mail.example.com:587" {
+		t.Errorf("default port: %v, %v", s, err)
+	}
+	valid.URL = "smtps://mail.example.com"
+	if s, err := New(valid); err != nil || s.(*smtpSink).addr != "mail.example.com:465" {
+		t.Errorf("default TLS port: %v, %v", s, err)
+	}
+}
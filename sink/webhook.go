@@ -0,0 +1,48 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// webhookSink POSTs each report to an HTTP endpoint
+type webhookSink struct {
+	name    string
+	url     string
+	headers map[string]string
+	timeout time.Duration
+}
+
+func (s *webhookSink) Name() string { return s.name }
+
+// Send posts the report and treats any non-2xx response as a failure
+func (s *webhookSink) Send(ctx context.Context, r *Report) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(r.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", r.ContentType)
+	req.Header.Set("User-Agent", "omnitrust")
+	for k, v := range s.headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s: %s", s.url, resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}